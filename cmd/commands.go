@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/mcp/server"
+)
+
+// errInvalid reports a check that failed after its output was printed
+var errInvalid = errors.New("configuration is invalid")
+
+// newFlagSet creates the flag set of a command with the --config flag every command shares
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "Path of the configuration file")
+	return fs, configPath
+}
+
+// runServe starts the MCP server, or prints a translation catalog with --i18n-export
+func runServe(args []string) error {
+	fs, configPath := newFlagSet("serve")
+	transport := fs.String("transport", "", "Transport to serve: sse, http or stdio (overrides server.transport)")
+	mock := fs.Bool("mock", false, "Answer tool calls from the fixtures in mock.dir")
+	debug := fs.Bool("debug", false, "Enable debug mode")
+	fs.BoolVar(debug, "d", false, "Shorthand for --debug")
+	exportLocale := fs.String("i18n-export", "", "Print the translation catalog of a locale for the registered tools and exit")
+	fs.Parse(args)
+
+	if *debug {
+		fmt.Fprintln(os.Stderr, "Debug mode enabled")
+	}
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if *transport != "" {
+		cfg.Server.Transport = *transport
+	}
+	if *mock {
+		cfg.Mock.Enabled = true
+	}
+	if *exportLocale != "" {
+		// The tools are listed in-process, which is trusted like a stdio client
+		cfg.Server.Transport = "stdio"
+	}
+
+	mcp := server.NewMCPServer(cfg)
+	defer mcp.Close()
+	if *exportLocale != "" {
+		catalog, err := mcp.ExportCatalog(context.Background(), *exportLocale)
+		if err != nil {
+			return fmt.Errorf("failed to export the %s catalog: %w", *exportLocale, err)
+		}
+		os.Stdout.Write(catalog)
+		return nil
+	}
+	if err := mcp.Start(); err != nil {
+		return fmt.Errorf("MCP server stopped: %w", err)
+	}
+	return nil
+}
+
+// runValidateConfig prints the validation result of the configuration; it fails when the configuration has errors
+func runValidateConfig(args []string) error {
+	fs, configPath := newFlagSet("validate-config")
+	asJSON := fs.Bool("json", false, "Print the validation result as JSON")
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	result := cfg.ValidateConfig()
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, service := range result.Services {
+			mark := "✓"
+			switch {
+			case !service.Configured && service.Required:
+				mark = "✗"
+			case !service.Configured:
+				mark = "○"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", mark, service.Service, service.Message)
+		}
+		w.Flush()
+		for _, message := range result.Errors {
+			fmt.Printf("error: %s\n", message)
+		}
+		for _, message := range result.Warnings {
+			fmt.Printf("warning: %s\n", message)
+		}
+		if result.Valid {
+			fmt.Printf("%s is valid\n", *configPath)
+		}
+	}
+
+	if !result.Valid {
+		return errInvalid
+	}
+	return nil
+}
+
+// runListTools starts the providers the configuration enables, lists the tools they register and exits
+// without serving a transport
+func runListTools(args []string) error {
+	fs, configPath := newFlagSet("list-tools")
+	mock := fs.Bool("mock", false, "Also list the tools of providers that are not configured, as mock mode registers them")
+	asJSON := fs.Bool("json", false, "Print the full tool definitions, with their input schemas, as JSON")
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	// The tools are listed in-process, which is trusted like a stdio client
+	cfg.Server.Transport = "stdio"
+	if *mock {
+		cfg.Mock.Enabled = true
+	}
+
+	mcp := server.NewMCPServer(cfg)
+	defer mcp.Close()
+	tools, err := mcp.ListTools(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(tools)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, tool := range tools {
+		fmt.Fprintf(w, "%s\t%s\n", tool.Name, firstSentence(tool.Description))
+	}
+	w.Flush()
+	fmt.Fprintf(os.Stderr, "%d tools\n", len(tools))
+	return nil
+}
+
+// runGenKey prints a new API key, or an auth.api_keys entry holding it when --name is given
+func runGenKey(args []string) error {
+	fs := flag.NewFlagSet("gen-key", flag.ExitOnError)
+	name := fs.String("name", "", "Print an auth.api_keys entry with this name instead of the bare key")
+	roles := fs.String("roles", "", "Comma-separated roles of the entry, e.g. developer,readonly")
+	fs.Parse(args)
+
+	key, err := auth.GenerateAPIKey()
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		fmt.Println(key)
+		return nil
+	}
+
+	// The fields of config.APIKey a new key needs
+	entry := struct {
+		Name    string   `yaml:"name"`
+		Key     string   `yaml:"key"`
+		Roles   []string `yaml:"roles"`
+		Enabled bool     `yaml:"enabled"`
+	}{Name: *name, Key: key, Roles: []string{}, Enabled: true}
+	for _, role := range strings.Split(*roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			entry.Roles = append(entry.Roles, role)
+		}
+	}
+	data, err := yaml.Marshal([]interface{}{entry})
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(data)
+	return nil
+}
+
+// firstSentence shortens a tool description to its first sentence
+func firstSentence(description string) string {
+	if i := strings.Index(description, ". "); i >= 0 {
+		return description[:i+1]
+	}
+	return description
+}
@@ -2,29 +2,60 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
-
-	"dev-mcp/internal/config"
-	"dev-mcp/internal/mcp/server"
+	"strings"
 )
 
+const defaultConfigPath = "./configs/config.yaml"
+
+// command is a subcommand of the CLI; run gets the arguments after the command name
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "Start the MCP server (the default command)", runServe},
+	{"validate-config", "Check the configuration and print the status of every service", runValidateConfig},
+	{"list-tools", "List the tools the configuration registers, without starting a transport", runListTools},
+	{"gen-key", "Generate an API key for auth.api_keys", runGenKey},
+}
+
 func main() {
-	// Check for debug mode (for future use)
-	for _, arg := range os.Args {
-		if arg == "--debug" || arg == "-d" {
-			fmt.Println("Debug mode enabled")
-			break
+	args := os.Args[1:]
+	name := "serve"
+	// Without a command, or with flags only, the server starts as before subcommands existed;
+	// "mcp" is the old name of serve
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+		if name == "mcp" {
+			name = "serve"
 		}
 	}
 
-	cfg, err := config.Load("./configs/config.yaml")
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
 	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
 
-	mcp := server.NewMCPServer(cfg)
-	mcp.Start()
-
-	fmt.Println("\nTo start as MCP server, run: go run cmd/main.go mcp")
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [command] [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command.\n", os.Args[0])
 }
@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+const (
+	defaultPath        = "./logs/audit.jsonl"
+	defaultMaxSizeMB   = 10
+	defaultMaxFiles    = 5
+	defaultMaxArgBytes = 4096
+	defaultRecent      = 1000
+	// maxErrorLength bounds the error message recorded for a failed call
+	maxErrorLength = 500
+)
+
+// Entry is one recorded tool invocation
+type Entry struct {
+	Time       time.Time       `json:"time"`
+	Tool       string          `json:"tool"`
+	User       string          `json:"user"`
+	Session    string          `json:"session,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"` // Redacted, and cut to max_arg_bytes
+	DurationMS int64           `json:"duration_ms"`
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// Logger records tool invocations to a sink and keeps the most recent ones in memory for audit_query
+type Logger struct {
+	sink        Sink
+	redactor    *Redactor
+	maxArgBytes int
+	logger      *logging.Logger
+
+	mu     sync.Mutex
+	recent []Entry // Ring buffer, oldest entry at next once full
+	next   int
+	full   bool
+}
+
+// NewLogger creates an audit logger; it returns nil when auditing is disabled
+func NewLogger(cfg *config.AuditConfig) (*Logger, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	l := &Logger{
+		redactor:    NewRedactor(cfg.RedactKeys),
+		maxArgBytes: defaultMaxArgBytes,
+		logger:      logging.New("audit"),
+	}
+	if cfg.MaxArgBytes > 0 {
+		l.maxArgBytes = cfg.MaxArgBytes
+	}
+	recent := defaultRecent
+	if cfg.Recent > 0 {
+		recent = cfg.Recent
+	}
+	l.recent = make([]Entry, recent)
+
+	switch cfg.Sink {
+	case "", "file":
+		path := cfg.Path
+		if path == "" {
+			path = defaultPath
+		}
+		maxSize, maxFiles := defaultMaxSizeMB, defaultMaxFiles
+		if cfg.MaxSizeMB > 0 {
+			maxSize = cfg.MaxSizeMB
+		}
+		if cfg.MaxFiles > 0 {
+			maxFiles = cfg.MaxFiles
+		}
+		sink, err := NewFileSink(path, int64(maxSize)<<20, maxFiles)
+		if err != nil {
+			return nil, err
+		}
+		// Calls from before a restart remain searchable
+		for _, entry := range sink.Tail(recent) {
+			l.remember(entry)
+		}
+		l.sink = sink
+	case "log":
+		l.sink = &LogSink{logger: l.logger}
+	default:
+		return nil, fmt.Errorf("unknown audit sink %q (use file or log)", cfg.Sink)
+	}
+	return l, nil
+}
+
+// Record redacts and stores an invocation; a failing sink is logged but never fails the call
+func (l *Logger) Record(entry Entry, arguments json.RawMessage) {
+	entry.Arguments = l.redactor.RedactJSON(arguments, l.maxArgBytes)
+	if len(entry.Error) > maxErrorLength {
+		entry.Error = entry.Error[:maxErrorLength] + "..."
+	}
+
+	l.mu.Lock()
+	l.remember(entry)
+	l.mu.Unlock()
+
+	if err := l.sink.Write(entry); err != nil {
+		l.logger.Error("failed to write audit log", logging.String("tool", entry.Tool), logging.Error(err))
+	}
+}
+
+// remember adds an entry to the ring buffer; the caller holds mu unless the logger is not yet shared
+func (l *Logger) remember(entry Entry) {
+	l.recent[l.next] = entry
+	l.next = (l.next + 1) % len(l.recent)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Query filters recent invocations
+type Query struct {
+	Tool     string    // Tool name, or a prefix ending in * such as database_*
+	User     string    // Exact caller name
+	Status   string    // success or error; any when empty
+	Since    time.Time // Only calls at or after this time
+	Contains string    // Case-insensitive text in the arguments or error
+	Limit    int
+}
+
+// Search returns the recent invocations matching q, newest first, and how many matched in total
+func (l *Logger) Search(q Query) ([]Entry, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.recent)
+	}
+	contains := strings.ToLower(q.Contains)
+	var matches []Entry
+	total := 0
+	for i := 1; i <= count; i++ {
+		entry := l.recent[(l.next-i+len(l.recent))%len(l.recent)]
+		if !q.matches(&entry, contains) {
+			continue
+		}
+		total++
+		if q.Limit <= 0 || len(matches) < q.Limit {
+			matches = append(matches, entry)
+		}
+	}
+	return matches, total
+}
+
+func (q *Query) matches(entry *Entry, contains string) bool {
+	switch {
+	case q.Tool != "" && !matchTool(q.Tool, entry.Tool):
+		return false
+	case q.User != "" && q.User != entry.User:
+		return false
+	case q.Status == "success" && !entry.Success, q.Status == "error" && entry.Success:
+		return false
+	case !q.Since.IsZero() && entry.Time.Before(q.Since):
+		return false
+	case contains != "" && !strings.Contains(strings.ToLower(string(entry.Arguments)), contains) &&
+		!strings.Contains(strings.ToLower(entry.Error), contains):
+		return false
+	}
+	return true
+}
+
+func matchTool(pattern, tool string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(tool, prefix)
+	}
+	return pattern == tool
+}
+
+// Close closes the sink
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.sink.Close()
+}
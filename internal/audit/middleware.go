@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Middleware returns MCP receiving middleware that records every tools/call. It should be the
+// outermost middleware so calls rejected by permission checks or deploy freezes are recorded too;
+// caller names the principal of a request.
+func (l *Logger) Middleware(caller func(ctx context.Context, req mcp.Request) string) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, method, req)
+
+			entry := Entry{
+				Time:       start.UTC(),
+				Tool:       callReq.Params.Name,
+				User:       caller(ctx, req),
+				DurationMS: time.Since(start).Milliseconds(),
+				Success:    err == nil,
+			}
+			if callReq.Session != nil {
+				entry.Session = callReq.Session.ID()
+			}
+			if err != nil {
+				entry.Error = err.Error()
+			} else if toolResult, ok := result.(*mcp.CallToolResult); ok && toolResult.IsError {
+				entry.Success = false
+				entry.Error = resultText(toolResult)
+			}
+			l.Record(entry, callReq.Params.Arguments)
+
+			return result, err
+		}
+	}
+}
+
+// resultText returns the text of an error result, which tools use for the error message
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "tool returned an error"
+}
@@ -0,0 +1,128 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+const (
+	defaultQueryLimit = 50
+	maxQueryLimit     = 500
+)
+
+// QueryTool creates the audit_query tool, which searches the invocations kept in memory
+func (l *Logger) QueryTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "audit_query",
+		Description: "Search recent tool invocations recorded by the audit log: who called which tool with which (redacted) arguments, how long it took and whether it failed. Newest first; calls from before a restart are included when the audit log is a file",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"tool": {
+					"type": "string",
+					"description": "Tool name, or a prefix ending in * such as database_*"
+				},
+				"user": {
+					"type": "string",
+					"description": "Caller (API key name)"
+				},
+				"status": {
+					"type": "string",
+					"enum": ["success", "error"],
+					"description": "Only successful or only failed calls"
+				},
+				"since": {
+					"type": "string",
+					"description": "Only calls within this duration (e.g. 30m, 24h) or after this RFC3339 time"
+				},
+				"contains": {
+					"type": "string",
+					"description": "Case-insensitive text in the arguments or error message"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum invocations to return",
+					"default": 50
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Tool     string `json:"tool,omitempty"`
+			User     string `json:"user,omitempty"`
+			Status   string `json:"status,omitempty"`
+			Since    string `json:"since,omitempty"`
+			Contains string `json:"contains,omitempty"`
+			Limit    int    `json:"limit,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if args.Status != "" && args.Status != "success" && args.Status != "error" {
+			return createErrorResult(fmt.Errorf("status must be success or error")), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = defaultQueryLimit
+		}
+		q := Query{Tool: args.Tool, User: args.User, Status: args.Status, Contains: args.Contains, Limit: min(args.Limit, maxQueryLimit)}
+		if args.Since != "" {
+			since, err := parseSince(args.Since, time.Now())
+			if err != nil {
+				return createErrorResult(err), nil
+			}
+			q.Since = since
+		}
+
+		entries, total := l.Search(q)
+		if entries == nil {
+			entries = []Entry{}
+		}
+		return formatJSONResult(map[string]interface{}{
+			"invocations": entries,
+			"count":       len(entries),
+			"matched":     total,
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// parseSince accepts a duration back from now or an RFC3339 time
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since must be a duration such as 24h or an RFC3339 time")
+	}
+	return t, nil
+}
+
+func createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Audit Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
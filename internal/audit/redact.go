@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// maxStringLength bounds single argument values such as file contents or queries
+	maxStringLength = 1024
+)
+
+// secretKeyPattern matches argument names whose values are never recorded
+var secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|access_?key|private_?key|authorization|credential|cookie)`)
+
+// Redactor removes secrets from tool arguments before they are recorded
+type Redactor struct {
+	keys map[string]bool // Extra argument names, lowercased
+}
+
+// NewRedactor creates a redactor; keys are argument names redacted besides the built-in secret names
+func NewRedactor(keys []string) *Redactor {
+	r := &Redactor{keys: map[string]bool{}}
+	for _, key := range keys {
+		r.keys[strings.ToLower(strings.TrimSpace(key))] = true
+	}
+	return r
+}
+
+// RedactJSON redacts secret values at any depth, shortens long strings and cuts the result to maxBytes.
+// Arguments that are not JSON are recorded only by size.
+func (r *Redactor) RedactJSON(arguments json.RawMessage, maxBytes int) json.RawMessage {
+	if len(arguments) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(arguments, &value); err != nil {
+		return quote(fmt.Sprintf("(%d bytes, not JSON)", len(arguments)))
+	}
+	data, err := json.Marshal(r.redact(value))
+	if err != nil {
+		return quote(fmt.Sprintf("(%d bytes)", len(arguments)))
+	}
+	if len(data) > maxBytes {
+		// A cut document is no longer JSON, so it is kept as a string
+		return quote(string(data[:maxBytes]) + fmt.Sprintf("... (%d bytes omitted)", len(data)-maxBytes))
+	}
+	return data
+}
+
+func (r *Redactor) redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if r.isSecret(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = r.redact(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redact(item)
+		}
+	case string:
+		if len(v) > maxStringLength {
+			return v[:maxStringLength] + fmt.Sprintf("... (%d bytes omitted)", len(v)-maxStringLength)
+		}
+	}
+	return value
+}
+
+func (r *Redactor) isSecret(key string) bool {
+	return secretKeyPattern.MatchString(key) || r.keys[strings.ToLower(key)]
+}
+
+func quote(s string) json.RawMessage {
+	data, _ := json.Marshal(s)
+	return data
+}
@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"dev-mcp/internal/logging"
+)
+
+// Sink stores audit entries
+type Sink interface {
+	Write(entry Entry) error
+	Close() error
+}
+
+// FileSink appends entries as JSON lines and rotates the file when it reaches maxBytes:
+// audit.jsonl becomes audit.jsonl.1, audit.jsonl.1 becomes audit.jsonl.2 and so on up to maxFiles
+type FileSink struct {
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileSink opens (or creates) the audit file, creating its directory when needed
+func NewFileSink(path string, maxBytes int64, maxFiles int) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	s := &FileSink{path: path, maxBytes: maxBytes, maxFiles: maxFiles}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	s.file, s.size = f, info.Size()
+	return nil
+}
+
+// Write appends an entry, rotating first when the file would exceed its size
+func (s *FileSink) Write(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+	if s.size > 0 && s.size+int64(len(line)) > s.maxBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// rotate shifts the numbered files up by one, dropping the oldest, and starts a new file
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	s.file = nil
+	os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxFiles))
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return s.open()
+}
+
+// Tail returns up to n of the last entries of the current file, oldest first
+func (s *FileSink) Tail(n int) []Entry {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	ring := make([]Entry, 0, n)
+	start := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if len(ring) < n {
+			ring = append(ring, entry)
+			continue
+		}
+		ring[start] = entry
+		start = (start + 1) % n
+	}
+	return append(ring[start:], ring[:start]...)
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// LogSink writes entries to the server log
+type LogSink struct {
+	logger *logging.Logger
+}
+
+// Write logs an entry; failed calls are logged as warnings
+func (s *LogSink) Write(entry Entry) error {
+	fields := []logging.Field{
+		logging.String("tool", entry.Tool),
+		logging.String("user", entry.User),
+		logging.String("arguments", string(entry.Arguments)),
+		logging.Duration("duration", time.Duration(entry.DurationMS)*time.Millisecond),
+	}
+	if !entry.Success {
+		s.logger.Warn("tool call failed", append(fields, logging.String("error", entry.Error))...)
+		return nil
+	}
+	s.logger.Info("tool call", fields...)
+	return nil
+}
+
+// Close does nothing; the server log stays open
+func (s *LogSink) Close() error {
+	return nil
+}
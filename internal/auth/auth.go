@@ -5,13 +5,32 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"path"
 	"strings"
 )
 
 // AuthConfig represents the authentication configuration
 type AuthConfig struct {
-	Enabled bool     `yaml:"enabled"`
-	APIKeys []APIKey `yaml:"api_keys"`
+	Enabled         bool                `yaml:"enabled"`
+	APIKeys         []APIKey            `yaml:"api_keys"`
+	ToolPermissions map[string][]string `yaml:"tool_permissions"` // Tool name or glob -> roles allowed to call it
+	RateLimit       RateLimitConfig     `yaml:"rate_limit"`
+	Policy          PolicyConfig        `yaml:"policy"`
+	Impersonation   ImpersonationConfig `yaml:"impersonation"`
+}
+
+// defaultToolPermissions apply when no tool_permissions are configured
+var defaultToolPermissions = map[string][]string{
+	"database_query":  {"read", "write", "admin"},
+	"loki_query":      {"read", "write", "admin", "monitor"},
+	"s3_query":        {"read", "write", "admin"},
+	"sentry_query":    {"monitor", "admin"},
+	"swagger_query":   {"read", "write", "admin"},
+	"llm_chat":        {"write", "admin"},
+	"http_request":    {"write", "admin"},
+	"provider_status": {"read", "write", "admin", "monitor"},
+	"audit_query":     {"admin"},
+	"*":               {"admin"},
 }
 
 // APIKey represents an API key for authentication
@@ -20,6 +39,9 @@ type APIKey struct {
 	Key     string   `yaml:"key"`
 	Roles   []string `yaml:"roles"`
 	Enabled bool     `yaml:"enabled"`
+
+	RequestsPerMinute int    `yaml:"requests_per_minute"` // Overrides rate_limit.requests_per_minute for this key
+	Locale            string `yaml:"locale"`              // Language of tool descriptions and errors for this key
 }
 
 // AuthResult represents authentication result
@@ -28,6 +50,9 @@ type AuthResult struct {
 	Username string   `json:"username"`
 	Roles    []string `json:"roles"`
 	Method   string   `json:"method"`
+	Locale   string   `json:"locale,omitempty"`
+
+	OnBehalfOf string `json:"on_behalf_of,omitempty"` // User named by the impersonation header of the HTTP request
 }
 
 // SimpleAuthenticator implements simple API key authentication
@@ -65,6 +90,7 @@ func (a *SimpleAuthenticator) AuthenticateBearer(token string) (*AuthResult, err
 				Username: apiKey.Name,
 				Roles:    apiKey.Roles,
 				Method:   "api_key",
+				Locale:   apiKey.Locale,
 			}, nil
 		}
 	}
@@ -78,18 +104,7 @@ func (a *SimpleAuthenticator) HasPermission(authResult *AuthResult, toolName str
 		return false
 	}
 
-	// Define tool permissions
-	toolPermissions := map[string][]string{
-		"database_query": {"read", "write", "admin"},
-		"loki_query":     {"read", "write", "admin", "monitor"},
-		"s3_query":       {"read", "write", "admin"},
-		"sentry_query":   {"monitor", "admin"},
-		"swagger_query":  {"read", "write", "admin"},
-		"llm_chat":       {"write", "admin"},
-		"http_request":   {"write", "admin"},
-	}
-
-	requiredRoles, exists := toolPermissions[toolName]
+	requiredRoles, exists := a.RequiredRoles(toolName)
 	if !exists {
 		// If tool is not defined, deny access
 		return false
@@ -98,7 +113,7 @@ func (a *SimpleAuthenticator) HasPermission(authResult *AuthResult, toolName str
 	// Check if user has any of the required roles
 	for _, userRole := range authResult.Roles {
 		for _, requiredRole := range requiredRoles {
-			if userRole == requiredRole {
+			if userRole == requiredRole || requiredRole == "*" {
 				return true
 			}
 		}
@@ -107,6 +122,35 @@ func (a *SimpleAuthenticator) HasPermission(authResult *AuthResult, toolName str
 	return false
 }
 
+// ToolPermissions returns the effective tool permissions: the configured ones, or the defaults
+func (a *SimpleAuthenticator) ToolPermissions() map[string][]string {
+	if len(a.config.ToolPermissions) > 0 {
+		return a.config.ToolPermissions
+	}
+	return defaultToolPermissions
+}
+
+// RequiredRoles returns the roles allowed to call a tool. An exact entry wins over glob
+// patterns (e.g. "database_*"); among patterns the longest match wins.
+func (a *SimpleAuthenticator) RequiredRoles(toolName string) ([]string, bool) {
+	permissions := a.ToolPermissions()
+	if roles, ok := permissions[toolName]; ok {
+		return roles, true
+	}
+
+	best := ""
+	var roles []string
+	for pattern, patternRoles := range permissions {
+		if matched, err := path.Match(pattern, toolName); err != nil || !matched {
+			continue
+		}
+		if len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best, roles = pattern, patternRoles
+		}
+	}
+	return roles, best != ""
+}
+
 // IsEnabled returns whether authentication is enabled
 func (a *SimpleAuthenticator) IsEnabled() bool {
 	return a.config.Enabled
@@ -0,0 +1,270 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/logging"
+)
+
+// Defaults for impersonation
+const (
+	defaultOnBehalfOfHeader  = "X-On-Behalf-Of"
+	defaultOnBehalfOfMetaKey = "dev-mcp/on_behalf_of"
+	tokenExchangeGrantType   = "urn:ietf:params:oauth:grant-type:token-exchange"
+	exchangedTokenLeeway     = 30 * time.Second
+)
+
+// ImpersonationConfig lets trusted callers name the human they act for, so downstream APIs attribute
+// actions to that user rather than to dev-mcp's service account
+type ImpersonationConfig struct {
+	Enabled     bool                          `yaml:"enabled"`
+	Header      string                        `yaml:"header"`      // HTTP request header naming the user, X-On-Behalf-Of by default
+	MetaKey     string                        `yaml:"meta_key"`    // tools/call _meta key naming the user, dev-mcp/on_behalf_of by default
+	Roles       []string                      `yaml:"roles"`       // Roles whose callers may name a user, admin by default
+	Downstreams map[string]DownstreamIdentity `yaml:"downstreams"` // Downstream API (sentry, github, gitlab, jenkins) -> how the user is forwarded
+}
+
+// DownstreamIdentity configures how the user reaches one downstream API. Without a token for the user,
+// the request keeps the service account's credentials and only the header names the user.
+type DownstreamIdentity struct {
+	Header        string              `yaml:"header"`         // Header carrying the user name, e.g. X-Forwarded-User
+	Tokens        map[string]string   `yaml:"tokens"`         // User -> personal token used instead of the service account's
+	TokenExchange TokenExchangeConfig `yaml:"token_exchange"` // Issues a token for users without a personal one
+	AuthHeader    string              `yaml:"auth_header"`    // Header the user's token goes in, Authorization by default
+	AuthScheme    string              `yaml:"auth_scheme"`    // Prefix of the token, Bearer by default; "-" for none
+	Required      bool                `yaml:"required"`       // Fail requests for users no token is available for
+}
+
+// TokenExchangeConfig is an OAuth 2.0 token exchange (RFC 8693) endpoint that issues access tokens for
+// a requested subject to dev-mcp's client, as Keycloak does for impersonation
+type TokenExchangeConfig struct {
+	URL          string `yaml:"url"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	Audience     string `yaml:"audience"`
+	Scope        string `yaml:"scope"`
+}
+
+// exchangedToken is a cached token issued for a user
+type exchangedToken struct {
+	value   string
+	expires time.Time
+}
+
+// Impersonator attaches the user a tool call acts for to its context and forwards it to downstream APIs
+type Impersonator struct {
+	config ImpersonationConfig
+	client *resty.Client
+
+	mu     sync.Mutex
+	tokens map[string]exchangedToken // downstream + "\x00" + user -> token
+}
+
+// onBehalfOf is the user of a tool call, with the impersonator that forwards it
+type onBehalfOf struct {
+	user         string
+	impersonator *Impersonator
+}
+
+const onBehalfOfKey contextKey = "on_behalf_of"
+
+// NewImpersonator creates an impersonator, or returns nil when impersonation is disabled
+func NewImpersonator(cfg ImpersonationConfig) *Impersonator {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Header == "" {
+		cfg.Header = defaultOnBehalfOfHeader
+	}
+	if cfg.MetaKey == "" {
+		cfg.MetaKey = defaultOnBehalfOfMetaKey
+	}
+	if len(cfg.Roles) == 0 {
+		cfg.Roles = []string{"admin"}
+	}
+	return &Impersonator{
+		config: cfg,
+		client: resty.New().SetTimeout(15*time.Second).SetHeader("User-Agent", "dev-mcp/1.0"),
+		tokens: map[string]exchangedToken{},
+	}
+}
+
+// Header returns the HTTP request header that names the user
+func (i *Impersonator) Header() string {
+	return i.config.Header
+}
+
+// Middleware returns MCP receiving middleware that resolves the user a tool call acts for, from the call's
+// _meta or the header of the HTTP session, and attaches it to the call's context. Only callers with one of
+// the configured roles may name a user. principal is the caller when the context carries none: the stdio
+// user, or everyone when authentication is disabled.
+func (i *Impersonator) Middleware(principal *AuthResult) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			caller := principal
+			if authResult, ok := GetAuthResult(ctx); ok && authResult != nil {
+				caller = authResult
+			}
+			user, _ := callReq.Params.Meta[i.config.MetaKey].(string)
+			if user == "" && caller != nil {
+				user = caller.OnBehalfOf
+			}
+			user = strings.TrimSpace(user)
+			if user == "" {
+				return next(ctx, method, req)
+			}
+
+			if caller == nil || !hasAnyRole(caller.Roles, i.config.Roles) {
+				name := "unauthenticated"
+				if caller != nil {
+					name = caller.Username
+				}
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Authorization Error: %s may not act on behalf of other users", name)}},
+					IsError: true,
+				}, nil
+			}
+
+			logging.ToolLogger.Info("tool call on behalf of user",
+				logging.String("tool", callReq.Params.Name),
+				logging.String("caller", caller.Username),
+				logging.String("user", user))
+			return next(context.WithValue(ctx, onBehalfOfKey, &onBehalfOf{user: user, impersonator: i}), method, req)
+		}
+	}
+}
+
+// OnBehalfOf returns the user a tool call acts for
+func OnBehalfOf(ctx context.Context) (string, bool) {
+	obo, ok := ctx.Value(onBehalfOfKey).(*onBehalfOf)
+	if !ok {
+		return "", false
+	}
+	return obo.user, true
+}
+
+// ApplyOnBehalfOf forwards the user a tool call acts for to a request to a downstream API, by the header
+// and token configured for that downstream. Requests of calls that act for no one are left unchanged.
+func ApplyOnBehalfOf(ctx context.Context, downstream string, req *resty.Request) error {
+	obo, ok := ctx.Value(onBehalfOfKey).(*onBehalfOf)
+	if !ok {
+		return nil
+	}
+	identity, ok := obo.impersonator.config.Downstreams[downstream]
+	if !ok {
+		return nil
+	}
+
+	if identity.Header != "" {
+		req.SetHeader(identity.Header, obo.user)
+	}
+	token, err := obo.impersonator.token(ctx, downstream, identity, obo.user)
+	if err != nil {
+		return fmt.Errorf("no %s credentials for %s: %w", downstream, obo.user, err)
+	}
+	if token == "" {
+		if identity.Required {
+			return fmt.Errorf("no %s credentials for %s", downstream, obo.user)
+		}
+		return nil
+	}
+
+	header, scheme := identity.AuthHeader, identity.AuthScheme
+	if header == "" {
+		header = "Authorization"
+	}
+	switch scheme {
+	case "":
+		token = "Bearer " + token
+	case "-":
+	default:
+		token = scheme + " " + token
+	}
+	req.SetHeader(header, token)
+	return nil
+}
+
+// token returns the user's personal token, or one issued by the token exchange, or "" when the
+// downstream has neither
+func (i *Impersonator) token(ctx context.Context, downstream string, identity DownstreamIdentity, user string) (string, error) {
+	if token := identity.Tokens[user]; token != "" {
+		return token, nil
+	}
+	exchange := identity.TokenExchange
+	if exchange.URL == "" {
+		return "", nil
+	}
+
+	key := downstream + "\x00" + user
+	i.mu.Lock()
+	cached, ok := i.tokens[key]
+	i.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	form := map[string]string{
+		"grant_type":        tokenExchangeGrantType,
+		"requested_subject": user,
+	}
+	if exchange.Audience != "" {
+		form["audience"] = exchange.Audience
+	}
+	if exchange.Scope != "" {
+		form["scope"] = exchange.Scope
+	}
+	req := i.client.R().
+		SetContext(ctx).
+		SetFormData(form).
+		SetResult(&result).
+		ForceContentType("application/json")
+	if exchange.ClientID != "" {
+		req.SetBasicAuth(exchange.ClientID, exchange.ClientSecret)
+	}
+	resp, err := req.Post(exchange.URL)
+	if err != nil {
+		return "", fmt.Errorf("token exchange failed: %w", err)
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("token exchange failed: %s", resp.Status())
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("token exchange returned no access token")
+	}
+
+	expiresIn := time.Duration(result.ExpiresIn) * time.Second
+	if expiresIn <= exchangedTokenLeeway {
+		expiresIn = 5 * time.Minute
+	}
+	i.mu.Lock()
+	i.tokens[key] = exchangedToken{value: result.AccessToken, expires: time.Now().Add(expiresIn - exchangedTokenLeeway)}
+	i.mu.Unlock()
+	return result.AccessToken, nil
+}
+
+func hasAnyRole(roles, allowed []string) bool {
+	for _, role := range roles {
+		for _, want := range allowed {
+			if role == want || want == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
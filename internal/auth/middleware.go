@@ -9,6 +9,7 @@ import (
 // Middleware provides HTTP authentication middleware
 type Middleware struct {
 	authenticator *SimpleAuthenticator
+	policy        *PolicyEngine
 }
 
 // NewMiddleware creates a new authentication middleware
@@ -18,6 +19,11 @@ func NewMiddleware(config *AuthConfig) *Middleware {
 	}
 }
 
+// SetPolicy makes tool calls subject to a policy; nil leaves only the role permissions
+func (m *Middleware) SetPolicy(policy *PolicyEngine) {
+	m.policy = policy
+}
+
 // AuthorizeRequest checks if the HTTP request is authorized
 func (m *Middleware) AuthorizeRequest(r *http.Request) (*AuthResult, error) {
 	// Skip authentication if disabled
@@ -69,6 +75,13 @@ func (m *Middleware) HTTPMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 		// Add auth result to request context
 		if authResult != nil {
+			if impersonation := m.authenticator.config.Impersonation; impersonation.Enabled {
+				header := impersonation.Header
+				if header == "" {
+					header = defaultOnBehalfOfHeader
+				}
+				authResult.OnBehalfOf = r.Header.Get(header)
+			}
 			// Store auth result in request context for later use
 			r = r.WithContext(WithAuthResult(r.Context(), authResult))
 		}
@@ -85,13 +98,5 @@ func (m *Middleware) GetRolesList() []string {
 
 // GetToolsList returns a list of available tools and their required permissions
 func (m *Middleware) GetToolsList() map[string][]string {
-	return map[string][]string{
-		"database_query": {"read", "write", "admin"},
-		"loki_query":     {"read", "write", "admin", "monitor"},
-		"s3_query":       {"read", "write", "admin"},
-		"sentry_query":   {"monitor", "admin"},
-		"swagger_query":  {"read", "write", "admin"},
-		"llm_chat":       {"write", "admin"},
-		"http_request":   {"write", "admin"},
-	}
+	return m.authenticator.ToolPermissions()
 }
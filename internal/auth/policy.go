@@ -0,0 +1,464 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/logging"
+)
+
+// PolicyConfig points at the policy file and describes the environment its rules can match
+type PolicyConfig struct {
+	File          string            `yaml:"file"`           // YAML file of rules; no policy when empty
+	Environment   map[string]string `yaml:"environment"`    // Labels of this deployment, e.g. env: production
+	ReloadSeconds int               `yaml:"reload_seconds"` // How often the file is checked for changes, 10 by default
+}
+
+// Policy effects
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// PolicyRule allows or denies the tool calls it matches. Every field that is set must match; a rule
+// with no fields matches every call.
+type PolicyRule struct {
+	Name        string            `yaml:"name"`
+	Effect      string            `yaml:"effect"`      // allow or deny
+	Tools       []string          `yaml:"tools"`       // Tool names or globs
+	Roles       []string          `yaml:"roles"`       // The caller has any of these roles
+	Callers     []string          `yaml:"callers"`     // API key names or globs
+	Environment map[string]string `yaml:"environment"` // Label -> glob the deployment's label must match
+	When        []PolicyCondition `yaml:"when"`        // Conditions on the arguments, all of which must hold
+	Message     string            `yaml:"message"`     // Shown to the caller when the rule denies a call
+}
+
+// PolicyCondition tests an argument, addressed by a dotted path such as options.bucket. For an array
+// argument it holds when any element passes. Exactly one test is set.
+type PolicyCondition struct {
+	Arg          string        `yaml:"arg"`
+	Equals       interface{}   `yaml:"equals"`
+	In           []interface{} `yaml:"in"`
+	Prefix       string        `yaml:"prefix"`
+	Glob         string        `yaml:"glob"`          // * stays within a path segment, ** crosses them
+	Matches      string        `yaml:"matches"`       // Regular expression
+	SQLOperation []string      `yaml:"sql_operation"` // Statement kinds of an SQL argument, e.g. insert, update, drop
+	Exists       *bool         `yaml:"exists"`
+	Not          bool          `yaml:"not"` // Negate the test
+
+	pattern *regexp.Regexp
+}
+
+// policyFile is the content of a policy file
+type policyFile struct {
+	Rules []PolicyRule `yaml:"rules"`
+}
+
+// PolicyDecision is the outcome of the first rule that matched a call
+type PolicyDecision struct {
+	Effect  string
+	Rule    string
+	Message string
+}
+
+// PolicyEngine evaluates tool calls against the rules of a policy file, first match wins, and reloads
+// the file when it changes. Calls no rule matches fall through to the role permissions.
+type PolicyEngine struct {
+	file        string
+	environment map[string]string
+
+	rules   atomic.Pointer[[]PolicyRule]
+	modTime time.Time
+}
+
+// NewPolicyEngine loads the policy file and watches it for changes; it returns nil without a file
+func NewPolicyEngine(config PolicyConfig) (*PolicyEngine, error) {
+	if config.File == "" {
+		return nil, nil
+	}
+	e := &PolicyEngine{file: config.File, environment: config.Environment}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(config.ReloadSeconds) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	go e.watch(interval)
+	return e, nil
+}
+
+// load reads and validates the policy file
+func (e *PolicyEngine) load() error {
+	info, err := os.Stat(e.file)
+	if err != nil {
+		return fmt.Errorf("failed to read policy: %w", err)
+	}
+	data, err := os.ReadFile(e.file)
+	if err != nil {
+		return fmt.Errorf("failed to read policy: %w", err)
+	}
+	var f policyFile
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return fmt.Errorf("policy %s: %w", e.file, err)
+	}
+	for i := range f.Rules {
+		if err := f.Rules[i].compile(i); err != nil {
+			return fmt.Errorf("policy %s: %w", e.file, err)
+		}
+	}
+	e.rules.Store(&f.Rules)
+	e.modTime = info.ModTime()
+	return nil
+}
+
+// watch reloads the policy when the file changes; an invalid file keeps the rules in force
+func (e *PolicyEngine) watch(interval time.Duration) {
+	for range time.Tick(interval) {
+		info, err := os.Stat(e.file)
+		if err != nil || info.ModTime().Equal(e.modTime) {
+			continue
+		}
+		if err := e.load(); err != nil {
+			e.modTime = info.ModTime()
+			logging.ServerLogger.Error("policy not reloaded; the previous rules stay in force", logging.Error(err))
+			continue
+		}
+		logging.ServerLogger.Info("policy reloaded", logging.String("file", e.file), logging.Int("rules", len(*e.rules.Load())))
+	}
+}
+
+// compile validates a rule and compiles its patterns
+func (r *PolicyRule) compile(index int) error {
+	if r.Name == "" {
+		r.Name = fmt.Sprintf("rule %d", index+1)
+	}
+	if r.Effect != EffectAllow && r.Effect != EffectDeny {
+		return fmt.Errorf("%s: effect must be allow or deny", r.Name)
+	}
+	for _, pattern := range append(append([]string{}, r.Tools...), r.Callers...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: invalid pattern %q", r.Name, pattern)
+		}
+	}
+	for label, pattern := range r.Environment {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: invalid pattern %q for environment label %s", r.Name, pattern, label)
+		}
+	}
+	for i := range r.When {
+		c := &r.When[i]
+		if c.Arg == "" {
+			return fmt.Errorf("%s: condition %d has no arg", r.Name, i+1)
+		}
+		tests := 0
+		for _, set := range []bool{c.Equals != nil, c.In != nil, c.Prefix != "", c.Glob != "", c.Matches != "", c.SQLOperation != nil, c.Exists != nil} {
+			if set {
+				tests++
+			}
+		}
+		if tests != 1 {
+			return fmt.Errorf("%s: condition on %s needs exactly one of equals, in, prefix, glob, matches, sql_operation and exists", r.Name, c.Arg)
+		}
+		var err error
+		switch {
+		case c.Matches != "":
+			c.pattern, err = regexp.Compile(c.Matches)
+		case c.Glob != "":
+			c.pattern, err = globPattern(c.Glob)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: condition on %s: %w", r.Name, c.Arg, err)
+		}
+		c.Equals = normalizeYAML(c.Equals)
+		for j := range c.In {
+			c.In[j] = normalizeYAML(c.In[j])
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the decision of the first rule matching a call, and false when none matches
+func (e *PolicyEngine) Evaluate(authResult *AuthResult, tool string, arguments json.RawMessage) (PolicyDecision, bool) {
+	var args map[string]interface{}
+	if len(arguments) > 0 {
+		// Arguments that are not an object only match rules without conditions
+		_ = json.Unmarshal(arguments, &args)
+	}
+	for _, rule := range *e.rules.Load() {
+		if !e.matchesCall(&rule, authResult, tool) || !rule.matchesArguments(args) {
+			continue
+		}
+		return PolicyDecision{Effect: rule.Effect, Rule: rule.Name, Message: rule.Message}, true
+	}
+	return PolicyDecision{}, false
+}
+
+// Listable decides whether a tool is listed for a caller before any arguments are known: a matching
+// rule without conditions decides, and a matching allow rule with conditions lists the tool because
+// some calls may pass. The bool is false when the role permissions decide.
+func (e *PolicyEngine) Listable(authResult *AuthResult, tool string) (bool, bool) {
+	for _, rule := range *e.rules.Load() {
+		if !e.matchesCall(&rule, authResult, tool) {
+			continue
+		}
+		if len(rule.When) == 0 {
+			return rule.Effect == EffectAllow, true
+		}
+		if rule.Effect == EffectAllow {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// matchesCall checks the tool, caller and environment of a rule
+func (e *PolicyEngine) matchesCall(rule *PolicyRule, authResult *AuthResult, tool string) bool {
+	if len(rule.Tools) > 0 && !matchAny(rule.Tools, tool) {
+		return false
+	}
+	if len(rule.Callers) > 0 && (authResult == nil || !matchAny(rule.Callers, authResult.Username)) {
+		return false
+	}
+	if len(rule.Roles) > 0 {
+		if authResult == nil {
+			return false
+		}
+		held := false
+		for _, role := range rule.Roles {
+			held = held || role == "*" || authResult.HasRole(role)
+		}
+		if !held {
+			return false
+		}
+	}
+	for label, pattern := range rule.Environment {
+		if matched, _ := path.Match(pattern, e.environment[label]); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesArguments checks the conditions of a rule
+func (r *PolicyRule) matchesArguments(args map[string]interface{}) bool {
+	for i := range r.When {
+		if !r.When[i].holds(args) {
+			return false
+		}
+	}
+	return true
+}
+
+// holds tests the condition's argument
+func (c *PolicyCondition) holds(args map[string]interface{}) bool {
+	value, found := lookupArgument(args, c.Arg)
+	if c.Exists != nil {
+		return (found == *c.Exists) != c.Not
+	}
+	if !found {
+		return c.Not
+	}
+	values := []interface{}{value}
+	if list, ok := value.([]interface{}); ok {
+		values = list
+	}
+	for _, v := range values {
+		if c.test(v) {
+			return !c.Not
+		}
+	}
+	return c.Not
+}
+
+// test applies the condition's test to one value
+func (c *PolicyCondition) test(value interface{}) bool {
+	switch {
+	case c.Equals != nil:
+		return sameValue(c.Equals, value)
+	case c.In != nil:
+		for _, candidate := range c.In {
+			if sameValue(candidate, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return false
+	}
+	switch {
+	case c.Prefix != "":
+		return strings.HasPrefix(text, c.Prefix)
+	case c.pattern != nil:
+		return c.pattern.MatchString(text)
+	default:
+		for _, operation := range sqlOperations(text) {
+			for _, wanted := range c.SQLOperation {
+				if strings.EqualFold(operation, wanted) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// lookupArgument follows a dotted path through nested objects
+func lookupArgument(args map[string]interface{}, name string) (interface{}, bool) {
+	var current interface{} = args
+	for _, key := range strings.Split(name, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// sameValue compares a value from the policy with one from the arguments; numbers compare by value
+func sameValue(expected, actual interface{}) bool {
+	if e, ok := toFloat(expected); ok {
+		a, ok := toFloat(actual)
+		return ok && a == e
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// normalizeYAML turns the maps decoded from YAML into JSON objects, so they compare with arguments
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// globPattern compiles a glob where * and ? stay within a path segment and ** crosses segments
+func globPattern(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// sqlStatementKeywords are the keywords that name a statement after a WITH clause
+var sqlStatementKeywords = map[string]bool{"select": true, "insert": true, "update": true, "delete": true, "merge": true}
+
+// sqlOperations returns the kind of every statement of an SQL text in lower case, such as select,
+// insert or drop; the statement after a WITH clause counts, not the WITH itself
+func sqlOperations(sql string) []string {
+	var operations []string
+	depth := 0
+	expectStatement, inWith := true, false
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case strings.HasPrefix(sql[i:], "--"):
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return operations
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			i++
+			for i < len(sql) && sql[i] != c {
+				i++
+			}
+			i++
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+		case c == ';':
+			expectStatement, inWith, depth = true, false, 0
+			i++
+		case isWordByte(c):
+			start := i
+			for i < len(sql) && isWordByte(sql[i]) {
+				i++
+			}
+			word := strings.ToLower(sql[start:i])
+			switch {
+			case expectStatement && word == "with":
+				expectStatement, inWith = false, true
+			case expectStatement:
+				operations = append(operations, word)
+				expectStatement = false
+			case inWith && depth == 0 && sqlStatementKeywords[word]:
+				operations = append(operations, word)
+				inWith = false
+			}
+		default:
+			i++
+		}
+	}
+	return operations
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
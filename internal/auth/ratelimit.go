@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/logging"
+)
+
+// RateLimitConfig limits how often each caller may call tools
+type RateLimitConfig struct {
+	Enabled           bool           `yaml:"enabled"`
+	RequestsPerMinute int            `yaml:"requests_per_minute"` // Tool calls per key per minute; 0 means unlimited
+	ToolQuotas        map[string]int `yaml:"tool_quotas"`         // Tool name or glob -> calls per key per minute for each matching tool
+}
+
+// bucketIdle is how long an unused bucket is kept; after a minute it is full again and equal to a new one
+const bucketIdle = time.Minute
+
+// bucket is a token bucket that holds up to limit calls and refills limit tokens per minute
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter enforces per-key and per-tool call rates with token buckets, so a key may burst up to
+// its limit and then continues at the limit's steady rate
+type RateLimiter struct {
+	config    RateLimitConfig
+	keyLimits map[string]int // API key name -> its own requests_per_minute
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// RateLimitError describes a rejected call
+type RateLimitError struct {
+	Scope      string // "key" or "tool"
+	Caller     string
+	Tool       string
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Scope == "tool" {
+		return fmt.Sprintf("quota of %d %s calls per minute exceeded for %s; retry after %s", e.Limit, e.Tool, e.Caller, e.retryAfter())
+	}
+	return fmt.Sprintf("rate limit of %d tool calls per minute exceeded for %s; retry after %s", e.Limit, e.Caller, e.retryAfter())
+}
+
+// retryAfter rounds the wait up to whole seconds
+func (e *RateLimitError) retryAfter() time.Duration {
+	return time.Duration(math.Ceil(e.RetryAfter.Seconds())) * time.Second
+}
+
+// NewRateLimiter creates a rate limiter; API keys with requests_per_minute set override the default
+func NewRateLimiter(config RateLimitConfig, apiKeys []APIKey) *RateLimiter {
+	keyLimits := map[string]int{}
+	for _, key := range apiKeys {
+		if key.RequestsPerMinute > 0 {
+			keyLimits[key.Name] = key.RequestsPerMinute
+		}
+	}
+	return &RateLimiter{
+		config:    config,
+		keyLimits: keyLimits,
+		buckets:   map[string]*bucket{},
+		now:       time.Now,
+	}
+}
+
+// IsEnabled returns whether rate limiting is enabled
+func (r *RateLimiter) IsEnabled() bool {
+	return r.config.Enabled
+}
+
+// Allow takes one call of tool by caller from the caller's key bucket and the tool's quota bucket.
+// Nothing is taken when either is exhausted, so rejected calls do not count against the caller.
+func (r *RateLimiter) Allow(caller, tool string) error {
+	keyLimit := r.config.RequestsPerMinute
+	if limit, ok := r.keyLimits[caller]; ok {
+		keyLimit = limit
+	}
+	toolLimit := r.ToolQuota(tool)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	var keyBucket, toolBucket *bucket
+	if keyLimit > 0 {
+		keyBucket = r.refill("key\x00"+caller, keyLimit, now)
+		if keyBucket.tokens < 1 {
+			return &RateLimitError{Scope: "key", Caller: caller, Tool: tool, Limit: keyLimit, RetryAfter: wait(keyBucket, keyLimit)}
+		}
+	}
+	if toolLimit > 0 {
+		toolBucket = r.refill("tool\x00"+caller+"\x00"+tool, toolLimit, now)
+		if toolBucket.tokens < 1 {
+			return &RateLimitError{Scope: "tool", Caller: caller, Tool: tool, Limit: toolLimit, RetryAfter: wait(toolBucket, toolLimit)}
+		}
+	}
+
+	if keyBucket != nil {
+		keyBucket.tokens--
+	}
+	if toolBucket != nil {
+		toolBucket.tokens--
+	}
+	return nil
+}
+
+// ToolQuota returns the per-minute quota of a tool, 0 when it has none. An exact entry wins over glob
+// patterns; among patterns the longest match wins, as for tool permissions.
+func (r *RateLimiter) ToolQuota(tool string) int {
+	if quota, ok := r.config.ToolQuotas[tool]; ok {
+		return quota
+	}
+
+	best, quota := "", 0
+	for pattern, patternQuota := range r.config.ToolQuotas {
+		if matched, err := path.Match(pattern, tool); err != nil || !matched {
+			continue
+		}
+		if len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best, quota = pattern, patternQuota
+		}
+	}
+	return quota
+}
+
+// refill returns the bucket for key with the tokens earned since it was last used
+func (r *RateLimiter) refill(key string, limit int, now time.Time) *bucket {
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit), last: now}
+		r.buckets[key] = b
+		return b
+	}
+	elapsed := now.Sub(b.last).Minutes()
+	b.tokens = math.Min(float64(limit), b.tokens+elapsed*float64(limit))
+	b.last = now
+	return b
+}
+
+// wait is the time until the bucket holds a whole token again
+func wait(b *bucket, limit int) time.Duration {
+	return time.Duration((1 - b.tokens) / float64(limit) * float64(time.Minute))
+}
+
+// sweep drops buckets that have been idle long enough to be full again, at most once a minute
+func (r *RateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < bucketIdle {
+		return
+	}
+	r.lastSweep = now
+	for key, b := range r.buckets {
+		if now.Sub(b.last) >= bucketIdle {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// Middleware returns MCP receiving middleware that applies the rate limiter to tools/call, keyed by
+// caller. It should run after ToolMiddleware, so calls are counted only once they are authorized.
+// Rejected calls get a tool error result whose structured content carries the limit and retry_after_seconds.
+func (r *RateLimiter) Middleware(caller func(ctx context.Context, req mcp.Request) string) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if !r.IsEnabled() || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			name := caller(ctx, req)
+			err := r.Allow(name, callReq.Params.Name)
+			limitErr, ok := err.(*RateLimitError)
+			if !ok {
+				return next(ctx, method, req)
+			}
+
+			retryAfter := int(limitErr.retryAfter().Seconds())
+			logging.ToolLogger.Warn("tool call rate limited",
+				logging.String("tool", limitErr.Tool),
+				logging.String("user", name),
+				logging.String("scope", limitErr.Scope),
+				logging.Int("retry_after_seconds", retryAfter))
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Rate Limit Error: %v", limitErr)}},
+				StructuredContent: map[string]interface{}{
+					"error":               "rate_limited",
+					"scope":               limitErr.Scope,
+					"tool":                limitErr.Tool,
+					"limit_per_minute":    limitErr.Limit,
+					"retry_after_seconds": retryAfter,
+				},
+				IsError: true,
+			}, nil
+		}
+	}
+}
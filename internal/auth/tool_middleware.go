@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/logging"
+)
+
+// ToolMiddleware returns MCP receiving middleware that enforces the policy and per-tool role permissions.
+// tools/call is rejected unless the first matching policy rule allows it or, when none matches, the
+// caller holds one of the tool's roles; tools/list only returns the tools the caller may call.
+func (m *Middleware) ToolMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if !m.IsEnabled() || (method != "tools/call" && method != "tools/list") {
+				return next(ctx, method, req)
+			}
+
+			authResult, err := m.principal(ctx, req)
+			if err != nil {
+				logging.ToolLogger.Warn("tool request rejected", logging.String("method", method), logging.Error(err))
+				return nil, fmt.Errorf("authentication failed: %w", err)
+			}
+
+			if method == "tools/list" {
+				result, err := next(ctx, method, req)
+				if list, ok := result.(*mcp.ListToolsResult); ok && err == nil {
+					allowed := list.Tools[:0]
+					for _, tool := range list.Tools {
+						if m.listable(authResult, tool.Name) {
+							allowed = append(allowed, tool)
+						}
+					}
+					list.Tools = allowed
+				}
+				return result, err
+			}
+
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+			if err := m.AuthorizeToolCall(authResult, callReq.Params.Name, callReq.Params.Arguments); err != nil {
+				return denied(authResult, callReq.Params.Name, err), nil
+			}
+
+			return next(WithAuthResult(ctx, authResult), method, req)
+		}
+	}
+}
+
+// PolicyMiddleware returns MCP receiving middleware that enforces only the policy, for callers the
+// role permissions trust: the local stdio user, or everyone when authentication is disabled. Calls
+// no rule matches are allowed.
+func (m *Middleware) PolicyMiddleware(principal *AuthResult) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if m.policy == nil {
+				return next(ctx, method, req)
+			}
+			switch method {
+			case "tools/list":
+				result, err := next(ctx, method, req)
+				if list, ok := result.(*mcp.ListToolsResult); ok && err == nil {
+					allowed := list.Tools[:0]
+					for _, tool := range list.Tools {
+						if listed, decided := m.policy.Listable(principal, tool.Name); listed || !decided {
+							allowed = append(allowed, tool)
+						}
+					}
+					list.Tools = allowed
+				}
+				return result, err
+			case "tools/call":
+				callReq, ok := req.(*mcp.CallToolRequest)
+				if !ok || callReq.Params == nil {
+					break
+				}
+				if decision, ok := m.policy.Evaluate(principal, callReq.Params.Name, callReq.Params.Arguments); ok && decision.Effect == EffectDeny {
+					return denied(principal, callReq.Params.Name, policyError(callReq.Params.Name, decision)), nil
+				}
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// AuthorizeToolCall decides a call by the first matching policy rule, or by the role permissions
+// when no rule matches
+func (m *Middleware) AuthorizeToolCall(authResult *AuthResult, tool string, arguments json.RawMessage) error {
+	if m.policy != nil {
+		if decision, ok := m.policy.Evaluate(authResult, tool, arguments); ok {
+			if decision.Effect == EffectDeny {
+				return policyError(tool, decision)
+			}
+			return nil
+		}
+	}
+	return m.CheckToolPermission(authResult, tool)
+}
+
+// AuthorizeAs decides a request that is not a tool call, such as a resource read, as a call of tool with
+// arguments, so the resources of a provider are open to the callers of its tools
+func (m *Middleware) AuthorizeAs(ctx context.Context, req mcp.Request, tool string, arguments json.RawMessage) error {
+	if !m.IsEnabled() {
+		return nil
+	}
+	authResult, err := m.principal(ctx, req)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	return m.AuthorizeToolCall(authResult, tool, arguments)
+}
+
+// listable reports whether tools/list shows a tool to the caller
+func (m *Middleware) listable(authResult *AuthResult, tool string) bool {
+	if m.policy != nil {
+		if listed, decided := m.policy.Listable(authResult, tool); decided {
+			return listed
+		}
+	}
+	return m.authenticator.HasPermission(authResult, tool)
+}
+
+func policyError(tool string, decision PolicyDecision) error {
+	if decision.Message != "" {
+		return fmt.Errorf("%s denied by policy rule %q: %s", tool, decision.Rule, decision.Message)
+	}
+	return fmt.Errorf("%s denied by policy rule %q", tool, decision.Rule)
+}
+
+// denied logs a rejected call and returns its error result
+func denied(authResult *AuthResult, tool string, err error) *mcp.CallToolResult {
+	logging.ToolLogger.Warn("tool call denied",
+		logging.String("tool", tool),
+		logging.String("user", authResult.Username),
+		logging.String("roles", strings.Join(authResult.Roles, ",")),
+		logging.Error(err))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Authorization Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+// Caller names the principal of a request for audit records: the API key name, "anonymous" when
+// authentication is disabled, or "unauthenticated" when the request carries no valid credentials
+func (m *Middleware) Caller(ctx context.Context, req mcp.Request) string {
+	if authResult, err := m.principal(ctx, req); err == nil {
+		return authResult.Username
+	}
+	if !m.IsEnabled() {
+		return "anonymous"
+	}
+	return "unauthenticated"
+}
+
+// Locale returns the locale configured for the caller's API key, or "" when it has none
+func (m *Middleware) Locale(ctx context.Context, req mcp.Request) string {
+	if authResult, err := m.principal(ctx, req); err == nil {
+		return authResult.Locale
+	}
+	return ""
+}
+
+// principal resolves the caller: the auth result attached to the session context by the
+// HTTP middleware, or else the Authorization header of the request itself. The HTTP transports
+// only route a request to a session when its key is the one that opened the session.
+func (m *Middleware) principal(ctx context.Context, req mcp.Request) (*AuthResult, error) {
+	if authResult, ok := GetAuthResult(ctx); ok && authResult != nil {
+		return authResult, nil
+	}
+
+	if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+		if authHeader := extra.Header.Get("Authorization"); authHeader != "" {
+			return m.authenticator.AuthenticateBearer(authHeader)
+		}
+	}
+
+	return nil, fmt.Errorf("no authenticated principal")
+}
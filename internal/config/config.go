@@ -5,26 +5,119 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/logging"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Loki     LokiConfig     `yaml:"loki"`
-	S3       S3Config       `yaml:"s3"`
-	Sentry   SentryConfig   `yaml:"sentry"`
-	Swagger  SwaggerConfig  `yaml:"swagger"`
-	LLM      LLMConfig      `yaml:"llm"`
-	Auth     AuthConfig     `yaml:"auth"`
+	Server      ServerConfig      `yaml:"server"`
+	Providers   ProvidersConfig   `yaml:"providers"`
+	Audit       AuditConfig       `yaml:"audit"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Databases   []DatabaseConfig  `yaml:"databases"` // Additional named connections (staging, analytics, replica...)
+	Loki        LokiConfig        `yaml:"loki"`
+	Grafana     GrafanaConfig     `yaml:"grafana"`
+	S3          S3Config          `yaml:"s3"`
+	File        FileConfig        `yaml:"file"`
+	Sentry      SentryConfig      `yaml:"sentry"`
+	Swagger     SwaggerConfig     `yaml:"swagger"`
+	LLM         LLMConfig         `yaml:"llm"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Knowledge   KnowledgeConfig   `yaml:"knowledge"`
+	Catalog     CatalogConfig     `yaml:"catalog"`
+	CICD        CICDConfig        `yaml:"cicd"`
+	CodeHost    CodeHostConfig    `yaml:"code_host"`
+	Registries  []RegistryConfig  `yaml:"registries"`
+	Terraform   TerraformConfig   `yaml:"terraform"`
+	AWS         AWSConfig         `yaml:"aws"`
+	Email       EmailConfig       `yaml:"email"`
+	Calendar    CalendarConfig    `yaml:"calendar"`
+	OnCall      OnCallConfig      `yaml:"oncall"`
+	Kubernetes  KubernetesConfig  `yaml:"kubernetes"`
+	Utility     UtilityConfig     `yaml:"utility"`
+	Git         GitConfig         `yaml:"git"`
+	Profiling   ProfilingConfig   `yaml:"profiling"`
+	Artifacts   ArtifactsConfig   `yaml:"artifacts"`
+	Exec        ExecConfig        `yaml:"exec"`
+	CodeQuality CodeQualityConfig `yaml:"code_quality"`
+	SBOM        SBOMConfig        `yaml:"sbom"`
+	Proto       ProtoConfig       `yaml:"proto"`
+	Kafka       KafkaConfig       `yaml:"kafka"`
+	MongoDB     MongoDBConfig     `yaml:"mongodb"`
+	Search      SearchConfig      `yaml:"search"`
+	Queue       QueueConfig       `yaml:"queue"`
+	Cache       CacheConfig       `yaml:"cache"`
+	Deploy      DeployConfig      `yaml:"deploy"`
+	SLO         SLOConfig         `yaml:"slo"`
+	Health      HealthConfig      `yaml:"health"`
+	Prompts     PromptsConfig     `yaml:"prompts"`
+	I18n        I18nConfig        `yaml:"i18n"`
+	Hints       HintsConfig       `yaml:"hints"`
+	Output      OutputConfig      `yaml:"output"`
+	Mock        MockConfig        `yaml:"mock"`
+	Store       StoreConfig       `yaml:"store"`
+
+	// Dotenv or YAML file merged at load time, so credentials can stay out of this file (MCP_SECRETS_FILE overrides)
+	SecretsFile string        `yaml:"secrets_file"`
+	Secrets     SecretsConfig `yaml:"secrets"`
+
+	secretWatcher *SecretWatcher // Re-resolves the secret references; nil when there are none
 }
 
 // AuthConfig represents the authentication configuration
 type AuthConfig struct {
-	Enabled bool     `yaml:"enabled"`
-	APIKeys []APIKey `yaml:"api_keys"`
+	Enabled         bool                `yaml:"enabled"`
+	APIKeys         []APIKey            `yaml:"api_keys"`
+	ToolPermissions map[string][]string `yaml:"tool_permissions"` // Tool name or glob (e.g. "database_*") -> allowed roles
+	RateLimit       RateLimitConfig     `yaml:"rate_limit"`
+	Policy          PolicyConfig        `yaml:"policy"`
+	Impersonation   ImpersonationConfig `yaml:"impersonation"`
+}
+
+// PolicyConfig points at the rules that allow or deny tool calls by tool, arguments, caller and environment
+type PolicyConfig struct {
+	File          string            `yaml:"file"`           // YAML rules, reloaded when the file changes; no policy when empty
+	Environment   map[string]string `yaml:"environment"`    // Labels of this deployment that rules can match, e.g. env: production
+	ReloadSeconds int               `yaml:"reload_seconds"` // How often the file is checked for changes, 10 by default
+}
+
+// ImpersonationConfig lets trusted callers name the user they act for, which is forwarded to downstream APIs
+type ImpersonationConfig struct {
+	Enabled     bool                          `yaml:"enabled"`
+	Header      string                        `yaml:"header"`      // HTTP request header naming the user, X-On-Behalf-Of by default
+	MetaKey     string                        `yaml:"meta_key"`    // tools/call _meta key naming the user, dev-mcp/on_behalf_of by default
+	Roles       []string                      `yaml:"roles"`       // Roles whose callers may name a user, admin by default
+	Downstreams map[string]DownstreamIdentity `yaml:"downstreams"` // sentry, grafana, pagerduty, opsgenie, github, gitlab or jenkins -> how the user is forwarded
+}
+
+// DownstreamIdentity configures how the user reaches one downstream API
+type DownstreamIdentity struct {
+	Header        string              `yaml:"header"`         // Header carrying the user name, e.g. X-Forwarded-User
+	Tokens        map[string]string   `yaml:"tokens"`         // User -> personal token used instead of the service account's
+	TokenExchange TokenExchangeConfig `yaml:"token_exchange"` // OAuth 2.0 token exchange issuing tokens for users without one
+	AuthHeader    string              `yaml:"auth_header"`    // Header the user's token goes in, Authorization by default
+	AuthScheme    string              `yaml:"auth_scheme"`    // Prefix of the token, Bearer by default; "-" for none
+	Required      bool                `yaml:"required"`       // Fail requests for users no token is available for
+}
+
+// TokenExchangeConfig is an RFC 8693 token endpoint that issues tokens for a requested_subject
+type TokenExchangeConfig struct {
+	URL          string `yaml:"url"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	Audience     string `yaml:"audience"`
+	Scope        string `yaml:"scope"`
+}
+
+// RateLimitConfig limits how often each API key may call tools
+type RateLimitConfig struct {
+	Enabled           bool           `yaml:"enabled"`
+	RequestsPerMinute int            `yaml:"requests_per_minute"` // Tool calls per key per minute; 0 (default) means unlimited
+	ToolQuotas        map[string]int `yaml:"tool_quotas"`         // Tool name or glob -> calls per key per minute for each matching tool
 }
 
 // APIKey represents an API key for authentication
@@ -33,22 +126,186 @@ type APIKey struct {
 	Key     string   `yaml:"key"`
 	Roles   []string `yaml:"roles"`
 	Enabled bool     `yaml:"enabled"`
+
+	RequestsPerMinute int    `yaml:"requests_per_minute"` // Overrides auth.rate_limit.requests_per_minute for this key
+	Locale            string `yaml:"locale"`              // Language of tool descriptions and errors for this key, e.g. zh-CN
 }
 
 // ServerConfig represents the server configuration
 type ServerConfig struct {
-	Port int    `yaml:"port"`
-	Host string `yaml:"host"`
+	Port      int    `yaml:"port"`
+	Host      string `yaml:"host"`
+	Transport string `yaml:"transport"` // stdio, sse (default) or http (Streamable HTTP)
+
+	// Streamable HTTP settings
+	Path                   string   `yaml:"path"`                     // Endpoint path, /mcp by default
+	Stateless              bool     `yaml:"stateless"`                // No Mcp-Session-Id sessions; every request stands alone
+	JSONResponse           bool     `yaml:"json_response"`            // Answer POSTs with application/json instead of an SSE stream
+	SessionIdleMinutes     int      `yaml:"session_idle_minutes"`     // Idle sessions are closed after this long, 30 by default
+	ResumeBufferBytes      int      `yaml:"resume_buffer_bytes"`      // Events kept for Last-Event-ID resumption, 10MB by default
+	AllowedOrigins         []string `yaml:"allowed_origins"`          // Browser origins allowed besides localhost, whose hosts may also be named by Host; "*" allows any
+	ShutdownTimeoutSeconds int      `yaml:"shutdown_timeout_seconds"` // Grace period for in-flight requests, 15 by default
+
+	Pprof bool `yaml:"pprof"` // Serve net/http/pprof at /debug/pprof/ to admin callers on HTTP transports
+
+	StdioQueueBytes int `yaml:"stdio_queue_bytes"` // Output queued for stdout before the stdio transport blocks, 4MB by default
+}
+
+// ProvidersConfig selects the providers the server starts, by name (e.g. database, loki, s3, kubernetes)
+type ProvidersConfig struct {
+	Enabled  []string `yaml:"enabled"`  // Only start these providers; all of them when empty
+	Disabled []string `yaml:"disabled"` // Never start these providers
+}
+
+// AuditConfig represents the audit log of tool invocations
+type AuditConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Sink        string   `yaml:"sink"`          // file (JSON lines) or log (the server log), file by default
+	Path        string   `yaml:"path"`          // JSON lines file for the file sink, ./logs/audit.jsonl by default
+	MaxSizeMB   int      `yaml:"max_size_mb"`   // Size at which the file is rotated, 10 by default
+	MaxFiles    int      `yaml:"max_files"`     // Rotated files kept besides the current one, 5 by default
+	MaxArgBytes int      `yaml:"max_arg_bytes"` // Recorded size of a call's arguments, 4096 by default
+	RedactKeys  []string `yaml:"redact_keys"`   // Argument names redacted besides passwords, secrets, tokens and keys
+	Recent      int      `yaml:"recent"`        // Invocations kept in memory for audit_query, 1000 by default
+}
+
+// PromptsConfig configures the prompt templates offered through the MCP prompts capability
+type PromptsConfig struct {
+	Directory string `yaml:"directory"` // One prompt per *.yaml file, configs/prompts by default
+}
+
+// MockConfig serves every tool call from fixture files instead of the real backends, for demos and client tests
+type MockConfig struct {
+	Enabled     bool     `yaml:"enabled"`     // Also enabled with --mock
+	Directory   string   `yaml:"directory"`   // One <tool>.yaml fixture per tool, configs/mock by default
+	Passthrough []string `yaml:"passthrough"` // Tool names or globs that still run for real, e.g. time_*
+}
+
+// StoreConfig configures the embedded key-value store that keeps server state across restarts
+type StoreConfig struct {
+	Path string `yaml:"path"` // Log file of the store, ./data/dev-mcp.db by default; created on the first write
+	Sync bool   `yaml:"sync"` // fsync after every commit, trading write speed for durability on power loss
+}
+
+// OutputConfig sets how tool results are rendered; a session can change it with output_preferences
+type OutputConfig struct {
+	OutputPreferences `yaml:",inline"`
+	Clients           map[string]OutputPreferences `yaml:"clients"` // MCP client name (clientInfo.name) -> preferences overriding the defaults
+
+	// Oversized results are written to the spill directory and replaced by a preview and a results:// link
+	SpillDirectory      string `yaml:"spill_directory"`       // <temp dir>/dev-mcp-results by default
+	SpillRetentionHours int    `yaml:"spill_retention_hours"` // Spilled results are deleted after this long, 24 by default
+	PreviewBytes        int    `yaml:"preview_bytes"`         // Text kept in the result as a preview, 4096 by default
+}
+
+// OutputPreferences are rendering preferences; unset fields keep the current rendering
+type OutputPreferences struct {
+	Format     string `yaml:"format"`      // pretty (indented JSON, default), json (compact JSON) or text (YAML-like)
+	Emoji      *bool  `yaml:"emoji"`       // Emoji in text results; unset means on
+	MaxRows    int    `yaml:"max_rows"`    // Items kept per list in JSON results; 0 (default) keeps all
+	DateFormat string `yaml:"date_format"` // rfc3339 (default), unix, unix_ms or a Go layout such as "2006-01-02 15:04:05"
+	Timezone   string `yaml:"timezone"`    // IANA zone for timestamps, e.g. Asia/Shanghai; unset leaves them as returned
+
+	MaxResultBytes int `yaml:"max_result_bytes"` // Larger results spill to a file; 1MB by default, -1 never spills
+}
+
+// I18nConfig configures the translation of tool descriptions and error results
+type I18nConfig struct {
+	Directory     string `yaml:"directory"`      // One <locale>.yaml catalog per locale, configs/i18n by default
+	DefaultLocale string `yaml:"default_locale"` // Used when neither the request nor the API key picks a locale; en by default
+}
+
+// HintsConfig configures the live hints appended to tool descriptions in tools/list, such as the database
+// connections, buckets and Loki labels available
+type HintsConfig struct {
+	Enabled        bool `yaml:"enabled"`
+	RefreshSeconds int  `yaml:"refresh_seconds"` // Hints are gathered again in the background after this long, 300 by default
+	TimeoutSeconds int  `yaml:"timeout_seconds"` // Bounds gathering hints, 5 by default; only the first tools/list waits for them
+	MaxChars       int  `yaml:"max_chars"`       // Longer hints are cut, 500 by default
+}
+
+// SecretsConfig configures the backends that resolve secret references such as vault:secret/data/db#password
+// or aws-sm:prod/db#password in configuration values
+type SecretsConfig struct {
+	RefreshSeconds int              `yaml:"refresh_seconds"` // Resolve the references again this often and restart the providers whose values changed; 0 resolves them at load time only
+	TimeoutSeconds int              `yaml:"timeout_seconds"` // Per secret read, 10 by default
+	Vault          VaultConfig      `yaml:"vault"`
+	AWS            AWSSecretsConfig `yaml:"aws_sm"`
+}
+
+// VaultConfig is the HashiCorp Vault server read by vault: references (KV v1 or v2)
+type VaultConfig struct {
+	Address   string `yaml:"address"`    // VAULT_ADDR by default
+	Token     string `yaml:"token"`      // VAULT_TOKEN by default
+	TokenFile string `yaml:"token_file"` // Read on every refresh, e.g. the sink of a Vault agent; used when token is empty
+	Namespace string `yaml:"namespace"`  // Vault Enterprise namespace
+}
+
+// AWSSecretsConfig is the AWS Secrets Manager read by aws-sm: references
+type AWSSecretsConfig struct {
+	Region   string `yaml:"region"`   // Defaults to aws.region, then s3.region
+	Profile  string `yaml:"profile"`  // Shared config profile; the default credential chain otherwise, or aws.access_key when set
+	Endpoint string `yaml:"endpoint"` // Overrides https://secretsmanager.<region>.amazonaws.com, e.g. for LocalStack
 }
 
 // DatabaseConfig represents the database configuration
 type DatabaseConfig struct {
+	Name     string `yaml:"name"`   // Connection name; the top-level database defaults to "default"
+	Driver   string `yaml:"driver"` // mysql (default) or postgres
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
 	DBName   string `yaml:"dbname"`
 	SSLMode  string `yaml:"sslmode"`
+
+	QueryTimeoutSeconds int `yaml:"query_timeout_seconds"` // Cancels queries running longer, 30 by default; database_query can override it per call
+
+	Cache       DatabaseCacheConfig     `yaml:"cache"`
+	Replicas    []DatabaseReplicaConfig `yaml:"replicas"` // Read-only queries are spread over them round-robin; writes stay on this connection
+	SlowQueries DatabaseSlowQueryConfig `yaml:"slow_queries"`
+	Masking     DatabaseMaskingConfig   `yaml:"masking"`
+}
+
+// DatabaseMaskingConfig holds the rules applied to query results before they are returned
+type DatabaseMaskingConfig struct {
+	Columns []ColumnMaskConfig `yaml:"columns"`
+	Rows    []RowFilterConfig  `yaml:"rows"`
+}
+
+// ColumnMaskConfig masks a column of a table in query results
+type ColumnMaskConfig struct {
+	Column string `yaml:"column"` // table.column, or *.column for every table
+	Action string `yaml:"action"` // hash, redact, partial, null or block
+}
+
+// RowFilterConfig drops the rows of a table whose column holds one of the values
+type RowFilterConfig struct {
+	Table  string   `yaml:"table"`
+	Column string   `yaml:"column"`
+	Values []string `yaml:"values"` // Compared with the column value as text
+}
+
+// DatabaseSlowQueryConfig controls the slow query log of a connection
+type DatabaseSlowQueryConfig struct {
+	ThresholdMs int `yaml:"threshold_ms"` // Queries running at least this long are logged, 1000 by default
+	Keep        int `yaml:"keep"`         // Slowest queries kept for database_slow_queries, 50 by default
+}
+
+// DatabaseReplicaConfig is a read replica of a connection; unset fields are taken from the connection
+type DatabaseReplicaConfig struct {
+	Name     string `yaml:"name"` // replica-1, replica-2... by default
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// DatabaseCacheConfig controls the cache of read-only query results of a connection
+type DatabaseCacheConfig struct {
+	Enabled    *bool `yaml:"enabled"`     // Unset means enabled
+	TTLSeconds int   `yaml:"ttl_seconds"` // How long a result is served from the cache, 60 by default
+	MaxEntries int   `yaml:"max_entries"` // Results kept, least recently used evicted first, 200 by default
 }
 
 // LokiConfig represents the Grafana Loki configuration
@@ -61,6 +318,16 @@ type LokiConfig struct {
 	Tenant       string `yaml:"tenant"`       // Loki tenant ID (for multi-tenant setups)
 }
 
+// GrafanaConfig represents the Grafana instance whose dashboards are searched and annotated
+type GrafanaConfig struct {
+	URL            string   `yaml:"url"`       // e.g. https://grafana.internal; the provider is disabled when empty
+	APIToken       string   `yaml:"api_token"` // Service account token (Viewer; Editor to create annotations)
+	OrgID          int      `yaml:"org_id"`    // Organization to act in, the token's own when 0
+	TimeoutSeconds int      `yaml:"timeout_seconds"`
+	WriteEnabled   bool     `yaml:"write_enabled"`   // Allows grafana_create_annotation; off by default
+	AnnotationTags []string `yaml:"annotation_tags"` // Tags added to every annotation created, e.g. ["dev-mcp"]
+}
+
 // S3Config represents the S3 configuration
 type S3Config struct {
 	Endpoint  string `yaml:"endpoint"`
@@ -68,6 +335,45 @@ type S3Config struct {
 	AccessKey string `yaml:"access_key"`
 	SecretKey string `yaml:"secret_key"`
 	Bucket    string `yaml:"bucket"`
+	ReadOnly  *bool  `yaml:"read_only"` // Blocks s3_put_object/s3_delete_object; unset means read-only
+
+	Resources S3ResourcesConfig `yaml:"resources"`
+	Select    S3SelectConfig    `yaml:"select"`
+}
+
+// S3ResourcesConfig represents the objects published as s3:// MCP resources
+type S3ResourcesConfig struct {
+	Prefixes   []string `yaml:"prefixes"`   // "bucket/prefix/" locations that can be listed and read; the whole default bucket when empty
+	Extensions []string `yaml:"extensions"` // Readable extensions, e.g. [".json", ".md"]; common text, data and image types when empty
+	MaxBytes   int64    `yaml:"max_bytes"`  // Largest object read, 1 MiB by default
+}
+
+// S3SelectConfig represents how s3_select runs its SQL
+type S3SelectConfig struct {
+	Local    bool  `yaml:"local"`     // Download the object and run the SQL in the server, for MinIO and other services without S3 Select
+	MaxBytes int64 `yaml:"max_bytes"` // Largest object downloaded when local, 64 MiB by default
+}
+
+// FileConfig represents the access of the file_* tools to the working directory
+type FileConfig struct {
+	ReadOnly *bool               `yaml:"read_only"` // Blocks writes outside the profiles; unset means read-only
+	Profiles []FileProfileConfig `yaml:"profiles"`  // Per-directory access; the longest matching path wins
+	Watch    FileWatchConfig     `yaml:"watch"`
+	// Named root directories the file tools resolve paths against with their workspace argument
+	Workspaces map[string]string `yaml:"workspaces"`
+}
+
+// FileWatchConfig controls the polling of file:// resources clients subscribe to
+type FileWatchConfig struct {
+	PollIntervalMs int `yaml:"poll_interval_ms"` // 1000 by default
+	MaxPaths       int `yaml:"max_paths"`        // Paths watched at once, 100 by default
+	MaxEntries     int `yaml:"max_entries"`      // Files compared under a watched directory, 10000 by default
+}
+
+// FileProfileConfig sets the access of a directory and everything under it
+type FileProfileConfig struct {
+	Path   string `yaml:"path"`   // e.g. ./docs
+	Access string `yaml:"access"` // read-write, read-only or deny
 }
 
 // SentryConfig represents the Sentry configuration
@@ -84,6 +390,7 @@ type SentryConfig struct {
 	ZoomWebhookURL  string            `yaml:"zoom_webhook_url"` // Zoom webhook URL
 	ZoomAuth        string            `yaml:"zoom_auth"`        // Zoom authorization header value
 	IssueQueries    map[string]string `yaml:"issue_queries"`    // Named issue queries
+	WriteEnabled    bool              `yaml:"write_enabled"`    // Allows sentry_update_issue; off by default
 }
 
 // SwaggerConfig represents the Swagger configuration
@@ -92,6 +399,394 @@ type SwaggerConfig struct {
 	Filepath string `yaml:"filepath"`
 }
 
+// KnowledgeConfig represents the org knowledge base configuration
+type KnowledgeConfig struct {
+	Dir      string `yaml:"dir"`       // Local directory with markdown/YAML docs
+	S3Bucket string `yaml:"s3_bucket"` // Optional S3 bucket holding docs
+	S3Prefix string `yaml:"s3_prefix"` // Key prefix of the docs inside the bucket
+}
+
+// CatalogConfig represents the service catalog configuration
+type CatalogConfig struct {
+	File string `yaml:"file"` // Catalog YAML: a simple services list or Backstage catalog-info entities
+}
+
+// CICDConfig represents the CI/CD pipeline provider configuration
+type CICDConfig struct {
+	GitHub       GitHubActionsConfig `yaml:"github"`
+	GitLab       GitLabCIConfig      `yaml:"gitlab"`
+	Jenkins      JenkinsConfig       `yaml:"jenkins"`
+	AllowTrigger bool                `yaml:"allow_trigger"` // Write gate for re-triggering runs
+	MaxLogBytes  int                 `yaml:"max_log_bytes"` // Tail size kept from failed job logs
+}
+
+// GitHubActionsConfig represents the GitHub Actions configuration
+type GitHubActionsConfig struct {
+	BaseURL string `yaml:"base_url"` // Defaults to https://api.github.com
+	Token   string `yaml:"token"`
+}
+
+// GitLabCIConfig represents the GitLab CI configuration
+type GitLabCIConfig struct {
+	BaseURL string `yaml:"base_url"` // Defaults to https://gitlab.com
+	Token   string `yaml:"token"`
+}
+
+// JenkinsConfig represents the Jenkins configuration
+type JenkinsConfig struct {
+	URL      string `yaml:"url"`
+	Username string `yaml:"username"`
+	APIToken string `yaml:"api_token"`
+}
+
+// CodeHostConfig represents the GitHub and GitLab accounts of the pull request and issue tools
+type CodeHostConfig struct {
+	GitHub       CodeHostServer `yaml:"github"`         // The cicd.github token is used when token is empty
+	GitLab       CodeHostServer `yaml:"gitlab"`         // The cicd.gitlab token is used when token is empty
+	DefaultRepo  string         `yaml:"default_repo"`   // owner/repo or group/project used when a tool names none
+	MaxDiffBytes int            `yaml:"max_diff_bytes"` // Patch bytes returned by code_pr_diff, 100000 by default
+	MaxComments  int            `yaml:"max_comments"`   // Comments returned by code_issue_thread, 100 by default
+}
+
+// CodeHostServer represents a GitHub or GitLab API endpoint and its token
+type CodeHostServer struct {
+	BaseURL string `yaml:"base_url"` // https://api.github.com or https://gitlab.com by default
+	Token   string `yaml:"token"`
+}
+
+// RegistryConfig represents an artifact registry (Docker registry, npm or Maven repository)
+type RegistryConfig struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"` // docker, npm or maven
+	URL      string `yaml:"url"`  // e.g. https://registry-1.docker.io, https://registry.npmjs.org
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Token    string `yaml:"token"` // Bearer token (npm) used instead of username/password
+}
+
+// TerraformConfig represents the Terraform state inspection configuration
+type TerraformConfig struct {
+	States []TerraformStateConfig `yaml:"states"`
+}
+
+// TerraformStateConfig locates a single Terraform state, either a local file or an S3 backend object
+type TerraformStateConfig struct {
+	Name   string `yaml:"name"`
+	Path   string `yaml:"path"`   // Local terraform.tfstate path
+	Bucket string `yaml:"bucket"` // S3 backend bucket (uses s3 credentials)
+	Key    string `yaml:"key"`    // S3 backend key, e.g. env/prod/terraform.tfstate
+}
+
+// AWSConfig represents the read-only AWS describe configuration.
+// Without keys or a profile, credentials come from the s3 section when it points at AWS, then the default AWS chain.
+type AWSConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	Region       string `yaml:"region"` // Defaults to s3.region
+	AccessKey    string `yaml:"access_key"`
+	SecretKey    string `yaml:"secret_key"`
+	SessionToken string `yaml:"session_token"`
+	Profile      string `yaml:"profile"` // Shared config profile used by the default credential chain
+}
+
+// EmailConfig represents the alert mailbox (IMAP, read-only) and report sending (SMTP) configuration
+type EmailConfig struct {
+	IMAPHost          string            `yaml:"imap_host"`
+	IMAPPort          int               `yaml:"imap_port"` // 993 (TLS) by default
+	SMTPHost          string            `yaml:"smtp_host"`
+	SMTPPort          int               `yaml:"smtp_port"` // 587 (STARTTLS) by default
+	Username          string            `yaml:"username"`
+	Password          string            `yaml:"password"`
+	Mailbox           string            `yaml:"mailbox"` // Defaults to INBOX
+	From              string            `yaml:"from"`    // Sender address, defaults to username
+	AllowSend         bool              `yaml:"allow_send"`
+	AllowedRecipients []string          `yaml:"allowed_recipients"` // Addresses or @domains; empty allows any
+	Templates         map[string]string `yaml:"templates"`          // Named text/template bodies for email_send
+}
+
+// CalendarConfig represents the iCal feeds of deploy freezes and on-call rotations
+type CalendarConfig struct {
+	Feeds          []CalendarFeedConfig `yaml:"feeds"`
+	Timezone       string               `yaml:"timezone"`        // Zone for floating times and all-day events, defaults to UTC
+	RefreshMinutes int                  `yaml:"refresh_minutes"` // How long a fetched feed is cached, 5 by default
+	BlockedTools   []string             `yaml:"blocked_tools"`   // Tool names or globs rejected during a deploy freeze
+	FailOpen       bool                 `yaml:"fail_open"`       // Allow blocked tools when the freeze feeds cannot be read
+}
+
+// CalendarFeedConfig locates a single iCal feed (a URL such as a Google Calendar secret address, or a local .ics file)
+type CalendarFeedConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // freeze or oncall
+	URL  string `yaml:"url"`
+}
+
+// OnCallConfig represents the PagerDuty and Opsgenie accounts of the on-call and incident tools
+type OnCallConfig struct {
+	PagerDuty    PagerDutyConfig `yaml:"pagerduty"`
+	Opsgenie     OpsgenieConfig  `yaml:"opsgenie"`
+	WriteEnabled bool            `yaml:"write_enabled"` // Allows incident_ack; off by default
+}
+
+// PagerDutyConfig represents a PagerDuty REST API account
+type PagerDutyConfig struct {
+	APIToken string `yaml:"api_token"`
+	BaseURL  string `yaml:"base_url"` // https://api.pagerduty.com by default; https://api.eu.pagerduty.com for EU accounts
+	From     string `yaml:"from"`     // Email of the PagerDuty user that acknowledges, required with account tokens
+}
+
+// OpsgenieConfig represents an Opsgenie API integration
+type OpsgenieConfig struct {
+	APIKey  string `yaml:"api_key"`
+	BaseURL string `yaml:"base_url"` // https://api.opsgenie.com by default; https://api.eu.opsgenie.com for EU accounts
+	User    string `yaml:"user"`     // Opsgenie user that acknowledges alerts, the integration when empty
+}
+
+// KubernetesConfig represents the kube-apiserver connection and the namespaces tools may access
+type KubernetesConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Kubeconfig  string   `yaml:"kubeconfig"`    // Path; empty uses $KUBECONFIG, ~/.kube/config, then the in-cluster service account
+	Context     string   `yaml:"context"`       // Kubeconfig context, defaults to current-context
+	InCluster   bool     `yaml:"in_cluster"`    // Always use the pod's service account
+	Namespaces  []string `yaml:"namespaces"`    // Namespaces tools may access; empty allows only the context's namespace
+	MaxLogBytes int      `yaml:"max_log_bytes"` // Cap on k8s_get_pod_logs output, 50000 by default
+}
+
+// GitConfig represents the repositories the git tools may read
+type GitConfig struct {
+	Repositories   []string `yaml:"repositories"`     // Whitelisted repository directories, or directories containing repositories
+	MaxOutputBytes int      `yaml:"max_output_bytes"` // Cap on patches and file contents, 100000 by default
+}
+
+// ProfilingConfig represents where pprof profiles may be read from; S3 objects use the s3 credentials
+type ProfilingConfig struct {
+	Directories     []string `yaml:"directories"`       // Local directories holding profiles, the working directory by default
+	MaxProfileBytes int64    `yaml:"max_profile_bytes"` // Largest profile accepted, 64MB by default
+}
+
+// ArtifactsConfig represents where build artifacts (binaries, bundle stats files) may be read from
+type ArtifactsConfig struct {
+	Directories      []string `yaml:"directories"`        // Local directories holding artifacts, the working directory by default
+	MaxArtifactBytes int64    `yaml:"max_artifact_bytes"` // Largest artifact accepted, 512MB by default
+}
+
+// ExecConfig represents the allowlisted commands exec_run may start; nothing runs unless enabled
+type ExecConfig struct {
+	Enabled        bool                `yaml:"enabled"`
+	Commands       []ExecCommandConfig `yaml:"commands"`
+	Directories    []string            `yaml:"directories"`      // Working directories commands may run in, the working directory by default
+	Env            []string            `yaml:"env"`              // Server environment variables passed to commands besides PATH and HOME
+	TimeoutSeconds int                 `yaml:"timeout_seconds"`  // Default and maximum run time, 120 by default
+	MaxOutputBytes int                 `yaml:"max_output_bytes"` // Cap on stdout and stderr each, 65536 by default
+	MaxConcurrent  int                 `yaml:"max_concurrent"`   // Commands running at once, 2 by default
+	AuditLog       string              `yaml:"audit_log"`        // JSON lines file recording every execution
+}
+
+// ExecCommandConfig is one allowed command: a fixed argv prefix plus the extra arguments a caller may append
+type ExecCommandConfig struct {
+	Name           string `yaml:"name"`         // Name callers use, e.g. go-test
+	Command        string `yaml:"command"`      // Executable and fixed arguments, e.g. "go test"
+	ArgsPattern    string `yaml:"args_pattern"` // Regexp every extra argument must match in full; empty allows none
+	Description    string `yaml:"description"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // Overrides exec.timeout_seconds
+}
+
+// CodeQualityConfig represents the formatters and linters run through the exec sandbox
+type CodeQualityConfig struct {
+	Enabled        bool                      `yaml:"enabled"`
+	Linters        []CodeQualityLinterConfig `yaml:"linters"`         // gofmt, golangci-lint, eslint and ruff; all installed ones by default
+	Directories    []string                  `yaml:"directories"`     // Project roots linters may run in, the working directory by default
+	Env            []string                  `yaml:"env"`             // Server environment variables passed to linters besides PATH and HOME
+	AllowFix       bool                      `yaml:"allow_fix"`       // Write gate for code_fix
+	TimeoutSeconds int                       `yaml:"timeout_seconds"` // Per linter run, 300 by default
+	AuditLog       string                    `yaml:"audit_log"`       // JSON lines file recording every linter run
+}
+
+// CodeQualityLinterConfig enables one linter, optionally with a different executable
+type CodeQualityLinterConfig struct {
+	Name    string `yaml:"name"`    // gofmt, golangci-lint, eslint or ruff
+	Command string `yaml:"command"` // Executable and leading arguments replacing the default, e.g. "npx eslint"
+}
+
+// SBOMConfig represents where sbom may scan projects and the license policy it applies
+type SBOMConfig struct {
+	Directories []string `yaml:"directories"`  // Project roots that may be scanned, the working directory by default
+	ModuleCache string   `yaml:"module_cache"` // Go module cache for dependency licenses, $GOMODCACHE or ~/go/pkg/mod by default
+	Allow       []string `yaml:"allow"`        // SPDX license IDs that are allowed; when set, all others are flagged
+	Deny        []string `yaml:"deny"`         // SPDX license IDs that are never allowed, e.g. AGPL-3.0
+}
+
+// ProtoConfig represents where .proto files may be read from
+type ProtoConfig struct {
+	Directories []string `yaml:"directories"`  // Directories holding .proto files, the working directory by default
+	ImportPaths []string `yaml:"import_paths"` // Roots imports are resolved against (like protoc -I), the directories by default
+}
+
+// KafkaConfig represents the Kafka cluster the read-only kafka tools inspect
+type KafkaConfig struct {
+	Brokers         []string `yaml:"brokers"`         // Bootstrap brokers (host:port); the provider is disabled when empty
+	ClientID        string   `yaml:"client_id"`       // dev-mcp by default
+	TLS             bool     `yaml:"tls"`             // Connect with TLS (implied by ca_file)
+	TLSSkipVerify   bool     `yaml:"tls_skip_verify"` // Do not verify broker certificates
+	CAFile          string   `yaml:"ca_file"`         // PEM file of CAs to trust instead of the system roots
+	SASLMechanism   string   `yaml:"sasl_mechanism"`  // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512; empty disables SASL
+	Username        string   `yaml:"username"`
+	Password        string   `yaml:"password"`
+	TimeoutSeconds  int      `yaml:"timeout_seconds"`   // Per broker request, 10 by default
+	MaxPeekMessages int      `yaml:"max_peek_messages"` // Cap on kafka_peek count, 100 by default
+	MaxValueBytes   int      `yaml:"max_value_bytes"`   // Key and value bytes shown per message, 4096 by default
+}
+
+// MongoDBConfig represents the MongoDB deployment the read-oriented mongo tools query
+type MongoDBConfig struct {
+	URI            string `yaml:"uri"`      // mongodb:// or mongodb+srv:// connection string; the provider is disabled when empty
+	Database       string `yaml:"database"` // Default database of the tools, the database of the uri by default
+	Username       string `yaml:"username"` // Overrides the credentials of the uri
+	Password       string `yaml:"password"`
+	AuthSource     string `yaml:"auth_source"`      // Database holding the user: the uri's authSource, then admin
+	AuthMechanism  string `yaml:"auth_mechanism"`   // SCRAM-SHA-256 or SCRAM-SHA-1; negotiated with the server when empty
+	TLS            bool   `yaml:"tls"`              // Connect with TLS (implied by ca_file and mongodb+srv://)
+	TLSSkipVerify  bool   `yaml:"tls_skip_verify"`  // Do not verify server certificates
+	CAFile         string `yaml:"ca_file"`          // PEM file of CAs to trust instead of the system roots
+	TimeoutSeconds int    `yaml:"timeout_seconds"`  // Per command, 30 by default
+	MaxDocuments   int    `yaml:"max_documents"`    // Cap on the documents a tool returns, 100 by default
+	MaxResultBytes int    `yaml:"max_result_bytes"` // Documents past this much JSON are left out, 262144 by default
+	UnsafeMode     bool   `yaml:"unsafe_mode"`      // Allow $out, $merge, any stage and server-side JavaScript
+}
+
+// SearchConfig represents the Elasticsearch or OpenSearch cluster the es tools query
+type SearchConfig struct {
+	URL            string   `yaml:"url"` // e.g. https://search.internal:9200; the provider is disabled when empty
+	Username       string   `yaml:"username"`
+	Password       string   `yaml:"password"`
+	APIKey         string   `yaml:"api_key"` // Encoded Elasticsearch API key, sent instead of basic auth
+	TLSSkipVerify  bool     `yaml:"tls_skip_verify"`
+	CAFile         string   `yaml:"ca_file"`          // PEM file of CAs to trust instead of the system roots
+	Indices        []string `yaml:"indices"`          // Index globs the tools may read, e.g. ["logs-*", "orders"]; all but hidden indices when empty
+	TimeoutSeconds int      `yaml:"timeout_seconds"`  // Per request, 30 by default
+	MaxHits        int      `yaml:"max_hits"`         // Cap on the size of es_search, 100 by default
+	MaxFieldChars  int      `yaml:"max_field_chars"`  // String values in hits are cut to this length, 2000 by default
+	MaxResultBytes int      `yaml:"max_result_bytes"` // Hits past this much JSON are left out, 262144 by default
+}
+
+// QueueConfig represents the message queue inspection configuration (RabbitMQ and AWS SQS)
+type QueueConfig struct {
+	RabbitMQ        RabbitMQConfig `yaml:"rabbitmq"`
+	SQS             SQSConfig      `yaml:"sqs"`
+	MaxPeekMessages int            `yaml:"max_peek_messages"` // Messages per queue_peek call, 20 by default
+	MaxBodyBytes    int            `yaml:"max_body_bytes"`    // Message bodies are cut to this size, 4096 by default
+}
+
+// RabbitMQConfig represents the RabbitMQ management API connection
+type RabbitMQConfig struct {
+	URL           string `yaml:"url"` // Management API, e.g. http://localhost:15672
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+	VHost         string `yaml:"vhost"` // Default virtual host, "/" by default
+	TLSSkipVerify bool   `yaml:"tls_skip_verify"`
+}
+
+// SQSConfig represents the AWS SQS connection; credentials come from the aws section
+type SQSConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Region      string `yaml:"region"`       // Defaults to aws.region, then s3.region
+	QueuePrefix string `yaml:"queue_prefix"` // Only queues whose names start with this prefix are listed
+}
+
+// CacheConfig represents the caches cache_invalidate can purge and the requests that warm them up again
+type CacheConfig struct {
+	CDN                  CDNConfig        `yaml:"cdn"`
+	Redis                RedisCacheConfig `yaml:"redis"`
+	WarmUp               []WarmUpRequest  `yaml:"warm_up"`
+	WarmUpTimeoutSeconds int              `yaml:"warm_up_timeout_seconds"` // Per warm-up request, 10 by default
+}
+
+// CDNConfig represents a CDN purge API
+type CDNConfig struct {
+	Type      string `yaml:"type"`       // cloudflare, fastly or http; CDN purges are disabled when empty
+	ZoneID    string `yaml:"zone_id"`    // Cloudflare zone
+	ServiceID string `yaml:"service_id"` // Fastly service
+	Token     string `yaml:"token"`      // API token (Cloudflare, Fastly) or bearer token (http)
+	URL       string `yaml:"url"`        // For http: endpoint receiving a JSON POST of {urls, tags, everything}
+}
+
+// RedisCacheConfig represents the Redis instance whose keys cache_invalidate may delete
+type RedisCacheConfig struct {
+	URL             string   `yaml:"url"`              // redis://[user:password@]host:6379/0, rediss:// for TLS
+	AllowDelete     bool     `yaml:"allow_delete"`     // Write gate; without it matching keys are only counted
+	AllowedPatterns []string `yaml:"allowed_patterns"` // Exact patterns or prefixes ending in *, e.g. "cache:*"; any pattern when empty
+	MaxKeys         int      `yaml:"max_keys"`         // Patterns matching more keys are not deleted, 10000 by default
+}
+
+// WarmUpRequest is an HTTP request sent after invalidation to fill caches again
+type WarmUpRequest struct {
+	Name         string            `yaml:"name"`
+	URL          string            `yaml:"url"`
+	Method       string            `yaml:"method"` // GET by default
+	Headers      map[string]string `yaml:"headers"`
+	Body         string            `yaml:"body"`
+	ExpectStatus int               `yaml:"expect_status"` // Any 2xx status by default
+}
+
+// DeployConfig represents the thresholds verify_deploy judges a release by
+type DeployConfig struct {
+	MaxNewIssues         int     `yaml:"max_new_issues"`          // New Sentry issues tolerated in a release, 0 by default
+	MaxErrorRateIncrease float64 `yaml:"max_error_rate_increase"` // Relative error rate increase tolerated, 0.5 (50%) by default
+	ErrorRateFloor       float64 `yaml:"error_rate_floor"`        // Error rates below this many lines per second pass, 0.05 by default
+	WindowMinutes        int     `yaml:"window_minutes"`          // Length of the windows before and after the deploy, 30 by default
+	HealthTimeoutSeconds int     `yaml:"health_timeout_seconds"`  // Per health check request, 10 by default
+}
+
+// SLOConfig represents the service level objectives slo_status reports on and the Prometheus server
+// their queries run against
+type SLOConfig struct {
+	Prometheus PrometheusConfig `yaml:"prometheus"`
+	Objectives []SLODefinition  `yaml:"objectives"`
+}
+
+// PrometheusConfig represents a Prometheus-compatible query API (Prometheus, Thanos, Mimir, VictoriaMetrics)
+type PrometheusConfig struct {
+	URL            string `yaml:"url"`   // e.g. http://prometheus:9090
+	Token          string `yaml:"token"` // Bearer token, optional
+	Username       string `yaml:"username"`
+	Password       string `yaml:"password"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // Per query, 30 by default
+}
+
+// SLODefinition is an availability or latency objective measured as the ratio of bad events to all
+// events. Queries contain ${window}, replaced by the range of each window (e.g. 1h, 6h, 3d, 30d)
+type SLODefinition struct {
+	Name        string  `yaml:"name"`
+	Service     string  `yaml:"service"` // Catalog service name, informational
+	Type        string  `yaml:"type"`    // availability or latency, informational
+	Description string  `yaml:"description"`
+	Target      float64 `yaml:"target"`      // Objective in percent, e.g. 99.9
+	PeriodDays  int     `yaml:"period_days"` // Error budget period, 30 by default
+	Source      string  `yaml:"source"`      // prometheus (default) or loki
+	BadQuery    string  `yaml:"bad_query"`   // Rate of bad events; give this or good_query
+	GoodQuery   string  `yaml:"good_query"`  // Rate of good events, e.g. requests under the latency threshold
+	TotalQuery  string  `yaml:"total_query"` // Rate of all events
+}
+
+// HealthConfig represents the dependency checks behind the health://dependencies resource
+type HealthConfig struct {
+	IntervalSeconds int              `yaml:"interval_seconds"` // Time between refreshes, 60 by default
+	TimeoutSeconds  int              `yaml:"timeout_seconds"`  // Per HTTP check, 5 by default
+	SlowMs          int              `yaml:"slow_ms"`          // Dependencies answering slower are degraded, 1000 by default
+	SkipLLM         bool             `yaml:"skip_llm"`         // Do not check the enabled llm.providers APIs
+	Endpoints       []HealthEndpoint `yaml:"endpoints"`        // Further HTTP dependencies
+}
+
+// HealthEndpoint is an HTTP dependency checked with a GET
+type HealthEndpoint struct {
+	Name         string `yaml:"name"`
+	URL          string `yaml:"url"`
+	ExpectStatus int    `yaml:"expect_status"` // Any 2xx status by default
+}
+
+// UtilityConfig represents optional data files used by the utility tools
+type UtilityConfig struct {
+	GeoIPDatabases []string `yaml:"geoip_databases"` // MaxMind .mmdb files (e.g. GeoLite2-City and GeoLite2-ASN) used by ip_info
+}
+
 // LLMConfig represents the configuration for large language models
 type LLMConfig struct {
 	Providers []ProviderConfig `yaml:"providers"`
@@ -115,6 +810,12 @@ func Load(filepath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	// Merge the secrets file and expand ${VAR} references
+	data, err = resolve(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config: %w", err)
+	}
+
 	var config Config
 	err = yaml.Unmarshal(data, &config)
 	if err != nil {
@@ -124,6 +825,29 @@ func Load(filepath string) (*Config, error) {
 	// Override with environment variables
 	config.overrideWithEnv()
 
+	// Resolve vault:, aws-sm: and other secret references with the backends this configuration sets up
+	resolved, found, err := resolveSecretReferences(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	if found {
+		secrets := config.Secrets
+		config = Config{}
+		if err := yaml.Unmarshal(resolved, &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+		}
+		config.overrideWithEnv()
+		if secrets.RefreshSeconds > 0 {
+			last := config
+			config.secretWatcher = &SecretWatcher{
+				data:     data,
+				last:     &last,
+				interval: time.Duration(secrets.RefreshSeconds) * time.Second,
+				logger:   logging.New("Secrets"),
+			}
+		}
+	}
+
 	return &config, nil
 }
 
@@ -138,8 +862,126 @@ func (c *Config) overrideWithEnv() {
 	if host := os.Getenv("MCP_SERVER_HOST"); host != "" {
 		c.Server.Host = host
 	}
+	if transport := os.Getenv("MCP_SERVER_TRANSPORT"); transport != "" {
+		c.Server.Transport = transport
+	}
+	if pprofEnabled := os.Getenv("MCP_SERVER_PPROF"); pprofEnabled != "" {
+		if enabled, err := strconv.ParseBool(pprofEnabled); err == nil {
+			c.Server.Pprof = enabled
+		}
+	}
+
+	// Provider selection
+	if enabled := os.Getenv("MCP_PROVIDERS_ENABLED"); enabled != "" {
+		c.Providers.Enabled = splitAndTrim(enabled)
+	}
+	if disabled := os.Getenv("MCP_PROVIDERS_DISABLED"); disabled != "" {
+		c.Providers.Disabled = splitAndTrim(disabled)
+	}
+
+	// Rate limits
+	if enabled := os.Getenv("MCP_RATE_LIMIT_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Auth.RateLimit.Enabled = b
+		}
+	}
+	if rpm := os.Getenv("MCP_RATE_LIMIT_REQUESTS_PER_MINUTE"); rpm != "" {
+		if n, err := strconv.Atoi(rpm); err == nil {
+			c.Auth.RateLimit.RequestsPerMinute = n
+		}
+	}
+
+	// Policy
+	if file := os.Getenv("MCP_POLICY_FILE"); file != "" {
+		c.Auth.Policy.File = file
+	}
+	if env := os.Getenv("MCP_ENVIRONMENT"); env != "" {
+		if c.Auth.Policy.Environment == nil {
+			c.Auth.Policy.Environment = map[string]string{}
+		}
+		c.Auth.Policy.Environment["env"] = env
+	}
+
+	// Impersonation
+	if enabled := os.Getenv("MCP_IMPERSONATION_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Auth.Impersonation.Enabled = b
+		}
+	}
+
+	// Audit configuration
+	if enabled := os.Getenv("MCP_AUDIT_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Audit.Enabled = b
+		}
+	}
+	if sink := os.Getenv("MCP_AUDIT_SINK"); sink != "" {
+		c.Audit.Sink = sink
+	}
+	if path := os.Getenv("MCP_AUDIT_PATH"); path != "" {
+		c.Audit.Path = path
+	}
+
+	// Prompts
+	if dir := os.Getenv("MCP_PROMPTS_DIR"); dir != "" {
+		c.Prompts.Directory = dir
+	}
+
+	// Translations
+	if dir := os.Getenv("MCP_I18N_DIR"); dir != "" {
+		c.I18n.Directory = dir
+	}
+	if locale := os.Getenv("MCP_LOCALE"); locale != "" {
+		c.I18n.DefaultLocale = locale
+	}
+
+	// Hints
+	if enabled := os.Getenv("MCP_HINTS_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Hints.Enabled = b
+		}
+	}
+
+	// Store
+	if path := os.Getenv("MCP_STORE_PATH"); path != "" {
+		c.Store.Path = path
+	}
+
+	// Mock mode
+	if enabled := os.Getenv("MCP_MOCK"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Mock.Enabled = b
+		}
+	}
+	if dir := os.Getenv("MCP_MOCK_DIR"); dir != "" {
+		c.Mock.Directory = dir
+	}
+
+	// Output
+	if format := os.Getenv("MCP_OUTPUT_FORMAT"); format != "" {
+		c.Output.Format = format
+	}
+	if emoji := os.Getenv("MCP_OUTPUT_EMOJI"); emoji != "" {
+		if b, err := strconv.ParseBool(emoji); err == nil {
+			c.Output.Emoji = &b
+		}
+	}
+	if timezone := os.Getenv("MCP_OUTPUT_TIMEZONE"); timezone != "" {
+		c.Output.Timezone = timezone
+	}
+	if maxBytes := os.Getenv("MCP_OUTPUT_MAX_RESULT_BYTES"); maxBytes != "" {
+		if n, err := strconv.Atoi(maxBytes); err == nil {
+			c.Output.MaxResultBytes = n
+		}
+	}
+	if dir := os.Getenv("MCP_OUTPUT_SPILL_DIR"); dir != "" {
+		c.Output.SpillDirectory = dir
+	}
 
 	// Database configuration
+	if driver := os.Getenv("MCP_DATABASE_DRIVER"); driver != "" {
+		c.Database.Driver = driver
+	}
 	if host := os.Getenv("MCP_DATABASE_HOST"); host != "" {
 		c.Database.Host = host
 	}
@@ -157,6 +999,14 @@ func (c *Config) overrideWithEnv() {
 	if dbname := os.Getenv("MCP_DATABASE_DBNAME"); dbname != "" {
 		c.Database.DBName = dbname
 	}
+	if sslMode := os.Getenv("MCP_DATABASE_SSLMODE"); sslMode != "" {
+		c.Database.SSLMode = sslMode
+	}
+	if timeout := os.Getenv("MCP_DATABASE_QUERY_TIMEOUT_SECONDS"); timeout != "" {
+		if n, err := strconv.Atoi(timeout); err == nil {
+			c.Database.QueryTimeoutSeconds = n
+		}
+	}
 
 	// Loki configuration
 	if host := os.Getenv("MCP_LOKI_HOST"); host != "" {
@@ -168,6 +1018,20 @@ func (c *Config) overrideWithEnv() {
 	if password := os.Getenv("MCP_LOKI_PASSWORD"); password != "" {
 		c.Loki.Password = password
 	}
+	if authToken := os.Getenv("MCP_LOKI_AUTH_TOKEN"); authToken != "" {
+		c.Loki.AuthToken = authToken
+	}
+	if tenant := os.Getenv("MCP_LOKI_TENANT"); tenant != "" {
+		c.Loki.Tenant = tenant
+	}
+
+	// Grafana configuration
+	if grafanaURL := os.Getenv("MCP_GRAFANA_URL"); grafanaURL != "" {
+		c.Grafana.URL = grafanaURL
+	}
+	if apiToken := os.Getenv("MCP_GRAFANA_API_TOKEN"); apiToken != "" {
+		c.Grafana.APIToken = apiToken
+	}
 
 	// S3 configuration
 	if endpoint := os.Getenv("MCP_S3_ENDPOINT"); endpoint != "" {
@@ -185,6 +1049,26 @@ func (c *Config) overrideWithEnv() {
 	if bucket := os.Getenv("MCP_S3_BUCKET"); bucket != "" {
 		c.S3.Bucket = bucket
 	}
+	if prefixes := os.Getenv("MCP_S3_RESOURCE_PREFIXES"); prefixes != "" {
+		c.S3.Resources.Prefixes = splitAndTrim(prefixes)
+	}
+	if local := os.Getenv("MCP_S3_SELECT_LOCAL"); local != "" {
+		if b, err := strconv.ParseBool(local); err == nil {
+			c.S3.Select.Local = b
+		}
+	}
+	if readOnly := os.Getenv("MCP_S3_READ_ONLY"); readOnly != "" {
+		if b, err := strconv.ParseBool(readOnly); err == nil {
+			c.S3.ReadOnly = &b
+		}
+	}
+
+	// File configuration
+	if readOnly := os.Getenv("MCP_FILE_READ_ONLY"); readOnly != "" {
+		if b, err := strconv.ParseBool(readOnly); err == nil {
+			c.File.ReadOnly = &b
+		}
+	}
 
 	// Sentry configuration
 	if dsn := os.Getenv("MCP_SENTRY_DSN"); dsn != "" {
@@ -208,6 +1092,11 @@ func (c *Config) overrideWithEnv() {
 	if authToken := os.Getenv("MCP_SENTRY_AUTH_TOKEN"); authToken != "" {
 		c.Sentry.AuthToken = authToken
 	}
+	if writeEnabled := os.Getenv("MCP_SENTRY_WRITE_ENABLED"); writeEnabled != "" {
+		if b, err := strconv.ParseBool(writeEnabled); err == nil {
+			c.Sentry.WriteEnabled = b
+		}
+	}
 	if projectIDs := os.Getenv("MCP_SENTRY_PROJECT_IDS"); projectIDs != "" {
 		c.Sentry.ProjectIDs = splitAndTrim(projectIDs)
 	}
@@ -246,6 +1135,331 @@ func (c *Config) overrideWithEnv() {
 		c.Swagger.Filepath = filepath
 	}
 
+	// Knowledge configuration
+	if dir := os.Getenv("MCP_KNOWLEDGE_DIR"); dir != "" {
+		c.Knowledge.Dir = dir
+	}
+	if bucket := os.Getenv("MCP_KNOWLEDGE_S3_BUCKET"); bucket != "" {
+		c.Knowledge.S3Bucket = bucket
+	}
+	if prefix := os.Getenv("MCP_KNOWLEDGE_S3_PREFIX"); prefix != "" {
+		c.Knowledge.S3Prefix = prefix
+	}
+
+	// Catalog configuration
+	if file := os.Getenv("MCP_CATALOG_FILE"); file != "" {
+		c.Catalog.File = file
+	}
+
+	// Artifact registry configuration (a single registry named "default")
+	if url := os.Getenv("MCP_REGISTRY_URL"); url != "" {
+		registryType := os.Getenv("MCP_REGISTRY_TYPE")
+		if registryType == "" {
+			registryType = "docker"
+		}
+		c.Registries = append(c.Registries, RegistryConfig{
+			Name:     "default",
+			Type:     registryType,
+			URL:      url,
+			Username: os.Getenv("MCP_REGISTRY_USERNAME"),
+			Password: os.Getenv("MCP_REGISTRY_PASSWORD"),
+			Token:    os.Getenv("MCP_REGISTRY_TOKEN"),
+		})
+	}
+
+	// Terraform configuration (a single state named "default")
+	if statePath := os.Getenv("MCP_TERRAFORM_STATE_PATH"); statePath != "" {
+		c.Terraform.States = append(c.Terraform.States, TerraformStateConfig{
+			Name: "default",
+			Path: statePath,
+		})
+	}
+
+	// AWS configuration
+	if enabled := os.Getenv("MCP_AWS_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.AWS.Enabled = b
+		}
+	}
+	if region := os.Getenv("MCP_AWS_REGION"); region != "" {
+		c.AWS.Region = region
+	}
+	if accessKey := os.Getenv("MCP_AWS_ACCESS_KEY"); accessKey != "" {
+		c.AWS.AccessKey = accessKey
+	}
+	if secretKey := os.Getenv("MCP_AWS_SECRET_KEY"); secretKey != "" {
+		c.AWS.SecretKey = secretKey
+	}
+	if sessionToken := os.Getenv("MCP_AWS_SESSION_TOKEN"); sessionToken != "" {
+		c.AWS.SessionToken = sessionToken
+	}
+	if profile := os.Getenv("MCP_AWS_PROFILE"); profile != "" {
+		c.AWS.Profile = profile
+	}
+
+	// Email configuration
+	if imapHost := os.Getenv("MCP_EMAIL_IMAP_HOST"); imapHost != "" {
+		c.Email.IMAPHost = imapHost
+	}
+	if imapPort := os.Getenv("MCP_EMAIL_IMAP_PORT"); imapPort != "" {
+		if port, err := strconv.Atoi(imapPort); err == nil {
+			c.Email.IMAPPort = port
+		}
+	}
+	if smtpHost := os.Getenv("MCP_EMAIL_SMTP_HOST"); smtpHost != "" {
+		c.Email.SMTPHost = smtpHost
+	}
+	if smtpPort := os.Getenv("MCP_EMAIL_SMTP_PORT"); smtpPort != "" {
+		if port, err := strconv.Atoi(smtpPort); err == nil {
+			c.Email.SMTPPort = port
+		}
+	}
+	if username := os.Getenv("MCP_EMAIL_USERNAME"); username != "" {
+		c.Email.Username = username
+	}
+	if password := os.Getenv("MCP_EMAIL_PASSWORD"); password != "" {
+		c.Email.Password = password
+	}
+	if mailbox := os.Getenv("MCP_EMAIL_MAILBOX"); mailbox != "" {
+		c.Email.Mailbox = mailbox
+	}
+	if from := os.Getenv("MCP_EMAIL_FROM"); from != "" {
+		c.Email.From = from
+	}
+	if allow := os.Getenv("MCP_EMAIL_ALLOW_SEND"); allow != "" {
+		if b, err := strconv.ParseBool(allow); err == nil {
+			c.Email.AllowSend = b
+		}
+	}
+
+	// Calendar configuration (feeds named "freeze" and "oncall")
+	if url := os.Getenv("MCP_CALENDAR_FREEZE_URL"); url != "" {
+		c.Calendar.Feeds = append(c.Calendar.Feeds, CalendarFeedConfig{Name: "freeze", Type: "freeze", URL: url})
+	}
+	if url := os.Getenv("MCP_CALENDAR_ONCALL_URL"); url != "" {
+		c.Calendar.Feeds = append(c.Calendar.Feeds, CalendarFeedConfig{Name: "oncall", Type: "oncall", URL: url})
+	}
+	if timezone := os.Getenv("MCP_CALENDAR_TIMEZONE"); timezone != "" {
+		c.Calendar.Timezone = timezone
+	}
+
+	// On-call configuration
+	if token := os.Getenv("MCP_PAGERDUTY_API_TOKEN"); token != "" {
+		c.OnCall.PagerDuty.APIToken = token
+	}
+	if from := os.Getenv("MCP_PAGERDUTY_FROM"); from != "" {
+		c.OnCall.PagerDuty.From = from
+	}
+	if apiKey := os.Getenv("MCP_OPSGENIE_API_KEY"); apiKey != "" {
+		c.OnCall.Opsgenie.APIKey = apiKey
+	}
+
+	// CI/CD configuration
+	if token := os.Getenv("MCP_CICD_GITHUB_TOKEN"); token != "" {
+		c.CICD.GitHub.Token = token
+	}
+	if baseURL := os.Getenv("MCP_CICD_GITHUB_BASE_URL"); baseURL != "" {
+		c.CICD.GitHub.BaseURL = baseURL
+	}
+	if token := os.Getenv("MCP_CICD_GITLAB_TOKEN"); token != "" {
+		c.CICD.GitLab.Token = token
+	}
+	if baseURL := os.Getenv("MCP_CICD_GITLAB_BASE_URL"); baseURL != "" {
+		c.CICD.GitLab.BaseURL = baseURL
+	}
+	if url := os.Getenv("MCP_CICD_JENKINS_URL"); url != "" {
+		c.CICD.Jenkins.URL = url
+	}
+	if username := os.Getenv("MCP_CICD_JENKINS_USERNAME"); username != "" {
+		c.CICD.Jenkins.Username = username
+	}
+	if apiToken := os.Getenv("MCP_CICD_JENKINS_API_TOKEN"); apiToken != "" {
+		c.CICD.Jenkins.APIToken = apiToken
+	}
+	if allow := os.Getenv("MCP_CICD_ALLOW_TRIGGER"); allow != "" {
+		if b, err := strconv.ParseBool(allow); err == nil {
+			c.CICD.AllowTrigger = b
+		}
+	}
+
+	// Code hosting configuration
+	if token := os.Getenv("MCP_CODE_HOST_GITHUB_TOKEN"); token != "" {
+		c.CodeHost.GitHub.Token = token
+	}
+	if token := os.Getenv("MCP_CODE_HOST_GITLAB_TOKEN"); token != "" {
+		c.CodeHost.GitLab.Token = token
+	}
+	if repo := os.Getenv("MCP_CODE_HOST_DEFAULT_REPO"); repo != "" {
+		c.CodeHost.DefaultRepo = repo
+	}
+
+	// Kubernetes configuration
+	if enabled := os.Getenv("MCP_KUBERNETES_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Kubernetes.Enabled = b
+		}
+	}
+	if kubeconfig := os.Getenv("MCP_KUBERNETES_KUBECONFIG"); kubeconfig != "" {
+		c.Kubernetes.Kubeconfig = kubeconfig
+	}
+	if kubeContext := os.Getenv("MCP_KUBERNETES_CONTEXT"); kubeContext != "" {
+		c.Kubernetes.Context = kubeContext
+	}
+	if namespaces := os.Getenv("MCP_KUBERNETES_NAMESPACES"); namespaces != "" {
+		c.Kubernetes.Namespaces = splitAndTrim(namespaces)
+	}
+
+	// Git configuration
+	if repositories := os.Getenv("MCP_GIT_REPOSITORIES"); repositories != "" {
+		c.Git.Repositories = splitAndTrim(repositories)
+	}
+
+	// Profiling configuration
+	if directories := os.Getenv("MCP_PROFILING_DIRECTORIES"); directories != "" {
+		c.Profiling.Directories = splitAndTrim(directories)
+	}
+
+	// Artifacts configuration
+	if directories := os.Getenv("MCP_ARTIFACTS_DIRECTORIES"); directories != "" {
+		c.Artifacts.Directories = splitAndTrim(directories)
+	}
+
+	// Exec configuration (commands are configured in YAML only)
+	if enabled := os.Getenv("MCP_EXEC_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Exec.Enabled = b
+		}
+	}
+	if directories := os.Getenv("MCP_EXEC_DIRECTORIES"); directories != "" {
+		c.Exec.Directories = splitAndTrim(directories)
+	}
+	if auditLog := os.Getenv("MCP_EXEC_AUDIT_LOG"); auditLog != "" {
+		c.Exec.AuditLog = auditLog
+	}
+
+	// Code quality configuration (linters are configured in YAML only)
+	if enabled := os.Getenv("MCP_CODE_QUALITY_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.CodeQuality.Enabled = b
+		}
+	}
+	if directories := os.Getenv("MCP_CODE_QUALITY_DIRECTORIES"); directories != "" {
+		c.CodeQuality.Directories = splitAndTrim(directories)
+	}
+	if allow := os.Getenv("MCP_CODE_QUALITY_ALLOW_FIX"); allow != "" {
+		if b, err := strconv.ParseBool(allow); err == nil {
+			c.CodeQuality.AllowFix = b
+		}
+	}
+
+	// SBOM configuration (the license policy is configured in YAML only)
+	if directories := os.Getenv("MCP_SBOM_DIRECTORIES"); directories != "" {
+		c.SBOM.Directories = splitAndTrim(directories)
+	}
+	if moduleCache := os.Getenv("MCP_SBOM_MODULE_CACHE"); moduleCache != "" {
+		c.SBOM.ModuleCache = moduleCache
+	}
+
+	// Proto configuration
+	if directories := os.Getenv("MCP_PROTO_DIRECTORIES"); directories != "" {
+		c.Proto.Directories = splitAndTrim(directories)
+	}
+	if importPaths := os.Getenv("MCP_PROTO_IMPORT_PATHS"); importPaths != "" {
+		c.Proto.ImportPaths = splitAndTrim(importPaths)
+	}
+
+	// Kafka configuration
+	if brokers := os.Getenv("MCP_KAFKA_BROKERS"); brokers != "" {
+		c.Kafka.Brokers = splitAndTrim(brokers)
+	}
+	if mechanism := os.Getenv("MCP_KAFKA_SASL_MECHANISM"); mechanism != "" {
+		c.Kafka.SASLMechanism = mechanism
+	}
+	if username := os.Getenv("MCP_KAFKA_USERNAME"); username != "" {
+		c.Kafka.Username = username
+	}
+	if password := os.Getenv("MCP_KAFKA_PASSWORD"); password != "" {
+		c.Kafka.Password = password
+	}
+	if tlsEnabled := os.Getenv("MCP_KAFKA_TLS"); tlsEnabled != "" {
+		if b, err := strconv.ParseBool(tlsEnabled); err == nil {
+			c.Kafka.TLS = b
+		}
+	}
+
+	// MongoDB configuration
+	if uri := os.Getenv("MCP_MONGODB_URI"); uri != "" {
+		c.MongoDB.URI = uri
+	}
+	if database := os.Getenv("MCP_MONGODB_DATABASE"); database != "" {
+		c.MongoDB.Database = database
+	}
+	if username := os.Getenv("MCP_MONGODB_USERNAME"); username != "" {
+		c.MongoDB.Username = username
+	}
+	if password := os.Getenv("MCP_MONGODB_PASSWORD"); password != "" {
+		c.MongoDB.Password = password
+	}
+
+	// Elasticsearch / OpenSearch configuration
+	if searchURL := os.Getenv("MCP_SEARCH_URL"); searchURL != "" {
+		c.Search.URL = searchURL
+	}
+	if username := os.Getenv("MCP_SEARCH_USERNAME"); username != "" {
+		c.Search.Username = username
+	}
+	if password := os.Getenv("MCP_SEARCH_PASSWORD"); password != "" {
+		c.Search.Password = password
+	}
+	if apiKey := os.Getenv("MCP_SEARCH_API_KEY"); apiKey != "" {
+		c.Search.APIKey = apiKey
+	}
+
+	// Message queues
+	if rabbitURL := os.Getenv("MCP_RABBITMQ_URL"); rabbitURL != "" {
+		c.Queue.RabbitMQ.URL = rabbitURL
+	}
+	if username := os.Getenv("MCP_RABBITMQ_USERNAME"); username != "" {
+		c.Queue.RabbitMQ.Username = username
+	}
+	if password := os.Getenv("MCP_RABBITMQ_PASSWORD"); password != "" {
+		c.Queue.RabbitMQ.Password = password
+	}
+	if enabled := os.Getenv("MCP_SQS_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.Queue.SQS.Enabled = b
+		}
+	}
+	if region := os.Getenv("MCP_SQS_REGION"); region != "" {
+		c.Queue.SQS.Region = region
+	}
+
+	// Cache invalidation
+	if token := os.Getenv("MCP_CDN_TOKEN"); token != "" {
+		c.Cache.CDN.Token = token
+	}
+	if redisURL := os.Getenv("MCP_REDIS_URL"); redisURL != "" {
+		c.Cache.Redis.URL = redisURL
+	}
+	if allowDelete := os.Getenv("MCP_REDIS_ALLOW_DELETE"); allowDelete != "" {
+		if b, err := strconv.ParseBool(allowDelete); err == nil {
+			c.Cache.Redis.AllowDelete = b
+		}
+	}
+
+	// SLO configuration
+	if prometheusURL := os.Getenv("MCP_PROMETHEUS_URL"); prometheusURL != "" {
+		c.SLO.Prometheus.URL = prometheusURL
+	}
+	if token := os.Getenv("MCP_PROMETHEUS_TOKEN"); token != "" {
+		c.SLO.Prometheus.Token = token
+	}
+
+	// Utility configuration
+	if databases := os.Getenv("MCP_GEOIP_DATABASES"); databases != "" {
+		c.Utility.GeoIPDatabases = splitAndTrim(databases)
+	}
+
 	// LLM configuration
 	c.overrideLLMConfigWithEnv()
 }
@@ -0,0 +1,447 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/go-resty/resty/v2"
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/logging"
+)
+
+const defaultSecretTimeout = 10 * time.Second
+
+// SecretBackend reads the secrets of one reference scheme
+type SecretBackend interface {
+	// Read returns the fields of the secret at path; a secret that is not a set of fields is returned
+	// under the empty key
+	Read(ctx context.Context, path string) (map[string]string, error)
+}
+
+// SecretBackendFactory creates a backend from the configuration being loaded, before its references are resolved
+type SecretBackendFactory func(c *Config) (SecretBackend, error)
+
+var (
+	secretBackendsMu sync.RWMutex
+	secretBackends   = map[string]SecretBackendFactory{
+		"vault":  newVaultBackend,
+		"aws-sm": newAWSSecretsBackend,
+	}
+)
+
+// secretReference matches scheme:path and scheme:path#key values
+var secretReference = regexp.MustCompile(`^([a-z][a-z0-9-]*):([^#\s]+)(?:#(\S+))?$`)
+
+// RegisterSecretBackend adds a backend resolving the references of a scheme, such as "vault" for vault:path#key.
+// It must be called before the configuration is loaded.
+func RegisterSecretBackend(scheme string, factory SecretBackendFactory) {
+	secretBackendsMu.Lock()
+	defer secretBackendsMu.Unlock()
+	secretBackends[scheme] = factory
+}
+
+// parseSecretReference splits a value into scheme, path and key when it references a registered backend
+func parseSecretReference(value string) (scheme, path, key string, ok bool) {
+	match := secretReference.FindStringSubmatch(value)
+	if match == nil {
+		return "", "", "", false
+	}
+	secretBackendsMu.RLock()
+	_, registered := secretBackends[match[1]]
+	secretBackendsMu.RUnlock()
+	return match[1], match[2], match[3], registered
+}
+
+// resolveSecretReferences replaces every secret reference among the string values of a YAML document. The
+// backends are configured from c, the configuration parsed from the same document. found reports whether
+// the document had references.
+func resolveSecretReferences(data []byte, c *Config) (resolved []byte, found bool, err error) {
+	var tree interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, false, err
+	}
+
+	timeout := defaultSecretTimeout
+	if c.Secrets.TimeoutSeconds > 0 {
+		timeout = time.Duration(c.Secrets.TimeoutSeconds) * time.Second
+	}
+	backends := map[string]SecretBackend{}
+	secrets := map[string]map[string]string{} // scheme:path -> fields, so a secret is read once per pass
+	var problems []string
+
+	lookup := func(field, value string) string {
+		scheme, path, key, ok := parseSecretReference(value)
+		if !ok {
+			return value
+		}
+		found = true
+
+		fields, read := secrets[scheme+":"+path]
+		if !read {
+			backend, ok := backends[scheme]
+			if !ok {
+				secretBackendsMu.RLock()
+				factory := secretBackends[scheme]
+				secretBackendsMu.RUnlock()
+				var err error
+				if backend, err = factory(c); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %v", scheme, err))
+				}
+				backends[scheme] = backend
+			}
+			if backend == nil {
+				return ""
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			var err error
+			fields, err = backend.Read(ctx, path)
+			cancel()
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s: %v", field, value, err))
+				return ""
+			}
+			secrets[scheme+":"+path] = fields
+		}
+
+		secret, err := secretField(fields, key)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s: %v", field, value, err))
+		}
+		return secret
+	}
+	tree = walkStrings(tree, "", lookup)
+
+	if len(problems) > 0 {
+		return nil, found, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	if !found {
+		return data, false, nil
+	}
+	resolved, err = yaml.Marshal(tree)
+	return resolved, true, err
+}
+
+// walkStrings replaces every string value of a parsed YAML document with replace(field, value), where
+// field is its dotted path such as databases.0.password
+func walkStrings(node interface{}, field string, replace func(field, value string) string) interface{} {
+	switch v := node.(type) {
+	case string:
+		return replace(field, v)
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			v[key] = walkStrings(value, strings.TrimPrefix(fmt.Sprintf("%s.%v", field, key), "."), replace)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = walkStrings(value, fmt.Sprintf("%s.%d", field, i), replace)
+		}
+	}
+	return node
+}
+
+// secretField picks the value of a reference from the fields of its secret: the named key, or the only value
+func secretField(fields map[string]string, key string) (string, error) {
+	if key != "" {
+		value, ok := fields[key]
+		if !ok {
+			return "", fmt.Errorf("the secret has no key %q", key)
+		}
+		return value, nil
+	}
+	if value, ok := fields[""]; ok {
+		return value, nil
+	}
+	if len(fields) == 1 {
+		for _, value := range fields {
+			return value, nil
+		}
+	}
+	keys := make([]string, 0, len(fields))
+	for name := range fields {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return "", fmt.Errorf("the secret has keys %s; name one with #key", strings.Join(keys, ", "))
+}
+
+// stringFields turns the fields of a JSON secret into strings; values that are not strings stay JSON
+func stringFields(data map[string]interface{}) map[string]string {
+	fields := make(map[string]string, len(data))
+	for name, value := range data {
+		if s, ok := value.(string); ok {
+			fields[name] = s
+			continue
+		}
+		encoded, _ := json.Marshal(value)
+		fields[name] = string(encoded)
+	}
+	return fields
+}
+
+// vaultBackend reads secrets from the KV engines of a HashiCorp Vault server
+type vaultBackend struct {
+	client *resty.Client
+	cfg    VaultConfig
+}
+
+func newVaultBackend(c *Config) (SecretBackend, error) {
+	cfg := c.Secrets.Vault
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("no Vault address (secrets.vault.address or VAULT_ADDR)")
+	}
+	if cfg.Token == "" && cfg.TokenFile == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfg.Token == "" && cfg.TokenFile == "" {
+		return nil, fmt.Errorf("no Vault token (secrets.vault.token, token_file or VAULT_TOKEN)")
+	}
+	return &vaultBackend{
+		client: resty.New().SetBaseURL(strings.TrimSuffix(cfg.Address, "/")).SetTimeout(defaultSecretTimeout),
+		cfg:    cfg,
+	}, nil
+}
+
+// Read reads a secret such as secret/data/db (KV v2) or secret/db (KV v1)
+func (b *vaultBackend) Read(ctx context.Context, path string) (map[string]string, error) {
+	token := b.cfg.Token
+	if token == "" {
+		// Re-read on every pass, so a token renewed by a Vault agent is picked up
+		data, err := os.ReadFile(b.cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Vault token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	var body struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []string               `json:"errors"`
+	}
+	req := b.client.R().SetContext(ctx).SetHeader("X-Vault-Token", token).ForceContentType("application/json").SetResult(&body).SetError(&body)
+	if b.cfg.Namespace != "" {
+		req.SetHeader("X-Vault-Namespace", b.cfg.Namespace)
+	}
+	resp, err := req.Get("/v1/" + strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	if resp.IsError() {
+		if len(body.Errors) > 0 {
+			return nil, fmt.Errorf("vault returned %s: %s", resp.Status(), strings.Join(body.Errors, "; "))
+		}
+		return nil, fmt.Errorf("vault returned %s", resp.Status())
+	}
+
+	data := body.Data
+	// KV v2 nests the secret under data, next to its metadata
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	return stringFields(data), nil
+}
+
+// awsSecretsBackend reads secrets from AWS Secrets Manager with signed GetSecretValue requests
+type awsSecretsBackend struct {
+	aws      awssdk.Config
+	endpoint string
+	http     *http.Client
+}
+
+func newAWSSecretsBackend(c *Config) (SecretBackend, error) {
+	cfg := c.Secrets.AWS
+	region := cfg.Region
+	for _, fallback := range []string{c.AWS.Region, c.S3.Region} {
+		if region == "" {
+			region = fallback
+		}
+	}
+	if region == "" {
+		return nil, fmt.Errorf("no AWS region (secrets.aws_sm.region or aws.region)")
+	}
+
+	opts := []func(*awscfg.LoadOptions) error{awscfg.WithRegion(region)}
+	switch {
+	case cfg.Profile != "":
+		opts = append(opts, awscfg.WithSharedConfigProfile(cfg.Profile))
+	case c.AWS.AccessKey != "":
+		opts = append(opts, awscfg.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(c.AWS.AccessKey, c.AWS.SecretKey, c.AWS.SessionToken)))
+	}
+	awsConfig, err := awscfg.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+	}
+	return &awsSecretsBackend{aws: awsConfig, endpoint: endpoint, http: &http.Client{Timeout: defaultSecretTimeout}}, nil
+}
+
+// Read reads a secret by name or ARN. A JSON object secret is returned by key and whole, under the empty key.
+func (b *awsSecretsBackend) Read(ctx context.Context, path string) (map[string]string, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds, err := b.aws.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("no AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", b.aws.Region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign Secrets Manager request: %w", err)
+	}
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("secrets manager request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary []byte `json:"SecretBinary"`
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid Secrets Manager response (%s): %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		errorType := body.Type[strings.LastIndex(body.Type, "#")+1:]
+		return nil, fmt.Errorf("secrets manager returned %s: %s %s", resp.Status, errorType, body.Message)
+	}
+
+	secret := body.SecretString
+	if secret == "" {
+		secret = string(body.SecretBinary)
+	}
+	fields := map[string]string{}
+	var object map[string]interface{}
+	if json.Unmarshal([]byte(secret), &object) == nil {
+		fields = stringFields(object)
+	}
+	fields[""] = secret
+	return fields, nil
+}
+
+// SecretWatcher resolves the secret references of a loaded configuration again on an interval, so rotated
+// credentials reach the providers
+type SecretWatcher struct {
+	data     []byte  // The configuration before its references were resolved
+	last     *Config // The configuration as last resolved
+	interval time.Duration
+	logger   *logging.Logger
+}
+
+// SecretWatcher returns the watcher of the configuration's secret references, or nil when it has none or
+// secrets.refresh_seconds is 0
+func (c *Config) SecretWatcher() *SecretWatcher {
+	return c.secretWatcher
+}
+
+// Run resolves the references every interval until ctx is done. onChange gets the top-level sections
+// (yaml names such as database or sentry) whose values changed and the configuration holding the new values.
+func (w *SecretWatcher) Run(ctx context.Context, onChange func(sections []string, next *Config)) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		sections, next, err := w.Refresh()
+		if err != nil {
+			w.logger.Error("secrets not refreshed; the previous values stay in force", logging.Error(err))
+			continue
+		}
+		if len(sections) > 0 {
+			w.logger.Info("secrets rotated", logging.String("sections", strings.Join(sections, ",")))
+			onChange(sections, next)
+		}
+	}
+}
+
+// Refresh resolves the references again and returns the sections whose values changed since the last time
+func (w *SecretWatcher) Refresh() ([]string, *Config, error) {
+	var unresolved Config
+	if err := yaml.Unmarshal(w.data, &unresolved); err != nil {
+		return nil, nil, err
+	}
+	unresolved.overrideWithEnv()
+	resolved, _, err := resolveSecretReferences(w.data, &unresolved)
+	if err != nil {
+		return nil, nil, err
+	}
+	next := &Config{}
+	if err := yaml.Unmarshal(resolved, next); err != nil {
+		return nil, nil, err
+	}
+	next.overrideWithEnv()
+
+	sections := changedSections(w.last, next)
+	w.last = next
+	return sections, next, nil
+}
+
+// changedSections compares two configurations by top-level section and returns the yaml names of those that differ
+func changedSections(a, b *Config) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	var sections []string
+	for i := 0; i < va.NumField(); i++ {
+		field := va.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			sections = append(sections, strings.Split(field.Tag.Get("yaml"), ",")[0])
+		}
+	}
+	return sections
+}
+
+// ApplySections copies the named top-level sections (yaml names) from another configuration
+func (c *Config) ApplySections(from *Config, sections []string) {
+	dst, src := reflect.ValueOf(c).Elem(), reflect.ValueOf(from).Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if field.IsExported() && slices.Contains(sections, name) {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
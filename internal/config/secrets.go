@@ -0,0 +1,251 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// envReference matches ${NAME} and ${NAME:-default}, and $${ which stands for a literal ${. Only upper-case
+// names are environment variables, so lower-case placeholders such as ${window} in SLO queries are kept.
+var envReference = regexp.MustCompile(`\$\$\{|\$\{([A-Z_][A-Z0-9_]*)(?::-([^}]*))?\}`)
+
+// resolve loads the secrets file of a configuration and expands the environment references of both,
+// returning the configuration with the secrets merged in
+func resolve(data []byte) ([]byte, error) {
+	var tree interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	// The secrets file may itself be named by an environment variable, but not by a secret
+	secretsFile := os.Getenv("MCP_SECRETS_FILE")
+	root, _ := tree.(map[interface{}]interface{})
+	if secretsFile == "" && root["secrets_file"] == nil && !bytes.Contains(data, []byte("${")) {
+		// Nothing to resolve; the file is used as written
+		return data, nil
+	}
+	if secretsFile == "" {
+		if path, ok := root["secrets_file"].(string); ok {
+			expanded, err := expandTree(path)
+			if err != nil {
+				return nil, fmt.Errorf("secrets_file: %w", err)
+			}
+			secretsFile = expanded.(string)
+		}
+	}
+	var secrets interface{}
+	if secretsFile != "" {
+		var err error
+		if secrets, err = loadSecrets(secretsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	tree, err := expandTree(tree)
+	if err != nil {
+		return nil, err
+	}
+	if secrets != nil {
+		if secrets, err = expandTree(secrets); err != nil {
+			return nil, fmt.Errorf("secrets file %s: %w", secretsFile, err)
+		}
+		tree = mergeTree(tree, secrets)
+	}
+	return yaml.Marshal(tree)
+}
+
+// loadSecrets reads a secrets file. A YAML file (.yaml or .yml) holds configuration values that are merged
+// over the configuration file and is returned; any other file is read as dotenv, whose variables are set
+// for the references and the MCP_* overrides unless the environment already has them.
+func loadSecrets(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var secrets interface{}
+		if err := yaml.Unmarshal(data, &secrets); err != nil {
+			return nil, fmt.Errorf("failed to parse secrets file %s: %w", path, err)
+		}
+		return secrets, nil
+	}
+
+	values, err := parseDotenv(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file %s: %w", path, err)
+	}
+	for name, value := range values {
+		if _, set := os.LookupEnv(name); !set {
+			os.Setenv(name, value)
+		}
+	}
+	return nil, nil
+}
+
+// parseDotenv reads NAME=value lines. Blank lines and # comments are skipped, an export prefix is allowed
+// and quotes around a value are removed; double-quoted values may contain escapes such as \n.
+func parseDotenv(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected NAME=value", number)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			} else {
+				value = value[1 : len(value)-1]
+			}
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		values[name] = value
+	}
+	return values, scanner.Err()
+}
+
+// expandTree replaces the environment references in every string value of a parsed YAML document. A value
+// that is a single reference takes the type its expansion reads as, so port: ${DB_PORT} stays a number.
+// A reference to an unset variable without a default is an error, so a missing secret is not silently empty.
+func expandTree(node interface{}) (interface{}, error) {
+	missing := map[string]bool{}
+	var expand func(node interface{}) interface{}
+	expand = func(node interface{}) interface{} {
+		switch v := node.(type) {
+		case string:
+			if location := envReference.FindStringIndex(v); location != nil && location[0] == 0 && location[1] == len(v) && v != "$${" {
+				return scalarValue(expandString(v, missing))
+			}
+			return expandString(v, missing)
+		case map[interface{}]interface{}:
+			for key, value := range v {
+				v[key] = expand(value)
+			}
+		case []interface{}:
+			for i, value := range v {
+				v[i] = expand(value)
+			}
+		}
+		return node
+	}
+	node = expand(node)
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unset environment variables: %s (use ${NAME:-default} for optional ones)", strings.Join(names, ", "))
+	}
+	return node, nil
+}
+
+func expandString(s string, missing map[string]bool) string {
+	return envReference.ReplaceAllStringFunc(s, func(reference string) string {
+		if reference == "$${" {
+			return "${"
+		}
+		match := envReference.FindStringSubmatch(reference)
+		if value, ok := os.LookupEnv(match[1]); ok {
+			return value
+		}
+		if strings.Contains(reference, ":-") {
+			return match[2]
+		}
+		missing[match[1]] = true
+		return ""
+	})
+}
+
+// scalarValue reads an expanded value as a YAML scalar, such as a number or a boolean. Values that would not be
+// written back the same, such as 0123 or yes, and anything but a scalar stay strings.
+func scalarValue(value string) interface{} {
+	var scalar interface{}
+	if err := yaml.Unmarshal([]byte(value), &scalar); err != nil {
+		return value
+	}
+	switch scalar.(type) {
+	case int, int64, uint64, float64, bool:
+		written, err := yaml.Marshal(scalar)
+		if err == nil && strings.TrimSpace(string(written)) == value {
+			return scalar
+		}
+	}
+	return value
+}
+
+// mergeTree merges overlay into base: maps are merged key by key, lists of named entries (such as
+// databases or auth.api_keys) entry by entry, and any other value of overlay replaces the one of base
+func mergeTree(base, overlay interface{}) interface{} {
+	switch o := overlay.(type) {
+	case map[interface{}]interface{}:
+		b, ok := base.(map[interface{}]interface{})
+		if !ok {
+			return overlay
+		}
+		for key, value := range o {
+			b[key] = mergeTree(b[key], value)
+		}
+		return b
+	case []interface{}:
+		b, ok := base.([]interface{})
+		if !ok || !namedEntries(o) || !namedEntries(b) {
+			return overlay
+		}
+		for _, entry := range o {
+			name := entry.(map[interface{}]interface{})["name"]
+			merged := false
+			for i, existing := range b {
+				if existing.(map[interface{}]interface{})["name"] == name {
+					b[i] = mergeTree(existing, entry)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				b = append(b, entry)
+			}
+		}
+		return b
+	}
+	return overlay
+}
+
+// namedEntries reports whether every entry of a list is a map with a name
+func namedEntries(list []interface{}) bool {
+	for _, entry := range list {
+		m, ok := entry.(map[interface{}]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["name"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadExpandsTypedReferences(t *testing.T) {
+	t.Setenv("TEST_DB_PORT", "5433")
+	t.Setenv("TEST_STATELESS", "true")
+	t.Setenv("TEST_DB_PASSWORD", "0123")
+	t.Setenv("TEST_DB_NAME", "5433")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte(`server:
+  stateless: ${TEST_STATELESS}
+  port: ${TEST_SERVER_PORT:-9090}
+database:
+  host: localhost
+  port: ${TEST_DB_PORT}
+  password: ${TEST_DB_PASSWORD}
+  dbname: ${TEST_DB_NAME}
+`)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Database.Port != 5433 {
+		t.Errorf("database.port = %d, want 5433", cfg.Database.Port)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("server.port = %d, want 9090", cfg.Server.Port)
+	}
+	if !cfg.Server.Stateless {
+		t.Error("server.stateless = false, want true")
+	}
+	if cfg.Database.Password != "0123" {
+		t.Errorf("database.password = %q, want 0123", cfg.Database.Password)
+	}
+	if cfg.Database.DBName != "5433" {
+		t.Errorf("database.dbname = %q, want 5433", cfg.Database.DBName)
+	}
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"path"
 	"strings"
+	"time"
 )
 
 // ConfigStatus represents the configuration status of a service
@@ -38,6 +40,16 @@ func (c *Config) ValidateConfig() *ValidationResult {
 		result.Errors = append(result.Errors, dbStatus.Message)
 	}
 
+	// Validate named database connections
+	if len(c.Databases) > 0 {
+		databasesStatus := c.validateDatabasesConfig()
+		result.Services = append(result.Services, databasesStatus)
+		if !databasesStatus.Configured {
+			result.Valid = false
+			result.Errors = append(result.Errors, databasesStatus.Message)
+		}
+	}
+
 	// Validate Loki Configuration
 	lokiStatus := c.validateLokiConfig()
 	result.Services = append(result.Services, lokiStatus)
@@ -45,6 +57,13 @@ func (c *Config) ValidateConfig() *ValidationResult {
 		result.Warnings = append(result.Warnings, lokiStatus.Message)
 	}
 
+	// Validate Grafana Configuration
+	grafanaStatus := c.validateGrafanaConfig()
+	result.Services = append(result.Services, grafanaStatus)
+	if !grafanaStatus.Configured {
+		result.Warnings = append(result.Warnings, grafanaStatus.Message)
+	}
+
 	// Validate S3 Configuration
 	s3Status := c.validateS3Config()
 	result.Services = append(result.Services, s3Status)
@@ -66,6 +85,69 @@ func (c *Config) ValidateConfig() *ValidationResult {
 		result.Warnings = append(result.Warnings, swaggerStatus.Message)
 	}
 
+	// Validate Knowledge Configuration
+	knowledgeStatus := c.validateKnowledgeConfig()
+	result.Services = append(result.Services, knowledgeStatus)
+	if !knowledgeStatus.Configured {
+		result.Warnings = append(result.Warnings, knowledgeStatus.Message)
+	}
+
+	// Validate Catalog Configuration
+	catalogStatus := c.validateCatalogConfig()
+	result.Services = append(result.Services, catalogStatus)
+	if !catalogStatus.Configured {
+		result.Warnings = append(result.Warnings, catalogStatus.Message)
+	}
+
+	// Validate CI/CD Configuration
+	cicdStatus := c.validateCICDConfig()
+	result.Services = append(result.Services, cicdStatus)
+	if !cicdStatus.Configured {
+		result.Warnings = append(result.Warnings, cicdStatus.Message)
+	}
+
+	// Validate Artifact Registry Configuration
+	registryStatus := c.validateRegistryConfig()
+	result.Services = append(result.Services, registryStatus)
+	if !registryStatus.Configured {
+		result.Warnings = append(result.Warnings, registryStatus.Message)
+	}
+
+	// Validate Terraform Configuration
+	terraformStatus := c.validateTerraformConfig()
+	result.Services = append(result.Services, terraformStatus)
+	if !terraformStatus.Configured {
+		result.Warnings = append(result.Warnings, terraformStatus.Message)
+	}
+
+	// Validate AWS Configuration
+	awsStatus := c.validateAWSConfig()
+	result.Services = append(result.Services, awsStatus)
+	if !awsStatus.Configured {
+		result.Warnings = append(result.Warnings, awsStatus.Message)
+	}
+
+	// Validate Email Configuration
+	emailStatus := c.validateEmailConfig()
+	result.Services = append(result.Services, emailStatus)
+	if !emailStatus.Configured {
+		result.Warnings = append(result.Warnings, emailStatus.Message)
+	}
+
+	// Validate Calendar Configuration
+	calendarStatus := c.validateCalendarConfig()
+	result.Services = append(result.Services, calendarStatus)
+	if !calendarStatus.Configured {
+		result.Warnings = append(result.Warnings, calendarStatus.Message)
+	}
+
+	// Validate On-call Configuration
+	oncallStatus := c.validateOnCallConfig()
+	result.Services = append(result.Services, oncallStatus)
+	if !oncallStatus.Configured {
+		result.Warnings = append(result.Warnings, oncallStatus.Message)
+	}
+
 	// Validate Auth Configuration
 	authStatus := c.validateAuthConfig()
 	result.Services = append(result.Services, authStatus)
@@ -80,7 +162,7 @@ func (c *Config) ValidateConfig() *ValidationResult {
 func (c *Config) validateDatabaseConfig() ConfigStatus {
 	status := ConfigStatus{
 		Service:  "database",
-		Required: true, // Database is required for core functionality
+		Required: len(c.Databases) == 0, // Required for core functionality unless named connections are configured
 	}
 
 	missing := []string{}
@@ -100,7 +182,11 @@ func (c *Config) validateDatabaseConfig() ConfigStatus {
 		missing = append(missing, "dbname")
 	}
 
-	if len(missing) > 0 {
+	driver := strings.ToLower(c.Database.Driver)
+	if driver != "" && driver != "mysql" && driver != "mariadb" && driver != "postgres" && driver != "postgresql" && driver != "pgsql" {
+		status.Configured = false
+		status.Message = fmt.Sprintf("Database not configured: unsupported driver %q (use mysql or postgres)", c.Database.Driver)
+	} else if len(missing) > 0 {
 		status.Configured = false
 		status.Message = fmt.Sprintf("Database not configured: missing %s", strings.Join(missing, ", "))
 	} else {
@@ -111,6 +197,47 @@ func (c *Config) validateDatabaseConfig() ConfigStatus {
 	return status
 }
 
+// validateDatabasesConfig validates the additional named database connections
+func (c *Config) validateDatabasesConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "databases",
+		Required: false,
+	}
+
+	problems := []string{}
+	seen := map[string]bool{}
+	if c.Database.Host != "" {
+		name := c.Database.Name
+		if name == "" {
+			name = "default"
+		}
+		seen[name] = true
+	}
+	for i, db := range c.Databases {
+		if db.Name == "" {
+			problems = append(problems, fmt.Sprintf("connection %d has no name", i))
+			continue
+		}
+		if seen[db.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate connection name %q", db.Name))
+		}
+		seen[db.Name] = true
+		if db.Host == "" || db.Username == "" || db.DBName == "" {
+			problems = append(problems, fmt.Sprintf("connection %q missing host, username or dbname", db.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		status.Configured = false
+		status.Message = fmt.Sprintf("Database connections invalid: %s", strings.Join(problems, "; "))
+	} else {
+		status.Configured = true
+		status.Message = fmt.Sprintf("%d named database connections configured", len(c.Databases))
+	}
+
+	return status
+}
+
 // validateLokiConfig validates Loki configuration
 func (c *Config) validateLokiConfig() ConfigStatus {
 	status := ConfigStatus{
@@ -129,6 +256,28 @@ func (c *Config) validateLokiConfig() ConfigStatus {
 	return status
 }
 
+// validateGrafanaConfig validates Grafana configuration
+func (c *Config) validateGrafanaConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "grafana",
+		Required: false,
+	}
+
+	switch {
+	case c.Grafana.URL == "":
+		status.Configured = false
+		status.Message = "Grafana not configured: missing url"
+	case c.Grafana.APIToken == "":
+		status.Configured = false
+		status.Message = "Grafana not configured: missing api_token"
+	default:
+		status.Configured = true
+		status.Message = "Grafana configuration is complete"
+	}
+
+	return status
+}
+
 // validateS3Config validates S3 configuration
 func (c *Config) validateS3Config() ConfigStatus {
 	status := ConfigStatus{
@@ -153,6 +302,9 @@ func (c *Config) validateS3Config() ConfigStatus {
 	} else {
 		status.Configured = true
 		status.Message = "S3 configuration is complete"
+		if c.S3.Select.Local {
+			status.Message += " (s3_select runs locally)"
+		}
 	}
 
 	return status
@@ -194,6 +346,282 @@ func (c *Config) validateSwaggerConfig() ConfigStatus {
 	return status
 }
 
+// validateKnowledgeConfig validates knowledge base configuration
+func (c *Config) validateKnowledgeConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "knowledge",
+		Required: false,
+	}
+
+	if c.Knowledge.Dir == "" && c.Knowledge.S3Bucket == "" {
+		status.Configured = false
+		status.Message = "Knowledge base not configured: missing dir or s3_bucket"
+	} else {
+		status.Configured = true
+		status.Message = "Knowledge base configuration is complete"
+	}
+
+	return status
+}
+
+// validateCatalogConfig validates service catalog configuration
+func (c *Config) validateCatalogConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "catalog",
+		Required: false,
+	}
+
+	if c.Catalog.File == "" {
+		status.Configured = false
+		status.Message = "Service catalog not configured: missing file"
+	} else {
+		status.Configured = true
+		status.Message = "Service catalog configuration is complete"
+	}
+
+	return status
+}
+
+// validateCICDConfig validates CI/CD provider configuration
+func (c *Config) validateCICDConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "cicd",
+		Required: false,
+	}
+
+	systems := []string{}
+	if c.CICD.GitHub.Token != "" {
+		systems = append(systems, "github")
+	}
+	if c.CICD.GitLab.Token != "" {
+		systems = append(systems, "gitlab")
+	}
+	if c.CICD.Jenkins.URL != "" {
+		systems = append(systems, "jenkins")
+	}
+
+	if len(systems) == 0 {
+		status.Configured = false
+		status.Message = "CI/CD not configured: missing github token, gitlab token or jenkins url"
+	} else {
+		status.Configured = true
+		status.Message = fmt.Sprintf("CI/CD configured for %s", strings.Join(systems, ", "))
+	}
+
+	return status
+}
+
+// validateRegistryConfig validates artifact registry configuration
+func (c *Config) validateRegistryConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "registry",
+		Required: false,
+	}
+
+	if len(c.Registries) == 0 {
+		status.Configured = false
+		status.Message = "Artifact registries not configured"
+		return status
+	}
+
+	problems := []string{}
+	for i, r := range c.Registries {
+		if r.Name == "" || r.URL == "" {
+			problems = append(problems, fmt.Sprintf("registry %d missing name or url", i))
+		}
+		switch strings.ToLower(r.Type) {
+		case "docker", "npm", "maven":
+		default:
+			problems = append(problems, fmt.Sprintf("registry %q has unsupported type %q", r.Name, r.Type))
+		}
+	}
+
+	if len(problems) > 0 {
+		status.Configured = false
+		status.Message = fmt.Sprintf("Artifact registries invalid: %s", strings.Join(problems, "; "))
+	} else {
+		status.Configured = true
+		status.Message = fmt.Sprintf("%d artifact registries configured", len(c.Registries))
+	}
+
+	return status
+}
+
+// validateTerraformConfig validates Terraform state configuration
+func (c *Config) validateTerraformConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "terraform",
+		Required: false,
+	}
+
+	if len(c.Terraform.States) == 0 {
+		status.Configured = false
+		status.Message = "Terraform states not configured"
+		return status
+	}
+
+	problems := []string{}
+	for i, st := range c.Terraform.States {
+		if st.Name == "" {
+			problems = append(problems, fmt.Sprintf("state %d has no name", i))
+		}
+		if st.Path == "" && (st.Bucket == "" || st.Key == "") {
+			problems = append(problems, fmt.Sprintf("state %q needs a path or an S3 bucket and key", st.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		status.Configured = false
+		status.Message = fmt.Sprintf("Terraform states invalid: %s", strings.Join(problems, "; "))
+	} else {
+		status.Configured = true
+		status.Message = fmt.Sprintf("%d Terraform states configured", len(c.Terraform.States))
+	}
+
+	return status
+}
+
+// validateAWSConfig validates the AWS describe configuration
+func (c *Config) validateAWSConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "aws",
+		Required: false,
+	}
+
+	region := c.AWS.Region
+	if region == "" {
+		region = c.S3.Region
+	}
+
+	switch {
+	case !c.AWS.Enabled:
+		status.Configured = false
+		status.Message = "AWS describe tools are disabled"
+	case region == "":
+		status.Configured = false
+		status.Message = "AWS enabled but no region configured (aws.region or s3.region)"
+	case (c.AWS.AccessKey == "") != (c.AWS.SecretKey == ""):
+		status.Configured = false
+		status.Message = "AWS access_key and secret_key must be set together"
+	default:
+		status.Configured = true
+		status.Message = fmt.Sprintf("AWS describe tools configured for region %s", region)
+	}
+
+	return status
+}
+
+// validateEmailConfig validates the email configuration
+func (c *Config) validateEmailConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "email",
+		Required: false,
+	}
+
+	if c.Email.IMAPHost == "" && c.Email.SMTPHost == "" {
+		status.Configured = false
+		status.Message = "Email not configured"
+		return status
+	}
+
+	if c.Email.Username == "" || c.Email.Password == "" {
+		status.Configured = false
+		status.Message = "Email configured but username or password is missing"
+		return status
+	}
+
+	features := []string{}
+	if c.Email.IMAPHost != "" {
+		features = append(features, "IMAP search (read-only)")
+	}
+	if c.Email.SMTPHost != "" {
+		if c.Email.AllowSend {
+			features = append(features, "SMTP send")
+		} else {
+			features = append(features, "SMTP send disabled (allow_send is false)")
+		}
+	}
+
+	status.Configured = true
+	status.Message = fmt.Sprintf("Email configured: %s", strings.Join(features, ", "))
+	return status
+}
+
+// validateCalendarConfig validates the deploy freeze and on-call calendar feeds
+func (c *Config) validateCalendarConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "calendar",
+		Required: false,
+	}
+
+	if len(c.Calendar.Feeds) == 0 {
+		status.Configured = false
+		status.Message = "Calendar feeds not configured"
+		return status
+	}
+
+	problems := []string{}
+	for i, feed := range c.Calendar.Feeds {
+		if feed.Name == "" || feed.URL == "" {
+			problems = append(problems, fmt.Sprintf("feed %d missing name or url", i))
+		}
+		if feed.Type != "freeze" && feed.Type != "oncall" {
+			problems = append(problems, fmt.Sprintf("feed %q has unsupported type %q", feed.Name, feed.Type))
+		}
+	}
+	if c.Calendar.Timezone != "" {
+		if _, err := time.LoadLocation(c.Calendar.Timezone); err != nil {
+			problems = append(problems, fmt.Sprintf("unknown timezone %q", c.Calendar.Timezone))
+		}
+	}
+	for _, pattern := range c.Calendar.BlockedTools {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid blocked_tools pattern %q", pattern))
+		}
+	}
+
+	if len(problems) > 0 {
+		status.Configured = false
+		status.Message = fmt.Sprintf("Calendar feeds invalid: %s", strings.Join(problems, "; "))
+	} else {
+		status.Configured = true
+		status.Message = fmt.Sprintf("%d calendar feeds configured", len(c.Calendar.Feeds))
+	}
+
+	return status
+}
+
+// validateOnCallConfig validates the PagerDuty and Opsgenie accounts
+func (c *Config) validateOnCallConfig() ConfigStatus {
+	status := ConfigStatus{
+		Service:  "oncall",
+		Required: false,
+	}
+
+	systems := []string{}
+	if c.OnCall.PagerDuty.APIToken != "" {
+		systems = append(systems, "pagerduty")
+	}
+	if c.OnCall.Opsgenie.APIKey != "" {
+		systems = append(systems, "opsgenie")
+	}
+
+	switch {
+	case len(systems) == 0:
+		status.Configured = false
+		status.Message = "On-call not configured: missing pagerduty api_token or opsgenie api_key"
+	case c.OnCall.WriteEnabled && c.OnCall.PagerDuty.APIToken != "" && c.OnCall.PagerDuty.From == "":
+		status.Configured = true
+		status.Message = fmt.Sprintf("On-call configured for %s; PagerDuty acknowledgements need pagerduty.from unless impersonation names an email",
+			strings.Join(systems, ", "))
+	default:
+		status.Configured = true
+		status.Message = fmt.Sprintf("On-call configured for %s", strings.Join(systems, ", "))
+	}
+
+	return status
+}
+
 // validateAuthConfig validates authentication configuration
 func (c *Config) validateAuthConfig() ConfigStatus {
 	status := ConfigStatus{
@@ -223,6 +651,19 @@ func (c *Config) validateAuthConfig() ConfigStatus {
 		}
 	}
 
+	for pattern, roles := range c.Auth.ToolPermissions {
+		if _, err := path.Match(pattern, ""); err != nil {
+			status.Configured = false
+			status.Message = fmt.Sprintf("Invalid auth.tool_permissions pattern %q: %v", pattern, err)
+			break
+		}
+		if len(roles) == 0 {
+			status.Configured = false
+			status.Message = fmt.Sprintf("auth.tool_permissions entry %q has no roles", pattern)
+			break
+		}
+	}
+
 	return status
 }
 
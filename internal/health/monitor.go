@@ -0,0 +1,294 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+	"dev-mcp/internal/provider"
+)
+
+const (
+	defaultInterval = time.Minute
+	defaultTimeout  = 5 * time.Second
+	defaultSlow     = time.Second
+)
+
+// Dependency status values
+const (
+	StatusOK       = "ok"
+	StatusSlow     = "slow" // Answered, but slower than health.slow_ms
+	StatusDown     = "down"
+	StatusDegraded = "degraded" // Overall: something is slow or down
+)
+
+// llmDefaults are the model list endpoints of the hosted LLM APIs
+var llmDefaults = map[string]string{
+	"openai":    "https://api.openai.com/v1",
+	"anthropic": "https://api.anthropic.com/v1",
+}
+
+// Dependency is the last check of one external dependency
+type Dependency struct {
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"` // provider, llm or endpoint
+	Target    string    `json:"target,omitempty"`
+	Status    string    `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Snapshot is the state of every dependency after a refresh
+type Snapshot struct {
+	Status       string         `json:"status"` // ok or degraded
+	Summary      string         `json:"summary"`
+	Dependencies []Dependency   `json:"dependencies"`
+	Counts       map[string]int `json:"counts"`
+	CheckedAt    time.Time      `json:"checked_at"`
+	NextCheck    time.Time      `json:"next_check"`
+}
+
+// httpCheck is an HTTP dependency checked with a GET
+type httpCheck struct {
+	name         string
+	kind         string
+	url          string
+	headers      map[string]string
+	expectStatus int
+}
+
+// Monitor checks the providers' backends, the LLM APIs and the configured endpoints periodically
+// and keeps the last snapshot
+type Monitor struct {
+	providers *provider.ProviderRegistry
+	checks    []httpCheck
+	http      *resty.Client
+	interval  time.Duration
+	slow      time.Duration
+	logger    *logging.Logger
+
+	mu       sync.Mutex // Held during a refresh so concurrent reads wait for it instead of checking again
+	snapshot *Snapshot
+}
+
+// NewMonitor creates a monitor over the registry's providers, the enabled LLM providers and the health endpoints
+func NewMonitor(cfg *config.HealthConfig, llm *config.LLMConfig, providers *provider.ProviderRegistry) *Monitor {
+	m := &Monitor{
+		providers: providers,
+		interval:  defaultInterval,
+		slow:      defaultSlow,
+		logger:    logging.New("health"),
+	}
+	timeout := defaultTimeout
+	if cfg.IntervalSeconds > 0 {
+		m.interval = time.Duration(cfg.IntervalSeconds) * time.Second
+	}
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	if cfg.SlowMs > 0 {
+		m.slow = time.Duration(cfg.SlowMs) * time.Millisecond
+	}
+	m.http = resty.New().
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(timeout)
+
+	if !cfg.SkipLLM && llm != nil {
+		for _, p := range llm.Providers {
+			if check, ok := llmCheck(p); ok {
+				m.checks = append(m.checks, check)
+			}
+		}
+	}
+	for _, endpoint := range cfg.Endpoints {
+		if endpoint.URL == "" {
+			continue
+		}
+		name := endpoint.Name
+		if name == "" {
+			name = endpoint.URL
+		}
+		m.checks = append(m.checks, httpCheck{name: name, kind: "endpoint", url: endpoint.URL, expectStatus: endpoint.ExpectStatus})
+	}
+	return m
+}
+
+// llmCheck lists the models of an enabled LLM provider, which needs a valid key but costs no tokens
+func llmCheck(p config.ProviderConfig) (httpCheck, bool) {
+	if !p.Enabled {
+		return httpCheck{}, false
+	}
+	base := strings.TrimSuffix(p.Endpoint, "/")
+	if base == "" {
+		base = llmDefaults[p.Type]
+	}
+	if base == "" {
+		return httpCheck{}, false
+	}
+
+	check := httpCheck{name: p.Name, kind: "llm", url: base + "/models", headers: map[string]string{}}
+	if check.name == "" {
+		check.name = p.Type
+	}
+	switch {
+	case p.Type == "anthropic":
+		check.headers["x-api-key"] = p.APIKey
+		check.headers["anthropic-version"] = "2023-06-01"
+	case p.APIKey != "":
+		check.headers["Authorization"] = "Bearer " + p.APIKey
+	}
+	return check, true
+}
+
+// Run refreshes the snapshot every interval until ctx is done
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Snapshot returns the last snapshot, refreshing it first when there is none yet or it is older than
+// the interval (the monitor is not running, e.g. before the transport starts)
+func (m *Monitor) Snapshot(ctx context.Context) *Snapshot {
+	m.mu.Lock()
+	snapshot := m.snapshot
+	m.mu.Unlock()
+	if snapshot != nil && time.Since(snapshot.CheckedAt) < 2*m.interval {
+		return snapshot
+	}
+	return m.Refresh(ctx)
+}
+
+// Refresh checks every dependency and stores the snapshot
+func (m *Monitor) Refresh(ctx context.Context) *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var dependencies []Dependency
+	for _, state := range m.providers.HealthCheck() {
+		if dependency, ok := m.fromProvider(state); ok {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	results := make([]Dependency, len(m.checks))
+	var wg sync.WaitGroup
+	for i, check := range m.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.checkHTTP(ctx, check)
+		}()
+	}
+	wg.Wait()
+	dependencies = append(dependencies, results...)
+
+	now := time.Now()
+	snapshot := &Snapshot{
+		Status:       StatusOK,
+		Dependencies: dependencies,
+		Counts:       map[string]int{StatusOK: 0, StatusSlow: 0, StatusDown: 0},
+		CheckedAt:    now,
+		NextCheck:    now.Add(m.interval),
+	}
+	if snapshot.Dependencies == nil {
+		snapshot.Dependencies = []Dependency{}
+	}
+
+	var down, slow []string
+	for _, dependency := range dependencies {
+		snapshot.Counts[dependency.Status]++
+		switch dependency.Status {
+		case StatusDown:
+			down = append(down, dependency.Name)
+		case StatusSlow:
+			slow = append(slow, dependency.Name)
+		}
+	}
+	switch {
+	case len(dependencies) == 0:
+		snapshot.Summary = "no external dependencies are configured"
+	case len(down) > 0 || len(slow) > 0:
+		snapshot.Status = StatusDegraded
+		var parts []string
+		if len(down) > 0 {
+			parts = append(parts, "down: "+strings.Join(down, ", "))
+		}
+		if len(slow) > 0 {
+			parts = append(parts, "slow: "+strings.Join(slow, ", "))
+		}
+		snapshot.Summary = strings.Join(parts, "; ")
+		if m.snapshot == nil || m.snapshot.Summary != snapshot.Summary {
+			m.logger.Warn("dependencies degraded", logging.String("summary", snapshot.Summary))
+		}
+	default:
+		snapshot.Summary = fmt.Sprintf("all %d dependencies healthy", len(dependencies))
+	}
+
+	m.snapshot = snapshot
+	return snapshot
+}
+
+// fromProvider turns a provider state into a dependency: providers that were health checked, and
+// configured providers that failed to start. Providers that are not configured are left out.
+func (m *Monitor) fromProvider(state provider.ProviderState) (Dependency, bool) {
+	dependency := Dependency{Name: state.Name, Kind: "provider", Error: state.LastError}
+	switch {
+	case state.CheckedAt != nil:
+		dependency.CheckedAt = *state.CheckedAt
+		dependency.LatencyMs = state.LatencyMs
+		dependency.Status = m.status(time.Duration(state.LatencyMs)*time.Millisecond, state.LastError != "")
+	case state.Enabled && state.LastError != "":
+		dependency.CheckedAt = time.Now()
+		dependency.Status = StatusDown
+	default:
+		return Dependency{}, false
+	}
+	return dependency, true
+}
+
+// checkHTTP requests an HTTP dependency
+func (m *Monitor) checkHTTP(ctx context.Context, check httpCheck) Dependency {
+	dependency := Dependency{Name: check.name, Kind: check.kind, Target: check.url}
+	start := time.Now()
+	resp, err := m.http.R().SetContext(ctx).SetHeaders(check.headers).Get(check.url)
+	dependency.CheckedAt = time.Now()
+	latency := dependency.CheckedAt.Sub(start)
+	dependency.LatencyMs = latency.Milliseconds()
+
+	switch {
+	case err != nil:
+		dependency.Error = err.Error()
+	case check.expectStatus != 0 && resp.StatusCode() != check.expectStatus:
+		dependency.Error = fmt.Sprintf("status %d, expected %d", resp.StatusCode(), check.expectStatus)
+	case check.expectStatus == 0 && !resp.IsSuccess():
+		dependency.Error = "status " + resp.Status()
+	}
+	dependency.Status = m.status(latency, dependency.Error != "")
+	return dependency
+}
+
+func (m *Monitor) status(latency time.Duration, failed bool) string {
+	switch {
+	case failed:
+		return StatusDown
+	case latency > m.slow:
+		return StatusSlow
+	default:
+		return StatusOK
+	}
+}
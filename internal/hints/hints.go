@@ -0,0 +1,142 @@
+package hints
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/config"
+)
+
+// Defaults for hints
+const (
+	defaultRefresh  = 5 * time.Minute
+	defaultTimeout  = 5 * time.Second
+	defaultMaxChars = 500
+)
+
+// Source gathers the hints of the tools, keyed by tool name
+type Source func(ctx context.Context) map[string]string
+
+type withoutHintsKey struct{}
+
+// WithoutHints marks a context whose tools/list shows the descriptions as the tools declare them
+func WithoutHints(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutHintsKey{}, true)
+}
+
+// Hinter appends live hints, such as the connections, buckets or labels available, to the tool
+// descriptions of tools/list. Hints are cached and regathered in the background once they are stale,
+// so listing tools does not wait on the providers.
+type Hinter struct {
+	source   Source
+	refresh  time.Duration
+	timeout  time.Duration
+	maxChars int
+
+	mu      sync.Mutex
+	hints   map[string]string
+	updated time.Time
+	pending chan struct{} // Closed when the gathering in progress ends; nil when none is
+}
+
+// New creates a hinter gathering hints from source, or returns nil when hints are disabled
+func New(cfg *config.HintsConfig, source Source) *Hinter {
+	if !cfg.Enabled {
+		return nil
+	}
+	h := &Hinter{
+		source:   source,
+		refresh:  time.Duration(cfg.RefreshSeconds) * time.Second,
+		timeout:  time.Duration(cfg.TimeoutSeconds) * time.Second,
+		maxChars: cfg.MaxChars,
+	}
+	if h.refresh <= 0 {
+		h.refresh = defaultRefresh
+	}
+	if h.timeout <= 0 {
+		h.timeout = defaultTimeout
+	}
+	if h.maxChars <= 0 {
+		h.maxChars = defaultMaxChars
+	}
+	return h
+}
+
+// Refresh returns how long hints are kept before they are gathered again
+func (h *Hinter) Refresh() time.Duration {
+	return h.refresh
+}
+
+// Middleware returns MCP receiving middleware that appends the hint of each tool to its description
+// in tools/list
+func (h *Hinter) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if method != "tools/list" || err != nil || ctx.Value(withoutHintsKey{}) != nil {
+				return result, err
+			}
+			r, ok := result.(*mcp.ListToolsResult)
+			if !ok {
+				return result, nil
+			}
+
+			hints := h.current()
+			for i, tool := range r.Tools {
+				hint := hints[tool.Name]
+				if hint == "" {
+					continue
+				}
+				if utf8.RuneCountInString(hint) > h.maxChars {
+					hint = string([]rune(hint)[:h.maxChars]) + "…"
+				}
+				// The server lists its own tools, so extend a copy
+				hinted := *tool
+				hinted.Description = tool.Description + "\n\n" + hint
+				r.Tools[i] = &hinted
+			}
+			return result, nil
+		}
+	}
+}
+
+// current returns the cached hints, starting to gather them again when they are stale. Until hints
+// were gathered once, it waits for them up to the timeout.
+func (h *Hinter) current() map[string]string {
+	h.mu.Lock()
+	if h.pending == nil && time.Since(h.updated) >= h.refresh {
+		h.pending = make(chan struct{})
+		go h.gather(h.pending)
+	}
+	hints, pending, first := h.hints, h.pending, h.updated.IsZero()
+	h.mu.Unlock()
+
+	if !first || pending == nil {
+		return hints
+	}
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+	select {
+	case <-pending:
+	case <-timer.C:
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hints
+}
+
+// gather collects the hints of the source as the server itself, not as the caller of tools/list
+func (h *Hinter) gather(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	hints := h.source(ctx)
+
+	h.mu.Lock()
+	h.hints, h.updated, h.pending = hints, time.Now(), nil
+	h.mu.Unlock()
+	close(done)
+}
@@ -0,0 +1,342 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/config"
+)
+
+// SourceLocale is the language the tools are written in; it needs no catalog
+const SourceLocale = "en"
+
+// LocaleHeader picks the locale of a single request, ahead of the API key's locale and Accept-Language
+const LocaleHeader = "X-MCP-Locale"
+
+// Catalog holds the translations of one locale, one <locale>.yaml file per locale in the i18n directory
+type Catalog struct {
+	Locale   string            `yaml:"locale"`
+	Tools    map[string]string `yaml:"tools"`    // Tool name -> description; empty entries are not translated yet
+	Messages map[string]string `yaml:"messages"` // English phrase of error results (e.g. "Database Error") -> translation
+}
+
+// catalog is a loaded catalog with its message replacer
+type catalog struct {
+	Catalog
+	messages *strings.Replacer
+}
+
+type localeKey struct{}
+
+// WithLocale attaches a locale to a context; it takes precedence over the request and the API key
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Translator translates tool descriptions and error results into the caller's locale
+type Translator struct {
+	dir           string
+	defaultLocale string
+	catalogs      map[string]*catalog // Lower-cased locale -> catalog
+}
+
+// NewTranslator loads every *.yaml catalog of the i18n directory. A missing directory yields a translator
+// that only knows the source locale.
+func NewTranslator(cfg *config.I18nConfig) (*Translator, error) {
+	t := &Translator{dir: cfg.Directory, defaultLocale: cfg.DefaultLocale, catalogs: map[string]*catalog{}}
+	if t.dir == "" {
+		t.dir = "configs/i18n"
+	}
+	if t.defaultLocale == "" {
+		t.defaultLocale = SourceLocale
+	}
+
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return nil, fmt.Errorf("failed to read i18n directory: %w", err)
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		c, err := loadCatalog(filepath.Join(t.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if c.Locale == "" {
+			c.Locale = strings.TrimSuffix(entry.Name(), ext)
+		}
+		key := strings.ToLower(c.Locale)
+		if _, ok := t.catalogs[key]; ok {
+			return nil, fmt.Errorf("i18n catalog %s: duplicate locale %q", entry.Name(), c.Locale)
+		}
+		t.catalogs[key] = c
+	}
+	return t, nil
+}
+
+func loadCatalog(path string) (*catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read i18n catalog: %w", err)
+	}
+	c := &catalog{}
+	if err := yaml.Unmarshal(data, &c.Catalog); err != nil {
+		return nil, fmt.Errorf("i18n catalog %s: %w", filepath.Base(path), err)
+	}
+
+	// Longer phrases first, so "Rate Limit Error" wins over a shorter phrase it contains
+	phrases := make([]string, 0, len(c.Messages))
+	for phrase, translation := range c.Messages {
+		if phrase != "" && translation != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+	sort.Slice(phrases, func(i, j int) bool {
+		if len(phrases[i]) != len(phrases[j]) {
+			return len(phrases[i]) > len(phrases[j])
+		}
+		return phrases[i] < phrases[j]
+	})
+	pairs := make([]string, 0, 2*len(phrases))
+	for _, phrase := range phrases {
+		pairs = append(pairs, phrase, c.Messages[phrase])
+	}
+	c.messages = strings.NewReplacer(pairs...)
+	return c, nil
+}
+
+// Locales returns the source locale and the locales with a catalog
+func (t *Translator) Locales() []string {
+	locales := []string{SourceLocale}
+	for _, c := range t.catalogs {
+		locales = append(locales, c.Locale)
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// DefaultLocale returns the locale used when neither the API key nor the request picks one
+func (t *Translator) DefaultLocale() string {
+	return t.defaultLocale
+}
+
+// Negotiate returns the first supported locale of the preferences, each a locale or an Accept-Language
+// list, falling back to the default locale
+func (t *Translator) Negotiate(preferences ...string) string {
+	for _, preference := range preferences {
+		for _, tag := range parseAcceptLanguage(preference) {
+			if locale, ok := t.match(tag); ok {
+				return locale
+			}
+		}
+	}
+	return t.defaultLocale
+}
+
+// match finds the locale of a language tag: an exact match, or the first catalog of the same language
+func (t *Translator) match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "*" {
+		return "", false
+	}
+	base, _, _ := strings.Cut(tag, "-")
+	if tag == SourceLocale || base == SourceLocale {
+		return SourceLocale, true
+	}
+	if c, ok := t.catalogs[tag]; ok {
+		return c.Locale, true
+	}
+	var candidates []string
+	for key, c := range t.catalogs {
+		if strings.HasPrefix(key, base+"-") || key == base {
+			candidates = append(candidates, c.Locale)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.Strings(candidates)
+	return candidates[0], true
+}
+
+// parseAcceptLanguage returns the tags of an Accept-Language value by descending quality; a plain
+// locale is a list of one
+func parseAcceptLanguage(value string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(value, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			tags = append(tags, weighted{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].quality > tags[j].quality })
+
+	result := make([]string, len(tags))
+	for i, tag := range tags {
+		result[i] = tag.tag
+	}
+	return result
+}
+
+// Description returns the description of a tool in a locale, or the source description
+func (t *Translator) Description(locale string, tool *mcp.Tool) string {
+	if c := t.catalogs[strings.ToLower(locale)]; c != nil && c.Tools[tool.Name] != "" {
+		return c.Tools[tool.Name]
+	}
+	return tool.Description
+}
+
+// Message translates the known phrases of an error result text into a locale
+func (t *Translator) Message(locale, text string) string {
+	if c := t.catalogs[strings.ToLower(locale)]; c != nil {
+		return c.messages.Replace(text)
+	}
+	return text
+}
+
+// Middleware returns MCP receiving middleware that translates the tool descriptions of tools/list and
+// the text of error results of tools/call. The locale is taken from the context, the X-MCP-Locale
+// header, the API key (keyLocale, may be nil), the Accept-Language header and the default, in this order.
+func (t *Translator) Middleware(keyLocale func(context.Context, mcp.Request) string) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if (method != "tools/list" && method != "tools/call") || len(t.catalogs) == 0 {
+				return next(ctx, method, req)
+			}
+
+			result, err := next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+			locale := t.requestLocale(ctx, req, keyLocale)
+			if strings.EqualFold(locale, SourceLocale) {
+				return result, nil
+			}
+
+			switch r := result.(type) {
+			case *mcp.ListToolsResult:
+				for i, tool := range r.Tools {
+					if description := t.Description(locale, tool); description != tool.Description {
+						// The server lists its own tools, so translate a copy
+						translated := *tool
+						translated.Description = description
+						r.Tools[i] = &translated
+					}
+				}
+			case *mcp.CallToolResult:
+				if r.IsError {
+					for _, content := range r.Content {
+						if text, ok := content.(*mcp.TextContent); ok {
+							text.Text = t.Message(locale, text.Text)
+						}
+					}
+				}
+			}
+			return result, nil
+		}
+	}
+}
+
+// requestLocale negotiates the locale of a request
+func (t *Translator) requestLocale(ctx context.Context, req mcp.Request, keyLocale func(context.Context, mcp.Request) string) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return t.Negotiate(locale)
+	}
+	var header, acceptLanguage, key string
+	if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+		header = extra.Header.Get(LocaleHeader)
+		acceptLanguage = extra.Header.Get("Accept-Language")
+	}
+	if keyLocale != nil {
+		key = keyLocale(ctx, req)
+	}
+	return t.Negotiate(header, key, acceptLanguage)
+}
+
+// Export writes the catalog of a locale for the given tools, keeping its current translations. Tools
+// without a translation get an empty entry under a comment with the source description; for the source
+// locale every entry holds the source description. Entries of tools that are not registered are kept.
+func (t *Translator) Export(locale string, tools []*mcp.Tool) []byte {
+	existing := Catalog{}
+	if c := t.catalogs[strings.ToLower(locale)]; c != nil {
+		existing = c.Catalog
+		locale = c.Locale
+	}
+	source := strings.EqualFold(locale, SourceLocale)
+
+	sorted := append([]*mcp.Tool(nil), tools...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Translations for %s; generate with: dev-mcp --i18n-export=%s\n", locale, locale)
+	fmt.Fprintf(&b, "locale: %s\n\n", strconv.Quote(locale))
+	b.WriteString("# Tool descriptions shown by tools/list; empty entries fall back to English\n")
+	b.WriteString("tools:\n")
+	registered := map[string]bool{}
+	for _, tool := range sorted {
+		registered[tool.Name] = true
+		translation := existing.Tools[tool.Name]
+		if source {
+			translation = tool.Description
+		} else if translation == "" {
+			fmt.Fprintf(&b, "  # %s\n", strings.Join(strings.Fields(tool.Description), " "))
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", tool.Name, strconv.Quote(translation))
+	}
+	var unregistered []string
+	for name := range existing.Tools {
+		if !registered[name] {
+			unregistered = append(unregistered, name)
+		}
+	}
+	if len(unregistered) > 0 {
+		sort.Strings(unregistered)
+		b.WriteString("  # Not registered by the configuration that generated this file\n")
+		for _, name := range unregistered {
+			fmt.Fprintf(&b, "  %s: %s\n", name, strconv.Quote(existing.Tools[name]))
+		}
+	}
+
+	b.WriteString("\n# Phrases replaced in the text of error results\n")
+	if len(existing.Messages) == 0 {
+		b.WriteString("messages: {}\n")
+		return []byte(b.String())
+	}
+	b.WriteString("messages:\n")
+	phrases := make([]string, 0, len(existing.Messages))
+	for phrase := range existing.Messages {
+		phrases = append(phrases, phrase)
+	}
+	sort.Strings(phrases)
+	for _, phrase := range phrases {
+		fmt.Fprintf(&b, "  %s: %s\n", strconv.Quote(phrase), strconv.Quote(existing.Messages[phrase]))
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,240 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v2"
+)
+
+// PromptDefinition represents a prompt with its metadata and handler
+type PromptDefinition struct {
+	Prompt   *mcp.Prompt
+	Handler  mcp.PromptHandler
+	Requires []string // Providers the prompt's instructions rely on
+}
+
+// Argument is a prompt argument as declared in a prompt file
+type Argument struct {
+	Name        string   `yaml:"name"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Required    bool     `yaml:"required"`
+	Default     string   `yaml:"default"` // Used when an optional argument is not given
+	Enum        []string `yaml:"enum"`    // Allowed values, when set
+	Pattern     string   `yaml:"pattern"` // Regular expression the whole value must match, when set
+}
+
+// Message is one message of a prompt; content is a Go text/template over the arguments
+type Message struct {
+	Role    string `yaml:"role"` // user (default) or assistant
+	Content string `yaml:"content"`
+}
+
+// File is a prompt file in the prompts directory
+type File struct {
+	Name        string     `yaml:"name"`
+	Title       string     `yaml:"title"`
+	Description string     `yaml:"description"`
+	Requires    []string   `yaml:"requires"` // Provider names; the prompt is only offered when they all run
+	Arguments   []Argument `yaml:"arguments"`
+	Messages    []Message  `yaml:"messages"`
+}
+
+// prompt is a validated prompt file with its parsed templates
+type prompt struct {
+	file      File
+	patterns  map[string]*regexp.Regexp
+	templates []*template.Template
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// argumentPattern also lets arguments be referenced as {{.name}} in templates
+var argumentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadPrompts reads every *.yaml and *.yml file in dir as one prompt. A missing directory yields no prompts.
+func LoadPrompts(dir string) ([]PromptDefinition, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read prompts directory: %w", err)
+	}
+
+	var definitions []PromptDefinition
+	seen := map[string]string{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		p, err := loadPrompt(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if other, ok := seen[p.file.Name]; ok {
+			return nil, fmt.Errorf("%s: prompt %s is already defined in %s", path, p.file.Name, other)
+		}
+		seen[p.file.Name] = path
+		definitions = append(definitions, p.definition())
+	}
+
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Prompt.Name < definitions[j].Prompt.Name })
+	return definitions, nil
+}
+
+// loadPrompt parses and validates a prompt file
+func loadPrompt(path string) (*prompt, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if !namePattern.MatchString(file.Name) {
+		return nil, fmt.Errorf("invalid prompt name %q", file.Name)
+	}
+	if len(file.Messages) == 0 {
+		return nil, fmt.Errorf("prompt %s has no messages", file.Name)
+	}
+
+	p := &prompt{file: file, patterns: map[string]*regexp.Regexp{}}
+	sample := map[string]string{}
+	for _, arg := range file.Arguments {
+		if !argumentPattern.MatchString(arg.Name) {
+			return nil, fmt.Errorf("invalid argument name %q", arg.Name)
+		}
+		if _, ok := sample[arg.Name]; ok {
+			return nil, fmt.Errorf("argument %s is declared twice", arg.Name)
+		}
+		if arg.Pattern != "" {
+			re, err := regexp.Compile("^(?:" + arg.Pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("argument %s: invalid pattern: %w", arg.Name, err)
+			}
+			p.patterns[arg.Name] = re
+		}
+		if arg.Default != "" {
+			if err := p.check(arg, arg.Default); err != nil {
+				return nil, fmt.Errorf("argument %s: default %w", arg.Name, err)
+			}
+		}
+		sample[arg.Name] = arg.Default
+	}
+
+	for i, message := range file.Messages {
+		switch message.Role {
+		case "":
+			p.file.Messages[i].Role = "user"
+		case "user", "assistant":
+		default:
+			return nil, fmt.Errorf("message %d: role must be user or assistant", i+1)
+		}
+		tmpl, err := template.New(fmt.Sprintf("%s#%d", file.Name, i+1)).Option("missingkey=error").Parse(message.Content)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		// Rendering with defaults catches references to undeclared arguments now rather than on first use
+		if err := tmpl.Execute(&strings.Builder{}, sample); err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		p.templates = append(p.templates, tmpl)
+	}
+
+	return p, nil
+}
+
+// check validates an argument value against its enum and pattern
+func (p *prompt) check(arg Argument, value string) error {
+	if len(arg.Enum) > 0 && !slices.Contains(arg.Enum, value) {
+		return fmt.Errorf("must be one of %s", strings.Join(arg.Enum, ", "))
+	}
+	if re, ok := p.patterns[arg.Name]; ok && !re.MatchString(value) {
+		return fmt.Errorf("must match %s", arg.Pattern)
+	}
+	return nil
+}
+
+// definition converts the prompt for registration with the MCP server
+func (p *prompt) definition() PromptDefinition {
+	mcpPrompt := &mcp.Prompt{
+		Name:        p.file.Name,
+		Title:       p.file.Title,
+		Description: p.file.Description,
+	}
+	for _, arg := range p.file.Arguments {
+		description := arg.Description
+		if len(arg.Enum) > 0 {
+			description = strings.TrimSpace(fmt.Sprintf("%s (one of: %s)", description, strings.Join(arg.Enum, ", ")))
+		}
+		if arg.Default != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s (default: %s)", description, arg.Default))
+		}
+		mcpPrompt.Arguments = append(mcpPrompt.Arguments, &mcp.PromptArgument{
+			Name:        arg.Name,
+			Title:       arg.Title,
+			Description: description,
+			Required:    arg.Required,
+		})
+	}
+
+	handler := func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return p.render(req.Params.Arguments)
+	}
+
+	return PromptDefinition{Prompt: mcpPrompt, Handler: handler, Requires: p.file.Requires}
+}
+
+// render checks the arguments and executes the message templates
+func (p *prompt) render(args map[string]string) (*mcp.GetPromptResult, error) {
+	values := map[string]string{}
+	for _, arg := range p.file.Arguments {
+		value, ok := args[arg.Name]
+		if !ok || value == "" {
+			if arg.Required {
+				return nil, fmt.Errorf("argument %s is required", arg.Name)
+			}
+			values[arg.Name] = arg.Default
+			continue
+		}
+		if err := p.check(arg, value); err != nil {
+			return nil, fmt.Errorf("argument %s %w", arg.Name, err)
+		}
+		values[arg.Name] = value
+	}
+	for name := range args {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("unknown argument %s", name)
+		}
+	}
+
+	result := &mcp.GetPromptResult{Description: p.file.Description}
+	for i, tmpl := range p.templates {
+		var text strings.Builder
+		if err := tmpl.Execute(&text, values); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", p.file.Name, err)
+		}
+		result.Messages = append(result.Messages, &mcp.PromptMessage{
+			Role:    mcp.Role(p.file.Messages[i].Role),
+			Content: &mcp.TextContent{Text: strings.TrimSpace(text.String())},
+		})
+	}
+	return result, nil
+}
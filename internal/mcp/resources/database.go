@@ -0,0 +1,248 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/provider/database"
+)
+
+const (
+	// exactCountMaxRows is the largest estimated size for which a table's rows are counted exactly
+	exactCountMaxRows = 100000
+	// countTimeout bounds an exact row count
+	countTimeout = 5 * time.Second
+)
+
+// ResourceTemplateDefinition represents a resource template with its metadata and handler
+type ResourceTemplateDefinition struct {
+	ResourceTemplate *mcp.ResourceTemplate
+	Handler          mcp.ResourceHandler
+}
+
+// getDatabaseResources returns the table list of the default database connection
+func getDatabaseResources(registry *database.Registry) []ResourceDefinition {
+	resource := &mcp.Resource{
+		URI:         "db://tables",
+		Name:        "Database Tables",
+		Description: fmt.Sprintf("Tables and views of the default database connection (%s) with estimated row counts; read db://tables/{table} for a table's schema", registry.Default()),
+		MIMEType:    "application/json",
+	}
+
+	return []ResourceDefinition{{
+		Resource: resource,
+		Handler:  createTablesHandler(registry),
+	}}
+}
+
+// GetDatabaseResourceTemplates returns the resource templates for browsing database tables
+func GetDatabaseResourceTemplates(registry *database.Registry) []ResourceTemplateDefinition {
+	tablesHandler := createTablesHandler(registry)
+	tableHandler := createTableHandler(registry)
+
+	return []ResourceTemplateDefinition{
+		{
+			ResourceTemplate: &mcp.ResourceTemplate{
+				URITemplate: "db://tables/{table}",
+				Name:        "Database Table",
+				Description: "Schema of a table of the default connection (columns, keys, foreign keys, indexes) with a row count summary. Use schema.table for a table outside the current schema",
+				MIMEType:    "application/json",
+			},
+			Handler: tableHandler,
+		},
+		{
+			ResourceTemplate: &mcp.ResourceTemplate{
+				URITemplate: "db://connections/{connection}/tables",
+				Name:        "Connection Tables",
+				Description: "Tables and views of a named database connection with estimated row counts",
+				MIMEType:    "application/json",
+			},
+			Handler: tablesHandler,
+		},
+		{
+			ResourceTemplate: &mcp.ResourceTemplate{
+				URITemplate: "db://connections/{connection}/tables/{table}",
+				Name:        "Connection Table",
+				Description: "Schema of a table of a named database connection with a row count summary",
+				MIMEType:    "application/json",
+			},
+			Handler: tableHandler,
+		},
+	}
+}
+
+// tableURI is a parsed db:// URI; table is empty for a table list
+type tableURI struct {
+	connection string
+	schema     string
+	table      string
+}
+
+// parseTableURI parses db://tables[/{table}] and db://connections/{connection}/tables[/{table}]
+func parseTableURI(uri string) (*tableURI, bool) {
+	rest, ok := strings.CutPrefix(uri, "db://")
+	if !ok {
+		return nil, false
+	}
+	parts := strings.Split(rest, "/")
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil || unescaped == "" {
+			return nil, false
+		}
+		parts[i] = unescaped
+	}
+
+	result := &tableURI{}
+	if parts[0] == "connections" {
+		if len(parts) < 3 {
+			return nil, false
+		}
+		result.connection = parts[1]
+		parts = parts[2:]
+	}
+	if parts[0] != "tables" || len(parts) > 2 {
+		return nil, false
+	}
+	if len(parts) == 2 {
+		result.table = parts[1]
+		if schema, table, ok := strings.Cut(parts[1], "."); ok {
+			result.schema, result.table = schema, table
+		}
+	}
+	return result, true
+}
+
+// DatabaseToolCall returns the tool, and its arguments, whose callers may read a db:// URI: database_list_tables
+// for a table list and database_describe_table for a table
+func DatabaseToolCall(registry *database.Registry, uri string) (string, map[string]string, bool) {
+	target, ok := parseTableURI(uri)
+	if !ok {
+		return "", nil, false
+	}
+	arguments := map[string]string{"connection": target.connection}
+	if target.connection == "" {
+		arguments["connection"] = registry.Default()
+	}
+	if target.schema != "" {
+		arguments["schema"] = target.schema
+	}
+	if target.table == "" {
+		return "database_list_tables", arguments, true
+	}
+	arguments["table"] = target.table
+	return "database_describe_table", arguments, true
+}
+
+// createTablesHandler creates the handler listing a connection's tables
+func createTablesHandler(registry *database.Registry) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		target, ok := parseTableURI(req.Params.URI)
+		if !ok || target.table != "" {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		client, err := registry.Get(target.connection)
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+
+		tables, err := client.ListTables(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		connection := target.connection
+		if connection == "" {
+			connection = registry.Default()
+		}
+		result := map[string]interface{}{
+			"connection": connection,
+			"tables":     tables,
+			"count":      len(tables),
+		}
+		return jsonResourceResult(req.Params.URI, result)
+	}
+}
+
+// createTableHandler creates the handler describing one table
+func createTableHandler(registry *database.Registry) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		target, ok := parseTableURI(req.Params.URI)
+		if !ok || target.table == "" {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		client, err := registry.Get(target.connection)
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+
+		// The table list confirms the table exists and carries its type and estimated size
+		tables, err := client.ListTables(ctx, target.schema)
+		if err != nil {
+			return nil, err
+		}
+		var info *database.TableInfo
+		for i := range tables {
+			if tables[i].Name == target.table {
+				info = &tables[i]
+				break
+			}
+		}
+		if info == nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+
+		schema, err := client.DescribeTable(ctx, target.schema, target.table)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := map[string]interface{}{"estimated": info.EstimatedRows}
+		if !strings.Contains(strings.ToUpper(info.Type), "VIEW") && info.EstimatedRows <= exactCountMaxRows {
+			countCtx, cancel := context.WithTimeout(ctx, countTimeout)
+			count, err := client.CountRows(countCtx, target.schema, target.table)
+			cancel()
+			if err != nil {
+				rows["count_error"] = err.Error()
+			} else {
+				rows["exact"] = count
+			}
+		}
+
+		connection := target.connection
+		if connection == "" {
+			connection = registry.Default()
+		}
+		result := map[string]interface{}{
+			"connection": connection,
+			"type":       info.Type,
+			"schema":     schema,
+			"rows":       rows,
+		}
+		return jsonResourceResult(req.Params.URI, result)
+	}
+}
+
+// jsonResourceResult returns data as the JSON contents of a resource
+func jsonResourceResult(uri string, data interface{}) (*mcp.ReadResourceResult, error) {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
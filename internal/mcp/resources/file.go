@@ -0,0 +1,143 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/provider/file"
+)
+
+// fileListingLimit bounds the files of a directory listing
+const fileListingLimit = 1000
+
+// GetFileResourceTemplates returns the resource template for reading files and listing directories
+// through the file provider's checks
+func GetFileResourceTemplates(provider *file.FileProvider) []ResourceTemplateDefinition {
+	return []ResourceTemplateDefinition{{
+		ResourceTemplate: &mcp.ResourceTemplate{
+			URITemplate: "file:///{+path}",
+			Name:        "File",
+			Description: "A file under the working directory, read with the checks of file_read (whitelist, directory profiles, size limit), as text or a base64 blob. " +
+				"A directory lists the files below it. Subscribe to a file or directory to get resources/updated notifications when it changes, such as build output or a log file",
+		},
+		Handler: func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return readFileResource(provider, req.Params.URI)
+		},
+	}}
+}
+
+// FilePath turns a file:///absolute/path URI into the path relative to the working directory the file
+// provider validates
+func FilePath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || (u.Host != "" && u.Host != "localhost") || u.Path == "" {
+		return "", false
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(wd, filepath.FromSlash(u.Path))
+	if err != nil {
+		return "", false
+	}
+	return rel, true
+}
+
+// FileURI builds the file:// URI of a path relative to the working directory
+func FileURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+// readFileResource reads a file, or lists the files under a directory
+func readFileResource(provider *file.FileProvider, uri string) (*mcp.ReadResourceResult, error) {
+	path, ok := FilePath(uri)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	info, err := provider.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		files, truncated, err := provider.ListFiles(path, fileListingLimit)
+		if err != nil {
+			return nil, err
+		}
+		type entry struct {
+			URI  string `json:"uri"`
+			Path string `json:"path"`
+		}
+		entries := make([]entry, 0, len(files))
+		for _, name := range files {
+			entries = append(entries, entry{URI: FileURI(name), Path: name})
+		}
+		result := map[string]interface{}{
+			"path":      path,
+			"files":     entries,
+			"truncated": truncated,
+		}
+		return jsonResourceResult(uri, result)
+	}
+
+	content, err := provider.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mimeType := objectMIMEType(path, "")
+	if mimeType == "application/octet-stream" && strings.HasPrefix(http.DetectContentType(content), "text/") {
+		mimeType = "text/plain"
+	}
+	contents := &mcp.ResourceContents{URI: uri, MIMEType: mimeType}
+	if isTextType(mimeType) && utf8.Valid(content) {
+		contents.Text = string(content)
+	} else {
+		contents.Blob = content
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+}
+
+// FileFingerprint summarises the state of a file, or of the files under a directory, so that a change of
+// content, size or modification time changes it. maxEntries bounds the files compared under a directory.
+func FileFingerprint(provider *file.FileProvider, path string, maxEntries int) (string, error) {
+	info, err := provider.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "missing", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano()), nil
+	}
+
+	files, _, err := provider.ListFiles(path, maxEntries)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, name := range files {
+		if info, err := os.Stat(name); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d\n", name, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String(), nil
+}
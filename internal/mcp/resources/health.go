@@ -0,0 +1,28 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/health"
+)
+
+// HealthDependenciesURI is the resource summarizing the health of every external dependency
+const HealthDependenciesURI = "health://dependencies"
+
+// GetHealthResources returns the dependency health resource served from the monitor's last snapshot
+func GetHealthResources(monitor *health.Monitor) []ResourceDefinition {
+	return []ResourceDefinition{{
+		Resource: &mcp.Resource{
+			URI:  HealthDependenciesURI,
+			Name: "Dependency Health",
+			Description: "Reachability and latency of every external dependency (databases, Loki, Grafana, Sentry, S3, Kafka, MongoDB, Elasticsearch, queues, LLM APIs and the endpoints in health.endpoints), " +
+				"refreshed periodically. status is ok or degraded, with the slow and down dependencies in summary",
+			MIMEType: "application/json",
+		},
+		Handler: func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return jsonResourceResult(req.Params.URI, monitor.Snapshot(ctx))
+		},
+	}}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"dev-mcp/internal/provider/database"
 	"dev-mcp/internal/provider/loki"
 	"dev-mcp/internal/provider/s3"
 )
@@ -19,9 +20,16 @@ type ResourceDefinition struct {
 }
 
 // GetAllResources collects all resources from different managers
-func GetAllResources(ctx context.Context, db interface{}, lokiClient *loki.Client, s3Client *s3.S3Client) []ResourceDefinition {
+func GetAllResources(ctx context.Context, dbRegistry *database.Registry, lokiClient *loki.Client, s3Client *s3.S3Client) []ResourceDefinition {
 	var allResources []ResourceDefinition
 
+	// Add database resources
+	if dbRegistry != nil {
+		dbResources := getDatabaseResources(dbRegistry)
+		allResources = append(allResources, dbResources...)
+		log.Printf("Added %d database resources", len(dbResources))
+	}
+
 	// Add Loki resources
 	if lokiClient != nil {
 		lokiResources := getLokiResources(ctx, lokiClient)
@@ -40,7 +48,6 @@ func GetAllResources(ctx context.Context, db interface{}, lokiClient *loki.Clien
 	return allResources
 }
 
-
 // getLokiResources returns Loki log stream resources
 func getLokiResources(ctx context.Context, client *loki.Client) []ResourceDefinition {
 	var resources []ResourceDefinition
@@ -97,46 +104,3 @@ func createStreamHandler(label string) mcp.ResourceHandler {
 		}, nil
 	}
 }
-
-// getS3Resources returns S3 bucket resources
-func getS3Resources(ctx context.Context, client *s3.S3Client) []ResourceDefinition {
-	// Create a resource for S3 data access
-	resource := &mcp.Resource{
-		URI:         "s3://buckets/data",
-		Name:        "S3 Data Access",
-		Description: "Access JSON data from S3 buckets and objects",
-		MIMEType:    "application/json",
-	}
-
-	handler := func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
-		result := map[string]interface{}{
-			"uri":         req.Params.URI,
-			"description": "S3 Data Access Resource",
-			"usage":       "Use s3_query tool to retrieve specific JSON data from S3 URLs or bucket/key combinations",
-			"examples": []string{
-				"s3://bucket-name/path/to/file.json",
-				"bucket: my-bucket, key: data/file.json",
-			},
-		}
-
-		jsonData, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal S3 data: %w", err)
-		}
-
-		return &mcp.ReadResourceResult{
-			Contents: []*mcp.ResourceContents{
-				{
-					URI:      req.Params.URI,
-					MIMEType: "application/json",
-					Text:     string(jsonData),
-				},
-			},
-		}, nil
-	}
-
-	return []ResourceDefinition{{
-		Resource: resource,
-		Handler:  handler,
-	}}
-}
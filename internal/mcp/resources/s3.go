@@ -0,0 +1,301 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/provider/s3"
+)
+
+const (
+	// defaultS3ResourceMaxBytes is the largest object read as a resource unless s3.resources.max_bytes is set
+	defaultS3ResourceMaxBytes = 1 << 20
+	// s3ListingLimit bounds the entries of a prefix listing
+	s3ListingLimit = 1000
+)
+
+// defaultS3ResourceExtensions are the extensions readable as resources unless s3.resources.extensions is set
+var defaultS3ResourceExtensions = []string{
+	".json", ".txt", ".md", ".csv", ".tsv", ".yaml", ".yml", ".xml", ".log", ".html", ".sql",
+	".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", ".pdf",
+}
+
+// extensionTypes covers extensions mime.TypeByExtension may not know
+var extensionTypes = map[string]string{
+	".md":   "text/markdown",
+	".csv":  "text/csv",
+	".tsv":  "text/tab-separated-values",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".log":  "text/plain",
+	".sql":  "application/sql",
+}
+
+// s3Location is a bucket prefix published as resources
+type s3Location struct {
+	bucket string
+	prefix string // Empty for the whole bucket, else ends in "/"
+}
+
+// s3Resources serves objects under the published locations
+type s3Resources struct {
+	client     *s3.S3Client
+	locations  []s3Location
+	extensions map[string]bool
+	maxBytes   int64
+}
+
+// newS3Resources reads the published locations and limits from s3.resources; it returns nil when
+// there is nothing to publish
+func newS3Resources(client *s3.S3Client) *s3Resources {
+	cfg := client.ResourcesConfig()
+	r := &s3Resources{
+		client:     client,
+		extensions: map[string]bool{},
+		maxBytes:   cfg.MaxBytes,
+	}
+	if r.maxBytes <= 0 {
+		r.maxBytes = defaultS3ResourceMaxBytes
+	}
+
+	extensions := cfg.Extensions
+	if len(extensions) == 0 {
+		extensions = defaultS3ResourceExtensions
+	}
+	for _, ext := range extensions {
+		r.extensions["."+strings.TrimPrefix(strings.ToLower(ext), ".")] = true
+	}
+
+	prefixes := cfg.Prefixes
+	if len(prefixes) == 0 && client.DefaultBucket() != "" {
+		prefixes = []string{client.DefaultBucket()}
+	}
+	for _, entry := range prefixes {
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(entry, "s3://"), "/")
+		if bucket == "" {
+			continue
+		}
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		r.locations = append(r.locations, s3Location{bucket: bucket, prefix: prefix})
+	}
+	if len(r.locations) == 0 {
+		return nil
+	}
+	return r
+}
+
+// getS3Resources returns a listing resource for each published bucket prefix
+func getS3Resources(ctx context.Context, client *s3.S3Client) []ResourceDefinition {
+	r := newS3Resources(client)
+	if r == nil {
+		return nil
+	}
+
+	var resources []ResourceDefinition
+	for _, location := range r.locations {
+		resources = append(resources, ResourceDefinition{
+			Resource: &mcp.Resource{
+				URI:         s3URI(location.bucket, location.prefix),
+				Name:        fmt.Sprintf("S3: %s/%s", location.bucket, location.prefix),
+				Description: fmt.Sprintf("Objects and sub-prefixes under s3://%s/%s; read an object with its s3://{bucket}/{key} URI", location.bucket, location.prefix),
+				MIMEType:    "application/json",
+			},
+			Handler: r.handle,
+		})
+	}
+	return resources
+}
+
+// GetS3ResourceTemplates returns the resource template for reading S3 objects and listing prefixes
+func GetS3ResourceTemplates(client *s3.S3Client) []ResourceTemplateDefinition {
+	r := newS3Resources(client)
+	if r == nil {
+		return nil
+	}
+
+	return []ResourceTemplateDefinition{{
+		ResourceTemplate: &mcp.ResourceTemplate{
+			URITemplate: "s3://{bucket}/{+key}",
+			Name:        "S3 Object",
+			Description: fmt.Sprintf("An S3 object under a published prefix (%s), up to %d bytes, as text or a base64 blob. A key ending in / lists the objects and sub-prefixes below it",
+				r.describeLocations(), r.maxBytes),
+		},
+		Handler: r.handle,
+	}}
+}
+
+// describeLocations lists the published locations for descriptions
+func (r *s3Resources) describeLocations() string {
+	names := make([]string, 0, len(r.locations))
+	for _, location := range r.locations {
+		names = append(names, location.bucket+"/"+location.prefix)
+	}
+	return strings.Join(names, ", ")
+}
+
+// published reports whether a key lies under a published location
+func (r *s3Resources) published(bucket, key string) bool {
+	for _, location := range r.locations {
+		if location.bucket == bucket && strings.HasPrefix(key, location.prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// s3URI builds the resource URI of a key, escaping each path segment
+func s3URI(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "s3://" + bucket + "/" + strings.Join(segments, "/")
+}
+
+// parseS3URI splits s3://{bucket}/{key} into its bucket and unescaped key
+func parseS3URI(uri string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", "", false
+	}
+	bucket, escaped, _ := strings.Cut(rest, "/")
+	key, err := url.PathUnescape(escaped)
+	if bucket == "" || err != nil {
+		return "", "", false
+	}
+	return bucket, key, true
+}
+
+// S3ToolCall returns the tool, and its arguments, whose callers may read an s3:// URI: s3_get_object for an
+// object and s3_list_objects for a prefix listing
+func S3ToolCall(uri string) (string, map[string]string, bool) {
+	bucket, key, ok := parseS3URI(uri)
+	if !ok {
+		return "", nil, false
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return "s3_list_objects", map[string]string{"bucket": bucket, "prefix": key}, true
+	}
+	return "s3_get_object", map[string]string{"bucket": bucket, "key": key}, true
+}
+
+// handle reads an object, or lists a prefix when the key is empty or ends in "/"
+func (r *s3Resources) handle(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	bucket, key, ok := parseS3URI(req.Params.URI)
+	if !ok || !r.published(bucket, key) {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return r.list(ctx, req.Params.URI, bucket, key)
+	}
+
+	ext := strings.ToLower(path.Ext(key))
+	if !r.extensions[ext] {
+		return nil, fmt.Errorf("objects with extension %q are not readable as resources (see s3.resources.extensions); use the s3 tools instead", ext)
+	}
+
+	content, err := r.client.ReadObject(ctx, bucket, key, r.maxBytes)
+	if errors.Is(err, s3.ErrObjectNotFound) {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", bucket, key, err)
+	}
+
+	mimeType := objectMIMEType(key, content.ContentType)
+	contents := &mcp.ResourceContents{URI: req.Params.URI, MIMEType: mimeType}
+	if isTextType(mimeType) && utf8.Valid(content.Body) {
+		contents.Text = string(content.Body)
+	} else {
+		contents.Blob = content.Body
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+}
+
+// list returns the objects and sub-prefixes directly under a prefix with their resource URIs
+func (r *s3Resources) list(ctx context.Context, uri, bucket, prefix string) (*mcp.ReadResourceResult, error) {
+	dir, err := r.client.ListDirectory(ctx, bucket, prefix, s3ListingLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list s3://%s/%s: %w", bucket, prefix, err)
+	}
+
+	type object struct {
+		URI          string     `json:"uri"`
+		Key          string     `json:"key"`
+		Size         int64      `json:"size"`
+		LastModified *time.Time `json:"last_modified,omitempty"`
+		MIMEType     string     `json:"mime_type"`
+		Readable     bool       `json:"readable"` // Within the extension and size limits
+	}
+	type subPrefix struct {
+		URI    string `json:"uri"`
+		Prefix string `json:"prefix"`
+	}
+
+	objects := []object{}
+	for _, entry := range dir.Objects {
+		objects = append(objects, object{
+			URI:          s3URI(bucket, entry.Key),
+			Key:          entry.Key,
+			Size:         entry.Size,
+			LastModified: entry.LastModified,
+			MIMEType:     objectMIMEType(entry.Key, ""),
+			Readable:     r.extensions[strings.ToLower(path.Ext(entry.Key))] && entry.Size <= r.maxBytes,
+		})
+	}
+	prefixes := []subPrefix{}
+	for _, p := range dir.Prefixes {
+		prefixes = append(prefixes, subPrefix{URI: s3URI(bucket, p), Prefix: p})
+	}
+
+	result := map[string]interface{}{
+		"bucket":    bucket,
+		"prefix":    prefix,
+		"objects":   objects,
+		"prefixes":  prefixes,
+		"truncated": dir.Truncated,
+	}
+	return jsonResourceResult(uri, result)
+}
+
+// objectMIMEType prefers the stored content type unless it is generic, then guesses from the extension
+func objectMIMEType(key, stored string) string {
+	if stored != "" && stored != "application/octet-stream" && stored != "binary/octet-stream" {
+		if mediaType, _, err := mime.ParseMediaType(stored); err == nil {
+			return mediaType
+		}
+	}
+	ext := strings.ToLower(path.Ext(key))
+	if mimeType, ok := extensionTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+			return mediaType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// isTextType reports whether contents of a MIME type are returned as text
+func isTextType(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") || strings.HasSuffix(mimeType, "+json") || strings.HasSuffix(mimeType, "+xml") {
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/yaml", "application/sql", "application/javascript":
+		return true
+	}
+	return false
+}
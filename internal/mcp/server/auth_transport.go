@@ -2,9 +2,12 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -12,103 +15,143 @@ import (
 	"dev-mcp/internal/logging"
 )
 
-// AuthenticatedSSETransport wraps SSE transport with authentication
+// maxSSEMessageBytes bounds a single JSON-RPC message POSTed to a session endpoint
+const maxSSEMessageBytes = 4 << 20
+
+// AuthenticatedSSETransport serves the HTTP+SSE transport (MCP 2024-11-05) for authenticated clients.
+// A GET opens a session: the response is an event stream that first announces the session's message
+// endpoint and then carries every server message. The client POSTs its JSON-RPC messages to that endpoint.
+// Requests must already carry an auth result (see auth.Middleware.HTTPMiddleware); a session may only
+// be written to by the principal that opened it.
 type AuthenticatedSSETransport struct {
 	authMiddleware *auth.Middleware
-	port           int
+	server         *mcp.Server
+
+	mu       sync.Mutex
+	sessions map[string]*sseSession
+}
+
+// sseSession is one open event stream and the principal that owns it
+type sseSession struct {
+	transport *mcp.SSEServerTransport
+	owner     string
 }
 
-// NewAuthenticatedSSETransport creates a new authenticated SSE transport
-func NewAuthenticatedSSETransport(authConfig *auth.AuthConfig, port int) *AuthenticatedSSETransport {
+// NewAuthenticatedSSETransport creates a new authenticated SSE transport bridging to server
+func NewAuthenticatedSSETransport(authMiddleware *auth.Middleware, server *mcp.Server) *AuthenticatedSSETransport {
 	return &AuthenticatedSSETransport{
-		authMiddleware: auth.NewMiddleware(authConfig),
-		port:           port,
+		authMiddleware: authMiddleware,
+		server:         server,
+		sessions:       make(map[string]*sseSession),
+	}
+}
+
+// ServeHTTP opens a session on GET and delivers messages to an open session on POST ?sessionid=
+func (t *AuthenticatedSSETransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authResult, ok := auth.GetAuthResult(r.Context())
+	if !ok || authResult == nil {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		t.serveStream(w, r, authResult)
+	case http.MethodPost:
+		t.serveMessage(w, r, authResult)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-// Start starts the authenticated SSE server
-func (t *AuthenticatedSSETransport) Start(ctx context.Context, server *mcp.Server) error {
+// serveStream runs a session for the lifetime of the GET request
+func (t *AuthenticatedSSETransport) serveStream(w http.ResponseWriter, r *http.Request, authResult *auth.AuthResult) {
 	logger := logging.New("SSE")
-	logger.Info("starting authenticated SSE transport", logging.String("port", fmt.Sprintf("%d", t.port)))
-
-	// Create HTTP server with authentication
-	mux := http.NewServeMux()
-
-	// Add the MCP endpoint with authentication
-	mux.HandleFunc("/mcp", t.authMiddleware.HTTPMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		// Get auth result from context
-		authResult, ok := auth.GetAuthResult(r.Context())
-		if !ok {
-			http.Error(w, "Authentication required", http.StatusUnauthorized)
-			return
-		}
-
-		logger.Info("authenticated request",
-			logging.String("user", authResult.Username),
-			logging.String("roles", strings.Join(authResult.Roles, ",")))
-
-		// Handle SSE connection here
-		// For now, we'll create a basic SSE response
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Headers", "Authorization")
-
-		// Send initial connection message
-		fmt.Fprintf(w, "data: {\"type\":\"connection\",\"status\":\"connected\",\"user\":\"%s\"}\n\n", authResult.Username)
-
-		// Flush to ensure the data is sent immediately
-		if flusher, ok := w.(http.Flusher); ok {
-			flusher.Flush()
-		}
-
-		// Keep connection alive
-		select {
-		case <-ctx.Done():
-			return
-		case <-r.Context().Done():
-			return
-		}
-	}))
-
-	// Add health check endpoint (no auth required)
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, `{"status":"ok","auth_enabled":%t}`, t.authMiddleware.IsEnabled())
-	})
-
-	// Add authentication info endpoint
-	mux.HandleFunc("/auth/info", t.authMiddleware.HTTPMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		authResult, _ := auth.GetAuthResult(r.Context())
-		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, `{"user":"%s","roles":["%s"],"method":"%s"}`,
-			authResult.Username,
-			strings.Join(authResult.Roles, `","`),
-			authResult.Method)
-	}))
-
-	// Create and start HTTP server
-	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", t.port),
-		Handler: mux,
+
+	if _, ok := w.(http.Flusher); !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
 	}
 
-	logger.Info("SSE server started", logging.String("address", httpServer.Addr))
+	sessionID, err := newSessionID()
+	if err != nil {
+		http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		return
+	}
+	endpoint, err := r.URL.Parse("?sessionid=" + sessionID)
+	if err != nil {
+		http.Error(w, "Failed to create session endpoint", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	transport := &mcp.SSEServerTransport{Endpoint: endpoint.RequestURI(), Response: w}
+	t.mu.Lock()
+	t.sessions[sessionID] = &sseSession{transport: transport, owner: authResult.UserID}
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.sessions, sessionID)
+		t.mu.Unlock()
+	}()
 
-	// Start server in background
+	// The session context carries the auth result, so tool calls run as the stream's owner
+	session, err := t.server.Connect(r.Context(), transport, nil)
+	if err != nil {
+		logger.Error("failed to connect SSE session", logging.Error(err))
+		http.Error(w, "Connection failed", http.StatusInternalServerError)
+		return
+	}
+	defer session.Close()
+
+	logger.Info("SSE session opened",
+		logging.String("session", sessionID),
+		logging.String("user", authResult.Username),
+		logging.String("roles", strings.Join(authResult.Roles, ",")))
+
+	ended := make(chan struct{})
 	go func() {
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Error("SSE server error", logging.String("error", err.Error()))
-		}
+		session.Wait()
+		close(ended)
 	}()
+	select {
+	case <-r.Context().Done():
+	case <-ended:
+	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
+	logger.Info("SSE session closed", logging.String("session", sessionID), logging.String("user", authResult.Username))
+}
+
+// serveMessage hands a POSTed JSON-RPC message to its session
+func (t *AuthenticatedSSETransport) serveMessage(w http.ResponseWriter, r *http.Request, authResult *auth.AuthResult) {
+	sessionID := r.URL.Query().Get("sessionid")
+	if sessionID == "" {
+		http.Error(w, "sessionid must be provided", http.StatusBadRequest)
+		return
+	}
 
-	// Graceful shutdown
-	logger.Info("shutting down SSE server")
-	return httpServer.Shutdown(context.Background())
+	t.mu.Lock()
+	session := t.sessions[sessionID]
+	t.mu.Unlock()
+	if session == nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if session.owner != authResult.UserID {
+		logging.New("SSE").Warn("message for another user's session rejected",
+			logging.String("session", sessionID),
+			logging.String("user", authResult.Username))
+		http.Error(w, "Session belongs to another user", http.StatusForbidden)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSSEMessageBytes)
+	session.transport.ServeHTTP(w, r)
 }
 
 // CheckToolAccess validates if the current user can access a specific tool
@@ -120,3 +163,11 @@ func (t *AuthenticatedSSETransport) CheckToolAccess(ctx context.Context, toolNam
 
 	return t.authMiddleware.CheckToolPermission(authResult, toolName)
 }
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+	"dev-mcp/internal/mcp/resources"
+	"dev-mcp/internal/provider/file"
+)
+
+// Defaults of file.watch
+const (
+	defaultWatchInterval   = time.Second
+	defaultWatchMaxPaths   = 100
+	defaultWatchMaxEntries = 10000
+)
+
+// fileWatcher polls the file:// resources clients subscribe to and sends resources/updated to the
+// subscribers of one that changed. There is no file system notification API to rely on everywhere, so
+// files are compared by size and modification time.
+type fileWatcher struct {
+	mu         sync.Mutex
+	server     *mcp.Server
+	provider   *file.FileProvider // nil while the file provider is not running
+	authorize  func(ctx context.Context, req mcp.Request, path string) error
+	interval   time.Duration
+	maxPaths   int
+	maxEntries int
+	watches    map[string]*fileWatch // Resource URI -> watch
+}
+
+// fileWatch is a subscribed path and the sessions subscribed to it
+type fileWatch struct {
+	path        string
+	fingerprint string
+	sessions    map[*mcp.ServerSession]bool
+}
+
+func newFileWatcher(cfg *config.FileWatchConfig) *fileWatcher {
+	w := &fileWatcher{
+		interval:   time.Duration(cfg.PollIntervalMs) * time.Millisecond,
+		maxPaths:   cfg.MaxPaths,
+		maxEntries: cfg.MaxEntries,
+		watches:    map[string]*fileWatch{},
+	}
+	if w.interval <= 0 {
+		w.interval = defaultWatchInterval
+	}
+	if w.maxPaths <= 0 {
+		w.maxPaths = defaultWatchMaxPaths
+	}
+	if w.maxEntries <= 0 {
+		w.maxEntries = defaultWatchMaxEntries
+	}
+	return w
+}
+
+// setProvider starts accepting subscriptions to the files of a running file provider
+func (w *fileWatcher) setProvider(provider *file.FileProvider) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.provider = provider
+}
+
+// subscribe is the SubscribeHandler of the server: it checks that the caller may read the file and starts
+// watching it
+func (w *fileWatcher) subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	path, ok := resources.FilePath(uri)
+	if !ok {
+		return fmt.Errorf("only file:// resources can be subscribed to, not %s", uri)
+	}
+	w.mu.Lock()
+	provider := w.provider
+	w.mu.Unlock()
+	if provider == nil {
+		return fmt.Errorf("the file provider is not running")
+	}
+	if w.authorize != nil {
+		if err := w.authorize(ctx, req, path); err != nil {
+			return err
+		}
+	}
+	// Reading the state also validates the path
+	fingerprint, err := resources.FileFingerprint(provider, path, w.maxEntries)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	watch, ok := w.watches[uri]
+	if !ok {
+		if len(w.watches) >= w.maxPaths {
+			return fmt.Errorf("too many watched paths (file.watch.max_paths is %d)", w.maxPaths)
+		}
+		watch = &fileWatch{path: path, fingerprint: fingerprint, sessions: map[*mcp.ServerSession]bool{}}
+		w.watches[uri] = watch
+	}
+	watch.sessions[req.Session] = true
+	logging.ServerLogger.Info("watching file", logging.String("path", path), logging.Int("subscribers", len(watch.sessions)))
+	return nil
+}
+
+// unsubscribe is the UnsubscribeHandler of the server; a path nobody is subscribed to is no longer polled
+func (w *fileWatcher) unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if watch, ok := w.watches[req.Params.URI]; ok {
+		delete(watch.sessions, req.Session)
+		if len(watch.sessions) == 0 {
+			delete(w.watches, req.Params.URI)
+		}
+	}
+	return nil
+}
+
+// Run polls the watched paths every interval until ctx is done
+func (w *fileWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		w.poll(ctx)
+	}
+}
+
+// poll drops the subscriptions of closed sessions, then notifies the subscribers of each path that changed
+func (w *fileWatcher) poll(ctx context.Context) {
+	live := map[*mcp.ServerSession]bool{}
+	for session := range w.server.Sessions() {
+		live[session] = true
+	}
+
+	w.mu.Lock()
+	provider := w.provider
+	pending := map[string]string{} // URI -> path
+	for uri, watch := range w.watches {
+		for session := range watch.sessions {
+			if !live[session] {
+				delete(watch.sessions, session)
+			}
+		}
+		if len(watch.sessions) == 0 {
+			delete(w.watches, uri)
+			continue
+		}
+		pending[uri] = watch.path
+	}
+	w.mu.Unlock()
+	if provider == nil {
+		return
+	}
+
+	for uri, path := range pending {
+		fingerprint, err := resources.FileFingerprint(provider, path, w.maxEntries)
+		if err != nil {
+			// A path that became unreadable, e.g. denied by a profile, is a change too
+			fingerprint = "error: " + err.Error()
+		}
+
+		w.mu.Lock()
+		watch, ok := w.watches[uri]
+		changed := ok && watch.fingerprint != fingerprint
+		if changed {
+			watch.fingerprint = fingerprint
+		}
+		w.mu.Unlock()
+
+		if changed {
+			w.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+		}
+	}
+}
+
+// count returns the number of watched paths and of subscriptions to them
+func (w *fileWatcher) count() (paths, subscriptions int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, watch := range w.watches {
+		subscriptions += len(watch.sessions)
+	}
+	return len(w.watches), subscriptions
+}
+
+// authorizeFiles lets the callers of file_read read and subscribe to file:// resources; the stdio user
+// and, without authentication, everyone may
+func (s *MCPServer) authorizeFiles(ctx context.Context, req mcp.Request, path string) error {
+	if s.transport == "stdio" || !s.authConfig.Enabled {
+		return nil
+	}
+	arguments, _ := json.Marshal(map[string]string{"path": path})
+	return s.authMiddleware.AuthorizeAs(ctx, req, "file_read", arguments)
+}
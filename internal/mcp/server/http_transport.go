@@ -0,0 +1,350 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/logging"
+)
+
+const (
+	defaultStreamablePath  = "/mcp"
+	defaultSessionIdle     = 30 * time.Minute
+	defaultShutdownTimeout = 15 * time.Second
+	sessionIDHeader        = "Mcp-Session-Id"
+)
+
+// newStreamableHandler creates the Streamable HTTP handler: Mcp-Session-Id sessions that expire when idle,
+// and an event store so clients can resume a broken stream with Last-Event-ID
+func (s *MCPServer) newStreamableHandler() http.Handler {
+	cfg := s.cfg.Server
+	opts := &mcp.StreamableHTTPOptions{
+		Stateless:      cfg.Stateless,
+		JSONResponse:   cfg.JSONResponse,
+		SessionTimeout: defaultSessionIdle,
+	}
+	if cfg.SessionIdleMinutes > 0 {
+		opts.SessionTimeout = time.Duration(cfg.SessionIdleMinutes) * time.Minute
+	}
+	// Stateless requests have no session to resume
+	if !cfg.Stateless {
+		store := mcp.NewMemoryEventStore(nil)
+		if cfg.ResumeBufferBytes > 0 {
+			store.SetMaxBytes(cfg.ResumeBufferBytes)
+		}
+		opts.EventStore = store
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(func(request *http.Request) *mcp.Server {
+		return s.server
+	}, opts)
+	if cfg.Stateless {
+		// Every stateless request runs with its own credentials
+		return handler
+	}
+	return newSessionOwners(opts.SessionTimeout).middleware(handler.ServeHTTP)
+}
+
+// sessionOwners ties Streamable HTTP sessions to the principal that initialized them. A session's context
+// keeps the auth result of its first request, so without this check any valid key sending another user's
+// Mcp-Session-Id would run tools with that user's roles (the SSE bridge checks its sessions the same way).
+type sessionOwners struct {
+	mu     sync.Mutex
+	idle   time.Duration
+	owners map[string]*sessionOwner
+}
+
+type sessionOwner struct {
+	user     string
+	lastSeen time.Time
+}
+
+func newSessionOwners(idle time.Duration) *sessionOwners {
+	return &sessionOwners{idle: idle, owners: make(map[string]*sessionOwner)}
+}
+
+// middleware records the owner of the session an initialize request creates and rejects requests for a
+// session from any other principal
+func (o *sessionOwners) middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := ""
+		if authResult, ok := auth.GetAuthResult(r.Context()); ok && authResult != nil {
+			user = authResult.UserID
+		}
+
+		sessionID := r.Header.Get(sessionIDHeader)
+		if sessionID == "" {
+			// The session ID is assigned in the response headers, before the client can use it
+			next(&sessionRecorder{ResponseWriter: w, record: func(id string) { o.set(id, user) }}, r)
+			return
+		}
+
+		if owner, known := o.touch(sessionID); known && owner != user {
+			logging.ServerLogger.Warn("request for another user's session rejected",
+				logging.String("session", sessionID),
+				logging.String("user", user))
+			http.Error(w, "Session belongs to another user", http.StatusForbidden)
+			return
+		}
+		recorder := &sessionRecorder{ResponseWriter: w}
+		next(recorder, r)
+		if r.Method == http.MethodDelete || recorder.status == http.StatusNotFound {
+			o.remove(sessionID)
+		}
+	}
+}
+
+// set records the owner of a new session, dropping the owners of sessions idle long enough to have expired
+func (o *sessionOwners) set(sessionID, user string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	for id, owner := range o.owners {
+		if now.Sub(owner.lastSeen) > o.idle {
+			delete(o.owners, id)
+		}
+	}
+	o.owners[sessionID] = &sessionOwner{user: user, lastSeen: now}
+}
+
+// touch returns the owner of a session and marks it as used
+func (o *sessionOwners) touch(sessionID string) (string, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	owner, ok := o.owners[sessionID]
+	if !ok {
+		return "", false
+	}
+	owner.lastSeen = time.Now()
+	return owner.user, true
+}
+
+func (o *sessionOwners) remove(sessionID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.owners, sessionID)
+}
+
+// sessionRecorder notes the status of a response and hands the Mcp-Session-Id it assigns to record
+type sessionRecorder struct {
+	http.ResponseWriter
+	record func(sessionID string)
+	status int
+}
+
+func (r *sessionRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+		if id := r.Header().Get(sessionIDHeader); id != "" && r.record != nil {
+			r.record(id)
+		}
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *sessionRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *sessionRecorder) Flush() {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *sessionRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// serveHTTP serves an HTTP transport until ctx is cancelled, then shuts down gracefully: long-lived GET streams
+// are closed at once (clients reconnect and resume), in-flight requests get the shutdown timeout to finish
+func (s *MCPServer) serveHTTP(ctx context.Context, addr, path string, handler http.Handler) error {
+	logger := logging.ServerLogger
+
+	streams, closeStreams := context.WithCancel(context.Background())
+	defer closeStreams()
+
+	mux := http.NewServeMux()
+	// Authenticate every request; the principal is stored on the session context
+	// and checked per tool by the receiving middleware
+	mux.Handle(path, s.checkOrigin(s.authMiddleware.HTTPMiddleware(endOnShutdown(streams, handler.ServeHTTP))))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"ok","transport":%q,"auth_enabled":%t}`, s.transport, s.authConfig.Enabled)
+	})
+	mux.HandleFunc("/auth/info", s.authMiddleware.HTTPMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		authResult, _ := auth.GetAuthResult(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"user":   authResult.Username,
+			"roles":  authResult.Roles,
+			"method": authResult.Method,
+		})
+	}))
+	mux.HandleFunc("/status", s.authMiddleware.HTTPMiddleware(s.handleStatus))
+	if s.cfg.Server.Pprof {
+		mux.Handle("/debug/pprof/", s.authMiddleware.HTTPMiddleware(s.requireAdmin(pprofHandler())))
+		logger.Warn("pprof endpoint enabled at /debug/pprof/ (admin only)")
+	}
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+	logger.Info("MCP HTTP server started",
+		logging.String("transport", s.transport),
+		logging.String("address", addr),
+		logging.String("path", path))
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := defaultShutdownTimeout
+	if s.cfg.Server.ShutdownTimeoutSeconds > 0 {
+		timeout = time.Duration(s.cfg.Server.ShutdownTimeoutSeconds) * time.Second
+	}
+	logger.Info("shutting down MCP HTTP server", logging.Duration("timeout", timeout))
+	closeStreams()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		httpServer.Close()
+		return fmt.Errorf("graceful shutdown incomplete: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// pprofHandler serves the net/http/pprof index and profiles under /debug/pprof/
+func pprofHandler() http.HandlerFunc {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux.ServeHTTP
+}
+
+// requireAdmin only lets admin callers through. With auth disabled everyone is admin,
+// so requests must then come from the local machine.
+func (s *MCPServer) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authResult, ok := auth.GetAuthResult(r.Context())
+		if !ok || authResult == nil || !authResult.HasRole("admin") {
+			http.Error(w, "Admin role required", http.StatusForbidden)
+			return
+		}
+		if !s.authConfig.Enabled {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil || !isLoopbackHost(host) {
+				http.Error(w, "Enable authentication to use pprof remotely", http.StatusForbidden)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// endOnShutdown ends GET requests (standalone and resumed event streams, which never finish on their own)
+// when streams is cancelled
+func endOnShutdown(streams context.Context, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next(w, r)
+			return
+		}
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(streams, cancel)
+		defer stop()
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// checkOrigin rejects browser requests from other sites (DNS rebinding protection required by the MCP spec).
+// Requests without an Origin header, from localhost or from allowed_origins pass. A rebinding page sends its
+// own name as both Origin and Host, so the Host header must also name the server: a loopback name, the
+// listen address (any IP address when listening on all interfaces) or the host of an allowed origin.
+func (s *MCPServer) checkOrigin(next http.HandlerFunc) http.HandlerFunc {
+	allowed := s.cfg.Server.AllowedOrigins
+	anyOrigin := slices.Contains(allowed, "*")
+	hosts := []string{strings.ToLower(s.host)}
+	for _, origin := range allowed {
+		if u, err := url.Parse(origin); err == nil && u.Hostname() != "" {
+			hosts = append(hosts, strings.ToLower(u.Hostname()))
+		}
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !anyOrigin && !s.isServerHost(r.Host, hosts) {
+			logging.ServerLogger.Warn("request for unknown host rejected", logging.String("host", r.Host))
+			http.Error(w, "Host not allowed", http.StatusForbidden)
+			return
+		}
+		origin := r.Header.Get("Origin")
+		if origin == "" || anyOrigin || slices.Contains(allowed, origin) {
+			next(w, r)
+			return
+		}
+		if u, err := url.Parse(origin); err == nil && isLoopbackHost(u.Hostname()) {
+			next(w, r)
+			return
+		}
+		logging.ServerLogger.Warn("request from disallowed origin rejected", logging.String("origin", origin))
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+	}
+}
+
+// isServerHost reports whether the Host header of a request names this server
+func (s *MCPServer) isServerHost(hostport string, hosts []string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if isLoopbackHost(host) || slices.Contains(hosts, host) {
+		return true
+	}
+	// Listening on all interfaces, the server is reached by any of its addresses; names could be rebound
+	listen := net.ParseIP(s.host)
+	return (s.host == "" || listen != nil && listen.IsUnspecified()) && net.ParseIP(host) != nil
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -1,14 +1,68 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"net/http"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"dev-mcp/internal/audit"
 	"dev-mcp/internal/auth"
 	"dev-mcp/internal/config"
+	"dev-mcp/internal/health"
+	"dev-mcp/internal/hints"
+	"dev-mcp/internal/i18n"
 	"dev-mcp/internal/logging"
+	"dev-mcp/internal/mcp/prompts"
+	"dev-mcp/internal/mcp/resources"
+	"dev-mcp/internal/mock"
+	"dev-mcp/internal/output"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/artifacts"
+	"dev-mcp/internal/provider/aws"
+	"dev-mcp/internal/provider/cache"
+	"dev-mcp/internal/provider/calendar"
+	"dev-mcp/internal/provider/catalog"
+	"dev-mcp/internal/provider/cicd"
+	"dev-mcp/internal/provider/codehost"
+	"dev-mcp/internal/provider/codequality"
+	"dev-mcp/internal/provider/database"
+	"dev-mcp/internal/provider/deploy"
+	"dev-mcp/internal/provider/diagnostics"
+	"dev-mcp/internal/provider/elasticsearch"
+	"dev-mcp/internal/provider/email"
+	"dev-mcp/internal/provider/exec"
+	"dev-mcp/internal/provider/file"
+	"dev-mcp/internal/provider/git"
+	"dev-mcp/internal/provider/grafana"
+	"dev-mcp/internal/provider/incident"
+	"dev-mcp/internal/provider/kafka"
+	"dev-mcp/internal/provider/knowledge"
+	"dev-mcp/internal/provider/kubernetes"
+	"dev-mcp/internal/provider/loki"
+	"dev-mcp/internal/provider/mongodb"
+	"dev-mcp/internal/provider/onboarding"
+	"dev-mcp/internal/provider/oncall"
+	"dev-mcp/internal/provider/profiling"
+	"dev-mcp/internal/provider/proto"
+	"dev-mcp/internal/provider/queue"
+	"dev-mcp/internal/provider/registry"
+	"dev-mcp/internal/provider/s3"
+	"dev-mcp/internal/provider/sbom"
+	"dev-mcp/internal/provider/sentry"
+	"dev-mcp/internal/provider/slo"
+	"dev-mcp/internal/provider/terraform"
+	"dev-mcp/internal/provider/utility"
+	"dev-mcp/internal/store"
 )
 
 // MCPServer represents an MCP server using the official Go SDK
@@ -20,23 +74,67 @@ type MCPServer struct {
 	transport      string
 	host           string
 	port           int
+	providers      *provider.ProviderRegistry
+	audit          *audit.Logger
+	health         *health.Monitor
+	i18n           *i18n.Translator
+	store          *store.Store
+	startedAt      time.Time
+	version        VersionInfo
+	stdioWriter    atomic.Pointer[frameWriter] // Set while serving stdio
+	files          *fileWatcher                // Subscriptions to file:// resources
+	freeze         mcp.Middleware              // Deploy freeze guard of the calendar provider, if running
 }
 
 // NewMCPServer creates a new MCP server using the official SDK
 func NewMCPServer(cfg *config.Config) *MCPServer {
-	// Create MCP server with implementation info
+	// Create MCP server with implementation info; subscriptions are supported for file:// resources
+	files := newFileWatcher(&cfg.File.Watch)
 	server := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "dev-mcp-server",
-			Version: "1.0.0",
+			Version: serverVersion,
+		},
+		&mcp.ServerOptions{
+			SubscribeHandler:   files.subscribe,
+			UnsubscribeHandler: files.unsubscribe,
 		},
-		nil, // No options for now
 	)
 
 	// Convert config.AuthConfig to auth.AuthConfig
 	authConfig := &auth.AuthConfig{
-		Enabled: cfg.Auth.Enabled,
-		APIKeys: make([]auth.APIKey, len(cfg.Auth.APIKeys)),
+		Enabled:         cfg.Auth.Enabled,
+		APIKeys:         make([]auth.APIKey, len(cfg.Auth.APIKeys)),
+		ToolPermissions: cfg.Auth.ToolPermissions,
+		RateLimit: auth.RateLimitConfig{
+			Enabled:           cfg.Auth.RateLimit.Enabled,
+			RequestsPerMinute: cfg.Auth.RateLimit.RequestsPerMinute,
+			ToolQuotas:        cfg.Auth.RateLimit.ToolQuotas,
+		},
+		Policy: auth.PolicyConfig{
+			File:          cfg.Auth.Policy.File,
+			Environment:   cfg.Auth.Policy.Environment,
+			ReloadSeconds: cfg.Auth.Policy.ReloadSeconds,
+		},
+		Impersonation: auth.ImpersonationConfig{
+			Enabled:     cfg.Auth.Impersonation.Enabled,
+			Header:      cfg.Auth.Impersonation.Header,
+			MetaKey:     cfg.Auth.Impersonation.MetaKey,
+			Roles:       cfg.Auth.Impersonation.Roles,
+			Downstreams: make(map[string]auth.DownstreamIdentity, len(cfg.Auth.Impersonation.Downstreams)),
+		},
+	}
+
+	// Convert impersonation downstreams
+	for name, downstream := range cfg.Auth.Impersonation.Downstreams {
+		authConfig.Impersonation.Downstreams[name] = auth.DownstreamIdentity{
+			Header:        downstream.Header,
+			Tokens:        downstream.Tokens,
+			TokenExchange: auth.TokenExchangeConfig(downstream.TokenExchange),
+			AuthHeader:    downstream.AuthHeader,
+			AuthScheme:    downstream.AuthScheme,
+			Required:      downstream.Required,
+		}
 	}
 
 	// Convert API keys
@@ -46,41 +144,614 @@ func NewMCPServer(cfg *config.Config) *MCPServer {
 			Key:     apiKey.Key,
 			Roles:   apiKey.Roles,
 			Enabled: apiKey.Enabled,
+
+			RequestsPerMinute: apiKey.RequestsPerMinute,
+			Locale:            apiKey.Locale,
 		}
 	}
 
 	mcpServer := &MCPServer{
 		server:         server,
 		authConfig:     authConfig,
+		cfg:            cfg,
 		authMiddleware: auth.NewMiddleware(authConfig),
-		transport:      "sse",
+		transport:      cfg.Server.Transport,
 		host:           cfg.Server.Host,
 		port:           cfg.Server.Port,
+		providers:      provider.NewProviderRegistry(cfg.Providers.Enabled, cfg.Providers.Disabled),
+		startedAt:      time.Now(),
+		version:        buildVersion(),
+		files:          files,
+	}
+	files.server = server
+	files.authorize = mcpServer.authorizeFiles
+
+	if mcpServer.transport == "" {
+		mcpServer.transport = "sse"
+	}
+
+	// Serve tool calls from fixtures. Added first, so permissions, rate limits and the audit log apply as usual.
+	if cfg.Mock.Enabled {
+		fixtures, err := mock.LoadFixtures(&cfg.Mock)
+		if err != nil {
+			log.Fatalf("Failed to load mock fixtures: %v", err)
+		}
+		server.AddReceivingMiddleware(fixtures.Middleware())
+		mcpServer.providers.SetMock(true)
+		logging.ServerLogger.Warn("mock mode: tool calls are served from fixtures",
+			logging.String("fixtures", strings.Join(fixtures.Tools(), ",")))
+	}
+
+	// Limit tool call rates per caller. Added before the permission check, so it runs after it.
+	if authConfig.RateLimit.Enabled {
+		caller := mcpServer.authMiddleware.Caller
+		if mcpServer.transport == "stdio" {
+			caller = func(context.Context, mcp.Request) string { return "stdio" }
+		}
+		server.AddReceivingMiddleware(auth.NewRateLimiter(authConfig.RateLimit, authConfig.APIKeys).Middleware(caller))
+	}
+
+	// A policy that fails to load must not leave the tools open
+	policy, err := auth.NewPolicyEngine(authConfig.Policy)
+	if err != nil {
+		log.Fatalf("Failed to load policy: %v", err)
+	}
+	mcpServer.authMiddleware.SetPolicy(policy)
+
+	// A stdio client is the local user who started the server and sends no credentials, as is everyone
+	// when authentication is disabled; other callers are resolved from their credentials.
+	var principal *auth.AuthResult
+	switch {
+	case mcpServer.transport == "stdio":
+		principal = &auth.AuthResult{UserID: "stdio", Username: "stdio", Roles: []string{"admin"}, Method: "stdio"}
+	case !authConfig.Enabled:
+		principal = &auth.AuthResult{UserID: "anonymous", Username: "anonymous", Roles: []string{"admin"}, Method: "disabled"}
+	}
+
+	// Resolve the user a call acts for. Added before the permission check, so only authorized calls get one.
+	if impersonator := auth.NewImpersonator(authConfig.Impersonation); impersonator != nil {
+		server.AddReceivingMiddleware(impersonator.Middleware(principal))
+		log.Printf("✓ Impersonation: users named by the %s header or tools/call _meta", impersonator.Header())
 	}
 
+	// Block risky tools during deploy freezes. Added before the permission check, so it runs after it;
+	// the calendar provider that decides is only started in registerProviders.
+	server.AddReceivingMiddleware(mcpServer.freezeGuard)
+
+	// Enforce the policy and per-tool role permissions on every registered tool. Only the policy applies
+	// to callers without credentials.
+	switch {
+	case principal != nil:
+		if mcpServer.transport == "stdio" && authConfig.Enabled {
+			logging.ServerLogger.Warn("tool permissions are not enforced over stdio")
+		}
+		server.AddReceivingMiddleware(mcpServer.authMiddleware.PolicyMiddleware(principal))
+	default:
+		server.AddReceivingMiddleware(mcpServer.authMiddleware.ToolMiddleware())
+	}
+	if policy != nil {
+		log.Printf("✓ Policy: %s", authConfig.Policy.File)
+	}
+
+	mcpServer.registerStore()
+	mcpServer.registerProviders()
+	mcpServer.health = health.NewMonitor(&cfg.Health, &cfg.LLM, mcpServer.providers)
+	mcpServer.registerStatus()
+	mcpServer.registerPrompts()
+	mcpServer.registerResources()
+	mcpServer.registerAudit()
+	mcpServer.registerOutput()
+	mcpServer.registerI18n()
+	mcpServer.registerHints()
+
 	return mcpServer
 }
 
-// Start starts the MCP server with the specified transport mode
+// registerStore opens the embedded store that keeps server state across restarts
+func (s *MCPServer) registerStore() {
+	st, err := store.Open(&s.cfg.Store)
+	if err != nil {
+		logging.ServerLogger.Error("state store disabled", logging.Error(err))
+		return
+	}
+	s.store = st
+
+	tool := st.InspectTool()
+	s.server.AddTool(tool.Tool, tool.Handler)
+	log.Printf("✓ Registered store tool: %s", tool.Tool.Name)
+}
+
+// registerOutput renders tool results according to the preferences of each session and spills oversized
+// ones to files. The middleware is added after the audit log, so audit records keep the results as the
+// tools returned them.
+func (s *MCPServer) registerOutput() {
+	formatter, err := output.NewFormatter(&s.cfg.Output)
+	if err != nil {
+		logging.ServerLogger.Error("output preferences disabled", logging.Error(err))
+		return
+	}
+	s.server.AddReceivingMiddleware(formatter.Middleware())
+	s.server.AddResourceTemplate(formatter.ResultsTemplate(), formatter.ReadResult)
+
+	tool := formatter.PreferencesTool()
+	s.server.AddTool(tool.Tool, tool.Handler)
+	log.Printf("✓ Registered output tool: %s", tool.Tool.Name)
+}
+
+// registerI18n translates tool descriptions and error results into the caller's locale. The middleware
+// is added after the audit log, so audit records keep the English text and rejections are translated too.
+func (s *MCPServer) registerI18n() {
+	translator, err := i18n.NewTranslator(&s.cfg.I18n)
+	if err != nil {
+		logging.ServerLogger.Error("translations disabled", logging.Error(err))
+		return
+	}
+	s.i18n = translator
+
+	keyLocale := s.authMiddleware.Locale
+	if s.transport == "stdio" {
+		// No API key; the default locale applies
+		keyLocale = nil
+	}
+	s.server.AddReceivingMiddleware(translator.Middleware(keyLocale))
+	log.Printf("✓ Translations: %s (default %s)", strings.Join(translator.Locales(), ", "), translator.DefaultLocale())
+}
+
+// registerHints appends live provider state to the tool descriptions. The middleware is added after the
+// translations, so hints follow the translated description.
+func (s *MCPServer) registerHints() {
+	hinter := hints.New(&s.cfg.Hints, s.providers.ToolHints)
+	if hinter == nil {
+		return
+	}
+	s.server.AddReceivingMiddleware(hinter.Middleware())
+	log.Printf("✓ Tool hints: refreshed every %s", hinter.Refresh())
+}
+
+// ExportCatalog generates the translation catalog of a locale from the tools this configuration registers,
+// keeping the translations already in the locale's catalog
+func (s *MCPServer) ExportCatalog(ctx context.Context, locale string) ([]byte, error) {
+	if s.i18n == nil {
+		return nil, fmt.Errorf("translations are disabled")
+	}
+	tools, err := s.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.i18n.Export(locale, tools), nil
+}
+
+// ListTools lists the tools this configuration registers through an in-process session, in the source
+// locale and without hints, as a client would see them
+func (s *MCPServer) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
+	ctx = hints.WithoutHints(i18n.WithLocale(ctx, i18n.SourceLocale))
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := s.server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the server: %w", err)
+	}
+	defer serverSession.Close()
+	client := mcp.NewClient(&mcp.Implementation{Name: "dev-mcp-cli", Version: serverVersion}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the server: %w", err)
+	}
+	defer session.Close()
+
+	var tools []*mcp.Tool
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// registerAudit records every tool call when auditing is enabled. The middleware is added after the
+// permission check and the deploy freeze guard, so it also records the calls they reject; output,
+// translations and hints are added after it and run before it.
+func (s *MCPServer) registerAudit() {
+	auditLogger, err := audit.NewLogger(&s.cfg.Audit)
+	if err != nil {
+		logging.ServerLogger.Error("audit log disabled", logging.Error(err))
+		return
+	}
+	if auditLogger == nil {
+		return
+	}
+	s.audit = auditLogger
+
+	caller := s.authMiddleware.Caller
+	if s.transport == "stdio" {
+		// The local user who started the server
+		caller = func(context.Context, mcp.Request) string { return "stdio" }
+	}
+	s.server.AddReceivingMiddleware(auditLogger.Middleware(caller))
+
+	tool := auditLogger.QueryTool()
+	s.server.AddTool(tool.Tool, tool.Handler)
+	log.Printf("✓ Registered audit tool: %s", tool.Tool.Name)
+}
+
+// registerPrompts offers the prompt templates of the prompts directory whose providers are running
+func (s *MCPServer) registerPrompts() {
+	dir := s.cfg.Prompts.Directory
+	if dir == "" {
+		dir = "configs/prompts"
+	}
+	definitions, err := prompts.LoadPrompts(dir)
+	if err != nil {
+		logging.ServerLogger.Error("prompts disabled", logging.Error(err))
+		return
+	}
+
+	for _, definition := range definitions {
+		missing := slices.DeleteFunc(slices.Clone(definition.Requires), func(name string) bool {
+			return s.providers.Active(name) || s.providers.Mocked(name)
+		})
+		if len(missing) > 0 {
+			log.Printf("○ Prompt %s skipped: %s not available", definition.Prompt.Name, strings.Join(missing, ", "))
+			continue
+		}
+		s.server.AddPrompt(definition.Prompt, definition.Handler)
+		log.Printf("✓ Registered prompt: %s", definition.Prompt.Name)
+	}
+}
+
+// registerResources publishes the dependency health, and the files, the database tables and the S3 objects
+// under the configured prefixes as resources when their providers are running
+func (s *MCPServer) registerResources() {
+	for _, definition := range resources.GetHealthResources(s.health) {
+		s.server.AddResource(definition.Resource, definition.Handler)
+	}
+
+	// Files are read and watched for the callers of file_read
+	if fileProvider, ok := s.providers.Get("file").(*file.FileProvider); ok && s.providers.Active("file") {
+		s.files.setProvider(fileProvider)
+		for _, definition := range resources.GetFileResourceTemplates(fileProvider) {
+			handler := definition.Handler
+			s.server.AddResourceTemplate(definition.ResourceTemplate, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+				if path, ok := resources.FilePath(req.Params.URI); ok {
+					if err := s.authorizeFiles(ctx, req, path); err != nil {
+						return nil, err
+					}
+				}
+				return handler(ctx, req)
+			})
+			log.Printf("✓ Registered resource template: %s", definition.ResourceTemplate.URITemplate)
+		}
+	}
+
+	var dbRegistry *database.Registry
+	var s3Client *s3.S3Client
+	var templates []resources.ResourceTemplateDefinition
+	if databaseProvider, ok := s.providers.Get("database").(*database.DatabaseProvider); ok && s.providers.Active("database") {
+		dbRegistry = databaseProvider.Registry()
+		templates = append(templates, resources.GetDatabaseResourceTemplates(dbRegistry)...)
+	}
+	if s3Provider, ok := s.providers.Get("s3").(*s3.S3Provider); ok && s.providers.Active("s3") {
+		s3Client = s3Provider.Client()
+		templates = append(templates, resources.GetS3ResourceTemplates(s3Client)...)
+	}
+	if dbRegistry == nil && s3Client == nil {
+		return
+	}
+
+	// Reads are decided as calls of the tools returning the same data
+	toolCall := func(uri string) (string, map[string]string, bool) {
+		switch {
+		case strings.HasPrefix(uri, "db://") && dbRegistry != nil:
+			return resources.DatabaseToolCall(dbRegistry, uri)
+		case strings.HasPrefix(uri, "s3://"):
+			return resources.S3ToolCall(uri)
+		}
+		return "", nil, false
+	}
+
+	// Loki is browsed through its tools
+	for _, definition := range resources.GetAllResources(context.Background(), dbRegistry, nil, s3Client) {
+		s.server.AddResource(definition.Resource, s.authorizeResource(definition.Handler, toolCall))
+	}
+	for _, definition := range templates {
+		s.server.AddResourceTemplate(definition.ResourceTemplate, s.authorizeResource(definition.Handler, toolCall))
+		log.Printf("✓ Registered resource template: %s", definition.ResourceTemplate.URITemplate)
+	}
+}
+
+// authorizeResource checks a resource read as a call of the tool toolCall names for its URI, as file://
+// reads are checked as file_read calls. URIs toolCall does not know are passed to the handler.
+func (s *MCPServer) authorizeResource(handler mcp.ResourceHandler, toolCall func(uri string) (string, map[string]string, bool)) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		if tool, args, ok := toolCall(req.Params.URI); ok && s.transport != "stdio" && s.authConfig.Enabled {
+			arguments, _ := json.Marshal(args)
+			if err := s.authMiddleware.AuthorizeAs(ctx, req, tool, arguments); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// registerProviders registers every provider with the registry and starts them in order
+func (s *MCPServer) registerProviders() {
+	r := s.providers
+	r.Register("database", func() provider.Provider {
+		return database.NewDatabaseProvider(&s.cfg.Database, s.cfg.Databases)
+	})
+	r.Register("loki", func() provider.Provider { return loki.NewLokiProvider(&s.cfg.Loki) })
+	r.Register("grafana", func() provider.Provider { return grafana.NewGrafanaProvider(&s.cfg.Grafana) })
+	r.Register("s3", func() provider.Provider { return s3.NewS3Provider(&s.cfg.S3) })
+	r.Register("sentry", func() provider.Provider { return sentry.NewSentryProvider(&s.cfg.Sentry) })
+	r.Register("file", func() provider.Provider { return file.NewFileProvider(&s.cfg.File) })
+	r.Register("knowledge", func() provider.Provider {
+		return knowledge.NewKnowledgeProvider(&s.cfg.Knowledge, &s.cfg.S3)
+	})
+	r.Register("catalog", func() provider.Provider { return catalog.NewCatalogProvider(&s.cfg.Catalog) })
+	r.Register("cicd", func() provider.Provider { return cicd.NewCICDProvider(&s.cfg.CICD) })
+	r.Register("code_host", func() provider.Provider {
+		return codehost.NewCodeHostProvider(&s.cfg.CodeHost, &s.cfg.CICD)
+	})
+	r.Register("registry", func() provider.Provider { return registry.NewRegistryProvider(s.cfg.Registries) })
+	r.Register("terraform", func() provider.Provider {
+		return terraform.NewTerraformProvider(&s.cfg.Terraform, &s.cfg.S3)
+	})
+	r.Register("aws", func() provider.Provider { return aws.NewAWSProvider(&s.cfg.AWS, &s.cfg.S3) })
+	r.Register("email", func() provider.Provider { return email.NewEmailProvider(&s.cfg.Email) })
+	r.Register("calendar", func() provider.Provider { return calendar.NewCalendarProvider(&s.cfg.Calendar) })
+	r.Register("oncall", func() provider.Provider { return oncall.NewOnCallProvider(&s.cfg.OnCall) })
+	r.Register("kubernetes", func() provider.Provider {
+		return kubernetes.NewKubernetesProvider(&s.cfg.Kubernetes)
+	})
+	r.Register("git", func() provider.Provider { return git.NewGitProvider(&s.cfg.Git) })
+	r.Register("profiling", func() provider.Provider {
+		return profiling.NewProfilingProvider(&s.cfg.Profiling, &s.cfg.S3)
+	})
+	r.Register("artifacts", func() provider.Provider { return artifacts.NewArtifactsProvider(&s.cfg.Artifacts) })
+	r.Register("sbom", func() provider.Provider {
+		// Without the file provider, SBOMs are returned inline or uploaded to S3
+		var files sbom.FileWriter
+		if fileProvider, ok := r.Get("file").(*file.FileProvider); ok {
+			files = fileProvider
+		}
+		return sbom.NewSBOMProvider(&s.cfg.SBOM, &s.cfg.S3, files)
+	})
+	r.Register("proto", func() provider.Provider {
+		// Without the file provider, descriptor sets are returned inline
+		var files proto.FileWriter
+		if fileProvider, ok := r.Get("file").(*file.FileProvider); ok {
+			files = fileProvider
+		}
+		return proto.NewProtoProvider(&s.cfg.Proto, files)
+	})
+	r.Register("kafka", func() provider.Provider {
+		// Without the proto provider, the protobuf format is unavailable
+		var protos kafka.ProtoDecoder
+		if protoProvider, ok := r.Get("proto").(*proto.ProtoProvider); ok {
+			protos = protoProvider
+		}
+		return kafka.NewKafkaProvider(&s.cfg.Kafka, protos)
+	})
+	r.Register("mongodb", func() provider.Provider { return mongodb.NewMongoDBProvider(&s.cfg.MongoDB) })
+	r.Register("elasticsearch", func() provider.Provider { return elasticsearch.NewElasticsearchProvider(&s.cfg.Search) })
+	r.Register("queue", func() provider.Provider {
+		return queue.NewQueueProvider(&s.cfg.Queue, &s.cfg.AWS, &s.cfg.S3)
+	})
+	r.Register("cache", func() provider.Provider {
+		// Without the database provider, there is no result cache to clear
+		var results cache.ResultCache
+		if databaseProvider, ok := r.Get("database").(*database.DatabaseProvider); ok && databaseProvider.IsAvailable() {
+			results = databaseProvider
+		}
+		return cache.NewCacheProvider(&s.cfg.Cache, results)
+	})
+	r.Register("deploy", func() provider.Provider {
+		// Each check is skipped when its provider is not running
+		var services deploy.Catalog
+		var issues deploy.IssueSource
+		var logs deploy.LogSource
+		if catalogProvider, ok := r.Get("catalog").(*catalog.CatalogProvider); ok && catalogProvider.IsAvailable() {
+			services = catalogProvider.Client()
+		}
+		if sentryProvider, ok := r.Get("sentry").(*sentry.SentryProvider); ok && sentryProvider.IsAvailable() {
+			issues = sentryProvider.Client()
+		}
+		if lokiProvider, ok := r.Get("loki").(*loki.LokiProvider); ok && lokiProvider.IsAvailable() {
+			logs = lokiProvider.Client()
+		}
+		return deploy.NewDeployProvider(&s.cfg.Deploy, services, issues, logs)
+	})
+	r.Register("incident", func() provider.Provider {
+		// Each source is skipped when its provider is not running
+		var services incident.Catalog
+		var issues incident.IssueSource
+		var logs incident.LogSource
+		var runs incident.RunSource
+		if catalogProvider, ok := r.Get("catalog").(*catalog.CatalogProvider); ok && catalogProvider.IsAvailable() {
+			services = catalogProvider.Client()
+		}
+		if sentryProvider, ok := r.Get("sentry").(*sentry.SentryProvider); ok && sentryProvider.IsAvailable() {
+			issues = sentryProvider.Client()
+		}
+		if lokiProvider, ok := r.Get("loki").(*loki.LokiProvider); ok && lokiProvider.IsAvailable() {
+			logs = lokiProvider.Client()
+		}
+		if cicdProvider, ok := r.Get("cicd").(*cicd.CICDProvider); ok && cicdProvider.IsAvailable() {
+			runs = cicdProvider.Client()
+		}
+		return incident.NewIncidentProvider(services, issues, logs, runs)
+	})
+	r.Register("slo", func() provider.Provider {
+		// Without the Loki provider, objectives with source loki report an unknown state
+		var logs slo.LogSource
+		if lokiProvider, ok := r.Get("loki").(*loki.LokiProvider); ok && lokiProvider.IsAvailable() {
+			logs = lokiProvider.Client()
+		}
+		return slo.NewSLOProvider(&s.cfg.SLO, logs)
+	})
+	r.Register("onboarding", func() provider.Provider {
+		// Each search is skipped when its provider is not running
+		var services onboarding.Catalog
+		var labels onboarding.LabelSource
+		var projects onboarding.ProjectSource
+		if catalogProvider, ok := r.Get("catalog").(*catalog.CatalogProvider); ok && catalogProvider.IsAvailable() {
+			services = catalogProvider.Client()
+		}
+		if lokiProvider, ok := r.Get("loki").(*loki.LokiProvider); ok && lokiProvider.IsAvailable() {
+			labels = lokiProvider.Client()
+		}
+		if sentryProvider, ok := r.Get("sentry").(*sentry.SentryProvider); ok && sentryProvider.IsAvailable() {
+			projects = sentryProvider.Client()
+		}
+		return onboarding.NewOnboardingProvider(services, labels, projects)
+	})
+	r.Register("exec", func() provider.Provider { return exec.NewExecProvider(&s.cfg.Exec) })
+	r.Register("code_quality", func() provider.Provider {
+		return codequality.NewCodeQualityProvider(&s.cfg.CodeQuality)
+	})
+	r.Register("diagnostics", func() provider.Provider { return diagnostics.NewDiagnosticsProvider() })
+	r.Register("utility", func() provider.Provider {
+		// Without the file provider, utility tools take inline content only
+		var files utility.FileAccess
+		if fileProvider, ok := r.Get("file").(*file.FileProvider); ok {
+			files = fileProvider
+		}
+		return utility.NewUtilityProvider(&s.cfg.Utility, &s.cfg.Swagger, &s.cfg.S3, files)
+	})
+
+	r.Start(s.server)
+
+	// Hand the deploy freeze check to freezeGuard, which is already in place after the role check
+	if calendarProvider, ok := r.Get("calendar").(*calendar.CalendarProvider); ok && calendarProvider.IsAvailable() {
+		s.freeze = calendarProvider.FreezeMiddleware()
+	}
+}
+
+// freezeGuard runs the deploy freeze check of the calendar provider, and passes calls through when it
+// is not running. It is set up before the providers are started so the permission check wraps it.
+func (s *MCPServer) freezeGuard(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if s.freeze == nil {
+			return next(ctx, method, req)
+		}
+		return s.freeze(next)(ctx, method, req)
+	}
+}
+
+// sectionProviders lists the providers built from each configuration section, including those built on
+// the clients of other providers
+var sectionProviders = map[string][]string{
+	"database":     {"database", "cache"},
+	"databases":    {"database", "cache"},
+	"loki":         {"loki", "deploy", "incident", "slo", "onboarding"},
+	"grafana":      {"grafana"},
+	"s3":           {"s3", "knowledge", "terraform", "aws", "profiling", "sbom", "queue"},
+	"sentry":       {"sentry", "deploy", "incident", "onboarding"},
+	"knowledge":    {"knowledge"},
+	"catalog":      {"catalog", "deploy", "incident", "onboarding"},
+	"cicd":         {"cicd", "incident", "code_host"},
+	"code_host":    {"code_host"},
+	"registries":   {"registry"},
+	"terraform":    {"terraform"},
+	"aws":          {"aws", "queue"},
+	"email":        {"email"},
+	"calendar":     {"calendar"},
+	"oncall":       {"oncall"},
+	"kubernetes":   {"kubernetes"},
+	"git":          {"git"},
+	"profiling":    {"profiling"},
+	"artifacts":    {"artifacts"},
+	"exec":         {"exec"},
+	"code_quality": {"code_quality"},
+	"sbom":         {"sbom"},
+	"proto":        {"proto", "kafka"},
+	"kafka":        {"kafka"},
+	"mongodb":      {"mongodb"},
+	"search":       {"elasticsearch"},
+	"queue":        {"queue"},
+	"cache":        {"cache"},
+	"deploy":       {"deploy"},
+	"slo":          {"slo"},
+}
+
+// applySecrets restarts the providers built from the sections whose secrets were rotated, with the new values.
+// Other sections, such as auth, are read once at startup and need a restart of the server.
+func (s *MCPServer) applySecrets(sections []string, next *config.Config) {
+	var names, unapplied []string
+	for _, section := range sections {
+		providers, ok := sectionProviders[section]
+		if !ok {
+			unapplied = append(unapplied, section)
+			continue
+		}
+		for _, name := range providers {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(unapplied) > 0 {
+		logging.ServerLogger.Warn("rotated secrets apply after a server restart",
+			logging.String("sections", strings.Join(unapplied, ",")))
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	applied := slices.DeleteFunc(slices.Clone(sections), func(section string) bool {
+		return slices.Contains(unapplied, section)
+	})
+	s.providers.Restart(s.server, names, func() {
+		s.cfg.ApplySections(next, applied)
+	})
+}
+
+// Start starts the MCP server with the configured transport and blocks until it stops or receives SIGINT/SIGTERM
 func (s *MCPServer) Start() error {
 	logger := logging.ServerLogger
 	logger.Info("Starting MCP server with authentication",
 		logging.String("transport", s.transport),
 		logging.String("auth_enabled", fmt.Sprintf("%t", s.authConfig.Enabled)))
 
-	addr := fmt.Sprintf("%s:%d", s.host, s.port)
-
-	server1 := mcp.NewServer(&mcp.Implementation{Name: "visal dev tools"}, nil)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Use the standard SDK SSE handler with authentication
-	sseHandler := mcp.NewSSEHandler(func(request *http.Request) *mcp.Server {
-		return server1
-	}, nil)
+	go s.health.Run(ctx)
+	go s.files.Run(ctx)
+	if watcher := s.cfg.SecretWatcher(); watcher != nil {
+		go watcher.Run(ctx, s.applySecrets)
+	}
 
-	logger.Info("starting SSE server using standard SDK handler", logging.String("address", addr))
+	addr := fmt.Sprintf("%s:%d", s.host, s.port)
 
-	// Use standard HTTP server with mux
-	return http.ListenAndServe(addr, sseHandler)
+	switch s.transport {
+	case "stdio":
+		// Every frame goes to the real stdout through one writer; anything else printing to stdout
+		// goes to stderr instead of corrupting the stream
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		writer := newFrameWriter(stdout, s.cfg.Server.StdioQueueBytes)
+		s.stdioWriter.Store(writer)
+		err := s.server.Run(ctx, &mcp.IOTransport{Reader: os.Stdin, Writer: writer})
+		frames, written, _ := writer.Stats()
+		logger.Info("stdio transport closed",
+			logging.Int("frames", int(frames)),
+			logging.Int("bytes", int(written)))
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	case "http", "streamable-http":
+		path := s.cfg.Server.Path
+		if path == "" {
+			path = defaultStreamablePath
+		}
+		return s.serveHTTP(ctx, addr, path, s.newStreamableHandler())
+	case "sse":
+		return s.serveHTTP(ctx, addr, "/", NewAuthenticatedSSETransport(s.authMiddleware, s.server))
+	default:
+		return fmt.Errorf("unknown transport %q (use stdio, sse or http)", s.transport)
+	}
 }
 
 // Close closes the MCP server and performs cleanup
@@ -88,4 +759,13 @@ func (s *MCPServer) Close() {
 	logger := logging.ServerLogger
 	logger.Info("Closing MCP server...")
 
+	if err := s.providers.Close(); err != nil {
+		logger.Warn("failed to close providers", logging.Error(err))
+	}
+	if err := s.audit.Close(); err != nil {
+		logger.Warn("failed to close audit log", logging.Error(err))
+	}
+	if err := s.store.Close(); err != nil {
+		logger.Warn("failed to close state store", logging.Error(err))
+	}
 }
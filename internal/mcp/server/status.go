@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/health"
+	"dev-mcp/internal/provider"
+)
+
+// serverVersion is the version the server reports to clients and in server_status
+const serverVersion = "1.0.0"
+
+// Overall states of server_status
+const (
+	statusOK       = "ok"
+	statusDegraded = "degraded" // A dependency is slow or down, or a provider has an error
+	statusInvalid  = "invalid"  // The configuration has errors
+)
+
+// StatusReport is the state of the whole server, as returned by server_status and /status
+type StatusReport struct {
+	Status        string                   `json:"status"` // ok, degraded or invalid
+	Version       VersionInfo              `json:"version"`
+	Transport     string                   `json:"transport"`
+	AuthEnabled   bool                     `json:"auth_enabled"`
+	StartedAt     time.Time                `json:"started_at"`
+	Uptime        string                   `json:"uptime"`
+	UptimeSeconds int64                    `json:"uptime_seconds"`
+	Config        *config.ValidationResult `json:"config"`
+	Providers     ProviderSummary          `json:"providers"`
+	Health        *health.Snapshot         `json:"health"`
+	Connections   ConnectionStats          `json:"connections"`
+}
+
+// VersionInfo identifies the running build
+type VersionInfo struct {
+	Server     string    `json:"server"`
+	Go         string    `json:"go"`
+	Revision   string    `json:"revision,omitempty"`
+	Modified   bool      `json:"modified,omitempty"` // Built from a tree with uncommitted changes
+	CommitTime time.Time `json:"commit_time,omitzero"`
+}
+
+// ProviderSummary counts the providers and lists their states
+type ProviderSummary struct {
+	Available int                      `json:"available"`
+	Errors    int                      `json:"errors"`
+	Total     int                      `json:"total"`
+	States    []provider.ProviderState `json:"states"`
+}
+
+// ConnectionStats describes the client sessions and, over stdio, the frames written
+type ConnectionStats struct {
+	Sessions    int           `json:"sessions"`
+	Clients     []SessionInfo `json:"clients"`
+	StdioFrames int64         `json:"stdio_frames,omitempty"`
+	StdioBytes  int64         `json:"stdio_bytes,omitempty"`
+	StdioQueued int           `json:"stdio_queued_bytes,omitempty"`
+	FileWatches int           `json:"file_watches"`       // Paths polled for subscribed file:// resources
+	FileSubs    int           `json:"file_subscriptions"` // Sessions subscribed to them
+}
+
+// SessionInfo is a connected client. Session IDs are left out: a session ID is what a request presents to
+// act in that session, so the report must not hand them to every authenticated caller.
+type SessionInfo struct {
+	Client  string `json:"client,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// buildVersion reads the Go version and VCS stamp of the binary
+func buildVersion() VersionInfo {
+	version := VersionInfo{Server: serverVersion, Go: runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version.Revision = setting.Value
+		case "vcs.modified":
+			version.Modified = setting.Value == "true"
+		case "vcs.time":
+			version.CommitTime, _ = time.Parse(time.RFC3339, setting.Value)
+		}
+	}
+	return version
+}
+
+// statusReport aggregates the configuration validation, the provider states, the dependency health and the
+// connected sessions. check runs the health checks first instead of using the last snapshot.
+func (s *MCPServer) statusReport(ctx context.Context, check bool) *StatusReport {
+	uptime := time.Since(s.startedAt)
+	report := &StatusReport{
+		Status:        statusOK,
+		Version:       s.version,
+		Transport:     s.transport,
+		AuthEnabled:   s.authConfig.Enabled,
+		StartedAt:     s.startedAt,
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Config:        s.cfg.ValidateConfig(),
+		Connections:   ConnectionStats{Clients: []SessionInfo{}},
+	}
+
+	if check {
+		report.Health = s.health.Refresh(ctx)
+	} else {
+		report.Health = s.health.Snapshot(ctx)
+	}
+	// The snapshot's provider checks are recorded in the registry, so the states include them
+	report.Providers.States = s.providers.States()
+	report.Providers.Total = len(report.Providers.States)
+	for _, state := range report.Providers.States {
+		if state.Available {
+			report.Providers.Available++
+		}
+		if state.LastError != "" {
+			report.Providers.Errors++
+		}
+	}
+
+	for session := range s.server.Sessions() {
+		info := SessionInfo{}
+		if params := session.InitializeParams(); params != nil && params.ClientInfo != nil {
+			info.Client, info.Version = params.ClientInfo.Name, params.ClientInfo.Version
+		}
+		report.Connections.Clients = append(report.Connections.Clients, info)
+	}
+	report.Connections.Sessions = len(report.Connections.Clients)
+	report.Connections.FileWatches, report.Connections.FileSubs = s.files.count()
+	if writer := s.stdioWriter.Load(); writer != nil {
+		report.Connections.StdioFrames, report.Connections.StdioBytes, report.Connections.StdioQueued = writer.Stats()
+	}
+
+	switch {
+	case !report.Config.Valid:
+		report.Status = statusInvalid
+	case report.Health.Status == health.StatusDegraded || report.Providers.Errors > 0:
+		report.Status = statusDegraded
+	}
+	return report
+}
+
+// registerStatus adds the server_status tool
+func (s *MCPServer) registerStatus() {
+	tool := s.createStatusTool()
+	s.server.AddTool(tool.Tool, tool.Handler)
+	log.Printf("✓ Registered status tool: %s", tool.Tool.Name)
+}
+
+// createStatusTool creates the server_status tool
+func (s *MCPServer) createStatusTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "server_status",
+		Description: "Report the state of this server in one payload: version and build, transport, uptime, configuration validation (errors and warnings per service), " +
+			"every provider's availability and last health check, dependency health and the connected client sessions. status is ok, degraded or invalid (configuration errors)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"check": {
+					"type": "boolean",
+					"description": "Run the health checks now instead of reporting the last periodic results",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Check bool `json:"check,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Status Error: invalid arguments: %v", err)}},
+					IsError: true,
+				}, nil
+			}
+		}
+
+		jsonData, err := json.MarshalIndent(s.statusReport(ctx, args.Check), "", "  ")
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Status Error: failed to marshal data: %v", err)}},
+				IsError: true,
+			}, nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+		}, nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// handleStatus serves the status report over HTTP; ?check=true runs the health checks first
+func (s *MCPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	report := s.statusReport(r.Context(), r.URL.Query().Get("check") == "true")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
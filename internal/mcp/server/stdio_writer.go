@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultStdioQueueBytes bounds the frames queued for stdout before writers block
+const defaultStdioQueueBytes = 4 << 20
+
+// frameWriter writes the frames of the stdio transport (responses and notifications alike) to stdout from
+// a single goroutine. Frames are buffered and flushed when the queue runs empty, and writers block while
+// more than limit bytes are queued, so a client that reads slowly slows the server down instead of
+// growing memory without bound.
+type frameWriter struct {
+	out   io.Writer
+	buf   *bufio.Writer
+	limit int
+
+	mu     sync.Mutex
+	cond   *sync.Cond // Signalled when frames are queued or written, and on close
+	queue  [][]byte
+	queued int // Bytes queued or being written
+	closed bool
+	err    error // First write error; later writes fail with it
+	done   chan struct{}
+
+	frames  atomic.Int64
+	written atomic.Int64
+}
+
+// newFrameWriter starts the writer goroutine over out
+func newFrameWriter(out io.Writer, limit int) *frameWriter {
+	if limit <= 0 {
+		limit = defaultStdioQueueBytes
+	}
+	w := &frameWriter{out: out, buf: bufio.NewWriter(out), limit: limit, done: make(chan struct{})}
+	w.cond = sync.NewCond(&w.mu)
+	go w.run()
+	return w
+}
+
+// Write queues one frame; the transport writes each message with a single call
+func (w *frameWriter) Write(p []byte) (int, error) {
+	frame := append([]byte(nil), p...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// A frame larger than the limit is still accepted once the queue is empty
+	for w.queued > 0 && w.queued+len(frame) > w.limit && !w.closed && w.err == nil {
+		w.cond.Wait()
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	w.queue = append(w.queue, frame)
+	w.queued += len(frame)
+	w.cond.Broadcast()
+	return len(p), nil
+}
+
+// run writes the queued frames in order until the writer is closed and drained
+func (w *frameWriter) run() {
+	defer close(w.done)
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 {
+			w.mu.Unlock()
+			return
+		}
+		batch := w.queue
+		w.queue = nil
+		w.mu.Unlock()
+
+		size := 0
+		var err error
+		for _, frame := range batch {
+			if err == nil {
+				_, err = w.buf.Write(frame)
+			}
+			size += len(frame)
+		}
+
+		w.mu.Lock()
+		if err == nil && len(w.queue) == 0 {
+			err = w.buf.Flush()
+		}
+		if err == nil {
+			w.frames.Add(int64(len(batch)))
+			w.written.Add(int64(size))
+		} else if w.err == nil {
+			w.err = err
+		}
+		w.queued -= size
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}
+}
+
+// Close writes the queued frames and stops the writer; stdout itself stays open
+func (w *frameWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Stats returns the frames and bytes written so far and the bytes still queued
+func (w *frameWriter) Stats() (frames, written int64, queued int) {
+	w.mu.Lock()
+	queued = w.queued
+	w.mu.Unlock()
+	return w.frames.Load(), w.written.Load(), queued
+}
@@ -0,0 +1,221 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/config"
+)
+
+// MetaKey labels mock results in their _meta with the fixture file they came from
+const MetaKey = "dev-mcp/mock"
+
+// Response is a canned response of a fixture file; exactly one of result, text and error is set
+type Response struct {
+	Match  map[string]interface{} `yaml:"match"`  // Arguments the call must have; a response without match is the fallback
+	Result interface{}            `yaml:"result"` // Returned as indented JSON, like the tools do
+	Text   string                 `yaml:"text"`   // Returned as is
+	Error  string                 `yaml:"error"`  // Returned as an error result
+}
+
+// Fixture holds the canned responses of one tool, in <tool>.yaml of the fixtures directory
+type Fixture struct {
+	Tool      string     `yaml:"tool"` // The file name by default
+	Responses []Response `yaml:"responses"`
+}
+
+// fixture is a loaded fixture file
+type fixture struct {
+	Fixture
+	file string
+}
+
+// Fixtures serves tool calls from fixture files, so results are the same on every run and need no backends
+type Fixtures struct {
+	dir         string
+	fixtures    map[string]*fixture
+	passthrough []string
+}
+
+// LoadFixtures reads every *.yaml fixture of the mock directory. A missing directory yields no fixtures,
+// so every call that does not pass through fails with a hint to add one.
+func LoadFixtures(cfg *config.MockConfig) (*Fixtures, error) {
+	f := &Fixtures{dir: cfg.Directory, fixtures: map[string]*fixture{}, passthrough: cfg.Passthrough}
+	if f.dir == "" {
+		f.dir = "configs/mock"
+	}
+	for _, pattern := range f.passthrough {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("mock.passthrough: invalid pattern %q", pattern)
+		}
+	}
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("failed to read mock directory: %w", err)
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		file := filepath.Join(f.dir, entry.Name())
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mock fixture: %w", err)
+		}
+		fx := &fixture{file: file}
+		if err := yaml.Unmarshal(data, &fx.Fixture); err != nil {
+			return nil, fmt.Errorf("mock fixture %s: %w", entry.Name(), err)
+		}
+		if fx.Tool == "" {
+			fx.Tool = strings.TrimSuffix(entry.Name(), ext)
+		}
+		if _, ok := f.fixtures[fx.Tool]; ok {
+			return nil, fmt.Errorf("mock fixture %s: duplicate fixture for tool %s", entry.Name(), fx.Tool)
+		}
+		for i, response := range fx.Responses {
+			set := 0
+			for _, given := range []bool{response.Result != nil, response.Text != "", response.Error != ""} {
+				if given {
+					set++
+				}
+			}
+			if set != 1 {
+				return nil, fmt.Errorf("mock fixture %s: response %d needs exactly one of result, text and error", entry.Name(), i+1)
+			}
+			fx.Responses[i].Result = normalize(response.Result)
+			for key, value := range response.Match {
+				fx.Responses[i].Match[key] = normalize(value)
+			}
+		}
+		f.fixtures[fx.Tool] = fx
+	}
+	return f, nil
+}
+
+// Tools returns the names of the tools with a fixture
+func (f *Fixtures) Tools() []string {
+	tools := make([]string, 0, len(f.fixtures))
+	for tool := range f.fixtures {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// Middleware returns MCP receiving middleware that answers every tools/call from the fixtures, except
+// for the tools that pass through
+func (f *Fixtures) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil || f.passes(callReq.Params.Name) {
+				return next(ctx, method, req)
+			}
+			return f.Call(callReq.Params.Name, callReq.Params.Arguments), nil
+		}
+	}
+}
+
+// passes reports whether a tool runs for real in mock mode
+func (f *Fixtures) passes(tool string) bool {
+	for _, pattern := range f.passthrough {
+		if matched, _ := path.Match(pattern, tool); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Call returns the first response of a tool's fixture whose match the arguments satisfy
+func (f *Fixtures) Call(tool string, arguments json.RawMessage) *mcp.CallToolResult {
+	fx, ok := f.fixtures[tool]
+	if !ok {
+		return errorResult(fmt.Errorf("no fixture for %s; add %s", tool, filepath.Join(f.dir, tool+".yaml")))
+	}
+	args := map[string]interface{}{}
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return errorResult(fmt.Errorf("invalid arguments: %w", err))
+		}
+	}
+
+	for i, response := range fx.Responses {
+		if !matches(response.Match, args) {
+			continue
+		}
+		label := fmt.Sprintf("Mock data: response %d of %s", i+1, fx.file)
+		result := &mcp.CallToolResult{Meta: mcp.Meta{MetaKey: fx.file}}
+		switch {
+		case response.Error != "":
+			result.IsError = true
+			result.Content = []mcp.Content{&mcp.TextContent{Text: response.Error}}
+		case response.Text != "":
+			result.Content = []mcp.Content{&mcp.TextContent{Text: response.Text}}
+		default:
+			jsonData, err := json.MarshalIndent(response.Result, "", "  ")
+			if err != nil {
+				return errorResult(fmt.Errorf("%s response %d: %w", fx.file, i+1, err))
+			}
+			result.Content = []mcp.Content{&mcp.TextContent{Text: string(jsonData)}}
+		}
+		result.Content = append(result.Content, &mcp.TextContent{Text: label})
+		return result
+	}
+	return errorResult(fmt.Errorf("no response of %s matches these arguments", fx.file))
+}
+
+// matches reports whether the arguments have every value of match; values compare as JSON
+func matches(match, args map[string]interface{}) bool {
+	for key, expected := range match {
+		actual, ok := args[key]
+		if !ok {
+			return false
+		}
+		expectedJSON, err1 := json.Marshal(expected)
+		actualJSON, err2 := json.Marshal(actual)
+		if err1 != nil || err2 != nil || string(expectedJSON) != string(actualJSON) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize turns the maps decoded from YAML into JSON objects
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func errorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Mock Error: %v", err)}},
+		IsError: true,
+	}
+}
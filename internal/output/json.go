@@ -0,0 +1,203 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// field is a member of a JSON object
+type field struct {
+	key   string
+	value interface{}
+}
+
+// object is a JSON object that keeps the order of its members, so re-rendered results read like the original
+type object []field
+
+// MarshalJSON writes the members in order
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// renderJSON re-renders a JSON object or array according to the preferences and returns the lists it cut.
+// It reports false for text that is not JSON or needs no change.
+func renderJSON(text string, prefs Preferences) (string, []string, bool) {
+	if prefs.Format == FormatPretty && prefs.MaxRows == 0 && prefs.DateFormat == DateRFC3339 && prefs.location == nil {
+		return "", nil, false
+	}
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return "", nil, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	value, err := decodeValue(dec)
+	if err != nil || dec.More() {
+		return "", nil, false
+	}
+
+	var cut []string
+	value = transform(value, "$", prefs, &cut)
+
+	var rendered []byte
+	switch prefs.Format {
+	case FormatJSON:
+		rendered, err = json.Marshal(value)
+	case FormatText:
+		rendered, err = yaml.Marshal(toYAML(value))
+	default:
+		rendered, err = json.MarshalIndent(value, "", "  ")
+	}
+	if err != nil {
+		return "", nil, false
+	}
+	return strings.TrimSuffix(string(rendered), "\n"), cut, true
+}
+
+// decodeValue reads the next JSON value, keeping object members in order
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		o := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, field{key: key.(string), value: value})
+		}
+		_, err := dec.Token()
+		return o, err
+	case '[':
+		list := []interface{}{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token()
+		return list, err
+	default:
+		return nil, fmt.Errorf("unexpected %v", delim)
+	}
+}
+
+// transform cuts lists to max_rows and converts timestamps, recording the path and length of every cut list
+func transform(value interface{}, path string, prefs Preferences, cut *[]string) interface{} {
+	switch v := value.(type) {
+	case object:
+		for i := range v {
+			v[i].value = transform(v[i].value, path+"."+v[i].key, prefs, cut)
+		}
+		return v
+	case []interface{}:
+		if prefs.MaxRows > 0 && len(v) > prefs.MaxRows {
+			*cut = append(*cut, fmt.Sprintf("%s (%d in total)", path, len(v)))
+			v = v[:prefs.MaxRows]
+		}
+		for i := range v {
+			v[i] = transform(v[i], fmt.Sprintf("%s[%d]", path, i), prefs, cut)
+		}
+		return v
+	case string:
+		return formatTime(v, prefs)
+	default:
+		return value
+	}
+}
+
+// formatTime converts an RFC 3339 timestamp to the preferred zone and format; other strings are kept
+func formatTime(value string, prefs Preferences) interface{} {
+	if len(value) < len("2006-01-02T15:04:05Z") || value[4] != '-' || value[10] != 'T' {
+		return value
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+	if prefs.location != nil {
+		t = t.In(prefs.location)
+	}
+	switch prefs.DateFormat {
+	case DateRFC3339:
+		return t.Format(time.RFC3339Nano)
+	case DateUnix:
+		return json.Number(strconv.FormatInt(t.Unix(), 10))
+	case DateUnixMs:
+		return json.Number(strconv.FormatInt(t.UnixMilli(), 10))
+	default:
+		return t.Format(prefs.DateFormat)
+	}
+}
+
+// toYAML converts a decoded value to ordered YAML maps with plain numbers
+func toYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case object:
+		m := make(yaml.MapSlice, len(v))
+		for i, f := range v {
+			m[i] = yaml.MapItem{Key: f.key, Value: toYAML(f.value)}
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = toYAML(item)
+		}
+		return list
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return value
+	}
+}
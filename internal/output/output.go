@@ -0,0 +1,277 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+// Result formats
+const (
+	FormatPretty = "pretty" // Indented JSON, as the tools return it
+	FormatJSON   = "json"   // Compact JSON on one line
+	FormatText   = "text"   // YAML-like text without braces and quotes
+)
+
+// Date formats besides Go layouts
+const (
+	DateRFC3339 = "rfc3339"
+	DateUnix    = "unix"
+	DateUnixMs  = "unix_ms"
+)
+
+// Preferences are the rendering preferences of a session
+type Preferences struct {
+	Format     string `json:"format"`
+	Emoji      bool   `json:"emoji"`
+	MaxRows    int    `json:"max_rows"`           // 0 keeps every item
+	DateFormat string `json:"date_format"`        // rfc3339, unix, unix_ms or a Go layout
+	Timezone   string `json:"timezone,omitempty"` // Empty leaves timestamps in the zone they were returned in
+	Client     string `json:"client,omitempty"`   // MCP client name whose configured preferences apply
+
+	MaxResultBytes int `json:"max_result_bytes"` // 0 never spills
+
+	location *time.Location
+}
+
+// Formatter renders tool results according to the preferences of the calling session
+type Formatter struct {
+	defaults config.OutputPreferences
+	clients  map[string]config.OutputPreferences
+	spill    spillConfig
+
+	mu       sync.Mutex
+	sessions map[*mcp.ServerSession]config.OutputPreferences // Set with output_preferences, dropped when the session ends
+}
+
+// NewFormatter validates the configured preferences
+func NewFormatter(cfg *config.OutputConfig) (*Formatter, error) {
+	f := &Formatter{
+		defaults: cfg.OutputPreferences,
+		clients:  cfg.Clients,
+		sessions: map[*mcp.ServerSession]config.OutputPreferences{},
+		spill:    newSpillConfig(cfg),
+	}
+	if _, err := resolve(f.defaults); err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+	for client, prefs := range f.clients {
+		if _, err := resolve(prefs); err != nil {
+			return nil, fmt.Errorf("output.clients.%s: %w", client, err)
+		}
+	}
+	return f, nil
+}
+
+// Preferences returns the preferences of a session: the session's own over its client's over the defaults
+func (f *Formatter) Preferences(session *mcp.ServerSession) Preferences {
+	prefs := f.defaults
+	var client string
+	if session != nil {
+		if params := session.InitializeParams(); params != nil && params.ClientInfo != nil {
+			client = params.ClientInfo.Name
+		}
+		if clientPrefs, ok := f.clients[client]; ok {
+			prefs = merge(prefs, clientPrefs)
+		} else {
+			client = ""
+		}
+		f.mu.Lock()
+		if sessionPrefs, ok := f.sessions[session]; ok {
+			prefs = merge(prefs, sessionPrefs)
+		}
+		f.mu.Unlock()
+	}
+
+	// Validated when they were configured or set
+	resolved, _ := resolve(prefs)
+	resolved.Client = client
+	return resolved
+}
+
+// Set changes preferences of a session on top of the ones it already set
+func (f *Formatter) Set(session *mcp.ServerSession, prefs config.OutputPreferences) error {
+	f.mu.Lock()
+	current, known := f.sessions[session]
+	f.mu.Unlock()
+	merged := merge(current, prefs)
+	if _, err := resolve(merge(f.defaults, merged)); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.sessions[session] = merged
+	f.mu.Unlock()
+	if !known {
+		go func() {
+			session.Wait()
+			f.Reset(session)
+		}()
+	}
+	return nil
+}
+
+// Reset drops the preferences a session set
+func (f *Formatter) Reset(session *mcp.ServerSession) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sessions, session)
+}
+
+// merge returns base with the fields set in override replaced
+func merge(base, override config.OutputPreferences) config.OutputPreferences {
+	if override.Format != "" {
+		base.Format = override.Format
+	}
+	if override.Emoji != nil {
+		base.Emoji = override.Emoji
+	}
+	if override.MaxRows != 0 {
+		base.MaxRows = override.MaxRows
+	}
+	if override.DateFormat != "" {
+		base.DateFormat = override.DateFormat
+	}
+	if override.Timezone != "" {
+		base.Timezone = override.Timezone
+	}
+	if override.MaxResultBytes != 0 {
+		base.MaxResultBytes = override.MaxResultBytes
+	}
+	return base
+}
+
+// resolve validates preferences and fills in the defaults. A negative max_rows or max_result_bytes turns
+// the limit off, so a session can undo a configured limit.
+func resolve(prefs config.OutputPreferences) (Preferences, error) {
+	resolved := Preferences{
+		Format:     prefs.Format,
+		Emoji:      prefs.Emoji == nil || *prefs.Emoji,
+		MaxRows:    max(prefs.MaxRows, 0),
+		DateFormat: prefs.DateFormat,
+		Timezone:   prefs.Timezone,
+
+		MaxResultBytes: max(prefs.MaxResultBytes, 0),
+	}
+	if prefs.MaxResultBytes == 0 {
+		resolved.MaxResultBytes = defaultMaxResultBytes
+	}
+	switch resolved.Format {
+	case "":
+		resolved.Format = FormatPretty
+	case FormatPretty, FormatJSON, FormatText:
+	default:
+		return Preferences{}, fmt.Errorf("unknown format %q (use pretty, json or text)", prefs.Format)
+	}
+	switch resolved.DateFormat {
+	case "":
+		resolved.DateFormat = DateRFC3339
+	case DateRFC3339, DateUnix, DateUnixMs:
+	default:
+		// A Go layout must render a reference time differently from the layout itself
+		if time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(resolved.DateFormat) == resolved.DateFormat {
+			return Preferences{}, fmt.Errorf("date_format %q is not rfc3339, unix, unix_ms or a Go layout like 2006-01-02 15:04:05", prefs.DateFormat)
+		}
+	}
+	if resolved.Timezone != "" {
+		location, err := time.LoadLocation(resolved.Timezone)
+		if err != nil {
+			return Preferences{}, fmt.Errorf("unknown timezone %q", prefs.Timezone)
+		}
+		resolved.location = location
+	}
+	return resolved, nil
+}
+
+// Middleware returns MCP receiving middleware that renders the result of every tools/call according
+// to the preferences of the calling session, and spills results that are still too large to a file
+func (f *Formatter) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || err != nil {
+				return result, err
+			}
+			if toolResult, ok := result.(*mcp.CallToolResult); ok {
+				prefs := f.Preferences(callReq.Session)
+				f.Format(prefs, toolResult)
+				if err := f.Spill(prefs, callReq.Params.Name, toolResult); err != nil {
+					logging.ServerLogger.Error("failed to spill oversized result",
+						logging.String("tool", callReq.Params.Name), logging.Error(err))
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// Format renders the text content of a result in place. JSON text is reformatted, its lists cut to
+// max_rows and its timestamps converted; a note is appended when lists were cut.
+func (f *Formatter) Format(prefs Preferences, result *mcp.CallToolResult) {
+	var notes []string
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if !prefs.Emoji {
+			text.Text = stripEmoji(text.Text)
+		}
+		if result.IsError {
+			continue
+		}
+		rendered, cut, ok := renderJSON(text.Text, prefs)
+		if ok {
+			text.Text = rendered
+			notes = append(notes, cut...)
+		}
+	}
+	if len(notes) > 0 {
+		result.Content = append(result.Content, &mcp.TextContent{
+			Text: fmt.Sprintf("Showing the first %d items of %s; set max_rows with output_preferences to see more", prefs.MaxRows, strings.Join(notes, ", ")),
+		})
+	}
+}
+
+// stripEmoji removes emoji, with their variation selectors and the space after them
+func stripEmoji(text string) string {
+	if !strings.ContainsFunc(text, isEmoji) {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if !isEmoji(runes[i]) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		for i+1 < len(runes) && (isEmoji(runes[i+1]) || runes[i+1] == '\uFE0F' || runes[i+1] == '\u200D') {
+			i++
+		}
+		if i+1 < len(runes) && runes[i+1] == ' ' {
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, transport, supplemental symbols
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF, r == 0x231A, r == 0x231B, r == 0x23F0, r == 0x23F3:
+		return true
+	}
+	return false
+}
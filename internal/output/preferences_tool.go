@@ -0,0 +1,115 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+)
+
+// PreferencesTool creates the output_preferences tool, which shows and changes the preferences of the calling session
+func (f *Formatter) PreferencesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "output_preferences",
+		Description: "Show or change how tool results are rendered for this session: pretty (indented JSON), json (compact, for parsers) or text (YAML-like), emoji on or off, how many items of each list to show, the format and timezone of timestamps, and the size above which results are saved to a results:// resource with a preview. Settings last until the session ends; call without arguments to see the current ones",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"format": {
+					"type": "string",
+					"enum": ["pretty", "json", "text"],
+					"description": "Rendering of JSON results"
+				},
+				"emoji": {
+					"type": "boolean",
+					"description": "Keep emoji in text results"
+				},
+				"max_rows": {
+					"type": "integer",
+					"description": "Items shown per list in JSON results; -1 shows all"
+				},
+				"date_format": {
+					"type": "string",
+					"description": "Timestamp format: rfc3339, unix, unix_ms or a Go layout such as 2006-01-02 15:04:05"
+				},
+				"timezone": {
+					"type": "string",
+					"description": "IANA timezone for timestamps, e.g. UTC or Asia/Shanghai"
+				},
+				"max_result_bytes": {
+					"type": "integer",
+					"description": "Results larger than this are saved to a results:// resource and replaced by a preview; -1 never does"
+				},
+				"reset": {
+					"type": "boolean",
+					"description": "Go back to the configured preferences before applying the other arguments",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Format     string `json:"format,omitempty"`
+			Emoji      *bool  `json:"emoji,omitempty"`
+			MaxRows    int    `json:"max_rows,omitempty"`
+			DateFormat string `json:"date_format,omitempty"`
+			Timezone   string `json:"timezone,omitempty"`
+			Reset      bool   `json:"reset,omitempty"`
+
+			MaxResultBytes int `json:"max_result_bytes,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if req.Session == nil {
+			return createErrorResult(fmt.Errorf("no session to keep preferences for")), nil
+		}
+
+		if args.Reset {
+			f.Reset(req.Session)
+		}
+		prefs := config.OutputPreferences{
+			Format:     args.Format,
+			Emoji:      args.Emoji,
+			MaxRows:    args.MaxRows,
+			DateFormat: args.DateFormat,
+			Timezone:   args.Timezone,
+
+			MaxResultBytes: args.MaxResultBytes,
+		}
+		if prefs != (config.OutputPreferences{}) {
+			if err := f.Set(req.Session, prefs); err != nil {
+				return createErrorResult(err), nil
+			}
+		}
+		return formatJSONResult(f.Preferences(req.Session)), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Output Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
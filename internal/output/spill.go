@@ -0,0 +1,205 @@
+package output
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	// defaultMaxResultBytes is the largest result sent as is unless max_result_bytes is set
+	defaultMaxResultBytes = 1 << 20
+	// defaultPreviewBytes is the text kept in a spilled result unless output.preview_bytes is set
+	defaultPreviewBytes = 4096
+	// defaultSpillRetention is how long spilled results are kept unless output.spill_retention_hours is set
+	defaultSpillRetention = 24 * time.Hour
+
+	// ResultsScheme is the URI scheme of spilled results
+	ResultsScheme = "results://"
+	// SpillMetaKey describes a spilled result in its _meta
+	SpillMetaKey = "dev-mcp/spill"
+)
+
+// spillConfig is where and for how long oversized results are kept
+type spillConfig struct {
+	dir          string
+	retention    time.Duration
+	previewBytes int
+}
+
+func newSpillConfig(cfg *config.OutputConfig) spillConfig {
+	spill := spillConfig{
+		dir:          cfg.SpillDirectory,
+		retention:    time.Duration(cfg.SpillRetentionHours) * time.Hour,
+		previewBytes: cfg.PreviewBytes,
+	}
+	if spill.dir == "" {
+		spill.dir = filepath.Join(os.TempDir(), "dev-mcp-results")
+	}
+	if spill.retention <= 0 {
+		spill.retention = defaultSpillRetention
+	}
+	if spill.previewBytes <= 0 {
+		spill.previewBytes = defaultPreviewBytes
+	}
+	return spill
+}
+
+// SpillInfo describes a spilled result
+type SpillInfo struct {
+	URI   string `json:"uri"`
+	File  string `json:"file"`
+	Bytes int    `json:"bytes"` // Size of the result as it would have been sent
+}
+
+// Spill replaces a result larger than max_result_bytes with a preview of its text and a link to the full
+// text, written to the spill directory and readable as a results:// resource. Clients would otherwise
+// fail on the oversized message. When the text cannot be written, the preview is returned with the error.
+func (f *Formatter) Spill(prefs Preferences, tool string, result *mcp.CallToolResult) error {
+	if prefs.MaxResultBytes <= 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil || len(encoded) <= prefs.MaxResultBytes {
+		return nil
+	}
+
+	var texts []string
+	var kept []mcp.Content
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		} else {
+			kept = append(kept, content)
+		}
+	}
+	text := strings.Join(texts, "\n\n")
+	preview := cutPreview(text, f.spill.previewBytes)
+
+	// Structured content repeats the text, so it goes too
+	result.StructuredContent = nil
+	result.Content = append([]mcp.Content{&mcp.TextContent{Text: preview}}, kept...)
+
+	mimeType, ext := "text/plain", ".txt"
+	if json.Valid([]byte(text)) {
+		mimeType, ext = "application/json", ".json"
+	}
+	info, err := f.writeSpill(tool, ext, text)
+	if err != nil {
+		result.Content = append(result.Content, &mcp.TextContent{
+			Text: fmt.Sprintf("The result was %d bytes, more than max_result_bytes (%d), and could not be saved (%v); showing its first %d bytes. Narrow the request to see the rest",
+				len(encoded), prefs.MaxResultBytes, err, len(preview)),
+		})
+		return err
+	}
+	info.Bytes = len(encoded)
+
+	size := int64(len(text))
+	result.Content = append(result.Content,
+		&mcp.TextContent{
+			Text: fmt.Sprintf("The result was %d bytes, more than max_result_bytes (%d); showing its first %d bytes. The full text is the resource %s (file://%s on the server), kept for %s",
+				info.Bytes, prefs.MaxResultBytes, len(preview), info.URI, filepath.ToSlash(info.File), f.spill.retention),
+		},
+		&mcp.ResourceLink{
+			URI:      info.URI,
+			Name:     strings.TrimPrefix(info.URI, ResultsScheme),
+			Title:    fmt.Sprintf("Full result of %s", tool),
+			MIMEType: mimeType,
+			Size:     &size,
+		},
+	)
+	if result.Meta == nil {
+		result.Meta = mcp.Meta{}
+	}
+	result.Meta[SpillMetaKey] = info
+	return nil
+}
+
+// writeSpill writes the text of a spilled result and removes the expired ones
+func (f *Formatter) writeSpill(tool, ext, text string) (SpillInfo, error) {
+	if err := os.MkdirAll(f.spill.dir, 0o700); err != nil {
+		return SpillInfo{}, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	f.pruneSpills()
+
+	// Unguessable, so one caller cannot read another's results
+	name := tool + "-" + strings.ToLower(rand.Text()) + ext
+	file := filepath.Join(f.spill.dir, name)
+	if err := os.WriteFile(file, []byte(text), 0o600); err != nil {
+		return SpillInfo{}, fmt.Errorf("failed to write spilled result: %w", err)
+	}
+	return SpillInfo{URI: ResultsScheme + name, File: file}, nil
+}
+
+// pruneSpills removes spilled results older than the retention
+func (f *Formatter) pruneSpills() {
+	entries, err := os.ReadDir(f.spill.dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-f.spill.retention)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err == nil && info.Mode().IsRegular() && info.ModTime().Before(cutoff) {
+			_ = os.Remove(filepath.Join(f.spill.dir, entry.Name()))
+		}
+	}
+}
+
+// ResultsTemplate is the resource template of spilled results
+func (f *Formatter) ResultsTemplate() *mcp.ResourceTemplate {
+	return &mcp.ResourceTemplate{
+		URITemplate: ResultsScheme + "{name}",
+		Name:        "Spilled Result",
+		Description: fmt.Sprintf("The full text of a tool result that was too large to return, linked from the result; kept for %s", f.spill.retention),
+	}
+}
+
+// ReadResult serves a spilled result
+func (f *Formatter) ReadResult(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	name, ok := strings.CutPrefix(req.Params.URI, ResultsScheme)
+	if !ok || name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	data, err := os.ReadFile(filepath.Join(f.spill.dir, name))
+	if os.IsNotExist(err) {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spilled result: %w", err)
+	}
+
+	mimeType := "text/plain"
+	if filepath.Ext(name) == ".json" {
+		mimeType = "application/json"
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: mimeType, Text: string(data)}},
+	}, nil
+}
+
+// cutPreview keeps the first lines of text that fit in limit bytes, or the first limit bytes when the
+// first line is longer
+func cutPreview(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	preview := text[:limit]
+	if i := strings.LastIndexByte(preview, '\n'); i > 0 {
+		return preview[:i]
+	}
+	for len(preview) > 0 && !utf8.ValidString(preview) {
+		preview = preview[:len(preview)-1]
+	}
+	return preview
+}
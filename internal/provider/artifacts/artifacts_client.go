@@ -0,0 +1,75 @@
+package artifacts
+
+import (
+	"fmt"
+	"os"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/file"
+)
+
+const (
+	defaultMaxArtifactBytes = 512 << 20
+	// maxStatsBytes bounds bundle stats files, which are read into memory
+	maxStatsBytes = 256 << 20
+)
+
+// ArtifactsClient reads build artifacts from whitelisted directories
+type ArtifactsClient struct {
+	validator *file.FileSecurityValidator
+	maxBytes  int64
+}
+
+// NewArtifactsClient creates a new artifacts client; without directories the working directory is allowed
+func NewArtifactsClient(cfg *config.ArtifactsConfig) *ArtifactsClient {
+	c := &ArtifactsClient{maxBytes: defaultMaxArtifactBytes}
+	var dirs []string
+	if cfg != nil {
+		dirs = cfg.Directories
+		if cfg.MaxArtifactBytes > 0 {
+			c.maxBytes = cfg.MaxArtifactBytes
+		}
+	}
+	c.validator = file.NewFileSecurityValidator(dirs)
+	c.validator.SetReadOnly(true)
+	c.validator.SetMaxFileSize(c.maxBytes)
+	return c
+}
+
+// Close releases resources held by the client
+func (c *ArtifactsClient) Close() error {
+	return nil
+}
+
+// LoadBinary analyzes an executable; it is read in place rather than loaded into memory
+func (c *ArtifactsClient) LoadBinary(path string) (*Binary, error) {
+	resolved, size, err := c.validator.ResolveWhitelisted(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open binary: %w", err)
+	}
+	defer f.Close()
+
+	b, err := AnalyzeBinary(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// LoadBundle analyzes a webpack stats file or esbuild metafile
+func (c *ArtifactsClient) LoadBundle(path string) (*Bundle, error) {
+	data, err := c.validator.ReadWhitelisted(path, maxStatsBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := AnalyzeBundle(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", path, err)
+	}
+	return bundle, nil
+}
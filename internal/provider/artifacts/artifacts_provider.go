@@ -0,0 +1,297 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// ArtifactsProvider reports what makes Go binaries and JavaScript bundles large and how that changed between builds
+type ArtifactsProvider struct {
+	*provider.BaseProvider
+	client *ArtifactsClient
+}
+
+// NewArtifactsProvider creates a new artifacts provider with config
+func NewArtifactsProvider(cfg *config.ArtifactsConfig) *ArtifactsProvider {
+	p := &ArtifactsProvider{
+		BaseProvider: provider.NewBaseProvider("artifacts"),
+		client:       NewArtifactsClient(cfg),
+	}
+
+	// Artifacts are read from local directories (the working directory by default), so the tools are always available
+	p.SetAvailable(true)
+	log.Printf("✓ Artifacts provider initialized successfully")
+
+	return p
+}
+
+// Test tests the artifacts configuration (for ProviderClient interface compatibility)
+func (p *ArtifactsProvider) Test(config interface{}) error {
+	return nil
+}
+
+// AddTools adds artifacts tools to the MCP server (for ProviderClient interface compatibility)
+func (p *ArtifactsProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the artifacts provider
+func (p *ArtifactsProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds artifacts tools to the MCP server
+func (p *ArtifactsProvider) addToolsToServer(server *mcp.Server) {
+	tools := []entity.ToolDefinition{
+		p.createBinarySizeTool(),
+		p.createBundleSizeTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered artifacts tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All artifacts tools registered successfully")
+}
+
+// createBinarySizeTool creates the binary size breakdown tool
+func (p *ArtifactsProvider) createBinarySizeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "binary_size",
+		Description: "Break down the size of a Go executable (ELF, Mach-O or PE) by section, module, package and symbol, like `go tool nm -size -sort size`. Stripped binaries fall back to function sizes from the pclntab. With base_path, diff two builds to see which modules, packages and symbols made the binary grow",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"path": {
+					"type": "string",
+					"description": "Executable to analyze (within the artifacts directories)"
+				},
+				"base_path": {
+					"type": "string",
+					"description": "Earlier build of the same executable to diff against"
+				},
+				"filter": {
+					"type": "string",
+					"description": "Regexp; only count symbols whose name matches, e.g. ^github.com/aws/ or encoding/json"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum entries per list",
+					"default": 20
+				}
+			},
+			"required": ["path"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Path     string `json:"path"`
+			BasePath string `json:"base_path,omitempty"`
+			Filter   string `json:"filter,omitempty"`
+			Limit    int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Path == "" {
+			return p.createErrorResult(fmt.Errorf("path parameter is required")), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = 20
+		}
+		var filter *regexp.Regexp
+		if args.Filter != "" {
+			var err error
+			if filter, err = regexp.Compile(args.Filter); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid filter: %w", err)), nil
+			}
+		}
+
+		binary, err := p.client.LoadBinary(args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		modules, packages, symbols := binarySizes(binary, filter)
+		total := symbols.Total()
+		result := map[string]interface{}{
+			"binary":      binary,
+			"file_size":   formatBytes(binary.FileSize),
+			"symbol_size": formatBytes(total),
+			"modules":     modules.Top(total, args.Limit),
+			"packages":    packages.Top(total, args.Limit),
+			"symbols":     symbols.Top(total, args.Limit),
+		}
+		if binary.Stripped {
+			result["note"] = "binary is stripped: only Go function code is attributed, data and type metadata are not"
+		}
+
+		if args.BasePath != "" {
+			base, err := p.client.LoadBinary(args.BasePath)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("base: %w", err)), nil
+			}
+			baseModules, basePackages, baseSymbols := binarySizes(base, filter)
+			result["diff"] = map[string]interface{}{
+				"file_size": map[string]interface{}{
+					"base":    base.FileSize,
+					"current": binary.FileSize,
+					"delta":   formatBytes(binary.FileSize - base.FileSize),
+				},
+				"symbol_size_delta": formatBytes(total - baseSymbols.Total()),
+				"modules":           modules.Diff(baseModules, args.Limit),
+				"packages":          packages.Diff(basePackages, args.Limit),
+				"symbols":           symbols.Diff(baseSymbols, args.Limit),
+			}
+			if base.GoVersion != binary.GoVersion {
+				result["note"] = fmt.Sprintf("the builds use different Go versions (%s and %s); runtime and standard library sizes are not comparable", base.GoVersion, binary.GoVersion)
+			}
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// binarySizes sums symbol sizes by module, package and symbol name
+func binarySizes(b *Binary, filter *regexp.Regexp) (modules, packages, symbols Sizes) {
+	modules, packages, symbols = Sizes{}, Sizes{}, Sizes{}
+	for _, s := range b.Symbols {
+		if filter != nil && !filter.MatchString(s.Name) {
+			continue
+		}
+		modules[s.Module] += s.Size
+		packages[s.Package] += s.Size
+		symbols[s.Name] += s.Size
+	}
+	return modules, packages, symbols
+}
+
+// createBundleSizeTool creates the JavaScript bundle size breakdown tool
+func (p *ArtifactsProvider) createBundleSizeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "bundle_size",
+		Description: "Break down a JavaScript bundle by npm package, module and output file from a webpack stats file (webpack --json) or an esbuild metafile (--metafile). With base_path, diff two builds to see which packages and modules made the bundle grow",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"path": {
+					"type": "string",
+					"description": "Stats file to analyze, e.g. stats.json or meta.json (within the artifacts directories)"
+				},
+				"base_path": {
+					"type": "string",
+					"description": "Stats file of an earlier build to diff against"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum entries per list",
+					"default": 20
+				}
+			},
+			"required": ["path"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Path     string `json:"path"`
+			BasePath string `json:"base_path,omitempty"`
+			Limit    int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Path == "" {
+			return p.createErrorResult(fmt.Errorf("path parameter is required")), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = 20
+		}
+
+		bundle, err := p.client.LoadBundle(args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		packages, modules, assets := bundleSizes(bundle)
+		total := modules.Total()
+		result := map[string]interface{}{
+			"format":      bundle.Format,
+			"module_size": formatBytes(total),
+			"asset_size":  formatBytes(assets.Total()),
+			"assets":      assets.Top(assets.Total(), args.Limit),
+			"packages":    packages.Top(total, args.Limit),
+			"modules":     modules.Top(total, args.Limit),
+		}
+		if bundle.Format == "webpack" {
+			result["note"] = "webpack module sizes are before minification; compare packages relative to each other"
+		}
+
+		if args.BasePath != "" {
+			base, err := p.client.LoadBundle(args.BasePath)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("base: %w", err)), nil
+			}
+			basePackages, baseModules, baseAssets := bundleSizes(base)
+			result["diff"] = map[string]interface{}{
+				"module_size_delta": formatBytes(total - baseModules.Total()),
+				"asset_size_delta":  formatBytes(assets.Total() - baseAssets.Total()),
+				"assets":            assets.Diff(baseAssets, args.Limit),
+				"packages":          packages.Diff(basePackages, args.Limit),
+				"modules":           modules.Diff(baseModules, args.Limit),
+			}
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// bundleSizes sums module sizes by package and module, and asset sizes by name
+func bundleSizes(b *Bundle) (packages, modules, assets Sizes) {
+	packages, modules, assets = Sizes{}, Sizes{}, Sizes{}
+	for _, m := range b.Modules {
+		packages[m.Package] += m.Size
+		modules[m.Name] += m.Size
+	}
+	for _, a := range b.Assets {
+		assets[a.Name] += a.Size
+	}
+	return packages, modules, assets
+}
+
+func (p *ArtifactsProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Artifacts Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *ArtifactsProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that ArtifactsProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*ArtifactsProvider)(nil)
@@ -0,0 +1,337 @@
+package artifacts
+
+import (
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Symbol is a sized symbol of a binary
+type Symbol struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	Section string `json:"section,omitempty"`
+	Package string `json:"package"`
+	Module  string `json:"module"`
+}
+
+// Section is a section of a binary that occupies file space
+type Section struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// Binary is an analyzed executable
+type Binary struct {
+	Format     string    `json:"format"`
+	Arch       string    `json:"arch"`
+	GoVersion  string    `json:"go_version,omitempty"`
+	MainModule string    `json:"main_module,omitempty"`
+	FileSize   int64     `json:"file_size"`
+	Stripped   bool      `json:"stripped,omitempty"` // Only function sizes from the pclntab are known
+	Sections   []Section `json:"sections"`
+	Symbols    []Symbol  `json:"-"`
+	modules    []string  // Module paths from the build info, longest first
+}
+
+// addrSymbol is a symbol whose size is derived from the next symbol's address
+type addrSymbol struct {
+	name    string
+	addr    uint64
+	section int
+}
+
+// AnalyzeBinary reads the symbols of an ELF, Mach-O or PE executable and attributes them to Go packages and modules
+func AnalyzeBinary(r io.ReaderAt, size int64) (*Binary, error) {
+	header := make([]byte, 4)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read binary header: %w", err)
+	}
+
+	var b *Binary
+	var err error
+	switch {
+	case string(header) == "\x7fELF":
+		b, err = analyzeELF(r)
+	case string(header[:2]) == "MZ":
+		b, err = analyzePE(r)
+	case isMachO(header):
+		b, err = analyzeMachO(r)
+	default:
+		return nil, fmt.Errorf("not an ELF, Mach-O or PE executable")
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.FileSize = size
+
+	if info, err := buildinfo.Read(r); err == nil {
+		b.GoVersion = info.GoVersion
+		b.MainModule = info.Main.Path
+		if info.Main.Path != "" {
+			b.modules = append(b.modules, info.Main.Path)
+		}
+		for _, dep := range info.Deps {
+			b.modules = append(b.modules, dep.Path)
+		}
+		sort.Slice(b.modules, func(i, j int) bool { return len(b.modules[i]) > len(b.modules[j]) })
+	}
+
+	for i := range b.Symbols {
+		b.Symbols[i].Package = symbolPackage(b.Symbols[i].Name)
+		b.Symbols[i].Module = b.moduleOf(b.Symbols[i].Package)
+	}
+	sort.Slice(b.Sections, func(i, j int) bool { return b.Sections[i].Size > b.Sections[j].Size })
+	return b, nil
+}
+
+func isMachO(header []byte) bool {
+	switch string(header) {
+	case "\xfe\xed\xfa\xce", "\xce\xfa\xed\xfe", "\xfe\xed\xfa\xcf", "\xcf\xfa\xed\xfe":
+		return true
+	}
+	return false
+}
+
+func analyzeELF(r io.ReaderAt) (*Binary, error) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ELF: %w", err)
+	}
+	b := &Binary{Format: "elf", Arch: f.Machine.String()}
+	for _, s := range f.Sections {
+		if s.Flags&elf.SHF_ALLOC != 0 && s.Type != elf.SHT_NOBITS && s.Size > 0 {
+			b.Sections = append(b.Sections, Section{Name: s.Name, Size: int64(s.Size)})
+		}
+	}
+
+	symbols, err := f.Symbols()
+	if errors.Is(err, elf.ErrNoSymbols) {
+		return b, b.fromPclntab(elfSection(f, ".gopclntab"), elfSection(f, ".text"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ELF symbols: %w", err)
+	}
+	for _, s := range symbols {
+		if s.Size == 0 || s.Section == elf.SHN_UNDEF || int(s.Section) >= len(f.Sections) {
+			continue
+		}
+		// Zero-initialized data takes memory, not file space
+		section := f.Sections[s.Section]
+		if section.Type == elf.SHT_NOBITS {
+			continue
+		}
+		b.Symbols = append(b.Symbols, Symbol{Name: s.Name, Size: int64(s.Size), Section: section.Name})
+	}
+	return b, nil
+}
+
+func analyzeMachO(r io.ReaderAt) (*Binary, error) {
+	f, err := macho.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Mach-O: %w", err)
+	}
+	b := &Binary{Format: "macho", Arch: f.Cpu.String()}
+	const zerofill = 0x1
+	ends := map[int]uint64{}
+	for i, s := range f.Sections {
+		if s.Flags&0xff == zerofill {
+			continue
+		}
+		ends[i+1] = s.Addr + s.Size
+		if s.Size > 0 {
+			b.Sections = append(b.Sections, Section{Name: s.Seg + "," + s.Name, Size: int64(s.Size)})
+		}
+	}
+
+	if f.Symtab == nil || len(f.Symtab.Syms) == 0 {
+		return b, b.fromPclntab(machoSection(f, "__gopclntab"), machoSection(f, "__text"))
+	}
+	var symbols []addrSymbol
+	for _, s := range f.Symtab.Syms {
+		// Stab debugging entries and undefined symbols have no section of their own
+		if s.Type&0xe0 != 0 || s.Sect == 0 {
+			continue
+		}
+		if _, ok := ends[int(s.Sect)]; !ok {
+			continue
+		}
+		symbols = append(symbols, addrSymbol{name: strings.TrimPrefix(s.Name, "_"), addr: s.Value, section: int(s.Sect)})
+	}
+	b.Symbols = sizeByAddress(symbols, ends, func(section int) string {
+		return f.Sections[section-1].Seg + "," + f.Sections[section-1].Name
+	})
+	return b, nil
+}
+
+func elfSection(f *elf.File, name string) sectionData {
+	if s := f.Section(name); s != nil {
+		return sectionData{data: s.Data, addr: s.Addr}
+	}
+	return sectionData{}
+}
+
+func machoSection(f *macho.File, name string) sectionData {
+	if s := f.Section(name); s != nil {
+		return sectionData{data: s.Data, addr: s.Addr}
+	}
+	return sectionData{}
+}
+
+func analyzePE(r io.ReaderAt) (*Binary, error) {
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PE: %w", err)
+	}
+	b := &Binary{Format: "pe"}
+	switch f.Machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		b.Arch = "amd64"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		b.Arch = "arm64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		b.Arch = "386"
+	default:
+		b.Arch = fmt.Sprintf("0x%x", f.Machine)
+	}
+	ends := map[int]uint64{}
+	for i, s := range f.Sections {
+		if s.Size == 0 || s.Characteristics&pe.IMAGE_SCN_CNT_UNINITIALIZED_DATA != 0 {
+			continue
+		}
+		// Go places .bss at the end of .data; only the raw (file-backed) part counts
+		ends[i+1] = uint64(min(s.VirtualSize, s.Size))
+		b.Sections = append(b.Sections, Section{Name: s.Name, Size: int64(s.Size)})
+	}
+	if len(f.Symbols) == 0 {
+		b.Stripped = true
+		return b, nil
+	}
+	var symbols []addrSymbol
+	for _, s := range f.Symbols {
+		if _, ok := ends[int(s.SectionNumber)]; !ok {
+			continue
+		}
+		symbols = append(symbols, addrSymbol{name: s.Name, addr: uint64(s.Value), section: int(s.SectionNumber)})
+	}
+	b.Symbols = sizeByAddress(symbols, ends, func(section int) string { return f.Sections[section-1].Name })
+	return b, nil
+}
+
+// sizeByAddress sizes symbols of formats without symbol sizes as the distance to the next symbol in the same section
+func sizeByAddress(symbols []addrSymbol, ends map[int]uint64, sectionName func(int) string) []Symbol {
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].section != symbols[j].section {
+			return symbols[i].section < symbols[j].section
+		}
+		return symbols[i].addr < symbols[j].addr
+	})
+	var sized []Symbol
+	for i, s := range symbols {
+		end := ends[s.section]
+		if i+1 < len(symbols) && symbols[i+1].section == s.section {
+			end = min(symbols[i+1].addr, end)
+		}
+		if end <= s.addr {
+			continue
+		}
+		sized = append(sized, Symbol{Name: s.name, Size: int64(end - s.addr), Section: sectionName(s.section)})
+	}
+	return sized
+}
+
+type sectionData struct {
+	data func() ([]byte, error)
+	addr uint64
+}
+
+// fromPclntab sizes the functions of a stripped Go binary from its pclntab
+func (b *Binary) fromPclntab(pclntab, text sectionData) error {
+	b.Stripped = true
+	if pclntab.data == nil {
+		return nil
+	}
+	data, err := pclntab.data()
+	if err != nil {
+		return fmt.Errorf("failed to read pclntab: %w", err)
+	}
+	symtab, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.addr))
+	if err != nil {
+		return fmt.Errorf("failed to parse pclntab: %w", err)
+	}
+	for _, fn := range symtab.Funcs {
+		if fn.End > fn.Entry {
+			b.Symbols = append(b.Symbols, Symbol{Name: fn.Name, Size: int64(fn.End - fn.Entry), Section: "text"})
+		}
+	}
+	return nil
+}
+
+// linkerRegions are symbols marking the start of linker-generated tables. ELF gives them no size, while
+// Mach-O and PE symbols are sized up to the next symbol, so they account for the unnamed data of the region.
+var linkerRegions = map[string]bool{
+	"runtime.pclntab": true, "runtime.rodata": true, "runtime.types": true, "runtime.noptrdata": true,
+	"runtime.data": true, "runtime.text": true, "runtime.gcdata": true, "runtime.gcbss": true,
+	"runtime.itablink": true, "runtime.typelink": true, "runtime.findfunctab": true, "type:*": true,
+}
+
+// symbolPackage extracts the Go package of a symbol name such as github.com/a/b.(*T).M, type:*a/b.T or go:itab.*a.T,io.Reader.
+// Non-Go symbols are grouped as (c) and linker-generated data as (runtime metadata).
+func symbolPackage(name string) string {
+	if linkerRegions[name] {
+		return "(runtime metadata)"
+	}
+	switch {
+	case strings.HasPrefix(name, "type:"):
+		name = strings.TrimLeft(strings.TrimPrefix(name, "type:"), "*[]")
+		// Unnamed types and linker type data belong to no package
+		for _, prefix := range []string{"func(", "map[", "struct {", "interface {", "chan ", "."} {
+			if strings.HasPrefix(name, prefix) {
+				return "(runtime metadata)"
+			}
+		}
+	case strings.HasPrefix(name, "go:itab."):
+		name = strings.TrimLeft(strings.TrimPrefix(name, "go:itab."), "*")
+	case strings.HasPrefix(name, "go:"):
+		return "(runtime metadata)"
+	}
+
+	// Type arguments may contain other packages' paths
+	if i := strings.IndexByte(name, '['); i > 0 {
+		name = name[:i]
+	}
+	start := strings.LastIndexByte(name, '/') + 1
+	dot := strings.IndexByte(name[start:], '.')
+	if dot <= 0 {
+		return "(c)"
+	}
+	return name[:start+dot]
+}
+
+// moduleOf finds the module providing a package: the longest matching module path,
+// std for the standard library and the main module for package main
+func (b *Binary) moduleOf(pkg string) string {
+	if strings.HasPrefix(pkg, "(") {
+		return pkg
+	}
+	if pkg == "main" && b.MainModule != "" {
+		return b.MainModule
+	}
+	for _, module := range b.modules {
+		if pkg == module || strings.HasPrefix(pkg, module+"/") {
+			return module
+		}
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	if !strings.Contains(first, ".") {
+		return "std"
+	}
+	return "(unknown module)"
+}
@@ -0,0 +1,162 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BundleModule is one source module inside a JavaScript bundle
+type BundleModule struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	Package string `json:"package"`
+}
+
+// BundleAsset is an emitted output file
+type BundleAsset struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// Bundle is an analyzed bundler stats file
+type Bundle struct {
+	Format  string         `json:"format"` // webpack or esbuild
+	Assets  []BundleAsset  `json:"assets"`
+	Modules []BundleModule `json:"-"`
+}
+
+type webpackModule struct {
+	Name    string          `json:"name"`
+	Size    int64           `json:"size"`
+	Modules []webpackModule `json:"modules"`
+}
+
+type webpackStats struct {
+	Assets []struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	} `json:"assets"`
+	Modules  []webpackModule `json:"modules"`
+	Children []webpackStats  `json:"children"`
+}
+
+type esbuildMetafile struct {
+	Inputs map[string]struct {
+		Bytes int64 `json:"bytes"`
+	} `json:"inputs"`
+	Outputs map[string]struct {
+		Bytes  int64 `json:"bytes"`
+		Inputs map[string]struct {
+			BytesInOutput int64 `json:"bytesInOutput"`
+		} `json:"inputs"`
+	} `json:"outputs"`
+}
+
+// AnalyzeBundle parses a webpack stats.json (webpack --json) or an esbuild metafile (--metafile)
+func AnalyzeBundle(data []byte) (*Bundle, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle stats JSON: %w", err)
+	}
+
+	if _, ok := probe["outputs"]; ok {
+		var meta esbuildMetafile
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, fmt.Errorf("failed to parse esbuild metafile: %w", err)
+		}
+		return analyzeEsbuild(&meta), nil
+	}
+	_, hasModules := probe["modules"]
+	_, hasChildren := probe["children"]
+	if !hasModules && !hasChildren {
+		return nil, fmt.Errorf("unrecognized stats file (expected webpack --json output or an esbuild metafile)")
+	}
+	var stats webpackStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse webpack stats: %w", err)
+	}
+	bundle := &Bundle{Format: "webpack"}
+	addWebpackStats(bundle, &stats)
+	if len(bundle.Modules) == 0 {
+		return nil, fmt.Errorf("webpack stats have no modules; generate them with module details (webpack --json or stats: 'normal')")
+	}
+	return bundle, nil
+}
+
+// addWebpackStats collects modules and assets, including those of child compilations
+func addWebpackStats(bundle *Bundle, stats *webpackStats) {
+	for _, asset := range stats.Assets {
+		if strings.HasSuffix(asset.Name, ".map") {
+			continue
+		}
+		bundle.Assets = append(bundle.Assets, BundleAsset{Name: asset.Name, Size: asset.Size})
+	}
+	var walk func(modules []webpackModule)
+	walk = func(modules []webpackModule) {
+		for _, m := range modules {
+			// Concatenated modules list their parts; counting both would double the size
+			if len(m.Modules) > 0 {
+				walk(m.Modules)
+				continue
+			}
+			name := cleanModuleName(m.Name)
+			bundle.Modules = append(bundle.Modules, BundleModule{Name: name, Size: m.Size, Package: modulePackage(name)})
+		}
+	}
+	walk(stats.Modules)
+	for i := range stats.Children {
+		addWebpackStats(bundle, &stats.Children[i])
+	}
+}
+
+// analyzeEsbuild uses the bytes each input contributes to the outputs, which reflect minification and tree shaking
+func analyzeEsbuild(meta *esbuildMetafile) *Bundle {
+	bundle := &Bundle{Format: "esbuild"}
+	sizes := map[string]int64{}
+	for name, output := range meta.Outputs {
+		// Source maps repeat the code they describe
+		if strings.HasSuffix(name, ".map") {
+			continue
+		}
+		bundle.Assets = append(bundle.Assets, BundleAsset{Name: name, Size: output.Bytes})
+		for input, contribution := range output.Inputs {
+			sizes[input] += contribution.BytesInOutput
+		}
+	}
+	if len(sizes) == 0 {
+		for input, info := range meta.Inputs {
+			sizes[input] = info.Bytes
+		}
+	}
+	for name, size := range sizes {
+		bundle.Modules = append(bundle.Modules, BundleModule{Name: name, Size: size, Package: modulePackage(name)})
+	}
+	sort.Slice(bundle.Assets, func(i, j int) bool { return bundle.Assets[i].Size > bundle.Assets[j].Size })
+	return bundle
+}
+
+// cleanModuleName drops loader prefixes, webpack's "+ N modules" suffix and the leading ./ from a module name
+func cleanModuleName(name string) string {
+	if i := strings.LastIndex(name, "!"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, " + "); i > 0 {
+		name = name[:i]
+	}
+	return strings.TrimPrefix(strings.TrimSpace(name), "./")
+}
+
+// modulePackage returns the npm package of a module path (the part after the last node_modules/), or (app)
+func modulePackage(name string) string {
+	i := strings.LastIndex(name, "node_modules/")
+	if i < 0 {
+		return "(app)"
+	}
+	parts := strings.SplitN(name[i+len("node_modules/"):], "/", 3)
+	if strings.HasPrefix(parts[0], "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
@@ -0,0 +1,118 @@
+package artifacts
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SizeEntry is a named share of an artifact's size
+type SizeEntry struct {
+	Name    string  `json:"name"`
+	Size    int64   `json:"size"`
+	Human   string  `json:"size_human"`
+	Percent float64 `json:"percent"`
+}
+
+// SizeDelta is the change of a named share between a base and a current artifact
+type SizeDelta struct {
+	Name    string `json:"name"`
+	Base    int64  `json:"base"`
+	Current int64  `json:"current"`
+	Delta   int64  `json:"delta"`
+	Human   string `json:"delta_human"`
+	Status  string `json:"status"` // added, removed, grown or shrunk
+}
+
+// Sizes sums sizes by name
+type Sizes map[string]int64
+
+// Total returns the sum of all sizes
+func (s Sizes) Total() int64 {
+	var total int64
+	for _, size := range s {
+		total += size
+	}
+	return total
+}
+
+// Top returns the largest entries with their share of total
+func (s Sizes) Top(total int64, limit int) []SizeEntry {
+	entries := make([]SizeEntry, 0, len(s))
+	for name, size := range s {
+		entries = append(entries, SizeEntry{Name: name, Size: size, Human: formatBytes(size), Percent: share(size, total)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Size != entries[j].Size {
+			return entries[i].Size > entries[j].Size
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries[:min(limit, len(entries))]
+}
+
+// Diff returns the entries that changed against base, largest change first
+func (s Sizes) Diff(base Sizes, limit int) []SizeDelta {
+	var deltas []SizeDelta
+	add := func(name string, before, after int64) {
+		d := SizeDelta{Name: name, Base: before, Current: after, Delta: after - before, Human: formatBytes(after - before)}
+		switch {
+		case before == 0:
+			d.Status = "added"
+		case after == 0:
+			d.Status = "removed"
+		case after > before:
+			d.Status = "grown"
+		default:
+			d.Status = "shrunk"
+		}
+		deltas = append(deltas, d)
+	}
+	for name, after := range s {
+		if before := base[name]; before != after {
+			add(name, before, after)
+		}
+	}
+	for name, before := range base {
+		if _, ok := s[name]; !ok && before != 0 {
+			add(name, before, 0)
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if abs(deltas[i].Delta) != abs(deltas[j].Delta) {
+			return abs(deltas[i].Delta) > abs(deltas[j].Delta)
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+	return deltas[:min(limit, len(deltas))]
+}
+
+func share(size, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(size*10000/total) / 100
+}
+
+func formatBytes(value int64) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	const unit = 1024
+	if value < unit {
+		return fmt.Sprintf("%s%dB", sign, value)
+	}
+	div, exp := int64(unit), 0
+	for n := value / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%s%.2f%cB", sign, float64(value)/float64(div), "KMGTPE"[exp])
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
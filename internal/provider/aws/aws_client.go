@@ -0,0 +1,461 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+// Instance is an EC2 instance summary
+type Instance struct {
+	InstanceID       string            `json:"instance_id"`
+	Name             string            `json:"name,omitempty"`
+	Type             string            `json:"type"`
+	State            string            `json:"state"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	PrivateIP        string            `json:"private_ip,omitempty"`
+	PublicIP         string            `json:"public_ip,omitempty"`
+	ImageID          string            `json:"image_id,omitempty"`
+	VpcID            string            `json:"vpc_id,omitempty"`
+	SubnetID         string            `json:"subnet_id,omitempty"`
+	SecurityGroups   []string          `json:"security_groups,omitempty"`
+	IAMProfile       string            `json:"iam_instance_profile,omitempty"`
+	LaunchTime       *time.Time        `json:"launch_time,omitempty"`
+	StateReason      string            `json:"state_reason,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+// DBInstance is an RDS instance summary
+type DBInstance struct {
+	Identifier         string     `json:"identifier"`
+	Engine             string     `json:"engine"`
+	EngineVersion      string     `json:"engine_version"`
+	Class              string     `json:"class"`
+	Status             string     `json:"status"`
+	Endpoint           string     `json:"endpoint,omitempty"`
+	Port               int32      `json:"port,omitempty"`
+	MultiAZ            bool       `json:"multi_az"`
+	AvailabilityZone   string     `json:"availability_zone,omitempty"`
+	StorageType        string     `json:"storage_type,omitempty"`
+	AllocatedStorageGB int32      `json:"allocated_storage_gb,omitempty"`
+	ReadReplicaOf      string     `json:"read_replica_of,omitempty"`
+	ReadReplicas       []string   `json:"read_replicas,omitempty"`
+	ParameterGroups    []string   `json:"parameter_groups,omitempty"`
+	PendingChanges     bool       `json:"pending_modifications"`
+	CreatedAt          *time.Time `json:"created_at,omitempty"`
+	LatestRestorable   *time.Time `json:"latest_restorable_time,omitempty"`
+}
+
+// DBEvent is an RDS event
+type DBEvent struct {
+	Time       *time.Time `json:"time,omitempty"`
+	SourceID   string     `json:"source_id"`
+	SourceType string     `json:"source_type"`
+	Categories []string   `json:"categories,omitempty"`
+	Message    string     `json:"message"`
+}
+
+// FunctionInfo is a Lambda function configuration. Environment variable values are never returned.
+type FunctionInfo struct {
+	Name                string   `json:"name"`
+	ARN                 string   `json:"arn,omitempty"`
+	Runtime             string   `json:"runtime,omitempty"`
+	Handler             string   `json:"handler,omitempty"`
+	PackageType         string   `json:"package_type,omitempty"`
+	MemoryMB            int32    `json:"memory_mb,omitempty"`
+	TimeoutSeconds      int32    `json:"timeout_seconds,omitempty"`
+	Architectures       []string `json:"architectures,omitempty"`
+	Version             string   `json:"version,omitempty"`
+	State               string   `json:"state,omitempty"`
+	StateReason         string   `json:"state_reason,omitempty"`
+	LastUpdate          string   `json:"last_update_status,omitempty"`
+	LastModified        string   `json:"last_modified,omitempty"`
+	Role                string   `json:"role,omitempty"`
+	Layers              []string `json:"layers,omitempty"`
+	EnvironmentKeys     []string `json:"environment_keys,omitempty"`
+	VpcSubnets          []string `json:"vpc_subnets,omitempty"`
+	DeadLetterQueue     string   `json:"dead_letter_target,omitempty"`
+	CodeSize            int64    `json:"code_size,omitempty"`
+	ReservedConcurrency *int32   `json:"reserved_concurrency,omitempty"`
+}
+
+// TrailEvent is a CloudTrail management event
+type TrailEvent struct {
+	Time      *time.Time `json:"time,omitempty"`
+	Name      string     `json:"name"`
+	Source    string     `json:"source,omitempty"`
+	Username  string     `json:"username,omitempty"`
+	ReadOnly  string     `json:"read_only,omitempty"`
+	EventID   string     `json:"event_id,omitempty"`
+	Resources []string   `json:"resources,omitempty"`
+}
+
+// AWSClient wraps read-only describe calls against EC2, RDS, Lambda and CloudTrail
+type AWSClient struct {
+	ec2        *ec2.Client
+	rds        *rds.Client
+	lambda     *lambda.Client
+	cloudtrail *cloudtrail.Client
+	region     string
+	available  bool
+	logger     *logging.Logger
+}
+
+// NewAWSClient creates a client from the aws config, reusing s3 settings where aws leaves them empty
+func NewAWSClient(cfg *config.AWSConfig, s3Cfg *config.S3Config) *AWSClient {
+	c := &AWSClient{logger: logging.New("AWSClient")}
+	if cfg == nil || !cfg.Enabled {
+		return c
+	}
+
+	awsConfig, err := LoadConfig(cfg, s3Cfg)
+	if err != nil {
+		c.logger.Error("failed to load AWS configuration", logging.Error(err))
+		return c
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := awsConfig.Credentials.Retrieve(ctx); err != nil {
+		c.logger.Error("no AWS credentials available", logging.Error(err))
+		return c
+	}
+
+	c.ec2 = ec2.NewFromConfig(awsConfig)
+	c.rds = rds.NewFromConfig(awsConfig)
+	c.lambda = lambda.NewFromConfig(awsConfig)
+	c.cloudtrail = cloudtrail.NewFromConfig(awsConfig)
+	c.region = awsConfig.Region
+	c.available = true
+	return c
+}
+
+// LoadConfig resolves region and credentials: explicit aws keys, the named profile, the s3 keys
+// when the s3 section points at AWS itself, then the default credential chain (env, instance role)
+func LoadConfig(cfg *config.AWSConfig, s3Cfg *config.S3Config) (awssdk.Config, error) {
+	region := cfg.Region
+	if region == "" && s3Cfg != nil {
+		region = s3Cfg.Region
+	}
+	if region == "" {
+		return awssdk.Config{}, fmt.Errorf("no AWS region configured")
+	}
+
+	opts := []func(*awscfg.LoadOptions) error{awscfg.WithRegion(region)}
+	switch {
+	case cfg.AccessKey != "":
+		opts = append(opts, awscfg.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, cfg.SessionToken)))
+	case cfg.Profile != "":
+		opts = append(opts, awscfg.WithSharedConfigProfile(cfg.Profile))
+	case s3Cfg != nil && s3Cfg.AccessKey != "" && isAWSEndpoint(s3Cfg.Endpoint):
+		opts = append(opts, awscfg.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(s3Cfg.AccessKey, s3Cfg.SecretKey, "")))
+	}
+
+	return awscfg.LoadDefaultConfig(context.TODO(), opts...)
+}
+
+// isAWSEndpoint reports whether an S3 endpoint is AWS rather than an S3-compatible service such as MinIO
+func isAWSEndpoint(endpoint string) bool {
+	return endpoint == "" || strings.Contains(endpoint, ".amazonaws.com")
+}
+
+// IsAvailable checks if the client is available
+func (c *AWSClient) IsAvailable() bool {
+	return c.available
+}
+
+// Region returns the region the client queries
+func (c *AWSClient) Region() string {
+	return c.region
+}
+
+// DescribeInstances lists EC2 instances by ID or by tag/state filters
+func (c *AWSClient) DescribeInstances(ctx context.Context, ids []string, tags map[string]string, state string, limit int) ([]Instance, error) {
+	input := &ec2.DescribeInstancesInput{InstanceIds: ids}
+	for key, value := range tags {
+		input.Filters = append(input.Filters, ec2types.Filter{
+			Name:   awssdk.String("tag:" + key),
+			Values: []string{value},
+		})
+	}
+	if state != "" {
+		input.Filters = append(input.Filters, ec2types.Filter{
+			Name:   awssdk.String("instance-state-name"),
+			Values: []string{state},
+		})
+	}
+
+	var instances []Instance
+	paginator := ec2.NewDescribeInstancesPaginator(c.ec2, input)
+	for paginator.HasMorePages() && len(instances) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, inst := range reservation.Instances {
+				instances = append(instances, instanceSummary(inst))
+			}
+		}
+	}
+
+	if len(instances) > limit {
+		instances = instances[:limit]
+	}
+	return instances, nil
+}
+
+func instanceSummary(inst ec2types.Instance) Instance {
+	out := Instance{
+		InstanceID: awssdk.ToString(inst.InstanceId),
+		Type:       string(inst.InstanceType),
+		PrivateIP:  awssdk.ToString(inst.PrivateIpAddress),
+		PublicIP:   awssdk.ToString(inst.PublicIpAddress),
+		ImageID:    awssdk.ToString(inst.ImageId),
+		VpcID:      awssdk.ToString(inst.VpcId),
+		SubnetID:   awssdk.ToString(inst.SubnetId),
+		LaunchTime: inst.LaunchTime,
+	}
+	if inst.State != nil {
+		out.State = string(inst.State.Name)
+	}
+	if inst.StateReason != nil {
+		out.StateReason = awssdk.ToString(inst.StateReason.Message)
+	}
+	if inst.Placement != nil {
+		out.AvailabilityZone = awssdk.ToString(inst.Placement.AvailabilityZone)
+	}
+	if inst.IamInstanceProfile != nil {
+		out.IAMProfile = awssdk.ToString(inst.IamInstanceProfile.Arn)
+	}
+	for _, sg := range inst.SecurityGroups {
+		out.SecurityGroups = append(out.SecurityGroups, awssdk.ToString(sg.GroupId))
+	}
+	if len(inst.Tags) > 0 {
+		out.Tags = make(map[string]string, len(inst.Tags))
+		for _, tag := range inst.Tags {
+			out.Tags[awssdk.ToString(tag.Key)] = awssdk.ToString(tag.Value)
+		}
+		out.Name = out.Tags["Name"]
+	}
+	return out
+}
+
+// DescribeDBInstances describes one RDS instance, or all of them when identifier is empty
+func (c *AWSClient) DescribeDBInstances(ctx context.Context, identifier string, limit int) ([]DBInstance, error) {
+	input := &rds.DescribeDBInstancesInput{}
+	if identifier != "" {
+		input.DBInstanceIdentifier = awssdk.String(identifier)
+	}
+
+	var instances []DBInstance
+	paginator := rds.NewDescribeDBInstancesPaginator(c.rds, input)
+	for paginator.HasMorePages() && len(instances) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+		}
+		for _, db := range page.DBInstances {
+			instances = append(instances, dbInstanceSummary(db))
+		}
+	}
+
+	if len(instances) > limit {
+		instances = instances[:limit]
+	}
+	return instances, nil
+}
+
+func dbInstanceSummary(db rdstypes.DBInstance) DBInstance {
+	out := DBInstance{
+		Identifier:         awssdk.ToString(db.DBInstanceIdentifier),
+		Engine:             awssdk.ToString(db.Engine),
+		EngineVersion:      awssdk.ToString(db.EngineVersion),
+		Class:              awssdk.ToString(db.DBInstanceClass),
+		Status:             awssdk.ToString(db.DBInstanceStatus),
+		MultiAZ:            awssdk.ToBool(db.MultiAZ),
+		AvailabilityZone:   awssdk.ToString(db.AvailabilityZone),
+		StorageType:        awssdk.ToString(db.StorageType),
+		AllocatedStorageGB: awssdk.ToInt32(db.AllocatedStorage),
+		ReadReplicaOf:      awssdk.ToString(db.ReadReplicaSourceDBInstanceIdentifier),
+		ReadReplicas:       db.ReadReplicaDBInstanceIdentifiers,
+		PendingChanges:     db.PendingModifiedValues != nil && !isEmptyPendingChanges(db.PendingModifiedValues),
+		CreatedAt:          db.InstanceCreateTime,
+		LatestRestorable:   db.LatestRestorableTime,
+	}
+	if db.Endpoint != nil {
+		out.Endpoint = awssdk.ToString(db.Endpoint.Address)
+		out.Port = awssdk.ToInt32(db.Endpoint.Port)
+	}
+	for _, pg := range db.DBParameterGroups {
+		out.ParameterGroups = append(out.ParameterGroups,
+			fmt.Sprintf("%s (%s)", awssdk.ToString(pg.DBParameterGroupName), awssdk.ToString(pg.ParameterApplyStatus)))
+	}
+	return out
+}
+
+func isEmptyPendingChanges(p *rdstypes.PendingModifiedValues) bool {
+	return p.DBInstanceClass == nil && p.EngineVersion == nil && p.AllocatedStorage == nil &&
+		p.MultiAZ == nil && p.StorageType == nil && p.MasterUserPassword == nil && p.BackupRetentionPeriod == nil
+}
+
+// DBEvents returns RDS events for an instance (or all instances) over the last hours (at most 14 days)
+func (c *AWSClient) DBEvents(ctx context.Context, identifier string, hours int) ([]DBEvent, error) {
+	if hours <= 0 {
+		hours = 24
+	}
+	if hours > 14*24 {
+		hours = 14 * 24
+	}
+
+	input := &rds.DescribeEventsInput{
+		SourceType: rdstypes.SourceTypeDbInstance,
+		Duration:   awssdk.Int32(int32(hours * 60)),
+	}
+	if identifier != "" {
+		input.SourceIdentifier = awssdk.String(identifier)
+	}
+
+	var events []DBEvent
+	paginator := rds.NewDescribeEventsPaginator(c.rds, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB events: %w", err)
+		}
+		for _, e := range page.Events {
+			events = append(events, DBEvent{
+				Time:       e.Date,
+				SourceID:   awssdk.ToString(e.SourceIdentifier),
+				SourceType: string(e.SourceType),
+				Categories: e.EventCategories,
+				Message:    awssdk.ToString(e.Message),
+			})
+		}
+	}
+	return events, nil
+}
+
+// FunctionConfiguration returns a Lambda function's configuration without environment values
+func (c *AWSClient) FunctionConfiguration(ctx context.Context, name, qualifier string) (*FunctionInfo, error) {
+	input := &lambda.GetFunctionConfigurationInput{FunctionName: awssdk.String(name)}
+	if qualifier != "" {
+		input.Qualifier = awssdk.String(qualifier)
+	}
+
+	fn, err := c.lambda.GetFunctionConfiguration(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get function configuration: %w", err)
+	}
+
+	info := &FunctionInfo{
+		Name:           awssdk.ToString(fn.FunctionName),
+		ARN:            awssdk.ToString(fn.FunctionArn),
+		Runtime:        string(fn.Runtime),
+		Handler:        awssdk.ToString(fn.Handler),
+		PackageType:    string(fn.PackageType),
+		MemoryMB:       awssdk.ToInt32(fn.MemorySize),
+		TimeoutSeconds: awssdk.ToInt32(fn.Timeout),
+		Version:        awssdk.ToString(fn.Version),
+		State:          string(fn.State),
+		StateReason:    awssdk.ToString(fn.StateReason),
+		LastUpdate:     string(fn.LastUpdateStatus),
+		LastModified:   awssdk.ToString(fn.LastModified),
+		Role:           awssdk.ToString(fn.Role),
+		CodeSize:       fn.CodeSize,
+	}
+	for _, arch := range fn.Architectures {
+		info.Architectures = append(info.Architectures, string(arch))
+	}
+	for _, layer := range fn.Layers {
+		info.Layers = append(info.Layers, awssdk.ToString(layer.Arn))
+	}
+	if fn.Environment != nil {
+		for key := range fn.Environment.Variables {
+			info.EnvironmentKeys = append(info.EnvironmentKeys, key)
+		}
+	}
+	if fn.VpcConfig != nil {
+		info.VpcSubnets = fn.VpcConfig.SubnetIds
+	}
+	if fn.DeadLetterConfig != nil {
+		info.DeadLetterQueue = awssdk.ToString(fn.DeadLetterConfig.TargetArn)
+	}
+
+	concurrency, err := c.lambda.GetFunctionConcurrency(ctx, &lambda.GetFunctionConcurrencyInput{FunctionName: awssdk.String(name)})
+	if err == nil {
+		info.ReservedConcurrency = concurrency.ReservedConcurrentExecutions
+	}
+	return info, nil
+}
+
+// ResourceEvents looks up recent CloudTrail management events that reference a resource name or ID
+func (c *AWSClient) ResourceEvents(ctx context.Context, resourceName string, hours, limit int, includeReadOnly bool) ([]TrailEvent, error) {
+	if hours <= 0 {
+		hours = 24
+	}
+
+	input := &cloudtrail.LookupEventsInput{
+		LookupAttributes: []cttypes.LookupAttribute{{
+			AttributeKey:   cttypes.LookupAttributeKeyResourceName,
+			AttributeValue: awssdk.String(resourceName),
+		}},
+		StartTime: awssdk.Time(time.Now().Add(-time.Duration(hours) * time.Hour)),
+		EndTime:   awssdk.Time(time.Now()),
+	}
+
+	var events []TrailEvent
+	paginator := cloudtrail.NewLookupEventsPaginator(c.cloudtrail, input)
+	for paginator.HasMorePages() && len(events) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up CloudTrail events: %w", err)
+		}
+		for _, e := range page.Events {
+			readOnly := awssdk.ToString(e.ReadOnly)
+			if !includeReadOnly && readOnly == "true" {
+				continue
+			}
+			event := TrailEvent{
+				Time:     e.EventTime,
+				Name:     awssdk.ToString(e.EventName),
+				Source:   awssdk.ToString(e.EventSource),
+				Username: awssdk.ToString(e.Username),
+				ReadOnly: readOnly,
+				EventID:  awssdk.ToString(e.EventId),
+			}
+			for _, r := range e.Resources {
+				event.Resources = append(event.Resources,
+					fmt.Sprintf("%s %s", awssdk.ToString(r.ResourceType), awssdk.ToString(r.ResourceName)))
+			}
+			events = append(events, event)
+		}
+	}
+
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// Close closes the AWS client
+func (c *AWSClient) Close() error {
+	return nil
+}
@@ -0,0 +1,355 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// AWSProvider exposes read-only describe tools for EC2, RDS, Lambda and CloudTrail.
+// Only Describe/Get/Lookup calls are made; the credentials should be limited to a read-only IAM policy.
+type AWSProvider struct {
+	*provider.BaseProvider
+	client *AWSClient
+}
+
+// NewAWSProvider creates a new AWS provider with config
+func NewAWSProvider(cfg *config.AWSConfig, s3Cfg *config.S3Config) *AWSProvider {
+	p := &AWSProvider{
+		BaseProvider: provider.NewBaseProvider("aws"),
+		client:       NewAWSClient(cfg, s3Cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "AWS describe tools not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ AWS provider initialized successfully (region %s)", p.client.Region())
+
+	return p
+}
+
+// Test tests the AWS configuration (for ProviderClient interface compatibility)
+func (p *AWSProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("aws provider not available")
+	}
+	return nil
+}
+
+// AddTools adds AWS tools to the MCP server (for ProviderClient interface compatibility)
+func (p *AWSProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the AWS provider
+func (p *AWSProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds AWS tools to the MCP server
+func (p *AWSProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ AWS provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createEC2InstancesTool().Tool, p.createEC2InstancesTool().Handler},
+		{p.createRDSInstancesTool().Tool, p.createRDSInstancesTool().Handler},
+		{p.createLambdaFunctionTool().Tool, p.createLambdaFunctionTool().Handler},
+		{p.createCloudTrailEventsTool().Tool, p.createCloudTrailEventsTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered AWS tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All AWS tools registered successfully")
+}
+
+// createEC2InstancesTool creates the EC2 describe tool
+func (p *AWSProvider) createEC2InstancesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "aws_ec2_instances",
+		Description: "Describe EC2 instances by ID or by tag and state: type, state, IPs, placement, security groups and tags",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"instance_ids": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Instance IDs to describe"
+				},
+				"tags": {
+					"type": "object",
+					"additionalProperties": {"type": "string"},
+					"description": "Tag filters, e.g. {\"Name\": \"api-*\", \"env\": \"prod\"}"
+				},
+				"state": {
+					"type": "string",
+					"description": "Instance state filter",
+					"enum": ["pending", "running", "shutting-down", "terminated", "stopping", "stopped"]
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of instances to return",
+					"default": 50
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			InstanceIDs []string          `json:"instance_ids,omitempty"`
+			Tags        map[string]string `json:"tags,omitempty"`
+			State       string            `json:"state,omitempty"`
+			Limit       int               `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Limit <= 0 {
+			args.Limit = 50
+		}
+
+		instances, err := p.client.DescribeInstances(ctx, args.InstanceIDs, args.Tags, args.State, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"region":    p.client.Region(),
+			"instances": instances,
+			"count":     len(instances),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createRDSInstancesTool creates the RDS describe tool
+func (p *AWSProvider) createRDSInstancesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "aws_rds_instances",
+		Description: "Describe RDS instances (engine, class, status, endpoint, replicas, pending changes) and their recent events",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"identifier": {
+					"type": "string",
+					"description": "DB instance identifier; omit to list all instances"
+				},
+				"include_events": {
+					"type": "boolean",
+					"description": "Include RDS events (failovers, reboots, maintenance, backups)",
+					"default": true
+				},
+				"event_hours": {
+					"type": "integer",
+					"description": "How many hours of events to return (max 336)",
+					"default": 24
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of instances to return",
+					"default": 50
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Identifier    string `json:"identifier,omitempty"`
+			IncludeEvents bool   `json:"include_events"`
+			EventHours    int    `json:"event_hours,omitempty"`
+			Limit         int    `json:"limit,omitempty"`
+		}{IncludeEvents: true}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Limit <= 0 {
+			args.Limit = 50
+		}
+
+		instances, err := p.client.DescribeDBInstances(ctx, args.Identifier, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"region":    p.client.Region(),
+			"instances": instances,
+			"count":     len(instances),
+		}
+
+		if args.IncludeEvents {
+			events, err := p.client.DBEvents(ctx, args.Identifier, args.EventHours)
+			if err != nil {
+				result["events_error"] = err.Error()
+			} else {
+				result["events"] = events
+			}
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createLambdaFunctionTool creates the Lambda configuration tool
+func (p *AWSProvider) createLambdaFunctionTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "aws_lambda_function",
+		Description: "Get a Lambda function's configuration: runtime, memory, timeout, state, role, layers, VPC and reserved concurrency. Environment variable names are listed, values are not",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"function_name": {
+					"type": "string",
+					"description": "Function name or ARN"
+				},
+				"qualifier": {
+					"type": "string",
+					"description": "Version or alias; defaults to $LATEST"
+				}
+			},
+			"required": ["function_name"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			FunctionName string `json:"function_name"`
+			Qualifier    string `json:"qualifier,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.FunctionName == "" {
+			return p.createErrorResult(fmt.Errorf("function_name parameter is required")), nil
+		}
+
+		info, err := p.client.FunctionConfiguration(ctx, args.FunctionName, args.Qualifier)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(info), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createCloudTrailEventsTool creates the CloudTrail lookup tool
+func (p *AWSProvider) createCloudTrailEventsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "aws_cloudtrail_events",
+		Description: "Look up recent CloudTrail management events for a resource (who changed it, when, and with which API call)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"resource_name": {
+					"type": "string",
+					"description": "Resource name or ID as recorded by CloudTrail (e.g. i-0abc123, my-db, my-function)"
+				},
+				"hours": {
+					"type": "integer",
+					"description": "How far back to look, in hours",
+					"default": 24
+				},
+				"include_read_only": {
+					"type": "boolean",
+					"description": "Also return read-only (Describe/Get/List) events",
+					"default": false
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of events to return",
+					"default": 50
+				}
+			},
+			"required": ["resource_name"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			ResourceName    string `json:"resource_name"`
+			Hours           int    `json:"hours,omitempty"`
+			IncludeReadOnly bool   `json:"include_read_only,omitempty"`
+			Limit           int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.ResourceName == "" {
+			return p.createErrorResult(fmt.Errorf("resource_name parameter is required")), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = 50
+		}
+
+		events, err := p.client.ResourceEvents(ctx, args.ResourceName, args.Hours, args.Limit, args.IncludeReadOnly)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"resource_name": args.ResourceName,
+			"events":        events,
+			"count":         len(events),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *AWSProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("AWS Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *AWSProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that AWSProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*AWSProvider)(nil)
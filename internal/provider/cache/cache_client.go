@@ -0,0 +1,411 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/redis/go-redis/v9"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+const (
+	defaultMaxKeys       = 10000
+	defaultWarmUpTimeout = 10 * time.Second
+	// redisDeleteBatch is the number of keys removed per UNLINK
+	redisDeleteBatch = 500
+	// cloudflarePurgeBatch is the number of URLs or tags Cloudflare accepts per purge request
+	cloudflarePurgeBatch = 30
+	// fastlyKeyBatch is the number of surrogate keys Fastly accepts per purge request
+	fastlyKeyBatch = 256
+)
+
+// Purge is a CDN purge request; Everything purges the whole zone or service
+type Purge struct {
+	URLs       []string `json:"urls,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Everything bool     `json:"everything,omitempty"`
+}
+
+// Empty reports whether the purge names nothing to purge
+func (p Purge) Empty() bool {
+	return len(p.URLs) == 0 && len(p.Tags) == 0 && !p.Everything
+}
+
+// KeyMatch is the result of matching a Redis key pattern
+type KeyMatch struct {
+	Pattern string   `json:"pattern"`
+	Keys    int      `json:"keys"`
+	Sample  []string `json:"sample,omitempty"`
+	Deleted int      `json:"deleted"`
+}
+
+// WarmUpResult is the outcome of one warm-up request
+type WarmUpResult struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Bytes      int    `json:"bytes"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// CacheClient purges a CDN, deletes Redis keys and sends warm-up requests
+type CacheClient struct {
+	config  *config.CacheConfig
+	cdn     *resty.Client
+	redis   *redis.Client
+	warmUp  *resty.Client
+	maxKeys int
+	logger  *logging.Logger
+}
+
+// NewCacheClient creates a cache client; targets that are not configured stay disabled
+func NewCacheClient(cfg *config.CacheConfig) (*CacheClient, error) {
+	c := &CacheClient{
+		config:  cfg,
+		maxKeys: defaultMaxKeys,
+		logger:  logging.New("CacheClient"),
+	}
+	if cfg.Redis.MaxKeys > 0 {
+		c.maxKeys = cfg.Redis.MaxKeys
+	}
+
+	switch cfg.CDN.Type {
+	case "":
+	case "cloudflare":
+		if cfg.CDN.ZoneID == "" || cfg.CDN.Token == "" {
+			return nil, fmt.Errorf("cache.cdn: cloudflare needs zone_id and token")
+		}
+		c.cdn = newHTTPClient("https://api.cloudflare.com/client/v4", 30*time.Second).SetAuthToken(cfg.CDN.Token)
+	case "fastly":
+		if cfg.CDN.ServiceID == "" || cfg.CDN.Token == "" {
+			return nil, fmt.Errorf("cache.cdn: fastly needs service_id and token")
+		}
+		c.cdn = newHTTPClient("https://api.fastly.com", 30*time.Second).SetHeader("Fastly-Key", cfg.CDN.Token)
+	case "http":
+		if cfg.CDN.URL == "" {
+			return nil, fmt.Errorf("cache.cdn: http needs url")
+		}
+		c.cdn = newHTTPClient("", 30*time.Second)
+		if cfg.CDN.Token != "" {
+			c.cdn.SetAuthToken(cfg.CDN.Token)
+		}
+	default:
+		return nil, fmt.Errorf("cache.cdn: unsupported type %q (cloudflare, fastly or http)", cfg.CDN.Type)
+	}
+
+	if cfg.Redis.URL != "" {
+		options, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			return nil, fmt.Errorf("cache.redis: invalid url: %w", err)
+		}
+		c.redis = redis.NewClient(options)
+	}
+
+	names := map[string]bool{}
+	for i, request := range cfg.WarmUp {
+		if request.Name == "" || request.URL == "" {
+			return nil, fmt.Errorf("cache.warm_up[%d]: name and url are required", i)
+		}
+		if names[request.Name] {
+			return nil, fmt.Errorf("cache.warm_up: %s is defined twice", request.Name)
+		}
+		names[request.Name] = true
+		if u, err := url.Parse(request.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("cache.warm_up: %s: url must be http or https", request.Name)
+		}
+	}
+	if len(cfg.WarmUp) > 0 {
+		timeout := defaultWarmUpTimeout
+		if cfg.WarmUpTimeoutSeconds > 0 {
+			timeout = time.Duration(cfg.WarmUpTimeoutSeconds) * time.Second
+		}
+		c.warmUp = newHTTPClient("", timeout)
+	}
+
+	return c, nil
+}
+
+func newHTTPClient(baseURL string, timeout time.Duration) *resty.Client {
+	client := resty.New().
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(timeout)
+	if baseURL != "" {
+		client.SetBaseURL(baseURL)
+	}
+	return client
+}
+
+// HasCDN reports whether a CDN purge API is configured
+func (c *CacheClient) HasCDN() bool {
+	return c.cdn != nil
+}
+
+// HasRedis reports whether a Redis instance is configured
+func (c *CacheClient) HasRedis() bool {
+	return c.redis != nil
+}
+
+// DeleteAllowed reports whether the Redis write gate is open
+func (c *CacheClient) DeleteAllowed() bool {
+	return c.config.Redis.AllowDelete
+}
+
+// WarmUpNames returns the names of the configured warm-up requests
+func (c *CacheClient) WarmUpNames() []string {
+	names := make([]string, 0, len(c.config.WarmUp))
+	for _, request := range c.config.WarmUp {
+		names = append(names, request.Name)
+	}
+	return names
+}
+
+// PurgeCDN sends a purge to the configured CDN and returns the number of purge requests made
+func (c *CacheClient) PurgeCDN(ctx context.Context, purge Purge) (int, error) {
+	if c.cdn == nil {
+		return 0, fmt.Errorf("no CDN is configured (set cache.cdn)")
+	}
+	c.logger.Warn("purging CDN cache",
+		logging.String("type", c.config.CDN.Type),
+		logging.Int("urls", len(purge.URLs)),
+		logging.Int("tags", len(purge.Tags)),
+		logging.String("everything", fmt.Sprint(purge.Everything)))
+
+	switch c.config.CDN.Type {
+	case "cloudflare":
+		return c.purgeCloudflare(ctx, purge)
+	case "fastly":
+		return c.purgeFastly(ctx, purge)
+	default:
+		resp, err := c.cdn.R().SetContext(ctx).SetBody(purge).Post(c.config.CDN.URL)
+		if err != nil {
+			return 0, fmt.Errorf("purge request failed: %w", err)
+		}
+		if resp.IsError() {
+			return 0, fmt.Errorf("purge endpoint returned %s", resp.Status())
+		}
+		return 1, nil
+	}
+}
+
+// purgeCloudflare purges files, cache tags or the whole zone
+func (c *CacheClient) purgeCloudflare(ctx context.Context, purge Purge) (int, error) {
+	var bodies []map[string]interface{}
+	if purge.Everything {
+		bodies = append(bodies, map[string]interface{}{"purge_everything": true})
+	} else {
+		for _, batch := range batches(purge.URLs, cloudflarePurgeBatch) {
+			bodies = append(bodies, map[string]interface{}{"files": batch})
+		}
+		for _, batch := range batches(purge.Tags, cloudflarePurgeBatch) {
+			bodies = append(bodies, map[string]interface{}{"tags": batch})
+		}
+	}
+
+	path := "/zones/" + url.PathEscape(c.config.CDN.ZoneID) + "/purge_cache"
+	for i, body := range bodies {
+		var result struct {
+			Success bool `json:"success"`
+			Errors  []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		resp, err := c.cdn.R().SetContext(ctx).SetBody(body).SetResult(&result).SetError(&result).Post(path)
+		if err != nil {
+			return i, fmt.Errorf("purge request failed: %w", err)
+		}
+		if resp.IsError() || !result.Success {
+			if len(result.Errors) > 0 {
+				return i, fmt.Errorf("cloudflare returned %s: %s", resp.Status(), result.Errors[0].Message)
+			}
+			return i, fmt.Errorf("cloudflare returned %s", resp.Status())
+		}
+	}
+	return len(bodies), nil
+}
+
+// purgeFastly purges single URLs, surrogate keys or the whole service
+func (c *CacheClient) purgeFastly(ctx context.Context, purge Purge) (int, error) {
+	service := "/service/" + url.PathEscape(c.config.CDN.ServiceID)
+	requests := 0
+	send := func(req *resty.Request, path string) error {
+		resp, err := req.SetContext(ctx).Post(path)
+		if err != nil {
+			return fmt.Errorf("purge request failed: %w", err)
+		}
+		if resp.IsError() {
+			return fmt.Errorf("fastly returned %s for %s", resp.Status(), path)
+		}
+		requests++
+		return nil
+	}
+
+	if purge.Everything {
+		err := send(c.cdn.R(), service+"/purge_all")
+		return requests, err
+	}
+	for _, target := range purge.URLs {
+		// A URL is purged by sending the purge to the URL itself
+		resp, err := c.cdn.R().SetContext(ctx).Execute("PURGE", target)
+		if err != nil {
+			return requests, fmt.Errorf("purge of %s failed: %w", target, err)
+		}
+		if resp.IsError() {
+			return requests, fmt.Errorf("fastly returned %s for %s", resp.Status(), target)
+		}
+		requests++
+	}
+	for _, batch := range batches(purge.Tags, fastlyKeyBatch) {
+		if err := send(c.cdn.R().SetHeader("Surrogate-Key", strings.Join(batch, " ")), service+"/purge"); err != nil {
+			return requests, err
+		}
+	}
+	return requests, nil
+}
+
+// CheckPattern verifies that a key pattern is allowed by cache.redis.allowed_patterns; a pattern
+// starting with a wildcard must be listed explicitly
+func (c *CacheClient) CheckPattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty key pattern")
+	}
+	literal := literalPrefix(pattern)
+	allowed := c.config.Redis.AllowedPatterns
+	if len(allowed) == 0 && literal != "" {
+		return nil
+	}
+	for _, entry := range allowed {
+		if entry == pattern {
+			return nil
+		}
+		// "cache:*" allows every pattern starting with "cache:"
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok && literalPrefix(prefix) == prefix && strings.HasPrefix(literal, prefix) {
+			return nil
+		}
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("pattern %q starts with a wildcard; list it in cache.redis.allowed_patterns to allow it", pattern)
+	}
+	return fmt.Errorf("pattern %q is not covered by cache.redis.allowed_patterns (%s)", pattern, strings.Join(allowed, ", "))
+}
+
+// literalPrefix returns the part of a Redis glob before its first special character
+func literalPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// DeleteKeys counts the keys matching a pattern with SCAN and, when del is set and the write gate
+// is open, deletes them with UNLINK. Patterns matching more than cache.redis.max_keys are not deleted.
+func (c *CacheClient) DeleteKeys(ctx context.Context, pattern string, del bool) (*KeyMatch, error) {
+	if c.redis == nil {
+		return nil, fmt.Errorf("no Redis instance is configured (set cache.redis.url)")
+	}
+	if err := c.CheckPattern(pattern); err != nil {
+		return nil, err
+	}
+	if del && !c.config.Redis.AllowDelete {
+		return nil, fmt.Errorf("deleting Redis keys is disabled (set cache.redis.allow_delete to enable)")
+	}
+
+	match := &KeyMatch{Pattern: pattern}
+	var keys []string
+	iter := c.redis.Scan(ctx, 0, pattern, 1000).Iterator()
+	for iter.Next(ctx) {
+		if len(keys) == c.maxKeys {
+			return match, fmt.Errorf("pattern %q matches more than %d keys; nothing was deleted (narrow it or raise cache.redis.max_keys)", pattern, c.maxKeys)
+		}
+		keys = append(keys, iter.Val())
+		match.Keys = len(keys)
+	}
+	if err := iter.Err(); err != nil {
+		return match, fmt.Errorf("failed to scan keys: %w", err)
+	}
+	match.Sample = keys[:min(len(keys), 5)]
+	if !del || len(keys) == 0 {
+		return match, nil
+	}
+
+	c.logger.Warn("deleting Redis keys", logging.String("pattern", pattern), logging.Int("keys", len(keys)))
+	for _, batch := range batches(keys, redisDeleteBatch) {
+		deleted, err := c.redis.Unlink(ctx, batch...).Result()
+		match.Deleted += int(deleted)
+		if err != nil {
+			return match, fmt.Errorf("failed to delete keys: %w", err)
+		}
+	}
+	return match, nil
+}
+
+// WarmUp sends the named warm-up request
+func (c *CacheClient) WarmUp(ctx context.Context, name string) (*WarmUpResult, error) {
+	var request *config.WarmUpRequest
+	for i := range c.config.WarmUp {
+		if c.config.WarmUp[i].Name == name {
+			request = &c.config.WarmUp[i]
+			break
+		}
+	}
+	if request == nil {
+		return nil, fmt.Errorf("unknown warm-up request %q (configured: %s)", name, strings.Join(c.WarmUpNames(), ", "))
+	}
+
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = "GET"
+	}
+	req := c.warmUp.R().SetContext(ctx).SetHeaders(request.Headers)
+	if request.Body != "" {
+		req.SetBody(request.Body)
+	}
+
+	start := time.Now()
+	resp, err := req.Execute(method, request.URL)
+	result := &WarmUpResult{Name: name, URL: request.URL, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		return result, fmt.Errorf("request failed: %w", err)
+	}
+	result.StatusCode = resp.StatusCode()
+	result.Bytes = len(resp.Body())
+
+	if request.ExpectStatus != 0 {
+		if resp.StatusCode() != request.ExpectStatus {
+			return result, fmt.Errorf("expected status %d, got %s", request.ExpectStatus, resp.Status())
+		}
+	} else if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return result, fmt.Errorf("unexpected status %s", resp.Status())
+	}
+	return result, nil
+}
+
+// Ping checks that the Redis instance answers
+func (c *CacheClient) Ping(ctx context.Context) error {
+	if c.redis == nil {
+		return nil
+	}
+	return c.redis.Ping(ctx).Err()
+}
+
+// Close closes the Redis connection
+func (c *CacheClient) Close() error {
+	if c.redis == nil {
+		return nil
+	}
+	return c.redis.Close()
+}
+
+// batches splits items into slices of at most size
+func batches(items []string, size int) [][]string {
+	var result [][]string
+	for start := 0; start < len(items); start += size {
+		result = append(result, items[start:min(start+size, len(items))])
+	}
+	return result
+}
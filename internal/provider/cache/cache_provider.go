@@ -0,0 +1,375 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// ResultCache is dev-mcp's own query result cache, kept by the database provider
+type ResultCache interface {
+	ClearResultCache(connection string) int
+}
+
+// Step is the outcome of one step of an invalidation run
+type Step struct {
+	Step       string      `json:"step"` // result_cache, cdn, redis or warm_up
+	Target     string      `json:"target,omitempty"`
+	Status     string      `json:"status"` // ok, failed, skipped or planned (dry run)
+	Detail     string      `json:"detail,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// CacheProvider invalidates caches after a config change or deploy and warms them up again
+type CacheProvider struct {
+	*provider.BaseProvider
+	client  *CacheClient
+	results ResultCache
+}
+
+// NewCacheProvider creates a new cache provider with config; results may be nil when the database
+// provider is not running
+func NewCacheProvider(cfg *config.CacheConfig, results ResultCache) *CacheProvider {
+	p := &CacheProvider{
+		BaseProvider: provider.NewBaseProvider("cache"),
+		results:      results,
+	}
+
+	client, err := NewCacheClient(cfg)
+	if err != nil {
+		log.Printf("⚠ Cache client initialization failed: %v", err)
+		p.SetStatus(false, "Cache client initialization failed", err)
+		return p
+	}
+	p.client = client
+
+	if results == nil && !client.HasCDN() && !client.HasRedis() && len(cfg.WarmUp) == 0 {
+		p.SetStatus(false, "No cache configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Cache provider initialized successfully (%s)", strings.Join(p.targets(), ", "))
+
+	return p
+}
+
+// targets names the configured steps
+func (p *CacheProvider) targets() []string {
+	var targets []string
+	if p.results != nil {
+		targets = append(targets, "result cache")
+	}
+	if p.client.HasCDN() {
+		targets = append(targets, "cdn")
+	}
+	if p.client.HasRedis() {
+		targets = append(targets, "redis")
+	}
+	if names := p.client.WarmUpNames(); len(names) > 0 {
+		targets = append(targets, fmt.Sprintf("%d warm-up requests", len(names)))
+	}
+	return targets
+}
+
+// Test tests the cache configuration (for ProviderClient interface compatibility)
+func (p *CacheProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("cache provider not available")
+	}
+	return nil
+}
+
+// AddTools adds cache tools to the MCP server (for ProviderClient interface compatibility)
+func (p *CacheProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the cache provider
+func (p *CacheProvider) Close() error {
+	if p.client == nil {
+		return nil
+	}
+	return p.client.Close()
+}
+
+// HealthCheck checks that the Redis instance answers
+func (p *CacheProvider) HealthCheck() error {
+	if p.client == nil {
+		return fmt.Errorf("cache provider not available")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.client.Ping(ctx)
+}
+
+// addToolsToServer adds cache tools to the MCP server
+func (p *CacheProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Cache provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createInvalidateTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered cache tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All cache tools registered successfully")
+}
+
+// createInvalidateTool creates the composite invalidation and warm-up tool
+func (p *CacheProvider) createInvalidateTool() entity.ToolDefinition {
+	warmUp := "none configured"
+	if names := p.client.WarmUpNames(); len(names) > 0 {
+		warmUp = strings.Join(names, ", ")
+	}
+	redisGate := "counted only; deletion is disabled unless cache.redis.allow_delete is set"
+	if p.client.DeleteAllowed() {
+		redisGate = "deleted with UNLINK"
+	}
+
+	tool := &mcp.Tool{
+		Name: "cache_invalidate",
+		Description: "After a config change or deploy, invalidate caches and warm them up again, in order: dev-mcp's own query result cache, " +
+			"a CDN purge (URLs, tags or everything), Redis keys matching patterns, then the configured warm-up requests. " +
+			"Each step's outcome is reported; a failed step does not stop the following ones. Use dry_run to see the plan and matching key counts first",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"result_cache": {
+					"type": "boolean",
+					"description": "Clear dev-mcp's cached database_query results",
+					"default": true
+				},
+				"connection": {
+					"type": "string",
+					"description": "Only clear cached results of this database connection"
+				},
+				"cdn_urls": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Full URLs to purge from the CDN"
+				},
+				"cdn_tags": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Cache tags (Cloudflare) or surrogate keys (Fastly) to purge"
+				},
+				"cdn_purge_everything": {
+					"type": "boolean",
+					"description": "Purge the whole CDN zone or service",
+					"default": false
+				},
+				"redis_patterns": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": %q
+				},
+				"warm_up": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": %q
+				},
+				"skip_warm_up": {
+					"type": "boolean",
+					"description": "Do not send warm-up requests",
+					"default": false
+				},
+				"dry_run": {
+					"type": "boolean",
+					"description": "Only report what would be done, with the number of Redis keys each pattern matches",
+					"default": false
+				}
+			}
+		}`, "Redis key patterns (SCAN MATCH syntax, e.g. cache:product:*); matching keys are "+redisGate,
+			"Names of the warm-up requests to send (default: all; configured: "+warmUp+")")),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			ResultCache        *bool    `json:"result_cache,omitempty"`
+			Connection         string   `json:"connection,omitempty"`
+			CDNURLs            []string `json:"cdn_urls,omitempty"`
+			CDNTags            []string `json:"cdn_tags,omitempty"`
+			CDNPurgeEverything bool     `json:"cdn_purge_everything,omitempty"`
+			RedisPatterns      []string `json:"redis_patterns,omitempty"`
+			WarmUp             []string `json:"warm_up,omitempty"`
+			SkipWarmUp         bool     `json:"skip_warm_up,omitempty"`
+			DryRun             bool     `json:"dry_run,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		purge := Purge{URLs: args.CDNURLs, Tags: args.CDNTags, Everything: args.CDNPurgeEverything}
+		if !purge.Empty() && !p.client.HasCDN() {
+			return p.createErrorResult(fmt.Errorf("no CDN is configured (set cache.cdn)")), nil
+		}
+		if len(args.RedisPatterns) > 0 && !p.client.HasRedis() {
+			return p.createErrorResult(fmt.Errorf("no Redis instance is configured (set cache.redis.url)")), nil
+		}
+		warmUp := args.WarmUp
+		if len(warmUp) == 0 && !args.SkipWarmUp {
+			warmUp = p.client.WarmUpNames()
+		}
+		if args.SkipWarmUp {
+			warmUp = nil
+		}
+
+		var steps []Step
+		run := func(step, target string, do func() (interface{}, string, error)) {
+			start := time.Now()
+			result, detail, err := do()
+			s := Step{Step: step, Target: target, Status: "ok", Detail: detail, Result: result, DurationMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				s.Status = "failed"
+				s.Detail = err.Error()
+			} else if args.DryRun {
+				s.Status = "planned"
+			}
+			steps = append(steps, s)
+		}
+		skip := func(step, target, reason string) {
+			steps = append(steps, Step{Step: step, Target: target, Status: "skipped", Detail: reason})
+		}
+
+		// 1. dev-mcp's own result cache
+		clearResults := args.ResultCache == nil || *args.ResultCache
+		switch {
+		case !clearResults:
+		case p.results == nil:
+			skip("result_cache", args.Connection, "the database provider is not running")
+		default:
+			run("result_cache", args.Connection, func() (interface{}, string, error) {
+				if args.DryRun {
+					return nil, "cached query results would be dropped", nil
+				}
+				cleared := p.results.ClearResultCache(args.Connection)
+				return map[string]int{"cleared": cleared}, fmt.Sprintf("dropped %d cached results", cleared), nil
+			})
+		}
+
+		// 2. CDN
+		if !purge.Empty() {
+			run("cdn", p.cdnTarget(purge), func() (interface{}, string, error) {
+				if args.DryRun {
+					return purge, "", nil
+				}
+				requests, err := p.client.PurgeCDN(ctx, purge)
+				return map[string]int{"purge_requests": requests}, "", err
+			})
+		}
+
+		// 3. Redis, behind the write gate
+		for _, pattern := range args.RedisPatterns {
+			del := !args.DryRun && p.client.DeleteAllowed()
+			run("redis", pattern, func() (interface{}, string, error) {
+				match, err := p.client.DeleteKeys(ctx, pattern, del)
+				detail := ""
+				if err == nil && !del && !args.DryRun {
+					detail = "keys were counted, not deleted: set cache.redis.allow_delete to enable deletion"
+				}
+				return match, detail, err
+			})
+			// With the write gate closed the keys are only counted
+			if last := &steps[len(steps)-1]; !del && !args.DryRun && last.Status == "ok" {
+				last.Status = "skipped"
+			}
+		}
+
+		// 4. Warm-up requests, sent directly to the configured URLs
+		for _, name := range warmUp {
+			run("warm_up", name, func() (interface{}, string, error) {
+				if args.DryRun {
+					return nil, "", p.checkWarmUp(name)
+				}
+				result, err := p.client.WarmUp(ctx, name)
+				return result, "", err
+			})
+		}
+
+		if len(steps) == 0 {
+			return p.createErrorResult(fmt.Errorf("nothing to do: give cdn_urls, cdn_tags, cdn_purge_everything, redis_patterns or warm_up")), nil
+		}
+
+		summary := map[string]int{}
+		for _, s := range steps {
+			summary[s.Status]++
+		}
+		result := map[string]interface{}{
+			"steps":   steps,
+			"summary": summary,
+			"success": summary["failed"] == 0,
+		}
+		if args.DryRun {
+			result["dry_run"] = true
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// cdnTarget describes what a purge covers
+func (p *CacheProvider) cdnTarget(purge Purge) string {
+	if purge.Everything {
+		return "everything"
+	}
+	var parts []string
+	if len(purge.URLs) > 0 {
+		parts = append(parts, fmt.Sprintf("%d urls", len(purge.URLs)))
+	}
+	if len(purge.Tags) > 0 {
+		parts = append(parts, fmt.Sprintf("%d tags", len(purge.Tags)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkWarmUp verifies that a warm-up request is configured
+func (p *CacheProvider) checkWarmUp(name string) error {
+	for _, configured := range p.client.WarmUpNames() {
+		if configured == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown warm-up request %q (configured: %s)", name, strings.Join(p.client.WarmUpNames(), ", "))
+}
+
+func (p *CacheProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Cache Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *CacheProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that CacheProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*CacheProvider)(nil)
@@ -0,0 +1,275 @@
+package calendar
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+const (
+	feedTypeFreeze = "freeze"
+	feedTypeOnCall = "oncall"
+
+	defaultRefresh = 5 * time.Minute
+	// lookahead bounds the search for the next shift or freeze
+	lookahead = 30 * 24 * time.Hour
+)
+
+// OnCallStatus is who is on call for a rotation feed at a point in time
+type OnCallStatus struct {
+	Feed    string       `json:"feed"`
+	Current []Occurrence `json:"current"`
+	Next    *Occurrence  `json:"next,omitempty"`
+}
+
+// FreezeStatus reports whether deploys are frozen at a point in time
+type FreezeStatus struct {
+	Frozen  bool         `json:"frozen"`
+	At      time.Time    `json:"at"`
+	Service string       `json:"service,omitempty"`
+	Active  []Occurrence `json:"active"`
+	Next    *Occurrence  `json:"next,omitempty"`
+}
+
+// cachedFeed is a parsed feed and when it was fetched
+type cachedFeed struct {
+	events    []*Event
+	fetchedAt time.Time
+}
+
+// CalendarClient reads deploy freeze and on-call iCal feeds
+type CalendarClient struct {
+	config   *config.CalendarConfig
+	location *time.Location
+	refresh  time.Duration
+	http     *resty.Client
+	logger   *logging.Logger
+
+	mu    sync.Mutex
+	cache map[string]*cachedFeed
+}
+
+// NewCalendarClient creates a new calendar client from config
+func NewCalendarClient(cfg *config.CalendarConfig) *CalendarClient {
+	c := &CalendarClient{
+		config:   cfg,
+		location: time.UTC,
+		refresh:  defaultRefresh,
+		http: resty.New().
+			SetHeader("User-Agent", "dev-mcp/1.0").
+			SetTimeout(30 * time.Second),
+		logger: logging.New("CalendarClient"),
+		cache:  map[string]*cachedFeed{},
+	}
+
+	if cfg != nil && cfg.Timezone != "" {
+		if loc, err := time.LoadLocation(cfg.Timezone); err == nil {
+			c.location = loc
+		} else {
+			c.logger.Warn("unknown calendar timezone, using UTC", logging.String("timezone", cfg.Timezone))
+		}
+	}
+	if cfg != nil && cfg.RefreshMinutes > 0 {
+		c.refresh = time.Duration(cfg.RefreshMinutes) * time.Minute
+	}
+	return c
+}
+
+// IsAvailable checks if any calendar feed is configured
+func (c *CalendarClient) IsAvailable() bool {
+	return c.config != nil && len(c.config.Feeds) > 0
+}
+
+// Feeds returns the names of the configured feeds of a type
+func (c *CalendarClient) Feeds(feedType string) []string {
+	var names []string
+	for _, feed := range c.config.Feeds {
+		if feed.Type == feedType {
+			names = append(names, feed.Name)
+		}
+	}
+	return names
+}
+
+// Location returns the zone used for floating times and all-day events
+func (c *CalendarClient) Location() *time.Location {
+	return c.location
+}
+
+// OnCall returns the current and next shift of every on-call feed, or of the named feed
+func (c *CalendarClient) OnCall(at time.Time, feedName string) ([]OnCallStatus, error) {
+	feeds, err := c.selectFeeds(feedTypeOnCall, feedName)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []OnCallStatus
+	for _, feed := range feeds {
+		events, err := c.events(feed)
+		if err != nil {
+			return nil, err
+		}
+
+		status := OnCallStatus{Feed: feed.Name, Current: []Occurrence{}}
+		status.Current = append(status.Current, occurrences(feed.Name, events, at, at.Add(time.Second))...)
+		status.Next = nextOccurrence(feed.Name, events, at, nil)
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Freeze reports the freeze windows active at a point in time. Events with categories only
+// freeze the services they list; events without categories freeze everything.
+func (c *CalendarClient) Freeze(at time.Time, service string) (*FreezeStatus, error) {
+	feeds, err := c.selectFeeds(feedTypeFreeze, "")
+	if err != nil {
+		return nil, err
+	}
+
+	applies := func(o Occurrence) bool { return appliesTo(o, service) }
+	status := &FreezeStatus{At: at, Service: service, Active: []Occurrence{}}
+	for _, feed := range feeds {
+		events, err := c.events(feed)
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range occurrences(feed.Name, events, at, at.Add(time.Second)) {
+			if applies(occurrence) {
+				status.Active = append(status.Active, occurrence)
+			}
+		}
+		if next := nextOccurrence(feed.Name, events, at, applies); next != nil {
+			if status.Next == nil || next.Start.Before(status.Next.Start) {
+				status.Next = next
+			}
+		}
+	}
+	status.Frozen = len(status.Active) > 0
+	return status, nil
+}
+
+// appliesTo checks whether a freeze covers a service; an empty service only matches global freezes.
+// A category matches the service name or the last path element of a repository ("org/payments").
+func appliesTo(o Occurrence, service string) bool {
+	if len(o.Categories) == 0 {
+		return true
+	}
+	service = strings.ToLower(strings.TrimSpace(service))
+	if service == "" {
+		return false
+	}
+	short := service[strings.LastIndex(service, "/")+1:]
+	for _, category := range o.Categories {
+		category = strings.ToLower(category)
+		if category == service || category == short {
+			return true
+		}
+	}
+	return false
+}
+
+// selectFeeds returns the configured feeds of a type, optionally narrowed to one name
+func (c *CalendarClient) selectFeeds(feedType, name string) ([]config.CalendarFeedConfig, error) {
+	var feeds []config.CalendarFeedConfig
+	for _, feed := range c.config.Feeds {
+		if feed.Type == feedType && (name == "" || feed.Name == name) {
+			feeds = append(feeds, feed)
+		}
+	}
+	if len(feeds) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("no %s feed named %q (available: %s)", feedType, name, strings.Join(c.Feeds(feedType), ", "))
+		}
+		return nil, fmt.Errorf("no %s calendar feeds configured", feedType)
+	}
+	return feeds, nil
+}
+
+// events returns the parsed events of a feed, refetching when the cached copy is stale.
+// If a refetch fails the stale copy is used so a flaky calendar host doesn't break lookups.
+func (c *CalendarClient) events(feed config.CalendarFeedConfig) ([]*Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.cache[feed.Name]
+	if ok && time.Since(cached.fetchedAt) < c.refresh {
+		return cached.events, nil
+	}
+
+	data, err := c.fetch(feed.URL)
+	if err == nil {
+		var events []*Event
+		if events, err = ParseICal(data, c.location); err == nil {
+			c.cache[feed.Name] = &cachedFeed{events: events, fetchedAt: time.Now()}
+			return events, nil
+		}
+	}
+
+	if ok {
+		c.logger.Warn("calendar refresh failed, using cached feed",
+			logging.String("feed", feed.Name),
+			logging.Error(err))
+		return cached.events, nil
+	}
+	return nil, fmt.Errorf("failed to load calendar feed %s: %w", feed.Name, err)
+}
+
+// fetch reads a feed from an http(s)/webcal URL or a local file
+func (c *CalendarClient) fetch(location string) (string, error) {
+	if strings.HasPrefix(location, "webcal://") {
+		location = "https://" + strings.TrimPrefix(location, "webcal://")
+	}
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	resp, err := c.http.R().Get(location)
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("calendar feed returned %s", resp.Status())
+	}
+	return resp.String(), nil
+}
+
+// occurrences expands the events of a feed overlapping [from, to), ordered by start
+func occurrences(feed string, events []*Event, from, to time.Time) []Occurrence {
+	var result []Occurrence
+	for _, event := range events {
+		for _, occurrence := range event.Occurrences(from, to) {
+			occurrence.Feed = feed
+			result = append(result, occurrence)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result
+}
+
+// nextOccurrence finds the first occurrence starting after at within the lookahead window
+func nextOccurrence(feed string, events []*Event, at time.Time, keep func(Occurrence) bool) *Occurrence {
+	for _, occurrence := range occurrences(feed, events, at, at.Add(lookahead)) {
+		if occurrence.Start.After(at) && (keep == nil || keep(occurrence)) {
+			next := occurrence
+			return &next
+		}
+	}
+	return nil
+}
+
+// Close closes the calendar client
+func (c *CalendarClient) Close() error {
+	return nil
+}
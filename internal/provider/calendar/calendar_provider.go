@@ -0,0 +1,222 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// CalendarProvider answers who is on call and whether deploys are frozen from iCal feeds,
+// and guards risky tools while a freeze is active
+type CalendarProvider struct {
+	*provider.BaseProvider
+	client       *CalendarClient
+	blockedTools []string
+	failOpen     bool // Allow blocked tools when the freeze feeds cannot be read
+}
+
+// NewCalendarProvider creates a new calendar provider with config
+func NewCalendarProvider(cfg *config.CalendarConfig) *CalendarProvider {
+	p := &CalendarProvider{
+		BaseProvider: provider.NewBaseProvider("calendar"),
+		client:       NewCalendarClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Calendar feeds not configured", nil)
+		return p
+	}
+
+	p.blockedTools = cfg.BlockedTools
+	p.failOpen = cfg.FailOpen
+	if len(p.blockedTools) == 0 {
+		p.blockedTools = []string{"cicd_retrigger"}
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Calendar provider initialized successfully (freeze feeds: %d, on-call feeds: %d)",
+		len(p.client.Feeds(feedTypeFreeze)), len(p.client.Feeds(feedTypeOnCall)))
+
+	return p
+}
+
+// Test tests the calendar configuration (for ProviderClient interface compatibility)
+func (p *CalendarProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("calendar provider not available")
+	}
+	return nil
+}
+
+// AddTools adds calendar tools to the MCP server (for ProviderClient interface compatibility)
+func (p *CalendarProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the calendar provider
+func (p *CalendarProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds calendar tools to the MCP server
+func (p *CalendarProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Calendar provider not available, tools not added")
+		return
+	}
+
+	var tools []entity.ToolDefinition
+	if len(p.client.Feeds(feedTypeOnCall)) > 0 {
+		tools = append(tools, p.createCurrentOnCallTool())
+	}
+	if len(p.client.Feeds(feedTypeFreeze)) > 0 {
+		tools = append(tools, p.createDeployFreezeTool())
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered calendar tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All calendar tools registered successfully")
+}
+
+// createCurrentOnCallTool creates the on-call lookup tool
+func (p *CalendarProvider) createCurrentOnCallTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "current_oncall",
+		Description: fmt.Sprintf("Show who is on call now (or at a given time) and the next shift, from the on-call rotation calendars (%s)", strings.Join(p.client.Feeds(feedTypeOnCall), ", ")),
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"rotation": {
+					"type": "string",
+					"description": "On-call feed name; omit for all rotations"
+				},
+				"at": {
+					"type": "string",
+					"description": "RFC 3339 time to check instead of now, e.g. 2024-06-01T09:00:00Z"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Rotation string `json:"rotation,omitempty"`
+			At       string `json:"at,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		at, err := parseAt(args.At)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		statuses, err := p.client.OnCall(at, args.Rotation)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"at":        at.In(p.client.Location()),
+			"rotations": statuses,
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDeployFreezeTool creates the deploy freeze check tool
+func (p *CalendarProvider) createDeployFreezeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "is_deploy_freeze",
+		Description: "Check whether a deploy freeze is in effect now (or at a given time), optionally for one service, and when the next freeze starts. Freeze events with categories only apply to the services they list",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"service": {
+					"type": "string",
+					"description": "Service or repository name; omit to check only company-wide freezes"
+				},
+				"at": {
+					"type": "string",
+					"description": "RFC 3339 time to check instead of now, e.g. 2024-12-24T10:00:00Z"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Service string `json:"service,omitempty"`
+			At      string `json:"at,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		at, err := parseAt(args.At)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		status, err := p.client.Freeze(at, args.Service)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(status), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// parseAt reads an optional RFC 3339 time, defaulting to now
+func parseAt(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid at value %q (use RFC 3339, e.g. 2024-06-01T09:00:00Z)", value)
+	}
+	return at, nil
+}
+
+// Helper functions
+func (p *CalendarProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Calendar Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *CalendarProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that CalendarProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*CalendarProvider)(nil)
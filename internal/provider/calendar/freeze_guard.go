@@ -0,0 +1,103 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/logging"
+)
+
+// serviceArguments are the tool arguments checked against service-scoped freezes
+var serviceArguments = []string{"service", "repo", "repository"}
+
+// FreezeMiddleware returns MCP receiving middleware that rejects calls to calendar.blocked_tools
+// while a deploy freeze is active. A freeze limited to some services only blocks calls whose
+// service/repo argument names one of them. If the freeze feeds cannot be read the call is rejected,
+// since the freeze may be active, unless calendar.fail_open is set.
+func (p *CalendarProvider) FreezeMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" || len(p.client.Feeds(feedTypeFreeze)) == 0 {
+				return next(ctx, method, req)
+			}
+
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || callReq.Params == nil || !p.isBlocked(callReq.Params.Name) {
+				return next(ctx, method, req)
+			}
+
+			services := []string{""}
+			var args map[string]interface{}
+			if err := json.Unmarshal(callReq.Params.Arguments, &args); err == nil {
+				for _, key := range serviceArguments {
+					if value, ok := args[key].(string); ok && value != "" {
+						services = append(services, value)
+					}
+				}
+			}
+
+			// Every service is checked, so an active freeze is reported even when another feed fails
+			now := time.Now()
+			var checkErr error
+			for _, service := range services {
+				status, err := p.client.Freeze(now, service)
+				if err != nil {
+					checkErr = err
+					continue
+				}
+				if status.Frozen {
+					window := status.Active[0]
+					logging.ToolLogger.Warn("tool call blocked by deploy freeze",
+						logging.String("tool", callReq.Params.Name),
+						logging.String("freeze", window.Summary))
+					return &mcp.CallToolResult{
+						Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+							"Deploy Freeze: %s is blocked during %q (%s until %s)",
+							callReq.Params.Name, window.Summary, window.Feed,
+							window.End.In(p.client.Location()).Format(time.RFC3339))}},
+						IsError: true,
+					}, nil
+				}
+			}
+			if checkErr != nil {
+				if p.failOpen {
+					logging.ToolLogger.Warn("deploy freeze check failed, allowing call",
+						logging.String("tool", callReq.Params.Name),
+						logging.Error(checkErr))
+					return next(ctx, method, req)
+				}
+				logging.ToolLogger.Warn("deploy freeze check failed, blocking call",
+					logging.String("tool", callReq.Params.Name),
+					logging.Error(checkErr))
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+						"Deploy Freeze: %s is blocked because the freeze status is unknown (%v); retry once the freeze calendar can be read",
+						callReq.Params.Name, checkErr)}},
+					IsError: true,
+				}, nil
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// isBlocked checks a tool name against the blocked tool names and globs
+func (p *CalendarProvider) isBlocked(toolName string) bool {
+	for _, pattern := range p.blockedTools {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == toolName {
+			return true
+		}
+		if matched, err := path.Match(pattern, toolName); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
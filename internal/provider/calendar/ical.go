@@ -0,0 +1,362 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Event is a VEVENT from an iCal feed; recurring events carry their recurrence set
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	Attendees   []string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+
+	recurrence   *rrule.Set
+	recurrenceID time.Time
+	cancelled    bool
+}
+
+// Occurrence is a single concrete instance of an event
+type Occurrence struct {
+	Feed        string    `json:"feed"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+	Categories  []string  `json:"categories,omitempty"`
+	Attendees   []string  `json:"attendees,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AllDay      bool      `json:"all_day,omitempty"`
+}
+
+// icalLine is an unfolded content line: NAME;PARAM=VALUE:value
+type icalLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// ParseICal parses the VEVENTs of an iCal document. Floating times and all-day dates are read in loc.
+// Modified instances (RECURRENCE-ID) replace the occurrence of their recurring event.
+func ParseICal(data string, loc *time.Location) ([]*Event, error) {
+	var (
+		events  []*Event
+		current *Event
+		rule    string
+		exdates []time.Time
+		rdates  []time.Time
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(unfold(data)))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line, ok := parseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		switch {
+		case line.name == "BEGIN" && strings.EqualFold(line.value, "VEVENT"):
+			current = &Event{}
+			rule, exdates, rdates = "", nil, nil
+			continue
+		case line.name == "END" && strings.EqualFold(line.value, "VEVENT"):
+			if current == nil {
+				continue
+			}
+			if current.Start.IsZero() {
+				current = nil
+				continue
+			}
+			if current.End.IsZero() || current.End.Before(current.Start) {
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				} else {
+					current.End = current.Start
+				}
+			}
+			if rule != "" {
+				set, err := recurrenceSet(rule, current.Start, exdates, rdates, loc)
+				if err != nil {
+					return nil, fmt.Errorf("event %q: %w", current.Summary, err)
+				}
+				current.recurrence = set
+			}
+			events = append(events, current)
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		switch line.name {
+		case "UID":
+			current.UID = line.value
+		case "SUMMARY":
+			current.Summary = unescapeText(line.value)
+		case "DESCRIPTION":
+			current.Description = unescapeText(line.value)
+		case "CATEGORIES":
+			for _, category := range splitText(line.value) {
+				if category = strings.TrimSpace(category); category != "" {
+					current.Categories = append(current.Categories, category)
+				}
+			}
+		case "ATTENDEE":
+			current.Attendees = append(current.Attendees, attendee(line))
+		case "STATUS":
+			current.cancelled = strings.EqualFold(line.value, "CANCELLED")
+		case "DTSTART":
+			t, allDay, err := parseTime(line.value, line.params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART %q: %w", line.value, err)
+			}
+			current.Start, current.AllDay = t, allDay
+		case "DTEND":
+			t, _, err := parseTime(line.value, line.params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTEND %q: %w", line.value, err)
+			}
+			current.End = t
+		case "DURATION":
+			d, err := parseDuration(line.value)
+			if err != nil {
+				return nil, err
+			}
+			if !current.Start.IsZero() {
+				current.End = current.Start.Add(d)
+			}
+		case "RRULE":
+			rule = line.value
+		case "EXDATE", "RDATE":
+			for _, value := range strings.Split(line.value, ",") {
+				t, _, err := parseTime(value, line.params, loc)
+				if err != nil {
+					continue
+				}
+				if line.name == "EXDATE" {
+					exdates = append(exdates, t)
+				} else {
+					rdates = append(rdates, t)
+				}
+			}
+		case "RECURRENCE-ID":
+			t, _, err := parseTime(line.value, line.params, loc)
+			if err == nil {
+				current.recurrenceID = t
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	return applyOverrides(events), nil
+}
+
+// applyOverrides excludes modified instances from their recurring event and drops cancelled events
+func applyOverrides(events []*Event) []*Event {
+	recurring := map[string]*Event{}
+	for _, event := range events {
+		if event.recurrence != nil && event.recurrenceID.IsZero() {
+			recurring[event.UID] = event
+		}
+	}
+
+	var result []*Event
+	for _, event := range events {
+		if !event.recurrenceID.IsZero() {
+			if master, ok := recurring[event.UID]; ok {
+				master.recurrence.ExDate(event.recurrenceID)
+			}
+		}
+		if !event.cancelled {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// Occurrences returns the instances of an event that overlap [from, to)
+func (e *Event) Occurrences(from, to time.Time) []Occurrence {
+	duration := e.End.Sub(e.Start)
+	occurrence := func(start time.Time) Occurrence {
+		return Occurrence{
+			Summary:     e.Summary,
+			Description: e.Description,
+			Categories:  e.Categories,
+			Attendees:   e.Attendees,
+			Start:       start,
+			End:         start.Add(duration),
+			AllDay:      e.AllDay,
+		}
+	}
+
+	if e.recurrence == nil {
+		if e.Start.Before(to) && (e.End.After(from) || (duration == 0 && !e.Start.Before(from))) {
+			return []Occurrence{occurrence(e.Start)}
+		}
+		return nil
+	}
+
+	var result []Occurrence
+	for _, start := range e.recurrence.Between(from.Add(-duration), to, true) {
+		if start.Before(to) && start.Add(duration).After(from) {
+			result = append(result, occurrence(start))
+		}
+	}
+	return result
+}
+
+func recurrenceSet(rule string, start time.Time, exdates, rdates []time.Time, loc *time.Location) (*rrule.Set, error) {
+	option, err := rrule.StrToROptionInLocation(rule, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RRULE %q: %w", rule, err)
+	}
+	option.Dtstart = start
+	r, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RRULE %q: %w", rule, err)
+	}
+
+	set := &rrule.Set{}
+	set.RRule(r)
+	for _, t := range exdates {
+		set.ExDate(t)
+	}
+	for _, t := range rdates {
+		set.RDate(t)
+	}
+	return set, nil
+}
+
+// unfold joins continuation lines (lines starting with a space or tab) per RFC 5545 section 3.1
+func unfold(data string) string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	return strings.ReplaceAll(data, "\n\t", "")
+}
+
+// parseLine splits a content line into name, parameters and value
+func parseLine(raw string) (icalLine, bool) {
+	inQuotes := false
+	colon := -1
+	for i, r := range raw {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icalLine{}, false
+	}
+
+	parts := strings.Split(raw[:colon], ";")
+	line := icalLine{
+		name:   strings.ToUpper(strings.TrimSpace(parts[0])),
+		params: map[string]string{},
+		value:  raw[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			line.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return line, true
+}
+
+// parseTime reads a DATE or DATE-TIME value: UTC ("Z"), with a TZID parameter, or floating (in loc)
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	zone := loc
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			zone = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, zone)
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration reads an RFC 5545 DURATION value such as P1D, PT8H or P1DT12H
+func parseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, fmt.Errorf("invalid DURATION %q", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] != "" {
+			n, _ := strconv.Atoi(m[i+2])
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// attendee formats an ATTENDEE line as "Name <address>"
+func attendee(line icalLine) string {
+	address := line.value
+	if len(address) >= 7 && strings.EqualFold(address[:7], "mailto:") {
+		address = address[7:]
+	}
+	if name := line.params["CN"]; name != "" && name != address {
+		return fmt.Sprintf("%s <%s>", name, address)
+	}
+	return address
+}
+
+// splitText splits a comma-separated TEXT list, honouring escaped commas
+func splitText(value string) []string {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			b.WriteByte('\\')
+			b.WriteByte(value[i+1])
+			i++
+		case value[i] == ',':
+			parts = append(parts, unescapeText(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return append(parts, unescapeText(b.String()))
+}
+
+var textEscapes = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescapeText(value string) string {
+	return textEscapes.Replace(value)
+}
@@ -0,0 +1,364 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/config"
+)
+
+// Service describes a single service in the catalog
+type Service struct {
+	Name           string            `yaml:"name" json:"name"`
+	Description    string            `yaml:"description" json:"description,omitempty"`
+	Owners         []string          `yaml:"owners" json:"owners,omitempty"`
+	Repos          []string          `yaml:"repos" json:"repos,omitempty"`
+	Dashboards     []string          `yaml:"dashboards" json:"dashboards,omitempty"`
+	LokiLabels     map[string]string `yaml:"loki_labels" json:"loki_labels,omitempty"`
+	SentryProjects []string          `yaml:"sentry_projects" json:"sentry_projects,omitempty"`
+	DependsOn      []string          `yaml:"depends_on" json:"depends_on,omitempty"`
+	HealthChecks   []string          `yaml:"health_checks" json:"health_checks,omitempty"` // URLs answering 2xx when the service is healthy
+}
+
+// catalogFile is the simple catalog format: a list of services
+type catalogFile struct {
+	Services []Service `yaml:"services"`
+}
+
+// backstageEntity is the subset of a Backstage catalog-info entity we understand
+type backstageEntity struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name        string            `yaml:"name"`
+		Description string            `yaml:"description"`
+		Annotations map[string]string `yaml:"annotations"`
+		Links       []struct {
+			URL   string `yaml:"url"`
+			Title string `yaml:"title"`
+		} `yaml:"links"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Owner     string   `yaml:"owner"`
+		DependsOn []string `yaml:"dependsOn"`
+	} `yaml:"spec"`
+}
+
+// Edge is a dependency edge in the service graph
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is the result of a dependency traversal
+type Graph struct {
+	Root     string     `json:"root"`
+	Nodes    []string   `json:"nodes"`
+	Edges    []Edge     `json:"edges"`
+	External []string   `json:"external,omitempty"` // Dependencies not present in the catalog
+	Cycles   [][]string `json:"cycles,omitempty"`
+}
+
+// CatalogClient loads the service catalog and answers lookups and graph queries
+type CatalogClient struct {
+	config   *config.CatalogConfig
+	mu       sync.RWMutex
+	services map[string]*Service
+}
+
+// NewCatalogClient creates a new catalog client from config
+func NewCatalogClient(cfg *config.CatalogConfig) *CatalogClient {
+	return &CatalogClient{
+		config:   cfg,
+		services: map[string]*Service{},
+	}
+}
+
+// IsAvailable returns whether a catalog file is configured
+func (c *CatalogClient) IsAvailable() bool {
+	return c.config != nil && c.config.File != ""
+}
+
+// Reload reads the catalog file, accepting either the simple format or Backstage entities
+func (c *CatalogClient) Reload() (int, error) {
+	if !c.IsAvailable() {
+		return 0, fmt.Errorf("service catalog not configured")
+	}
+
+	data, err := os.ReadFile(c.config.File)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read catalog file: %w", err)
+	}
+
+	services, err := parseCatalog(data)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.services = services
+	c.mu.Unlock()
+	return len(services), nil
+}
+
+// parseCatalog decodes every YAML document in the file
+func parseCatalog(data []byte) (map[string]*Service, error) {
+	services := map[string]*Service{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse catalog: %w", err)
+		}
+		if raw == nil {
+			continue
+		}
+
+		doc, err := yaml.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := raw["kind"]; ok {
+			var entity backstageEntity
+			if err := yaml.Unmarshal(doc, &entity); err != nil {
+				return nil, fmt.Errorf("failed to parse catalog entity: %w", err)
+			}
+			if svc := fromBackstage(entity); svc != nil {
+				services[svc.Name] = svc
+			}
+			continue
+		}
+
+		var file catalogFile
+		if err := yaml.Unmarshal(doc, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse catalog services: %w", err)
+		}
+		for i := range file.Services {
+			svc := file.Services[i]
+			if svc.Name == "" {
+				return nil, fmt.Errorf("catalog service at index %d has no name", i)
+			}
+			services[svc.Name] = &svc
+		}
+	}
+	return services, nil
+}
+
+// fromBackstage maps a Backstage Component entity to a Service
+func fromBackstage(e backstageEntity) *Service {
+	if !strings.EqualFold(e.Kind, "Component") || e.Metadata.Name == "" {
+		return nil
+	}
+
+	svc := &Service{
+		Name:        e.Metadata.Name,
+		Description: e.Metadata.Description,
+		LokiLabels:  map[string]string{},
+	}
+	if e.Spec.Owner != "" {
+		svc.Owners = []string{stripEntityRef(e.Spec.Owner)}
+	}
+	for _, dep := range e.Spec.DependsOn {
+		svc.DependsOn = append(svc.DependsOn, stripEntityRef(dep))
+	}
+	for _, link := range e.Metadata.Links {
+		svc.Dashboards = append(svc.Dashboards, link.URL)
+	}
+
+	for key, value := range e.Metadata.Annotations {
+		switch {
+		case key == "github.com/project-slug":
+			svc.Repos = append(svc.Repos, "github.com/"+value)
+		case key == "gitlab.com/project-slug":
+			svc.Repos = append(svc.Repos, "gitlab.com/"+value)
+		case key == "sentry.io/project-slug":
+			svc.SentryProjects = append(svc.SentryProjects, value)
+		case strings.HasPrefix(key, "loki/label-"):
+			svc.LokiLabels[strings.TrimPrefix(key, "loki/label-")] = value
+		case key == "dev-mcp/health-checks":
+			for _, url := range strings.Split(value, ",") {
+				if url = strings.TrimSpace(url); url != "" {
+					svc.HealthChecks = append(svc.HealthChecks, url)
+				}
+			}
+		}
+	}
+	return svc
+}
+
+// stripEntityRef turns "component:default/billing" into "billing"
+func stripEntityRef(ref string) string {
+	if i := strings.Index(ref, ":"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	return ref
+}
+
+// Get returns a service by name
+func (c *CatalogClient) Get(name string) (*Service, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	svc, ok := c.services[name]
+	return svc, ok
+}
+
+// List returns all services sorted by name, optionally filtered by owner
+func (c *CatalogClient) List(owner string) []*Service {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]*Service, 0, len(c.services))
+	for _, svc := range c.services {
+		if owner != "" && !contains(svc.Owners, owner) {
+			continue
+		}
+		out = append(out, svc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// LokiSelector builds a LogQL stream selector from the service's Loki labels
+func (s *Service) LokiSelector() string {
+	if len(s.LokiLabels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(s.LokiLabels))
+	for k := range s.LokiLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, s.LokiLabels[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// Dependents returns the services that directly depend on the named service
+func (c *CatalogClient) Dependents(name string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []string
+	for _, svc := range c.services {
+		if contains(svc.DependsOn, name) {
+			out = append(out, svc.Name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DependencyGraph traverses dependencies breadth-first from a root service.
+// direction is "downstream" (what the service depends on), "upstream" (who depends on it) or "both".
+func (c *CatalogClient) DependencyGraph(root, direction string, maxDepth int) (*Graph, error) {
+	if _, ok := c.Get(root); !ok {
+		return nil, fmt.Errorf("service not found in catalog: %s", root)
+	}
+	if maxDepth <= 0 {
+		maxDepth = 5
+	}
+
+	graph := &Graph{Root: root}
+	seenNodes := map[string]bool{root: true}
+	seenEdges := map[Edge]bool{}
+	external := map[string]bool{}
+	seenCycles := map[string]bool{}
+
+	type item struct {
+		name  string
+		depth int
+		path  []string
+	}
+	queue := []item{{name: root, path: []string{root}}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur.depth >= maxDepth {
+			continue
+		}
+
+		var next []Edge
+		if direction == "downstream" || direction == "both" || direction == "" {
+			if svc, ok := c.Get(cur.name); ok {
+				for _, dep := range svc.DependsOn {
+					next = append(next, Edge{From: cur.name, To: dep})
+				}
+			}
+		}
+		if direction == "upstream" || direction == "both" {
+			for _, dependent := range c.Dependents(cur.name) {
+				next = append(next, Edge{From: dependent, To: cur.name})
+			}
+		}
+
+		for _, edge := range next {
+			if !seenEdges[edge] {
+				seenEdges[edge] = true
+				graph.Edges = append(graph.Edges, edge)
+			}
+			neighbor := edge.To
+			if neighbor == cur.name {
+				neighbor = edge.From
+			}
+			if contains(cur.path, neighbor) {
+				cycle := append(append([]string{}, cur.path[indexOf(cur.path, neighbor):]...), neighbor)
+				if key := strings.Join(cycle, "->"); !seenCycles[key] {
+					seenCycles[key] = true
+					graph.Cycles = append(graph.Cycles, cycle)
+				}
+				continue
+			}
+			if _, ok := c.Get(neighbor); !ok {
+				external[neighbor] = true
+				continue
+			}
+			if !seenNodes[neighbor] {
+				seenNodes[neighbor] = true
+				path := append(append([]string{}, cur.path...), neighbor)
+				queue = append(queue, item{name: neighbor, depth: cur.depth + 1, path: path})
+			}
+		}
+	}
+
+	for node := range seenNodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for node := range external {
+		graph.External = append(graph.External, node)
+	}
+	sort.Strings(graph.Nodes)
+	sort.Strings(graph.External)
+	return graph, nil
+}
+
+// Close closes the catalog client
+func (c *CatalogClient) Close() error {
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	return indexOf(list, value) >= 0
+}
+
+func indexOf(list []string, value string) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,267 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// CatalogProvider exposes service ownership and dependency information
+type CatalogProvider struct {
+	*provider.BaseProvider
+	client *CatalogClient
+}
+
+// NewCatalogProvider creates a new service catalog provider with config
+func NewCatalogProvider(cfg *config.CatalogConfig) *CatalogProvider {
+	p := &CatalogProvider{
+		BaseProvider: provider.NewBaseProvider("catalog"),
+		client:       NewCatalogClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Service catalog not configured", nil)
+		return p
+	}
+
+	count, err := p.client.Reload()
+	if err != nil {
+		log.Printf("⚠ Service catalog load failed: %v", err)
+		p.SetStatus(false, "Service catalog load failed", err)
+		return p
+	}
+
+	p.SetStatus(true, fmt.Sprintf("Loaded %d services", count), nil)
+	log.Printf("✓ Catalog provider initialized successfully (%d services)", count)
+
+	return p
+}
+
+// Client returns the catalog client so composite tools can resolve services
+func (p *CatalogProvider) Client() *CatalogClient {
+	return p.client
+}
+
+// Test tests the catalog configuration (for ProviderClient interface compatibility)
+func (p *CatalogProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("catalog provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Catalog tools to the MCP server (for ProviderClient interface compatibility)
+func (p *CatalogProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the Catalog provider
+func (p *CatalogProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds Catalog tools to the MCP server
+func (p *CatalogProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Catalog provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createListServicesTool().Tool, p.createListServicesTool().Handler},
+		{p.createGetServiceTool().Tool, p.createGetServiceTool().Handler},
+		{p.createDependencyGraphTool().Tool, p.createDependencyGraphTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered Catalog tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All Catalog tools registered successfully")
+}
+
+// createListServicesTool creates the service listing tool
+func (p *CatalogProvider) createListServicesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "catalog_list_services",
+		Description: "List services in the service catalog, optionally filtered by owner",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"owner": {
+					"type": "string",
+					"description": "Only return services owned by this team or person"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Owner string `json:"owner,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		services := p.client.List(args.Owner)
+		summary := make([]map[string]interface{}, 0, len(services))
+		for _, svc := range services {
+			summary = append(summary, map[string]interface{}{
+				"name":        svc.Name,
+				"description": svc.Description,
+				"owners":      svc.Owners,
+			})
+		}
+
+		result := map[string]interface{}{
+			"services": summary,
+			"count":    len(summary),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createGetServiceTool creates the service lookup tool
+func (p *CatalogProvider) createGetServiceTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "catalog_get_service",
+		Description: "Look up a service's owners, repos, dashboards, Loki labels, and Sentry projects",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"description": "Service name"
+				}
+			},
+			"required": ["name"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Name string `json:"name"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Name == "" {
+			return p.createErrorResult(fmt.Errorf("name parameter is required")), nil
+		}
+
+		svc, ok := p.client.Get(args.Name)
+		if !ok {
+			return p.createErrorResult(fmt.Errorf("service not found in catalog: %s", args.Name)), nil
+		}
+
+		result := map[string]interface{}{
+			"service":       svc,
+			"loki_selector": svc.LokiSelector(),
+			"dependents":    p.client.Dependents(svc.Name),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDependencyGraphTool creates the dependency graph traversal tool
+func (p *CatalogProvider) createDependencyGraphTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "catalog_dependency_graph",
+		Description: "Traverse the service dependency graph from a service (downstream dependencies, upstream dependents, or both)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"description": "Root service name"
+				},
+				"direction": {
+					"type": "string",
+					"description": "Traversal direction",
+					"enum": ["downstream", "upstream", "both"],
+					"default": "downstream"
+				},
+				"depth": {
+					"type": "integer",
+					"description": "Maximum traversal depth",
+					"default": 5
+				}
+			},
+			"required": ["name"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Name      string `json:"name"`
+			Direction string `json:"direction,omitempty"`
+			Depth     int    `json:"depth,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Name == "" {
+			return p.createErrorResult(fmt.Errorf("name parameter is required")), nil
+		}
+
+		if args.Direction == "" {
+			args.Direction = "downstream"
+		}
+		if args.Direction != "downstream" && args.Direction != "upstream" && args.Direction != "both" {
+			return p.createErrorResult(fmt.Errorf("invalid direction: %s", args.Direction)), nil
+		}
+
+		graph, err := p.client.DependencyGraph(args.Name, args.Direction, args.Depth)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(graph), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *CatalogProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Catalog Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *CatalogProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that CatalogProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*CatalogProvider)(nil)
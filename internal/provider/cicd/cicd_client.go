@@ -0,0 +1,179 @@
+package cicd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+const defaultMaxLogBytes = 20000
+
+// Run is a pipeline/workflow/build run normalized across CI systems
+type Run struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name,omitempty"`
+	Status     string     `json:"status"`
+	Conclusion string     `json:"conclusion,omitempty"`
+	Branch     string     `json:"branch,omitempty"`
+	Commit     string     `json:"commit,omitempty"`
+	Event      string     `json:"event,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// JobLog is the (tail of the) log of a failed job
+type JobLog struct {
+	JobID     string `json:"job_id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	URL       string `json:"url,omitempty"`
+	Log       string `json:"log"`
+	Truncated bool   `json:"truncated"`
+}
+
+// Backend is implemented by each supported CI system
+type Backend interface {
+	// Name returns the system name ("github", "gitlab", "jenkins")
+	Name() string
+	// ListRuns lists recent runs for a repository (or Jenkins job), optionally filtered by branch
+	ListRuns(repo, branch string, limit int) ([]Run, error)
+	// FailedJobLogs returns the logs of the failed jobs of a run
+	FailedJobLogs(repo, runID string) ([]JobLog, error)
+	// Retrigger re-runs a run, as the user the tool call acts for when there is one, and returns a short
+	// description of what was triggered
+	Retrigger(ctx context.Context, repo, runID string) (string, error)
+}
+
+// CICDClient dispatches requests to the configured CI backends
+type CICDClient struct {
+	config   *config.CICDConfig
+	backends map[string]Backend
+	logger   *logging.Logger
+}
+
+// NewCICDClient creates a client with a backend for every configured CI system
+func NewCICDClient(cfg *config.CICDConfig) *CICDClient {
+	c := &CICDClient{
+		config:   cfg,
+		backends: map[string]Backend{},
+		logger:   logging.New("CICDClient"),
+	}
+	if cfg == nil {
+		return c
+	}
+
+	if cfg.GitHub.Token != "" {
+		c.backends["github"] = newGitHubBackend(&cfg.GitHub)
+	}
+	if cfg.GitLab.Token != "" {
+		c.backends["gitlab"] = newGitLabBackend(&cfg.GitLab)
+	}
+	if cfg.Jenkins.URL != "" {
+		c.backends["jenkins"] = newJenkinsBackend(&cfg.Jenkins)
+	}
+	return c
+}
+
+// IsAvailable checks if at least one CI system is configured
+func (c *CICDClient) IsAvailable() bool {
+	return len(c.backends) > 0
+}
+
+// Systems returns the names of the configured CI systems
+func (c *CICDClient) Systems() []string {
+	names := make([]string, 0, len(c.backends))
+	for name := range c.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// backend resolves a system name; an empty name is allowed when exactly one system is configured
+func (c *CICDClient) backend(system string) (Backend, error) {
+	if system == "" {
+		if len(c.backends) == 1 {
+			for _, b := range c.backends {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("system parameter is required when several CI systems are configured (%v)", c.Systems())
+	}
+	b, ok := c.backends[system]
+	if !ok {
+		return nil, fmt.Errorf("CI system %q is not configured (available: %v)", system, c.Systems())
+	}
+	return b, nil
+}
+
+// ListRuns lists recent runs on a CI system
+func (c *CICDClient) ListRuns(system, repo, branch string, limit int) ([]Run, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	return b.ListRuns(repo, branch, limit)
+}
+
+// FailedJobLogs fetches the failed job logs of a run, keeping only the tail of each log
+func (c *CICDClient) FailedJobLogs(system, repo, runID string, maxBytes int) ([]JobLog, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes <= 0 {
+		maxBytes = c.config.MaxLogBytes
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxLogBytes
+	}
+
+	logs, err := b.FailedJobLogs(repo, runID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		logs[i].Log, logs[i].Truncated = tail(logs[i].Log, maxBytes)
+	}
+	return logs, nil
+}
+
+// Retrigger re-runs a run if the write gate is open
+func (c *CICDClient) Retrigger(ctx context.Context, system, repo, runID string) (string, error) {
+	if !c.config.AllowTrigger {
+		return "", fmt.Errorf("re-triggering runs is disabled (set cicd.allow_trigger to enable)")
+	}
+	b, err := c.backend(system)
+	if err != nil {
+		return "", err
+	}
+	user, _ := auth.OnBehalfOf(ctx)
+	c.logger.Warn("re-triggering CI run",
+		logging.String("system", b.Name()),
+		logging.String("repo", repo),
+		logging.String("run_id", runID),
+		logging.String("on_behalf_of", user))
+	return b.Retrigger(ctx, repo, runID)
+}
+
+// Close closes the CI/CD client
+func (c *CICDClient) Close() error {
+	return nil
+}
+
+// tail keeps the last maxBytes of a log, where failures are usually reported
+func tail(log string, maxBytes int) (string, bool) {
+	if len(log) <= maxBytes {
+		return log, false
+	}
+	return "...(truncated)...\n" + log[len(log)-maxBytes:], true
+}
@@ -0,0 +1,292 @@
+package cicd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// CICDProvider exposes pipeline status from GitHub Actions, GitLab CI and Jenkins
+type CICDProvider struct {
+	*provider.BaseProvider
+	client *CICDClient
+}
+
+// NewCICDProvider creates a new CI/CD provider with config
+func NewCICDProvider(cfg *config.CICDConfig) *CICDProvider {
+	p := &CICDProvider{
+		BaseProvider: provider.NewBaseProvider("cicd"),
+		client:       NewCICDClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "CI/CD not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ CI/CD provider initialized successfully (%s)", strings.Join(p.client.Systems(), ", "))
+
+	return p
+}
+
+// Client returns the CI/CD client
+func (p *CICDProvider) Client() *CICDClient {
+	return p.client
+}
+
+// Test tests the CI/CD configuration (for ProviderClient interface compatibility)
+func (p *CICDProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("cicd provider not available")
+	}
+	return nil
+}
+
+// AddTools adds CI/CD tools to the MCP server (for ProviderClient interface compatibility)
+func (p *CICDProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the CI/CD provider
+func (p *CICDProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds CI/CD tools to the MCP server
+func (p *CICDProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ CI/CD provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createListRunsTool().Tool, p.createListRunsTool().Handler},
+		{p.createFailedLogsTool().Tool, p.createFailedLogsTool().Handler},
+		{p.createRetriggerTool().Tool, p.createRetriggerTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered CI/CD tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All CI/CD tools registered successfully")
+}
+
+// createListRunsTool creates the pipeline run listing tool
+func (p *CICDProvider) createListRunsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "cicd_list_runs",
+		Description: "List recent CI/CD pipeline runs for a repository and branch (GitHub Actions, GitLab CI or Jenkins)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"system": {
+					"type": "string",
+					"description": "CI system to query; optional when only one is configured",
+					"enum": ["github", "gitlab", "jenkins"]
+				},
+				"repo": {
+					"type": "string",
+					"description": "Repository (owner/repo for GitHub, group/project for GitLab) or Jenkins job path (folder/job)"
+				},
+				"branch": {
+					"type": "string",
+					"description": "Only list runs for this branch"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of runs to return",
+					"default": 10
+				}
+			},
+			"required": ["repo"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			System string `json:"system,omitempty"`
+			Repo   string `json:"repo"`
+			Branch string `json:"branch,omitempty"`
+			Limit  int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Repo == "" {
+			return p.createErrorResult(fmt.Errorf("repo parameter is required")), nil
+		}
+
+		runs, err := p.client.ListRuns(args.System, args.Repo, args.Branch, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"repo":   args.Repo,
+			"branch": args.Branch,
+			"runs":   runs,
+			"count":  len(runs),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createFailedLogsTool creates the failed job log retrieval tool
+func (p *CICDProvider) createFailedLogsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "cicd_failed_logs",
+		Description: "Fetch the logs of the failed jobs of a pipeline run (tail only, truncated to max_bytes)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"system": {
+					"type": "string",
+					"description": "CI system to query; optional when only one is configured",
+					"enum": ["github", "gitlab", "jenkins"]
+				},
+				"repo": {
+					"type": "string",
+					"description": "Repository or Jenkins job path"
+				},
+				"run_id": {
+					"type": "string",
+					"description": "Run ID as returned by cicd_list_runs"
+				},
+				"max_bytes": {
+					"type": "integer",
+					"description": "Maximum number of bytes kept from the end of each log"
+				}
+			},
+			"required": ["repo", "run_id"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			System   string `json:"system,omitempty"`
+			Repo     string `json:"repo"`
+			RunID    string `json:"run_id"`
+			MaxBytes int    `json:"max_bytes,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Repo == "" || args.RunID == "" {
+			return p.createErrorResult(fmt.Errorf("repo and run_id parameters are required")), nil
+		}
+
+		logs, err := p.client.FailedJobLogs(args.System, args.Repo, args.RunID, args.MaxBytes)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"repo":        args.Repo,
+			"run_id":      args.RunID,
+			"failed_jobs": logs,
+			"count":       len(logs),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createRetriggerTool creates the run re-trigger tool (gated by cicd.allow_trigger)
+func (p *CICDProvider) createRetriggerTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "cicd_retrigger",
+		Description: "Re-run a pipeline run's failed jobs. Disabled unless cicd.allow_trigger is enabled.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"system": {
+					"type": "string",
+					"description": "CI system; optional when only one is configured",
+					"enum": ["github", "gitlab", "jenkins"]
+				},
+				"repo": {
+					"type": "string",
+					"description": "Repository or Jenkins job path"
+				},
+				"run_id": {
+					"type": "string",
+					"description": "Run ID as returned by cicd_list_runs"
+				}
+			},
+			"required": ["repo", "run_id"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			System string `json:"system,omitempty"`
+			Repo   string `json:"repo"`
+			RunID  string `json:"run_id"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Repo == "" || args.RunID == "" {
+			return p.createErrorResult(fmt.Errorf("repo and run_id parameters are required")), nil
+		}
+
+		message, err := p.client.Retrigger(ctx, args.System, args.Repo, args.RunID)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"status":  "triggered",
+			"message": message,
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *CICDProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("CI/CD Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *CICDProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that CICDProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*CICDProvider)(nil)
@@ -0,0 +1,158 @@
+package cicd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+)
+
+// githubBackend talks to the GitHub Actions REST API
+type githubBackend struct {
+	client *resty.Client
+}
+
+func newGitHubBackend(cfg *config.GitHubActionsConfig) *githubBackend {
+	baseURL := "https://api.github.com"
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetHeader("Authorization", "Bearer "+cfg.Token).
+		SetHeader("Accept", "application/vnd.github+json").
+		SetHeader("X-GitHub-Api-Version", "2022-11-28").
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+
+	return &githubBackend{client: client}
+}
+
+func (b *githubBackend) Name() string { return "github" }
+
+type githubRun struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Conclusion   string    `json:"conclusion"`
+	HeadBranch   string    `json:"head_branch"`
+	HeadSHA      string    `json:"head_sha"`
+	Event        string    `json:"event"`
+	HTMLURL      string    `json:"html_url"`
+	RunStartedAt time.Time `json:"run_started_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type githubJob struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+}
+
+// ListRuns lists workflow runs of an "owner/repo" repository
+func (b *githubBackend) ListRuns(repo, branch string, limit int) ([]Run, error) {
+	var result struct {
+		WorkflowRuns []githubRun `json:"workflow_runs"`
+	}
+
+	req := b.client.R().
+		SetQueryParam("per_page", fmt.Sprintf("%d", limit)).
+		SetResult(&result)
+	if branch != "" {
+		req.SetQueryParam("branch", branch)
+	}
+
+	resp, err := req.Get(fmt.Sprintf("/repos/%s/actions/runs", repo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("github API error: %s", resp.Status())
+	}
+
+	runs := make([]Run, 0, len(result.WorkflowRuns))
+	for _, r := range result.WorkflowRuns {
+		started, updated := r.RunStartedAt, r.UpdatedAt
+		runs = append(runs, Run{
+			ID:         fmt.Sprintf("%d", r.ID),
+			Name:       r.Name,
+			Status:     r.Status,
+			Conclusion: r.Conclusion,
+			Branch:     r.HeadBranch,
+			Commit:     r.HeadSHA,
+			Event:      r.Event,
+			URL:        r.HTMLURL,
+			StartedAt:  &started,
+			UpdatedAt:  &updated,
+		})
+	}
+	return runs, nil
+}
+
+// FailedJobLogs returns the logs of the failed jobs of a workflow run
+func (b *githubBackend) FailedJobLogs(repo, runID string) ([]JobLog, error) {
+	var result struct {
+		Jobs []githubJob `json:"jobs"`
+	}
+
+	resp, err := b.client.R().
+		SetQueryParam("per_page", "100").
+		SetResult(&result).
+		Get(fmt.Sprintf("/repos/%s/actions/runs/%s/jobs", repo, runID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("github API error: %s", resp.Status())
+	}
+
+	var logs []JobLog
+	for _, job := range result.Jobs {
+		if job.Conclusion != "failure" && job.Conclusion != "timed_out" {
+			continue
+		}
+		logResp, err := b.client.R().
+			Get(fmt.Sprintf("/repos/%s/actions/jobs/%d/logs", repo, job.ID))
+		text := ""
+		if err != nil {
+			text = fmt.Sprintf("failed to fetch log: %v", err)
+		} else if logResp.IsError() {
+			text = fmt.Sprintf("failed to fetch log: github API error: %s", logResp.Status())
+		} else {
+			text = logResp.String()
+		}
+		logs = append(logs, JobLog{
+			JobID:  fmt.Sprintf("%d", job.ID),
+			Name:   job.Name,
+			Status: job.Conclusion,
+			URL:    job.HTMLURL,
+			Log:    text,
+		})
+	}
+	return logs, nil
+}
+
+// Retrigger re-runs the failed jobs of a workflow run
+func (b *githubBackend) Retrigger(ctx context.Context, repo, runID string) (string, error) {
+	req := b.client.R().SetContext(ctx)
+	if err := auth.ApplyOnBehalfOf(ctx, "github", req); err != nil {
+		return "", err
+	}
+	resp, err := req.
+		Post(fmt.Sprintf("/repos/%s/actions/runs/%s/rerun-failed-jobs", repo, runID))
+	if err != nil {
+		return "", fmt.Errorf("failed to re-run workflow: %w", err)
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("github API error: %s: %s", resp.Status(), resp.String())
+	}
+	return fmt.Sprintf("re-running failed jobs of workflow run %s in %s", runID, repo), nil
+}
@@ -0,0 +1,152 @@
+package cicd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+)
+
+// gitlabBackend talks to the GitLab CI REST API
+type gitlabBackend struct {
+	client *resty.Client
+}
+
+func newGitLabBackend(cfg *config.GitLabCIConfig) *gitlabBackend {
+	baseURL := "https://gitlab.com"
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL+"/api/v4").
+		SetHeader("PRIVATE-TOKEN", cfg.Token).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+
+	return &gitlabBackend{client: client}
+}
+
+func (b *gitlabBackend) Name() string { return "gitlab" }
+
+type gitlabPipeline struct {
+	ID        int64     `json:"id"`
+	Status    string    `json:"status"`
+	Ref       string    `json:"ref"`
+	SHA       string    `json:"sha"`
+	Source    string    `json:"source"`
+	WebURL    string    `json:"web_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type gitlabJob struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Stage  string `json:"stage"`
+	Status string `json:"status"`
+	WebURL string `json:"web_url"`
+}
+
+// project encodes a "group/project" path as a GitLab project ID
+func (b *gitlabBackend) project(repo string) string {
+	return url.PathEscape(repo)
+}
+
+// ListRuns lists pipelines of a "group/project" repository
+func (b *gitlabBackend) ListRuns(repo, branch string, limit int) ([]Run, error) {
+	var pipelines []gitlabPipeline
+
+	req := b.client.R().
+		SetQueryParam("per_page", fmt.Sprintf("%d", limit)).
+		SetResult(&pipelines)
+	if branch != "" {
+		req.SetQueryParam("ref", branch)
+	}
+
+	resp, err := req.Get(fmt.Sprintf("/projects/%s/pipelines", b.project(repo)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelines: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("gitlab API error: %s", resp.Status())
+	}
+
+	runs := make([]Run, 0, len(pipelines))
+	for _, p := range pipelines {
+		created, updated := p.CreatedAt, p.UpdatedAt
+		runs = append(runs, Run{
+			ID:        fmt.Sprintf("%d", p.ID),
+			Status:    p.Status,
+			Branch:    p.Ref,
+			Commit:    p.SHA,
+			Event:     p.Source,
+			URL:       p.WebURL,
+			StartedAt: &created,
+			UpdatedAt: &updated,
+		})
+	}
+	return runs, nil
+}
+
+// FailedJobLogs returns the traces of the failed jobs of a pipeline
+func (b *gitlabBackend) FailedJobLogs(repo, runID string) ([]JobLog, error) {
+	var jobs []gitlabJob
+
+	resp, err := b.client.R().
+		SetQueryParam("scope[]", "failed").
+		SetQueryParam("per_page", "100").
+		SetResult(&jobs).
+		Get(fmt.Sprintf("/projects/%s/pipelines/%s/jobs", b.project(repo), runID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("gitlab API error: %s", resp.Status())
+	}
+
+	logs := make([]JobLog, 0, len(jobs))
+	for _, job := range jobs {
+		traceResp, err := b.client.R().
+			Get(fmt.Sprintf("/projects/%s/jobs/%d/trace", b.project(repo), job.ID))
+		text := ""
+		if err != nil {
+			text = fmt.Sprintf("failed to fetch trace: %v", err)
+		} else if traceResp.IsError() {
+			text = fmt.Sprintf("failed to fetch trace: gitlab API error: %s", traceResp.Status())
+		} else {
+			text = traceResp.String()
+		}
+		logs = append(logs, JobLog{
+			JobID:  fmt.Sprintf("%d", job.ID),
+			Name:   job.Stage + "/" + job.Name,
+			Status: job.Status,
+			URL:    job.WebURL,
+			Log:    text,
+		})
+	}
+	return logs, nil
+}
+
+// Retrigger retries the failed jobs of a pipeline
+func (b *gitlabBackend) Retrigger(ctx context.Context, repo, runID string) (string, error) {
+	req := b.client.R().SetContext(ctx)
+	if err := auth.ApplyOnBehalfOf(ctx, "gitlab", req); err != nil {
+		return "", err
+	}
+	resp, err := req.
+		Post(fmt.Sprintf("/projects/%s/pipelines/%s/retry", b.project(repo), runID))
+	if err != nil {
+		return "", fmt.Errorf("failed to retry pipeline: %w", err)
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("gitlab API error: %s: %s", resp.Status(), resp.String())
+	}
+	return fmt.Sprintf("retrying failed jobs of pipeline %s in %s", runID, repo), nil
+}
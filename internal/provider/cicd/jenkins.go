@@ -0,0 +1,178 @@
+package cicd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+)
+
+// jenkinsBackend talks to the Jenkins JSON API
+type jenkinsBackend struct {
+	client *resty.Client
+}
+
+func newJenkinsBackend(cfg *config.JenkinsConfig) *jenkinsBackend {
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.URL, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+	if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.APIToken)
+	}
+
+	return &jenkinsBackend{client: client}
+}
+
+func (b *jenkinsBackend) Name() string { return "jenkins" }
+
+type jenkinsBuild struct {
+	Number    int64  `json:"number"`
+	Result    string `json:"result"`
+	Building  bool   `json:"building"`
+	Timestamp int64  `json:"timestamp"`
+	Duration  int64  `json:"duration"`
+	URL       string `json:"url"`
+	Actions   []struct {
+		LastBuiltRevision *struct {
+			SHA1   string `json:"SHA1"`
+			Branch []struct {
+				Name string `json:"name"`
+			} `json:"branch"`
+		} `json:"lastBuiltRevision"`
+	} `json:"actions"`
+}
+
+// jobPath turns "folder/job" (and an optional multibranch branch) into "/job/folder/job/job"
+func (b *jenkinsBackend) jobPath(repo, branch string) string {
+	var parts []string
+	for _, segment := range strings.Split(strings.Trim(repo, "/"), "/") {
+		if segment != "" {
+			parts = append(parts, "job", url.PathEscape(segment))
+		}
+	}
+	if branch != "" {
+		parts = append(parts, "job", url.PathEscape(branch))
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// splitRun accepts "<number>" or "<branch>#<number>" for multibranch pipelines
+func splitRun(runID string) (branch, number string) {
+	if i := strings.LastIndex(runID, "#"); i >= 0 {
+		return runID[:i], runID[i+1:]
+	}
+	return "", runID
+}
+
+// ListRuns lists recent builds of a job; for multibranch pipelines branch selects the branch job
+func (b *jenkinsBackend) ListRuns(repo, branch string, limit int) ([]Run, error) {
+	var result struct {
+		Builds []jenkinsBuild `json:"builds"`
+	}
+
+	tree := fmt.Sprintf("builds[number,result,building,timestamp,duration,url,actions[lastBuiltRevision[SHA1,branch[name]]]]{0,%d}", limit)
+	resp, err := b.client.R().
+		SetQueryParam("tree", tree).
+		SetResult(&result).
+		Get(b.jobPath(repo, branch) + "/api/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list builds: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("jenkins API error: %s", resp.Status())
+	}
+
+	runs := make([]Run, 0, len(result.Builds))
+	for _, build := range result.Builds {
+		started := time.UnixMilli(build.Timestamp)
+		run := Run{
+			ID:         fmt.Sprintf("%d", build.Number),
+			Status:     "completed",
+			Conclusion: strings.ToLower(build.Result),
+			Branch:     branch,
+			URL:        build.URL,
+			StartedAt:  &started,
+		}
+		if branch != "" {
+			run.ID = branch + "#" + run.ID
+		}
+		if build.Building {
+			run.Status = "in_progress"
+		} else {
+			finished := started.Add(time.Duration(build.Duration) * time.Millisecond)
+			run.UpdatedAt = &finished
+		}
+		for _, action := range build.Actions {
+			if rev := action.LastBuiltRevision; rev != nil {
+				run.Commit = rev.SHA1
+				if run.Branch == "" && len(rev.Branch) > 0 {
+					run.Branch = rev.Branch[0].Name
+				}
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// FailedJobLogs returns the console output of a failed build
+func (b *jenkinsBackend) FailedJobLogs(repo, runID string) ([]JobLog, error) {
+	branch, number := splitRun(runID)
+	buildPath := b.jobPath(repo, branch) + "/" + number
+
+	var build jenkinsBuild
+	resp, err := b.client.R().
+		SetQueryParam("tree", "number,result,building,url").
+		SetResult(&build).
+		Get(buildPath + "/api/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("jenkins API error: %s", resp.Status())
+	}
+	if build.Building || build.Result == "SUCCESS" {
+		return []JobLog{}, nil
+	}
+
+	consoleResp, err := b.client.R().Get(buildPath + "/consoleText")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch console log: %w", err)
+	}
+	if consoleResp.IsError() {
+		return nil, fmt.Errorf("jenkins API error: %s", consoleResp.Status())
+	}
+
+	return []JobLog{{
+		JobID:  runID,
+		Name:   repo,
+		Status: strings.ToLower(build.Result),
+		URL:    build.URL,
+		Log:    consoleResp.String(),
+	}}, nil
+}
+
+// Retrigger schedules a new build of the job the run belongs to
+func (b *jenkinsBackend) Retrigger(ctx context.Context, repo, runID string) (string, error) {
+	req := b.client.R().SetContext(ctx)
+	if err := auth.ApplyOnBehalfOf(ctx, "jenkins", req); err != nil {
+		return "", err
+	}
+	branch, _ := splitRun(runID)
+	resp, err := req.Post(b.jobPath(repo, branch) + "/build")
+	if err != nil {
+		return "", fmt.Errorf("failed to trigger build: %w", err)
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("jenkins API error: %s: %s", resp.Status(), resp.String())
+	}
+	queued := resp.Header().Get("Location")
+	return fmt.Sprintf("scheduled a new build of %s (queue item: %s)", repo, queued), nil
+}
@@ -0,0 +1,415 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultMaxDiffBytes = 100000
+	defaultMaxComments  = 100
+	defaultListLimit    = 20
+	// maxBodyChars bounds the body of an issue, pull request or comment
+	maxBodyChars = 8000
+	// maxDiffFiles bounds the files of a pull request diff, as GitHub does
+	maxDiffFiles = 3000
+)
+
+// PullRequest is a GitHub pull request or GitLab merge request
+type PullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // open, closed or merged
+	Draft     bool       `json:"draft,omitempty"`
+	Author    string     `json:"author"`
+	Source    string     `json:"source_branch"`
+	Target    string     `json:"target_branch"`
+	Labels    []string   `json:"labels,omitempty"`
+	Reviewers []string   `json:"reviewers,omitempty"`
+	URL       string     `json:"url"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ListOptions filters the pull requests of a repository
+type ListOptions struct {
+	State  string // open (default), closed, merged or all
+	Author string
+	Limit  int
+}
+
+// FileDiff is the change to one file of a pull request
+type FileDiff struct {
+	Path         string `json:"path"`
+	OldPath      string `json:"old_path,omitempty"` // Set when the file was renamed
+	Status       string `json:"status"`             // added, modified, removed or renamed
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	Patch        string `json:"patch,omitempty"`
+	PatchOmitted string `json:"patch_omitted,omitempty"` // Why the patch is missing
+}
+
+// PullRequestDiff holds the changed files of a pull request and their patches
+type PullRequestDiff struct {
+	Number       int        `json:"number"`
+	Title        string     `json:"title"`
+	Source       string     `json:"source_branch"`
+	Target       string     `json:"target_branch"`
+	HeadSHA      string     `json:"head_sha,omitempty"`
+	ChangedFiles int        `json:"changed_files"`
+	Additions    int        `json:"additions"`
+	Deletions    int        `json:"deletions"`
+	Files        []FileDiff `json:"files"`
+	Notes        []string   `json:"notes,omitempty"`
+}
+
+// Comment is a comment of an issue or pull request thread
+type Comment struct {
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	URL       string     `json:"url,omitempty"`
+}
+
+// IssueThread is an issue or pull request with its comments, oldest first
+type IssueThread struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	Author    string     `json:"author"`
+	Labels    []string   `json:"labels,omitempty"`
+	Assignees []string   `json:"assignees,omitempty"`
+	URL       string     `json:"url"`
+	Body      string     `json:"body"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Comments  []Comment  `json:"comments"`
+	Truncated string     `json:"truncated,omitempty"`
+}
+
+// Check is one CI check run, commit status or pipeline job
+type Check struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"` // queued, in_progress or completed
+	Conclusion  string     `json:"conclusion,omitempty"`
+	Description string     `json:"description,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CheckSummary holds the CI checks of a commit and their overall state
+type CheckSummary struct {
+	Ref    string         `json:"ref"`
+	SHA    string         `json:"sha,omitempty"`
+	State  string         `json:"state"` // success, failure, pending or none
+	Counts map[string]int `json:"counts"`
+	Checks []Check        `json:"checks"`
+}
+
+// Backend is implemented by each supported code hosting service
+type Backend interface {
+	// Name returns the system name ("github", "gitlab")
+	Name() string
+	// ListPullRequests lists the pull requests of a repository, most recently updated first
+	ListPullRequests(ctx context.Context, repo string, opts ListOptions) ([]PullRequest, error)
+	// PullRequestDiff returns the changed files of a pull request with their patches
+	PullRequestDiff(ctx context.Context, repo string, number int) (*PullRequestDiff, error)
+	// IssueThread returns an issue, or a pull request when pullRequest is set, with up to maxComments comments
+	IssueThread(ctx context.Context, repo string, number int, pullRequest bool, maxComments int) (*IssueThread, error)
+	// Checks returns the CI checks of a pull request's head commit (number > 0) or of a branch, tag or SHA
+	Checks(ctx context.Context, repo string, number int, ref string) (*CheckSummary, error)
+}
+
+// CodeHostClient dispatches requests to the configured code hosting services
+type CodeHostClient struct {
+	config       *config.CodeHostConfig
+	backends     map[string]Backend
+	maxDiffBytes int
+	maxComments  int
+}
+
+// NewCodeHostClient creates a client with a backend for every service with a token. The CI/CD tokens are
+// used for the services the code_host section gives none.
+func NewCodeHostClient(cfg *config.CodeHostConfig, cicd *config.CICDConfig) *CodeHostClient {
+	c := &CodeHostClient{
+		config:       cfg,
+		backends:     map[string]Backend{},
+		maxDiffBytes: defaultMaxDiffBytes,
+		maxComments:  defaultMaxComments,
+	}
+	if cfg == nil {
+		c.config = &config.CodeHostConfig{}
+		return c
+	}
+	if cfg.MaxDiffBytes > 0 {
+		c.maxDiffBytes = cfg.MaxDiffBytes
+	}
+	if cfg.MaxComments > 0 {
+		c.maxComments = cfg.MaxComments
+	}
+
+	github, gitlab := cfg.GitHub, cfg.GitLab
+	if github.Token == "" && cicd != nil {
+		github = config.CodeHostServer{BaseURL: cicd.GitHub.BaseURL, Token: cicd.GitHub.Token}
+	}
+	if gitlab.Token == "" && cicd != nil {
+		gitlab = config.CodeHostServer{BaseURL: cicd.GitLab.BaseURL, Token: cicd.GitLab.Token}
+	}
+	if github.Token != "" {
+		c.backends["github"] = newGitHubBackend(&github)
+	}
+	if gitlab.Token != "" {
+		c.backends["gitlab"] = newGitLabBackend(&gitlab)
+	}
+	return c
+}
+
+// IsAvailable checks if at least one service is configured
+func (c *CodeHostClient) IsAvailable() bool {
+	return len(c.backends) > 0
+}
+
+// Systems returns the names of the configured services
+func (c *CodeHostClient) Systems() []string {
+	names := make([]string, 0, len(c.backends))
+	for name := range c.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultRepo returns the repository used when a tool names none
+func (c *CodeHostClient) DefaultRepo() string {
+	return c.config.DefaultRepo
+}
+
+// MaxComments returns the default number of comments of a thread
+func (c *CodeHostClient) MaxComments() int {
+	return c.maxComments
+}
+
+// backend resolves a system name; an empty name is allowed when exactly one service is configured
+func (c *CodeHostClient) backend(system string) (Backend, error) {
+	if system == "" {
+		if len(c.backends) == 1 {
+			for _, b := range c.backends {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("system parameter is required when several code hosts are configured (%v)", c.Systems())
+	}
+	b, ok := c.backends[system]
+	if !ok {
+		return nil, fmt.Errorf("code host %q is not configured (available: %v)", system, c.Systems())
+	}
+	return b, nil
+}
+
+// repo resolves a repository name, falling back to code_host.default_repo
+func (c *CodeHostClient) repo(repo string) (string, error) {
+	repo = strings.Trim(strings.TrimSpace(repo), "/")
+	if repo == "" {
+		repo = c.config.DefaultRepo
+	}
+	if repo == "" {
+		return "", fmt.Errorf("repo parameter is required (code_host.default_repo is not set)")
+	}
+	if !strings.Contains(repo, "/") {
+		return "", fmt.Errorf("repo must be owner/repo or group/project, got %q", repo)
+	}
+	return repo, nil
+}
+
+// ListPullRequests lists the pull requests of a repository
+func (c *CodeHostClient) ListPullRequests(ctx context.Context, system, repo string, opts ListOptions) ([]PullRequest, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, err
+	}
+	if repo, err = c.repo(repo); err != nil {
+		return nil, err
+	}
+	switch opts.State {
+	case "":
+		opts.State = "open"
+	case "open", "closed", "merged", "all":
+	default:
+		return nil, fmt.Errorf("invalid state %q (use open, closed, merged or all)", opts.State)
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultListLimit
+	}
+	opts.Limit = min(opts.Limit, 100)
+	return b.ListPullRequests(ctx, repo, opts)
+}
+
+// PullRequestDiff returns the changed files of a pull request matching pathPattern. Patches are returned
+// until code_host.max_diff_bytes (or maxBytes when smaller) is reached; later files only list their counts.
+func (c *CodeHostClient) PullRequestDiff(ctx context.Context, system, repo string, number int, pathPattern string, maxBytes int) (*PullRequestDiff, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, err
+	}
+	if repo, err = c.repo(repo); err != nil {
+		return nil, err
+	}
+	if number <= 0 {
+		return nil, fmt.Errorf("number parameter is required")
+	}
+	if maxBytes <= 0 || maxBytes > c.maxDiffBytes {
+		maxBytes = c.maxDiffBytes
+	}
+
+	diff, err := b.PullRequestDiff(ctx, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	files := diff.Files[:0]
+	budget, omitted := maxBytes, 0
+	for _, file := range diff.Files {
+		if !matchPath(pathPattern, file.Path) && !matchPath(pathPattern, file.OldPath) {
+			continue
+		}
+		if file.Patch != "" && len(file.Patch) > budget {
+			file.Patch = ""
+			file.PatchOmitted = "over max_diff_bytes"
+			omitted++
+		} else {
+			budget -= len(file.Patch)
+		}
+		files = append(files, file)
+	}
+	diff.Files = files
+	if omitted > 0 {
+		diff.Notes = append(diff.Notes, fmt.Sprintf("%d patches left out to stay within %d bytes; ask for them with path", omitted, maxBytes))
+	}
+	return diff, nil
+}
+
+// IssueThread returns an issue or pull request with its comments
+func (c *CodeHostClient) IssueThread(ctx context.Context, system, repo string, number int, pullRequest bool, maxComments int) (*IssueThread, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, err
+	}
+	if repo, err = c.repo(repo); err != nil {
+		return nil, err
+	}
+	if number <= 0 {
+		return nil, fmt.Errorf("number parameter is required")
+	}
+	if maxComments <= 0 || maxComments > c.maxComments {
+		maxComments = c.maxComments
+	}
+	return b.IssueThread(ctx, repo, number, pullRequest, maxComments)
+}
+
+// Checks returns the CI checks of a pull request or ref
+func (c *CodeHostClient) Checks(ctx context.Context, system, repo string, number int, ref string) (*CheckSummary, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, err
+	}
+	if repo, err = c.repo(repo); err != nil {
+		return nil, err
+	}
+	if number <= 0 && ref == "" {
+		return nil, fmt.Errorf("number or ref parameter is required")
+	}
+	summary, err := b.Checks(ctx, repo, number, ref)
+	if err != nil {
+		return nil, err
+	}
+	summary.Counts = map[string]int{}
+	for _, check := range summary.Checks {
+		summary.Counts[checkOutcome(check)]++
+	}
+	summary.State = overallState(summary.Counts)
+	sort.SliceStable(summary.Checks, func(i, j int) bool {
+		return outcomeOrder[checkOutcome(summary.Checks[i])] < outcomeOrder[checkOutcome(summary.Checks[j])]
+	})
+	return summary, nil
+}
+
+// Close closes the code host client
+func (c *CodeHostClient) Close() error {
+	return nil
+}
+
+// outcomeOrder lists failed checks first, then running ones
+var outcomeOrder = map[string]int{"failure": 0, "pending": 1, "neutral": 2, "success": 3}
+
+// checkOutcome reduces a check to failure, pending, neutral or success
+func checkOutcome(check Check) string {
+	if check.Status != "completed" {
+		return "pending"
+	}
+	switch check.Conclusion {
+	case "success":
+		return "success"
+	case "failure", "cancelled", "timed_out", "action_required", "startup_failure", "error":
+		return "failure"
+	}
+	return "neutral" // skipped, neutral, stale, allowed failures
+}
+
+func overallState(counts map[string]int) string {
+	switch {
+	case counts["failure"] > 0:
+		return "failure"
+	case counts["pending"] > 0:
+		return "pending"
+	case counts["success"]+counts["neutral"] > 0:
+		return "success"
+	}
+	return "none"
+}
+
+// matchPath matches a glob pattern against the path or its base name, or a substring when there are no
+// glob characters
+func matchPath(pattern, file string) bool {
+	if pattern == "" {
+		return true
+	}
+	if file == "" {
+		return false
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		if matched, err := path.Match(pattern, file); err == nil && matched {
+			return true
+		}
+		matched, err := path.Match(pattern, path.Base(file))
+		return err == nil && matched
+	}
+	return strings.Contains(file, pattern)
+}
+
+// truncateBody cuts a body to maxBodyChars
+func truncateBody(body string) string {
+	if runes := []rune(body); len(runes) > maxBodyChars {
+		return string(runes[:maxBodyChars]) + fmt.Sprintf("\n...(%d more characters)", len(runes)-maxBodyChars)
+	}
+	return body
+}
+
+// apiError turns an error response into an error carrying the service's message
+func apiError(system string, resp *resty.Response) error {
+	body := strings.TrimSpace(resp.String())
+	if len(body) > 500 {
+		body = body[:500] + "..."
+	}
+	if body == "" {
+		return fmt.Errorf("%s API error: %s", system, resp.Status())
+	}
+	return fmt.Errorf("%s API error: %s: %s", system, resp.Status(), body)
+}
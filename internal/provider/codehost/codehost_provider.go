@@ -0,0 +1,332 @@
+package codehost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// CodeHostProvider exposes pull requests, diffs, issue threads and CI checks from GitHub and GitLab
+type CodeHostProvider struct {
+	*provider.BaseProvider
+	client *CodeHostClient
+}
+
+// NewCodeHostProvider creates a new code host provider with config; the CI/CD tokens are used for the
+// services the code_host section gives none
+func NewCodeHostProvider(cfg *config.CodeHostConfig, cicd *config.CICDConfig) *CodeHostProvider {
+	p := &CodeHostProvider{
+		BaseProvider: provider.NewBaseProvider("code_host"),
+		client:       NewCodeHostClient(cfg, cicd),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Code host not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Code host provider initialized successfully (%s)", strings.Join(p.client.Systems(), ", "))
+
+	return p
+}
+
+// Client returns the code host client
+func (p *CodeHostProvider) Client() *CodeHostClient {
+	return p.client
+}
+
+// Test tests the code host configuration (for ProviderClient interface compatibility)
+func (p *CodeHostProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("code_host provider not available")
+	}
+	return nil
+}
+
+// AddTools adds code host tools to the MCP server (for ProviderClient interface compatibility)
+func (p *CodeHostProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the code host provider
+func (p *CodeHostProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds code host tools to the MCP server
+func (p *CodeHostProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Code host provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createListPullRequestsTool(),
+		p.createPullRequestDiffTool(),
+		p.createIssueThreadTool(),
+		p.createChecksTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered code host tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All code host tools registered successfully")
+}
+
+// repoDescription describes the repo parameter, naming the default repository when there is one
+func (p *CodeHostProvider) repoDescription() string {
+	description := "Repository as owner/repo (GitHub) or group/project (GitLab)"
+	if repo := p.client.DefaultRepo(); repo != "" {
+		description += fmt.Sprintf("; defaults to %s", repo)
+	}
+	return description
+}
+
+// systemProperty is the JSON schema of the system parameter
+func (p *CodeHostProvider) systemProperty() string {
+	return fmt.Sprintf(`{"type": "string", "enum": ["github", "gitlab"], "description": "Code host to query; optional when one is configured (configured: %s)"}`,
+		strings.Join(p.client.Systems(), ", "))
+}
+
+// createListPullRequestsTool creates the pull request listing tool
+func (p *CodeHostProvider) createListPullRequestsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "code_list_prs",
+		Description: "List the pull requests (GitHub) or merge requests (GitLab) of a repository, most recently updated first, with their author, branches, labels, reviewers and draft state",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"repo": {
+					"type": "string",
+					"description": %q
+				},
+				"system": %s,
+				"state": {
+					"type": "string",
+					"enum": ["open", "closed", "merged", "all"],
+					"default": "open"
+				},
+				"author": {
+					"type": "string",
+					"description": "Only pull requests opened by this user name"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum pull requests to return (at most 100)",
+					"default": 20
+				}
+			}
+		}`, p.repoDescription(), p.systemProperty())),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Repo   string `json:"repo,omitempty"`
+			System string `json:"system,omitempty"`
+			State  string `json:"state,omitempty"`
+			Author string `json:"author,omitempty"`
+			Limit  int    `json:"limit,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		pulls, err := p.client.ListPullRequests(ctx, args.System, args.Repo, ListOptions{State: args.State, Author: args.Author, Limit: args.Limit})
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(map[string]interface{}{
+			"pull_requests": pulls,
+			"count":         len(pulls),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createPullRequestDiffTool creates the pull request diff tool
+func (p *CodeHostProvider) createPullRequestDiffTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "code_pr_diff",
+		Description: "Fetch the diff of a pull request or merge request: every changed file with its status, added and removed lines and unified patch. Past the size limit patches are left out; narrow with path to read them",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"number": {
+					"type": "integer",
+					"description": "Pull request number, or merge request IID"
+				},
+				"repo": {
+					"type": "string",
+					"description": %q
+				},
+				"system": %s,
+				"path": {
+					"type": "string",
+					"description": "Only files matching this glob (*.go, internal/*) or path substring"
+				},
+				"max_bytes": {
+					"type": "integer",
+					"description": "Patch bytes to return, at most code_host.max_diff_bytes"
+				}
+			},
+			"required": ["number"]
+		}`, p.repoDescription(), p.systemProperty())),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Number   int    `json:"number"`
+			Repo     string `json:"repo,omitempty"`
+			System   string `json:"system,omitempty"`
+			Path     string `json:"path,omitempty"`
+			MaxBytes int    `json:"max_bytes,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		diff, err := p.client.PullRequestDiff(ctx, args.System, args.Repo, args.Number, args.Path, args.MaxBytes)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(diff), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createIssueThreadTool creates the issue thread tool
+func (p *CodeHostProvider) createIssueThreadTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "code_issue_thread",
+		Description: "Read an issue, or the conversation of a pull request or merge request: its description, labels, assignees and comments, oldest first",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"number": {
+					"type": "integer",
+					"description": "Issue or pull request number (GitLab: IID)"
+				},
+				"repo": {
+					"type": "string",
+					"description": %q
+				},
+				"system": %s,
+				"pull_request": {
+					"type": "boolean",
+					"description": "The number is a pull or merge request (GitLab numbers merge requests separately from issues)",
+					"default": false
+				},
+				"max_comments": {
+					"type": "integer",
+					"description": "Comments to return, at most %d",
+					"default": %d
+				}
+			},
+			"required": ["number"]
+		}`, p.repoDescription(), p.systemProperty(), p.client.MaxComments(), p.client.MaxComments())),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Number      int    `json:"number"`
+			Repo        string `json:"repo,omitempty"`
+			System      string `json:"system,omitempty"`
+			PullRequest bool   `json:"pull_request,omitempty"`
+			MaxComments int    `json:"max_comments,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		thread, err := p.client.IssueThread(ctx, args.System, args.Repo, args.Number, args.PullRequest, args.MaxComments)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(thread), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createChecksTool creates the CI check status tool
+func (p *CodeHostProvider) createChecksTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "code_pr_checks",
+		Description: "Fetch the CI checks of a pull request's head commit, or of a branch, tag or commit: GitHub check runs and commit statuses, or the jobs of the GitLab pipeline. Returns the overall state (success, failure, pending or none), counts and each check, failures first",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"number": {
+					"type": "integer",
+					"description": "Pull request number, or merge request IID"
+				},
+				"ref": {
+					"type": "string",
+					"description": "Branch, tag or commit SHA, instead of number"
+				},
+				"repo": {
+					"type": "string",
+					"description": %q
+				},
+				"system": %s
+			}
+		}`, p.repoDescription(), p.systemProperty())),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Number int    `json:"number,omitempty"`
+			Ref    string `json:"ref,omitempty"`
+			Repo   string `json:"repo,omitempty"`
+			System string `json:"system,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		checks, err := p.client.Checks(ctx, args.System, args.Repo, args.Number, args.Ref)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(checks), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *CodeHostProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Code Host Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *CodeHostProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that CodeHostProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*CodeHostProvider)(nil)
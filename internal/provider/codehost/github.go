@@ -0,0 +1,344 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+// githubBackend talks to the GitHub REST API
+type githubBackend struct {
+	client *resty.Client
+}
+
+func newGitHubBackend(cfg *config.CodeHostServer) *githubBackend {
+	baseURL := "https://api.github.com"
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetHeader("Authorization", "Bearer "+cfg.Token).
+		SetHeader("Accept", "application/vnd.github+json").
+		SetHeader("X-GitHub-Api-Version", "2022-11-28").
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+
+	return &githubBackend{client: client}
+}
+
+func (b *githubBackend) Name() string { return "github" }
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubPull struct {
+	Number             int           `json:"number"`
+	Title              string        `json:"title"`
+	State              string        `json:"state"`
+	Draft              bool          `json:"draft"`
+	User               githubUser    `json:"user"`
+	Body               string        `json:"body"`
+	Labels             []githubLabel `json:"labels"`
+	RequestedReviewers []githubUser  `json:"requested_reviewers"`
+	HTMLURL            string        `json:"html_url"`
+	CreatedAt          *time.Time    `json:"created_at"`
+	UpdatedAt          *time.Time    `json:"updated_at"`
+	MergedAt           *time.Time    `json:"merged_at"`
+	Head               struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
+
+func (pr *githubPull) normalize() PullRequest {
+	state := pr.State
+	if pr.MergedAt != nil {
+		state = "merged"
+	}
+	result := PullRequest{
+		Number:    pr.Number,
+		Title:     pr.Title,
+		State:     state,
+		Draft:     pr.Draft,
+		Author:    pr.User.Login,
+		Source:    pr.Head.Ref,
+		Target:    pr.Base.Ref,
+		URL:       pr.HTMLURL,
+		CreatedAt: pr.CreatedAt,
+		UpdatedAt: pr.UpdatedAt,
+	}
+	for _, label := range pr.Labels {
+		result.Labels = append(result.Labels, label.Name)
+	}
+	for _, reviewer := range pr.RequestedReviewers {
+		result.Reviewers = append(result.Reviewers, reviewer.Login)
+	}
+	return result
+}
+
+// get fetches a path into result
+func (b *githubBackend) get(ctx context.Context, path string, query map[string]string, result interface{}) error {
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetQueryParams(query).
+		SetResult(result).
+		Get(path)
+	if err != nil {
+		return fmt.Errorf("github request failed: %w", err)
+	}
+	if resp.IsError() {
+		return apiError("github", resp)
+	}
+	return nil
+}
+
+// ListPullRequests lists pull requests of an "owner/repo" repository
+func (b *githubBackend) ListPullRequests(ctx context.Context, repo string, opts ListOptions) ([]PullRequest, error) {
+	state := opts.State
+	if state == "merged" {
+		state = "closed"
+	}
+	perPage := opts.Limit
+	if opts.Author != "" || opts.State == "merged" {
+		perPage = 100 // Filtered below: the pulls API filters neither
+	}
+
+	var pulls []githubPull
+	if err := b.get(ctx, fmt.Sprintf("/repos/%s/pulls", repo), map[string]string{
+		"state":     state,
+		"sort":      "updated",
+		"direction": "desc",
+		"per_page":  strconv.Itoa(perPage),
+	}, &pulls); err != nil {
+		return nil, err
+	}
+
+	result := []PullRequest{}
+	for i := range pulls {
+		pr := pulls[i].normalize()
+		if opts.State == "merged" && pr.State != "merged" {
+			continue
+		}
+		if opts.Author != "" && !strings.EqualFold(pr.Author, opts.Author) {
+			continue
+		}
+		result = append(result, pr)
+		if len(result) == opts.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// PullRequestDiff returns the files of a pull request with their patches
+func (b *githubBackend) PullRequestDiff(ctx context.Context, repo string, number int) (*PullRequestDiff, error) {
+	var pull githubPull
+	if err := b.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), nil, &pull); err != nil {
+		return nil, err
+	}
+	diff := &PullRequestDiff{
+		Number:       pull.Number,
+		Title:        pull.Title,
+		Source:       pull.Head.Ref,
+		Target:       pull.Base.Ref,
+		HeadSHA:      pull.Head.SHA,
+		ChangedFiles: pull.ChangedFiles,
+		Additions:    pull.Additions,
+		Deletions:    pull.Deletions,
+		Files:        []FileDiff{},
+	}
+
+	for page := 1; len(diff.Files) < maxDiffFiles; page++ {
+		var files []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+			Status           string `json:"status"`
+			Additions        int    `json:"additions"`
+			Deletions        int    `json:"deletions"`
+			Patch            string `json:"patch"`
+		}
+		if err := b.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d/files", repo, number), map[string]string{
+			"per_page": "100",
+			"page":     strconv.Itoa(page),
+		}, &files); err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			file := FileDiff{
+				Path:      f.Filename,
+				OldPath:   f.PreviousFilename,
+				Status:    f.Status,
+				Additions: f.Additions,
+				Deletions: f.Deletions,
+				Patch:     f.Patch,
+			}
+			if f.Patch == "" && f.Additions+f.Deletions > 0 {
+				file.PatchOmitted = "binary or too large for the API"
+			}
+			diff.Files = append(diff.Files, file)
+		}
+		if len(files) < 100 {
+			break
+		}
+	}
+	if diff.ChangedFiles > len(diff.Files) {
+		diff.Notes = append(diff.Notes, fmt.Sprintf("the API lists only %d of %d changed files", len(diff.Files), diff.ChangedFiles))
+	}
+	return diff, nil
+}
+
+// IssueThread returns an issue or pull request conversation; pull requests are issues in the GitHub API
+func (b *githubBackend) IssueThread(ctx context.Context, repo string, number int, pullRequest bool, maxComments int) (*IssueThread, error) {
+	var issue struct {
+		githubPull
+		Comments    int          `json:"comments"`
+		Assignees   []githubUser `json:"assignees"`
+		PullRequest *struct{}    `json:"pull_request"`
+	}
+	if err := b.get(ctx, fmt.Sprintf("/repos/%s/issues/%d", repo, number), nil, &issue); err != nil {
+		return nil, err
+	}
+	if pullRequest && issue.PullRequest == nil {
+		return nil, fmt.Errorf("#%d in %s is an issue, not a pull request", number, repo)
+	}
+
+	thread := &IssueThread{
+		Number:    issue.Number,
+		Title:     issue.Title,
+		State:     issue.State,
+		Author:    issue.User.Login,
+		URL:       issue.HTMLURL,
+		Body:      truncateBody(issue.Body),
+		CreatedAt: issue.CreatedAt,
+		Comments:  []Comment{},
+	}
+	for _, label := range issue.Labels {
+		thread.Labels = append(thread.Labels, label.Name)
+	}
+	for _, assignee := range issue.Assignees {
+		thread.Assignees = append(thread.Assignees, assignee.Login)
+	}
+
+	for page := 1; len(thread.Comments) < maxComments; page++ {
+		var comments []struct {
+			User      githubUser `json:"user"`
+			Body      string     `json:"body"`
+			HTMLURL   string     `json:"html_url"`
+			CreatedAt *time.Time `json:"created_at"`
+		}
+		if err := b.get(ctx, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number), map[string]string{
+			"per_page": "100",
+			"page":     strconv.Itoa(page),
+		}, &comments); err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if len(thread.Comments) == maxComments {
+				break
+			}
+			thread.Comments = append(thread.Comments, Comment{
+				Author:    comment.User.Login,
+				Body:      truncateBody(comment.Body),
+				CreatedAt: comment.CreatedAt,
+				URL:       comment.HTMLURL,
+			})
+		}
+		if len(comments) < 100 {
+			break
+		}
+	}
+	if issue.Comments > len(thread.Comments) {
+		thread.Truncated = fmt.Sprintf("showing the first %d of %d comments", len(thread.Comments), issue.Comments)
+	}
+	return thread, nil
+}
+
+// Checks returns the check runs and commit statuses of a pull request's head commit or of a ref
+func (b *githubBackend) Checks(ctx context.Context, repo string, number int, ref string) (*CheckSummary, error) {
+	summary := &CheckSummary{Ref: ref, Checks: []Check{}}
+	if number > 0 {
+		var pull githubPull
+		if err := b.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), nil, &pull); err != nil {
+			return nil, err
+		}
+		ref = pull.Head.SHA
+		summary.Ref = fmt.Sprintf("#%d (%s)", number, pull.Head.Ref)
+	}
+	summary.SHA = ref
+
+	var runs struct {
+		CheckRuns []struct {
+			Name        string     `json:"name"`
+			HeadSHA     string     `json:"head_sha"`
+			Status      string     `json:"status"`
+			Conclusion  string     `json:"conclusion"`
+			HTMLURL     string     `json:"html_url"`
+			StartedAt   *time.Time `json:"started_at"`
+			CompletedAt *time.Time `json:"completed_at"`
+			Output      struct {
+				Title string `json:"title"`
+			} `json:"output"`
+		} `json:"check_runs"`
+	}
+	if err := b.get(ctx, fmt.Sprintf("/repos/%s/commits/%s/check-runs", repo, url.PathEscape(ref)), map[string]string{"per_page": "100"}, &runs); err != nil {
+		return nil, err
+	}
+	for _, run := range runs.CheckRuns {
+		summary.SHA = run.HeadSHA
+		summary.Checks = append(summary.Checks, Check{
+			Name:        run.Name,
+			Status:      run.Status,
+			Conclusion:  run.Conclusion,
+			Description: run.Output.Title,
+			URL:         run.HTMLURL,
+			StartedAt:   run.StartedAt,
+			CompletedAt: run.CompletedAt,
+		})
+	}
+
+	// Commit statuses come from integrations that predate the checks API
+	var combined struct {
+		SHA      string `json:"sha"`
+		Statuses []struct {
+			Context     string     `json:"context"`
+			State       string     `json:"state"` // error, failure, pending or success
+			Description string     `json:"description"`
+			TargetURL   string     `json:"target_url"`
+			UpdatedAt   *time.Time `json:"updated_at"`
+		} `json:"statuses"`
+	}
+	if err := b.get(ctx, fmt.Sprintf("/repos/%s/commits/%s/status", repo, url.PathEscape(ref)), map[string]string{"per_page": "100"}, &combined); err != nil {
+		return nil, err
+	}
+	if combined.SHA != "" {
+		summary.SHA = combined.SHA
+	}
+	for _, status := range combined.Statuses {
+		check := Check{Name: status.Context, Status: "completed", Conclusion: status.State, Description: status.Description, URL: status.TargetURL, CompletedAt: status.UpdatedAt}
+		if status.State == "pending" {
+			check.Status, check.Conclusion, check.CompletedAt = "in_progress", "", nil
+		}
+		summary.Checks = append(summary.Checks, check)
+	}
+	return summary, nil
+}
@@ -0,0 +1,369 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+// shaPattern matches full and abbreviated commit SHAs
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// gitlabBackend talks to the GitLab REST API
+type gitlabBackend struct {
+	client *resty.Client
+}
+
+func newGitLabBackend(cfg *config.CodeHostServer) *gitlabBackend {
+	baseURL := "https://gitlab.com"
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL+"/api/v4").
+		SetHeader("PRIVATE-TOKEN", cfg.Token).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+
+	return &gitlabBackend{client: client}
+}
+
+func (b *gitlabBackend) Name() string { return "gitlab" }
+
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+type gitlabMergeRequest struct {
+	IID          int          `json:"iid"`
+	Title        string       `json:"title"`
+	State        string       `json:"state"` // opened, closed, locked or merged
+	Draft        bool         `json:"draft"`
+	Author       gitlabUser   `json:"author"`
+	Description  string       `json:"description"`
+	SourceBranch string       `json:"source_branch"`
+	TargetBranch string       `json:"target_branch"`
+	SHA          string       `json:"sha"`
+	Labels       []string     `json:"labels"`
+	Reviewers    []gitlabUser `json:"reviewers"`
+	Assignees    []gitlabUser `json:"assignees"`
+	WebURL       string       `json:"web_url"`
+	CreatedAt    *time.Time   `json:"created_at"`
+	UpdatedAt    *time.Time   `json:"updated_at"`
+	HeadPipeline *struct {
+		ID     int64  `json:"id"`
+		SHA    string `json:"sha"`
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+func (mr *gitlabMergeRequest) normalize() PullRequest {
+	result := PullRequest{
+		Number:    mr.IID,
+		Title:     mr.Title,
+		State:     gitlabState(mr.State),
+		Draft:     mr.Draft,
+		Author:    mr.Author.Username,
+		Source:    mr.SourceBranch,
+		Target:    mr.TargetBranch,
+		Labels:    mr.Labels,
+		URL:       mr.WebURL,
+		CreatedAt: mr.CreatedAt,
+		UpdatedAt: mr.UpdatedAt,
+	}
+	for _, reviewer := range mr.Reviewers {
+		result.Reviewers = append(result.Reviewers, reviewer.Username)
+	}
+	return result
+}
+
+// gitlabState maps GitLab states onto open, closed and merged
+func gitlabState(state string) string {
+	if state == "opened" {
+		return "open"
+	}
+	return state
+}
+
+// project encodes a "group/project" path as a GitLab project ID
+func (b *gitlabBackend) project(repo string) string {
+	return url.PathEscape(repo)
+}
+
+// get fetches a path into result
+func (b *gitlabBackend) get(ctx context.Context, path string, query map[string]string, result interface{}) (*resty.Response, error) {
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetQueryParams(query).
+		SetResult(result).
+		Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab request failed: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError("gitlab", resp)
+	}
+	return resp, nil
+}
+
+// ListPullRequests lists merge requests of a "group/project" repository
+func (b *gitlabBackend) ListPullRequests(ctx context.Context, repo string, opts ListOptions) ([]PullRequest, error) {
+	state := opts.State
+	if state == "open" {
+		state = "opened"
+	}
+	query := map[string]string{
+		"state":    state,
+		"order_by": "updated_at",
+		"sort":     "desc",
+		"per_page": strconv.Itoa(opts.Limit),
+	}
+	if opts.Author != "" {
+		query["author_username"] = opts.Author
+	}
+
+	var mrs []gitlabMergeRequest
+	if _, err := b.get(ctx, fmt.Sprintf("/projects/%s/merge_requests", b.project(repo)), query, &mrs); err != nil {
+		return nil, err
+	}
+	result := make([]PullRequest, 0, len(mrs))
+	for i := range mrs {
+		result = append(result, mrs[i].normalize())
+	}
+	return result, nil
+}
+
+// PullRequestDiff returns the changes of a merge request
+func (b *gitlabBackend) PullRequestDiff(ctx context.Context, repo string, number int) (*PullRequestDiff, error) {
+	var mr struct {
+		gitlabMergeRequest
+		ChangesCount string `json:"changes_count"` // "1000+" past the limit
+		Overflow     bool   `json:"overflow"`
+		Changes      []struct {
+			OldPath     string `json:"old_path"`
+			NewPath     string `json:"new_path"`
+			NewFile     bool   `json:"new_file"`
+			RenamedFile bool   `json:"renamed_file"`
+			DeletedFile bool   `json:"deleted_file"`
+			Diff        string `json:"diff"`
+		} `json:"changes"`
+	}
+	if _, err := b.get(ctx, fmt.Sprintf("/projects/%s/merge_requests/%d/changes", b.project(repo), number), nil, &mr); err != nil {
+		return nil, err
+	}
+
+	diff := &PullRequestDiff{
+		Number:  mr.IID,
+		Title:   mr.Title,
+		Source:  mr.SourceBranch,
+		Target:  mr.TargetBranch,
+		HeadSHA: mr.SHA,
+		Files:   []FileDiff{},
+	}
+	for _, change := range mr.Changes {
+		file := FileDiff{Path: change.NewPath, Status: "modified", Patch: change.Diff}
+		switch {
+		case change.NewFile:
+			file.Status = "added"
+		case change.DeletedFile:
+			file.Status = "removed"
+			file.Path = change.OldPath
+		case change.RenamedFile:
+			file.Status = "renamed"
+			file.OldPath = change.OldPath
+		}
+		file.Additions, file.Deletions = countChanges(change.Diff)
+		if change.Diff == "" && !change.RenamedFile {
+			file.PatchOmitted = "binary or too large for the API"
+		}
+		diff.Additions += file.Additions
+		diff.Deletions += file.Deletions
+		diff.Files = append(diff.Files, file)
+	}
+	diff.ChangedFiles = len(diff.Files)
+	if mr.Overflow {
+		diff.Notes = append(diff.Notes, fmt.Sprintf("the API lists only %d of %s changed files", len(diff.Files), mr.ChangesCount))
+	}
+	return diff, nil
+}
+
+// countChanges counts the added and removed lines of a unified diff
+func countChanges(patch string) (int, int) {
+	additions, deletions := 0, 0
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// IssueThread returns an issue or merge request with its user comments; system notes are left out
+func (b *gitlabBackend) IssueThread(ctx context.Context, repo string, number int, pullRequest bool, maxComments int) (*IssueThread, error) {
+	kind := "issues"
+	if pullRequest {
+		kind = "merge_requests"
+	}
+	base := fmt.Sprintf("/projects/%s/%s/%d", b.project(repo), kind, number)
+
+	var item gitlabMergeRequest
+	if _, err := b.get(ctx, base, nil, &item); err != nil {
+		return nil, err
+	}
+	thread := &IssueThread{
+		Number:    item.IID,
+		Title:     item.Title,
+		State:     gitlabState(item.State),
+		Author:    item.Author.Username,
+		Labels:    item.Labels,
+		URL:       item.WebURL,
+		Body:      truncateBody(item.Description),
+		CreatedAt: item.CreatedAt,
+		Comments:  []Comment{},
+	}
+	for _, assignee := range item.Assignees {
+		thread.Assignees = append(thread.Assignees, assignee.Username)
+	}
+
+	more := false
+	for page := 1; ; page++ {
+		var notes []struct {
+			ID        int64      `json:"id"`
+			Author    gitlabUser `json:"author"`
+			Body      string     `json:"body"`
+			System    bool       `json:"system"`
+			CreatedAt *time.Time `json:"created_at"`
+		}
+		resp, err := b.get(ctx, base+"/notes", map[string]string{
+			"sort":     "asc",
+			"order_by": "created_at",
+			"per_page": "100",
+			"page":     strconv.Itoa(page),
+		}, &notes)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			if note.System {
+				continue
+			}
+			if len(thread.Comments) == maxComments {
+				more = true
+				break
+			}
+			thread.Comments = append(thread.Comments, Comment{
+				Author:    note.Author.Username,
+				Body:      truncateBody(note.Body),
+				CreatedAt: note.CreatedAt,
+				URL:       fmt.Sprintf("%s#note_%d", item.WebURL, note.ID),
+			})
+		}
+		if more || resp.Header().Get("X-Next-Page") == "" {
+			break
+		}
+	}
+	if more {
+		thread.Truncated = fmt.Sprintf("showing the first %d comments", maxComments)
+	}
+	return thread, nil
+}
+
+// Checks returns the jobs of the head pipeline of a merge request, or of the latest pipeline of a ref
+func (b *gitlabBackend) Checks(ctx context.Context, repo string, number int, ref string) (*CheckSummary, error) {
+	project := b.project(repo)
+	summary := &CheckSummary{Ref: ref, Checks: []Check{}}
+
+	var pipelineID int64
+	if number > 0 {
+		var mr gitlabMergeRequest
+		if _, err := b.get(ctx, fmt.Sprintf("/projects/%s/merge_requests/%d", project, number), nil, &mr); err != nil {
+			return nil, err
+		}
+		summary.Ref = fmt.Sprintf("!%d (%s)", number, mr.SourceBranch)
+		summary.SHA = mr.SHA
+		if mr.HeadPipeline == nil {
+			return summary, nil
+		}
+		pipelineID = mr.HeadPipeline.ID
+	} else {
+		query := map[string]string{"per_page": "1", "order_by": "id", "sort": "desc"}
+		if shaPattern.MatchString(ref) {
+			query["sha"] = ref
+		} else {
+			query["ref"] = ref
+		}
+		var pipelines []struct {
+			ID  int64  `json:"id"`
+			SHA string `json:"sha"`
+		}
+		if _, err := b.get(ctx, fmt.Sprintf("/projects/%s/pipelines", project), query, &pipelines); err != nil {
+			return nil, err
+		}
+		if len(pipelines) == 0 {
+			return summary, nil
+		}
+		pipelineID = pipelines[0].ID
+		summary.SHA = pipelines[0].SHA
+	}
+
+	for page := 1; ; page++ {
+		var jobs []struct {
+			Name         string     `json:"name"`
+			Stage        string     `json:"stage"`
+			Status       string     `json:"status"`
+			AllowFailure bool       `json:"allow_failure"`
+			WebURL       string     `json:"web_url"`
+			StartedAt    *time.Time `json:"started_at"`
+			FinishedAt   *time.Time `json:"finished_at"`
+		}
+		resp, err := b.get(ctx, fmt.Sprintf("/projects/%s/pipelines/%d/jobs", project, pipelineID), map[string]string{
+			"per_page": "100",
+			"page":     strconv.Itoa(page),
+		}, &jobs)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			check := Check{Name: job.Stage + "/" + job.Name, URL: job.WebURL, StartedAt: job.StartedAt, CompletedAt: job.FinishedAt}
+			check.Status, check.Conclusion = gitlabJobStatus(job.Status, job.AllowFailure)
+			summary.Checks = append(summary.Checks, check)
+		}
+		if resp.Header().Get("X-Next-Page") == "" {
+			break
+		}
+	}
+	return summary, nil
+}
+
+// gitlabJobStatus maps a job status onto the status and conclusion of a check
+func gitlabJobStatus(status string, allowFailure bool) (string, string) {
+	switch status {
+	case "success":
+		return "completed", "success"
+	case "failed":
+		if allowFailure {
+			return "completed", "allowed_failure"
+		}
+		return "completed", "failure"
+	case "canceled":
+		return "completed", "cancelled"
+	case "skipped", "manual":
+		return "completed", status
+	case "running":
+		return "in_progress", ""
+	}
+	return "queued", "" // created, pending, preparing, scheduled, waiting_for_resource
+}
@@ -0,0 +1,742 @@
+package codequality
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/exec"
+)
+
+const (
+	defaultTimeoutSeconds = 300
+	// Linter reports are JSON and cannot be parsed once the middle is dropped
+	maxReportBytes = 8 << 20
+	maxFiles       = 200
+	fixSuffix      = "-fix"
+	// fileArgPattern keeps paths from being read as options
+	fileArgPattern = `[^-].*`
+)
+
+// Diagnostic is one finding of a formatter or linter
+type Diagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Linter   string `json:"linter"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message"`
+	Fixable  bool   `json:"fixable,omitempty"`
+}
+
+// LinterRun is the outcome of one linter invocation
+type LinterRun struct {
+	Linter   string   `json:"linter"`
+	Targets  []string `json:"targets"`
+	ExitCode int      `json:"exit_code"`
+	Duration string   `json:"duration"`
+	Issues   int      `json:"issues"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Report groups diagnostics by file
+type Report struct {
+	Dir          string                  `json:"dir"`
+	Files        map[string][]Diagnostic `json:"files"`
+	Summary      Summary                 `json:"summary"`
+	Runs         []LinterRun             `json:"runs"`
+	ChangedFiles []string                `json:"changed_files,omitempty"`
+}
+
+// Summary counts diagnostics
+type Summary struct {
+	Files    int `json:"files_with_issues"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Fixable  int `json:"fixable"`
+}
+
+// Request selects the files and linters of a check or fix
+type Request struct {
+	Dir     string
+	Files   []string
+	Linters []string
+	User    string
+}
+
+// linter describes how to run and parse a supported tool
+type linter struct {
+	name       string
+	extensions []string
+	check      []string
+	fix        []string // nil when the tool cannot fix
+	// target turns a directory argument into what the tool expects, e.g. ./... for golangci-lint
+	target func(dir string) string
+	// packages means the tool lints whole Go packages, so files are passed as their directory
+	packages bool
+	parse    func(result *exec.RunResult, dir string) ([]Diagnostic, error)
+}
+
+var supportedLinters = []*linter{
+	{
+		name:       "gofmt",
+		extensions: []string{".go"},
+		check:      []string{"gofmt", "-l", "-e"},
+		fix:        []string{"gofmt", "-w"},
+		parse:      parseGofmt,
+	},
+	{
+		name:       "golangci-lint",
+		extensions: []string{".go"},
+		check:      []string{"golangci-lint", "run", "--output.json.path", "stdout", "--output.text.path", "stderr", "--show-stats=false"},
+		fix:        []string{"golangci-lint", "run", "--fix", "--output.text.path", "stderr", "--show-stats=false"},
+		target:     func(dir string) string { return strings.TrimSuffix(dir, "/") + "/..." },
+		packages:   true,
+		parse:      parseGolangciLint,
+	},
+	{
+		name:       "eslint",
+		extensions: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts", ".vue"},
+		check:      []string{"eslint", "--format", "json"},
+		fix:        []string{"eslint", "--fix", "--format", "json"},
+		parse:      parseESLint,
+	},
+	{
+		name:       "ruff",
+		extensions: []string{".py", ".pyi"},
+		check:      []string{"ruff", "check", "--output-format", "json", "--no-cache"},
+		fix:        []string{"ruff", "check", "--fix", "--output-format", "json", "--no-cache"},
+		parse:      parseRuff,
+	},
+}
+
+// CodeQualityClient runs formatters and linters through an exec sandbox
+type CodeQualityClient struct {
+	runner   *exec.ExecClient
+	linters  []*linter
+	allowFix bool
+	initErr  error
+}
+
+// NewCodeQualityClient creates a new code quality client; linters whose executable is missing are skipped
+func NewCodeQualityClient(cfg *config.CodeQualityConfig) *CodeQualityClient {
+	c := &CodeQualityClient{}
+	if cfg == nil || !cfg.Enabled {
+		return c
+	}
+	c.allowFix = cfg.AllowFix
+
+	configured := cfg.Linters
+	if len(configured) == 0 {
+		for _, l := range supportedLinters {
+			configured = append(configured, config.CodeQualityLinterConfig{Name: l.name})
+		}
+	}
+
+	// Each linter becomes an exec command whose only extra arguments are file paths
+	execCfg := &config.ExecConfig{
+		Enabled:        true,
+		Directories:    cfg.Directories,
+		Env:            cfg.Env,
+		TimeoutSeconds: defaultTimeoutSeconds,
+		MaxOutputBytes: maxReportBytes,
+		AuditLog:       cfg.AuditLog,
+	}
+	if cfg.TimeoutSeconds > 0 {
+		execCfg.TimeoutSeconds = cfg.TimeoutSeconds
+	}
+	for _, lc := range configured {
+		l := findLinter(lc.Name)
+		if l == nil {
+			log.Printf("⚠ Skipping unknown linter %q (supported: gofmt, golangci-lint, eslint, ruff)", lc.Name)
+			continue
+		}
+		execCfg.Commands = append(execCfg.Commands, config.ExecCommandConfig{
+			Name:        l.name,
+			Command:     commandLine(lc.Command, l.check),
+			ArgsPattern: fileArgPattern,
+		})
+		if c.allowFix && l.fix != nil {
+			execCfg.Commands = append(execCfg.Commands, config.ExecCommandConfig{
+				Name:        l.name + fixSuffix,
+				Command:     commandLine(lc.Command, l.fix),
+				ArgsPattern: fileArgPattern,
+			})
+		}
+	}
+
+	c.runner = exec.NewExecClient(execCfg)
+	if err := c.runner.InitError(); err != nil {
+		c.initErr = err
+		return c
+	}
+	for _, command := range c.runner.Commands() {
+		if l := findLinter(command.Name); l != nil {
+			c.linters = append(c.linters, l)
+		}
+	}
+	if len(c.linters) == 0 {
+		c.initErr = fmt.Errorf("none of the configured linters are installed")
+	}
+	return c
+}
+
+// commandLine replaces the executable of argv with override, keeping the tool's own arguments
+func commandLine(override string, argv []string) string {
+	if override == "" {
+		return strings.Join(argv, " ")
+	}
+	return override + " " + strings.Join(argv[1:], " ")
+}
+
+func findLinter(name string) *linter {
+	for _, l := range supportedLinters {
+		if l.name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// IsAvailable reports whether at least one linter can run
+func (c *CodeQualityClient) IsAvailable() bool {
+	return c.runner != nil && len(c.linters) > 0
+}
+
+// InitError returns the configuration error, if any
+func (c *CodeQualityClient) InitError() error {
+	return c.initErr
+}
+
+// FixAllowed reports whether the code_fix write gate is open
+func (c *CodeQualityClient) FixAllowed() bool {
+	return c.allowFix
+}
+
+// Linters returns the usable linters and the file extensions each handles
+func (c *CodeQualityClient) Linters() map[string][]string {
+	result := map[string][]string{}
+	for _, l := range c.linters {
+		result[l.name] = l.extensions
+	}
+	return result
+}
+
+// Directories returns the project roots linters may run in
+func (c *CodeQualityClient) Directories() []string {
+	return c.runner.Directories()
+}
+
+// Close closes the audit log
+func (c *CodeQualityClient) Close() error {
+	if c.runner == nil {
+		return nil
+	}
+	return c.runner.Close()
+}
+
+// Check runs the selected linters and returns their diagnostics
+func (c *CodeQualityClient) Check(ctx context.Context, req Request) (*Report, error) {
+	dir, plan, err := c.plan(req)
+	if err != nil {
+		return nil, err
+	}
+	report := newReport(dir)
+	for _, step := range plan {
+		c.runLinter(ctx, req, dir, step, false, report)
+	}
+	report.summarize()
+	return report, nil
+}
+
+// Fix runs the fixers of the selected linters, then checks again and reports what is left and which files changed
+func (c *CodeQualityClient) Fix(ctx context.Context, req Request) (*Report, error) {
+	if !c.allowFix {
+		return nil, fmt.Errorf("applying fixes is disabled (set code_quality.allow_fix to enable)")
+	}
+	dir, plan, err := c.plan(req)
+	if err != nil {
+		return nil, err
+	}
+	before := hashTargets(dir, plan)
+
+	fixes := newReport(dir)
+	for _, step := range plan {
+		if step.linter.fix != nil {
+			c.runLinter(ctx, req, dir, step, true, fixes)
+		}
+	}
+
+	report := newReport(dir)
+	for _, step := range plan {
+		c.runLinter(ctx, req, dir, step, false, report)
+	}
+	report.summarize()
+	report.Runs = append(fixes.Runs, report.Runs...)
+
+	after := hashTargets(dir, plan)
+	for file, sum := range after {
+		if before[file] != sum {
+			report.ChangedFiles = append(report.ChangedFiles, file)
+		}
+	}
+	sort.Strings(report.ChangedFiles)
+	return report, nil
+}
+
+type planStep struct {
+	linter  *linter
+	targets []string
+	files   map[string]bool // Requested files to report on; nil reports everything
+}
+
+func (s *planStep) add(target string) {
+	if !slices.Contains(s.targets, target) {
+		s.targets = append(s.targets, target)
+	}
+}
+
+// plan resolves the working directory and assigns each target to the linters that handle it:
+// files by extension, directories to every selected linter
+func (c *CodeQualityClient) plan(req Request) (string, []planStep, error) {
+	dir, err := c.runner.ResolveDir(req.Dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	selected := c.linters
+	if len(req.Linters) > 0 {
+		selected = nil
+		for _, name := range req.Linters {
+			i := slices.IndexFunc(c.linters, func(l *linter) bool { return l.name == name })
+			if i < 0 {
+				return "", nil, fmt.Errorf("linter %q is not available", name)
+			}
+			selected = append(selected, c.linters[i])
+		}
+	}
+
+	files := req.Files
+	if len(files) == 0 {
+		files = []string{"."}
+	}
+	if len(files) > maxFiles {
+		return "", nil, fmt.Errorf("too many files (%d, max %d)", len(files), maxFiles)
+	}
+
+	steps := map[string]*planStep{}
+	for _, f := range files {
+		rel, isDir, err := resolveTarget(dir, f)
+		if err != nil {
+			return "", nil, err
+		}
+		matched := false
+		for _, l := range selected {
+			step := steps[l.name]
+			if step == nil {
+				step = &planStep{linter: l, files: map[string]bool{}}
+				steps[l.name] = step
+			}
+			switch {
+			case isDir:
+				target := rel
+				if l.target != nil {
+					target = l.target(rel)
+				}
+				step.add(target)
+				// Everything under a directory is reported
+				step.files = nil
+				matched = true
+			case slices.Contains(l.extensions, strings.ToLower(filepath.Ext(rel))):
+				target := rel
+				if l.packages {
+					target = localPath(filepath.Dir(rel))
+				}
+				step.add(target)
+				if step.files != nil {
+					step.files[rel] = true
+				}
+				matched = true
+			}
+		}
+		if !matched && len(req.Files) > 0 {
+			return "", nil, fmt.Errorf("no selected linter handles %s", f)
+		}
+	}
+
+	var plan []planStep
+	for _, l := range selected {
+		if step := steps[l.name]; step != nil && len(step.targets) > 0 {
+			plan = append(plan, *step)
+		}
+	}
+	return dir, plan, nil
+}
+
+// resolveTarget checks that a file or directory lies inside dir and returns it relative to dir as ./path
+func resolveTarget(dir, target string) (string, bool, error) {
+	if strings.ContainsRune(target, 0) {
+		return "", false, fmt.Errorf("invalid path")
+	}
+	path := target
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s not found", target)
+	}
+	rel, err := filepath.Rel(dir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false, fmt.Errorf("%s is outside %s", target, dir)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", false, err
+	}
+	return localPath(rel), info.IsDir(), nil
+}
+
+// localPath prefixes a relative path with ./ so no tool mistakes it for anything else
+func localPath(rel string) string {
+	if rel == "." {
+		return rel
+	}
+	return "./" + filepath.ToSlash(rel)
+}
+
+// runLinter runs one linter and records its diagnostics (check) or just its run (fix)
+func (c *CodeQualityClient) runLinter(ctx context.Context, req Request, dir string, step planStep, fix bool, report *Report) {
+	l, targets := step.linter, step.targets
+	name := l.name
+	if fix {
+		name += fixSuffix
+	}
+	run := LinterRun{Linter: name, Targets: targets, ExitCode: -1}
+	defer func() { report.Runs = append(report.Runs, run) }()
+
+	result, err := c.runner.Run(ctx, exec.RunRequest{Name: name, Args: targets, Dir: dir, User: req.User})
+	if err != nil {
+		run.Error = err.Error()
+		return
+	}
+	run.ExitCode = result.ExitCode
+	run.Duration = result.Duration
+	switch {
+	case result.TimedOut:
+		run.Error = "timed out"
+		return
+	case result.StdoutTruncated > 0:
+		run.Error = fmt.Sprintf("report too large (%d bytes omitted); lint fewer files", result.StdoutTruncated)
+		return
+	}
+
+	// Fixers report what they could not fix; the check that follows covers that
+	if fix {
+		return
+	}
+
+	diagnostics, err := l.parse(result, dir)
+	if err != nil {
+		run.Error = err.Error()
+		return
+	}
+	for _, d := range diagnostics {
+		if step.files != nil && !step.files[d.File] {
+			continue
+		}
+		d.Linter = l.name
+		report.Files[d.File] = append(report.Files[d.File], d)
+		run.Issues++
+	}
+}
+
+func newReport(dir string) *Report {
+	return &Report{Dir: dir, Files: map[string][]Diagnostic{}}
+}
+
+func (r *Report) summarize() {
+	r.Summary = Summary{Files: len(r.Files)}
+	for _, diagnostics := range r.Files {
+		sort.SliceStable(diagnostics, func(i, j int) bool {
+			if diagnostics[i].Line != diagnostics[j].Line {
+				return diagnostics[i].Line < diagnostics[j].Line
+			}
+			return diagnostics[i].Column < diagnostics[j].Column
+		})
+		for _, d := range diagnostics {
+			if d.Severity == "error" {
+				r.Summary.Errors++
+			} else {
+				r.Summary.Warnings++
+			}
+			if d.Fixable {
+				r.Summary.Fixable++
+			}
+		}
+	}
+}
+
+// hashTargets hashes the regular files among the targets, to tell which ones a fix changed.
+// Directory targets are walked for files with the linter's extensions.
+func hashTargets(dir string, plan []planStep) map[string][32]byte {
+	sums := map[string][32]byte{}
+	hash := func(path string) {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return
+		}
+		rel = "./" + filepath.ToSlash(rel)
+		if _, done := sums[rel]; done {
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err == nil {
+			sums[rel] = [32]byte(h.Sum(nil))
+		}
+	}
+	for _, step := range plan {
+		for file := range step.files {
+			hash(filepath.Join(dir, file))
+		}
+		for _, target := range step.targets {
+			path := filepath.Join(dir, strings.TrimSuffix(target, "/..."))
+			// Package targets of single files only change the requested files
+			if step.files != nil && step.linter.packages {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if !info.IsDir() {
+				hash(path)
+				continue
+			}
+			filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				if d.IsDir() && p != path && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules" || d.Name() == "vendor") {
+					return filepath.SkipDir
+				}
+				if !d.IsDir() && slices.Contains(step.linter.extensions, strings.ToLower(filepath.Ext(p))) {
+					hash(p)
+				}
+				return nil
+			})
+		}
+	}
+	return sums
+}
+
+// relativeFile reports a tool's path relative to dir in the same ./path form as the targets
+func relativeFile(dir, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	path = filepath.ToSlash(filepath.Clean(path))
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "../") {
+		return path
+	}
+	return "./" + path
+}
+
+// gofmtErrorPattern matches syntax errors such as "./a.go:3:1: expected declaration"
+var gofmtErrorPattern = regexp.MustCompile(`^(.+?):(\d+):(\d+): (.+)$`)
+
+// parseGofmt reads the files gofmt -l lists as unformatted and the syntax errors it reports on stderr
+func parseGofmt(result *exec.RunResult, dir string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	for _, line := range strings.Split(result.Stdout, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			diagnostics = append(diagnostics, Diagnostic{
+				File:     relativeFile(dir, line),
+				Severity: "warning",
+				Rule:     "format",
+				Message:  "file is not gofmt-formatted",
+				Fixable:  true,
+			})
+		}
+	}
+	scanner := bufio.NewScanner(strings.NewReader(result.Stderr))
+	for scanner.Scan() {
+		m := gofmtErrorPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     relativeFile(dir, m[1]),
+			Line:     atoi(m[2]),
+			Column:   atoi(m[3]),
+			Severity: "error",
+			Rule:     "syntax",
+			Message:  m[4],
+		})
+	}
+	if len(diagnostics) == 0 && result.ExitCode != 0 {
+		return nil, fmt.Errorf("gofmt failed: %s", strings.TrimSpace(result.Stderr))
+	}
+	return diagnostics, nil
+}
+
+// parseGolangciLint reads the JSON report of golangci-lint
+func parseGolangciLint(result *exec.RunResult, dir string) ([]Diagnostic, error) {
+	var report struct {
+		Issues []struct {
+			FromLinter  string          `json:"FromLinter"`
+			Text        string          `json:"Text"`
+			Severity    string          `json:"Severity"`
+			Replacement json.RawMessage `json:"Replacement"`
+			Pos         struct {
+				Filename string `json:"Filename"`
+				Line     int    `json:"Line"`
+				Column   int    `json:"Column"`
+			} `json:"Pos"`
+		} `json:"Issues"`
+	}
+	if err := decodeReport(result, "golangci-lint", &report); err != nil {
+		return nil, err
+	}
+	diagnostics := make([]Diagnostic, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		severity := strings.ToLower(issue.Severity)
+		if severity != "error" {
+			severity = "warning"
+		}
+		// Typecheck issues mean the code does not compile
+		if issue.FromLinter == "typecheck" {
+			severity = "error"
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     relativeFile(dir, issue.Pos.Filename),
+			Line:     issue.Pos.Line,
+			Column:   issue.Pos.Column,
+			Severity: severity,
+			Rule:     issue.FromLinter,
+			Message:  issue.Text,
+			Fixable:  len(issue.Replacement) > 0 && string(issue.Replacement) != "null",
+		})
+	}
+	return diagnostics, nil
+}
+
+// parseESLint reads the JSON formatter output of eslint
+func parseESLint(result *exec.RunResult, dir string) ([]Diagnostic, error) {
+	var files []struct {
+		FilePath string `json:"filePath"`
+		Messages []struct {
+			RuleID   string          `json:"ruleId"`
+			Severity int             `json:"severity"`
+			Message  string          `json:"message"`
+			Line     int             `json:"line"`
+			Column   int             `json:"column"`
+			Fix      json.RawMessage `json:"fix"`
+		} `json:"messages"`
+	}
+	if err := decodeReport(result, "eslint", &files); err != nil {
+		return nil, err
+	}
+	var diagnostics []Diagnostic
+	for _, f := range files {
+		for _, m := range f.Messages {
+			severity := "warning"
+			if m.Severity == 2 {
+				severity = "error"
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				File:     relativeFile(dir, f.FilePath),
+				Line:     m.Line,
+				Column:   m.Column,
+				Severity: severity,
+				Rule:     m.RuleID,
+				Message:  m.Message,
+				Fixable:  len(m.Fix) > 0,
+			})
+		}
+	}
+	return diagnostics, nil
+}
+
+// parseRuff reads the JSON output of ruff check
+func parseRuff(result *exec.RunResult, dir string) ([]Diagnostic, error) {
+	var issues []struct {
+		Code     *string         `json:"code"`
+		Message  string          `json:"message"`
+		Filename string          `json:"filename"`
+		Fix      json.RawMessage `json:"fix"`
+		Location struct {
+			Row    int `json:"row"`
+			Column int `json:"column"`
+		} `json:"location"`
+	}
+	if err := decodeReport(result, "ruff", &issues); err != nil {
+		return nil, err
+	}
+	diagnostics := make([]Diagnostic, 0, len(issues))
+	for _, issue := range issues {
+		// Issues without a code are syntax errors
+		severity, rule := "error", "syntax"
+		if issue.Code != nil {
+			severity, rule = "warning", *issue.Code
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     relativeFile(dir, issue.Filename),
+			Line:     issue.Location.Row,
+			Column:   issue.Location.Column,
+			Severity: severity,
+			Rule:     rule,
+			Message:  issue.Message,
+			Fixable:  len(issue.Fix) > 0 && string(issue.Fix) != "null",
+		})
+	}
+	return diagnostics, nil
+}
+
+// decodeReport unmarshals a JSON report from stdout; a missing report means the tool itself failed
+func decodeReport(result *exec.RunResult, tool string, v interface{}) error {
+	out := strings.TrimSpace(result.Stdout)
+	if out == "" {
+		if result.ExitCode == 0 {
+			return nil
+		}
+		return fmt.Errorf("%s failed (exit code %d): %s", tool, result.ExitCode, lastLines(result.Stderr, 20))
+	}
+	if err := json.Unmarshal([]byte(out), v); err != nil {
+		return fmt.Errorf("failed to parse %s output: %w: %s", tool, err, lastLines(result.Stderr, 20))
+	}
+	return nil
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,204 @@
+package codequality
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// CodeQualityProvider runs formatters and linters and reports per-file diagnostics
+type CodeQualityProvider struct {
+	*provider.BaseProvider
+	client *CodeQualityClient
+}
+
+// NewCodeQualityProvider creates a new code quality provider with config
+func NewCodeQualityProvider(cfg *config.CodeQualityConfig) *CodeQualityProvider {
+	p := &CodeQualityProvider{
+		BaseProvider: provider.NewBaseProvider("code_quality"),
+		client:       NewCodeQualityClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Code quality linters not configured", p.client.InitError())
+		if err := p.client.InitError(); err != nil {
+			log.Printf("⚠ Code quality provider not available: %v", err)
+		}
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Code quality provider initialized successfully (%s)", strings.Join(p.linterNames(), ", "))
+
+	return p
+}
+
+// Test tests the code quality configuration (for ProviderClient interface compatibility)
+func (p *CodeQualityProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("code quality provider not available")
+	}
+	return nil
+}
+
+// AddTools adds code quality tools to the MCP server (for ProviderClient interface compatibility)
+func (p *CodeQualityProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the code quality provider
+func (p *CodeQualityProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds code quality tools to the MCP server
+func (p *CodeQualityProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Code quality provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createCheckTool(),
+		p.createFixTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered code quality tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All code quality tools registered successfully")
+}
+
+func (p *CodeQualityProvider) linterNames() []string {
+	var names []string
+	for name := range p.client.Linters() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// codeArgs are the arguments shared by code_check and code_fix
+type codeArgs struct {
+	Files   []string `json:"files,omitempty"`
+	Dir     string   `json:"dir,omitempty"`
+	Linters []string `json:"linters,omitempty"`
+}
+
+// codeSchema is the input schema shared by code_check and code_fix
+const codeSchema = `{
+	"type": "object",
+	"properties": {
+		"files": {
+			"type": "array",
+			"items": {"type": "string"},
+			"description": "Files or directories relative to dir; each file goes to the linters for its language, directories to all selected linters. Defaults to the whole directory"
+		},
+		"dir": {
+			"type": "string",
+			"description": "Project directory, absolute or relative to a code quality directory; defaults to the first one"
+		},
+		"linters": {
+			"type": "array",
+			"items": {"type": "string"},
+			"description": "Linters to run (default: all available)"
+		}
+	}
+}`
+
+// createCheckTool creates the lint/format check tool
+func (p *CodeQualityProvider) createCheckTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "code_check",
+		Description: fmt.Sprintf("Run formatters and linters (available: %s) on files or directories and return their findings as per-file diagnostics with line, column, severity, rule and whether it is auto-fixable", strings.Join(p.linterNames(), ", ")),
+		InputSchema: json.RawMessage(codeSchema),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		request, err := p.parseArgs(ctx, req)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		report, err := p.client.Check(ctx, request)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(report), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createFixTool creates the auto-fix tool (gated by code_quality.allow_fix)
+func (p *CodeQualityProvider) createFixTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "code_fix",
+		Description: "Apply formatter and linter auto-fixes (gofmt -w, golangci-lint --fix, eslint --fix, ruff check --fix) in place, then report the files that changed and the diagnostics left. Disabled unless code_quality.allow_fix is enabled.",
+		InputSchema: json.RawMessage(codeSchema),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		request, err := p.parseArgs(ctx, req)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		report, err := p.client.Fix(ctx, request)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(report), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *CodeQualityProvider) parseArgs(ctx context.Context, req *mcp.CallToolRequest) (Request, error) {
+	var args codeArgs
+	if len(req.Params.Arguments) > 0 {
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return Request{}, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	request := Request{Dir: args.Dir, Files: args.Files, Linters: args.Linters}
+	if authResult, ok := auth.GetAuthResult(ctx); ok && authResult != nil {
+		request.User = authResult.Username
+	}
+	return request, nil
+}
+
+func (p *CodeQualityProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Code Quality Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *CodeQualityProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that CodeQualityProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*CodeQualityProvider)(nil)
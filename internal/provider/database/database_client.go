@@ -1,26 +1,36 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
 	"strings"
 	"sync"
-
-	_ "github.com/go-sql-driver/mysql"
+	"time"
 
 	"dev-mcp/internal/config"
 	"dev-mcp/internal/logging"
 )
 
+// defaultQueryTimeout bounds queries when no timeout is configured
+const defaultQueryTimeout = 30 * time.Second
+
 // DatabaseClient provides secure database operations
 type DatabaseClient struct {
 	db         *sql.DB
 	config     *config.DatabaseConfig
+	dialect    Dialect
 	logger     *logging.Logger
 	unsafeMode bool
 	allowedOps []string
 	blockedOps []string
+	cache      *QueryCache // Read-only query results; nil when disabled
+	replicas   []*replica  // Read replicas taking read-only queries round-robin
+	queryLog   *QueryLog
+	masking    *MaskingPolicy
+	replicaMu  sync.Mutex
+	nextIndex  int
 	mu         sync.RWMutex
 }
 
@@ -37,11 +47,12 @@ func NewDatabaseClient(cfg *config.DatabaseConfig) (*DatabaseClient, error) {
 		return nil, fmt.Errorf("database configuration is incomplete")
 	}
 
-	// For now, we'll assume MySQL since that's what the config supports
-	connStr := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
-		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+	dialect, err := NewDialect(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
 
-	db, err := sql.Open("mysql", connStr)
+	db, err := sql.Open(dialect.DriverName(), dialect.DSN(cfg))
 	if err != nil {
 		logger.Error("failed to open database connection", logging.String("error", err.Error()))
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
@@ -61,41 +72,73 @@ func NewDatabaseClient(cfg *config.DatabaseConfig) (*DatabaseClient, error) {
 	client := &DatabaseClient{
 		db:         db,
 		config:     cfg,
+		dialect:    dialect,
 		logger:     logger,
 		unsafeMode: false,
-		allowedOps: []string{"SELECT", "SHOW", "DESCRIBE", "EXPLAIN"},
-		blockedOps: []string{"INSERT", "UPDATE", "DELETE", "DROP", "TRUNCATE", "ALTER", "CREATE"},
+		allowedOps: dialect.AllowedOperations(),
+		blockedOps: dialect.BlockedOperations(),
+		cache:      NewQueryCache(cfg.Cache),
+		queryLog:   NewQueryLog(cfg.SlowQueries),
+		masking:    NewMaskingPolicy(cfg.Masking, logger),
 	}
+	client.replicas = openReplicas(cfg, dialect, logger)
 
-	logger.Info("database client initialized successfully")
+	logger.Info("database client initialized successfully", logging.String("driver", dialect.Name()))
 	return client, nil
 }
 
 // Query executes a secure SQL query with validation
-func (c *DatabaseClient) Query(query string) ([]map[string]interface{}, error) {
+func (c *DatabaseClient) Query(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	_, results, err := c.QueryWithColumns(ctx, query)
+	return results, err
+}
+
+// QueryWithColumns executes a secure SQL query and also returns the column names in result order.
+// Args are bound to the query's placeholders (? for MySQL, $1, $2, ... for PostgreSQL) by the driver,
+// so values never become part of the SQL text. The query is cancelled with ctx, and after the configured
+// query timeout when ctx has no deadline of its own.
+func (c *DatabaseClient) QueryWithColumns(ctx context.Context, query string, args ...interface{}) ([]string, []map[string]interface{}, error) {
+	columns, rows, _, err := c.QueryRouted(ctx, query, args...)
+	return columns, rows, err
+}
+
+// QueryRouted runs a query like QueryWithColumns and also reports where it ran: read-only queries go to the
+// connection's read replicas round-robin when it has any, everything else to the primary.
+func (c *DatabaseClient) QueryRouted(ctx context.Context, query string, args ...interface{}) ([]string, []map[string]interface{}, *Routing, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.db == nil {
-		return nil, fmt.Errorf("database not initialized")
+		return nil, nil, nil, fmt.Errorf("database not initialized")
 	}
 
 	// Validate the query for security
 	if err := c.validateQuery(query); err != nil {
-		return nil, fmt.Errorf("SQL security validation failed: %w", err)
+		return nil, nil, nil, fmt.Errorf("SQL security validation failed: %w", err)
+	}
+	if err := checkPlaceholders(c.dialect, query, len(args)); err != nil {
+		return nil, nil, nil, err
 	}
 
+	return c.routedQuery(ctx, query, args)
+}
+
+// runQuery executes a validated query on the primary or a replica and reads every row
+func (c *DatabaseClient) runQuery(ctx context.Context, db *sql.DB, query string, args []interface{}) ([]string, []map[string]interface{}, error) {
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
 	// Execute the query
-	rows, err := c.db.Query(query)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, nil, queryError(ctx, "failed to execute query", err)
 	}
 	defer rows.Close()
 
 	// Get column information
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get columns: %w", err)
+		return nil, nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
 	// Read all rows
@@ -110,7 +153,7 @@ func (c *DatabaseClient) Query(query string) ([]map[string]interface{}, error) {
 
 		// Scan the row into the value pointers
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return nil, nil, queryError(ctx, "failed to scan row", err)
 		}
 
 		// Create a map for this row
@@ -130,10 +173,113 @@ func (c *DatabaseClient) Query(query string) ([]map[string]interface{}, error) {
 
 	// Check for errors after iteration
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error during row iteration: %w", err)
+		return nil, nil, queryError(ctx, "error during row iteration", err)
 	}
 
-	return results, nil
+	return columns, results, nil
+}
+
+// QueryResult is the outcome of QueryCached and QueryMasked
+type QueryResult struct {
+	Columns  []string
+	Rows     []map[string]interface{}
+	CachedAt time.Time // When the result was cached; zero when the query ran
+	Routing  *Routing
+	Masking  *MaskingReport // Set by QueryMasked
+}
+
+// QueryMasked runs a query like QueryCached and applies the connection's masking policy to the result.
+// Queries naming blocked columns are rejected before they run.
+func (c *DatabaseClient) QueryMasked(ctx context.Context, query string, noCache bool, args ...interface{}) (*QueryResult, error) {
+	if err := c.masking.Check(query, c.mysqlSyntax()); err != nil {
+		return nil, fmt.Errorf("masking policy: %w", err)
+	}
+	result, err := c.QueryCached(ctx, query, noCache, args...)
+	if err != nil {
+		return nil, err
+	}
+	columns, rows, report, err := c.masking.Apply(query, c.mysqlSyntax(), result.Columns, result.Rows)
+	if err != nil {
+		return nil, fmt.Errorf("masking policy: %w", err)
+	}
+	result.Columns, result.Rows, result.Masking = columns, rows, report
+	return result, nil
+}
+
+// QueryCached runs a query like QueryRouted, serving read-only queries from the connection's query cache
+// unless noCache is set. Any other statement that succeeds clears the cache, since it may have changed the
+// cached data.
+func (c *DatabaseClient) QueryCached(ctx context.Context, query string, noCache bool, args ...interface{}) (*QueryResult, error) {
+	cacheable := c.cache != nil && isReadOnlyQuery(query, c.mysqlSyntax())
+	if cacheable && !noCache {
+		if columns, rows, cachedAt, ok := c.cache.Get(query, args); ok {
+			return &QueryResult{
+				Columns:  columns,
+				Rows:     rows,
+				CachedAt: cachedAt,
+				Routing:  &Routing{Target: TargetCache, Reason: "served from the query cache", Replicas: len(c.replicas), HealthyReplicas: c.healthyReplicas()},
+			}, nil
+		}
+	}
+
+	columns, rows, routing, err := c.QueryRouted(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case cacheable:
+		c.cache.Put(query, args, columns, rows)
+	case c.cache != nil:
+		c.cache.Clear()
+	}
+	return &QueryResult{Columns: columns, Rows: rows, Routing: routing}, nil
+}
+
+// Masking returns the masking policy applied to the connection's query results
+func (c *DatabaseClient) Masking() *MaskingPolicy {
+	return c.masking
+}
+
+// mysqlSyntax reports whether queries use MySQL quoting (backslash escapes, backquoted identifiers)
+func (c *DatabaseClient) mysqlSyntax() bool {
+	return c.dialect == nil || c.dialect.Name() == "mysql"
+}
+
+// QueryLog returns the metrics and slow query log of the connection
+func (c *DatabaseClient) QueryLog() *QueryLog {
+	return c.queryLog
+}
+
+// Cache returns the connection's query cache, or nil when it is disabled
+func (c *DatabaseClient) Cache() *QueryCache {
+	return c.cache
+}
+
+// QueryTimeout returns how long a query may run when its context has no deadline
+func (c *DatabaseClient) QueryTimeout() time.Duration {
+	if c.config != nil && c.config.QueryTimeoutSeconds > 0 {
+		return time.Duration(c.config.QueryTimeoutSeconds) * time.Second
+	}
+	return defaultQueryTimeout
+}
+
+// withQueryTimeout bounds ctx by the query timeout unless it already has a deadline
+func (c *DatabaseClient) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.QueryTimeout())
+}
+
+// queryError explains a failure caused by the query's context running out
+func queryError(ctx context.Context, action string, err error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("%s: query timed out: %w", action, err)
+	case context.Canceled:
+		return fmt.Errorf("%s: query cancelled: %w", action, err)
+	}
+	return fmt.Errorf("%s: %w", action, err)
 }
 
 // validateQuery performs security validation on SQL queries
@@ -163,6 +309,10 @@ func (c *DatabaseClient) validateQuery(query string) error {
 
 	// First, check for dangerous patterns before allowing any operations
 	if c.hasDangerousPatterns(query) {
+		// Values inlined as literals are checked like SQL; bound params are not part of the query text
+		if _, literals := sqlCode(query, c.dialect == nil || c.dialect.Name() == "mysql"); literals > 0 {
+			return fmt.Errorf("query contains potentially dangerous patterns; pass values as params bound to placeholders instead of inlining them as literals")
+		}
 		return fmt.Errorf("query contains potentially dangerous patterns")
 	}
 
@@ -253,6 +403,10 @@ func (c *DatabaseClient) hasDangerousPatterns(query string) bool {
 		"EXECUTE.*SP_",
 	}
 
+	if c.dialect != nil {
+		dangerousPatterns = append(dangerousPatterns, c.dialect.DangerousPatterns()...)
+	}
+
 	for _, pattern := range dangerousPatterns {
 		re := regexp.MustCompile(pattern)
 		if re.MatchString(query) {
@@ -263,6 +417,11 @@ func (c *DatabaseClient) hasDangerousPatterns(query string) bool {
 	return false
 }
 
+// Dialect returns the SQL dialect of the connected database
+func (c *DatabaseClient) Dialect() Dialect {
+	return c.dialect
+}
+
 // EnableUnsafeMode enables unsafe mode (allows all operations)
 func (c *DatabaseClient) EnableUnsafeMode() {
 	c.mu.Lock()
@@ -329,6 +488,9 @@ func (c *DatabaseClient) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	for _, r := range c.replicas {
+		r.db.Close()
+	}
 	if c.db != nil {
 		if err := c.db.Close(); err != nil {
 			c.logger.Error("failed to close database connection", logging.String("error", err.Error()))
@@ -348,6 +510,8 @@ func (c *DatabaseClient) HealthCheck() error {
 	if err := c.db.Ping(); err != nil {
 		return fmt.Errorf("database health check failed: %w", err)
 	}
+	// Replicas only leave or rejoin the rotation; the primary alone decides health
+	c.checkReplicas()
 
 	c.logger.Debug("database health check passed")
 	return nil
@@ -356,4 +520,4 @@ func (c *DatabaseClient) HealthCheck() error {
 // ValidateQueryForTest validates a query for testing purposes (exported for test access)
 func (c *DatabaseClient) ValidateQueryForTest(query string) error {
 	return c.validateQuery(query)
-}
\ No newline at end of file
+}
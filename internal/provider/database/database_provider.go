@@ -6,40 +6,59 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"dev-mcp/entity"
+	"dev-mcp/internal/auth"
 	"dev-mcp/internal/config"
 	"dev-mcp/internal/provider"
 )
 
+// maxQueryTimeoutSeconds caps the per-call timeout of database_query
+const maxQueryTimeoutSeconds = 600
+
 // DatabaseProvider provides database query functionality
 type DatabaseProvider struct {
 	*provider.BaseProvider
-	client *DatabaseClient
+	registry *Registry
+	results  *ResultCache
 }
 
-// NewDatabaseProvider creates a new Database provider with config
-func NewDatabaseProvider(cfg *config.DatabaseConfig) *DatabaseProvider {
+// NewDatabaseProvider creates a new Database provider from the primary database config
+// and any additional named connections
+func NewDatabaseProvider(cfg *config.DatabaseConfig, connections []config.DatabaseConfig) *DatabaseProvider {
 	p := &DatabaseProvider{
 		BaseProvider: provider.NewBaseProvider("database"),
+		results:      NewResultCache(),
 	}
 
-	// Try to create database client
-	client, err := NewDatabaseClient(cfg)
-	if err != nil {
+	// Connect every configured database
+	p.registry = NewRegistry(cfg, connections)
+	if p.registry.Len() == 0 {
+		err := fmt.Errorf("no database connection could be established")
 		log.Printf("⚠ Database client initialization failed: %v", err)
 		p.SetStatus(false, "Database client initialization failed", err)
 		return p
 	}
 
-	p.client = client
 	p.SetAvailable(true)
-	log.Printf("✓ Database provider initialized successfully")
+	log.Printf("✓ Database provider initialized successfully (connections: %s)", strings.Join(p.registry.Names(), ", "))
 	return p
 }
 
+// Registry returns the database connection registry
+func (p *DatabaseProvider) Registry() *Registry {
+	return p.registry
+}
+
+// ClearResultCache drops cached query results of a connection (all connections when empty); later
+// pages of those results must be fetched by re-running the query
+func (p *DatabaseProvider) ClearResultCache(connection string) int {
+	return p.results.Clear(connection)
+}
+
 // Test tests the database configuration and connection (for ProviderClient interface compatibility)
 func (p *DatabaseProvider) Test(config interface{}) error {
 	// Since client is already initialized in constructor, just check availability
@@ -58,14 +77,41 @@ func (p *DatabaseProvider) AddTools(server *mcp.Server, config interface{}) erro
 	toolDef2 := p.createDatabaseSecurityTool()
 	server.AddTool(toolDef2.Tool, toolDef2.Handler)
 
+	toolDef3 := p.createDatabaseConnectionsTool()
+	server.AddTool(toolDef3.Tool, toolDef3.Handler)
+
+	toolDef4 := p.createDatabaseQueryNextPageTool()
+	server.AddTool(toolDef4.Tool, toolDef4.Handler)
+
+	toolDef5 := p.createListTablesTool()
+	server.AddTool(toolDef5.Tool, toolDef5.Handler)
+
+	toolDef6 := p.createDescribeTableTool()
+	server.AddTool(toolDef6.Tool, toolDef6.Handler)
+
+	toolDef7 := p.createListIndexesTool()
+	server.AddTool(toolDef7.Tool, toolDef7.Handler)
+
+	toolDef8 := p.createDatabaseCacheTool()
+	server.AddTool(toolDef8.Tool, toolDef8.Handler)
+
+	toolDef9 := p.createSlowQueriesTool()
+	server.AddTool(toolDef9.Tool, toolDef9.Handler)
+
+	toolDef10 := p.createMaskingPolicyTool()
+	server.AddTool(toolDef10.Tool, toolDef10.Handler)
+
+	toolDef11 := p.createDatabaseExecuteTool()
+	server.AddTool(toolDef11.Tool, toolDef11.Handler)
+
 	log.Printf("✓ Database tools added to server successfully")
 	return nil
 }
 
 // Close closes the Database provider
 func (p *DatabaseProvider) Close() error {
-	if p.client != nil {
-		return p.client.Close()
+	if p.registry != nil {
+		return p.registry.Close()
 	}
 	return nil
 }
@@ -76,13 +122,20 @@ func (p *DatabaseProvider) HealthCheck() error {
 		return fmt.Errorf("database provider not available")
 	}
 
-	if p.client == nil {
+	if p.registry == nil {
 		return fmt.Errorf("database client not initialized")
 	}
 
-	if err := p.client.HealthCheck(); err != nil {
-		p.SetStatus(false, "Database health check failed", err)
-		return err
+	for _, name := range p.registry.Names() {
+		client, err := p.registry.Get(name)
+		if err != nil {
+			return err
+		}
+		if err := client.HealthCheck(); err != nil {
+			err = fmt.Errorf("connection %s: %w", name, err)
+			p.SetStatus(false, "Database health check failed", err)
+			return err
+		}
 	}
 
 	p.SetStatus(true, "Database healthy", nil)
@@ -93,36 +146,120 @@ func (p *DatabaseProvider) HealthCheck() error {
 func (p *DatabaseProvider) createDatabaseQueryTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
 		Name:        "database_query",
-		Description: "Execute secure database queries and manage database operations. Only read-only operations are allowed by default (SELECT, SHOW, DESCRIBE, EXPLAIN). Write operations are blocked for security unless unsafe mode is enabled.",
+		Description: "Execute secure database queries and manage database operations. Only read-only operations are allowed by default (SELECT, SHOW, DESCRIBE, EXPLAIN). Write operations are blocked for security unless unsafe mode is enabled. Pass values as params bound to placeholders (? for MySQL, $1, $2, ... for PostgreSQL) rather than inlining them in the SQL. Results are paginated: use next_cursor with database_query_next_page to read further rows.",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
 				"query": {
 					"type": "string",
 					"description": "SQL query to execute (read-only operations only by default)"
+				},
+				"params": {
+					"type": "array",
+					"description": "Values bound to the query's placeholders in order (strings, numbers, booleans or null); the count must match the placeholders",
+					"items": {}
+				},
+				"connection": {
+					"type": "string",
+					"description": "Name of the configured database connection to query (defaults to the primary database)"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Rows per page (max 500)",
+					"default": 50
+				},
+				"offset": {
+					"type": "integer",
+					"description": "Row offset; a non-zero offset reuses the cached result of the same query when available",
+					"default": 0
+				},
+				"cursor": {
+					"type": "string",
+					"description": "Cursor from a previous page (next_cursor); when set, query and offset are ignored"
+				},
+				"timeout_seconds": {
+					"type": "integer",
+					"description": "Cancel the query after this many seconds instead of the connection's query timeout (max 600)"
+				},
+				"format": {
+					"type": "string",
+					"enum": ["json", "csv", "markdown"],
+					"description": "Output format: json (rows as objects, columns in SELECT order), csv (header row, NULL as an empty field) or markdown (a table, NULL shown as NULL)",
+					"default": "json"
+				},
+				"max_cell_chars": {
+					"type": "integer",
+					"description": "Cut string values longer than this many characters, for wide text columns (0 keeps them whole)",
+					"default": 0
+				},
+				"no_cache": {
+					"type": "boolean",
+					"description": "Run the query even if a recent result is in the query cache (read-only queries are cached per connection)",
+					"default": false
 				}
-			},
-			"required": ["query"]
+			}
 		}`),
 	}
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract query from request
 		var args struct {
-			Query string `json:"query"`
+			Query      string            `json:"query"`
+			Params     []json.RawMessage `json:"params,omitempty"`
+			Connection string            `json:"connection,omitempty"`
+			Limit      int               `json:"limit,omitempty"`
+			Offset     int               `json:"offset,omitempty"`
+			Cursor     string            `json:"cursor,omitempty"`
+			Timeout    int               `json:"timeout_seconds,omitempty"`
+			Format     string            `json:"format,omitempty"`
+			MaxCell    int               `json:"max_cell_chars,omitempty"`
+			NoCache    bool              `json:"no_cache,omitempty"`
 		}
 
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
 		}
+		format, err := validFormat(args.Format)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		if args.Cursor != "" {
+			return p.nextPage(ctx, args.Cursor, args.Limit, format, args.MaxCell), nil
+		}
 
 		if args.Query == "" {
 			return p.createErrorResult(fmt.Errorf("query parameter is required")), nil
 		}
+		params, err := BindParams(args.Params)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		connection := p.connectionName(args.Connection)
+		if args.Offset > 0 {
+			if cached, ok := p.results.Get(resultOwner(ctx), connection, args.Query, params); ok {
+				return p.formatPage(cached.Page(args.Offset, args.Limit), format, args.MaxCell), nil
+			}
+		}
+
+		client, err := p.registry.Get(args.Connection)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		// The query is cancelled with the request; a per-call timeout replaces the connection's
+		if args.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(min(args.Timeout, maxQueryTimeoutSeconds))*time.Second)
+			defer cancel()
+		}
 
 		// Execute the query
-		log.Printf("Executing database query: %s", args.Query)
-		results, err := p.client.Query(args.Query)
+		log.Printf("Executing database query on %s: %s (%d params)", connection, args.Query, len(params))
+		started := time.Now()
+		queryResult, err := client.QueryMasked(ctx, args.Query, args.NoCache, params...)
+		p.recordQuery(ctx, client, connection, args.Query, len(params), queryResult, err, time.Since(started))
 		if err != nil {
 			log.Printf("Query execution failed: %v", err)
 
@@ -131,10 +268,10 @@ func (p *DatabaseProvider) createDatabaseQueryTool() entity.ToolDefinition {
 				return &mcp.CallToolResult{
 					Content: []mcp.Content{
 						&mcp.TextContent{
-							Text: fmt.Sprintf("🚫 SQL Security Error: %s\n\n🔒 Security Policy:\n• Allowed operations: %s\n• Blocked operations: %s\n\n💡 Only read-only operations are permitted for security reasons.\nUse SELECT, SHOW, DESCRIBE, or EXPLAIN statements only.",
+							Text: fmt.Sprintf("🚫 SQL Security Error: %s\n\n🔒 Security Policy:\n• Allowed operations: %s\n• Blocked operations: %s\n\n💡 Only read-only operations are permitted for security reasons.\nUse SELECT, SHOW, DESCRIBE, or EXPLAIN statements only, and pass values as params instead of inlining them.",
 								err.Error(),
-								strings.Join(p.client.GetAllowedOperations(), ", "),
-								strings.Join(p.client.GetBlockedOperations(), ", ")),
+								strings.Join(client.GetAllowedOperations(), ", "),
+								strings.Join(client.GetBlockedOperations(), ", ")),
 						},
 					},
 					IsError: true,
@@ -151,49 +288,311 @@ func (p *DatabaseProvider) createDatabaseQueryTool() entity.ToolDefinition {
 			}, nil
 		}
 
-		// Format results
-		resultText := fmt.Sprintf("✅ Query executed successfully\n\nRows returned: %d\n\n", len(results))
+		// Cache the full result and return the requested page
+		cached := p.results.Put(resultOwner(ctx), connection, args.Query, params, queryResult.Columns, queryResult.Rows)
+		result := p.formatPage(cached.Page(args.Offset, args.Limit), format, args.MaxCell)
+		if result.IsError {
+			return result, nil
+		}
+		if !queryResult.CachedAt.IsZero() {
+			result.Content = append(result.Content, &mcp.TextContent{
+				Text: fmt.Sprintf("Served from the query cache (cached %s ago); pass no_cache to re-run the query", time.Since(queryResult.CachedAt).Round(time.Second)),
+			})
+		}
+		// Where the query ran and what the masking policy changed go in the result metadata
+		if queryResult.Routing != nil && queryResult.Routing.Replicas > 0 {
+			result.Meta = mcp.Meta{RoutingMetaKey: queryResult.Routing}
+		}
+		if queryResult.Masking != nil && queryResult.Masking.Changed() {
+			if result.Meta == nil {
+				result.Meta = mcp.Meta{}
+			}
+			result.Meta[MaskingMetaKey] = queryResult.Masking
+			result.Content = append(result.Content, &mcp.TextContent{Text: maskingSummary(queryResult.Masking)})
+		}
+		return result, nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
 
-		if len(results) == 0 {
-			resultText += "No data returned."
-		} else {
-			// Show column headers
-			if len(results) > 0 {
-				var columns []string
-				for col := range results[0] {
-					columns = append(columns, col)
+// createDatabaseExecuteTool creates the tool running write statements in a transaction
+func (p *DatabaseProvider) createDatabaseExecuteTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_execute",
+		Description: "Run INSERT, UPDATE, DELETE, REPLACE or MERGE statements in one transaction (requires unsafe mode). By default this is a dry run: the statements run, their affected row counts are reported and the transaction is rolled back. Pass commit: true to keep the writes. A failing statement rolls back the whole transaction. Pass values as params bound to placeholders (? for MySQL, $1, $2, ... for PostgreSQL).",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"statements": {
+					"type": "array",
+					"description": "Statements to run in order, one SQL statement per entry",
+					"items": {
+						"type": "object",
+						"properties": {
+							"sql": {
+								"type": "string",
+								"description": "INSERT, UPDATE, DELETE, REPLACE or MERGE statement"
+							},
+							"params": {
+								"type": "array",
+								"description": "Values bound to the statement's placeholders in order",
+								"items": {}
+							}
+						},
+						"required": ["sql"]
+					}
+				},
+				"connection": {
+					"type": "string",
+					"description": "Name of the configured database connection (defaults to the primary database)"
+				},
+				"dry_run": {
+					"type": "boolean",
+					"description": "Run the statements, report the affected rows and roll back (the default unless commit is set)",
+					"default": true
+				},
+				"commit": {
+					"type": "boolean",
+					"description": "Commit the transaction; cannot be combined with dry_run: true",
+					"default": false
+				},
+				"timeout_seconds": {
+					"type": "integer",
+					"description": "Roll back the transaction after this many seconds instead of the connection's query timeout (max 600)"
 				}
-				resultText += fmt.Sprintf("Columns: %v\n\n", columns)
-			}
+			},
+			"required": ["statements"]
+		}`),
+	}
 
-			// Show first 5 rows
-			limit := len(results)
-			if limit > 5 {
-				limit = 5
-			}
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Statements []struct {
+				SQL    string            `json:"sql"`
+				Params []json.RawMessage `json:"params,omitempty"`
+			} `json:"statements"`
+			Connection string `json:"connection,omitempty"`
+			DryRun     *bool  `json:"dry_run,omitempty"`
+			Commit     bool   `json:"commit,omitempty"`
+			Timeout    int    `json:"timeout_seconds,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if len(args.Statements) == 0 {
+			return p.createErrorResult(fmt.Errorf("statements parameter is required")), nil
+		}
+		if args.Commit && args.DryRun != nil && *args.DryRun {
+			return p.createErrorResult(fmt.Errorf("commit and dry_run cannot both be set")), nil
+		}
 
-			resultText += "Sample data:\n"
-			for i := 0; i < limit; i++ {
-				resultText += fmt.Sprintf("Row %d: %v\n", i+1, results[i])
+		statements := make([]ExecStatement, 0, len(args.Statements))
+		for i, statement := range args.Statements {
+			params, err := BindParams(statement.Params)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("statement %d: %w", i+1, err)), nil
 			}
+			statements = append(statements, ExecStatement{SQL: statement.SQL, Args: params})
+		}
+
+		client, err := p.registry.Get(args.Connection)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		if args.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(min(args.Timeout, maxQueryTimeoutSeconds))*time.Second)
+			defer cancel()
+		}
 
-			if len(results) > 5 {
-				resultText += fmt.Sprintf("... and %d more rows\n", len(results)-5)
+		connection := p.connectionName(args.Connection)
+		log.Printf("Executing %d statements on %s (commit: %t)", len(statements), connection, args.Commit)
+		result, err := client.Execute(ctx, statements, args.Commit)
+		if err != nil {
+			if result == nil {
+				return p.createErrorResult(err), nil
 			}
+			errResult := p.formatJSONResult(result)
+			errResult.IsError = true
+			return errResult, nil
+		}
+		if result.Committed {
+			// Pages of earlier results no longer match the data
+			p.results.Clear(connection)
 		}
+		return p.formatJSONResult(result), nil
+	}
 
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: resultText,
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// resultOwner names the caller a cached result belongs to: the user and whom they act for. Callers
+// without credentials (stdio, authentication disabled) share one name.
+func resultOwner(ctx context.Context) string {
+	var owner string
+	if authResult, ok := auth.GetAuthResult(ctx); ok && authResult != nil {
+		owner = authResult.UserID
+	}
+	if onBehalfOf, ok := auth.OnBehalfOf(ctx); ok {
+		owner += "\x00" + onBehalfOf
+	}
+	return owner
+}
+
+// recordQuery adds a database_query call to the connection's metrics, logging it when it was slow
+func (p *DatabaseProvider) recordQuery(ctx context.Context, client *DatabaseClient, connection, query string, params int, result *QueryResult, err error, duration time.Duration) {
+	record := QueryRecord{
+		Connection: connection,
+		Query:      query,
+		Params:     params,
+		At:         time.Now(),
+	}
+	if authResult, ok := auth.GetAuthResult(ctx); ok && authResult != nil {
+		record.Caller = authResult.Username
+	}
+	record.OnBehalfOf, _ = auth.OnBehalfOf(ctx)
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Rows = len(result.Rows)
+		if result.Routing != nil {
+			record.Target = result.Routing.Target
+		}
+	}
+
+	if client.QueryLog().Record(record, duration) {
+		log.Printf("⚠ Slow query on %s (%s, %d rows, caller %q): %s", connection, duration.Round(time.Millisecond), record.Rows, record.Caller, query)
+	}
+}
+
+// createDatabaseQueryNextPageTool creates the tool that pages through a cached query result
+func (p *DatabaseProvider) createDatabaseQueryNextPageTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_query_next_page",
+		Description: "Fetch the next page of a database_query result using its next_cursor. Results are cached server-side for 10 minutes; the query is not re-executed.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"cursor": {
+					"type": "string",
+					"description": "next_cursor returned by database_query or a previous page"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Rows per page (max 500)",
+					"default": 50
+				},
+				"format": {
+					"type": "string",
+					"enum": ["json", "csv", "markdown"],
+					"description": "Output format: json (rows as objects, columns in SELECT order), csv (header row, NULL as an empty field) or markdown (a table, NULL shown as NULL)",
+					"default": "json"
 				},
+				"max_cell_chars": {
+					"type": "integer",
+					"description": "Cut string values longer than this many characters, for wide text columns (0 keeps them whole)",
+					"default": 0
+				}
 			},
-		}, nil
+			"required": ["cursor"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Cursor  string `json:"cursor"`
+			Limit   int    `json:"limit,omitempty"`
+			Format  string `json:"format,omitempty"`
+			MaxCell int    `json:"max_cell_chars,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Cursor == "" {
+			return p.createErrorResult(fmt.Errorf("cursor parameter is required")), nil
+		}
+
+		format, err := validFormat(args.Format)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.nextPage(ctx, args.Cursor, args.Limit, format, args.MaxCell), nil
 	}
 
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
 
+// ToolHints names the connections the database tools can target, with their drivers
+func (p *DatabaseProvider) ToolHints(ctx context.Context) map[string]string {
+	var connections []string
+	for _, name := range p.registry.Names() {
+		client, err := p.registry.Get(name)
+		if err != nil {
+			continue
+		}
+		description := fmt.Sprintf("%s (%s", name, client.Dialect().Name())
+		if name == p.registry.Default() {
+			description += ", default"
+		}
+		connections = append(connections, description+")")
+	}
+	hint := provider.HintList("Connections", connections, 10)
+	return map[string]string{
+		"database_query":          hint,
+		"database_list_tables":    hint,
+		"database_describe_table": hint,
+		"database_list_indexes":   hint,
+	}
+}
+
+// nextPage serves a page of a cached result from a cursor
+func (p *DatabaseProvider) nextPage(ctx context.Context, cursor string, limit int, format string, maxCell int) *mcp.CallToolResult {
+	cached, offset, err := p.results.Resolve(resultOwner(ctx), cursor)
+	if err != nil {
+		return p.createErrorResult(err)
+	}
+	return p.formatPage(cached.Page(offset, limit), format, maxCell)
+}
+
+// formatPage renders a page of rows as JSON, CSV or a Markdown table. Text tables are followed by a
+// summary of the rows shown and the cursor for the next page.
+func (p *DatabaseProvider) formatPage(page *Page, format string, maxCell int) *mcp.CallToolResult {
+	page, truncated := truncateCells(page, maxCell)
+
+	var table string
+	switch format {
+	case FormatCSV:
+		text, err := renderCSV(page)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("failed to write CSV: %w", err))
+		}
+		table = text
+	case FormatMarkdown:
+		table = renderMarkdown(page)
+	default:
+		result := p.formatJSONResult(page)
+		if truncated > 0 && !result.IsError {
+			result.Content = append(result.Content, &mcp.TextContent{
+				Text: fmt.Sprintf("%d value(s) cut to %d characters", truncated, maxCell),
+			})
+		}
+		return result
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: table},
+			&mcp.TextContent{Text: pageSummary(page, truncated, maxCell)},
+		},
+	}
+}
+
 // createDatabaseSecurityTool creates the database security management tool
 func (p *DatabaseProvider) createDatabaseSecurityTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
@@ -206,6 +605,10 @@ func (p *DatabaseProvider) createDatabaseSecurityTool() entity.ToolDefinition {
 					"type": "string",
 					"description": "Action to perform: 'status', 'enable_unsafe', 'disable_unsafe', 'allowed_ops', 'blocked_ops'",
 					"enum": ["status", "enable_unsafe", "disable_unsafe", "allowed_ops", "blocked_ops"]
+				},
+				"connection": {
+					"type": "string",
+					"description": "Name of the configured database connection (defaults to the primary database)"
 				}
 			},
 			"required": ["action"]
@@ -215,7 +618,8 @@ func (p *DatabaseProvider) createDatabaseSecurityTool() entity.ToolDefinition {
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract action from request
 		var args struct {
-			Action string `json:"action"`
+			Action     string `json:"action"`
+			Connection string `json:"connection,omitempty"`
 		}
 
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
@@ -226,20 +630,25 @@ func (p *DatabaseProvider) createDatabaseSecurityTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("action parameter is required")), nil
 		}
 
+		client, err := p.registry.Get(args.Connection)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
 		// Execute the requested action
-		log.Printf("Executing database security action: %s", args.Action)
+		log.Printf("Executing database security action on %s: %s", p.connectionName(args.Connection), args.Action)
 
 		switch args.Action {
 		case "status":
-			return p.getSecurityStatus(), nil
+			return p.getSecurityStatus(client), nil
 		case "enable_unsafe":
-			return p.enableUnsafeMode(), nil
+			return p.enableUnsafeMode(client), nil
 		case "disable_unsafe":
-			return p.disableUnsafeMode(), nil
+			return p.disableUnsafeMode(client), nil
 		case "allowed_ops":
-			return p.getAllowedOperations(), nil
+			return p.getAllowedOperations(client), nil
 		case "blocked_ops":
-			return p.getBlockedOperations(), nil
+			return p.getBlockedOperations(client), nil
 		default:
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
@@ -255,10 +664,10 @@ func (p *DatabaseProvider) createDatabaseSecurityTool() entity.ToolDefinition {
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
 
-func (p *DatabaseProvider) getSecurityStatus() *mcp.CallToolResult {
-	unsafeMode := p.client.IsUnsafeModeEnabled()
-	allowedOps := p.client.GetAllowedOperations()
-	blockedOps := p.client.GetBlockedOperations()
+func (p *DatabaseProvider) getSecurityStatus(client *DatabaseClient) *mcp.CallToolResult {
+	unsafeMode := client.IsUnsafeModeEnabled()
+	allowedOps := client.GetAllowedOperations()
+	blockedOps := client.GetBlockedOperations()
 
 	statusIcon := "🔒"
 	statusText := "SECURE"
@@ -300,9 +709,9 @@ Available Actions:
 	}
 }
 
-func (p *DatabaseProvider) enableUnsafeMode() *mcp.CallToolResult {
+func (p *DatabaseProvider) enableUnsafeMode(client *DatabaseClient) *mcp.CallToolResult {
 	log.Printf("Enabling unsafe database mode")
-	p.client.EnableUnsafeMode()
+	client.EnableUnsafeMode()
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -331,12 +740,12 @@ Use 'disable_unsafe' action to return to secure mode.`,
 	}
 }
 
-func (p *DatabaseProvider) disableUnsafeMode() *mcp.CallToolResult {
+func (p *DatabaseProvider) disableUnsafeMode(client *DatabaseClient) *mcp.CallToolResult {
 	log.Printf("Disabling unsafe database mode")
-	p.client.DisableUnsafeMode()
+	client.DisableUnsafeMode()
 
-	allowedOps := p.client.GetAllowedOperations()
-	blockedOps := p.client.GetBlockedOperations()
+	allowedOps := client.GetAllowedOperations()
+	blockedOps := client.GetBlockedOperations()
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -361,8 +770,8 @@ func (p *DatabaseProvider) disableUnsafeMode() *mcp.CallToolResult {
 	}
 }
 
-func (p *DatabaseProvider) getAllowedOperations() *mcp.CallToolResult {
-	allowedOps := p.client.GetAllowedOperations()
+func (p *DatabaseProvider) getAllowedOperations(client *DatabaseClient) *mcp.CallToolResult {
+	allowedOps := client.GetAllowedOperations()
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -379,8 +788,8 @@ These operations are permitted and considered safe for normal use.`,
 	}
 }
 
-func (p *DatabaseProvider) getBlockedOperations() *mcp.CallToolResult {
-	blockedOps := p.client.GetBlockedOperations()
+func (p *DatabaseProvider) getBlockedOperations(client *DatabaseClient) *mcp.CallToolResult {
+	blockedOps := client.GetBlockedOperations()
 
 	if len(blockedOps) == 0 {
 		return &mcp.CallToolResult{
@@ -433,6 +842,431 @@ func (p *DatabaseProvider) getSecurityAdvice(unsafeMode bool) string {
 • Use unsafe mode only for administrative tasks`
 }
 
+// createListTablesTool creates the tool listing tables and views
+func (p *DatabaseProvider) createListTablesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_list_tables",
+		Description: "List the tables and views of a database schema with their estimated row counts",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"connection": {
+					"type": "string",
+					"description": "Name of the configured database connection (defaults to the primary database)"
+				},
+				"schema": {
+					"type": "string",
+					"description": "Schema (PostgreSQL) or database (MySQL) name; defaults to the connection's current schema"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Connection string `json:"connection,omitempty"`
+			Schema     string `json:"schema,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		client, err := p.registry.Get(args.Connection)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		tables, err := client.ListTables(ctx, args.Schema)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"connection": p.connectionName(args.Connection),
+			"tables":     tables,
+			"count":      len(tables),
+		}
+		if args.Schema != "" {
+			result["schema"] = args.Schema
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDescribeTableTool creates the table description tool
+func (p *DatabaseProvider) createDescribeTableTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_describe_table",
+		Description: "Describe a table: columns with types, nullability and defaults, primary key, unique keys, foreign keys and indexes",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"table": {
+					"type": "string",
+					"description": "Table name"
+				},
+				"connection": {
+					"type": "string",
+					"description": "Name of the configured database connection (defaults to the primary database)"
+				},
+				"schema": {
+					"type": "string",
+					"description": "Schema (PostgreSQL) or database (MySQL) name; defaults to the connection's current schema"
+				}
+			},
+			"required": ["table"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Table      string `json:"table"`
+			Connection string `json:"connection,omitempty"`
+			Schema     string `json:"schema,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Table == "" {
+			return p.createErrorResult(fmt.Errorf("table parameter is required")), nil
+		}
+
+		client, err := p.registry.Get(args.Connection)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		schema, err := client.DescribeTable(ctx, args.Schema, args.Table)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(schema), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createListIndexesTool creates the tool listing a table's indexes
+func (p *DatabaseProvider) createListIndexesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_list_indexes",
+		Description: "List the indexes of a table with their columns in index order, uniqueness and index method",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"table": {
+					"type": "string",
+					"description": "Table name"
+				},
+				"connection": {
+					"type": "string",
+					"description": "Name of the configured database connection (defaults to the primary database)"
+				},
+				"schema": {
+					"type": "string",
+					"description": "Schema (PostgreSQL) or database (MySQL) name; defaults to the connection's current schema"
+				}
+			},
+			"required": ["table"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Table      string `json:"table"`
+			Connection string `json:"connection,omitempty"`
+			Schema     string `json:"schema,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Table == "" {
+			return p.createErrorResult(fmt.Errorf("table parameter is required")), nil
+		}
+
+		client, err := p.registry.Get(args.Connection)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		indexes, err := client.ListIndexes(ctx, args.Schema, args.Table)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"table":   args.Table,
+			"indexes": indexes,
+			"count":   len(indexes),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDatabaseConnectionsTool creates the tool listing configured database connections
+func (p *DatabaseProvider) createDatabaseConnectionsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_connections",
+		Description: "List the configured database connections that database_query can target via its connection argument",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {}}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := map[string]interface{}{
+			"default":     p.registry.Default(),
+			"connections": p.registry.Statuses(),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDatabaseCacheTool creates the tool inspecting and clearing the query caches
+func (p *DatabaseProvider) createDatabaseCacheTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_cache",
+		Description: "Show the hit rate and size of the per-connection caches of read-only query results, or clear them so the next queries reach the database",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"action": {
+					"type": "string",
+					"enum": ["stats", "clear"],
+					"description": "stats (default) or clear",
+					"default": "stats"
+				},
+				"connection": {
+					"type": "string",
+					"description": "Connection whose cache to show or clear (defaults to all connections)"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Action     string `json:"action,omitempty"`
+			Connection string `json:"connection,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Action == "" {
+			args.Action = "stats"
+		}
+		if args.Action != "stats" && args.Action != "clear" {
+			return p.createErrorResult(fmt.Errorf("unknown action: %s. Available actions: stats, clear", args.Action)), nil
+		}
+
+		names := p.registry.Names()
+		if args.Connection != "" {
+			names = []string{args.Connection}
+		}
+
+		connections := map[string]interface{}{}
+		cleared := 0
+		for _, name := range names {
+			client, err := p.registry.Get(name)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			cache := client.Cache()
+			if cache == nil {
+				connections[name] = QueryCacheStats{Enabled: false}
+				continue
+			}
+			if args.Action == "clear" {
+				cleared += cache.Clear()
+			}
+			connections[name] = cache.Stats()
+		}
+
+		result := map[string]interface{}{
+			"action":      args.Action,
+			"connections": connections,
+		}
+		if args.Action == "clear" {
+			log.Printf("Cleared %d cached query result(s)", cleared)
+			result["cleared"] = cleared
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createSlowQueriesTool creates the tool listing the slowest database_query calls
+func (p *DatabaseProvider) createSlowQueriesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_slow_queries",
+		Description: "List the slowest database_query calls over each connection's slow threshold, slowest first, with their duration, rows returned, caller and where they ran, plus per-connection query metrics (count, errors, cache hits, average and max duration)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"connection": {
+					"type": "string",
+					"description": "Connection to report on (defaults to all connections)"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of queries to list",
+					"default": 20
+				},
+				"clear": {
+					"type": "boolean",
+					"description": "Forget the slow queries after listing them; the metrics keep counting",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Connection string `json:"connection,omitempty"`
+			Limit      int    `json:"limit,omitempty"`
+			Clear      bool   `json:"clear,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = 20
+		}
+
+		names := p.registry.Names()
+		if args.Connection != "" {
+			names = []string{args.Connection}
+		}
+
+		metrics := map[string]QueryMetrics{}
+		queries := []QueryRecord{}
+		for _, name := range names {
+			client, err := p.registry.Get(name)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			queryLog := client.QueryLog()
+			metrics[name] = queryLog.Metrics()
+			queries = append(queries, queryLog.Slowest()...)
+			if args.Clear {
+				queryLog.Clear()
+			}
+		}
+		sortSlowest(queries)
+		total := len(queries)
+		if len(queries) > args.Limit {
+			queries = queries[:args.Limit]
+		}
+
+		result := map[string]interface{}{
+			"queries":   queries,
+			"count":     len(queries),
+			"total":     total,
+			"metrics":   metrics,
+			"cleared":   args.Clear,
+			"truncated": total > len(queries),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createMaskingPolicyTool creates the tool inspecting the masking policies
+func (p *DatabaseProvider) createMaskingPolicyTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "database_masking",
+		Description: "Show the masking policy of each connection: the column rules (hash, redact, partial, null or block) and row filters applied to database_query results. With a query, show the tables it names, the rules that apply to it and whether it would be rejected, without running it",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"connection": {
+					"type": "string",
+					"description": "Connection whose policy to show (defaults to all connections, or the primary database when query is set)"
+				},
+				"query": {
+					"type": "string",
+					"description": "SQL query to check against the policy"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Connection string `json:"connection,omitempty"`
+			Query      string `json:"query,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Query != "" {
+			client, err := p.registry.Get(args.Connection)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			policy := client.Masking()
+			tables, columns, rows := policy.Matching(args.Query, client.mysqlSyntax())
+			result := map[string]interface{}{
+				"connection":   p.connectionName(args.Connection),
+				"tables":       tables,
+				"column_rules": columns,
+				"row_rules":    rows,
+				"allowed":      true,
+			}
+			if err := policy.Check(args.Query, client.mysqlSyntax()); err != nil {
+				result["allowed"] = false
+				result["reason"] = err.Error()
+			}
+			return p.formatJSONResult(result), nil
+		}
+
+		names := p.registry.Names()
+		if args.Connection != "" {
+			names = []string{args.Connection}
+		}
+		policies := map[string]interface{}{}
+		for _, name := range names {
+			client, err := p.registry.Get(name)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			policy := client.Masking()
+			policies[name] = map[string]interface{}{
+				"column_rules": policy.ColumnRules(),
+				"row_rules":    policy.RowRules(),
+			}
+		}
+		return p.formatJSONResult(map[string]interface{}{"connections": policies}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// connectionName resolves an empty connection argument to the default connection name
+func (p *DatabaseProvider) connectionName(name string) string {
+	if name == "" {
+		return p.registry.Default()
+	}
+	return name
+}
+
 // Helper functions
 func (p *DatabaseProvider) createErrorResult(err error) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
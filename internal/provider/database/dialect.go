@@ -0,0 +1,246 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"dev-mcp/internal/config"
+)
+
+// Dialect captures the driver-specific behaviour of a SQL backend
+type Dialect interface {
+	// Name returns the dialect name used in config (e.g. "mysql", "postgres")
+	Name() string
+	// DriverName returns the database/sql driver name
+	DriverName() string
+	// DSN builds the connection string from config
+	DSN(cfg *config.DatabaseConfig) string
+	// AllowedOperations returns the read-only statements permitted by default
+	AllowedOperations() []string
+	// BlockedOperations returns the statements blocked by default
+	BlockedOperations() []string
+	// DangerousPatterns returns dialect-specific regex patterns that are always rejected
+	DangerousPatterns() []string
+	// ListTablesQuery returns a query listing the tables and views of a schema as (name, type, estimated rows),
+	// taking the schema name (empty for the current schema) as its only argument
+	ListTablesQuery() string
+	// ListColumnsQuery returns a query listing the columns of a table as (name, type, nullable, default),
+	// taking the schema and table name as arguments
+	ListColumnsQuery() string
+	// ListConstraintsQuery returns a query listing the primary key, unique and foreign key columns of a table as
+	// (constraint, constraint type, column, referenced table, referenced column), taking the schema and table name as arguments
+	ListConstraintsQuery() string
+	// ListIndexesQuery returns a query listing the index columns of a table as (index, column, non-unique, primary, method),
+	// taking the schema and table name as arguments
+	ListIndexesQuery() string
+	// QuoteIdentifier quotes a schema or table name for use in generated SQL
+	QuoteIdentifier(name string) string
+	// Placeholders returns the number of bind arguments a query takes, ignoring string literals
+	Placeholders(query string) int
+}
+
+// NewDialect returns the dialect for a configured driver name
+func NewDialect(driver string) (Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "", "mysql", "mariadb":
+		return mysqlDialect{}, nil
+	case "postgres", "postgresql", "pgsql":
+		return postgresDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
+// mysqlDialect implements Dialect for MySQL / MariaDB
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string       { return "mysql" }
+func (mysqlDialect) DriverName() string { return "mysql" }
+
+func (mysqlDialect) DSN(cfg *config.DatabaseConfig) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
+		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+}
+
+func (mysqlDialect) AllowedOperations() []string {
+	return []string{"SELECT", "SHOW", "DESCRIBE", "EXPLAIN"}
+}
+
+func (mysqlDialect) BlockedOperations() []string {
+	return []string{"INSERT", "UPDATE", "DELETE", "DROP", "TRUNCATE", "ALTER", "CREATE"}
+}
+
+func (mysqlDialect) DangerousPatterns() []string {
+	return []string{
+		"INTO\\s+OUTFILE",
+		"INTO\\s+DUMPFILE",
+		"LOAD_FILE\\s*\\(",
+		"LOAD\\s+DATA",
+	}
+}
+
+func (mysqlDialect) ListTablesQuery() string {
+	return `SELECT table_name, table_type, COALESCE(table_rows, 0)
+		FROM information_schema.tables
+		WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE())
+		ORDER BY table_name`
+}
+
+func (mysqlDialect) ListColumnsQuery() string {
+	return `SELECT column_name, column_type, is_nullable, column_default
+		FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+		ORDER BY ordinal_position`
+}
+
+func (mysqlDialect) ListConstraintsQuery() string {
+	return `SELECT tc.constraint_name, tc.constraint_type, k.column_name,
+			COALESCE(k.referenced_table_name, ''), COALESCE(k.referenced_column_name, '')
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage k
+			ON k.constraint_schema = tc.constraint_schema
+			AND k.table_name = tc.table_name
+			AND k.constraint_name = tc.constraint_name
+		WHERE tc.table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND tc.table_name = ?
+			AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+		ORDER BY tc.constraint_name, k.ordinal_position`
+}
+
+func (mysqlDialect) ListIndexesQuery() string {
+	return `SELECT index_name, COALESCE(column_name, ''), non_unique,
+			CASE WHEN index_name = 'PRIMARY' THEN 1 ELSE 0 END, index_type
+		FROM information_schema.statistics
+		WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+		ORDER BY index_name, seq_in_index`
+}
+
+func (mysqlDialect) QuoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Placeholders counts the ? markers of a query
+func (mysqlDialect) Placeholders(query string) int {
+	code, _ := sqlCode(query, true)
+	return strings.Count(code, "?")
+}
+
+// postgresDialect implements Dialect for PostgreSQL
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string       { return "postgres" }
+func (postgresDialect) DriverName() string { return "postgres" }
+
+func (postgresDialect) DSN(cfg *config.DatabaseConfig) string {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 5432
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     fmt.Sprintf("%s:%d", cfg.Host, port),
+		Path:     "/" + cfg.DBName,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	return u.String()
+}
+
+func (postgresDialect) AllowedOperations() []string {
+	return []string{"SELECT", "SHOW", "EXPLAIN"}
+}
+
+func (postgresDialect) BlockedOperations() []string {
+	return []string{"INSERT", "UPDATE", "DELETE", "DROP", "TRUNCATE", "ALTER", "CREATE", "COPY", "GRANT", "REVOKE"}
+}
+
+func (postgresDialect) DangerousPatterns() []string {
+	return []string{
+		"COPY\\s+.*\\s+(TO|FROM)\\s+PROGRAM",
+		"PG_READ_FILE\\s*\\(",
+		"PG_READ_BINARY_FILE\\s*\\(",
+		"PG_LS_DIR\\s*\\(",
+		"LO_IMPORT\\s*\\(",
+		"LO_EXPORT\\s*\\(",
+		"DBLINK\\s*\\(",
+	}
+}
+
+func (postgresDialect) ListTablesQuery() string {
+	return `SELECT t.table_name, t.table_type, COALESCE(GREATEST(c.reltuples, 0), 0)::bigint
+		FROM information_schema.tables t
+		LEFT JOIN pg_namespace n ON n.nspname = t.table_schema
+		LEFT JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = t.table_name
+		WHERE t.table_schema = COALESCE(NULLIF($1::text, ''), current_schema())
+		ORDER BY t.table_name`
+}
+
+func (postgresDialect) ListColumnsQuery() string {
+	return `SELECT column_name,
+			CASE WHEN data_type IN ('USER-DEFINED', 'ARRAY') THEN udt_name ELSE data_type END,
+			is_nullable, column_default
+		FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF($1::text, ''), current_schema()) AND table_name = $2
+		ORDER BY ordinal_position`
+}
+
+func (postgresDialect) ListConstraintsQuery() string {
+	return `SELECT tc.constraint_name, tc.constraint_type, kcu.column_name,
+			COALESCE(ref.table_name, ''), COALESCE(ref.column_name, '')
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.constraint_name = tc.constraint_name
+			AND kcu.table_name = tc.table_name
+		LEFT JOIN information_schema.referential_constraints rc
+			ON rc.constraint_schema = tc.constraint_schema
+			AND rc.constraint_name = tc.constraint_name
+		LEFT JOIN information_schema.key_column_usage ref
+			ON ref.constraint_schema = rc.unique_constraint_schema
+			AND ref.constraint_name = rc.unique_constraint_name
+			AND ref.ordinal_position = kcu.position_in_unique_constraint
+		WHERE tc.table_schema = COALESCE(NULLIF($1::text, ''), current_schema()) AND tc.table_name = $2
+			AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+		ORDER BY tc.constraint_name, kcu.ordinal_position`
+}
+
+// ListIndexesQuery reads pg_index, since information_schema has no index views in PostgreSQL
+func (postgresDialect) ListIndexesQuery() string {
+	return `SELECT i.relname, COALESCE(a.attname, '(expression)'),
+			CASE WHEN ix.indisunique THEN 0 ELSE 1 END,
+			CASE WHEN ix.indisprimary THEN 1 ELSE 0 END,
+			am.amname
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_am am ON am.oid = i.relam
+		CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, position)
+		LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = COALESCE(NULLIF($1::text, ''), current_schema()) AND t.relname = $2
+		ORDER BY i.relname, k.position`
+}
+
+func (postgresDialect) QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Placeholders returns the highest $n of a query, since positional placeholders may repeat
+func (postgresDialect) Placeholders(query string) int {
+	code, _ := sqlCode(query, false)
+	highest := 0
+	for _, match := range positionalPattern.FindAllStringSubmatch(code, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"dev-mcp/internal/logging"
+)
+
+// writeOperations are the statements database_execute runs. DDL is left out: MySQL commits it implicitly, so
+// it could not be rolled back.
+var writeOperations = map[string]bool{"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true, "MERGE": true}
+
+// ExecStatement is a write statement and the values bound to its placeholders
+type ExecStatement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// StatementResult reports what a statement of a transaction did
+type StatementResult struct {
+	SQL          string `json:"sql"`
+	RowsAffected int64  `json:"rows_affected"`
+	LastInsertID int64  `json:"last_insert_id,omitempty"` // MySQL only
+}
+
+// ExecuteResult is the outcome of a transaction run by Execute
+type ExecuteResult struct {
+	Statements   []StatementResult `json:"statements"`
+	RowsAffected int64             `json:"rows_affected"`
+	Committed    bool              `json:"committed"`
+	DryRun       bool              `json:"dry_run"`
+	FailedAt     int               `json:"failed_at,omitempty"` // 1-based index of the statement that failed
+	Error        string            `json:"error,omitempty"`
+}
+
+// Execute runs write statements in one transaction. Unless commit is set the transaction is rolled back once
+// every statement ran, so the affected row counts can be checked first; a failing statement rolls back the
+// statements before it. Writes require unsafe mode, like writes through Query.
+func (c *DatabaseClient) Execute(ctx context.Context, statements []ExecStatement, commit bool) (*ExecuteResult, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if !c.unsafeMode {
+		return nil, fmt.Errorf("writes require unsafe mode; enable it with database_security first")
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("no statements to execute")
+	}
+	for i, statement := range statements {
+		if err := c.validateWrite(statement); err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+	}
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, queryError(ctx, "failed to begin transaction", err)
+	}
+
+	result := &ExecuteResult{DryRun: !commit, Statements: []StatementResult{}}
+	for i, statement := range statements {
+		res, err := tx.ExecContext(ctx, statement.SQL, statement.Args...)
+		if err != nil {
+			c.rollback(tx)
+			result.FailedAt = i + 1
+			result.Error = queryError(ctx, "failed to execute statement", err).Error()
+			return result, fmt.Errorf("statement %d failed, transaction rolled back: %s", i+1, result.Error)
+		}
+		affected, _ := res.RowsAffected()
+		statementResult := StatementResult{SQL: statement.SQL, RowsAffected: affected}
+		if c.mysqlSyntax() {
+			statementResult.LastInsertID, _ = res.LastInsertId()
+		}
+		result.Statements = append(result.Statements, statementResult)
+		result.RowsAffected += affected
+	}
+
+	if !commit {
+		c.rollback(tx)
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		err = queryError(ctx, "failed to commit transaction", err)
+		result.Error = err.Error()
+		return result, err
+	}
+	result.Committed = true
+	// The committed writes may have changed cached results
+	if c.cache != nil {
+		c.cache.Clear()
+	}
+	c.logger.Warn("transaction committed", logging.Int("statements", len(statements)), logging.Int("rows_affected", int(result.RowsAffected)))
+	return result, nil
+}
+
+// validateWrite checks that a statement is a single DML statement whose placeholders match its args
+func (c *DatabaseClient) validateWrite(statement ExecStatement) error {
+	code, _ := sqlCode(statement.SQL, c.mysqlSyntax())
+	fields := strings.Fields(code)
+	if len(fields) == 0 {
+		return fmt.Errorf("empty statement")
+	}
+	if operation := strings.ToUpper(fields[0]); !writeOperations[operation] {
+		return fmt.Errorf("operation '%s' is not allowed; only INSERT, UPDATE, DELETE, REPLACE and MERGE run in a transaction", operation)
+	}
+	if strings.Contains(strings.TrimRight(strings.TrimSpace(code), ";"), ";") {
+		return fmt.Errorf("one statement per entry; pass several statements as separate entries")
+	}
+	return checkPlaceholders(c.dialect, statement.SQL, len(statement.Args))
+}
+
+// rollback ends a transaction without its writes
+func (c *DatabaseClient) rollback(tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil {
+		c.logger.Warn("failed to roll back transaction", logging.Error(err))
+	}
+}
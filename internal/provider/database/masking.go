@@ -0,0 +1,582 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+// Masking actions of a column rule
+const (
+	MaskHash    = "hash"    // sha256: and 16 hex digits, so equal values stay comparable
+	MaskRedact  = "redact"  // [REDACTED]
+	MaskPartial = "partial" // Keeps the last 4 characters, or the domain of an email
+	MaskNull    = "null"    // NULL
+	MaskBlock   = "block"   // The column is dropped, and queries naming it are rejected
+)
+
+// MaskingMetaKey holds the masking report of a query in the _meta of its result
+const MaskingMetaKey = "dev-mcp/masking"
+
+const redactedValue = "[REDACTED]"
+
+// tableKeywordPattern finds the clauses naming the tables a query reads or writes
+var tableKeywordPattern = regexp.MustCompile(`(?i)\b(FROM|JOIN|UPDATE|INTO)\s+`)
+
+// fromPattern ends the select list of a query
+var fromPattern = regexp.MustCompile(`(?i)\bFROM\b`)
+
+// nestedQueryPattern finds subqueries, CTEs and set operations, whose select lists are not the result's
+var nestedQueryPattern = regexp.MustCompile(`(?i)\(\s*SELECT\b|^\s*WITH\b|\b(UNION|INTERSECT|EXCEPT)\b`)
+
+// selectPattern finds the SELECT keywords of a query, including those of subqueries
+var selectPattern = regexp.MustCompile(`\bSELECT\b`)
+
+// distinctPattern matches the set quantifier that may start a select list
+var distinctPattern = regexp.MustCompile(`(?i)^(DISTINCT|ALL)\s+`)
+
+// bareColumnPattern matches a select item that is a column, optionally qualified by its table
+var bareColumnPattern = regexp.MustCompile(`^([\w$]+\.)?[\w$]+$`)
+
+// ColumnRule masks a column of a table in query results
+type ColumnRule struct {
+	Table  string `json:"table"` // * for every table
+	Column string `json:"column"`
+	Action string `json:"action"`
+}
+
+// RowRule drops the rows of a table whose column holds one of the values
+type RowRule struct {
+	Table  string   `json:"table"`
+	Column string   `json:"column"`
+	Values []string `json:"values"`
+}
+
+// MaskingReport tells what the policy changed in a result
+type MaskingReport struct {
+	Tables       []string          `json:"tables"`
+	Masked       map[string]string `json:"masked,omitempty"`  // Result column -> action
+	Removed      []string          `json:"removed,omitempty"` // Blocked columns dropped from the result
+	RowsFiltered int               `json:"rows_filtered,omitempty"`
+}
+
+// Changed reports whether the policy altered the result
+func (r *MaskingReport) Changed() bool {
+	return len(r.Masked) > 0 || len(r.Removed) > 0 || r.RowsFiltered > 0
+}
+
+// MaskingPolicy applies the column and row rules of a connection to query results. Rules match the tables a
+// query names in its FROM, JOIN, UPDATE and INTO clauses, and the result columns by name. A blocked column
+// named anywhere in the query, a row-filtered table queried without returning its filter column as itself,
+// a masked column that the query names but does not return as a plain select item (renamed, inside an
+// expression, through a subquery, CTE or set operation, or only filtered on), and whole rows of a table with
+// rules are rejected.
+type MaskingPolicy struct {
+	columns []ColumnRule
+	rows    []RowRule
+}
+
+// NewMaskingPolicy builds the masking policy of a connection; invalid rules are skipped
+func NewMaskingPolicy(cfg config.DatabaseMaskingConfig, logger *logging.Logger) *MaskingPolicy {
+	policy := &MaskingPolicy{}
+	for _, rule := range cfg.Columns {
+		table, column, ok := strings.Cut(rule.Column, ".")
+		if !ok || table == "" || column == "" {
+			logger.Warn("skipping masking rule: column must be table.column or *.column", logging.String("column", rule.Column))
+			continue
+		}
+		switch rule.Action {
+		case MaskHash, MaskRedact, MaskPartial, MaskNull, MaskBlock:
+		default:
+			logger.Warn("skipping masking rule: action must be hash, redact, partial, null or block",
+				logging.String("column", rule.Column), logging.String("action", rule.Action))
+			continue
+		}
+		policy.columns = append(policy.columns, ColumnRule{Table: strings.ToLower(table), Column: strings.ToLower(column), Action: rule.Action})
+	}
+	for _, rule := range cfg.Rows {
+		if rule.Table == "" || rule.Column == "" || len(rule.Values) == 0 {
+			logger.Warn("skipping row filter: table, column and values are required", logging.String("table", rule.Table))
+			continue
+		}
+		policy.rows = append(policy.rows, RowRule{Table: strings.ToLower(rule.Table), Column: strings.ToLower(rule.Column), Values: rule.Values})
+	}
+	return policy
+}
+
+// Empty reports whether the policy has no rules
+func (m *MaskingPolicy) Empty() bool {
+	return len(m.columns) == 0 && len(m.rows) == 0
+}
+
+// ColumnRules returns the column rules of the policy
+func (m *MaskingPolicy) ColumnRules() []ColumnRule {
+	return slices.Clone(m.columns)
+}
+
+// RowRules returns the row rules of the policy
+func (m *MaskingPolicy) RowRules() []RowRule {
+	return slices.Clone(m.rows)
+}
+
+// Matching returns the rules that apply to the tables a query names
+func (m *MaskingPolicy) Matching(query string, mysql bool) ([]string, []ColumnRule, []RowRule) {
+	tables := referencedTables(identifierCode(query, mysql))
+	var columns []ColumnRule
+	for _, rule := range m.columns {
+		if rule.Table == "*" || slices.Contains(tables, rule.Table) {
+			columns = append(columns, rule)
+		}
+	}
+	var rows []RowRule
+	for _, rule := range m.rows {
+		if slices.Contains(tables, rule.Table) {
+			rows = append(rows, rule)
+		}
+	}
+	return tables, columns, rows
+}
+
+// Check rejects, before it runs, a query naming a blocked column of a table it queries, naming a masked
+// column other than as a plain item of the outer select list, not returning the filter column of a
+// row-filtered table as itself, or selecting whole rows of a table with rules
+func (m *MaskingPolicy) Check(query string, mysql bool) error {
+	if m.Empty() {
+		return nil
+	}
+	code := identifierCode(query, mysql)
+	selectList := selectList(code)
+	describes := describesOnly(query, mysql)
+	_, columns, rows := m.Matching(query, mysql)
+	_, names := tableReferences(code)
+	if !describes {
+		if err := checkWholeRows(code, names, columns, rows); err != nil {
+			return err
+		}
+	}
+	for _, rule := range columns {
+		if !mentions(code, rule.Column) {
+			continue
+		}
+		switch {
+		case rule.Action == MaskBlock:
+			return fmt.Errorf("column %s.%s is blocked by the masking policy and cannot be queried", rule.Table, rule.Column)
+		case describes:
+			// EXPLAIN, SHOW and DESCRIBE return no values to mask
+		case nestedQueryPattern.MatchString(code):
+			// Only the outer select list is matched against the result columns
+			return fmt.Errorf("column %s.%s is masked by the masking policy and cannot be queried through a subquery, CTE or set operation", rule.Table, rule.Column)
+		case !selectedAsItself(selectList, rule.Column), !mentions(selectList, rule.Column) && !selectsAll(selectList):
+			return maskedColumnError(rule)
+		}
+	}
+	for _, rule := range rows {
+		switch {
+		case describes:
+		case nestedQueryPattern.MatchString(code):
+			return fmt.Errorf("rows of %s are filtered on %s by the masking policy and cannot be queried through a subquery, CTE or set operation", rule.Table, rule.Column)
+		case !returnsColumnOf(selectList, rule.Column, rule.Table, names):
+			return fmt.Errorf("rows of %s are filtered on %s by the masking policy; select that column of the table as itself to query the table", rule.Table, rule.Column)
+		}
+	}
+	return nil
+}
+
+// checkWholeRows rejects select items using a table with rules, or one of its aliases, as a value, such as
+// SELECT u FROM users u or row_to_json(u): the row would carry its columns unmasked. Every select list is
+// checked, as a subquery may pass the row on.
+func checkWholeRows(code string, names map[string]string, columns []ColumnRule, rows []RowRule) error {
+	ruled := map[string]bool{}
+	for _, rule := range columns {
+		if rule.Table == "*" {
+			for _, table := range names {
+				ruled[table] = true
+			}
+			continue
+		}
+		ruled[rule.Table] = true
+	}
+	for _, rule := range rows {
+		ruled[rule.Table] = true
+	}
+	if len(ruled) == 0 {
+		return nil
+	}
+	upper := strings.ToUpper(code)
+	for _, start := range selectPattern.FindAllStringIndex(upper, -1) {
+		list := selectList(code[start[0]:])
+		for _, item := range splitSelectItems(list) {
+			item = distinctPattern.ReplaceAllString(item, "")
+			for name, table := range names {
+				if !ruled[table] || strings.EqualFold(item, name+".*") {
+					continue
+				}
+				if wholeRowPattern(name).MatchString(item) {
+					return fmt.Errorf("table %s has masking rules; select its columns rather than whole rows (%s)", table, item)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// returnsColumnOf reports whether a select list returns the column of a table under its own name: every
+// item naming the column is the column, bare or qualified by the table or one of its aliases, and the
+// column is selected or the list has a * or table.* item of the table
+func returnsColumnOf(list, column, table string, names map[string]string) bool {
+	selected := false
+	for _, item := range splitSelectItems(list) {
+		item = distinctPattern.ReplaceAllString(item, "")
+		if item == "*" {
+			selected = true
+			continue
+		}
+		if qualifier, ok := strings.CutSuffix(item, ".*"); ok && bareColumnPattern.MatchString(qualifier) {
+			selected = selected || names[strings.ToLower(qualifier)] == table
+			continue
+		}
+		if !mentions(item, column) {
+			continue
+		}
+		if !bareColumnPattern.MatchString(item) {
+			return false
+		}
+		qualifier, name, qualified := strings.Cut(item, ".")
+		if !qualified {
+			name = qualifier
+		}
+		if !strings.EqualFold(name, column) || qualified && names[strings.ToLower(qualifier)] != table {
+			return false
+		}
+		selected = true
+	}
+	return selected
+}
+
+func maskedColumnError(rule ColumnRule) error {
+	return fmt.Errorf("column %s.%s is masked by the masking policy and must be selected as itself, not renamed, inside an expression or only filtered on", rule.Table, rule.Column)
+}
+
+// Apply masks the columns and filters the rows of a result. The rows are copied, so results shared with the
+// query cache stay intact.
+func (m *MaskingPolicy) Apply(query string, mysql bool, columns []string, rows []map[string]interface{}) ([]string, []map[string]interface{}, *MaskingReport, error) {
+	tables, columnRules, rowRules := m.Matching(query, mysql)
+	report := &MaskingReport{Tables: tables}
+	if len(columnRules) == 0 && len(rowRules) == 0 || describesOnly(query, mysql) {
+		return columns, rows, report, nil
+	}
+
+	// Result columns by lower-case name; PostgreSQL folds unquoted names, MySQL keeps them as written
+	byName := map[string]string{}
+	for _, column := range columns {
+		byName[strings.ToLower(column)] = column
+	}
+	if err := m.Check(query, mysql); err != nil {
+		return nil, nil, nil, err
+	}
+	code := identifierCode(query, mysql)
+
+	actions := map[string]string{}
+	for _, rule := range columnRules {
+		column, ok := byName[rule.Column]
+		if !ok {
+			// A masked column the query names must come back under its own name
+			if rule.Action != MaskBlock && mentions(code, rule.Column) {
+				return nil, nil, nil, maskedColumnError(rule)
+			}
+			continue
+		}
+		actions[column] = rule.Action
+	}
+
+	filters := map[string][]string{}
+	for _, rule := range rowRules {
+		column, ok := byName[rule.Column]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("rows of %s are filtered on %s by the masking policy; select that column to query the table", rule.Table, rule.Column)
+		}
+		filters[column] = append(filters[column], rule.Values...)
+	}
+
+	kept := columns
+	if len(actions) > 0 {
+		kept = make([]string, 0, len(columns))
+		for _, column := range columns {
+			switch action := actions[column]; action {
+			case "":
+				kept = append(kept, column)
+			case MaskBlock:
+				report.Removed = append(report.Removed, column)
+			default:
+				kept = append(kept, column)
+				if report.Masked == nil {
+					report.Masked = map[string]string{}
+				}
+				report.Masked[column] = action
+			}
+		}
+	}
+
+	masked := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if filteredOut(row, filters) {
+			report.RowsFiltered++
+			continue
+		}
+		if len(actions) == 0 {
+			masked = append(masked, row)
+			continue
+		}
+		copied := make(map[string]interface{}, len(kept))
+		for _, column := range kept {
+			copied[column] = maskValue(row[column], actions[column])
+		}
+		masked = append(masked, copied)
+	}
+	return kept, masked, report, nil
+}
+
+// maskingSummary tells the model which values of a result are not the stored ones
+func maskingSummary(report *MaskingReport) string {
+	var parts []string
+	if len(report.Masked) > 0 {
+		masked := make([]string, 0, len(report.Masked))
+		for column, action := range report.Masked {
+			masked = append(masked, column+" ("+action+")")
+		}
+		slices.Sort(masked)
+		parts = append(parts, "masked "+strings.Join(masked, ", "))
+	}
+	if len(report.Removed) > 0 {
+		parts = append(parts, "removed blocked column(s) "+strings.Join(report.Removed, ", "))
+	}
+	if report.RowsFiltered > 0 {
+		parts = append(parts, fmt.Sprintf("filtered out %d row(s)", report.RowsFiltered))
+	}
+	return "Masking policy: " + strings.Join(parts, "; ")
+}
+
+// describesOnly reports whether a query returns metadata (EXPLAIN, SHOW, DESCRIBE) rather than table data
+func describesOnly(query string, mysql bool) bool {
+	code, _ := sqlCode(query, mysql)
+	fields := strings.Fields(code)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "EXPLAIN", "SHOW", "DESCRIBE", "DESC":
+		return true
+	}
+	return false
+}
+
+// filteredOut reports whether a row holds a filtered value in one of the filter columns
+func filteredOut(row map[string]interface{}, filters map[string][]string) bool {
+	for column, values := range filters {
+		value := row[column]
+		if value == nil {
+			continue
+		}
+		if slices.Contains(values, cellText(value, "")) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskValue applies a masking action to a value; NULL stays NULL
+func maskValue(value interface{}, action string) interface{} {
+	if value == nil || action == "" {
+		return value
+	}
+	text := cellText(value, "")
+	switch action {
+	case MaskHash:
+		sum := sha256.Sum256([]byte(text))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	case MaskRedact:
+		return redactedValue
+	case MaskPartial:
+		if at := strings.LastIndex(text, "@"); at > 0 {
+			return "***" + text[at:]
+		}
+		n := utf8.RuneCountInString(text)
+		if n <= 4 {
+			return strings.Repeat("*", n)
+		}
+		return strings.Repeat("*", n-4) + string([]rune(text)[n-4:])
+	case MaskNull:
+		return nil
+	}
+	return value
+}
+
+// identifierCode blanks the string literals of a query, leaving their quotes, but keeps quoted identifiers,
+// unquoted, so table and column names written as `users` or "users" are found like bare ones
+func identifierCode(query string, mysql bool) string {
+	var b strings.Builder
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		identifier := c == '`' && mysql || c == '"' && !mysql
+		if c != '\'' && c != '"' && c != '`' || c == '`' && !mysql {
+			b.WriteByte(c)
+			continue
+		}
+		end := closingQuote(query, i, c, !identifier)
+		if identifier {
+			b.WriteString(strings.ReplaceAll(query[i+1:min(end, len(query))], string(c)+string(c), string(c)))
+		} else {
+			// Keep the quotes, so a literal aliased to a column name is not taken for the column
+			b.WriteString("''" + strings.Repeat(" ", max(min(end, len(query)-1)-i-1, 0)))
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// referencedTables returns the lower-case names, without schema, of the tables a query names after FROM,
+// JOIN, UPDATE and INTO, including comma-separated lists
+func referencedTables(code string) []string {
+	tables, _ := tableReferences(code)
+	return tables
+}
+
+// tableReferences returns the tables of referencedTables, and the lower-case names each of them is known by
+// in the query (its own name and its aliases)
+func tableReferences(code string) ([]string, map[string]string) {
+	var tables []string
+	names := map[string]string{}
+	for _, match := range tableKeywordPattern.FindAllStringIndex(code, -1) {
+		rest := code[match[1]:]
+		for {
+			name := identifierPrefix(rest)
+			if name == "" {
+				break // A subquery or function; its own FROM is matched separately
+			}
+			if dot := strings.LastIndex(name, "."); dot >= 0 {
+				name = name[dot+1:]
+			}
+			table := strings.ToLower(name)
+			if table != "" && !slices.Contains(tables, table) {
+				tables = append(tables, table)
+			}
+			if table != "" {
+				names[table] = table
+			}
+			// Skip an alias, then continue with the next table of a comma-separated list
+			rest = strings.TrimLeft(rest[len(identifierPrefix(rest)):], " \t\r\n")
+			if word := identifierPrefix(rest); word != "" && !isClauseKeyword(word) {
+				if strings.EqualFold(word, "AS") {
+					rest = strings.TrimLeft(rest[len(word):], " \t\r\n")
+					word = identifierPrefix(rest)
+				}
+				if word != "" && table != "" {
+					names[strings.ToLower(word)] = table
+				}
+				rest = strings.TrimLeft(rest[len(word):], " \t\r\n")
+			}
+			if !strings.HasPrefix(rest, ",") {
+				break
+			}
+			rest = strings.TrimLeft(rest[1:], " \t\r\n")
+		}
+	}
+	return tables, names
+}
+
+// identifierPrefix returns the (possibly schema-qualified) identifier a string starts with
+func identifierPrefix(s string) string {
+	end := 0
+	for end < len(s) {
+		c := s[end]
+		if c == '_' || c == '$' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80 {
+			end++
+			continue
+		}
+		break
+	}
+	return s[:end]
+}
+
+// isClauseKeyword reports whether a word after a table name starts the next clause rather than an alias
+func isClauseKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "WHERE", "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "NATURAL", "ON", "USING", "GROUP", "ORDER",
+		"HAVING", "LIMIT", "OFFSET", "UNION", "EXCEPT", "INTERSECT", "SET", "VALUES", "WINDOW", "FOR", "RETURNING", "SELECT":
+		return true
+	}
+	return false
+}
+
+// selectList returns the part of a query between SELECT and its first FROM
+func selectList(code string) string {
+	upper := strings.ToUpper(code)
+	start := strings.Index(upper, "SELECT")
+	if start < 0 {
+		return ""
+	}
+	list := code[start+len("SELECT"):]
+	if from := fromPattern.FindStringIndex(list); from != nil {
+		list = list[:from[0]]
+	}
+	return list
+}
+
+// selectedAsItself reports whether every select item naming a column is that column, bare or qualified
+func selectedAsItself(list, column string) bool {
+	for _, item := range splitSelectItems(list) {
+		item = distinctPattern.ReplaceAllString(item, "")
+		if mentions(item, column) && (!bareColumnPattern.MatchString(item) || !mentions(item[strings.LastIndex(item, ".")+1:], column)) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectsAll reports whether a select list has a * or table.* item
+func selectsAll(list string) bool {
+	for _, item := range splitSelectItems(list) {
+		if item = distinctPattern.ReplaceAllString(item, ""); item == "*" || strings.HasSuffix(item, ".*") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSelectItems splits a select list on the commas outside parentheses
+func splitSelectItems(list string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(list[start:]))
+}
+
+// wholeRowPattern matches a table name or alias used as a value (u, row_to_json(u), (u.*)::text) rather than
+// to qualify a column
+func wholeRowPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(^|[^\w$.])` + regexp.QuoteMeta(name) + `(\s*\.\s*\*)?($|[^\w$.])`)
+}
+
+// mentions reports whether code names a column as a whole word
+func mentions(code, column string) bool {
+	return regexp.MustCompile(`(?i)(^|[^\w$])` + regexp.QuoteMeta(column) + `($|[^\w$])`).MatchString(code)
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"testing"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+func testMaskingPolicy() *MaskingPolicy {
+	return NewMaskingPolicy(config.DatabaseMaskingConfig{
+		Columns: []config.ColumnMaskConfig{{Column: "users.email", Action: MaskRedact}},
+		Rows:    []config.RowFilterConfig{{Table: "users", Column: "tenant", Values: []string{"internal"}}},
+	}, logging.New("test"))
+}
+
+func TestMaskingRowFilterColumnMustBeItself(t *testing.T) {
+	policy := testMaskingPolicy()
+	rejected := []string{
+		"SELECT 1 AS tenant, email FROM users",
+		"SELECT 'x' tenant, email FROM users",
+		"SELECT o.tenant, u.email FROM users u JOIN orgs o ON o.id = u.org_id",
+		"SELECT o.*, u.email FROM users u JOIN orgs o ON o.id = u.org_id",
+		"SELECT tenant, email FROM (SELECT 1 AS tenant, email FROM users) s",
+		"SELECT email FROM users",
+	}
+	for _, query := range rejected {
+		if err := policy.Check(query, false); err == nil {
+			t.Errorf("Check(%q) = nil, want an error", query)
+		}
+	}
+	allowed := []string{
+		"SELECT tenant, email FROM users",
+		"SELECT u.tenant, u.email FROM users u",
+		"SELECT * FROM users",
+		"SELECT u.* FROM users u JOIN orgs o ON o.id = u.org_id",
+	}
+	for _, query := range allowed {
+		if err := policy.Check(query, false); err != nil {
+			t.Errorf("Check(%q) = %v, want nil", query, err)
+		}
+	}
+}
+
+func TestMaskingApplyFiltersAliasedLiteral(t *testing.T) {
+	policy := testMaskingPolicy()
+	rows := []map[string]interface{}{{"tenant": int64(1), "email": "a@example.com"}}
+	if _, _, _, err := policy.Apply("SELECT 1 AS tenant, email FROM users", false, []string{"tenant", "email"}, rows); err == nil {
+		t.Fatal("Apply accepted a literal aliased to the filter column")
+	}
+}
+
+func TestMaskingRejectsWholeRows(t *testing.T) {
+	policy := testMaskingPolicy()
+	rejected := []string{
+		"SELECT u FROM users u",
+		"SELECT u, tenant FROM users u",
+		"SELECT row_to_json(u), u.tenant FROM users u",
+		"SELECT to_jsonb(users.*), tenant FROM users",
+		"SELECT (u.*)::text, u.tenant FROM users AS u",
+		"SELECT x FROM (SELECT row_to_json(u) AS x FROM users u) s",
+	}
+	for _, query := range rejected {
+		if err := policy.Check(query, false); err == nil {
+			t.Errorf("Check(%q) = nil, want an error", query)
+		}
+	}
+	if err := policy.Check("SELECT u.tenant, u.email FROM users u", false); err != nil {
+		t.Errorf("Check rejected a query selecting columns: %v", err)
+	}
+	// Tables without rules may still be selected whole
+	if err := policy.Check("SELECT o FROM orgs o", false); err != nil {
+		t.Errorf("Check rejected a whole row of a table without rules: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// positionalPattern matches PostgreSQL positional placeholders ($1, $2, ...)
+var positionalPattern = regexp.MustCompile(`\$(\d+)`)
+
+// sqlCode blanks out the string literals and quoted identifiers of a query, so placeholders and keywords
+// are only found in the SQL itself, and reports how many string literals it held. MySQL strings honour
+// backslash escapes; PostgreSQL ones only with the E prefix, and $tag$ dollar-quoted strings count as literals.
+func sqlCode(query string, mysql bool) (string, int) {
+	code := []byte(query)
+	literals := 0
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(code); i++ {
+			code[i] = ' '
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || (c == '`' && mysql):
+			escapes := mysql && c != '`' || !mysql && c == '\'' && i > 0 && (query[i-1] == 'E' || query[i-1] == 'e')
+			end := closingQuote(query, i, c, escapes)
+			if c == '\'' || c == '"' && mysql {
+				literals++
+			}
+			blank(i+1, end)
+			i = end
+		case c == '$' && !mysql:
+			tag, ok := dollarTag(query[i:])
+			if !ok {
+				continue
+			}
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + len(tag)
+			}
+			literals++
+			blank(i+len(tag), end)
+			i = end + len(tag) - 1
+		}
+	}
+	return string(code), literals
+}
+
+// closingQuote returns the index of the quote closing the one at start, skipping doubled quotes and,
+// when escapes is set, backslash-escaped characters; an unterminated literal runs to the end
+func closingQuote(query string, start int, quote byte, escapes bool) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(query)
+}
+
+// dollarTag returns the opening $tag$ (or $$) of a PostgreSQL dollar-quoted string; $1 is a placeholder
+func dollarTag(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1], true
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// checkPlaceholders verifies that a query takes exactly as many bind arguments as given
+func checkPlaceholders(dialect Dialect, query string, args int) error {
+	if dialect == nil {
+		return nil
+	}
+	want := dialect.Placeholders(query)
+	if want != args {
+		return fmt.Errorf("query has %d placeholder(s) but %d param(s) were given", want, args)
+	}
+	return nil
+}
+
+// BindParams converts JSON query params to driver arguments: integral numbers become int64 and other
+// numbers float64, strings, booleans and null pass through; arrays and objects are rejected
+func BindParams(params []json.RawMessage) ([]interface{}, error) {
+	args := make([]interface{}, 0, len(params))
+	for i, raw := range params {
+		var value interface{}
+		decoder := json.NewDecoder(strings.NewReader(string(raw)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("params[%d]: %w", i, err)
+		}
+		switch v := value.(type) {
+		case nil, string, bool:
+			args = append(args, v)
+		case json.Number:
+			if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+				args = append(args, n)
+			} else if f, err := v.Float64(); err == nil {
+				args = append(args, f)
+			} else {
+				return nil, fmt.Errorf("params[%d]: invalid number %s", i, v)
+			}
+		default:
+			return nil, fmt.Errorf("params[%d] must be a string, number, boolean or null", i)
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"container/list"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultQueryCacheTTL     = 60 * time.Second
+	defaultQueryCacheEntries = 200
+	// maxCachedRows keeps large results out of the cache; they are paged from the result cache instead
+	maxCachedRows = 10000
+)
+
+// readOnlyOperations are the statements that only read
+var readOnlyOperations = map[string]bool{"SELECT": true, "SHOW": true, "DESCRIBE": true, "DESC": true, "EXPLAIN": true}
+
+// sideEffectPattern matches read statements that write or lock anyway: SELECT ... INTO, SELECT ... FOR UPDATE,
+// EXPLAIN ANALYZE (which runs the statement) and sequence functions
+var sideEffectPattern = regexp.MustCompile(`(?i)\b(INTO|FOR\s+(UPDATE|SHARE|NO\s+KEY\s+UPDATE|KEY\s+SHARE)|LOCK|ANALYZE|NEXTVAL|SETVAL)\b`)
+
+// QueryCacheStats counts the use of a query cache
+type QueryCacheStats struct {
+	Enabled     bool    `json:"enabled"`
+	Entries     int     `json:"entries"`
+	MaxEntries  int     `json:"max_entries"`
+	TTLSeconds  int     `json:"ttl_seconds"`
+	Hits        int64   `json:"hits"`
+	Misses      int64   `json:"misses"`
+	HitRate     float64 `json:"hit_rate"`
+	Evictions   int64   `json:"evictions"`
+	Expirations int64   `json:"expirations"`
+}
+
+// queryCacheEntry is a cached read-only query result
+type queryCacheEntry struct {
+	key      string
+	columns  []string
+	rows     []map[string]interface{}
+	cachedAt time.Time
+}
+
+// QueryCache is an LRU cache with a TTL of read-only query results, keyed by the normalized query text and
+// its bound params, so repeated schema and lookup queries don't reach the database
+type QueryCache struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	maxEntries  int
+	order       *list.List // Front is the most recently used
+	entries     map[string]*list.Element
+	hits        int64
+	misses      int64
+	evictions   int64
+	expirations int64
+}
+
+// NewQueryCache creates the query cache of a connection, or returns nil when it is disabled
+func NewQueryCache(cfg config.DatabaseCacheConfig) *QueryCache {
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return nil
+	}
+	c := &QueryCache{
+		ttl:        defaultQueryCacheTTL,
+		maxEntries: defaultQueryCacheEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+	if cfg.TTLSeconds > 0 {
+		c.ttl = time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	if cfg.MaxEntries > 0 {
+		c.maxEntries = cfg.MaxEntries
+	}
+	return c
+}
+
+// queryCacheKey builds the cache key of a query and its bound params
+func queryCacheKey(query string, args []interface{}) string {
+	params, _ := json.Marshal(args)
+	return normalizeQuery(query) + "\x00" + string(params)
+}
+
+// normalizeQuery collapses the whitespace outside string literals and quoted identifiers and drops a trailing
+// semicolon, so the same query written differently shares a cache entry. Case is kept: MySQL table names
+// can be case-sensitive.
+func normalizeQuery(query string) string {
+	var b strings.Builder
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch c {
+		case ' ', '\t', '\n', '\r', '\f', '\v':
+			space = true
+			continue
+		case '\'', '"', '`':
+			end := closingQuote(query, i, c, c != '`')
+			if end >= len(query) {
+				end = len(query) - 1
+			}
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteString(query[i : end+1])
+			i = end
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteByte(c)
+	}
+	return strings.TrimRight(b.String(), "; ")
+}
+
+// isReadOnlyQuery reports whether a query only reads, judging by its SQL outside string literals; such
+// queries may be cached and run on read replicas
+func isReadOnlyQuery(query string, mysql bool) bool {
+	code, _ := sqlCode(query, mysql)
+	fields := strings.Fields(code)
+	if len(fields) == 0 || !readOnlyOperations[strings.ToUpper(fields[0])] {
+		return false
+	}
+	return !sideEffectPattern.MatchString(code)
+}
+
+// Get returns the cached result of a query unless it expired
+func (c *QueryCache) Get(query string, args []interface{}) ([]string, []map[string]interface{}, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[queryCacheKey(query, args)]
+	if !ok {
+		c.misses++
+		return nil, nil, time.Time{}, false
+	}
+	entry := element.Value.(*queryCacheEntry)
+	if time.Since(entry.cachedAt) > c.ttl {
+		c.removeLocked(element)
+		c.expirations++
+		c.misses++
+		return nil, nil, time.Time{}, false
+	}
+	c.order.MoveToFront(element)
+	c.hits++
+	return entry.columns, entry.rows, entry.cachedAt, true
+}
+
+// Put caches the result of a query, evicting the least recently used results when full; results with more
+// than maxCachedRows rows are not cached
+func (c *QueryCache) Put(query string, args []interface{}, columns []string, rows []map[string]interface{}) {
+	if len(rows) > maxCachedRows {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := queryCacheKey(query, args)
+	if element, ok := c.entries[key]; ok {
+		c.removeLocked(element)
+	}
+	c.entries[key] = c.order.PushFront(&queryCacheEntry{key: key, columns: columns, rows: rows, cachedAt: time.Now()})
+	for c.order.Len() > c.maxEntries {
+		c.removeLocked(c.order.Back())
+		c.evictions++
+	}
+}
+
+// Clear drops every cached result and returns how many there were
+func (c *QueryCache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cleared := c.order.Len()
+	c.order.Init()
+	c.entries = map[string]*list.Element{}
+	return cleared
+}
+
+// Stats returns the size and hit counts of the cache
+func (c *QueryCache) Stats() QueryCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := QueryCacheStats{
+		Enabled:     true,
+		Entries:     c.order.Len(),
+		MaxEntries:  c.maxEntries,
+		TTLSeconds:  int(c.ttl / time.Second),
+		Hits:        c.hits,
+		Misses:      c.misses,
+		Evictions:   c.evictions,
+		Expirations: c.expirations,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+func (c *QueryCache) removeLocked(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*queryCacheEntry).key)
+}
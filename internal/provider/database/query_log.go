@@ -0,0 +1,140 @@
+package database
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultSlowQueryThreshold = time.Second
+	defaultSlowQueriesKept    = 50
+)
+
+// QueryRecord describes one database_query call
+type QueryRecord struct {
+	Connection string    `json:"connection"`
+	Query      string    `json:"query"`
+	Params     int       `json:"params"`
+	Target     string    `json:"target,omitempty"` // Where it ran: primary, cache or a replica
+	Caller     string    `json:"caller,omitempty"`
+	OnBehalfOf string    `json:"on_behalf_of,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	Rows       int       `json:"rows"`
+	Error      string    `json:"error,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// QueryMetrics sums up the queries of a connection since the server started
+type QueryMetrics struct {
+	Queries       int64   `json:"queries"`
+	Errors        int64   `json:"errors"`
+	CacheHits     int64   `json:"cache_hits"`
+	SlowQueries   int64   `json:"slow_queries"`
+	RowsReturned  int64   `json:"rows_returned"`
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+	MaxDurationMs float64 `json:"max_duration_ms"`
+	ThresholdMs   int64   `json:"slow_threshold_ms"`
+	totalMs       float64
+}
+
+// QueryLog keeps the metrics of a connection's queries and the slowest queries over the slow threshold. Once
+// full, a new slow query replaces the fastest one kept when it is slower.
+type QueryLog struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	keep      int
+	slow      []QueryRecord
+	metrics   QueryMetrics
+}
+
+// NewQueryLog creates the query log of a connection
+func NewQueryLog(cfg config.DatabaseSlowQueryConfig) *QueryLog {
+	l := &QueryLog{threshold: defaultSlowQueryThreshold, keep: defaultSlowQueriesKept}
+	if cfg.ThresholdMs > 0 {
+		l.threshold = time.Duration(cfg.ThresholdMs) * time.Millisecond
+	}
+	if cfg.Keep > 0 {
+		l.keep = cfg.Keep
+	}
+	l.metrics.ThresholdMs = l.threshold.Milliseconds()
+	return l
+}
+
+// Record adds a query to the metrics and reports whether it was slow
+func (l *QueryLog) Record(record QueryRecord, duration time.Duration) bool {
+	record.DurationMs = float64(duration.Microseconds()) / 1000
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.metrics.Queries++
+	l.metrics.RowsReturned += int64(record.Rows)
+	l.metrics.totalMs += record.DurationMs
+	l.metrics.MaxDurationMs = max(l.metrics.MaxDurationMs, record.DurationMs)
+	if record.Error != "" {
+		l.metrics.Errors++
+	}
+	if record.Target == TargetCache {
+		l.metrics.CacheHits++
+	}
+	if duration < l.threshold {
+		return false
+	}
+
+	l.metrics.SlowQueries++
+	if len(l.slow) < l.keep {
+		l.slow = append(l.slow, record)
+		return true
+	}
+	fastest := 0
+	for i := range l.slow {
+		if l.slow[i].DurationMs < l.slow[fastest].DurationMs {
+			fastest = i
+		}
+	}
+	if record.DurationMs > l.slow[fastest].DurationMs {
+		l.slow[fastest] = record
+	}
+	return true
+}
+
+// Slowest returns the slow queries kept, slowest first
+func (l *QueryLog) Slowest() []QueryRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records := make([]QueryRecord, len(l.slow))
+	copy(records, l.slow)
+	sortSlowest(records)
+	return records
+}
+
+// Metrics returns the totals of the connection's queries
+func (l *QueryLog) Metrics() QueryMetrics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	metrics := l.metrics
+	if metrics.Queries > 0 {
+		metrics.AvgDurationMs = metrics.totalMs / float64(metrics.Queries)
+	}
+	return metrics
+}
+
+// Clear forgets the slow queries kept and returns how many there were; the metrics keep counting
+func (l *QueryLog) Clear() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cleared := len(l.slow)
+	l.slow = nil
+	return cleared
+}
+
+// sortSlowest orders records slowest first
+func sortSlowest(records []QueryRecord) {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].DurationMs > records[j].DurationMs })
+}
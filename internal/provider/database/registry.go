@@ -0,0 +1,173 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+// DefaultConnection is the name of the connection built from the top-level database config
+const DefaultConnection = "default"
+
+// ConnectionStatus describes a configured connection and whether it is usable
+type ConnectionStatus struct {
+	Name      string `json:"name"`
+	Driver    string `json:"driver"`
+	Host      string `json:"host"`
+	DBName    string `json:"dbname"`
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"`
+
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+}
+
+// Registry holds a named set of database clients
+type Registry struct {
+	mu          sync.RWMutex
+	clients     map[string]*DatabaseClient
+	statuses    map[string]ConnectionStatus
+	defaultName string
+	logger      *logging.Logger
+}
+
+// NewRegistry connects to the primary database and every named connection.
+// Connections that fail are recorded in the status list but not registered.
+func NewRegistry(primary *config.DatabaseConfig, connections []config.DatabaseConfig) *Registry {
+	r := &Registry{
+		clients:  map[string]*DatabaseClient{},
+		statuses: map[string]ConnectionStatus{},
+		logger:   logging.New("DatabaseRegistry"),
+	}
+
+	if primary != nil && primary.Host != "" {
+		name := primary.Name
+		if name == "" {
+			name = DefaultConnection
+		}
+		r.add(name, primary)
+		r.defaultName = name
+	}
+
+	for i := range connections {
+		cfg := &connections[i]
+		if cfg.Name == "" {
+			r.logger.Warn("skipping database connection without name", logging.Int("index", i))
+			continue
+		}
+		if _, exists := r.statuses[cfg.Name]; exists {
+			r.logger.Warn("skipping duplicate database connection", logging.String("name", cfg.Name))
+			continue
+		}
+		r.add(cfg.Name, cfg)
+	}
+
+	// Fall back to the first healthy named connection when no primary is configured
+	if _, ok := r.clients[r.defaultName]; !ok {
+		for _, name := range r.Names() {
+			r.defaultName = name
+			break
+		}
+	}
+
+	return r
+}
+
+// add connects a single database and records the outcome
+func (r *Registry) add(name string, cfg *config.DatabaseConfig) {
+	status := ConnectionStatus{
+		Name:   name,
+		Driver: cfg.Driver,
+		Host:   cfg.Host,
+		DBName: cfg.DBName,
+	}
+	if status.Driver == "" {
+		status.Driver = "mysql"
+	}
+
+	client, err := NewDatabaseClient(cfg)
+	if err != nil {
+		r.logger.Warn("database connection failed", logging.String("name", name), logging.Error(err))
+		status.Error = err.Error()
+	} else {
+		r.clients[name] = client
+		status.Connected = true
+	}
+	r.statuses[name] = status
+}
+
+// Get returns the client for a connection name; an empty name selects the default connection
+func (r *Registry) Get(name string) (*DatabaseClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if name == "" {
+		name = r.defaultName
+	}
+	if client, ok := r.clients[name]; ok {
+		return client, nil
+	}
+	if status, ok := r.statuses[name]; ok {
+		return nil, fmt.Errorf("database connection %q is not available: %s", name, status.Error)
+	}
+	return nil, fmt.Errorf("unknown database connection %q (available: %v)", name, r.namesLocked())
+}
+
+// Default returns the name of the default connection
+func (r *Registry) Default() string {
+	return r.defaultName
+}
+
+// Names returns the sorted names of the connected databases
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.namesLocked()
+}
+
+func (r *Registry) namesLocked() []string {
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Statuses returns the status of every configured connection, sorted by name
+func (r *Registry) Statuses() []ConnectionStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ConnectionStatus, 0, len(r.statuses))
+	for name, status := range r.statuses {
+		if client, ok := r.clients[name]; ok {
+			status.Replicas = client.ReplicaStatuses()
+		}
+		out = append(out, status)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Len returns the number of connected databases
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.clients)
+}
+
+// Close closes every database connection
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var firstErr error
+	for name, client := range r.clients {
+		if err := client.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close database connection %q: %w", name, err)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+const (
+	// replicaEviction is how long a failed replica is left out of the rotation before it is probed again
+	replicaEviction = 30 * time.Second
+	// replicaProbeTimeout bounds the ping deciding whether a replica is down
+	replicaProbeTimeout = 3 * time.Second
+	// RoutingMetaKey holds the routing report of a query in the _meta of its result
+	RoutingMetaKey = "dev-mcp/routing"
+)
+
+// Query targets reported by Routing
+const (
+	TargetPrimary = "primary"
+	TargetCache   = "cache"
+)
+
+// Routing reports where a query ran and why
+type Routing struct {
+	Target          string   `json:"target"` // primary, cache or the name of a replica
+	Reason          string   `json:"reason"`
+	Failed          []string `json:"failed,omitempty"` // Replicas evicted while trying the query
+	Replicas        int      `json:"replicas"`
+	HealthyReplicas int      `json:"healthy_replicas"`
+}
+
+// ReplicaStatus describes a read replica and its health
+type ReplicaStatus struct {
+	Name         string     `json:"name"`
+	Host         string     `json:"host"`
+	Healthy      bool       `json:"healthy"`
+	EvictedUntil *time.Time `json:"evicted_until,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	Queries      int64      `json:"queries"`
+	Failures     int64      `json:"failures"`
+}
+
+// replica is a read replica of a connection. A replica that fails is evicted from the rotation for
+// replicaEviction and pinged before it gets queries again.
+type replica struct {
+	name string
+	host string
+	db   *sql.DB
+
+	mu           sync.Mutex
+	evictedUntil time.Time
+	lastError    string
+	queries      int64
+	failures     int64
+}
+
+// openReplicas opens the read replicas of a connection. A replica that cannot be reached starts evicted;
+// one whose connection cannot even be opened is skipped.
+func openReplicas(cfg *config.DatabaseConfig, dialect Dialect, logger *logging.Logger) []*replica {
+	var replicas []*replica
+	for i, replicaCfg := range cfg.Replicas {
+		name := replicaCfg.Name
+		if name == "" {
+			name = fmt.Sprintf("replica-%d", i+1)
+		}
+		// Unset fields are taken from the connection
+		dsnCfg := *cfg
+		if replicaCfg.Host != "" {
+			dsnCfg.Host = replicaCfg.Host
+		}
+		if replicaCfg.Port != 0 {
+			dsnCfg.Port = replicaCfg.Port
+		}
+		if replicaCfg.Username != "" {
+			dsnCfg.Username = replicaCfg.Username
+		}
+		if replicaCfg.Password != "" {
+			dsnCfg.Password = replicaCfg.Password
+		}
+
+		db, err := sql.Open(dialect.DriverName(), dialect.DSN(&dsnCfg))
+		if err != nil {
+			logger.Warn("skipping read replica", logging.String("replica", name), logging.Error(err))
+			continue
+		}
+		db.SetMaxOpenConns(10)
+		db.SetMaxIdleConns(5)
+
+		r := &replica{name: name, host: dsnCfg.Host, db: db}
+		if err := r.ping(); err != nil {
+			logger.Warn("read replica unreachable, evicted", logging.String("replica", name), logging.Error(err))
+			r.evict(err)
+		}
+		replicas = append(replicas, r)
+	}
+	return replicas
+}
+
+func (r *replica) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaProbeTimeout)
+	defer cancel()
+	return r.db.PingContext(ctx)
+}
+
+// evict takes the replica out of the rotation for replicaEviction
+func (r *replica) evict(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictedUntil = time.Now().Add(replicaEviction)
+	r.lastError = err.Error()
+	r.failures++
+}
+
+// usable reports whether the replica may take a query, pinging it first when its eviction has run out
+func (r *replica) usable() bool {
+	r.mu.Lock()
+	evicted := !r.evictedUntil.IsZero()
+	waiting := time.Now().Before(r.evictedUntil)
+	r.mu.Unlock()
+
+	if !evicted {
+		return true
+	}
+	if waiting {
+		return false
+	}
+	if err := r.ping(); err != nil {
+		r.evict(err)
+		return false
+	}
+	r.restore()
+	return true
+}
+
+// restore puts an evicted replica back in the rotation
+func (r *replica) restore() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictedUntil = time.Time{}
+}
+
+func (r *replica) status() ReplicaStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := ReplicaStatus{
+		Name:      r.name,
+		Host:      r.host,
+		Healthy:   r.evictedUntil.IsZero(),
+		LastError: r.lastError,
+		Queries:   r.queries,
+		Failures:  r.failures,
+	}
+	if !r.evictedUntil.IsZero() {
+		until := r.evictedUntil
+		status.EvictedUntil = &until
+	}
+	return status
+}
+
+// nextReplica returns the next usable replica in round-robin order, or nil when none is usable
+func (c *DatabaseClient) nextReplica() *replica {
+	for range c.replicas {
+		c.replicaMu.Lock()
+		r := c.replicas[c.nextIndex%len(c.replicas)]
+		c.nextIndex++
+		c.replicaMu.Unlock()
+		if r.usable() {
+			return r
+		}
+	}
+	return nil
+}
+
+// healthyReplicas counts the replicas in the rotation
+func (c *DatabaseClient) healthyReplicas() int {
+	healthy := 0
+	for _, r := range c.replicas {
+		if r.status().Healthy {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// routedQuery runs a validated query on a replica when it only reads, failing over to the next replica and
+// finally the primary when replicas are down. A replica is only evicted when it also fails a ping: a query
+// that fails on a healthy replica would fail on the primary too, so its error is returned.
+func (c *DatabaseClient) routedQuery(ctx context.Context, query string, args []interface{}) ([]string, []map[string]interface{}, *Routing, error) {
+	routing := &Routing{Target: TargetPrimary, Replicas: len(c.replicas)}
+	switch {
+	case len(c.replicas) == 0:
+		routing.Reason = "no read replicas configured"
+	case !isReadOnlyQuery(query, c.mysqlSyntax()):
+		routing.Reason = "writes and locking reads run on the primary"
+	default:
+		routing.Reason = "no healthy read replica; fell back to the primary"
+		for range c.replicas {
+			r := c.nextReplica()
+			if r == nil {
+				break
+			}
+			columns, rows, err := c.runQuery(ctx, r.db, query, args)
+			if err == nil {
+				r.mu.Lock()
+				r.queries++
+				r.mu.Unlock()
+				routing.Target = r.name
+				routing.Reason = "read-only query routed round-robin to a healthy replica"
+				routing.HealthyReplicas = c.healthyReplicas()
+				return columns, rows, routing, nil
+			}
+			if ctx.Err() != nil || r.ping() == nil {
+				return nil, nil, routing, err
+			}
+			c.logger.Warn("read replica failed, evicted", logging.String("replica", r.name), logging.Error(err))
+			r.evict(err)
+			routing.Failed = append(routing.Failed, r.name)
+		}
+	}
+
+	columns, rows, err := c.runQuery(ctx, c.db, query, args)
+	routing.HealthyReplicas = c.healthyReplicas()
+	return columns, rows, routing, err
+}
+
+// ReplicaStatuses returns the health of the connection's read replicas
+func (c *DatabaseClient) ReplicaStatuses() []ReplicaStatus {
+	statuses := make([]ReplicaStatus, 0, len(c.replicas))
+	for _, r := range c.replicas {
+		statuses = append(statuses, r.status())
+	}
+	return statuses
+}
+
+// checkReplicas pings every replica, evicting the ones that fail and restoring the ones that answer
+func (c *DatabaseClient) checkReplicas() {
+	for _, r := range c.replicas {
+		if err := r.ping(); err != nil {
+			r.evict(err)
+			continue
+		}
+		r.restore()
+	}
+}
@@ -0,0 +1,234 @@
+package database
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPageSize   = 50
+	maxPageSize       = 500
+	resultCacheTTL    = 10 * time.Minute
+	resultCacheMaxLen = 20
+)
+
+// CachedResult is a full query result, as masked for its owner, kept server-side so later pages don't
+// re-run the query
+type CachedResult struct {
+	Key        string // Random, so cursors cannot be computed from the query
+	Owner      string // Caller the result was returned to; only they may page through it
+	hash       string // resultKey of the connection, query and params
+	Connection string
+	Query      string
+	Args       []interface{}
+	Columns    []string
+	Rows       []map[string]interface{}
+	CreatedAt  time.Time
+	lastUsed   time.Time
+}
+
+// Page is a slice of a cached result
+type Page struct {
+	Connection string                   `json:"connection"`
+	Columns    []string                 `json:"columns"`
+	Rows       []map[string]interface{} `json:"rows"`
+	Offset     int                      `json:"offset"`
+	Returned   int                      `json:"returned"`
+	TotalRows  int                      `json:"total_rows"`
+	HasMore    bool                     `json:"has_more"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	CachedAt   time.Time                `json:"cached_at"`
+}
+
+// ResultCache holds recent query results under random keys, found again by their cursors or by the owner,
+// connection, query and bound params
+type ResultCache struct {
+	mu      sync.Mutex
+	entries map[string]*CachedResult
+}
+
+// NewResultCache creates an empty result cache
+func NewResultCache() *ResultCache {
+	return &ResultCache{entries: map[string]*CachedResult{}}
+}
+
+// resultKey hashes a connection name, query and its bound params into a cache key
+func resultKey(connection, query string, args []interface{}) string {
+	params, _ := json.Marshal(args)
+	sum := sha256.Sum256([]byte(connection + "\x00" + strings.TrimSpace(query) + "\x00" + string(params)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Get returns a fresh result cached for the owner with a connection, query and params
+func (c *ResultCache) Get(owner, connection, query string, args []interface{}) (*CachedResult, bool) {
+	hash := resultKey(connection, query, args)
+	c.mu.Lock()
+	var key string
+	for k, entry := range c.entries {
+		if entry.hash == hash && entry.Owner == owner {
+			key = k
+			break
+		}
+	}
+	c.mu.Unlock()
+	if key == "" {
+		return nil, false
+	}
+	return c.getByKey(key)
+}
+
+func (c *ResultCache) getByKey(key string) (*CachedResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(entry.CreatedAt) > resultCacheTTL {
+		delete(c.entries, key)
+		return nil, false
+	}
+	entry.lastUsed = time.Now()
+	return entry, true
+}
+
+// Put stores a result for its owner, replacing the owner's earlier result of the same query and evicting
+// the least recently used entry when full
+func (c *ResultCache) Put(owner, connection, query string, args []interface{}, columns []string, rows []map[string]interface{}) *CachedResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	hash := resultKey(connection, query, args)
+	for key, entry := range c.entries {
+		if now.Sub(entry.CreatedAt) > resultCacheTTL || entry.hash == hash && entry.Owner == owner {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= resultCacheMaxLen {
+		var oldest string
+		for key, entry := range c.entries {
+			if oldest == "" || entry.lastUsed.Before(c.entries[oldest].lastUsed) {
+				oldest = key
+			}
+		}
+		delete(c.entries, oldest)
+	}
+
+	entry := &CachedResult{
+		Key:        rand.Text(),
+		Owner:      owner,
+		hash:       hash,
+		Connection: connection,
+		Query:      query,
+		Args:       args,
+		Columns:    columns,
+		Rows:       rows,
+		CreatedAt:  now,
+		lastUsed:   now,
+	}
+	c.entries[entry.Key] = entry
+	return entry
+}
+
+// Clear drops the cached results of a connection, or all of them when connection is empty, and
+// returns how many were dropped
+func (c *ResultCache) Clear(connection string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cleared := 0
+	for key, entry := range c.entries {
+		if connection == "" || entry.Connection == connection {
+			delete(c.entries, key)
+			cleared++
+		}
+	}
+	return cleared
+}
+
+// Page returns rows [offset, offset+limit) of the cached result with a cursor for the next page
+func (r *CachedResult) Page(offset, limit int) *Page {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(r.Rows) {
+		offset = len(r.Rows)
+	}
+
+	end := offset + limit
+	if end > len(r.Rows) {
+		end = len(r.Rows)
+	}
+
+	page := &Page{
+		Connection: r.Connection,
+		Columns:    r.Columns,
+		Rows:       r.Rows[offset:end],
+		Offset:     offset,
+		Returned:   end - offset,
+		TotalRows:  len(r.Rows),
+		HasMore:    end < len(r.Rows),
+		CachedAt:   r.CreatedAt,
+	}
+	if page.Rows == nil {
+		page.Rows = []map[string]interface{}{}
+	}
+	if page.HasMore {
+		page.NextCursor = encodeCursor(r.Key, end)
+	}
+	return page
+}
+
+// Resolve looks up the cached result and offset a cursor points at; a cursor only works for the owner of
+// its result
+func (c *ResultCache) Resolve(owner, cursor string) (*CachedResult, int, error) {
+	key, offset, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, 0, err
+	}
+	entry, ok := c.getByKey(key)
+	if !ok {
+		return nil, 0, fmt.Errorf("cursor has expired; re-run database_query to start over")
+	}
+	if entry.Owner != owner {
+		return nil, 0, fmt.Errorf("cursor was issued to another caller; run database_query yourself")
+	}
+	return entry, offset, nil
+}
+
+// encodeCursor builds an opaque cursor from a cache key and row offset
+func encodeCursor(key string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + ":" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (string, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid cursor")
+	}
+	key, offsetText, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(offsetText)
+	if err != nil || offset < 0 {
+		return "", 0, fmt.Errorf("invalid cursor")
+	}
+	return key, offset, nil
+}
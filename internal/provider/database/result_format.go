@@ -0,0 +1,175 @@
+package database
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Result formats of database_query
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// markdownNull stands for NULL in Markdown tables, where an empty cell would read as an empty string
+const markdownNull = "NULL"
+
+// orderedRow marshals the values of a row in column order, which a map would sort by name
+type orderedRow struct {
+	columns []string
+	values  map[string]interface{}
+}
+
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[column])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON writes the rows with their columns in SELECT order
+func (p *Page) MarshalJSON() ([]byte, error) {
+	rows := make([]orderedRow, len(p.Rows))
+	for i, row := range p.Rows {
+		rows[i] = orderedRow{columns: p.Columns, values: row}
+	}
+	return json.Marshal(struct {
+		Connection string       `json:"connection"`
+		Columns    []string     `json:"columns"`
+		Rows       []orderedRow `json:"rows"`
+		Offset     int          `json:"offset"`
+		Returned   int          `json:"returned"`
+		TotalRows  int          `json:"total_rows"`
+		HasMore    bool         `json:"has_more"`
+		NextCursor string       `json:"next_cursor,omitempty"`
+		CachedAt   time.Time    `json:"cached_at"`
+	}{p.Connection, p.Columns, rows, p.Offset, p.Returned, p.TotalRows, p.HasMore, p.NextCursor, p.CachedAt})
+}
+
+// validFormat checks a format argument, returning the default for an empty one
+func validFormat(format string) (string, error) {
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case "":
+		return FormatJSON, nil
+	case FormatJSON, FormatCSV, FormatMarkdown:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported format %q (use json, csv or markdown)", format)
+}
+
+// truncateCells returns a copy of a page whose string values are cut to maxChars characters;
+// maxChars <= 0 leaves the page as it is
+func truncateCells(page *Page, maxChars int) (*Page, int) {
+	if maxChars <= 0 {
+		return page, 0
+	}
+	truncated := 0
+	cut := *page
+	cut.Rows = make([]map[string]interface{}, len(page.Rows))
+	for i, row := range page.Rows {
+		copied := make(map[string]interface{}, len(row))
+		for column, value := range row {
+			if s, ok := value.(string); ok && utf8.RuneCountInString(s) > maxChars {
+				value = string([]rune(s)[:maxChars]) + "…"
+				truncated++
+			}
+			copied[column] = value
+		}
+		cut.Rows[i] = copied
+	}
+	return &cut, truncated
+}
+
+// renderCSV writes a page as CSV with a header row; NULL becomes an empty field
+func renderCSV(page *Page) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(page.Columns); err != nil {
+		return "", err
+	}
+	record := make([]string, len(page.Columns))
+	for _, row := range page.Rows {
+		for i, column := range page.Columns {
+			record[i] = cellText(row[column], "")
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// renderMarkdown writes a page as a GitHub-flavored Markdown table
+func renderMarkdown(page *Page) string {
+	var b strings.Builder
+	escape := strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+	b.WriteString("|")
+	for _, column := range page.Columns {
+		b.WriteString(" " + escape.Replace(column) + " |")
+	}
+	b.WriteString("\n|")
+	for range page.Columns {
+		b.WriteString(" --- |")
+	}
+	for _, row := range page.Rows {
+		b.WriteString("\n|")
+		for _, column := range page.Columns {
+			b.WriteString(" " + escape.Replace(cellText(row[column], markdownNull)) + " |")
+		}
+	}
+	return b.String()
+}
+
+// cellText renders a value for a text table
+func cellText(value interface{}, null string) string {
+	switch v := value.(type) {
+	case nil:
+		return null
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// pageSummary describes which rows of the result a text table shows and how to read more
+func pageSummary(page *Page, truncated, maxChars int) string {
+	summary := fmt.Sprintf("Rows %d-%d of %d from %s", page.Offset+1, page.Offset+page.Returned, page.TotalRows, page.Connection)
+	if page.Returned == 0 {
+		summary = fmt.Sprintf("No rows at offset %d of %d from %s", page.Offset, page.TotalRows, page.Connection)
+	}
+	if truncated > 0 {
+		summary += fmt.Sprintf("; %d value(s) cut to %d characters", truncated, maxChars)
+	}
+	if page.HasMore {
+		summary += fmt.Sprintf(". More rows: database_query_next_page with cursor %s", page.NextCursor)
+	}
+	return summary
+}
@@ -0,0 +1,276 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// TableInfo describes a table or view in a schema
+type TableInfo struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	EstimatedRows int64  `json:"estimated_rows"`
+}
+
+// ColumnInfo describes a single table column
+type ColumnInfo struct {
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Nullable   bool    `json:"nullable"`
+	Default    *string `json:"default,omitempty"`
+	PrimaryKey bool    `json:"primary_key,omitempty"`
+}
+
+// ForeignKey describes a foreign key constraint; columns and referenced columns are paired by position
+type ForeignKey struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+}
+
+// UniqueKey describes a unique constraint
+type UniqueKey struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+// IndexInfo describes an index and its columns in index order
+type IndexInfo struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+	Method  string   `json:"method"`
+}
+
+// TableSchema is the full description of a table
+type TableSchema struct {
+	Schema      string       `json:"schema,omitempty"`
+	Table       string       `json:"table"`
+	Columns     []ColumnInfo `json:"columns"`
+	PrimaryKey  []string     `json:"primary_key"`
+	UniqueKeys  []UniqueKey  `json:"unique_keys,omitempty"`
+	ForeignKeys []ForeignKey `json:"foreign_keys,omitempty"`
+	Indexes     []IndexInfo  `json:"indexes,omitempty"`
+}
+
+// ListTables returns the tables and views of a schema (empty for the current schema)
+func (c *DatabaseClient) ListTables(ctx context.Context, schema string) ([]TableInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, c.dialect.ListTablesQuery(), schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []TableInfo{}
+	for rows.Next() {
+		var table TableInfo
+		if err := rows.Scan(&table.Name, &table.Type, &table.EstimatedRows); err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// GetColumns returns the column definitions of a table
+func (c *DatabaseClient) GetColumns(ctx context.Context, schema, table string) ([]ColumnInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, c.dialect.ListColumnsQuery(), schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		var nullable string
+		var def sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &def); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		col.Nullable = strings.EqualFold(nullable, "YES")
+		if def.Valid {
+			col.Default = &def.String
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table not found: %s", table)
+	}
+	return columns, nil
+}
+
+// ListIndexes returns the indexes of a table
+func (c *DatabaseClient) ListIndexes(ctx context.Context, schema, table string) ([]IndexInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, c.dialect.ListIndexesQuery(), schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	indexes := []IndexInfo{}
+	byName := map[string]int{}
+	for rows.Next() {
+		var name, column, method string
+		var nonUnique, primary int
+		if err := rows.Scan(&name, &column, &nonUnique, &primary, &method); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		i, ok := byName[name]
+		if !ok {
+			indexes = append(indexes, IndexInfo{
+				Name:    name,
+				Unique:  nonUnique == 0,
+				Primary: primary == 1,
+				Method:  strings.ToLower(method),
+			})
+			i = len(indexes) - 1
+			byName[name] = i
+		}
+		if column != "" {
+			indexes[i].Columns = append(indexes[i].Columns, column)
+		}
+	}
+	return indexes, rows.Err()
+}
+
+// DescribeTable returns the columns, keys, foreign keys and indexes of a table
+func (c *DatabaseClient) DescribeTable(ctx context.Context, schema, table string) (*TableSchema, error) {
+	// One timeout covers all the lookups
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
+	columns, err := c.GetColumns(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TableSchema{
+		Schema:     schema,
+		Table:      table,
+		Columns:    columns,
+		PrimaryKey: []string{},
+	}
+	if err := c.loadConstraints(ctx, schema, table, result); err != nil {
+		return nil, err
+	}
+
+	primary := map[string]bool{}
+	for _, name := range result.PrimaryKey {
+		primary[name] = true
+	}
+	for i := range result.Columns {
+		result.Columns[i].PrimaryKey = primary[result.Columns[i].Name]
+	}
+
+	indexes, err := c.ListIndexes(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	result.Indexes = indexes
+	return result, nil
+}
+
+// loadConstraints fills in the primary key, unique keys and foreign keys of a table
+func (c *DatabaseClient) loadConstraints(ctx context.Context, schema, table string, result *TableSchema) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, c.dialect.ListConstraintsQuery(), schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to list constraints of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	uniques := map[string]int{}
+	foreignKeys := map[string]int{}
+	for rows.Next() {
+		var name, kind, column, refTable, refColumn string
+		if err := rows.Scan(&name, &kind, &column, &refTable, &refColumn); err != nil {
+			return fmt.Errorf("failed to scan constraint: %w", err)
+		}
+
+		switch strings.ToUpper(kind) {
+		case "PRIMARY KEY":
+			result.PrimaryKey = append(result.PrimaryKey, column)
+		case "UNIQUE":
+			i, ok := uniques[name]
+			if !ok {
+				result.UniqueKeys = append(result.UniqueKeys, UniqueKey{Name: name})
+				i = len(result.UniqueKeys) - 1
+				uniques[name] = i
+			}
+			result.UniqueKeys[i].Columns = append(result.UniqueKeys[i].Columns, column)
+		case "FOREIGN KEY":
+			i, ok := foreignKeys[name]
+			if !ok {
+				result.ForeignKeys = append(result.ForeignKeys, ForeignKey{Name: name, ReferencedTable: refTable})
+				i = len(result.ForeignKeys) - 1
+				foreignKeys[name] = i
+			}
+			result.ForeignKeys[i].Columns = append(result.ForeignKeys[i].Columns, column)
+			result.ForeignKeys[i].ReferencedColumns = append(result.ForeignKeys[i].ReferencedColumns, refColumn)
+		}
+	}
+	return rows.Err()
+}
+
+// CountRows counts the rows of a table exactly; ctx bounds the scan, which can be slow on large tables
+func (c *DatabaseClient) CountRows(ctx context.Context, schema, table string) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.db == nil {
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	name := c.dialect.QuoteIdentifier(table)
+	if schema != "" {
+		name = c.dialect.QuoteIdentifier(schema) + "." + name
+	}
+	var count int64
+	if err := c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+name).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rows of %s: %w", table, err)
+	}
+	return count, nil
+}
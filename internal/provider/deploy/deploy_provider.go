@@ -0,0 +1,221 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/loki"
+)
+
+// DeployProvider verifies releases against Sentry, Loki and the services' health endpoints
+type DeployProvider struct {
+	*provider.BaseProvider
+	verifier *Verifier
+	catalog  Catalog
+}
+
+// NewDeployProvider creates a new deploy provider with config; catalog, issues and logs may be nil
+// when their providers are not running
+func NewDeployProvider(cfg *config.DeployConfig, catalog Catalog, issues IssueSource, logs LogSource) *DeployProvider {
+	p := &DeployProvider{
+		BaseProvider: provider.NewBaseProvider("deploy"),
+		verifier:     NewVerifier(cfg, issues, logs),
+		catalog:      catalog,
+	}
+
+	var sources []string
+	if catalog != nil {
+		sources = append(sources, "catalog")
+	}
+	if issues != nil {
+		sources = append(sources, "sentry")
+	}
+	if logs != nil {
+		sources = append(sources, "loki")
+	}
+	if len(sources) == 0 {
+		p.SetStatus(false, "Needs the catalog, sentry or loki provider", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Deploy provider initialized successfully (%s)", strings.Join(sources, ", "))
+
+	return p
+}
+
+// Test tests the deploy configuration (for ProviderClient interface compatibility)
+func (p *DeployProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("deploy provider not available")
+	}
+	return nil
+}
+
+// AddTools adds deploy tools to the MCP server (for ProviderClient interface compatibility)
+func (p *DeployProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the deploy provider
+func (p *DeployProvider) Close() error {
+	return nil
+}
+
+// addToolsToServer adds deploy tools to the MCP server
+func (p *DeployProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Deploy provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createVerifyTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered deploy tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All deploy tools registered successfully")
+}
+
+// createVerifyTool creates the composite go/no-go tool
+func (p *DeployProvider) createVerifyTool() entity.ToolDefinition {
+	window := p.verifier.Window()
+	tool := &mcp.Tool{
+		Name: "verify_deploy",
+		Description: fmt.Sprintf("Decide whether a release of a service is healthy: checks Sentry for unresolved issues first seen in the release, "+
+			"compares the Loki error log rate in the %s before the deploy with the %s after it, and requests the service's health endpoints. "+
+			"The Sentry projects, Loki labels and health endpoints come from the service catalog unless given. "+
+			"Returns each check and a verdict: go, no-go (a check failed) or inconclusive (a check could not run)", window, window),
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"service": {
+					"type": "string",
+					"description": "Service name as registered in the service catalog"
+				},
+				"release": {
+					"type": "string",
+					"description": "Release version as reported to Sentry (e.g. 1.42.0 or a commit SHA)"
+				},
+				"deployed_at": {
+					"type": "string",
+					"description": "When the release was deployed: RFC3339, Unix seconds or relative like 20m (ago). Default: one window ago"
+				},
+				"sentry_projects": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Sentry project slugs (overrides the catalog)"
+				},
+				"loki_selector": {
+					"type": "string",
+					"description": "LogQL stream selector of the service, e.g. {app=\"checkout\"} (overrides the catalog)"
+				},
+				"health_checks": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Health endpoint URLs expected to answer 2xx (overrides the catalog)"
+				}
+			},
+			"required": ["service", "release"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Service        string   `json:"service"`
+			Release        string   `json:"release"`
+			DeployedAt     string   `json:"deployed_at,omitempty"`
+			SentryProjects []string `json:"sentry_projects,omitempty"`
+			LokiSelector   string   `json:"loki_selector,omitempty"`
+			HealthChecks   []string `json:"health_checks,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Service == "" {
+			return p.createErrorResult(fmt.Errorf("service parameter is required")), nil
+		}
+		if args.Release == "" {
+			return p.createErrorResult(fmt.Errorf("release parameter is required")), nil
+		}
+
+		now := time.Now()
+		target := Target{
+			Service:        args.Service,
+			Release:        args.Release,
+			DeployedAt:     now.Add(-window),
+			SentryProjects: args.SentryProjects,
+			LokiSelector:   args.LokiSelector,
+			HealthChecks:   args.HealthChecks,
+		}
+		if args.DeployedAt != "" {
+			deployedAt, err := loki.ParseTime(args.DeployedAt, now)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid deployed_at: %w", err)), nil
+			}
+			if deployedAt.After(now) {
+				return p.createErrorResult(fmt.Errorf("deployed_at is in the future")), nil
+			}
+			target.DeployedAt = deployedAt
+		}
+
+		// Fill what was not given from the catalog
+		var notes []string
+		if p.catalog != nil {
+			if svc, ok := p.catalog.Get(args.Service); ok {
+				if len(target.SentryProjects) == 0 {
+					target.SentryProjects = svc.SentryProjects
+				}
+				if target.LokiSelector == "" {
+					target.LokiSelector = svc.LokiSelector()
+				}
+				if len(target.HealthChecks) == 0 {
+					target.HealthChecks = svc.HealthChecks
+				}
+			} else {
+				notes = append(notes, fmt.Sprintf("service %q is not in the catalog; checks are not scoped to it unless given", args.Service))
+			}
+		}
+
+		report := p.verifier.Verify(ctx, target)
+		report.Notes = notes
+		return p.formatJSONResult(report), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *DeployProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Deploy Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *DeployProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that DeployProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*DeployProvider)(nil)
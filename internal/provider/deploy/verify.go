@@ -0,0 +1,354 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/catalog"
+	"dev-mcp/internal/provider/loki"
+	"dev-mcp/internal/provider/sentry"
+)
+
+const (
+	defaultMaxErrorRateIncrease = 0.5
+	defaultErrorRateFloor       = 0.05
+	defaultWindow               = 30 * time.Minute
+	defaultHealthTimeout        = 10 * time.Second
+	// minAfterWindow is the shortest window after a deploy worth comparing
+	minAfterWindow = time.Minute
+	// maxListedIssues bounds the new issues listed in a report
+	maxListedIssues = 10
+)
+
+// Check status values
+const (
+	StatusPass    = "pass"
+	StatusFail    = "fail"
+	StatusSkipped = "skipped" // The check could not run: no source configured or nothing to check
+	StatusError   = "error"   // The source failed to answer
+)
+
+// Catalog looks up services in the service catalog
+type Catalog interface {
+	Get(name string) (*catalog.Service, bool)
+}
+
+// IssueSource searches Sentry issues
+type IssueSource interface {
+	FetchIssues(ctx context.Context, query string, minutesBack int) ([]sentry.Issue, error)
+}
+
+// LogSource runs LogQL queries
+type LogSource interface {
+	Query(params loki.QueryParams) (*loki.QueryResponse, error)
+}
+
+// Target is the service and release being verified, with where to look
+type Target struct {
+	Service        string
+	Release        string
+	DeployedAt     time.Time
+	SentryProjects []string
+	LokiSelector   string
+	HealthChecks   []string
+}
+
+// Check is the outcome of one verification
+type Check struct {
+	Name   string      `json:"name"`
+	Status string      `json:"status"`
+	Detail string      `json:"detail"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// Report is the go/no-go summary of a deploy
+type Report struct {
+	Service    string    `json:"service"`
+	Release    string    `json:"release"`
+	DeployedAt time.Time `json:"deployed_at"`
+	Verdict    string    `json:"verdict"` // go, no-go or inconclusive
+	Summary    string    `json:"summary"`
+	Checks     []Check   `json:"checks"`
+	Notes      []string  `json:"notes,omitempty"`
+}
+
+// Verifier runs the deploy checks against the configured sources; each source may be nil
+type Verifier struct {
+	issues               IssueSource
+	logs                 LogSource
+	http                 *resty.Client
+	maxNewIssues         int
+	maxErrorRateIncrease float64
+	errorRateFloor       float64
+	window               time.Duration
+}
+
+// NewVerifier creates a verifier with the thresholds of cfg
+func NewVerifier(cfg *config.DeployConfig, issues IssueSource, logs LogSource) *Verifier {
+	v := &Verifier{
+		issues:               issues,
+		logs:                 logs,
+		maxErrorRateIncrease: defaultMaxErrorRateIncrease,
+		errorRateFloor:       defaultErrorRateFloor,
+		window:               defaultWindow,
+	}
+	timeout := defaultHealthTimeout
+	if cfg != nil {
+		v.maxNewIssues = cfg.MaxNewIssues
+		if cfg.MaxErrorRateIncrease > 0 {
+			v.maxErrorRateIncrease = cfg.MaxErrorRateIncrease
+		}
+		if cfg.ErrorRateFloor > 0 {
+			v.errorRateFloor = cfg.ErrorRateFloor
+		}
+		if cfg.WindowMinutes > 0 {
+			v.window = time.Duration(cfg.WindowMinutes) * time.Minute
+		}
+		if cfg.HealthTimeoutSeconds > 0 {
+			timeout = time.Duration(cfg.HealthTimeoutSeconds) * time.Second
+		}
+	}
+	v.http = resty.New().
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(timeout)
+	return v
+}
+
+// Window returns the length of the windows compared before and after a deploy
+func (v *Verifier) Window() time.Duration {
+	return v.window
+}
+
+// Verify runs every check and derives the verdict: no-go when a check fails, inconclusive when a
+// check could not get an answer, else go
+func (v *Verifier) Verify(ctx context.Context, target Target) *Report {
+	report := &Report{Service: target.Service, Release: target.Release, DeployedAt: target.DeployedAt}
+	report.Checks = append(report.Checks,
+		v.checkSentry(ctx, target),
+		v.checkErrorRate(target),
+	)
+	report.Checks = append(report.Checks, v.checkHealth(ctx, target)...)
+
+	var failed, errored, passed []string
+	for _, check := range report.Checks {
+		switch check.Status {
+		case StatusFail:
+			failed = append(failed, check.Name)
+		case StatusError:
+			errored = append(errored, check.Name)
+		case StatusPass:
+			passed = append(passed, check.Name)
+		}
+	}
+	switch {
+	case len(failed) > 0:
+		report.Verdict = "no-go"
+		report.Summary = "failed: " + strings.Join(failed, ", ")
+	case len(errored) > 0:
+		report.Verdict = "inconclusive"
+		report.Summary = "could not check: " + strings.Join(errored, ", ")
+	case len(passed) == 0:
+		report.Verdict = "inconclusive"
+		report.Summary = "no check could run; configure sentry, loki or health checks for the service"
+	default:
+		report.Verdict = "go"
+		report.Summary = fmt.Sprintf("%d checks passed", len(passed))
+	}
+	return report
+}
+
+// checkSentry looks for issues first seen in the release
+func (v *Verifier) checkSentry(ctx context.Context, target Target) Check {
+	check := Check{Name: "sentry_new_issues"}
+	if v.issues == nil {
+		check.Status, check.Detail = StatusSkipped, "the sentry provider is not running"
+		return check
+	}
+
+	query := fmt.Sprintf("firstRelease:%q is:unresolved", target.Release)
+	if len(target.SentryProjects) > 0 {
+		query += fmt.Sprintf(" project:[%s]", strings.Join(target.SentryProjects, ","))
+	}
+	issues, err := v.issues.FetchIssues(ctx, query, 0)
+	if err != nil {
+		check.Status, check.Detail = StatusError, err.Error()
+		return check
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return count(issues[i]) > count(issues[j]) })
+	type issue struct {
+		ID        string    `json:"id"`
+		ShortID   string    `json:"short_id"`
+		Title     string    `json:"title"`
+		Level     string    `json:"level"`
+		Events    string    `json:"events"`
+		Users     int       `json:"users"`
+		FirstSeen time.Time `json:"first_seen"`
+	}
+	listed := make([]issue, 0, min(len(issues), maxListedIssues))
+	for _, i := range issues[:min(len(issues), maxListedIssues)] {
+		listed = append(listed, issue{ID: i.ID, ShortID: i.ShortID, Title: i.Title, Level: i.Level, Events: i.Count, Users: i.UserCount, FirstSeen: i.FirstSeen})
+	}
+	check.Data = map[string]interface{}{"query": query, "new_issues": len(issues), "issues": listed}
+
+	if len(issues) > v.maxNewIssues {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%d new unresolved issues first seen in %s (at most %d tolerated)", len(issues), target.Release, v.maxNewIssues)
+	} else {
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("%d new unresolved issues first seen in %s", len(issues), target.Release)
+	}
+	return check
+}
+
+// count returns an issue's event count
+func count(issue sentry.Issue) int64 {
+	n, _ := strconv.ParseInt(issue.Count, 10, 64)
+	return n
+}
+
+// errorRateQuery returns the error_rate preset, scoped to the service's streams when a selector is known
+func errorRateQuery(selector string, window time.Duration) (string, error) {
+	duration := strconv.Itoa(int(window.Minutes())) + "m"
+	if selector == "" {
+		return loki.BuildPresetQuery("error_rate", map[string]string{"window": duration})
+	}
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(selector), "{"), "}"))
+	if inner == "" {
+		return "", fmt.Errorf("invalid Loki selector %q", selector)
+	}
+	return fmt.Sprintf(`sum(rate({%s, level="error"}[%s]))`, inner, duration), nil
+}
+
+// checkErrorRate compares the error log rate in the window before the deploy with the window after it
+func (v *Verifier) checkErrorRate(target Target) Check {
+	check := Check{Name: "loki_error_rate"}
+	if v.logs == nil {
+		check.Status, check.Detail = StatusSkipped, "the loki provider is not running"
+		return check
+	}
+
+	now := time.Now()
+	after := min(v.window, now.Sub(target.DeployedAt)).Truncate(time.Minute)
+	if after < minAfterWindow {
+		check.Status, check.Detail = StatusSkipped, "the deploy is less than a minute old; verify again later"
+		return check
+	}
+
+	beforeQuery, err := errorRateQuery(target.LokiSelector, v.window)
+	if err != nil {
+		check.Status, check.Detail = StatusError, err.Error()
+		return check
+	}
+	afterQuery, _ := errorRateQuery(target.LokiSelector, after)
+
+	before, err := v.rate(beforeQuery, target.DeployedAt)
+	if err != nil {
+		check.Status, check.Detail = StatusError, fmt.Sprintf("before the deploy: %v", err)
+		return check
+	}
+	current, err := v.rate(afterQuery, target.DeployedAt.Add(after))
+	if err != nil {
+		check.Status, check.Detail = StatusError, fmt.Sprintf("after the deploy: %v", err)
+		return check
+	}
+
+	data := map[string]interface{}{
+		"before_query":      beforeQuery,
+		"after_query":       afterQuery,
+		"before_per_second": round(before),
+		"after_per_second":  round(current),
+		"after_window":      after.String(),
+		"max_increase":      v.maxErrorRateIncrease,
+		"floor_per_second":  v.errorRateFloor,
+		"scoped_to_service": target.LokiSelector != "",
+	}
+	if target.LokiSelector == "" {
+		data["note"] = "no Loki labels for the service: the rate covers every stream with level=error"
+	}
+	if before > 0 {
+		data["change"] = round(current/before - 1)
+	}
+	check.Data = data
+
+	switch {
+	case current <= v.errorRateFloor:
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("error rate %.3f/s is under the %.3f/s floor", current, v.errorRateFloor)
+	case current > before*(1+v.maxErrorRateIncrease):
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("error rate rose from %.3f/s to %.3f/s (more than %.0f%%)", before, current, v.maxErrorRateIncrease*100)
+	default:
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("error rate %.3f/s after the deploy, %.3f/s before", current, before)
+	}
+	return check
+}
+
+// rate runs an instant metric query at a time and returns its single value; no series means zero
+func (v *Verifier) rate(query string, at time.Time) (float64, error) {
+	resp, err := v.logs.Query(loki.QueryParams{Query: query, End: at})
+	if err != nil {
+		return 0, err
+	}
+	if resp.Data.ResultType != "vector" {
+		return 0, fmt.Errorf("expected a vector result, got %q", resp.Data.ResultType)
+	}
+	var samples []struct {
+		Value [2]interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(resp.Data.Result, &samples); err != nil {
+		return 0, fmt.Errorf("invalid vector result: %w", err)
+	}
+	total := 0.0
+	for _, sample := range samples {
+		text, _ := sample.Value[1].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid sample value %v", sample.Value[1])
+		}
+		total += value
+	}
+	return total, nil
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+// checkHealth requests each health endpoint and expects a 2xx answer
+func (v *Verifier) checkHealth(ctx context.Context, target Target) []Check {
+	if len(target.HealthChecks) == 0 {
+		return []Check{{Name: "health", Status: StatusSkipped, Detail: "no health checks configured for the service (health_checks in the catalog)"}}
+	}
+
+	checks := make([]Check, 0, len(target.HealthChecks))
+	for _, url := range target.HealthChecks {
+		check := Check{Name: "health " + url}
+		start := time.Now()
+		resp, err := v.http.R().SetContext(ctx).Get(url)
+		elapsed := time.Since(start)
+		if err != nil {
+			check.Status, check.Detail = StatusFail, fmt.Sprintf("request failed: %v", err)
+			checks = append(checks, check)
+			continue
+		}
+		check.Data = map[string]interface{}{"status_code": resp.StatusCode(), "duration_ms": elapsed.Milliseconds()}
+		if resp.IsSuccess() {
+			check.Status, check.Detail = StatusPass, fmt.Sprintf("%s in %dms", resp.Status(), elapsed.Milliseconds())
+		} else {
+			check.Status, check.Detail = StatusFail, resp.Status()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,219 @@
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// startTime approximates the process start for uptime
+var startTime = time.Now()
+
+// RuntimeInfo describes the Go runtime and build of the running server
+type RuntimeInfo struct {
+	GoVersion  string `json:"go_version"`
+	Platform   string `json:"platform"`
+	PID        int    `json:"pid"`
+	Uptime     string `json:"uptime"`
+	StartedAt  string `json:"started_at"`
+	NumCPU     int    `json:"num_cpu"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	GOGC       string `json:"gogc"`
+	MemLimit   string `json:"memory_limit"`
+	Module     string `json:"module,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Revision   string `json:"vcs_revision,omitempty"`
+	Modified   bool   `json:"vcs_modified,omitempty"`
+	OpenFiles  int    `json:"open_files,omitempty"` // Linux only
+}
+
+// HeapStats is a readable subset of runtime.MemStats
+type HeapStats struct {
+	HeapAlloc     string  `json:"heap_alloc"`
+	HeapInuse     string  `json:"heap_inuse"`
+	HeapIdle      string  `json:"heap_idle"`
+	HeapReleased  string  `json:"heap_released"`
+	HeapObjects   uint64  `json:"heap_objects"`
+	StackInuse    string  `json:"stack_inuse"`
+	Sys           string  `json:"sys"`
+	TotalAlloc    string  `json:"total_alloc"`
+	Mallocs       uint64  `json:"mallocs"`
+	Frees         uint64  `json:"frees"`
+	NextGC        string  `json:"next_gc"`
+	NumGC         uint32  `json:"num_gc"`
+	NumForcedGC   uint32  `json:"num_forced_gc"`
+	GCCPUFraction float64 `json:"gc_cpu_percent"`
+	PauseTotal    string  `json:"gc_pause_total"`
+	LastGC        string  `json:"last_gc,omitempty"`
+}
+
+// GCPause is one recent stop-the-world pause
+type GCPause struct {
+	At       string `json:"at"`
+	Duration string `json:"duration"`
+}
+
+// GoroutineGroup is a set of goroutines with the same stack
+type GoroutineGroup struct {
+	Count int      `json:"count"`
+	Top   string   `json:"top"`
+	Stack []string `json:"stack"`
+}
+
+// ReadRuntimeInfo collects runtime, build and process information
+func ReadRuntimeInfo() RuntimeInfo {
+	info := RuntimeInfo{
+		GoVersion:  runtime.Version(),
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		PID:        os.Getpid(),
+		Uptime:     time.Since(startTime).Round(time.Second).String(),
+		StartedAt:  startTime.UTC().Format(time.RFC3339),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		GOGC:       "100",
+		MemLimit:   "none",
+	}
+	if gogc := os.Getenv("GOGC"); gogc != "" {
+		info.GOGC = gogc
+	}
+	// A negative limit reads the current value without changing it
+	if limit := debug.SetMemoryLimit(-1); limit != 1<<63-1 {
+		info.MemLimit = formatBytes(uint64(limit))
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.Module = build.Main.Path
+		info.Version = build.Main.Version
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+		info.OpenFiles = len(entries)
+	}
+	return info
+}
+
+// ReadHeapStats reads memory statistics and up to pauses recent GC pauses, newest first
+func ReadHeapStats(pauses int) (HeapStats, []GCPause) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	stats := HeapStats{
+		HeapAlloc:     formatBytes(m.HeapAlloc),
+		HeapInuse:     formatBytes(m.HeapInuse),
+		HeapIdle:      formatBytes(m.HeapIdle),
+		HeapReleased:  formatBytes(m.HeapReleased),
+		HeapObjects:   m.HeapObjects,
+		StackInuse:    formatBytes(m.StackInuse),
+		Sys:           formatBytes(m.Sys),
+		TotalAlloc:    formatBytes(m.TotalAlloc),
+		Mallocs:       m.Mallocs,
+		Frees:         m.Frees,
+		NextGC:        formatBytes(m.NextGC),
+		NumGC:         m.NumGC,
+		NumForcedGC:   m.NumForcedGC,
+		GCCPUFraction: float64(int(m.GCCPUFraction*10000)) / 100,
+		PauseTotal:    time.Duration(m.PauseTotalNs).String(),
+	}
+	if m.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(m.LastGC)).UTC().Format(time.RFC3339Nano)
+	}
+
+	// PauseNs and PauseEnd are circular buffers; the latest pause is at (NumGC+255)%256
+	var recent []GCPause
+	for i := 0; i < pauses && i < int(m.NumGC) && i < len(m.PauseNs); i++ {
+		idx := (int(m.NumGC) - 1 - i + len(m.PauseNs)) % len(m.PauseNs)
+		recent = append(recent, GCPause{
+			At:       time.Unix(0, int64(m.PauseEnd[idx])).UTC().Format(time.RFC3339Nano),
+			Duration: time.Duration(m.PauseNs[idx]).String(),
+		})
+	}
+	return stats, recent
+}
+
+var goroutineHeaderPattern = regexp.MustCompile(`^(\d+) @`)
+
+// GoroutineGroups groups goroutines by identical stack, largest groups first, with at most depth frames each
+func GoroutineGroups(limit, depth int) ([]GoroutineGroup, error) {
+	var buf bytes.Buffer
+	// debug=1 aggregates goroutines with the same stack: "N @ pc..." followed by "#\tpc\tfunc+off\tfile:line" lines
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil, err
+	}
+
+	var groups []GoroutineGroup
+	var current *GoroutineGroup
+	finish := func() {
+		if current != nil {
+			groups = append(groups, *current)
+		}
+	}
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := goroutineHeaderPattern.FindStringSubmatch(line); m != nil {
+			finish()
+			count, _ := strconv.Atoi(m[1])
+			current = &GoroutineGroup{Count: count}
+			continue
+		}
+		if current == nil || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "#"))
+		if len(fields) < 2 {
+			continue
+		}
+		function := fields[1]
+		if i := strings.LastIndex(function, "+0x"); i > 0 {
+			function = function[:i]
+		}
+		frame := function
+		if len(fields) > 2 {
+			frame += " " + fields[2]
+		}
+		if current.Top == "" {
+			current.Top = function
+		}
+		if len(current.Stack) < depth {
+			current.Stack = append(current.Stack, frame)
+		}
+	}
+	finish()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Count > groups[j].Count })
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+	return groups, nil
+}
+
+func formatBytes(value uint64) string {
+	const unit = 1024
+	if value < unit {
+		return strconv.FormatUint(value, 10) + "B"
+	}
+	div, exp := uint64(unit), 0
+	for n := value / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(value)/float64(div), 'f', 2, 64) + string("KMGTPE"[exp]) + "iB"
+}
@@ -0,0 +1,167 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"runtime"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/provider"
+)
+
+// DiagnosticsProvider reports the server's own runtime state for debugging dev-mcp itself
+type DiagnosticsProvider struct {
+	*provider.BaseProvider
+}
+
+// NewDiagnosticsProvider creates a new diagnostics provider; it needs no configuration
+func NewDiagnosticsProvider() *DiagnosticsProvider {
+	p := &DiagnosticsProvider{
+		BaseProvider: provider.NewBaseProvider("diagnostics"),
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Diagnostics provider initialized successfully")
+
+	return p
+}
+
+// Test tests the diagnostics provider (for ProviderClient interface compatibility)
+func (p *DiagnosticsProvider) Test(config interface{}) error {
+	return nil
+}
+
+// AddTools adds diagnostics tools to the MCP server (for ProviderClient interface compatibility)
+func (p *DiagnosticsProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the diagnostics provider
+func (p *DiagnosticsProvider) Close() error {
+	return nil
+}
+
+// addToolsToServer adds diagnostics tools to the MCP server
+func (p *DiagnosticsProvider) addToolsToServer(server *mcp.Server) {
+	tools := []entity.ToolDefinition{
+		p.createDebugSelfTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered diagnostics tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All diagnostics tools registered successfully")
+}
+
+// createDebugSelfTool creates the server self-diagnostics tool
+func (p *DiagnosticsProvider) createDebugSelfTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "debug_self",
+		Description: "Runtime diagnostics of this MCP server: uptime, build, goroutine count and the largest goroutine groups by stack, heap statistics and recent GC pauses. Use when dev-mcp itself is slow, leaking memory or goroutines",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"goroutines": {
+					"type": "integer",
+					"description": "Number of goroutine groups (goroutines sharing a stack) to list, largest first; 0 lists none (default: 10)"
+				},
+				"stack_depth": {
+					"type": "integer",
+					"description": "Frames shown per goroutine group (default: 8)"
+				},
+				"gc_pauses": {
+					"type": "integer",
+					"description": "Number of recent GC pauses to list, newest first (default: 10, max: 256)"
+				},
+				"run_gc": {
+					"type": "boolean",
+					"description": "Run a garbage collection before reading heap statistics, to see live memory rather than garbage (default: false)"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Goroutines *int `json:"goroutines,omitempty"`
+			StackDepth int  `json:"stack_depth,omitempty"`
+			GCPauses   int  `json:"gc_pauses,omitempty"`
+			RunGC      bool `json:"run_gc,omitempty"`
+		}{}
+
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		groupLimit := 10
+		if args.Goroutines != nil {
+			groupLimit = *args.Goroutines
+		}
+		if args.StackDepth <= 0 {
+			args.StackDepth = 8
+		}
+		if args.GCPauses <= 0 {
+			args.GCPauses = 10
+		}
+
+		result := map[string]interface{}{
+			"runtime": ReadRuntimeInfo(),
+		}
+
+		if args.RunGC {
+			start := time.Now()
+			runtime.GC()
+			result["gc_run"] = time.Since(start).String()
+		}
+		heap, pauses := ReadHeapStats(args.GCPauses)
+		result["heap"] = heap
+		result["recent_gc_pauses"] = pauses
+
+		goroutines := map[string]interface{}{
+			"count": runtime.NumGoroutine(),
+		}
+		if groupLimit > 0 {
+			groups, err := GoroutineGroups(groupLimit, args.StackDepth)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("failed to read goroutine profile: %w", err)), nil
+			}
+			goroutines["groups"] = groups
+		}
+		result["goroutines"] = goroutines
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *DiagnosticsProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Diagnostics Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *DiagnosticsProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that DiagnosticsProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*DiagnosticsProvider)(nil)
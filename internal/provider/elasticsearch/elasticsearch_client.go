@@ -0,0 +1,724 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultTimeout        = 30 * time.Second
+	defaultMaxHits        = 100
+	defaultMaxFieldChars  = 2000
+	defaultMaxResultBytes = 256 << 10
+	// defaultSize is the number of hits Elasticsearch returns when a search names no size
+	defaultSize = 10
+	// maxShardFailures bounds the shard failures reported with a search
+	maxShardFailures = 5
+)
+
+// Client queries an Elasticsearch or OpenSearch cluster over its REST API, limited to the configured indices
+type Client struct {
+	client         *resty.Client
+	indices        []string
+	timeout        time.Duration
+	maxHits        int
+	maxFieldChars  int
+	maxResultBytes int
+	available      bool
+	initErr        error
+}
+
+// ClusterInfo is the reply of GET /
+type ClusterInfo struct {
+	Name         string `json:"name"`
+	ClusterName  string `json:"cluster_name"`
+	Version      string `json:"version"`
+	Distribution string `json:"distribution"` // elasticsearch or opensearch
+}
+
+// SearchResult is a search response cut down to fit a tool result
+type SearchResult struct {
+	Index           string            `json:"index"`
+	Took            int               `json:"took_ms"`
+	TimedOut        bool              `json:"timed_out"`
+	Total           int64             `json:"total"`
+	TotalRelation   string            `json:"total_relation,omitempty"` // "gte" when the total is a lower bound
+	MaxScore        *float64          `json:"max_score,omitempty"`
+	Shards          ShardSummary      `json:"shards"`
+	Size            int               `json:"size"` // Hits asked for after capping
+	Returned        int               `json:"returned"`
+	Hits            []Hit             `json:"hits"`
+	Aggregations    json.RawMessage   `json:"aggregations,omitempty"`
+	NextSearchAfter []json.RawMessage `json:"next_search_after,omitempty"` // Sort values of the last hit, for search_after
+	Notes           []string          `json:"notes,omitempty"`             // What was capped or left out
+}
+
+// ShardSummary counts the shards a search ran on
+type ShardSummary struct {
+	Total      int      `json:"total"`
+	Successful int      `json:"successful"`
+	Skipped    int      `json:"skipped,omitempty"`
+	Failed     int      `json:"failed,omitempty"`
+	Failures   []string `json:"failures,omitempty"`
+}
+
+// Hit is one search hit
+type Hit struct {
+	Index     string              `json:"_index"`
+	ID        string              `json:"_id"`
+	Score     *float64            `json:"_score,omitempty"`
+	Source    interface{}         `json:"_source,omitempty"`
+	Fields    interface{}         `json:"fields,omitempty"`
+	Highlight map[string][]string `json:"highlight,omitempty"`
+	Sort      []json.RawMessage   `json:"sort,omitempty"`
+}
+
+// IndexInfo is a row of _cat/indices
+type IndexInfo struct {
+	Index      string   `json:"index"`
+	Health     string   `json:"health"`
+	Status     string   `json:"status"`
+	Docs       int64    `json:"docs"`
+	StoreBytes int64    `json:"store_bytes"`
+	Primaries  int      `json:"primaries"`
+	Replicas   int      `json:"replicas"`
+	Created    string   `json:"created,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+}
+
+// FieldMapping is a field of the merged mapping of one or more indices
+type FieldMapping struct {
+	Field string `json:"field"`
+	Type  string `json:"type"` // "conflict" when indices map the field differently
+	// Conflicts lists the indices behind each type when the indices disagree
+	Conflicts map[string][]string `json:"conflicts,omitempty"`
+	Missing   []string            `json:"missing_in,omitempty"` // Indices that do not map the field
+}
+
+// MappingResult holds the flattened mappings of the indices an expression resolves to
+type MappingResult struct {
+	Index   string                     `json:"index"`
+	Indices []string                   `json:"indices"`
+	Fields  []FieldMapping             `json:"fields"`
+	Raw     map[string]json.RawMessage `json:"raw,omitempty"`
+}
+
+// NewClient creates a new Elasticsearch client from config; without a url it is not available
+func NewClient(cfg *config.SearchConfig) *Client {
+	c := &Client{
+		timeout:        defaultTimeout,
+		maxHits:        defaultMaxHits,
+		maxFieldChars:  defaultMaxFieldChars,
+		maxResultBytes: defaultMaxResultBytes,
+	}
+	if cfg == nil || cfg.URL == "" {
+		return c
+	}
+	if cfg.TimeoutSeconds > 0 {
+		c.timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	if cfg.MaxHits > 0 {
+		c.maxHits = cfg.MaxHits
+	}
+	if cfg.MaxFieldChars > 0 {
+		c.maxFieldChars = cfg.MaxFieldChars
+	}
+	if cfg.MaxResultBytes > 0 {
+		c.maxResultBytes = cfg.MaxResultBytes
+	}
+	c.indices = cfg.Indices
+
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.URL, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetHeader("Content-Type", "application/json").
+		SetTimeout(c.timeout)
+
+	if cfg.APIKey != "" {
+		client.SetHeader("Authorization", "ApiKey "+cfg.APIKey)
+	} else if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+
+	if cfg.TLSSkipVerify || cfg.CAFile != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: cfg.TLSSkipVerify}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				c.initErr = fmt.Errorf("failed to read CA file: %w", err)
+				return c
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				c.initErr = fmt.Errorf("no certificates found in CA file %s", cfg.CAFile)
+				return c
+			}
+			tlsConfig.RootCAs = pool
+		}
+		client.SetTLSClientConfig(tlsConfig)
+	}
+
+	c.client = client
+	c.available = true
+	return c
+}
+
+// IsAvailable returns whether a url is configured and the settings are valid
+func (c *Client) IsAvailable() bool {
+	return c.available
+}
+
+// InitError returns the configuration error, if any
+func (c *Client) InitError() error {
+	return c.initErr
+}
+
+// MaxHits returns the cap on the hits of a search
+func (c *Client) MaxHits() int {
+	return c.maxHits
+}
+
+// Close releases resources held by the client
+func (c *Client) Close() error {
+	return nil
+}
+
+// Info returns the cluster name, version and distribution
+func (c *Client) Info(ctx context.Context) (*ClusterInfo, error) {
+	if !c.available {
+		return nil, fmt.Errorf("elasticsearch client not available")
+	}
+	var reply struct {
+		Name        string `json:"name"`
+		ClusterName string `json:"cluster_name"`
+		Version     struct {
+			Number       string `json:"number"`
+			Distribution string `json:"distribution"`
+		} `json:"version"`
+	}
+	resp, err := c.client.R().SetContext(ctx).SetResult(&reply).Get("/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach elasticsearch: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+	info := &ClusterInfo{
+		Name:         reply.Name,
+		ClusterName:  reply.ClusterName,
+		Version:      reply.Version.Number,
+		Distribution: reply.Version.Distribution,
+	}
+	if info.Distribution == "" {
+		info.Distribution = "elasticsearch"
+	}
+	return info, nil
+}
+
+// Search runs a query DSL body against an index expression. The size is capped at max_hits, string values
+// in the hits are cut to max_field_chars and hits past max_result_bytes are left out.
+func (c *Client) Search(ctx context.Context, index string, body map[string]interface{}) (*SearchResult, error) {
+	if !c.available {
+		return nil, fmt.Errorf("elasticsearch client not available")
+	}
+	if err := c.checkIndex(index); err != nil {
+		return nil, err
+	}
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	if _, ok := body["pit"]; ok {
+		return nil, fmt.Errorf("pit is not supported; page with search_after instead")
+	}
+
+	result := &SearchResult{Index: index, Size: defaultSize}
+	if raw, ok := body["size"]; ok {
+		size, err := intValue(raw)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("size must be a non-negative integer")
+		}
+		result.Size = size
+	}
+	if result.Size > c.maxHits {
+		result.Notes = append(result.Notes, fmt.Sprintf("size %d capped at %d (search.max_hits)", result.Size, c.maxHits))
+		result.Size = c.maxHits
+	}
+	body["size"] = result.Size
+	if _, ok := body["timeout"]; !ok {
+		body["timeout"] = fmt.Sprintf("%ds", int(c.timeout.Seconds()))
+	}
+
+	var reply struct {
+		Took     int  `json:"took"`
+		TimedOut bool `json:"timed_out"`
+		Shards   struct {
+			Total      int `json:"total"`
+			Successful int `json:"successful"`
+			Skipped    int `json:"skipped"`
+			Failed     int `json:"failed"`
+			Failures   []struct {
+				Index  string `json:"index"`
+				Shard  int    `json:"shard"`
+				Reason struct {
+					Type   string `json:"type"`
+					Reason string `json:"reason"`
+				} `json:"reason"`
+			} `json:"failures"`
+		} `json:"_shards"`
+		Hits struct {
+			Total    json.RawMessage `json:"total"`
+			MaxScore *float64        `json:"max_score"`
+			Hits     []struct {
+				Index     string              `json:"_index"`
+				ID        string              `json:"_id"`
+				Score     *float64            `json:"_score"`
+				Source    json.RawMessage     `json:"_source"`
+				Fields    json.RawMessage     `json:"fields"`
+				Highlight map[string][]string `json:"highlight"`
+				Sort      []json.RawMessage   `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+		Aggregations json.RawMessage `json:"aggregations"`
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&reply).
+		Post("/" + index + "/_search")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search elasticsearch: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+
+	result.Took = reply.Took
+	result.TimedOut = reply.TimedOut
+	result.MaxScore = reply.Hits.MaxScore
+	result.Total, result.TotalRelation = parseTotal(reply.Hits.Total)
+	result.Shards = ShardSummary{
+		Total:      reply.Shards.Total,
+		Successful: reply.Shards.Successful,
+		Skipped:    reply.Shards.Skipped,
+		Failed:     reply.Shards.Failed,
+	}
+	for i, failure := range reply.Shards.Failures {
+		if i == maxShardFailures {
+			result.Shards.Failures = append(result.Shards.Failures, fmt.Sprintf("... %d more", len(reply.Shards.Failures)-i))
+			break
+		}
+		result.Shards.Failures = append(result.Shards.Failures,
+			fmt.Sprintf("%s[%d]: %s: %s", failure.Index, failure.Shard, failure.Reason.Type, failure.Reason.Reason))
+	}
+
+	budget := c.maxResultBytes
+	if len(reply.Aggregations) > 0 {
+		if len(reply.Aggregations) > budget {
+			result.Notes = append(result.Notes, fmt.Sprintf("aggregations left out: %d bytes is more than search.max_result_bytes (%d); lower their size", len(reply.Aggregations), c.maxResultBytes))
+		} else {
+			result.Aggregations = reply.Aggregations
+			budget -= len(reply.Aggregations)
+		}
+	}
+
+	truncatedFields := 0
+	result.Hits = []Hit{}
+	for i, h := range reply.Hits.Hits {
+		hit := Hit{Index: h.Index, ID: h.ID, Score: h.Score, Highlight: h.Highlight, Sort: h.Sort}
+		cut := 0
+		hit.Source = c.decodeTruncated(h.Source, &cut)
+		hit.Fields = c.decodeTruncated(h.Fields, &cut)
+		encoded, err := json.Marshal(hit)
+		if err != nil {
+			return nil, err
+		}
+		if len(encoded) > budget && len(result.Hits) > 0 {
+			result.Notes = append(result.Notes, fmt.Sprintf("%d of %d hits left out to stay within search.max_result_bytes (%d); narrow _source or lower size", len(reply.Hits.Hits)-i, len(reply.Hits.Hits), c.maxResultBytes))
+			break
+		}
+		budget -= len(encoded)
+		truncatedFields += cut
+		result.Hits = append(result.Hits, hit)
+	}
+	result.Returned = len(result.Hits)
+	if truncatedFields > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("%d string values cut to %d characters (search.max_field_chars)", truncatedFields, c.maxFieldChars))
+	}
+	if n := len(result.Hits); n > 0 && len(result.Hits[n-1].Sort) > 0 && int64(n) < result.Total {
+		result.NextSearchAfter = result.Hits[n-1].Sort
+	}
+	return result, nil
+}
+
+// ListIndices lists the indices the allowlist permits whose names match a pattern, sorted by name.
+// Hidden indices (whose names start with a dot) are left out unless includeHidden is set.
+func (c *Client) ListIndices(ctx context.Context, pattern string, includeHidden, withAliases bool) ([]IndexInfo, error) {
+	if !c.available {
+		return nil, fmt.Errorf("elasticsearch client not available")
+	}
+	expandWildcards := "open,closed"
+	if includeHidden {
+		expandWildcards = "all"
+	}
+	var rows []map[string]string
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"format":           "json",
+			"bytes":            "b",
+			"h":                "health,status,index,docs.count,store.size,pri,rep,creation.date.string",
+			"expand_wildcards": expandWildcards,
+		}).
+		SetResult(&rows).
+		Get("/_cat/indices")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indices: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+
+	var aliases map[string][]string
+	if withAliases {
+		if aliases, err = c.aliases(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	indices := []IndexInfo{}
+	for _, row := range rows {
+		name := row["index"]
+		if !c.allowed(name, includeHidden) || !matchName(pattern, name) {
+			continue
+		}
+		info := IndexInfo{
+			Index:   name,
+			Health:  row["health"],
+			Status:  row["status"],
+			Created: row["creation.date.string"],
+			Aliases: aliases[name],
+		}
+		info.Docs, _ = strconv.ParseInt(row["docs.count"], 10, 64)
+		info.StoreBytes, _ = strconv.ParseInt(row["store.size"], 10, 64)
+		info.Primaries, _ = strconv.Atoi(row["pri"])
+		info.Replicas, _ = strconv.Atoi(row["rep"])
+		indices = append(indices, info)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].Index < indices[j].Index })
+	return indices, nil
+}
+
+// aliases maps index names to their aliases
+func (c *Client) aliases(ctx context.Context) (map[string][]string, error) {
+	var rows []map[string]string
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{"format": "json", "h": "alias,index"}).
+		SetResult(&rows).
+		Get("/_cat/aliases")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+	aliases := map[string][]string{}
+	for _, row := range rows {
+		aliases[row["index"]] = append(aliases[row["index"]], row["alias"])
+	}
+	for _, names := range aliases {
+		sort.Strings(names)
+	}
+	return aliases, nil
+}
+
+// Mapping flattens the mappings of the indices an expression resolves to into field paths and their types,
+// multi-fields included, and flags the fields the indices map differently
+func (c *Client) Mapping(ctx context.Context, index, fieldPattern string, raw bool) (*MappingResult, error) {
+	if !c.available {
+		return nil, fmt.Errorf("elasticsearch client not available")
+	}
+	if err := c.checkIndex(index); err != nil {
+		return nil, err
+	}
+	var reply map[string]struct {
+		Mappings json.RawMessage `json:"mappings"`
+	}
+	resp, err := c.client.R().SetContext(ctx).SetResult(&reply).Get("/" + index + "/_mapping")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mapping: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+
+	result := &MappingResult{Index: index, Indices: []string{}, Fields: []FieldMapping{}}
+	types := map[string]map[string][]string{} // field -> type -> indices
+	for name, entry := range reply {
+		if !c.allowed(name, true) {
+			continue
+		}
+		result.Indices = append(result.Indices, name)
+		if raw {
+			if result.Raw == nil {
+				result.Raw = map[string]json.RawMessage{}
+			}
+			result.Raw[name] = entry.Mappings
+		}
+		var mappings map[string]json.RawMessage
+		if err := json.Unmarshal(entry.Mappings, &mappings); err != nil {
+			return nil, fmt.Errorf("invalid mapping of %s: %w", name, err)
+		}
+		properties, ok := mappings["properties"]
+		if !ok && len(mappings) == 1 {
+			// Indices created before 7.0 nest the properties under a mapping type
+			for _, typed := range mappings {
+				var inner map[string]json.RawMessage
+				if json.Unmarshal(typed, &inner) == nil {
+					properties = inner["properties"]
+				}
+			}
+		}
+		fields := map[string]string{}
+		if err := flattenProperties("", properties, fields); err != nil {
+			return nil, fmt.Errorf("invalid mapping of %s: %w", name, err)
+		}
+		for field, fieldType := range fields {
+			if types[field] == nil {
+				types[field] = map[string][]string{}
+			}
+			types[field][fieldType] = append(types[field][fieldType], name)
+		}
+	}
+	sort.Strings(result.Indices)
+
+	for field, byType := range types {
+		if !matchName(fieldPattern, field) {
+			continue
+		}
+		mapping := FieldMapping{Field: field}
+		mapped := map[string]bool{}
+		for fieldType, names := range byType {
+			mapping.Type = fieldType
+			for _, name := range names {
+				mapped[name] = true
+			}
+		}
+		if len(byType) > 1 {
+			mapping.Type = "conflict"
+			mapping.Conflicts = byType
+			for _, names := range byType {
+				sort.Strings(names)
+			}
+		}
+		if len(mapped) < len(result.Indices) {
+			for _, name := range result.Indices {
+				if !mapped[name] {
+					mapping.Missing = append(mapping.Missing, name)
+				}
+			}
+		}
+		result.Fields = append(result.Fields, mapping)
+	}
+	sort.Slice(result.Fields, func(i, j int) bool { return result.Fields[i].Field < result.Fields[j].Field })
+	return result, nil
+}
+
+// flattenProperties walks a properties object, recording each field path and its type; objects without a
+// type are "object" and multi-fields are recorded as field.subfield
+func flattenProperties(prefix string, properties json.RawMessage, fields map[string]string) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	var props map[string]struct {
+		Type       string          `json:"type"`
+		Properties json.RawMessage `json:"properties"`
+		Fields     json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(properties, &props); err != nil {
+		return err
+	}
+	for name, prop := range props {
+		field := prefix + name
+		fieldType := prop.Type
+		if fieldType == "" {
+			fieldType = "object"
+		}
+		fields[field] = fieldType
+		if err := flattenProperties(field+".", prop.Properties, fields); err != nil {
+			return err
+		}
+		if err := flattenProperties(field+".", prop.Fields, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkIndex validates an index expression (comma-separated names, globs and -exclusions) against the
+// allowlist. With an allowlist, a glob must fall within an allowed glob: logs-2024* within logs-*.
+func (c *Client) checkIndex(index string) error {
+	if index == "" {
+		return fmt.Errorf("index is required")
+	}
+	if strings.ContainsAny(index, "/\\?#\" <>|") {
+		return fmt.Errorf("invalid index expression %q", index)
+	}
+	for _, part := range strings.Split(index, ",") {
+		if part == "" {
+			return fmt.Errorf("invalid index expression %q", index)
+		}
+		if strings.HasPrefix(part, "-") {
+			continue // Exclusions only narrow the expression
+		}
+		if !c.allowed(part, false) {
+			if len(c.indices) > 0 {
+				return fmt.Errorf("index %s is not allowed (search.indices: %s)", part, strings.Join(c.indices, ", "))
+			}
+			return fmt.Errorf("index %s is hidden; add it to search.indices to allow it", part)
+		}
+	}
+	return nil
+}
+
+// allowed reports whether the allowlist permits an index name or glob. Without an allowlist every index is
+// permitted except hidden ones, unless includeHidden is set.
+func (c *Client) allowed(name string, includeHidden bool) bool {
+	if len(c.indices) == 0 {
+		return includeHidden || !strings.HasPrefix(name, ".")
+	}
+	for _, pattern := range c.indices {
+		if pattern == name {
+			return true
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeTruncated decodes a JSON value, cutting its strings to max_field_chars
+func (c *Client) decodeTruncated(data json.RawMessage, truncated *int) interface{} {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return data
+	}
+	return truncateStrings(value, c.maxFieldChars, truncated)
+}
+
+func truncateStrings(value interface{}, limit int, truncated *int) interface{} {
+	switch v := value.(type) {
+	case string:
+		if runes := []rune(v); len(runes) > limit {
+			*truncated++
+			return string(runes[:limit]) + fmt.Sprintf("... (%d more characters)", len(runes)-limit)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = truncateStrings(item, limit, truncated)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = truncateStrings(item, limit, truncated)
+		}
+	}
+	return value
+}
+
+// parseTotal reads hits.total, an object with value and relation since 7.0 and a number before
+func parseTotal(raw json.RawMessage) (int64, string) {
+	var total struct {
+		Value    int64  `json:"value"`
+		Relation string `json:"relation"`
+	}
+	if err := json.Unmarshal(raw, &total); err == nil {
+		if total.Relation == "eq" {
+			total.Relation = ""
+		}
+		return total.Value, total.Relation
+	}
+	var n int64
+	_ = json.Unmarshal(raw, &n)
+	return n, ""
+}
+
+// intValue reads an integer from a decoded JSON number
+func intValue(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("not an integer")
+		}
+		return int(v), nil
+	case json.Number:
+		n, err := strconv.Atoi(v.String())
+		return n, err
+	case string:
+		return strconv.Atoi(v)
+	}
+	return 0, fmt.Errorf("not an integer")
+}
+
+// matchName matches a glob pattern, or a case-insensitive substring when there are no glob characters
+func matchName(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, err := path.Match(pattern, name)
+		return err == nil && matched
+	}
+	return strings.Contains(strings.ToLower(name), strings.ToLower(pattern))
+}
+
+// apiError turns an Elasticsearch error response into an error carrying its type and reason
+func apiError(resp *resty.Response) error {
+	var reply struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(resp.Body(), &reply) == nil && len(reply.Error) > 0 {
+		var detail struct {
+			Type      string `json:"type"`
+			Reason    string `json:"reason"`
+			RootCause []struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"root_cause"`
+		}
+		if json.Unmarshal(reply.Error, &detail) == nil && detail.Reason != "" {
+			message := fmt.Sprintf("%s: %s", detail.Type, detail.Reason)
+			if len(detail.RootCause) > 0 && detail.RootCause[0].Reason != detail.Reason {
+				message += fmt.Sprintf(" (caused by %s: %s)", detail.RootCause[0].Type, detail.RootCause[0].Reason)
+			}
+			return fmt.Errorf("elasticsearch API error: %s: %s", resp.Status(), message)
+		}
+	}
+	body := strings.TrimSpace(resp.String())
+	if len(body) > 500 {
+		body = body[:500] + "..."
+	}
+	if body == "" {
+		return fmt.Errorf("elasticsearch API error: %s", resp.Status())
+	}
+	return fmt.Errorf("elasticsearch API error: %s: %s", resp.Status(), body)
+}
@@ -0,0 +1,257 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// ElasticsearchProvider provides read-only Elasticsearch and OpenSearch access: query DSL searches with
+// capped, truncated results, index listings and flattened mappings
+type ElasticsearchProvider struct {
+	*provider.BaseProvider
+	client *Client
+}
+
+// NewElasticsearchProvider creates a new Elasticsearch provider with config
+func NewElasticsearchProvider(cfg *config.SearchConfig) *ElasticsearchProvider {
+	p := &ElasticsearchProvider{
+		BaseProvider: provider.NewBaseProvider("elasticsearch"),
+		client:       NewClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Elasticsearch not configured", p.client.InitError())
+		if err := p.client.InitError(); err != nil {
+			log.Printf("⚠ Elasticsearch provider not available: %v", err)
+		}
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Elasticsearch provider initialized successfully (url: %s)", cfg.URL)
+
+	return p
+}
+
+// Test tests the Elasticsearch configuration (for ProviderClient interface compatibility)
+func (p *ElasticsearchProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("elasticsearch provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Elasticsearch tools to the MCP server (for ProviderClient interface compatibility)
+func (p *ElasticsearchProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the Elasticsearch provider
+func (p *ElasticsearchProvider) Close() error {
+	return p.client.Close()
+}
+
+// HealthCheck checks that the cluster answers with the configured credentials
+func (p *ElasticsearchProvider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.client.timeout)
+	defer cancel()
+	_, err := p.client.Info(ctx)
+	return err
+}
+
+// addToolsToServer adds Elasticsearch tools to the MCP server
+func (p *ElasticsearchProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Elasticsearch provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createSearchTool(),
+		p.createListIndicesTool(),
+		p.createIndexMappingTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered Elasticsearch tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All Elasticsearch tools registered successfully")
+}
+
+// createSearchTool creates the query DSL search tool
+func (p *ElasticsearchProvider) createSearchTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "es_search",
+		Description: fmt.Sprintf("Search an Elasticsearch or OpenSearch index with a query DSL body, passed through as is "+
+			`({"query": {"match": {"message": "timeout"}}, "sort": [{"@timestamp": "desc"}], "aggs": {...}}). `+
+			"size is capped at %d; long strings in hits are cut and hits past the result size limit are left out, with notes saying so. "+
+			"To page, sort on a unique field and pass next_search_after back as search_after", p.client.MaxHits()),
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"index": {
+					"type": "string",
+					"description": "Index, alias or comma-separated expression with globs, e.g. logs-2024.05.* or orders,-orders-archive"
+				},
+				"body": {
+					"type": "object",
+					"description": "Search request body: query, size, from, sort, _source, fields, aggs, highlight, search_after, track_total_hits"
+				}
+			},
+			"required": ["index"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Index string                 `json:"index"`
+			Body  map[string]interface{} `json:"body,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Index == "" {
+			return p.createErrorResult(fmt.Errorf("index parameter is required")), nil
+		}
+
+		result, err := p.client.Search(ctx, args.Index, args.Body)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createListIndicesTool creates the index listing tool
+func (p *ElasticsearchProvider) createListIndicesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "es_list_indices",
+		Description: "List Elasticsearch or OpenSearch indices with their health, status, document count, store size, shard counts and creation date, sorted by name",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"pattern": {
+					"type": "string",
+					"description": "Glob (logs-*) or case-insensitive substring the index names must match"
+				},
+				"include_hidden": {
+					"type": "boolean",
+					"description": "Include hidden and system indices, whose names start with a dot",
+					"default": false
+				},
+				"aliases": {
+					"type": "boolean",
+					"description": "Add the aliases of each index",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Pattern       string `json:"pattern,omitempty"`
+			IncludeHidden bool   `json:"include_hidden,omitempty"`
+			Aliases       bool   `json:"aliases,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		indices, err := p.client.ListIndices(ctx, args.Pattern, args.IncludeHidden, args.Aliases)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(map[string]interface{}{
+			"indices": indices,
+			"count":   len(indices),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createIndexMappingTool creates the index mapping tool
+func (p *ElasticsearchProvider) createIndexMappingTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "es_index_mapping",
+		Description: "Show the fields of an Elasticsearch or OpenSearch index as flattened paths and types (user.name: keyword, message.raw: keyword). With a pattern over several indices the mappings are merged, flagging fields whose types conflict or that some indices lack",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"index": {
+					"type": "string",
+					"description": "Index, alias or expression with globs"
+				},
+				"field": {
+					"type": "string",
+					"description": "Glob (user.*) or case-insensitive substring the field paths must match"
+				},
+				"raw": {
+					"type": "boolean",
+					"description": "Also return the mapping of each index as Elasticsearch returns it",
+					"default": false
+				}
+			},
+			"required": ["index"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Index string `json:"index"`
+			Field string `json:"field,omitempty"`
+			Raw   bool   `json:"raw,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Index == "" {
+			return p.createErrorResult(fmt.Errorf("index parameter is required")), nil
+		}
+
+		result, err := p.client.Mapping(ctx, args.Index, args.Field, args.Raw)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *ElasticsearchProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Elasticsearch Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *ElasticsearchProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that ElasticsearchProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*ElasticsearchProvider)(nil)
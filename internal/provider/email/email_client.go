@@ -0,0 +1,372 @@
+package email
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/emersion/go-message"
+	"github.com/emersion/go-message/mail"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+const (
+	defaultIMAPPort    = 993
+	defaultSMTPPort    = 587
+	defaultMailbox     = "INBOX"
+	defaultMaxBodySize = 20000
+)
+
+// SearchQuery selects messages in the alerts mailbox
+type SearchQuery struct {
+	From       string
+	Subject    string
+	Text       string
+	Since      time.Time
+	UnseenOnly bool
+	Limit      int
+}
+
+// MessageSummary is a message listed by a search
+type MessageSummary struct {
+	UID     uint32    `json:"uid"`
+	Date    time.Time `json:"date"`
+	From    []string  `json:"from"`
+	To      []string  `json:"to,omitempty"`
+	Subject string    `json:"subject"`
+	Seen    bool      `json:"seen"`
+	Size    uint32    `json:"size"`
+}
+
+// Message is a full message with its text body
+type Message struct {
+	MessageSummary
+	MessageID   string   `json:"message_id,omitempty"`
+	Cc          []string `json:"cc,omitempty"`
+	Body        string   `json:"body"`
+	BodyType    string   `json:"body_type"`
+	Truncated   bool     `json:"truncated,omitempty"`
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+// EmailClient searches an IMAP mailbox (read-only) and sends mail over SMTP
+type EmailClient struct {
+	config *config.EmailConfig
+	logger *logging.Logger
+}
+
+// NewEmailClient creates a new email client from config
+func NewEmailClient(cfg *config.EmailConfig) *EmailClient {
+	return &EmailClient{
+		config: cfg,
+		logger: logging.New("EmailClient"),
+	}
+}
+
+// IsAvailable checks if either IMAP or SMTP is configured with credentials
+func (c *EmailClient) IsAvailable() bool {
+	return c.config != nil && (c.IMAPEnabled() || c.SMTPEnabled())
+}
+
+// IMAPEnabled reports whether mailbox search is configured
+func (c *EmailClient) IMAPEnabled() bool {
+	return c.config != nil && c.config.IMAPHost != "" && c.config.Username != "" && c.config.Password != ""
+}
+
+// SMTPEnabled reports whether sending is configured (sending is still gated by allow_send)
+func (c *EmailClient) SMTPEnabled() bool {
+	return c.config != nil && c.config.SMTPHost != "" && c.config.Username != "" && c.config.Password != ""
+}
+
+// mailbox returns the configured mailbox name
+func (c *EmailClient) mailbox() string {
+	if c.config.Mailbox != "" {
+		return c.config.Mailbox
+	}
+	return defaultMailbox
+}
+
+// connect dials the IMAP server, logs in and opens the mailbox read-only (EXAMINE),
+// so searching and reading never changes flags
+func (c *EmailClient) connect() (*client.Client, error) {
+	if !c.IMAPEnabled() {
+		return nil, fmt.Errorf("IMAP is not configured")
+	}
+
+	port := c.config.IMAPPort
+	if port == 0 {
+		port = defaultIMAPPort
+	}
+	addr := net.JoinHostPort(c.config.IMAPHost, strconv.Itoa(port))
+
+	var (
+		imapClient *client.Client
+		err        error
+	)
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if port == 143 {
+		imapClient, err = client.DialWithDialer(dialer, addr)
+		if err == nil {
+			err = imapClient.StartTLS(&tls.Config{ServerName: c.config.IMAPHost})
+		}
+	} else {
+		imapClient, err = client.DialWithDialerTLS(dialer, addr, &tls.Config{ServerName: c.config.IMAPHost})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to IMAP server: %w", err)
+	}
+	imapClient.Timeout = 60 * time.Second
+
+	if err := imapClient.Login(c.config.Username, c.config.Password); err != nil {
+		imapClient.Logout()
+		return nil, fmt.Errorf("IMAP login failed: %w", err)
+	}
+	if _, err := imapClient.Select(c.mailbox(), true); err != nil {
+		imapClient.Logout()
+		return nil, fmt.Errorf("failed to open mailbox %s: %w", c.mailbox(), err)
+	}
+	return imapClient, nil
+}
+
+// Search returns the newest messages matching the query
+func (c *EmailClient) Search(query SearchQuery) ([]MessageSummary, error) {
+	imapClient, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer imapClient.Logout()
+
+	criteria := imap.NewSearchCriteria()
+	if query.From != "" {
+		criteria.Header.Add("From", query.From)
+	}
+	if query.Subject != "" {
+		criteria.Header.Add("Subject", query.Subject)
+	}
+	if query.Text != "" {
+		criteria.Text = []string{query.Text}
+	}
+	if !query.Since.IsZero() {
+		criteria.Since = query.Since
+	}
+	if query.UnseenOnly {
+		criteria.WithoutFlags = []string{imap.SeenFlag}
+	}
+
+	uids, err := imapClient.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("IMAP search failed: %w", err)
+	}
+	if len(uids) == 0 {
+		return []MessageSummary{}, nil
+	}
+
+	// UIDs ascend with arrival; keep the newest
+	if query.Limit > 0 && len(uids) > query.Limit {
+		uids = uids[len(uids)-query.Limit:]
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+
+	messages := make(chan *imap.Message, len(uids))
+	items := []imap.FetchItem{imap.FetchUid, imap.FetchEnvelope, imap.FetchFlags, imap.FetchRFC822Size}
+	if err := imapClient.UidFetch(seqset, items, messages); err != nil {
+		return nil, fmt.Errorf("IMAP fetch failed: %w", err)
+	}
+
+	var summaries []MessageSummary
+	for msg := range messages {
+		summaries = append(summaries, summarize(msg))
+	}
+
+	// Newest first
+	for i, j := 0, len(summaries)-1; i < j; i, j = i+1, j-1 {
+		summaries[i], summaries[j] = summaries[j], summaries[i]
+	}
+	return summaries, nil
+}
+
+// Read fetches a single message by UID and extracts its text body
+func (c *EmailClient) Read(uid uint32, maxBytes int) (*Message, error) {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBodySize
+	}
+
+	imapClient, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer imapClient.Logout()
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+
+	section := &imap.BodySectionName{Peek: true}
+	items := []imap.FetchItem{imap.FetchUid, imap.FetchEnvelope, imap.FetchFlags, imap.FetchRFC822Size, section.FetchItem()}
+
+	messages := make(chan *imap.Message, 1)
+	if err := imapClient.UidFetch(seqset, items, messages); err != nil {
+		return nil, fmt.Errorf("IMAP fetch failed: %w", err)
+	}
+
+	msg := <-messages
+	if msg == nil {
+		return nil, fmt.Errorf("message %d not found in %s", uid, c.mailbox())
+	}
+
+	result := &Message{MessageSummary: summarize(msg)}
+	if msg.Envelope != nil {
+		result.MessageID = msg.Envelope.MessageId
+		result.Cc = addresses(msg.Envelope.Cc)
+	}
+
+	body := msg.GetBody(section)
+	if body == nil {
+		return nil, fmt.Errorf("server returned no body for message %d", uid)
+	}
+	if err := extractBody(body, result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Body) > maxBytes {
+		result.Body = result.Body[:maxBytes]
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// extractBody walks the MIME parts, preferring text/plain over text/html, and lists attachments
+func extractBody(r io.Reader, result *Message) error {
+	reader, err := mail.CreateReader(r)
+	if err != nil && !message.IsUnknownCharset(err) {
+		return fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	var plain, html string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if message.IsUnknownCharset(err) {
+				continue
+			}
+			return fmt.Errorf("failed to read message part: %w", err)
+		}
+
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, _, _ := h.ContentType()
+			data, err := io.ReadAll(io.LimitReader(part.Body, 1<<20))
+			if err != nil {
+				continue
+			}
+			switch {
+			case contentType == "text/plain" && plain == "":
+				plain = string(data)
+			case contentType == "text/html" && html == "":
+				html = string(data)
+			}
+		case *mail.AttachmentHeader:
+			filename, _ := h.Filename()
+			result.Attachments = append(result.Attachments, filename)
+		}
+	}
+
+	switch {
+	case plain != "":
+		result.Body, result.BodyType = strings.TrimSpace(plain), "text/plain"
+	case html != "":
+		result.Body, result.BodyType = htmlToText(html), "text/html (tags stripped)"
+	default:
+		result.BodyType = "none"
+	}
+	return nil
+}
+
+func summarize(msg *imap.Message) MessageSummary {
+	summary := MessageSummary{UID: msg.Uid, Size: msg.Size}
+	if msg.Envelope != nil {
+		summary.Date = msg.Envelope.Date
+		summary.Subject = msg.Envelope.Subject
+		summary.From = addresses(msg.Envelope.From)
+		summary.To = addresses(msg.Envelope.To)
+	}
+	for _, flag := range msg.Flags {
+		if flag == imap.SeenFlag {
+			summary.Seen = true
+		}
+	}
+	return summary
+}
+
+func addresses(list []*imap.Address) []string {
+	var out []string
+	for _, addr := range list {
+		if addr.PersonalName != "" {
+			out = append(out, fmt.Sprintf("%s <%s>", addr.PersonalName, addr.Address()))
+		} else {
+			out = append(out, addr.Address())
+		}
+	}
+	return out
+}
+
+// htmlToText strips tags and collapses whitespace; good enough for alert emails
+func htmlToText(html string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range html {
+		switch {
+		case r == '<':
+			inTag = true
+			b.WriteRune(' ')
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	lines := strings.Split(b.String(), "\n")
+	var out []string
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// ParseSince accepts a duration ago ("24h", "7d") or a date ("2006-01-02")
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since value %q (use e.g. 24h, 7d or 2006-01-02)", value)
+}
+
+// Close closes the email client
+func (c *EmailClient) Close() error {
+	return nil
+}
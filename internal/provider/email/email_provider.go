@@ -0,0 +1,314 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// EmailProvider exposes the alerts mailbox for triage (read-only IMAP) and templated
+// summary emails over SMTP, which are only sent when email.allow_send is enabled
+type EmailProvider struct {
+	*provider.BaseProvider
+	client *EmailClient
+}
+
+// NewEmailProvider creates a new email provider with config
+func NewEmailProvider(cfg *config.EmailConfig) *EmailProvider {
+	p := &EmailProvider{
+		BaseProvider: provider.NewBaseProvider("email"),
+		client:       NewEmailClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Email provider not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Email provider initialized successfully")
+
+	return p
+}
+
+// Test tests the email configuration (for ProviderClient interface compatibility)
+func (p *EmailProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("email provider not available")
+	}
+	return nil
+}
+
+// AddTools adds email tools to the MCP server (for ProviderClient interface compatibility)
+func (p *EmailProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the email provider
+func (p *EmailProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds email tools to the MCP server
+func (p *EmailProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Email provider not available, tools not added")
+		return
+	}
+
+	var tools []entity.ToolDefinition
+	if p.client.IMAPEnabled() {
+		tools = append(tools, p.createSearchTool(), p.createReadTool())
+	}
+	if p.client.SMTPEnabled() {
+		tools = append(tools, p.createSendTool())
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered email tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All email tools registered successfully")
+}
+
+// createSearchTool creates the mailbox search tool
+func (p *EmailProvider) createSearchTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "email_search",
+		Description: "Search the alerts mailbox (read-only) by sender, subject, text and age. Returns the newest matches first",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"from": {
+					"type": "string",
+					"description": "Sender address or name substring (e.g. alertmanager@, pagerduty)"
+				},
+				"subject": {
+					"type": "string",
+					"description": "Subject substring"
+				},
+				"text": {
+					"type": "string",
+					"description": "Text that must appear in the headers or body"
+				},
+				"since": {
+					"type": "string",
+					"description": "Only messages after this point: a duration (24h, 7d) or a date (2006-01-02)",
+					"default": "24h"
+				},
+				"unseen_only": {
+					"type": "boolean",
+					"description": "Only return messages not yet marked as read",
+					"default": false
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of messages to return",
+					"default": 20
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			From       string `json:"from,omitempty"`
+			Subject    string `json:"subject,omitempty"`
+			Text       string `json:"text,omitempty"`
+			Since      string `json:"since,omitempty"`
+			UnseenOnly bool   `json:"unseen_only,omitempty"`
+			Limit      int    `json:"limit,omitempty"`
+		}{Since: "24h"}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		since, err := ParseSince(args.Since, time.Now())
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = 20
+		}
+
+		messages, err := p.client.Search(SearchQuery{
+			From:       args.From,
+			Subject:    args.Subject,
+			Text:       args.Text,
+			Since:      since,
+			UnseenOnly: args.UnseenOnly,
+			Limit:      args.Limit,
+		})
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"mailbox":  p.client.mailbox(),
+			"messages": messages,
+			"count":    len(messages),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createReadTool creates the message read tool
+func (p *EmailProvider) createReadTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "email_read",
+		Description: "Read a message from the alerts mailbox by UID (from email_search). The message is not marked as read",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"uid": {
+					"type": "integer",
+					"description": "Message UID returned by email_search"
+				},
+				"max_bytes": {
+					"type": "integer",
+					"description": "Maximum body size to return",
+					"default": 20000
+				}
+			},
+			"required": ["uid"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			UID      uint32 `json:"uid"`
+			MaxBytes int    `json:"max_bytes,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.UID == 0 {
+			return p.createErrorResult(fmt.Errorf("uid parameter is required")), nil
+		}
+
+		message, err := p.client.Read(args.UID, args.MaxBytes)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(message), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createSendTool creates the templated send tool
+func (p *EmailProvider) createSendTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "email_send",
+		Description: fmt.Sprintf("Send a summary email rendered from a template (available: %v). "+
+			"Template data is passed as fields; the subject is available as .Title. "+
+			"Disabled unless email.allow_send is enabled; recipients are limited to email.allowed_recipients", p.client.Templates()),
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"to": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Recipient addresses"
+				},
+				"subject": {
+					"type": "string",
+					"description": "Email subject"
+				},
+				"template": {
+					"type": "string",
+					"description": "Template name",
+					"default": "summary"
+				},
+				"data": {
+					"type": "object",
+					"description": "Template fields; the built-in summary template uses Summary (string), Items (list of strings) and Link (string)"
+				},
+				"body": {
+					"type": "string",
+					"description": "Plain text body to send instead of a template"
+				}
+			},
+			"required": ["to", "subject"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			To       []string               `json:"to"`
+			Subject  string                 `json:"subject"`
+			Template string                 `json:"template,omitempty"`
+			Data     map[string]interface{} `json:"data,omitempty"`
+			Body     string                 `json:"body,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if len(args.To) == 0 {
+			return p.createErrorResult(fmt.Errorf("to parameter is required")), nil
+		}
+		if args.Subject == "" {
+			return p.createErrorResult(fmt.Errorf("subject parameter is required")), nil
+		}
+
+		err := p.client.Send(SendRequest{
+			To:       args.To,
+			Subject:  args.Subject,
+			Template: args.Template,
+			Data:     args.Data,
+			Body:     args.Body,
+		})
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"sent":    true,
+			"to":      args.To,
+			"subject": args.Subject,
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *EmailProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Email Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *EmailProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that EmailProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*EmailProvider)(nil)
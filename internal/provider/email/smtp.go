@@ -0,0 +1,222 @@
+package email
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"dev-mcp/internal/logging"
+)
+
+// defaultSummaryTemplate is used when email_send names no template or the "summary" template is not configured
+const defaultSummaryTemplate = `{{.Title}}
+{{with .Summary}}
+{{.}}
+{{end}}{{with .Items}}
+{{range .}}- {{.}}
+{{end}}{{end}}{{with .Link}}
+Details: {{.}}
+{{end}}
+--
+Sent by dev-mcp
+`
+
+// SendRequest describes a templated email
+type SendRequest struct {
+	To       []string
+	Subject  string
+	Template string
+	Data     map[string]interface{}
+	Body     string
+}
+
+// Templates returns the names of the configured templates plus the built-in summary template
+func (c *EmailClient) Templates() []string {
+	names := []string{"summary"}
+	for name := range c.config.Templates {
+		if name != "summary" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// Send renders and sends an email if the write gate is open
+func (c *EmailClient) Send(req SendRequest) error {
+	if !c.config.AllowSend {
+		return fmt.Errorf("sending email is disabled (set email.allow_send to enable)")
+	}
+	if !c.SMTPEnabled() {
+		return fmt.Errorf("SMTP is not configured")
+	}
+	if len(req.To) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+
+	recipients := make([]string, 0, len(req.To))
+	for _, to := range req.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		if !c.recipientAllowed(addr.Address) {
+			return fmt.Errorf("recipient %s is not in email.allowed_recipients", addr.Address)
+		}
+		recipients = append(recipients, addr.Address)
+	}
+
+	body := req.Body
+	if body == "" {
+		rendered, err := c.render(req.Template, req.Subject, req.Data)
+		if err != nil {
+			return err
+		}
+		body = rendered
+	}
+
+	from := c.config.From
+	if from == "" {
+		from = c.config.Username
+	}
+	msg, err := buildMessage(from, recipients, req.Subject, body)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Warn("sending email",
+		logging.String("to", strings.Join(recipients, ",")),
+		logging.String("subject", req.Subject))
+	return c.deliver(from, recipients, msg)
+}
+
+// recipientAllowed checks an address against the allowed_recipients list (exact address or @domain)
+func (c *EmailClient) recipientAllowed(address string) bool {
+	if len(c.config.AllowedRecipients) == 0 {
+		return true
+	}
+	address = strings.ToLower(address)
+	for _, allowed := range c.config.AllowedRecipients {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if strings.HasPrefix(allowed, "@") {
+			if strings.HasSuffix(address, allowed) {
+				return true
+			}
+		} else if address == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// render executes a named template with the subject available as .Title
+func (c *EmailClient) render(name, subject string, data map[string]interface{}) (string, error) {
+	if name == "" {
+		name = "summary"
+	}
+	text, ok := c.config.Templates[name]
+	if !ok {
+		if name != "summary" {
+			return "", fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(c.Templates(), ", "))
+		}
+		text = defaultSummaryTemplate
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	values := map[string]interface{}{"Title": subject}
+	for k, v := range data {
+		values[k] = v
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// buildMessage assembles a UTF-8 text/plain message
+func buildMessage(from string, to []string, subject, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// deliver sends the message, using implicit TLS on port 465 and STARTTLS otherwise
+func (c *EmailClient) deliver(from string, to []string, msg []byte) error {
+	port := c.config.SMTPPort
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(c.config.SMTPHost, strconv.Itoa(port))
+	auth := smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.SMTPHost)
+
+	if port != 465 {
+		if err := smtp.SendMail(addr, auth, from, to, msg); err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", addr, &tls.Config{ServerName: c.config.SMTPHost})
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	smtpClient, err := smtp.NewClient(conn, c.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer smtpClient.Close()
+
+	if err := smtpClient.Auth(auth); err != nil {
+		return fmt.Errorf("SMTP authentication failed: %w", err)
+	}
+	if err := smtpClient.Mail(from); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := smtpClient.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("failed to send email to %s: %w", rcpt, err)
+		}
+	}
+	w, err := smtpClient.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return smtpClient.Quit()
+}
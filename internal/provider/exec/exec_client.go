@@ -0,0 +1,476 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+	"dev-mcp/internal/provider/file"
+)
+
+const (
+	defaultTimeout        = 120 * time.Second
+	defaultMaxOutputBytes = 64 * 1024
+	defaultMaxConcurrent  = 2
+	maxExtraArgs          = 64
+	// waitDelay bounds how long output pipes are drained after the process exits or is killed
+	waitDelay = 5 * time.Second
+)
+
+// Command is an allowed command resolved at startup
+type Command struct {
+	Name        string         `json:"name"`
+	Argv        []string       `json:"command"`
+	Description string         `json:"description,omitempty"`
+	ArgsPattern string         `json:"args_pattern,omitempty"`
+	Timeout     time.Duration  `json:"-"`
+	path        string         // Absolute path of the executable
+	args        *regexp.Regexp // nil when no extra arguments are allowed
+}
+
+// RunRequest describes a single execution
+type RunRequest struct {
+	Name    string
+	Args    []string
+	Dir     string
+	Timeout time.Duration
+	User    string
+}
+
+// RunResult is the outcome of an execution
+type RunResult struct {
+	Command         []string `json:"command"`
+	Dir             string   `json:"dir"`
+	ExitCode        int      `json:"exit_code"`
+	Success         bool     `json:"success"`
+	TimedOut        bool     `json:"timed_out,omitempty"`
+	Duration        string   `json:"duration"`
+	Stdout          string   `json:"stdout"`
+	Stderr          string   `json:"stderr"`
+	StdoutTruncated int64    `json:"stdout_bytes_omitted,omitempty"`
+	StderrTruncated int64    `json:"stderr_bytes_omitted,omitempty"`
+}
+
+// auditEntry is one line of the audit log
+type auditEntry struct {
+	Time       string   `json:"time"`
+	User       string   `json:"user,omitempty"`
+	Command    string   `json:"command"`
+	Argv       []string `json:"argv,omitempty"`
+	Dir        string   `json:"dir,omitempty"`
+	ExitCode   *int     `json:"exit_code,omitempty"`
+	DurationMS int64    `json:"duration_ms,omitempty"`
+	TimedOut   bool     `json:"timed_out,omitempty"`
+	Rejected   string   `json:"rejected,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// ExecClient runs allowlisted commands in whitelisted directories without a shell
+type ExecClient struct {
+	commands  map[string]*Command
+	roots     []string
+	validator *file.FileSecurityValidator
+	env       []string
+	timeout   time.Duration
+	maxOutput int
+	slots     chan struct{}
+	logger    *logging.Logger
+
+	auditMu   sync.Mutex
+	auditFile *os.File
+	initErr   error
+}
+
+// NewExecClient creates a new exec client; it is available only when enabled with at least one usable command
+func NewExecClient(cfg *config.ExecConfig) *ExecClient {
+	c := &ExecClient{
+		commands:  map[string]*Command{},
+		timeout:   defaultTimeout,
+		maxOutput: defaultMaxOutputBytes,
+		logger:    logging.New("exec"),
+	}
+	if cfg == nil || !cfg.Enabled || len(cfg.Commands) == 0 {
+		return c
+	}
+	if cfg.TimeoutSeconds > 0 {
+		c.timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	if cfg.MaxOutputBytes > 0 {
+		c.maxOutput = cfg.MaxOutputBytes
+	}
+	concurrent := defaultMaxConcurrent
+	if cfg.MaxConcurrent > 0 {
+		concurrent = cfg.MaxConcurrent
+	}
+	c.slots = make(chan struct{}, concurrent)
+
+	dirs := cfg.Directories
+	if len(dirs) == 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			c.initErr = fmt.Errorf("failed to get working directory: %w", err)
+			return c
+		}
+		dirs = []string{wd}
+	}
+	for _, dir := range dirs {
+		// Symlinks are resolved so a link inside a directory cannot point outside it
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			resolved, err = filepath.Abs(resolved)
+		}
+		if err != nil {
+			log.Printf("⚠ Skipping exec directory %s: %v", dir, err)
+			continue
+		}
+		c.roots = append(c.roots, resolved)
+	}
+	if len(c.roots) == 0 {
+		c.initErr = fmt.Errorf("none of the exec directories exist")
+		return c
+	}
+	c.validator = file.NewFileSecurityValidator(c.roots)
+	c.validator.SetReadOnly(true)
+
+	c.env = []string{"NO_COLOR=1", "TERM=dumb", "GIT_TERMINAL_PROMPT=0"}
+	for _, name := range append([]string{"PATH", "HOME"}, cfg.Env...) {
+		if value, ok := os.LookupEnv(name); ok {
+			c.env = append(c.env, name+"="+value)
+		}
+	}
+
+	for _, cc := range cfg.Commands {
+		command, err := newCommand(cc)
+		if err != nil {
+			log.Printf("⚠ Skipping exec command %q: %v", cc.Name, err)
+			continue
+		}
+		if _, exists := c.commands[command.Name]; exists {
+			log.Printf("⚠ Skipping duplicate exec command %q", command.Name)
+			continue
+		}
+		c.commands[command.Name] = command
+	}
+	if len(c.commands) == 0 {
+		c.initErr = fmt.Errorf("none of the exec commands are usable")
+		return c
+	}
+
+	if cfg.AuditLog != "" {
+		f, err := os.OpenFile(cfg.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			// Executions must be auditable, so a broken audit log disables the tool
+			c.commands = map[string]*Command{}
+			c.initErr = fmt.Errorf("failed to open exec audit log: %w", err)
+			return c
+		}
+		c.auditFile = f
+	}
+	return c
+}
+
+func newCommand(cc config.ExecCommandConfig) (*Command, error) {
+	argv := strings.Fields(cc.Command)
+	if cc.Name == "" || len(argv) == 0 {
+		return nil, fmt.Errorf("name and command are required")
+	}
+	path, err := osexec.LookPath(argv[0])
+	if err != nil {
+		return nil, fmt.Errorf("executable not found: %w", err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, err
+	}
+	command := &Command{
+		Name:        cc.Name,
+		Argv:        argv,
+		Description: cc.Description,
+		ArgsPattern: cc.ArgsPattern,
+		path:        path,
+	}
+	if cc.TimeoutSeconds > 0 {
+		command.Timeout = time.Duration(cc.TimeoutSeconds) * time.Second
+	}
+	if cc.ArgsPattern != "" {
+		// Anchored so the whole argument must match
+		if command.args, err = regexp.Compile(`^(?:` + cc.ArgsPattern + `)$`); err != nil {
+			return nil, fmt.Errorf("invalid args_pattern: %w", err)
+		}
+	}
+	return command, nil
+}
+
+// IsAvailable reports whether at least one command can run
+func (c *ExecClient) IsAvailable() bool {
+	return len(c.commands) > 0 && len(c.roots) > 0
+}
+
+// InitError returns the configuration error, if any
+func (c *ExecClient) InitError() error {
+	return c.initErr
+}
+
+// Commands returns the allowed commands sorted by name
+func (c *ExecClient) Commands() []*Command {
+	commands := make([]*Command, 0, len(c.commands))
+	for _, command := range c.commands {
+		commands = append(commands, command)
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands
+}
+
+// Directories returns the directories commands may run in
+func (c *ExecClient) Directories() []string {
+	return c.roots
+}
+
+// Close closes the audit log
+func (c *ExecClient) Close() error {
+	c.auditMu.Lock()
+	defer c.auditMu.Unlock()
+	if c.auditFile == nil {
+		return nil
+	}
+	err := c.auditFile.Close()
+	c.auditFile = nil
+	return err
+}
+
+// Run validates and executes a request; every call, including rejected ones, is audited
+func (c *ExecClient) Run(ctx context.Context, req RunRequest) (*RunResult, error) {
+	entry := auditEntry{Time: time.Now().UTC().Format(time.RFC3339), User: req.User, Command: req.Name}
+
+	command, dir, timeout, err := c.validate(req)
+	if err != nil {
+		entry.Rejected = err.Error()
+		c.audit(entry)
+		return nil, err
+	}
+	argv := append(append([]string{}, command.Argv...), req.Args...)
+	entry.Argv, entry.Dir = argv, dir
+
+	select {
+	case c.slots <- struct{}{}:
+		defer func() { <-c.slots }()
+	default:
+		err := fmt.Errorf("too many commands running (max %d); try again later", cap(c.slots))
+		entry.Rejected = err.Error()
+		c.audit(entry)
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := osexec.CommandContext(runCtx, command.path, argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = c.env
+	cmd.WaitDelay = waitDelay
+	stdout := newHeadTailBuffer(c.maxOutput)
+	stderr := newHeadTailBuffer(c.maxOutput)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	setProcessGroup(cmd)
+
+	start := time.Now()
+	runErr := cmd.Run()
+	elapsed := time.Since(start)
+
+	result := &RunResult{
+		Command:         argv,
+		Dir:             dir,
+		ExitCode:        -1,
+		Duration:        elapsed.Round(time.Millisecond).String(),
+		Stdout:          stdout.String(),
+		Stderr:          stderr.String(),
+		StdoutTruncated: stdout.Omitted(),
+		StderrTruncated: stderr.Omitted(),
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	result.TimedOut = errors.Is(runCtx.Err(), context.DeadlineExceeded)
+	result.Success = runErr == nil
+
+	entry.ExitCode = &result.ExitCode
+	entry.DurationMS = elapsed.Milliseconds()
+	entry.TimedOut = result.TimedOut
+	var exitErr *osexec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		entry.Error = runErr.Error()
+	}
+	c.audit(entry)
+
+	if runErr != nil && !errors.As(runErr, &exitErr) && !result.TimedOut && ctx.Err() == nil {
+		return nil, fmt.Errorf("failed to run %s: %w", command.Name, runErr)
+	}
+	return result, nil
+}
+
+// validate resolves the command, working directory and timeout of a request
+func (c *ExecClient) validate(req RunRequest) (*Command, string, time.Duration, error) {
+	command, ok := c.commands[req.Name]
+	if !ok {
+		return nil, "", 0, fmt.Errorf("command %q is not allowed", req.Name)
+	}
+	if len(req.Args) > 0 && command.args == nil {
+		return nil, "", 0, fmt.Errorf("command %q does not accept extra arguments", req.Name)
+	}
+	if len(req.Args) > maxExtraArgs {
+		return nil, "", 0, fmt.Errorf("too many arguments (%d, max %d)", len(req.Args), maxExtraArgs)
+	}
+	for _, arg := range req.Args {
+		if strings.ContainsRune(arg, 0) || !command.args.MatchString(arg) {
+			return nil, "", 0, fmt.Errorf("argument %q is not allowed for %s (must match %s)", arg, req.Name, command.ArgsPattern)
+		}
+	}
+
+	dir, err := c.ResolveDir(req.Dir)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	timeout := c.timeout
+	if command.Timeout > 0 {
+		timeout = command.Timeout
+	}
+	// Callers may shorten but not extend the configured timeout
+	if req.Timeout > 0 && req.Timeout < timeout {
+		timeout = req.Timeout
+	}
+	return command, dir, timeout, nil
+}
+
+// ResolveDir maps the dir argument to a whitelisted directory; relative paths are looked up under each root
+func (c *ExecClient) ResolveDir(dir string) (string, error) {
+	if strings.ContainsRune(dir, 0) {
+		return "", fmt.Errorf("invalid directory")
+	}
+	var candidates []string
+	switch {
+	case dir == "":
+		candidates = []string{c.roots[0]}
+	case filepath.IsAbs(dir):
+		candidates = []string{dir}
+	default:
+		for _, root := range c.roots {
+			candidates = append(candidates, filepath.Join(root, dir))
+		}
+	}
+
+	for _, candidate := range candidates {
+		resolved, err := filepath.EvalSymlinks(candidate)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(resolved)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		if !c.validator.IsPathWhitelisted(resolved) {
+			return "", fmt.Errorf("directory %s is outside the exec directories", dir)
+		}
+		return resolved, nil
+	}
+	return "", fmt.Errorf("directory %s not found in the exec directories", dir)
+}
+
+// audit writes an entry to the audit log (when configured) and the server log
+func (c *ExecClient) audit(entry auditEntry) {
+	fields := []logging.Field{
+		logging.String("command", entry.Command),
+		logging.String("user", entry.User),
+		logging.String("dir", entry.Dir),
+	}
+	switch {
+	case entry.Rejected != "":
+		c.logger.Warn("exec rejected", append(fields, logging.String("reason", entry.Rejected))...)
+	case entry.ExitCode != nil:
+		c.logger.Info("exec finished", append(fields,
+			logging.String("argv", strings.Join(entry.Argv, " ")),
+			logging.Int("exit_code", *entry.ExitCode),
+			logging.Duration("duration", time.Duration(entry.DurationMS)*time.Millisecond))...)
+	}
+
+	c.auditMu.Lock()
+	defer c.auditMu.Unlock()
+	if c.auditFile == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if _, err := c.auditFile.Write(append(line, '\n')); err != nil {
+		c.logger.Error("failed to write exec audit log", logging.Error(err))
+	}
+}
+
+// headTailBuffer keeps the first and last halves of the output, dropping the middle of large outputs
+type headTailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	head  []byte
+	tail  []byte
+	total int64
+}
+
+func newHeadTailBuffer(limit int) *headTailBuffer {
+	return &headTailBuffer{limit: limit}
+}
+
+func (b *headTailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total += int64(len(p))
+	headLimit := b.limit / 2
+	data := p
+	if room := headLimit - len(b.head); room > 0 {
+		n := min(room, len(data))
+		b.head = append(b.head, data[:n]...)
+		data = data[n:]
+	}
+	if len(data) == 0 {
+		return len(p), nil
+	}
+	tailLimit := b.limit - headLimit
+	if len(data) >= tailLimit {
+		b.tail = append(b.tail[:0], data[len(data)-tailLimit:]...)
+		return len(p), nil
+	}
+	b.tail = append(b.tail, data...)
+	if over := len(b.tail) - tailLimit; over > 0 {
+		b.tail = b.tail[:copy(b.tail, b.tail[over:])]
+	}
+	return len(p), nil
+}
+
+// Omitted returns the number of bytes dropped from the middle
+func (b *headTailBuffer) Omitted() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.total - int64(len(b.head)+len(b.tail))
+}
+
+func (b *headTailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	omitted := b.total - int64(len(b.head)+len(b.tail))
+	if omitted == 0 {
+		return string(b.head) + string(b.tail)
+	}
+	return fmt.Sprintf("%s\n... [%d bytes omitted] ...\n%s", b.head, omitted, b.tail)
+}
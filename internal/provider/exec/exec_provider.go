@@ -0,0 +1,209 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// ExecProvider runs allowlisted commands such as tests, builds and linters
+type ExecProvider struct {
+	*provider.BaseProvider
+	client *ExecClient
+}
+
+// NewExecProvider creates a new exec provider with config
+func NewExecProvider(cfg *config.ExecConfig) *ExecProvider {
+	p := &ExecProvider{
+		BaseProvider: provider.NewBaseProvider("exec"),
+		client:       NewExecClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Exec commands not configured", p.client.InitError())
+		if err := p.client.InitError(); err != nil {
+			log.Printf("⚠ Exec provider not available: %v", err)
+		}
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Exec provider initialized successfully (%d commands)", len(p.client.Commands()))
+
+	return p
+}
+
+// Test tests the exec configuration (for ProviderClient interface compatibility)
+func (p *ExecProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("exec provider not available")
+	}
+	return nil
+}
+
+// AddTools adds exec tools to the MCP server (for ProviderClient interface compatibility)
+func (p *ExecProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the exec provider
+func (p *ExecProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds exec tools to the MCP server
+func (p *ExecProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Exec provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createListCommandsTool(),
+		p.createRunTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered exec tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All exec tools registered successfully")
+}
+
+// createListCommandsTool creates the allowed command listing tool
+func (p *ExecProvider) createListCommandsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "exec_list_commands",
+		Description: "List the commands exec_run may start, the extra arguments each accepts and the directories they may run in",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		commands := []map[string]interface{}{}
+		for _, command := range p.client.Commands() {
+			entry := map[string]interface{}{
+				"name":       command.Name,
+				"command":    command.Argv,
+				"extra_args": command.ArgsPattern != "",
+			}
+			if command.ArgsPattern != "" {
+				entry["args_pattern"] = command.ArgsPattern
+			}
+			if command.Description != "" {
+				entry["description"] = command.Description
+			}
+			if command.Timeout > 0 {
+				entry["timeout"] = command.Timeout.String()
+			}
+			commands = append(commands, entry)
+		}
+		return p.formatJSONResult(map[string]interface{}{
+			"commands":    commands,
+			"directories": p.client.Directories(),
+			"timeout":     p.client.timeout.String(),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createRunTool creates the command execution tool
+func (p *ExecProvider) createRunTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "exec_run",
+		Description: "Run an allowlisted command (see exec_list_commands) such as tests, a build or a linter, without a shell, in a whitelisted directory. Returns the exit code and stdout/stderr; long output keeps its beginning and end. Every execution is audited",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"command": {
+					"type": "string",
+					"description": "Name of an allowed command, e.g. go-test"
+				},
+				"args": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Extra arguments appended to the command; each must match the command's args_pattern"
+				},
+				"dir": {
+					"type": "string",
+					"description": "Working directory, absolute or relative to an exec directory; defaults to the first exec directory"
+				},
+				"timeout_seconds": {
+					"type": "integer",
+					"description": "Shorter timeout than the configured one"
+				}
+			},
+			"required": ["command"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Command        string   `json:"command"`
+			Args           []string `json:"args,omitempty"`
+			Dir            string   `json:"dir,omitempty"`
+			TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Command == "" {
+			return p.createErrorResult(fmt.Errorf("command parameter is required")), nil
+		}
+
+		run := RunRequest{
+			Name:    args.Command,
+			Args:    args.Args,
+			Dir:     args.Dir,
+			Timeout: time.Duration(args.TimeoutSeconds) * time.Second,
+		}
+		if authResult, ok := auth.GetAuthResult(ctx); ok && authResult != nil {
+			run.User = authResult.Username
+		}
+
+		result, err := p.client.Run(ctx, run)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *ExecProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Exec Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *ExecProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that ExecProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*ExecProvider)(nil)
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package exec
+
+import (
+	osexec "os/exec"
+)
+
+// setProcessGroup is a no-op where process groups are unavailable; a timeout kills only the command itself
+func setProcessGroup(cmd *osexec.Cmd) {}
@@ -0,0 +1,157 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CopyResult summarises a file or directory copy
+type CopyResult struct {
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	IsDir       bool     `json:"is_dir"`
+	Files       int      `json:"files"`
+	Directories int      `json:"directories"`
+	Bytes       int64    `json:"bytes"`
+	Overwritten int      `json:"overwritten"`
+	Skipped     []string `json:"skipped,omitempty"` // Symlinks and special files, which are not copied
+}
+
+// copyEntry is a file or directory of a planned copy
+type copyEntry struct {
+	src, dst string
+	mode     fs.FileMode
+	isDir    bool
+	size     int64
+}
+
+// Copy copies a file, or a directory when recursive is set. Every entry is validated before anything is
+// written, so a copy that would break a rule writes nothing.
+func (p *FileProvider) Copy(src, dst string, recursive, overwrite bool) (*CopyResult, error) {
+	if err := p.validator.ValidateFileOperation("read", src); err != nil {
+		return nil, fmt.Errorf("source path security validation failed: %w", err)
+	}
+	if err := p.validator.ValidateFileOperation("create", dst); err != nil {
+		return nil, fmt.Errorf("destination path security validation failed: %w", err)
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, fmt.Errorf("source does not exist: %s", src)
+	}
+	if info.IsDir() && !recursive {
+		return nil, fmt.Errorf("source is a directory, use recursive=true to copy directories")
+	}
+	if _, err := os.Stat(dst); err == nil && !overwrite {
+		return nil, fmt.Errorf("destination already exists: %s (use overwrite=true to replace it)", dst)
+	}
+	if info.IsDir() {
+		absSrc, _ := filepath.Abs(src)
+		absDst, _ := filepath.Abs(dst)
+		if rel, err := filepath.Rel(absSrc, absDst); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("cannot copy a directory into itself: %s", dst)
+		}
+	}
+
+	result := &CopyResult{Source: src, Destination: dst, IsDir: info.IsDir()}
+	entries, err := p.planCopy(src, dst, info, result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.isDir {
+			if err := os.MkdirAll(entry.dst, entry.mode.Perm()|0700); err != nil {
+				return result, fmt.Errorf("failed to create directory %s: %w", entry.dst, err)
+			}
+			result.Directories++
+			continue
+		}
+		if dstInfo, err := os.Stat(entry.dst); err == nil {
+			if dstInfo.IsDir() {
+				return result, fmt.Errorf("cannot overwrite directory %s with a file", entry.dst)
+			}
+			result.Overwritten++
+		}
+		if err := copyFile(entry.src, entry.dst, entry.mode); err != nil {
+			return result, err
+		}
+		result.Files++
+		result.Bytes += entry.size
+	}
+	return result, nil
+}
+
+// planCopy lists the entries of a copy, validating that each source may be read and each destination written
+func (p *FileProvider) planCopy(src, dst string, info fs.FileInfo, result *CopyResult) ([]copyEntry, error) {
+	if !info.IsDir() {
+		if err := p.validator.ValidateFileSize(info.Size()); err != nil {
+			return nil, fmt.Errorf("file size validation failed: %w", err)
+		}
+		return []copyEntry{{src: src, dst: dst, mode: info.Mode(), size: info.Size()}}, nil
+	}
+
+	var entries []copyEntry
+	err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if !entry.IsDir() && !entry.Type().IsRegular() {
+			result.Skipped = append(result.Skipped, path)
+			return nil
+		}
+
+		if err := p.validator.ValidateFileOperation("read", path); err != nil {
+			return fmt.Errorf("source path security validation failed: %w", err)
+		}
+		if err := p.validator.ValidateFileOperation("create", target); err != nil {
+			return fmt.Errorf("destination path security validation failed: %w", err)
+		}
+		entryInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			if err := p.validator.ValidateFileSize(entryInfo.Size()); err != nil {
+				return fmt.Errorf("file size validation failed for %s: %w", path, err)
+			}
+		}
+		entries = append(entries, copyEntry{src: path, dst: target, mode: entryInfo.Mode(), isDir: entry.IsDir(), size: entryInfo.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// copyFile copies the content and permissions of a regular file, replacing the destination
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
@@ -16,8 +16,12 @@ type FileSecurityValidator struct {
 	whitelistedExtensions []string
 	// Maximum file size allowed for operations (in bytes)
 	maxFileSize int64
-	// Read-only mode - if true, write operations are blocked
+	// Read-only mode - if true, write operations are blocked outside the profiles that allow them
 	readOnly bool
+	// Per-directory access profiles, matched by the longest path
+	profiles []DirectoryProfile
+	// Absolute roots of the workspaces, under which resolved absolute paths are accepted
+	workspaceRoots []string
 	// Mutex for thread safety
 	mu sync.RWMutex
 }
@@ -69,14 +73,47 @@ func (v *FileSecurityValidator) ValidateFileOperation(operation, filePath string
 		return fmt.Errorf("file operation not allowed: file extension not in whitelist")
 	}
 
-	// Check read-only mode for write operations
-	if v.readOnly && (operation == "write" || operation == "create" || operation == "delete" || operation == "rename") {
-		return fmt.Errorf("file operation not allowed: system is in read-only mode")
+	// Check the directory profile, or the read-only mode outside the profiles, of both the path and the
+	// file it resolves to, so a symlink cannot lead into a stricter profile
+	if err := v.checkProfile(operation, filePath, absPath); err != nil {
+		return err
+	}
+	if resolved := resolveExisting(absPath); resolved != absPath {
+		return v.checkProfile(operation, filePath, resolved)
 	}
 
 	return nil
 }
 
+// checkProfile applies the profile of an absolute path, or the read-only mode outside the profiles
+func (v *FileSecurityValidator) checkProfile(operation, filePath, absPath string) error {
+	profile := v.matchProfile(absPath)
+	switch {
+	case profile != nil && profile.Access == AccessDeny:
+		return fmt.Errorf("file operation not allowed: path '%s' is denied by the profile of %s", filePath, profile.Path)
+	case profile != nil && profile.Access == AccessReadOnly && isWriteOperation(operation):
+		return fmt.Errorf("file operation not allowed: %s is read-only", profile.Path)
+	case profile == nil && v.readOnly && isWriteOperation(operation):
+		return fmt.Errorf("file operation not allowed: system is in read-only mode")
+	}
+	return nil
+}
+
+// resolveExisting resolves the symlinks of an absolute path; for a path that does not exist yet, those of
+// its closest existing parent
+func resolveExisting(absPath string) string {
+	rest := ""
+	for dir := absPath; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		if filepath.Dir(dir) == dir {
+			return absPath
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+	}
+}
+
 // ValidateFileSize validates if a file size is within allowed limits
 func (v *FileSecurityValidator) ValidateFileSize(size int64) error {
 	v.mu.RLock()
@@ -88,6 +125,56 @@ func (v *FileSecurityValidator) ValidateFileSize(size int64) error {
 	return nil
 }
 
+// IsPathWhitelisted reports whether a path is within the whitelisted directories, for providers
+// that accept absolute paths under their own roots (such as repositories)
+func (v *FileSecurityValidator) IsPathWhitelisted(path string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.isPathWhitelisted(path)
+}
+
+// ResolveWhitelisted validates a file for reading and returns its location with symlinks resolved, and
+// its size. Symlinks are resolved so a link inside a whitelisted directory cannot point outside it.
+func (v *FileSecurityValidator) ResolveWhitelisted(path string) (string, int64, error) {
+	if err := v.ValidateFileOperation("read", path); err != nil {
+		return "", 0, fmt.Errorf("security validation failed: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if !v.IsPathWhitelisted(resolved) {
+		return "", 0, fmt.Errorf("path %s is outside the allowed directories", path)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get file info: %w", err)
+	}
+	if info.IsDir() {
+		return "", 0, fmt.Errorf("%s is a directory", path)
+	}
+	if err := v.ValidateFileSize(info.Size()); err != nil {
+		return "", 0, fmt.Errorf("file size validation failed: %w", err)
+	}
+	return resolved, info.Size(), nil
+}
+
+// ReadWhitelisted reads a file that ResolveWhitelisted accepts; maxBytes, when set, lowers the size limit
+func (v *FileSecurityValidator) ReadWhitelisted(path string, maxBytes int64) ([]byte, error) {
+	resolved, size, err := v.ResolveWhitelisted(path)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes > 0 && size > maxBytes {
+		return nil, fmt.Errorf("file size (%d bytes) exceeds maximum allowed size (%d bytes)", size, maxBytes)
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
 // isPathWhitelisted checks if a path is within whitelisted directories
 func (v *FileSecurityValidator) isPathWhitelisted(path string) bool {
 	// Convert path to absolute if it's not already
@@ -120,8 +207,9 @@ func (v *FileSecurityValidator) isPathWhitelisted(path string) bool {
 
 // hasDangerousPatterns checks for dangerous path patterns
 func (v *FileSecurityValidator) hasDangerousPatterns(path string) bool {
-	// Check for absolute paths (already handled by filepath.Abs, but double-checking)
-	if filepath.IsAbs(path) {
+	// Check for absolute paths (already handled by filepath.Abs, but double-checking); only paths
+	// resolved under a workspace root may be absolute
+	if filepath.IsAbs(path) && !v.isWorkspacePath(path) {
 		return true
 	}
 
@@ -197,6 +285,13 @@ func (v *FileSecurityValidator) SetWhitelistedExtensions(extensions []string) {
 	v.whitelistedExtensions = extensions
 }
 
+// MaxFileSize returns the maximum allowed file size, which also bounds ranged reads
+func (v *FileSecurityValidator) MaxFileSize() int64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.maxFileSize
+}
+
 // AddWhitelistedDirectory adds a directory to the whitelist
 func (v *FileSecurityValidator) AddWhitelistedDirectory(dir string) {
 	v.mu.Lock()
@@ -204,17 +299,37 @@ func (v *FileSecurityValidator) AddWhitelistedDirectory(dir string) {
 	v.whitelistedDirs = append(v.whitelistedDirs, dir)
 }
 
+// AddWorkspaceRoot whitelists the root of a workspace and accepts absolute paths under it
+func (v *FileSecurityValidator) AddWorkspaceRoot(root string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.whitelistedDirs = append(v.whitelistedDirs, root)
+	v.workspaceRoots = append(v.workspaceRoots, root)
+}
+
+// isWorkspacePath checks if an absolute path is a workspace root or lies under one
+func (v *FileSecurityValidator) isWorkspacePath(path string) bool {
+	for _, root := range v.workspaceRoots {
+		rel, err := filepath.Rel(root, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetSecurityStatus returns the current security status
 func (v *FileSecurityValidator) GetSecurityStatus() map[string]interface{} {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
 	return map[string]interface{}{
-		"readonly":            v.readOnly,
-		"max_file_size":       v.maxFileSize,
-		"whitelisted_dirs":    v.whitelistedDirs,
-		"whitelisted_exts":    v.whitelistedExtensions,
-		"dangerous_patterns":  []string{"..", "\\x00", "system directories"},
+		"readonly":           v.readOnly,
+		"max_file_size":      v.maxFileSize,
+		"whitelisted_dirs":   v.whitelistedDirs,
+		"whitelisted_exts":   v.whitelistedExtensions,
+		"workspace_roots":    v.workspaceRoots,
+		"dangerous_patterns": []string{"..", "\\x00", "system directories"},
 	}
 
-}
\ No newline at end of file
+}
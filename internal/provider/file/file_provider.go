@@ -5,12 +5,16 @@ import (
 	"dev-mcp/entity"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
 )
 
 // FileInfo represents file information
@@ -27,27 +31,37 @@ type FileInfo struct {
 
 // FileProvider provides file system functionality
 type FileProvider struct {
+	*provider.BaseProvider
 	allowedDirs []string
-	readOnly    bool
 	validator   *FileSecurityValidator
+	workspaces  map[string]string // Workspace name to absolute root
 }
 
-// NewFileProvider creates a new File provider with server
-func NewFileProvider(server *mcp.Server) *FileProvider {
+// NewFileProvider creates a new File provider limited to the working directory and the configured
+// workspaces, with the access of its directory profiles
+func NewFileProvider(cfg *config.FileConfig) *FileProvider {
 	// Create file security validator with default whitelisted directories
 	validator := NewFileSecurityValidator([]string{"."})
 
-	// Set read-only mode based on provider setting
-	validator.SetReadOnly(true)
-
 	p := &FileProvider{
-		allowedDirs: []string{"."}, // 默认允许当前目录
-		readOnly:    true,          // 默认只读模式
-		validator:   validator,
+		BaseProvider: provider.NewBaseProvider("file"),
+		allowedDirs:  []string{"."}, // Current directory by default
+		validator:    validator,
+		workspaces:   make(map[string]string),
 	}
 
-	// Add tools to server immediately
-	p.addToolsToServer(server)
+	// Workspace roots are whitelisted first so that profiles may cover them
+	p.addWorkspaces(cfg.Workspaces)
+
+	// Read-only by default outside the profiles
+	validator.SetReadOnly(cfg.ReadOnly == nil || *cfg.ReadOnly)
+	for _, profile := range cfg.Profiles {
+		if err := validator.SetProfile(profile.Path, profile.Access); err != nil {
+			log.Printf("⚠ Skipping file profile %s: %v", profile.Path, err)
+		}
+	}
+
+	p.SetAvailable(true)
 	log.Printf("✓ File provider initialized successfully")
 
 	return p
@@ -60,8 +74,7 @@ func (p *FileProvider) Test(config interface{}) error {
 }
 
 // AddTools adds File tools to the MCP server (for ProviderClient interface compatibility)
-func (p *FileProvider) AddTools(server *mcp.Server) error {
-	// Tools are already added in constructor, but we can call addToolsToServer again if needed
+func (p *FileProvider) AddTools(server *mcp.Server, config interface{}) error {
 	p.addToolsToServer(server)
 	return nil
 }
@@ -79,6 +92,12 @@ func (p *FileProvider) addToolsToServer(server *mcp.Server) {
 		{p.createFileDeleteTool().Tool, p.createFileDeleteTool().Handler},
 		{p.createFileInfoTool().Tool, p.createFileInfoTool().Handler},
 		{p.createFileRenameTool().Tool, p.createFileRenameTool().Handler},
+		{p.createFileCopyTool().Tool, p.createFileCopyTool().Handler},
+		{p.createFileMkdirTool().Tool, p.createFileMkdirTool().Handler},
+		{p.createFilePatchTool().Tool, p.createFilePatchTool().Handler},
+		{p.createFileSearchTool().Tool, p.createFileSearchTool().Handler},
+		{p.createFileSecurityTool().Tool, p.createFileSecurityTool().Handler},
+		{p.createWorkspaceListTool().Tool, p.createWorkspaceListTool().Handler},
 	}
 
 	for _, tool := range tools {
@@ -95,22 +114,101 @@ func (p *FileProvider) Close() error {
 	return nil
 }
 
-// validateWriteOperation validates if a write operation is allowed
-func (p *FileProvider) validateWriteOperation() error {
-	if p.readOnly {
-		return fmt.Errorf("file system is in read-only mode")
+// ReadFile reads a whole file with the same checks as file_read, for providers that consume files
+func (p *FileProvider) ReadFile(path string) ([]byte, error) {
+	if err := p.validator.ValidateFileOperation("read", path); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	if err := p.validator.ValidateFileSize(info.Size()); err != nil {
+		return nil, fmt.Errorf("file size validation failed: %w", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content, nil
+}
+
+// WriteFile writes content to a file with the same checks as file_write, for providers that produce files
+func (p *FileProvider) WriteFile(path string, content []byte, createDirs bool) error {
+	if err := p.validator.ValidateFileOperation("write", path); err != nil {
+		return fmt.Errorf("security validation failed: %w", err)
+	}
+	if err := p.validator.ValidateFileSize(int64(len(content))); err != nil {
+		return fmt.Errorf("file size validation failed: %w", err)
+	}
+
+	if createDirs {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directories: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
 	}
 	return nil
 }
 
+// Stat returns the information of a file or directory that passes read validation, for watchers and resources
+func (p *FileProvider) Stat(path string) (os.FileInfo, error) {
+	if err := p.validator.ValidateFileOperation("read", path); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	return os.Stat(path)
+}
+
+// ListFiles returns up to limit regular files under root that pass read validation, skipping .git and
+// node_modules, for providers that index source trees. The second result reports whether the limit was hit.
+func (p *FileProvider) ListFiles(root string, limit int) ([]string, bool, error) {
+	if err := p.validator.ValidateFileOperation("read", root); err != nil {
+		return nil, false, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	var files []string
+	truncated := false
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip entries that cannot be read
+			return nil
+		}
+		if entry.IsDir() {
+			if (entry.Name() == ".git" || entry.Name() == "node_modules") && filePath != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || p.validator.ValidateFileOperation("read", filePath) != nil {
+			return nil
+		}
+		if len(files) >= limit {
+			truncated = true
+			return filepath.SkipAll
+		}
+		files = append(files, filePath)
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list files: %w", err)
+	}
+	return files, truncated, nil
+}
+
 // createFileReadTool creates the file read tool
 func (p *FileProvider) createFileReadTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
 		Name:        "file_read",
-		Description: "Read file contents with security validation",
+		Description: "Read file contents with security validation. Files over the size limit (1MB) can be read in chunks with offset/length, start_line/end_line or tail; the response reports the total size and whether more data remains",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
 				"path": {
 					"type": "string",
 					"description": "File path to read"
@@ -119,6 +217,26 @@ func (p *FileProvider) createFileReadTool() entity.ToolDefinition {
 					"type": "string",
 					"description": "File encoding (default: utf-8)",
 					"default": "utf-8"
+				},
+				"offset": {
+					"type": "integer",
+					"description": "Byte offset to start reading from (use next_offset of the previous chunk to continue)"
+				},
+				"length": {
+					"type": "integer",
+					"description": "Maximum number of bytes to read from offset (default and max: 1MB)"
+				},
+				"start_line": {
+					"type": "integer",
+					"description": "First line to read (1-based)"
+				},
+				"end_line": {
+					"type": "integer",
+					"description": "Last line to read (inclusive); omit to read until the size limit"
+				},
+				"tail": {
+					"type": "integer",
+					"description": "Read only the last N lines"
 				}
 			},
 			"required": ["path"]
@@ -127,14 +245,26 @@ func (p *FileProvider) createFileReadTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
-			Path     string `json:"path"`
-			Encoding string `json:"encoding,omitempty"`
+			Workspace string `json:"workspace,omitempty"`
+			Path      string `json:"path"`
+			Encoding  string `json:"encoding,omitempty"`
+			Offset    *int64 `json:"offset,omitempty"`
+			Length    int    `json:"length,omitempty"`
+			StartLine int    `json:"start_line,omitempty"`
+			EndLine   int    `json:"end_line,omitempty"`
+			Tail      int    `json:"tail,omitempty"`
 		}
 
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
 		}
 
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
 		if args.Path == "" {
 			return p.createErrorResult(fmt.Errorf("path parameter is required")), nil
 		}
@@ -143,6 +273,19 @@ func (p *FileProvider) createFileReadTool() entity.ToolDefinition {
 			args.Encoding = "utf-8"
 		}
 
+		byteRange := args.Offset != nil || args.Length > 0
+		lineRange := args.StartLine > 0 || args.EndLine > 0
+		tail := args.Tail > 0
+		modes := 0
+		for _, set := range []bool{byteRange, lineRange, tail} {
+			if set {
+				modes++
+			}
+		}
+		if modes > 1 {
+			return p.createErrorResult(fmt.Errorf("use only one of offset/length, start_line/end_line or tail")), nil
+		}
+
 		// Security validation using FileSecurityValidator
 		if err := p.validator.ValidateFileOperation("read", args.Path); err != nil {
 			return p.createErrorResult(fmt.Errorf("security validation failed: %w", err)), nil
@@ -157,16 +300,20 @@ func (p *FileProvider) createFileReadTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("failed to get file info: %w", err)), nil
 		}
 
-		// Validate file size using FileSecurityValidator
-		if err := p.validator.ValidateFileSize(info.Size()); err != nil {
-			return p.createErrorResult(fmt.Errorf("file size validation failed: %w", err)), nil
-		}
-
 		// Check if it's a directory
 		if info.IsDir() {
 			return p.createErrorResult(fmt.Errorf("path is a directory, not a file: %s", args.Path)), nil
 		}
 
+		if modes == 1 {
+			return p.readRange(args.Path, info, args.Offset, args.Length, args.StartLine, args.EndLine, args.Tail, args.Encoding), nil
+		}
+
+		// Validate file size using FileSecurityValidator
+		if err := p.validator.ValidateFileSize(info.Size()); err != nil {
+			return p.createErrorResult(fmt.Errorf("file size validation failed: %w (read it in chunks with offset/length, start_line/end_line or tail)", err)), nil
+		}
+
 		// Read file
 		content, err := os.ReadFile(args.Path)
 		if err != nil {
@@ -182,6 +329,7 @@ func (p *FileProvider) createFileReadTool() entity.ToolDefinition {
 			"path":     args.Path,
 			"content":  string(content),
 			"size":     len(content),
+			"sha256":   contentHash(content), // The base_sha256 of a file_patch
 			"encoding": args.Encoding,
 			"mod_time": info.ModTime(),
 		}
@@ -192,6 +340,60 @@ func (p *FileProvider) createFileReadTool() entity.ToolDefinition {
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
 
+// readRange serves a chunk of a file; chunks are bounded by the validator's max file size
+func (p *FileProvider) readRange(path string, info os.FileInfo, offset *int64, length, startLine, endLine, tail int, encoding string) *mcp.CallToolResult {
+	maxBytes := int(p.validator.MaxFileSize())
+	if length <= 0 || length > maxBytes {
+		length = maxBytes
+	}
+
+	var (
+		chunk *ReadChunk
+		err   error
+	)
+	switch {
+	case tail > 0:
+		chunk, err = readTail(path, tail, info.Size(), maxBytes)
+	case startLine > 0 || endLine > 0:
+		chunk, err = readLineRange(path, startLine, endLine, maxBytes)
+	default:
+		var start int64
+		if offset != nil {
+			start = *offset
+		}
+		chunk, err = readByteRange(path, start, length, info.Size())
+	}
+	if err != nil {
+		return p.createErrorResult(err)
+	}
+
+	result := map[string]interface{}{
+		"path":     path,
+		"content":  chunk.Content,
+		"size":     info.Size(),
+		"offset":   chunk.Offset,
+		"length":   len(chunk.Content),
+		"has_more": chunk.HasMore,
+		"encoding": encoding,
+		"mod_time": info.ModTime(),
+	}
+	if chunk.HasMore {
+		result["next_offset"] = chunk.NextOffset
+	}
+	if chunk.EndLine > 0 {
+		result["start_line"] = chunk.StartLine
+		result["end_line"] = chunk.EndLine
+		if chunk.HasMore {
+			result["next_line"] = chunk.EndLine + 1
+		}
+	}
+	if tail > 0 {
+		result["tail"] = tail
+	}
+
+	return p.formatJSONResult(result)
+}
+
 // createFileWriteTool creates the file write tool
 func (p *FileProvider) createFileWriteTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
@@ -200,6 +402,10 @@ func (p *FileProvider) createFileWriteTool() entity.ToolDefinition {
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
 				"path": {
 					"type": "string",
 					"description": "Path to the file to write"
@@ -225,6 +431,7 @@ func (p *FileProvider) createFileWriteTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
+			Workspace  string `json:"workspace,omitempty"`
 			Path       string `json:"path"`
 			Content    string `json:"content"`
 			Append     bool   `json:"append,omitempty"`
@@ -235,16 +442,17 @@ func (p *FileProvider) createFileWriteTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
 		}
 
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
 		// Security validation using FileSecurityValidator
 		if err := p.validator.ValidateFileOperation("write", args.Path); err != nil {
 			return p.createErrorResult(fmt.Errorf("security validation failed: %w", err)), nil
 		}
 
-		// Validate write operation
-		if err := p.validateWriteOperation(); err != nil {
-			return p.createErrorResult(fmt.Errorf("write operation not allowed: %w", err)), nil
-		}
-
 		// Validate file size using FileSecurityValidator
 		if err := p.validator.ValidateFileSize(int64(len(args.Content))); err != nil {
 			return p.createErrorResult(fmt.Errorf("file size validation failed: %w", err)), nil
@@ -259,7 +467,6 @@ func (p *FileProvider) createFileWriteTool() entity.ToolDefinition {
 		}
 
 		// Write file
-		var err error
 		if args.Append {
 			file, err := os.OpenFile(args.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 			if err != nil {
@@ -306,6 +513,10 @@ func (p *FileProvider) createFileListTool() entity.ToolDefinition {
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
 				"path": {
 					"type": "string",
 					"description": "Directory path to list",
@@ -326,6 +537,7 @@ func (p *FileProvider) createFileListTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
+			Workspace string `json:"workspace,omitempty"`
 			Path      string `json:"path,omitempty"`
 			Pattern   string `json:"pattern,omitempty"`
 			Recursive bool   `json:"recursive,omitempty"`
@@ -335,6 +547,12 @@ func (p *FileProvider) createFileListTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
 		}
 
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
 		if args.Path == "" {
 			args.Path = "."
 		}
@@ -468,6 +686,151 @@ func (p *FileProvider) createFileListTool() entity.ToolDefinition {
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
 
+// createFileSearchTool creates the file content search tool
+func (p *FileProvider) createFileSearchTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "file_search",
+		Description: "Search file contents (grep) under a directory with security validation. Returns matching path, line, column and snippet, with optional context lines",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
+				"pattern": {
+					"type": "string",
+					"description": "Regular expression (RE2 syntax) or literal text to search for"
+				},
+				"path": {
+					"type": "string",
+					"description": "Directory (or file) to search",
+					"default": "."
+				},
+				"literal": {
+					"type": "boolean",
+					"description": "Treat pattern as literal text instead of a regular expression",
+					"default": false
+				},
+				"case_sensitive": {
+					"type": "boolean",
+					"description": "Match case exactly",
+					"default": true
+				},
+				"include": {
+					"type": "string",
+					"description": "Only search files whose name matches this glob (e.g. *.go)"
+				},
+				"max_matches": {
+					"type": "integer",
+					"description": "Maximum number of matches to return (max 1000)",
+					"default": 100
+				},
+				"context_lines": {
+					"type": "integer",
+					"description": "Lines of context before and after each match (max 10)",
+					"default": 0
+				},
+				"skip_binary": {
+					"type": "boolean",
+					"description": "Skip binary files",
+					"default": true
+				}
+			},
+			"required": ["pattern"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Workspace     string `json:"workspace,omitempty"`
+			Pattern       string `json:"pattern"`
+			Path          string `json:"path,omitempty"`
+			Literal       bool   `json:"literal,omitempty"`
+			CaseSensitive bool   `json:"case_sensitive"`
+			Include       string `json:"include,omitempty"`
+			MaxMatches    int    `json:"max_matches,omitempty"`
+			ContextLines  int    `json:"context_lines,omitempty"`
+			SkipBinary    bool   `json:"skip_binary"`
+		}{CaseSensitive: true, SkipBinary: true}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
+		if args.Pattern == "" {
+			return p.createErrorResult(fmt.Errorf("pattern parameter is required")), nil
+		}
+		if args.Path == "" {
+			args.Path = "."
+		}
+		if args.MaxMatches <= 0 {
+			args.MaxMatches = defaultSearchMatches
+		}
+		if args.MaxMatches > maxSearchMatches {
+			args.MaxMatches = maxSearchMatches
+		}
+		if args.ContextLines < 0 {
+			args.ContextLines = 0
+		}
+		if args.ContextLines > maxContextLines {
+			args.ContextLines = maxContextLines
+		}
+		if args.Include != "" {
+			if _, err := filepath.Match(args.Include, ""); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid include glob: %w", err)), nil
+			}
+		}
+
+		// Security validation using FileSecurityValidator
+		if err := p.validator.ValidateFileOperation("read", args.Path); err != nil {
+			return p.createErrorResult(fmt.Errorf("security validation failed: %w", err)), nil
+		}
+
+		if _, err := os.Stat(args.Path); err != nil {
+			if os.IsNotExist(err) {
+				return p.createErrorResult(fmt.Errorf("path does not exist: %s", args.Path)), nil
+			}
+			return p.createErrorResult(fmt.Errorf("failed to get path info: %w", err)), nil
+		}
+
+		search, err := p.searchFiles(ctx, args.Path, SearchOptions{
+			Pattern:       args.Pattern,
+			Literal:       args.Literal,
+			CaseSensitive: args.CaseSensitive,
+			Include:       args.Include,
+			MaxMatches:    args.MaxMatches,
+			ContextLines:  args.ContextLines,
+			SkipBinary:    args.SkipBinary,
+		})
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("search failed: %w", err)), nil
+		}
+
+		result := map[string]interface{}{
+			"path":              args.Path,
+			"pattern":           args.Pattern,
+			"matches":           search.Matches,
+			"count":             len(search.Matches),
+			"files_scanned":     search.FilesScanned,
+			"files_matched":     search.FilesMatched,
+			"skipped_binary":    search.SkippedBinary,
+			"skipped_too_large": search.SkippedTooBig,
+			"truncated":         search.Truncated,
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
 // createFileDeleteTool creates the file delete tool
 func (p *FileProvider) createFileDeleteTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
@@ -476,6 +839,10 @@ func (p *FileProvider) createFileDeleteTool() entity.ToolDefinition {
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
 				"path": {
 					"type": "string",
 					"description": "Path to the file or directory to delete"
@@ -492,6 +859,7 @@ func (p *FileProvider) createFileDeleteTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
+			Workspace string `json:"workspace,omitempty"`
 			Path      string `json:"path"`
 			Recursive bool   `json:"recursive,omitempty"`
 		}
@@ -500,16 +868,17 @@ func (p *FileProvider) createFileDeleteTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
 		}
 
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
 		// Security validation using FileSecurityValidator
 		if err := p.validator.ValidateFileOperation("delete", args.Path); err != nil {
 			return p.createErrorResult(fmt.Errorf("security validation failed: %w", err)), nil
 		}
 
-		// Validate write operation
-		if err := p.validateWriteOperation(); err != nil {
-			return p.createErrorResult(fmt.Errorf("delete operation not allowed: %w", err)), nil
-		}
-
 		// Check if file exists
 		info, err := os.Stat(args.Path)
 		if os.IsNotExist(err) {
@@ -554,6 +923,10 @@ func (p *FileProvider) createFileInfoTool() entity.ToolDefinition {
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
 				"path": {
 					"type": "string",
 					"description": "Path to the file or directory"
@@ -565,13 +938,20 @@ func (p *FileProvider) createFileInfoTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
-			Path string `json:"path"`
+			Workspace string `json:"workspace,omitempty"`
+			Path      string `json:"path"`
 		}
 
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
 		}
 
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
 		// Security validation using FileSecurityValidator
 		if err := p.validator.ValidateFileOperation("read", args.Path); err != nil {
 			return p.createErrorResult(fmt.Errorf("security validation failed: %w", err)), nil
@@ -616,6 +996,10 @@ func (p *FileProvider) createFileRenameTool() entity.ToolDefinition {
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
 				"old_path": {
 					"type": "string",
 					"description": "Current path of the file or directory"
@@ -631,16 +1015,25 @@ func (p *FileProvider) createFileRenameTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
-			OldPath string `json:"old_path"`
-			NewPath string `json:"new_path"`
+			Workspace string `json:"workspace,omitempty"`
+			OldPath   string `json:"old_path"`
+			NewPath   string `json:"new_path"`
 		}
 
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
 		}
 
+		var err error
+		if args.OldPath, err = p.resolvePath(args.Workspace, args.OldPath); err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if args.NewPath, err = p.resolvePath(args.Workspace, args.NewPath); err != nil {
+			return p.createErrorResult(err), nil
+		}
+
 		// Security validation for both paths using FileSecurityValidator
-		if err := p.validator.ValidateFileOperation("read", args.OldPath); err != nil {
+		if err := p.validator.ValidateFileOperation("rename", args.OldPath); err != nil {
 			return p.createErrorResult(fmt.Errorf("source path security validation failed: %w", err)), nil
 		}
 
@@ -648,11 +1041,6 @@ func (p *FileProvider) createFileRenameTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("destination path security validation failed: %w", err)), nil
 		}
 
-		// Validate write operation
-		if err := p.validateWriteOperation(); err != nil {
-			return p.createErrorResult(fmt.Errorf("rename operation not allowed: %w", err)), nil
-		}
-
 		// Check if source exists
 		info, err := os.Stat(args.OldPath)
 		if os.IsNotExist(err) {
@@ -682,6 +1070,241 @@ func (p *FileProvider) createFileRenameTool() entity.ToolDefinition {
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
 
+// createFileCopyTool creates the file copy tool
+func (p *FileProvider) createFileCopyTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "file_copy",
+		Description: "Copy a file, or a directory recursively, with security validation. Every file is checked before anything is written",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
+				"source": {
+					"type": "string",
+					"description": "Path of the file or directory to copy"
+				},
+				"destination": {
+					"type": "string",
+					"description": "Path of the copy"
+				},
+				"recursive": {
+					"type": "boolean",
+					"description": "Whether to copy directories with their contents (default: false)",
+					"default": false
+				},
+				"overwrite": {
+					"type": "boolean",
+					"description": "Whether to replace an existing destination; directories are merged file by file (default: false)",
+					"default": false
+				}
+			},
+			"required": ["source", "destination"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Workspace   string `json:"workspace,omitempty"`
+			Source      string `json:"source"`
+			Destination string `json:"destination"`
+			Recursive   bool   `json:"recursive,omitempty"`
+			Overwrite   bool   `json:"overwrite,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		var err error
+		if args.Source, err = p.resolvePath(args.Workspace, args.Source); err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if args.Destination, err = p.resolvePath(args.Workspace, args.Destination); err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result, err := p.Copy(args.Source, args.Destination, args.Recursive, args.Overwrite)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createFileMkdirTool creates the directory creation tool
+func (p *FileProvider) createFileMkdirTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "file_mkdir",
+		Description: "Create a directory with security validation",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
+				"path": {
+					"type": "string",
+					"description": "Path of the directory to create"
+				},
+				"parents": {
+					"type": "boolean",
+					"description": "Whether to create missing parent directories and accept an existing directory, like mkdir -p (default: false)",
+					"default": false
+				}
+			},
+			"required": ["path"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Workspace string `json:"workspace,omitempty"`
+			Path      string `json:"path"`
+			Parents   bool   `json:"parents,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
+		// Security validation using FileSecurityValidator
+		if err := p.validator.ValidateFileOperation("create", args.Path); err != nil {
+			return p.createErrorResult(fmt.Errorf("security validation failed: %w", err)), nil
+		}
+
+		existed := false
+		if info, err := os.Stat(args.Path); err == nil {
+			if !info.IsDir() {
+				return p.createErrorResult(fmt.Errorf("path exists and is not a directory: %s", args.Path)), nil
+			}
+			if !args.Parents {
+				return p.createErrorResult(fmt.Errorf("directory already exists: %s", args.Path)), nil
+			}
+			existed = true
+		}
+
+		if args.Parents {
+			err = os.MkdirAll(args.Path, 0755)
+		} else {
+			err = os.Mkdir(args.Path, 0755)
+		}
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("failed to create directory: %w", err)), nil
+		}
+
+		result := map[string]interface{}{
+			"path":    args.Path,
+			"created": !existed,
+			"parents": args.Parents,
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createFilePatchTool creates the file patch tool
+func (p *FileProvider) createFilePatchTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "file_patch",
+		Description: "Edit part of a file with a unified diff or a list of line edits instead of writing it back whole. " +
+			"Pass the sha256 returned by file_read as base_sha256 to fail if the file changed since; the patched file replaces the original atomically and its new sha256 is returned",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"workspace": {
+					"type": "string",
+					"description": "Workspace the paths are relative to (see workspace_list; default: the working directory)"
+				},
+				"path": {
+					"type": "string",
+					"description": "Path to the file to patch"
+				},
+				"base_sha256": {
+					"type": "string",
+					"description": "SHA-256 of the content the patch was made against, as returned by file_read or a previous file_patch"
+				},
+				"diff": {
+					"type": "string",
+					"description": "Unified diff of this file (hunks starting with @@ -start,count +start,count @@); hunks whose lines moved are found near their position"
+				},
+				"edits": {
+					"type": "array",
+					"description": "Line edits of the original file, instead of diff; they may not overlap",
+					"items": {
+						"type": "object",
+						"properties": {
+							"start_line": {
+								"type": "integer",
+								"description": "First line to replace (1-based)"
+							},
+							"end_line": {
+								"type": "integer",
+								"description": "Last line to replace, inclusive; start_line-1 inserts before start_line"
+							},
+							"replacement": {
+								"type": "string",
+								"description": "New text of the lines; empty deletes them"
+							}
+						},
+						"required": ["start_line", "end_line", "replacement"]
+					}
+				},
+				"dry_run": {
+					"type": "boolean",
+					"description": "Check that the patch applies and return the resulting hash without writing (default: false)",
+					"default": false
+				}
+			},
+			"required": ["path"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Workspace  string      `json:"workspace,omitempty"`
+			Path       string      `json:"path"`
+			BaseSHA256 string      `json:"base_sha256,omitempty"`
+			Diff       string      `json:"diff,omitempty"`
+			Edits      []PatchEdit `json:"edits,omitempty"`
+			DryRun     bool        `json:"dry_run,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		path, err := p.resolvePath(args.Workspace, args.Path)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		args.Path = path
+
+		result, err := p.Patch(args.Path, args.BaseSHA256, args.Diff, args.Edits, args.DryRun)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
 // Helper functions
 func (p *FileProvider) createErrorResult(err error) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
@@ -700,3 +1323,6 @@ func (p *FileProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
 		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
 	}
 }
+
+// Verify that FileProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*FileProvider)(nil)
@@ -0,0 +1,305 @@
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PatchEdit replaces the lines start_line..end_line (1-based, inclusive) with replacement. An end_line of
+// start_line-1 inserts before start_line, and an empty replacement deletes the lines.
+type PatchEdit struct {
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	Replacement string `json:"replacement"`
+}
+
+// PatchResult describes an applied (or, for a dry run, checked) patch
+type PatchResult struct {
+	Path         string `json:"path"`
+	BaseSHA256   string `json:"base_sha256"`
+	SHA256       string `json:"sha256"` // Hash of the patched content, the base of the next patch
+	Size         int    `json:"size"`
+	Changes      int    `json:"changes"` // Hunks or edits applied
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	DryRun       bool   `json:"dry_run,omitempty"`
+}
+
+// hunkHeader matches "@@ -start,count +start,count @@"; counts default to 1
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// diffHunk is a hunk of a unified diff: the lines it expects and the lines that replace them
+type diffHunk struct {
+	oldStart int
+	oldLines []string
+	newLines []string
+	added    int
+	removed  int
+}
+
+// contentHash returns the hex SHA-256 of file content, as reported by file_read and checked by file_patch
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fileLines splits content into lines without their newlines and reports whether the last line had one
+func fileLines(content string) ([]string, bool) {
+	if content == "" {
+		return nil, false
+	}
+	lines := strings.Split(content, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1], true
+	}
+	return lines, false
+}
+
+// Patch applies a unified diff or a list of line edits to a file. When baseHash is set the file must still
+// have that content hash. The result is written to a temporary file that replaces the original, so readers
+// never see a partial write.
+func (p *FileProvider) Patch(path, baseHash, diff string, edits []PatchEdit, dryRun bool) (*PatchResult, error) {
+	if (diff == "") == (len(edits) == 0) {
+		return nil, fmt.Errorf("provide either diff or edits")
+	}
+	if err := p.validator.ValidateFileOperation("write", path); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file does not exist: %s", path)
+		}
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("path is a directory, not a file: %s", path)
+	}
+	if err := p.validator.ValidateFileSize(info.Size()); err != nil {
+		return nil, fmt.Errorf("file size validation failed: %w", err)
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	result := &PatchResult{Path: path, BaseSHA256: contentHash(original), DryRun: dryRun}
+	if baseHash != "" && !strings.EqualFold(baseHash, result.BaseSHA256) {
+		return nil, fmt.Errorf("file changed since it was read: base_sha256 is %s but the file has %s; read it again and rebuild the patch", baseHash, result.BaseSHA256)
+	}
+
+	lines, trailingNewline := fileLines(string(original))
+	if diff != "" {
+		hunks, err := parseUnifiedDiff(diff)
+		if err != nil {
+			return nil, err
+		}
+		if lines, err = applyHunks(lines, hunks, result); err != nil {
+			return nil, err
+		}
+	} else {
+		if lines, err = applyEdits(lines, edits, result); err != nil {
+			return nil, err
+		}
+	}
+
+	patched := strings.Join(lines, "\n")
+	if trailingNewline && len(lines) > 0 {
+		patched += "\n"
+	}
+	if err := p.validator.ValidateFileSize(int64(len(patched))); err != nil {
+		return nil, fmt.Errorf("file size validation failed: %w", err)
+	}
+	result.SHA256 = contentHash([]byte(patched))
+	result.Size = len(patched)
+	if dryRun {
+		return result, nil
+	}
+
+	if err := replaceFile(path, []byte(patched), info.Mode(), result.BaseSHA256); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// replaceFile writes content next to path and renames it over path, unless path no longer has baseHash
+func replaceFile(path string, content []byte, mode os.FileMode, baseHash string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".patch-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(mode.Perm()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	// Check again right before the rename, to catch a write made while the patch was applied
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if contentHash(current) != baseHash {
+		return fmt.Errorf("file changed while the patch was applied; read it again and rebuild the patch")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+// parseUnifiedDiff reads the hunks of a single-file unified diff; file headers are ignored
+func parseUnifiedDiff(diff string) ([]diffHunk, error) {
+	var hunks []diffHunk
+	var current *diffHunk
+	oldLeft, newLeft := 0, 0
+	for number, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		if match := hunkHeader.FindStringSubmatch(line); match != nil {
+			if current != nil && (oldLeft > 0 || newLeft > 0) {
+				return nil, fmt.Errorf("diff line %d: the previous hunk is shorter than its header says", number+1)
+			}
+			start, _ := strconv.Atoi(match[1])
+			oldLeft, newLeft = hunkCount(match[2]), hunkCount(match[4])
+			hunks = append(hunks, diffHunk{oldStart: start})
+			current = &hunks[len(hunks)-1]
+			continue
+		}
+		if current == nil || (oldLeft == 0 && newLeft == 0) {
+			// File headers (---, +++, diff --git, index) and text between hunks
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"; the file's own trailing newline is kept
+		case strings.HasPrefix(line, "+"):
+			current.newLines = append(current.newLines, line[1:])
+			current.added++
+			newLeft--
+		case strings.HasPrefix(line, "-"):
+			current.oldLines = append(current.oldLines, line[1:])
+			current.removed++
+			oldLeft--
+		case strings.HasPrefix(line, " "), line == "":
+			// Some editors strip the space of empty context lines
+			text := strings.TrimPrefix(line, " ")
+			current.oldLines = append(current.oldLines, text)
+			current.newLines = append(current.newLines, text)
+			oldLeft--
+			newLeft--
+		default:
+			return nil, fmt.Errorf("diff line %d: expected ' ', '+' or '-' in a hunk: %q", number+1, line)
+		}
+		if oldLeft < 0 || newLeft < 0 {
+			return nil, fmt.Errorf("diff line %d: the hunk is longer than its header says", number+1)
+		}
+	}
+	if len(hunks) == 0 {
+		return nil, fmt.Errorf("diff has no hunks (@@ -start,count +start,count @@)")
+	}
+	if oldLeft > 0 || newLeft > 0 {
+		return nil, fmt.Errorf("the last hunk is shorter than its header says")
+	}
+	return hunks, nil
+}
+
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// applyHunks applies the hunks in order. A hunk whose lines moved is found by searching outward from its
+// header's position, as patch does; one whose lines are not in the file fails the whole patch.
+func applyHunks(lines []string, hunks []diffHunk, result *PatchResult) ([]string, error) {
+	patched := slices.Clone(lines)
+	shift := 0    // Lines added minus removed by the hunks applied so far
+	minStart := 0 // Hunks apply in order and may not overlap
+	for i, hunk := range hunks {
+		expected := hunk.oldStart - 1 + shift
+		if len(hunk.oldLines) == 0 {
+			// A pure insertion's header names the line it follows
+			expected = hunk.oldStart + shift
+		}
+		at := findLines(patched, hunk.oldLines, expected, minStart)
+		if at < 0 {
+			return nil, fmt.Errorf("hunk %d (@@ -%d) does not match the file: its context or removed lines are not there", i+1, hunk.oldStart)
+		}
+		patched = slices.Replace(patched, at, at+len(hunk.oldLines), hunk.newLines...)
+		shift += len(hunk.newLines) - len(hunk.oldLines)
+		minStart = at + len(hunk.newLines)
+		result.LinesAdded += hunk.added
+		result.LinesRemoved += hunk.removed
+		result.Changes++
+	}
+	return patched, nil
+}
+
+// findLines returns the index at or after minStart where want occurs, closest to expected, or -1
+func findLines(lines, want []string, expected, minStart int) int {
+	matches := func(at int) bool {
+		if at < minStart || at+len(want) > len(lines) {
+			return false
+		}
+		return slices.Equal(lines[at:at+len(want)], want)
+	}
+	expected = max(min(expected, len(lines)), minStart)
+	for distance := 0; expected-distance >= minStart || expected+distance <= len(lines); distance++ {
+		if matches(expected - distance) {
+			return expected - distance
+		}
+		if matches(expected + distance) {
+			return expected + distance
+		}
+	}
+	return -1
+}
+
+// applyEdits applies line edits, which refer to the lines of the original file and may not overlap
+func applyEdits(lines []string, edits []PatchEdit, result *PatchResult) ([]string, error) {
+	sorted := slices.Clone(edits)
+	slices.SortStableFunc(sorted, func(a, b PatchEdit) int { return a.StartLine - b.StartLine })
+	for i, edit := range sorted {
+		if edit.StartLine < 1 || edit.StartLine > len(lines)+1 {
+			return nil, fmt.Errorf("edit at line %d: start_line must be between 1 and %d", edit.StartLine, len(lines)+1)
+		}
+		if edit.EndLine < edit.StartLine-1 || edit.EndLine > len(lines) {
+			return nil, fmt.Errorf("edit at line %d: end_line must be between start_line-1 (an insertion) and %d", edit.StartLine, len(lines))
+		}
+		if i > 0 && edit.StartLine <= sorted[i-1].EndLine {
+			return nil, fmt.Errorf("edits at lines %d and %d overlap", sorted[i-1].StartLine, edit.StartLine)
+		}
+	}
+
+	patched := slices.Clone(lines)
+	// From the bottom up, so the line numbers of the remaining edits stay valid
+	for i := len(sorted) - 1; i >= 0; i-- {
+		edit := sorted[i]
+		var replacement []string
+		if edit.Replacement != "" {
+			replacement = strings.Split(strings.TrimSuffix(edit.Replacement, "\n"), "\n")
+		}
+		patched = slices.Replace(patched, edit.StartLine-1, edit.EndLine, replacement...)
+		result.LinesRemoved += edit.EndLine - edit.StartLine + 1
+		result.LinesAdded += len(replacement)
+		result.Changes++
+	}
+	return patched, nil
+}
@@ -0,0 +1,226 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// Access levels of a directory profile
+const (
+	AccessReadWrite = "read-write"
+	AccessReadOnly  = "read-only"
+	AccessDeny      = "deny" // No operation at all, including reads and listings
+)
+
+// DirectoryProfile sets the access of a directory and everything under it
+type DirectoryProfile struct {
+	Path     string `json:"path"`
+	Access   string `json:"access"`
+	abs      string // Absolute path the profile is matched by
+	resolved string // abs with its symlinks resolved, matched by resolved paths
+}
+
+// isWriteOperation reports whether an operation modifies the file system
+func isWriteOperation(operation string) bool {
+	switch operation {
+	case "write", "create", "delete", "rename":
+		return true
+	}
+	return false
+}
+
+// SetProfile sets the access of a directory, replacing the profile it already has
+func (v *FileSecurityValidator) SetProfile(path, access string) error {
+	switch access {
+	case AccessReadWrite, AccessReadOnly, AccessDeny:
+	default:
+		return fmt.Errorf("invalid access %q: use %s, %s or %s", access, AccessReadWrite, AccessReadOnly, AccessDeny)
+	}
+	if path == "" {
+		return fmt.Errorf("profile path cannot be empty")
+	}
+	abs, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.isPathWhitelisted(abs) {
+		return fmt.Errorf("path '%s' is outside whitelisted directories", path)
+	}
+	profile := DirectoryProfile{Path: path, Access: access, abs: abs, resolved: resolveExisting(abs)}
+	for i := range v.profiles {
+		if v.profiles[i].abs == abs {
+			v.profiles[i] = profile
+			return nil
+		}
+	}
+	v.profiles = append(v.profiles, profile)
+	return nil
+}
+
+// RemoveProfile removes the profile of a directory, which then gets the access of its closest parent profile
+func (v *FileSecurityValidator) RemoveProfile(path string) error {
+	abs, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i := range v.profiles {
+		if v.profiles[i].abs == abs {
+			v.profiles = slices.Delete(v.profiles, i, i+1)
+			return nil
+		}
+	}
+	return fmt.Errorf("no profile for %s", path)
+}
+
+// Profiles returns the directory profiles sorted by path
+func (v *FileSecurityValidator) Profiles() []DirectoryProfile {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	profiles := slices.Clone(v.profiles)
+	slices.SortFunc(profiles, func(a, b DirectoryProfile) int { return strings.Compare(a.abs, b.abs) })
+	return profiles
+}
+
+// Access returns the access of a path and the profile that grants it; profile is empty when no profile
+// matches and the read-only mode decides
+func (v *FileSecurityValidator) Access(path string) (access, profile string, err error) {
+	abs, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if match := v.matchProfile(abs); match != nil {
+		return match.Access, match.Path, nil
+	}
+	if v.readOnly {
+		return AccessReadOnly, "", nil
+	}
+	return AccessReadWrite, "", nil
+}
+
+// matchProfile returns the profile of the closest directory holding an absolute path, as given or with its
+// symlinks resolved, or nil
+func (v *FileSecurityValidator) matchProfile(absPath string) *DirectoryProfile {
+	var match *DirectoryProfile
+	var matched int
+	for i := range v.profiles {
+		profile := &v.profiles[i]
+		for _, dir := range []string{profile.abs, profile.resolved} {
+			rel, err := filepath.Rel(dir, absPath)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			if match == nil || len(dir) > matched {
+				match, matched = profile, len(dir)
+			}
+		}
+	}
+	return match
+}
+
+// createFileSecurityTool creates the file access management tool
+func (p *FileProvider) createFileSecurityTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "file_security",
+		Description: "Manage file access: view the read-only mode and the per-directory profiles, toggle the read-only mode, set or remove the profile of a directory " +
+			"(read-write, read-only or deny; the longest matching path wins) or check the access of a path. Changes last until restart. Requires admin role.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"action": {
+					"type": "string",
+					"description": "Action to perform: 'status', 'enable_writes', 'disable_writes', 'set_profile', 'remove_profile', 'check'",
+					"enum": ["status", "enable_writes", "disable_writes", "set_profile", "remove_profile", "check"]
+				},
+				"path": {
+					"type": "string",
+					"description": "Directory of set_profile and remove_profile, or path of check, e.g. ./docs"
+				},
+				"access": {
+					"type": "string",
+					"description": "Access of set_profile",
+					"enum": ["read-write", "read-only", "deny"]
+				}
+			},
+			"required": ["action"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Action string `json:"action"`
+			Path   string `json:"path,omitempty"`
+			Access string `json:"access,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		log.Printf("Executing file security action: %s %s", args.Action, args.Path)
+
+		switch args.Action {
+		case "status":
+		case "enable_writes":
+			p.validator.SetReadOnly(false)
+		case "disable_writes":
+			p.validator.SetReadOnly(true)
+		case "set_profile":
+			if args.Path == "" || args.Access == "" {
+				return p.createErrorResult(fmt.Errorf("path and access parameters are required")), nil
+			}
+			if err := p.validator.SetProfile(args.Path, args.Access); err != nil {
+				return p.createErrorResult(err), nil
+			}
+		case "remove_profile":
+			if args.Path == "" {
+				return p.createErrorResult(fmt.Errorf("path parameter is required")), nil
+			}
+			if err := p.validator.RemoveProfile(args.Path); err != nil {
+				return p.createErrorResult(err), nil
+			}
+		case "check":
+			if args.Path == "" {
+				return p.createErrorResult(fmt.Errorf("path parameter is required")), nil
+			}
+			access, profile, err := p.validator.Access(args.Path)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			result := map[string]interface{}{
+				"path":     args.Path,
+				"access":   access,
+				"readable": p.validator.ValidateFileOperation("read", args.Path) == nil,
+			}
+			if profile != "" {
+				result["profile"] = profile
+			}
+			return p.formatJSONResult(result), nil
+		default:
+			return p.createErrorResult(fmt.Errorf("unknown action: %s. Available actions: status, enable_writes, disable_writes, set_profile, remove_profile, check", args.Action)), nil
+		}
+
+		status := p.validator.GetSecurityStatus()
+		status["profiles"] = p.validator.Profiles()
+		return p.formatJSONResult(status), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,57 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfilesApplyToSymlinkTargets(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, dir := range []string{"open", "secret", "docs"} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"secret/key.txt", "docs/guide.txt"} {
+		if err := os.WriteFile(file, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"open/key.txt":   "../secret/key.txt",
+		"open/secret":    "../secret",
+		"open/guide.txt": "../docs/guide.txt",
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	v := NewFileSecurityValidator([]string{"."})
+	if err := v.SetProfile("secret", AccessDeny); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.SetProfile("docs", AccessReadOnly); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"open/key.txt", "open/secret/key.txt", "open/secret/new.txt"} {
+		if err := v.ValidateFileOperation("read", path); err == nil {
+			t.Errorf("read of %s through a symlink into a denied directory was allowed", path)
+		}
+	}
+	if _, _, err := v.ResolveWhitelisted("open/key.txt"); err == nil {
+		t.Error("ResolveWhitelisted allowed a symlink into a denied directory")
+	}
+	if err := v.ValidateFileOperation("write", "open/guide.txt"); err == nil {
+		t.Error("write through a symlink into a read-only directory was allowed")
+	}
+	if err := v.ValidateFileOperation("read", "open/guide.txt"); err != nil {
+		t.Errorf("read through a symlink into a read-only directory: %v", err)
+	}
+	if err := v.ValidateFileOperation("write", filepath.Join("open", "notes.txt")); err != nil {
+		t.Errorf("write of a plain file: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// tailBlockSize is how much is read per step when scanning backwards for tail lines
+const tailBlockSize = 64 * 1024
+
+// ReadChunk is a part of a file returned by a ranged read
+type ReadChunk struct {
+	Content    string
+	Offset     int64
+	StartLine  int
+	EndLine    int
+	HasMore    bool
+	NextOffset int64
+}
+
+// readByteRange reads up to length bytes starting at offset
+func readByteRange(path string, offset int64, length int, size int64) (*ReadChunk, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+	if offset > size {
+		return nil, fmt.Errorf("offset %d is beyond the end of the file (%d bytes)", offset, size)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	end := offset + int64(n)
+	return &ReadChunk{
+		Content:    string(buf[:n]),
+		Offset:     offset,
+		HasMore:    end < size,
+		NextOffset: end,
+	}, nil
+}
+
+// readLineRange reads lines startLine..endLine (1-based, inclusive; endLine 0 reads to the end),
+// stopping early once maxBytes would be exceeded
+func readLineRange(path string, startLine, endLine, maxBytes int) (*ReadChunk, error) {
+	if startLine < 1 {
+		startLine = 1
+	}
+	if endLine != 0 && endLine < startLine {
+		return nil, fmt.Errorf("end_line must not be before start_line")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	chunk := &ReadChunk{StartLine: startLine}
+	var content bytes.Buffer
+	var position int64
+	lineNumber := 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNumber++
+			if lineNumber == startLine {
+				chunk.Offset = position
+			}
+			if lineNumber >= startLine {
+				if endLine != 0 && lineNumber > endLine {
+					chunk.HasMore = true
+					break
+				}
+				if content.Len()+len(line) > maxBytes {
+					if content.Len() == 0 {
+						return nil, fmt.Errorf("line %d is longer than the %d byte limit; use offset/length instead", lineNumber, maxBytes)
+					}
+					chunk.HasMore = true
+					break
+				}
+				content.Write(line)
+				chunk.EndLine = lineNumber
+			}
+			position += int64(len(line))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	if lineNumber < startLine {
+		return nil, fmt.Errorf("start_line %d is beyond the end of the file (%d lines)", startLine, lineNumber)
+	}
+
+	chunk.Content = content.String()
+	chunk.NextOffset = chunk.Offset + int64(content.Len())
+	return chunk, nil
+}
+
+// readTail reads the last n lines of a file, keeping at most maxBytes (whole lines only)
+func readTail(path string, n int, size int64, maxBytes int) (*ReadChunk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	// Scan backwards for the start of the n-th line from the end; a trailing newline ends the last line
+	start := int64(0)
+	end := size
+	newlines := 0
+	buf := make([]byte, tailBlockSize)
+scan:
+	for pos := size; pos > 0; {
+		blockStart := pos - tailBlockSize
+		if blockStart < 0 {
+			blockStart = 0
+		}
+		block := buf[:pos-blockStart]
+		if _, err := f.ReadAt(block, blockStart); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		for i := len(block) - 1; i >= 0; i-- {
+			if block[i] != '\n' || blockStart+int64(i) == size-1 {
+				continue
+			}
+			newlines++
+			if newlines == n {
+				start = blockStart + int64(i) + 1
+				break scan
+			}
+		}
+		pos = blockStart
+		if end-pos > int64(maxBytes) {
+			break
+		}
+	}
+
+	// Keep within maxBytes, dropping a partial first line
+	if end-start > int64(maxBytes) {
+		start = end - int64(maxBytes)
+		chunk, err := readByteRange(path, start, maxBytes, size)
+		if err != nil {
+			return nil, err
+		}
+		if i := bytes.IndexByte([]byte(chunk.Content), '\n'); i >= 0 {
+			chunk.Content = chunk.Content[i+1:]
+			chunk.Offset = start + int64(i) + 1
+		}
+		chunk.HasMore = false
+		return chunk, nil
+	}
+
+	return readByteRange(path, start, int(end-start), size)
+}
@@ -0,0 +1,192 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultSearchMatches = 100
+	maxSearchMatches     = 1000
+	maxContextLines      = 10
+	maxSnippetLength     = 500
+	// binarySniffLength is how much of a file is checked for NUL bytes, as git does
+	binarySniffLength = 8000
+)
+
+// SearchOptions controls a content search
+type SearchOptions struct {
+	Pattern       string
+	Literal       bool
+	CaseSensitive bool
+	Include       string
+	MaxMatches    int
+	ContextLines  int
+	SkipBinary    bool
+}
+
+// SearchMatch is a matching line with optional surrounding context
+type SearchMatch struct {
+	Path   string   `json:"path"`
+	Line   int      `json:"line"`
+	Column int      `json:"column"`
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// SearchResult summarises a content search
+type SearchResult struct {
+	Matches       []SearchMatch `json:"matches"`
+	FilesScanned  int           `json:"files_scanned"`
+	FilesMatched  int           `json:"files_matched"`
+	SkippedBinary int           `json:"skipped_binary"`
+	SkippedTooBig int           `json:"skipped_too_large"`
+	Truncated     bool          `json:"truncated"`
+}
+
+// compileSearchPattern builds the matcher for a literal or regular expression pattern
+func compileSearchPattern(opts SearchOptions) (*regexp.Regexp, error) {
+	pattern := opts.Pattern
+	if opts.Literal {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !opts.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// searchFiles greps the files under root that pass security validation
+func (p *FileProvider) searchFiles(ctx context.Context, root string, opts SearchOptions) (*SearchResult, error) {
+	re, err := compileSearchPattern(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SearchResult{Matches: []SearchMatch{}}
+	matchedFiles := map[string]bool{}
+
+	err = filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip entries that cannot be read
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" && filePath != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if opts.Include != "" {
+			if matched, err := filepath.Match(opts.Include, entry.Name()); err != nil || !matched {
+				return nil
+			}
+		}
+		if err := p.validator.ValidateFileOperation("read", filePath); err != nil {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		if err := p.validator.ValidateFileSize(info.Size()); err != nil {
+			result.SkippedTooBig++
+			return nil
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil
+		}
+		if opts.SkipBinary && isBinary(content) {
+			result.SkippedBinary++
+			return nil
+		}
+
+		result.FilesScanned++
+		if searchContent(filePath, content, re, opts, result) {
+			matchedFiles[filePath] = true
+		}
+		if len(result.Matches) >= opts.MaxMatches {
+			result.Truncated = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.FilesMatched = len(matchedFiles)
+	return result, nil
+}
+
+// searchContent appends the matching lines of one file, returning whether any line matched
+func searchContent(filePath string, content []byte, re *regexp.Regexp, opts SearchOptions, result *SearchResult) bool {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	found := false
+	for i, line := range lines {
+		loc := re.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		found = true
+
+		match := SearchMatch{
+			Path:   filePath,
+			Line:   i + 1,
+			Column: loc[0] + 1,
+			Text:   snippet(line),
+		}
+		if opts.ContextLines > 0 {
+			start := max(0, i-opts.ContextLines)
+			end := min(len(lines), i+1+opts.ContextLines)
+			for _, l := range lines[start:i] {
+				match.Before = append(match.Before, snippet(l))
+			}
+			for _, l := range lines[i+1 : end] {
+				match.After = append(match.After, snippet(l))
+			}
+		}
+
+		result.Matches = append(result.Matches, match)
+		if len(result.Matches) >= opts.MaxMatches {
+			break
+		}
+	}
+	return found
+}
+
+// isBinary reports whether content looks binary (contains a NUL byte near the start)
+func isBinary(content []byte) bool {
+	if len(content) > binarySniffLength {
+		content = content[:binarySniffLength]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// snippet trims a line to a bounded length
+func snippet(line string) string {
+	if len(line) > maxSnippetLength {
+		return line[:maxSnippetLength] + "..."
+	}
+	return line
+}
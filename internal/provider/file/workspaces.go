@@ -0,0 +1,119 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// Workspace is a named root directory the file tools resolve their paths against
+type Workspace struct {
+	Name string `json:"name"`
+	Root string `json:"root"` // Absolute path of the root
+}
+
+// addWorkspaces resolves the configured workspaces and whitelists their roots; workspaces whose root
+// cannot be resolved are skipped
+func (p *FileProvider) addWorkspaces(workspaces map[string]string) {
+	for name, root := range workspaces {
+		if name == "" || root == "" {
+			log.Printf("⚠ Skipping file workspace %q: name and root are required", name)
+			continue
+		}
+		abs, err := filepath.Abs(filepath.Clean(root))
+		if err != nil {
+			log.Printf("⚠ Skipping file workspace %s: %v", name, err)
+			continue
+		}
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			log.Printf("⚠ File workspace %s: %s is not a directory", name, abs)
+		}
+		p.workspaces[name] = abs
+		p.validator.AddWorkspaceRoot(abs)
+	}
+}
+
+// Workspaces returns the configured workspaces sorted by name
+func (p *FileProvider) Workspaces() []Workspace {
+	workspaces := make([]Workspace, 0, len(p.workspaces))
+	for name, root := range p.workspaces {
+		workspaces = append(workspaces, Workspace{Name: name, Root: root})
+	}
+	slices.SortFunc(workspaces, func(a, b Workspace) int { return strings.Compare(a.Name, b.Name) })
+	return workspaces
+}
+
+// resolvePath returns the path a tool operates on: the path itself without a workspace (relative to the
+// working directory), or the path joined to the root of the workspace, which it may not leave
+func (p *FileProvider) resolvePath(workspace, path string) (string, error) {
+	if workspace == "" {
+		return path, nil
+	}
+	root, ok := p.workspaces[workspace]
+	if !ok {
+		names := make([]string, 0, len(p.workspaces))
+		for _, w := range p.Workspaces() {
+			names = append(names, w.Name)
+		}
+		if len(names) == 0 {
+			return "", fmt.Errorf("unknown workspace %q: no workspaces are configured", workspace)
+		}
+		return "", fmt.Errorf("unknown workspace %q: available workspaces: %s", workspace, strings.Join(names, ", "))
+	}
+	if path != "" && !filepath.IsLocal(path) {
+		return "", fmt.Errorf("path '%s' must be relative to the root of workspace %s", path, workspace)
+	}
+	return filepath.Join(root, path), nil
+}
+
+// createWorkspaceListTool creates the workspace listing tool
+func (p *FileProvider) createWorkspaceListTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "workspace_list",
+		Description: "List the workspaces the file tools can work in: their names, root directories and access. " +
+			"Pass a name as the workspace argument of a file tool to resolve its paths against that root; without it paths are relative to the working directory",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		wd, _ := os.Getwd()
+		workspaces := make([]map[string]interface{}, 0, len(p.workspaces))
+		for _, w := range p.Workspaces() {
+			entry := map[string]interface{}{
+				"name": w.Name,
+				"root": w.Root,
+			}
+			info, err := os.Stat(w.Root)
+			entry["exists"] = err == nil && info.IsDir()
+			if access, profile, err := p.validator.Access(w.Root); err == nil {
+				entry["access"] = access
+				if profile != "" {
+					entry["profile"] = profile
+				}
+			}
+			workspaces = append(workspaces, entry)
+		}
+
+		result := map[string]interface{}{
+			"workspaces":        workspaces,
+			"count":             len(workspaces),
+			"working_directory": wd,
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
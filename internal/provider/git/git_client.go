@@ -0,0 +1,725 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/file"
+)
+
+const (
+	defaultMaxOutputBytes = 100000
+	defaultLogLimit       = 20
+	maxLogLimit           = 200
+	defaultBlameLines     = 400
+	maxBranches           = 200
+	commandTimeout        = 30 * time.Second
+
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Commit is a commit as listed by git_log and git_show
+type Commit struct {
+	Hash       string     `json:"hash"`
+	ShortHash  string     `json:"short_hash"`
+	Author     string     `json:"author"`
+	Email      string     `json:"email"`
+	Date       string     `json:"date"`
+	Parents    []string   `json:"parents,omitempty"`
+	Refs       string     `json:"refs,omitempty"`
+	Subject    string     `json:"subject"`
+	Body       string     `json:"body,omitempty"`
+	Files      []FileStat `json:"files,omitempty"`
+	Insertions int        `json:"insertions,omitempty"`
+	Deletions  int        `json:"deletions,omitempty"`
+}
+
+// FileStat is the number of lines changed in a file
+type FileStat struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// DiffResult is a diff with per-file statistics
+type DiffResult struct {
+	Range        string     `json:"range"`
+	Files        []FileStat `json:"files"`
+	FilesChanged int        `json:"files_changed"`
+	Insertions   int        `json:"insertions"`
+	Deletions    int        `json:"deletions"`
+	Patch        string     `json:"patch,omitempty"`
+	Truncated    bool       `json:"truncated,omitempty"`
+}
+
+// BlameHunk is a run of consecutive lines last changed by the same commit
+type BlameHunk struct {
+	Commit    string   `json:"commit"`
+	Author    string   `json:"author"`
+	Date      string   `json:"date"`
+	Summary   string   `json:"summary"`
+	StartLine int      `json:"start_line"`
+	EndLine   int      `json:"end_line"`
+	Lines     []string `json:"lines"`
+}
+
+// Branch is a local or remote-tracking branch
+type Branch struct {
+	Name     string `json:"name"`
+	Current  bool   `json:"current,omitempty"`
+	Remote   bool   `json:"remote,omitempty"`
+	Commit   string `json:"commit"`
+	Date     string `json:"date"`
+	Author   string `json:"author"`
+	Subject  string `json:"subject"`
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead,omitempty"`
+	Behind   int    `json:"behind,omitempty"`
+	Gone     bool   `json:"upstream_gone,omitempty"`
+}
+
+// LogOptions filters git_log
+type LogOptions struct {
+	Revision    string
+	Path        string
+	Author      string
+	Grep        string
+	Since       string
+	Until       string
+	Limit       int
+	Stats       bool
+	FirstParent bool
+}
+
+// DiffOptions selects what git_diff compares
+type DiffOptions struct {
+	From     string
+	To       string
+	Path     string
+	Staged   bool
+	StatOnly bool
+	Context  int
+}
+
+// GitClient runs read-only git commands in whitelisted repositories
+type GitClient struct {
+	gitPath   string
+	roots     []string
+	validator *file.FileSecurityValidator
+	maxOutput int
+	initErr   error
+}
+
+var (
+	revisionPattern = regexp.MustCompile(`^[A-Za-z0-9._/@{}~^:+-]+$`)
+	trackPattern    = regexp.MustCompile(`(ahead|behind) (\d+)`)
+)
+
+// NewGitClient creates a new git client; repositories must be configured for it to be available
+func NewGitClient(cfg *config.GitConfig) *GitClient {
+	c := &GitClient{maxOutput: defaultMaxOutputBytes}
+	if cfg == nil || len(cfg.Repositories) == 0 {
+		return c
+	}
+	if cfg.MaxOutputBytes > 0 {
+		c.maxOutput = cfg.MaxOutputBytes
+	}
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		c.initErr = fmt.Errorf("git executable not found: %w", err)
+		return c
+	}
+
+	var rootErr error
+	for _, root := range cfg.Repositories {
+		// Symlinks are resolved so a link inside a root cannot point outside it
+		resolved, err := filepath.EvalSymlinks(root)
+		if err == nil {
+			resolved, err = filepath.Abs(resolved)
+		}
+		if err != nil {
+			rootErr = fmt.Errorf("repository path %s: %w", root, err)
+			log.Printf("⚠ Skipping git repository path: %v", rootErr)
+			continue
+		}
+		c.roots = append(c.roots, resolved)
+	}
+	if len(c.roots) == 0 {
+		c.initErr = rootErr
+		return c
+	}
+	c.gitPath = gitPath
+
+	// The file provider's validator enforces the same whitelist semantics as file_read
+	c.validator = file.NewFileSecurityValidator(c.roots)
+	c.validator.SetReadOnly(true)
+	return c
+}
+
+// IsAvailable reports whether git and at least one repository root are available
+func (c *GitClient) IsAvailable() bool {
+	return c.gitPath != "" && len(c.roots) > 0
+}
+
+// InitError returns the configuration error, if any
+func (c *GitClient) InitError() error {
+	return c.initErr
+}
+
+// Repositories returns the whitelisted repository roots
+func (c *GitClient) Repositories() []string {
+	return c.roots
+}
+
+// Close releases resources held by the client
+func (c *GitClient) Close() error {
+	return nil
+}
+
+// ResolveRepository maps the repo argument to the top level of a whitelisted work tree.
+// An empty repo selects the only configured root; relative paths are looked up under each root.
+func (c *GitClient) ResolveRepository(ctx context.Context, repo string) (string, error) {
+	if strings.ContainsRune(repo, 0) {
+		return "", fmt.Errorf("invalid repository path")
+	}
+
+	var candidates []string
+	switch {
+	case repo == "" && len(c.roots) == 1:
+		candidates = c.roots
+	case repo == "":
+		return "", fmt.Errorf("repo parameter is required when several repositories are configured (%s)", strings.Join(c.roots, ", "))
+	case filepath.IsAbs(repo):
+		candidates = []string{repo}
+	default:
+		for _, root := range c.roots {
+			if filepath.Base(root) == repo {
+				candidates = append(candidates, root)
+			}
+			candidates = append(candidates, filepath.Join(root, repo))
+		}
+	}
+
+	for _, candidate := range candidates {
+		resolved, err := filepath.EvalSymlinks(candidate)
+		if err != nil {
+			continue
+		}
+		if !c.validator.IsPathWhitelisted(resolved) {
+			return "", fmt.Errorf("repository '%s' is outside the whitelisted directories", repo)
+		}
+		out, _, err := c.run(ctx, resolved, 0, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return "", fmt.Errorf("'%s' is not a git repository", repo)
+		}
+		top := strings.TrimSpace(string(out))
+		if resolvedTop, err := filepath.EvalSymlinks(top); err == nil {
+			top = resolvedTop
+		}
+		// A whitelisted subdirectory must not expose the history of an enclosing repository
+		if !c.validator.IsPathWhitelisted(top) {
+			return "", fmt.Errorf("repository root %s is outside the whitelisted directories", top)
+		}
+		return top, nil
+	}
+	return "", fmt.Errorf("repository '%s' not found under %s", repo, strings.Join(c.roots, ", "))
+}
+
+// run executes git in dir with settings that keep it from prompting, writing or running repository-configured programs.
+// Output beyond limit bytes (0 for no limit) is dropped and reported as truncated.
+func (c *GitClient) run(ctx context.Context, dir string, limit int, args ...string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	base := []string{
+		"-C", dir, "--no-pager",
+		"-c", "core.fsmonitor=false",
+		"-c", "core.quotePath=false",
+		"-c", "color.ui=false",
+		"-c", "diff.external=",
+	}
+	cmd := exec.CommandContext(ctx, c.gitPath, append(base, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_OPTIONAL_LOCKS=0", "GIT_PAGER=cat", "LC_ALL=C")
+	stdout := &limitedBuffer{limit: limit}
+	var stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = stdout, &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, false, fmt.Errorf("git %s timed out after %s", args[0], commandTimeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		msg = strings.TrimPrefix(msg, "fatal: ")
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, false, fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	return stdout.Bytes(), stdout.truncated, nil
+}
+
+// limitedBuffer keeps the first limit bytes written and discards the rest. It wraps rather than
+// embeds bytes.Buffer so io.Copy cannot bypass Write through ReadFrom.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// Bytes returns the kept output
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// validateRevision rejects revisions that git could read as options
+func validateRevision(rev string) error {
+	if rev == "" {
+		return nil
+	}
+	if strings.HasPrefix(rev, "-") || len(rev) > 256 || !revisionPattern.MatchString(rev) {
+		return fmt.Errorf("invalid revision %q", rev)
+	}
+	return nil
+}
+
+// validatePath only accepts paths relative to the repository root that stay inside it
+func validatePath(path string) error {
+	if path == "" {
+		return nil
+	}
+	clean := filepath.Clean(path)
+	if filepath.IsAbs(path) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) || strings.ContainsRune(path, 0) {
+		return fmt.Errorf("invalid path %q: must be relative to the repository root", path)
+	}
+	return nil
+}
+
+// validateOption rejects option values that could start a new option or span lines
+func validateOption(name, value string) error {
+	if strings.HasPrefix(value, "-") || strings.ContainsAny(value, "\x00\n") {
+		return fmt.Errorf("invalid %s %q", name, value)
+	}
+	return nil
+}
+
+// commitFormat prints commit fields separated by fieldSep; the trailing separator ends the body so --numstat output can follow
+const commitFormat = "--format=" + recordSep + "%H" + fieldSep + "%h" + fieldSep + "%an" + fieldSep + "%ae" + fieldSep + "%aI" + fieldSep + "%P" + fieldSep + "%D" + fieldSep + "%s" + fieldSep + "%b" + fieldSep
+
+// parseCommits parses output produced with commitFormat
+func parseCommits(out string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.SplitN(record, fieldSep, 10)
+		if len(fields) < 10 {
+			continue
+		}
+		commit := Commit{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Author:    fields[2],
+			Email:     fields[3],
+			Date:      fields[4],
+			Refs:      fields[6],
+			Subject:   fields[7],
+			Body:      strings.TrimSpace(fields[8]),
+		}
+		if fields[5] != "" {
+			commit.Parents = strings.Fields(fields[5])
+		}
+		commit.Files = parseNumstat(fields[9])
+		for _, f := range commit.Files {
+			commit.Insertions += f.Added
+			commit.Deletions += f.Deleted
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// parseNumstat parses "added<TAB>deleted<TAB>path" lines; binary files show "-" counts
+func parseNumstat(out string) []FileStat {
+	var files []FileStat
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		stat := FileStat{Path: parts[2]}
+		if parts[0] == "-" && parts[1] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Added, _ = strconv.Atoi(parts[0])
+			stat.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		files = append(files, stat)
+	}
+	return files
+}
+
+// Log lists commits reachable from a revision, newest first
+func (c *GitClient) Log(ctx context.Context, repo string, opts LogOptions) ([]Commit, error) {
+	if err := validateRevision(opts.Revision); err != nil {
+		return nil, err
+	}
+	if err := validatePath(opts.Path); err != nil {
+		return nil, err
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultLogLimit
+	}
+	if opts.Limit > maxLogLimit {
+		opts.Limit = maxLogLimit
+	}
+
+	args := []string{"log", "--no-color", "-n", strconv.Itoa(opts.Limit), commitFormat}
+	if opts.Stats {
+		args = append(args, "--numstat")
+	}
+	if opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	for _, option := range []struct{ name, flag, value string }{
+		{"author", "--author=", opts.Author},
+		{"grep", "--grep=", opts.Grep},
+		{"since", "--since=", opts.Since},
+		{"until", "--until=", opts.Until},
+	} {
+		if option.value == "" {
+			continue
+		}
+		if err := validateOption(option.name, option.value); err != nil {
+			return nil, err
+		}
+		args = append(args, option.flag+option.value)
+	}
+	if opts.Grep != "" {
+		args = append(args, "--regexp-ignore-case")
+	}
+	if opts.Revision != "" {
+		args = append(args, opts.Revision)
+	}
+	args = append(args, "--")
+	if opts.Path != "" {
+		args = append(args, opts.Path)
+	}
+
+	out, _, err := c.run(ctx, repo, 0, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommits(string(out)), nil
+}
+
+// Diff compares two revisions, a revision and the working tree, or the index and HEAD
+func (c *GitClient) Diff(ctx context.Context, repo string, opts DiffOptions) (*DiffResult, error) {
+	for _, rev := range []string{opts.From, opts.To} {
+		if err := validateRevision(rev); err != nil {
+			return nil, err
+		}
+	}
+	if err := validatePath(opts.Path); err != nil {
+		return nil, err
+	}
+	if opts.To != "" && opts.From == "" {
+		return nil, fmt.Errorf("from is required when to is set")
+	}
+
+	var revs []string
+	result := &DiffResult{}
+	switch {
+	case opts.Staged:
+		revs = []string{"--cached"}
+		result.Range = "staged changes (index vs HEAD)"
+		if opts.From != "" {
+			revs = append(revs, opts.From)
+			result.Range = "index vs " + opts.From
+		}
+	case opts.From != "" && opts.To != "":
+		revs = []string{opts.From, opts.To}
+		result.Range = opts.From + ".." + opts.To
+	case opts.From != "":
+		revs = []string{opts.From}
+		result.Range = opts.From + " vs working tree"
+	default:
+		result.Range = "unstaged changes (working tree vs index)"
+	}
+	pathArgs := []string{"--"}
+	if opts.Path != "" {
+		pathArgs = append(pathArgs, opts.Path)
+	}
+
+	args := append(append([]string{"diff", "--no-color", "--numstat"}, revs...), pathArgs...)
+	out, _, err := c.run(ctx, repo, 0, args...)
+	if err != nil {
+		return nil, err
+	}
+	result.Files = parseNumstat(string(out))
+	result.FilesChanged = len(result.Files)
+	for _, f := range result.Files {
+		result.Insertions += f.Added
+		result.Deletions += f.Deleted
+	}
+	if opts.StatOnly || len(result.Files) == 0 {
+		return result, nil
+	}
+
+	contextLines := opts.Context
+	if contextLines <= 0 {
+		contextLines = 3
+	}
+	args = append([]string{"diff", "--no-color", "--no-ext-diff", "--no-textconv", "-U" + strconv.Itoa(contextLines)}, revs...)
+	patch, truncated, err := c.run(ctx, repo, c.maxOutput, append(args, pathArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	result.Patch, result.Truncated = string(patch), truncated
+	return result, nil
+}
+
+// Show returns a commit with its changed files and patch
+func (c *GitClient) Show(ctx context.Context, repo, rev string, statOnly bool) (*Commit, *DiffResult, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	if err := validateRevision(rev); err != nil {
+		return nil, nil, err
+	}
+
+	// Merge commits are shown against their first parent, like a pull request diff
+	out, _, err := c.run(ctx, repo, 0, "show", "--no-color", "--numstat", "--diff-merges=first-parent", commitFormat, rev, "--")
+	if err != nil {
+		return nil, nil, err
+	}
+	commits := parseCommits(string(out))
+	if len(commits) == 0 {
+		return nil, nil, fmt.Errorf("revision %s is not a commit", rev)
+	}
+	commit := commits[0]
+	diff := &DiffResult{Range: commit.ShortHash, Files: commit.Files, FilesChanged: len(commit.Files), Insertions: commit.Insertions, Deletions: commit.Deletions}
+	commit.Files, commit.Insertions, commit.Deletions = nil, 0, 0
+	if statOnly || diff.FilesChanged == 0 {
+		return &commit, diff, nil
+	}
+
+	patch, truncated, err := c.run(ctx, repo, c.maxOutput, "show", "--no-color", "--no-ext-diff", "--no-textconv", "--diff-merges=first-parent", "--format=", rev, "--")
+	if err != nil {
+		return nil, nil, err
+	}
+	diff.Patch, diff.Truncated = strings.TrimLeft(string(patch), "\n"), truncated
+	return &commit, diff, nil
+}
+
+// ShowFile returns a file's content at a revision
+func (c *GitClient) ShowFile(ctx context.Context, repo, rev, path string) (string, bool, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	if err := validateRevision(rev); err != nil {
+		return "", false, err
+	}
+	if path == "" {
+		return "", false, fmt.Errorf("path is required")
+	}
+	if err := validatePath(path); err != nil {
+		return "", false, err
+	}
+	out, truncated, err := c.run(ctx, repo, c.maxOutput, "show", "--no-textconv", rev+":"+filepath.ToSlash(filepath.Clean(path)))
+	if err != nil {
+		return "", false, err
+	}
+	if bytes.IndexByte(out, 0) >= 0 {
+		return "", false, fmt.Errorf("%s is a binary file", path)
+	}
+	return string(out), truncated, nil
+}
+
+// Blame attributes each line in a range of a file to the commit that last changed it
+func (c *GitClient) Blame(ctx context.Context, repo, path, rev string, startLine, endLine int) ([]BlameHunk, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	if err := validatePath(path); err != nil {
+		return nil, err
+	}
+	if err := validateRevision(rev); err != nil {
+		return nil, err
+	}
+	if startLine <= 0 {
+		startLine = 1
+	}
+	if endLine <= 0 || endLine-startLine >= defaultBlameLines {
+		endLine = startLine + defaultBlameLines - 1
+	}
+	if endLine < startLine {
+		return nil, fmt.Errorf("end_line must not be before start_line")
+	}
+
+	args := []string{"blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", startLine, endLine)}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	out, _, err := c.run(ctx, repo, 0, append(args, "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlame(string(out)), nil
+}
+
+// parseBlame groups porcelain blame output into hunks of consecutive lines from the same commit
+func parseBlame(out string) []BlameHunk {
+	type commitInfo struct{ author, date, summary string }
+	commits := map[string]*commitInfo{}
+	var hunks []BlameHunk
+	var sha string
+	var line int
+
+	for _, raw := range strings.Split(out, "\n") {
+		if strings.HasPrefix(raw, "\t") {
+			info := commits[sha]
+			short := sha
+			if len(short) > 8 {
+				short = short[:8]
+			}
+			if n := len(hunks); n > 0 && hunks[n-1].Commit == short && hunks[n-1].EndLine == line-1 {
+				hunks[n-1].EndLine = line
+				hunks[n-1].Lines = append(hunks[n-1].Lines, raw[1:])
+				continue
+			}
+			hunk := BlameHunk{Commit: short, StartLine: line, EndLine: line, Lines: []string{raw[1:]}}
+			if info != nil {
+				hunk.Author, hunk.Date, hunk.Summary = info.author, info.date, info.summary
+			}
+			hunks = append(hunks, hunk)
+			continue
+		}
+		fields := strings.Fields(raw)
+		if len(fields) >= 3 && len(fields[0]) >= 40 && isHex(fields[0]) {
+			sha = fields[0]
+			line, _ = strconv.Atoi(fields[2])
+			if commits[sha] == nil {
+				commits[sha] = &commitInfo{}
+			}
+			continue
+		}
+		key, value, _ := strings.Cut(raw, " ")
+		info := commits[sha]
+		if info == nil {
+			continue
+		}
+		switch key {
+		case "author":
+			info.author = value
+		case "author-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				info.date = time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+			}
+		case "summary":
+			info.summary = value
+		}
+	}
+	for i := range hunks {
+		if strings.Trim(hunks[i].Commit, "0") == "" {
+			hunks[i].Summary = "Not committed yet"
+		}
+	}
+	return hunks
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Branches lists branches by most recent commit, optionally including remote-tracking branches
+func (c *GitClient) Branches(ctx context.Context, repo string, includeRemote bool, contains string) ([]Branch, error) {
+	format := strings.Join([]string{"%(HEAD)", "%(refname)", "%(refname:short)", "%(objectname:short)", "%(committerdate:iso-strict)", "%(authorname)", "%(upstream:short)", "%(upstream:track)", "%(subject)"}, fieldSep)
+	args := []string{"for-each-ref", "--sort=-committerdate", "--format=" + format, "refs/heads"}
+	if includeRemote {
+		args = append(args, "refs/remotes")
+	}
+	out, _, err := c.run(ctx, repo, 0, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []Branch
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(line, fieldSep, 9)
+		if len(fields) != 9 || strings.HasSuffix(fields[1], "/HEAD") {
+			continue
+		}
+		if contains != "" && !strings.Contains(strings.ToLower(fields[2]), strings.ToLower(contains)) {
+			continue
+		}
+		branch := Branch{
+			Name:     fields[2],
+			Current:  fields[0] == "*",
+			Remote:   strings.HasPrefix(fields[1], "refs/remotes/"),
+			Commit:   fields[3],
+			Date:     fields[4],
+			Author:   fields[5],
+			Upstream: fields[6],
+			Gone:     strings.Contains(fields[7], "gone"),
+			Subject:  fields[8],
+		}
+		for _, m := range trackPattern.FindAllStringSubmatch(fields[7], -1) {
+			n, _ := strconv.Atoi(m[2])
+			if m[1] == "ahead" {
+				branch.Ahead = n
+			} else {
+				branch.Behind = n
+			}
+		}
+		branches = append(branches, branch)
+		if len(branches) >= maxBranches {
+			break
+		}
+	}
+	return branches, nil
+}
+
+// CurrentBranch returns the checked-out branch, or the commit when HEAD is detached
+func (c *GitClient) CurrentBranch(ctx context.Context, repo string) string {
+	out, _, err := c.run(ctx, repo, 0, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return ""
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		if out, _, err := c.run(ctx, repo, 0, "rev-parse", "--short", "HEAD"); err == nil {
+			return "detached at " + strings.TrimSpace(string(out))
+		}
+	}
+	return branch
+}
@@ -0,0 +1,491 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// GitProvider exposes read-only history, diff and blame tools for whitelisted repositories
+type GitProvider struct {
+	*provider.BaseProvider
+	client *GitClient
+}
+
+// NewGitProvider creates a new git provider with config
+func NewGitProvider(cfg *config.GitConfig) *GitProvider {
+	p := &GitProvider{
+		BaseProvider: provider.NewBaseProvider("git"),
+		client:       NewGitClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Git repositories not configured", p.client.InitError())
+		if err := p.client.InitError(); err != nil {
+			log.Printf("⚠ Git provider not available: %v", err)
+		}
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Git provider initialized successfully (%d repository roots)", len(p.client.Repositories()))
+
+	return p
+}
+
+// Test tests the git configuration (for ProviderClient interface compatibility)
+func (p *GitProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("git provider not available")
+	}
+	return nil
+}
+
+// AddTools adds git tools to the MCP server (for ProviderClient interface compatibility)
+func (p *GitProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the git provider
+func (p *GitProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds git tools to the MCP server
+func (p *GitProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Git provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createLogTool().Tool, p.createLogTool().Handler},
+		{p.createDiffTool().Tool, p.createDiffTool().Handler},
+		{p.createBlameTool().Tool, p.createBlameTool().Handler},
+		{p.createShowTool().Tool, p.createShowTool().Handler},
+		{p.createBranchListTool().Tool, p.createBranchListTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered Git tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All Git tools registered successfully")
+}
+
+// repoSchema is the repo property shared by all tools
+func (p *GitProvider) repoSchema() string {
+	roots, _ := json.Marshal(strings.Join(p.client.Repositories(), ", "))
+	return `"repo": {
+					"type": "string",
+					"description": "Repository path, absolute or relative to a whitelisted root (optional when only one root is configured). Roots: ` + strings.Trim(string(roots), `"`) + `"
+				}`
+}
+
+// createLogTool creates the commit history tool
+func (p *GitProvider) createLogTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "git_log",
+		Description: "List commits newest first, optionally for a file or directory, author, message pattern or date range, with per-file line counts",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.repoSchema() + `,
+				"revision": {
+					"type": "string",
+					"description": "Branch, tag, commit or range such as v1.2.0..main (default: HEAD)"
+				},
+				"path": {
+					"type": "string",
+					"description": "Only commits touching this file or directory (relative to the repository root)"
+				},
+				"author": {
+					"type": "string",
+					"description": "Author name or email pattern"
+				},
+				"grep": {
+					"type": "string",
+					"description": "Commit message pattern (case-insensitive)"
+				},
+				"since": {
+					"type": "string",
+					"description": "Only commits after this date, e.g. 2024-05-01 or \"2 weeks ago\""
+				},
+				"until": {
+					"type": "string",
+					"description": "Only commits before this date"
+				},
+				"first_parent": {
+					"type": "boolean",
+					"description": "Follow only the first parent of merges, listing one entry per merged pull request",
+					"default": false
+				},
+				"stats": {
+					"type": "boolean",
+					"description": "Include changed files with added/deleted line counts",
+					"default": false
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of commits (max 200)",
+					"default": 20
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Repo        string `json:"repo,omitempty"`
+			Revision    string `json:"revision,omitempty"`
+			Path        string `json:"path,omitempty"`
+			Author      string `json:"author,omitempty"`
+			Grep        string `json:"grep,omitempty"`
+			Since       string `json:"since,omitempty"`
+			Until       string `json:"until,omitempty"`
+			FirstParent bool   `json:"first_parent,omitempty"`
+			Stats       bool   `json:"stats,omitempty"`
+			Limit       int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		repo, err := p.client.ResolveRepository(ctx, args.Repo)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		commits, err := p.client.Log(ctx, repo, LogOptions{
+			Revision:    args.Revision,
+			Path:        args.Path,
+			Author:      args.Author,
+			Grep:        args.Grep,
+			Since:       args.Since,
+			Until:       args.Until,
+			Limit:       args.Limit,
+			Stats:       args.Stats,
+			FirstParent: args.FirstParent,
+		})
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"repository": repo,
+			"commits":    commits,
+			"count":      len(commits),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDiffTool creates the diff tool
+func (p *GitProvider) createDiffTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "git_diff",
+		Description: "Show the diff between two revisions (e.g. the last good and first bad release), a revision and the working tree, or uncommitted changes, with per-file line counts",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.repoSchema() + `,
+				"from": {
+					"type": "string",
+					"description": "Base revision; without from, uncommitted changes are shown. Use from...to syntax in from to diff against the merge base"
+				},
+				"to": {
+					"type": "string",
+					"description": "Target revision (default: the working tree)"
+				},
+				"path": {
+					"type": "string",
+					"description": "Limit the diff to this file or directory"
+				},
+				"staged": {
+					"type": "boolean",
+					"description": "Show staged changes (index vs HEAD, or vs from)",
+					"default": false
+				},
+				"stat_only": {
+					"type": "boolean",
+					"description": "Only list changed files with line counts",
+					"default": false
+				},
+				"context_lines": {
+					"type": "integer",
+					"description": "Lines of context around each change",
+					"default": 3
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Repo     string `json:"repo,omitempty"`
+			From     string `json:"from,omitempty"`
+			To       string `json:"to,omitempty"`
+			Path     string `json:"path,omitempty"`
+			Staged   bool   `json:"staged,omitempty"`
+			StatOnly bool   `json:"stat_only,omitempty"`
+			Context  int    `json:"context_lines,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		repo, err := p.client.ResolveRepository(ctx, args.Repo)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		diff, err := p.client.Diff(ctx, repo, DiffOptions{
+			From:     args.From,
+			To:       args.To,
+			Path:     args.Path,
+			Staged:   args.Staged,
+			StatOnly: args.StatOnly,
+			Context:  args.Context,
+		})
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(diff), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createBlameTool creates the blame tool
+func (p *GitProvider) createBlameTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "git_blame",
+		Description: "Show which commit, author and date last changed each line in a range of a file, grouped into runs of lines from the same commit",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.repoSchema() + `,
+				"path": {
+					"type": "string",
+					"description": "File path relative to the repository root"
+				},
+				"revision": {
+					"type": "string",
+					"description": "Blame the file as of this revision (default: the working tree)"
+				},
+				"start_line": {
+					"type": "integer",
+					"description": "First line (1-based)",
+					"default": 1
+				},
+				"end_line": {
+					"type": "integer",
+					"description": "Last line (at most 400 lines per call)"
+				}
+			},
+			"required": ["path"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Repo      string `json:"repo,omitempty"`
+			Path      string `json:"path"`
+			Revision  string `json:"revision,omitempty"`
+			StartLine int    `json:"start_line,omitempty"`
+			EndLine   int    `json:"end_line,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Path == "" {
+			return p.createErrorResult(fmt.Errorf("path parameter is required")), nil
+		}
+
+		repo, err := p.client.ResolveRepository(ctx, args.Repo)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		hunks, err := p.client.Blame(ctx, repo, args.Path, args.Revision, args.StartLine, args.EndLine)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"path":  args.Path,
+			"hunks": hunks,
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createShowTool creates the commit/file show tool
+func (p *GitProvider) createShowTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "git_show",
+		Description: "Show a commit's message, author, changed files and patch (merges against their first parent), or a file's content at a revision when path is given",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.repoSchema() + `,
+				"revision": {
+					"type": "string",
+					"description": "Commit, branch or tag (default: HEAD)"
+				},
+				"path": {
+					"type": "string",
+					"description": "Return this file's content as of the revision instead of the commit"
+				},
+				"stat_only": {
+					"type": "boolean",
+					"description": "Omit the patch and only list changed files",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Repo     string `json:"repo,omitempty"`
+			Revision string `json:"revision,omitempty"`
+			Path     string `json:"path,omitempty"`
+			StatOnly bool   `json:"stat_only,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		repo, err := p.client.ResolveRepository(ctx, args.Repo)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		if args.Path != "" {
+			content, truncated, err := p.client.ShowFile(ctx, repo, args.Revision, args.Path)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			revision := args.Revision
+			if revision == "" {
+				revision = "HEAD"
+			}
+			return p.formatJSONResult(map[string]interface{}{
+				"revision":  revision,
+				"path":      args.Path,
+				"content":   content,
+				"truncated": truncated,
+			}), nil
+		}
+
+		commit, diff, err := p.client.Show(ctx, repo, args.Revision, args.StatOnly)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"commit": commit,
+			"diff":   diff,
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createBranchListTool creates the branch listing tool
+func (p *GitProvider) createBranchListTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "git_branch_list",
+		Description: "List branches by most recent commit with their last commit, upstream and ahead/behind counts; remote-tracking branches reflect the last fetch",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.repoSchema() + `,
+				"include_remote": {
+					"type": "boolean",
+					"description": "Include remote-tracking branches such as origin/main",
+					"default": false
+				},
+				"contains": {
+					"type": "string",
+					"description": "Only branches whose name contains this text"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Repo          string `json:"repo,omitempty"`
+			IncludeRemote bool   `json:"include_remote,omitempty"`
+			Contains      string `json:"contains,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		repo, err := p.client.ResolveRepository(ctx, args.Repo)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		branches, err := p.client.Branches(ctx, repo, args.IncludeRemote, args.Contains)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"repository": repo,
+			"current":    p.client.CurrentBranch(ctx, repo),
+			"branches":   branches,
+			"count":      len(branches),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *GitProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Git Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *GitProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that GitProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*GitProvider)(nil)
@@ -0,0 +1,534 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultTimeout     = 30 * time.Second
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// Client talks to the Grafana HTTP API with a service account token
+type Client struct {
+	config    *config.GrafanaConfig
+	client    *resty.Client
+	baseURL   string
+	timeout   time.Duration
+	available bool
+}
+
+// Dashboard is a dashboard found by a search
+type Dashboard struct {
+	UID    string   `json:"uid"`
+	Title  string   `json:"title"`
+	Folder string   `json:"folder,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	URL    string   `json:"url"`
+}
+
+// DashboardPanels holds the panels of a dashboard with their queries, and its template variables
+type DashboardPanels struct {
+	UID       string     `json:"uid"`
+	Title     string     `json:"title"`
+	Folder    string     `json:"folder,omitempty"`
+	URL       string     `json:"url"`
+	Variables []Variable `json:"variables,omitempty"`
+	Panels    []Panel    `json:"panels"`
+	Notes     []string   `json:"notes,omitempty"`
+}
+
+// Variable is a template variable; queries reference it as $name
+type Variable struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Query   string `json:"query,omitempty"`
+	Current string `json:"current,omitempty"`
+}
+
+// Panel is a dashboard panel with the queries of its targets
+type Panel struct {
+	ID         int64       `json:"id"`
+	Title      string      `json:"title"`
+	Type       string      `json:"type"`
+	Row        string      `json:"row,omitempty"` // Title of the row the panel sits in
+	Datasource *Datasource `json:"datasource,omitempty"`
+	Queries    []Query     `json:"queries"`
+}
+
+// Query is one target of a panel
+type Query struct {
+	RefID      string      `json:"ref_id"`
+	Datasource *Datasource `json:"datasource,omitempty"` // Set when it differs from the panel's
+	Query      string      `json:"query"`                // PromQL, LogQL, SQL, ... depending on the datasource
+	Hidden     bool        `json:"hidden,omitempty"`
+}
+
+// Datasource identifies a datasource; Name and Type are filled in from the datasource list when Grafana
+// lets the token read it
+type Datasource struct {
+	UID  string `json:"uid,omitempty"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+// Annotation is an annotation to create; a zero TimeEnd marks a point in time instead of a range
+type Annotation struct {
+	DashboardUID string
+	PanelID      int64
+	Time         time.Time
+	TimeEnd      time.Time
+	Tags         []string
+	Text         string
+}
+
+// AnnotationResult is the reply to a created annotation
+type AnnotationResult struct {
+	ID        int64      `json:"id"`
+	Time      time.Time  `json:"time"`
+	TimeEnd   *time.Time `json:"time_end,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	Dashboard string     `json:"dashboard_uid,omitempty"` // Organization-wide annotation when empty
+	PanelID   int64      `json:"panel_id,omitempty"`
+}
+
+// NewClient creates a new Grafana client from config; without a url and token it is not available
+func NewClient(cfg *config.GrafanaConfig) *Client {
+	if cfg == nil || cfg.URL == "" || cfg.APIToken == "" {
+		return &Client{available: false}
+	}
+
+	timeout := defaultTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	baseURL := strings.TrimSuffix(cfg.URL, "/")
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetAuthToken(cfg.APIToken).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(timeout)
+	if cfg.OrgID > 0 {
+		client.SetHeader("X-Grafana-Org-Id", strconv.Itoa(cfg.OrgID))
+	}
+
+	return &Client{
+		config:    cfg,
+		client:    client,
+		baseURL:   baseURL,
+		timeout:   timeout,
+		available: true,
+	}
+}
+
+// IsAvailable reports whether the client is configured
+func (c *Client) IsAvailable() bool {
+	return c.available
+}
+
+// IsWriteEnabled reports whether annotations may be created
+func (c *Client) IsWriteEnabled() bool {
+	return c.config != nil && c.config.WriteEnabled
+}
+
+// Close closes the Grafana client
+func (c *Client) Close() error {
+	return nil
+}
+
+// get fetches a path into result
+func (c *Client) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	if !c.available {
+		return fmt.Errorf("grafana client not initialized")
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParamsFromValues(query).
+		SetResult(result).
+		Get(path)
+	if err != nil {
+		return fmt.Errorf("grafana request failed: %w", err)
+	}
+	if resp.IsError() {
+		return apiError(resp)
+	}
+	return nil
+}
+
+// Ping checks that Grafana answers and accepts the token
+func (c *Client) Ping(ctx context.Context) error {
+	var org struct {
+		ID int64 `json:"id"`
+	}
+	return c.get(ctx, "/api/org", nil, &org)
+}
+
+// SearchDashboards searches dashboards by title, tags and folder title; folder matches a substring
+func (c *Client) SearchDashboards(ctx context.Context, queryText string, tags []string, folder string, limit int) ([]Dashboard, error) {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	query := url.Values{"type": {"dash-db"}}
+	if queryText != "" {
+		query.Set("query", queryText)
+	}
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+	// The folder is filtered here, so ask for more when one is given
+	if folder == "" {
+		query.Set("limit", strconv.Itoa(limit))
+	} else {
+		query.Set("limit", strconv.Itoa(maxSearchLimit))
+	}
+
+	var hits []struct {
+		UID         string   `json:"uid"`
+		Title       string   `json:"title"`
+		URL         string   `json:"url"`
+		Tags        []string `json:"tags"`
+		FolderTitle string   `json:"folderTitle"`
+	}
+	if err := c.get(ctx, "/api/search", query, &hits); err != nil {
+		return nil, err
+	}
+
+	result := []Dashboard{}
+	for _, hit := range hits {
+		if folder != "" && !strings.Contains(strings.ToLower(hit.FolderTitle), strings.ToLower(folder)) {
+			continue
+		}
+		result = append(result, Dashboard{
+			UID:    hit.UID,
+			Title:  hit.Title,
+			Folder: hit.FolderTitle,
+			Tags:   hit.Tags,
+			URL:    c.baseURL + hit.URL,
+		})
+		if len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// rawPanel is a panel of the dashboard JSON model
+type rawPanel struct {
+	ID         int64           `json:"id"`
+	Title      string          `json:"title"`
+	Type       string          `json:"type"`
+	Datasource json.RawMessage `json:"datasource"`
+	Targets    []struct {
+		RefID      string          `json:"refId"`
+		Datasource json.RawMessage `json:"datasource"`
+		Hide       bool            `json:"hide"`
+		Expr       string          `json:"expr"`   // Prometheus, Loki
+		RawSQL     string          `json:"rawSql"` // SQL datasources
+		Query      json.RawMessage `json:"query"`  // Elasticsearch, InfluxQL, Flux, Tempo, ...
+		Target     json.RawMessage `json:"target"` // Graphite
+	} `json:"targets"`
+	Panels []rawPanel `json:"panels"` // Panels of a collapsed row
+}
+
+// DashboardPanels returns the panels of a dashboard with their queries; with panel, only the panels whose
+// title contains it or whose ID it is
+func (c *Client) DashboardPanels(ctx context.Context, uid, panel string) (*DashboardPanels, error) {
+	var reply struct {
+		Dashboard struct {
+			UID    string     `json:"uid"`
+			Title  string     `json:"title"`
+			Panels []rawPanel `json:"panels"`
+			Rows   []struct { // Schema before Grafana 5
+				Title  string     `json:"title"`
+				Panels []rawPanel `json:"panels"`
+			} `json:"rows"`
+			Templating struct {
+				List []struct {
+					Name    string          `json:"name"`
+					Type    string          `json:"type"`
+					Query   json.RawMessage `json:"query"`
+					Current struct {
+						Text json.RawMessage `json:"text"`
+					} `json:"current"`
+				} `json:"list"`
+			} `json:"templating"`
+		} `json:"dashboard"`
+		Meta struct {
+			URL         string `json:"url"`
+			FolderTitle string `json:"folderTitle"`
+		} `json:"meta"`
+	}
+	if err := c.get(ctx, "/api/dashboards/uid/"+url.PathEscape(uid), nil, &reply); err != nil {
+		return nil, err
+	}
+
+	dashboard := reply.Dashboard
+	result := &DashboardPanels{
+		UID:    dashboard.UID,
+		Title:  dashboard.Title,
+		Folder: reply.Meta.FolderTitle,
+		URL:    c.baseURL + reply.Meta.URL,
+		Panels: []Panel{},
+	}
+	for _, v := range dashboard.Templating.List {
+		result.Variables = append(result.Variables, Variable{
+			Name:    v.Name,
+			Type:    v.Type,
+			Query:   variableText(v.Query),
+			Current: variableText(v.Current.Text),
+		})
+	}
+
+	row := ""
+	var add func(p rawPanel)
+	add = func(p rawPanel) {
+		if p.Type == "row" {
+			row = p.Title
+			for _, nested := range p.Panels {
+				add(nested)
+			}
+			return
+		}
+		if panel != "" && strconv.FormatInt(p.ID, 10) != panel && !strings.Contains(strings.ToLower(p.Title), strings.ToLower(panel)) {
+			return
+		}
+		result.Panels = append(result.Panels, newPanel(p, row))
+	}
+	for _, p := range dashboard.Panels {
+		add(p)
+	}
+	for _, r := range dashboard.Rows {
+		row = r.Title
+		for _, p := range r.Panels {
+			add(p)
+		}
+	}
+
+	if err := c.resolveDatasources(ctx, result); err != nil {
+		result.Notes = append(result.Notes, fmt.Sprintf("datasource names not resolved: %v", err))
+	}
+	return result, nil
+}
+
+// newPanel converts a panel of the dashboard model, leaving out target datasources equal to the panel's
+func newPanel(p rawPanel, row string) Panel {
+	panel := Panel{
+		ID:         p.ID,
+		Title:      p.Title,
+		Type:       p.Type,
+		Row:        row,
+		Datasource: parseDatasource(p.Datasource),
+		Queries:    []Query{},
+	}
+	for _, t := range p.Targets {
+		query := Query{RefID: t.RefID, Hidden: t.Hide}
+		for _, text := range []string{t.Expr, t.RawSQL, rawString(t.Query), rawString(t.Target)} {
+			if text != "" {
+				query.Query = text
+				break
+			}
+		}
+		if ds := parseDatasource(t.Datasource); ds != nil && (panel.Datasource == nil || *ds != *panel.Datasource) {
+			query.Datasource = ds
+		}
+		panel.Queries = append(panel.Queries, query)
+	}
+	return panel
+}
+
+// parseDatasource reads a datasource reference: an object with a uid and type, or a name in older dashboards
+func parseDatasource(raw json.RawMessage) *Datasource {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		if name == "" {
+			return nil
+		}
+		return &Datasource{Name: name}
+	}
+	var ref struct {
+		UID  string `json:"uid"`
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &ref) != nil || (ref.UID == "" && ref.Type == "") {
+		return nil
+	}
+	return &Datasource{UID: ref.UID, Type: ref.Type}
+}
+
+// rawString returns a JSON string as is and any other JSON value as its text; "" for null
+func rawString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	return string(raw)
+}
+
+// variableText reads a variable's query or current value: a string, a list of values, or a query object
+// of the newer datasource plugins
+func variableText(raw json.RawMessage) string {
+	var values []string
+	if json.Unmarshal(raw, &values) == nil {
+		return strings.Join(values, ",")
+	}
+	var object struct {
+		Query string `json:"query"`
+	}
+	if json.Unmarshal(raw, &object) == nil && object.Query != "" {
+		return object.Query
+	}
+	return rawString(raw)
+}
+
+// resolveDatasources fills in the names and types of the datasources the panels reference by UID
+func (c *Client) resolveDatasources(ctx context.Context, result *DashboardPanels) error {
+	var refs []*Datasource
+	for i := range result.Panels {
+		if ds := result.Panels[i].Datasource; ds != nil && ds.UID != "" {
+			refs = append(refs, ds)
+		}
+		for j := range result.Panels[i].Queries {
+			if ds := result.Panels[i].Queries[j].Datasource; ds != nil && ds.UID != "" {
+				refs = append(refs, ds)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var datasources []Datasource
+	if err := c.get(ctx, "/api/datasources", nil, &datasources); err != nil {
+		return err
+	}
+	byUID := make(map[string]Datasource, len(datasources))
+	for _, ds := range datasources {
+		byUID[ds.UID] = ds
+	}
+	for _, ref := range refs {
+		if ds, ok := byUID[ref.UID]; ok {
+			ref.Name, ref.Type = ds.Name, ds.Type
+		}
+	}
+	return nil
+}
+
+// CreateAnnotation creates an annotation on a dashboard or panel, or across the organization without one,
+// as the user the tool call acts for when there is one
+func (c *Client) CreateAnnotation(ctx context.Context, annotation Annotation) (*AnnotationResult, error) {
+	if !c.available {
+		return nil, fmt.Errorf("grafana client not initialized")
+	}
+	if !c.IsWriteEnabled() {
+		return nil, fmt.Errorf("grafana writes are disabled (set grafana.write_enabled to true)")
+	}
+	if strings.TrimSpace(annotation.Text) == "" {
+		return nil, fmt.Errorf("text is required")
+	}
+	if annotation.PanelID != 0 && annotation.DashboardUID == "" {
+		return nil, fmt.Errorf("panel_id needs dashboard_uid")
+	}
+	if !annotation.TimeEnd.IsZero() && annotation.TimeEnd.Before(annotation.Time) {
+		return nil, fmt.Errorf("time_end %s is before time %s", annotation.TimeEnd.Format(time.RFC3339), annotation.Time.Format(time.RFC3339))
+	}
+
+	tags := append([]string{}, c.config.AnnotationTags...)
+	for _, tag := range annotation.Tags {
+		if !containsTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	body := map[string]interface{}{
+		"time": annotation.Time.UnixMilli(),
+		"tags": tags,
+		"text": annotation.Text,
+	}
+	if !annotation.TimeEnd.IsZero() {
+		body["timeEnd"] = annotation.TimeEnd.UnixMilli()
+	}
+	if annotation.DashboardUID != "" {
+		body["dashboardUID"] = annotation.DashboardUID
+	}
+	if annotation.PanelID != 0 {
+		body["panelId"] = annotation.PanelID
+	}
+
+	req := c.client.R().SetContext(ctx)
+	if err := auth.ApplyOnBehalfOf(ctx, "grafana", req); err != nil {
+		return nil, err
+	}
+	var reply struct {
+		ID int64 `json:"id"`
+	}
+	resp, err := req.SetBody(body).SetResult(&reply).Post("/api/annotations")
+	if err != nil {
+		return nil, fmt.Errorf("grafana request failed: %w", err)
+	}
+	if resp.IsError() {
+		if resp.StatusCode() == 403 {
+			return nil, fmt.Errorf("%w (the token needs the Editor role or annotations:write)", apiError(resp))
+		}
+		return nil, apiError(resp)
+	}
+
+	result := &AnnotationResult{
+		ID:        reply.ID,
+		Time:      annotation.Time,
+		Tags:      tags,
+		Dashboard: annotation.DashboardUID,
+		PanelID:   annotation.PanelID,
+	}
+	if !annotation.TimeEnd.IsZero() {
+		result.TimeEnd = &annotation.TimeEnd
+	}
+	return result, nil
+}
+
+// containsTag reports whether tags holds tag, ignoring case
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiError builds an error from a failed response, with Grafana's message when the body has one
+func apiError(resp *resty.Response) error {
+	var reply struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(resp.Body(), &reply) == nil && reply.Message != "" {
+		return fmt.Errorf("grafana API error: %s: %s", resp.Status(), reply.Message)
+	}
+	body := strings.TrimSpace(resp.String())
+	if len(body) > 500 {
+		body = body[:500] + "..."
+	}
+	if body == "" {
+		return fmt.Errorf("grafana API error: %s", resp.Status())
+	}
+	return fmt.Errorf("grafana API error: %s: %s", resp.Status(), body)
+}
@@ -0,0 +1,291 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/loki"
+)
+
+// GrafanaProvider searches Grafana dashboards, reads the queries behind their panels and annotates
+// time ranges, e.g. the incident windows found with the Loki tools
+type GrafanaProvider struct {
+	*provider.BaseProvider
+	client *Client
+}
+
+// NewGrafanaProvider creates a new Grafana provider with config
+func NewGrafanaProvider(cfg *config.GrafanaConfig) *GrafanaProvider {
+	p := &GrafanaProvider{
+		BaseProvider: provider.NewBaseProvider("grafana"),
+		client:       NewClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Grafana not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Grafana provider initialized successfully (url: %s)", cfg.URL)
+
+	return p
+}
+
+// Client returns the Grafana client
+func (p *GrafanaProvider) Client() *Client {
+	return p.client
+}
+
+// Test tests the Grafana configuration (for ProviderClient interface compatibility)
+func (p *GrafanaProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("grafana provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Grafana tools to the MCP server (for ProviderClient interface compatibility)
+func (p *GrafanaProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the Grafana provider
+func (p *GrafanaProvider) Close() error {
+	return p.client.Close()
+}
+
+// HealthCheck checks that Grafana answers and accepts the token
+func (p *GrafanaProvider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.client.timeout)
+	defer cancel()
+	return p.client.Ping(ctx)
+}
+
+// addToolsToServer adds Grafana tools to the MCP server
+func (p *GrafanaProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Grafana provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createSearchDashboardsTool(),
+		p.createDashboardPanelsTool(),
+		p.createAnnotationTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered Grafana tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All Grafana tools registered successfully")
+}
+
+// createSearchDashboardsTool creates the dashboard search tool
+func (p *GrafanaProvider) createSearchDashboardsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "grafana_search_dashboards",
+		Description: "Search Grafana dashboards by title, tags and folder; returns each dashboard's UID, folder, tags and URL",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"query": {
+					"type": "string",
+					"description": "Text the dashboard title contains; every dashboard when empty"
+				},
+				"tags": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Only dashboards with all of these tags"
+				},
+				"folder": {
+					"type": "string",
+					"description": "Only dashboards in folders whose title contains this"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum dashboards to return (at most %d)",
+					"default": %d
+				}
+			}
+		}`, maxSearchLimit, defaultSearchLimit)),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Query  string   `json:"query,omitempty"`
+			Tags   []string `json:"tags,omitempty"`
+			Folder string   `json:"folder,omitempty"`
+			Limit  int      `json:"limit,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		dashboards, err := p.client.SearchDashboards(ctx, args.Query, args.Tags, args.Folder, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(map[string]interface{}{
+			"dashboards": dashboards,
+			"count":      len(dashboards),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDashboardPanelsTool creates the tool that reads the panel queries of a dashboard
+func (p *GrafanaProvider) createDashboardPanelsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "grafana_dashboard_panels",
+		Description: "Read the panels of a Grafana dashboard with the queries behind them (PromQL, LogQL, SQL, ...) and their datasources, including panels in collapsed rows, plus the dashboard's template variables. LogQL queries can be run with loki_query",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"uid": {
+					"type": "string",
+					"description": "Dashboard UID, from grafana_search_dashboards or the dashboard URL (/d/<uid>/...)"
+				},
+				"panel": {
+					"type": "string",
+					"description": "Only panels whose title contains this, or whose ID it is"
+				}
+			},
+			"required": ["uid"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			UID   string `json:"uid"`
+			Panel string `json:"panel,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.UID == "" {
+			return p.createErrorResult(fmt.Errorf("uid is required")), nil
+		}
+
+		panels, err := p.client.DashboardPanels(ctx, args.UID, args.Panel)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(panels), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createAnnotationTool creates the annotation tool
+func (p *GrafanaProvider) createAnnotationTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "grafana_create_annotation",
+		Description: "Annotate a point in time or a time range in Grafana, e.g. an incident window or a deploy, on one dashboard or panel or across the organization. grafana.annotation_tags are added to the given tags. Requires grafana.write_enabled",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"text": {
+					"type": "string",
+					"description": "Annotation text; may contain links"
+				},
+				"time": {
+					"type": "string",
+					"description": "Start: RFC3339, unix timestamp, or a duration ago such as 30m (default: now)"
+				},
+				"time_end": {
+					"type": "string",
+					"description": "End of the range, in the same formats; a point in time when omitted"
+				},
+				"tags": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Tags, e.g. [\"incident\", \"checkout\"]"
+				},
+				"dashboard_uid": {
+					"type": "string",
+					"description": "Dashboard to annotate; an organization annotation, shown by dashboards that query its tags, when omitted"
+				},
+				"panel_id": {
+					"type": "integer",
+					"description": "Panel of the dashboard to annotate"
+				}
+			},
+			"required": ["text"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Text         string   `json:"text"`
+			Time         string   `json:"time,omitempty"`
+			TimeEnd      string   `json:"time_end,omitempty"`
+			Tags         []string `json:"tags,omitempty"`
+			DashboardUID string   `json:"dashboard_uid,omitempty"`
+			PanelID      int64    `json:"panel_id,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		now := time.Now()
+		annotation := Annotation{
+			DashboardUID: args.DashboardUID,
+			PanelID:      args.PanelID,
+			Tags:         args.Tags,
+			Text:         args.Text,
+		}
+		var err error
+		if annotation.Time, err = loki.ParseTime(args.Time, now); err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if args.TimeEnd != "" {
+			if annotation.TimeEnd, err = loki.ParseTime(args.TimeEnd, now); err != nil {
+				return p.createErrorResult(err), nil
+			}
+		}
+
+		result, err := p.client.CreateAnnotation(ctx, annotation)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *GrafanaProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Grafana Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *GrafanaProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that GrafanaProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*GrafanaProvider)(nil)
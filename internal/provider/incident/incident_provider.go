@@ -0,0 +1,240 @@
+package incident
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/loki"
+)
+
+const (
+	defaultIncidentWindow = time.Hour
+	defaultDeployLookback = 6 * time.Hour
+	maxIncidentWindow     = 7 * 24 * time.Hour
+)
+
+// IncidentProvider correlates Sentry issues, Loki error logs and CI runs around an incident
+type IncidentProvider struct {
+	*provider.BaseProvider
+	investigator *Investigator
+	catalog      Catalog
+}
+
+// NewIncidentProvider creates a new incident provider; catalog, issues, logs and runs may be nil when
+// their providers are not running
+func NewIncidentProvider(catalog Catalog, issues IssueSource, logs LogSource, runs RunSource) *IncidentProvider {
+	p := &IncidentProvider{
+		BaseProvider: provider.NewBaseProvider("incident"),
+		investigator: NewInvestigator(issues, logs, runs),
+		catalog:      catalog,
+	}
+
+	var sources []string
+	if issues != nil {
+		sources = append(sources, "sentry")
+	}
+	if logs != nil {
+		sources = append(sources, "loki")
+	}
+	if runs != nil {
+		sources = append(sources, "cicd")
+	}
+	if len(sources) == 0 {
+		p.SetStatus(false, "Needs the sentry, loki or cicd provider", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Incident provider initialized successfully (%s)", strings.Join(sources, ", "))
+
+	return p
+}
+
+// Test tests the incident configuration (for ProviderClient interface compatibility)
+func (p *IncidentProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("incident provider not available")
+	}
+	return nil
+}
+
+// AddTools adds incident tools to the MCP server (for ProviderClient interface compatibility)
+func (p *IncidentProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the incident provider
+func (p *IncidentProvider) Close() error {
+	return nil
+}
+
+// addToolsToServer adds incident tools to the MCP server
+func (p *IncidentProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Incident provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createInvestigateTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered incident tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All incident tools registered successfully")
+}
+
+// createInvestigateTool creates the cross-provider incident report tool
+func (p *IncidentProvider) createInvestigateTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "investigate_incident",
+		Description: "Investigate an incident of a service over a time window: pulls the Sentry issues seen in the window, analyzes the service's Loki error logs " +
+			"(top error patterns, histogram and peak) and lists the CI runs of its repositories from before the window to its end, then orders them on a timeline " +
+			"and attributes new issues and error milestones to the deploys that finished within an hour before them. " +
+			"The Sentry projects, Loki labels and repositories come from the service catalog unless given",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"service": {
+					"type": "string",
+					"description": "Service name as registered in the service catalog"
+				},
+				"start": {
+					"type": "string",
+					"description": "Window start: RFC3339, Unix seconds or relative like 2h (ago). Default: 1h ago"
+				},
+				"end": {
+					"type": "string",
+					"description": "Window end: RFC3339, Unix seconds, relative or now. Default: now"
+				},
+				"deploy_lookback": {
+					"type": "string",
+					"description": "How long before the window to look for CI runs, e.g. 6h or 1d",
+					"default": "6h"
+				},
+				"sentry_projects": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Sentry project slugs (overrides the catalog)"
+				},
+				"loki_selector": {
+					"type": "string",
+					"description": "LogQL stream selector of the service, e.g. {app=\"checkout\"} (overrides the catalog)"
+				},
+				"repos": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Repositories whose CI runs to list, e.g. github.com/org/repo (overrides the catalog)"
+				}
+			},
+			"required": ["service"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Service        string   `json:"service"`
+			Start          string   `json:"start,omitempty"`
+			End            string   `json:"end,omitempty"`
+			DeployLookback string   `json:"deploy_lookback,omitempty"`
+			SentryProjects []string `json:"sentry_projects,omitempty"`
+			LokiSelector   string   `json:"loki_selector,omitempty"`
+			Repos          []string `json:"repos,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Service == "" {
+			return p.createErrorResult(fmt.Errorf("service parameter is required")), nil
+		}
+
+		now := time.Now()
+		target := Target{
+			Service:        args.Service,
+			DeployLookback: defaultDeployLookback,
+			SentryProjects: args.SentryProjects,
+			LokiSelector:   args.LokiSelector,
+			Repos:          args.Repos,
+		}
+		var err error
+		if target.End, err = loki.ParseTime(args.End, now); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid end: %w", err)), nil
+		}
+		target.Start = target.End.Add(-defaultIncidentWindow)
+		if args.Start != "" {
+			if target.Start, err = loki.ParseTime(args.Start, now); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid start: %w", err)), nil
+			}
+		}
+		if !target.Start.Before(target.End) {
+			return p.createErrorResult(fmt.Errorf("start must be before end")), nil
+		}
+		if target.End.Sub(target.Start) > maxIncidentWindow {
+			return p.createErrorResult(fmt.Errorf("the window may span at most %s", maxIncidentWindow)), nil
+		}
+		if args.DeployLookback != "" {
+			lookback, err := loki.ParseDuration(args.DeployLookback)
+			if err != nil || lookback < 0 {
+				return p.createErrorResult(fmt.Errorf("invalid deploy_lookback %q", args.DeployLookback)), nil
+			}
+			target.DeployLookback = lookback
+		}
+
+		// Fill what was not given from the catalog
+		var notes []string
+		if p.catalog != nil {
+			if svc, ok := p.catalog.Get(args.Service); ok {
+				if len(target.SentryProjects) == 0 {
+					target.SentryProjects = svc.SentryProjects
+				}
+				if target.LokiSelector == "" {
+					target.LokiSelector = svc.LokiSelector()
+				}
+				if len(target.Repos) == 0 {
+					target.Repos = svc.Repos
+				}
+			} else {
+				notes = append(notes, fmt.Sprintf("service %q is not in the catalog; sources are not scoped to it unless given", args.Service))
+			}
+		}
+
+		report := p.investigator.Investigate(ctx, target)
+		report.Notes = notes
+		return p.formatJSONResult(report), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *IncidentProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Incident Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *IncidentProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that IncidentProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*IncidentProvider)(nil)
@@ -0,0 +1,484 @@
+package incident
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dev-mcp/internal/provider/catalog"
+	"dev-mcp/internal/provider/cicd"
+	"dev-mcp/internal/provider/loki"
+	"dev-mcp/internal/provider/sentry"
+)
+
+const (
+	// errorLineFilter keeps the log lines that look like errors
+	errorLineFilter = `|~ "(?i)(error|fatal|panic|exception)"`
+	// maxErrorLines bounds the error log lines analyzed
+	maxErrorLines = 2000
+	// maxListedIssues bounds the Sentry issues listed in a report
+	maxListedIssues = 20
+	// runsPerRepo bounds the CI runs fetched per repository
+	runsPerRepo = 30
+	// correlationWindow is how long after a deploy a new signal is attributed to it
+	correlationWindow = time.Hour
+)
+
+// Source status values
+const (
+	StatusOK      = "ok"
+	StatusSkipped = "skipped" // The provider is not running or nothing scopes the search to the service
+	StatusError   = "error"   // The provider failed to answer
+)
+
+// Catalog looks up services in the service catalog
+type Catalog interface {
+	Get(name string) (*catalog.Service, bool)
+}
+
+// IssueSource searches Sentry issues
+type IssueSource interface {
+	FetchIssues(ctx context.Context, query string, minutesBack int) ([]sentry.Issue, error)
+}
+
+// LogSource runs LogQL range queries
+type LogSource interface {
+	QueryRange(params loki.QueryParams) (*loki.QueryResponse, error)
+}
+
+// RunSource lists CI runs
+type RunSource interface {
+	Systems() []string
+	ListRuns(system, repo, branch string, limit int) ([]cicd.Run, error)
+}
+
+// Target is the service and time window being investigated, with where to look
+type Target struct {
+	Service        string
+	Start          time.Time
+	End            time.Time
+	DeployLookback time.Duration // Runs this long before the window are included
+	SentryProjects []string
+	LokiSelector   string
+	Repos          []string // "github.com/org/repo", "gitlab.com/group/project" or a repo of the only CI system
+}
+
+// Source is the outcome of asking one provider
+type Source struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Issue is a Sentry issue seen in the window
+type Issue struct {
+	ID          string    `json:"id"`
+	ShortID     string    `json:"short_id"`
+	Title       string    `json:"title"`
+	Level       string    `json:"level"`
+	Project     string    `json:"project"`
+	Events      string    `json:"events"`
+	Users       int       `json:"users"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	NewInWindow bool      `json:"new_in_window"`
+}
+
+// Logs summarizes the error log lines of the window
+type Logs struct {
+	Query     string                `json:"query"`
+	Truncated bool                  `json:"truncated"`
+	Analysis  *loki.Analysis        `json:"analysis"`
+	Peak      *loki.HistogramBucket `json:"peak,omitempty"` // The bucket with the most error lines
+}
+
+// Deploy is a CI run around the window
+type Deploy struct {
+	System     string     `json:"system"`
+	Repo       string     `json:"repo"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name,omitempty"`
+	Status     string     `json:"status"`
+	Conclusion string     `json:"conclusion,omitempty"`
+	Branch     string     `json:"branch,omitempty"`
+	Commit     string     `json:"commit,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Event is an entry of the report's timeline
+type Event struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"` // cicd, sentry or loki
+	Kind    string    `json:"kind"`   // deploy, new_issue, first_error or error_peak
+	Summary string    `json:"summary"`
+}
+
+// Report is the combined view of an incident window
+type Report struct {
+	Service      string    `json:"service"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Summary      string    `json:"summary"`
+	Correlations []string  `json:"correlations"`
+	Timeline     []Event   `json:"timeline"`
+	IssuesTotal  int       `json:"sentry_issues_total"`
+	IssuesNew    int       `json:"sentry_issues_new"` // First seen in the window
+	Issues       []Issue   `json:"sentry_issues"`
+	Logs         *Logs     `json:"loki_errors,omitempty"`
+	Deploys      []Deploy  `json:"deploys"`
+	Sources      []Source  `json:"sources"`
+	Notes        []string  `json:"notes,omitempty"`
+}
+
+// Investigator gathers the signals of an incident from the configured sources; each source may be nil
+type Investigator struct {
+	issues IssueSource
+	logs   LogSource
+	runs   RunSource
+}
+
+// NewInvestigator creates an investigator over the given sources
+func NewInvestigator(issues IssueSource, logs LogSource, runs RunSource) *Investigator {
+	return &Investigator{issues: issues, logs: logs, runs: runs}
+}
+
+// Investigate pulls the Sentry issues, error logs and CI runs of a window, orders them on a timeline
+// and attributes new signals to the deploys that finished shortly before them
+func (v *Investigator) Investigate(ctx context.Context, target Target) *Report {
+	report := &Report{
+		Service:      target.Service,
+		Start:        target.Start,
+		End:          target.End,
+		Correlations: []string{},
+		Issues:       []Issue{},
+		Deploys:      []Deploy{},
+	}
+
+	report.Sources = append(report.Sources,
+		v.collectIssues(ctx, target, report),
+		v.collectLogs(target, report),
+		v.collectDeploys(target, report),
+	)
+	report.Timeline = timeline(report)
+	report.Correlations = correlate(report.Timeline)
+	report.Summary = summarize(report)
+	return report
+}
+
+// collectIssues fetches the issues seen in the window
+func (v *Investigator) collectIssues(ctx context.Context, target Target, report *Report) Source {
+	source := Source{Name: "sentry"}
+	if v.issues == nil {
+		source.Status, source.Detail = StatusSkipped, "the sentry provider is not running"
+		return source
+	}
+
+	query := fmt.Sprintf("lastSeen:>=%s firstSeen:<=%s", sentryTime(target.Start), sentryTime(target.End))
+	if len(target.SentryProjects) > 0 {
+		query += fmt.Sprintf(" project:[%s]", strings.Join(target.SentryProjects, ","))
+	}
+	issues, err := v.issues.FetchIssues(ctx, query, 0)
+	if err != nil {
+		source.Status, source.Detail = StatusError, err.Error()
+		return source
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return count(issues[i]) > count(issues[j]) })
+	report.IssuesTotal = len(issues)
+	for _, issue := range issues {
+		isNew := !issue.FirstSeen.Before(target.Start)
+		if isNew {
+			report.IssuesNew++
+		}
+		if len(report.Issues) < maxListedIssues {
+			report.Issues = append(report.Issues, Issue{
+				ID: issue.ID, ShortID: issue.ShortID, Title: issue.Title, Level: issue.Level, Project: issue.Project.Slug,
+				Events: issue.Count, Users: issue.UserCount, FirstSeen: issue.FirstSeen, LastSeen: issue.LastSeen, NewInWindow: isNew,
+			})
+		}
+	}
+	source.Status = StatusOK
+	source.Detail = fmt.Sprintf("%d issues seen in the window, %d first seen in it (query: %s)", len(issues), report.IssuesNew, query)
+	if len(target.SentryProjects) == 0 {
+		source.Detail += "; not scoped to the service's projects"
+	}
+	return source
+}
+
+// sentryTime formats a time for Sentry's search syntax
+func sentryTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05")
+}
+
+// count returns an issue's event count
+func count(issue sentry.Issue) int64 {
+	n, _ := strconv.ParseInt(issue.Count, 10, 64)
+	return n
+}
+
+// collectLogs analyzes the error lines of the service's streams in the window
+func (v *Investigator) collectLogs(target Target, report *Report) Source {
+	source := Source{Name: "loki"}
+	switch {
+	case v.logs == nil:
+		source.Status, source.Detail = StatusSkipped, "the loki provider is not running"
+		return source
+	case target.LokiSelector == "":
+		source.Status, source.Detail = StatusSkipped, "no Loki selector for the service (loki_labels in the catalog or loki_selector)"
+		return source
+	}
+
+	query := target.LokiSelector + " " + errorLineFilter
+	response, err := v.logs.QueryRange(loki.QueryParams{
+		Query:     query,
+		Start:     target.Start,
+		End:       target.End,
+		Limit:     maxErrorLines,
+		Direction: "backward",
+	})
+	if err != nil {
+		source.Status, source.Detail = StatusError, err.Error()
+		return source
+	}
+	lines, err := loki.StreamLines(response)
+	if err != nil {
+		source.Status, source.Detail = StatusError, err.Error()
+		return source
+	}
+
+	logs := &Logs{Query: query, Truncated: len(lines) >= maxErrorLines, Analysis: loki.Analyze(lines, loki.AnalyzeOptions{From: target.Start, To: target.End})}
+	for i, bucket := range logs.Analysis.Histogram {
+		if bucket.Count > 0 && (logs.Peak == nil || bucket.Count > logs.Peak.Count) {
+			logs.Peak = &logs.Analysis.Histogram[i]
+		}
+	}
+	report.Logs = logs
+
+	source.Status = StatusOK
+	source.Detail = fmt.Sprintf("%d error lines", len(lines))
+	if logs.Truncated {
+		source.Detail += fmt.Sprintf(" (the newest %d; earlier lines were not read)", maxErrorLines)
+	}
+	return source
+}
+
+// collectDeploys lists the CI runs of the service's repositories from the lookback before the window to its end
+func (v *Investigator) collectDeploys(target Target, report *Report) Source {
+	source := Source{Name: "cicd"}
+	switch {
+	case v.runs == nil:
+		source.Status, source.Detail = StatusSkipped, "the cicd provider is not running"
+		return source
+	case len(target.Repos) == 0:
+		source.Status, source.Detail = StatusSkipped, "no repositories for the service (repos in the catalog or the repos argument)"
+		return source
+	}
+
+	from := target.Start.Add(-target.DeployLookback)
+	var failures []string
+	for _, repo := range target.Repos {
+		system, path, err := ciRepo(repo, v.runs.Systems())
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		runs, err := v.runs.ListRuns(system, path, "", runsPerRepo)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", repo, err))
+			continue
+		}
+		for _, run := range runs {
+			at := run.UpdatedAt
+			if at == nil {
+				at = run.StartedAt
+			}
+			if at == nil || at.Before(from) || at.After(target.End) {
+				continue
+			}
+			deploy := Deploy{
+				System: system, Repo: path, ID: run.ID, Name: run.Name, Status: run.Status, Conclusion: run.Conclusion,
+				Branch: run.Branch, Commit: run.Commit, URL: run.URL, StartedAt: run.StartedAt,
+			}
+			if finished(run) {
+				deploy.FinishedAt = run.UpdatedAt
+			}
+			report.Deploys = append(report.Deploys, deploy)
+		}
+	}
+	sort.Slice(report.Deploys, func(i, j int) bool { return deployTime(report.Deploys[i]).Before(deployTime(report.Deploys[j])) })
+
+	if len(failures) == len(target.Repos) {
+		source.Status, source.Detail = StatusError, strings.Join(failures, "; ")
+		return source
+	}
+	source.Status = StatusOK
+	source.Detail = fmt.Sprintf("%d runs since %s", len(report.Deploys), from.UTC().Format(time.RFC3339))
+	if len(failures) > 0 {
+		source.Detail += "; failed: " + strings.Join(failures, "; ")
+	}
+	return source
+}
+
+// ciRepo maps a catalog repository to a CI system and the repository path it knows
+func ciRepo(repo string, systems []string) (string, string, error) {
+	hosts := map[string]string{"github.com/": "github", "gitlab.com/": "gitlab"}
+	for prefix, system := range hosts {
+		if path, ok := strings.CutPrefix(repo, prefix); ok {
+			for _, configured := range systems {
+				if configured == system {
+					return system, path, nil
+				}
+			}
+			return "", "", fmt.Errorf("%s: the %s CI system is not configured", repo, system)
+		}
+	}
+	if len(systems) == 1 && !strings.Contains(strings.SplitN(repo, "/", 2)[0], ".") {
+		return systems[0], repo, nil
+	}
+	return "", "", fmt.Errorf("%s: no CI system for this repository", repo)
+}
+
+// finished reports whether a run has ended, whatever its CI system calls the states
+func finished(run cicd.Run) bool {
+	if run.Conclusion != "" {
+		return true
+	}
+	switch strings.ToLower(run.Status) {
+	case "completed", "success", "failed", "failure", "canceled", "cancelled", "skipped", "aborted", "unstable":
+		return true
+	}
+	return false
+}
+
+// deployTime is when a run finished, else when it started
+func deployTime(d Deploy) time.Time {
+	if d.FinishedAt != nil {
+		return *d.FinishedAt
+	}
+	if d.StartedAt != nil {
+		return *d.StartedAt
+	}
+	return time.Time{}
+}
+
+// timeline orders the deploys, the issues first seen in the window and the error log milestones
+func timeline(report *Report) []Event {
+	events := []Event{}
+	for _, d := range report.Deploys {
+		summary := fmt.Sprintf("%s run %s of %s", d.System, d.ID, d.Repo)
+		if d.Name != "" {
+			summary += fmt.Sprintf(" (%s)", d.Name)
+		}
+		state := d.Status
+		if d.Conclusion != "" {
+			state = d.Conclusion
+		}
+		if d.FinishedAt != nil {
+			summary += " finished: " + state
+		} else {
+			summary += " started: " + state
+		}
+		events = append(events, Event{Time: deployTime(d), Source: "cicd", Kind: "deploy", Summary: summary})
+	}
+	for _, issue := range report.Issues {
+		if issue.NewInWindow {
+			events = append(events, Event{
+				Time: issue.FirstSeen, Source: "sentry", Kind: "new_issue",
+				Summary: fmt.Sprintf("%s first seen: %s (%s events)", issue.ShortID, issue.Title, issue.Events),
+			})
+		}
+	}
+	if report.Logs != nil && report.Logs.Analysis.Lines > 0 {
+		analysis := report.Logs.Analysis
+		first := "first error line in the window"
+		if len(analysis.TopErrors) > 0 {
+			first += ": " + analysis.TopErrors[0].Pattern
+		}
+		events = append(events, Event{Time: analysis.From, Source: "loki", Kind: "first_error", Summary: first})
+		if peak := report.Logs.Peak; peak != nil {
+			// The peak's bucket may start before the first line
+			at := peak.Start
+			if at.Before(analysis.From) {
+				at = analysis.From
+			}
+			events = append(events, Event{
+				Time: at, Source: "loki", Kind: "error_peak",
+				Summary: fmt.Sprintf("peak of %d error lines in %s", peak.Count, analysis.Bucket),
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+// correlate attributes each new issue and error milestone to the last deploy that finished within the
+// correlation window before it
+func correlate(events []Event) []string {
+	correlations := []string{}
+	var lastDeploy *Event
+	for i := range events {
+		event := &events[i]
+		if event.Kind == "deploy" {
+			lastDeploy = event
+			continue
+		}
+		if lastDeploy == nil || event.Time.Sub(lastDeploy.Time) > correlationWindow {
+			continue
+		}
+		correlations = append(correlations, fmt.Sprintf("%s %s after %s", describe(event), roundDuration(event.Time.Sub(lastDeploy.Time)), lastDeploy.Summary))
+	}
+	return correlations
+}
+
+// describe names an event in a correlation
+func describe(event *Event) string {
+	switch event.Kind {
+	case "new_issue":
+		return "Sentry issue " + strings.SplitN(event.Summary, " ", 2)[0] + " appeared"
+	case "first_error":
+		return "Error logs began"
+	case "error_peak":
+		return "Error logs peaked"
+	}
+	return event.Summary
+}
+
+// roundDuration formats a delay to the minute, or to the second under a minute, as 5m rather than 5m0s
+func roundDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	text := d.Round(time.Minute).String()
+	text = strings.TrimSuffix(text, "0s")
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// summarize states what the window holds in one sentence per source
+func summarize(report *Report) string {
+	var parts []string
+	if report.IssuesTotal > 0 {
+		parts = append(parts, fmt.Sprintf("%d Sentry issues active (%d new)", report.IssuesTotal, report.IssuesNew))
+	}
+	if report.Logs != nil {
+		parts = append(parts, fmt.Sprintf("%d error log lines", report.Logs.Analysis.Lines))
+	}
+	if len(report.Deploys) > 0 {
+		parts = append(parts, fmt.Sprintf("%d CI runs", len(report.Deploys)))
+	}
+	if len(parts) == 0 {
+		return "no signals found in the window"
+	}
+	summary := strings.Join(parts, ", ")
+	if len(report.Correlations) > 0 {
+		summary += "; " + report.Correlations[0]
+	}
+	return summary
+}
@@ -0,0 +1,189 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// maxResponseBytes bounds a single broker response
+const maxResponseBytes = 64 << 20
+
+// versionRanges are the request versions the client knows how to use, by API key. The version
+// sent is the highest one both sides support; Fetch starts at v4, the first to return v2 record batches.
+var versionRanges = map[int16][2]int16{
+	1:  {4, 12}, // Fetch (v13+ identify topics by ID)
+	2:  {1, 7},  // ListOffsets
+	3:  {1, 12}, // Metadata
+	9:  {1, 7},  // OffsetFetch (v8+ batch groups)
+	10: {0, 3},  // FindCoordinator (v4+ batch keys)
+	15: {0, 5},  // DescribeGroups
+	16: {0, 4},  // ListGroups
+	17: {1, 1},  // SASLHandshake
+	18: {0, 0},  // ApiVersions
+	32: {0, 4},  // DescribeConfigs
+	36: {0, 1},  // SASLAuthenticate
+}
+
+// conn is a connection to one broker
+type conn struct {
+	addr          string
+	netConn       net.Conn
+	formatter     *kmsg.RequestFormatter
+	correlationID int32
+	timeout       time.Duration
+	versions      map[int16][2]int16 // API key -> broker's min and max version
+}
+
+// dial connects to a broker, negotiates API versions and authenticates
+func (c *KafkaClient) dial(ctx context.Context, addr string) (*conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to broker %s: %w", addr, err)
+	}
+	if c.tlsConfig != nil {
+		cfg := c.tlsConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName, _, _ = net.SplitHostPort(addr)
+		}
+		tlsConn := tls.Client(netConn, cfg)
+		handshakeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		err := tlsConn.HandshakeContext(handshakeCtx)
+		cancel()
+		if err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("TLS handshake with broker %s failed: %w", addr, err)
+		}
+		netConn = tlsConn
+	}
+
+	cn := &conn{
+		addr:      addr,
+		netConn:   netConn,
+		formatter: kmsg.NewRequestFormatter(kmsg.FormatterClientID(c.clientID)),
+		timeout:   c.timeout,
+	}
+
+	apiVersions := kmsg.NewPtrApiVersionsRequest()
+	apiVersions.SetVersion(0)
+	resp, err := cn.roundTrip(ctx, apiVersions)
+	if err != nil {
+		cn.close()
+		return nil, err
+	}
+	versionsResp := resp.(*kmsg.ApiVersionsResponse)
+	if versionsResp.ErrorCode != 0 {
+		cn.close()
+		return nil, fmt.Errorf("broker %s: ApiVersions: %w", addr, kafkaError(versionsResp.ErrorCode))
+	}
+	cn.versions = make(map[int16][2]int16, len(versionsResp.ApiKeys))
+	for _, key := range versionsResp.ApiKeys {
+		cn.versions[key.ApiKey] = [2]int16{key.MinVersion, key.MaxVersion}
+	}
+
+	if c.sasl != nil {
+		if err := c.sasl.authenticate(ctx, cn); err != nil {
+			cn.close()
+			return nil, fmt.Errorf("broker %s: SASL authentication failed: %w", addr, err)
+		}
+	}
+	return cn, nil
+}
+
+// request sends req at the highest version supported by both the broker and the client
+func (cn *conn) request(ctx context.Context, req kmsg.Request) (kmsg.Response, error) {
+	ours, ok := versionRanges[req.Key()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported request key %d", req.Key())
+	}
+	broker, ok := cn.versions[req.Key()]
+	if !ok {
+		return nil, fmt.Errorf("broker %s does not support %s requests", cn.addr, kmsg.NameForKey(req.Key()))
+	}
+	version := min(ours[1], broker[1], req.MaxVersion())
+	if version < max(ours[0], broker[0]) {
+		return nil, fmt.Errorf("broker %s supports %s versions %d-%d, the client %d-%d", cn.addr, kmsg.NameForKey(req.Key()), broker[0], broker[1], ours[0], ours[1])
+	}
+	req.SetVersion(version)
+	return cn.roundTrip(ctx, req)
+}
+
+// roundTrip writes one request and reads its response
+func (cn *conn) roundTrip(ctx context.Context, req kmsg.Request) (kmsg.Response, error) {
+	deadline := time.Now().Add(cn.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := cn.netConn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	cn.correlationID++
+	if _, err := cn.netConn.Write(cn.formatter.AppendRequest(nil, req, cn.correlationID)); err != nil {
+		return nil, fmt.Errorf("broker %s: failed to send %s: %w", cn.addr, kmsg.NameForKey(req.Key()), err)
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(cn.netConn, size[:]); err != nil {
+		return nil, fmt.Errorf("broker %s: failed to read %s response: %w", cn.addr, kmsg.NameForKey(req.Key()), err)
+	}
+	n := int32(binary.BigEndian.Uint32(size[:]))
+	if n < 4 || n > maxResponseBytes {
+		return nil, fmt.Errorf("broker %s: invalid response size %d", cn.addr, n)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(cn.netConn, body); err != nil {
+		return nil, fmt.Errorf("broker %s: failed to read %s response: %w", cn.addr, kmsg.NameForKey(req.Key()), err)
+	}
+	if id := int32(binary.BigEndian.Uint32(body)); id != cn.correlationID {
+		return nil, fmt.Errorf("broker %s: response correlation ID %d does not match request %d", cn.addr, id, cn.correlationID)
+	}
+	body = body[4:]
+
+	// Flexible responses have tagged fields in the header, except ApiVersions which is always v0
+	if req.IsFlexible() && req.Key() != 18 {
+		var err error
+		if body, err = skipTags(body); err != nil {
+			return nil, fmt.Errorf("broker %s: %w", cn.addr, err)
+		}
+	}
+
+	resp := req.ResponseKind()
+	resp.SetVersion(req.GetVersion())
+	if err := resp.ReadFrom(body); err != nil {
+		return nil, fmt.Errorf("broker %s: failed to parse %s response: %w", cn.addr, kmsg.NameForKey(req.Key()), err)
+	}
+	return resp, nil
+}
+
+func (cn *conn) close() {
+	cn.netConn.Close()
+}
+
+// skipTags skips a tagged field section: a count, then a tag, size and value for each field
+func skipTags(b []byte) ([]byte, error) {
+	count, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid response header")
+	}
+	b = b[n:]
+	for range count {
+		if _, n = binary.Uvarint(b); n <= 0 {
+			return nil, fmt.Errorf("invalid response header")
+		}
+		b = b[n:]
+		size, n := binary.Uvarint(b)
+		if n <= 0 || size > uint64(len(b)-n) {
+			return nil, fmt.Errorf("invalid response header")
+		}
+		b = b[n+int(size):]
+	}
+	return b, nil
+}
@@ -0,0 +1,243 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// maxDescribeGroups bounds the consumer groups whose offsets kafka_describe_topic fetches
+const maxDescribeGroups = 200
+
+// keyTopicConfigs are shown by kafka_describe_topic even when they are not overridden on the topic
+var keyTopicConfigs = map[string]bool{
+	"cleanup.policy":      true,
+	"retention.ms":        true,
+	"retention.bytes":     true,
+	"min.insync.replicas": true,
+	"max.message.bytes":   true,
+	"compression.type":    true,
+}
+
+// TopicDescription describes a topic's partitions, configuration and the consumer groups reading it
+type TopicDescription struct {
+	Topic             string            `json:"topic"`
+	Internal          bool              `json:"internal,omitempty"`
+	ReplicationFactor int               `json:"replication_factor"`
+	Messages          int64             `json:"messages"` // Sum over partitions of end minus start offset
+	Partitions        []PartitionInfo   `json:"partitions"`
+	Configs           map[string]string `json:"configs,omitempty"`
+	Overrides         []string          `json:"overrides,omitempty"` // Configs set on the topic itself
+	Groups            []TopicGroupLag   `json:"groups,omitempty"`
+}
+
+// PartitionInfo describes one partition of a topic
+type PartitionInfo struct {
+	Partition       int32   `json:"partition"`
+	Leader          int32   `json:"leader"` // -1 when offline
+	Replicas        []int32 `json:"replicas"`
+	ISR             []int32 `json:"isr"`
+	UnderReplicated bool    `json:"under_replicated,omitempty"`
+	Start           int64   `json:"start_offset"`
+	End             int64   `json:"end_offset"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// TopicGroupLag is the lag of a consumer group on one topic
+type TopicGroupLag struct {
+	Group      string         `json:"group"`
+	State      string         `json:"state,omitempty"`
+	Lag        int64          `json:"lag"`
+	Partitions []PartitionLag `json:"partitions"`
+}
+
+// DescribeTopic describes a topic's partitions with their replicas and offsets, its key and overridden
+// configs and, with withGroups, the lag of every consumer group matching groupPattern that has committed
+// offsets on it, largest first
+func (c *KafkaClient) DescribeTopic(ctx context.Context, topic, groupPattern string, withGroups bool) (*TopicDescription, []string, error) {
+	s := c.newSession()
+	defer s.close()
+
+	meta, err := s.metadata(ctx, []string{topic})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(meta.Topics) != 1 || meta.Topics[0].Topic == nil {
+		return nil, nil, fmt.Errorf("topic %s not found", topic)
+	}
+	t := meta.Topics[0]
+	if t.ErrorCode != 0 {
+		return nil, nil, fmt.Errorf("topic %s: %w", topic, kafkaError(t.ErrorCode))
+	}
+
+	result := &TopicDescription{Topic: topic, Internal: t.IsInternal, Partitions: []PartitionInfo{}}
+	parts := make([]partitionKey, 0, len(t.Partitions))
+	for _, p := range t.Partitions {
+		info := PartitionInfo{
+			Partition:       p.Partition,
+			Leader:          p.Leader,
+			Replicas:        p.Replicas,
+			ISR:             p.ISR,
+			UnderReplicated: len(p.ISR) < len(p.Replicas),
+			Start:           -1,
+			End:             -1,
+		}
+		if p.ErrorCode != 0 {
+			info.Error = kafkaError(p.ErrorCode).Error()
+		}
+		result.ReplicationFactor = max(result.ReplicationFactor, len(p.Replicas))
+		result.Partitions = append(result.Partitions, info)
+		parts = append(parts, partitionKey{topic, p.Partition})
+	}
+	sort.Slice(result.Partitions, func(i, j int) bool { return result.Partitions[i].Partition < result.Partitions[j].Partition })
+
+	leaderOf := leaders(meta)
+	start, warnings := s.listOffsets(ctx, leaderOf, parts, offsetEarliest)
+	end, endWarnings := s.listOffsets(ctx, leaderOf, parts, offsetLatest)
+	warnings = append(warnings, endWarnings...)
+	for i := range result.Partitions {
+		key := partitionKey{topic, result.Partitions[i].Partition}
+		first, okStart := start[key]
+		last, okEnd := end[key]
+		if okStart {
+			result.Partitions[i].Start = first
+		}
+		if okEnd {
+			result.Partitions[i].End = last
+		}
+		if okStart && okEnd {
+			result.Messages += last - first
+		}
+	}
+
+	if err := s.topicConfigs(ctx, result); err != nil {
+		warnings = append(warnings, fmt.Sprintf("configs: %v", err))
+	}
+
+	if withGroups {
+		groupWarnings := s.topicGroupLag(ctx, result, parts, groupPattern, start, end)
+		warnings = append(warnings, groupWarnings...)
+	}
+	return result, warnings, nil
+}
+
+// topicConfigs fills in the key configs of a topic and those overridden on it; sensitive values are masked
+func (s *session) topicConfigs(ctx context.Context, result *TopicDescription) error {
+	cn, err := s.any(ctx)
+	if err != nil {
+		return err
+	}
+	req := kmsg.NewPtrDescribeConfigsRequest()
+	resource := kmsg.NewDescribeConfigsRequestResource()
+	resource.ResourceType = kmsg.ConfigResourceTypeTopic
+	resource.ResourceName = result.Topic
+	req.Resources = []kmsg.DescribeConfigsRequestResource{resource}
+	resp, err := cn.request(ctx, req)
+	if err != nil {
+		return err
+	}
+	described := resp.(*kmsg.DescribeConfigsResponse)
+	if len(described.Resources) != 1 {
+		return fmt.Errorf("unexpected response")
+	}
+	if code := described.Resources[0].ErrorCode; code != 0 {
+		return kafkaError(code)
+	}
+
+	result.Configs = map[string]string{}
+	for _, cfg := range described.Resources[0].Configs {
+		// Before v1 there is no source, only whether the value is the default
+		override := cfg.Source == kmsg.ConfigSourceDynamicTopicConfig || (cfg.Source < 0 && !cfg.IsDefault)
+		if !override && !keyTopicConfigs[cfg.Name] {
+			continue
+		}
+		value := ""
+		switch {
+		case cfg.IsSensitive:
+			value = "***"
+		case cfg.Value != nil:
+			value = *cfg.Value
+		}
+		result.Configs[cfg.Name] = value
+		if override {
+			result.Overrides = append(result.Overrides, cfg.Name)
+		}
+	}
+	sort.Strings(result.Overrides)
+	return nil
+}
+
+// topicGroupLag adds the lag of the consumer groups with committed offsets on the topic
+func (s *session) topicGroupLag(ctx context.Context, result *TopicDescription, parts []partitionKey, groupPattern string, start, end map[partitionKey]int64) []string {
+	groups, warnings := s.listGroups(ctx)
+	var candidates []GroupSummary
+	for _, g := range groups {
+		// Groups with an empty protocol type commit offsets without using the group protocol
+		if (g.ProtocolType == "consumer" || g.ProtocolType == "") && matchTopic(groupPattern, g.Group) {
+			candidates = append(candidates, g)
+		}
+	}
+	if len(candidates) > maxDescribeGroups {
+		warnings = append(warnings, fmt.Sprintf("%d consumer groups match; only the first %d were checked, narrow them with group", len(candidates), maxDescribeGroups))
+		candidates = candidates[:maxDescribeGroups]
+	}
+
+	for _, g := range candidates {
+		coordinator, err := s.coordinator(ctx, g.Group)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		fetch := kmsg.NewPtrOffsetFetchRequest()
+		fetch.Group = g.Group
+		topic := kmsg.NewOffsetFetchRequestTopic()
+		topic.Topic = result.Topic
+		for _, p := range parts {
+			topic.Partitions = append(topic.Partitions, p.partition)
+		}
+		fetch.Topics = []kmsg.OffsetFetchRequestTopic{topic}
+		resp, err := coordinator.request(ctx, fetch)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("group %s: %v", g.Group, err))
+			continue
+		}
+		committedResp := resp.(*kmsg.OffsetFetchResponse)
+		if committedResp.ErrorCode != 0 {
+			warnings = append(warnings, fmt.Sprintf("group %s: %v", g.Group, kafkaError(committedResp.ErrorCode)))
+			continue
+		}
+
+		groupLag := TopicGroupLag{Group: g.Group, State: g.State}
+		for _, t := range committedResp.Topics {
+			for _, p := range t.Partitions {
+				// Partitions the group never committed come back with offset -1
+				if p.Offset < 0 && p.ErrorCode == 0 {
+					continue
+				}
+				key := partitionKey{t.Topic, p.Partition}
+				lag := partitionLag(key, p.Offset, start, end)
+				if p.ErrorCode != 0 {
+					lag.Error = kafkaError(p.ErrorCode).Error()
+				}
+				groupLag.Partitions = append(groupLag.Partitions, lag)
+				if lag.Lag > 0 {
+					groupLag.Lag += lag.Lag
+				}
+			}
+		}
+		if len(groupLag.Partitions) == 0 {
+			continue
+		}
+		sort.Slice(groupLag.Partitions, func(i, j int) bool { return groupLag.Partitions[i].Partition < groupLag.Partitions[j].Partition })
+		result.Groups = append(result.Groups, groupLag)
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		if result.Groups[i].Lag != result.Groups[j].Lag {
+			return result.Groups[i].Lag > result.Groups[j].Lag
+		}
+		return result.Groups[i].Group < result.Groups[j].Group
+	})
+	return warnings
+}
@@ -0,0 +1,44 @@
+package kafka
+
+import "fmt"
+
+// errorNames are the Kafka protocol error codes the tools are likely to meet
+var errorNames = map[int16]string{
+	-1:  "UNKNOWN_SERVER_ERROR",
+	1:   "OFFSET_OUT_OF_RANGE",
+	2:   "CORRUPT_MESSAGE",
+	3:   "UNKNOWN_TOPIC_OR_PARTITION",
+	5:   "LEADER_NOT_AVAILABLE",
+	6:   "NOT_LEADER_OR_FOLLOWER",
+	7:   "REQUEST_TIMED_OUT",
+	9:   "REPLICA_NOT_AVAILABLE",
+	14:  "COORDINATOR_LOAD_IN_PROGRESS",
+	15:  "COORDINATOR_NOT_AVAILABLE",
+	16:  "NOT_COORDINATOR",
+	17:  "INVALID_TOPIC_EXCEPTION",
+	29:  "TOPIC_AUTHORIZATION_FAILED",
+	30:  "GROUP_AUTHORIZATION_FAILED",
+	31:  "CLUSTER_AUTHORIZATION_FAILED",
+	33:  "UNSUPPORTED_SASL_MECHANISM",
+	34:  "ILLEGAL_SASL_STATE",
+	35:  "UNSUPPORTED_VERSION",
+	58:  "SASL_AUTHENTICATION_FAILED",
+	69:  "GROUP_ID_NOT_FOUND",
+	74:  "FENCED_LEADER_EPOCH",
+	75:  "UNKNOWN_LEADER_EPOCH",
+	100: "UNKNOWN_TOPIC_ID",
+}
+
+// KafkaError is an error code returned by a broker
+type KafkaError int16
+
+func (e KafkaError) Error() string {
+	if name, ok := errorNames[int16(e)]; ok {
+		return name
+	}
+	return fmt.Sprintf("Kafka error code %d", int16(e))
+}
+
+func kafkaError(code int16) error {
+	return KafkaError(code)
+}
@@ -0,0 +1,739 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	// fetchMaxBytes bounds one fetch response; partitions get fetchPartitionBytes each
+	fetchMaxBytes       = 16 << 20
+	fetchPartitionBytes = 1 << 20
+	// maxFetchRounds bounds the fetches made for one peek
+	maxFetchRounds = 20
+
+	offsetLatest   = -1
+	offsetEarliest = -2
+)
+
+// KafkaClient reads cluster metadata, consumer group offsets and messages. It never commits
+// offsets or joins groups: every tool call opens its own connections and closes them afterwards.
+type KafkaClient struct {
+	brokers   []string
+	clientID  string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+	sasl      *saslAuth
+	initErr   error
+}
+
+// TopicSummary describes a topic
+type TopicSummary struct {
+	Name              string `json:"name"`
+	Partitions        int    `json:"partitions"`
+	ReplicationFactor int    `json:"replication_factor"`
+	Internal          bool   `json:"internal,omitempty"`
+	UnderReplicated   int    `json:"under_replicated_partitions,omitempty"`
+	Offline           int    `json:"offline_partitions,omitempty"`
+	Messages          *int64 `json:"messages,omitempty"` // Sum over partitions of end minus start offset
+	Error             string `json:"error,omitempty"`
+}
+
+// GroupSummary describes a consumer group
+type GroupSummary struct {
+	Group        string `json:"group"`
+	ProtocolType string `json:"protocol_type,omitempty"`
+	State        string `json:"state,omitempty"`
+}
+
+// GroupLag is the lag of a consumer group on each partition it has committed offsets for
+type GroupLag struct {
+	Group    string        `json:"group"`
+	State    string        `json:"state"`
+	Protocol string        `json:"protocol,omitempty"`
+	Members  []GroupMember `json:"members"`
+	TotalLag int64         `json:"total_lag"`
+	Topics   []TopicLag    `json:"topics"`
+}
+
+// GroupMember is a member of a consumer group and the partitions assigned to it
+type GroupMember struct {
+	MemberID   string           `json:"member_id"`
+	ClientID   string           `json:"client_id"`
+	ClientHost string           `json:"client_host"`
+	Assignment map[string][]int `json:"assignment,omitempty"`
+}
+
+// TopicLag is a group's lag on one topic
+type TopicLag struct {
+	Topic      string         `json:"topic"`
+	Lag        int64          `json:"lag"`
+	Partitions []PartitionLag `json:"partitions"`
+}
+
+// PartitionLag is a group's position on one partition
+type PartitionLag struct {
+	Partition int32  `json:"partition"`
+	Committed int64  `json:"committed"` // -1 when the group has no offset for the partition
+	End       int64  `json:"end"`
+	Lag       int64  `json:"lag"`
+	Member    string `json:"member,omitempty"` // Client ID of the member consuming the partition
+	Error     string `json:"error,omitempty"`
+}
+
+// PeekResult holds the most recent records of a topic
+type PeekResult struct {
+	Records  []Record
+	Ranges   map[int32][2]int64 // Partition -> start and end offset peeked
+	Warnings []string
+}
+
+// NewKafkaClient creates a new Kafka client from config; without brokers it is not available
+func NewKafkaClient(cfg *config.KafkaConfig) *KafkaClient {
+	c := &KafkaClient{clientID: "dev-mcp", timeout: defaultTimeout}
+	if cfg == nil {
+		return c
+	}
+	for _, broker := range cfg.Brokers {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			c.brokers = append(c.brokers, broker)
+		}
+	}
+	if cfg.ClientID != "" {
+		c.clientID = cfg.ClientID
+	}
+	if cfg.TimeoutSeconds > 0 {
+		c.timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	if cfg.TLS || cfg.CAFile != "" {
+		c.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: cfg.TLSSkipVerify}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				c.initErr = fmt.Errorf("failed to read CA file: %w", err)
+				return c
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				c.initErr = fmt.Errorf("no certificates found in CA file %s", cfg.CAFile)
+				return c
+			}
+			c.tlsConfig.RootCAs = pool
+		}
+	}
+
+	sasl, err := newSASLAuth(cfg.SASLMechanism, cfg.Username, cfg.Password)
+	if err != nil {
+		c.initErr = err
+		return c
+	}
+	c.sasl = sasl
+	return c
+}
+
+// IsAvailable returns whether brokers are configured and the settings are valid
+func (c *KafkaClient) IsAvailable() bool {
+	return len(c.brokers) > 0 && c.initErr == nil
+}
+
+// InitError returns the configuration error, if any
+func (c *KafkaClient) InitError() error {
+	return c.initErr
+}
+
+// Brokers returns the bootstrap brokers
+func (c *KafkaClient) Brokers() []string {
+	return c.brokers
+}
+
+// Close releases resources held by the client
+func (c *KafkaClient) Close() error {
+	return nil
+}
+
+// session holds the broker connections of one tool call
+type session struct {
+	client  *KafkaClient
+	conns   map[string]*conn
+	brokers map[int32]string // Node ID -> address, from the last metadata response
+}
+
+func (c *KafkaClient) newSession() *session {
+	return &session{client: c, conns: map[string]*conn{}, brokers: map[int32]string{}}
+}
+
+func (s *session) close() {
+	for _, cn := range s.conns {
+		cn.close()
+	}
+}
+
+// broker returns a connection to addr, dialing it on first use
+func (s *session) broker(ctx context.Context, addr string) (*conn, error) {
+	if cn, ok := s.conns[addr]; ok {
+		return cn, nil
+	}
+	cn, err := s.client.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	s.conns[addr] = cn
+	return cn, nil
+}
+
+// node returns a connection to a broker by node ID
+func (s *session) node(ctx context.Context, id int32) (*conn, error) {
+	addr, ok := s.brokers[id]
+	if !ok {
+		return nil, fmt.Errorf("broker %d is not in the cluster metadata", id)
+	}
+	return s.broker(ctx, addr)
+}
+
+// any returns a connection to the first reachable bootstrap broker
+func (s *session) any(ctx context.Context) (*conn, error) {
+	for _, cn := range s.conns {
+		return cn, nil
+	}
+	var errs []error
+	for _, addr := range s.client.brokers {
+		cn, err := s.broker(ctx, addr)
+		if err == nil {
+			return cn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// metadata fetches the brokers and the given topics (all topics when nil)
+func (s *session) metadata(ctx context.Context, topics []string) (*kmsg.MetadataResponse, error) {
+	cn, err := s.any(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := kmsg.NewPtrMetadataRequest()
+	if topics != nil {
+		req.Topics = make([]kmsg.MetadataRequestTopic, 0, len(topics))
+		for _, topic := range topics {
+			t := kmsg.NewMetadataRequestTopic()
+			t.Topic = kmsg.StringPtr(topic)
+			req.Topics = append(req.Topics, t)
+		}
+	}
+	resp, err := cn.request(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	meta := resp.(*kmsg.MetadataResponse)
+	for _, b := range meta.Brokers {
+		s.brokers[b.NodeID] = net.JoinHostPort(b.Host, strconv.Itoa(int(b.Port)))
+	}
+	return meta, nil
+}
+
+// partitionKey identifies a partition
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+// leaders maps each partition in the metadata to its leader's node ID
+func leaders(meta *kmsg.MetadataResponse) map[partitionKey]int32 {
+	result := map[partitionKey]int32{}
+	for _, t := range meta.Topics {
+		if t.Topic == nil {
+			continue
+		}
+		for _, p := range t.Partitions {
+			result[partitionKey{*t.Topic, p.Partition}] = p.Leader
+		}
+	}
+	return result
+}
+
+// listOffsets returns the earliest or latest offset of each partition, asking each partition's
+// leader. Partitions that fail are left out and described in the warnings.
+func (s *session) listOffsets(ctx context.Context, leaderOf map[partitionKey]int32, parts []partitionKey, timestamp int64) (map[partitionKey]int64, []string) {
+	byLeader := map[int32][]partitionKey{}
+	var warnings []string
+	for _, p := range parts {
+		leader, ok := leaderOf[p]
+		if !ok || leader < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s/%d: no leader", p.topic, p.partition))
+			continue
+		}
+		byLeader[leader] = append(byLeader[leader], p)
+	}
+
+	offsets := map[partitionKey]int64{}
+	for leader, leaderParts := range byLeader {
+		req := kmsg.NewPtrListOffsetsRequest()
+		topics := map[string]int{}
+		for _, p := range leaderParts {
+			i, ok := topics[p.topic]
+			if !ok {
+				t := kmsg.NewListOffsetsRequestTopic()
+				t.Topic = p.topic
+				req.Topics = append(req.Topics, t)
+				i = len(req.Topics) - 1
+				topics[p.topic] = i
+			}
+			rp := kmsg.NewListOffsetsRequestTopicPartition()
+			rp.Partition = p.partition
+			rp.Timestamp = timestamp
+			req.Topics[i].Partitions = append(req.Topics[i].Partitions, rp)
+		}
+
+		cn, err := s.node(ctx, leader)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		resp, err := cn.request(ctx, req)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		for _, t := range resp.(*kmsg.ListOffsetsResponse).Topics {
+			for _, p := range t.Partitions {
+				if p.ErrorCode != 0 {
+					warnings = append(warnings, fmt.Sprintf("%s/%d: %v", t.Topic, p.Partition, kafkaError(p.ErrorCode)))
+					continue
+				}
+				offsets[partitionKey{t.Topic, p.Partition}] = p.Offset
+			}
+		}
+	}
+	return offsets, warnings
+}
+
+// matchTopic matches a glob pattern, or a case-insensitive substring when there are no glob characters
+func matchTopic(pattern, topic string) bool {
+	if pattern == "" {
+		return true
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, err := path.Match(pattern, topic)
+		return err == nil && matched
+	}
+	return strings.Contains(strings.ToLower(topic), strings.ToLower(pattern))
+}
+
+// ListTopics lists the topics matching pattern, optionally with their message counts
+func (c *KafkaClient) ListTopics(ctx context.Context, pattern string, includeInternal, withCounts bool) ([]TopicSummary, []string, error) {
+	s := c.newSession()
+	defer s.close()
+
+	meta, err := s.metadata(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var topics []TopicSummary
+	var parts []partitionKey
+	for _, t := range meta.Topics {
+		if t.Topic == nil || !matchTopic(pattern, *t.Topic) || (t.IsInternal && !includeInternal) {
+			continue
+		}
+		summary := TopicSummary{Name: *t.Topic, Partitions: len(t.Partitions), Internal: t.IsInternal}
+		if t.ErrorCode != 0 {
+			summary.Error = kafkaError(t.ErrorCode).Error()
+		}
+		for _, p := range t.Partitions {
+			summary.ReplicationFactor = max(summary.ReplicationFactor, len(p.Replicas))
+			if len(p.ISR) < len(p.Replicas) {
+				summary.UnderReplicated++
+			}
+			if p.Leader < 0 {
+				summary.Offline++
+			}
+			parts = append(parts, partitionKey{*t.Topic, p.Partition})
+		}
+		topics = append(topics, summary)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+
+	if !withCounts || len(parts) == 0 {
+		return topics, nil, nil
+	}
+	leaderOf := leaders(meta)
+	start, startWarnings := s.listOffsets(ctx, leaderOf, parts, offsetEarliest)
+	end, endWarnings := s.listOffsets(ctx, leaderOf, parts, offsetLatest)
+	counts := map[string]int64{}
+	for _, p := range parts {
+		first, okStart := start[p]
+		last, okEnd := end[p]
+		if okStart && okEnd {
+			counts[p.topic] += last - first
+		}
+	}
+	for i := range topics {
+		if count, ok := counts[topics[i].Name]; ok {
+			topics[i].Messages = &count
+		}
+	}
+	return topics, append(startWarnings, endWarnings...), nil
+}
+
+// ListGroups lists the consumer groups of every broker
+func (c *KafkaClient) ListGroups(ctx context.Context) ([]GroupSummary, []string, error) {
+	s := c.newSession()
+	defer s.close()
+
+	if _, err := s.metadata(ctx, []string{}); err != nil {
+		return nil, nil, err
+	}
+	groups, warnings := s.listGroups(ctx)
+	return groups, warnings, nil
+}
+
+// listGroups lists the consumer groups of every broker in the metadata, sorted by name
+func (s *session) listGroups(ctx context.Context) ([]GroupSummary, []string) {
+	var groups []GroupSummary
+	var warnings []string
+	for id := range s.brokers {
+		cn, err := s.node(ctx, id)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		resp, err := cn.request(ctx, kmsg.NewPtrListGroupsRequest())
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		listResp := resp.(*kmsg.ListGroupsResponse)
+		if listResp.ErrorCode != 0 {
+			warnings = append(warnings, fmt.Sprintf("broker %d: %v", id, kafkaError(listResp.ErrorCode)))
+			continue
+		}
+		for _, g := range listResp.Groups {
+			groups = append(groups, GroupSummary{Group: g.Group, ProtocolType: g.ProtocolType, State: g.GroupState})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Group < groups[j].Group })
+	return groups, warnings
+}
+
+// ConsumerLag describes a consumer group and its lag on the topics matching topicPattern
+func (c *KafkaClient) ConsumerLag(ctx context.Context, group, topicPattern string) (*GroupLag, []string, error) {
+	s := c.newSession()
+	defer s.close()
+
+	// The coordinator is found through any broker; the metadata also maps node IDs to addresses
+	if _, err := s.metadata(ctx, []string{}); err != nil {
+		return nil, nil, err
+	}
+	coordinator, err := s.coordinator(ctx, group)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	describe := kmsg.NewPtrDescribeGroupsRequest()
+	describe.Groups = []string{group}
+	resp, err := coordinator.request(ctx, describe)
+	if err != nil {
+		return nil, nil, err
+	}
+	described := resp.(*kmsg.DescribeGroupsResponse)
+	if len(described.Groups) != 1 {
+		return nil, nil, fmt.Errorf("describe group %s: unexpected response", group)
+	}
+	g := described.Groups[0]
+	if g.ErrorCode != 0 {
+		return nil, nil, fmt.Errorf("describe group %s: %w", group, kafkaError(g.ErrorCode))
+	}
+
+	result := &GroupLag{Group: group, State: g.State, Protocol: g.Protocol, Members: []GroupMember{}, Topics: []TopicLag{}}
+	owner := map[partitionKey]string{}
+	for _, m := range g.Members {
+		member := GroupMember{MemberID: m.MemberID, ClientID: m.ClientID, ClientHost: m.ClientHost}
+		if g.ProtocolType == "consumer" && len(m.MemberAssignment) > 0 {
+			var assignment kmsg.ConsumerMemberAssignment
+			if assignment.ReadFrom(m.MemberAssignment) == nil {
+				member.Assignment = map[string][]int{}
+				for _, t := range assignment.Topics {
+					for _, p := range t.Partitions {
+						member.Assignment[t.Topic] = append(member.Assignment[t.Topic], int(p))
+						owner[partitionKey{t.Topic, p}] = m.ClientID
+					}
+				}
+			}
+		}
+		result.Members = append(result.Members, member)
+	}
+
+	fetch := kmsg.NewPtrOffsetFetchRequest()
+	fetch.Group = group
+	resp, err = coordinator.request(ctx, fetch)
+	if err != nil {
+		return nil, nil, err
+	}
+	committedResp := resp.(*kmsg.OffsetFetchResponse)
+	if committedResp.ErrorCode != 0 {
+		return nil, nil, fmt.Errorf("fetch offsets of group %s: %w", group, kafkaError(committedResp.ErrorCode))
+	}
+
+	committed := map[partitionKey]int64{}
+	partitionErrors := map[partitionKey]string{}
+	var topics []string
+	for _, t := range committedResp.Topics {
+		if !matchTopic(topicPattern, t.Topic) {
+			continue
+		}
+		topics = append(topics, t.Topic)
+		for _, p := range t.Partitions {
+			key := partitionKey{t.Topic, p.Partition}
+			committed[key] = p.Offset
+			if p.ErrorCode != 0 {
+				partitionErrors[key] = kafkaError(p.ErrorCode).Error()
+			}
+		}
+	}
+	// Assigned partitions without a commit yet also lag
+	for key := range owner {
+		if _, ok := committed[key]; !ok && matchTopic(topicPattern, key.topic) {
+			committed[key] = -1
+			if !slices.Contains(topics, key.topic) {
+				topics = append(topics, key.topic)
+			}
+		}
+	}
+	if len(topics) == 0 {
+		return result, nil, nil
+	}
+
+	meta, err := s.metadata(ctx, topics)
+	if err != nil {
+		return nil, nil, err
+	}
+	parts := make([]partitionKey, 0, len(committed))
+	for key := range committed {
+		parts = append(parts, key)
+	}
+	leaderOf := leaders(meta)
+	end, warnings := s.listOffsets(ctx, leaderOf, parts, offsetLatest)
+	start, startWarnings := s.listOffsets(ctx, leaderOf, parts, offsetEarliest)
+	warnings = append(warnings, startWarnings...)
+
+	byTopic := map[string]*TopicLag{}
+	for _, key := range parts {
+		lag := partitionLag(key, committed[key], start, end)
+		lag.Member = owner[key]
+		lag.Error = partitionErrors[key]
+		t := byTopic[key.topic]
+		if t == nil {
+			t = &TopicLag{Topic: key.topic}
+			byTopic[key.topic] = t
+		}
+		t.Partitions = append(t.Partitions, lag)
+		if lag.Lag > 0 {
+			t.Lag += lag.Lag
+		}
+	}
+	for _, t := range byTopic {
+		sort.Slice(t.Partitions, func(i, j int) bool { return t.Partitions[i].Partition < t.Partitions[j].Partition })
+		result.TotalLag += t.Lag
+		result.Topics = append(result.Topics, *t)
+	}
+	sort.Slice(result.Topics, func(i, j int) bool {
+		if result.Topics[i].Lag != result.Topics[j].Lag {
+			return result.Topics[i].Lag > result.Topics[j].Lag
+		}
+		return result.Topics[i].Topic < result.Topics[j].Topic
+	})
+	return result, warnings, nil
+}
+
+// partitionLag computes a group's lag on a partition from its committed offset (-1 without a commit)
+// and the partition's start and end offsets; End and Lag are -1 when the end offset is unknown
+func partitionLag(key partitionKey, committed int64, start, end map[partitionKey]int64) PartitionLag {
+	lag := PartitionLag{Partition: key.partition, Committed: committed, End: -1, Lag: -1}
+	if e, ok := end[key]; ok {
+		lag.End = e
+		position := committed
+		if position < 0 {
+			// Without a commit, the group starts from the earliest or latest offset by its own
+			// setting; the earliest gives the upper bound
+			position = start[key]
+		}
+		if first, ok := start[key]; ok && position < first {
+			// Messages below the start offset were deleted by retention and cannot be consumed
+			position = first
+		}
+		lag.Lag = max(e-position, 0)
+	}
+	return lag
+}
+
+// coordinator returns a connection to the coordinator of a group
+func (s *session) coordinator(ctx context.Context, group string) (*conn, error) {
+	cn, err := s.any(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := kmsg.NewPtrFindCoordinatorRequest()
+	req.CoordinatorKey = group
+	resp, err := cn.request(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	found := resp.(*kmsg.FindCoordinatorResponse)
+	if found.ErrorCode != 0 {
+		return nil, fmt.Errorf("find coordinator of group %s: %w", group, kafkaError(found.ErrorCode))
+	}
+	return s.broker(ctx, net.JoinHostPort(found.Host, strconv.Itoa(int(found.Port))))
+}
+
+// Peek reads the last count records of each of the topic's partitions (or only the given ones)
+// up to their current end offsets
+func (c *KafkaClient) Peek(ctx context.Context, topic string, partitions []int32, count int) (*PeekResult, error) {
+	s := c.newSession()
+	defer s.close()
+
+	meta, err := s.metadata(ctx, []string{topic})
+	if err != nil {
+		return nil, err
+	}
+	if len(meta.Topics) != 1 || meta.Topics[0].Topic == nil {
+		return nil, fmt.Errorf("topic %s not found", topic)
+	}
+	if code := meta.Topics[0].ErrorCode; code != 0 {
+		return nil, fmt.Errorf("topic %s: %w", topic, kafkaError(code))
+	}
+
+	var parts []partitionKey
+	for _, p := range meta.Topics[0].Partitions {
+		if len(partitions) == 0 || slices.Contains(partitions, p.Partition) {
+			parts = append(parts, partitionKey{topic, p.Partition})
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("topic %s has no partition %v", topic, partitions)
+	}
+
+	leaderOf := leaders(meta)
+	start, warnings := s.listOffsets(ctx, leaderOf, parts, offsetEarliest)
+	end, endWarnings := s.listOffsets(ctx, leaderOf, parts, offsetLatest)
+	result := &PeekResult{Ranges: map[int32][2]int64{}, Warnings: append(warnings, endWarnings...)}
+
+	// Fetch position and end of each partition that has records to read
+	position := map[partitionKey]int64{}
+	for _, p := range parts {
+		first, okStart := start[p]
+		last, okEnd := end[p]
+		if !okStart || !okEnd {
+			continue
+		}
+		from := max(first, last-int64(count))
+		result.Ranges[p.partition] = [2]int64{from, last}
+		if from < last {
+			position[p] = from
+		}
+	}
+
+	perPartition := map[int32][]Record{}
+	for round := 0; len(position) > 0 && round < maxFetchRounds; round++ {
+		byLeader := map[int32][]partitionKey{}
+		for p := range position {
+			byLeader[leaderOf[p]] = append(byLeader[leaderOf[p]], p)
+		}
+		for leader, leaderParts := range byLeader {
+			records, next, errs := s.fetch(ctx, leader, topic, leaderParts, position)
+			result.Warnings = append(result.Warnings, errs...)
+			for _, p := range leaderParts {
+				last := result.Ranges[p.partition][1]
+				for _, r := range records[p.partition] {
+					if r.Offset >= position[p] && r.Offset < last {
+						perPartition[p.partition] = append(perPartition[p.partition], r)
+					}
+				}
+				// Stop on errors, when done, or when a fetch made no progress
+				if n, ok := next[p.partition]; ok && n > position[p] && n < last {
+					position[p] = n
+				} else {
+					delete(position, p)
+				}
+			}
+		}
+	}
+	for p := range position {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("partition %d: stopped after %d fetches at offset %d", p.partition, maxFetchRounds, position[p]))
+	}
+
+	for _, records := range perPartition {
+		result.Records = append(result.Records, records...)
+	}
+	return result, nil
+}
+
+// fetch reads one batch of records of the topic's partitions from their leader, returning the
+// records and next offset of each partition
+func (s *session) fetch(ctx context.Context, leader int32, topic string, parts []partitionKey, position map[partitionKey]int64) (map[int32][]Record, map[int32]int64, []string) {
+	req := kmsg.NewPtrFetchRequest()
+	req.MaxWaitMillis = 100
+	req.MinBytes = 1
+	req.MaxBytes = fetchMaxBytes
+	t := kmsg.NewFetchRequestTopic()
+	t.Topic = topic
+	for _, p := range parts {
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.Partition = p.partition
+		rp.FetchOffset = position[p]
+		rp.PartitionMaxBytes = fetchPartitionBytes
+		t.Partitions = append(t.Partitions, rp)
+	}
+	req.Topics = []kmsg.FetchRequestTopic{t}
+
+	cn, err := s.node(ctx, leader)
+	if err != nil {
+		return nil, nil, []string{err.Error()}
+	}
+	resp, err := cn.request(ctx, req)
+	if err != nil {
+		return nil, nil, []string{err.Error()}
+	}
+	fetchResp := resp.(*kmsg.FetchResponse)
+	if fetchResp.ErrorCode != 0 {
+		return nil, nil, []string{fmt.Sprintf("fetch from broker %d: %v", leader, kafkaError(fetchResp.ErrorCode))}
+	}
+
+	records := map[int32][]Record{}
+	next := map[int32]int64{}
+	var warnings []string
+	for _, rt := range fetchResp.Topics {
+		for _, rp := range rt.Partitions {
+			if rp.ErrorCode != 0 {
+				warnings = append(warnings, fmt.Sprintf("partition %d: %v", rp.Partition, kafkaError(rp.ErrorCode)))
+				continue
+			}
+			batch, n, err := parseBatches(rp.Partition, rp.RecordBatches)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("partition %d: %v", rp.Partition, err))
+			}
+			records[rp.Partition] = append(records[rp.Partition], batch...)
+			if n >= 0 {
+				next[rp.Partition] = n
+			}
+		}
+	}
+	return records, next, warnings
+}
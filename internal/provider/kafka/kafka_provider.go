@@ -0,0 +1,645 @@
+package kafka
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+const (
+	defaultPeekCount       = 10
+	defaultMaxPeekMessages = 100
+	defaultMaxValueBytes   = 4096
+	defaultTopicLimit      = 500
+	// maxLagPartitions bounds the partitions listed by kafka_consumer_lag
+	maxLagPartitions = 1000
+)
+
+// ProtoDecoder decodes protobuf payloads with the schemas of the proto provider
+type ProtoDecoder interface {
+	Decoder(path, message string) (func([]byte) (interface{}, error), error)
+}
+
+// KafkaProvider provides read-only Kafka inspection: topics, consumer group lag and recent messages
+type KafkaProvider struct {
+	*provider.BaseProvider
+	client          *KafkaClient
+	proto           ProtoDecoder
+	maxPeekMessages int
+	maxValueBytes   int
+}
+
+// NewKafkaProvider creates a new Kafka provider with config; proto may be nil, which disables the protobuf format
+func NewKafkaProvider(cfg *config.KafkaConfig, proto ProtoDecoder) *KafkaProvider {
+	p := &KafkaProvider{
+		BaseProvider:    provider.NewBaseProvider("kafka"),
+		client:          NewKafkaClient(cfg),
+		proto:           proto,
+		maxPeekMessages: defaultMaxPeekMessages,
+		maxValueBytes:   defaultMaxValueBytes,
+	}
+	if cfg != nil && cfg.MaxPeekMessages > 0 {
+		p.maxPeekMessages = cfg.MaxPeekMessages
+	}
+	if cfg != nil && cfg.MaxValueBytes > 0 {
+		p.maxValueBytes = cfg.MaxValueBytes
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Kafka not configured", p.client.InitError())
+		if err := p.client.InitError(); err != nil {
+			log.Printf("⚠ Kafka provider not available: %v", err)
+		}
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Kafka provider initialized successfully (%d bootstrap brokers)", len(p.client.Brokers()))
+
+	return p
+}
+
+// Test tests the Kafka configuration (for ProviderClient interface compatibility)
+func (p *KafkaProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("kafka provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Kafka tools to the MCP server (for ProviderClient interface compatibility)
+func (p *KafkaProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the Kafka provider
+func (p *KafkaProvider) Close() error {
+	return p.client.Close()
+}
+
+// HealthCheck checks that a bootstrap broker answers a metadata request
+func (p *KafkaProvider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.client.timeout)
+	defer cancel()
+	s := p.client.newSession()
+	defer s.close()
+	_, err := s.metadata(ctx, []string{})
+	return err
+}
+
+// addToolsToServer adds Kafka tools to the MCP server
+func (p *KafkaProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Kafka provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createListTopicsTool(),
+		p.createDescribeTopicTool(),
+		p.createConsumerLagTool(),
+		p.createPeekTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered Kafka tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All Kafka tools registered successfully")
+}
+
+// createListTopicsTool creates the topic listing tool
+func (p *KafkaProvider) createListTopicsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "kafka_list_topics",
+		Description: "List Kafka topics with their partition count, replication factor, under-replicated and offline partitions, and optionally the number of retained messages",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"pattern": {
+					"type": "string",
+					"description": "Glob (orders.*) or case-insensitive substring the topic names must match"
+				},
+				"include_internal": {
+					"type": "boolean",
+					"description": "Include internal topics such as __consumer_offsets",
+					"default": false
+				},
+				"message_counts": {
+					"type": "boolean",
+					"description": "Add each topic's retained message count (end minus start offsets; approximate for compacted topics)",
+					"default": false
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum topics to return",
+					"default": 500
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Pattern         string `json:"pattern,omitempty"`
+			IncludeInternal bool   `json:"include_internal,omitempty"`
+			MessageCounts   bool   `json:"message_counts,omitempty"`
+			Limit           int    `json:"limit,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if args.Limit <= 0 {
+			args.Limit = defaultTopicLimit
+		}
+
+		topics, warnings, err := p.client.ListTopics(ctx, args.Pattern, args.IncludeInternal, args.MessageCounts)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		result := map[string]interface{}{"count": len(topics)}
+		if len(topics) > args.Limit {
+			topics = topics[:args.Limit]
+			result["truncated"] = true
+		}
+		if topics == nil {
+			topics = []TopicSummary{}
+		}
+		result["topics"] = topics
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createConsumerLagTool creates the consumer group lag tool
+func (p *KafkaProvider) createConsumerLagTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "kafka_consumer_lag",
+		Description: "Describe a consumer group: its state, members with their assigned partitions, and the lag (end offset minus committed offset) per topic and partition, largest first. Without group, list the consumer groups and their states",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"group": {
+					"type": "string",
+					"description": "Consumer group ID; omit to list groups"
+				},
+				"topic": {
+					"type": "string",
+					"description": "Only topics matching this glob or substring"
+				},
+				"lagging_only": {
+					"type": "boolean",
+					"description": "List only partitions with lag",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Group       string `json:"group,omitempty"`
+			Topic       string `json:"topic,omitempty"`
+			LaggingOnly bool   `json:"lagging_only,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		if args.Group == "" {
+			groups, warnings, err := p.client.ListGroups(ctx)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			if groups == nil {
+				groups = []GroupSummary{}
+			}
+			result := map[string]interface{}{"groups": groups, "count": len(groups)}
+			if len(warnings) > 0 {
+				result["warnings"] = warnings
+			}
+			return p.formatJSONResult(result), nil
+		}
+
+		lag, warnings, err := p.client.ConsumerLag(ctx, args.Group, args.Topic)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		listed := 0
+		truncated := false
+		for i := range lag.Topics {
+			t := &lag.Topics[i]
+			partitions := t.Partitions[:0]
+			for _, part := range t.Partitions {
+				if args.LaggingOnly && part.Lag <= 0 {
+					continue
+				}
+				if listed >= maxLagPartitions {
+					truncated = true
+					break
+				}
+				partitions = append(partitions, part)
+				listed++
+			}
+			t.Partitions = partitions
+		}
+
+		result := map[string]interface{}{"group": lag}
+		if lag.State == "Dead" && len(lag.Topics) == 0 {
+			result["note"] = "the group has no members and no committed offsets; check the group ID"
+		}
+		if truncated {
+			result["truncated"] = true
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDescribeTopicTool creates the topic description tool
+func (p *KafkaProvider) createDescribeTopicTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "kafka_describe_topic",
+		Description: "Describe a Kafka topic: each partition's leader, replicas, in-sync replicas and start and end offsets, key and overridden configs (retention, cleanup policy, min.insync.replicas), and the lag of every consumer group with committed offsets on the topic, largest first",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"topic": {
+					"type": "string",
+					"description": "Topic to describe"
+				},
+				"group": {
+					"type": "string",
+					"description": "Only consumer groups matching this glob or substring"
+				},
+				"groups": {
+					"type": "boolean",
+					"description": "Look up the consumer groups reading the topic and their lag",
+					"default": true
+				}
+			},
+			"required": ["topic"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Topic  string `json:"topic"`
+			Group  string `json:"group,omitempty"`
+			Groups *bool  `json:"groups,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Topic == "" {
+			return p.createErrorResult(fmt.Errorf("topic parameter is required")), nil
+		}
+		withGroups := args.Groups == nil || *args.Groups
+
+		description, warnings, err := p.client.DescribeTopic(ctx, args.Topic, args.Group, withGroups)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		result := map[string]interface{}{"topic": description}
+		if withGroups && len(description.Groups) == 0 {
+			result["note"] = "no consumer group has committed offsets on this topic"
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createPeekTool creates the recent message peek tool
+func (p *KafkaProvider) createPeekTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "kafka_peek",
+		Description: "Read the last N messages of a topic (newest first) without joining a consumer group or committing offsets. Keys and values are shown as JSON, text, base64, or protobuf decoded with a message type from the proto provider",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"topic": {
+					"type": "string",
+					"description": "Topic to read"
+				},
+				"count": {
+					"type": "integer",
+					"description": "Number of most recent messages",
+					"default": 10
+				},
+				"partition": {
+					"type": "integer",
+					"description": "Read only this partition; all partitions by default"
+				},
+				"value_format": {
+					"type": "string",
+					"description": "How to show values: auto (JSON if valid, else text, else base64), json, string, base64 or protobuf",
+					"enum": ["auto", "json", "string", "base64", "protobuf"],
+					"default": "auto"
+				},
+				"key_format": {
+					"type": "string",
+					"description": "How to show keys",
+					"enum": ["auto", "json", "string", "base64", "protobuf"],
+					"default": "auto"
+				},
+				"proto_path": {
+					"type": "string",
+					"description": "For protobuf: .proto file or directory (within the proto directories)"
+				},
+				"proto_message": {
+					"type": "string",
+					"description": "For protobuf: message type, full or short name (e.g. acme.orders.v1.OrderCreated)"
+				},
+				"schema_registry": {
+					"type": "boolean",
+					"description": "Payloads use the Confluent Schema Registry wire format (magic byte, schema ID, message indexes), which is stripped before decoding",
+					"default": false
+				}
+			},
+			"required": ["topic"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Topic          string `json:"topic"`
+			Count          int    `json:"count,omitempty"`
+			Partition      *int32 `json:"partition,omitempty"`
+			ValueFormat    string `json:"value_format,omitempty"`
+			KeyFormat      string `json:"key_format,omitempty"`
+			ProtoPath      string `json:"proto_path,omitempty"`
+			ProtoMessage   string `json:"proto_message,omitempty"`
+			SchemaRegistry bool   `json:"schema_registry,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Topic == "" {
+			return p.createErrorResult(fmt.Errorf("topic parameter is required")), nil
+		}
+		if args.Count <= 0 {
+			args.Count = defaultPeekCount
+		}
+		if args.Count > p.maxPeekMessages {
+			return p.createErrorResult(fmt.Errorf("count must be at most %d", p.maxPeekMessages)), nil
+		}
+
+		keyDecoder, err := p.newDecoder(args.KeyFormat, args.ProtoPath, args.ProtoMessage, args.SchemaRegistry)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("key_format: %w", err)), nil
+		}
+		valueDecoder, err := p.newDecoder(args.ValueFormat, args.ProtoPath, args.ProtoMessage, args.SchemaRegistry)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("value_format: %w", err)), nil
+		}
+
+		var partitions []int32
+		if args.Partition != nil {
+			partitions = []int32{*args.Partition}
+		}
+		peek, err := p.client.Peek(ctx, args.Topic, partitions, args.Count)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		records := peek.Records
+		sort.Slice(records, func(i, j int) bool {
+			if !records[i].Timestamp.Equal(records[j].Timestamp) {
+				return records[i].Timestamp.After(records[j].Timestamp)
+			}
+			if records[i].Partition != records[j].Partition {
+				return records[i].Partition < records[j].Partition
+			}
+			return records[i].Offset > records[j].Offset
+		})
+		if len(records) > args.Count {
+			records = records[:args.Count]
+		}
+
+		messages := make([]map[string]interface{}, 0, len(records))
+		for _, r := range records {
+			message := map[string]interface{}{
+				"partition": r.Partition,
+				"offset":    r.Offset,
+				"timestamp": r.Timestamp.UTC().Format(time.RFC3339Nano),
+			}
+			if r.Key != nil {
+				keyDecoder.decode(r.Key, "key", message)
+			}
+			if r.Value == nil {
+				message["value"] = nil
+				message["tombstone"] = true
+			} else {
+				valueDecoder.decode(r.Value, "value", message)
+			}
+			if len(r.Headers) > 0 {
+				headers := make([]map[string]string, 0, len(r.Headers))
+				for _, h := range r.Headers {
+					headers = append(headers, map[string]string{"key": h.Key, "value": showBytes(h.Value, p.maxValueBytes)})
+				}
+				message["headers"] = headers
+			}
+			messages = append(messages, message)
+		}
+
+		ranges := map[string]interface{}{}
+		for partition, r := range peek.Ranges {
+			ranges[fmt.Sprint(partition)] = map[string]int64{"start": r[0], "end": r[1]}
+		}
+		result := map[string]interface{}{
+			"topic":      args.Topic,
+			"messages":   messages,
+			"count":      len(messages),
+			"partitions": ranges,
+		}
+		if len(peek.Warnings) > 0 {
+			result["warnings"] = peek.Warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// payloadDecoder shows keys or values in one format
+type payloadDecoder struct {
+	format         string
+	proto          func([]byte) (interface{}, error)
+	schemaRegistry bool
+	maxBytes       int
+}
+
+func (p *KafkaProvider) newDecoder(format, protoPath, protoMessage string, schemaRegistry bool) (*payloadDecoder, error) {
+	d := &payloadDecoder{format: format, schemaRegistry: schemaRegistry, maxBytes: p.maxValueBytes}
+	switch format {
+	case "":
+		d.format = "auto"
+	case "auto", "json", "string", "base64":
+	case "protobuf":
+		if p.proto == nil {
+			return nil, fmt.Errorf("protobuf needs the proto provider, which is disabled")
+		}
+		if protoPath == "" || protoMessage == "" {
+			return nil, fmt.Errorf("protobuf needs proto_path and proto_message")
+		}
+		decode, err := p.proto.Decoder(protoPath, protoMessage)
+		if err != nil {
+			return nil, err
+		}
+		d.proto = decode
+	default:
+		return nil, fmt.Errorf("unknown format %s", format)
+	}
+	return d, nil
+}
+
+// decode adds the shown payload to message under name, with its size and any decoding problem
+func (d *payloadDecoder) decode(data []byte, name string, message map[string]interface{}) {
+	if d.schemaRegistry && d.format != "string" && d.format != "base64" && len(data) >= 5 && data[0] == 0 {
+		message[name+"_schema_id"] = binary.BigEndian.Uint32(data[1:5])
+		data = data[5:]
+		if d.format == "protobuf" {
+			indexes, rest, err := messageIndexes(data)
+			if err != nil {
+				message[name+"_error"] = err.Error()
+			} else {
+				data = rest
+				if len(indexes) > 0 && !(len(indexes) == 1 && indexes[0] == 0) {
+					message[name+"_message_indexes"] = indexes
+				}
+			}
+		}
+	}
+	message[name+"_bytes"] = len(data)
+
+	format := d.format
+	if format == "auto" {
+		switch {
+		case json.Valid(data):
+			format = "json"
+		case utf8.Valid(data):
+			format = "string"
+		default:
+			format = "base64"
+		}
+	}
+	if len(data) > d.maxBytes && (format == "json" || format == "protobuf") {
+		// Too large to show whole; a cut-off prefix is shown instead of a partial decode
+		message[name+"_truncated"] = true
+		if format == "json" {
+			message[name] = string(data[:d.maxBytes])
+		} else {
+			message[name] = base64.StdEncoding.EncodeToString(data[:d.maxBytes])
+		}
+		return
+	}
+
+	switch format {
+	case "json":
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			message[name+"_error"] = fmt.Sprintf("not valid JSON: %v", err)
+			message[name] = showBytes(data, d.maxBytes)
+			return
+		}
+		message[name] = value
+	case "protobuf":
+		value, err := d.proto(data)
+		if err != nil {
+			message[name+"_error"] = fmt.Sprintf("protobuf decoding failed: %v", err)
+			message[name] = base64.StdEncoding.EncodeToString(data)
+			return
+		}
+		message[name] = value
+	case "base64":
+		if len(data) > d.maxBytes {
+			message[name+"_truncated"] = true
+			data = data[:d.maxBytes]
+		}
+		message[name] = base64.StdEncoding.EncodeToString(data)
+	default:
+		if len(data) > d.maxBytes {
+			message[name+"_truncated"] = true
+		}
+		message[name] = showBytes(data, d.maxBytes)
+	}
+}
+
+// showBytes shows bytes as text, cut to maxBytes
+func showBytes(data []byte, maxBytes int) string {
+	if len(data) > maxBytes {
+		data = data[:maxBytes]
+	}
+	return string(data)
+}
+
+// messageIndexes reads the message index list of the Schema Registry protobuf format: a zigzag varint
+// count followed by that many indexes, where a count of 0 means the first message of the schema
+func messageIndexes(data []byte) ([]int64, []byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 || count < 0 || count > 100 {
+		return nil, nil, fmt.Errorf("invalid schema registry message indexes")
+	}
+	data = data[n:]
+	indexes := make([]int64, 0, count)
+	for range count {
+		index, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, nil, fmt.Errorf("invalid schema registry message indexes")
+		}
+		indexes = append(indexes, index)
+		data = data[n:]
+	}
+	return indexes, data, nil
+}
+
+func (p *KafkaProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Kafka Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *KafkaProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that KafkaProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*KafkaProvider)(nil)
@@ -0,0 +1,269 @@
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/klauspost/compress/snappy/xerial"
+	"github.com/klauspost/compress/zstd"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const (
+	// maxBatchBytes bounds a decompressed record batch
+	maxBatchBytes = 64 << 20
+	// batchHeaderBytes is the size of a v2 batch header after its offset and length
+	batchHeaderBytes = 49
+)
+
+// zstdDecoder is shared; DecodeAll is safe for concurrent use
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxBatchBytes))
+
+// Record is one message read from a partition
+type Record struct {
+	Partition int32
+	Offset    int64
+	Timestamp time.Time
+	Key       []byte
+	Value     []byte
+	Headers   []kmsg.Header
+}
+
+// parseBatches reads the records of the v2 record batches in a fetch response partition, with their
+// batches' next offset. A batch cut off at the end of the response is ignored, as it is sent whole
+// on the next fetch; control batches of transactions are skipped.
+func parseBatches(partition int32, data []byte) ([]Record, int64, error) {
+	var records []Record
+	next := int64(-1)
+	for len(data) >= 17 {
+		length := int64(int32(binary.BigEndian.Uint32(data[8:12])))
+		if length < batchHeaderBytes {
+			return nil, next, fmt.Errorf("invalid record batch length %d", length)
+		}
+		if int64(len(data)) < 12+length {
+			break
+		}
+		raw := data[:12+length]
+		data = data[12+length:]
+
+		// Magic is at byte 16; v0 and v1 message sets predate Kafka 0.11
+		if magic := raw[16]; magic != 2 {
+			return nil, next, fmt.Errorf("unsupported record batch version %d", magic)
+		}
+		var batch kmsg.RecordBatch
+		if err := batch.ReadFrom(raw); err != nil {
+			return nil, next, fmt.Errorf("invalid record batch: %w", err)
+		}
+		next = batch.FirstOffset + int64(batch.LastOffsetDelta) + 1
+		if batch.Attributes&0x20 != 0 {
+			continue
+		}
+
+		payload, err := decompress(batch.Attributes&0x07, batch.Records)
+		if err != nil {
+			return nil, next, err
+		}
+		for i := int32(0); i < batch.NumRecords && len(payload) > 0; i++ {
+			size, n := binary.Varint(payload)
+			if n <= 0 || size < 0 || size > int64(len(payload)-n) {
+				return nil, next, fmt.Errorf("invalid record in batch at offset %d", batch.FirstOffset)
+			}
+			var record kmsg.Record
+			if err := record.ReadFrom(payload[:n+int(size)]); err != nil {
+				return nil, next, fmt.Errorf("invalid record in batch at offset %d: %w", batch.FirstOffset, err)
+			}
+			payload = payload[n+int(size):]
+			records = append(records, Record{
+				Partition: partition,
+				Offset:    batch.FirstOffset + int64(record.OffsetDelta),
+				Timestamp: time.UnixMilli(batch.FirstTimestamp + record.TimestampDelta64),
+				Key:       record.Key,
+				Value:     record.Value,
+				Headers:   record.Headers,
+			})
+		}
+	}
+	return records, next, nil
+}
+
+// decompress expands the records of a batch by its compression codec
+func decompress(codec int16, data []byte) ([]byte, error) {
+	switch codec {
+	case 0:
+		return data, nil
+	case 1:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		out, err := io.ReadAll(io.LimitReader(r, maxBatchBytes))
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		return out, nil
+	case 2:
+		out, err := xerial.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("snappy: %w", err)
+		}
+		return out, nil
+	case 3:
+		return decodeLZ4Frames(data)
+	case 4:
+		out, err := zstdDecoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unknown compression codec %d", codec)
+}
+
+// decodeLZ4Frames decodes LZ4 frames (the format Kafka uses for lz4 batches). Checksums are not verified.
+func decodeLZ4Frames(data []byte) ([]byte, error) {
+	var out []byte
+	for len(data) > 0 {
+		if len(data) < 7 {
+			return nil, fmt.Errorf("lz4: truncated frame")
+		}
+		magic := binary.LittleEndian.Uint32(data)
+		if magic&0xFFFFFFF0 == 0x184D2A50 {
+			// Skippable frame
+			size := int(binary.LittleEndian.Uint32(data[4:]))
+			if size > len(data)-8 {
+				return nil, fmt.Errorf("lz4: truncated frame")
+			}
+			data = data[8+size:]
+			continue
+		}
+		if magic != 0x184D2204 {
+			return nil, fmt.Errorf("lz4: invalid frame magic %#x", magic)
+		}
+		flags := data[4]
+		if flags>>6 != 1 {
+			return nil, fmt.Errorf("lz4: unsupported frame version %d", flags>>6)
+		}
+		blockChecksum, contentSize, contentChecksum, dictID := flags&0x10 != 0, flags&0x08 != 0, flags&0x04 != 0, flags&0x01 != 0
+		pos := 6 // magic, FLG and BD
+		if contentSize {
+			pos += 8
+		}
+		if dictID {
+			pos += 4
+		}
+		pos++ // header checksum
+		if pos > len(data) {
+			return nil, fmt.Errorf("lz4: truncated frame header")
+		}
+		data = data[pos:]
+
+		for {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("lz4: truncated block")
+			}
+			size := binary.LittleEndian.Uint32(data)
+			data = data[4:]
+			if size == 0 {
+				break
+			}
+			uncompressed := size&0x80000000 != 0
+			size &= 0x7FFFFFFF
+			if int(size) > len(data) {
+				return nil, fmt.Errorf("lz4: truncated block")
+			}
+			var err error
+			if uncompressed {
+				out = append(out, data[:size]...)
+			} else if out, err = decodeLZ4Block(out, data[:size]); err != nil {
+				return nil, err
+			}
+			if len(out) > maxBatchBytes {
+				return nil, fmt.Errorf("lz4: batch larger than %d bytes", maxBatchBytes)
+			}
+			data = data[size:]
+			if blockChecksum {
+				if len(data) < 4 {
+					return nil, fmt.Errorf("lz4: truncated block")
+				}
+				data = data[4:]
+			}
+		}
+		if contentChecksum {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("lz4: truncated frame")
+			}
+			data = data[4:]
+		}
+	}
+	return out, nil
+}
+
+// decodeLZ4Block appends a decoded LZ4 block to out. Matches may reach back into earlier blocks
+// of the frame, which are already in out.
+func decodeLZ4Block(out, src []byte) ([]byte, error) {
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+
+		literals := int(token >> 4)
+		if literals == 15 {
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("lz4: corrupt block")
+				}
+				b := src[i]
+				i++
+				literals += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+		if literals > len(src)-i {
+			return nil, fmt.Errorf("lz4: corrupt block")
+		}
+		out = append(out, src[i:i+literals]...)
+		i += literals
+		if i == len(src) {
+			// The last sequence has literals only
+			break
+		}
+
+		if i+2 > len(src) {
+			return nil, fmt.Errorf("lz4: corrupt block")
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		if offset == 0 || offset > len(out) {
+			return nil, fmt.Errorf("lz4: corrupt block")
+		}
+		matchLen := int(token & 0x0F)
+		if matchLen == 15 {
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("lz4: corrupt block")
+				}
+				b := src[i]
+				i++
+				matchLen += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+		matchLen += 4
+		if len(out)+matchLen > maxBatchBytes {
+			return nil, fmt.Errorf("lz4: batch larger than %d bytes", maxBatchBytes)
+		}
+		// Byte by byte, since a match may overlap the bytes it produces
+		start := len(out) - offset
+		for j := 0; j < matchLen; j++ {
+			out = append(out, out[start+j])
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,155 @@
+package kafka
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// saslAuth authenticates connections with SASL/PLAIN or SASL/SCRAM
+type saslAuth struct {
+	mechanism string
+	username  string
+	password  string
+}
+
+// newSASLAuth validates the mechanism; an empty mechanism disables SASL
+func newSASLAuth(mechanism, username, password string) (*saslAuth, error) {
+	mechanism = strings.ToUpper(strings.TrimSpace(mechanism))
+	switch mechanism {
+	case "":
+		return nil, nil
+	case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism %s (use PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512)", mechanism)
+	}
+	if username == "" {
+		return nil, fmt.Errorf("SASL %s requires a username", mechanism)
+	}
+	return &saslAuth{mechanism: mechanism, username: username, password: password}, nil
+}
+
+func (s *saslAuth) authenticate(ctx context.Context, cn *conn) error {
+	handshake := kmsg.NewPtrSASLHandshakeRequest()
+	handshake.Mechanism = s.mechanism
+	resp, err := cn.request(ctx, handshake)
+	if err != nil {
+		return err
+	}
+	if handshakeResp := resp.(*kmsg.SASLHandshakeResponse); handshakeResp.ErrorCode != 0 {
+		return fmt.Errorf("%s (broker mechanisms: %s)", kafkaError(handshakeResp.ErrorCode), strings.Join(handshakeResp.SupportedMechanisms, ", "))
+	}
+
+	if s.mechanism == "PLAIN" {
+		_, err := s.exchange(ctx, cn, []byte("\x00"+s.username+"\x00"+s.password))
+		return err
+	}
+	return s.scram(ctx, cn)
+}
+
+// exchange sends one SASL message and returns the broker's reply
+func (s *saslAuth) exchange(ctx context.Context, cn *conn, message []byte) ([]byte, error) {
+	req := kmsg.NewPtrSASLAuthenticateRequest()
+	req.SASLAuthBytes = message
+	resp, err := cn.request(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	authResp := resp.(*kmsg.SASLAuthenticateResponse)
+	if authResp.ErrorCode != 0 {
+		if authResp.ErrorMessage != nil {
+			return nil, fmt.Errorf("%s: %s", kafkaError(authResp.ErrorCode), *authResp.ErrorMessage)
+		}
+		return nil, kafkaError(authResp.ErrorCode)
+	}
+	return authResp.SASLAuthBytes, nil
+}
+
+// scram runs the RFC 5802 exchange and verifies the broker's signature
+func (s *saslAuth) scram(ctx context.Context, cn *conn) error {
+	newHash, size := sha256.New, sha256.Size
+	if s.mechanism == "SCRAM-SHA-512" {
+		newHash, size = sha512.New, sha512.Size
+	}
+
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	clientNonce := base64.RawStdEncoding.EncodeToString(nonce)
+	username := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(s.username)
+	clientFirstBare := "n=" + username + ",r=" + clientNonce
+
+	serverFirst, err := s.exchange(ctx, cn, []byte("n,,"+clientFirstBare))
+	if err != nil {
+		return err
+	}
+	attrs := scramAttributes(string(serverFirst))
+	serverNonce, salt64, iterations := attrs["r"], attrs["s"], attrs["i"]
+	if !strings.HasPrefix(serverNonce, clientNonce) {
+		return fmt.Errorf("server nonce does not extend the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil {
+		return fmt.Errorf("invalid salt: %w", err)
+	}
+	iter, err := strconv.Atoi(iterations)
+	if err != nil || iter <= 0 {
+		return fmt.Errorf("invalid iteration count %q", iterations)
+	}
+
+	salted, err := pbkdf2.Key(newHash, s.password, salt, iter, size)
+	if err != nil {
+		return err
+	}
+	clientKey := hmacSum(newHash, salted, "Client Key")
+	storedKey := newHash()
+	storedKey.Write(clientKey)
+	clientFinalBare := "c=biws,r=" + serverNonce
+	authMessage := clientFirstBare + "," + string(serverFirst) + "," + clientFinalBare
+	proof := hmacSum(newHash, storedKey.Sum(nil), authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+
+	serverFinal, err := s.exchange(ctx, cn, []byte(clientFinalBare+",p="+base64.StdEncoding.EncodeToString(proof)))
+	if err != nil {
+		return err
+	}
+	final := scramAttributes(string(serverFinal))
+	if e, ok := final["e"]; ok {
+		return fmt.Errorf("server rejected authentication: %s", e)
+	}
+	serverSignature := hmacSum(newHash, hmacSum(newHash, salted, "Server Key"), authMessage)
+	if final["v"] != base64.StdEncoding.EncodeToString(serverSignature) {
+		return fmt.Errorf("server signature does not match")
+	}
+	return nil
+}
+
+func hmacSum(newHash func() hash.Hash, key []byte, message string) []byte {
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// scramAttributes parses a comma-separated list of k=v attributes
+func scramAttributes(message string) map[string]string {
+	attrs := map[string]string{}
+	for _, part := range strings.Split(message, ",") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
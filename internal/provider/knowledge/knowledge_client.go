@@ -0,0 +1,327 @@
+package knowledge
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+	"dev-mcp/internal/provider/s3"
+)
+
+// Document represents a single team-maintained knowledge document
+type Document struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Kind    string `json:"kind"`
+	Source  string `json:"source"`
+	Content string `json:"content"`
+}
+
+// SearchHit represents a ranked search result
+type SearchHit struct {
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Kind    string  `json:"kind"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// KnowledgeClient loads knowledge documents and keeps a term index over them
+type KnowledgeClient struct {
+	config   *config.KnowledgeConfig
+	s3Client *s3.S3Client
+	logger   *logging.Logger
+
+	mu       sync.RWMutex
+	docs     map[string]*Document
+	postings map[string]map[string]int // term -> document ID -> term frequency
+	lengths  map[string]int            // document ID -> token count
+}
+
+// supportedExtensions maps the file extensions we index to a document kind
+var supportedExtensions = map[string]string{
+	".md":       "markdown",
+	".markdown": "markdown",
+	".yaml":     "yaml",
+	".yml":      "yaml",
+}
+
+// NewKnowledgeClient creates a new knowledge client from config
+func NewKnowledgeClient(cfg *config.KnowledgeConfig, s3Cfg *config.S3Config) *KnowledgeClient {
+	c := &KnowledgeClient{
+		config:   cfg,
+		logger:   logging.New("KnowledgeClient"),
+		docs:     map[string]*Document{},
+		postings: map[string]map[string]int{},
+		lengths:  map[string]int{},
+	}
+	if cfg != nil && cfg.S3Bucket != "" {
+		c.s3Client = s3.NewS3Client(s3Cfg)
+	}
+	return c
+}
+
+// IsAvailable checks if a knowledge source is configured
+func (c *KnowledgeClient) IsAvailable() bool {
+	return c.config != nil && (c.config.Dir != "" || c.config.S3Bucket != "")
+}
+
+// Reload reads all documents from the configured sources and rebuilds the index
+func (c *KnowledgeClient) Reload() (int, error) {
+	if !c.IsAvailable() {
+		return 0, fmt.Errorf("knowledge base not configured")
+	}
+
+	docs := map[string]*Document{}
+
+	if c.config.Dir != "" {
+		if err := c.loadFromDir(docs); err != nil {
+			return 0, err
+		}
+	}
+
+	if c.config.S3Bucket != "" {
+		if err := c.loadFromS3(docs); err != nil {
+			return 0, err
+		}
+	}
+
+	postings := map[string]map[string]int{}
+	lengths := map[string]int{}
+	for id, doc := range docs {
+		tokens := tokenize(doc.Title + " " + doc.Content)
+		lengths[id] = len(tokens)
+		for _, tok := range tokens {
+			if postings[tok] == nil {
+				postings[tok] = map[string]int{}
+			}
+			postings[tok][id]++
+		}
+	}
+
+	c.mu.Lock()
+	c.docs = docs
+	c.postings = postings
+	c.lengths = lengths
+	c.mu.Unlock()
+
+	c.logger.Info("knowledge base indexed", logging.Int("documents", len(docs)))
+	return len(docs), nil
+}
+
+// loadFromDir walks the configured directory for supported documents
+func (c *KnowledgeClient) loadFromDir(docs map[string]*Document) error {
+	root := c.config.Dir
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk knowledge dir: %w", err)
+		}
+		if info.IsDir() {
+			return nil
+		}
+		kind, ok := supportedExtensions[strings.ToLower(filepath.Ext(p))]
+		if !ok {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			rel = p
+		}
+		id := filepath.ToSlash(rel)
+		docs[id] = newDocument(id, kind, "file", string(data))
+		return nil
+	})
+}
+
+// loadFromS3 reads supported documents under the configured S3 prefix
+func (c *KnowledgeClient) loadFromS3(docs map[string]*Document) error {
+	if c.s3Client == nil || !c.s3Client.IsAvailable() {
+		return fmt.Errorf("s3 client not available for knowledge bucket %s", c.config.S3Bucket)
+	}
+
+	keys, err := c.s3Client.ListKeys(c.config.S3Bucket, c.config.S3Prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list knowledge objects: %w", err)
+	}
+
+	for _, key := range keys {
+		kind, ok := supportedExtensions[strings.ToLower(path.Ext(key))]
+		if !ok {
+			continue
+		}
+		data, err := c.s3Client.GetObjectBytes(c.config.S3Bucket, key)
+		if err != nil {
+			c.logger.Warn("failed to fetch knowledge object", logging.String("key", key), logging.Error(err))
+			continue
+		}
+		id := strings.TrimPrefix(strings.TrimPrefix(key, c.config.S3Prefix), "/")
+		docs[id] = newDocument(id, kind, "s3", string(data))
+	}
+	return nil
+}
+
+// newDocument builds a document and derives its title
+func newDocument(id, kind, source, content string) *Document {
+	return &Document{
+		ID:      id,
+		Title:   extractTitle(id, kind, content),
+		Kind:    kind,
+		Source:  source,
+		Content: content,
+	}
+}
+
+// extractTitle picks a title from front matter, YAML fields, or the first heading
+func extractTitle(id, kind, content string) string {
+	switch kind {
+	case "yaml":
+		var meta map[string]interface{}
+		if err := yaml.Unmarshal([]byte(content), &meta); err == nil {
+			for _, field := range []string{"title", "name", "service"} {
+				if v, ok := meta[field].(string); ok && v != "" {
+					return v
+				}
+			}
+		}
+	case "markdown":
+		body := content
+		if strings.HasPrefix(body, "---\n") {
+			if end := strings.Index(body[4:], "\n---"); end >= 0 {
+				var meta map[string]interface{}
+				if err := yaml.Unmarshal([]byte(body[4:4+end]), &meta); err == nil {
+					if v, ok := meta["title"].(string); ok && v != "" {
+						return v
+					}
+				}
+				body = body[4+end+4:]
+			}
+		}
+		for _, line := range strings.Split(body, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "#") {
+				return strings.TrimSpace(strings.TrimLeft(line, "#"))
+			}
+		}
+	}
+	return strings.TrimSuffix(path.Base(id), path.Ext(id))
+}
+
+// Search ranks documents against the query using TF-IDF scoring
+func (c *KnowledgeClient) Search(query string, limit int) []SearchHit {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	terms := tokenize(query)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	total := float64(len(c.docs))
+	scores := map[string]float64{}
+	for _, term := range terms {
+		postings := c.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + total/float64(len(postings)))
+		for id, tf := range postings {
+			scores[id] += float64(tf) / float64(c.lengths[id]+1) * idf
+			if strings.Contains(strings.ToLower(c.docs[id].Title), term) {
+				scores[id] += idf
+			}
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for id, score := range scores {
+		doc := c.docs[id]
+		hits = append(hits, SearchHit{
+			ID:      id,
+			Title:   doc.Title,
+			Kind:    doc.Kind,
+			Score:   math.Round(score*1000) / 1000,
+			Snippet: snippet(doc.Content, terms),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score == hits[j].Score {
+			return hits[i].ID < hits[j].ID
+		}
+		return hits[i].Score > hits[j].Score
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// Get returns a document by ID
+func (c *KnowledgeClient) Get(id string) (*Document, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	doc, ok := c.docs[id]
+	return doc, ok
+}
+
+// List returns all indexed documents sorted by ID
+func (c *KnowledgeClient) List() []*Document {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]*Document, 0, len(c.docs))
+	for _, doc := range c.docs {
+		out = append(out, doc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Close closes the knowledge client
+func (c *KnowledgeClient) Close() error {
+	return nil
+}
+
+// tokenize lowercases text and splits it into alphanumeric terms
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) >= 2 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// snippet returns the first line that mentions any of the terms
+func snippet(content string, terms []string) string {
+	for _, line := range strings.Split(content, "\n") {
+		lower := strings.ToLower(line)
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				line = strings.TrimSpace(line)
+				if len(line) > 200 {
+					line = line[:200] + "..."
+				}
+				return line
+			}
+		}
+	}
+	return ""
+}
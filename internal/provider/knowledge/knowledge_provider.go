@@ -0,0 +1,286 @@
+package knowledge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+const resourcePrefix = "knowledge://docs/"
+
+// KnowledgeProvider exposes org-wide docs (service catalog, oncall contacts, known issues)
+type KnowledgeProvider struct {
+	*provider.BaseProvider
+	client *KnowledgeClient
+	server *mcp.Server
+	uris   []string
+}
+
+// NewKnowledgeProvider creates a new Knowledge provider with config
+func NewKnowledgeProvider(cfg *config.KnowledgeConfig, s3Cfg *config.S3Config) *KnowledgeProvider {
+	p := &KnowledgeProvider{
+		BaseProvider: provider.NewBaseProvider("knowledge"),
+	}
+
+	p.client = NewKnowledgeClient(cfg, s3Cfg)
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Knowledge base not configured", nil)
+		return p
+	}
+
+	count, err := p.client.Reload()
+	if err != nil {
+		log.Printf("⚠ Knowledge base load failed: %v", err)
+		p.SetStatus(false, "Knowledge base load failed", err)
+		return p
+	}
+
+	p.SetStatus(true, fmt.Sprintf("Indexed %d documents", count), nil)
+	log.Printf("✓ Knowledge provider initialized successfully (%d documents)", count)
+
+	return p
+}
+
+// Test tests the knowledge configuration (for ProviderClient interface compatibility)
+func (p *KnowledgeProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("knowledge provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Knowledge tools to the MCP server (for ProviderClient interface compatibility)
+func (p *KnowledgeProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.server = server
+	p.addToolsToServer(server)
+	p.registerResources()
+	return nil
+}
+
+// Close closes the Knowledge provider
+func (p *KnowledgeProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds Knowledge tools to the MCP server
+func (p *KnowledgeProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Knowledge provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createKnowledgeSearchTool().Tool, p.createKnowledgeSearchTool().Handler},
+		{p.createKnowledgeGetTool().Tool, p.createKnowledgeGetTool().Handler},
+		{p.createKnowledgeReloadTool().Tool, p.createKnowledgeReloadTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered Knowledge tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All Knowledge tools registered successfully")
+}
+
+// registerResources publishes each document as a knowledge://docs/{id} resource
+func (p *KnowledgeProvider) registerResources() {
+	if len(p.uris) > 0 {
+		p.server.RemoveResources(p.uris...)
+	}
+	p.uris = p.uris[:0]
+
+	for _, doc := range p.client.List() {
+		mimeType := "text/markdown"
+		if doc.Kind == "yaml" {
+			mimeType = "application/yaml"
+		}
+		uri := resourcePrefix + doc.ID
+		p.server.AddResource(&mcp.Resource{
+			URI:         uri,
+			Name:        doc.Title,
+			Description: fmt.Sprintf("Knowledge document %s (%s)", doc.ID, doc.Source),
+			MIMEType:    mimeType,
+		}, p.readResource)
+		p.uris = append(p.uris, uri)
+	}
+}
+
+// readResource returns the document referenced by a knowledge:// URI
+func (p *KnowledgeProvider) readResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	id := strings.TrimPrefix(req.Params.URI, resourcePrefix)
+	doc, ok := p.client.Get(id)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+
+	mimeType := "text/markdown"
+	if doc.Kind == "yaml" {
+		mimeType = "application/yaml"
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      req.Params.URI,
+				MIMEType: mimeType,
+				Text:     doc.Content,
+			},
+		},
+	}, nil
+}
+
+// createKnowledgeSearchTool creates the knowledge search tool
+func (p *KnowledgeProvider) createKnowledgeSearchTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "knowledge_search",
+		Description: "Search team-maintained docs (service catalog, oncall contacts, known issues) to ground answers in org context",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"query": {
+					"type": "string",
+					"description": "Free-text search query"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of documents to return",
+					"default": 10
+				}
+			},
+			"required": ["query"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Query string `json:"query"`
+			Limit int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Query == "" {
+			return p.createErrorResult(fmt.Errorf("query parameter is required")), nil
+		}
+
+		hits := p.client.Search(args.Query, args.Limit)
+		for i := range hits {
+			hits[i].ID = resourcePrefix + hits[i].ID
+		}
+
+		result := map[string]interface{}{
+			"query":   args.Query,
+			"results": hits,
+			"count":   len(hits),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createKnowledgeGetTool creates the knowledge document retrieval tool
+func (p *KnowledgeProvider) createKnowledgeGetTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "knowledge_get",
+		Description: "Get the full content of a knowledge document by ID or knowledge:// URI",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {
+					"type": "string",
+					"description": "Document ID (relative path) or knowledge://docs/ URI"
+				}
+			},
+			"required": ["id"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			ID string `json:"id"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.ID == "" {
+			return p.createErrorResult(fmt.Errorf("id parameter is required")), nil
+		}
+
+		doc, ok := p.client.Get(strings.TrimPrefix(args.ID, resourcePrefix))
+		if !ok {
+			return p.createErrorResult(fmt.Errorf("document not found: %s", args.ID)), nil
+		}
+
+		return p.formatJSONResult(doc), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createKnowledgeReloadTool creates the tool that re-reads and re-indexes all docs
+func (p *KnowledgeProvider) createKnowledgeReloadTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "knowledge_reload",
+		Description: "Reload knowledge documents from the configured directory or S3 prefix and rebuild the index",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {}}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		count, err := p.client.Reload()
+		if err != nil {
+			p.SetStatus(false, "Knowledge base reload failed", err)
+			return p.createErrorResult(err), nil
+		}
+
+		p.SetStatus(true, fmt.Sprintf("Indexed %d documents", count), nil)
+		p.registerResources()
+
+		result := map[string]interface{}{
+			"documents": count,
+			"status":    "reloaded",
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *KnowledgeProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Knowledge Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *KnowledgeProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that KnowledgeProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*KnowledgeProvider)(nil)
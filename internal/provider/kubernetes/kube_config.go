@@ -0,0 +1,339 @@
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// execTimeout bounds credential plugins such as aws eks get-token or gke-gcloud-auth-plugin
+	execTimeout = 30 * time.Second
+)
+
+// kubeconfigFile is the subset of a kubeconfig used to connect
+type kubeconfigFile struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+			TLSServerName            string `yaml:"tls-server-name"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string       `yaml:"name"`
+		User kubeUserAuth `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// kubeUserAuth holds the supported credential types of a kubeconfig user
+type kubeUserAuth struct {
+	Token                 string `yaml:"token"`
+	TokenFile             string `yaml:"tokenFile"`
+	ClientCertificate     string `yaml:"client-certificate"`
+	ClientCertificateData string `yaml:"client-certificate-data"`
+	ClientKey             string `yaml:"client-key"`
+	ClientKeyData         string `yaml:"client-key-data"`
+	Username              string `yaml:"username"`
+	Password              string `yaml:"password"`
+	Exec                  *struct {
+		Command string   `yaml:"command"`
+		Args    []string `yaml:"args"`
+		Env     []struct {
+			Name  string `yaml:"name"`
+			Value string `yaml:"value"`
+		} `yaml:"env"`
+	} `yaml:"exec"`
+	AuthProvider *struct {
+		Name string `yaml:"name"`
+	} `yaml:"auth-provider"`
+}
+
+// connection is everything needed to call one kube-apiserver
+type connection struct {
+	Server    string
+	Namespace string
+	Context   string
+	TLS       *tls.Config
+	Username  string
+	Password  string
+	// token returns the bearer token for a request; it may re-read a file or re-run an exec plugin
+	token func() (string, error)
+}
+
+// loadConnection resolves the connection from config: in-cluster, an explicit kubeconfig,
+// $KUBECONFIG, ~/.kube/config, and finally in-cluster when running in a pod
+func loadConnection(cfg *config.KubernetesConfig) (*connection, error) {
+	if cfg.InCluster {
+		return inClusterConnection()
+	}
+
+	path := cfg.Kubeconfig
+	if path == "" {
+		path = os.Getenv("KUBECONFIG")
+		// KUBECONFIG may list several files; the first one is used
+		if i := strings.IndexRune(path, filepath.ListSeparator); i >= 0 {
+			path = path[:i]
+		}
+	}
+	if path == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			candidate := filepath.Join(home, ".kube", "config")
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+			}
+		}
+	}
+	if path == "" {
+		if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+			return inClusterConnection()
+		}
+		return nil, fmt.Errorf("no kubeconfig found (set kubernetes.kubeconfig, $KUBECONFIG or kubernetes.in_cluster)")
+	}
+	return kubeconfigConnection(path, cfg.Context)
+}
+
+// inClusterConnection uses the pod's service account token and CA
+func inClusterConnection() (*connection, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a cluster (KUBERNETES_SERVICE_HOST/PORT unset)")
+	}
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("service account CA is not valid PEM")
+	}
+	namespace, _ := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+
+	return &connection{
+		Server:    "https://" + net.JoinHostPort(host, port),
+		Namespace: strings.TrimSpace(string(namespace)),
+		Context:   "in-cluster",
+		TLS:       &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		// Projected tokens rotate, so the file is re-read for every request
+		token: tokenFromFile(filepath.Join(serviceAccountDir, "token")),
+	}, nil
+}
+
+// kubeconfigConnection reads a kubeconfig file and resolves the context
+func kubeconfigConnection(path, contextName string) (*connection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+	var kc kubeconfigFile
+	if err := yaml.Unmarshal(data, &kc); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	// Relative file references in a kubeconfig are relative to the kubeconfig itself
+	resolve := func(file string) string {
+		if file == "" || filepath.IsAbs(file) {
+			return file
+		}
+		return filepath.Join(filepath.Dir(path), file)
+	}
+
+	if contextName == "" {
+		contextName = kc.CurrentContext
+	}
+	if contextName == "" {
+		return nil, fmt.Errorf("kubeconfig %s has no current-context; set kubernetes.context", path)
+	}
+
+	conn := &connection{Context: contextName}
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == contextName {
+			clusterName, userName, conn.Namespace = c.Context.Cluster, c.Context.User, c.Context.Namespace
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("context %q not found in %s", contextName, path)
+	}
+	if conn.Namespace == "" {
+		conn.Namespace = "default"
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	found = false
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+		conn.Server = strings.TrimSuffix(c.Cluster.Server, "/")
+		tlsConfig.ServerName = c.Cluster.TLSServerName
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		ca, err := inlineOrFile(c.Cluster.CertificateAuthorityData, resolve(c.Cluster.CertificateAuthority))
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: certificate authority: %w", clusterName, err)
+		}
+		if len(ca) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("cluster %s: certificate authority is not valid PEM", clusterName)
+			}
+			tlsConfig.RootCAs = pool
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("cluster %q of context %q not found in %s", clusterName, contextName, path)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		auth := u.User
+		if auth.AuthProvider != nil {
+			return nil, fmt.Errorf("user %s uses the %q auth-provider, which is not supported; use an exec credential plugin", userName, auth.AuthProvider.Name)
+		}
+		cert, err := inlineOrFile(auth.ClientCertificateData, resolve(auth.ClientCertificate))
+		if err != nil {
+			return nil, fmt.Errorf("user %s: client certificate: %w", userName, err)
+		}
+		key, err := inlineOrFile(auth.ClientKeyData, resolve(auth.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("user %s: client key: %w", userName, err)
+		}
+		if len(cert) > 0 && len(key) > 0 {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, fmt.Errorf("user %s: invalid client certificate: %w", userName, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+
+		switch {
+		case auth.Token != "":
+			token := auth.Token
+			conn.token = func() (string, error) { return token, nil }
+		case auth.TokenFile != "":
+			conn.token = tokenFromFile(resolve(auth.TokenFile))
+		case auth.Exec != nil:
+			plugin := &execPlugin{command: auth.Exec.Command, args: auth.Exec.Args}
+			for _, env := range auth.Exec.Env {
+				plugin.env = append(plugin.env, env.Name+"="+env.Value)
+			}
+			conn.token = plugin.Token
+		}
+		conn.Username, conn.Password = auth.Username, auth.Password
+	}
+
+	conn.TLS = tlsConfig
+	return conn, nil
+}
+
+// inlineOrFile returns base64 inline data when set, otherwise the file's contents
+func inlineOrFile(data, file string) ([]byte, error) {
+	if data != "" {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %w", err)
+		}
+		return decoded, nil
+	}
+	if file == "" {
+		return nil, nil
+	}
+	return os.ReadFile(file)
+}
+
+func tokenFromFile(path string) func() (string, error) {
+	return func() (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+// execPlugin runs a client.authentication.k8s.io credential plugin and caches its token until it expires
+type execPlugin struct {
+	command string
+	args    []string
+	env     []string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token returns a cached token or runs the plugin for a new one
+func (e *execPlugin) Token() (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.token != "" && (e.expires.IsZero() || time.Now().Add(time.Minute).Before(e.expires)) {
+		return e.token, nil
+	}
+
+	cmd := exec.Command(e.command, e.args...)
+	cmd.Env = append(os.Environ(), e.env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to run credential plugin %s: %w", e.command, err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return "", fmt.Errorf("credential plugin %s failed: %v: %s", e.command, err, strings.TrimSpace(stderr.String()))
+		}
+	case <-time.After(execTimeout):
+		_ = cmd.Process.Kill()
+		return "", fmt.Errorf("credential plugin %s timed out", e.command)
+	}
+
+	var credential struct {
+		Status struct {
+			Token               string    `json:"token"`
+			ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return "", fmt.Errorf("credential plugin %s returned invalid output: %w", e.command, err)
+	}
+	if credential.Status.Token == "" {
+		return "", fmt.Errorf("credential plugin %s returned no token (client certificate credentials are not supported)", e.command)
+	}
+	e.token, e.expires = credential.Status.Token, credential.Status.ExpirationTimestamp
+	return e.token, nil
+}
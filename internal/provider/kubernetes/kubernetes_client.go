@@ -0,0 +1,735 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultMaxLogBytes = 50000
+	defaultTailLines   = 200
+	defaultListLimit   = 100
+)
+
+// ObjectMeta is the subset of Kubernetes object metadata used by the tools
+type ObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Labels            map[string]string `json:"labels"`
+	Annotations       map[string]string `json:"annotations"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	DeletionTimestamp *time.Time        `json:"deletionTimestamp"`
+	Generation        int64             `json:"generation"`
+	OwnerReferences   []struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"ownerReferences"`
+}
+
+// Container is a container spec
+type Container struct {
+	Name      string `json:"name"`
+	Image     string `json:"image"`
+	Resources struct {
+		Requests map[string]string `json:"requests,omitempty"`
+		Limits   map[string]string `json:"limits,omitempty"`
+	} `json:"resources"`
+	Ports []struct {
+		Name          string `json:"name,omitempty"`
+		ContainerPort int    `json:"containerPort"`
+		Protocol      string `json:"protocol,omitempty"`
+	} `json:"ports"`
+	LivenessProbe  map[string]interface{} `json:"livenessProbe"`
+	ReadinessProbe map[string]interface{} `json:"readinessProbe"`
+}
+
+// ContainerState is one of waiting, running or terminated
+type ContainerState struct {
+	Waiting *struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"waiting"`
+	Running *struct {
+		StartedAt time.Time `json:"startedAt"`
+	} `json:"running"`
+	Terminated *struct {
+		ExitCode   int       `json:"exitCode"`
+		Reason     string    `json:"reason"`
+		Message    string    `json:"message"`
+		FinishedAt time.Time `json:"finishedAt"`
+	} `json:"terminated"`
+}
+
+// ContainerStatus is the observed state of a container
+type ContainerStatus struct {
+	Name         string         `json:"name"`
+	Ready        bool           `json:"ready"`
+	RestartCount int            `json:"restartCount"`
+	Image        string         `json:"image"`
+	State        ContainerState `json:"state"`
+	LastState    ContainerState `json:"lastState"`
+}
+
+// Pod is the subset of a pod used by the tools
+type Pod struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		NodeName       string      `json:"nodeName"`
+		Containers     []Container `json:"containers"`
+		InitContainers []Container `json:"initContainers"`
+	} `json:"spec"`
+	Status struct {
+		Phase                 string            `json:"phase"`
+		Reason                string            `json:"reason"`
+		Message               string            `json:"message"`
+		PodIP                 string            `json:"podIP"`
+		ContainerStatuses     []ContainerStatus `json:"containerStatuses"`
+		InitContainerStatuses []ContainerStatus `json:"initContainerStatuses"`
+	} `json:"status"`
+}
+
+// Deployment is the subset of a deployment used by the tools
+type Deployment struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int `json:"replicas"`
+		Paused   bool `json:"paused"`
+		Selector struct {
+			MatchLabels map[string]string `json:"matchLabels"`
+		} `json:"selector"`
+		Strategy struct {
+			Type          string                 `json:"type"`
+			RollingUpdate map[string]interface{} `json:"rollingUpdate,omitempty"`
+		} `json:"strategy"`
+		Template struct {
+			Spec struct {
+				Containers []Container `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		ObservedGeneration  int64 `json:"observedGeneration"`
+		Replicas            int   `json:"replicas"`
+		UpdatedReplicas     int   `json:"updatedReplicas"`
+		ReadyReplicas       int   `json:"readyReplicas"`
+		AvailableReplicas   int   `json:"availableReplicas"`
+		UnavailableReplicas int   `json:"unavailableReplicas"`
+		Conditions          []struct {
+			Type           string    `json:"type"`
+			Status         string    `json:"status"`
+			Reason         string    `json:"reason"`
+			Message        string    `json:"message"`
+			LastUpdateTime time.Time `json:"lastUpdateTime"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// ReplicaSet is the subset of a replica set used to show rollout history
+type ReplicaSet struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int `json:"replicas"`
+		Template struct {
+			Spec struct {
+				Containers []Container `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		Replicas      int `json:"replicas"`
+		ReadyReplicas int `json:"readyReplicas"`
+	} `json:"status"`
+}
+
+// Event is a core/v1 event
+type Event struct {
+	Metadata       ObjectMeta `json:"metadata"`
+	InvolvedObject struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"involvedObject"`
+	Reason         string    `json:"reason"`
+	Message        string    `json:"message"`
+	Type           string    `json:"type"`
+	Count          int       `json:"count"`
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+	EventTime      time.Time `json:"eventTime"`
+	Source         struct {
+		Component string `json:"component"`
+	} `json:"source"`
+	Series *struct {
+		Count            int       `json:"count"`
+		LastObservedTime time.Time `json:"lastObservedTime"`
+	} `json:"series"`
+}
+
+// KubernetesClient calls the kube-apiserver REST API for a set of allowed namespaces
+type KubernetesClient struct {
+	client      *resty.Client
+	conn        *connection
+	namespaces  map[string]bool
+	maxLogBytes int
+	initErr     error
+}
+
+// NewKubernetesClient creates a client from config; it is unavailable when disabled or the connection cannot be resolved
+func NewKubernetesClient(cfg *config.KubernetesConfig) *KubernetesClient {
+	c := &KubernetesClient{namespaces: map[string]bool{}, maxLogBytes: defaultMaxLogBytes}
+	if cfg == nil || !cfg.Enabled {
+		return c
+	}
+
+	conn, err := loadConnection(cfg)
+	if err != nil {
+		c.initErr = err
+		return c
+	}
+	c.conn = conn
+	if cfg.MaxLogBytes > 0 {
+		c.maxLogBytes = cfg.MaxLogBytes
+	}
+
+	// Without an allowlist only the context's own namespace is reachable
+	for _, ns := range cfg.Namespaces {
+		c.namespaces[ns] = true
+	}
+	if len(c.namespaces) == 0 {
+		c.namespaces[conn.Namespace] = true
+	}
+
+	c.client = resty.New().
+		SetBaseURL(conn.Server).
+		SetTLSClientConfig(conn.TLS).
+		SetHeader("Accept", "application/json").
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+	if conn.Username != "" {
+		c.client.SetBasicAuth(conn.Username, conn.Password)
+	}
+	if conn.token != nil {
+		c.client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+			token, err := conn.token()
+			if err != nil {
+				return err
+			}
+			req.SetAuthToken(token)
+			return nil
+		})
+	}
+	return c
+}
+
+// IsAvailable checks if the client is connected to a cluster
+func (c *KubernetesClient) IsAvailable() bool {
+	return c.client != nil
+}
+
+// InitError returns why the client is unavailable, if it was enabled
+func (c *KubernetesClient) InitError() error {
+	return c.initErr
+}
+
+// Context returns the kubeconfig context (or "in-cluster") and API server
+func (c *KubernetesClient) Context() (string, string) {
+	if c.conn == nil {
+		return "", ""
+	}
+	return c.conn.Context, c.conn.Server
+}
+
+// Namespaces returns the namespaces the tools may access
+func (c *KubernetesClient) Namespaces() []string {
+	names := make([]string, 0, len(c.namespaces))
+	for ns := range c.namespaces {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveNamespace applies the default namespace and the allowlist
+func (c *KubernetesClient) resolveNamespace(namespace string) (string, error) {
+	if namespace == "" {
+		namespace = c.conn.Namespace
+		if !c.namespaces[namespace] && len(c.namespaces) == 1 {
+			namespace = c.Namespaces()[0]
+		}
+	}
+	if !c.namespaces[namespace] {
+		return "", fmt.Errorf("namespace %q is not allowed (allowed: %s)", namespace, strings.Join(c.Namespaces(), ", "))
+	}
+	return namespace, nil
+}
+
+// get performs a GET and decodes the JSON response into result
+func (c *KubernetesClient) get(ctx context.Context, path string, params map[string]string, result interface{}) error {
+	if !c.IsAvailable() {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		Get(path)
+	if err != nil {
+		return fmt.Errorf("kubernetes API request failed: %w", err)
+	}
+	if resp.IsError() {
+		return apiError(resp)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body(), result); err != nil {
+		return fmt.Errorf("failed to parse kubernetes response: %w", err)
+	}
+	return nil
+}
+
+// apiError extracts the message of a metav1.Status error body
+func apiError(resp *resty.Response) error {
+	var status struct {
+		Message string `json:"message"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.Unmarshal(resp.Body(), &status); err == nil && status.Message != "" {
+		return fmt.Errorf("kubernetes API error: %s (%d %s)", status.Message, resp.StatusCode(), status.Reason)
+	}
+	return fmt.Errorf("kubernetes API error: %s", resp.Status())
+}
+
+// ListPods lists pods with kubectl-style status, readiness and restart counts
+func (c *KubernetesClient) ListPods(ctx context.Context, namespace, labelSelector, fieldSelector string, limit int) ([]map[string]interface{}, string, error) {
+	namespace, err := c.resolveNamespace(namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	params := map[string]string{"limit": strconv.Itoa(limit)}
+	if labelSelector != "" {
+		params["labelSelector"] = labelSelector
+	}
+	if fieldSelector != "" {
+		params["fieldSelector"] = fieldSelector
+	}
+	var list struct {
+		Items []Pod `json:"items"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/pods", url.PathEscape(namespace)), params, &list); err != nil {
+		return nil, "", err
+	}
+
+	pods := make([]map[string]interface{}, 0, len(list.Items))
+	for _, pod := range list.Items {
+		pods = append(pods, summarizePod(&pod))
+	}
+	return pods, namespace, nil
+}
+
+// summarizePod renders a pod like a kubectl get pods row, plus the reasons behind restarts
+func summarizePod(pod *Pod) map[string]interface{} {
+	ready, restarts := 0, 0
+	var containers []map[string]interface{}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		restarts += status.RestartCount
+		container := map[string]interface{}{
+			"name":     status.Name,
+			"ready":    status.Ready,
+			"restarts": status.RestartCount,
+			"state":    describeState(status.State),
+		}
+		if last := describeState(status.LastState); last != "" {
+			container["last_state"] = last
+		}
+		containers = append(containers, container)
+	}
+
+	summary := map[string]interface{}{
+		"name":     pod.Metadata.Name,
+		"status":   podStatus(pod),
+		"ready":    fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+		"restarts": restarts,
+		"age":      age(pod.Metadata.CreationTimestamp),
+		"node":     pod.Spec.NodeName,
+		"ip":       pod.Status.PodIP,
+	}
+	if len(containers) > 0 {
+		summary["containers"] = containers
+	}
+	if pod.Status.Message != "" {
+		summary["message"] = pod.Status.Message
+	}
+	for _, owner := range pod.Metadata.OwnerReferences {
+		summary["owner"] = owner.Kind + "/" + owner.Name
+	}
+	return summary
+}
+
+// podStatus mirrors the STATUS column of kubectl get pods
+func podStatus(pod *Pod) string {
+	if pod.Metadata.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for i, status := range pod.Status.InitContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode == 0 {
+			continue
+		}
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing" {
+			return "Init:" + status.State.Waiting.Reason
+		}
+		if status.State.Terminated != nil {
+			return "Init:" + status.State.Terminated.Reason
+		}
+		return fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+	}
+	status := pod.Status.Phase
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			status = cs.State.Terminated.Reason
+		}
+	}
+	return status
+}
+
+func describeState(state ContainerState) string {
+	switch {
+	case state.Waiting != nil:
+		text := "waiting: " + state.Waiting.Reason
+		if state.Waiting.Message != "" {
+			text += " (" + state.Waiting.Message + ")"
+		}
+		return text
+	case state.Running != nil:
+		return "running since " + state.Running.StartedAt.UTC().Format(time.RFC3339)
+	case state.Terminated != nil:
+		text := fmt.Sprintf("terminated: %s, exit code %d", state.Terminated.Reason, state.Terminated.ExitCode)
+		if !state.Terminated.FinishedAt.IsZero() {
+			text += " at " + state.Terminated.FinishedAt.UTC().Format(time.RFC3339)
+		}
+		return text
+	}
+	return ""
+}
+
+// LogOptions selects which part of a container log is returned
+type LogOptions struct {
+	Container    string
+	TailLines    int
+	SinceSeconds int
+	Previous     bool
+	Timestamps   bool
+}
+
+// GetPodLogs returns the tail of a container's log, capped at the configured byte limit
+func (c *KubernetesClient) GetPodLogs(ctx context.Context, namespace, pod string, opts LogOptions) (map[string]interface{}, error) {
+	namespace, err := c.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	if pod == "" {
+		return nil, fmt.Errorf("pod name is required")
+	}
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	if opts.TailLines <= 0 {
+		opts.TailLines = defaultTailLines
+	}
+
+	params := map[string]string{
+		"tailLines": strconv.Itoa(opts.TailLines),
+		// One extra byte tells whether the log was cut
+		"limitBytes": strconv.Itoa(c.maxLogBytes + 1),
+	}
+	if opts.Container != "" {
+		params["container"] = opts.Container
+	}
+	if opts.SinceSeconds > 0 {
+		params["sinceSeconds"] = strconv.Itoa(opts.SinceSeconds)
+	}
+	if opts.Previous {
+		params["previous"] = "true"
+	}
+	if opts.Timestamps {
+		params["timestamps"] = "true"
+	}
+
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		SetHeader("Accept", "text/plain").
+		Get(fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log", url.PathEscape(namespace), url.PathEscape(pod)))
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes API request failed: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+
+	logText := resp.String()
+	truncated := len(logText) > c.maxLogBytes
+	if truncated {
+		logText = logText[:c.maxLogBytes]
+	}
+	result := map[string]interface{}{
+		"namespace": namespace,
+		"pod":       pod,
+		"log":       logText,
+		"lines":     strings.Count(logText, "\n"),
+		"truncated": truncated,
+	}
+	if opts.Container != "" {
+		result["container"] = opts.Container
+	}
+	if opts.Previous {
+		result["previous"] = true
+	}
+	return result, nil
+}
+
+// DescribeDeployment returns a deployment's rollout status, containers, replica sets, pods and recent events
+func (c *KubernetesClient) DescribeDeployment(ctx context.Context, namespace, name string) (map[string]interface{}, error) {
+	namespace, err := c.resolveNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("deployment name is required")
+	}
+
+	var deployment Deployment
+	if err := c.get(ctx, fmt.Sprintf("/apis/apps/v1/namespaces/%s/deployments/%s", url.PathEscape(namespace), url.PathEscape(name)), nil, &deployment); err != nil {
+		return nil, err
+	}
+
+	desired := 1
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+
+	conditions := make([]map[string]interface{}, 0, len(status.Conditions))
+	for _, condition := range status.Conditions {
+		conditions = append(conditions, map[string]interface{}{
+			"type":    condition.Type,
+			"status":  condition.Status,
+			"reason":  condition.Reason,
+			"message": condition.Message,
+			"updated": condition.LastUpdateTime.UTC().Format(time.RFC3339),
+		})
+	}
+
+	result := map[string]interface{}{
+		"name":      deployment.Metadata.Name,
+		"namespace": namespace,
+		"age":       age(deployment.Metadata.CreationTimestamp),
+		"replicas": map[string]int{
+			"desired":     desired,
+			"current":     status.Replicas,
+			"updated":     status.UpdatedReplicas,
+			"ready":       status.ReadyReplicas,
+			"available":   status.AvailableReplicas,
+			"unavailable": status.UnavailableReplicas,
+		},
+		"rollout_complete": status.ObservedGeneration >= deployment.Metadata.Generation &&
+			status.UpdatedReplicas == desired && status.AvailableReplicas == desired && status.Replicas == desired,
+		"paused":     deployment.Spec.Paused,
+		"strategy":   deployment.Spec.Strategy,
+		"selector":   deployment.Spec.Selector.MatchLabels,
+		"containers": deployment.Spec.Template.Spec.Containers,
+		"conditions": conditions,
+	}
+	if revision := deployment.Metadata.Annotations["deployment.kubernetes.io/revision"]; revision != "" {
+		result["revision"] = revision
+	}
+
+	selector := labelSelector(deployment.Spec.Selector.MatchLabels)
+	if selector == "" {
+		return result, nil
+	}
+
+	// Replica sets and pods are best effort: the deployment itself is the main answer
+	var replicaSets struct {
+		Items []ReplicaSet `json:"items"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/apis/apps/v1/namespaces/%s/replicasets", url.PathEscape(namespace)), map[string]string{"labelSelector": selector}, &replicaSets); err == nil {
+		var history []map[string]interface{}
+		for _, rs := range replicaSets.Items {
+			if !ownedBy(rs.Metadata, "Deployment", name) {
+				continue
+			}
+			var images []string
+			for _, container := range rs.Spec.Template.Spec.Containers {
+				images = append(images, container.Image)
+			}
+			history = append(history, map[string]interface{}{
+				"name":     rs.Metadata.Name,
+				"revision": rs.Metadata.Annotations["deployment.kubernetes.io/revision"],
+				"replicas": rs.Status.Replicas,
+				"ready":    rs.Status.ReadyReplicas,
+				"images":   images,
+				"age":      age(rs.Metadata.CreationTimestamp),
+			})
+		}
+		sort.Slice(history, func(i, j int) bool {
+			a, _ := strconv.Atoi(fmt.Sprint(history[i]["revision"]))
+			b, _ := strconv.Atoi(fmt.Sprint(history[j]["revision"]))
+			return a > b
+		})
+		result["replica_sets"] = history
+	} else {
+		result["replica_sets_error"] = err.Error()
+	}
+
+	if pods, _, err := c.ListPods(ctx, namespace, selector, "", defaultListLimit); err == nil {
+		result["pods"] = pods
+	} else {
+		result["pods_error"] = err.Error()
+	}
+
+	if events, _, err := c.GetEvents(ctx, namespace, "Deployment", name, false, 20); err == nil {
+		result["events"] = events
+	}
+	return result, nil
+}
+
+// GetEvents lists recent events, newest first, optionally for one object and only warnings
+func (c *KubernetesClient) GetEvents(ctx context.Context, namespace, kind, name string, warningsOnly bool, limit int) ([]map[string]interface{}, string, error) {
+	namespace, err := c.resolveNamespace(namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var selectors []string
+	if kind != "" {
+		selectors = append(selectors, "involvedObject.kind="+kind)
+	}
+	if name != "" {
+		selectors = append(selectors, "involvedObject.name="+name)
+	}
+	if warningsOnly {
+		selectors = append(selectors, "type=Warning")
+	}
+	params := map[string]string{}
+	if len(selectors) > 0 {
+		params["fieldSelector"] = strings.Join(selectors, ",")
+	}
+
+	var list struct {
+		Items []Event `json:"items"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/events", url.PathEscape(namespace)), params, &list); err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return eventTime(&list.Items[i]).After(eventTime(&list.Items[j]))
+	})
+	if len(list.Items) > limit {
+		list.Items = list.Items[:limit]
+	}
+
+	events := make([]map[string]interface{}, 0, len(list.Items))
+	for _, event := range list.Items {
+		count := event.Count
+		if event.Series != nil && event.Series.Count > count {
+			count = event.Series.Count
+		}
+		if count == 0 {
+			count = 1
+		}
+		last := eventTime(&event)
+		events = append(events, map[string]interface{}{
+			"type":      event.Type,
+			"reason":    event.Reason,
+			"object":    event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			"message":   event.Message,
+			"count":     count,
+			"last_seen": last.UTC().Format(time.RFC3339),
+			"age":       age(last),
+			"source":    event.Source.Component,
+		})
+	}
+	return events, namespace, nil
+}
+
+// eventTime picks the most recent timestamp an event carries
+func eventTime(event *Event) time.Time {
+	latest := event.LastTimestamp
+	for _, t := range []time.Time{event.EventTime, event.FirstTimestamp, event.Metadata.CreationTimestamp} {
+		if latest.IsZero() {
+			latest = t
+		}
+	}
+	if event.Series != nil && event.Series.LastObservedTime.After(latest) {
+		latest = event.Series.LastObservedTime
+	}
+	return latest
+}
+
+func ownedBy(meta ObjectMeta, kind, name string) bool {
+	for _, owner := range meta.OwnerReferences {
+		if owner.Kind == kind && owner.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func labelSelector(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for key, value := range labels {
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// age renders the time since t like kubectl's AGE column
+func age(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// Close closes the Kubernetes client
+func (c *KubernetesClient) Close() error {
+	// HTTP client doesn't need explicit closing
+	return nil
+}
@@ -0,0 +1,350 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// KubernetesProvider exposes read-only pod, log, deployment and event tools for the configured namespaces.
+// Only GET requests are made; the credentials should be bound to a read-only role.
+type KubernetesProvider struct {
+	*provider.BaseProvider
+	client *KubernetesClient
+}
+
+// NewKubernetesProvider creates a new Kubernetes provider with config
+func NewKubernetesProvider(cfg *config.KubernetesConfig) *KubernetesProvider {
+	p := &KubernetesProvider{
+		BaseProvider: provider.NewBaseProvider("kubernetes"),
+		client:       NewKubernetesClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Kubernetes not configured", p.client.InitError())
+		if err := p.client.InitError(); err != nil {
+			log.Printf("⚠ Kubernetes provider not available: %v", err)
+		}
+		return p
+	}
+
+	p.SetAvailable(true)
+	kubeContext, apiServer := p.client.Context()
+	log.Printf("✓ Kubernetes provider initialized successfully (context %s, %s)", kubeContext, apiServer)
+
+	return p
+}
+
+// Test tests the Kubernetes configuration (for ProviderClient interface compatibility)
+func (p *KubernetesProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("kubernetes provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Kubernetes tools to the MCP server (for ProviderClient interface compatibility)
+func (p *KubernetesProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the Kubernetes provider
+func (p *KubernetesProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds Kubernetes tools to the MCP server
+func (p *KubernetesProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Kubernetes provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createListPodsTool().Tool, p.createListPodsTool().Handler},
+		{p.createGetPodLogsTool().Tool, p.createGetPodLogsTool().Handler},
+		{p.createDescribeDeploymentTool().Tool, p.createDescribeDeploymentTool().Handler},
+		{p.createGetEventsTool().Tool, p.createGetEventsTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered Kubernetes tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All Kubernetes tools registered successfully")
+}
+
+// namespaceSchema is the namespace property shared by all tools
+func (p *KubernetesProvider) namespaceSchema() string {
+	allowed, _ := json.Marshal(p.client.Namespaces())
+	return `"namespace": {
+					"type": "string",
+					"description": "Namespace (default: the kubeconfig context's namespace). Allowed: ` + string(allowed[1:len(allowed)-1]) + `"
+				}`
+}
+
+// createListPodsTool creates the pod listing tool
+func (p *KubernetesProvider) createListPodsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "k8s_list_pods",
+		Description: "List pods in a namespace with kubectl-style status (CrashLoopBackOff, OOMKilled, ...), readiness, restart counts, node, IP and the last termination reason of each container",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.namespaceSchema() + `,
+				"label_selector": {
+					"type": "string",
+					"description": "Label selector, e.g. app=api,tier!=cache"
+				},
+				"field_selector": {
+					"type": "string",
+					"description": "Field selector, e.g. status.phase!=Running or spec.nodeName=node-1"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of pods to return",
+					"default": 100
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Namespace     string `json:"namespace,omitempty"`
+			LabelSelector string `json:"label_selector,omitempty"`
+			FieldSelector string `json:"field_selector,omitempty"`
+			Limit         int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		pods, namespace, err := p.client.ListPods(ctx, args.Namespace, args.LabelSelector, args.FieldSelector, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"namespace": namespace,
+			"pods":      pods,
+			"total":     len(pods),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createGetPodLogsTool creates the pod log tool
+func (p *KubernetesProvider) createGetPodLogsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "k8s_get_pod_logs",
+		Description: "Get the tail of a pod container's log; use previous to read the log of the crashed instance of a restarting container",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.namespaceSchema() + `,
+				"pod": {
+					"type": "string",
+					"description": "Pod name"
+				},
+				"container": {
+					"type": "string",
+					"description": "Container name (required when the pod has several containers)"
+				},
+				"tail_lines": {
+					"type": "integer",
+					"description": "Number of lines from the end of the log",
+					"default": 200
+				},
+				"since_seconds": {
+					"type": "integer",
+					"description": "Only return lines newer than this many seconds"
+				},
+				"previous": {
+					"type": "boolean",
+					"description": "Return the log of the previous (terminated) container instance",
+					"default": false
+				},
+				"timestamps": {
+					"type": "boolean",
+					"description": "Prefix each line with its timestamp",
+					"default": false
+				}
+			},
+			"required": ["pod"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Namespace    string `json:"namespace,omitempty"`
+			Pod          string `json:"pod"`
+			Container    string `json:"container,omitempty"`
+			TailLines    int    `json:"tail_lines,omitempty"`
+			SinceSeconds int    `json:"since_seconds,omitempty"`
+			Previous     bool   `json:"previous,omitempty"`
+			Timestamps   bool   `json:"timestamps,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Pod == "" {
+			return p.createErrorResult(fmt.Errorf("pod parameter is required")), nil
+		}
+
+		result, err := p.client.GetPodLogs(ctx, args.Namespace, args.Pod, LogOptions{
+			Container:    args.Container,
+			TailLines:    args.TailLines,
+			SinceSeconds: args.SinceSeconds,
+			Previous:     args.Previous,
+			Timestamps:   args.Timestamps,
+		})
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDescribeDeploymentTool creates the deployment describe tool
+func (p *KubernetesProvider) createDescribeDeploymentTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "k8s_describe_deployment",
+		Description: "Describe a deployment: replica counts and rollout status, conditions, strategy, container images/resources/probes, replica set history by revision, its pods and recent events",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.namespaceSchema() + `,
+				"name": {
+					"type": "string",
+					"description": "Deployment name"
+				}
+			},
+			"required": ["name"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Namespace string `json:"namespace,omitempty"`
+			Name      string `json:"name"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Name == "" {
+			return p.createErrorResult(fmt.Errorf("name parameter is required")), nil
+		}
+
+		result, err := p.client.DescribeDeployment(ctx, args.Namespace, args.Name)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createGetEventsTool creates the events tool
+func (p *KubernetesProvider) createGetEventsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "k8s_get_events",
+		Description: "List recent events in a namespace, newest first, optionally for one object (e.g. a pod that fails scheduling or probes) and only warnings",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				` + p.namespaceSchema() + `,
+				"kind": {
+					"type": "string",
+					"description": "Kind of the involved object, e.g. Pod, Deployment, ReplicaSet, Node"
+				},
+				"name": {
+					"type": "string",
+					"description": "Name of the involved object"
+				},
+				"warnings_only": {
+					"type": "boolean",
+					"description": "Only return Warning events",
+					"default": false
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of events to return",
+					"default": 50
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Namespace    string `json:"namespace,omitempty"`
+			Kind         string `json:"kind,omitempty"`
+			Name         string `json:"name,omitempty"`
+			WarningsOnly bool   `json:"warnings_only,omitempty"`
+			Limit        int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		events, namespace, err := p.client.GetEvents(ctx, args.Namespace, args.Kind, args.Name, args.WarningsOnly, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"namespace": namespace,
+			"events":    events,
+			"total":     len(events),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *KubernetesProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Kubernetes Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *KubernetesProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that KubernetesProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*KubernetesProvider)(nil)
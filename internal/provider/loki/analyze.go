@@ -0,0 +1,297 @@
+package loki
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Bounds of loki_analyze
+const (
+	defaultAnalyzeLimit    = 1000
+	maxAnalyzeLimit        = 5000
+	defaultAnalyzeTop      = 10
+	defaultAnalyzeExamples = 3
+	defaultHistogramBins   = 20
+	maxPatternChars        = 200
+	maxLabelValues         = 10
+)
+
+// AnalyzeOptions shapes the aggregates of an analysis
+type AnalyzeOptions struct {
+	Top      int           // Clusters and label values reported
+	Examples int           // Example lines kept per cluster
+	Labels   []string      // Labels counted by value; every label when empty
+	Bucket   time.Duration // Width of the histogram buckets; a twentieth of the time range when zero
+	From     time.Time     // Start of the histogram; the oldest line when zero
+	To       time.Time     // End of the histogram; the newest line when zero
+}
+
+// Analysis aggregates log lines
+type Analysis struct {
+	Lines     int                     `json:"lines"`
+	From      time.Time               `json:"from,omitzero"`
+	To        time.Time               `json:"to,omitzero"`
+	Levels    map[string]int          `json:"levels"`
+	TopErrors []*Cluster              `json:"top_errors"`
+	TopLines  []*Cluster              `json:"top_messages"`
+	Labels    map[string][]LabelCount `json:"labels"`
+	Bucket    string                  `json:"bucket,omitempty"`
+	Histogram []HistogramBucket       `json:"histogram"`
+}
+
+// Cluster groups lines whose messages only differ in ids, numbers and other variable parts
+type Cluster struct {
+	Pattern   string    `json:"pattern"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen,omitzero"`
+	LastSeen  time.Time `json:"last_seen,omitzero"`
+	Examples  []string  `json:"examples"`
+}
+
+// LabelCount is the number of lines with a label value
+type LabelCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// HistogramBucket counts the lines of a time bucket
+type HistogramBucket struct {
+	Start  time.Time `json:"start"`
+	Count  int       `json:"count"`
+	Errors int       `json:"errors"`
+}
+
+// Variable parts of messages, replaced in the order listed
+var messageNormalizers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<ts>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`), "<email>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{0,64}(\d[a-f]|[a-f]\d)[0-9a-f]{2,64}\b`), "<hex>"},
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), `"<str>"`},
+	{regexp.MustCompile(`\b\d+(\.\d+)?(ms|s|m|h|µs|us|ns|b|kb|mb|gb)?\b`), "<n>"},
+}
+
+var (
+	levelPattern       = regexp.MustCompile(`(?i)\b(fatal|panic|critical|crit|error|err|warning|warn|info|debug|trace)\b`)
+	logfmtField        = regexp.MustCompile(`(?:^|\s)(level|lvl|severity|msg|message)=("(?:[^"\\]|\\.)*"|\S+)`)
+	messageFields      = []string{"msg", "message", "error", "err", "log"}
+	levelFields        = []string{"level", "lvl", "severity", "detected_level"}
+	normalizedLevelMap = map[string]string{
+		"fatal": "error", "panic": "error", "critical": "error", "crit": "error", "error": "error", "err": "error",
+		"warning": "warn", "warn": "warn", "info": "info", "debug": "debug", "trace": "debug",
+	}
+)
+
+// Analyze aggregates log lines into level counts, clusters of similar messages, label value counts
+// and a time histogram
+func Analyze(lines []LogLine, opts AnalyzeOptions) *Analysis {
+	if opts.Top <= 0 {
+		opts.Top = defaultAnalyzeTop
+	}
+	if opts.Examples <= 0 {
+		opts.Examples = defaultAnalyzeExamples
+	}
+	a := &Analysis{Lines: len(lines), Levels: map[string]int{}, Labels: map[string][]LabelCount{}}
+	clusters := map[string]*Cluster{} // Pattern -> cluster of every line
+	errors := map[string]*Cluster{}   // Pattern -> cluster of error lines
+	values := map[string]map[string]int{}
+	for _, line := range lines {
+		if !line.Timestamp.IsZero() && (a.From.IsZero() || line.Timestamp.Before(a.From)) {
+			a.From = line.Timestamp
+		}
+		if line.Timestamp.After(a.To) {
+			a.To = line.Timestamp
+		}
+	}
+
+	isError := make([]bool, len(lines))
+	for i, line := range lines {
+		level, message := parseLine(line)
+		a.Levels[level]++
+		pattern := normalizeMessage(message)
+		addToCluster(clusters, pattern, line, opts.Examples)
+		if level == "error" {
+			isError[i] = true
+			addToCluster(errors, pattern, line, opts.Examples)
+		}
+		for name, value := range line.Labels {
+			if len(opts.Labels) > 0 && !slices.Contains(opts.Labels, name) {
+				continue
+			}
+			if values[name] == nil {
+				values[name] = map[string]int{}
+			}
+			values[name][value]++
+		}
+	}
+
+	a.TopErrors = topClusters(errors, opts.Top)
+	a.TopLines = topClusters(clusters, opts.Top)
+	for name, counts := range values {
+		a.Labels[name] = topValues(counts, min(opts.Top, maxLabelValues))
+	}
+	from, to := a.From, a.To
+	if !opts.From.IsZero() && !opts.To.IsZero() && opts.From.Before(opts.To) {
+		from, to = opts.From, opts.To
+	}
+	a.Histogram, a.Bucket = histogram(lines, isError, from, to, opts.Bucket)
+	return a
+}
+
+// parseLine finds the level and message of a line from its labels, JSON fields or logfmt fields,
+// falling back to the first level word and the whole line
+func parseLine(line LogLine) (string, string) {
+	level, message := "", line.Line
+
+	var fields map[string]interface{}
+	if strings.HasPrefix(strings.TrimSpace(line.Line), "{") && json.Unmarshal([]byte(line.Line), &fields) == nil {
+		for _, name := range levelFields {
+			if value, ok := fields[name].(string); ok && level == "" {
+				level = value
+			}
+		}
+		for _, name := range messageFields {
+			if value, ok := fields[name].(string); ok && value != "" {
+				message = value
+				break
+			}
+		}
+	} else {
+		for _, match := range logfmtField.FindAllStringSubmatch(line.Line, -1) {
+			value := strings.Trim(match[2], `"`)
+			switch match[1] {
+			case "level", "lvl", "severity":
+				if level == "" {
+					level = value
+				}
+			default:
+				if message == line.Line {
+					message = value
+				}
+			}
+		}
+	}
+	for _, name := range levelFields {
+		if value := line.Labels[name]; value != "" && level == "" {
+			level = value
+		}
+	}
+	if level == "" {
+		level = levelPattern.FindString(line.Line)
+	}
+
+	if normalized, ok := normalizedLevelMap[strings.ToLower(level)]; ok {
+		return normalized, message
+	}
+	return "unknown", message
+}
+
+// normalizeMessage replaces the variable parts of a message, so similar messages share a pattern
+func normalizeMessage(message string) string {
+	pattern := strings.Join(strings.Fields(message), " ")
+	for _, n := range messageNormalizers {
+		pattern = n.pattern.ReplaceAllString(pattern, n.replacement)
+	}
+	if utf8.RuneCountInString(pattern) > maxPatternChars {
+		pattern = string([]rune(pattern)[:maxPatternChars]) + "…"
+	}
+	return pattern
+}
+
+func addToCluster(clusters map[string]*Cluster, pattern string, line LogLine, examples int) {
+	cluster := clusters[pattern]
+	if cluster == nil {
+		cluster = &Cluster{Pattern: pattern, FirstSeen: line.Timestamp, LastSeen: line.Timestamp}
+		clusters[pattern] = cluster
+	}
+	cluster.Count++
+	if line.Timestamp.Before(cluster.FirstSeen) {
+		cluster.FirstSeen = line.Timestamp
+	}
+	if line.Timestamp.After(cluster.LastSeen) {
+		cluster.LastSeen = line.Timestamp
+	}
+	if len(cluster.Examples) < examples {
+		cluster.Examples = append(cluster.Examples, line.Line)
+	}
+}
+
+// topClusters returns the largest clusters, most lines first
+func topClusters(clusters map[string]*Cluster, top int) []*Cluster {
+	sorted := make([]*Cluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		sorted = append(sorted, cluster)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Pattern < sorted[j].Pattern
+	})
+	return sorted[:min(len(sorted), top)]
+}
+
+// topValues returns the most frequent values of a label
+func topValues(values map[string]int, top int) []LabelCount {
+	counts := make([]LabelCount, 0, len(values))
+	for value, count := range values {
+		counts = append(counts, LabelCount{Value: value, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts[:min(len(counts), top)]
+}
+
+// histogram counts the lines and error lines per time bucket between from and to
+func histogram(lines []LogLine, isError []bool, from, to time.Time, bucket time.Duration) ([]HistogramBucket, string) {
+	if from.IsZero() {
+		return []HistogramBucket{}, ""
+	}
+	if bucket <= 0 {
+		bucket = histogramBucket(to.Sub(from))
+	}
+	start := from.Truncate(bucket)
+	buckets := make([]HistogramBucket, int(to.Sub(start)/bucket)+1)
+	for i := range buckets {
+		buckets[i].Start = start.Add(time.Duration(i) * bucket)
+	}
+	for i, line := range lines {
+		if line.Timestamp.IsZero() || line.Timestamp.Before(start) || line.Timestamp.After(to) {
+			continue
+		}
+		b := &buckets[int(line.Timestamp.Sub(start)/bucket)]
+		b.Count++
+		if isError[i] {
+			b.Errors++
+		}
+	}
+	return buckets, bucket.String()
+}
+
+// histogramBucket picks a round bucket width giving about twenty buckets over a time range
+func histogramBucket(span time.Duration) time.Duration {
+	widths := []time.Duration{
+		time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+		time.Minute, 5 * time.Minute, 10 * time.Minute, 30 * time.Minute,
+		time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+	}
+	for _, width := range widths {
+		if span/width < defaultHistogramBins {
+			return width
+		}
+	}
+	return widths[len(widths)-1]
+}
@@ -1,27 +1,81 @@
 package loki
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
 
 	"dev-mcp/internal/config"
 )
 
+const (
+	defaultLimit = 100
+	defaultSince = time.Hour
+)
+
+// QueryParams holds the parameters of a LogQL query
+type QueryParams struct {
+	Query     string
+	Start     time.Time
+	End       time.Time
+	Limit     int
+	Direction string        // "backward" (newest first, default) or "forward"
+	Step      time.Duration // Resolution for metric range queries; zero lets Loki choose
+}
+
+// QueryResponse is the response of the Loki query APIs
+type QueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+		Stats      json.RawMessage `json:"stats,omitempty"`
+	} `json:"data"`
+}
+
+// Stream is a single log stream of a "streams" result
+type Stream struct {
+	Labels map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
 // Client represents a Loki client
 type Client struct {
 	config    *config.LokiConfig
+	client    *resty.Client
 	available bool
 }
 
 // NewClient creates a new Loki client
 func NewClient(cfg *config.LokiConfig) *Client {
-	if cfg == nil {
+	if cfg == nil || cfg.Host == "" {
 		return &Client{
 			available: false,
 		}
 	}
 
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.Host, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(60 * time.Second)
+
+	if cfg.AuthToken != "" {
+		client.SetAuthToken(cfg.AuthToken)
+	} else if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	if cfg.Tenant != "" {
+		client.SetHeader("X-Scope-OrgID", cfg.Tenant)
+	}
+
 	return &Client{
 		config:    cfg,
+		client:    client,
 		available: true,
 	}
 }
@@ -31,62 +85,189 @@ func (c *Client) IsAvailable() bool {
 	return c.available
 }
 
-// QueryLogs executes a LogQL query and returns results
-func (c *Client) QueryLogs(query string, limit int) (interface{}, error) {
+// QueryRange executes a LogQL query over a time range (/loki/api/v1/query_range)
+func (c *Client) QueryRange(params QueryParams) (*QueryResponse, error) {
+	return c.queryRange(context.Background(), params)
+}
+
+func (c *Client) queryRange(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	if !c.available {
+		return nil, fmt.Errorf("loki client not available")
+	}
+
+	params = withDefaults(params)
+	query := map[string]string{
+		"query":     params.Query,
+		"start":     strconv.FormatInt(params.Start.UnixNano(), 10),
+		"end":       strconv.FormatInt(params.End.UnixNano(), 10),
+		"limit":     strconv.Itoa(params.Limit),
+		"direction": params.Direction,
+	}
+	if params.Step > 0 {
+		query["step"] = params.Step.String()
+	}
+
+	return c.doQuery(ctx, "/loki/api/v1/query_range", query)
+}
+
+// Query executes an instant LogQL query at params.End (/loki/api/v1/query)
+func (c *Client) Query(params QueryParams) (*QueryResponse, error) {
 	if !c.available {
 		return nil, fmt.Errorf("loki client not available")
 	}
 
-	// Set default limit
-	if limit == 0 {
-		limit = 100
-	}
-
-	// For demonstration purposes, return a mock result
-	result := map[string]interface{}{
-		"status": "success",
-		"data": map[string]interface{}{
-			"resultType": "streams",
-			"result": []interface{}{
-				map[string]interface{}{
-					"stream": map[string]interface{}{
-						"job":      "api-server",
-						"instance": "localhost:8080",
-						"level":    "info",
-					},
-					"values": [][]string{
-						{"1640995200000000000", fmt.Sprintf("LogQL: %s", query)},
-						{"1640995201000000000", "INFO: Processing log query"},
-						{"1640995202000000000", fmt.Sprintf("INFO: Found logs matching query (limit: %d)", limit)},
-					},
-				},
-			},
-		},
-		"stats": map[string]interface{}{
-			"summary": map[string]interface{}{
-				"bytesTotal": 1024,
-				"linesTotal": 3,
-				"execTime":   0.1,
-				"queueTime":  0.01,
-			},
-		},
-	}
-
-	return result, nil
+	params = withDefaults(params)
+	query := map[string]string{
+		"query":     params.Query,
+		"time":      strconv.FormatInt(params.End.UnixNano(), 10),
+		"limit":     strconv.Itoa(params.Limit),
+		"direction": params.Direction,
+	}
+
+	return c.doQuery(context.Background(), "/loki/api/v1/query", query)
+}
+
+// doQuery performs a query request and decodes the response
+func (c *Client) doQuery(ctx context.Context, path string, query map[string]string) (*QueryResponse, error) {
+	var result QueryResponse
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(query).
+		SetResult(&result).
+		Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loki: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("loki query returned status %q", result.Status)
+	}
+	return &result, nil
+}
+
+// QueryLogs executes a LogQL range query over the last hour and returns results
+func (c *Client) QueryLogs(query string, limit int) (interface{}, error) {
+	return c.QueryRange(QueryParams{Query: query, Limit: limit})
+}
+
+// Ping checks that the Loki query API answers
+func (c *Client) Ping(ctx context.Context) error {
+	if !c.available {
+		return fmt.Errorf("loki client not available")
+	}
+	resp, err := c.client.R().SetContext(ctx).Get("/loki/api/v1/labels")
+	if err != nil {
+		return fmt.Errorf("failed to reach loki: %w", err)
+	}
+	if resp.IsError() {
+		return apiError(resp)
+	}
+	return nil
 }
 
 // GetLogLabels retrieves available log labels
 func (c *Client) GetLogLabels() ([]string, error) {
+	return c.labels("/loki/api/v1/labels")
+}
+
+// GetLabelValues retrieves the known values of a label
+func (c *Client) GetLabelValues(name string) ([]string, error) {
+	return c.labels(fmt.Sprintf("/loki/api/v1/label/%s/values", name))
+}
+
+func (c *Client) labels(path string) ([]string, error) {
 	if !c.available {
 		return nil, fmt.Errorf("loki client not available")
 	}
 
-	// Return mock labels for demonstration
-	return []string{"job", "instance", "level", "app"}, nil
+	var result struct {
+		Status string   `json:"status"`
+		Data   []string `json:"data"`
+	}
+	now := time.Now()
+	resp, err := c.client.R().
+		SetQueryParams(map[string]string{
+			"start": strconv.FormatInt(now.Add(-6*time.Hour).UnixNano(), 10),
+			"end":   strconv.FormatInt(now.UnixNano(), 10),
+		}).
+		SetResult(&result).
+		Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loki labels: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp)
+	}
+	return result.Data, nil
 }
 
 // Close closes the Loki client connection
 func (c *Client) Close() error {
 	// Loki client doesn't need explicit closing
 	return nil
-}
\ No newline at end of file
+}
+
+// withDefaults fills in the time range, limit and direction
+func withDefaults(params QueryParams) QueryParams {
+	if params.End.IsZero() {
+		params.End = time.Now()
+	}
+	if params.Start.IsZero() {
+		params.Start = params.End.Add(-defaultSince)
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultLimit
+	}
+	if params.Direction == "" {
+		params.Direction = "backward"
+	}
+	return params
+}
+
+// apiError turns a Loki error response into an error carrying Loki's message
+func apiError(resp *resty.Response) error {
+	body := strings.TrimSpace(resp.String())
+	if len(body) > 500 {
+		body = body[:500] + "..."
+	}
+	if body == "" {
+		return fmt.Errorf("loki API error: %s", resp.Status())
+	}
+	return fmt.Errorf("loki API error: %s: %s", resp.Status(), body)
+}
+
+// ParseTime parses an absolute or relative time: RFC3339, unix seconds/nanoseconds,
+// "now", or a duration ago such as "15m" or "2h"
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "now" {
+		return now, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.Unix(0, n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	if d, err := ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC3339, unix timestamp or a duration like 15m", value)
+}
+
+// ParseDuration extends time.ParseDuration with a "d" (day) unit
+func ParseDuration(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -21,8 +22,8 @@ type LokiProvider struct {
 	client *Client
 }
 
-// NewLokiProvider creates a new Loki provider with config and server
-func NewLokiProvider(cfg *config.LokiConfig, server *mcp.Server) *LokiProvider {
+// NewLokiProvider creates a new Loki provider with config
+func NewLokiProvider(cfg *config.LokiConfig) *LokiProvider {
 	p := &LokiProvider{
 		BaseProvider: provider.NewBaseProvider("loki"),
 	}
@@ -32,8 +33,6 @@ func NewLokiProvider(cfg *config.LokiConfig, server *mcp.Server) *LokiProvider {
 
 	if p.client.IsAvailable() {
 		p.SetAvailable(true)
-		// Add tools to server immediately
-		p.addToolsToServer(server)
 		log.Printf("✓ Loki provider initialized successfully")
 	} else {
 		p.SetStatus(false, "Loki client initialization failed", nil)
@@ -47,6 +46,37 @@ func (p *LokiProvider) Close() error {
 	return p.client.Close()
 }
 
+// Client returns the Loki client
+func (p *LokiProvider) Client() *Client {
+	return p.client
+}
+
+// HealthCheck checks that Loki answers
+func (p *LokiProvider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.client.Ping(ctx)
+}
+
+// ToolHints names the stream labels seen recently, to build selectors from
+func (p *LokiProvider) ToolHints(ctx context.Context) map[string]string {
+	labels, err := p.client.GetLogLabels()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, label := range labels {
+		if !strings.HasPrefix(label, "__") {
+			names = append(names, label)
+		}
+	}
+	hint := provider.HintList("Labels", names, 20)
+	return map[string]string{
+		"loki_query":  hint,
+		"loki_labels": hint,
+	}
+}
+
 // Test tests the Loki configuration and connection (for ProviderClient interface compatibility)
 func (p *LokiProvider) Test(config interface{}) error {
 	// Since client is already initialized in constructor, just check availability
@@ -58,7 +88,6 @@ func (p *LokiProvider) Test(config interface{}) error {
 
 // AddTools adds Loki tools to the MCP server (for ProviderClient interface compatibility)
 func (p *LokiProvider) AddTools(server *mcp.Server, config interface{}) error {
-	// Tools are already added in constructor, but we can call addToolsToServer again if needed
 	p.addToolsToServer(server)
 	return nil
 }
@@ -78,6 +107,9 @@ func (p *LokiProvider) addToolsToServer(server *mcp.Server) {
 		{p.createLokiQueryTool().Tool, p.createLokiQueryTool().Handler},
 		{p.createLokiPresetQueryTool().Tool, p.createLokiPresetQueryTool().Handler},
 		{p.createLokiListPresetsTool().Tool, p.createLokiListPresetsTool().Handler},
+		{p.createLokiLabelsTool().Tool, p.createLokiLabelsTool().Handler},
+		{p.createLokiTailTool().Tool, p.createLokiTailTool().Handler},
+		{p.createLokiAnalyzeTool().Tool, p.createLokiAnalyzeTool().Handler},
 	}
 
 	for _, tool := range tools {
@@ -92,7 +124,7 @@ func (p *LokiProvider) addToolsToServer(server *mcp.Server) {
 func (p *LokiProvider) createLokiQueryTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
 		Name:        "loki_query",
-		Description: "Query Grafana Loki logs using LogQL",
+		Description: "Query Grafana Loki logs using LogQL over a time range (or as an instant query)",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
@@ -104,6 +136,29 @@ func (p *LokiProvider) createLokiQueryTool() entity.ToolDefinition {
 					"type": "integer",
 					"description": "Maximum number of results to return",
 					"default": 100
+				},
+				"start": {
+					"type": "string",
+					"description": "Range start: RFC3339, unix timestamp, or a duration ago such as 30m or 2h (default: 1h ago)"
+				},
+				"end": {
+					"type": "string",
+					"description": "Range end: RFC3339, unix timestamp, duration ago, or now (default: now)"
+				},
+				"direction": {
+					"type": "string",
+					"description": "Sort order of log lines",
+					"enum": ["backward", "forward"],
+					"default": "backward"
+				},
+				"step": {
+					"type": "string",
+					"description": "Query resolution step for metric queries, e.g. 30s or 5m"
+				},
+				"instant": {
+					"type": "boolean",
+					"description": "Run an instant query at 'end' instead of a range query",
+					"default": false
 				}
 			},
 			"required": ["query"]
@@ -112,8 +167,13 @@ func (p *LokiProvider) createLokiQueryTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
-			Query string `json:"query"`
-			Limit int    `json:"limit,omitempty"`
+			Query     string `json:"query"`
+			Limit     int    `json:"limit,omitempty"`
+			Start     string `json:"start,omitempty"`
+			End       string `json:"end,omitempty"`
+			Direction string `json:"direction,omitempty"`
+			Step      string `json:"step,omitempty"`
+			Instant   bool   `json:"instant,omitempty"`
 		}
 
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
@@ -124,47 +184,310 @@ func (p *LokiProvider) createLokiQueryTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("query parameter is required")), nil
 		}
 
-		// Set default limit
-		if args.Limit == 0 {
-			args.Limit = 100
+		params, err := buildQueryParams(args.Query, args.Limit, args.Start, args.End, args.Direction, args.Step)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		var result *QueryResponse
+		if args.Instant {
+			result, err = p.client.Query(params)
+		} else {
+			result, err = p.client.QueryRange(params)
+		}
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createLokiLabelsTool creates the tool listing label names or the values of a label
+func (p *LokiProvider) createLokiLabelsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "loki_labels",
+		Description: "List Loki label names, or the values of one label, seen in the last 6 hours",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"description": "Label name whose values to list; omit to list label names"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Name string `json:"name,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		var values []string
+		var err error
+		if args.Name == "" {
+			values, err = p.client.GetLogLabels()
+		} else {
+			values, err = p.client.GetLabelValues(args.Name)
+		}
+		if err != nil {
+			return p.createErrorResult(err), nil
 		}
 
-		// For demonstration purposes, return a mock result
-		// In a real implementation, you would call p.client.Query(args.Query)
 		result := map[string]interface{}{
-			"status": "success",
-			"data": map[string]interface{}{
-				"resultType": "streams",
-				"result": []interface{}{
-					map[string]interface{}{
-						"stream": map[string]interface{}{
-							"job":      "api-server",
-							"instance": "localhost:8080",
-						},
-						"values": [][]string{
-							{"1640995200000000000", "INFO: API request received"},
-							{"1640995201000000000", "INFO: Processing request"},
-							{"1640995202000000000", "INFO: Request completed successfully"},
-						},
-					},
+			"label":  args.Name,
+			"values": values,
+			"count":  len(values),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createLokiTailTool creates the tool that follows a log query for a while
+func (p *LokiProvider) createLokiTailTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "loki_tail",
+		Description: "Follow the logs of a LogQL log query for up to 30 seconds (e.g. while reproducing a bug) and return the new lines, oldest first. Stops early at max_lines",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"query": {
+					"type": "string",
+					"description": "LogQL log query, e.g. {app=\"api\"} |= \"error\""
 				},
-			},
-			"stats": map[string]interface{}{
-				"summary": map[string]interface{}{
-					"bytesTotal": 1024,
-					"linesTotal": 3,
-					"execTime":   0.1,
-					"queueTime":  0.01,
+				"duration_seconds": {
+					"type": "integer",
+					"description": "How long to follow the logs (max 30)",
+					"default": 10
+				},
+				"max_lines": {
+					"type": "integer",
+					"description": "Stop after this many lines (max 1000)",
+					"default": 200
 				},
+				"since": {
+					"type": "string",
+					"description": "Also return lines from this time on: RFC3339, unix timestamp, or a duration ago such as 30s (default: now)"
+				}
 			},
+			"required": ["query"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Query           string `json:"query"`
+			DurationSeconds int    `json:"duration_seconds,omitempty"`
+			MaxLines        int    `json:"max_lines,omitempty"`
+			Since           string `json:"since,omitempty"`
 		}
 
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Query == "" {
+			return p.createErrorResult(fmt.Errorf("query parameter is required")), nil
+		}
+
+		opts := TailOptions{
+			Duration: time.Duration(args.DurationSeconds) * time.Second,
+			MaxLines: args.MaxLines,
+		}
+		if opts.Duration <= 0 {
+			opts.Duration = defaultTailDuration
+		}
+		if opts.Duration > maxTailDuration {
+			return p.createErrorResult(fmt.Errorf("duration_seconds must be at most %d", int(maxTailDuration.Seconds()))), nil
+		}
+		if opts.MaxLines <= 0 {
+			opts.MaxLines = defaultTailLines
+		}
+		if opts.MaxLines > maxTailLines {
+			return p.createErrorResult(fmt.Errorf("max_lines must be at most %d", maxTailLines)), nil
+		}
+		if args.Since != "" {
+			since, err := ParseTime(args.Since, time.Now())
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			opts.Since = since
+		}
+
+		result, err := p.client.Tail(ctx, args.Query, opts)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
 		return p.formatJSONResult(result), nil
 	}
 
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
 
+// createLokiAnalyzeTool creates the tool that aggregates log lines instead of returning them
+func (p *LokiProvider) createLokiAnalyzeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "loki_analyze",
+		Description: "Aggregate the lines of a LogQL log query, a loki_query result or raw lines: counts by level, the top error messages and top messages normalized into patterns (ids, numbers and timestamps replaced) with example lines, counts by label value and a time histogram",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"query": {
+					"type": "string",
+					"description": "LogQL log query whose lines to analyze"
+				},
+				"start": {
+					"type": "string",
+					"description": "Range start of the query: RFC3339, unix timestamp, or a duration ago such as 30m (default: 1h ago)"
+				},
+				"end": {
+					"type": "string",
+					"description": "Range end of the query (default: now)"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Newest lines of the query to analyze (max 5000)",
+					"default": 1000
+				},
+				"result": {
+					"type": "object",
+					"description": "A loki_query result to analyze instead of running a query"
+				},
+				"lines": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Raw log lines to analyze instead of running a query"
+				},
+				"labels": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Labels to count lines by (default: every label)"
+				},
+				"bucket": {
+					"type": "string",
+					"description": "Width of the histogram buckets, e.g. 1m or 5m (default: about 20 buckets)"
+				},
+				"top": {
+					"type": "integer",
+					"description": "Message patterns and label values to report",
+					"default": 10
+				},
+				"examples": {
+					"type": "integer",
+					"description": "Example lines per message pattern",
+					"default": 3
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Query    string          `json:"query,omitempty"`
+			Start    string          `json:"start,omitempty"`
+			End      string          `json:"end,omitempty"`
+			Limit    int             `json:"limit,omitempty"`
+			Result   json.RawMessage `json:"result,omitempty"`
+			Lines    []string        `json:"lines,omitempty"`
+			Labels   []string        `json:"labels,omitempty"`
+			Bucket   string          `json:"bucket,omitempty"`
+			Top      int             `json:"top,omitempty"`
+			Examples int             `json:"examples,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		opts := AnalyzeOptions{Top: args.Top, Examples: args.Examples, Labels: args.Labels}
+		if args.Bucket != "" {
+			bucket, err := ParseDuration(args.Bucket)
+			if err != nil || bucket <= 0 {
+				return p.createErrorResult(fmt.Errorf("invalid bucket %q", args.Bucket)), nil
+			}
+			opts.Bucket = bucket
+		}
+
+		var lines []LogLine
+		switch {
+		case args.Query != "":
+			if args.Limit <= 0 {
+				args.Limit = defaultAnalyzeLimit
+			}
+			if args.Limit > maxAnalyzeLimit {
+				return p.createErrorResult(fmt.Errorf("limit must be at most %d", maxAnalyzeLimit)), nil
+			}
+			params, err := buildQueryParams(args.Query, args.Limit, args.Start, args.End, "", "")
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			response, err := p.client.queryRange(ctx, params)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			if lines, err = StreamLines(response); err != nil {
+				return p.createErrorResult(err), nil
+			}
+		case len(args.Result) > 0:
+			var response QueryResponse
+			if err := json.Unmarshal(args.Result, &response); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid result: %w", err)), nil
+			}
+			var err error
+			if lines, err = StreamLines(&response); err != nil {
+				return p.createErrorResult(err), nil
+			}
+		case len(args.Lines) > 0:
+			for _, line := range args.Lines {
+				lines = append(lines, LogLine{Line: line})
+			}
+		default:
+			return p.createErrorResult(fmt.Errorf("one of query, result or lines is required")), nil
+		}
+
+		return p.formatJSONResult(Analyze(lines, opts)), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// buildQueryParams converts tool arguments into query parameters
+func buildQueryParams(query string, limit int, start, end, direction, step string) (QueryParams, error) {
+	now := time.Now()
+	params := QueryParams{Query: query, Limit: limit, Direction: direction}
+
+	if direction != "" && direction != "backward" && direction != "forward" {
+		return params, fmt.Errorf("invalid direction %q: use backward or forward", direction)
+	}
+
+	var err error
+	if params.End, err = ParseTime(end, now); err != nil {
+		return params, err
+	}
+	if start != "" {
+		if params.Start, err = ParseTime(start, now); err != nil {
+			return params, err
+		}
+		if !params.Start.Before(params.End) {
+			return params, fmt.Errorf("start must be before end")
+		}
+	}
+	if step != "" {
+		if params.Step, err = ParseDuration(step); err != nil {
+			return params, fmt.Errorf("invalid step %q: %w", step, err)
+		}
+	}
+	return params, nil
+}
+
 // createLokiPresetQueryTool creates a tool to run predefined / parameterized queries.
 func (p *LokiProvider) createLokiPresetQueryTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
@@ -175,7 +498,9 @@ func (p *LokiProvider) createLokiPresetQueryTool() entity.ToolDefinition {
 			"properties": {
 				"name": {"type": "string", "description": "Preset query name"},
 				"params": {"type": "object", "description": "Parameter key/value overrides"},
-				"limit": {"type": "integer", "description": "Maximum number of results (for raw queries)", "default": 100}
+				"limit": {"type": "integer", "description": "Maximum number of results (for raw queries)", "default": 100},
+				"start": {"type": "string", "description": "Range start: RFC3339, unix timestamp, or a duration ago such as 30m (default: 1h ago)"},
+				"end": {"type": "string", "description": "Range end (default: now)"}
 			},
 			"required": ["name"]
 		}`),
@@ -186,6 +511,8 @@ func (p *LokiProvider) createLokiPresetQueryTool() entity.ToolDefinition {
 			Name   string            `json:"name"`
 			Params map[string]string `json:"params,omitempty"`
 			Limit  int               `json:"limit,omitempty"`
+			Start  string            `json:"start,omitempty"`
+			End    string            `json:"end,omitempty"`
 		}
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
@@ -200,8 +527,11 @@ func (p *LokiProvider) createLokiPresetQueryTool() entity.ToolDefinition {
 		if err != nil {
 			return p.createErrorResult(err), nil
 		}
-		// Simulate execution using mock client
-		result, err := p.client.QueryLogs(q, args.Limit)
+		params, err := buildQueryParams(q, args.Limit, args.Start, args.End, "", "")
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		result, err := p.client.QueryRange(params)
 		if err != nil {
 			return p.createErrorResult(err), nil
 		}
@@ -229,7 +559,7 @@ func (p *LokiProvider) createLokiListPresetsTool() entity.ToolDefinition {
 		presets := ListPresetMetadata()
 		// Build a compact textual table for readability in plain clients.
 		var b strings.Builder
-		b.WriteString("Available Loki Preset Queries (mock environment)\n\n")
+		b.WriteString("Available Loki Preset Queries\n\n")
 		for _, pset := range presets {
 			b.WriteString(pset.Name + ": " + pset.Description + "\n")
 			if len(pset.Params) > 0 {
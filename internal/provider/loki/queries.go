@@ -1,10 +1,9 @@
 package loki
 
-// This file defines a collection of commonly used (mock) Loki LogQL queries.
+// This file defines a collection of commonly used Loki LogQL queries.
 // They are exposed via preset tools so that a caller can quickly run standard
-// queries without remembering exact LogQL syntax. Since the current Loki
-// client implementation returns mock data, these queries only construct the
-// query strings; real backend integration can later plug into the same API.
+// queries without remembering exact LogQL syntax. The presets only construct
+// the query strings; execution goes through the Loki client.
 
 import (
 	"fmt"
@@ -0,0 +1,179 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Bounds of loki_tail
+const (
+	defaultTailDuration = 10 * time.Second
+	maxTailDuration     = 30 * time.Second
+	defaultTailLines    = 200
+	maxTailLines        = 1000
+	tailPollInterval    = 2 * time.Second
+)
+
+// Reasons a tail stops
+const (
+	TailStoppedDuration  = "duration"
+	TailStoppedMaxLines  = "max_lines"
+	TailStoppedCancelled = "cancelled"
+	TailStoppedError     = "error"
+)
+
+// TailOptions bounds a tail
+type TailOptions struct {
+	Duration time.Duration // How long to follow the logs
+	MaxLines int           // Lines after which the tail stops early
+	Since    time.Time     // Lines from this time on; the start of the tail when zero
+}
+
+// LogLine is a log line of a stream
+type LogLine struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Labels    map[string]string `json:"labels"`
+	Line      string            `json:"line"`
+}
+
+// TailResult holds the lines a tail collected, oldest first
+type TailResult struct {
+	Query   string    `json:"query"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Count   int       `json:"count"`
+	Polls   int       `json:"polls"`
+	Stopped string    `json:"stopped"` // duration, max_lines, cancelled or error
+	Error   string    `json:"error,omitempty"`
+	Lines   []LogLine `json:"lines"`
+}
+
+// Tail follows a log query by polling query_range forward from where the last poll ended, until the
+// duration passes, the line limit is reached or ctx ends. Lines Loki ingests after their time range was
+// polled are missed, as they would be by a live tail.
+func (c *Client) Tail(ctx context.Context, query string, opts TailOptions) (*TailResult, error) {
+	if !c.available {
+		return nil, fmt.Errorf("loki client not available")
+	}
+
+	start := time.Now()
+	from := opts.Since
+	if from.IsZero() {
+		from = start
+	}
+	result := &TailResult{Query: query, From: from, Lines: []LogLine{}}
+	deadline := start.Add(opts.Duration)
+	// Polls start at the newest line returned, so lines sharing its timestamp are not lost; seen skips
+	// the ones already returned
+	seen := map[string]bool{}
+
+	for {
+		end := time.Now()
+		if end.After(deadline) {
+			end = deadline
+		}
+		var lines []LogLine
+		if end.After(from) {
+			response, err := c.queryRange(ctx, QueryParams{
+				Query:     query,
+				Start:     from,
+				End:       end,
+				Limit:     opts.MaxLines - len(result.Lines) + len(seen),
+				Direction: "forward",
+			})
+			result.Polls++
+			if err != nil {
+				if ctx.Err() != nil {
+					result.Stopped = TailStoppedCancelled
+					break
+				}
+				if result.Polls == 1 {
+					return nil, err
+				}
+				result.Stopped, result.Error = TailStoppedError, err.Error()
+				break
+			}
+			if lines, err = StreamLines(response); err != nil {
+				return nil, err
+			}
+		}
+
+		fresh := 0
+		for _, line := range lines {
+			key := tailKey(line)
+			if seen[key] {
+				continue
+			}
+			if !line.Timestamp.Equal(from) {
+				from, seen = line.Timestamp, map[string]bool{}
+			}
+			seen[key] = true
+			fresh++
+			result.Lines = append(result.Lines, line)
+			if len(result.Lines) >= opts.MaxLines {
+				break
+			}
+		}
+		result.To = end
+
+		if len(result.Lines) >= opts.MaxLines {
+			result.Stopped = TailStoppedMaxLines
+			break
+		}
+		if !end.Before(deadline) {
+			result.Stopped = TailStoppedDuration
+			break
+		}
+		if fresh == 0 && end.After(from) {
+			// Nothing new: later polls need not cover this range again
+			from, seen = end, map[string]bool{}
+		}
+
+		timer := time.NewTimer(min(tailPollInterval, time.Until(deadline)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Stopped = TailStoppedCancelled
+		case <-timer.C:
+		}
+		if result.Stopped != "" {
+			break
+		}
+	}
+
+	result.Count = len(result.Lines)
+	return result, nil
+}
+
+// StreamLines flattens the streams of a log query response into lines ordered by time
+func StreamLines(response *QueryResponse) ([]LogLine, error) {
+	if response.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("a log query is needed, not a metric query (result type %q)", response.Data.ResultType)
+	}
+	var streams []Stream
+	if err := json.Unmarshal(response.Data.Result, &streams); err != nil {
+		return nil, fmt.Errorf("failed to decode loki streams: %w", err)
+	}
+
+	var lines []LogLine
+	for _, stream := range streams {
+		for _, value := range stream.Values {
+			ns, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid loki timestamp %q", value[0])
+			}
+			lines = append(lines, LogLine{Timestamp: time.Unix(0, ns), Labels: stream.Labels, Line: value[1]})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Timestamp.Before(lines[j].Timestamp) })
+	return lines, nil
+}
+
+// tailKey identifies a line among those sharing its timestamp; fmt prints the labels sorted by name
+func tailKey(line LogLine) string {
+	return fmt.Sprint(line.Timestamp.UnixNano(), line.Labels, line.Line)
+}
@@ -0,0 +1,175 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// credential authenticates connections with SCRAM-SHA-256 or SCRAM-SHA-1
+type credential struct {
+	mechanism string // Empty to pick from the mechanisms the server offers for the user
+	source    string
+	username  string
+	password  string
+}
+
+// newCredential validates the mechanism; without a username connections are not authenticated
+func newCredential(mechanism, source, username, password string) (*credential, error) {
+	if username == "" {
+		return nil, nil
+	}
+	mechanism = strings.ToUpper(strings.TrimSpace(mechanism))
+	switch mechanism {
+	case "", "SCRAM-SHA-256", "SCRAM-SHA-1":
+	default:
+		return nil, fmt.Errorf("unsupported auth mechanism %s (use SCRAM-SHA-256 or SCRAM-SHA-1)", mechanism)
+	}
+	if source == "" {
+		source = "admin"
+	}
+	return &credential{mechanism: mechanism, source: source, username: username, password: password}, nil
+}
+
+// supportedMechs is the hello field asking the server which mechanisms the user can use
+func (cr *credential) supportedMechs() string {
+	if cr == nil || cr.mechanism != "" {
+		return ""
+	}
+	return cr.source + "." + cr.username
+}
+
+// authenticate runs the SCRAM conversation on a connection
+func (cr *credential) authenticate(ctx context.Context, cn *conn) error {
+	mechanism := cr.mechanism
+	if mechanism == "" {
+		mechanism = "SCRAM-SHA-1"
+		if mechs, ok := cn.hello.Lookup("saslSupportedMechs"); ok {
+			if list, ok := mechs.([]interface{}); ok && slices.Contains(list, interface{}("SCRAM-SHA-256")) {
+				mechanism = "SCRAM-SHA-256"
+			}
+		}
+	}
+
+	newHash, size, password := sha256.New, sha256.Size, cr.password
+	if mechanism == "SCRAM-SHA-1" {
+		// SCRAM-SHA-1 hashes the MONGODB-CR digest of the password rather than the password itself
+		digest := md5.Sum([]byte(cr.username + ":mongo:" + cr.password))
+		newHash, size, password = sha1.New, sha1.Size, hex.EncodeToString(digest[:])
+	}
+
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	clientNonce := base64.RawStdEncoding.EncodeToString(nonce)
+	username := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(cr.username)
+	clientFirstBare := "n=" + username + ",r=" + clientNonce
+
+	reply, err := cn.command(ctx, cr.source, Document{
+		{"saslStart", int32(1)},
+		{"mechanism", mechanism},
+		{"payload", Binary{Data: []byte("n,," + clientFirstBare)}},
+		{"autoAuthorize", int32(1)},
+		{"options", Document{{"skipEmptyExchange", true}}},
+	})
+	if err != nil {
+		return err
+	}
+	conversationID, _ := reply.Lookup("conversationId")
+	serverFirst := saslPayload(reply)
+
+	attrs := scramAttributes(serverFirst)
+	serverNonce, salt64, iterations := attrs["r"], attrs["s"], attrs["i"]
+	if !strings.HasPrefix(serverNonce, clientNonce) {
+		return fmt.Errorf("server nonce does not extend the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil {
+		return fmt.Errorf("invalid salt: %w", err)
+	}
+	iter, err := strconv.Atoi(iterations)
+	if err != nil || iter <= 0 {
+		return fmt.Errorf("invalid iteration count %q", iterations)
+	}
+
+	salted, err := pbkdf2.Key(newHash, password, salt, iter, size)
+	if err != nil {
+		return err
+	}
+	clientKey := hmacSum(newHash, salted, "Client Key")
+	storedKey := newHash()
+	storedKey.Write(clientKey)
+	clientFinalBare := "c=biws,r=" + serverNonce
+	authMessage := clientFirstBare + "," + serverFirst + "," + clientFinalBare
+	proof := hmacSum(newHash, storedKey.Sum(nil), authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+
+	reply, err = cn.command(ctx, cr.source, Document{
+		{"saslContinue", int32(1)},
+		{"conversationId", conversationID},
+		{"payload", Binary{Data: []byte(clientFinalBare + ",p=" + base64.StdEncoding.EncodeToString(proof))}},
+	})
+	if err != nil {
+		return err
+	}
+	final := scramAttributes(saslPayload(reply))
+	if e, ok := final["e"]; ok {
+		return fmt.Errorf("server rejected authentication: %s", e)
+	}
+	serverSignature := hmacSum(newHash, hmacSum(newHash, salted, "Server Key"), authMessage)
+	if final["v"] != base64.StdEncoding.EncodeToString(serverSignature) {
+		return fmt.Errorf("server signature does not match")
+	}
+
+	// Servers that ignore skipEmptyExchange expect one more empty round
+	if done, _ := reply.Lookup("done"); !isTrue(done) {
+		if _, err := cn.command(ctx, cr.source, Document{
+			{"saslContinue", int32(1)},
+			{"conversationId", conversationID},
+			{"payload", Binary{Data: []byte{}}},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saslPayload returns the payload of a saslStart or saslContinue reply
+func saslPayload(reply Document) string {
+	payload, _ := reply.Lookup("payload")
+	if b, ok := payload.(Binary); ok {
+		return string(b.Data)
+	}
+	return ""
+}
+
+func hmacSum(newHash func() hash.Hash, key []byte, message string) []byte {
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// scramAttributes parses a comma-separated list of k=v attributes
+func scramAttributes(message string) map[string]string {
+	attrs := map[string]string{}
+	for _, part := range strings.Split(message, ",") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,612 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BSON element types
+const (
+	typeDouble     = 0x01
+	typeString     = 0x02
+	typeDocument   = 0x03
+	typeArray      = 0x04
+	typeBinary     = 0x05
+	typeUndefined  = 0x06
+	typeObjectID   = 0x07
+	typeBool       = 0x08
+	typeDateTime   = 0x09
+	typeNull       = 0x0A
+	typeRegex      = 0x0B
+	typeDBPointer  = 0x0C
+	typeJavaScript = 0x0D
+	typeSymbol     = 0x0E
+	typeCodeScope  = 0x0F
+	typeInt32      = 0x10
+	typeTimestamp  = 0x11
+	typeInt64      = 0x12
+	typeDecimal128 = 0x13
+	typeMinKey     = 0xFF
+	typeMaxKey     = 0x7F
+)
+
+// maxNesting bounds the depth of documents read from the server or the tools
+const maxNesting = 100
+
+// Document is an ordered BSON document. Commands must keep their key order: the server takes the first key as
+// the command name, and sort specifications are applied in order.
+type Document []Element
+
+// Element is a key and value of a Document
+type Element struct {
+	Key   string
+	Value interface{}
+}
+
+// Lookup returns the value of a key
+func (d Document) Lookup(key string) (interface{}, bool) {
+	for _, e := range d {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+// MarshalJSON writes the document as a JSON object in key order
+func (d Document) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, e := range d {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.Key)
+		b.Write(key)
+		b.WriteByte(':')
+		value, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// The BSON types without a JSON equivalent are written as MongoDB relaxed extended JSON, which the tools also
+// accept in filters and pipelines.
+
+// ObjectID is a BSON ObjectId
+type ObjectID [12]byte
+
+func (id ObjectID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$oid": hex.EncodeToString(id[:])})
+}
+
+// DateTime is a BSON UTC datetime in milliseconds since the epoch
+type DateTime int64
+
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	at := time.UnixMilli(int64(t)).UTC()
+	if at.Year() < 1970 || at.Year() > 9999 {
+		return json.Marshal(map[string]interface{}{"$date": map[string]string{"$numberLong": strconv.FormatInt(int64(t), 10)}})
+	}
+	return json.Marshal(map[string]string{"$date": at.Format("2006-01-02T15:04:05.000Z07:00")})
+}
+
+// Binary is BSON binary data with its subtype
+type Binary struct {
+	Subtype byte
+	Data    []byte
+}
+
+func (b Binary) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$binary": map[string]string{
+		"base64":  base64.StdEncoding.EncodeToString(b.Data),
+		"subType": fmt.Sprintf("%02x", b.Subtype),
+	}})
+}
+
+// Regex is a BSON regular expression
+type Regex struct {
+	Pattern string
+	Options string
+}
+
+func (r Regex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$regularExpression": map[string]string{"pattern": r.Pattern, "options": r.Options}})
+}
+
+// Timestamp is a BSON timestamp, as used in the oplog
+type Timestamp struct {
+	T uint32
+	I uint32
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"$timestamp": map[string]uint32{"t": t.T, "i": t.I}})
+}
+
+// JavaScript is BSON JavaScript code
+type JavaScript string
+
+func (j JavaScript) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$code": string(j)})
+}
+
+// MinKey and MaxKey compare below and above every other BSON value
+type (
+	MinKey struct{}
+	MaxKey struct{}
+)
+
+func (MinKey) MarshalJSON() ([]byte, error) { return []byte(`{"$minKey":1}`), nil }
+func (MaxKey) MarshalJSON() ([]byte, error) { return []byte(`{"$maxKey":1}`), nil }
+
+// Undefined is the deprecated BSON undefined value
+type Undefined struct{}
+
+func (Undefined) MarshalJSON() ([]byte, error) { return []byte(`{"$undefined":true}`), nil }
+
+// nonFinite is a NaN or infinite double, which plain JSON cannot hold
+type nonFinite float64
+
+func (f nonFinite) MarshalJSON() ([]byte, error) {
+	value := "NaN"
+	switch {
+	case math.IsInf(float64(f), 1):
+		value = "Infinity"
+	case math.IsInf(float64(f), -1):
+		value = "-Infinity"
+	}
+	return json.Marshal(map[string]string{"$numberDouble": value})
+}
+
+// Decimal128 is an IEEE 754-2008 128-bit decimal
+type Decimal128 struct {
+	High uint64
+	Low  uint64
+}
+
+const decimalExponentBias = 6176
+
+func (d Decimal128) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$numberDecimal": d.String()})
+}
+
+// String formats the decimal in scientific notation when its exponent is positive or it is very small,
+// like the MongoDB shell
+func (d Decimal128) String() string {
+	sign := ""
+	if d.High>>63 == 1 {
+		sign = "-"
+	}
+	combination := d.High >> 58 & 0x1F
+	var exponent int
+	coefficient := new(big.Int)
+	switch {
+	case combination == 0x1F:
+		return "NaN"
+	case combination == 0x1E:
+		return sign + "Infinity"
+	case d.High>>61&0x3 == 0x3:
+		// The coefficient would exceed 34 digits: non-canonical, so zero
+		exponent = int(d.High>>47&0x3FFF) - decimalExponentBias
+	default:
+		exponent = int(d.High>>49&0x3FFF) - decimalExponentBias
+		coefficient.SetUint64(d.High & (1<<49 - 1))
+		coefficient.Lsh(coefficient, 64)
+		coefficient.Or(coefficient, new(big.Int).SetUint64(d.Low))
+	}
+
+	digits := coefficient.String()
+	adjusted := exponent + len(digits) - 1
+	if exponent > 0 || adjusted < -6 {
+		s := digits[:1]
+		if len(digits) > 1 {
+			s += "." + digits[1:]
+		}
+		return fmt.Sprintf("%s%sE%+d", sign, s, adjusted)
+	}
+	if exponent == 0 {
+		return sign + digits
+	}
+	if pad := -exponent - len(digits) + 1; pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) + exponent
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// parseDecimal128 parses a decimal number such as -12.50 or 1.5E-3
+func parseDecimal128(s string) (Decimal128, error) {
+	text := strings.TrimSpace(s)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimLeft(text, "+-")
+	exponent := 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		e, err := strconv.Atoi(text[i+1:])
+		if err != nil {
+			return Decimal128{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		exponent, text = e, text[:i]
+	}
+	if whole, fraction, ok := strings.Cut(text, "."); ok {
+		text = whole + fraction
+		exponent -= len(fraction)
+	}
+	if text == "" || strings.Trim(text, "0123456789") != "" {
+		return Decimal128{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	text = strings.TrimLeft(text, "0")
+	if text == "" {
+		text = "0"
+	}
+	if len(text) > 34 {
+		return Decimal128{}, fmt.Errorf("decimal %q has more than 34 significant digits", s)
+	}
+	biased := exponent + decimalExponentBias
+	if biased < 0 || biased > 0x2FFF {
+		return Decimal128{}, fmt.Errorf("decimal %q is out of range", s)
+	}
+
+	coefficient, _ := new(big.Int).SetString(text, 10)
+	low := new(big.Int).And(coefficient, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+	high := new(big.Int).Rsh(coefficient, 64).Uint64() | uint64(biased)<<49
+	if negative {
+		high |= 1 << 63
+	}
+	return Decimal128{High: high, Low: low}, nil
+}
+
+// marshalDocument encodes a document as BSON
+func marshalDocument(doc Document) ([]byte, error) {
+	return appendDocument(nil, doc, 0)
+}
+
+func appendDocument(b []byte, doc Document, depth int) ([]byte, error) {
+	if depth > maxNesting {
+		return nil, fmt.Errorf("document nested more than %d levels deep", maxNesting)
+	}
+	start := len(b)
+	b = append(b, 0, 0, 0, 0)
+	for _, e := range doc {
+		var err error
+		if b, err = appendElement(b, e.Key, e.Value, depth); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, 0)
+	binary.LittleEndian.PutUint32(b[start:], uint32(len(b)-start))
+	return b, nil
+}
+
+func appendElement(b []byte, key string, value interface{}, depth int) ([]byte, error) {
+	if strings.IndexByte(key, 0) >= 0 {
+		return nil, fmt.Errorf("key %q contains a NUL byte", key)
+	}
+	header := func(t byte) []byte {
+		b = append(b, t)
+		b = append(b, key...)
+		return append(b, 0)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return header(typeNull), nil
+	case bool:
+		b = header(typeBool)
+		if v {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case int:
+		if v >= math.MinInt32 && v <= math.MaxInt32 {
+			return binary.LittleEndian.AppendUint32(header(typeInt32), uint32(int32(v))), nil
+		}
+		return binary.LittleEndian.AppendUint64(header(typeInt64), uint64(v)), nil
+	case int32:
+		return binary.LittleEndian.AppendUint32(header(typeInt32), uint32(v)), nil
+	case int64:
+		return binary.LittleEndian.AppendUint64(header(typeInt64), uint64(v)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(header(typeDouble), math.Float64bits(v)), nil
+	case nonFinite:
+		return binary.LittleEndian.AppendUint64(header(typeDouble), math.Float64bits(float64(v))), nil
+	case string:
+		return appendString(header(typeString), v), nil
+	case Document:
+		return appendDocument(header(typeDocument), v, depth+1)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		doc := make(Document, 0, len(v))
+		for _, k := range keys {
+			doc = append(doc, Element{k, v[k]})
+		}
+		return appendDocument(header(typeDocument), doc, depth+1)
+	case []interface{}:
+		doc := make(Document, len(v))
+		for i, item := range v {
+			doc[i] = Element{strconv.Itoa(i), item}
+		}
+		return appendDocument(header(typeArray), doc, depth+1)
+	case []string:
+		doc := make(Document, len(v))
+		for i, item := range v {
+			doc[i] = Element{strconv.Itoa(i), item}
+		}
+		return appendDocument(header(typeArray), doc, depth+1)
+	case ObjectID:
+		return append(header(typeObjectID), v[:]...), nil
+	case DateTime:
+		return binary.LittleEndian.AppendUint64(header(typeDateTime), uint64(v)), nil
+	case time.Time:
+		return binary.LittleEndian.AppendUint64(header(typeDateTime), uint64(v.UnixMilli())), nil
+	case Binary:
+		b = binary.LittleEndian.AppendUint32(header(typeBinary), uint32(len(v.Data)))
+		b = append(b, v.Subtype)
+		return append(b, v.Data...), nil
+	case []byte:
+		b = binary.LittleEndian.AppendUint32(header(typeBinary), uint32(len(v)))
+		b = append(b, 0)
+		return append(b, v...), nil
+	case Regex:
+		b = append(header(typeRegex), v.Pattern...)
+		b = append(b, 0)
+		b = append(b, v.Options...)
+		return append(b, 0), nil
+	case JavaScript:
+		return appendString(header(typeJavaScript), string(v)), nil
+	case Timestamp:
+		b = binary.LittleEndian.AppendUint32(header(typeTimestamp), v.I)
+		return binary.LittleEndian.AppendUint32(b, v.T), nil
+	case Decimal128:
+		b = binary.LittleEndian.AppendUint64(header(typeDecimal128), v.Low)
+		return binary.LittleEndian.AppendUint64(b, v.High), nil
+	case MinKey:
+		return header(typeMinKey), nil
+	case MaxKey:
+		return header(typeMaxKey), nil
+	case Undefined:
+		return header(typeUndefined), nil
+	}
+	return nil, fmt.Errorf("cannot encode %T as BSON", value)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)+1))
+	b = append(b, s...)
+	return append(b, 0)
+}
+
+// unmarshalDocument decodes a BSON document
+func unmarshalDocument(data []byte) (Document, error) {
+	r := &bsonReader{data: data}
+	doc, err := r.document(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BSON: %w", err)
+	}
+	return doc, nil
+}
+
+// bsonReader decodes BSON; values decode to Document, []interface{}, string, int32, int64, float64, bool,
+// nil and the types of this file
+type bsonReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bsonReader) take(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("truncated data")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *bsonReader) uint32() (uint32, error) {
+	b, err := r.take(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r *bsonReader) uint64() (uint64, error) {
+	b, err := r.take(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (r *bsonReader) cstring() (string, error) {
+	end := bytes.IndexByte(r.data[r.pos:], 0)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string")
+	}
+	s := string(r.data[r.pos : r.pos+end])
+	r.pos += end + 1
+	return s, nil
+}
+
+func (r *bsonReader) string() (string, error) {
+	size, err := r.uint32()
+	if err != nil {
+		return "", err
+	}
+	if size == 0 {
+		return "", fmt.Errorf("invalid string length")
+	}
+	b, err := r.take(int(size))
+	if err != nil {
+		return "", err
+	}
+	return string(b[:len(b)-1]), nil
+}
+
+func (r *bsonReader) document(depth int) (Document, error) {
+	if depth > maxNesting {
+		return nil, fmt.Errorf("document nested more than %d levels deep", maxNesting)
+	}
+	start := r.pos
+	size, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	end := start + int(size)
+	if size < 5 || end > len(r.data) {
+		return nil, fmt.Errorf("invalid document length %d", size)
+	}
+	doc := Document{}
+	for r.pos < end-1 {
+		t, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		key, err := r.cstring()
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.value(t[0], depth)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		doc = append(doc, Element{key, value})
+	}
+	if r.pos != end-1 || r.data[r.pos] != 0 {
+		return nil, fmt.Errorf("document overruns its length")
+	}
+	r.pos = end
+	return doc, nil
+}
+
+func (r *bsonReader) value(t byte, depth int) (interface{}, error) {
+	switch t {
+	case typeDouble:
+		bits, err := r.uint64()
+		f := math.Float64frombits(bits)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nonFinite(f), err
+		}
+		return f, err
+	case typeString, typeSymbol:
+		return r.string()
+	case typeJavaScript:
+		s, err := r.string()
+		return JavaScript(s), err
+	case typeDocument:
+		return r.document(depth + 1)
+	case typeArray:
+		doc, err := r.document(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]interface{}, len(doc))
+		for i, e := range doc {
+			items[i] = e.Value
+		}
+		return items, nil
+	case typeBinary:
+		size, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		subtype, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.take(int(size))
+		if err != nil {
+			return nil, err
+		}
+		return Binary{Subtype: subtype[0], Data: append([]byte(nil), data...)}, nil
+	case typeUndefined:
+		return Undefined{}, nil
+	case typeObjectID:
+		b, err := r.take(12)
+		if err != nil {
+			return nil, err
+		}
+		return ObjectID(b), nil
+	case typeBool:
+		b, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] == 1, nil
+	case typeDateTime:
+		v, err := r.uint64()
+		return DateTime(int64(v)), err
+	case typeNull:
+		return nil, nil
+	case typeRegex:
+		pattern, err := r.cstring()
+		if err != nil {
+			return nil, err
+		}
+		options, err := r.cstring()
+		return Regex{Pattern: pattern, Options: options}, err
+	case typeDBPointer:
+		namespace, err := r.string()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.take(12)
+		if err != nil {
+			return nil, err
+		}
+		return Document{{"$ref", namespace}, {"$id", ObjectID(b)}}, nil
+	case typeCodeScope:
+		if _, err := r.uint32(); err != nil {
+			return nil, err
+		}
+		code, err := r.string()
+		if err != nil {
+			return nil, err
+		}
+		scope, err := r.document(depth + 1)
+		return Document{{"$code", code}, {"$scope", scope}}, err
+	case typeInt32:
+		v, err := r.uint32()
+		return int32(v), err
+	case typeTimestamp:
+		i, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		t, err := r.uint32()
+		return Timestamp{T: t, I: i}, err
+	case typeInt64:
+		v, err := r.uint64()
+		return int64(v), err
+	case typeDecimal128:
+		low, err := r.uint64()
+		if err != nil {
+			return nil, err
+		}
+		high, err := r.uint64()
+		return Decimal128{High: high, Low: low}, err
+	case typeMinKey:
+		return MinKey{}, nil
+	case typeMaxKey:
+		return MaxKey{}, nil
+	}
+	return nil, fmt.Errorf("unknown BSON type 0x%02x", t)
+}
@@ -0,0 +1,345 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	opMsg = 2013
+	// maxMessageBytes is the largest message a server sends (maxMessageSizeBytes)
+	maxMessageBytes = 48_000_000
+	// flagChecksumPresent marks an OP_MSG ending with a CRC-32C checksum
+	flagChecksumPresent = 1
+	defaultPort         = "27017"
+)
+
+// codeCommandNotFound is returned by servers older than 4.4.2 for hello
+const codeCommandNotFound = 59
+
+// CommandError is an error returned by the server for a command
+type CommandError struct {
+	Code     int32
+	CodeName string
+	Message  string
+}
+
+func (e *CommandError) Error() string {
+	if e.CodeName != "" {
+		return fmt.Sprintf("%s (%s, code %d)", e.Message, e.CodeName, e.Code)
+	}
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// connString is the part of a mongodb:// or mongodb+srv:// connection string the client uses
+type connString struct {
+	hosts         []string
+	database      string
+	username      string
+	password      string
+	authSource    string
+	authMechanism string
+	tls           bool
+	tlsInsecure   bool
+	tlsCAFile     string
+}
+
+// parseConnString parses a connection string, resolving the hosts of a mongodb+srv:// string through DNS
+func parseConnString(ctx context.Context, uri string) (*connString, error) {
+	srv := strings.HasPrefix(uri, "mongodb+srv://")
+	if !srv && !strings.HasPrefix(uri, "mongodb://") {
+		return nil, fmt.Errorf("uri must start with mongodb:// or mongodb+srv://")
+	}
+	rest := uri[strings.Index(uri, "://")+3:]
+	cs := &connString{tls: srv}
+
+	if at := strings.LastIndex(strings.SplitN(rest, "/", 2)[0], "@"); at >= 0 {
+		userinfo := rest[:at]
+		rest = rest[at+1:]
+		user, password, _ := strings.Cut(userinfo, ":")
+		var err error
+		if cs.username, err = url.PathUnescape(user); err != nil {
+			return nil, fmt.Errorf("invalid username in uri: %w", err)
+		}
+		if cs.password, err = url.PathUnescape(password); err != nil {
+			return nil, fmt.Errorf("invalid password in uri: %w", err)
+		}
+	}
+
+	hostPart, pathPart, _ := strings.Cut(rest, "/")
+	hostPart, query, _ := strings.Cut(hostPart, "?")
+	database, pathQuery, _ := strings.Cut(pathPart, "?")
+	if query == "" {
+		query = pathQuery
+	}
+	cs.database, _ = url.PathUnescape(database)
+
+	if srv {
+		if strings.Contains(hostPart, ",") || strings.Contains(hostPart, ":") {
+			return nil, fmt.Errorf("a mongodb+srv:// uri takes one host name without a port")
+		}
+		_, records, err := net.DefaultResolver.LookupSRV(ctx, "mongodb", "tcp", hostPart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", hostPart, err)
+		}
+		for _, record := range records {
+			cs.hosts = append(cs.hosts, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+		}
+		// The TXT record may set authSource and replicaSet
+		if txts, err := net.DefaultResolver.LookupTXT(ctx, hostPart); err == nil && len(txts) > 0 {
+			if err := cs.applyOptions(strings.Join(txts, "")); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, host := range strings.Split(hostPart, ",") {
+			if host == "" {
+				continue
+			}
+			if _, _, err := net.SplitHostPort(host); err != nil {
+				host = net.JoinHostPort(strings.Trim(host, "[]"), defaultPort)
+			}
+			cs.hosts = append(cs.hosts, host)
+		}
+	}
+	if len(cs.hosts) == 0 {
+		return nil, fmt.Errorf("uri has no hosts")
+	}
+	if err := cs.applyOptions(query); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+func (cs *connString) applyOptions(query string) error {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return fmt.Errorf("invalid uri options: %w", err)
+	}
+	for key, vals := range values {
+		value := vals[len(vals)-1]
+		switch strings.ToLower(key) {
+		case "authsource":
+			cs.authSource = value
+		case "authmechanism":
+			cs.authMechanism = strings.ToUpper(value)
+		case "tls", "ssl":
+			cs.tls = value == "true"
+		case "tlsinsecure", "tlsallowinvalidcertificates":
+			cs.tlsInsecure = value == "true"
+		case "tlscafile":
+			cs.tlsCAFile = value
+		}
+	}
+	return nil
+}
+
+// conn is a connection to one server
+type conn struct {
+	addr      string
+	netConn   net.Conn
+	timeout   time.Duration
+	requestID int32
+	hello     Document // Reply to the handshake
+}
+
+// dial connects to a server and runs the handshake; saslMechs asks for the authentication mechanisms of a
+// user, as authSource.username
+func (c *MongoClient) dial(ctx context.Context, addr, saslMechs string) (*conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if c.tlsConfig != nil {
+		cfg := c.tlsConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName, _, _ = net.SplitHostPort(addr)
+		}
+		tlsConn := tls.Client(netConn, cfg)
+		handshakeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		err := tlsConn.HandshakeContext(handshakeCtx)
+		cancel()
+		if err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("TLS handshake with %s failed: %w", addr, err)
+		}
+		netConn = tlsConn
+	}
+
+	cn := &conn{addr: addr, netConn: netConn, timeout: c.timeout}
+	hello := Document{
+		{"hello", int32(1)},
+		{"client", Document{
+			{"application", Document{{"name", c.appName}}},
+			{"driver", Document{{"name", "dev-mcp"}, {"version", "1"}}},
+			{"os", Document{{"type", runtime.GOOS}}},
+		}},
+	}
+	if saslMechs != "" {
+		hello = append(hello, Element{"saslSupportedMechs", saslMechs})
+	}
+	reply, err := cn.command(ctx, "admin", hello)
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == codeCommandNotFound {
+		hello[0] = Element{"isMaster", int32(1)}
+		reply, err = cn.command(ctx, "admin", hello)
+	}
+	if err != nil {
+		cn.close()
+		return nil, fmt.Errorf("handshake with %s failed: %w", addr, err)
+	}
+	cn.hello = reply
+	return cn, nil
+}
+
+// writable reports whether the server is a primary, a standalone server or a mongos
+func (cn *conn) writable() bool {
+	for _, key := range []string{"isWritablePrimary", "ismaster"} {
+		if v, ok := cn.hello.Lookup(key); ok {
+			return v == true
+		}
+	}
+	return false
+}
+
+// command runs a command on a database and returns its reply, or a *CommandError when it failed
+func (cn *conn) command(ctx context.Context, db string, cmd Document) (Document, error) {
+	cmd = append(cmd[:len(cmd):len(cmd)], Element{"$db", db})
+	body, err := marshalDocument(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(cn.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := cn.netConn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	cn.requestID++
+	msg := make([]byte, 0, 21+len(body))
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(21+len(body)))
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(cn.requestID))
+	msg = binary.LittleEndian.AppendUint32(msg, 0)
+	msg = binary.LittleEndian.AppendUint32(msg, opMsg)
+	msg = binary.LittleEndian.AppendUint32(msg, 0) // Flags
+	msg = append(msg, 0)                           // Section kind 0: the command
+	msg = append(msg, body...)
+	if _, err := cn.netConn.Write(msg); err != nil {
+		return nil, fmt.Errorf("%s: failed to send %s: %w", cn.addr, cmd[0].Key, err)
+	}
+
+	reply, err := cn.readReply()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read %s reply: %w", cn.addr, cmd[0].Key, err)
+	}
+	if ok, _ := reply.Lookup("ok"); !isTrue(ok) {
+		cmdErr := &CommandError{Message: "command failed"}
+		if v, ok := reply.Lookup("errmsg"); ok {
+			cmdErr.Message, _ = v.(string)
+		}
+		if v, ok := reply.Lookup("code"); ok {
+			n, _ := toInt64(v)
+			cmdErr.Code = int32(n)
+		}
+		if v, ok := reply.Lookup("codeName"); ok {
+			cmdErr.CodeName, _ = v.(string)
+		}
+		return nil, cmdErr
+	}
+	return reply, nil
+}
+
+// readReply reads an OP_MSG reply and returns its body section
+func (cn *conn) readReply() (Document, error) {
+	var header [16]byte
+	if _, err := io.ReadFull(cn.netConn, header[:]); err != nil {
+		return nil, err
+	}
+	size := int(binary.LittleEndian.Uint32(header[0:]))
+	if size < 21 || size > maxMessageBytes {
+		return nil, fmt.Errorf("invalid message size %d", size)
+	}
+	if responseTo := int32(binary.LittleEndian.Uint32(header[8:])); responseTo != cn.requestID {
+		return nil, fmt.Errorf("reply to request %d does not match request %d", responseTo, cn.requestID)
+	}
+	if opCode := binary.LittleEndian.Uint32(header[12:]); opCode != opMsg {
+		return nil, fmt.Errorf("unexpected reply opcode %d", opCode)
+	}
+	payload := make([]byte, size-16)
+	if _, err := io.ReadFull(cn.netConn, payload); err != nil {
+		return nil, err
+	}
+
+	flags := binary.LittleEndian.Uint32(payload)
+	payload = payload[4:]
+	if flags&flagChecksumPresent != 0 {
+		payload = payload[:len(payload)-4]
+	}
+	for len(payload) > 0 {
+		kind := payload[0]
+		payload = payload[1:]
+		if len(payload) < 4 {
+			return nil, fmt.Errorf("truncated reply section")
+		}
+		sectionSize := int(binary.LittleEndian.Uint32(payload))
+		if sectionSize > len(payload) {
+			return nil, fmt.Errorf("truncated reply section")
+		}
+		if kind == 0 {
+			return unmarshalDocument(payload[:sectionSize])
+		}
+		payload = payload[sectionSize:] // Document sequences are not used in replies
+	}
+	return nil, fmt.Errorf("reply has no body")
+}
+
+func (cn *conn) close() {
+	cn.netConn.Close()
+}
+
+// isTrue reports whether a reply's ok field or a flag is set, whatever its numeric type
+func isTrue(v interface{}) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	n, ok := toFloat(v)
+	return ok && n != 0
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,256 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseExtendedJSON parses a filter, projection, sort or pipeline written as MongoDB extended JSON. Objects
+// keep their key order, and the wrappers $oid, $date, $numberLong, $numberInt, $numberDouble, $numberDecimal,
+// $binary, $uuid, $regularExpression, $timestamp, $minKey and $maxKey become their BSON types.
+func parseExtendedJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := parseJSONValue(decoder, 0)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+// parseDocumentArg parses an extended JSON object argument; empty input is an empty document
+func parseDocumentArg(name string, data json.RawMessage) (Document, error) {
+	if len(bytes.TrimSpace(data)) == 0 || string(bytes.TrimSpace(data)) == "null" {
+		return Document{}, nil
+	}
+	value, err := parseExtendedJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	doc, ok := value.(Document)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s: must be an object", name)
+	}
+	return doc, nil
+}
+
+func parseJSONValue(decoder *json.Decoder, depth int) (interface{}, error) {
+	if depth > maxNesting {
+		return nil, fmt.Errorf("JSON nested more than %d levels deep", maxNesting)
+	}
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			doc := Document{}
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := parseJSONValue(decoder, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				doc = append(doc, Element{keyToken.(string), value})
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, err
+			}
+			return fromExtendedJSON(doc)
+		case '[':
+			items := []interface{}{}
+			for decoder.More() {
+				value, err := parseJSONValue(decoder, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, value)
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, err
+			}
+			return items, nil
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	case json.Number:
+		return parseNumber(t.String())
+	default:
+		return t, nil // string, bool or nil
+	}
+}
+
+// parseNumber keeps integers as int32 or int64 like the MongoDB shell, and other numbers as doubles
+func parseNumber(s string) (interface{}, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			if n >= math.MinInt32 && n <= math.MaxInt32 {
+				return int32(n), nil
+			}
+			return n, nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s", s)
+	}
+	return f, nil
+}
+
+// fromExtendedJSON turns an extended JSON wrapper object into its BSON value; other objects are returned as is
+func fromExtendedJSON(doc Document) (interface{}, error) {
+	if len(doc) == 0 || !strings.HasPrefix(doc[0].Key, "$") {
+		return doc, nil
+	}
+	key, value := doc[0].Key, doc[0].Value
+	if len(doc) == 2 && key == "$regex" {
+		// {"$regex": ..., "$options": ...} is a query operator, not a wrapper
+		return doc, nil
+	}
+	if len(doc) != 1 {
+		return doc, nil
+	}
+	text, isString := value.(string)
+	wrapperError := func(want string) error {
+		return fmt.Errorf("%s must be %s", key, want)
+	}
+
+	switch key {
+	case "$oid":
+		b, err := hex.DecodeString(text)
+		if !isString || err != nil || len(b) != 12 {
+			return nil, wrapperError("a string of 24 hex digits")
+		}
+		return ObjectID(b), nil
+	case "$date":
+		switch v := value.(type) {
+		case string:
+			at, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, wrapperError("an RFC 3339 time such as 2024-05-01T00:00:00Z")
+			}
+			return DateTime(at.UnixMilli()), nil
+		case int32:
+			return DateTime(v), nil
+		case int64:
+			return DateTime(v), nil
+		case Document:
+			if ms, ok := v.Lookup("$numberLong"); ok {
+				if s, ok := ms.(string); ok {
+					if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+						return DateTime(n), nil
+					}
+				}
+			}
+		}
+		return nil, wrapperError("an RFC 3339 time or milliseconds since the epoch")
+	case "$numberLong":
+		n, err := strconv.ParseInt(text, 10, 64)
+		if !isString || err != nil {
+			return nil, wrapperError("a string holding a 64-bit integer")
+		}
+		return n, nil
+	case "$numberInt":
+		n, err := strconv.ParseInt(text, 10, 32)
+		if !isString || err != nil {
+			return nil, wrapperError("a string holding a 32-bit integer")
+		}
+		return int32(n), nil
+	case "$numberDouble":
+		if !isString {
+			return nil, wrapperError("a string")
+		}
+		switch text {
+		case "NaN", "Infinity", "-Infinity":
+			f, _ := strconv.ParseFloat(text, 64)
+			return nonFinite(f), nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, wrapperError("a string holding a number")
+		}
+		return f, nil
+	case "$numberDecimal":
+		if !isString {
+			return nil, wrapperError("a string")
+		}
+		return parseDecimal128(text)
+	case "$binary":
+		fields, ok := value.(Document)
+		if !ok {
+			return nil, wrapperError(`{"base64": ..., "subType": ...}`)
+		}
+		data64, _ := fields.Lookup("base64")
+		subType, _ := fields.Lookup("subType")
+		s64, _ := data64.(string)
+		data, err := base64.StdEncoding.DecodeString(s64)
+		if err != nil {
+			return nil, fmt.Errorf("$binary.base64: %w", err)
+		}
+		sub, _ := subType.(string)
+		subtype, err := strconv.ParseUint(sub, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("$binary.subType must be a hex byte such as \"00\"")
+		}
+		return Binary{Subtype: byte(subtype), Data: data}, nil
+	case "$uuid":
+		b, err := hex.DecodeString(strings.ReplaceAll(text, "-", ""))
+		if !isString || err != nil || len(b) != 16 {
+			return nil, wrapperError("a UUID string")
+		}
+		return Binary{Subtype: 4, Data: b}, nil
+	case "$regularExpression":
+		fields, ok := value.(Document)
+		if !ok {
+			return nil, wrapperError(`{"pattern": ..., "options": ...}`)
+		}
+		pattern, _ := fields.Lookup("pattern")
+		options, _ := fields.Lookup("options")
+		p, _ := pattern.(string)
+		o, _ := options.(string)
+		return Regex{Pattern: p, Options: o}, nil
+	case "$timestamp":
+		fields, ok := value.(Document)
+		if !ok {
+			return nil, wrapperError(`{"t": ..., "i": ...}`)
+		}
+		t, _ := fields.Lookup("t")
+		i, _ := fields.Lookup("i")
+		tv, tok := toUint32(t)
+		iv, iok := toUint32(i)
+		if !tok || !iok {
+			return nil, wrapperError(`{"t": ..., "i": ...} with unsigned 32-bit integers`)
+		}
+		return Timestamp{T: tv, I: iv}, nil
+	case "$minKey":
+		return MinKey{}, nil
+	case "$maxKey":
+		return MaxKey{}, nil
+	}
+	return doc, nil
+}
+
+func toUint32(v interface{}) (uint32, bool) {
+	switch n := v.(type) {
+	case int32:
+		return uint32(n), n >= 0
+	case int64:
+		return uint32(n), n >= 0 && n <= math.MaxUint32
+	}
+	return 0, false
+}
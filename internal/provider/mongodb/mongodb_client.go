@@ -0,0 +1,639 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultTimeout        = 30 * time.Second
+	defaultMaxDocuments   = 100
+	defaultMaxResultBytes = 256 << 10
+	// maxCollections bounds the collections listed by mongo_list_collections
+	maxCollections = 1000
+)
+
+// readPreference lets reads run on a secondary when the client is connected to one, or through a mongos
+// when the primary is down
+var readPreference = Document{{"mode", "primaryPreferred"}}
+
+// MongoClient runs read commands against a MongoDB deployment. Every tool call opens its own connection,
+// preferring the primary of a replica set and falling back to a secondary, and closes it afterwards.
+type MongoClient struct {
+	hosts          []string
+	database       string
+	credential     *credential
+	tlsConfig      *tls.Config
+	timeout        time.Duration
+	appName        string
+	maxDocuments   int
+	maxResultBytes int
+	validator      *SecurityValidator
+	initErr        error
+}
+
+// FindOptions are the arguments of a find command
+type FindOptions struct {
+	Filter     Document
+	Projection Document
+	Sort       Document
+	Skip       int64
+	Limit      int
+}
+
+// QueryResult holds the documents returned by a find or an aggregation
+type QueryResult struct {
+	Database   string            `json:"database"`
+	Collection string            `json:"collection,omitempty"`
+	Documents  []json.RawMessage `json:"documents"`
+	Count      int               `json:"count"`
+	HasMore    bool              `json:"has_more"`            // More documents matched than were returned
+	Truncated  string            `json:"truncated,omitempty"` // Why documents were left out
+	Wrote      bool              `json:"wrote,omitempty"`     // The pipeline ended with $out or $merge
+}
+
+// CollectionInfo describes a collection or view
+type CollectionInfo struct {
+	Name      string      `json:"name"`
+	Type      string      `json:"type"` // collection, view or timeseries
+	ViewOn    string      `json:"view_on,omitempty"`
+	Capped    bool        `json:"capped,omitempty"`
+	Validator bool        `json:"has_validator,omitempty"`
+	ReadOnly  bool        `json:"read_only,omitempty"`
+	Documents *int64      `json:"documents,omitempty"` // Estimated from the collection metadata
+	Options   interface{} `json:"options,omitempty"`
+}
+
+// NewMongoClient creates a new MongoDB client from config; without a uri it is not available
+func NewMongoClient(cfg *config.MongoDBConfig) *MongoClient {
+	c := &MongoClient{
+		timeout:        defaultTimeout,
+		appName:        "dev-mcp",
+		maxDocuments:   defaultMaxDocuments,
+		maxResultBytes: defaultMaxResultBytes,
+		validator:      NewSecurityValidator(false),
+	}
+	if cfg == nil || cfg.URI == "" {
+		return c
+	}
+	if cfg.TimeoutSeconds > 0 {
+		c.timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	if cfg.MaxDocuments > 0 {
+		c.maxDocuments = cfg.MaxDocuments
+	}
+	if cfg.MaxResultBytes > 0 {
+		c.maxResultBytes = cfg.MaxResultBytes
+	}
+	c.validator = NewSecurityValidator(cfg.UnsafeMode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	cs, err := parseConnString(ctx, cfg.URI)
+	if err != nil {
+		c.initErr = err
+		return c
+	}
+	c.hosts = cs.hosts
+	c.database = cs.database
+	if cfg.Database != "" {
+		c.database = cfg.Database
+	}
+
+	username, password, source, mechanism := cs.username, cs.password, cs.authSource, cs.authMechanism
+	if cfg.Username != "" {
+		username, password = cfg.Username, cfg.Password
+	}
+	if cfg.AuthSource != "" {
+		source = cfg.AuthSource
+	}
+	if source == "" && cs.database != "" {
+		source = cs.database
+	}
+	if cfg.AuthMechanism != "" {
+		mechanism = cfg.AuthMechanism
+	}
+	if c.credential, err = newCredential(mechanism, source, username, password); err != nil {
+		c.initErr = err
+		return c
+	}
+
+	caFile := cs.tlsCAFile
+	if cfg.CAFile != "" {
+		caFile = cfg.CAFile
+	}
+	if cs.tls || cfg.TLS || caFile != "" {
+		c.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: cfg.TLSSkipVerify || cs.tlsInsecure}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				c.initErr = fmt.Errorf("failed to read CA file: %w", err)
+				return c
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				c.initErr = fmt.Errorf("no certificates found in CA file %s", caFile)
+				return c
+			}
+			c.tlsConfig.RootCAs = pool
+		}
+	}
+	return c
+}
+
+// IsAvailable returns whether a uri is configured and the settings are valid
+func (c *MongoClient) IsAvailable() bool {
+	return len(c.hosts) > 0 && c.initErr == nil
+}
+
+// InitError returns the configuration error, if any
+func (c *MongoClient) InitError() error {
+	return c.initErr
+}
+
+// Hosts returns the seed hosts of the deployment
+func (c *MongoClient) Hosts() []string {
+	return c.hosts
+}
+
+// Validator returns the validator of filters and pipelines
+func (c *MongoClient) Validator() *SecurityValidator {
+	return c.validator
+}
+
+// MaxDocuments returns the cap on the documents a tool returns
+func (c *MongoClient) MaxDocuments() int {
+	return c.maxDocuments
+}
+
+// Close releases resources held by the client
+func (c *MongoClient) Close() error {
+	return nil
+}
+
+// databaseName returns the database a tool targets
+func (c *MongoClient) databaseName(name string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if c.database != "" {
+		return c.database, nil
+	}
+	return "", fmt.Errorf("database is required: the uri names no default database and mongodb.database is not set")
+}
+
+// connect opens an authenticated connection, to the primary when one answers and to a secondary otherwise
+func (c *MongoClient) connect(ctx context.Context) (*conn, error) {
+	var fallback *conn
+	var errs []string
+	tried := map[string]bool{}
+	for _, addr := range c.hosts {
+		if fallback != nil {
+			break
+		}
+		if tried[addr] {
+			continue
+		}
+		tried[addr] = true
+		cn, err := c.dial(ctx, addr, c.credential.supportedMechs())
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if cn.writable() {
+			return c.authenticate(ctx, cn)
+		}
+		// A secondary names the primary, which is tried next
+		fallback = cn
+		if primary, ok := cn.hello.Lookup("primary"); ok {
+			if addr, ok := primary.(string); ok && !tried[addr] {
+				if primaryConn, err := c.dial(ctx, addr, c.credential.supportedMechs()); err == nil {
+					fallback.close()
+					return c.authenticate(ctx, primaryConn)
+				}
+			}
+		}
+	}
+	if fallback != nil {
+		return c.authenticate(ctx, fallback)
+	}
+	return nil, fmt.Errorf("no MongoDB server reachable: %s", strings.Join(errs, "; "))
+}
+
+func (c *MongoClient) authenticate(ctx context.Context, cn *conn) (*conn, error) {
+	if c.credential == nil {
+		return cn, nil
+	}
+	if err := c.credential.authenticate(ctx, cn); err != nil {
+		cn.close()
+		return nil, fmt.Errorf("authentication as %s failed: %w", c.credential.username, err)
+	}
+	return cn, nil
+}
+
+// withConn runs fn on a new connection and closes it afterwards
+func (c *MongoClient) withConn(ctx context.Context, fn func(cn *conn) error) error {
+	cn, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cn.close()
+	return fn(cn)
+}
+
+// Ping checks that a server answers and accepts the credentials
+func (c *MongoClient) Ping(ctx context.Context) error {
+	return c.withConn(ctx, func(cn *conn) error {
+		_, err := cn.command(ctx, "admin", Document{{"ping", int32(1)}})
+		return err
+	})
+}
+
+// maxTimeMS bounds a command on the server by the context's deadline or the client timeout
+func (c *MongoClient) maxTimeMS(ctx context.Context) int64 {
+	timeout := c.timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline))
+	}
+	return max(timeout.Milliseconds(), 1)
+}
+
+// Find runs a find command and returns up to opts.Limit documents
+func (c *MongoClient) Find(ctx context.Context, database, collection string, opts FindOptions) (*QueryResult, error) {
+	db, err := c.databaseName(database)
+	if err != nil {
+		return nil, err
+	}
+	for name, doc := range map[string]Document{"filter": opts.Filter, "projection": opts.Projection, "sort": opts.Sort} {
+		if err := c.validator.ValidateFilter(name, doc); err != nil {
+			return nil, err
+		}
+	}
+	limit := c.limit(opts.Limit)
+
+	cmd := Document{{"find", collection}, {"filter", opts.Filter}}
+	if len(opts.Projection) > 0 {
+		cmd = append(cmd, Element{"projection", opts.Projection})
+	}
+	if len(opts.Sort) > 0 {
+		cmd = append(cmd, Element{"sort", opts.Sort})
+	}
+	if opts.Skip > 0 {
+		cmd = append(cmd, Element{"skip", opts.Skip})
+	}
+	// One document more than asked tells whether there are more
+	cmd = append(cmd,
+		Element{"limit", int64(limit + 1)},
+		Element{"batchSize", int32(limit + 1)},
+		Element{"maxTimeMS", c.maxTimeMS(ctx)},
+		Element{"$readPreference", readPreference},
+	)
+
+	result := &QueryResult{Database: db, Collection: collection}
+	err = c.withConn(ctx, func(cn *conn) error {
+		reply, err := cn.command(ctx, db, cmd)
+		if err != nil {
+			return err
+		}
+		docs, more, err := c.readCursor(ctx, cn, db, reply, limit+1)
+		if err != nil {
+			return err
+		}
+		c.fillResult(result, docs, limit, more)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Aggregate runs a validated pipeline on a collection, or on the database when collection is empty (for
+// stages such as $documents), and returns up to limit documents
+func (c *MongoClient) Aggregate(ctx context.Context, database, collection string, pipeline []interface{}, limit int) (*QueryResult, error) {
+	db, err := c.databaseName(database)
+	if err != nil {
+		return nil, err
+	}
+	writes, err := c.validator.ValidatePipeline(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	limit = c.limit(limit)
+
+	// A trailing $limit keeps the server from computing documents that are not returned; it cannot follow
+	// $out or $merge
+	stages := append([]interface{}(nil), pipeline...)
+	if !writes {
+		stages = append(stages, Document{{"$limit", int64(limit + 1)}})
+	}
+	var target interface{} = collection
+	if collection == "" {
+		target = int32(1)
+	}
+	cmd := Document{
+		{"aggregate", target},
+		{"pipeline", stages},
+		{"cursor", Document{{"batchSize", int32(limit + 1)}}},
+		{"maxTimeMS", c.maxTimeMS(ctx)},
+	}
+	if !writes {
+		cmd = append(cmd, Element{"$readPreference", readPreference})
+	}
+
+	result := &QueryResult{Database: db, Collection: collection, Wrote: writes}
+	err = c.withConn(ctx, func(cn *conn) error {
+		reply, err := cn.command(ctx, db, cmd)
+		if err != nil {
+			return err
+		}
+		docs, more, err := c.readCursor(ctx, cn, db, reply, limit+1)
+		if err != nil {
+			return err
+		}
+		c.fillResult(result, docs, limit, more)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListCollections lists the collections and views of a database whose names match pattern, a glob or a
+// case-insensitive substring, optionally with their estimated document counts
+func (c *MongoClient) ListCollections(ctx context.Context, database, pattern string, withCounts bool) (string, []CollectionInfo, error) {
+	db, err := c.databaseName(database)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var collections []CollectionInfo
+	err = c.withConn(ctx, func(cn *conn) error {
+		reply, err := cn.command(ctx, db, Document{
+			{"listCollections", int32(1)},
+			{"authorizedCollections", true},
+			{"$readPreference", readPreference},
+		})
+		if err != nil {
+			return err
+		}
+		docs, _, err := c.readCursor(ctx, cn, db, reply, maxCollections)
+		if err != nil {
+			return err
+		}
+		for _, item := range docs {
+			doc, ok := item.(Document)
+			if !ok {
+				continue
+			}
+			info := collectionInfo(doc)
+			if info.Name == "" || !matchName(pattern, info.Name) {
+				continue
+			}
+			if withCounts && info.Type == "collection" {
+				if countReply, err := cn.command(ctx, db, Document{{"count", info.Name}, {"$readPreference", readPreference}}); err == nil {
+					if n, ok := countReply.Lookup("n"); ok {
+						if count, ok := toInt64(n); ok {
+							info.Documents = &count
+						}
+					}
+				}
+			}
+			collections = append(collections, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+	return db, collections, nil
+}
+
+// ListDatabases lists the databases the user may read
+func (c *MongoClient) ListDatabases(ctx context.Context) ([]string, error) {
+	var names []string
+	err := c.withConn(ctx, func(cn *conn) error {
+		reply, err := cn.command(ctx, "admin", Document{
+			{"listDatabases", int32(1)},
+			{"nameOnly", true},
+			{"authorizedDatabases", true},
+			{"$readPreference", readPreference},
+		})
+		if err != nil {
+			return err
+		}
+		databases, _ := reply.Lookup("databases")
+		items, _ := databases.([]interface{})
+		for _, item := range items {
+			if doc, ok := item.(Document); ok {
+				if name, ok := doc.Lookup("name"); ok {
+					names = append(names, fmt.Sprint(name))
+				}
+			}
+		}
+		return nil
+	})
+	sort.Strings(names)
+	return names, err
+}
+
+// CollectionStats returns the storage statistics of a collection (one entry per shard on a sharded
+// cluster) and its indexes
+func (c *MongoClient) CollectionStats(ctx context.Context, database, collection string) (map[string]interface{}, error) {
+	db, err := c.databaseName(database)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{"database": db, "collection": collection}
+	err = c.withConn(ctx, func(cn *conn) error {
+		reply, err := cn.command(ctx, db, Document{
+			{"aggregate", collection},
+			{"pipeline", []interface{}{Document{{"$collStats", Document{{"storageStats", Document{}}}}}}},
+			{"cursor", Document{}},
+			{"$readPreference", readPreference},
+		})
+		if err != nil {
+			return err
+		}
+		docs, _, err := c.readCursor(ctx, cn, db, reply, 1000)
+		if err != nil {
+			return err
+		}
+		var shards []interface{}
+		for _, item := range docs {
+			if doc, ok := item.(Document); ok {
+				shards = append(shards, storageSummary(doc))
+			}
+		}
+		if len(shards) == 1 {
+			result["stats"] = shards[0]
+		} else {
+			result["shards"] = shards
+		}
+
+		reply, err = cn.command(ctx, db, Document{{"listIndexes", collection}, {"$readPreference", readPreference}})
+		if err != nil {
+			return err
+		}
+		indexes, _, err := c.readCursor(ctx, cn, db, reply, 1000)
+		if err != nil {
+			return err
+		}
+		result["indexes"] = indexes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// storageSummary keeps the storage statistics of a $collStats document
+func storageSummary(doc Document) Document {
+	summary := Document{}
+	if shard, ok := doc.Lookup("shard"); ok {
+		summary = append(summary, Element{"shard", shard})
+	}
+	stats, _ := doc.Lookup("storageStats")
+	storage, _ := stats.(Document)
+	for _, key := range []string{"count", "size", "avgObjSize", "storageSize", "freeStorageSize", "capped", "max", "nindexes", "totalIndexSize", "indexSizes", "totalSize"} {
+		if value, ok := storage.Lookup(key); ok {
+			summary = append(summary, Element{key, value})
+		}
+	}
+	return summary
+}
+
+// readCursor reads up to limit documents of the cursor in a reply, fetching further batches with getMore,
+// and kills the cursor when documents remain. It reports whether the cursor had more.
+func (c *MongoClient) readCursor(ctx context.Context, cn *conn, db string, reply Document, limit int) ([]interface{}, bool, error) {
+	cursorValue, _ := reply.Lookup("cursor")
+	cursor, ok := cursorValue.(Document)
+	if !ok {
+		return nil, false, fmt.Errorf("reply has no cursor")
+	}
+	batch, _ := cursor.Lookup("firstBatch")
+	docs, _ := batch.([]interface{})
+	idValue, _ := cursor.Lookup("id")
+	id, _ := toInt64(idValue)
+	nsValue, _ := cursor.Lookup("ns")
+	ns, _ := nsValue.(string)
+	_, collection, _ := strings.Cut(ns, ".")
+
+	for id != 0 && len(docs) < limit {
+		more, err := cn.command(ctx, db, Document{
+			{"getMore", id},
+			{"collection", collection},
+			{"batchSize", int32(limit - len(docs))},
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		cursorValue, _ = more.Lookup("cursor")
+		cursor, _ = cursorValue.(Document)
+		batch, _ = cursor.Lookup("nextBatch")
+		next, _ := batch.([]interface{})
+		docs = append(docs, next...)
+		idValue, _ = cursor.Lookup("id")
+		id, _ = toInt64(idValue)
+	}
+	if id != 0 {
+		// Best effort: the server also times out idle cursors
+		_, _ = cn.command(ctx, db, Document{{"killCursors", collection}, {"cursors", []interface{}{id}}})
+	}
+	more := id != 0 || len(docs) > limit
+	if len(docs) > limit {
+		docs = docs[:limit]
+	}
+	return docs, more, nil
+}
+
+// fillResult adds up to limit documents to a result, leaving out those past the result size cap
+func (c *MongoClient) fillResult(result *QueryResult, docs []interface{}, limit int, more bool) {
+	if len(docs) > limit {
+		docs, more = docs[:limit], true
+	}
+	result.Documents = []json.RawMessage{}
+	size := 0
+	for i, doc := range docs {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			data, _ = json.Marshal(map[string]string{"error": err.Error()})
+		}
+		if size+len(data) > c.maxResultBytes && i > 0 {
+			result.Truncated = fmt.Sprintf("%d documents left out to keep the result under %d bytes (mongodb.max_result_bytes); add a projection or lower the limit", len(docs)-i, c.maxResultBytes)
+			more = true
+			break
+		}
+		size += len(data)
+		result.Documents = append(result.Documents, data)
+	}
+	result.Count = len(result.Documents)
+	result.HasMore = more
+}
+
+// limit applies the default and the cap to a requested document count
+func (c *MongoClient) limit(limit int) int {
+	if limit <= 0 {
+		return min(20, c.maxDocuments)
+	}
+	return min(limit, c.maxDocuments)
+}
+
+// collectionInfo summarizes a listCollections entry
+func collectionInfo(doc Document) CollectionInfo {
+	info := CollectionInfo{Type: "collection"}
+	if name, ok := doc.Lookup("name"); ok {
+		info.Name, _ = name.(string)
+	}
+	if t, ok := doc.Lookup("type"); ok {
+		info.Type, _ = t.(string)
+	}
+	optionsValue, _ := doc.Lookup("options")
+	options, _ := optionsValue.(Document)
+	if viewOn, ok := options.Lookup("viewOn"); ok {
+		info.ViewOn, _ = viewOn.(string)
+	}
+	if capped, ok := options.Lookup("capped"); ok {
+		info.Capped = isTrue(capped)
+	}
+	if _, ok := options.Lookup("validator"); ok {
+		info.Validator = true
+	}
+	if infoValue, ok := doc.Lookup("info"); ok {
+		if infoDoc, ok := infoValue.(Document); ok {
+			if readOnly, ok := infoDoc.Lookup("readOnly"); ok {
+				info.ReadOnly = isTrue(readOnly)
+			}
+		}
+	}
+	if len(options) > 0 {
+		info.Options = options
+	}
+	return info
+}
+
+// matchName matches a glob pattern, or a case-insensitive substring when there are no glob characters
+func matchName(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, err := path.Match(pattern, name)
+		return err == nil && matched
+	}
+	return strings.Contains(strings.ToLower(name), strings.ToLower(pattern))
+}
@@ -0,0 +1,383 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// MongoDBProvider provides read-oriented MongoDB queries: finds, validated aggregations, collections and
+// their storage statistics
+type MongoDBProvider struct {
+	*provider.BaseProvider
+	client *MongoClient
+}
+
+// NewMongoDBProvider creates a new MongoDB provider with config
+func NewMongoDBProvider(cfg *config.MongoDBConfig) *MongoDBProvider {
+	p := &MongoDBProvider{
+		BaseProvider: provider.NewBaseProvider("mongodb"),
+		client:       NewMongoClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "MongoDB not configured", p.client.InitError())
+		if err := p.client.InitError(); err != nil {
+			log.Printf("⚠ MongoDB provider not available: %v", err)
+		}
+		return p
+	}
+
+	p.SetAvailable(true)
+	if p.client.Validator().UnsafeMode() {
+		log.Printf("⚠ MongoDB unsafe mode enabled: $out, $merge and server-side JavaScript are allowed")
+	}
+	log.Printf("✓ MongoDB provider initialized successfully (hosts: %s)", strings.Join(p.client.Hosts(), ", "))
+
+	return p
+}
+
+// Test tests the MongoDB configuration (for ProviderClient interface compatibility)
+func (p *MongoDBProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("mongodb provider not available")
+	}
+	return nil
+}
+
+// AddTools adds MongoDB tools to the MCP server (for ProviderClient interface compatibility)
+func (p *MongoDBProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the MongoDB provider
+func (p *MongoDBProvider) Close() error {
+	return p.client.Close()
+}
+
+// HealthCheck checks that a server answers a ping with the configured credentials
+func (p *MongoDBProvider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.client.timeout)
+	defer cancel()
+	return p.client.Ping(ctx)
+}
+
+// addToolsToServer adds MongoDB tools to the MCP server
+func (p *MongoDBProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ MongoDB provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createListCollectionsTool(),
+		p.createFindTool(),
+		p.createAggregateTool(),
+		p.createCollectionStatsTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered MongoDB tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All MongoDB tools registered successfully")
+}
+
+// createListCollectionsTool creates the collection listing tool
+func (p *MongoDBProvider) createListCollectionsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "mongo_list_collections",
+		Description: "List the collections and views of a MongoDB database with their type, options and optionally their document counts. With list_databases, list the databases instead",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"database": {
+					"type": "string",
+					"description": "Database to list (defaults to mongodb.database or the database of the uri)"
+				},
+				"pattern": {
+					"type": "string",
+					"description": "Glob (orders_*) or case-insensitive substring the collection names must match"
+				},
+				"counts": {
+					"type": "boolean",
+					"description": "Add each collection's document count, taken from the collection metadata",
+					"default": false
+				},
+				"list_databases": {
+					"type": "boolean",
+					"description": "List the databases the user can read instead of collections",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Database      string `json:"database,omitempty"`
+			Pattern       string `json:"pattern,omitempty"`
+			Counts        bool   `json:"counts,omitempty"`
+			ListDatabases bool   `json:"list_databases,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		if args.ListDatabases {
+			databases, err := p.client.ListDatabases(ctx)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			if databases == nil {
+				databases = []string{}
+			}
+			return p.formatJSONResult(map[string]interface{}{"databases": databases, "count": len(databases)}), nil
+		}
+
+		database, collections, err := p.client.ListCollections(ctx, args.Database, args.Pattern, args.Counts)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if collections == nil {
+			collections = []CollectionInfo{}
+		}
+		return p.formatJSONResult(map[string]interface{}{
+			"database":    database,
+			"collections": collections,
+			"count":       len(collections),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createFindTool creates the find tool
+func (p *MongoDBProvider) createFindTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "mongo_find",
+		Description: fmt.Sprintf("Find documents in a MongoDB collection. filter, projection and sort are MongoDB extended JSON "+
+			`({"_id": {"$oid": "..."}}, {"createdAt": {"$gte": {"$date": "2024-05-01T00:00:00Z"}}}); keys of sort apply in order. `+
+			"Returns at most %d documents, with has_more when more matched; $where and other server-side JavaScript is blocked", p.client.MaxDocuments()),
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"collection": {
+					"type": "string",
+					"description": "Collection to query"
+				},
+				"database": {
+					"type": "string",
+					"description": "Database of the collection (defaults to mongodb.database or the database of the uri)"
+				},
+				"filter": {
+					"type": "object",
+					"description": "Query filter, e.g. {\"status\": \"failed\"}"
+				},
+				"projection": {
+					"type": "object",
+					"description": "Fields to include or exclude, e.g. {\"payload\": 0}"
+				},
+				"sort": {
+					"type": "object",
+					"description": "Sort order, e.g. {\"createdAt\": -1}"
+				},
+				"skip": {
+					"type": "integer",
+					"description": "Documents to skip",
+					"default": 0
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum documents to return",
+					"default": 20
+				}
+			},
+			"required": ["collection"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Collection string          `json:"collection"`
+			Database   string          `json:"database,omitempty"`
+			Filter     json.RawMessage `json:"filter,omitempty"`
+			Projection json.RawMessage `json:"projection,omitempty"`
+			Sort       json.RawMessage `json:"sort,omitempty"`
+			Skip       int64           `json:"skip,omitempty"`
+			Limit      int             `json:"limit,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Collection == "" {
+			return p.createErrorResult(fmt.Errorf("collection parameter is required")), nil
+		}
+
+		opts := FindOptions{Skip: args.Skip, Limit: args.Limit}
+		var err error
+		if opts.Filter, err = parseDocumentArg("filter", args.Filter); err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if opts.Projection, err = parseDocumentArg("projection", args.Projection); err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if opts.Sort, err = parseDocumentArg("sort", args.Sort); err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result, err := p.client.Find(ctx, args.Database, args.Collection, opts)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createAggregateTool creates the aggregation tool
+func (p *MongoDBProvider) createAggregateTool() entity.ToolDefinition {
+	description := "Run an aggregation pipeline on a MongoDB collection, or on the database without a collection (for $documents). " +
+		"Stages are MongoDB extended JSON. Only read stages are allowed ($match, $group, $project, $lookup, $facet, $unwind, $sort, ...): " +
+		"$out and $merge, unknown stages and server-side JavaScript are blocked"
+	if p.client.Validator().UnsafeMode() {
+		description = "Run an aggregation pipeline on a MongoDB collection, or on the database without a collection. " +
+			"Stages are MongoDB extended JSON. Unsafe mode is enabled: a final $out or $merge stage writes its output to a collection"
+	}
+	tool := &mcp.Tool{
+		Name:        "mongo_aggregate",
+		Description: description,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"pipeline": {
+					"type": "array",
+					"description": "Stages in order, e.g. [{\"$match\": {\"status\": \"failed\"}}, {\"$group\": {\"_id\": \"$code\", \"n\": {\"$sum\": 1}}}]",
+					"items": {"type": "object"}
+				},
+				"collection": {
+					"type": "string",
+					"description": "Collection to aggregate; omit to aggregate on the database"
+				},
+				"database": {
+					"type": "string",
+					"description": "Database of the collection (defaults to mongodb.database or the database of the uri)"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum documents to return",
+					"default": 20
+				}
+			},
+			"required": ["pipeline"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Pipeline   json.RawMessage `json:"pipeline"`
+			Collection string          `json:"collection,omitempty"`
+			Database   string          `json:"database,omitempty"`
+			Limit      int             `json:"limit,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if len(args.Pipeline) == 0 {
+			return p.createErrorResult(fmt.Errorf("pipeline parameter is required")), nil
+		}
+		value, err := parseExtendedJSON(args.Pipeline)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid pipeline: %w", err)), nil
+		}
+		pipeline, ok := value.([]interface{})
+		if !ok {
+			return p.createErrorResult(fmt.Errorf("invalid pipeline: must be an array of stages")), nil
+		}
+
+		result, err := p.client.Aggregate(ctx, args.Database, args.Collection, pipeline, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createCollectionStatsTool creates the collection statistics tool
+func (p *MongoDBProvider) createCollectionStatsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "mongo_collection_stats",
+		Description: "Show the storage statistics of a MongoDB collection (document count, data, storage and index sizes, per shard on a sharded cluster) and its index definitions",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"collection": {
+					"type": "string",
+					"description": "Collection to describe"
+				},
+				"database": {
+					"type": "string",
+					"description": "Database of the collection (defaults to mongodb.database or the database of the uri)"
+				}
+			},
+			"required": ["collection"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Collection string `json:"collection"`
+			Database   string `json:"database,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Collection == "" {
+			return p.createErrorResult(fmt.Errorf("collection parameter is required")), nil
+		}
+
+		stats, err := p.client.CollectionStats(ctx, args.Database, args.Collection)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(stats), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *MongoDBProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("MongoDB Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *MongoDBProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that MongoDBProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*MongoDBProvider)(nil)
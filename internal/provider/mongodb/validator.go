@@ -0,0 +1,154 @@
+package mongodb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// readStages are the aggregation stages mongo_aggregate runs; they only read
+var readStages = map[string]bool{
+	"$addFields": true, "$bucket": true, "$bucketAuto": true, "$collStats": true, "$count": true,
+	"$densify": true, "$documents": true, "$facet": true, "$fill": true, "$geoNear": true,
+	"$graphLookup": true, "$group": true, "$indexStats": true, "$limit": true, "$listSearchIndexes": true,
+	"$lookup": true, "$match": true, "$planCacheStats": true, "$project": true, "$redact": true,
+	"$replaceRoot": true, "$replaceWith": true, "$sample": true, "$search": true, "$searchMeta": true,
+	"$set": true, "$setWindowFields": true, "$skip": true, "$sort": true, "$sortByCount": true,
+	"$unionWith": true, "$unset": true, "$unwind": true, "$vectorSearch": true,
+}
+
+// writeStages write their input to a collection
+var writeStages = map[string]bool{"$out": true, "$merge": true}
+
+// javaScriptOperators run JavaScript on the server
+var javaScriptOperators = map[string]bool{"$where": true, "$function": true, "$accumulator": true}
+
+// SecurityValidator checks filters and pipelines before they are sent. Outside unsafe mode it rejects the
+// write stages $out and $merge, stages it does not know (such as $changeStream, which never returns) and
+// server-side JavaScript ($where, $function, $accumulator).
+type SecurityValidator struct {
+	unsafeMode bool
+}
+
+// NewSecurityValidator creates a validator; unsafe mode allows writes and JavaScript
+func NewSecurityValidator(unsafeMode bool) *SecurityValidator {
+	return &SecurityValidator{unsafeMode: unsafeMode}
+}
+
+// UnsafeMode reports whether writes and JavaScript are allowed
+func (v *SecurityValidator) UnsafeMode() bool {
+	return v.unsafeMode
+}
+
+// ValidateFilter checks a filter, projection or sort document
+func (v *SecurityValidator) ValidateFilter(name string, doc Document) error {
+	if err := v.checkOperators(doc); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// ValidatePipeline checks every stage of a pipeline, including the pipelines nested in $facet, $lookup and
+// $unionWith. It returns whether the pipeline writes, which only happens in unsafe mode.
+func (v *SecurityValidator) ValidatePipeline(pipeline []interface{}) (bool, error) {
+	return v.validatePipeline(pipeline, true)
+}
+
+func (v *SecurityValidator) validatePipeline(pipeline []interface{}, top bool) (bool, error) {
+	writes := false
+	for i, item := range pipeline {
+		stage, ok := item.(Document)
+		if !ok || len(stage) != 1 {
+			return false, fmt.Errorf("stage %d must be an object with exactly one stage operator", i+1)
+		}
+		name, spec := stage[0].Key, stage[0].Value
+		switch {
+		case writeStages[name]:
+			if !v.unsafeMode {
+				return false, fmt.Errorf("stage %d: %s writes to a collection and is blocked; enable mongodb.unsafe_mode to allow it", i+1, name)
+			}
+			if !top || i != len(pipeline)-1 {
+				return false, fmt.Errorf("stage %d: %s must be the last stage of the top-level pipeline", i+1, name)
+			}
+			writes = true
+		case readStages[name]:
+		case v.unsafeMode && strings.HasPrefix(name, "$"):
+		default:
+			return false, fmt.Errorf("stage %d: unknown or unsupported stage %q (allowed: %s)", i+1, name, strings.Join(allowedStages(), ", "))
+		}
+		if err := v.checkOperators(spec); err != nil {
+			return false, fmt.Errorf("stage %d (%s): %w", i+1, name, err)
+		}
+		if err := v.validateNested(name, spec); err != nil {
+			return false, fmt.Errorf("stage %d (%s): %w", i+1, name, err)
+		}
+	}
+	return writes, nil
+}
+
+// validateNested checks the sub-pipelines of $facet, $lookup and $unionWith
+func (v *SecurityValidator) validateNested(name string, spec interface{}) error {
+	doc, ok := spec.(Document)
+	if !ok {
+		return nil
+	}
+	switch name {
+	case "$facet":
+		for _, facet := range doc {
+			pipeline, ok := facet.Value.([]interface{})
+			if !ok {
+				return fmt.Errorf("facet %s must be a pipeline", facet.Key)
+			}
+			if _, err := v.validatePipeline(pipeline, false); err != nil {
+				return fmt.Errorf("facet %s: %w", facet.Key, err)
+			}
+		}
+	case "$lookup", "$unionWith":
+		if value, ok := doc.Lookup("pipeline"); ok {
+			pipeline, ok := value.([]interface{})
+			if !ok {
+				return fmt.Errorf("pipeline must be an array")
+			}
+			if _, err := v.validatePipeline(pipeline, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkOperators rejects server-side JavaScript anywhere in a value, outside unsafe mode
+func (v *SecurityValidator) checkOperators(value interface{}) error {
+	if v.unsafeMode {
+		return nil
+	}
+	switch val := value.(type) {
+	case Document:
+		for _, e := range val {
+			if javaScriptOperators[e.Key] {
+				return fmt.Errorf("%s runs JavaScript on the server and is blocked; enable mongodb.unsafe_mode to allow it", e.Key)
+			}
+			if err := v.checkOperators(e.Value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range val {
+			if err := v.checkOperators(item); err != nil {
+				return err
+			}
+		}
+	case JavaScript:
+		return fmt.Errorf("JavaScript values are blocked; enable mongodb.unsafe_mode to allow them")
+	}
+	return nil
+}
+
+func allowedStages() []string {
+	stages := make([]string, 0, len(readStages))
+	for stage := range readStages {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	return stages
+}
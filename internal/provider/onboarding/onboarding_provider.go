@@ -0,0 +1,309 @@
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/provider"
+)
+
+// probeTimeout bounds each HTTP probe
+const probeTimeout = 5 * time.Second
+
+// maxListedMatches bounds the label values and projects listed per step
+const maxListedMatches = 10
+
+// catalogEntry is a service in the simple catalog format, leaving out what was not found
+type catalogEntry struct {
+	Name           string            `yaml:"name"`
+	LokiLabels     map[string]string `yaml:"loki_labels,omitempty"`
+	SentryProjects []string          `yaml:"sentry_projects,omitempty"`
+	HealthChecks   []string          `yaml:"health_checks,omitempty"`
+}
+
+// OnboardingProvider helps wire a new service into dev-mcp
+type OnboardingProvider struct {
+	*provider.BaseProvider
+	prober   *prober
+	catalog  Catalog
+	labels   LabelSource
+	projects ProjectSource
+}
+
+// NewOnboardingProvider creates a new onboarding provider; catalog, labels and projects may be nil
+// when their providers are not running
+func NewOnboardingProvider(catalog Catalog, labels LabelSource, projects ProjectSource) *OnboardingProvider {
+	p := &OnboardingProvider{
+		BaseProvider: provider.NewBaseProvider("onboarding"),
+		prober: &prober{http: resty.New().
+			SetHeader("User-Agent", "dev-mcp/1.0").
+			SetTimeout(probeTimeout)},
+		catalog:  catalog,
+		labels:   labels,
+		projects: projects,
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Onboarding provider initialized successfully")
+
+	return p
+}
+
+// Test tests the onboarding configuration (for ProviderClient interface compatibility)
+func (p *OnboardingProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("onboarding provider not available")
+	}
+	return nil
+}
+
+// AddTools adds onboarding tools to the MCP server (for ProviderClient interface compatibility)
+func (p *OnboardingProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the onboarding provider
+func (p *OnboardingProvider) Close() error {
+	return nil
+}
+
+// addToolsToServer adds onboarding tools to the MCP server
+func (p *OnboardingProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Onboarding provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createOnboardTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered onboarding tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All onboarding tools registered successfully")
+}
+
+// createOnboardTool creates the guided setup tool
+func (p *OnboardingProvider) createOnboardTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "onboard_service",
+		Description: "Walk through wiring a new service into dev-mcp: check whether the catalog already has it, probe its Swagger/OpenAPI document and health endpoint, " +
+			"find the Loki labels carrying its name and its Sentry project, then emit the catalog entry and config.yaml snippet to add. " +
+			"Nothing is written; review the snippets, and pass the chosen values back to refine them",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"service": {
+					"type": "string",
+					"description": "Service name as its team refers to it, e.g. checkout"
+				},
+				"base_url": {
+					"type": "string",
+					"description": %q
+				},
+				"swagger_url": {
+					"type": "string",
+					"description": "Full URL of the Swagger/OpenAPI document, when known"
+				},
+				"loki_label": {
+					"type": "string",
+					"description": "Loki label=value to use instead of searching, e.g. app=checkout"
+				},
+				"sentry_project": {
+					"type": "string",
+					"description": "Sentry project slug to use instead of searching"
+				}
+			},
+			"required": ["service"]
+		}`, "Base URL of the service, e.g. https://checkout.internal; probes "+strings.Join(specPaths, ", ")+" and "+strings.Join(healthPaths, ", "))),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Service       string `json:"service"`
+			BaseURL       string `json:"base_url,omitempty"`
+			SwaggerURL    string `json:"swagger_url,omitempty"`
+			LokiLabel     string `json:"loki_label,omitempty"`
+			SentryProject string `json:"sentry_project,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Service == "" {
+			return p.createErrorResult(fmt.Errorf("service parameter is required")), nil
+		}
+		args.BaseURL = strings.TrimSuffix(args.BaseURL, "/")
+		var givenLabel, givenValue string
+		if args.LokiLabel != "" {
+			var ok bool
+			givenLabel, givenValue, ok = strings.Cut(args.LokiLabel, "=")
+			givenValue = strings.Trim(givenValue, `"`)
+			if !ok || givenLabel == "" || givenValue == "" {
+				return p.createErrorResult(fmt.Errorf("loki_label must look like label=value")), nil
+			}
+		}
+
+		entry := catalogEntry{Name: args.Service}
+		var spec *APISpec
+		var steps []Step
+
+		// 1. Catalog
+		switch {
+		case p.catalog == nil:
+			steps = append(steps, Step{Name: "catalog", Status: StatusSkipped, Detail: "the catalog provider is not running; set catalog.file to a services list"})
+		default:
+			if existing, ok := p.catalog.Get(args.Service); ok {
+				steps = append(steps, Step{Name: "catalog", Status: StatusFound, Detail: "the service is already in the catalog; the snippet shows what to add or change", Data: existing})
+			} else {
+				steps = append(steps, Step{Name: "catalog", Status: StatusNotFound, Detail: "not in the catalog yet"})
+			}
+		}
+
+		// 2. Swagger/OpenAPI document
+		step := Step{Name: "api_spec"}
+		var candidates []string
+		if args.SwaggerURL != "" {
+			candidates = []string{args.SwaggerURL}
+		} else if args.BaseURL != "" {
+			for _, path := range specPaths {
+				candidates = append(candidates, args.BaseURL+path)
+			}
+		}
+		if len(candidates) == 0 {
+			step.Status, step.Detail = StatusSkipped, "give base_url or swagger_url to probe the API document"
+		} else if found, tried := p.prober.probeSpec(ctx, candidates); found != nil {
+			spec = found
+			step.Status, step.Detail, step.Data = StatusFound, fmt.Sprintf("%s %s (%s, %d paths)", found.Title, found.Version, found.Format, found.Paths), found
+		} else {
+			step.Status, step.Detail, step.Data = StatusNotFound, "no Swagger/OpenAPI document at the probed URLs", map[string]interface{}{"tried": tried}
+		}
+		steps = append(steps, step)
+
+		// 3. Health endpoint
+		step = Step{Name: "health_check"}
+		if args.BaseURL == "" {
+			step.Status, step.Detail = StatusSkipped, "give base_url to probe health endpoints"
+		} else if url, tried := p.prober.probeHealth(ctx, args.BaseURL); url != "" {
+			entry.HealthChecks = []string{url}
+			step.Status, step.Detail = StatusFound, url
+		} else {
+			step.Status, step.Detail, step.Data = StatusNotFound, "no common health endpoint answered 2xx", map[string]interface{}{"tried": tried}
+		}
+		steps = append(steps, step)
+
+		// 4. Loki labels
+		step = Step{Name: "loki_labels"}
+		switch {
+		case givenLabel != "":
+			entry.LokiLabels = map[string]string{givenLabel: givenValue}
+			step.Status, step.Detail = StatusFound, "given"
+		case p.labels == nil:
+			step.Status, step.Detail = StatusSkipped, "the loki provider is not running"
+		default:
+			matches, err := matchLabels(p.labels, args.Service)
+			switch {
+			case err != nil:
+				step.Status, step.Detail = StatusError, err.Error()
+			case len(matches) == 0:
+				step.Status, step.Detail = StatusNotFound, fmt.Sprintf("no value of %s mentions %q in the last 6h; pass loki_label", strings.Join(serviceLabels, ", "), args.Service)
+			default:
+				best := matches[0]
+				entry.LokiLabels = map[string]string{best.Label: best.Value}
+				step.Status = StatusFound
+				step.Detail = fmt.Sprintf("%s=%q", best.Label, best.Value)
+				if !best.Exact {
+					step.Detail += " (partial match; check the alternatives)"
+				}
+				step.Data = map[string]interface{}{"matches": matches[:min(len(matches), maxListedMatches)]}
+			}
+		}
+		steps = append(steps, step)
+
+		// 5. Sentry project
+		step = Step{Name: "sentry_project"}
+		switch {
+		case args.SentryProject != "":
+			entry.SentryProjects = []string{args.SentryProject}
+			step.Status, step.Detail = StatusFound, "given"
+		case p.projects == nil:
+			step.Status, step.Detail = StatusSkipped, "the sentry provider is not running"
+		default:
+			projects, err := p.projects.ListProjects(ctx)
+			if err != nil {
+				step.Status, step.Detail = StatusError, err.Error()
+				break
+			}
+			matches := matchProjects(projects, args.Service)
+			if len(matches) == 0 {
+				step.Status, step.Detail = StatusNotFound, fmt.Sprintf("none of the %d projects mentions %q; pass sentry_project", len(projects), args.Service)
+				break
+			}
+			entry.SentryProjects = []string{matches[0].Slug}
+			step.Status, step.Detail = StatusFound, matches[0].Slug
+			if !strings.EqualFold(matches[0].Slug, args.Service) {
+				step.Detail += " (partial match; check the alternatives)"
+			}
+			step.Data = map[string]interface{}{"matches": matches[:min(len(matches), maxListedMatches)]}
+		}
+		steps = append(steps, step)
+
+		catalogSnippet, err := yaml.Marshal(map[string][]catalogEntry{"services": {entry}})
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("failed to build the catalog snippet: %w", err)), nil
+		}
+		result := map[string]interface{}{
+			"service":         args.Service,
+			"steps":           steps,
+			"catalog_snippet": string(catalogSnippet),
+		}
+		var next []string
+		next = append(next, "add the catalog_snippet entry to the file set in catalog.file and restart dev-mcp")
+		if spec != nil {
+			result["config_snippet"] = fmt.Sprintf("swagger:\n  url: %q\n", spec.URL)
+			next = append(next, "set swagger.url in config.yaml to use the document in validate_json (it replaces the current document)")
+		}
+		for _, s := range steps {
+			if s.Status == StatusNotFound || s.Status == StatusError {
+				next = append(next, fmt.Sprintf("%s was not resolved: %s", s.Name, s.Detail))
+			}
+		}
+		result["next_steps"] = next
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *OnboardingProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Onboarding Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *OnboardingProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that OnboardingProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*OnboardingProvider)(nil)
@@ -0,0 +1,212 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"gopkg.in/yaml.v2"
+
+	"dev-mcp/internal/provider/catalog"
+	"dev-mcp/internal/provider/sentry"
+)
+
+// Step status values
+const (
+	StatusFound    = "found"
+	StatusNotFound = "not_found"
+	StatusSkipped  = "skipped" // Nothing to probe: no URL given or the provider is not running
+	StatusError    = "error"
+)
+
+// maxSpecBytes bounds the API specification read while probing
+const maxSpecBytes = 10 << 20
+
+// specPaths are where frameworks commonly serve their Swagger/OpenAPI document
+var specPaths = []string{
+	"/openapi.json", "/swagger.json", "/v3/api-docs", "/v2/api-docs", "/swagger/doc.json",
+	"/swagger/v1/swagger.json", "/api-docs", "/openapi.yaml", "/swagger.yaml", "/docs/openapi.json",
+}
+
+// healthPaths are common health endpoints
+var healthPaths = []string{"/healthz", "/health", "/livez", "/ready", "/actuator/health", "/status"}
+
+// serviceLabels are the Loki labels that usually carry a service name, most specific first
+var serviceLabels = []string{
+	"service_name", "service", "app", "app_kubernetes_io_name", "application", "component", "container", "job",
+}
+
+// Catalog looks up services in the service catalog
+type Catalog interface {
+	Get(name string) (*catalog.Service, bool)
+}
+
+// LabelSource lists Loki labels and their values
+type LabelSource interface {
+	GetLogLabels() ([]string, error)
+	GetLabelValues(name string) ([]string, error)
+}
+
+// ProjectSource lists Sentry projects
+type ProjectSource interface {
+	ListProjects(ctx context.Context) ([]sentry.Project, error)
+}
+
+// Step is the outcome of one onboarding probe
+type Step struct {
+	Name   string      `json:"name"`
+	Status string      `json:"status"`
+	Detail string      `json:"detail"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// APISpec is a Swagger/OpenAPI document found by probing
+type APISpec struct {
+	URL     string `json:"url"`
+	Format  string `json:"format"` // e.g. "openapi 3.0.3" or "swagger 2.0"
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version,omitempty"`
+	Paths   int    `json:"paths"`
+}
+
+// LabelMatch is a Loki label value matching the service
+type LabelMatch struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Exact bool   `json:"exact"`
+}
+
+// prober runs the onboarding probes
+type prober struct {
+	http *resty.Client
+}
+
+// probeSpec requests each candidate URL and returns the first Swagger/OpenAPI document
+func (p *prober) probeSpec(ctx context.Context, candidates []string) (*APISpec, []string) {
+	var tried []string
+	for _, url := range candidates {
+		spec, err := p.fetchSpec(ctx, url)
+		if err != nil {
+			tried = append(tried, fmt.Sprintf("%s: %v", url, err))
+			continue
+		}
+		return spec, tried
+	}
+	return nil, tried
+}
+
+// fetchSpec reads a URL and checks that it holds a Swagger/OpenAPI document (JSON or YAML)
+func (p *prober) fetchSpec(ctx context.Context, url string) (*APISpec, error) {
+	resp, err := p.http.R().SetContext(ctx).SetDoNotParseResponse(true).Get(url)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.RawBody()
+	defer body.Close()
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("status %s", resp.Status())
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxSpecBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSpecBytes {
+		return nil, fmt.Errorf("larger than %d bytes", maxSpecBytes)
+	}
+
+	// YAML is a superset of JSON, so one decoder reads both
+	var doc struct {
+		OpenAPI string `yaml:"openapi"`
+		Swagger string `yaml:"swagger"`
+		Info    struct {
+			Title   string `yaml:"title"`
+			Version string `yaml:"version"`
+		} `yaml:"info"`
+		Paths map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("not JSON or YAML")
+	}
+	spec := &APISpec{URL: url, Title: doc.Info.Title, Version: doc.Info.Version, Paths: len(doc.Paths)}
+	switch {
+	case doc.OpenAPI != "":
+		spec.Format = "openapi " + doc.OpenAPI
+	case doc.Swagger != "":
+		spec.Format = "swagger " + doc.Swagger
+	default:
+		return nil, fmt.Errorf("no openapi or swagger version field")
+	}
+	return spec, nil
+}
+
+// probeHealth returns the first health endpoint answering 2xx
+func (p *prober) probeHealth(ctx context.Context, baseURL string) (string, []string) {
+	var tried []string
+	for _, path := range healthPaths {
+		url := baseURL + path
+		resp, err := p.http.R().SetContext(ctx).Get(url)
+		switch {
+		case err != nil:
+			tried = append(tried, fmt.Sprintf("%s: %v", url, err))
+		case !resp.IsSuccess():
+			tried = append(tried, fmt.Sprintf("%s: status %s", url, resp.Status()))
+		default:
+			return url, tried
+		}
+	}
+	return "", tried
+}
+
+// matchLabels looks for the service name among the values of the labels that usually carry it.
+// Exact matches come first, then values containing the name, in label order.
+func matchLabels(labels LabelSource, service string) ([]LabelMatch, error) {
+	available, err := labels.GetLogLabels()
+	if err != nil {
+		return nil, err
+	}
+	present := map[string]bool{}
+	for _, label := range available {
+		present[label] = true
+	}
+
+	name := strings.ToLower(service)
+	var exact, partial []LabelMatch
+	for _, label := range serviceLabels {
+		if !present[label] {
+			continue
+		}
+		values, err := labels.GetLabelValues(label)
+		if err != nil {
+			return nil, fmt.Errorf("label %s: %w", label, err)
+		}
+		for _, value := range values {
+			lower := strings.ToLower(value)
+			switch {
+			case lower == name:
+				exact = append(exact, LabelMatch{Label: label, Value: value, Exact: true})
+			case strings.Contains(lower, name):
+				partial = append(partial, LabelMatch{Label: label, Value: value})
+			}
+		}
+	}
+	return append(exact, partial...), nil
+}
+
+// matchProjects returns the Sentry projects whose slug or name matches the service, exact matches first
+func matchProjects(projects []sentry.Project, service string) []sentry.Project {
+	name := strings.ToLower(service)
+	var matches []sentry.Project
+	for _, project := range projects {
+		slug, title := strings.ToLower(project.Slug), strings.ToLower(project.Name)
+		if strings.Contains(slug, name) || strings.Contains(title, name) {
+			matches = append(matches, project)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return strings.EqualFold(matches[i].Slug, service) && !strings.EqualFold(matches[j].Slug, service)
+	})
+	return matches
+}
@@ -0,0 +1,267 @@
+package oncall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	defaultIncidentLimit = 25
+	maxIncidentLimit     = 100
+	// maxSchedules bounds the schedules whose on-call users are looked up when no schedule is named
+	maxSchedules = 50
+)
+
+// Shift is a user on call for a schedule or an escalation policy level
+type Shift struct {
+	System           string     `json:"system"`
+	Schedule         string     `json:"schedule,omitempty"`
+	EscalationPolicy string     `json:"escalation_policy,omitempty"`
+	Level            int        `json:"escalation_level,omitempty"`
+	User             string     `json:"user"`
+	Email            string     `json:"email,omitempty"`
+	Start            *time.Time `json:"start,omitempty"`
+	End              *time.Time `json:"end,omitempty"` // Nil for permanent assignments
+}
+
+// Incident is a PagerDuty incident or an Opsgenie alert
+type Incident struct {
+	System    string     `json:"system"`
+	ID        string     `json:"id"`
+	Number    string     `json:"number,omitempty"` // PagerDuty incident number or Opsgenie tiny ID
+	Title     string     `json:"title"`
+	Status    string     `json:"status"` // triggered, acknowledged or resolved
+	Urgency   string     `json:"urgency,omitempty"`
+	Priority  string     `json:"priority,omitempty"`
+	Service   string     `json:"service,omitempty"`
+	Assignees []string   `json:"assignees,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	URL       string     `json:"url,omitempty"`
+}
+
+// IncidentOptions filters the incidents of incident_list
+type IncidentOptions struct {
+	Status  string // open (triggered or acknowledged, default), triggered, acknowledged, resolved or all
+	Since   time.Time
+	Service string
+	Limit   int
+}
+
+// statuses returns the incident statuses an option asks for
+func (o IncidentOptions) statuses() []string {
+	switch o.Status {
+	case "", "open":
+		return []string{"triggered", "acknowledged"}
+	case "all":
+		return []string{"triggered", "acknowledged", "resolved"}
+	}
+	return []string{o.Status}
+}
+
+// AckResult is the outcome of acknowledging an incident
+type AckResult struct {
+	System    string `json:"system"`
+	ID        string `json:"id"`
+	Status    string `json:"status"` // acknowledged, or requested when the service processes it asynchronously
+	Title     string `json:"title,omitempty"`
+	NoteAdded bool   `json:"note_added,omitempty"`
+}
+
+// Backend is an on-call and incident management service
+type Backend interface {
+	// Name returns the system name, pagerduty or opsgenie
+	Name() string
+	// OnCall returns who is on call at a time, for the schedules matching schedule or for every schedule
+	OnCall(ctx context.Context, schedule string, at time.Time) ([]Shift, error)
+	// Incidents lists incidents, newest first
+	Incidents(ctx context.Context, opts IncidentOptions) ([]Incident, error)
+	// Acknowledge acknowledges an incident, adding note to it when given
+	Acknowledge(ctx context.Context, id, note string) (*AckResult, error)
+}
+
+// OnCallClient dispatches requests to the configured on-call services
+type OnCallClient struct {
+	config   *config.OnCallConfig
+	backends map[string]Backend
+}
+
+// NewOnCallClient creates a client with a backend for every service with credentials
+func NewOnCallClient(cfg *config.OnCallConfig) *OnCallClient {
+	c := &OnCallClient{config: cfg, backends: map[string]Backend{}}
+	if cfg == nil {
+		c.config = &config.OnCallConfig{}
+		return c
+	}
+	if cfg.PagerDuty.APIToken != "" {
+		c.backends["pagerduty"] = newPagerDutyBackend(&cfg.PagerDuty)
+	}
+	if cfg.Opsgenie.APIKey != "" {
+		c.backends["opsgenie"] = newOpsgenieBackend(&cfg.Opsgenie)
+	}
+	return c
+}
+
+// IsAvailable checks if at least one service is configured
+func (c *OnCallClient) IsAvailable() bool {
+	return len(c.backends) > 0
+}
+
+// IsWriteEnabled reports whether incidents may be acknowledged
+func (c *OnCallClient) IsWriteEnabled() bool {
+	return c.config.WriteEnabled
+}
+
+// Systems returns the names of the configured services
+func (c *OnCallClient) Systems() []string {
+	names := make([]string, 0, len(c.backends))
+	for name := range c.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectBackends resolves a system name; an empty name selects every configured service
+func (c *OnCallClient) selectBackends(system string) ([]Backend, error) {
+	if system == "" {
+		backends := make([]Backend, 0, len(c.backends))
+		for _, name := range c.Systems() {
+			backends = append(backends, c.backends[name])
+		}
+		return backends, nil
+	}
+	b, ok := c.backends[system]
+	if !ok {
+		return nil, fmt.Errorf("on-call system %q is not configured (available: %v)", system, c.Systems())
+	}
+	return []Backend{b}, nil
+}
+
+// OnCall returns who is on call at a time across the selected services. When several services are asked,
+// the errors of some are returned as warnings alongside the shifts of the others.
+func (c *OnCallClient) OnCall(ctx context.Context, system, schedule string, at time.Time) ([]Shift, []string, error) {
+	backends, err := c.selectBackends(system)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shifts := []Shift{}
+	var warnings []string
+	for _, b := range backends {
+		found, err := b.OnCall(ctx, schedule, at)
+		if err != nil {
+			if len(backends) == 1 {
+				return nil, nil, err
+			}
+			warnings = append(warnings, fmt.Sprintf("%s: %v", b.Name(), err))
+			continue
+		}
+		shifts = append(shifts, found...)
+	}
+	sort.SliceStable(shifts, func(i, j int) bool {
+		a, b := shifts[i], shifts[j]
+		if a.System != b.System {
+			return a.System < b.System
+		}
+		if a.EscalationPolicy != b.EscalationPolicy {
+			return a.EscalationPolicy < b.EscalationPolicy
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		return a.Schedule < b.Schedule
+	})
+	return shifts, warnings, nil
+}
+
+// Incidents lists incidents across the selected services, newest first
+func (c *OnCallClient) Incidents(ctx context.Context, system string, opts IncidentOptions) ([]Incident, []string, error) {
+	switch opts.Status {
+	case "", "open", "triggered", "acknowledged", "resolved", "all":
+	default:
+		return nil, nil, fmt.Errorf("invalid status %q: use open, triggered, acknowledged, resolved or all", opts.Status)
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultIncidentLimit
+	}
+	opts.Limit = min(opts.Limit, maxIncidentLimit)
+
+	backends, err := c.selectBackends(system)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	incidents := []Incident{}
+	var warnings []string
+	for _, b := range backends {
+		found, err := b.Incidents(ctx, opts)
+		if err != nil {
+			if len(backends) == 1 {
+				return nil, nil, err
+			}
+			warnings = append(warnings, fmt.Sprintf("%s: %v", b.Name(), err))
+			continue
+		}
+		incidents = append(incidents, found...)
+	}
+	sort.SliceStable(incidents, func(i, j int) bool {
+		a, b := incidents[i].CreatedAt, incidents[j].CreatedAt
+		return a != nil && (b == nil || a.After(*b))
+	})
+	if len(incidents) > opts.Limit {
+		incidents = incidents[:opts.Limit]
+	}
+	return incidents, warnings, nil
+}
+
+// Acknowledge acknowledges an incident of one service; system may be empty when only one is configured
+func (c *OnCallClient) Acknowledge(ctx context.Context, system, id, note string) (*AckResult, error) {
+	if !c.IsWriteEnabled() {
+		return nil, fmt.Errorf("acknowledging incidents is disabled (set oncall.write_enabled to true)")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	if system == "" && len(c.backends) > 1 {
+		return nil, fmt.Errorf("system parameter is required when several on-call systems are configured (%v)", c.Systems())
+	}
+	backends, err := c.selectBackends(system)
+	if err != nil {
+		return nil, err
+	}
+	return backends[0].Acknowledge(ctx, id, note)
+}
+
+// Close closes the on-call client
+func (c *OnCallClient) Close() error {
+	return nil
+}
+
+// matchName reports whether a schedule or service name is the one asked for: its ID, or a
+// case-insensitive substring of its name
+func matchName(want, id, name string) bool {
+	if want == "" {
+		return true
+	}
+	return want == id || strings.Contains(strings.ToLower(name), strings.ToLower(want))
+}
+
+// apiError builds an error from a failed response, with the body when there is one
+func apiError(system string, resp *resty.Response) error {
+	body := strings.TrimSpace(resp.String())
+	if len(body) > 500 {
+		body = body[:500] + "..."
+	}
+	if body == "" {
+		return fmt.Errorf("%s API error: %s", system, resp.Status())
+	}
+	return fmt.Errorf("%s API error: %s: %s", system, resp.Status(), body)
+}
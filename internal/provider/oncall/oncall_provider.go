@@ -0,0 +1,282 @@
+package oncall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/loki"
+)
+
+// OnCallProvider exposes who is on call and the open incidents of PagerDuty and Opsgenie, and acknowledges
+// incidents when writes are enabled
+type OnCallProvider struct {
+	*provider.BaseProvider
+	client *OnCallClient
+}
+
+// NewOnCallProvider creates a new on-call provider with config
+func NewOnCallProvider(cfg *config.OnCallConfig) *OnCallProvider {
+	p := &OnCallProvider{
+		BaseProvider: provider.NewBaseProvider("oncall"),
+		client:       NewOnCallClient(cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "PagerDuty or Opsgenie not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ On-call provider initialized successfully (%s)", strings.Join(p.client.Systems(), ", "))
+
+	return p
+}
+
+// Client returns the on-call client
+func (p *OnCallProvider) Client() *OnCallClient {
+	return p.client
+}
+
+// Test tests the on-call configuration (for ProviderClient interface compatibility)
+func (p *OnCallProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("oncall provider not available")
+	}
+	return nil
+}
+
+// AddTools adds on-call tools to the MCP server (for ProviderClient interface compatibility)
+func (p *OnCallProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the on-call provider
+func (p *OnCallProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds on-call tools to the MCP server
+func (p *OnCallProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ On-call provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createCurrentTool(),
+		p.createIncidentListTool(),
+		p.createIncidentAckTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered on-call tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All on-call tools registered successfully")
+}
+
+// systemProperty is the JSON schema of the system parameter
+func (p *OnCallProvider) systemProperty(description string) string {
+	return fmt.Sprintf(`{"type": "string", "enum": ["pagerduty", "opsgenie"], "description": %q}`,
+		fmt.Sprintf("%s (configured: %s)", description, strings.Join(p.client.Systems(), ", ")))
+}
+
+// createCurrentTool creates the on-call lookup tool
+func (p *OnCallProvider) createCurrentTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "oncall_current",
+		Description: "Show who is on call in PagerDuty or Opsgenie for a schedule, or for every schedule and escalation policy, now or at a given time; PagerDuty also gives the escalation level and shift end",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"schedule": {
+					"type": "string",
+					"description": "Schedule ID, or text its name contains; every schedule when empty"
+				},
+				"at": {
+					"type": "string",
+					"description": "Time to look up: RFC3339, unix timestamp, or a duration ago such as 2h (default: now)"
+				},
+				"system": %s
+			}
+		}`, p.systemProperty("Service to ask; every configured one when empty"))),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Schedule string `json:"schedule,omitempty"`
+			At       string `json:"at,omitempty"`
+			System   string `json:"system,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		at, err := loki.ParseTime(args.At, time.Now())
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		shifts, warnings, err := p.client.OnCall(ctx, args.System, strings.TrimSpace(args.Schedule), at)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		result := map[string]interface{}{
+			"at":      at.UTC().Format(time.RFC3339),
+			"on_call": shifts,
+			"count":   len(shifts),
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createIncidentListTool creates the incident listing tool
+func (p *OnCallProvider) createIncidentListTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "incident_list",
+		Description: "List PagerDuty incidents and Opsgenie alerts, newest first, with their status (triggered, acknowledged or resolved), urgency or priority, service and assignees — paging context for incident reports",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"status": {
+					"type": "string",
+					"enum": ["open", "triggered", "acknowledged", "resolved", "all"],
+					"description": "open is triggered or acknowledged",
+					"default": "open"
+				},
+				"since": {
+					"type": "string",
+					"description": "Only incidents created after this: RFC3339, unix timestamp, or a duration ago such as 24h or 7d (default: any time)"
+				},
+				"service": {
+					"type": "string",
+					"description": "PagerDuty service ID or name text; Opsgenie alert entity or tag"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum incidents to return (at most %d)",
+					"default": %d
+				},
+				"system": %s
+			}
+		}`, maxIncidentLimit, defaultIncidentLimit, p.systemProperty("Service to ask; every configured one when empty"))),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Status  string `json:"status,omitempty"`
+			Since   string `json:"since,omitempty"`
+			Service string `json:"service,omitempty"`
+			Limit   int    `json:"limit,omitempty"`
+			System  string `json:"system,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		opts := IncidentOptions{Status: args.Status, Service: strings.TrimSpace(args.Service), Limit: args.Limit}
+		if args.Since != "" {
+			since, err := loki.ParseTime(args.Since, time.Now())
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			opts.Since = since
+		}
+		incidents, warnings, err := p.client.Incidents(ctx, args.System, opts)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		result := map[string]interface{}{
+			"incidents": incidents,
+			"count":     len(incidents),
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createIncidentAckTool creates the incident acknowledgement tool (gated by oncall.write_enabled)
+func (p *OnCallProvider) createIncidentAckTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "incident_ack",
+		Description: "Acknowledge a PagerDuty incident or Opsgenie alert, optionally adding a note, so it stops escalating. Requires oncall.write_enabled",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"id": {
+					"type": "string",
+					"description": "Incident ID from incident_list (Opsgenie: alert ID or tiny ID)"
+				},
+				"note": {
+					"type": "string",
+					"description": "Note added to the incident, e.g. who is looking into it"
+				},
+				"system": %s
+			},
+			"required": ["id"]
+		}`, p.systemProperty("Service of the incident; required when both are configured"))),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			ID     string `json:"id"`
+			Note   string `json:"note,omitempty"`
+			System string `json:"system,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		result, err := p.client.Acknowledge(ctx, args.System, args.ID, strings.TrimSpace(args.Note))
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *OnCallProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("On-call Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *OnCallProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that OnCallProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*OnCallProvider)(nil)
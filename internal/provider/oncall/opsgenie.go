@@ -0,0 +1,213 @@
+package oncall
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+)
+
+// opsgenieBackend talks to the Opsgenie REST API; incidents are Opsgenie alerts
+type opsgenieBackend struct {
+	client *resty.Client
+	user   string
+}
+
+func newOpsgenieBackend(cfg *config.OpsgenieConfig) *opsgenieBackend {
+	baseURL := "https://api.opsgenie.com"
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetHeader("Authorization", "GenieKey "+cfg.APIKey).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+
+	return &opsgenieBackend{client: client, user: cfg.User}
+}
+
+func (b *opsgenieBackend) Name() string { return "opsgenie" }
+
+// get fetches a path into result
+func (b *opsgenieBackend) get(ctx context.Context, path string, query map[string]string, result interface{}) error {
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetQueryParams(query).
+		SetResult(result).
+		Get(path)
+	if err != nil {
+		return fmt.Errorf("opsgenie request failed: %w", err)
+	}
+	if resp.IsError() {
+		return apiError("opsgenie", resp)
+	}
+	return nil
+}
+
+// OnCall returns the on-call recipients at a time of the matching schedules, or of every enabled schedule
+func (b *opsgenieBackend) OnCall(ctx context.Context, schedule string, at time.Time) ([]Shift, error) {
+	var schedules struct {
+		Data []struct {
+			ID      string `json:"id"`
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+		} `json:"data"`
+	}
+	if err := b.get(ctx, "/v2/schedules", nil, &schedules); err != nil {
+		return nil, err
+	}
+
+	shifts := []Shift{}
+	checked := 0
+	for _, s := range schedules.Data {
+		if !s.Enabled || !matchName(schedule, s.ID, s.Name) {
+			continue
+		}
+		if checked == maxSchedules {
+			return nil, fmt.Errorf("more than %d Opsgenie schedules match; name one with schedule", maxSchedules)
+		}
+		checked++
+
+		var onCalls struct {
+			Data struct {
+				OnCallRecipients []string `json:"onCallRecipients"`
+			} `json:"data"`
+		}
+		if err := b.get(ctx, fmt.Sprintf("/v2/schedules/%s/on-calls", url.PathEscape(s.ID)), map[string]string{
+			"scheduleIdentifierType": "id",
+			"flat":                   "true",
+			"date":                   at.UTC().Format(time.RFC3339),
+		}, &onCalls); err != nil {
+			return nil, err
+		}
+		for _, recipient := range onCalls.Data.OnCallRecipients {
+			shift := Shift{System: "opsgenie", Schedule: s.Name, User: recipient}
+			if strings.Contains(recipient, "@") {
+				shift.Email = recipient
+			}
+			shifts = append(shifts, shift)
+		}
+	}
+	if schedule != "" && checked == 0 {
+		return nil, fmt.Errorf("no Opsgenie schedule matches %q", schedule)
+	}
+	return shifts, nil
+}
+
+// alertQuery builds the Opsgenie search query of the incident options
+func alertQuery(opts IncidentOptions) string {
+	var terms []string
+	switch opts.Status {
+	case "", "open":
+		terms = append(terms, "status: open")
+	case "triggered":
+		terms = append(terms, "status: open", "acknowledged: false")
+	case "acknowledged":
+		terms = append(terms, "status: open", "acknowledged: true")
+	case "resolved":
+		terms = append(terms, "status: closed")
+	}
+	if !opts.Since.IsZero() {
+		terms = append(terms, fmt.Sprintf("createdAt >= %d", opts.Since.UnixMilli()))
+	}
+	if opts.Service != "" {
+		service := strconv.Quote(opts.Service)
+		terms = append(terms, fmt.Sprintf("(entity: %s OR tag: %s)", service, service))
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// Incidents lists alerts, newest first
+func (b *opsgenieBackend) Incidents(ctx context.Context, opts IncidentOptions) ([]Incident, error) {
+	var reply struct {
+		Data []struct {
+			ID           string     `json:"id"`
+			TinyID       string     `json:"tinyId"`
+			Message      string     `json:"message"`
+			Status       string     `json:"status"` // open or closed
+			Acknowledged bool       `json:"acknowledged"`
+			Priority     string     `json:"priority"` // P1 to P5
+			Owner        string     `json:"owner"`
+			Entity       string     `json:"entity"`
+			CreatedAt    *time.Time `json:"createdAt"`
+		} `json:"data"`
+	}
+	if err := b.get(ctx, "/v2/alerts", map[string]string{
+		"query": alertQuery(opts),
+		"limit": strconv.Itoa(opts.Limit),
+		"sort":  "createdAt",
+		"order": "desc",
+	}, &reply); err != nil {
+		return nil, err
+	}
+
+	incidents := make([]Incident, 0, len(reply.Data))
+	for _, alert := range reply.Data {
+		incident := Incident{
+			System:    "opsgenie",
+			ID:        alert.ID,
+			Number:    alert.TinyID,
+			Title:     alert.Message,
+			Status:    "triggered",
+			Priority:  alert.Priority,
+			Service:   alert.Entity,
+			CreatedAt: alert.CreatedAt,
+		}
+		switch {
+		case alert.Status == "closed":
+			incident.Status = "resolved"
+		case alert.Acknowledged:
+			incident.Status = "acknowledged"
+		}
+		if alert.Owner != "" {
+			incident.Assignees = []string{alert.Owner}
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, nil
+}
+
+// Acknowledge acknowledges an alert by ID or tiny ID, as the user the tool call acts for when there is
+// one and otherwise as opsgenie.user. Opsgenie processes the request asynchronously.
+func (b *opsgenieBackend) Acknowledge(ctx context.Context, id, note string) (*AckResult, error) {
+	identifierType := "id"
+	if _, err := strconv.Atoi(id); err == nil {
+		identifierType = "tiny"
+	}
+	user := b.user
+	if onBehalfOf, ok := auth.OnBehalfOf(ctx); ok {
+		user = onBehalfOf
+	}
+	body := map[string]string{"source": "dev-mcp"}
+	if user != "" {
+		body["user"] = user
+	}
+	if note != "" {
+		body["note"] = note
+	}
+
+	req := b.client.R().SetContext(ctx)
+	if err := auth.ApplyOnBehalfOf(ctx, "opsgenie", req); err != nil {
+		return nil, err
+	}
+	resp, err := req.
+		SetQueryParam("identifierType", identifierType).
+		SetBody(body).
+		Post(fmt.Sprintf("/v2/alerts/%s/acknowledge", url.PathEscape(id)))
+	if err != nil {
+		return nil, fmt.Errorf("opsgenie request failed: %w", err)
+	}
+	if resp.IsError() {
+		return nil, apiError("opsgenie", resp)
+	}
+	return &AckResult{System: "opsgenie", ID: id, Status: "requested", NoteAdded: note != ""}, nil
+}
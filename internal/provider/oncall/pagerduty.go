@@ -0,0 +1,279 @@
+package oncall
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/auth"
+	"dev-mcp/internal/config"
+)
+
+// pagerDutyIDPattern matches PagerDuty object IDs such as PABC123
+var pagerDutyIDPattern = regexp.MustCompile(`^P[A-Z0-9]{6}$`)
+
+// maxOnCallPages bounds the pages of /oncalls read for one lookup
+const maxOnCallPages = 5
+
+// pagerDutyBackend talks to the PagerDuty REST API v2
+type pagerDutyBackend struct {
+	client *resty.Client
+	from   string
+}
+
+func newPagerDutyBackend(cfg *config.PagerDutyConfig) *pagerDutyBackend {
+	baseURL := "https://api.pagerduty.com"
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+
+	client := resty.New().
+		SetBaseURL(baseURL).
+		SetHeader("Authorization", "Token token="+cfg.APIToken).
+		SetHeader("Accept", "application/vnd.pagerduty+json;version=2").
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+
+	return &pagerDutyBackend{client: client, from: cfg.From}
+}
+
+func (b *pagerDutyBackend) Name() string { return "pagerduty" }
+
+// pagerDutyReference is a reference to another object, with its name as summary
+type pagerDutyReference struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+	Email   string `json:"email"` // Users, with include[]=users
+}
+
+// get fetches a path into result
+func (b *pagerDutyBackend) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetQueryParamsFromValues(query).
+		SetResult(result).
+		Get(path)
+	if err != nil {
+		return fmt.Errorf("pagerduty request failed: %w", err)
+	}
+	if resp.IsError() {
+		return apiError("pagerduty", resp)
+	}
+	return nil
+}
+
+// resolve returns the IDs of the schedules or services whose ID is want or whose name contains it
+func (b *pagerDutyBackend) resolve(ctx context.Context, kind, want string) ([]string, error) {
+	if pagerDutyIDPattern.MatchString(want) {
+		return []string{want}, nil
+	}
+	var reply map[string][]pagerDutyReference
+	if err := b.get(ctx, "/"+kind, url.Values{"query": {want}, "limit": {"100"}}, &reply); err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, object := range reply[kind] {
+		if matchName(want, object.ID, object.Summary) {
+			ids = append(ids, object.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no PagerDuty %s matches %q", strings.TrimSuffix(kind, "s"), want)
+	}
+	return ids, nil
+}
+
+// OnCall returns the on-call entries at a time, for the matching schedules or for every escalation policy
+func (b *pagerDutyBackend) OnCall(ctx context.Context, schedule string, at time.Time) ([]Shift, error) {
+	// earliest keeps one entry per escalation policy, level and user
+	query := url.Values{
+		"since":     {at.UTC().Format(time.RFC3339)},
+		"until":     {at.UTC().Format(time.RFC3339)},
+		"earliest":  {"true"},
+		"include[]": {"users"},
+		"limit":     {"100"},
+	}
+	if schedule != "" {
+		ids, err := b.resolve(ctx, "schedules", schedule)
+		if err != nil {
+			return nil, err
+		}
+		query["schedule_ids[]"] = ids
+	}
+
+	shifts := []Shift{}
+	for page := 0; page < maxOnCallPages; page++ {
+		query.Set("offset", strconv.Itoa(page*100))
+		var reply struct {
+			OnCalls []struct {
+				User             pagerDutyReference  `json:"user"`
+				Schedule         *pagerDutyReference `json:"schedule"` // Nil when the policy targets the user directly
+				EscalationPolicy pagerDutyReference  `json:"escalation_policy"`
+				EscalationLevel  int                 `json:"escalation_level"`
+				Start            *time.Time          `json:"start"`
+				End              *time.Time          `json:"end"`
+			} `json:"oncalls"`
+			More bool `json:"more"`
+		}
+		if err := b.get(ctx, "/oncalls", query, &reply); err != nil {
+			return nil, err
+		}
+		for _, entry := range reply.OnCalls {
+			shift := Shift{
+				System:           "pagerduty",
+				EscalationPolicy: entry.EscalationPolicy.Summary,
+				Level:            entry.EscalationLevel,
+				User:             entry.User.Summary,
+				Email:            entry.User.Email,
+				Start:            entry.Start,
+				End:              entry.End,
+			}
+			if entry.Schedule != nil {
+				shift.Schedule = entry.Schedule.Summary
+			}
+			shifts = append(shifts, shift)
+		}
+		if !reply.More {
+			break
+		}
+	}
+	return shifts, nil
+}
+
+type pagerDutyIncident struct {
+	ID             string              `json:"id"`
+	IncidentNumber int                 `json:"incident_number"`
+	Title          string              `json:"title"`
+	Status         string              `json:"status"` // triggered, acknowledged or resolved
+	Urgency        string              `json:"urgency"`
+	Priority       *pagerDutyReference `json:"priority"`
+	Service        pagerDutyReference  `json:"service"`
+	Assignments    []struct {
+		Assignee pagerDutyReference `json:"assignee"`
+	} `json:"assignments"`
+	CreatedAt *time.Time `json:"created_at"`
+	HTMLURL   string     `json:"html_url"`
+}
+
+func (i *pagerDutyIncident) normalize() Incident {
+	incident := Incident{
+		System:    "pagerduty",
+		ID:        i.ID,
+		Number:    strconv.Itoa(i.IncidentNumber),
+		Title:     i.Title,
+		Status:    i.Status,
+		Urgency:   i.Urgency,
+		Service:   i.Service.Summary,
+		CreatedAt: i.CreatedAt,
+		URL:       i.HTMLURL,
+	}
+	if i.Priority != nil {
+		incident.Priority = i.Priority.Summary
+	}
+	for _, assignment := range i.Assignments {
+		incident.Assignees = append(incident.Assignees, assignment.Assignee.Summary)
+	}
+	return incident
+}
+
+// Incidents lists incidents, newest first
+func (b *pagerDutyBackend) Incidents(ctx context.Context, opts IncidentOptions) ([]Incident, error) {
+	query := url.Values{
+		"statuses[]": opts.statuses(),
+		"sort_by":    {"created_at:desc"},
+		"limit":      {strconv.Itoa(opts.Limit)},
+	}
+	if opts.Since.IsZero() {
+		query.Set("date_range", "all")
+	} else {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339))
+		query.Set("until", time.Now().UTC().Format(time.RFC3339))
+	}
+	if opts.Service != "" {
+		ids, err := b.resolve(ctx, "services", opts.Service)
+		if err != nil {
+			return nil, err
+		}
+		query["service_ids[]"] = ids
+	}
+
+	var reply struct {
+		Incidents []pagerDutyIncident `json:"incidents"`
+	}
+	if err := b.get(ctx, "/incidents", query, &reply); err != nil {
+		return nil, err
+	}
+	incidents := make([]Incident, 0, len(reply.Incidents))
+	for i := range reply.Incidents {
+		incidents = append(incidents, reply.Incidents[i].normalize())
+	}
+	return incidents, nil
+}
+
+// write starts a request that changes an incident, as the user the tool call acts for when it is an email
+// and otherwise as pagerduty.from
+func (b *pagerDutyBackend) write(ctx context.Context) (*resty.Request, error) {
+	req := b.client.R().SetContext(ctx)
+	from := b.from
+	if user, ok := auth.OnBehalfOf(ctx); ok && strings.Contains(user, "@") {
+		from = user
+	}
+	if from != "" {
+		req.SetHeader("From", from)
+	}
+	if err := auth.ApplyOnBehalfOf(ctx, "pagerduty", req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Acknowledge acknowledges an incident and adds the note to it
+func (b *pagerDutyBackend) Acknowledge(ctx context.Context, id, note string) (*AckResult, error) {
+	req, err := b.write(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var reply struct {
+		Incident pagerDutyIncident `json:"incident"`
+	}
+	resp, err := req.
+		SetBody(map[string]interface{}{
+			"incident": map[string]string{"type": "incident_reference", "status": "acknowledged"},
+		}).
+		SetResult(&reply).
+		Put("/incidents/" + url.PathEscape(id))
+	if err != nil {
+		return nil, fmt.Errorf("pagerduty request failed: %w", err)
+	}
+	if resp.IsError() {
+		if resp.StatusCode() == 400 && b.from == "" {
+			return nil, fmt.Errorf("%w (set oncall.pagerduty.from to the email of a PagerDuty user)", apiError("pagerduty", resp))
+		}
+		return nil, apiError("pagerduty", resp)
+	}
+	result := &AckResult{System: "pagerduty", ID: reply.Incident.ID, Status: reply.Incident.Status, Title: reply.Incident.Title}
+
+	if note != "" {
+		req, err := b.write(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := req.
+			SetBody(map[string]interface{}{"note": map[string]string{"content": note}}).
+			Post(fmt.Sprintf("/incidents/%s/notes", url.PathEscape(id)))
+		if err != nil {
+			return nil, fmt.Errorf("incident acknowledged, but adding the note failed: %w", err)
+		}
+		if resp.IsError() {
+			return nil, fmt.Errorf("incident acknowledged, but adding the note failed: %w", apiError("pagerduty", resp))
+		}
+		result.NoteAdded = true
+	}
+	return result, nil
+}
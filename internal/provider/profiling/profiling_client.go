@@ -0,0 +1,693 @@
+package profiling
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/pprof/profile"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/file"
+	"dev-mcp/internal/provider/s3"
+)
+
+const defaultMaxProfileBytes = 64 << 20
+
+// Source locates a profile, either a local file or an S3 object
+type Source struct {
+	Path   string
+	Bucket string
+	Key    string
+}
+
+func (s Source) String() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	return "s3://" + s.Bucket + "/" + s.Key
+}
+
+// ProfilingClient loads pprof profiles from whitelisted directories or S3
+type ProfilingClient struct {
+	validator *file.FileSecurityValidator
+	s3Client  *s3.S3Client
+	maxBytes  int64
+}
+
+// NewProfilingClient creates a new profiling client; without directories the working directory is allowed
+func NewProfilingClient(cfg *config.ProfilingConfig, s3Cfg *config.S3Config) *ProfilingClient {
+	c := &ProfilingClient{maxBytes: defaultMaxProfileBytes, s3Client: s3.NewS3Client(s3Cfg)}
+	var dirs []string
+	if cfg != nil {
+		dirs = cfg.Directories
+		if cfg.MaxProfileBytes > 0 {
+			c.maxBytes = cfg.MaxProfileBytes
+		}
+	}
+	c.validator = file.NewFileSecurityValidator(dirs)
+	c.validator.SetReadOnly(true)
+	c.validator.SetMaxFileSize(c.maxBytes)
+	return c
+}
+
+// S3Available reports whether profiles can be read from S3
+func (c *ProfilingClient) S3Available() bool {
+	return c.s3Client.IsAvailable()
+}
+
+// Close releases resources held by the client
+func (c *ProfilingClient) Close() error {
+	return c.s3Client.Close()
+}
+
+// Load reads and parses a profile; gzip-compressed and legacy text formats are accepted
+func (c *ProfilingClient) Load(src Source) (*profile.Profile, error) {
+	var data []byte
+	switch {
+	case src.Path != "":
+		var err error
+		if data, err = c.validator.ReadWhitelisted(src.Path, 0); err != nil {
+			return nil, err
+		}
+	case src.Bucket != "" && src.Key != "":
+		if !c.S3Available() {
+			return nil, fmt.Errorf("s3 is not configured")
+		}
+		size, err := c.s3Client.GetObjectSize(src.Bucket, src.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get object size: %w", err)
+		}
+		if size > c.maxBytes {
+			return nil, fmt.Errorf("profile size (%d bytes) exceeds maximum allowed size (%d bytes)", size, c.maxBytes)
+		}
+		if data, err = c.s3Client.GetObjectBytes(src.Bucket, src.Key); err != nil {
+			return nil, fmt.Errorf("failed to download profile: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("path or bucket and key are required")
+	}
+
+	prof, err := profile.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s as a pprof profile: %w", src, err)
+	}
+	return prof, nil
+}
+
+// Options select and filter the samples being analyzed
+type Options struct {
+	SampleType  string // Sample type name such as cpu, alloc_space or inuse_space; the profile default when empty
+	Granularity string // functions (default), lines or files
+	Focus       string // Keep only samples with a frame matching this regexp
+	Ignore      string // Drop samples with a frame matching this regexp
+}
+
+// Analysis is a profile reduced to one sample value per stack, stacks as node keys leaf first
+type Analysis struct {
+	SampleType string
+	Unit       string
+	Total      int64
+	Samples    int
+	stacks     [][]string
+	values     []int64
+	files      map[string]string // node key -> source file
+}
+
+// ProfileInfo describes a profile's metadata
+type ProfileInfo struct {
+	SampleTypes []string `json:"sample_types"`
+	SampleType  string   `json:"sample_type"`
+	Unit        string   `json:"unit"`
+	Samples     int      `json:"samples"`
+	Total       int64    `json:"total"`
+	TotalText   string   `json:"total_text"`
+	Duration    string   `json:"duration,omitempty"`
+	Period      string   `json:"period,omitempty"`
+	CollectedAt string   `json:"collected_at,omitempty"`
+	Comments    []string `json:"comments,omitempty"`
+	// Average CPU cores busy over the profile duration (CPU profiles only)
+	AverageCores float64 `json:"average_cores,omitempty"`
+}
+
+// Analyze selects a sample type, applies focus/ignore filters and builds the stacks at the requested granularity
+func Analyze(prof *profile.Profile, opts Options) (*Analysis, error) {
+	index, err := sampleIndex(prof, opts.SampleType)
+	if err != nil {
+		return nil, err
+	}
+	var focus, ignore *regexp.Regexp
+	if opts.Focus != "" {
+		if focus, err = regexp.Compile(opts.Focus); err != nil {
+			return nil, fmt.Errorf("invalid focus pattern: %w", err)
+		}
+	}
+	if opts.Ignore != "" {
+		if ignore, err = regexp.Compile(opts.Ignore); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern: %w", err)
+		}
+	}
+	switch opts.Granularity {
+	case "", "functions", "lines", "files":
+	default:
+		return nil, fmt.Errorf("unsupported granularity %q (use functions, lines or files)", opts.Granularity)
+	}
+
+	a := &Analysis{
+		SampleType: prof.SampleType[index].Type,
+		Unit:       prof.SampleType[index].Unit,
+		files:      map[string]string{},
+	}
+	for _, sample := range prof.Sample {
+		value := sample.Value[index]
+		if value == 0 {
+			continue
+		}
+		var stack []string
+		matched := focus == nil
+		dropped := false
+		for _, loc := range sample.Location {
+			if len(loc.Line) == 0 {
+				name := fmt.Sprintf("0x%x", loc.Address)
+				if loc.Mapping != nil && loc.Mapping.File != "" {
+					name = filepath.Base(loc.Mapping.File) + " " + name
+				}
+				stack = append(stack, name)
+				continue
+			}
+			// Inlined functions come first, the function they were inlined into last
+			for _, line := range loc.Line {
+				if line.Function == nil {
+					continue
+				}
+				name := line.Function.Name
+				if focus != nil && focus.MatchString(name) {
+					matched = true
+				}
+				if ignore != nil && ignore.MatchString(name) {
+					dropped = true
+				}
+				key := nodeKey(line, opts.Granularity)
+				stack = append(stack, key)
+				if _, ok := a.files[key]; !ok && line.Function.Filename != "" {
+					a.files[key] = line.Function.Filename
+				}
+			}
+		}
+		if !matched || dropped || len(stack) == 0 {
+			continue
+		}
+		a.stacks = append(a.stacks, stack)
+		a.values = append(a.values, value)
+		a.Total += value
+		a.Samples++
+	}
+	return a, nil
+}
+
+// sampleIndex resolves a sample type name to its index; empty selects the profile default (the last type)
+func sampleIndex(prof *profile.Profile, name string) (int, error) {
+	if len(prof.SampleType) == 0 {
+		return 0, fmt.Errorf("the profile has no sample types")
+	}
+	if name == "" {
+		name = prof.DefaultSampleType
+	}
+	if name == "" {
+		return len(prof.SampleType) - 1, nil
+	}
+	var names []string
+	for i, st := range prof.SampleType {
+		if st.Type == name {
+			return i, nil
+		}
+		names = append(names, st.Type)
+	}
+	return 0, fmt.Errorf("unknown sample type %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+func nodeKey(line profile.Line, granularity string) string {
+	switch granularity {
+	case "lines":
+		return fmt.Sprintf("%s %s:%d", line.Function.Name, line.Function.Filename, line.Line)
+	case "files":
+		if line.Function.Filename != "" {
+			return line.Function.Filename
+		}
+	}
+	return line.Function.Name
+}
+
+// Info summarizes the profile's metadata for the selected sample type
+func Info(prof *profile.Profile, a *Analysis) ProfileInfo {
+	info := ProfileInfo{
+		SampleType: a.SampleType,
+		Unit:       a.Unit,
+		Samples:    a.Samples,
+		Total:      a.Total,
+		TotalText:  FormatValue(a.Total, a.Unit),
+		Comments:   prof.Comments,
+	}
+	for _, st := range prof.SampleType {
+		info.SampleTypes = append(info.SampleTypes, st.Type+" ("+st.Unit+")")
+	}
+	if prof.DurationNanos > 0 {
+		info.Duration = time.Duration(prof.DurationNanos).Round(time.Millisecond).String()
+		if a.Unit == "nanoseconds" {
+			info.AverageCores = float64(int(float64(a.Total)/float64(prof.DurationNanos)*100)) / 100
+		}
+	}
+	if prof.PeriodType != nil && prof.Period > 0 {
+		info.Period = FormatValue(prof.Period, prof.PeriodType.Unit) + " " + prof.PeriodType.Type
+	}
+	if prof.TimeNanos > 0 {
+		info.CollectedAt = time.Unix(0, prof.TimeNanos).UTC().Format(time.RFC3339)
+	}
+	return info
+}
+
+// Entry is one function, line or file with its flat (self) and cumulative values
+type Entry struct {
+	Name    string  `json:"name"`
+	File    string  `json:"file,omitempty"`
+	Flat    int64   `json:"flat"`
+	FlatPct float64 `json:"flat_pct"`
+	Cum     int64   `json:"cum"`
+	CumPct  float64 `json:"cum_pct"`
+	Text    string  `json:"text"` // flat and cum formatted in the profile's unit
+}
+
+// Top returns entries sorted by flat or cumulative value; recursion counts once toward cum
+func (a *Analysis) Top(sortBy string, limit int) []Entry {
+	flat, cum := a.nodeValues()
+	entries := make([]Entry, 0, len(cum))
+	for name, c := range cum {
+		entries = append(entries, a.entry(name, flat[name], c))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		x, y := entries[i], entries[j]
+		if sortBy == "cum" {
+			if abs(x.Cum) != abs(y.Cum) {
+				return abs(x.Cum) > abs(y.Cum)
+			}
+		} else if abs(x.Flat) != abs(y.Flat) {
+			return abs(x.Flat) > abs(y.Flat)
+		}
+		return x.Name < y.Name
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+func (a *Analysis) nodeValues() (map[string]int64, map[string]int64) {
+	flat := map[string]int64{}
+	cum := map[string]int64{}
+	for i, stack := range a.stacks {
+		value := a.values[i]
+		flat[stack[0]] += value
+		seen := make(map[string]bool, len(stack))
+		for _, name := range stack {
+			if !seen[name] {
+				seen[name] = true
+				cum[name] += value
+			}
+		}
+	}
+	return flat, cum
+}
+
+func (a *Analysis) entry(name string, flat, cum int64) Entry {
+	return Entry{
+		Name:    name,
+		File:    a.files[name],
+		Flat:    flat,
+		FlatPct: a.percent(flat),
+		Cum:     cum,
+		CumPct:  a.percent(cum),
+		Text:    fmt.Sprintf("flat %s, cum %s", FormatValue(flat, a.Unit), FormatValue(cum, a.Unit)),
+	}
+}
+
+func (a *Analysis) percent(value int64) float64 {
+	if a.Total == 0 {
+		return 0
+	}
+	return float64(int(float64(value)/float64(a.Total)*10000)) / 100
+}
+
+// DiffEntry compares a function, line or file between a base profile and the current one
+type DiffEntry struct {
+	Name      string  `json:"name"`
+	File      string  `json:"file,omitempty"`
+	BaseFlat  int64   `json:"base_flat"`
+	Flat      int64   `json:"flat"`
+	BaseCum   int64   `json:"base_cum"`
+	Cum       int64   `json:"cum"`
+	DeltaFlat int64   `json:"delta_flat"`
+	DeltaCum  int64   `json:"delta_cum"`
+	DeltaPct  float64 `json:"delta_pct"` // delta of the sorted value relative to the base total
+	Text      string  `json:"text"`
+}
+
+// Diff compares two analyses of the same sample type, sorted by the largest change in flat or cum
+func Diff(base, current *Analysis, sortBy string, limit int) []DiffEntry {
+	baseFlat, baseCum := base.nodeValues()
+	flat, cum := current.nodeValues()
+	names := map[string]bool{}
+	for name := range baseCum {
+		names[name] = true
+	}
+	for name := range cum {
+		names[name] = true
+	}
+
+	entries := make([]DiffEntry, 0, len(names))
+	for name := range names {
+		entry := DiffEntry{
+			Name:      name,
+			File:      current.files[name],
+			BaseFlat:  baseFlat[name],
+			Flat:      flat[name],
+			BaseCum:   baseCum[name],
+			Cum:       cum[name],
+			DeltaFlat: flat[name] - baseFlat[name],
+			DeltaCum:  cum[name] - baseCum[name],
+		}
+		if entry.File == "" {
+			entry.File = base.files[name]
+		}
+		delta := entry.DeltaFlat
+		if sortBy == "cum" {
+			delta = entry.DeltaCum
+		}
+		if delta == 0 {
+			continue
+		}
+		entry.DeltaPct = base.percent(delta)
+		entry.Text = fmt.Sprintf("flat %s -> %s, cum %s -> %s", FormatValue(entry.BaseFlat, base.Unit), FormatValue(entry.Flat, base.Unit),
+			FormatValue(entry.BaseCum, base.Unit), FormatValue(entry.Cum, base.Unit))
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		x, y := entries[i].DeltaFlat, entries[j].DeltaFlat
+		if sortBy == "cum" {
+			x, y = entries[i].DeltaCum, entries[j].DeltaCum
+		}
+		if abs(x) != abs(y) {
+			return abs(x) > abs(y)
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// GraphNode is a node of the call graph
+type GraphNode struct {
+	ID int `json:"id"`
+	Entry
+}
+
+// GraphEdge is a caller -> callee edge; indirect edges skip nodes that were pruned
+type GraphEdge struct {
+	From     int     `json:"from"`
+	To       int     `json:"to"`
+	Weight   int64   `json:"weight"`
+	Pct      float64 `json:"pct"`
+	Indirect bool    `json:"indirect,omitempty"`
+}
+
+// Graph is the pruned call graph
+type Graph struct {
+	Nodes        []GraphNode `json:"nodes"`
+	Edges        []GraphEdge `json:"edges"`
+	DroppedNodes int         `json:"dropped_nodes"`
+	DroppedEdges int         `json:"dropped_edges"`
+}
+
+// Graph builds the call graph keeping at most maxNodes nodes whose cum reaches nodeFraction of the total,
+// and edges whose weight reaches edgeFraction; stacks are reconnected across pruned nodes
+func (a *Analysis) Graph(maxNodes int, nodeFraction, edgeFraction float64) Graph {
+	entries := a.Top("cum", 0)
+	kept := map[string]int{}
+	var graph Graph
+	for _, entry := range entries {
+		if len(graph.Nodes) >= maxNodes || float64(abs(entry.Cum)) < nodeFraction*float64(abs(a.Total)) {
+			graph.DroppedNodes++
+			continue
+		}
+		id := len(graph.Nodes) + 1
+		kept[entry.Name] = id
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: id, Entry: entry})
+	}
+
+	type edgeKey struct {
+		from, to int
+		indirect bool
+	}
+	weights := map[edgeKey]int64{}
+	for i, stack := range a.stacks {
+		seen := map[edgeKey]bool{}
+		callee := 0
+		skipped := false
+		for _, name := range stack {
+			id, ok := kept[name]
+			if !ok {
+				skipped = callee != 0
+				continue
+			}
+			if callee != 0 && id != callee {
+				key := edgeKey{from: id, to: callee, indirect: skipped}
+				if !seen[key] {
+					seen[key] = true
+					weights[key] += a.values[i]
+				}
+			}
+			callee = id
+			skipped = false
+		}
+	}
+	for key, weight := range weights {
+		if float64(abs(weight)) < edgeFraction*float64(abs(a.Total)) {
+			graph.DroppedEdges++
+			continue
+		}
+		graph.Edges = append(graph.Edges, GraphEdge{From: key.from, To: key.to, Weight: weight, Pct: a.percent(weight), Indirect: key.indirect})
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		x, y := graph.Edges[i], graph.Edges[j]
+		if abs(x.Weight) != abs(y.Weight) {
+			return abs(x.Weight) > abs(y.Weight)
+		}
+		if x.From != y.From {
+			return x.From < y.From
+		}
+		return x.To < y.To
+	})
+	return graph
+}
+
+// HotPath is an aggregated call stack from root to leaf
+type HotPath struct {
+	Frames []string `json:"frames"` // root first; long stacks keep their root and leaf ends
+	Value  int64    `json:"value"`
+	Pct    float64  `json:"pct"`
+	Text   string   `json:"text"`
+}
+
+const (
+	pathHeadFrames = 4
+	pathTailFrames = 12
+)
+
+// HotPaths aggregates identical stacks (recursion collapsed) and returns the heaviest
+func (a *Analysis) HotPaths(limit int) []HotPath {
+	totals := map[string]int64{}
+	for i, stack := range a.stacks {
+		var frames []string
+		for j := len(stack) - 1; j >= 0; j-- {
+			if len(frames) > 0 && frames[len(frames)-1] == stack[j] {
+				continue
+			}
+			frames = append(frames, stack[j])
+		}
+		totals[strings.Join(frames, "\x00")] += a.values[i]
+	}
+	paths := make([]HotPath, 0, len(totals))
+	for key, value := range totals {
+		frames := strings.Split(key, "\x00")
+		if len(frames) > pathHeadFrames+pathTailFrames {
+			elided := fmt.Sprintf("... %d frames ...", len(frames)-pathHeadFrames-pathTailFrames)
+			frames = append(append(frames[:pathHeadFrames:pathHeadFrames], elided), frames[len(frames)-pathTailFrames:]...)
+		}
+		paths = append(paths, HotPath{Frames: frames, Value: value, Pct: a.percent(value), Text: FormatValue(value, a.Unit)})
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if abs(paths[i].Value) != abs(paths[j].Value) {
+			return abs(paths[i].Value) > abs(paths[j].Value)
+		}
+		return strings.Join(paths[i].Frames, ";") < strings.Join(paths[j].Frames, ";")
+	})
+	if limit > 0 && len(paths) > limit {
+		paths = paths[:limit]
+	}
+	return paths
+}
+
+// Category is the share of samples with at least one frame in a well-known area (GC, locking, ...)
+type Category struct {
+	Name  string  `json:"name"`
+	Value int64   `json:"value"`
+	Pct   float64 `json:"pct"`
+}
+
+var categories = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"garbage collection", regexp.MustCompile(`^runtime\.(gcBgMarkWorker|gcDrain|gcAssistAlloc|markroot|scanobject|scanblock|greyobject|bgsweep|bgscavenge|sweepone|gcStart|gcMarkDone|wbBufFlush)`)},
+	{"memory allocation", regexp.MustCompile(`^runtime\.(mallocgc|newobject|newarray|makeslice|growslice|makemap|rawstring|rawbyteslice|concatstrings|slicebytetostring|stringtoslicebyte)`)},
+	{"scheduler", regexp.MustCompile(`^runtime\.(schedule|findRunnable|findrunnable|park_m|goschedImpl|gopreempt_m|stealWork|runqgrab|notesleep|futexsleep|netpoll|checkTimers|mcall)$`)},
+	{"syscalls", regexp.MustCompile(`^(syscall|internal/syscall/[^.]+|golang\.org/x/sys/[^.]+)\.|^runtime\.(cgocall|entersyscall|exitsyscall)`)},
+	{"locking", regexp.MustCompile(`^sync\.|^runtime\.(semacquire|semrelease|lock2|unlock2|lockWithRank)`)},
+	{"maps and hashing", regexp.MustCompile(`^(runtime\.(mapaccess|mapassign|mapdelete|mapiter|memhash|strhash|aeshash)|internal/runtime/maps\.)`)},
+	{"serialization", regexp.MustCompile(`^(encoding/(json|xml|gob|csv)|google\.golang\.org/protobuf|github\.com/golang/protobuf|gopkg\.in/yaml|github\.com/goccy/go-json|github\.com/json-iterator)`)},
+	{"reflection", regexp.MustCompile(`^reflect\.`)},
+	{"regular expressions", regexp.MustCompile(`^regexp\.`)},
+	{"logging and formatting", regexp.MustCompile(`^(fmt\.|log\.|log/slog\.|go\.uber\.org/zap|github\.com/sirupsen/logrus|github\.com/rs/zerolog)`)},
+	{"crypto and TLS", regexp.MustCompile(`^crypto/`)},
+	{"compression", regexp.MustCompile(`^(compress/|github\.com/klauspost/compress)`)},
+	{"network and HTTP", regexp.MustCompile(`^(net\.|net/http|internal/poll\.|google\.golang\.org/grpc)`)},
+	{"database", regexp.MustCompile(`^(database/sql|github\.com/(lib/pq|jackc/pgx|go-sql-driver/mysql)|go\.mongodb\.org|github\.com/redis)`)},
+}
+
+// Categories returns the inclusive share of each well-known area; shares overlap
+func (a *Analysis) Categories() []Category {
+	values := make([]int64, len(categories))
+	for i, stack := range a.stacks {
+		for c, category := range categories {
+			for _, name := range stack {
+				if category.pattern.MatchString(name) {
+					values[c] += a.values[i]
+					break
+				}
+			}
+		}
+	}
+	var result []Category
+	for c, value := range values {
+		if value != 0 {
+			result = append(result, Category{Name: categories[c].name, Value: value, Pct: a.percent(value)})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Value > result[j].Value })
+	return result
+}
+
+// Findings turns the top entries and category shares into short observations
+func (a *Analysis) Findings(top []Entry, cats []Category) []string {
+	var findings []string
+	subject := "samples"
+	switch a.Unit {
+	case "nanoseconds":
+		subject = "CPU time"
+	case "bytes":
+		subject = strings.ReplaceAll(a.SampleType, "_", " ")
+	}
+
+	if len(top) > 0 && top[0].FlatPct >= 20 {
+		findings = append(findings, fmt.Sprintf("%s alone accounts for %.1f%% of %s", top[0].Name, top[0].FlatPct, subject))
+	}
+
+	thresholds := map[string]float64{
+		"garbage collection":     15,
+		"memory allocation":      15,
+		"scheduler":              15,
+		"syscalls":               25,
+		"locking":                10,
+		"maps and hashing":       15,
+		"serialization":          20,
+		"reflection":             10,
+		"regular expressions":    10,
+		"logging and formatting": 10,
+		"crypto and TLS":         25,
+		"compression":            20,
+	}
+	advice := map[string]string{
+		"garbage collection":     "reduce allocations (check the alloc_space profile) or tune GOGC/GOMEMLIMIT",
+		"memory allocation":      "reuse buffers (sync.Pool), preallocate slices and maps, avoid string/[]byte conversions",
+		"scheduler":              "many short-lived goroutines or channel hand-offs; consider batching or worker pools",
+		"syscalls":               "many small reads/writes; buffer I/O or batch requests",
+		"locking":                "possible mutex contention; collect a mutex or block profile",
+		"maps and hashing":       "map-heavy hot path; consider presizing maps or slice-based lookups",
+		"serialization":          "encoding/decoding is hot; avoid re-encoding, cache results or use a faster codec",
+		"reflection":             "reflection in a hot path; cache reflect lookups or use generated code",
+		"regular expressions":    "regexps in a hot path; compile once at package level or use strings functions",
+		"logging and formatting": "logging/fmt is hot; lower log verbosity or avoid fmt.Sprintf in loops",
+		"crypto and TLS":         "TLS handshakes or hashing are hot; check connection reuse and keep-alives",
+		"compression":            "compression is hot; lower the level or skip compressing small payloads",
+	}
+	if a.Unit == "nanoseconds" {
+		for _, category := range cats {
+			if limit, ok := thresholds[category.Name]; ok && category.Pct >= limit {
+				findings = append(findings, fmt.Sprintf("%s: %.1f%% of CPU time; %s", category.Name, category.Pct, advice[category.Name]))
+			}
+		}
+	}
+	if a.Unit == "bytes" && len(top) > 0 && top[0].FlatPct < 20 {
+		findings = append(findings, fmt.Sprintf("top allocation site %s: %s (%.1f%%)", top[0].Name, FormatValue(top[0].Flat, a.Unit), top[0].FlatPct))
+	}
+	return findings
+}
+
+// FormatValue renders a sample value in its unit
+func FormatValue(value int64, unit string) string {
+	switch unit {
+	case "nanoseconds":
+		d := time.Duration(value)
+		switch {
+		case abs(value) >= int64(time.Second):
+			return d.Round(10 * time.Millisecond).String()
+		case abs(value) >= int64(time.Millisecond):
+			return d.Round(10 * time.Microsecond).String()
+		}
+		return d.String()
+	case "bytes":
+		return formatBytes(value)
+	case "count", "":
+		return fmt.Sprintf("%d", value)
+	}
+	return fmt.Sprintf("%d %s", value, unit)
+}
+
+func formatBytes(value int64) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	const unit = 1024
+	if value < unit {
+		return fmt.Sprintf("%s%dB", sign, value)
+	}
+	div, exp := int64(unit), 0
+	for n := value / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%s%.2f%cB", sign, float64(value)/float64(div), "KMGTPE"[exp])
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
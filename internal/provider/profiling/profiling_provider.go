@@ -0,0 +1,368 @@
+package profiling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/google/pprof/profile"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// ProfilingProvider analyzes pprof CPU, heap, allocation, mutex and block profiles
+type ProfilingProvider struct {
+	*provider.BaseProvider
+	client *ProfilingClient
+}
+
+// NewProfilingProvider creates a new profiling provider with config.
+// S3 settings are used for profiles stored in S3.
+func NewProfilingProvider(cfg *config.ProfilingConfig, s3Cfg *config.S3Config) *ProfilingProvider {
+	p := &ProfilingProvider{
+		BaseProvider: provider.NewBaseProvider("profiling"),
+		client:       NewProfilingClient(cfg, s3Cfg),
+	}
+
+	// Profiles are read from local directories (the working directory by default), so the tools are always available
+	p.SetAvailable(true)
+	log.Printf("✓ Profiling provider initialized successfully (S3: %t)", p.client.S3Available())
+
+	return p
+}
+
+// Test tests the profiling configuration (for ProviderClient interface compatibility)
+func (p *ProfilingProvider) Test(config interface{}) error {
+	return nil
+}
+
+// AddTools adds profiling tools to the MCP server (for ProviderClient interface compatibility)
+func (p *ProfilingProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the profiling provider
+func (p *ProfilingProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds profiling tools to the MCP server
+func (p *ProfilingProvider) addToolsToServer(server *mcp.Server) {
+	tools := []entity.ToolDefinition{
+		p.createTopTool(),
+		p.createGraphTool(),
+		p.createHotspotsTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered profiling tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All profiling tools registered successfully")
+}
+
+// profileArgs are the arguments shared by all profiling tools
+type profileArgs struct {
+	Path        string `json:"path,omitempty"`
+	Bucket      string `json:"bucket,omitempty"`
+	Key         string `json:"key,omitempty"`
+	SampleType  string `json:"sample_type,omitempty"`
+	Focus       string `json:"focus,omitempty"`
+	Ignore      string `json:"ignore,omitempty"`
+	Granularity string `json:"granularity,omitempty"`
+}
+
+// profileSchemaProperties are the schema properties shared by all profiling tools
+const profileSchemaProperties = `
+				"path": {
+					"type": "string",
+					"description": "Local profile file, e.g. cpu.pprof or heap.pb.gz (within the profiling directories)"
+				},
+				"bucket": {
+					"type": "string",
+					"description": "S3 bucket holding the profile, instead of path"
+				},
+				"key": {
+					"type": "string",
+					"description": "S3 object key of the profile"
+				},
+				"sample_type": {
+					"type": "string",
+					"description": "Sample type to analyze, e.g. cpu or samples (CPU), inuse_space, inuse_objects, alloc_space or alloc_objects (heap), contentions or delay (mutex/block); the profile default when omitted"
+				},
+				"focus": {
+					"type": "string",
+					"description": "Regexp; keep only samples with a function matching it"
+				},
+				"ignore": {
+					"type": "string",
+					"description": "Regexp; drop samples with a function matching it"
+				},
+				"granularity": {
+					"type": "string",
+					"enum": ["functions", "lines", "files"],
+					"description": "Aggregate by function, source line or file",
+					"default": "functions"
+				}`
+
+// load reads a profile and analyzes it with the shared arguments
+func (p *ProfilingProvider) load(args profileArgs) (*profile.Profile, *Analysis, error) {
+	prof, err := p.client.Load(Source{Path: args.Path, Bucket: args.Bucket, Key: args.Key})
+	if err != nil {
+		return nil, nil, err
+	}
+	analysis, err := Analyze(prof, Options{
+		SampleType:  args.SampleType,
+		Granularity: args.Granularity,
+		Focus:       args.Focus,
+		Ignore:      args.Ignore,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return prof, analysis, nil
+}
+
+// createTopTool creates the top functions tool
+func (p *ProfilingProvider) createTopTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "pprof_top",
+		Description: "List the top functions (or lines/files) of a pprof profile by flat (self) or cumulative value, like `go tool pprof -top`. With a base profile the result is a diff showing what got slower or allocates more",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {` + profileSchemaProperties + `,
+				"sort": {
+					"type": "string",
+					"enum": ["flat", "cum"],
+					"description": "Sort by self value or by value including callees",
+					"default": "flat"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of entries to return",
+					"default": 20
+				},
+				"base_path": {
+					"type": "string",
+					"description": "Local base profile to diff against (like -diff_base)"
+				},
+				"base_key": {
+					"type": "string",
+					"description": "S3 key of the base profile, in the same bucket"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			profileArgs
+			Sort     string `json:"sort,omitempty"`
+			Limit    int    `json:"limit,omitempty"`
+			BasePath string `json:"base_path,omitempty"`
+			BaseKey  string `json:"base_key,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Limit <= 0 {
+			args.Limit = 20
+		}
+		if args.Sort != "" && args.Sort != "flat" && args.Sort != "cum" {
+			return p.createErrorResult(fmt.Errorf("sort must be flat or cum")), nil
+		}
+
+		prof, analysis, err := p.load(args.profileArgs)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		if args.BasePath == "" && args.BaseKey == "" {
+			return p.formatJSONResult(map[string]interface{}{
+				"profile": Info(prof, analysis),
+				"top":     analysis.Top(args.Sort, args.Limit),
+			}), nil
+		}
+
+		baseArgs := args.profileArgs
+		baseArgs.Path, baseArgs.Key = args.BasePath, args.BaseKey
+		if args.BaseKey == "" {
+			baseArgs.Bucket = ""
+		}
+		// The base is analyzed with the current profile's sample type so the values are comparable
+		baseArgs.SampleType = analysis.SampleType
+		baseProf, base, err := p.load(baseArgs)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("base profile: %w", err)), nil
+		}
+		if base.Unit != analysis.Unit {
+			return p.createErrorResult(fmt.Errorf("base profile unit %s does not match %s", base.Unit, analysis.Unit)), nil
+		}
+
+		result := map[string]interface{}{
+			"profile": Info(prof, analysis),
+			"base":    Info(baseProf, base),
+			"delta":   FormatValue(analysis.Total-base.Total, analysis.Unit),
+			"changes": Diff(base, analysis, args.Sort, args.Limit),
+		}
+		if prof.DurationNanos > 0 && baseProf.DurationNanos > 0 && prof.DurationNanos != baseProf.DurationNanos {
+			result["note"] = "the profiles cover different durations; compare percentages rather than absolute values"
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createGraphTool creates the call graph tool
+func (p *ProfilingProvider) createGraphTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "pprof_graph",
+		Description: "Return the call graph of a pprof profile as JSON nodes (flat and cumulative values) and caller -> callee edges, pruned like `go tool pprof -nodefraction/-edgefraction`; edges marked indirect skip pruned nodes",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {` + profileSchemaProperties + `,
+				"max_nodes": {
+					"type": "integer",
+					"description": "Maximum number of nodes, highest cumulative value first",
+					"default": 80
+				},
+				"node_fraction": {
+					"type": "number",
+					"description": "Drop nodes below this fraction of the total",
+					"default": 0.005
+				},
+				"edge_fraction": {
+					"type": "number",
+					"description": "Drop edges below this fraction of the total",
+					"default": 0.001
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			profileArgs
+			MaxNodes     int      `json:"max_nodes,omitempty"`
+			NodeFraction *float64 `json:"node_fraction,omitempty"`
+			EdgeFraction *float64 `json:"edge_fraction,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.MaxNodes <= 0 {
+			args.MaxNodes = 80
+		}
+		if args.MaxNodes > 500 {
+			args.MaxNodes = 500
+		}
+		nodeFraction, edgeFraction := 0.005, 0.001
+		if args.NodeFraction != nil {
+			nodeFraction = *args.NodeFraction
+		}
+		if args.EdgeFraction != nil {
+			edgeFraction = *args.EdgeFraction
+		}
+
+		prof, analysis, err := p.load(args.profileArgs)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"profile": Info(prof, analysis),
+			"graph":   analysis.Graph(args.MaxNodes, nodeFraction, edgeFraction),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createHotspotsTool creates the hotspot report tool
+func (p *ProfilingProvider) createHotspotsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "pprof_hotspots",
+		Description: "Summarize a pprof profile into a hotspot report: profile metadata (duration, total, average CPU cores), top functions by self and cumulative value, the heaviest call paths, time spent in well-known areas (GC, allocation, scheduler, syscalls, locking, serialization, regexps, logging, crypto, ...) and findings with suggestions",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {` + profileSchemaProperties + `,
+				"limit": {
+					"type": "integer",
+					"description": "Number of top functions and call paths to include",
+					"default": 10
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			profileArgs
+			Limit int `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Limit <= 0 {
+			args.Limit = 10
+		}
+
+		prof, analysis, err := p.load(args.profileArgs)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if analysis.Total == 0 {
+			return p.formatJSONResult(map[string]interface{}{
+				"profile": Info(prof, analysis),
+				"message": "no samples match",
+			}), nil
+		}
+
+		topFlat := analysis.Top("flat", args.Limit)
+		categories := analysis.Categories()
+		return p.formatJSONResult(map[string]interface{}{
+			"profile":    Info(prof, analysis),
+			"top_flat":   topFlat,
+			"top_cum":    analysis.Top("cum", args.Limit),
+			"hot_paths":  analysis.HotPaths(args.Limit),
+			"categories": categories,
+			"findings":   analysis.Findings(topFlat, categories),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *ProfilingProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Profiling Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *ProfilingProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that ProfilingProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*ProfilingProvider)(nil)
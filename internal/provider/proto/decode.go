@@ -0,0 +1,396 @@
+package proto
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxDecodeDepth bounds message nesting when decoding
+const maxDecodeDepth = 64
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+// FindMessage returns a message by full name, or by a shorter name that matches exactly one message
+func (s *Set) FindMessage(name string) (*Message, error) {
+	name = strings.TrimPrefix(name, ".")
+	if m := s.Message(name); m != nil {
+		return m, nil
+	}
+	var matches []*Message
+	var walk func(messages []*Message)
+	walk = func(messages []*Message) {
+		for _, m := range messages {
+			if !m.MapEntry && strings.HasSuffix(m.FullName, "."+name) {
+				matches = append(matches, m)
+			}
+			walk(m.Messages)
+		}
+	}
+	for _, f := range s.Files {
+		walk(f.Messages)
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("message %s not found", name)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m.FullName)
+	}
+	return nil, fmt.Errorf("message %s is ambiguous, use the full name of one of: %s", name, strings.Join(names, ", "))
+}
+
+// Decode decodes binary protobuf data as message m into JSON-friendly values, keyed by field name.
+// Enums are decoded to their names, bytes to base64, Timestamp and Duration to strings, and fields
+// missing from the schema are kept under their numbers.
+func (s *Set) Decode(m *Message, data []byte) (map[string]interface{}, error) {
+	return s.decodeMessage(m, data, 0)
+}
+
+func (s *Set) decodeMessage(m *Message, data []byte, depth int) (map[string]interface{}, error) {
+	if depth > maxDecodeDepth {
+		return nil, fmt.Errorf("messages nested deeper than %d", maxDecodeDepth)
+	}
+	fields := make(map[int]*Field, len(m.Fields))
+	for _, f := range m.Fields {
+		fields[f.Number] = f
+	}
+
+	result := map[string]interface{}{}
+	for len(data) > 0 {
+		number, wireType, raw, rest, err := readField(data)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+
+		f, ok := fields[number]
+		if !ok {
+			appendValue(result, strconv.Itoa(number), decodeUnknown(wireType, raw, depth))
+			continue
+		}
+
+		switch {
+		case f.Kind == "map":
+			if wireType != wireBytes {
+				return nil, wireTypeError(f, wireType)
+			}
+			entry, err := s.decodeMessage(f.entry, raw, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			values, _ := result[f.Name].(map[string]interface{})
+			if values == nil {
+				values = map[string]interface{}{}
+				result[f.Name] = values
+			}
+			key := entry["key"]
+			if key == nil {
+				key = ""
+			}
+			values[fmt.Sprint(key)] = entry["value"]
+		case f.Kind == "message":
+			if wireType != wireBytes {
+				return nil, wireTypeError(f, wireType)
+			}
+			value, err := s.decodeNested(f.TypeName, raw, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			setValue(result, f, value)
+		case wireType == wireBytes && f.Label == "repeated" && f.Type != "string" && f.Type != "bytes":
+			// Packed repeated scalars and enums
+			for len(raw) > 0 {
+				value, n, err := s.decodeScalar(f, packedWireType(f.Type), raw)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", f.Name, err)
+				}
+				raw = raw[n:]
+				setValue(result, f, value)
+			}
+		default:
+			value, _, err := s.decodeScalar(f, wireType, raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			setValue(result, f, value)
+		}
+	}
+	return result, nil
+}
+
+// decodeNested decodes a message field, formatting well-known types as their JSON mapping does
+func (s *Set) decodeNested(typeName string, data []byte, depth int) (interface{}, error) {
+	switch typeName {
+	case "google.protobuf.Timestamp", "google.protobuf.Duration":
+		seconds, nanos, err := secondsNanos(data)
+		if err != nil {
+			return nil, err
+		}
+		if typeName == "google.protobuf.Timestamp" {
+			return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano), nil
+		}
+		return (time.Duration(seconds)*time.Second + time.Duration(nanos)).String(), nil
+	}
+
+	m := s.Message(typeName)
+	if m == nil {
+		// Types of imports that were not found are decoded by field number
+		return decodeUnknown(wireBytes, data, depth), nil
+	}
+	return s.decodeMessage(m, data, depth)
+}
+
+// decodeScalar decodes one scalar or enum value, returning the bytes consumed from raw
+func (s *Set) decodeScalar(f *Field, wireType int, raw []byte) (interface{}, int, error) {
+	if f.Kind == "enum" {
+		if wireType != wireVarint {
+			return nil, 0, wireTypeError(f, wireType)
+		}
+		v, n := binary.Uvarint(raw)
+		if n <= 0 {
+			return nil, 0, errTruncated
+		}
+		number := int(int32(v))
+		if e := s.Enum(f.TypeName); e != nil {
+			for _, value := range e.Values {
+				if value.Number == number {
+					return value.Name, n, nil
+				}
+			}
+		}
+		return number, n, nil
+	}
+
+	if packedWireType(f.Type) != wireType {
+		return nil, 0, wireTypeError(f, wireType)
+	}
+	switch wireType {
+	case wireVarint:
+		v, n := binary.Uvarint(raw)
+		if n <= 0 {
+			return nil, 0, errTruncated
+		}
+		switch f.Type {
+		case "int32":
+			return int32(v), n, nil
+		case "int64":
+			return int64(v), n, nil
+		case "uint32":
+			return uint32(v), n, nil
+		case "sint32":
+			return int32(uint32(v)>>1) ^ -int32(v&1), n, nil
+		case "sint64":
+			return int64(v>>1) ^ -int64(v&1), n, nil
+		case "bool":
+			return v != 0, n, nil
+		}
+		return v, n, nil
+	case wireFixed32:
+		if len(raw) < 4 {
+			return nil, 0, errTruncated
+		}
+		v := binary.LittleEndian.Uint32(raw)
+		switch f.Type {
+		case "sfixed32":
+			return int32(v), 4, nil
+		case "float":
+			return jsonFloat(float64(math.Float32frombits(v))), 4, nil
+		}
+		return v, 4, nil
+	case wireFixed64:
+		if len(raw) < 8 {
+			return nil, 0, errTruncated
+		}
+		v := binary.LittleEndian.Uint64(raw)
+		switch f.Type {
+		case "sfixed64":
+			return int64(v), 8, nil
+		case "double":
+			return jsonFloat(math.Float64frombits(v)), 8, nil
+		}
+		return v, 8, nil
+	}
+	if f.Type == "string" {
+		return string(raw), len(raw), nil
+	}
+	return base64.StdEncoding.EncodeToString(raw), len(raw), nil
+}
+
+// packedWireType is the wire type of a scalar type's values
+func packedWireType(scalar string) int {
+	switch scalar {
+	case "fixed32", "sfixed32", "float":
+		return wireFixed32
+	case "fixed64", "sfixed64", "double":
+		return wireFixed64
+	case "string", "bytes":
+		return wireBytes
+	}
+	return wireVarint
+}
+
+// jsonFloat keeps NaN and infinities, which JSON numbers cannot hold, as strings
+func jsonFloat(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
+
+// decodeUnknown decodes a field without a schema: varints and fixed values as numbers, and
+// length-delimited values as text, a nested message, or base64
+func decodeUnknown(wireType int, raw []byte, depth int) interface{} {
+	switch wireType {
+	case wireVarint:
+		v, _ := binary.Uvarint(raw)
+		return v
+	case wireFixed32:
+		return binary.LittleEndian.Uint32(raw)
+	case wireFixed64:
+		return binary.LittleEndian.Uint64(raw)
+	}
+	if utf8.Valid(raw) && printable(raw) {
+		return string(raw)
+	}
+	if depth < maxDecodeDepth {
+		if nested, ok := decodeUnknownMessage(raw, depth+1); ok {
+			return nested
+		}
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func decodeUnknownMessage(data []byte, depth int) (map[string]interface{}, bool) {
+	result := map[string]interface{}{}
+	for len(data) > 0 {
+		number, wireType, raw, rest, err := readField(data)
+		if err != nil {
+			return nil, false
+		}
+		data = rest
+		appendValue(result, strconv.Itoa(number), decodeUnknown(wireType, raw, depth))
+	}
+	return result, len(result) > 0
+}
+
+func printable(b []byte) bool {
+	for _, r := range string(b) {
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// readField splits the next field off data. For varints raw holds the encoded varint, for fixed
+// values their bytes, and for length-delimited values their contents.
+func readField(data []byte) (number, wireType int, raw, rest []byte, err error) {
+	tag, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, 0, nil, nil, errTruncated
+	}
+	number, wireType = int(tag>>3), int(tag&7)
+	if number <= 0 {
+		return 0, 0, nil, nil, fmt.Errorf("invalid field number %d", number)
+	}
+	data = data[n:]
+
+	switch wireType {
+	case wireVarint:
+		_, n = binary.Uvarint(data)
+		if n <= 0 {
+			return 0, 0, nil, nil, errTruncated
+		}
+		return number, wireType, data[:n], data[n:], nil
+	case wireFixed64, wireFixed32:
+		size := 8
+		if wireType == wireFixed32 {
+			size = 4
+		}
+		if len(data) < size {
+			return 0, 0, nil, nil, errTruncated
+		}
+		return number, wireType, data[:size], data[size:], nil
+	case wireBytes:
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return 0, 0, nil, nil, errTruncated
+		}
+		end := n + int(size)
+		return number, wireType, data[n:end], data[end:], nil
+	}
+	return 0, 0, nil, nil, fmt.Errorf("field %d: unsupported wire type %d (groups are not supported)", number, wireType)
+}
+
+// secondsNanos reads the seconds (1) and nanos (2) fields of a Timestamp or Duration
+func secondsNanos(data []byte) (int64, int64, error) {
+	var seconds, nanos int64
+	for len(data) > 0 {
+		number, wireType, raw, rest, err := readField(data)
+		if err != nil {
+			return 0, 0, err
+		}
+		data = rest
+		if wireType != wireVarint {
+			continue
+		}
+		v, _ := binary.Uvarint(raw)
+		switch number {
+		case 1:
+			seconds = int64(v)
+		case 2:
+			nanos = int64(int32(v))
+		}
+	}
+	return seconds, nanos, nil
+}
+
+// setValue stores a field value; repeated fields collect their values and for others the last value wins
+func setValue(result map[string]interface{}, f *Field, value interface{}) {
+	if f.Label != "repeated" {
+		result[f.Name] = value
+		return
+	}
+	values, _ := result[f.Name].([]interface{})
+	result[f.Name] = append(values, value)
+}
+
+// appendValue stores a value of an unknown field, turning repeated occurrences into a list
+func appendValue(result map[string]interface{}, key string, value interface{}) {
+	existing, ok := result[key]
+	if !ok {
+		result[key] = value
+		return
+	}
+	if values, ok := existing.([]interface{}); ok {
+		result[key] = append(values, value)
+		return
+	}
+	result[key] = []interface{}{existing, value}
+}
+
+func wireTypeError(f *Field, wireType int) error {
+	return fmt.Errorf("field %s: wire type %d does not match type %s", f.Name, wireType, f.Type)
+}
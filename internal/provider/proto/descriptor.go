@@ -0,0 +1,245 @@
+package proto
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The descriptor.proto field numbers and enum values written below are fixed by the protobuf
+// wire format, so a FileDescriptorSet can be encoded without the protobuf runtime.
+
+var fieldTypes = map[string]uint64{
+	"double": 1, "float": 2, "int64": 3, "uint64": 4, "int32": 5, "fixed64": 6, "fixed32": 7,
+	"bool": 8, "string": 9, "bytes": 12, "uint32": 13, "sfixed32": 15, "sfixed64": 16,
+	"sint32": 17, "sint64": 18,
+}
+
+const (
+	typeMessage = 11
+	typeEnum    = 14
+
+	labelOptional = 1
+	labelRequired = 2
+	labelRepeated = 3
+)
+
+// fileOptionFields maps the FileOptions fields worth keeping in a descriptor set to their numbers
+var fileOptionFields = map[string]int{
+	"java_package":         1,
+	"java_outer_classname": 8,
+	"go_package":           11,
+	"objc_class_prefix":    36,
+	"csharp_namespace":     37,
+	"swift_prefix":         39,
+	"php_namespace":        41,
+	"ruby_package":         45,
+}
+
+// encoder appends protobuf wire-format fields
+type encoder []byte
+
+func (e *encoder) varint(v uint64) {
+	for v >= 0x80 {
+		*e = append(*e, byte(v)|0x80)
+		v >>= 7
+	}
+	*e = append(*e, byte(v))
+}
+
+func (e *encoder) tag(field int, wireType uint64) {
+	e.varint(uint64(field)<<3 | wireType)
+}
+
+func (e *encoder) uint(field int, v uint64) {
+	e.tag(field, 0)
+	e.varint(v)
+}
+
+// int32 encodes negative values as ten-byte varints, as protobuf does
+func (e *encoder) int32(field int, v int) {
+	e.uint(field, uint64(int64(v)))
+}
+
+func (e *encoder) bool(field int, v bool) {
+	if v {
+		e.uint(field, 1)
+	}
+}
+
+func (e *encoder) bytes(field int, b []byte) {
+	e.tag(field, 2)
+	e.varint(uint64(len(b)))
+	*e = append(*e, b...)
+}
+
+func (e *encoder) string(field int, s string) {
+	if s != "" {
+		e.bytes(field, []byte(s))
+	}
+}
+
+// EncodeDescriptorSet encodes files as a google.protobuf.FileDescriptorSet, the format of
+// protoc --descriptor_set_out and grpcurl -protoset. Source info (comments) is not included.
+func EncodeDescriptorSet(files []*File) []byte {
+	var set encoder
+	for _, f := range files {
+		set.bytes(1, encodeFile(f))
+	}
+	return set
+}
+
+func encodeFile(f *File) []byte {
+	var e encoder
+	e.string(1, f.Path)
+	e.string(2, f.Package)
+	for _, imp := range f.Imports {
+		e.bytes(3, []byte(imp))
+	}
+	for _, i := range f.Public {
+		e.int32(10, i)
+	}
+	for _, i := range f.Weak {
+		e.int32(11, i)
+	}
+	for _, m := range f.Messages {
+		e.bytes(4, encodeMessage(m, f.Syntax))
+	}
+	for _, en := range f.Enums {
+		e.bytes(5, encodeEnum(en))
+	}
+	for _, s := range f.Services {
+		e.bytes(6, encodeService(s))
+	}
+	var opts encoder
+	for _, opt := range f.Options {
+		if field, ok := fileOptionFields[opt.Name]; ok {
+			opts.string(field, opt.Value)
+		}
+		if opt.Name == "java_multiple_files" {
+			opts.bool(10, opt.Value == "true")
+		}
+	}
+	if len(opts) > 0 {
+		e.bytes(8, opts)
+	}
+	// protoc leaves syntax unset for proto2
+	if f.Syntax == "proto3" {
+		e.string(12, f.Syntax)
+	}
+	return e
+}
+
+func encodeMessage(m *Message, syntax string) []byte {
+	var e encoder
+	e.string(1, m.Name)
+
+	// proto3 optional fields get a synthetic oneof each, declared after the real ones
+	oneofs := append([]string(nil), m.Oneofs...)
+	for _, f := range m.Fields {
+		var field encoder
+		field.string(1, f.Name)
+		field.int32(3, f.Number)
+		label := uint64(labelOptional)
+		switch f.Label {
+		case "required":
+			label = labelRequired
+		case "repeated":
+			label = labelRepeated
+		}
+		field.uint(4, label)
+		switch {
+		case f.Kind == "message" || f.Kind == "map":
+			field.uint(5, typeMessage)
+			field.string(6, "."+f.TypeName)
+		case f.Kind == "enum":
+			field.uint(5, typeEnum)
+			field.string(6, "."+f.TypeName)
+		default:
+			field.uint(5, fieldTypes[f.Type])
+		}
+		oneofIndex := f.oneofIndex
+		proto3Optional := syntax == "proto3" && f.Label == "optional" && !m.MapEntry
+		if proto3Optional {
+			oneofIndex = len(oneofs)
+			oneofs = append(oneofs, "_"+f.Name)
+		}
+		if oneofIndex >= 0 {
+			field.int32(9, oneofIndex)
+		}
+		field.string(10, jsonName(f.Name))
+		if f.Deprecated {
+			var opts encoder
+			opts.bool(3, true)
+			field.bytes(8, opts)
+		}
+		field.bool(17, proto3Optional)
+		e.bytes(2, field)
+	}
+	for _, nested := range m.Messages {
+		e.bytes(3, encodeMessage(nested, syntax))
+	}
+	for _, en := range m.Enums {
+		e.bytes(4, encodeEnum(en))
+	}
+	if m.MapEntry {
+		var opts encoder
+		opts.bool(7, true)
+		e.bytes(7, opts)
+	}
+	for _, name := range oneofs {
+		var oneof encoder
+		oneof.string(1, name)
+		e.bytes(8, oneof)
+	}
+	return e
+}
+
+func encodeEnum(en *Enum) []byte {
+	var e encoder
+	e.string(1, en.Name)
+	for _, v := range en.Values {
+		var value encoder
+		value.string(1, v.Name)
+		value.int32(2, v.Number)
+		e.bytes(2, value)
+	}
+	return e
+}
+
+func encodeService(s *Service) []byte {
+	var e encoder
+	e.string(1, s.Name)
+	for _, m := range s.Methods {
+		var method encoder
+		method.string(1, m.Name)
+		method.string(2, "."+m.InputType)
+		method.string(3, "."+m.OutputType)
+		if m.Deprecated {
+			var opts encoder
+			opts.bool(33, true)
+			method.bytes(4, opts)
+		}
+		method.bool(5, m.ClientStreaming)
+		method.bool(6, m.ServerStreaming)
+		e.bytes(2, method)
+	}
+	return e
+}
+
+// jsonName is the lowerCamelCase JSON name protoc derives from a field name
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,872 @@
+package proto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// File is a parsed .proto file
+type File struct {
+	Path     string     `json:"path"` // Import path, relative to its import root
+	Syntax   string     `json:"syntax"`
+	Package  string     `json:"package,omitempty"`
+	Imports  []string   `json:"imports,omitempty"`
+	Public   []int      `json:"-"` // Indexes into Imports of public imports
+	Weak     []int      `json:"-"`
+	Options  []Option   `json:"options,omitempty"`
+	Messages []*Message `json:"messages,omitempty"`
+	Enums    []*Enum    `json:"enums,omitempty"`
+	Services []*Service `json:"services,omitempty"`
+}
+
+// Option is a file, message, field or method option as written, e.g. go_package = "example.com/api"
+type Option struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Message is a message type
+type Message struct {
+	Name     string     `json:"name"`
+	FullName string     `json:"full_name"`
+	Comment  string     `json:"comment,omitempty"`
+	Fields   []*Field   `json:"fields"`
+	Oneofs   []string   `json:"oneofs,omitempty"`
+	Messages []*Message `json:"messages,omitempty"`
+	Enums    []*Enum    `json:"enums,omitempty"`
+	MapEntry bool       `json:"-"` // Synthesized entry type of a map field
+}
+
+// Field is a message field
+type Field struct {
+	Name       string `json:"name"`
+	Number     int    `json:"number"`
+	Label      string `json:"label,omitempty"` // optional, required or repeated as written
+	Type       string `json:"type"`            // As written: a scalar type or a (possibly relative) type name
+	TypeName   string `json:"type_name,omitempty"`
+	Kind       string `json:"kind"` // scalar, message, enum or map
+	MapKey     string `json:"map_key,omitempty"`
+	MapValue   string `json:"map_value,omitempty"`
+	Oneof      string `json:"oneof,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+
+	oneofIndex int
+	entry      *Message // Entry type of a map field
+}
+
+// Enum is an enum type
+type Enum struct {
+	Name     string       `json:"name"`
+	FullName string       `json:"full_name"`
+	Comment  string       `json:"comment,omitempty"`
+	Values   []*EnumValue `json:"values"`
+}
+
+// EnumValue is a named enum constant
+type EnumValue struct {
+	Name   string `json:"name"`
+	Number int    `json:"number"`
+}
+
+// Service is an RPC service
+type Service struct {
+	Name     string    `json:"name"`
+	FullName string    `json:"full_name"`
+	Comment  string    `json:"comment,omitempty"`
+	Methods  []*Method `json:"methods"`
+}
+
+// Method is an RPC of a service
+type Method struct {
+	Name            string   `json:"name"`
+	InputType       string   `json:"input_type"` // Fully qualified once resolved
+	OutputType      string   `json:"output_type"`
+	ClientStreaming bool     `json:"client_streaming,omitempty"`
+	ServerStreaming bool     `json:"server_streaming,omitempty"`
+	Comment         string   `json:"comment,omitempty"`
+	Options         []Option `json:"options,omitempty"` // e.g. google.api.http annotations
+	Deprecated      bool     `json:"deprecated,omitempty"`
+}
+
+// token is a lexical token; comment holds the comment block directly preceding it and
+// trailing a comment that follows it on the same line
+type token struct {
+	text     string
+	kind     byte // i identifier, n number, s string, p punctuation
+	line     int
+	comment  string
+	trailing string
+}
+
+// scalarTypes are the built-in field types
+var scalarTypes = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+// tokenize splits a .proto source into tokens, attaching each comment block to the next token
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	var comment []string
+	line, commentEnd := 1, 0
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+			// A blank line detaches a comment from the declaration below it
+			if len(comment) > 0 && line > commentEnd+1 {
+				comment = nil
+			}
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			text := strings.TrimSpace(strings.TrimPrefix(src[i:i+end], "//"))
+			if n := len(tokens); n > 0 && tokens[n-1].line == line && len(comment) == 0 {
+				tokens[n-1].trailing = text
+			} else {
+				comment = append(comment, text)
+				commentEnd = line
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			body := src[i+2 : i+2+end]
+			for _, l := range strings.Split(body, "\n") {
+				comment = append(comment, strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(l), "*")))
+			}
+			line += strings.Count(body, "\n")
+			commentEnd = line
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				if j < len(src) && src[j] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, token{text: src[i : j+1], kind: 's', line: line, comment: joinComment(comment)})
+			comment = nil
+			i = j + 1
+		case isIdentStart(c):
+			j := i
+			for j < len(src) && (isIdentStart(src[j]) || isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{text: src[i:j], kind: 'i', line: line, comment: joinComment(comment)})
+			comment = nil
+			i = j
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || isIdentStart(src[j]) || src[j] == '.' ||
+				((src[j] == '+' || src[j] == '-') && (src[j-1] == 'e' || src[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, token{text: src[i:j], kind: 'n', line: line, comment: joinComment(comment)})
+			comment = nil
+			i = j
+		default:
+			tokens = append(tokens, token{text: string(c), kind: 'p', line: line, comment: joinComment(comment)})
+			comment = nil
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func joinComment(lines []string) string {
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c < 0x80 && unicode.IsLetter(rune(c))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser is a recursive descent parser over the tokens of one file
+type parser struct {
+	tokens []token
+	pos    int
+	file   *File
+}
+
+// Parse parses a .proto source. Type names are left as written; Resolve qualifies them.
+func Parse(path, src string) (*File, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	p := &parser{tokens: tokens, file: &File{Path: path, Syntax: "proto2"}}
+	if err := p.parseFile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p.file, nil
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: 0}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+// trailing returns the comment following the last consumed token on its line
+func (p *parser) trailing() string {
+	if p.pos == 0 {
+		return ""
+	}
+	return p.tokens[p.pos-1].trailing
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) expect(text string) error {
+	t := p.next()
+	if t.text != text {
+		p.pos--
+		return p.errorf("expected %q, found %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *parser) accept(text string) bool {
+	if p.peek().text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.next()
+	if t.kind != 'i' {
+		p.pos--
+		return "", p.errorf("expected identifier, found %q", t.text)
+	}
+	return t.text, nil
+}
+
+func (p *parser) parseFile() error {
+	for p.pos < len(p.tokens) {
+		t := p.next()
+		switch t.text {
+		case ";":
+		case "syntax", "edition":
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			value := p.next()
+			p.file.Syntax = unquote(value.text)
+			if t.text == "edition" {
+				p.file.Syntax = "editions"
+			}
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "package":
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			p.file.Package = name
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "import":
+			modifier := ""
+			if p.peek().text == "public" || p.peek().text == "weak" {
+				modifier = p.next().text
+			}
+			path := p.next()
+			if path.kind != 's' {
+				return p.errorf("expected import path, found %q", path.text)
+			}
+			switch modifier {
+			case "public":
+				p.file.Public = append(p.file.Public, len(p.file.Imports))
+			case "weak":
+				p.file.Weak = append(p.file.Weak, len(p.file.Imports))
+			}
+			p.file.Imports = append(p.file.Imports, unquote(path.text))
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "option":
+			opt, err := p.parseOption(";")
+			if err != nil {
+				return err
+			}
+			p.file.Options = append(p.file.Options, opt)
+		case "message":
+			m, err := p.parseMessage(t.comment, p.file.Package)
+			if err != nil {
+				return err
+			}
+			p.file.Messages = append(p.file.Messages, m)
+		case "enum":
+			e, err := p.parseEnum(t.comment, p.file.Package)
+			if err != nil {
+				return err
+			}
+			p.file.Enums = append(p.file.Enums, e)
+		case "service":
+			s, err := p.parseService(t.comment)
+			if err != nil {
+				return err
+			}
+			p.file.Services = append(p.file.Services, s)
+		case "extend":
+			// Extensions (e.g. custom options) are not part of the API surface
+			if err := p.skipDeclaration(); err != nil {
+				return err
+			}
+		default:
+			p.pos--
+			return p.errorf("unexpected %q", t.text)
+		}
+	}
+	return nil
+}
+
+// parseOption reads "name = value" up to the terminator; aggregate values ({ ... }) are kept as text
+func (p *parser) parseOption(terminator string) (Option, error) {
+	var name strings.Builder
+	for {
+		t := p.next()
+		if t.kind == 0 {
+			return Option{}, p.errorf("unterminated option")
+		}
+		if t.text == "=" {
+			break
+		}
+		name.WriteString(t.text)
+	}
+	value, err := p.optionValue()
+	if err != nil {
+		return Option{}, err
+	}
+	if terminator != "" {
+		if err := p.expect(terminator); err != nil {
+			return Option{}, err
+		}
+	}
+	return Option{Name: name.String(), Value: value}, nil
+}
+
+func (p *parser) optionValue() (string, error) {
+	t := p.next()
+	switch {
+	case t.text == "{":
+		var parts []string
+		depth := 1
+		for depth > 0 {
+			t := p.next()
+			switch t.kind {
+			case 0:
+				return "", p.errorf("unterminated option value")
+			case 's':
+				t.text = strconv.Quote(unquote(t.text))
+			}
+			switch t.text {
+			case "{":
+				depth++
+			case "}":
+				depth--
+			}
+			if depth > 0 {
+				parts = append(parts, t.text)
+			}
+		}
+		return "{" + strings.Join(parts, " ") + "}", nil
+	case t.text == "-" || t.text == "+":
+		return t.text + p.next().text, nil
+	case t.kind == 's':
+		// Adjacent string literals are concatenated
+		value := unquote(t.text)
+		for p.peek().kind == 's' {
+			value += unquote(p.next().text)
+		}
+		return value, nil
+	}
+	return t.text, nil
+}
+
+// parseFieldOptions reads [name = value, ...] after a field or enum value
+func (p *parser) parseFieldOptions() ([]Option, error) {
+	if !p.accept("[") {
+		return nil, nil
+	}
+	var opts []Option
+	for {
+		var name strings.Builder
+		for p.peek().text != "=" {
+			t := p.next()
+			if t.kind == 0 {
+				return nil, p.errorf("unterminated options")
+			}
+			name.WriteString(t.text)
+		}
+		p.next()
+		value, err := p.optionValue()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, Option{Name: name.String(), Value: value})
+		if p.accept("]") {
+			return opts, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipDeclaration skips an unsupported declaration up to its ; or its closing brace
+func (p *parser) skipDeclaration() error {
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == 0:
+			return p.errorf("unexpected end of file")
+		case t.text == "{":
+			depth++
+		case t.text == "}":
+			depth--
+			if depth <= 0 {
+				return nil
+			}
+		case t.text == ";" && depth == 0:
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseMessage(comment, scope string) (*Message, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{Name: name, FullName: qualify(scope, name), Comment: comment}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMessageBody(m, ""); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseMessageBody reads declarations up to the closing brace; oneof is set inside a oneof block
+func (p *parser) parseMessageBody(m *Message, oneof string) error {
+	for {
+		t := p.next()
+		switch t.text {
+		case "}":
+			return nil
+		case ";":
+		case "":
+			return p.errorf("unterminated message %s", m.Name)
+		case "option":
+			if _, err := p.parseOption(";"); err != nil {
+				return err
+			}
+		case "message":
+			if oneof != "" {
+				p.pos--
+				return p.errorf("unexpected message in oneof")
+			}
+			nested, err := p.parseMessage(t.comment, m.FullName)
+			if err != nil {
+				return err
+			}
+			m.Messages = append(m.Messages, nested)
+		case "enum":
+			nested, err := p.parseEnum(t.comment, m.FullName)
+			if err != nil {
+				return err
+			}
+			m.Enums = append(m.Enums, nested)
+		case "oneof":
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			m.Oneofs = append(m.Oneofs, name)
+			if err := p.parseMessageBody(m, name); err != nil {
+				return err
+			}
+		case "reserved", "extensions":
+			if err := p.skipDeclaration(); err != nil {
+				return err
+			}
+		case "extend":
+			if err := p.skipDeclaration(); err != nil {
+				return err
+			}
+		case "map":
+			if p.peek().text != "<" {
+				p.pos--
+				if err := p.parseField(m, "", oneof, t.comment); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := p.parseMapField(m, t.comment); err != nil {
+				return err
+			}
+		case "optional", "required", "repeated":
+			if err := p.parseField(m, t.text, oneof, t.comment); err != nil {
+				return err
+			}
+		default:
+			if t.kind != 'i' {
+				p.pos--
+				return p.errorf("unexpected %q in message %s", t.text, m.Name)
+			}
+			p.pos--
+			if err := p.parseField(m, "", oneof, t.comment); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *parser) parseField(m *Message, label, oneof, comment string) error {
+	typ := p.next()
+	if typ.kind != 'i' {
+		p.pos--
+		return p.errorf("expected field type, found %q", typ.text)
+	}
+	if typ.text == "group" {
+		return p.errorf("groups are not supported")
+	}
+	if comment == "" {
+		comment = typ.comment
+	}
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	number, err := p.fieldNumber()
+	if err != nil {
+		return err
+	}
+	opts, err := p.parseFieldOptions()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(";"); err != nil {
+		return err
+	}
+	if comment == "" {
+		comment = p.trailing()
+	}
+	f := &Field{Name: name, Number: number, Label: label, Type: typ.text, Oneof: oneof, Comment: comment, oneofIndex: -1}
+	if oneof != "" {
+		f.oneofIndex = len(m.Oneofs) - 1
+	}
+	f.Deprecated = deprecated(opts)
+	m.Fields = append(m.Fields, f)
+	return nil
+}
+
+// parseMapField reads map<K, V> name = N; and synthesizes the repeated entry message protoc generates
+func (p *parser) parseMapField(m *Message, comment string) error {
+	p.next() // <
+	key, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(","); err != nil {
+		return err
+	}
+	value, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(">"); err != nil {
+		return err
+	}
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	number, err := p.fieldNumber()
+	if err != nil {
+		return err
+	}
+	opts, err := p.parseFieldOptions()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(";"); err != nil {
+		return err
+	}
+	if comment == "" {
+		comment = p.trailing()
+	}
+
+	entryName := mapEntryName(name)
+	entry := &Message{
+		Name:     entryName,
+		FullName: qualify(m.FullName, entryName),
+		MapEntry: true,
+		Fields: []*Field{
+			{Name: "key", Number: 1, Label: "optional", Type: key, oneofIndex: -1},
+			{Name: "value", Number: 2, Label: "optional", Type: value, oneofIndex: -1},
+		},
+	}
+	m.Messages = append(m.Messages, entry)
+	m.Fields = append(m.Fields, &Field{
+		Name: name, Number: number, Label: "repeated", Type: entryName, Kind: "map",
+		MapKey: key, MapValue: value, Comment: comment, Deprecated: deprecated(opts),
+		oneofIndex: -1, entry: entry,
+	})
+	return nil
+}
+
+func (p *parser) fieldNumber() (int, error) {
+	if err := p.expect("="); err != nil {
+		return 0, err
+	}
+	t := p.next()
+	n, err := strconv.ParseInt(t.text, 0, 32)
+	if err != nil || n <= 0 {
+		p.pos--
+		return 0, p.errorf("invalid field number %q", t.text)
+	}
+	return int(n), nil
+}
+
+func (p *parser) parseEnum(comment, scope string) (*Enum, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	e := &Enum{Name: name, FullName: qualify(scope, name), Comment: comment}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		t := p.next()
+		switch {
+		case t.text == "}":
+			return e, nil
+		case t.text == ";":
+		case t.kind == 0:
+			return nil, p.errorf("unterminated enum %s", name)
+		case t.text == "option":
+			if _, err := p.parseOption(";"); err != nil {
+				return nil, err
+			}
+		case t.text == "reserved":
+			if err := p.skipDeclaration(); err != nil {
+				return nil, err
+			}
+		case t.kind == 'i':
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			sign := ""
+			if p.accept("-") {
+				sign = "-"
+			}
+			n, err := strconv.ParseInt(sign+p.next().text, 0, 32)
+			if err != nil {
+				p.pos--
+				return nil, p.errorf("invalid value for enum constant %s", t.text)
+			}
+			if _, err := p.parseFieldOptions(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			e.Values = append(e.Values, &EnumValue{Name: t.text, Number: int(n)})
+		default:
+			p.pos--
+			return nil, p.errorf("unexpected %q in enum %s", t.text, name)
+		}
+	}
+}
+
+func (p *parser) parseService(comment string) (*Service, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{Name: name, FullName: qualify(p.file.Package, name), Comment: comment}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		t := p.next()
+		switch {
+		case t.text == "}":
+			return s, nil
+		case t.text == ";":
+		case t.kind == 0:
+			return nil, p.errorf("unterminated service %s", name)
+		case t.text == "option":
+			if _, err := p.parseOption(";"); err != nil {
+				return nil, err
+			}
+		case t.text == "rpc":
+			m, err := p.parseMethod(t.comment)
+			if err != nil {
+				return nil, err
+			}
+			s.Methods = append(s.Methods, m)
+		default:
+			p.pos--
+			return nil, p.errorf("unexpected %q in service %s", t.text, name)
+		}
+	}
+}
+
+// parseMethod reads rpc Name (stream In) returns (stream Out) followed by ; or an options block
+func (p *parser) parseMethod(comment string) (*Method, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	m := &Method{Name: name, Comment: comment}
+	if m.InputType, m.ClientStreaming, err = p.methodType(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("returns"); err != nil {
+		return nil, err
+	}
+	if m.OutputType, m.ServerStreaming, err = p.methodType(); err != nil {
+		return nil, err
+	}
+	if p.accept(";") {
+		return m, nil
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		t := p.next()
+		switch t.text {
+		case ";":
+		case "option":
+			opt, err := p.parseOption(";")
+			if err != nil {
+				return nil, err
+			}
+			m.Options = append(m.Options, opt)
+		default:
+			p.pos--
+			return nil, p.errorf("unexpected %q in rpc %s", t.text, name)
+		}
+	}
+	m.Deprecated = deprecated(m.Options)
+	return m, nil
+}
+
+func (p *parser) methodType() (string, bool, error) {
+	if err := p.expect("("); err != nil {
+		return "", false, err
+	}
+	stream := false
+	// "stream" is also a valid message name, so it only marks streaming when a type follows
+	if p.peek().text == "stream" && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text != ")" {
+		p.next()
+		stream = true
+	}
+	typ, err := p.ident()
+	if err != nil {
+		return "", false, err
+	}
+	if err := p.expect(")"); err != nil {
+		return "", false, err
+	}
+	return typ, stream, nil
+}
+
+func deprecated(opts []Option) bool {
+	for _, opt := range opts {
+		if opt.Name == "deprecated" && opt.Value == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// mapEntryName is the entry type protoc synthesizes for a map field: foo_bar becomes FooBarEntry
+func mapEntryName(field string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range field {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "Entry"
+}
+
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') {
+		if u, err := strconv.Unquote(`"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	}
+	return s
+}
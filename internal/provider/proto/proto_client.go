@@ -0,0 +1,253 @@
+package proto
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/file"
+)
+
+const (
+	// maxProtoBytes bounds a single .proto file
+	maxProtoBytes = 4 << 20
+	// maxLoadedFiles bounds the files loaded for one request, imports included
+	maxLoadedFiles = 1000
+)
+
+// skippedDirs are not searched for .proto files
+var skippedDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true}
+
+// FileSummary is one .proto file found by List
+type FileSummary struct {
+	Path       string   `json:"path"`
+	ImportPath string   `json:"import_path"`
+	Package    string   `json:"package,omitempty"`
+	Syntax     string   `json:"syntax,omitempty"`
+	Services   []string `json:"services,omitempty"`
+	Messages   int      `json:"messages"`
+	Enums      int      `json:"enums"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// ProtoClient reads .proto files from whitelisted directories
+type ProtoClient struct {
+	validator   *file.FileSecurityValidator
+	dirs        []string
+	importPaths []string
+}
+
+// NewProtoClient creates a new proto client; without directories the working directory is allowed
+func NewProtoClient(cfg *config.ProtoConfig) *ProtoClient {
+	c := &ProtoClient{}
+	var dirs []string
+	if cfg != nil {
+		dirs = cfg.Directories
+		c.importPaths = cfg.ImportPaths
+	}
+	c.dirs = dirs
+	if len(c.dirs) == 0 {
+		c.dirs = []string{"."}
+	}
+	if len(c.importPaths) == 0 {
+		c.importPaths = c.dirs
+	}
+	c.validator = file.NewFileSecurityValidator(dirs)
+	c.validator.SetReadOnly(true)
+	c.validator.SetMaxFileSize(maxProtoBytes)
+	return c
+}
+
+// Close releases resources held by the client
+func (c *ProtoClient) Close() error {
+	return nil
+}
+
+// List finds the .proto files under dir (every configured directory when empty) and summarizes each
+func (c *ProtoClient) List(dir string, limit int) ([]FileSummary, bool, error) {
+	roots := c.dirs
+	if dir != "" {
+		if err := c.validator.ValidateFileOperation("read", dir); err != nil {
+			return nil, false, fmt.Errorf("security validation failed: %w", err)
+		}
+		roots = []string{dir}
+	}
+
+	var summaries []FileSummary
+	truncated := false
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && (skippedDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != ".proto" {
+				return nil
+			}
+			if len(summaries) >= limit {
+				truncated = true
+				return filepath.SkipAll
+			}
+			summaries = append(summaries, c.summarize(path))
+			return nil
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to search %s: %w", root, err)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Path < summaries[j].Path })
+	return summaries, truncated, nil
+}
+
+func (c *ProtoClient) summarize(path string) FileSummary {
+	summary := FileSummary{Path: path, ImportPath: c.importPath(path)}
+	data, err := c.readFile(path)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	f, err := Parse(summary.ImportPath, string(data))
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	summary.Package, summary.Syntax = f.Package, f.Syntax
+	for _, s := range f.Services {
+		summary.Services = append(summary.Services, s.FullName)
+	}
+	summary.Messages, summary.Enums = len(f.Messages), len(f.Enums)
+	return summary
+}
+
+// Load parses the given files or directories and everything they import, and resolves type references.
+// Imports are searched in the import paths; missing imports are reported in the set.
+func (c *ProtoClient) Load(paths []string) (*Set, []string, error) {
+	l := &loader{client: c, loaded: map[string]bool{}}
+	var targets []string
+	for _, path := range paths {
+		files, err := c.expand(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range files {
+			importPath := c.importPath(p)
+			if err := l.load(importPath, p); err != nil {
+				return nil, nil, err
+			}
+			targets = append(targets, importPath)
+		}
+	}
+	set := newSet(l.files, l.missing)
+	set.resolve()
+	return set, targets, nil
+}
+
+// expand returns the .proto files of a directory, or the path itself when it is a file
+func (c *ProtoClient) expand(path string) ([]string, error) {
+	if err := c.validator.ValidateFileOperation("read", path); err != nil {
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	summaries, truncated, err := c.List(path, maxLoadedFiles)
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		return nil, fmt.Errorf("%s holds more than %d .proto files; pass a subdirectory", path, maxLoadedFiles)
+	}
+	if len(summaries) == 0 {
+		return nil, fmt.Errorf("no .proto files found in %s", path)
+	}
+	files := make([]string, 0, len(summaries))
+	for _, s := range summaries {
+		files = append(files, s.Path)
+	}
+	return files, nil
+}
+
+// loader loads files depth first, so each file's imports precede it
+type loader struct {
+	client  *ProtoClient
+	loaded  map[string]bool
+	files   []*File
+	missing []string
+}
+
+func (l *loader) load(importPath, path string) error {
+	if l.loaded[importPath] {
+		return nil
+	}
+	l.loaded[importPath] = true
+	if len(l.loaded) > maxLoadedFiles {
+		return fmt.Errorf("more than %d files imported", maxLoadedFiles)
+	}
+
+	data, err := l.client.readFile(path)
+	if err != nil {
+		return err
+	}
+	f, err := Parse(importPath, string(data))
+	if err != nil {
+		return err
+	}
+	for _, imp := range f.Imports {
+		found, ok := l.client.findImport(imp)
+		if !ok {
+			if !l.loaded[imp] {
+				l.loaded[imp] = true
+				l.missing = append(l.missing, imp)
+			}
+			continue
+		}
+		if err := l.load(imp, found); err != nil {
+			return err
+		}
+	}
+	l.files = append(l.files, f)
+	return nil
+}
+
+// findImport locates an import in the import paths, like protoc -I
+func (c *ProtoClient) findImport(importPath string) (string, bool) {
+	for _, root := range c.importPaths {
+		candidate := filepath.Join(root, filepath.FromSlash(importPath))
+		if c.validator.ValidateFileOperation("read", candidate) != nil {
+			continue
+		}
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// importPath is the name a file is imported by: its path relative to the first import root containing it
+func (c *ProtoClient) importPath(path string) string {
+	for _, root := range c.importPaths {
+		rel, err := filepath.Rel(root, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// readFile reads a file within the allowed directories
+func (c *ProtoClient) readFile(path string) ([]byte, error) {
+	return c.validator.ReadWhitelisted(path, 0)
+}
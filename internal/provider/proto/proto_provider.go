@@ -0,0 +1,475 @@
+package proto
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+const (
+	defaultListLimit = 200
+	// maxReachableTypes bounds the types returned with a described symbol
+	maxReachableTypes = 200
+	// maxInlineDescriptorBytes bounds a descriptor set returned as base64 instead of written to a file
+	maxInlineDescriptorBytes = 1 << 20
+)
+
+// FileWriter writes local files; the file provider implements it so its directory whitelist,
+// size limit and read-only mode apply to written descriptor sets
+type FileWriter interface {
+	WriteFile(path string, content []byte, createDirs bool) error
+}
+
+// ProtoProvider gives structured access to the services, RPCs and messages of .proto files
+type ProtoProvider struct {
+	*provider.BaseProvider
+	client *ProtoClient
+	files  FileWriter
+}
+
+// NewProtoProvider creates a new proto provider with config; files may be nil, which disables writing descriptor sets
+func NewProtoProvider(cfg *config.ProtoConfig, files FileWriter) *ProtoProvider {
+	p := &ProtoProvider{
+		BaseProvider: provider.NewBaseProvider("proto"),
+		client:       NewProtoClient(cfg),
+		files:        files,
+	}
+
+	// Schemas are read from local directories (the working directory by default), so the tools are always available
+	p.SetAvailable(true)
+	log.Printf("✓ Proto provider initialized successfully")
+
+	return p
+}
+
+// Test tests the proto configuration (for ProviderClient interface compatibility)
+func (p *ProtoProvider) Test(config interface{}) error {
+	return nil
+}
+
+// AddTools adds proto tools to the MCP server (for ProviderClient interface compatibility)
+func (p *ProtoProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the proto provider
+func (p *ProtoProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds proto tools to the MCP server
+func (p *ProtoProvider) addToolsToServer(server *mcp.Server) {
+	tools := []entity.ToolDefinition{
+		p.createListTool(),
+		p.createDescribeTool(),
+		p.createDescriptorSetTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered proto tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All proto tools registered successfully")
+}
+
+// createListTool creates the .proto file listing tool
+func (p *ProtoProvider) createListTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "proto_list",
+		Description: "Find .proto files and list each one's package, services and message/enum counts. Start here to discover internal gRPC APIs, then use proto_describe",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"dir": {
+					"type": "string",
+					"description": "Directory to search (within the proto directories); all of them when omitted"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum files to list",
+					"default": 200
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Dir   string `json:"dir,omitempty"`
+			Limit int    `json:"limit,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if args.Limit <= 0 {
+			args.Limit = defaultListLimit
+		}
+
+		files, truncated, err := p.client.List(args.Dir, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if files == nil {
+			files = []FileSummary{}
+		}
+		return p.formatJSONResult(map[string]interface{}{
+			"files":     files,
+			"count":     len(files),
+			"truncated": truncated,
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDescribeTool creates the schema description tool
+func (p *ProtoProvider) createDescribeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "proto_describe",
+		Description: "Parse .proto files and describe their services (RPCs with request/response types and streaming), messages (fields with numbers, types and comments) and enums, with type names resolved across imports. With symbol, return only that service, RPC, message or enum plus every message and enum it uses",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"path": {
+					"type": "string",
+					"description": ".proto file or directory of .proto files (within the proto directories)"
+				},
+				"symbol": {
+					"type": "string",
+					"description": "Service, RPC (Service.Method), message or enum; full (acme.billing.v1.Invoice) or short name"
+				},
+				"include_imports": {
+					"type": "boolean",
+					"description": "Also describe the imported files (without symbol)",
+					"default": false
+				}
+			},
+			"required": ["path"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Path           string `json:"path"`
+			Symbol         string `json:"symbol,omitempty"`
+			IncludeImports bool   `json:"include_imports,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Path == "" {
+			return p.createErrorResult(fmt.Errorf("path parameter is required")), nil
+		}
+
+		set, targets, err := p.client.Load([]string{args.Path})
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		result := map[string]interface{}{}
+		if len(set.Missing) > 0 {
+			result["missing_imports"] = set.Missing
+		}
+		if len(set.Warnings) > 0 {
+			result["warnings"] = set.Warnings
+		}
+
+		if args.Symbol != "" {
+			definition, err := describeSymbol(set, args.Symbol)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			for k, v := range definition {
+				result[k] = v
+			}
+			return p.formatJSONResult(result), nil
+		}
+
+		isTarget := map[string]bool{}
+		for _, t := range targets {
+			isTarget[t] = true
+		}
+		var files []*File
+		for _, f := range set.Files {
+			if args.IncludeImports || isTarget[f.Path] {
+				files = append(files, f)
+			}
+		}
+		result["files"] = files
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// describeSymbol finds a service, method, message or enum and collects the types it reaches
+func describeSymbol(set *Set, symbol string) (map[string]interface{}, error) {
+	symbol = strings.TrimPrefix(symbol, ".")
+	matches := func(full string) bool {
+		return full == symbol || strings.HasSuffix(full, "."+symbol)
+	}
+
+	var candidates []string
+	var found map[string]interface{}
+	var roots []string
+	for _, f := range set.Files {
+		for _, svc := range f.Services {
+			if matches(svc.FullName) {
+				candidates = append(candidates, svc.FullName)
+				found = map[string]interface{}{"kind": "service", "service": svc, "file": f.Path}
+				roots = nil
+				for _, m := range svc.Methods {
+					roots = append(roots, m.InputType, m.OutputType)
+				}
+			}
+			for _, m := range svc.Methods {
+				if matches(svc.FullName + "." + m.Name) {
+					candidates = append(candidates, svc.FullName+"."+m.Name)
+					found = map[string]interface{}{"kind": "rpc", "service": svc.FullName, "rpc": m, "file": f.Path}
+					roots = []string{m.InputType, m.OutputType}
+				}
+			}
+		}
+		var walkMessages func(messages []*Message)
+		walkMessages = func(messages []*Message) {
+			for _, m := range messages {
+				if !m.MapEntry && matches(m.FullName) {
+					candidates = append(candidates, m.FullName)
+					found = map[string]interface{}{"kind": "message", "file": f.Path}
+					roots = []string{m.FullName}
+				}
+				for _, e := range m.Enums {
+					if matches(e.FullName) {
+						candidates = append(candidates, e.FullName)
+						found = map[string]interface{}{"kind": "enum", "enum": e, "file": f.Path}
+						roots = nil
+					}
+				}
+				walkMessages(m.Messages)
+			}
+		}
+		walkMessages(f.Messages)
+		for _, e := range f.Enums {
+			if matches(e.FullName) {
+				candidates = append(candidates, e.FullName)
+				found = map[string]interface{}{"kind": "enum", "enum": e, "file": f.Path}
+				roots = nil
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	case 1:
+	default:
+		if len(candidates) > 20 {
+			candidates = candidates[:20]
+		}
+		return nil, fmt.Errorf("symbol %s is ambiguous, use the full name of one of: %s", symbol, strings.Join(candidates, ", "))
+	}
+
+	if len(roots) > 0 {
+		messages, enums, truncated := reachableTypes(set, roots)
+		found["messages"] = messages
+		if len(enums) > 0 {
+			found["enums"] = enums
+		}
+		if truncated {
+			found["truncated"] = true
+		}
+	}
+	return found, nil
+}
+
+// reachableTypes collects the messages and enums reachable from the root messages through their fields
+func reachableTypes(set *Set, roots []string) ([]*Message, []*Enum, bool) {
+	var messages []*Message
+	var enums []*Enum
+	seen := map[string]bool{}
+	queue := append([]string(nil), roots...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if len(messages)+len(enums) >= maxReachableTypes {
+			return messages, enums, true
+		}
+		if e := set.Enum(name); e != nil {
+			enums = append(enums, e)
+			continue
+		}
+		m := set.Message(name)
+		if m == nil {
+			// Well-known types are referenced by name only
+			continue
+		}
+		messages = append(messages, m)
+		for _, f := range m.Fields {
+			switch f.Kind {
+			case "message", "enum":
+				queue = append(queue, f.TypeName)
+			case "map":
+				if value := f.entry.Fields[1]; value.Kind == "message" || value.Kind == "enum" {
+					queue = append(queue, value.TypeName)
+				}
+			}
+		}
+	}
+	return messages, enums, false
+}
+
+// createDescriptorSetTool creates the FileDescriptorSet compiler tool
+func (p *ProtoProvider) createDescriptorSetTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "proto_descriptor_set",
+		Description: "Compile .proto files into a binary FileDescriptorSet (like protoc --descriptor_set_out --include_imports), usable as a protoset by gRPC clients such as grpcurl. Written to output_path, or returned base64-encoded. Comments are not included",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"paths": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": ".proto files or directories to compile (within the proto directories)"
+				},
+				"include_imports": {
+					"type": "boolean",
+					"description": "Include every imported file, so the set is self-contained",
+					"default": true
+				},
+				"output_path": {
+					"type": "string",
+					"description": "Write the set to this local file, e.g. build/api.protoset (needs a writable file provider)"
+				},
+				"create_dirs": {
+					"type": "boolean",
+					"description": "Create missing parent directories of output_path",
+					"default": false
+				}
+			},
+			"required": ["paths"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Paths          []string `json:"paths"`
+			IncludeImports bool     `json:"include_imports"`
+			OutputPath     string   `json:"output_path,omitempty"`
+			CreateDirs     bool     `json:"create_dirs,omitempty"`
+		}{IncludeImports: true}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if len(args.Paths) == 0 {
+			return p.createErrorResult(fmt.Errorf("paths parameter is required")), nil
+		}
+		if args.OutputPath != "" && p.files == nil {
+			return p.createErrorResult(fmt.Errorf("writing descriptor sets is not available")), nil
+		}
+
+		set, targets, err := p.client.Load(args.Paths)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		// A descriptor with unresolved type names would be rejected by every consumer
+		if len(set.Warnings) > 0 {
+			return p.createErrorResult(fmt.Errorf("cannot compile: %s", strings.Join(set.Warnings, "; "))), nil
+		}
+
+		isTarget := map[string]bool{}
+		for _, t := range targets {
+			isTarget[t] = true
+		}
+		var files []*File
+		services := 0
+		for _, f := range set.Files {
+			if args.IncludeImports || isTarget[f.Path] {
+				files = append(files, f)
+				services += len(f.Services)
+			}
+		}
+		content := EncodeDescriptorSet(files)
+
+		paths := make([]string, 0, len(files))
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+		result := map[string]interface{}{
+			"files":    paths,
+			"services": services,
+			"bytes":    len(content),
+		}
+		if len(set.Missing) > 0 {
+			result["missing_imports"] = set.Missing
+			result["note"] = "imports that were not found are listed as dependencies but not included; consumers must supply them (well-known google/protobuf types usually are)"
+		}
+
+		if args.OutputPath != "" {
+			if err := p.files.WriteFile(args.OutputPath, content, args.CreateDirs); err != nil {
+				return p.createErrorResult(err), nil
+			}
+			result["path"] = args.OutputPath
+			return p.formatJSONResult(result), nil
+		}
+		if len(content) > maxInlineDescriptorBytes {
+			return p.createErrorResult(fmt.Errorf("descriptor set is %d bytes; write it to output_path instead", len(content))), nil
+		}
+		result["descriptor_set_base64"] = base64.StdEncoding.EncodeToString(content)
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Decoder loads the .proto files at path (a file or directory) and returns a function that decodes
+// binary messages of the named type, so other providers can show protobuf payloads as JSON
+func (p *ProtoProvider) Decoder(path, message string) (func([]byte) (interface{}, error), error) {
+	set, _, err := p.client.Load([]string{path})
+	if err != nil {
+		return nil, err
+	}
+	m, err := set.FindMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	return func(data []byte) (interface{}, error) {
+		return set.Decode(m, data)
+	}, nil
+}
+
+func (p *ProtoProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Proto Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *ProtoProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that ProtoProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*ProtoProvider)(nil)
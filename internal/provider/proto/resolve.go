@@ -0,0 +1,196 @@
+package proto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// wellKnownTypes are the types of the google/protobuf imports protoc ships with, used when those
+// files are not under an import path. They are referenced by name only.
+var wellKnownTypes = map[string][]string{
+	"google/protobuf/any.proto":        {"Any"},
+	"google/protobuf/duration.proto":   {"Duration"},
+	"google/protobuf/empty.proto":      {"Empty"},
+	"google/protobuf/field_mask.proto": {"FieldMask"},
+	"google/protobuf/struct.proto":     {"Struct", "Value", "ListValue", "enum:NullValue"},
+	"google/protobuf/timestamp.proto":  {"Timestamp"},
+	"google/protobuf/wrappers.proto": {
+		"DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value",
+		"UInt32Value", "BoolValue", "StringValue", "BytesValue",
+	},
+	"google/protobuf/descriptor.proto": {
+		"FileDescriptorSet", "FileDescriptorProto", "DescriptorProto", "FieldDescriptorProto",
+		"EnumDescriptorProto", "ServiceDescriptorProto", "MethodDescriptorProto",
+		"FileOptions", "MessageOptions", "FieldOptions", "EnumOptions", "ServiceOptions", "MethodOptions",
+	},
+}
+
+// Set is a group of parsed files with their imports, whose type references can be resolved
+type Set struct {
+	Files    []*File // Files in load order; each file's imports come before it
+	Missing  []string
+	Warnings []string
+	types    map[string]string // Fully qualified name -> message or enum
+	byPath   map[string]*File
+}
+
+// newSet indexes the types of the loaded files and of well-known imports that were not found
+func newSet(files []*File, missing []string) *Set {
+	s := &Set{Files: files, Missing: missing, types: map[string]string{}, byPath: map[string]*File{}}
+	for _, f := range files {
+		s.byPath[f.Path] = f
+		for _, m := range f.Messages {
+			s.indexMessage(m)
+		}
+		for _, e := range f.Enums {
+			s.types[e.FullName] = "enum"
+		}
+	}
+	for _, path := range missing {
+		for _, name := range wellKnownTypes[path] {
+			kind := "message"
+			if n, ok := strings.CutPrefix(name, "enum:"); ok {
+				name, kind = n, "enum"
+			}
+			s.types["google.protobuf."+name] = kind
+		}
+	}
+	return s
+}
+
+func (s *Set) indexMessage(m *Message) {
+	s.types[m.FullName] = "message"
+	for _, nested := range m.Messages {
+		s.indexMessage(nested)
+	}
+	for _, e := range m.Enums {
+		s.types[e.FullName] = "enum"
+	}
+}
+
+// resolve qualifies the type references of every file; unresolved names are reported as warnings
+func (s *Set) resolve() {
+	for _, f := range s.Files {
+		for _, m := range f.Messages {
+			s.resolveMessage(f, m)
+		}
+		for _, svc := range f.Services {
+			for _, method := range svc.Methods {
+				for _, typ := range []*string{&method.InputType, &method.OutputType} {
+					if full, kind := s.lookup(f.Package, *typ); kind == "message" {
+						*typ = full
+					} else {
+						s.Warnings = append(s.Warnings, fmt.Sprintf("%s: rpc %s.%s: unknown message type %s", f.Path, svc.Name, method.Name, *typ))
+					}
+				}
+			}
+		}
+	}
+}
+
+func (s *Set) resolveMessage(f *File, m *Message) {
+	for _, field := range m.Fields {
+		if field.entry != nil {
+			s.resolveMessage(f, field.entry)
+			field.TypeName = field.entry.FullName
+			if value := field.entry.Fields[1]; value.TypeName != "" {
+				field.MapValue = value.TypeName
+			}
+			continue
+		}
+		if scalarTypes[field.Type] {
+			field.Kind = "scalar"
+			continue
+		}
+		full, kind := s.lookup(m.FullName, field.Type)
+		if kind == "" {
+			field.Kind = "unknown"
+			s.Warnings = append(s.Warnings, fmt.Sprintf("%s: field %s.%s: unknown type %s", f.Path, m.FullName, field.Name, field.Type))
+			continue
+		}
+		field.TypeName, field.Kind = full, kind
+	}
+	for _, nested := range m.Messages {
+		if !nested.MapEntry {
+			s.resolveMessage(f, nested)
+		}
+	}
+}
+
+// lookup resolves a type name as protoc does: a leading dot means fully qualified, otherwise the
+// name is searched in the enclosing scope and then in each outer scope
+func (s *Set) lookup(scope, name string) (string, string) {
+	if full, ok := strings.CutPrefix(name, "."); ok {
+		return full, s.types[full]
+	}
+	for {
+		candidate := qualify(scope, name)
+		if kind, ok := s.types[candidate]; ok {
+			return candidate, kind
+		}
+		if scope == "" {
+			return name, ""
+		}
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+// Message returns a message by fully qualified name
+func (s *Set) Message(name string) *Message {
+	for _, f := range s.Files {
+		if m := findMessage(f.Messages, name); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// Enum returns an enum by fully qualified name
+func (s *Set) Enum(name string) *Enum {
+	for _, f := range s.Files {
+		for _, e := range f.Enums {
+			if e.FullName == name {
+				return e
+			}
+		}
+		if e := findNestedEnum(f.Messages, name); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func findMessage(messages []*Message, name string) *Message {
+	for _, m := range messages {
+		if m.FullName == name {
+			return m
+		}
+		if strings.HasPrefix(name, m.FullName+".") {
+			if nested := findMessage(m.Messages, name); nested != nil {
+				return nested
+			}
+		}
+	}
+	return nil
+}
+
+func findNestedEnum(messages []*Message, name string) *Enum {
+	for _, m := range messages {
+		if !strings.HasPrefix(name, m.FullName+".") {
+			continue
+		}
+		for _, e := range m.Enums {
+			if e.FullName == name {
+				return e
+			}
+		}
+		if e := findNestedEnum(m.Messages, name); e != nil {
+			return e
+		}
+	}
+	return nil
+}
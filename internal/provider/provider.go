@@ -68,6 +68,13 @@ func (bp *BaseProvider) SetStatus(available bool, message string, err error) {
 	}
 }
 
+// Status returns the availability, status message and last error of the provider
+func (bp *BaseProvider) Status() ProviderStatus {
+	status := bp.status
+	status.Available = bp.available
+	return status
+}
+
 // Close provides default close implementation (can be overridden)
 func (bp *BaseProvider) Close() error {
 	return nil
@@ -0,0 +1,439 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// Provider is a provider managed by the ProviderRegistry
+type Provider interface {
+	ProviderClient
+	Name() string
+	IsAvailable() bool
+	Status() ProviderStatus
+	Close() error
+}
+
+// HealthChecker is implemented by providers that can check their backends on demand
+type HealthChecker interface {
+	HealthCheck() error
+}
+
+// ToolHinter is implemented by providers that can describe their live state, such as the connections,
+// buckets or labels available, to help callers pick tool arguments. Hints are keyed by tool name.
+type ToolHinter interface {
+	ToolHints(ctx context.Context) map[string]string
+}
+
+// Factory constructs a provider from its configuration
+type Factory func() Provider
+
+// ProviderState is the registry's view of a provider, as listed by provider_status
+type ProviderState struct {
+	Name      string     `json:"name"`
+	Enabled   bool       `json:"enabled"`
+	Available bool       `json:"available"`
+	Message   string     `json:"message,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	LatencyMs int64      `json:"latency_ms,omitempty"` // Duration of the last health check
+	Mocked    bool       `json:"mocked,omitempty"`     // Not configured; its tools were added for mock mode
+}
+
+type registryEntry struct {
+	name      string
+	factory   Factory
+	provider  Provider
+	active    bool // Test passed and the tools were added
+	mocked    bool // Test failed, but the tools were added to be served from fixtures
+	lastError string
+	checkedAt time.Time
+	latency   time.Duration
+}
+
+// ProviderRegistry creates providers in registration order, tests them, adds their tools to the
+// server and closes them on shutdown. Providers can be switched off by name in the configuration.
+type ProviderRegistry struct {
+	mu       sync.RWMutex
+	entries  []*registryEntry
+	enabled  map[string]bool // When non-empty, only these providers start
+	disabled map[string]bool
+	mock     bool // Add the tools of providers that are not configured
+}
+
+// NewProviderRegistry creates a registry; enabled limits the providers started when not empty,
+// and disabled providers are never started
+func NewProviderRegistry(enabled, disabled []string) *ProviderRegistry {
+	r := &ProviderRegistry{enabled: map[string]bool{}, disabled: map[string]bool{}}
+	for _, name := range enabled {
+		r.enabled[name] = true
+	}
+	for _, name := range disabled {
+		r.disabled[name] = true
+	}
+	return r
+}
+
+// Register adds a provider factory; providers are created by Start in registration order
+func (r *ProviderRegistry) Register(name string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, &registryEntry{name: name, factory: factory})
+}
+
+// SetMock makes Start add the tools of providers that are not configured, for a mock mode that
+// serves every tool call from fixtures. It must be called before Start.
+func (r *ProviderRegistry) SetMock(mock bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mock = mock
+}
+
+// Enabled reports whether the configuration allows a provider to start
+func (r *ProviderRegistry) Enabled(name string) bool {
+	if r.disabled[name] {
+		return false
+	}
+	return len(r.enabled) == 0 || r.enabled[name]
+}
+
+// Start creates every enabled provider, adds the tools of those whose Test passes and registers provider_status
+func (r *ProviderRegistry) Start(server *mcp.Server) {
+	known := map[string]bool{}
+	for _, entry := range r.entries {
+		known[entry.name] = true
+		if !r.Enabled(entry.name) {
+			log.Printf("○ %s provider disabled by configuration", entry.name)
+			continue
+		}
+
+		r.start(server, entry)
+	}
+
+	for _, names := range []map[string]bool{r.enabled, r.disabled} {
+		for name := range names {
+			if !known[name] {
+				log.Printf("⚠ Unknown provider %q in providers configuration", name)
+			}
+		}
+	}
+
+	tool := r.createStatusTool()
+	server.AddTool(tool.Tool, tool.Handler)
+	log.Printf("✓ Registered provider tool: %s", tool.Tool.Name)
+}
+
+// start creates a provider and adds its tools when its Test passes
+func (r *ProviderRegistry) start(server *mcp.Server, entry *registryEntry) {
+	p := entry.factory()
+	r.mu.Lock()
+	entry.provider = p
+	entry.active, entry.mocked, entry.lastError = false, false, ""
+	r.mu.Unlock()
+
+	if err := p.Test(nil); err != nil {
+		r.setError(entry, statusError(p, err))
+		if r.mock {
+			r.addMockTools(server, entry, p)
+		}
+		return
+	}
+	if err := p.AddTools(server, nil); err != nil {
+		log.Printf("⚠ Failed to add %s tools: %v", entry.name, err)
+		r.setError(entry, err.Error())
+		return
+	}
+	r.mu.Lock()
+	entry.active = true
+	r.mu.Unlock()
+}
+
+// Restart closes the named providers, calls update (e.g. to apply rotated credentials to the configuration)
+// and starts them again in registration order, so providers built on others get their new clients. The
+// tools of a provider that no longer passes Test stay registered and fail until it is restarted again.
+func (r *ProviderRegistry) Restart(server *mcp.Server, names []string, update func()) {
+	var restart []*registryEntry
+	for _, entry := range r.entries {
+		if slices.Contains(names, entry.name) && entry.provider != nil {
+			restart = append(restart, entry)
+		}
+	}
+
+	for i := len(restart) - 1; i >= 0; i-- {
+		entry := restart[i]
+		r.mu.Lock()
+		old := entry.provider
+		entry.active = false
+		r.mu.Unlock()
+		if err := old.Close(); err != nil {
+			log.Printf("⚠ Failed to close %s provider: %v", entry.name, err)
+		}
+	}
+	if update != nil {
+		update()
+	}
+	for _, entry := range restart {
+		r.start(server, entry)
+		r.mu.RLock()
+		active, lastError := entry.active, entry.lastError
+		r.mu.RUnlock()
+		if active {
+			log.Printf("✓ %s provider restarted", entry.name)
+		} else {
+			log.Printf("⚠ %s provider unavailable after restart: %s", entry.name, lastError)
+		}
+	}
+}
+
+// addMockTools adds the tools of a provider that is not configured. The provider is only reported
+// available while its tools are added, so other providers and resources never use its missing client.
+func (r *ProviderRegistry) addMockTools(server *mcp.Server, entry *registryEntry, p Provider) {
+	settable, ok := p.(interface{ SetAvailable(bool) })
+	if !ok {
+		return
+	}
+	settable.SetAvailable(true)
+	err := p.AddTools(server, nil)
+	settable.SetAvailable(false)
+	if err != nil {
+		log.Printf("⚠ Failed to add %s tools for mock mode: %v", entry.name, err)
+		return
+	}
+	r.mu.Lock()
+	entry.mocked = true
+	r.mu.Unlock()
+	log.Printf("◐ %s tools added for mock mode", entry.name)
+}
+
+// Get returns a started provider, or nil when it is unknown or disabled
+func (r *ProviderRegistry) Get(name string) Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, entry := range r.entries {
+		if entry.name == name {
+			return entry.provider
+		}
+	}
+	return nil
+}
+
+// Active reports whether a provider started and its tools were added
+func (r *ProviderRegistry) Active(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, entry := range r.entries {
+		if entry.name == name {
+			return entry.active
+		}
+	}
+	return false
+}
+
+// Mocked reports whether a provider is not configured but its tools were added for mock mode
+func (r *ProviderRegistry) Mocked(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, entry := range r.entries {
+		if entry.name == name {
+			return entry.mocked
+		}
+	}
+	return false
+}
+
+// HealthCheck runs the health check of every active provider that has one and returns the resulting states.
+// Providers that failed an earlier check are checked again, so a recovered backend is reported as such.
+func (r *ProviderRegistry) HealthCheck() []ProviderState {
+	r.mu.RLock()
+	var checkers []*registryEntry
+	for _, entry := range r.entries {
+		if _, ok := entry.provider.(HealthChecker); ok && entry.active {
+			checkers = append(checkers, entry)
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, entry := range checkers {
+		checker := entry.provider.(HealthChecker)
+
+		start := time.Now()
+		err := checker.HealthCheck()
+		r.mu.Lock()
+		entry.checkedAt = time.Now()
+		entry.latency = entry.checkedAt.Sub(start)
+		entry.lastError = ""
+		if err != nil {
+			entry.lastError = err.Error()
+		}
+		r.mu.Unlock()
+	}
+	return r.States()
+}
+
+// ToolHints collects the hints of every active provider that has them. A tool hinted by several
+// providers gets all of their hints.
+func (r *ProviderRegistry) ToolHints(ctx context.Context) map[string]string {
+	r.mu.RLock()
+	var hinters []ToolHinter
+	for _, entry := range r.entries {
+		if hinter, ok := entry.provider.(ToolHinter); ok && entry.active {
+			hinters = append(hinters, hinter)
+		}
+	}
+	r.mu.RUnlock()
+
+	hints := map[string]string{}
+	for _, hinter := range hinters {
+		for tool, hint := range hinter.ToolHints(ctx) {
+			if hint == "" {
+				continue
+			}
+			if hints[tool] != "" {
+				hint = hints[tool] + " " + hint
+			}
+			hints[tool] = hint
+		}
+	}
+	return hints
+}
+
+// HintList formats a hint listing items, such as "Buckets: logs, assets (and 3 more)."; no items give ""
+func HintList(label string, items []string, max int) string {
+	if len(items) == 0 {
+		return ""
+	}
+	if max <= 0 || len(items) <= max {
+		return fmt.Sprintf("%s: %s.", label, strings.Join(items, ", "))
+	}
+	return fmt.Sprintf("%s: %s (and %d more).", label, strings.Join(items[:max], ", "), len(items)-max)
+}
+
+// States returns the state of every registered provider in registration order
+func (r *ProviderRegistry) States() []ProviderState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	states := make([]ProviderState, 0, len(r.entries))
+	for _, entry := range r.entries {
+		state := ProviderState{Name: entry.name, Enabled: entry.provider != nil, LastError: entry.lastError, Mocked: entry.mocked}
+		if entry.provider != nil {
+			status := entry.provider.Status()
+			state.Available = status.Available
+			state.Message = status.Message
+		} else {
+			state.Message = "disabled by configuration"
+		}
+		if !entry.checkedAt.IsZero() {
+			checkedAt := entry.checkedAt
+			state.CheckedAt = &checkedAt
+			state.LatencyMs = entry.latency.Milliseconds()
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// Close closes the started providers in reverse registration order
+func (r *ProviderRegistry) Close() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var errs []error
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		if entry.provider == nil {
+			continue
+		}
+		if err := entry.provider.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *ProviderRegistry) setError(entry *registryEntry, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.lastError = message
+}
+
+// statusError prefers the reason a provider recorded for being unavailable over the generic Test error;
+// a provider that is merely not configured has no error
+func statusError(p Provider, err error) string {
+	status := p.Status()
+	if status.Error != "" || status.Message != "" {
+		return status.Error
+	}
+	return err.Error()
+}
+
+// createStatusTool creates the provider_status tool
+func (r *ProviderRegistry) createStatusTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "provider_status",
+		Description: "List every provider of this server with whether it is enabled by configuration, whether it is available (configured and connected) and its last error. Use it to find out why a provider's tools are missing",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"check": {
+					"type": "boolean",
+					"description": "Run health checks (e.g. database pings) before reporting",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Check bool `json:"check,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Provider Error: invalid arguments: %v", err)}},
+					IsError: true,
+				}, nil
+			}
+		}
+
+		states := r.States()
+		if args.Check {
+			states = r.HealthCheck()
+		}
+		available := 0
+		for _, state := range states {
+			if state.Available {
+				available++
+			}
+		}
+
+		jsonData, err := json.MarshalIndent(map[string]interface{}{
+			"providers": states,
+			"available": available,
+			"total":     len(states),
+		}, "", "  ")
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Provider Error: failed to marshal data: %v", err)}},
+				IsError: true,
+			}, nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+		}, nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,279 @@
+package queue
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+const (
+	defaultMaxPeekMessages = 20
+	defaultMaxBodyBytes    = 4096
+	defaultQueueLimit      = 100
+)
+
+// Queue is a queue summary normalized across brokers
+type Queue struct {
+	System    string `json:"system"`
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	Depth     int64  `json:"depth"`               // Messages waiting to be delivered
+	InFlight  int64  `json:"in_flight"`           // Delivered but not yet acknowledged or deleted
+	Delayed   int64  `json:"delayed,omitempty"`   // SQS messages not yet visible
+	Consumers *int64 `json:"consumers,omitempty"` // RabbitMQ only
+	// OldestMessageAgeSeconds is the age of the message at the head of the queue, when the broker reports it
+	OldestMessageAgeSeconds *float64 `json:"oldest_message_age_seconds,omitempty"`
+	DeadLetterTarget        string   `json:"dead_letter_target,omitempty"` // Where rejected or expired messages go
+	State                   string   `json:"state,omitempty"`
+}
+
+// QueueDetail is a queue with its rates, configuration and dead-letter queues
+type QueueDetail struct {
+	Queue
+	PublishRate    *float64               `json:"publish_rate,omitempty"` // Messages per second (RabbitMQ)
+	DeliverRate    *float64               `json:"deliver_rate,omitempty"`
+	AckRate        *float64               `json:"ack_rate,omitempty"`
+	OldestAsOf     *time.Time             `json:"oldest_message_age_as_of,omitempty"` // Time of the SQS metric datapoint
+	MaxReceives    int                    `json:"max_receive_count,omitempty"`        // SQS redrive policy
+	DeadLetter     []Queue                `json:"dead_letter_queues,omitempty"`
+	DeadLetterFrom []string               `json:"dead_letter_sources,omitempty"` // Queues that send their failures here
+	Settings       map[string]interface{} `json:"settings,omitempty"`
+	Notes          []string               `json:"notes,omitempty"`
+}
+
+// Message is a peeked message; the body is parsed JSON when it is valid JSON, else text or base64
+type Message struct {
+	ID           string                 `json:"id,omitempty"`
+	Body         interface{}            `json:"body"`
+	BodyEncoding string                 `json:"body_encoding,omitempty"` // base64 for binary bodies
+	BodyBytes    int                    `json:"body_bytes"`
+	Truncated    bool                   `json:"truncated,omitempty"`
+	SentAt       *time.Time             `json:"sent_at,omitempty"`
+	ReceiveCount int                    `json:"receive_count,omitempty"` // SQS approximate receive count
+	Redelivered  bool                   `json:"redelivered,omitempty"`   // RabbitMQ
+	Exchange     string                 `json:"exchange,omitempty"`
+	RoutingKey   string                 `json:"routing_key,omitempty"`
+	Headers      map[string]interface{} `json:"headers,omitempty"`    // RabbitMQ headers (x-death explains dead-lettering)
+	Attributes   map[string]interface{} `json:"attributes,omitempty"` // SQS message and system attributes
+}
+
+// Backend is implemented by each supported broker
+type Backend interface {
+	// Name returns the system name ("rabbitmq", "sqs")
+	Name() string
+	// ListQueues lists queues whose names match pattern, with their depths
+	ListQueues(ctx context.Context, pattern string, limit int) ([]Queue, bool, error)
+	// DescribeQueue describes a queue and its dead-letter queues
+	DescribeQueue(ctx context.Context, name string) (*QueueDetail, error)
+	// Peek reads up to count messages from the head of a queue and leaves them in the queue
+	Peek(ctx context.Context, name string, count, maxBodyBytes int) ([]Message, []string, error)
+	// Ping checks that the broker answers
+	Ping(ctx context.Context) error
+}
+
+// QueueClient dispatches requests to the configured brokers
+type QueueClient struct {
+	backends        map[string]Backend
+	maxPeekMessages int
+	maxBodyBytes    int
+	logger          *logging.Logger
+}
+
+// NewQueueClient creates a client with a backend for every configured broker; SQS takes its
+// credentials from the aws section
+func NewQueueClient(cfg *config.QueueConfig, awsCfg *config.AWSConfig, s3Cfg *config.S3Config) *QueueClient {
+	c := &QueueClient{
+		backends:        map[string]Backend{},
+		maxPeekMessages: defaultMaxPeekMessages,
+		maxBodyBytes:    defaultMaxBodyBytes,
+		logger:          logging.New("QueueClient"),
+	}
+	if cfg == nil {
+		return c
+	}
+	if cfg.MaxPeekMessages > 0 {
+		c.maxPeekMessages = cfg.MaxPeekMessages
+	}
+	if cfg.MaxBodyBytes > 0 {
+		c.maxBodyBytes = cfg.MaxBodyBytes
+	}
+
+	if cfg.RabbitMQ.URL != "" {
+		c.backends["rabbitmq"] = newRabbitMQBackend(&cfg.RabbitMQ)
+	}
+	if cfg.SQS.Enabled {
+		backend, err := newSQSBackend(&cfg.SQS, awsCfg, s3Cfg)
+		if err != nil {
+			c.logger.Error("SQS not available", logging.Error(err))
+		} else {
+			c.backends["sqs"] = backend
+		}
+	}
+	return c
+}
+
+// IsAvailable checks if at least one broker is configured
+func (c *QueueClient) IsAvailable() bool {
+	return len(c.backends) > 0
+}
+
+// Systems returns the names of the configured brokers
+func (c *QueueClient) Systems() []string {
+	names := make([]string, 0, len(c.backends))
+	for name := range c.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MaxPeekMessages returns the most messages a peek may read
+func (c *QueueClient) MaxPeekMessages() int {
+	return c.maxPeekMessages
+}
+
+// backend resolves a system name; an empty name is allowed when exactly one broker is configured
+func (c *QueueClient) backend(system string) (Backend, error) {
+	if system == "" {
+		if len(c.backends) == 1 {
+			for _, b := range c.backends {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("system parameter is required when several brokers are configured (%v)", c.Systems())
+	}
+	b, ok := c.backends[system]
+	if !ok {
+		return nil, fmt.Errorf("queue system %q is not configured (available: %v)", system, c.Systems())
+	}
+	return b, nil
+}
+
+// ListQueues lists the queues of one broker, or of every broker when system is empty; brokers that
+// fail are reported as warnings so the others are still listed
+func (c *QueueClient) ListQueues(ctx context.Context, system, pattern string, limit int) ([]Queue, bool, []string, error) {
+	if limit <= 0 {
+		limit = defaultQueueLimit
+	}
+	systems := c.Systems()
+	if system != "" {
+		if _, err := c.backend(system); err != nil {
+			return nil, false, nil, err
+		}
+		systems = []string{system}
+	}
+
+	var queues []Queue
+	var warnings []string
+	truncated := false
+	for _, name := range systems {
+		found, more, err := c.backends[name].ListQueues(ctx, pattern, limit)
+		if err != nil {
+			if system != "" {
+				return nil, false, nil, err
+			}
+			warnings = append(warnings, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		queues = append(queues, found...)
+		truncated = truncated || more
+	}
+
+	// Deepest first: the backlog is usually what the caller is after
+	sort.SliceStable(queues, func(i, j int) bool { return queues[i].Depth > queues[j].Depth })
+	if len(queues) > limit {
+		queues = queues[:limit]
+		truncated = true
+	}
+	return queues, truncated, warnings, nil
+}
+
+// DescribeQueue describes a queue on a broker
+func (c *QueueClient) DescribeQueue(ctx context.Context, system, name string) (*QueueDetail, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, err
+	}
+	return b.DescribeQueue(ctx, name)
+}
+
+// Peek reads messages from the head of a queue without removing them
+func (c *QueueClient) Peek(ctx context.Context, system, name string, count int) ([]Message, []string, error) {
+	b, err := c.backend(system)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count <= 0 {
+		count = 5
+	}
+	if count > c.maxPeekMessages {
+		return nil, nil, fmt.Errorf("count must be at most %d", c.maxPeekMessages)
+	}
+	return b.Peek(ctx, name, count, c.maxBodyBytes)
+}
+
+// Ping checks every configured broker
+func (c *QueueClient) Ping(ctx context.Context) error {
+	for _, name := range c.Systems() {
+		if err := c.backends[name].Ping(ctx); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Close releases the client's resources
+func (c *QueueClient) Close() error {
+	return nil
+}
+
+// matchName reports whether a queue name matches a glob (when the pattern has wildcards) or a
+// case-insensitive substring
+func matchName(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}
+	return strings.Contains(strings.ToLower(name), strings.ToLower(pattern))
+}
+
+// showBody sets a message body from raw bytes: parsed JSON when valid and complete, else text, else
+// base64, cut to maxBytes
+func showBody(m *Message, body []byte, size, maxBytes int) {
+	m.BodyBytes = size
+	if len(body) > maxBytes {
+		body = body[:maxBytes]
+	}
+	m.Truncated = len(body) < size
+	if !m.Truncated && json.Valid(body) {
+		var value interface{}
+		if json.Unmarshal(body, &value) == nil {
+			m.Body = value
+			return
+		}
+	}
+	if m.Truncated {
+		// The cut may split the last character
+		for i := 0; i < utf8.UTFMax-1 && len(body) > 0 && !utf8.Valid(body); i++ {
+			body = body[:len(body)-1]
+		}
+	}
+	if utf8.Valid(body) {
+		m.Body = string(body)
+		return
+	}
+	m.Body = base64.StdEncoding.EncodeToString(body)
+	m.BodyEncoding = "base64"
+}
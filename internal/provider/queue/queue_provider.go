@@ -0,0 +1,275 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// QueueProvider provides read-only message queue inspection for RabbitMQ and SQS: depths, dead-letter
+// queues and non-destructive peeks
+type QueueProvider struct {
+	*provider.BaseProvider
+	client *QueueClient
+}
+
+// NewQueueProvider creates a new queue provider with config; SQS uses the credentials of the aws section
+func NewQueueProvider(cfg *config.QueueConfig, awsCfg *config.AWSConfig, s3Cfg *config.S3Config) *QueueProvider {
+	p := &QueueProvider{
+		BaseProvider: provider.NewBaseProvider("queue"),
+		client:       NewQueueClient(cfg, awsCfg, s3Cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "No message queue configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Queue provider initialized successfully (%s)", strings.Join(p.client.Systems(), ", "))
+
+	return p
+}
+
+// Test tests the queue configuration (for ProviderClient interface compatibility)
+func (p *QueueProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("queue provider not available")
+	}
+	return nil
+}
+
+// AddTools adds queue tools to the MCP server (for ProviderClient interface compatibility)
+func (p *QueueProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the queue provider
+func (p *QueueProvider) Close() error {
+	return p.client.Close()
+}
+
+// HealthCheck checks that every configured broker answers
+func (p *QueueProvider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return p.client.Ping(ctx)
+}
+
+// addToolsToServer adds queue tools to the MCP server
+func (p *QueueProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Queue provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createListTool(),
+		p.createDescribeTool(),
+		p.createPeekTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered queue tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All queue tools registered successfully")
+}
+
+// systemDescription documents the system parameter with the configured brokers
+func (p *QueueProvider) systemDescription() string {
+	return fmt.Sprintf("Broker: %s; may be omitted when only one is configured", strings.Join(p.client.Systems(), " or "))
+}
+
+// createListTool creates the queue listing tool
+func (p *QueueProvider) createListTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "queue_list",
+		Description: "List message queues with their depth (ready messages), in-flight (unacknowledged) messages, consumers and dead-letter target, deepest first. Lists every configured broker unless system is given",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"system": {
+					"type": "string",
+					"description": %q
+				},
+				"pattern": {
+					"type": "string",
+					"description": "Glob (orders.*) or case-insensitive substring the queue names must match; use dlq or *.dlq to find dead-letter queues"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum queues to return",
+					"default": 100
+				}
+			}
+		}`, "Only this broker ("+strings.Join(p.client.Systems(), " or ")+")")),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			System  string `json:"system,omitempty"`
+			Pattern string `json:"pattern,omitempty"`
+			Limit   int    `json:"limit,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		queues, truncated, warnings, err := p.client.ListQueues(ctx, args.System, args.Pattern, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if queues == nil {
+			queues = []Queue{}
+		}
+		result := map[string]interface{}{"queues": queues, "count": len(queues)}
+		if truncated {
+			result["truncated"] = true
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDescribeTool creates the queue detail tool
+func (p *QueueProvider) createDescribeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "queue_describe",
+		Description: "Describe a queue: depth, in-flight messages, age of the oldest message, rates (RabbitMQ), settings, the dead-letter queues it feeds with their depths, and the queues that dead-letter into it",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"system": {
+					"type": "string",
+					"description": %q
+				},
+				"queue": {
+					"type": "string",
+					"description": "Queue name (an SQS queue URL also works)"
+				}
+			},
+			"required": ["queue"]
+		}`, p.systemDescription())),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			System string `json:"system,omitempty"`
+			Queue  string `json:"queue"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Queue == "" {
+			return p.createErrorResult(fmt.Errorf("queue parameter is required")), nil
+		}
+
+		detail, err := p.client.DescribeQueue(ctx, args.System, args.Queue)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		return p.formatJSONResult(detail), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createPeekTool creates the non-destructive message peek tool
+func (p *QueueProvider) createPeekTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "queue_peek",
+		Description: "Read messages at the head of a queue without consuming them, typically to see why messages ended up in a dead-letter queue. " +
+			"RabbitMQ messages are requeued (and marked redelivered); SQS messages are made visible again at once. " +
+			"SQS queues with a redrive policy are refused because every receive counts towards maxReceiveCount",
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"system": {
+					"type": "string",
+					"description": %q
+				},
+				"queue": {
+					"type": "string",
+					"description": "Queue name (an SQS queue URL also works)"
+				},
+				"count": {
+					"type": "integer",
+					"description": "Number of messages to read (at most %d)",
+					"default": 5
+				}
+			},
+			"required": ["queue"]
+		}`, p.systemDescription(), p.client.MaxPeekMessages())),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			System string `json:"system,omitempty"`
+			Queue  string `json:"queue"`
+			Count  int    `json:"count,omitempty"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if args.Queue == "" {
+			return p.createErrorResult(fmt.Errorf("queue parameter is required")), nil
+		}
+
+		messages, warnings, err := p.client.Peek(ctx, args.System, args.Queue, args.Count)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if messages == nil {
+			messages = []Message{}
+		}
+		result := map[string]interface{}{"queue": args.Queue, "messages": messages, "count": len(messages)}
+		if len(messages) == 0 {
+			result["note"] = "no message was available; the queue may be empty or its messages in flight"
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *QueueProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Queue Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *QueueProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that QueueProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*QueueProvider)(nil)
@@ -0,0 +1,374 @@
+package queue
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+// maxDeadLetterQueues bounds the dead-letter queues looked up for one queue
+const maxDeadLetterQueues = 5
+
+// rabbitMQBackend talks to the RabbitMQ management HTTP API
+type rabbitMQBackend struct {
+	client *resty.Client
+	vhost  string
+}
+
+func newRabbitMQBackend(cfg *config.RabbitMQConfig) *rabbitMQBackend {
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.URL, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+	if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	if cfg.TLSSkipVerify {
+		client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	}
+
+	vhost := cfg.VHost
+	if vhost == "" {
+		vhost = "/"
+	}
+	return &rabbitMQBackend{client: client, vhost: vhost}
+}
+
+func (b *rabbitMQBackend) Name() string { return "rabbitmq" }
+
+type rabbitRate struct {
+	Rate float64 `json:"rate"`
+}
+
+type rabbitQueue struct {
+	Name                   string                 `json:"name"`
+	Type                   string                 `json:"type"`
+	State                  string                 `json:"state"`
+	Messages               int64                  `json:"messages"`
+	MessagesReady          int64                  `json:"messages_ready"`
+	MessagesUnacknowledged int64                  `json:"messages_unacknowledged"`
+	Consumers              int64                  `json:"consumers"`
+	Arguments              map[string]interface{} `json:"arguments"`
+	Policy                 string                 `json:"policy"`
+	// Empty objects are sent as [] by some versions, so these are decoded by hand
+	EffectivePolicy      json.RawMessage `json:"effective_policy_definition"`
+	HeadMessageTimestamp json.RawMessage `json:"head_message_timestamp"`
+	MessageStats         *struct {
+		PublishDetails    *rabbitRate `json:"publish_details"`
+		DeliverGetDetails *rabbitRate `json:"deliver_get_details"`
+		AckDetails        *rabbitRate `json:"ack_details"`
+	} `json:"message_stats"`
+}
+
+// objectOf decodes a JSON object, treating anything else (such as an empty array) as empty
+func objectOf(raw json.RawMessage) map[string]interface{} {
+	var object map[string]interface{}
+	if json.Unmarshal(raw, &object) != nil {
+		return nil
+	}
+	return object
+}
+
+// setting reads a queue setting from its x- argument, falling back to its policy
+func (q *rabbitQueue) setting(argument, policyKey string) (interface{}, bool) {
+	if v, ok := q.Arguments[argument]; ok {
+		return v, true
+	}
+	v, ok := objectOf(q.EffectivePolicy)[policyKey]
+	return v, ok
+}
+
+// deadLetter returns the dead-letter exchange and routing key of a queue
+func (q *rabbitQueue) deadLetter() (exchange, routingKey string, ok bool) {
+	dlx, ok := q.setting("x-dead-letter-exchange", "dead-letter-exchange")
+	if !ok {
+		return "", "", false
+	}
+	exchange, _ = dlx.(string)
+	if key, found := q.setting("x-dead-letter-routing-key", "dead-letter-routing-key"); found {
+		routingKey, _ = key.(string)
+	}
+	return exchange, routingKey, true
+}
+
+func (q *rabbitQueue) summary() Queue {
+	consumers := q.Consumers
+	queue := Queue{
+		System:    "rabbitmq",
+		Name:      q.Name,
+		Type:      q.Type,
+		Depth:     q.MessagesReady,
+		InFlight:  q.MessagesUnacknowledged,
+		Consumers: &consumers,
+		State:     q.State,
+	}
+	var ts float64
+	if json.Unmarshal(q.HeadMessageTimestamp, &ts) == nil && ts > 0 {
+		age := time.Since(time.Unix(int64(ts), 0)).Seconds()
+		queue.OldestMessageAgeSeconds = &age
+	}
+	if exchange, routingKey, ok := q.deadLetter(); ok {
+		queue.DeadLetterTarget = describeDeadLetter(exchange, routingKey)
+	}
+	return queue
+}
+
+func describeDeadLetter(exchange, routingKey string) string {
+	target := fmt.Sprintf("exchange %q", exchange)
+	if exchange == "" {
+		target = "default exchange"
+	}
+	if routingKey != "" {
+		target += fmt.Sprintf(" with routing key %q", routingKey)
+	}
+	return target
+}
+
+// queuePath returns the API path of a queue in the configured vhost
+func (b *rabbitMQBackend) queuePath(name string) string {
+	return "/api/queues/" + url.PathEscape(b.vhost) + "/" + url.PathEscape(name)
+}
+
+// apiError turns an error response into an error
+func apiError(resp *resty.Response, what string) error {
+	var body struct {
+		Error  string `json:"error"`
+		Reason string `json:"reason"`
+	}
+	if json.Unmarshal(resp.Body(), &body) == nil && body.Reason != "" {
+		return fmt.Errorf("rabbitmq API error (%s) for %s: %s", resp.Status(), what, body.Reason)
+	}
+	return fmt.Errorf("rabbitmq API error (%s) for %s", resp.Status(), what)
+}
+
+// getQueue fetches one queue
+func (b *rabbitMQBackend) getQueue(ctx context.Context, name string) (*rabbitQueue, error) {
+	var queue rabbitQueue
+	resp, err := b.client.R().SetContext(ctx).SetResult(&queue).Get(b.queuePath(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue %s: %w", name, err)
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, fmt.Errorf("queue %s not found in vhost %s", name, b.vhost)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp, "queue "+name)
+	}
+	return &queue, nil
+}
+
+// ListQueues lists the queues of the vhost
+func (b *rabbitMQBackend) ListQueues(ctx context.Context, pattern string, limit int) ([]Queue, bool, error) {
+	var result []rabbitQueue
+	resp, err := b.client.R().SetContext(ctx).SetResult(&result).Get("/api/queues/" + url.PathEscape(b.vhost))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list queues: %w", err)
+	}
+	if resp.IsError() {
+		return nil, false, apiError(resp, "vhost "+b.vhost)
+	}
+
+	var queues []Queue
+	for i := range result {
+		if !matchName(pattern, result[i].Name) {
+			continue
+		}
+		queues = append(queues, result[i].summary())
+	}
+	return queues, false, nil
+}
+
+// DescribeQueue describes a queue with its rates, settings and dead-letter queues
+func (b *rabbitMQBackend) DescribeQueue(ctx context.Context, name string) (*QueueDetail, error) {
+	q, err := b.getQueue(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &QueueDetail{Queue: q.summary(), Settings: map[string]interface{}{}}
+	if stats := q.MessageStats; stats != nil {
+		if stats.PublishDetails != nil {
+			detail.PublishRate = &stats.PublishDetails.Rate
+		}
+		if stats.DeliverGetDetails != nil {
+			detail.DeliverRate = &stats.DeliverGetDetails.Rate
+		}
+		if stats.AckDetails != nil {
+			detail.AckRate = &stats.AckDetails.Rate
+		}
+	}
+	for key, value := range q.Arguments {
+		detail.Settings[key] = value
+	}
+	if q.Policy != "" {
+		detail.Settings["policy"] = q.Policy
+		for key, value := range objectOf(q.EffectivePolicy) {
+			detail.Settings["policy."+key] = value
+		}
+	}
+	if detail.OldestMessageAgeSeconds == nil && q.MessagesReady > 0 {
+		detail.Notes = append(detail.Notes, "the oldest message's age is only known when publishers set the timestamp property")
+	}
+	if q.Consumers == 0 && q.MessagesReady > 0 {
+		detail.Notes = append(detail.Notes, "messages are waiting but the queue has no consumers")
+	}
+
+	exchange, routingKey, ok := q.deadLetter()
+	if !ok {
+		return detail, nil
+	}
+	names, err := b.deadLetterQueues(ctx, exchange, routingKey, name)
+	if err != nil {
+		detail.Notes = append(detail.Notes, fmt.Sprintf("dead-letter queues could not be resolved: %v", err))
+		return detail, nil
+	}
+	if len(names) == 0 {
+		detail.Notes = append(detail.Notes, fmt.Sprintf("no queue is bound to the dead-letter %s, so dead-lettered messages are dropped", describeDeadLetter(exchange, routingKey)))
+	}
+	for _, dlq := range names {
+		dq, err := b.getQueue(ctx, dlq)
+		if err != nil {
+			detail.Notes = append(detail.Notes, err.Error())
+			continue
+		}
+		detail.DeadLetter = append(detail.DeadLetter, dq.summary())
+	}
+	return detail, nil
+}
+
+// deadLetterQueues returns the queues a dead-letter exchange routes to. Bindings are matched on the
+// dead-letter routing key when one is set; otherwise messages keep their original routing keys, so
+// every bound queue is a candidate.
+func (b *rabbitMQBackend) deadLetterQueues(ctx context.Context, exchange, routingKey, source string) ([]string, error) {
+	if exchange == "" {
+		// The default exchange routes by queue name
+		if routingKey == "" {
+			return []string{source}, nil
+		}
+		return []string{routingKey}, nil
+	}
+
+	var bindings []struct {
+		Destination     string `json:"destination"`
+		DestinationType string `json:"destination_type"`
+		RoutingKey      string `json:"routing_key"`
+	}
+	resp, err := b.client.R().SetContext(ctx).SetResult(&bindings).
+		Get("/api/exchanges/" + url.PathEscape(b.vhost) + "/" + url.PathEscape(exchange) + "/bindings/source")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bindings of %s: %w", exchange, err)
+	}
+	if resp.IsError() {
+		return nil, apiError(resp, "exchange "+exchange)
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, binding := range bindings {
+		if binding.DestinationType != "queue" || seen[binding.Destination] {
+			continue
+		}
+		if routingKey != "" && binding.RoutingKey != "" && binding.RoutingKey != "#" && binding.RoutingKey != routingKey {
+			continue
+		}
+		seen[binding.Destination] = true
+		names = append(names, binding.Destination)
+		if len(names) == maxDeadLetterQueues {
+			break
+		}
+	}
+	return names, nil
+}
+
+// Peek gets messages with requeueing, so they stay in the queue (marked as redelivered)
+func (b *rabbitMQBackend) Peek(ctx context.Context, name string, count, maxBodyBytes int) ([]Message, []string, error) {
+	q, err := b.getQueue(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if q.Type == "stream" {
+		return nil, nil, fmt.Errorf("queue %s is a stream; streams cannot be peeked through the management API", name)
+	}
+
+	warnings := []string{"peeked messages were requeued and are now marked as redelivered"}
+	if q.Type == "quorum" {
+		limit := "the broker's default"
+		if v, ok := q.setting("x-delivery-limit", "delivery-limit"); ok {
+			limit = fmt.Sprint(v)
+		}
+		warnings = append(warnings, fmt.Sprintf("on quorum queues every peek counts as a delivery attempt; once a message exceeds the delivery limit (%s) it is dead-lettered or dropped", limit))
+	}
+
+	var result []struct {
+		PayloadBytes    int             `json:"payload_bytes"`
+		Redelivered     bool            `json:"redelivered"`
+		Exchange        string          `json:"exchange"`
+		RoutingKey      string          `json:"routing_key"`
+		Properties      json.RawMessage `json:"properties"`
+		Payload         string          `json:"payload"`
+		PayloadEncoding string          `json:"payload_encoding"`
+	}
+	resp, err := b.client.R().SetContext(ctx).
+		SetBody(map[string]interface{}{
+			"count":    count,
+			"ackmode":  "ack_requeue_true",
+			"encoding": "auto",
+			"truncate": maxBodyBytes,
+		}).
+		SetResult(&result).
+		Post(b.queuePath(name) + "/get")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to peek queue %s: %w", name, err)
+	}
+	if resp.IsError() {
+		return nil, nil, apiError(resp, "queue "+name)
+	}
+
+	messages := make([]Message, 0, len(result))
+	for _, r := range result {
+		m := Message{Redelivered: r.Redelivered, Exchange: r.Exchange, RoutingKey: r.RoutingKey}
+		body := []byte(r.Payload)
+		if r.PayloadEncoding == "base64" {
+			if decoded, err := base64.StdEncoding.DecodeString(r.Payload); err == nil {
+				body = decoded
+			}
+		}
+		showBody(&m, body, r.PayloadBytes, maxBodyBytes)
+
+		properties := objectOf(r.Properties)
+		if id, ok := properties["message_id"].(string); ok {
+			m.ID = id
+		}
+		if ts, ok := properties["timestamp"].(float64); ok && ts > 0 {
+			sent := time.Unix(int64(ts), 0).UTC()
+			m.SentAt = &sent
+		}
+		if headers, ok := properties["headers"].(map[string]interface{}); ok && len(headers) > 0 {
+			m.Headers = headers
+		}
+		messages = append(messages, m)
+	}
+	return messages, warnings, nil
+}
+
+// Ping checks that the management API answers
+func (b *rabbitMQBackend) Ping(ctx context.Context) error {
+	resp, err := b.client.R().SetContext(ctx).Get("/api/overview")
+	if err != nil {
+		return fmt.Errorf("failed to reach the management API: %w", err)
+	}
+	if resp.IsError() {
+		return apiError(resp, "overview")
+	}
+	return nil
+}
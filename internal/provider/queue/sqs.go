@@ -0,0 +1,433 @@
+package queue
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/aws"
+)
+
+const (
+	// peekVisibilitySeconds hides peeked messages while a peek collects them; they are made visible
+	// again right after, and at the latest when this runs out
+	peekVisibilitySeconds = 30
+	// oldestMetricWindow is how far back the ApproximateAgeOfOldestMessage metric is read
+	oldestMetricWindow = 15 * time.Minute
+)
+
+// summaryAttributes are the queue attributes read for listings
+var summaryAttributes = []sqstypes.QueueAttributeName{
+	sqstypes.QueueAttributeNameApproximateNumberOfMessages,
+	sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+	sqstypes.QueueAttributeNameApproximateNumberOfMessagesDelayed,
+	sqstypes.QueueAttributeNameRedrivePolicy,
+}
+
+// sqsBackend reads queues with the SQS API and the queue age metric from CloudWatch
+type sqsBackend struct {
+	client *sqs.Client
+	aws    awssdk.Config
+	prefix string
+	http   *http.Client
+}
+
+func newSQSBackend(cfg *config.SQSConfig, awsCfg *config.AWSConfig, s3Cfg *config.S3Config) (*sqsBackend, error) {
+	resolved := config.AWSConfig{}
+	if awsCfg != nil {
+		resolved = *awsCfg
+	}
+	if cfg.Region != "" {
+		resolved.Region = cfg.Region
+	}
+	awsConfig, err := aws.LoadConfig(&resolved, s3Cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqsBackend{
+		client: sqs.NewFromConfig(awsConfig),
+		aws:    awsConfig,
+		prefix: cfg.QueuePrefix,
+		http:   &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (b *sqsBackend) Name() string { return "sqs" }
+
+// redrivePolicy is the JSON RedrivePolicy attribute of a queue with a dead-letter queue
+type redrivePolicy struct {
+	DeadLetterTargetArn string      `json:"deadLetterTargetArn"`
+	MaxReceiveCount     json.Number `json:"maxReceiveCount"`
+}
+
+func parseRedrivePolicy(attribute string) (*redrivePolicy, bool) {
+	if attribute == "" {
+		return nil, false
+	}
+	var policy redrivePolicy
+	if err := json.Unmarshal([]byte(attribute), &policy); err != nil || policy.DeadLetterTargetArn == "" {
+		return nil, false
+	}
+	return &policy, true
+}
+
+// queueName returns the last segment of a queue URL or ARN
+func queueName(urlOrArn string) string {
+	if i := strings.LastIndexAny(urlOrArn, "/:"); i >= 0 {
+		return urlOrArn[i+1:]
+	}
+	return urlOrArn
+}
+
+func summaryOf(name string, attributes map[string]string) Queue {
+	number := func(key sqstypes.QueueAttributeName) int64 {
+		n, _ := strconv.ParseInt(attributes[string(key)], 10, 64)
+		return n
+	}
+	queue := Queue{
+		System:   "sqs",
+		Name:     name,
+		Type:     "standard",
+		Depth:    number(sqstypes.QueueAttributeNameApproximateNumberOfMessages),
+		InFlight: number(sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+		Delayed:  number(sqstypes.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+	}
+	if strings.HasSuffix(name, ".fifo") {
+		queue.Type = "fifo"
+	}
+	if policy, ok := parseRedrivePolicy(attributes[string(sqstypes.QueueAttributeNameRedrivePolicy)]); ok {
+		queue.DeadLetterTarget = queueName(policy.DeadLetterTargetArn)
+	}
+	return queue
+}
+
+// queueURL resolves a queue name (or URL) to its URL
+func (b *sqsBackend) queueURL(ctx context.Context, name string) (string, error) {
+	if strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "http://") {
+		return name, nil
+	}
+	out, err := b.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: awssdk.String(name)})
+	if err != nil {
+		return "", fmt.Errorf("queue %s not found: %w", name, err)
+	}
+	return awssdk.ToString(out.QueueUrl), nil
+}
+
+func (b *sqsBackend) attributes(ctx context.Context, queueURL string, names []sqstypes.QueueAttributeName) (map[string]string, error) {
+	out, err := b.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       awssdk.String(queueURL),
+		AttributeNames: names,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes of %s: %w", queueName(queueURL), err)
+	}
+	return out.Attributes, nil
+}
+
+// ListQueues lists queues under the configured prefix whose names match pattern, reading the
+// attributes of at most limit of them
+func (b *sqsBackend) ListQueues(ctx context.Context, pattern string, limit int) ([]Queue, bool, error) {
+	input := &sqs.ListQueuesInput{MaxResults: awssdk.Int32(1000)}
+	if b.prefix != "" {
+		input.QueueNamePrefix = awssdk.String(b.prefix)
+	}
+
+	var urls []string
+	truncated := false
+	paginator := sqs.NewListQueuesPaginator(b.client, input)
+	for paginator.HasMorePages() && !truncated {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to list queues: %w", err)
+		}
+		for _, queueURL := range page.QueueUrls {
+			if !matchName(pattern, queueName(queueURL)) {
+				continue
+			}
+			if len(urls) == limit {
+				truncated = true
+				break
+			}
+			urls = append(urls, queueURL)
+		}
+	}
+
+	queues := make([]Queue, 0, len(urls))
+	for _, queueURL := range urls {
+		attributes, err := b.attributes(ctx, queueURL, summaryAttributes)
+		if err != nil {
+			return nil, false, err
+		}
+		queues = append(queues, summaryOf(queueName(queueURL), attributes))
+	}
+	return queues, truncated, nil
+}
+
+// DescribeQueue describes a queue with its oldest message age, redrive policy and dead-letter relations
+func (b *sqsBackend) DescribeQueue(ctx context.Context, name string) (*QueueDetail, error) {
+	queueURL, err := b.queueURL(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := b.attributes(ctx, queueURL, []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameAll})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &QueueDetail{Queue: summaryOf(queueName(queueURL), attributes), Settings: map[string]interface{}{}}
+	for _, key := range []sqstypes.QueueAttributeName{
+		sqstypes.QueueAttributeNameVisibilityTimeout,
+		sqstypes.QueueAttributeNameMessageRetentionPeriod,
+		sqstypes.QueueAttributeNameDelaySeconds,
+		sqstypes.QueueAttributeNameReceiveMessageWaitTimeSeconds,
+		sqstypes.QueueAttributeNameMaximumMessageSize,
+		sqstypes.QueueAttributeNameContentBasedDeduplication,
+		sqstypes.QueueAttributeNameRedriveAllowPolicy,
+	} {
+		if value, ok := attributes[string(key)]; ok {
+			detail.Settings[string(key)] = value
+		}
+	}
+
+	age, asOf, err := b.oldestMessageAge(ctx, detail.Name)
+	switch {
+	case err != nil:
+		detail.Notes = append(detail.Notes, fmt.Sprintf("oldest message age unavailable: %v", err))
+	case age != nil:
+		detail.OldestMessageAgeSeconds = age
+		detail.OldestAsOf = asOf
+	default:
+		detail.Notes = append(detail.Notes, "no ApproximateAgeOfOldestMessage datapoint in the last 15 minutes")
+	}
+
+	if policy, ok := parseRedrivePolicy(attributes[string(sqstypes.QueueAttributeNameRedrivePolicy)]); ok {
+		detail.MaxReceives, _ = strconv.Atoi(policy.MaxReceiveCount.String())
+		dlqURL, err := b.queueURL(ctx, queueName(policy.DeadLetterTargetArn))
+		if err == nil {
+			var dlqAttributes map[string]string
+			dlqAttributes, err = b.attributes(ctx, dlqURL, summaryAttributes)
+			if err == nil {
+				detail.DeadLetter = append(detail.DeadLetter, summaryOf(queueName(dlqURL), dlqAttributes))
+			}
+		}
+		if err != nil {
+			detail.Notes = append(detail.Notes, fmt.Sprintf("dead-letter queue: %v", err))
+		}
+	}
+
+	sources, err := b.client.ListDeadLetterSourceQueues(ctx, &sqs.ListDeadLetterSourceQueuesInput{
+		QueueUrl:   awssdk.String(queueURL),
+		MaxResults: awssdk.Int32(100),
+	})
+	if err != nil {
+		detail.Notes = append(detail.Notes, fmt.Sprintf("dead-letter sources unavailable: %v", err))
+	} else {
+		for _, source := range sources.QueueUrls {
+			detail.DeadLetterFrom = append(detail.DeadLetterFrom, queueName(source))
+		}
+	}
+	return detail, nil
+}
+
+// oldestMessageAge reads the latest ApproximateAgeOfOldestMessage datapoint of a queue from CloudWatch.
+// The query API is called directly, signed with the SDK's credentials.
+func (b *sqsBackend) oldestMessageAge(ctx context.Context, name string) (*float64, *time.Time, error) {
+	now := time.Now().UTC()
+	query := url.Values{
+		"Action":                    {"GetMetricStatistics"},
+		"Version":                   {"2010-08-01"},
+		"Namespace":                 {"AWS/SQS"},
+		"MetricName":                {"ApproximateAgeOfOldestMessage"},
+		"Dimensions.member.1.Name":  {"QueueName"},
+		"Dimensions.member.1.Value": {name},
+		"StartTime":                 {now.Add(-oldestMetricWindow).Format(time.RFC3339)},
+		"EndTime":                   {now.Format(time.RFC3339)},
+		"Period":                    {"60"},
+		"Statistics.member.1":       {"Maximum"},
+	}
+	endpoint := fmt.Sprintf("https://monitoring.%s.amazonaws.com/?%s", b.aws.Region, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentials, err := b.aws.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("no AWS credentials: %w", err)
+	}
+	emptyHash := sha256.Sum256(nil)
+	if err := v4.NewSigner().SignHTTP(ctx, credentials, req, hex.EncodeToString(emptyHash[:]), "monitoring", b.aws.Region, now); err != nil {
+		return nil, nil, fmt.Errorf("failed to sign CloudWatch request: %w", err)
+	}
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CloudWatch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("CloudWatch returned %s (the credentials need cloudwatch:GetMetricStatistics)", resp.Status)
+	}
+
+	var result struct {
+		Datapoints []struct {
+			Timestamp time.Time `xml:"Timestamp"`
+			Maximum   float64   `xml:"Maximum"`
+		} `xml:"GetMetricStatisticsResult>Datapoints>member"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("invalid CloudWatch response: %w", err)
+	}
+	if len(result.Datapoints) == 0 {
+		return nil, nil, nil
+	}
+	latest := result.Datapoints[0]
+	for _, point := range result.Datapoints[1:] {
+		if point.Timestamp.After(latest.Timestamp) {
+			latest = point
+		}
+	}
+	return &latest.Maximum, &latest.Timestamp, nil
+}
+
+// Peek receives messages with a short visibility timeout and makes them visible again at once.
+// Receiving counts towards a redrive policy's maxReceiveCount, so queues with one are refused:
+// peeking them could move messages to their dead-letter queue.
+func (b *sqsBackend) Peek(ctx context.Context, name string, count, maxBodyBytes int) ([]Message, []string, error) {
+	queueURL, err := b.queueURL(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	attributes, err := b.attributes(ctx, queueURL, []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameRedrivePolicy})
+	if err != nil {
+		return nil, nil, err
+	}
+	if policy, ok := parseRedrivePolicy(attributes[string(sqstypes.QueueAttributeNameRedrivePolicy)]); ok {
+		return nil, nil, fmt.Errorf("queue %s has a redrive policy (maxReceiveCount %s): peeking counts as a receive and could move messages to %s; peek that dead-letter queue instead",
+			name, policy.MaxReceiveCount, queueName(policy.DeadLetterTargetArn))
+	}
+
+	var received []sqstypes.Message
+	seen := map[string]bool{}
+	for len(received) < count {
+		out, err := b.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:                    awssdk.String(queueURL),
+			MaxNumberOfMessages:         int32(min(count-len(received), 10)),
+			VisibilityTimeout:           peekVisibilitySeconds,
+			WaitTimeSeconds:             1,
+			MessageAttributeNames:       []string{"All"},
+			MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{sqstypes.MessageSystemAttributeNameAll},
+		})
+		if err != nil {
+			if len(received) > 0 {
+				break
+			}
+			return nil, nil, fmt.Errorf("failed to receive from %s: %w", name, err)
+		}
+		fresh := 0
+		for _, m := range out.Messages {
+			if id := awssdk.ToString(m.MessageId); !seen[id] {
+				seen[id] = true
+				received = append(received, m)
+				fresh++
+			}
+		}
+		if fresh == 0 {
+			break
+		}
+	}
+
+	var warnings []string
+	if err := b.release(ctx, queueURL, received); err != nil {
+		warnings = append(warnings, fmt.Sprintf("some messages stay invisible for up to %d seconds: %v", peekVisibilitySeconds, err))
+	}
+	if len(received) > 0 {
+		warnings = append(warnings, "peeking counts as a receive: ApproximateReceiveCount of these messages went up by one")
+	}
+
+	messages := make([]Message, 0, len(received))
+	for _, r := range received {
+		m := Message{ID: awssdk.ToString(r.MessageId), Attributes: map[string]interface{}{}}
+		body := awssdk.ToString(r.Body)
+		showBody(&m, []byte(body), len(body), maxBodyBytes)
+		for key, value := range r.Attributes {
+			switch key {
+			case string(sqstypes.MessageSystemAttributeNameSentTimestamp):
+				if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+					sent := time.UnixMilli(ms).UTC()
+					m.SentAt = &sent
+				}
+			case string(sqstypes.MessageSystemAttributeNameApproximateReceiveCount):
+				m.ReceiveCount, _ = strconv.Atoi(value)
+			default:
+				m.Attributes[key] = value
+			}
+		}
+		for key, value := range r.MessageAttributes {
+			switch {
+			case value.StringValue != nil:
+				m.Attributes[key] = awssdk.ToString(value.StringValue)
+			case value.BinaryValue != nil:
+				m.Attributes[key] = value.BinaryValue
+			}
+		}
+		if len(m.Attributes) == 0 {
+			m.Attributes = nil
+		}
+		messages = append(messages, m)
+	}
+	return messages, warnings, nil
+}
+
+// release makes received messages visible again
+func (b *sqsBackend) release(ctx context.Context, queueURL string, messages []sqstypes.Message) error {
+	for start := 0; start < len(messages); start += 10 {
+		batch := messages[start:min(start+10, len(messages))]
+		entries := make([]sqstypes.ChangeMessageVisibilityBatchRequestEntry, 0, len(batch))
+		for i, m := range batch {
+			entries = append(entries, sqstypes.ChangeMessageVisibilityBatchRequestEntry{
+				Id:                awssdk.String(strconv.Itoa(start + i)),
+				ReceiptHandle:     m.ReceiptHandle,
+				VisibilityTimeout: 0,
+			})
+		}
+		out, err := b.client.ChangeMessageVisibilityBatch(ctx, &sqs.ChangeMessageVisibilityBatchInput{
+			QueueUrl: awssdk.String(queueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.Failed) > 0 {
+			return fmt.Errorf("%s", awssdk.ToString(out.Failed[0].Message))
+		}
+	}
+	return nil
+}
+
+// Ping checks that SQS answers with the configured credentials
+func (b *sqsBackend) Ping(ctx context.Context) error {
+	input := &sqs.ListQueuesInput{MaxResults: awssdk.Int32(1)}
+	if b.prefix != "" {
+		input.QueueNamePrefix = awssdk.String(b.prefix)
+	}
+	if _, err := b.client.ListQueues(ctx, input); err != nil {
+		return fmt.Errorf("failed to list queues: %w", err)
+	}
+	return nil
+}
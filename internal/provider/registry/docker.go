@@ -0,0 +1,334 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+const manifestAccept = "application/vnd.oci.image.index.v1+json, " +
+	"application/vnd.docker.distribution.manifest.list.v2+json, " +
+	"application/vnd.oci.image.manifest.v1+json, " +
+	"application/vnd.docker.distribution.manifest.v2+json"
+
+// dockerBackend talks to a Docker Registry HTTP API V2 (Docker Hub, GHCR, ECR, Harbor...)
+type dockerBackend struct {
+	client   *resty.Client
+	username string
+	password string
+
+	mu     sync.Mutex
+	tokens map[string]string // scope -> bearer token
+}
+
+func newDockerBackend(cfg *config.RegistryConfig) *dockerBackend {
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.URL, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+
+	b := &dockerBackend{
+		client:   client,
+		username: cfg.Username,
+		password: cfg.Password,
+		tokens:   map[string]string{},
+	}
+	if cfg.Token != "" {
+		b.tokens[""] = cfg.Token
+	}
+	return b
+}
+
+type dockerDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	Platform  *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+		Variant      string `json:"variant,omitempty"`
+	} `json:"platform,omitempty"`
+}
+
+type dockerManifest struct {
+	MediaType string             `json:"mediaType"`
+	Config    dockerDescriptor   `json:"config"`
+	Layers    []dockerDescriptor `json:"layers"`
+	Manifests []dockerDescriptor `json:"manifests"` // set for image indexes / manifest lists
+}
+
+type dockerImageConfig struct {
+	Architecture string     `json:"architecture"`
+	OS           string     `json:"os"`
+	Created      *time.Time `json:"created"`
+	Config       struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+// get performs a GET, answering a Bearer challenge with a token request when needed
+func (b *dockerBackend) get(path, scope string, headers map[string]string) (*resty.Response, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		req := b.client.R().SetHeaders(headers)
+		if token := b.token(scope); token != "" {
+			req.SetAuthToken(token)
+		} else if b.username != "" {
+			req.SetBasicAuth(b.username, b.password)
+		}
+
+		resp, err := req.Get(path)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode() != 401 || attempt > 0 {
+			return resp, nil
+		}
+
+		challenge := resp.Header().Get("WWW-Authenticate")
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return resp, nil
+		}
+		if err := b.fetchToken(challenge, scope); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("registry authentication failed")
+}
+
+func (b *dockerBackend) token(scope string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.tokens[scope]; ok {
+		return t
+	}
+	return b.tokens[""]
+}
+
+// fetchToken requests a bearer token from the realm named in a WWW-Authenticate challenge
+func (b *dockerBackend) fetchToken(challenge, scope string) error {
+	params := parseChallenge(challenge[len("bearer "):])
+	realm := params["realm"]
+	if realm == "" {
+		return fmt.Errorf("registry auth challenge has no realm")
+	}
+
+	query := url.Values{}
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if params["scope"] != "" {
+		query.Set("scope", params["scope"])
+	} else if scope != "" {
+		query.Set("scope", scope)
+	}
+
+	req := resty.New().SetTimeout(30 * time.Second).R()
+	if b.username != "" {
+		req.SetBasicAuth(b.username, b.password)
+	}
+
+	var result struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	resp, err := req.Get(realm + "?" + query.Encode())
+	if err != nil {
+		return fmt.Errorf("failed to fetch registry token: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("registry token request failed: %s", resp.Status())
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return fmt.Errorf("failed to decode registry token: %w", err)
+	}
+
+	token := result.Token
+	if token == "" {
+		token = result.AccessToken
+	}
+	b.mu.Lock()
+	b.tokens[scope] = token
+	b.mu.Unlock()
+	return nil
+}
+
+// parseChallenge parses `realm="...",service="...",scope="..."`
+func parseChallenge(s string) map[string]string {
+	out := map[string]string{}
+	for _, part := range splitChallenge(s) {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			out[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return out
+}
+
+// splitChallenge splits on commas that are not inside quotes (scopes may contain commas)
+func splitChallenge(s string) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func pullScope(repository string) string {
+	return fmt.Sprintf("repository:%s:pull", repository)
+}
+
+// ListTags lists image tags, following Link-header pagination
+func (b *dockerBackend) ListTags(repository string, limit int) ([]Tag, error) {
+	var tags []Tag
+	path := fmt.Sprintf("/v2/%s/tags/list?n=%d", repository, limit)
+
+	for path != "" && len(tags) < limit {
+		resp, err := b.get(path, pullScope(repository), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		if resp.IsError() {
+			return nil, fmt.Errorf("registry API error: %s", resp.Status())
+		}
+
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.Unmarshal(resp.Body(), &page); err != nil {
+			return nil, fmt.Errorf("failed to decode tag list: %w", err)
+		}
+		for _, t := range page.Tags {
+			tags = append(tags, Tag{Name: t})
+		}
+		path = nextLink(resp.Header().Get("Link"))
+	}
+
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags, nil
+}
+
+// nextLink extracts the target of a `<...>; rel="next"` Link header
+func nextLink(link string) string {
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return link[start+1 : end]
+}
+
+// Inspect resolves a tag to a manifest (choosing a platform for multi-arch images) and reads its config
+func (b *dockerBackend) Inspect(repository, tag, platform string) (*ArtifactInfo, error) {
+	scope := pullScope(repository)
+	manifest, digest, mediaType, err := b.manifest(repository, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ArtifactInfo{
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+		MediaType:  mediaType,
+	}
+
+	if len(manifest.Manifests) > 0 {
+		if platform == "" {
+			platform = "linux/amd64"
+		}
+		var chosen *dockerDescriptor
+		for i := range manifest.Manifests {
+			m := &manifest.Manifests[i]
+			if m.Platform == nil || m.Platform.OS == "unknown" {
+				continue
+			}
+			p := m.Platform.OS + "/" + m.Platform.Architecture
+			if m.Platform.Variant != "" {
+				p += "/" + m.Platform.Variant
+			}
+			info.Platforms = append(info.Platforms, p)
+			if chosen == nil && strings.HasPrefix(p, platform) {
+				chosen = m
+			}
+		}
+		if chosen == nil {
+			return nil, fmt.Errorf("platform %s not found in image index (available: %v)", platform, info.Platforms)
+		}
+		manifest, _, _, err = b.manifest(repository, chosen.Digest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	info.Layers = len(manifest.Layers)
+	for _, layer := range manifest.Layers {
+		info.Size += layer.Size
+	}
+
+	if manifest.Config.Digest != "" {
+		resp, err := b.get(fmt.Sprintf("/v2/%s/blobs/%s", repository, manifest.Config.Digest), scope, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch image config: %w", err)
+		}
+		if resp.IsError() {
+			return nil, fmt.Errorf("registry API error fetching image config: %s", resp.Status())
+		}
+		var cfg dockerImageConfig
+		if err := json.Unmarshal(resp.Body(), &cfg); err != nil {
+			return nil, fmt.Errorf("failed to decode image config: %w", err)
+		}
+		info.Architecture = cfg.Architecture
+		info.OS = cfg.OS
+		info.Created = cfg.Created
+		info.Labels = cfg.Config.Labels
+	}
+
+	return info, nil
+}
+
+// manifest fetches a manifest by tag or digest
+func (b *dockerBackend) manifest(repository, reference string) (*dockerManifest, string, string, error) {
+	resp, err := b.get(fmt.Sprintf("/v2/%s/manifests/%s", repository, reference), pullScope(repository),
+		map[string]string{"Accept": manifestAccept})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	if resp.StatusCode() == 404 {
+		return nil, "", "", fmt.Errorf("manifest not found: %s:%s", repository, reference)
+	}
+	if resp.IsError() {
+		return nil, "", "", fmt.Errorf("registry API error: %s", resp.Status())
+	}
+
+	var m dockerManifest
+	if err := json.Unmarshal(resp.Body(), &m); err != nil {
+		return nil, "", "", fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	mediaType := m.MediaType
+	if mediaType == "" {
+		mediaType = resp.Header().Get("Content-Type")
+	}
+	return &m, resp.Header().Get("Docker-Content-Digest"), mediaType, nil
+}
@@ -0,0 +1,170 @@
+package registry
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+// mavenBackend reads Maven repository metadata (Maven Central, Nexus, Artifactory...)
+type mavenBackend struct {
+	client *resty.Client
+}
+
+func newMavenBackend(cfg *config.RegistryConfig) *mavenBackend {
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.URL, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+	if cfg.Token != "" {
+		client.SetAuthToken(cfg.Token)
+	} else if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	return &mavenBackend{client: client}
+}
+
+type mavenMetadata struct {
+	Versioning struct {
+		Latest      string   `xml:"latest"`
+		Release     string   `xml:"release"`
+		Versions    []string `xml:"versions>version"`
+		LastUpdated string   `xml:"lastUpdated"`
+	} `xml:"versioning"`
+}
+
+type mavenPOM struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description"`
+	Packaging   string `xml:"packaging"`
+	SCM         struct {
+		URL string `xml:"url"`
+		Tag string `xml:"tag"`
+	} `xml:"scm"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+}
+
+// artifactPath turns "group.id:artifact-id" into "group/id/artifact-id"
+func artifactPath(coordinates string) (string, string, error) {
+	parts := strings.Split(coordinates, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("maven repository must be groupId:artifactId, got %q", coordinates)
+	}
+	return strings.ReplaceAll(parts[0], ".", "/") + "/" + parts[1], parts[1], nil
+}
+
+// ListTags lists versions from maven-metadata.xml, newest first
+func (b *mavenBackend) ListTags(coordinates string, limit int) ([]Tag, error) {
+	path, _, err := artifactPath(coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.R().Get("/" + path + "/maven-metadata.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maven metadata: %w", err)
+	}
+	if resp.StatusCode() == 404 {
+		return nil, fmt.Errorf("artifact not found: %s", coordinates)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("maven repository error: %s", resp.Status())
+	}
+
+	var meta mavenMetadata
+	if err := xml.Unmarshal(resp.Body(), &meta); err != nil {
+		return nil, fmt.Errorf("failed to decode maven metadata: %w", err)
+	}
+
+	versions := meta.Versioning.Versions
+	tags := make([]Tag, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		tag := Tag{Name: versions[i]}
+		if versions[i] == meta.Versioning.Latest {
+			tag.Aliases = append(tag.Aliases, "latest")
+		}
+		if versions[i] == meta.Versioning.Release {
+			tag.Aliases = append(tag.Aliases, "release")
+		}
+		tags = append(tags, tag)
+		if len(tags) >= limit {
+			break
+		}
+	}
+	return tags, nil
+}
+
+// Inspect reads the POM of a version; the SCM tag or a git revision property is reported as the revision
+func (b *mavenBackend) Inspect(coordinates, version, platform string) (*ArtifactInfo, error) {
+	path, artifact, err := artifactPath(coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.R().Get(fmt.Sprintf("/%s/%s/%s-%s.pom", path, version, artifact, version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pom: %w", err)
+	}
+	if resp.StatusCode() == 404 {
+		return nil, fmt.Errorf("version %s not found for %s", version, coordinates)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("maven repository error: %s", resp.Status())
+	}
+
+	var pom mavenPOM
+	if err := xml.Unmarshal(resp.Body(), &pom); err != nil {
+		return nil, fmt.Errorf("failed to decode pom: %w", err)
+	}
+
+	info := &ArtifactInfo{
+		Repository: coordinates,
+		Tag:        version,
+		MediaType:  pom.Packaging,
+		Labels:     map[string]string{},
+	}
+	if pom.Name != "" {
+		info.Labels["name"] = pom.Name
+	}
+	if pom.SCM.URL != "" {
+		info.Labels["scm.url"] = pom.SCM.URL
+	}
+	if pom.SCM.Tag != "" && pom.SCM.Tag != "HEAD" {
+		info.Revision = pom.SCM.Tag
+	}
+	for _, prop := range pom.Properties.Entries {
+		info.Labels[prop.XMLName.Local] = strings.TrimSpace(prop.Value)
+	}
+	if sha1 := b.checksum(fmt.Sprintf("/%s/%s/%s-%s.pom.sha1", path, version, artifact, version)); sha1 != "" {
+		info.Digest = "sha1:" + sha1
+	}
+	if lastModified := resp.Header().Get("Last-Modified"); lastModified != "" {
+		if t, err := time.Parse(time.RFC1123, lastModified); err == nil {
+			info.Created = &t
+		}
+	}
+	return info, nil
+}
+
+// checksum fetches a published checksum file, returning "" when absent
+func (b *mavenBackend) checksum(path string) string {
+	resp, err := b.client.R().Get(path)
+	if err != nil || resp.IsError() {
+		return ""
+	}
+	fields := strings.Fields(resp.String())
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,148 @@
+package registry
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+)
+
+// npmBackend talks to an npm registry (registry.npmjs.org, Verdaccio, Artifactory...)
+type npmBackend struct {
+	client *resty.Client
+}
+
+func newNPMBackend(cfg *config.RegistryConfig) *npmBackend {
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.URL, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(30 * time.Second)
+	if cfg.Token != "" {
+		client.SetAuthToken(cfg.Token)
+	} else if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	return &npmBackend{client: client}
+}
+
+type npmPackument struct {
+	DistTags map[string]string     `json:"dist-tags"`
+	Time     map[string]time.Time  `json:"time"`
+	Versions map[string]npmVersion `json:"versions"`
+}
+
+type npmVersion struct {
+	Version string `json:"version"`
+	GitHead string `json:"gitHead"`
+	Dist    struct {
+		Shasum       string `json:"shasum"`
+		Integrity    string `json:"integrity"`
+		Tarball      string `json:"tarball"`
+		UnpackedSize int64  `json:"unpackedSize"`
+		FileCount    int    `json:"fileCount"`
+	} `json:"dist"`
+	Repository interface{} `json:"repository"`
+}
+
+// packument fetches the full package document; scoped names keep their "@" and encode the slash
+func (b *npmBackend) packument(pkg string) (*npmPackument, error) {
+	var doc npmPackument
+	resp, err := b.client.R().
+		SetResult(&doc).
+		Get("/" + strings.Replace(url.PathEscape(pkg), "%40", "@", 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch package: %w", err)
+	}
+	if resp.StatusCode() == 404 {
+		return nil, fmt.Errorf("package not found: %s", pkg)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("npm registry error: %s", resp.Status())
+	}
+	return &doc, nil
+}
+
+// ListTags lists published versions, newest first, with the dist-tags pointing at them
+func (b *npmBackend) ListTags(pkg string, limit int) ([]Tag, error) {
+	doc, err := b.packument(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := map[string][]string{}
+	for tag, version := range doc.DistTags {
+		aliases[version] = append(aliases[version], tag)
+	}
+
+	tags := make([]Tag, 0, len(doc.Versions))
+	for version := range doc.Versions {
+		tag := Tag{Name: version, Aliases: aliases[version]}
+		sort.Strings(tag.Aliases)
+		if t, ok := doc.Time[version]; ok {
+			published := t
+			tag.Published = &published
+		}
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Published != nil && tags[j].Published != nil {
+			return tags[i].Published.After(*tags[j].Published)
+		}
+		return tags[i].Name > tags[j].Name
+	})
+
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags, nil
+}
+
+// Inspect returns metadata of a version or dist-tag; gitHead is reported as the build revision
+func (b *npmBackend) Inspect(pkg, tag, platform string) (*ArtifactInfo, error) {
+	doc, err := b.packument(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	version := tag
+	if v, ok := doc.DistTags[tag]; ok {
+		version = v
+	}
+	meta, ok := doc.Versions[version]
+	if !ok {
+		return nil, fmt.Errorf("version %s not found for %s", tag, pkg)
+	}
+
+	info := &ArtifactInfo{
+		Repository: pkg,
+		Tag:        version,
+		Digest:     meta.Dist.Integrity,
+		Revision:   meta.GitHead,
+		Size:       meta.Dist.UnpackedSize,
+		Labels: map[string]string{
+			"shasum":  meta.Dist.Shasum,
+			"tarball": meta.Dist.Tarball,
+		},
+	}
+	if info.Digest == "" {
+		info.Digest = "sha1:" + meta.Dist.Shasum
+	}
+	if meta.Dist.FileCount > 0 {
+		info.Labels["file_count"] = fmt.Sprintf("%d", meta.Dist.FileCount)
+	}
+	for distTag, v := range doc.DistTags {
+		if v == version {
+			info.Labels["dist-tag."+distTag] = v
+		}
+	}
+	if t, ok := doc.Time[version]; ok {
+		created := t
+		info.Created = &created
+	}
+	return info, nil
+}
@@ -0,0 +1,237 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/logging"
+)
+
+// revisionLabels are the labels/fields commonly used to record the source commit of a build
+var revisionLabels = []string{
+	"org.opencontainers.image.revision",
+	"org.label-schema.vcs-ref",
+	"vcs-ref",
+	"git_commit",
+	"git-commit",
+	"GIT_COMMIT",
+	"git.commit.id",
+}
+
+// Tag is a tag or version published in a registry
+type Tag struct {
+	Name      string     `json:"name"`
+	Published *time.Time `json:"published,omitempty"`
+	Aliases   []string   `json:"aliases,omitempty"` // e.g. npm dist-tags pointing at this version
+}
+
+// ArtifactInfo describes a single tagged artifact
+type ArtifactInfo struct {
+	Registry     string            `json:"registry"`
+	Repository   string            `json:"repository"`
+	Tag          string            `json:"tag"`
+	Digest       string            `json:"digest,omitempty"`
+	MediaType    string            `json:"media_type,omitempty"`
+	Created      *time.Time        `json:"created,omitempty"`
+	Revision     string            `json:"revision,omitempty"` // Build commit
+	Architecture string            `json:"architecture,omitempty"`
+	OS           string            `json:"os,omitempty"`
+	Platforms    []string          `json:"platforms,omitempty"`
+	Size         int64             `json:"size,omitempty"`
+	Layers       int               `json:"layers,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// Comparison is the metadata diff between two tags
+type Comparison struct {
+	A             *ArtifactInfo        `json:"a"`
+	B             *ArtifactInfo        `json:"b"`
+	SameDigest    bool                 `json:"same_digest"`
+	SameRevision  bool                 `json:"same_revision"`
+	SizeDelta     int64                `json:"size_delta,omitempty"`
+	CreatedDelta  string               `json:"created_delta,omitempty"`
+	LabelsAdded   map[string]string    `json:"labels_added,omitempty"`
+	LabelsRemoved map[string]string    `json:"labels_removed,omitempty"`
+	LabelsChanged map[string][2]string `json:"labels_changed,omitempty"`
+}
+
+// Backend is implemented by each registry type
+type Backend interface {
+	// ListTags lists the tags/versions of a repository or package
+	ListTags(repository string, limit int) ([]Tag, error)
+	// Inspect returns metadata for a single tag/version
+	Inspect(repository, tag, platform string) (*ArtifactInfo, error)
+}
+
+// RegistryClient dispatches requests to the configured registries
+type RegistryClient struct {
+	backends map[string]Backend
+	types    map[string]string
+	logger   *logging.Logger
+}
+
+// NewRegistryClient creates a client with a backend for every configured registry
+func NewRegistryClient(cfgs []config.RegistryConfig) *RegistryClient {
+	c := &RegistryClient{
+		backends: map[string]Backend{},
+		types:    map[string]string{},
+		logger:   logging.New("RegistryClient"),
+	}
+
+	for i := range cfgs {
+		cfg := &cfgs[i]
+		if cfg.Name == "" || cfg.URL == "" {
+			continue
+		}
+		registryType := strings.ToLower(cfg.Type)
+		switch registryType {
+		case "docker", "":
+			registryType = "docker"
+			c.backends[cfg.Name] = newDockerBackend(cfg)
+		case "npm":
+			c.backends[cfg.Name] = newNPMBackend(cfg)
+		case "maven":
+			c.backends[cfg.Name] = newMavenBackend(cfg)
+		default:
+			c.logger.Warn("skipping registry with unsupported type",
+				logging.String("name", cfg.Name), logging.String("type", cfg.Type))
+			continue
+		}
+		c.types[cfg.Name] = registryType
+	}
+	return c
+}
+
+// IsAvailable checks if at least one registry is configured
+func (c *RegistryClient) IsAvailable() bool {
+	return len(c.backends) > 0
+}
+
+// Registries returns the configured registry names and their types
+func (c *RegistryClient) Registries() map[string]string {
+	out := make(map[string]string, len(c.types))
+	for name, t := range c.types {
+		out[name] = t
+	}
+	return out
+}
+
+// backend resolves a registry name; an empty name is allowed when exactly one registry is configured
+func (c *RegistryClient) backend(name string) (string, Backend, error) {
+	if name == "" {
+		if len(c.backends) == 1 {
+			for n, b := range c.backends {
+				return n, b, nil
+			}
+		}
+		return "", nil, fmt.Errorf("registry parameter is required when several registries are configured (%v)", c.names())
+	}
+	b, ok := c.backends[name]
+	if !ok {
+		return "", nil, fmt.Errorf("registry %q is not configured (available: %v)", name, c.names())
+	}
+	return name, b, nil
+}
+
+func (c *RegistryClient) names() []string {
+	names := make([]string, 0, len(c.backends))
+	for name := range c.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListTags lists the tags of a repository
+func (c *RegistryClient) ListTags(registry, repository string, limit int) ([]Tag, error) {
+	_, b, err := c.backend(registry)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	return b.ListTags(repository, limit)
+}
+
+// Inspect returns the metadata of a tag
+func (c *RegistryClient) Inspect(registry, repository, tag, platform string) (*ArtifactInfo, error) {
+	name, b, err := c.backend(registry)
+	if err != nil {
+		return nil, err
+	}
+	info, err := b.Inspect(repository, tag, platform)
+	if err != nil {
+		return nil, err
+	}
+	info.Registry = name
+	if info.Revision == "" {
+		info.Revision = revisionFromLabels(info.Labels)
+	}
+	return info, nil
+}
+
+// Compare inspects two tags of the same repository and diffs their metadata
+func (c *RegistryClient) Compare(registry, repository, tagA, tagB, platform string) (*Comparison, error) {
+	a, err := c.Inspect(registry, repository, tagA, platform)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", tagA, err)
+	}
+	b, err := c.Inspect(registry, repository, tagB, platform)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", tagB, err)
+	}
+
+	cmp := &Comparison{
+		A:            a,
+		B:            b,
+		SameDigest:   a.Digest != "" && a.Digest == b.Digest,
+		SameRevision: a.Revision != "" && a.Revision == b.Revision,
+		SizeDelta:    b.Size - a.Size,
+	}
+	if a.Created != nil && b.Created != nil {
+		cmp.CreatedDelta = b.Created.Sub(*a.Created).String()
+	}
+
+	for k, v := range b.Labels {
+		old, ok := a.Labels[k]
+		if !ok {
+			if cmp.LabelsAdded == nil {
+				cmp.LabelsAdded = map[string]string{}
+			}
+			cmp.LabelsAdded[k] = v
+		} else if old != v {
+			if cmp.LabelsChanged == nil {
+				cmp.LabelsChanged = map[string][2]string{}
+			}
+			cmp.LabelsChanged[k] = [2]string{old, v}
+		}
+	}
+	for k, v := range a.Labels {
+		if _, ok := b.Labels[k]; !ok {
+			if cmp.LabelsRemoved == nil {
+				cmp.LabelsRemoved = map[string]string{}
+			}
+			cmp.LabelsRemoved[k] = v
+		}
+	}
+	return cmp, nil
+}
+
+// Close closes the registry client
+func (c *RegistryClient) Close() error {
+	return nil
+}
+
+// revisionFromLabels picks the build commit out of well-known labels
+func revisionFromLabels(labels map[string]string) string {
+	for _, key := range revisionLabels {
+		if v := labels[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
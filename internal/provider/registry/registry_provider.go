@@ -0,0 +1,279 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// RegistryProvider exposes artifact registry metadata (Docker images, npm packages, Maven artifacts)
+type RegistryProvider struct {
+	*provider.BaseProvider
+	client *RegistryClient
+}
+
+// NewRegistryProvider creates a new artifact registry provider with config
+func NewRegistryProvider(cfgs []config.RegistryConfig) *RegistryProvider {
+	p := &RegistryProvider{
+		BaseProvider: provider.NewBaseProvider("registry"),
+		client:       NewRegistryClient(cfgs),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Artifact registries not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Registry provider initialized successfully (%d registries)", len(p.client.Registries()))
+
+	return p
+}
+
+// Test tests the registry configuration (for ProviderClient interface compatibility)
+func (p *RegistryProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("registry provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Registry tools to the MCP server (for ProviderClient interface compatibility)
+func (p *RegistryProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the Registry provider
+func (p *RegistryProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds Registry tools to the MCP server
+func (p *RegistryProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Registry provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createListTagsTool().Tool, p.createListTagsTool().Handler},
+		{p.createInspectTool().Tool, p.createInspectTool().Handler},
+		{p.createCompareTool().Tool, p.createCompareTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered Registry tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All Registry tools registered successfully")
+}
+
+// createListTagsTool creates the tag listing tool
+func (p *RegistryProvider) createListTagsTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "registry_list_tags",
+		Description: "List image tags (Docker registry) or published versions (npm, Maven) of an artifact",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"registry": {
+					"type": "string",
+					"description": "Configured registry name; optional when only one is configured"
+				},
+				"repository": {
+					"type": "string",
+					"description": "Image repository (e.g. library/nginx), npm package (e.g. @scope/pkg) or Maven groupId:artifactId"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of tags to return",
+					"default": 50
+				}
+			},
+			"required": ["repository"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Registry   string `json:"registry,omitempty"`
+			Repository string `json:"repository"`
+			Limit      int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Repository == "" {
+			return p.createErrorResult(fmt.Errorf("repository parameter is required")), nil
+		}
+
+		tags, err := p.client.ListTags(args.Registry, args.Repository, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"repository": args.Repository,
+			"tags":       tags,
+			"count":      len(tags),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createInspectTool creates the tag inspection tool
+func (p *RegistryProvider) createInspectTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "registry_inspect",
+		Description: "Inspect a tag's manifest and metadata: digest, creation time, labels, size and build commit (revision)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"registry": {
+					"type": "string",
+					"description": "Configured registry name; optional when only one is configured"
+				},
+				"repository": {
+					"type": "string",
+					"description": "Image repository, npm package or Maven groupId:artifactId"
+				},
+				"tag": {
+					"type": "string",
+					"description": "Tag, digest, version or npm dist-tag"
+				},
+				"platform": {
+					"type": "string",
+					"description": "Platform to inspect for multi-arch images",
+					"default": "linux/amd64"
+				}
+			},
+			"required": ["repository", "tag"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Registry   string `json:"registry,omitempty"`
+			Repository string `json:"repository"`
+			Tag        string `json:"tag"`
+			Platform   string `json:"platform,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Repository == "" || args.Tag == "" {
+			return p.createErrorResult(fmt.Errorf("repository and tag parameters are required")), nil
+		}
+
+		info, err := p.client.Inspect(args.Registry, args.Repository, args.Tag, args.Platform)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(info), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createCompareTool creates the tag comparison tool
+func (p *RegistryProvider) createCompareTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "registry_compare",
+		Description: "Compare the metadata of two tags of the same artifact: digest, build commit, size, creation time and label differences",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"registry": {
+					"type": "string",
+					"description": "Configured registry name; optional when only one is configured"
+				},
+				"repository": {
+					"type": "string",
+					"description": "Image repository, npm package or Maven groupId:artifactId"
+				},
+				"tag_a": {
+					"type": "string",
+					"description": "First (baseline) tag"
+				},
+				"tag_b": {
+					"type": "string",
+					"description": "Second tag"
+				},
+				"platform": {
+					"type": "string",
+					"description": "Platform to compare for multi-arch images",
+					"default": "linux/amd64"
+				}
+			},
+			"required": ["repository", "tag_a", "tag_b"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Registry   string `json:"registry,omitempty"`
+			Repository string `json:"repository"`
+			TagA       string `json:"tag_a"`
+			TagB       string `json:"tag_b"`
+			Platform   string `json:"platform,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Repository == "" || args.TagA == "" || args.TagB == "" {
+			return p.createErrorResult(fmt.Errorf("repository, tag_a and tag_b parameters are required")), nil
+		}
+
+		cmp, err := p.client.Compare(args.Registry, args.Repository, args.TagA, args.TagB, args.Platform)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(cmp), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *RegistryProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Registry Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *RegistryProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that RegistryProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*RegistryProvider)(nil)
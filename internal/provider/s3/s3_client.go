@@ -1,31 +1,38 @@
 package s3
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"path"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	cfg "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	appcfg "dev-mcp/internal/config"
 )
 
-// readSeekCloser wraps a Reader to provide a no-op Close method for S3 PutObject
-type readSeekCloser struct {
-	*strings.Reader
-}
-
-func (r readSeekCloser) Close() error { return nil }
+// maxPutObjectSize bounds uploads made through s3_put_object (a single PUT, no multipart)
+const maxPutObjectSize = 5 * 1024 * 1024
 
 // S3Client provides complete S3 operations implementation
 type S3Client struct {
 	s3Client  *s3.Client
 	config    *appcfg.S3Config
 	available bool
+	// readOnly blocks uploads and deletes; it starts from s3.read_only and can be toggled at runtime
+	readOnly bool
+	mu       sync.RWMutex
 }
 
 // NewS3Client creates a new S3 client from config
@@ -73,6 +80,7 @@ func NewS3Client(conf *appcfg.S3Config) *S3Client {
 		s3Client:  s3Client,
 		config:    conf,
 		available: true,
+		readOnly:  conf.ReadOnly == nil || *conf.ReadOnly,
 	}
 }
 
@@ -81,6 +89,47 @@ func (c *S3Client) IsAvailable() bool {
 	return c.available
 }
 
+// IsReadOnly reports whether uploads and deletes are blocked
+func (c *S3Client) IsReadOnly() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.readOnly
+}
+
+// SetReadOnly toggles write access at runtime
+func (c *S3Client) SetReadOnly(readOnly bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readOnly = readOnly
+}
+
+// validateWriteOperation checks that the client may modify objects
+func (c *S3Client) validateWriteOperation() error {
+	if !c.IsAvailable() {
+		return fmt.Errorf("s3 client not available")
+	}
+	if c.IsReadOnly() {
+		return fmt.Errorf("s3 is in read-only mode (set s3.read_only to false, or enable writes with s3_security)")
+	}
+	return nil
+}
+
+// ObjectExists reports whether an object exists
+func (c *S3Client) ObjectExists(bucket, key string) (bool, error) {
+	_, err := c.s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err == nil {
+		return true, nil
+	}
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to head object: %w", err)
+}
+
 // getContent retrieves the content of a text file (json, txt, etc.) from S3, and signs the URL if needed
 func (c *S3Client) GetSignedURL(bucket, key string, expireSeconds int32) (string, error) {
 	presignClient := s3.NewPresignClient(c.s3Client)
@@ -106,7 +155,7 @@ func (c *S3Client) GetContent(bucket, key string) (interface{}, error) {
 		return nil, fmt.Errorf("bucket and key are required")
 	}
 
-	// 判断文件类型，只允许 json、txt、csv、xml
+	// Only json, txt, csv and xml files are read
 	allowedExt := map[string]bool{".json": true, ".txt": true, ".csv": true, ".xml": true}
 	ext := ""
 	if len(key) > 4 {
@@ -125,7 +174,7 @@ func (c *S3Client) GetContent(bucket, key string) (interface{}, error) {
 		return nil, err
 	}
 
-	// 读取内容（仅适合小文件，生产建议流式处理）
+	// Read the whole content (small files only; stream large ones)
 	buf := make([]byte, 0)
 	tmp := make([]byte, 4096)
 	for {
@@ -138,7 +187,7 @@ func (c *S3Client) GetContent(bucket, key string) (interface{}, error) {
 		}
 	}
 
-	// 始终生成签名 URL
+	// Always generate a presigned URL
 	signedUrl, _ := c.GetSignedURL(bucket, key, 600)
 
 	result := map[string]interface{}{
@@ -155,33 +204,246 @@ func (c *S3Client) GetContent(bucket, key string) (interface{}, error) {
 	return result, nil
 }
 
-// ListObjects lists objects in an S3 bucket
-func (c *S3Client) ListObjects(bucket, prefix string, limit int) (interface{}, error) {
+// GetObjectBytes retrieves the raw body of an object regardless of its file type
+func (c *S3Client) GetObjectBytes(bucket, key string) ([]byte, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("bucket and key are required")
+	}
+
+	resp, err := c.s3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// GetObjectVersionBytes retrieves the raw body of a specific object version; an empty versionID reads the latest
+func (c *S3Client) GetObjectVersionBytes(bucket, key, versionID string) ([]byte, error) {
+	if versionID == "" {
+		return c.GetObjectBytes(bucket, key)
+	}
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
+
+	resp, err := c.s3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket:    &bucket,
+		Key:       &key,
+		VersionId: &versionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// ObjectVersion describes one version of a versioned object
+type ObjectVersion struct {
+	VersionID    string     `json:"versionId"`
+	IsLatest     bool       `json:"isLatest"`
+	Size         int64      `json:"size"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+}
+
+// ListObjectVersions lists the versions of a single key, newest first
+func (c *S3Client) ListObjectVersions(bucket, key string, limit int) ([]ObjectVersion, error) {
 	if !c.IsAvailable() {
 		return nil, fmt.Errorf("s3 client not available")
 	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var versions []ObjectVersion
+	paginator := s3.NewListObjectVersionsPaginator(c.s3Client, &s3.ListObjectVersionsInput{
+		Bucket: &bucket,
+		Prefix: &key,
+	})
+	for paginator.HasMorePages() && len(versions) < limit {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range page.Versions {
+			if aws.ToString(v.Key) != key {
+				continue
+			}
+			versions = append(versions, ObjectVersion{
+				VersionID:    aws.ToString(v.VersionId),
+				IsLatest:     aws.ToBool(v.IsLatest),
+				Size:         aws.ToInt64(v.Size),
+				LastModified: v.LastModified,
+			})
+			if len(versions) >= limit {
+				break
+			}
+		}
+	}
+	return versions, nil
+}
 
+// ListKeys returns every object key under a prefix, following continuation tokens
+func (c *S3Client) ListKeys(bucket, prefix string) ([]string, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
 	if bucket == "" {
 		return nil, fmt.Errorf("bucket is required")
 	}
 
-	if limit == 0 {
-		limit = 100
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(c.s3Client, &s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+	return keys, nil
+}
+
+// maxListKeys is the most keys a ListObjectsV2 page holds
+const maxListKeys = 1000
+
+// maxSortScan bounds the objects walked to sort a listing by anything but key order
+const maxSortScan = 100000
+
+// ListOptions selects the objects s3_list_objects returns
+type ListOptions struct {
+	Prefix            string
+	Delimiter         string // e.g. "/": list one level, rolling deeper keys up into common prefixes
+	ContinuationToken string // nextContinuationToken of the previous page
+	Limit             int
+	Sort              string // key (default), size or last_modified
+	Order             string // asc (default) or desc
+}
+
+// ListObjects lists objects in an S3 bucket. In key order a page of up to 1000 objects is returned with
+// the token of the next page; other orders walk the prefix (up to maxSortScan objects) and return the
+// first limit objects of the sorted result.
+func (c *S3Client) ListObjects(ctx context.Context, bucket string, opts ListOptions) (interface{}, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
+
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = 100
+	}
+	opts.Limit = min(opts.Limit, maxListKeys)
+	if opts.Sort == "" {
+		opts.Sort = "key"
+	}
+	if opts.Order == "" {
+		opts.Order = "asc"
+	}
+	switch {
+	case opts.Sort != "key" && opts.Sort != "size" && opts.Sort != "last_modified":
+		return nil, fmt.Errorf("invalid sort %q: use key, size or last_modified", opts.Sort)
+	case opts.Order != "asc" && opts.Order != "desc":
+		return nil, fmt.Errorf("invalid order %q: use asc or desc", opts.Order)
 	}
 
 	input := &s3.ListObjectsV2Input{
-		Bucket:  &bucket,
-		Prefix:  &prefix,
-		MaxKeys: aws.Int32(int32(limit)),
+		Bucket: &bucket,
+		Prefix: &opts.Prefix,
 	}
-	resp, err := c.s3Client.ListObjectsV2(context.TODO(), input)
-	if err != nil {
-		return nil, err
+	if opts.Delimiter != "" {
+		input.Delimiter = &opts.Delimiter
 	}
 
-	objects := make([]map[string]interface{}, 0, len(resp.Contents))
-	for _, obj := range resp.Contents {
-		objects = append(objects, map[string]interface{}{
+	result := map[string]interface{}{
+		"bucket":  bucket,
+		"prefix":  opts.Prefix,
+		"maxKeys": opts.Limit,
+		"sort":    opts.Sort,
+		"order":   opts.Order,
+	}
+	if opts.Delimiter != "" {
+		result["delimiter"] = opts.Delimiter
+	}
+
+	var objects []types.Object
+	var prefixes []string
+	if opts.Sort == "key" && opts.Order == "asc" {
+		// S3 lists keys in ascending order, so this order pages with continuation tokens
+		input.MaxKeys = aws.Int32(int32(opts.Limit))
+		if opts.ContinuationToken != "" {
+			input.ContinuationToken = &opts.ContinuationToken
+		}
+		resp, err := c.s3Client.ListObjectsV2(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		objects = resp.Contents
+		for _, p := range resp.CommonPrefixes {
+			prefixes = append(prefixes, aws.ToString(p.Prefix))
+		}
+		result["isTruncated"] = aws.ToBool(resp.IsTruncated)
+		if token := aws.ToString(resp.NextContinuationToken); token != "" {
+			result["nextContinuationToken"] = token
+		}
+	} else {
+		if opts.ContinuationToken != "" {
+			return nil, fmt.Errorf("continuation_token only pages listings sorted by key in ascending order")
+		}
+		input.MaxKeys = aws.Int32(maxListKeys)
+		scanTruncated := false
+		paginator := s3.NewListObjectsV2Paginator(c.s3Client, input)
+		for paginator.HasMorePages() && !scanTruncated {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list objects: %w", err)
+			}
+			for _, p := range page.CommonPrefixes {
+				prefixes = append(prefixes, aws.ToString(p.Prefix))
+			}
+			for _, obj := range page.Contents {
+				if len(objects) == maxSortScan {
+					scanTruncated = true
+					break
+				}
+				objects = append(objects, obj)
+			}
+		}
+		sortObjects(objects, opts.Sort, opts.Order == "desc")
+		if opts.Order == "desc" {
+			sort.Sort(sort.Reverse(sort.StringSlice(prefixes)))
+		}
+
+		result["scanned"] = len(objects)
+		result["scanTruncated"] = scanTruncated
+		result["isTruncated"] = len(objects) > opts.Limit || len(prefixes) > opts.Limit
+		if len(objects) > opts.Limit {
+			objects = objects[:opts.Limit]
+		}
+		if len(prefixes) > opts.Limit {
+			prefixes = prefixes[:opts.Limit]
+		}
+	}
+
+	entries := make([]map[string]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		entries = append(entries, map[string]interface{}{
 			"key":          aws.ToString(obj.Key),
 			"size":         obj.Size,
 			"lastModified": obj.LastModified,
@@ -189,44 +451,266 @@ func (c *S3Client) ListObjects(bucket, prefix string, limit int) (interface{}, e
 			"storageClass": obj.StorageClass,
 		})
 	}
-
-	result := map[string]interface{}{
-		"bucket":      bucket,
-		"prefix":      prefix,
-		"objects":     objects,
-		"count":       len(objects),
-		"isTruncated": resp.IsTruncated,
-		"maxKeys":     limit,
+	result["objects"] = entries
+	result["count"] = len(entries)
+	if opts.Delimiter != "" {
+		if prefixes == nil {
+			prefixes = []string{}
+		}
+		result["commonPrefixes"] = prefixes
 	}
 	return result, nil
 }
 
-// PutObject uploads an object to S3 (for testing)
-func (c *S3Client) PutObject(bucket, key, content string) (interface{}, error) {
+// sortObjects sorts objects by key, size or last_modified; ties keep key order
+func sortObjects(objects []types.Object, by string, descending bool) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		a, b := objects[i], objects[j]
+		if descending {
+			a, b = b, a
+		}
+		switch by {
+		case "size":
+			if aws.ToInt64(a.Size) != aws.ToInt64(b.Size) {
+				return aws.ToInt64(a.Size) < aws.ToInt64(b.Size)
+			}
+		case "last_modified":
+			at, bt := aws.ToTime(a.LastModified), aws.ToTime(b.LastModified)
+			if !at.Equal(bt) {
+				return at.Before(bt)
+			}
+		}
+		return aws.ToString(a.Key) < aws.ToString(b.Key)
+	})
+}
+
+// maxFindScan bounds the objects s3_find walks in one call; the rest is searched by resuming from startAfter
+const maxFindScan = 100000
+
+// FindOptions filters the objects s3_find returns; zero values do not filter
+type FindOptions struct {
+	Prefix         string
+	StartAfter     string   // Resume a search after this key
+	Suffixes       []string // Key endings, case-insensitive, e.g. ".parquet"
+	MinSize        int64
+	MaxSize        int64
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+	StorageClass   string // e.g. STANDARD or GLACIER
+	Limit          int
+}
+
+// match reports whether an object passes the filters
+func (o FindOptions) match(obj types.Object) bool {
+	if len(o.Suffixes) > 0 {
+		key := strings.ToLower(aws.ToString(obj.Key))
+		found := false
+		for _, suffix := range o.Suffixes {
+			if strings.HasSuffix(key, strings.ToLower(suffix)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	size := aws.ToInt64(obj.Size)
+	if size < o.MinSize || (o.MaxSize > 0 && size > o.MaxSize) {
+		return false
+	}
+	modified := aws.ToTime(obj.LastModified)
+	if (!o.ModifiedAfter.IsZero() && modified.Before(o.ModifiedAfter)) ||
+		(!o.ModifiedBefore.IsZero() && !modified.Before(o.ModifiedBefore)) {
+		return false
+	}
+	// Some S3-compatible services leave the storage class out of listings for standard objects
+	class := string(obj.StorageClass)
+	if class == "" {
+		class = string(types.ObjectStorageClassStandard)
+	}
+	return o.StorageClass == "" || strings.EqualFold(class, o.StorageClass)
+}
+
+// FoundObject is an object matched by s3_find
+type FoundObject struct {
+	Key          string     `json:"key"`
+	Size         int64      `json:"size"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	StorageClass string     `json:"storageClass,omitempty"`
+}
+
+// FindResult is a bounded set of matching objects. StartAfter is set when the search stopped before the
+// end of the prefix, at limit matches or after maxFindScan objects; passing it back continues the search.
+type FindResult struct {
+	Bucket     string        `json:"bucket"`
+	Prefix     string        `json:"prefix"`
+	Objects    []FoundObject `json:"objects"`
+	Count      int           `json:"count"`
+	TotalSize  int64         `json:"totalSize"`
+	Scanned    int           `json:"scanned"`
+	Truncated  bool          `json:"truncated"`
+	StartAfter string        `json:"startAfter,omitempty"`
+}
+
+// FindObjects walks a prefix in key order and returns the objects passing the filters, stopping at
+// opts.Limit matches or maxFindScan objects
+func (c *S3Client) FindObjects(ctx context.Context, bucket string, opts FindOptions) (*FindResult, error) {
 	if !c.IsAvailable() {
 		return nil, fmt.Errorf("s3 client not available")
 	}
 
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = 100
+	}
+	opts.Limit = min(opts.Limit, maxListKeys)
+	if opts.MaxSize > 0 && opts.MinSize > opts.MaxSize {
+		return nil, fmt.Errorf("min_size %d is larger than max_size %d", opts.MinSize, opts.MaxSize)
+	}
+	if !opts.ModifiedAfter.IsZero() && !opts.ModifiedBefore.IsZero() && !opts.ModifiedAfter.Before(opts.ModifiedBefore) {
+		return nil, fmt.Errorf("modified_after must be before modified_before")
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:  &bucket,
+		Prefix:  &opts.Prefix,
+		MaxKeys: aws.Int32(maxListKeys),
+	}
+	if opts.StartAfter != "" {
+		input.StartAfter = &opts.StartAfter
+	}
+
+	result := &FindResult{Bucket: bucket, Prefix: opts.Prefix, Objects: []FoundObject{}}
+	lastKey := ""
+	paginator := s3.NewListObjectsV2Paginator(c.s3Client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if len(result.Objects) == opts.Limit || result.Scanned == maxFindScan {
+				result.Truncated = true
+				result.StartAfter = lastKey
+				result.Count = len(result.Objects)
+				return result, nil
+			}
+			result.Scanned++
+			lastKey = aws.ToString(obj.Key)
+			if !opts.match(obj) {
+				continue
+			}
+			result.Objects = append(result.Objects, FoundObject{
+				Key:          lastKey,
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: obj.LastModified,
+				StorageClass: string(obj.StorageClass),
+			})
+			result.TotalSize += aws.ToInt64(obj.Size)
+		}
+	}
+	result.Count = len(result.Objects)
+	return result, nil
+}
+
+// PutObject uploads an object to S3; an existing object is only replaced when overwrite is set
+func (c *S3Client) PutObject(bucket, key string, body []byte, contentType string, overwrite bool) (interface{}, error) {
+	if err := c.validateWriteOperation(); err != nil {
+		return nil, err
+	}
+
 	if bucket == "" || key == "" {
 		return nil, fmt.Errorf("bucket and key are required")
 	}
+	if len(body) > maxPutObjectSize {
+		return nil, fmt.Errorf("object is %s, above the %s upload limit", humanSize(int64(len(body))), humanSize(maxPutObjectSize))
+	}
+
+	existed, err := c.ObjectExists(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if existed && !overwrite {
+		return nil, fmt.Errorf("object s3://%s/%s already exists (set overwrite to replace it)", bucket, key)
+	}
 
 	input := &s3.PutObjectInput{
 		Bucket: &bucket,
 		Key:    &key,
-		Body:   readSeekCloser{strings.NewReader(content)},
+		Body:   bytes.NewReader(body),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
 	}
 	resp, err := c.s3Client.PutObject(context.TODO(), input)
 	if err != nil {
+		return nil, fmt.Errorf("failed to put object: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"bucket":      bucket,
+		"key":         key,
+		"size":        len(body),
+		"contentType": contentType,
+		"etag":        aws.ToString(resp.ETag),
+		"replaced":    existed,
+		"status":      "uploaded",
+	}
+	if resp.VersionId != nil {
+		result["versionId"] = aws.ToString(resp.VersionId)
+	}
+	return result, nil
+}
+
+// DeleteObject deletes an object, or one version of it when versionID is set
+func (c *S3Client) DeleteObject(bucket, key, versionID string) (interface{}, error) {
+	if err := c.validateWriteOperation(); err != nil {
 		return nil, err
 	}
 
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("bucket and key are required")
+	}
+
+	// S3 deletes are idempotent, so check first to report missing keys instead of silently succeeding
+	if versionID == "" {
+		exists, err := c.ObjectExists(bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("object s3://%s/%s does not exist", bucket, key)
+		}
+	}
+
+	input := &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+	resp, err := c.s3Client.DeleteObject(context.TODO(), input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete object: %w", err)
+	}
+
 	result := map[string]interface{}{
 		"bucket": bucket,
 		"key":    key,
-		"size":   len(content),
-		"etag":   aws.ToString(resp.ETag),
-		"status": "uploaded",
+		"status": "deleted",
+	}
+	if versionID != "" {
+		result["versionId"] = versionID
+	}
+	if aws.ToBool(resp.DeleteMarker) {
+		// Versioned bucket: the object is hidden behind a delete marker and can be restored
+		result["deleteMarker"] = true
+		result["deleteMarkerVersionId"] = aws.ToString(resp.VersionId)
 	}
 	return result, nil
 }
@@ -258,6 +742,9 @@ func (c *S3Client) GetBucketInfo(bucket string) (interface{}, error) {
 	return mockData, nil
 }
 
+// maxScanObjects bounds how many objects a size scan walks before stopping
+const maxScanObjects = 1000000
+
 // GetObjectSize retrieves the size of a specific object in bytes
 func (c *S3Client) GetObjectSize(bucket, key string) (int64, error) {
 	if !c.IsAvailable() {
@@ -268,28 +755,158 @@ func (c *S3Client) GetObjectSize(bucket, key string) (int64, error) {
 		return 0, fmt.Errorf("bucket and key are required")
 	}
 
-	// In a real implementation, this would make a HEAD request to get object metadata
-	// For now, return mock size data based on file extension
-	mockSize := int64(1024) // Default 1KB
+	head, err := c.s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to head object: %w", err)
+	}
 
-	// Simulate different file sizes based on file type
-	if len(key) > 4 {
-		ext := key[len(key)-4:]
-		switch ext {
-		case ".jpg", ".png", ".gif":
-			mockSize = 2048 * 1024 // 2MB for images
-		case ".mp4", ".avi", ".mov":
-			mockSize = 100 * 1024 * 1024 // 100MB for videos
-		case ".pdf":
-			mockSize = 5 * 1024 * 1024 // 5MB for PDFs
-		case ".log":
-			mockSize = 50 * 1024 // 50KB for logs
-		case ".json", ".xml":
-			mockSize = 10 * 1024 // 10KB for config files
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+// sizeStats accumulates size statistics over a set of objects
+type sizeStats struct {
+	count  int
+	total  int64
+	sizes  []int64
+	top    []map[string]interface{}
+	groups map[string]map[string]map[string]int64 // dimension -> group -> {count, size}
+	min    map[string]interface{}
+	max    map[string]interface{}
+}
+
+func newSizeStats() *sizeStats {
+	return &sizeStats{groups: map[string]map[string]map[string]int64{}}
+}
+
+// add records one object
+func (s *sizeStats) add(key string, size int64, dimensions map[string]string) {
+	s.count++
+	s.total += size
+	s.sizes = append(s.sizes, size)
+
+	entry := map[string]interface{}{"key": key, "size": size}
+	if s.min == nil || size < s.min["size"].(int64) {
+		s.min = entry
+	}
+	if s.max == nil || size > s.max["size"].(int64) {
+		s.max = entry
+	}
+
+	// Keep the 10 largest objects
+	s.top = append(s.top, entry)
+	sort.Slice(s.top, func(i, j int) bool { return s.top[i]["size"].(int64) > s.top[j]["size"].(int64) })
+	if len(s.top) > 10 {
+		s.top = s.top[:10]
+	}
+
+	for dimension, group := range dimensions {
+		if s.groups[dimension] == nil {
+			s.groups[dimension] = map[string]map[string]int64{}
+		}
+		if s.groups[dimension][group] == nil {
+			s.groups[dimension][group] = map[string]int64{}
+		}
+		s.groups[dimension][group]["count"]++
+		s.groups[dimension][group]["size"] += size
+	}
+}
+
+func (s *sizeStats) average() int64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.total / int64(s.count)
+}
+
+func (s *sizeStats) median() int64 {
+	if len(s.sizes) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), s.sizes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// distribution buckets object sizes into coarse ranges
+func (s *sizeStats) distribution() map[string]int {
+	dist := map[string]int{
+		"lessThan1MB":      0,
+		"1MBto10MB":        0,
+		"10MBto100MB":      0,
+		"greaterThan100MB": 0,
+	}
+	for _, size := range s.sizes {
+		switch {
+		case size < 1<<20:
+			dist["lessThan1MB"]++
+		case size < 10<<20:
+			dist["1MBto10MB"]++
+		case size < 100<<20:
+			dist["10MBto100MB"]++
+		default:
+			dist["greaterThan100MB"]++
+		}
+	}
+	return dist
+}
+
+// scanObjects walks every object under a prefix using paginated ListObjectsV2
+func (c *S3Client) scanObjects(bucket, prefix string, fn func(obj types.Object)) (bool, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket:  &bucket,
+		MaxKeys: aws.Int32(1000),
+	}
+	if prefix != "" {
+		input.Prefix = &prefix
+	}
+
+	scanned := 0
+	paginator := s3.NewListObjectsV2Paginator(c.s3Client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return false, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if scanned >= maxScanObjects {
+				return true, nil
+			}
+			fn(obj)
+			scanned++
+		}
+	}
+	return false, nil
+}
+
+// contentTypeOf guesses an object's content type from its extension (listing does not return it)
+func contentTypeOf(key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	if ext == "" {
+		return "unknown"
+	}
+	if ct := mime.TypeByExtension(ext); ct != "" {
+		if i := strings.Index(ct, ";"); i >= 0 {
+			ct = ct[:i]
 		}
+		return ct
 	}
+	return "unknown (" + ext + ")"
+}
 
-	return mockSize, nil
+// childPrefix returns the next "directory" below prefix, or "(root)" for objects directly under it
+func childPrefix(key, prefix string) string {
+	rest := strings.TrimPrefix(key, prefix)
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return prefix + rest[:i+1]
+	}
+	return "(root)"
 }
 
 // GetBucketSize calculates the total size of all objects in a bucket
@@ -302,45 +919,34 @@ func (c *S3Client) GetBucketSize(bucket string) (interface{}, error) {
 		return nil, fmt.Errorf("bucket name is required")
 	}
 
-	// In a real implementation, this would iterate through all objects or use bucket metrics
-	mockData := map[string]interface{}{
-		"bucket":      bucket,
-		"totalSize":   int64(250 * 1024 * 1024), // 250MB total
-		"objectCount": 157,
-		"sizeByType": map[string]interface{}{
-			"images": map[string]interface{}{
-				"count": 45,
-				"size":  int64(90 * 1024 * 1024), // 90MB
-			},
-			"documents": map[string]interface{}{
-				"count": 32,
-				"size":  int64(80 * 1024 * 1024), // 80MB
-			},
-			"logs": map[string]interface{}{
-				"count": 50,
-				"size":  int64(25 * 1024 * 1024), // 25MB
-			},
-			"videos": map[string]interface{}{
-				"count": 5,
-				"size":  int64(50 * 1024 * 1024), // 50MB
-			},
-			"others": map[string]interface{}{
-				"count": 25,
-				"size":  int64(5 * 1024 * 1024), // 5MB
-			},
-		},
-		"averageObjectSize": int64(1592356), // ~1.5MB average
-		"largestObject": map[string]interface{}{
-			"key":  "videos/presentation.mp4",
-			"size": int64(25 * 1024 * 1024), // 25MB
-		},
-		"smallestObject": map[string]interface{}{
-			"key":  "config/app.json",
-			"size": int64(256), // 256 bytes
-		},
+	stats := newSizeStats()
+	truncated, err := c.scanObjects(bucket, "", func(obj types.Object) {
+		key := aws.ToString(obj.Key)
+		stats.add(key, aws.ToInt64(obj.Size), map[string]string{
+			"contentType":  contentTypeOf(key),
+			"prefix":       childPrefix(key, ""),
+			"storageClass": string(obj.StorageClass),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return mockData, nil
+	result := map[string]interface{}{
+		"bucket":             bucket,
+		"totalSize":          stats.total,
+		"totalSizeHuman":     humanSize(stats.total),
+		"objectCount":        stats.count,
+		"averageObjectSize":  stats.average(),
+		"sizeByContentType":  stats.groups["contentType"],
+		"sizeByPrefix":       stats.groups["prefix"],
+		"sizeByStorageClass": stats.groups["storageClass"],
+		"largestObject":      stats.max,
+		"smallestObject":     stats.min,
+		"truncated":          truncated,
+	}
+
+	return result, nil
 }
 
 // GetObjectSizeInfo retrieves detailed size information for an object
@@ -353,18 +959,41 @@ func (c *S3Client) GetObjectSizeInfo(bucket, key string) (interface{}, error) {
 		return nil, fmt.Errorf("bucket and key are required")
 	}
 
-	// Get the basic size first
-	size, err := c.GetObjectSize(bucket, key)
+	head, err := c.s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to head object: %w", err)
 	}
 
-	// Calculate human-readable sizes
+	size := aws.ToInt64(head.ContentLength)
 	sizeInKB := float64(size) / 1024
 	sizeInMB := sizeInKB / 1024
 	sizeInGB := sizeInMB / 1024
 
-	mockData := map[string]interface{}{
+	storageClass := string(head.StorageClass)
+	if storageClass == "" {
+		storageClass = "STANDARD"
+	}
+
+	checksums := map[string]string{
+		"etag": aws.ToString(head.ETag),
+	}
+	if v := aws.ToString(head.ChecksumCRC32); v != "" {
+		checksums["crc32"] = v
+	}
+	if v := aws.ToString(head.ChecksumCRC32C); v != "" {
+		checksums["crc32c"] = v
+	}
+	if v := aws.ToString(head.ChecksumSHA1); v != "" {
+		checksums["sha1"] = v
+	}
+	if v := aws.ToString(head.ChecksumSHA256); v != "" {
+		checksums["sha256"] = v
+	}
+
+	result := map[string]interface{}{
 		"bucket": bucket,
 		"key":    key,
 		"size": map[string]interface{}{
@@ -373,23 +1002,22 @@ func (c *S3Client) GetObjectSizeInfo(bucket, key string) (interface{}, error) {
 			"megabytes": fmt.Sprintf("%.2f MB", sizeInMB),
 			"gigabytes": fmt.Sprintf("%.4f GB", sizeInGB),
 		},
-		"storageClass": "STANDARD",
-		"compressed":   false,
-		"encrypted":    true,
+		"storageClass": storageClass,
+		"compressed":   aws.ToString(head.ContentEncoding) == "gzip" || aws.ToString(head.ContentEncoding) == "br",
+		"encrypted":    head.ServerSideEncryption != "",
+		"encryption":   string(head.ServerSideEncryption),
 		"metadata": map[string]interface{}{
-			"contentType":     "application/octet-stream",
-			"cacheControl":    "max-age=3600",
-			"contentEncoding": "identity",
-		},
-		"checksums": map[string]string{
-			"etag": "\"abc123def456\"",
-			"md5":  "d41d8cd98f00b204e9800998ecf8427e",
+			"contentType":     aws.ToString(head.ContentType),
+			"cacheControl":    aws.ToString(head.CacheControl),
+			"contentEncoding": aws.ToString(head.ContentEncoding),
+			"user":            head.Metadata,
 		},
-		"lastModified": "2024-11-09T10:30:00Z",
-		"createdDate":  "2024-11-09T10:30:00Z",
+		"checksums":    checksums,
+		"lastModified": head.LastModified,
+		"versionId":    aws.ToString(head.VersionId),
 	}
 
-	return mockData, nil
+	return result, nil
 }
 
 // GetSizeStatistics provides comprehensive size statistics for objects matching a prefix
@@ -402,40 +1030,196 @@ func (c *S3Client) GetSizeStatistics(bucket, prefix string) (interface{}, error)
 		return nil, fmt.Errorf("bucket name is required")
 	}
 
-	mockData := map[string]interface{}{
+	stats := newSizeStats()
+	truncated, err := c.scanObjects(bucket, prefix, func(obj types.Object) {
+		key := aws.ToString(obj.Key)
+		stats.add(key, aws.ToInt64(obj.Size), map[string]string{
+			"contentType": contentTypeOf(key),
+			"prefix":      childPrefix(key, prefix),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var minSize, maxSize int64
+	if stats.min != nil {
+		minSize = stats.min["size"].(int64)
+		maxSize = stats.max["size"].(int64)
+	}
+
+	result := map[string]interface{}{
 		"bucket": bucket,
 		"prefix": prefix,
 		"statistics": map[string]interface{}{
-			"totalObjects": 25,
-			"totalSize":    int64(75 * 1024 * 1024), // 75MB
-			"averageSize":  int64(3 * 1024 * 1024),  // 3MB
-			"medianSize":   int64(1 * 1024 * 1024),  // 1MB
-			"minSize":      int64(1024),             // 1KB
-			"maxSize":      int64(15 * 1024 * 1024), // 15MB
-		},
-		"sizeDistribution": map[string]interface{}{
-			"lessThan1MB":      12,
-			"1MBto10MB":        10,
-			"10MBto100MB":      3,
-			"greaterThan100MB": 0,
-		},
-		"topLargestObjects": []map[string]interface{}{
-			{
-				"key":  prefix + "/large-dataset.json",
-				"size": int64(15 * 1024 * 1024), // 15MB
-			},
-			{
-				"key":  prefix + "/backup.zip",
-				"size": int64(12 * 1024 * 1024), // 12MB
-			},
-			{
-				"key":  prefix + "/report.pdf",
-				"size": int64(8 * 1024 * 1024), // 8MB
-			},
+			"totalObjects": stats.count,
+			"totalSize":    stats.total,
+			"averageSize":  stats.average(),
+			"medianSize":   stats.median(),
+			"minSize":      minSize,
+			"maxSize":      maxSize,
 		},
+		"sizeDistribution":  stats.distribution(),
+		"sizeByContentType": stats.groups["contentType"],
+		"sizeByPrefix":      stats.groups["prefix"],
+		"topLargestObjects": stats.top,
+		"truncated":         truncated,
 	}
 
-	return mockData, nil
+	return result, nil
+}
+
+// humanSize formats a byte count with a binary unit
+func humanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// ErrObjectNotFound is returned by ReadObject when the bucket or key does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectContent is the body of an object read up to a size limit
+type ObjectContent struct {
+	Body        []byte
+	ContentType string // As stored with the object; may be empty or generic
+	Size        int64
+}
+
+// ReadObject reads an object that is at most maxBytes long
+func (c *S3Client) ReadObject(ctx context.Context, bucket, key string, maxBytes int64) (*ObjectContent, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
+
+	resp, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		var noSuchBucket *types.NoSuchBucket
+		if errors.As(err, &noSuchKey) || errors.As(err, &noSuchBucket) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	size := aws.ToInt64(resp.ContentLength)
+	if size > maxBytes {
+		return nil, fmt.Errorf("object is %s, larger than the %s limit", humanSize(size), humanSize(maxBytes))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("object is larger than the %s limit", humanSize(maxBytes))
+	}
+	return &ObjectContent{Body: body, ContentType: aws.ToString(resp.ContentType), Size: int64(len(body))}, nil
+}
+
+// DirectoryEntry is an object directly under a listed prefix
+type DirectoryEntry struct {
+	Key          string
+	Size         int64
+	LastModified *time.Time
+}
+
+// Directory is one level of a bucket listed with the "/" delimiter
+type Directory struct {
+	Objects   []DirectoryEntry
+	Prefixes  []string // Sub-prefixes, each ending in "/"
+	Truncated bool
+}
+
+// ListDirectory lists the objects and sub-prefixes directly under a prefix, up to limit entries
+func (c *S3Client) ListDirectory(ctx context.Context, bucket, prefix string, limit int) (*Directory, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
+
+	resp, err := c.s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:    &bucket,
+		Prefix:    &prefix,
+		Delimiter: aws.String("/"),
+		MaxKeys:   aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dir := &Directory{Truncated: aws.ToBool(resp.IsTruncated)}
+	for _, p := range resp.CommonPrefixes {
+		dir.Prefixes = append(dir.Prefixes, aws.ToString(p.Prefix))
+	}
+	for _, obj := range resp.Contents {
+		key := aws.ToString(obj.Key)
+		if key == prefix {
+			// The placeholder object some tools create for a "folder"
+			continue
+		}
+		dir.Objects = append(dir.Objects, DirectoryEntry{Key: key, Size: aws.ToInt64(obj.Size), LastModified: obj.LastModified})
+	}
+	return dir, nil
+}
+
+// ResourcesConfig returns the configuration of the s3:// resources
+func (c *S3Client) ResourcesConfig() appcfg.S3ResourcesConfig {
+	if c.config == nil {
+		return appcfg.S3ResourcesConfig{}
+	}
+	return c.config.Resources
+}
+
+// DefaultBucket returns the configured default bucket
+func (c *S3Client) DefaultBucket() string {
+	if c.config == nil {
+		return ""
+	}
+	return c.config.Bucket
+}
+
+// Ping checks that the S3 endpoint answers: the default bucket is looked up when set, else the buckets are listed
+func (c *S3Client) Ping(ctx context.Context) error {
+	if !c.IsAvailable() {
+		return fmt.Errorf("s3 client not available")
+	}
+	var err error
+	if bucket := c.DefaultBucket(); bucket != "" {
+		_, err = c.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	} else {
+		_, err = c.s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reach s3: %w", err)
+	}
+	return nil
+}
+
+// ListBuckets returns the names of the buckets the credentials can see
+func (c *S3Client) ListBuckets(ctx context.Context) ([]string, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
+	output, err := c.s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+	names := make([]string, 0, len(output.Buckets))
+	for _, bucket := range output.Buckets {
+		names = append(names, aws.ToString(bucket.Name))
+	}
+	sort.Strings(names)
+	return names, nil
 }
 
 // Close closes the S3 client
@@ -2,15 +2,21 @@ package s3
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
+	"mime"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"dev-mcp/entity"
 	appcfg "dev-mcp/internal/config"
 	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/loki"
 )
 
 // S3Provider provides S3 storage functionality
@@ -19,8 +25,8 @@ type S3Provider struct {
 	client *S3Client
 }
 
-// NewS3Provider creates a new S3 provider with config and server
-func NewS3Provider(cfg *appcfg.S3Config, server *mcp.Server) *S3Provider {
+// NewS3Provider creates a new S3 provider with config
+func NewS3Provider(cfg *appcfg.S3Config) *S3Provider {
 	p := &S3Provider{
 		BaseProvider: provider.NewBaseProvider("s3"),
 	}
@@ -30,8 +36,6 @@ func NewS3Provider(cfg *appcfg.S3Config, server *mcp.Server) *S3Provider {
 
 	if p.client.IsAvailable() {
 		p.SetAvailable(true)
-		// Add tools to server immediately
-		p.addToolsToServer(server)
 		log.Printf("✓ S3 provider initialized successfully")
 	} else {
 		p.SetStatus(false, "S3 client initialization failed", nil)
@@ -40,6 +44,43 @@ func NewS3Provider(cfg *appcfg.S3Config, server *mcp.Server) *S3Provider {
 	return p
 }
 
+// Client returns the S3 client
+func (p *S3Provider) Client() *S3Client {
+	return p.client
+}
+
+// HealthCheck checks that the S3 endpoint answers
+func (p *S3Provider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.client.Ping(ctx)
+}
+
+// ToolHints names the default bucket and the buckets the S3 tools can read
+func (p *S3Provider) ToolHints(ctx context.Context) map[string]string {
+	var hints []string
+	if bucket := p.client.DefaultBucket(); bucket != "" {
+		hints = append(hints, fmt.Sprintf("Default bucket: %s.", bucket))
+	}
+	if buckets, err := p.client.ListBuckets(ctx); err == nil {
+		if hint := provider.HintList("Buckets", buckets, 20); hint != "" {
+			hints = append(hints, hint)
+		}
+	}
+	if len(hints) == 0 {
+		return nil
+	}
+	hint := strings.Join(hints, " ")
+	result := map[string]string{}
+	for _, tool := range []string{
+		"s3_get_content", "s3_list_objects", "s3_find", "s3_select", "s3_get_object_size",
+		"s3_get_bucket_size", "s3_get_size_statistics", "s3_put_object", "s3_delete_object",
+	} {
+		result[tool] = hint
+	}
+	return result
+}
+
 // Test tests the S3 configuration and connection (for ProviderClient interface compatibility)
 func (p *S3Provider) Test(config interface{}) error {
 	// Since client is already initialized in constructor, just check availability
@@ -51,7 +92,6 @@ func (p *S3Provider) Test(config interface{}) error {
 
 // AddTools adds S3 tools to the MCP server (for ProviderClient interface compatibility)
 func (p *S3Provider) AddTools(server *mcp.Server, config interface{}) error {
-	// Tools are already added in constructor, but we can call addToolsToServer again if needed
 	p.addToolsToServer(server)
 	return nil
 }
@@ -70,9 +110,14 @@ func (p *S3Provider) addToolsToServer(server *mcp.Server) {
 	}{
 		{p.createS3GetContentTool().Tool, p.createS3GetContentTool().Handler},
 		{p.createS3ListObjectsTool().Tool, p.createS3ListObjectsTool().Handler},
+		{p.createS3FindTool().Tool, p.createS3FindTool().Handler},
+		{p.createS3SelectTool().Tool, p.createS3SelectTool().Handler},
 		{p.createS3GetObjectSizeTool().Tool, p.createS3GetObjectSizeTool().Handler},
 		{p.createS3GetBucketSizeTool().Tool, p.createS3GetBucketSizeTool().Handler},
 		{p.createS3GetSizeStatisticsTool().Tool, p.createS3GetSizeStatisticsTool().Handler},
+		{p.createS3PutObjectTool().Tool, p.createS3PutObjectTool().Handler},
+		{p.createS3DeleteObjectTool().Tool, p.createS3DeleteObjectTool().Handler},
+		{p.createS3SecurityTool().Tool, p.createS3SecurityTool().Handler},
 	}
 
 	for _, tool := range tools {
@@ -83,7 +128,7 @@ func (p *S3Provider) addToolsToServer(server *mcp.Server) {
 	log.Printf("✓ All S3 tools registered successfully")
 }
 
-// createS3GetContentTool creates the S3 get content tool (文本文件+自动签名)
+// createS3GetContentTool creates the S3 get content tool (text files, always with a presigned URL)
 func (p *S3Provider) createS3GetContentTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
 		Name:        "s3_get_content",
@@ -242,7 +287,7 @@ func (p *S3Provider) createS3GetObjectTool() entity.ToolDefinition {
 func (p *S3Provider) createS3ListObjectsTool() entity.ToolDefinition {
 	tool := &mcp.Tool{
 		Name:        "s3_list_objects",
-		Description: "List objects in S3 bucket",
+		Description: "List objects in an S3 bucket. Pages through large buckets with continuation_token, lists one folder level with delimiter \"/\" (sub-folders come back as commonPrefixes), and sorts by key, size or last_modified; sorts other than key ascending walk the prefix (up to 100000 objects) and cannot be paged",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
@@ -254,9 +299,29 @@ func (p *S3Provider) createS3ListObjectsTool() entity.ToolDefinition {
 					"type": "string",
 					"description": "Object key prefix (optional)"
 				},
+				"delimiter": {
+					"type": "string",
+					"description": "Folder separator, usually \"/\": keys below the next separator are rolled up into commonPrefixes"
+				},
+				"continuation_token": {
+					"type": "string",
+					"description": "nextContinuationToken of the previous page"
+				},
+				"sort": {
+					"type": "string",
+					"enum": ["key", "size", "last_modified"],
+					"description": "Sort field",
+					"default": "key"
+				},
+				"order": {
+					"type": "string",
+					"enum": ["asc", "desc"],
+					"description": "Sort order",
+					"default": "asc"
+				},
 				"limit": {
 					"type": "integer",
-					"description": "Maximum number of objects to return",
+					"description": "Maximum number of objects to return (at most 1000)",
 					"default": 100
 				}
 			},
@@ -266,9 +331,13 @@ func (p *S3Provider) createS3ListObjectsTool() entity.ToolDefinition {
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var args struct {
-			Bucket string `json:"bucket"`
-			Prefix string `json:"prefix,omitempty"`
-			Limit  int    `json:"limit,omitempty"`
+			Bucket            string `json:"bucket"`
+			Prefix            string `json:"prefix,omitempty"`
+			Delimiter         string `json:"delimiter,omitempty"`
+			ContinuationToken string `json:"continuation_token,omitempty"`
+			Sort              string `json:"sort,omitempty"`
+			Order             string `json:"order,omitempty"`
+			Limit             int    `json:"limit,omitempty"`
 		}
 
 		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
@@ -279,12 +348,235 @@ func (p *S3Provider) createS3ListObjectsTool() entity.ToolDefinition {
 			return p.createErrorResult(fmt.Errorf("bucket parameter is required")), nil
 		}
 
-		if args.Limit == 0 {
-			args.Limit = 100
+		// Use the S3 client to list objects
+		result, err := p.client.ListObjects(ctx, args.Bucket, ListOptions{
+			Prefix:            args.Prefix,
+			Delimiter:         args.Delimiter,
+			ContinuationToken: args.ContinuationToken,
+			Limit:             args.Limit,
+			Sort:              args.Sort,
+			Order:             args.Order,
+		})
+		if err != nil {
+			return p.createErrorResult(err), nil
 		}
 
-		// Use the S3 client to list objects
-		result, err := p.client.ListObjects(args.Bucket, args.Prefix, args.Limit)
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createS3FindTool creates the S3 object search tool
+func (p *S3Provider) createS3FindTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "s3_find",
+		Description: fmt.Sprintf("Find objects under a prefix by key suffix, size range, last-modified window and storage class. Walks the listing pages itself and returns up to limit matches; when it stops early (at limit matches or after %d objects) pass the returned startAfter back as start_after to continue", maxFindScan),
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"bucket": {
+					"type": "string",
+					"description": "S3 bucket name"
+				},
+				"prefix": {
+					"type": "string",
+					"description": "Object key prefix to search under (optional)"
+				},
+				"suffix": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Key endings, any of which matches (case-insensitive), e.g. [\".csv\", \".csv.gz\"]"
+				},
+				"min_size": {
+					"type": "integer",
+					"description": "Minimum object size in bytes"
+				},
+				"max_size": {
+					"type": "integer",
+					"description": "Maximum object size in bytes"
+				},
+				"modified_after": {
+					"type": "string",
+					"description": "Only objects modified at or after this: RFC3339, unix timestamp, or a duration ago such as 24h or 7d"
+				},
+				"modified_before": {
+					"type": "string",
+					"description": "Only objects modified before this, in the same formats"
+				},
+				"storage_class": {
+					"type": "string",
+					"description": "Storage class, e.g. STANDARD, STANDARD_IA or GLACIER"
+				},
+				"start_after": {
+					"type": "string",
+					"description": "startAfter of the previous call, to continue the search"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of matching objects to return (at most 1000)",
+					"default": 100
+				}
+			},
+			"required": ["bucket"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Bucket         string   `json:"bucket"`
+			Prefix         string   `json:"prefix,omitempty"`
+			Suffix         []string `json:"suffix,omitempty"`
+			MinSize        int64    `json:"min_size,omitempty"`
+			MaxSize        int64    `json:"max_size,omitempty"`
+			ModifiedAfter  string   `json:"modified_after,omitempty"`
+			ModifiedBefore string   `json:"modified_before,omitempty"`
+			StorageClass   string   `json:"storage_class,omitempty"`
+			StartAfter     string   `json:"start_after,omitempty"`
+			Limit          int      `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Bucket == "" {
+			return p.createErrorResult(fmt.Errorf("bucket parameter is required")), nil
+		}
+
+		opts := FindOptions{
+			Prefix:       args.Prefix,
+			StartAfter:   args.StartAfter,
+			Suffixes:     args.Suffix,
+			MinSize:      args.MinSize,
+			MaxSize:      args.MaxSize,
+			StorageClass: strings.TrimSpace(args.StorageClass),
+			Limit:        args.Limit,
+		}
+		now := time.Now()
+		if args.ModifiedAfter != "" {
+			after, err := loki.ParseTime(args.ModifiedAfter, now)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			opts.ModifiedAfter = after
+		}
+		if args.ModifiedBefore != "" {
+			before, err := loki.ParseTime(args.ModifiedBefore, now)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			opts.ModifiedBefore = before
+		}
+
+		result, err := p.client.FindObjects(ctx, args.Bucket, opts)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createS3SelectTool creates the S3 Select tool
+func (p *S3Provider) createS3SelectTool() entity.ToolDefinition {
+	description := "Run an S3 Select SQL query on a CSV or JSON object (optionally gzip or bzip2 compressed) and return the matching records as JSON, without downloading the object. " +
+		"Example: SELECT s.id, s.status FROM S3Object s WHERE s.status = 'failed' LIMIT 50. CSV columns are named by the header row, or _1, _2, ...; JSON fields by path such as s.request.method"
+	if p.client.config != nil && p.client.config.Select.Local {
+		description = "Run an SQL query on a CSV or JSON object (optionally gzip or bzip2 compressed) and return the matching records as JSON. The object is downloaded and queried locally, which supports " +
+			"SELECT */columns/COUNT/SUM/AVG/MIN/MAX FROM S3Object s with WHERE (=, <>, <, >, LIKE, IN, BETWEEN, IS NULL, AND, OR, NOT, CAST, LOWER, UPPER) and LIMIT. " +
+			"Example: SELECT s.id, s.status FROM S3Object s WHERE s.status = 'failed' LIMIT 50. CSV columns are named by the header row, or _1, _2, ...; JSON fields by path such as s.request.method"
+	}
+
+	tool := &mcp.Tool{
+		Name:        "s3_select",
+		Description: description,
+		InputSchema: json.RawMessage(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"bucket": {
+					"type": "string",
+					"description": "S3 bucket name"
+				},
+				"key": {
+					"type": "string",
+					"description": "Object key"
+				},
+				"expression": {
+					"type": "string",
+					"description": "SQL query, e.g. SELECT * FROM S3Object s WHERE CAST(s.latency_ms AS INT) > 500"
+				},
+				"input_format": {
+					"type": "string",
+					"enum": ["csv", "json"],
+					"description": "Object format; guessed from the extension (.csv, .tsv, .json, .jsonl, .ndjson) when omitted"
+				},
+				"csv_header": {
+					"type": "string",
+					"enum": ["use", "ignore", "none"],
+					"description": "CSV first line: column names (use), skipped (ignore) or data (none)",
+					"default": "use"
+				},
+				"csv_delimiter": {
+					"type": "string",
+					"description": "CSV field delimiter; tab for .tsv, comma otherwise"
+				},
+				"json_type": {
+					"type": "string",
+					"enum": ["lines", "document"],
+					"description": "One JSON object per line, or a single document; document for .json, lines otherwise"
+				},
+				"compression": {
+					"type": "string",
+					"enum": ["none", "gzip", "bzip2"],
+					"description": "Object compression; guessed from a .gz or .bz2 extension when omitted"
+				},
+				"max_records": {
+					"type": "integer",
+					"description": "Maximum number of records to return (at most %d)",
+					"default": %d
+				}
+			},
+			"required": ["bucket", "key", "expression"]
+		}`, maxSelectRecords, defaultSelectRecords)),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Bucket       string `json:"bucket"`
+			Key          string `json:"key"`
+			Expression   string `json:"expression"`
+			InputFormat  string `json:"input_format,omitempty"`
+			CSVHeader    string `json:"csv_header,omitempty"`
+			CSVDelimiter string `json:"csv_delimiter,omitempty"`
+			JSONType     string `json:"json_type,omitempty"`
+			Compression  string `json:"compression,omitempty"`
+			MaxRecords   int    `json:"max_records,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Bucket == "" {
+			return p.createErrorResult(fmt.Errorf("bucket parameter is required")), nil
+		}
+
+		if args.Key == "" {
+			return p.createErrorResult(fmt.Errorf("key parameter is required")), nil
+		}
+
+		result, err := p.client.SelectObject(ctx, args.Bucket, args.Key, SelectOptions{
+			Expression:   args.Expression,
+			InputFormat:  args.InputFormat,
+			CSVHeader:    args.CSVHeader,
+			CSVDelimiter: args.CSVDelimiter,
+			JSONType:     args.JSONType,
+			Compression:  args.Compression,
+			MaxRecords:   args.MaxRecords,
+		})
 		if err != nil {
 			return p.createErrorResult(err), nil
 		}
@@ -467,3 +759,246 @@ func (p *S3Provider) createS3GetSizeStatisticsTool() entity.ToolDefinition {
 
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
+
+// createS3PutObjectTool creates the S3 upload tool
+func (p *S3Provider) createS3PutObjectTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "s3_put_object",
+		Description: "Upload an object to S3 (max 5 MB). Blocked while S3 is in read-only mode; existing objects are only replaced when overwrite is set",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"bucket": {
+					"type": "string",
+					"description": "S3 bucket name"
+				},
+				"key": {
+					"type": "string",
+					"description": "Object key"
+				},
+				"content": {
+					"type": "string",
+					"description": "Object content"
+				},
+				"encoding": {
+					"type": "string",
+					"description": "Encoding of content: text or base64 (for binary objects)",
+					"enum": ["text", "base64"],
+					"default": "text"
+				},
+				"content_type": {
+					"type": "string",
+					"description": "Content-Type of the object (default: guessed from the key's extension)"
+				},
+				"overwrite": {
+					"type": "boolean",
+					"description": "Replace the object if it already exists",
+					"default": false
+				}
+			},
+			"required": ["bucket", "key", "content"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Bucket      string `json:"bucket"`
+			Key         string `json:"key"`
+			Content     string `json:"content"`
+			Encoding    string `json:"encoding,omitempty"`
+			ContentType string `json:"content_type,omitempty"`
+			Overwrite   bool   `json:"overwrite,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Bucket == "" || args.Key == "" {
+			return p.createErrorResult(fmt.Errorf("bucket and key parameters are required")), nil
+		}
+
+		var body []byte
+		switch args.Encoding {
+		case "", "text":
+			body = []byte(args.Content)
+		case "base64":
+			decoded, err := base64.StdEncoding.DecodeString(args.Content)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid base64 content: %w", err)), nil
+			}
+			body = decoded
+		default:
+			return p.createErrorResult(fmt.Errorf("unsupported encoding %q (use text or base64)", args.Encoding)), nil
+		}
+
+		contentType := args.ContentType
+		if contentType == "" {
+			if ct := mime.TypeByExtension(path.Ext(args.Key)); ct != "" {
+				contentType = ct
+			}
+		}
+
+		log.Printf("Uploading s3://%s/%s (%d bytes)", args.Bucket, args.Key, len(body))
+		result, err := p.client.PutObject(args.Bucket, args.Key, body, contentType, args.Overwrite)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createS3DeleteObjectTool creates the S3 delete tool
+func (p *S3Provider) createS3DeleteObjectTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "s3_delete_object",
+		Description: "Delete an object (or one version of it) from S3. Blocked while S3 is in read-only mode; in versioned buckets a delete without version_id only adds a delete marker",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"bucket": {
+					"type": "string",
+					"description": "S3 bucket name"
+				},
+				"key": {
+					"type": "string",
+					"description": "Object key"
+				},
+				"version_id": {
+					"type": "string",
+					"description": "Permanently delete this version (versioned buckets only)"
+				}
+			},
+			"required": ["bucket", "key"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Bucket    string `json:"bucket"`
+			Key       string `json:"key"`
+			VersionID string `json:"version_id,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Bucket == "" || args.Key == "" {
+			return p.createErrorResult(fmt.Errorf("bucket and key parameters are required")), nil
+		}
+
+		log.Printf("Deleting s3://%s/%s", args.Bucket, args.Key)
+		result, err := p.client.DeleteObject(args.Bucket, args.Key, args.VersionID)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createS3SecurityTool creates the S3 write access management tool
+func (p *S3Provider) createS3SecurityTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "s3_security",
+		Description: "Manage S3 write access: view status or toggle read-only mode for s3_put_object and s3_delete_object. Requires admin role.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"action": {
+					"type": "string",
+					"description": "Action to perform: 'status', 'enable_writes', 'disable_writes'",
+					"enum": ["status", "enable_writes", "disable_writes"]
+				}
+			},
+			"required": ["action"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Action string `json:"action"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Action == "" {
+			return p.createErrorResult(fmt.Errorf("action parameter is required")), nil
+		}
+
+		log.Printf("Executing S3 security action: %s", args.Action)
+
+		switch args.Action {
+		case "status":
+		case "enable_writes":
+			p.client.SetReadOnly(false)
+		case "disable_writes":
+			p.client.SetReadOnly(true)
+		default:
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{
+						Text: fmt.Sprintf("❌ Unknown action: %s. Available actions: status, enable_writes, disable_writes", args.Action),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
+
+		return p.getSecurityStatus(), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *S3Provider) getSecurityStatus() *mcp.CallToolResult {
+	readOnly := p.client.IsReadOnly()
+
+	statusIcon := "🔒"
+	statusText := "READ-ONLY"
+	advice := "• s3_put_object and s3_delete_object are blocked\n• Use 'enable_writes' for administrative uploads or cleanups"
+	if !readOnly {
+		statusIcon = "⚠️"
+		statusText = "WRITABLE"
+		advice = "• s3_put_object and s3_delete_object are allowed\n• Deletes in unversioned buckets cannot be undone\n• Use 'disable_writes' as soon as the change is done"
+	}
+
+	text := fmt.Sprintf(`%s S3 Security Status: %s
+
+🔐 Current Security Configuration:
+• Read-only: %t
+• Upload limit: %s
+
+%s Security Information:
+%s
+
+Available Actions:
+• status - Show current security status
+• enable_writes - Allow uploads and deletes
+• disable_writes - Return to read-only mode`,
+		statusIcon, statusText,
+		readOnly,
+		humanSize(maxPutObjectSize),
+		statusIcon,
+		advice)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+		},
+		IsError: false,
+	}
+}
+
+// Verify that S3Provider implements ProviderClient interface
+var _ provider.ProviderClient = (*S3Provider)(nil)
@@ -0,0 +1,345 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	defaultSelectRecords = 100
+	maxSelectRecords     = 1000
+	// defaultSelectMaxBytes is the largest object downloaded for a local select unless s3.select.max_bytes is set
+	defaultSelectMaxBytes = 64 << 20
+)
+
+// SelectOptions describes an s3_select query and the serialization of the object it reads
+type SelectOptions struct {
+	Expression   string // S3 Select SQL, e.g. SELECT s.name FROM S3Object s WHERE s.status = 'failed'
+	InputFormat  string // csv or json; guessed from the key when empty
+	CSVHeader    string // use (default), ignore or none
+	CSVDelimiter string // Field delimiter, "," by default
+	JSONType     string // lines or document; guessed from the key when empty
+	Compression  string // none, gzip or bzip2; guessed from the key when empty
+	MaxRecords   int
+}
+
+// SelectResult holds the records a query returned, as JSON values
+type SelectResult struct {
+	Bucket    string            `json:"bucket"`
+	Key       string            `json:"key"`
+	Mode      string            `json:"mode"` // s3 (S3 Select) or local
+	Records   []json.RawMessage `json:"records"`
+	Count     int               `json:"count"`
+	Truncated bool              `json:"truncated"`                // More records than max_records matched
+	Scanned   int64             `json:"bytesScanned,omitempty"`   // Object bytes read
+	Processed int64             `json:"bytesProcessed,omitempty"` // Uncompressed bytes processed (S3 Select only)
+}
+
+// normalize validates the options and fills in what is guessed from the key
+func (o *SelectOptions) normalize(key string) error {
+	if strings.TrimSpace(o.Expression) == "" {
+		return fmt.Errorf("expression is required")
+	}
+	name := strings.ToLower(key)
+	if o.Compression == "" {
+		switch path.Ext(name) {
+		case ".gz":
+			o.Compression = "gzip"
+		case ".bz2":
+			o.Compression = "bzip2"
+		default:
+			o.Compression = "none"
+		}
+	}
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".bz2")
+	ext := path.Ext(name)
+	if o.InputFormat == "" {
+		switch ext {
+		case ".csv", ".tsv", ".txt":
+			o.InputFormat = "csv"
+		case ".json", ".jsonl", ".ndjson":
+			o.InputFormat = "json"
+		default:
+			return fmt.Errorf("cannot tell the format of %q from its extension: set input_format to csv or json", key)
+		}
+	}
+	if o.CSVHeader == "" {
+		o.CSVHeader = "use"
+	}
+	if o.CSVDelimiter == "" {
+		o.CSVDelimiter = ","
+		if ext == ".tsv" {
+			o.CSVDelimiter = "\t"
+		}
+	}
+	if o.JSONType == "" {
+		o.JSONType = "lines"
+		if ext == ".json" {
+			o.JSONType = "document"
+		}
+	}
+	if o.MaxRecords <= 0 {
+		o.MaxRecords = defaultSelectRecords
+	}
+	o.MaxRecords = min(o.MaxRecords, maxSelectRecords)
+
+	switch {
+	case o.InputFormat != "csv" && o.InputFormat != "json":
+		return fmt.Errorf("invalid input_format %q: use csv or json", o.InputFormat)
+	case o.CSVHeader != "use" && o.CSVHeader != "ignore" && o.CSVHeader != "none":
+		return fmt.Errorf("invalid csv_header %q: use use, ignore or none", o.CSVHeader)
+	case len([]rune(o.CSVDelimiter)) != 1:
+		return fmt.Errorf("csv_delimiter must be a single character")
+	case o.JSONType != "lines" && o.JSONType != "document":
+		return fmt.Errorf("invalid json_type %q: use lines or document", o.JSONType)
+	case o.Compression != "none" && o.Compression != "gzip" && o.Compression != "bzip2":
+		return fmt.Errorf("invalid compression %q: use none, gzip or bzip2", o.Compression)
+	}
+	return nil
+}
+
+// SelectObject runs an SQL expression against a CSV or JSON object, with S3 Select or, when s3.select.local
+// is set, by downloading the object and evaluating the query here
+func (c *S3Client) SelectObject(ctx context.Context, bucket, key string, opts SelectOptions) (*SelectResult, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("s3 client not available")
+	}
+
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("bucket and key are required")
+	}
+	if err := opts.normalize(key); err != nil {
+		return nil, err
+	}
+
+	if c.config.Select.Local {
+		return c.selectLocal(ctx, bucket, key, opts)
+	}
+	return c.selectRemote(ctx, bucket, key, opts)
+}
+
+// selectRemote runs the query with SelectObjectContent, reading records until MaxRecords
+func (c *S3Client) selectRemote(ctx context.Context, bucket, key string, opts SelectOptions) (*SelectResult, error) {
+	input := &types.InputSerialization{
+		CompressionType: map[string]types.CompressionType{
+			"none":  types.CompressionTypeNone,
+			"gzip":  types.CompressionTypeGzip,
+			"bzip2": types.CompressionTypeBzip2,
+		}[opts.Compression],
+	}
+	if opts.InputFormat == "csv" {
+		input.CSV = &types.CSVInput{
+			FileHeaderInfo: types.FileHeaderInfo(strings.ToUpper(opts.CSVHeader)),
+			FieldDelimiter: aws.String(opts.CSVDelimiter),
+		}
+	} else {
+		input.JSON = &types.JSONInput{Type: types.JSONType(strings.ToUpper(opts.JSONType))}
+	}
+
+	resp, err := c.s3Client.SelectObjectContent(ctx, &s3.SelectObjectContentInput{
+		Bucket:              &bucket,
+		Key:                 &key,
+		Expression:          &opts.Expression,
+		ExpressionType:      types.ExpressionTypeSql,
+		InputSerialization:  input,
+		OutputSerialization: &types.OutputSerialization{JSON: &types.JSONOutput{RecordDelimiter: aws.String("\n")}},
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("s3 select failed (set s3.select.local to true if the service does not support S3 Select): %w", err)
+	}
+	stream := resp.GetStream()
+	defer stream.Close()
+
+	result := &SelectResult{Bucket: bucket, Key: key, Mode: "s3", Records: []json.RawMessage{}}
+	var pending []byte
+	for event := range stream.Events() {
+		switch e := event.(type) {
+		case *types.SelectObjectContentEventStreamMemberRecords:
+			// A record may be split across events; keep the partial line for the next one
+			pending = append(pending, e.Value.Payload...)
+			for {
+				i := bytes.IndexByte(pending, '\n')
+				if i < 0 {
+					break
+				}
+				line := bytes.TrimSpace(pending[:i])
+				pending = pending[i+1:]
+				if len(line) == 0 {
+					continue
+				}
+				if len(result.Records) == opts.MaxRecords {
+					result.Truncated = true
+					result.Count = len(result.Records)
+					return result, nil
+				}
+				result.Records = append(result.Records, json.RawMessage(bytes.Clone(line)))
+			}
+		case *types.SelectObjectContentEventStreamMemberStats:
+			if e.Value.Details != nil {
+				result.Scanned = aws.ToInt64(e.Value.Details.BytesScanned)
+				result.Processed = aws.ToInt64(e.Value.Details.BytesProcessed)
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("s3 select failed: %w", err)
+	}
+	if line := bytes.TrimSpace(pending); len(line) > 0 && len(result.Records) < opts.MaxRecords {
+		result.Records = append(result.Records, json.RawMessage(line))
+	}
+	result.Count = len(result.Records)
+	return result, nil
+}
+
+// selectLocal downloads the object (up to s3.select.max_bytes) and evaluates the query record by record
+func (c *S3Client) selectLocal(ctx context.Context, bucket, key string, opts SelectOptions) (*SelectResult, error) {
+	query, err := parseSelect(opts.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBytes := c.config.Select.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultSelectMaxBytes
+	}
+	object, err := c.ReadObject(ctx, bucket, key, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader = bytes.NewReader(object.Body)
+	switch opts.Compression {
+	case "gzip":
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress object: %w", err)
+		}
+		defer zr.Close()
+		body = zr
+	case "bzip2":
+		body = bzip2.NewReader(body)
+	}
+
+	result := &SelectResult{Bucket: bucket, Key: key, Mode: "local", Records: []json.RawMessage{}, Scanned: object.Size}
+	run := query.newRun(opts.MaxRecords)
+	if opts.InputFormat == "csv" {
+		err = readCSVRecords(body, opts, run.add)
+	} else {
+		err = readJSONRecords(body, opts.JSONType, run.add)
+	}
+	if err != nil && !errors.Is(err, errSelectDone) {
+		return nil, err
+	}
+	records, truncated, err := run.finish()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		result.Records = append(result.Records, data)
+	}
+	result.Count = len(result.Records)
+	result.Truncated = truncated
+	return result, nil
+}
+
+// readCSVRecords calls fn with each row as a record keyed by column name (with use) and by _1, _2, ...
+func readCSVRecords(r io.Reader, opts SelectOptions, fn func(record) error) error {
+	reader := csv.NewReader(r)
+	reader.Comma = []rune(opts.CSVDelimiter)[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header []string
+	if opts.CSVHeader != "none" {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if opts.CSVHeader == "use" {
+			header = row
+		}
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		rec := record{columns: header, values: make([]interface{}, len(row))}
+		for i, value := range row {
+			rec.values[i] = value
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// readJSONRecords calls fn with each JSON object: one per line, or the elements of a document that is an
+// array (a single object is one record)
+func readJSONRecords(r io.Reader, jsonType string, fn func(record) error) error {
+	decode := func(data []byte) error {
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		if items, ok := value.([]interface{}); ok && jsonType == "document" {
+			for _, item := range items {
+				if err := fn(record{value: item}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return fn(record{value: value})
+	}
+
+	if jsonType == "document" {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return decode(data)
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := decode(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,1007 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The local s3_select evaluates the part of the S3 Select SQL dialect that filters and projects records:
+//
+//	SELECT *|expr [AS name], ...|COUNT(*)|SUM(expr)|AVG|MIN|MAX FROM S3Object[[*]] [[AS] alias] [WHERE cond] [LIMIT n]
+//
+// with =, !=, <>, <, <=, >, >=, [NOT] LIKE, [NOT] IN, [NOT] BETWEEN, IS [NOT] NULL, AND, OR, NOT, CAST and
+// the LOWER, UPPER, TRIM and CHAR_LENGTH functions. Unlike S3 Select, values that look like numbers compare
+// as numbers without a CAST.
+
+// errSelectDone stops reading the object once the query has all the records it returns
+var errSelectDone = errors.New("select done")
+
+// record is a CSV row (values, named by columns when the header is used) or a JSON value
+type record struct {
+	columns []string
+	values  []interface{}
+	value   interface{}
+}
+
+// lookup returns the value at a path, nil when it is missing
+func (r record) lookup(parts []string) interface{} {
+	if r.values != nil {
+		if len(parts) != 1 {
+			return nil
+		}
+		if n, ok := positional(parts[0]); ok {
+			if n <= len(r.values) {
+				return r.values[n-1]
+			}
+			return nil
+		}
+		for i, column := range r.columns {
+			if strings.EqualFold(column, parts[0]) && i < len(r.values) {
+				return r.values[i]
+			}
+		}
+		return nil
+	}
+	value := r.value
+	for _, part := range parts {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if v, ok := object[part]; ok {
+			value = v
+			continue
+		}
+		value = nil
+		for name, v := range object {
+			if strings.EqualFold(name, part) {
+				value = v
+				break
+			}
+		}
+	}
+	return value
+}
+
+// fields returns the record as the output of SELECT *
+func (r record) fields() interface{} {
+	if r.values == nil {
+		return r.value
+	}
+	out := make(orderedRecord, 0, len(r.values))
+	for i, value := range r.values {
+		name := fmt.Sprintf("_%d", i+1)
+		if i < len(r.columns) {
+			name = r.columns[i]
+		}
+		out = append(out, field{name, value})
+	}
+	return out
+}
+
+// positional parses the _1, _2, ... column names of CSV records
+func positional(name string) (int, bool) {
+	if !strings.HasPrefix(name, "_") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(name[1:])
+	return n, err == nil && n > 0
+}
+
+type field struct {
+	name  string
+	value interface{}
+}
+
+// orderedRecord is an output record that keeps the order of the selected columns
+type orderedRecord []field
+
+func (r orderedRecord) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Expressions
+
+type expr interface {
+	eval(r record) (interface{}, error)
+}
+
+type literal struct{ value interface{} }
+
+func (e literal) eval(record) (interface{}, error) { return e.value, nil }
+
+type pathExpr struct{ parts []string }
+
+func (e pathExpr) eval(r record) (interface{}, error) { return r.lookup(e.parts), nil }
+
+type notExpr struct{ x expr }
+
+func (e notExpr) eval(r record) (interface{}, error) {
+	v, err := e.x.eval(r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return !truthy(v), nil
+}
+
+type logicExpr struct {
+	and  bool
+	l, r expr
+}
+
+func (e logicExpr) eval(r record) (interface{}, error) {
+	l, err := e.l.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	if e.and && l != nil && !truthy(l) {
+		return false, nil
+	}
+	if !e.and && truthy(l) {
+		return true, nil
+	}
+	rv, err := e.r.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	if e.and {
+		if rv != nil && !truthy(rv) {
+			return false, nil
+		}
+		if l == nil || rv == nil {
+			return nil, nil
+		}
+		return true, nil
+	}
+	if truthy(rv) {
+		return true, nil
+	}
+	if l == nil || rv == nil {
+		return nil, nil
+	}
+	return false, nil
+}
+
+type compareExpr struct {
+	op   string
+	l, r expr
+}
+
+func (e compareExpr) eval(r record) (interface{}, error) {
+	l, err := e.l.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := e.r.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := compareValues(l, rv)
+	if !ok {
+		return nil, nil
+	}
+	switch e.op {
+	case "=":
+		return c == 0, nil
+	case "!=", "<>":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+type likeExpr struct {
+	x       expr
+	pattern *regexp.Regexp
+	not     bool
+}
+
+func (e likeExpr) eval(r record) (interface{}, error) {
+	v, err := e.x.eval(r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return e.pattern.MatchString(text(v)) != e.not, nil
+}
+
+type isNullExpr struct {
+	x   expr
+	not bool
+}
+
+func (e isNullExpr) eval(r record) (interface{}, error) {
+	v, err := e.x.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	return (v == nil) != e.not, nil
+}
+
+type inExpr struct {
+	x    expr
+	list []expr
+	not  bool
+}
+
+func (e inExpr) eval(r record) (interface{}, error) {
+	v, err := e.x.eval(r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	for _, item := range e.list {
+		iv, err := item.eval(r)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := compareValues(v, iv); ok && c == 0 {
+			return !e.not, nil
+		}
+	}
+	return e.not, nil
+}
+
+type betweenExpr struct {
+	x, low, high expr
+	not          bool
+}
+
+func (e betweenExpr) eval(r record) (interface{}, error) {
+	var values [3]interface{}
+	for i, x := range []expr{e.x, e.low, e.high} {
+		v, err := x.eval(r)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	low, ok1 := compareValues(values[0], values[1])
+	high, ok2 := compareValues(values[0], values[2])
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+	return (low >= 0 && high <= 0) != e.not, nil
+}
+
+type castExpr struct {
+	x      expr
+	toType string
+}
+
+func (e castExpr) eval(r record) (interface{}, error) {
+	v, err := e.x.eval(r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch e.toType {
+	case "STRING", "VARCHAR", "CHAR":
+		return text(v), nil
+	case "BOOL", "BOOLEAN":
+		b, err := strconv.ParseBool(text(v))
+		if err != nil {
+			return nil, fmt.Errorf("cannot cast %q to %s", text(v), e.toType)
+		}
+		return b, nil
+	}
+	f, ok := number(v)
+	if !ok {
+		return nil, fmt.Errorf("cannot cast %q to %s", text(v), e.toType)
+	}
+	if e.toType == "INT" || e.toType == "INTEGER" || e.toType == "BIGINT" {
+		return math.Trunc(f), nil
+	}
+	return f, nil
+}
+
+type funcExpr struct {
+	name string
+	arg  expr
+}
+
+func (e funcExpr) eval(r record) (interface{}, error) {
+	v, err := e.arg.eval(r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch e.name {
+	case "LOWER":
+		return strings.ToLower(text(v)), nil
+	case "UPPER":
+		return strings.ToUpper(text(v)), nil
+	case "TRIM":
+		return strings.TrimSpace(text(v)), nil
+	}
+	return float64(len([]rune(text(v)))), nil
+}
+
+// Values
+
+// number returns the value as a number when it is one or is text that parses as one
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// text returns the value as a string
+func text(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(s)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+func truthy(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// compareValues orders two values, as numbers when both are numeric and as text otherwise; it is not ok
+// when either is null
+func compareValues(a, b interface{}) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return strings.Compare(text(a), text(b)), true
+}
+
+// Queries
+
+type aggregate struct {
+	fn  string // COUNT, SUM, AVG, MIN or MAX
+	arg expr   // Nil for COUNT(*)
+}
+
+type selectItem struct {
+	name string
+	x    expr
+	agg  *aggregate
+}
+
+type selectQuery struct {
+	star  bool
+	items []selectItem
+	where expr
+	limit int // -1 without LIMIT
+}
+
+// selectRun evaluates a query over the records of an object
+type selectRun struct {
+	query     *selectQuery
+	max       int
+	rows      []interface{}
+	truncated bool
+	// Aggregate state, per item
+	counts []int
+	sums   []float64
+	best   []interface{}
+}
+
+func (q *selectQuery) aggregated() bool {
+	return len(q.items) > 0 && q.items[0].agg != nil
+}
+
+func (q *selectQuery) newRun(maxRecords int) *selectRun {
+	return &selectRun{
+		query:  q,
+		max:    maxRecords,
+		counts: make([]int, len(q.items)),
+		sums:   make([]float64, len(q.items)),
+		best:   make([]interface{}, len(q.items)),
+	}
+}
+
+// add evaluates a record; it returns errSelectDone once no further record can change the result
+func (s *selectRun) add(r record) error {
+	if s.query.where != nil {
+		v, err := s.query.where.eval(r)
+		if err != nil {
+			return err
+		}
+		if !truthy(v) {
+			return nil
+		}
+	}
+
+	if s.query.aggregated() {
+		for i, item := range s.query.items {
+			if item.agg.arg == nil {
+				s.counts[i]++
+				continue
+			}
+			v, err := item.agg.arg.eval(r)
+			if err != nil {
+				return err
+			}
+			if v == nil {
+				continue
+			}
+			switch item.agg.fn {
+			case "SUM", "AVG":
+				f, ok := number(v)
+				if !ok {
+					return fmt.Errorf("%s of a value that is not a number: %q", item.agg.fn, text(v))
+				}
+				s.sums[i] += f
+			case "MIN", "MAX":
+				c, _ := compareValues(v, s.best[i])
+				if s.best[i] == nil || (item.agg.fn == "MIN" && c < 0) || (item.agg.fn == "MAX" && c > 0) {
+					s.best[i] = v
+				}
+			}
+			s.counts[i]++
+		}
+		return nil
+	}
+
+	if s.query.limit >= 0 && len(s.rows) == s.query.limit {
+		return errSelectDone
+	}
+	if len(s.rows) == s.max {
+		s.truncated = true
+		return errSelectDone
+	}
+	if s.query.star {
+		s.rows = append(s.rows, r.fields())
+		return nil
+	}
+	row := make(orderedRecord, 0, len(s.query.items))
+	for _, item := range s.query.items {
+		v, err := item.x.eval(r)
+		if err != nil {
+			return err
+		}
+		row = append(row, field{item.name, v})
+	}
+	s.rows = append(s.rows, row)
+	return nil
+}
+
+// finish returns the output records and whether more matched than were kept
+func (s *selectRun) finish() ([]interface{}, bool, error) {
+	if !s.query.aggregated() {
+		return s.rows, s.truncated, nil
+	}
+	row := make(orderedRecord, 0, len(s.query.items))
+	for i, item := range s.query.items {
+		var v interface{}
+		switch item.agg.fn {
+		case "COUNT":
+			v = s.counts[i]
+		case "SUM":
+			if s.counts[i] > 0 {
+				v = s.sums[i]
+			}
+		case "AVG":
+			if s.counts[i] > 0 {
+				v = s.sums[i] / float64(s.counts[i])
+			}
+		default:
+			v = s.best[i]
+		}
+		row = append(row, field{item.name, v})
+	}
+	return []interface{}{row}, false, nil
+}
+
+// Parsing
+
+type token struct {
+	kind  byte // i identifier, q quoted identifier, s string, n number, p punctuation, 0 end
+	value string
+}
+
+func tokenize(sql string) ([]token, error) {
+	var tokens []token
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == c {
+					if j+1 < len(runes) && runes[j+1] == c {
+						sb.WriteRune(c)
+						j++
+						continue
+					}
+					break
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated %c", c)
+			}
+			kind := byte('s')
+			if c == '"' {
+				kind = 'q'
+			}
+			tokens = append(tokens, token{kind, sb.String()})
+			i = j + 1
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E' ||
+				((runes[j] == '-' || runes[j] == '+') && (runes[j-1] == 'e' || runes[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, token{'n', string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{'i', string(runes[i:j])})
+			i = j
+		default:
+			op := string(c)
+			if i+1 < len(runes) {
+				if two := string(runes[i : i+2]); two == "<=" || two == ">=" || two == "!=" || two == "<>" {
+					op = two
+				}
+			}
+			if !strings.Contains("*,.()[]=<>!-", op[:1]) || op == "!" {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, token{'p', op})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{}), nil
+}
+
+type selectParser struct {
+	tokens []token
+	pos    int
+	alias  string
+}
+
+// parseSelect parses an S3 Select SQL expression for local evaluation
+func parseSelect(sql string) (*selectQuery, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	p := &selectParser{tokens: tokens}
+	q, err := p.query()
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w (s3.select.local supports SELECT ... FROM S3Object [WHERE ...] [LIMIT n])", err)
+	}
+	return q, nil
+}
+
+func (p *selectParser) peek() token { return p.tokens[p.pos] }
+
+func (p *selectParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != 0 {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token when it is the keyword
+func (p *selectParser) keyword(word string) bool {
+	if t := p.peek(); t.kind == 'i' && strings.EqualFold(t.value, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// punct consumes the next token when it is the punctuation
+func (p *selectParser) punct(value string) bool {
+	if t := p.peek(); t.kind == 'p' && t.value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *selectParser) expect(value string) error {
+	if p.punct(value) || p.keyword(value) {
+		return nil
+	}
+	return fmt.Errorf("expected %s, found %s", value, describe(p.peek()))
+}
+
+func describe(t token) string {
+	if t.kind == 0 {
+		return "end of expression"
+	}
+	return strconv.Quote(t.value)
+}
+
+var reserved = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AND": true, "OR": true, "NOT": true, "AS": true,
+	"LIKE": true, "IN": true, "IS": true, "NULL": true, "BETWEEN": true, "TRUE": true, "FALSE": true,
+}
+
+func (p *selectParser) query() (*selectQuery, error) {
+	q := &selectQuery{limit: -1}
+	p.alias = p.fromAlias()
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	if p.punct("*") {
+		q.star = true
+	} else {
+		for {
+			item, err := p.selectItem(len(q.items) + 1)
+			if err != nil {
+				return nil, err
+			}
+			q.items = append(q.items, item)
+			if !p.punct(",") {
+				break
+			}
+		}
+		for _, item := range q.items[1:] {
+			if (item.agg != nil) != (q.items[0].agg != nil) {
+				return nil, fmt.Errorf("aggregates cannot be mixed with other columns")
+			}
+		}
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	if t := p.next(); t.kind != 'i' || !strings.EqualFold(t.value, "S3Object") {
+		return nil, fmt.Errorf("expected S3Object, found %s", describe(t))
+	}
+	if p.punct("[") {
+		if !p.punct("*") || !p.punct("]") {
+			return nil, fmt.Errorf("only S3Object[*] is supported")
+		}
+	}
+	if p.punct(".") {
+		return nil, fmt.Errorf("paths into the document after S3Object are not supported")
+	}
+	p.keyword("AS")
+	if t := p.peek(); t.kind == 'i' && !reserved[strings.ToUpper(t.value)] {
+		p.pos++
+	}
+
+	if p.keyword("WHERE") {
+		where, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		q.where = where
+	}
+	if p.keyword("LIMIT") {
+		t := p.next()
+		n, err := strconv.Atoi(t.value)
+		if t.kind != 'n' || err != nil || n < 0 {
+			return nil, fmt.Errorf("LIMIT takes a number, found %s", describe(t))
+		}
+		q.limit = n
+	}
+	if t := p.peek(); t.kind != 0 {
+		return nil, fmt.Errorf("unexpected %s", describe(t))
+	}
+	return q, nil
+}
+
+// fromAlias looks ahead for the alias of S3Object, which the paths of the SELECT list may start with
+func (p *selectParser) fromAlias() string {
+	for i := 0; i+2 < len(p.tokens); i++ {
+		if p.tokens[i].kind != 'i' || !strings.EqualFold(p.tokens[i].value, "FROM") {
+			continue
+		}
+		j := i + 2
+		if p.tokens[j].value == "[" {
+			j += 3
+		}
+		if j < len(p.tokens) && p.tokens[j].kind == 'i' && strings.EqualFold(p.tokens[j].value, "AS") {
+			j++
+		}
+		if j < len(p.tokens) && p.tokens[j].kind == 'i' && !reserved[strings.ToUpper(p.tokens[j].value)] {
+			return p.tokens[j].value
+		}
+		return ""
+	}
+	return ""
+}
+
+func (p *selectParser) selectItem(position int) (selectItem, error) {
+	item := selectItem{name: fmt.Sprintf("_%d", position)}
+	if t := p.peek(); t.kind == 'i' && p.tokens[p.pos+1].value == "(" {
+		switch fn := strings.ToUpper(t.value); fn {
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			p.pos += 2
+			item.agg = &aggregate{fn: fn}
+			if fn == "COUNT" && p.punct("*") {
+				// COUNT(*)
+			} else {
+				arg, err := p.or()
+				if err != nil {
+					return item, err
+				}
+				item.agg.arg = arg
+			}
+			if err := p.expect(")"); err != nil {
+				return item, err
+			}
+		}
+	}
+	if item.agg == nil {
+		x, err := p.or()
+		if err != nil {
+			return item, err
+		}
+		item.x = x
+		if path, ok := x.(pathExpr); ok {
+			item.name = path.parts[len(path.parts)-1]
+		}
+	}
+	if p.keyword("AS") {
+		t := p.next()
+		if t.kind != 'i' && t.kind != 'q' {
+			return item, fmt.Errorf("expected a column name after AS, found %s", describe(t))
+		}
+		item.name = t.value
+	}
+	return item, nil
+}
+
+func (p *selectParser) or() (expr, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = logicExpr{and: false, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *selectParser) and() (expr, error) {
+	l, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		r, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l = logicExpr{and: true, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *selectParser) not() (expr, error) {
+	if p.keyword("NOT") {
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x}, nil
+	}
+	return p.comparison()
+}
+
+func (p *selectParser) comparison() (expr, error) {
+	l, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == 'p' {
+		switch t.value {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.pos++
+			r, err := p.primary()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: t.value, l: l, r: r}, nil
+		}
+	}
+	if p.keyword("IS") {
+		not := p.keyword("NOT")
+		if !p.keyword("NULL") && !p.keyword("MISSING") {
+			return nil, fmt.Errorf("expected NULL after IS, found %s", describe(p.peek()))
+		}
+		return isNullExpr{x: l, not: not}, nil
+	}
+	not := p.keyword("NOT")
+	switch {
+	case p.keyword("LIKE"):
+		t := p.next()
+		if t.kind != 's' {
+			return nil, fmt.Errorf("LIKE takes a string pattern, found %s", describe(t))
+		}
+		return likeExpr{x: l, pattern: likePattern(t.value), not: not}, nil
+	case p.keyword("IN"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		in := inExpr{x: l, not: not}
+		for {
+			item, err := p.primary()
+			if err != nil {
+				return nil, err
+			}
+			in.list = append(in.list, item)
+			if !p.punct(",") {
+				break
+			}
+		}
+		return in, p.expect(")")
+	case p.keyword("BETWEEN"):
+		low, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		return betweenExpr{x: l, low: low, high: high, not: not}, nil
+	case not:
+		return nil, fmt.Errorf("expected LIKE, IN or BETWEEN after NOT, found %s", describe(p.peek()))
+	}
+	return l, nil
+}
+
+func (p *selectParser) primary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case 's':
+		return literal{t.value}, nil
+	case 'n':
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.value)
+		}
+		return literal{f}, nil
+	case 'q':
+		return p.path(t.value)
+	case 'p':
+		switch t.value {
+		case "(":
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "-":
+			if n := p.next(); n.kind == 'n' {
+				f, err := strconv.ParseFloat(n.value, 64)
+				if err == nil {
+					return literal{-f}, nil
+				}
+			}
+			return nil, fmt.Errorf("expected a number after -")
+		}
+	case 'i':
+		switch word := strings.ToUpper(t.value); {
+		case word == "NULL" || word == "MISSING":
+			return literal{nil}, nil
+		case word == "TRUE" || word == "FALSE":
+			return literal{word == "TRUE"}, nil
+		case word == "CAST" && p.punct("("):
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("AS"); err != nil {
+				return nil, err
+			}
+			typ := p.next()
+			switch name := strings.ToUpper(typ.value); name {
+			case "INT", "INTEGER", "BIGINT", "FLOAT", "DOUBLE", "DECIMAL", "NUMERIC", "STRING", "VARCHAR", "CHAR", "BOOL", "BOOLEAN":
+				return castExpr{x: x, toType: name}, p.expect(")")
+			}
+			return nil, fmt.Errorf("unsupported CAST type %s", describe(typ))
+		case (word == "LOWER" || word == "UPPER" || word == "TRIM" || word == "CHAR_LENGTH" || word == "CHARACTER_LENGTH") && p.punct("("):
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			return funcExpr{name: word, arg: arg}, p.expect(")")
+		case reserved[word]:
+			return nil, fmt.Errorf("unexpected %s", describe(t))
+		}
+		return p.path(t.value)
+	}
+	return nil, fmt.Errorf("unexpected %s", describe(t))
+}
+
+// path parses a column or JSON path such as s.name, s."user name" or s.request.status
+func (p *selectParser) path(first string) (expr, error) {
+	parts := []string{first}
+	for p.punct(".") {
+		t := p.next()
+		if t.kind != 'i' && t.kind != 'q' {
+			return nil, fmt.Errorf("expected a name after '.', found %s", describe(t))
+		}
+		parts = append(parts, t.value)
+	}
+	if len(parts) > 1 && (strings.EqualFold(parts[0], p.alias) || strings.EqualFold(parts[0], "S3Object")) {
+		parts = parts[1:]
+	}
+	return pathExpr{parts}, nil
+}
+
+// likePattern converts a LIKE pattern (% and _ wildcards) to a regular expression
+func likePattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
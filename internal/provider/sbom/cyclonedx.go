@@ -0,0 +1,138 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// CycloneDX 1.5 JSON document, limited to the fields this tool fills in
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     []cdxTool    `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTool struct {
+	Name string `json:"name"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Scope      string        `json:"scope,omitempty"`
+	Licenses   []cdxLicense  `json:"licenses,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+// cdxLicense holds either a single license ID or an SPDX expression
+type cdxLicense struct {
+	License    *cdxLicenseID `json:"license,omitempty"`
+	Expression string        `json:"expression,omitempty"`
+}
+
+type cdxLicenseID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// buildCycloneDX converts an inventory into a CycloneDX 1.5 SBOM; license verdicts are recorded as properties
+func buildCycloneDX(inv *Inventory, policy *Policy, now time.Time) *cdxBOM {
+	rootRef := "root:" + inv.Name
+	bom := &cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: now.UTC().Format(time.RFC3339),
+			Tools:     []cdxTool{{Name: "dev-mcp sbom"}},
+			Component: cdxComponent{Type: "application", BOMRef: rootRef, Name: inv.Name, Version: inv.Version},
+		},
+		Components:   make([]cdxComponent, 0, len(inv.Components)),
+		Dependencies: []cdxDependency{{Ref: rootRef, DependsOn: nonNil(inv.DependsOn)}},
+	}
+
+	for _, comp := range inv.Components {
+		c := cdxComponent{
+			Type:     "library",
+			BOMRef:   comp.PURL,
+			Name:     comp.Name,
+			Version:  comp.Version,
+			PURL:     comp.PURL,
+			Scope:    "required",
+			Licenses: cdxLicenses(comp.License),
+			Properties: []cdxProperty{
+				{Name: "dev-mcp:ecosystem", Value: comp.Ecosystem},
+				{Name: "dev-mcp:direct", Value: fmt.Sprintf("%t", comp.Direct)},
+				{Name: "dev-mcp:license_verdict", Value: policy.Evaluate(comp.License)},
+			},
+		}
+		if comp.Dev {
+			c.Scope = "optional"
+		}
+		if comp.Replaced != "" {
+			c.Properties = append(c.Properties, cdxProperty{Name: "dev-mcp:replaced_by", Value: comp.Replaced})
+		}
+		if comp.LicenseSource != "" {
+			c.Properties = append(c.Properties, cdxProperty{Name: "dev-mcp:license_source", Value: comp.LicenseSource})
+		}
+		bom.Components = append(bom.Components, c)
+		bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: comp.PURL, DependsOn: nonNil(comp.DependsOn)})
+	}
+	return bom
+}
+
+// cdxLicenses uses the id form for a single SPDX identifier, the expression form for expressions
+// and the name form for free text such as npm's "SEE LICENSE IN LICENSE.md"
+func cdxLicenses(license string) []cdxLicense {
+	tokens := tokenizeExpression(license)
+	switch {
+	case len(tokens) == 0:
+		return nil
+	case !isExpression(tokens):
+		return []cdxLicense{{License: &cdxLicenseID{Name: license}}}
+	case len(tokens) == 1:
+		return []cdxLicense{{License: &cdxLicenseID{ID: license}}}
+	}
+	return []cdxLicense{{Expression: license}}
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func nonNil(refs []string) []string {
+	if refs == nil {
+		return []string{}
+	}
+	return refs
+}
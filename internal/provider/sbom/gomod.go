@@ -0,0 +1,289 @@
+package sbom
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// ModRequire is a require directive of a go.mod file
+type ModRequire struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// ModReplace is a replace directive; a local replacement has a directory path and no version
+type ModReplace struct {
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+}
+
+// ModFile is the part of a go.mod file an SBOM needs
+type ModFile struct {
+	Module   string
+	Requires []ModRequire
+	Replaces []ModReplace
+}
+
+// ParseModFile reads the module, require and replace directives of a go.mod file
+func ParseModFile(data []byte) (*ModFile, error) {
+	mod := &ModFile{}
+	block := ""
+	for n, line := range strings.Split(string(data), "\n") {
+		indirect := strings.Contains(line, "// indirect")
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			fields = append([]string{block}, fields...)
+		} else if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+
+		for i := range fields {
+			fields[i] = strings.Trim(fields[i], "\"`")
+		}
+		switch fields[0] {
+		case "module":
+			if len(fields) >= 2 {
+				mod.Module = fields[1]
+			}
+		case "require":
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("go.mod line %d: malformed require", n+1)
+			}
+			mod.Requires = append(mod.Requires, ModRequire{Path: fields[1], Version: fields[2], Indirect: indirect})
+		case "replace":
+			arrow := -1
+			for i, f := range fields {
+				if f == "=>" {
+					arrow = i
+				}
+			}
+			if arrow < 2 || arrow+1 >= len(fields) {
+				return nil, fmt.Errorf("go.mod line %d: malformed replace", n+1)
+			}
+			r := ModReplace{OldPath: fields[1], NewPath: fields[arrow+1]}
+			if arrow == 3 {
+				r.OldVersion = fields[2]
+			}
+			if arrow+2 < len(fields) {
+				r.NewVersion = fields[arrow+2]
+			}
+			mod.Replaces = append(mod.Replaces, r)
+		}
+	}
+	if mod.Module == "" {
+		return nil, fmt.Errorf("go.mod has no module directive")
+	}
+	return mod, nil
+}
+
+// replacement returns the replace directive that applies to a module version, if any
+func (m *ModFile) replacement(modPath, version string) (ModReplace, bool) {
+	for _, r := range m.Replaces {
+		if r.OldPath == modPath && (r.OldVersion == "" || r.OldVersion == version) {
+			return r, true
+		}
+	}
+	return ModReplace{}, false
+}
+
+// scanGo lists the modules required by go.mod. Dependency edges come from the go.mod files of the
+// dependencies in the module cache, licenses from their license files.
+func (c *SBOMClient) scanGo(dir string, inv *Inventory) error {
+	data, err := c.readProjectFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return err
+	}
+	mod, err := ParseModFile(data)
+	if err != nil {
+		return err
+	}
+	if inv.Name == "" {
+		inv.Name = mod.Module
+	}
+
+	selected := map[string]*Component{}
+	noSource := 0
+	for _, req := range mod.Requires {
+		if _, ok := selected[req.Path]; ok {
+			continue
+		}
+		comp := &Component{
+			Ecosystem: "go",
+			Name:      req.Path,
+			Version:   req.Version,
+			Direct:    !req.Indirect,
+			PURL:      fmt.Sprintf("pkg:golang/%s@%s", req.Path, req.Version),
+		}
+		source, version := req.Path, req.Version
+		if r, ok := mod.replacement(req.Path, req.Version); ok {
+			comp.Replaced = r.NewPath
+			if r.NewVersion != "" {
+				comp.Replaced += " " + r.NewVersion
+			}
+			source, version = r.NewPath, r.NewVersion
+		}
+		comp.License, comp.LicenseSource = c.goLicense(dir, source, version)
+		if version != "" && !c.inModuleCache(source, version) {
+			noSource++
+		}
+		selected[req.Path] = comp
+		inv.Components = append(inv.Components, comp)
+		if comp.Direct {
+			inv.DependsOn = append(inv.DependsOn, comp.PURL)
+		}
+	}
+
+	missing := 0
+	for _, comp := range selected {
+		requires, ok := c.goModRequires(comp.Name, comp.Version)
+		if !ok {
+			missing++
+			continue
+		}
+		for _, dep := range requires {
+			if target, ok := selected[dep]; ok {
+				comp.DependsOn = append(comp.DependsOn, target.PURL)
+			}
+		}
+	}
+	if noSource > 0 {
+		inv.Notes = append(inv.Notes, fmt.Sprintf("the source of %d Go modules is not in the module cache (%s), so their licenses are unknown; run go mod download", noSource, c.moduleCache))
+	}
+	if missing > 0 {
+		inv.Notes = append(inv.Notes, fmt.Sprintf("the go.mod of %d Go modules is not in the module cache, so their dependencies are not listed; run go mod download", missing))
+	}
+	return nil
+}
+
+// goModRequires returns the module paths a dependency's go.mod requires
+func (c *SBOMClient) goModRequires(modPath, version string) ([]string, bool) {
+	escPath, err1 := escapeModulePath(modPath)
+	escVersion, err2 := escapeModulePath(version)
+	if err1 != nil || err2 != nil {
+		return nil, false
+	}
+	candidates := []string{
+		filepath.Join(c.moduleCache, "cache", "download", filepath.FromSlash(escPath), "@v", escVersion+".mod"),
+		filepath.Join(c.moduleCache, filepath.FromSlash(escPath)+"@"+escVersion, "go.mod"),
+	}
+	for _, file := range candidates {
+		data, err := readLimited(file, maxLicenseBytes)
+		if err != nil {
+			continue
+		}
+		mod, err := ParseModFile(data)
+		if err != nil {
+			return nil, false
+		}
+		var paths []string
+		for _, req := range mod.Requires {
+			paths = append(paths, req.Path)
+		}
+		return paths, true
+	}
+	return nil, false
+}
+
+// goLicense detects the license of a module in the module cache, or of a local replacement directory
+func (c *SBOMClient) goLicense(dir, modPath, version string) (string, string) {
+	if version == "" {
+		// Local replacements are read like other project files
+		local := modPath
+		if !filepath.IsAbs(local) {
+			local = filepath.Join(dir, local)
+		}
+		return c.licenseFromDir(local, c.readProjectFile)
+	}
+	moduleDir, err := c.moduleDir(modPath, version)
+	if err != nil {
+		return "", ""
+	}
+	return c.licenseFromDir(moduleDir, func(file string) ([]byte, error) { return readLimited(file, maxLicenseBytes) })
+}
+
+// moduleDir returns the directory a module version is extracted to in the module cache
+func (c *SBOMClient) moduleDir(modPath, version string) (string, error) {
+	escPath, err := escapeModulePath(modPath)
+	if err != nil {
+		return "", err
+	}
+	escVersion, err := escapeModulePath(version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(c.moduleCache, filepath.FromSlash(escPath)+"@"+escVersion), nil
+}
+
+// inModuleCache reports whether the source of a module version has been downloaded
+func (c *SBOMClient) inModuleCache(modPath, version string) bool {
+	moduleDir, err := c.moduleDir(modPath, version)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(moduleDir)
+	return err == nil && info.IsDir()
+}
+
+// licenseFromDir classifies the license files (LICENSE, LICENCE, COPYING and variants) at the top of a directory
+func (c *SBOMClient) licenseFromDir(dir string, read func(string) ([]byte, error)) (string, string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", ""
+	}
+	var ids, sources []string
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if entry.IsDir() || !(strings.HasPrefix(name, "license") || strings.HasPrefix(name, "licence") || strings.HasPrefix(name, "copying")) {
+			continue
+		}
+		data, err := read(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if id := ClassifyLicense(string(data)); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+			sources = append(sources, entry.Name())
+		}
+	}
+	// Several license files (e.g. LICENSE-MIT and LICENSE-APACHE) are conservatively combined with AND
+	return strings.Join(ids, " AND "), strings.Join(sources, ", ")
+}
+
+// escapeModulePath applies the module cache's case encoding (uppercase letters become ! and the
+// lowercase letter) and rejects paths that could leave the cache
+func escapeModulePath(p string) (string, error) {
+	if p == "" || strings.HasPrefix(p, "/") || strings.Contains(p, "\\") || path.Clean(p) != p || strings.HasPrefix(p, "..") {
+		return "", fmt.Errorf("invalid module path %q", p)
+	}
+	var b strings.Builder
+	for _, r := range p {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,192 @@
+package sbom
+
+import (
+	"regexp"
+	"strings"
+)
+
+// License policy verdicts
+const (
+	VerdictAllowed    = "allowed"
+	VerdictDenied     = "denied"
+	VerdictNotAllowed = "not_allowed" // Not on a configured allow list
+	VerdictUnknown    = "unknown"     // No license could be determined
+)
+
+// licenseSignature identifies a license by phrases that all occur in its text
+type licenseSignature struct {
+	id  string
+	all []string
+}
+
+// licenseSignatures are checked in order, so more specific texts come first
+var licenseSignatures = []licenseSignature{
+	// The MPL and EPL name the GNU licenses as "secondary licenses", so they are matched before them
+	{id: "MPL-2.0", all: []string{"mozilla public license", "2.0"}},
+	{id: "EPL-2.0", all: []string{"eclipse public license", "2.0"}},
+	{id: "AGPL-3.0", all: []string{"gnu affero general public license"}},
+	{id: "LGPL-2.1", all: []string{"gnu lesser general public license", "version 2.1"}},
+	{id: "LGPL-3.0", all: []string{"gnu lesser general public license"}},
+	{id: "GPL-3.0", all: []string{"gnu general public license", "version 3"}},
+	{id: "GPL-2.0", all: []string{"gnu general public license", "version 2"}},
+	{id: "Apache-2.0", all: []string{"apache license", "version 2.0"}},
+	{id: "BSD-3-Clause", all: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", all: []string{"redistribution and use in source and binary forms"}},
+	{id: "MIT", all: []string{"permission is hereby granted, free of charge"}},
+	{id: "ISC", all: []string{"distribute this software for any purpose with or without fee is hereby granted"}},
+	{id: "Unlicense", all: []string{"this is free and unencumbered software released into the public domain"}},
+	{id: "CC0-1.0", all: []string{"cc0 1.0 universal"}},
+	{id: "BSL-1.0", all: []string{"boost software license"}},
+	{id: "Zlib", all: []string{"this software is provided 'as-is', without any express or implied warranty"}},
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// ClassifyLicense returns the SPDX identifier of a license text, or "" when it is not recognized
+func ClassifyLicense(text string) string {
+	normalized := spaces.ReplaceAllString(strings.ToLower(text), " ")
+	for _, sig := range licenseSignatures {
+		if containsAll(normalized, sig.all) {
+			return sig.id
+		}
+	}
+	return ""
+}
+
+func containsAll(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if !strings.Contains(text, phrase) {
+			return false
+		}
+	}
+	return true
+}
+
+// Policy judges licenses against allow and deny lists of SPDX identifiers
+type Policy struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+// NewPolicy creates a policy; with an empty allow list every license that is not denied is allowed
+func NewPolicy(allow, deny []string) *Policy {
+	p := &Policy{allow: map[string]bool{}, deny: map[string]bool{}}
+	for _, id := range allow {
+		p.allow[strings.ToLower(strings.TrimSpace(id))] = true
+	}
+	for _, id := range deny {
+		p.deny[strings.ToLower(strings.TrimSpace(id))] = true
+	}
+	return p
+}
+
+// Evaluate judges a license or SPDX expression such as "MIT OR Apache-2.0". For OR the most permissive
+// choice counts, for AND the most restrictive part.
+func (p *Policy) Evaluate(license string) string {
+	tokens := tokenizeExpression(license)
+	if len(tokens) == 0 {
+		return VerdictUnknown
+	}
+	// Free text such as npm's "SEE LICENSE IN LICENSE.md" needs a human to read it
+	if !isExpression(tokens) {
+		return VerdictUnknown
+	}
+	rank, _ := p.parseOr(tokens)
+	return verdicts[rank]
+}
+
+// verdicts are ordered from most to least permissive
+var verdicts = []string{VerdictAllowed, VerdictUnknown, VerdictNotAllowed, VerdictDenied}
+
+func (p *Policy) parseOr(tokens []string) (int, []string) {
+	rank, rest := p.parseAnd(tokens)
+	for len(rest) > 0 && strings.EqualFold(rest[0], "OR") {
+		var next int
+		next, rest = p.parseAnd(rest[1:])
+		rank = min(rank, next)
+	}
+	return rank, rest
+}
+
+func (p *Policy) parseAnd(tokens []string) (int, []string) {
+	rank, rest := p.parseTerm(tokens)
+	for len(rest) > 0 && strings.EqualFold(rest[0], "AND") {
+		var next int
+		next, rest = p.parseTerm(rest[1:])
+		rank = max(rank, next)
+	}
+	return rank, rest
+}
+
+func (p *Policy) parseTerm(tokens []string) (int, []string) {
+	if len(tokens) == 0 {
+		return 1, nil
+	}
+	if tokens[0] == "(" {
+		rank, rest := p.parseOr(tokens[1:])
+		if len(rest) > 0 && rest[0] == ")" {
+			rest = rest[1:]
+		}
+		return rank, rest
+	}
+	id, rest := tokens[0], tokens[1:]
+	// License exceptions (e.g. GPL-2.0 WITH Classpath-exception-2.0) are judged by the base license
+	if len(rest) >= 2 && strings.EqualFold(rest[0], "WITH") {
+		rest = rest[2:]
+	}
+	return p.rank(id), rest
+}
+
+// rank places a single license identifier in verdicts; GPL-3.0-only and GPL-3.0-or-later match GPL-3.0.
+// npm's UNLICENSED (proprietary) is an ordinary identifier that policies can list.
+func (p *Policy) rank(id string) int {
+	id = strings.ToLower(strings.TrimSuffix(id, "+"))
+	base := strings.TrimSuffix(strings.TrimSuffix(id, "-only"), "-or-later")
+	switch {
+	case id == "noassertion" || id == "unknown":
+		return 1
+	case p.deny[id] || p.deny[base]:
+		return 3
+	case len(p.allow) == 0 || p.allow[id] || p.allow[base]:
+		return 0
+	}
+	return 2
+}
+
+var spdxID = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// isExpression reports whether tokens form an SPDX expression: identifiers joined by AND, OR and WITH, with balanced parentheses
+func isExpression(tokens []string) bool {
+	depth, operand := 0, true // operand: an identifier or ( is expected next
+	for _, token := range tokens {
+		switch op := strings.ToUpper(token); {
+		case token == "(":
+			if !operand {
+				return false
+			}
+			depth++
+		case token == ")":
+			if operand || depth == 0 {
+				return false
+			}
+			depth--
+		case op == "AND" || op == "OR" || op == "WITH":
+			if operand {
+				return false
+			}
+			operand = true
+		default:
+			if !operand || !spdxID.MatchString(token) {
+				return false
+			}
+			operand = false
+		}
+	}
+	return len(tokens) > 0 && !operand && depth == 0
+}
+
+// tokenizeExpression splits an SPDX expression into identifiers, operators and parentheses
+func tokenizeExpression(expr string) []string {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	return strings.Fields(expr)
+}
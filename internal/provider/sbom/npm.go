@@ -0,0 +1,251 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// npmPackage is a package entry of package.json or of package-lock.json's "packages" map (lockfile v2/v3)
+type npmPackage struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	License              json.RawMessage   `json:"license"` // A string, or {"type": ...} in old package.json files
+	Dev                  bool              `json:"dev"`
+	Link                 bool              `json:"link"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// npmLockDependency is an entry of lockfile v1's nested "dependencies" map
+type npmLockDependency struct {
+	Version      string                       `json:"version"`
+	Dev          bool                         `json:"dev"`
+	Requires     map[string]string            `json:"requires"`
+	Dependencies map[string]npmLockDependency `json:"dependencies"`
+}
+
+type npmLock struct {
+	Name            string                       `json:"name"`
+	LockfileVersion int                          `json:"lockfileVersion"`
+	Packages        map[string]npmPackage        `json:"packages"`
+	Dependencies    map[string]npmLockDependency `json:"dependencies"`
+}
+
+// scanNpm lists the packages of package-lock.json, or only the direct dependencies of package.json without one
+func (c *SBOMClient) scanNpm(dir string, includeDev bool, inv *Inventory) error {
+	manifestData, err := c.readProjectFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return err
+	}
+	var manifest npmPackage
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	if inv.Name == "" {
+		inv.Name, inv.Version = manifest.Name, manifest.Version
+	}
+
+	lockData, err := c.readProjectFile(filepath.Join(dir, "package-lock.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		inv.Notes = append(inv.Notes, "no package-lock.json: only direct npm dependencies are listed, with versions from node_modules when installed")
+		c.scanPackageJSON(dir, &manifest, includeDev, inv)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var lock npmLock
+	if err := json.Unmarshal(lockData, &lock); err != nil {
+		return fmt.Errorf("failed to parse package-lock.json: %w", err)
+	}
+	if len(lock.Packages) == 0 && len(lock.Dependencies) > 0 {
+		lock.Packages = flattenLockV1(lock.Dependencies)
+		lock.Packages[""] = manifest
+	}
+	c.scanLockPackages(dir, lock.Packages, includeDev, inv)
+	return nil
+}
+
+// scanLockPackages adds the packages of a lockfile keyed by install location, such as node_modules/a/node_modules/b
+func (c *SBOMClient) scanLockPackages(dir string, packages map[string]npmPackage, includeDev bool, inv *Inventory) {
+	locations := make([]string, 0, len(packages))
+	for location := range packages {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	byLocation := map[string]*Component{}
+	byPURL := map[string]*Component{}
+	for _, location := range locations {
+		pkg := packages[location]
+		if location == "" || pkg.Link || (pkg.Dev && !includeDev) {
+			continue
+		}
+		name := pkg.Name
+		if i := strings.LastIndex(location, "node_modules/"); i >= 0 {
+			name = location[i+len("node_modules/"):]
+		}
+		purl := npmPURL(name, pkg.Version)
+		if existing, ok := byPURL[purl]; ok {
+			byLocation[location] = existing
+			continue
+		}
+		comp := &Component{Ecosystem: "npm", Name: name, Version: pkg.Version, Dev: pkg.Dev, PURL: purl}
+		if comp.License = npmLicense(pkg.License); comp.License != "" {
+			comp.LicenseSource = "package-lock.json"
+		} else {
+			comp.License, comp.LicenseSource = c.installedNpmLicense(filepath.Join(dir, filepath.FromSlash(location)))
+		}
+		byLocation[location] = comp
+		byPURL[purl] = comp
+		inv.Components = append(inv.Components, comp)
+	}
+
+	edges := func(from string, deps ...map[string]string) []string {
+		var refs []string
+		seen := map[string]bool{}
+		for _, group := range deps {
+			for name := range group {
+				if target, ok := byLocation[resolveNpmLocation(packages, from, name)]; ok && !seen[target.PURL] {
+					seen[target.PURL] = true
+					refs = append(refs, target.PURL)
+				}
+			}
+		}
+		sort.Strings(refs)
+		return refs
+	}
+	root := packages[""]
+	rootDeps := []map[string]string{root.Dependencies, root.OptionalDependencies, root.PeerDependencies}
+	if includeDev {
+		rootDeps = append(rootDeps, root.DevDependencies)
+	}
+	inv.DependsOn = append(inv.DependsOn, edges("", rootDeps...)...)
+	for _, ref := range inv.DependsOn {
+		if comp, ok := byPURL[ref]; ok {
+			comp.Direct = true
+		}
+	}
+	for _, location := range locations {
+		comp, ok := byLocation[location]
+		if !ok || comp.DependsOn != nil {
+			continue
+		}
+		pkg := packages[location]
+		comp.DependsOn = edges(location, pkg.Dependencies, pkg.OptionalDependencies, pkg.PeerDependencies)
+	}
+}
+
+// scanPackageJSON lists the direct dependencies of a package.json, reading installed versions from node_modules
+func (c *SBOMClient) scanPackageJSON(dir string, manifest *npmPackage, includeDev bool, inv *Inventory) {
+	add := func(deps map[string]string, dev bool) {
+		names := make([]string, 0, len(deps))
+		for name := range deps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			comp := &Component{Ecosystem: "npm", Name: name, Version: deps[name], Direct: true, Dev: dev}
+			installed := filepath.Join(dir, "node_modules", filepath.FromSlash(name))
+			if data, err := c.readProjectFile(filepath.Join(installed, "package.json")); err == nil {
+				var pkg npmPackage
+				if json.Unmarshal(data, &pkg) == nil && pkg.Version != "" {
+					comp.Version = pkg.Version
+					if comp.License = npmLicense(pkg.License); comp.License != "" {
+						comp.LicenseSource = "package.json"
+					}
+				}
+			}
+			if comp.License == "" {
+				comp.License, comp.LicenseSource = c.licenseFromDir(installed, c.readProjectFile)
+			}
+			comp.PURL = npmPURL(name, comp.Version)
+			inv.Components = append(inv.Components, comp)
+			inv.DependsOn = append(inv.DependsOn, comp.PURL)
+		}
+	}
+	add(manifest.Dependencies, false)
+	add(manifest.OptionalDependencies, false)
+	if includeDev {
+		add(manifest.DevDependencies, true)
+	}
+}
+
+// installedNpmLicense reads the license of an installed package from its package.json or license file
+func (c *SBOMClient) installedNpmLicense(installed string) (string, string) {
+	if data, err := c.readProjectFile(filepath.Join(installed, "package.json")); err == nil {
+		var pkg npmPackage
+		if json.Unmarshal(data, &pkg) == nil {
+			if license := npmLicense(pkg.License); license != "" {
+				return license, "package.json"
+			}
+		}
+	}
+	return c.licenseFromDir(installed, c.readProjectFile)
+}
+
+// resolveNpmLocation finds where Node would load name from when required by the package at location:
+// its own node_modules first, then those of each enclosing package, then the top level
+func resolveNpmLocation(packages map[string]npmPackage, location, name string) string {
+	for location != "" {
+		candidate := location + "/node_modules/" + name
+		if _, ok := packages[candidate]; ok {
+			return candidate
+		}
+		i := strings.LastIndex(location, "/node_modules/")
+		if i < 0 {
+			break
+		}
+		location = location[:i]
+	}
+	return "node_modules/" + name
+}
+
+// flattenLockV1 converts lockfile v1's nested dependencies into v2's location-keyed packages
+func flattenLockV1(deps map[string]npmLockDependency) map[string]npmPackage {
+	packages := map[string]npmPackage{}
+	var walk func(prefix string, deps map[string]npmLockDependency)
+	walk = func(prefix string, deps map[string]npmLockDependency) {
+		for name, dep := range deps {
+			location := prefix + "node_modules/" + name
+			packages[location] = npmPackage{Version: dep.Version, Dev: dep.Dev, Dependencies: dep.Requires}
+			walk(location+"/", dep.Dependencies)
+		}
+	}
+	walk("", deps)
+	return packages
+}
+
+// npmLicense reads a license field, which is an SPDX expression or, in old packages, {"type": "MIT"}
+func npmLicense(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var license string
+	if json.Unmarshal(raw, &license) == nil {
+		return strings.TrimSpace(license)
+	}
+	var typed struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &typed) == nil {
+		return strings.TrimSpace(typed.Type)
+	}
+	return ""
+}
+
+// npmPURL builds a package URL; the @ of a scope is percent-encoded
+func npmPURL(name, version string) string {
+	if strings.HasPrefix(name, "@") {
+		name = "%40" + name[1:]
+	}
+	return "pkg:npm/" + name + "@" + url.PathEscape(version)
+}
@@ -0,0 +1,151 @@
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/file"
+)
+
+const (
+	// maxManifestBytes bounds go.mod, package.json and lock files, which are read into memory
+	maxManifestBytes = 64 << 20
+	// maxLicenseBytes bounds license files and the go.mod files of dependencies
+	maxLicenseBytes = 256 << 10
+)
+
+// Component is a dependency found in a project
+type Component struct {
+	Ecosystem     string   `json:"ecosystem"` // go or npm
+	Name          string   `json:"name"`
+	Version       string   `json:"version"`
+	Direct        bool     `json:"direct"`
+	Dev           bool     `json:"dev,omitempty"`
+	PURL          string   `json:"purl"`
+	Replaced      string   `json:"replaced,omitempty"` // Target of a go.mod replace directive
+	License       string   `json:"license,omitempty"`  // SPDX identifier or expression
+	LicenseSource string   `json:"license_source,omitempty"`
+	DependsOn     []string `json:"-"` // PURLs of the components this one requires
+}
+
+// Inventory is the dependency tree of a project
+type Inventory struct {
+	Name       string
+	Version    string
+	Components []*Component
+	DependsOn  []string // PURLs of the project's direct dependencies
+	Notes      []string // Limits of the scan, such as missing lock files or module cache entries
+}
+
+// SBOMClient reads dependency manifests from whitelisted project directories
+type SBOMClient struct {
+	validator   *file.FileSecurityValidator
+	moduleCache string
+}
+
+// NewSBOMClient creates a new SBOM client; without directories the working directory is allowed
+func NewSBOMClient(cfg *config.SBOMConfig) *SBOMClient {
+	c := &SBOMClient{}
+	var dirs []string
+	if cfg != nil {
+		dirs = cfg.Directories
+		c.moduleCache = cfg.ModuleCache
+	}
+	if c.moduleCache == "" {
+		c.moduleCache = defaultModuleCache()
+	}
+	c.validator = file.NewFileSecurityValidator(dirs)
+	c.validator.SetReadOnly(true)
+	c.validator.SetMaxFileSize(maxManifestBytes)
+	return c
+}
+
+// defaultModuleCache locates the Go module cache the way the go command does
+func defaultModuleCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, "go", "pkg", "mod")
+	}
+	return ""
+}
+
+// Close releases resources held by the client
+func (c *SBOMClient) Close() error {
+	return nil
+}
+
+// Scan lists the Go and npm dependencies of the project in dir; dev-only npm packages are skipped unless includeDev
+func (c *SBOMClient) Scan(dir string, includeDev bool) (*Inventory, error) {
+	inv := &Inventory{}
+	found := false
+	if c.exists(filepath.Join(dir, "go.mod")) {
+		found = true
+		if err := c.scanGo(dir, inv); err != nil {
+			return nil, fmt.Errorf("failed to scan go.mod: %w", err)
+		}
+	}
+	if c.exists(filepath.Join(dir, "package.json")) {
+		found = true
+		if err := c.scanNpm(dir, includeDev, inv); err != nil {
+			return nil, fmt.Errorf("failed to scan package.json: %w", err)
+		}
+		for _, lock := range []string{"yarn.lock", "pnpm-lock.yaml"} {
+			if c.exists(filepath.Join(dir, lock)) {
+				inv.Notes = append(inv.Notes, lock+" is not supported; npm versions come from package-lock.json or node_modules")
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no go.mod or package.json found in %s", dir)
+	}
+	if inv.Name == "" {
+		inv.Name = filepath.Base(dir)
+	}
+
+	sort.SliceStable(inv.Components, func(i, j int) bool {
+		a, b := inv.Components[i], inv.Components[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		return a.Name < b.Name
+	})
+	return inv, nil
+}
+
+// exists reports whether a project file is present and may be read
+func (c *SBOMClient) exists(path string) bool {
+	_, err := c.readProjectFile(path)
+	return err == nil
+}
+
+// readProjectFile reads a file within the allowed directories
+func (c *SBOMClient) readProjectFile(path string) ([]byte, error) {
+	return c.validator.ReadWhitelisted(path, 0)
+}
+
+// readLimited reads at most max bytes of a file outside the project, such as a module cache entry
+func readLimited(file string, max int64) ([]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, errors.New("file is too large")
+	}
+	return data, nil
+}
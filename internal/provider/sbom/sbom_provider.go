@@ -0,0 +1,281 @@
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/s3"
+)
+
+// maxListedViolations bounds each violation list in the tool result; the SBOM itself lists everything
+const maxListedViolations = 100
+
+// FileWriter writes local files; the file provider implements it so its directory whitelist,
+// size limit and read-only mode apply to written reports
+type FileWriter interface {
+	WriteFile(path string, content []byte, createDirs bool) error
+}
+
+// SBOMProvider inventories project dependencies as a CycloneDX SBOM and checks their licenses against a policy
+type SBOMProvider struct {
+	*provider.BaseProvider
+	client   *SBOMClient
+	policy   *Policy
+	files    FileWriter
+	s3Client *s3.S3Client
+}
+
+// NewSBOMProvider creates a new SBOM provider with config; files may be nil, which disables writing local reports
+func NewSBOMProvider(cfg *config.SBOMConfig, s3Cfg *config.S3Config, files FileWriter) *SBOMProvider {
+	p := &SBOMProvider{
+		BaseProvider: provider.NewBaseProvider("sbom"),
+		client:       NewSBOMClient(cfg),
+		policy:       NewPolicy(cfg.Allow, cfg.Deny),
+		files:        files,
+		s3Client:     s3.NewS3Client(s3Cfg),
+	}
+
+	// Manifests are read from local directories (the working directory by default), so the tool is always available
+	p.SetAvailable(true)
+	log.Printf("✓ SBOM provider initialized successfully")
+
+	return p
+}
+
+// Test tests the SBOM configuration (for ProviderClient interface compatibility)
+func (p *SBOMProvider) Test(config interface{}) error {
+	return nil
+}
+
+// AddTools adds SBOM tools to the MCP server (for ProviderClient interface compatibility)
+func (p *SBOMProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the SBOM provider
+func (p *SBOMProvider) Close() error {
+	if err := p.client.Close(); err != nil {
+		return err
+	}
+	return p.s3Client.Close()
+}
+
+// addToolsToServer adds SBOM tools to the MCP server
+func (p *SBOMProvider) addToolsToServer(server *mcp.Server) {
+	tools := []entity.ToolDefinition{
+		p.createSBOMTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered SBOM tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All SBOM tools registered successfully")
+}
+
+// createSBOMTool creates the dependency inventory and license check tool
+func (p *SBOMProvider) createSBOMTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "sbom",
+		Description: "Inventory the dependencies of a Go module (go.mod) and/or npm project (package-lock.json or package.json) as a CycloneDX 1.5 SBOM, and check every license against the configured allow/deny policy. Licenses come from the module cache and node_modules. Returns counts and policy violations; the SBOM is returned inline or written to output_path or S3",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"dir": {
+					"type": "string",
+					"description": "Project directory holding go.mod and/or package.json (within the sbom directories)",
+					"default": "."
+				},
+				"include_dev": {
+					"type": "boolean",
+					"description": "Include npm devDependencies",
+					"default": false
+				},
+				"output_path": {
+					"type": "string",
+					"description": "Write the SBOM to this local file, e.g. reports/sbom.cdx.json (needs a writable file provider)"
+				},
+				"create_dirs": {
+					"type": "boolean",
+					"description": "Create missing parent directories of output_path",
+					"default": false
+				},
+				"s3_bucket": {
+					"type": "string",
+					"description": "Upload the SBOM to this bucket (with s3_key)"
+				},
+				"s3_key": {
+					"type": "string",
+					"description": "Object key for the uploaded SBOM"
+				},
+				"overwrite": {
+					"type": "boolean",
+					"description": "Replace an existing S3 object",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Dir        string `json:"dir,omitempty"`
+			IncludeDev bool   `json:"include_dev,omitempty"`
+			OutputPath string `json:"output_path,omitempty"`
+			CreateDirs bool   `json:"create_dirs,omitempty"`
+			S3Bucket   string `json:"s3_bucket,omitempty"`
+			S3Key      string `json:"s3_key,omitempty"`
+			Overwrite  bool   `json:"overwrite,omitempty"`
+		}{Dir: "."}
+
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if args.Dir == "" {
+			args.Dir = "."
+		}
+		if (args.S3Bucket == "") != (args.S3Key == "") {
+			return p.createErrorResult(fmt.Errorf("s3_bucket and s3_key must be given together")), nil
+		}
+		if args.OutputPath != "" && p.files == nil {
+			return p.createErrorResult(fmt.Errorf("writing reports is not available")), nil
+		}
+		if args.S3Bucket != "" && !p.s3Client.IsAvailable() {
+			return p.createErrorResult(fmt.Errorf("s3 is not configured")), nil
+		}
+
+		inv, err := p.client.Scan(args.Dir, args.IncludeDev)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		bom := buildCycloneDX(inv, p.policy, time.Now())
+		content, err := json.MarshalIndent(bom, "", "  ")
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("failed to encode SBOM: %w", err)), nil
+		}
+
+		result := p.summarize(inv)
+		result["serial_number"] = bom.SerialNumber
+		if args.OutputPath != "" {
+			if err := p.files.WriteFile(args.OutputPath, content, args.CreateDirs); err != nil {
+				return p.createErrorResult(err), nil
+			}
+			result["path"] = args.OutputPath
+			result["written_bytes"] = len(content)
+		}
+		if args.S3Bucket != "" {
+			upload, err := p.s3Client.PutObject(args.S3Bucket, args.S3Key, content, "application/vnd.cyclonedx+json", args.Overwrite)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("failed to upload SBOM: %w", err)), nil
+			}
+			result["s3"] = upload
+		}
+		if args.OutputPath == "" && args.S3Bucket == "" {
+			result["sbom"] = bom
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// summarize counts components and licenses and lists the components the policy flags
+func (p *SBOMProvider) summarize(inv *Inventory) map[string]interface{} {
+	ecosystems := map[string]int{}
+	licenses := map[string]int{}
+	violations := map[string][]*Component{}
+	counts := map[string]int{}
+	direct := 0
+	for _, comp := range inv.Components {
+		ecosystems[comp.Ecosystem]++
+		if comp.Direct {
+			direct++
+		}
+		license := comp.License
+		if license == "" {
+			license = "(unknown)"
+		}
+		licenses[license]++
+
+		verdict := p.policy.Evaluate(comp.License)
+		counts[verdict]++
+		if verdict != VerdictAllowed && len(violations[verdict]) < maxListedViolations {
+			violations[verdict] = append(violations[verdict], comp)
+		}
+	}
+
+	// Denied licenses first, as they are the ones to act on
+	var flagged []map[string]interface{}
+	for _, verdict := range []string{VerdictDenied, VerdictNotAllowed, VerdictUnknown} {
+		for _, comp := range violations[verdict] {
+			flagged = append(flagged, map[string]interface{}{
+				"verdict":   verdict,
+				"component": comp,
+			})
+		}
+	}
+	licenseNames := make([]string, 0, len(licenses))
+	for license := range licenses {
+		licenseNames = append(licenseNames, license)
+	}
+	sort.Slice(licenseNames, func(i, j int) bool {
+		if licenses[licenseNames[i]] != licenses[licenseNames[j]] {
+			return licenses[licenseNames[i]] > licenses[licenseNames[j]]
+		}
+		return licenseNames[i] < licenseNames[j]
+	})
+	licenseCounts := make([]map[string]interface{}, 0, len(licenseNames))
+	for _, license := range licenseNames {
+		licenseCounts = append(licenseCounts, map[string]interface{}{"license": license, "count": licenses[license]})
+	}
+
+	result := map[string]interface{}{
+		"project":    inv.Name,
+		"components": len(inv.Components),
+		"direct":     direct,
+		"ecosystems": ecosystems,
+		"licenses":   licenseCounts,
+		"verdicts":   counts,
+		"violations": flagged,
+		"passed":     counts[VerdictDenied] == 0 && counts[VerdictNotAllowed] == 0,
+	}
+	if len(inv.Notes) > 0 {
+		result["notes"] = inv.Notes
+	}
+	return result
+}
+
+func (p *SBOMProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("SBOM Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *SBOMProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that SBOMProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*SBOMProvider)(nil)
@@ -8,29 +8,42 @@ import (
 
 	"github.com/go-resty/resty/v2"
 
+	"dev-mcp/internal/auth"
 	"dev-mcp/internal/config"
 )
 
 // Issue represents a Sentry issue/group
 type Issue struct {
-	ID          string    `json:"id"`
-	ShortID     string    `json:"shortId"`
-	Title       string    `json:"title"`
-	Culprit     string    `json:"culprit"`
-	Level       string    `json:"level"`
-	Status      string    `json:"status"`
+	ID      string `json:"id"`
+	ShortID string `json:"shortId"`
+	Title   string `json:"title"`
+	Culprit string `json:"culprit"`
+	Level   string `json:"level"`
+	Status  string `json:"status"`
 	Project struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
 		Slug string `json:"slug"`
 	} `json:"project"`
-	Count         string    `json:"count"`
-	UserCount     int       `json:"userCount"`
-	FirstSeen     time.Time `json:"firstSeen"`
-	LastSeen      time.Time `json:"lastSeen"`
-	Environment   *string   `json:"environment"`
+	Count       string    `json:"count"`
+	UserCount   int       `json:"userCount"`
+	FirstSeen   time.Time `json:"firstSeen"`
+	LastSeen    time.Time `json:"lastSeen"`
+	Environment *string   `json:"environment"`
+}
+
+// Project represents a Sentry project
+type Project struct {
+	ID          string    `json:"id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Platform    string    `json:"platform"`
+	DateCreated time.Time `json:"dateCreated"`
 }
 
+// maxProjectPages bounds the pages of projects ListProjects follows
+const maxProjectPages = 10
+
 // SentryClient provides enhanced Sentry operations
 type SentryClient struct {
 	client  *resty.Client
@@ -74,6 +87,100 @@ func NewSentryClient(cfg *config.SentryConfig) *SentryClient {
 	}
 }
 
+// Ping checks that the Sentry API answers and the token can read the organization
+func (c *SentryClient) Ping(ctx context.Context) error {
+	if c.client == nil || c.config == nil {
+		return fmt.Errorf("sentry client not initialized")
+	}
+	req, err := c.request(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := req.Get(fmt.Sprintf("/organizations/%s/", c.config.Organization))
+	if err != nil {
+		return fmt.Errorf("failed to reach sentry: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("sentry API error: %s", resp.Status())
+	}
+	return nil
+}
+
+// ListProjects lists the projects of the organization, following the cursor pagination
+func (c *SentryClient) ListProjects(ctx context.Context) ([]Project, error) {
+	if c.client == nil || c.config == nil {
+		return nil, fmt.Errorf("sentry client not initialized")
+	}
+
+	var projects []Project
+	cursor := ""
+	for page := 0; page < maxProjectPages; page++ {
+		var batch []Project
+		req, err := c.request(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.SetResult(&batch)
+		if cursor != "" {
+			req.SetQueryParam("cursor", cursor)
+		}
+		resp, err := req.Get(fmt.Sprintf("/organizations/%s/projects/", c.config.Organization))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sentry projects: %w", err)
+		}
+		if resp.IsError() {
+			return nil, fmt.Errorf("sentry API error: %s (status: %d)", resp.Status(), resp.StatusCode())
+		}
+		projects = append(projects, batch...)
+
+		if cursor = nextCursor(resp.Header().Get("Link")); cursor == "" {
+			break
+		}
+	}
+	return projects, nil
+}
+
+// ListEnvironments lists the names of the organization's environments
+func (c *SentryClient) ListEnvironments(ctx context.Context) ([]string, error) {
+	if c.client == nil || c.config == nil {
+		return nil, fmt.Errorf("sentry client not initialized")
+	}
+	var environments []struct {
+		Name string `json:"name"`
+	}
+	req, err := c.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := req.SetResult(&environments).Get(fmt.Sprintf("/organizations/%s/environments/", c.config.Organization))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sentry environments: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("sentry API error: %s (status: %d)", resp.Status(), resp.StatusCode())
+	}
+	names := make([]string, 0, len(environments))
+	for _, environment := range environments {
+		names = append(names, environment.Name)
+	}
+	return names, nil
+}
+
+// nextCursor returns the cursor of the next page from a Sentry Link header, or "" on the last page
+func nextCursor(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		if !strings.Contains(part, `rel="next"`) || !strings.Contains(part, `results="true"`) {
+			continue
+		}
+		for _, attr := range strings.Split(part, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(attr), "cursor="); ok {
+				return strings.Trim(value, `"`)
+			}
+		}
+	}
+	return ""
+}
+
 // GetIssues retrieves Sentry issues with optional filtering
 func (c *SentryClient) GetIssues(query string, limit int) (interface{}, error) {
 	if c.client == nil || c.config == nil {
@@ -202,6 +309,147 @@ func (c *SentryClient) GetIssueDetails(issueID string) (interface{}, error) {
 	return result, nil
 }
 
+// GetLatestEvent retrieves the most recent event of an issue, including stack traces and breadcrumbs
+func (c *SentryClient) GetLatestEvent(issueID string, opts EventOptions) (interface{}, error) {
+	if c.client == nil || c.config == nil {
+		return nil, fmt.Errorf("sentry client not initialized")
+	}
+
+	if issueID == "" {
+		return nil, fmt.Errorf("issue ID is required")
+	}
+
+	event, err := c.fetchEvent(fmt.Sprintf("/issues/%s/events/latest/", issueID))
+	if err != nil {
+		return nil, err
+	}
+	return summarizeEvent(event, opts), nil
+}
+
+// GetEvent retrieves a specific event, looked up through its issue or otherwise its project
+func (c *SentryClient) GetEvent(eventID, issueID, project string, opts EventOptions) (interface{}, error) {
+	if c.client == nil || c.config == nil {
+		return nil, fmt.Errorf("sentry client not initialized")
+	}
+
+	if eventID == "" {
+		return nil, fmt.Errorf("event ID is required")
+	}
+
+	var url string
+	switch {
+	case issueID != "":
+		url = fmt.Sprintf("/issues/%s/events/%s/", issueID, eventID)
+	case project != "" || c.config.Project != "":
+		if project == "" {
+			project = c.config.Project
+		}
+		url = fmt.Sprintf("/projects/%s/%s/events/%s/", c.config.Organization, project, eventID)
+	default:
+		return nil, fmt.Errorf("issue_id or project is required to look up an event")
+	}
+
+	event, err := c.fetchEvent(url)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeEvent(event, opts), nil
+}
+
+// fetchEvent fetches and decodes one event
+func (c *SentryClient) fetchEvent(url string) (*Event, error) {
+	resp, err := c.client.R().
+		SetResult(Event{}).
+		Get(url)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sentry event: %w", err)
+	}
+
+	if resp.IsError() {
+		if resp.StatusCode() == 404 {
+			return nil, fmt.Errorf("event not found: %s", url)
+		}
+		return nil, fmt.Errorf("sentry API error: %s", resp.Status())
+	}
+
+	event, ok := resp.Result().(*Event)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse sentry event response")
+	}
+	return event, nil
+}
+
+// IssueUpdate is the body of PUT /issues/{id}/; nil fields are left unchanged
+type IssueUpdate struct {
+	Status        string                 `json:"status,omitempty"`
+	StatusDetails map[string]interface{} `json:"statusDetails,omitempty"`
+	// AssignedTo is "user:<id>", "team:<id>", a username or email; an empty string unassigns
+	AssignedTo *string `json:"assignedTo,omitempty"`
+}
+
+// IsWriteEnabled reports whether issue updates are allowed by config
+func (c *SentryClient) IsWriteEnabled() bool {
+	return c.config != nil && c.config.WriteEnabled
+}
+
+// UpdateIssue changes an issue's status or assignee, as the user the tool call acts for when there is one
+func (c *SentryClient) UpdateIssue(ctx context.Context, issueID string, update IssueUpdate) (map[string]interface{}, error) {
+	if c.client == nil || c.config == nil {
+		return nil, fmt.Errorf("sentry client not initialized")
+	}
+
+	if !c.IsWriteEnabled() {
+		return nil, fmt.Errorf("sentry writes are disabled (set sentry.write_enabled to true)")
+	}
+
+	if issueID == "" {
+		return nil, fmt.Errorf("issue ID is required")
+	}
+
+	url := fmt.Sprintf("/issues/%s/", issueID)
+
+	req, err := c.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := req.
+		SetBody(update).
+		SetResult(map[string]interface{}{}).
+		Put(url)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update sentry issue: %w", err)
+	}
+
+	if resp.IsError() {
+		switch resp.StatusCode() {
+		case 404:
+			return nil, fmt.Errorf("issue not found: %s", issueID)
+		case 400:
+			return nil, fmt.Errorf("sentry rejected the update: %s", strings.TrimSpace(resp.String()))
+		case 403:
+			return nil, fmt.Errorf("sentry API error: %s (the auth token needs the event:write scope)", resp.Status())
+		}
+		return nil, fmt.Errorf("sentry API error: %s", resp.Status())
+	}
+
+	result, ok := resp.Result().(*map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to parse sentry update response")
+	}
+	return *result, nil
+}
+
+// request starts an API request that forwards the user the tool call acts for, if any
+func (c *SentryClient) request(ctx context.Context) (*resty.Request, error) {
+	req := c.client.R().SetContext(ctx)
+	if err := auth.ApplyOnBehalfOf(ctx, "sentry", req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
 // Close closes the Sentry client
 func (c *SentryClient) Close() error {
 	// Sentry client doesn't need explicit closing
@@ -252,8 +500,11 @@ func (c *SentryClient) FetchIssues(ctx context.Context, query string, minutesBac
 	params["limit"] = "100"
 
 	// Make API request
-	resp, err := c.client.R().
-		SetContext(ctx).
+	req, err := c.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := req.
 		SetQueryParams(params).
 		SetResult([]Issue{}).
 		Get(url)
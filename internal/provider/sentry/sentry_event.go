@@ -0,0 +1,341 @@
+package sentry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultMaxFrames      = 30
+	defaultMaxBreadcrumbs = 30
+	// maxFrameContextLines bounds the source lines kept around each frame's line
+	maxFrameContextLines = 5
+)
+
+// sensitiveHeaders are masked in the request context of an event
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+	"proxy-authorization": true,
+}
+
+// Event is a Sentry event as returned by the events API
+type Event struct {
+	ID          string                     `json:"id"`
+	EventID     string                     `json:"eventID"`
+	GroupID     string                     `json:"groupID"`
+	Title       string                     `json:"title"`
+	Message     string                     `json:"message"`
+	Platform    string                     `json:"platform"`
+	DateCreated string                     `json:"dateCreated"`
+	Culprit     string                     `json:"culprit"`
+	Tags        []EventTag                 `json:"tags"`
+	Entries     []EventEntry               `json:"entries"`
+	Contexts    map[string]json.RawMessage `json:"contexts"`
+	User        json.RawMessage            `json:"user"`
+	Release     *struct {
+		Version string `json:"version"`
+	} `json:"release"`
+	SDK *struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"sdk"`
+}
+
+// EventTag is a key/value tag on an event
+type EventTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// EventEntry is one typed section of an event (exception, breadcrumbs, request, message, threads)
+type EventEntry struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// ExceptionValue is one exception in a (possibly chained) exception entry
+type ExceptionValue struct {
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Module    string `json:"module"`
+	Mechanism *struct {
+		Type    string `json:"type"`
+		Handled *bool  `json:"handled"`
+	} `json:"mechanism"`
+	Stacktrace *Stacktrace `json:"stacktrace"`
+}
+
+// Stacktrace holds frames ordered oldest call first, as Sentry sends them
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is one stack frame
+type Frame struct {
+	Filename string                 `json:"filename"`
+	AbsPath  string                 `json:"absPath"`
+	Module   string                 `json:"module"`
+	Function string                 `json:"function"`
+	LineNo   *int                   `json:"lineNo"`
+	ColNo    *int                   `json:"colNo"`
+	InApp    bool                   `json:"inApp"`
+	Context  [][]interface{}        `json:"context"`
+	Vars     map[string]interface{} `json:"vars"`
+}
+
+// Breadcrumb is a trail entry recorded before the event
+type Breadcrumb struct {
+	Timestamp string                 `json:"timestamp,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	Category  string                 `json:"category,omitempty"`
+	Level     string                 `json:"level,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// EventOptions controls how much of an event is returned
+type EventOptions struct {
+	MaxFrames      int
+	MaxBreadcrumbs int
+	InAppOnly      bool
+	IncludeVars    bool
+}
+
+// summarizeEvent reduces an event to what is needed for debugging, keeping the innermost frames
+// and the most recent breadcrumbs
+func summarizeEvent(event *Event, opts EventOptions) map[string]interface{} {
+	if opts.MaxFrames <= 0 {
+		opts.MaxFrames = defaultMaxFrames
+	}
+	if opts.MaxBreadcrumbs <= 0 {
+		opts.MaxBreadcrumbs = defaultMaxBreadcrumbs
+	}
+
+	tags := map[string]string{}
+	for _, tag := range event.Tags {
+		tags[tag.Key] = tag.Value
+	}
+
+	result := map[string]interface{}{
+		"eventId":     event.EventID,
+		"issueId":     event.GroupID,
+		"title":       event.Title,
+		"platform":    event.Platform,
+		"dateCreated": event.DateCreated,
+		"culprit":     event.Culprit,
+		"tags":        tags,
+	}
+	if event.Message != "" {
+		result["message"] = event.Message
+	}
+	if event.Release != nil {
+		result["release"] = event.Release.Version
+	}
+	if event.SDK != nil {
+		result["sdk"] = event.SDK.Name + " " + event.SDK.Version
+	}
+	if len(event.User) > 0 && string(event.User) != "null" {
+		result["user"] = event.User
+	}
+	if len(event.Contexts) > 0 {
+		result["contexts"] = event.Contexts
+	}
+
+	for _, entry := range event.Entries {
+		switch entry.Type {
+		case "exception":
+			var data struct {
+				Values []ExceptionValue `json:"values"`
+			}
+			if err := json.Unmarshal(entry.Data, &data); err == nil {
+				result["exceptions"] = summarizeExceptions(data.Values, opts)
+			}
+		case "threads":
+			// Crashed thread stacks (native/mobile SDKs) when there is no exception entry
+			var data struct {
+				Values []struct {
+					ID         interface{} `json:"id"`
+					Name       string      `json:"name"`
+					Crashed    bool        `json:"crashed"`
+					Stacktrace *Stacktrace `json:"stacktrace"`
+				} `json:"values"`
+			}
+			if err := json.Unmarshal(entry.Data, &data); err == nil {
+				for _, thread := range data.Values {
+					if thread.Crashed && thread.Stacktrace != nil {
+						frames, omitted := selectFrames(thread.Stacktrace.Frames, opts)
+						result["crashedThread"] = map[string]interface{}{
+							"id":            thread.ID,
+							"name":          thread.Name,
+							"frames":        frames,
+							"omittedFrames": omitted,
+						}
+					}
+				}
+			}
+		case "breadcrumbs":
+			var data struct {
+				Values []Breadcrumb `json:"values"`
+			}
+			if err := json.Unmarshal(entry.Data, &data); err == nil {
+				crumbs := data.Values
+				if len(crumbs) > opts.MaxBreadcrumbs {
+					result["omittedBreadcrumbs"] = len(crumbs) - opts.MaxBreadcrumbs
+					crumbs = crumbs[len(crumbs)-opts.MaxBreadcrumbs:]
+				}
+				result["breadcrumbs"] = crumbs
+			}
+		case "request":
+			var data map[string]interface{}
+			if err := json.Unmarshal(entry.Data, &data); err == nil {
+				redactHeaders(data)
+				result["request"] = data
+			}
+		case "message":
+			var data struct {
+				Formatted string `json:"formatted"`
+			}
+			if err := json.Unmarshal(entry.Data, &data); err == nil && data.Formatted != "" {
+				result["message"] = data.Formatted
+			}
+		}
+	}
+
+	return result
+}
+
+// summarizeExceptions renders a chained exception, outermost (the one that was raised last) first
+func summarizeExceptions(values []ExceptionValue, opts EventOptions) []map[string]interface{} {
+	exceptions := make([]map[string]interface{}, 0, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		value := values[i]
+		exception := map[string]interface{}{
+			"type":  value.Type,
+			"value": value.Value,
+		}
+		if value.Module != "" {
+			exception["module"] = value.Module
+		}
+		if value.Mechanism != nil {
+			exception["mechanism"] = value.Mechanism.Type
+			if value.Mechanism.Handled != nil {
+				exception["handled"] = *value.Mechanism.Handled
+			}
+		}
+		if value.Stacktrace != nil {
+			frames, omitted := selectFrames(value.Stacktrace.Frames, opts)
+			exception["frames"] = frames
+			if omitted > 0 {
+				exception["omittedFrames"] = omitted
+			}
+		}
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions
+}
+
+// selectFrames returns up to MaxFrames frames, innermost (where the error happened) first.
+// With InAppOnly, library frames are dropped unless no frame is in-app.
+func selectFrames(frames []Frame, opts EventOptions) ([]map[string]interface{}, int) {
+	candidates := frames
+	if opts.InAppOnly {
+		var inApp []Frame
+		for _, frame := range frames {
+			if frame.InApp {
+				inApp = append(inApp, frame)
+			}
+		}
+		if len(inApp) > 0 {
+			candidates = inApp
+		}
+	}
+
+	selected := make([]map[string]interface{}, 0, min(len(candidates), opts.MaxFrames))
+	for i := len(candidates) - 1; i >= 0 && len(selected) < opts.MaxFrames; i-- {
+		selected = append(selected, formatFrame(candidates[i], opts.IncludeVars))
+	}
+	return selected, len(frames) - len(selected)
+}
+
+func formatFrame(frame Frame, includeVars bool) map[string]interface{} {
+	location := frame.Filename
+	if location == "" {
+		location = frame.AbsPath
+	}
+	if location == "" {
+		location = frame.Module
+	}
+	if frame.LineNo != nil {
+		location = fmt.Sprintf("%s:%d", location, *frame.LineNo)
+		if frame.ColNo != nil {
+			location = fmt.Sprintf("%s:%d", location, *frame.ColNo)
+		}
+	}
+
+	formatted := map[string]interface{}{
+		"function": frame.Function,
+		"location": location,
+		"inApp":    frame.InApp,
+	}
+	if frame.Module != "" {
+		formatted["module"] = frame.Module
+	}
+	if context := frameContext(frame); context != "" {
+		formatted["context"] = context
+	}
+	if includeVars && len(frame.Vars) > 0 {
+		formatted["vars"] = frame.Vars
+	}
+	return formatted
+}
+
+// frameContext renders the source lines around a frame, marking the failing line with "*"
+func frameContext(frame Frame) string {
+	if len(frame.Context) == 0 {
+		return ""
+	}
+	var lines []string
+	for _, pair := range frame.Context {
+		if len(pair) != 2 {
+			continue
+		}
+		number, ok := pair[0].(float64)
+		if !ok {
+			continue
+		}
+		if frame.LineNo != nil && (int(number) < *frame.LineNo-maxFrameContextLines || int(number) > *frame.LineNo+maxFrameContextLines) {
+			continue
+		}
+		marker := " "
+		if frame.LineNo != nil && int(number) == *frame.LineNo {
+			marker = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s %5d | %v", marker, int(number), pair[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redactHeaders masks credentials in a request entry; Sentry sends headers as [name, value] pairs
+func redactHeaders(request map[string]interface{}) {
+	headers, ok := request["headers"].([]interface{})
+	if !ok {
+		return
+	}
+	for _, header := range headers {
+		pair, ok := header.([]interface{})
+		if !ok || len(pair) != 2 {
+			continue
+		}
+		if name, ok := pair[0].(string); ok && sensitiveHeaders[strings.ToLower(name)] {
+			pair[1] = "[redacted]"
+		}
+	}
+	if cookies, ok := request["cookies"]; ok && cookies != nil {
+		request["cookies"] = "[redacted]"
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -19,8 +22,8 @@ type SentryProvider struct {
 	client *SentryClient
 }
 
-// NewSentryProvider creates a new Sentry provider with config and server
-func NewSentryProvider(cfg *config.SentryConfig, server *mcp.Server) *SentryProvider {
+// NewSentryProvider creates a new Sentry provider with config
+func NewSentryProvider(cfg *config.SentryConfig) *SentryProvider {
 	p := &SentryProvider{
 		BaseProvider: provider.NewBaseProvider("sentry"),
 	}
@@ -30,8 +33,6 @@ func NewSentryProvider(cfg *config.SentryConfig, server *mcp.Server) *SentryProv
 
 	if p.client != nil && p.client.IsAvailable() {
 		p.SetAvailable(true)
-		// Add tools to server immediately
-		p.addToolsToServer(server)
 		log.Printf("✓ Sentry provider initialized successfully")
 	} else {
 		p.SetStatus(false, "Sentry client initialization failed", nil)
@@ -40,6 +41,34 @@ func NewSentryProvider(cfg *config.SentryConfig, server *mcp.Server) *SentryProv
 	return p
 }
 
+// Client returns the Sentry client
+func (p *SentryProvider) Client() *SentryClient {
+	return p.client
+}
+
+// HealthCheck checks that Sentry answers
+func (p *SentryProvider) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.client.Ping(ctx)
+}
+
+// ToolHints names the projects and environments issues and events can be looked up in
+func (p *SentryProvider) ToolHints(ctx context.Context) map[string]string {
+	hints := map[string]string{}
+	if projects, err := p.client.ListProjects(ctx); err == nil {
+		slugs := make([]string, 0, len(projects))
+		for _, project := range projects {
+			slugs = append(slugs, project.Slug)
+		}
+		hints["sentry_get_event"] = provider.HintList("Projects", slugs, 20)
+	}
+	if environments, err := p.client.ListEnvironments(ctx); err == nil && len(environments) > 0 {
+		hints["sentry_get_issues"] = provider.HintList("Environments (filter with environment:<name> in query)", environments, 20)
+	}
+	return hints
+}
+
 // Test tests the Sentry configuration and connection (for ProviderClient interface compatibility)
 func (p *SentryProvider) Test(config interface{}) error {
 	// Since client is already initialized in constructor, just check availability
@@ -51,7 +80,6 @@ func (p *SentryProvider) Test(config interface{}) error {
 
 // AddTools adds Sentry tools to the MCP server (for ProviderClient interface compatibility)
 func (p *SentryProvider) AddTools(server *mcp.Server, config interface{}) error {
-	// Tools are already added in constructor, but we can call addToolsToServer again if needed
 	p.addToolsToServer(server)
 	return nil
 }
@@ -70,6 +98,9 @@ func (p *SentryProvider) addToolsToServer(server *mcp.Server) {
 	}{
 		{p.createGetIssuesTools().Tool, p.createGetIssuesTools().Handler},
 		{p.createGetIssueDetailsTool().Tool, p.createGetIssueDetailsTool().Handler},
+		{p.createGetLatestEventTool().Tool, p.createGetLatestEventTool().Handler},
+		{p.createGetEventTool().Tool, p.createGetEventTool().Handler},
+		{p.createUpdateIssueTool().Tool, p.createUpdateIssueTool().Handler},
 	}
 
 	for _, tool := range tools {
@@ -169,6 +200,282 @@ func (p *SentryProvider) createGetIssueDetailsTool() entity.ToolDefinition {
 	return entity.ToolDefinition{Tool: tool, Handler: handler}
 }
 
+// eventOptionsSchema are the input properties shared by the event tools
+const eventOptionsSchema = `
+				"in_app_only": {
+					"type": "boolean",
+					"description": "Only include application frames (library frames are kept if no frame is in-app)",
+					"default": true
+				},
+				"max_frames": {
+					"type": "integer",
+					"description": "Maximum frames per stack trace, innermost first",
+					"default": 30
+				},
+				"max_breadcrumbs": {
+					"type": "integer",
+					"description": "Maximum breadcrumbs, most recent kept",
+					"default": 30
+				},
+				"include_vars": {
+					"type": "boolean",
+					"description": "Include local variables captured in frames",
+					"default": false
+				}`
+
+// eventArgs are the arguments shared by the event tools
+type eventArgs struct {
+	InAppOnly      bool `json:"in_app_only"`
+	MaxFrames      int  `json:"max_frames,omitempty"`
+	MaxBreadcrumbs int  `json:"max_breadcrumbs,omitempty"`
+	IncludeVars    bool `json:"include_vars,omitempty"`
+}
+
+func (a eventArgs) options() EventOptions {
+	return EventOptions{
+		MaxFrames:      a.MaxFrames,
+		MaxBreadcrumbs: a.MaxBreadcrumbs,
+		InAppOnly:      a.InAppOnly,
+		IncludeVars:    a.IncludeVars,
+	}
+}
+
+// createGetLatestEventTool creates the latest event tool
+func (p *SentryProvider) createGetLatestEventTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "sentry_get_latest_event",
+		Description: "Get the latest event of a Sentry issue with exception stack traces (innermost frame first, with source context), breadcrumbs, tags and request context",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"issue_id": {
+					"type": "string",
+					"description": "The ID of the issue"
+				},` + eventOptionsSchema + `
+			},
+			"required": ["issue_id"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			IssueID string `json:"issue_id"`
+			eventArgs
+		}{eventArgs: eventArgs{InAppOnly: true}}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.IssueID == "" {
+			return p.createErrorResult(fmt.Errorf("issue_id is required")), nil
+		}
+
+		result, err := p.client.GetLatestEvent(args.IssueID, args.options())
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createGetEventTool creates the event lookup tool
+func (p *SentryProvider) createGetEventTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "sentry_get_event",
+		Description: "Get a specific Sentry event by ID with exception stack traces, breadcrumbs, tags and request context. Needs the event's issue_id or project (defaults to the configured project)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"event_id": {
+					"type": "string",
+					"description": "The event ID (32 hex characters)"
+				},
+				"issue_id": {
+					"type": "string",
+					"description": "The ID of the issue the event belongs to"
+				},
+				"project": {
+					"type": "string",
+					"description": "Project slug, used when issue_id is not given"
+				},` + eventOptionsSchema + `
+			},
+			"required": ["event_id"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			EventID string `json:"event_id"`
+			IssueID string `json:"issue_id,omitempty"`
+			Project string `json:"project,omitempty"`
+			eventArgs
+		}{eventArgs: eventArgs{InAppOnly: true}}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.EventID == "" {
+			return p.createErrorResult(fmt.Errorf("event_id is required")), nil
+		}
+
+		result, err := p.client.GetEvent(args.EventID, args.IssueID, args.Project, args.options())
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createUpdateIssueTool creates the issue triage tool
+func (p *SentryProvider) createUpdateIssueTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "sentry_update_issue",
+		Description: "Triage a Sentry issue: resolve it (now or in the next release), unresolve it, ignore it (for a duration or a number of occurrences) or assign/unassign it. Requires sentry.write_enabled",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"issue_id": {
+					"type": "string",
+					"description": "The ID of the issue to update"
+				},
+				"action": {
+					"type": "string",
+					"description": "Triage action",
+					"enum": ["resolve", "resolve_in_next_release", "unresolve", "ignore", "assign", "unassign"]
+				},
+				"ignore_duration": {
+					"type": "string",
+					"description": "For ignore: snooze for this long, e.g. 30m, 6h, 7d (default: until unignored)"
+				},
+				"ignore_count": {
+					"type": "integer",
+					"description": "For ignore: snooze until the issue occurs this many more times"
+				},
+				"assignee": {
+					"type": "string",
+					"description": "For assign: user:<id>, team:<id>, a username or an email"
+				}
+			},
+			"required": ["issue_id", "action"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			IssueID        string `json:"issue_id"`
+			Action         string `json:"action"`
+			IgnoreDuration string `json:"ignore_duration,omitempty"`
+			IgnoreCount    int    `json:"ignore_count,omitempty"`
+			Assignee       string `json:"assignee,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.IssueID == "" {
+			return p.createErrorResult(fmt.Errorf("issue_id is required")), nil
+		}
+
+		update, err := buildIssueUpdate(args.Action, args.IgnoreDuration, args.IgnoreCount, args.Assignee)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		updated, err := p.client.UpdateIssue(ctx, args.IssueID, update)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"issue_id": args.IssueID,
+			"action":   args.Action,
+		}
+		for _, key := range []string{"status", "statusDetails", "assignedTo"} {
+			if value, ok := updated[key]; ok {
+				result[key] = value
+			}
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// buildIssueUpdate translates a triage action into the Sentry update body
+func buildIssueUpdate(action, ignoreDuration string, ignoreCount int, assignee string) (IssueUpdate, error) {
+	if (ignoreDuration != "" || ignoreCount > 0) && action != "ignore" {
+		return IssueUpdate{}, fmt.Errorf("ignore_duration and ignore_count only apply to the ignore action")
+	}
+	if assignee != "" && action != "assign" {
+		return IssueUpdate{}, fmt.Errorf("assignee only applies to the assign action")
+	}
+
+	switch action {
+	case "resolve":
+		return IssueUpdate{Status: "resolved"}, nil
+	case "resolve_in_next_release":
+		return IssueUpdate{Status: "resolvedInNextRelease"}, nil
+	case "unresolve":
+		return IssueUpdate{Status: "unresolved"}, nil
+	case "ignore":
+		update := IssueUpdate{Status: "ignored", StatusDetails: map[string]interface{}{}}
+		if ignoreDuration != "" {
+			minutes, err := parseIgnoreMinutes(ignoreDuration)
+			if err != nil {
+				return IssueUpdate{}, err
+			}
+			update.StatusDetails["ignoreDuration"] = minutes
+		}
+		if ignoreCount > 0 {
+			update.StatusDetails["ignoreCount"] = ignoreCount
+		}
+		return update, nil
+	case "assign":
+		if assignee == "" {
+			return IssueUpdate{}, fmt.Errorf("assignee is required for the assign action")
+		}
+		return IssueUpdate{AssignedTo: &assignee}, nil
+	case "unassign":
+		unassigned := ""
+		return IssueUpdate{AssignedTo: &unassigned}, nil
+	case "":
+		return IssueUpdate{}, fmt.Errorf("action is required")
+	default:
+		return IssueUpdate{}, fmt.Errorf("unsupported action: %s", action)
+	}
+}
+
+// parseIgnoreMinutes converts a duration such as 30m, 6h or 7d into whole minutes
+func parseIgnoreMinutes(value string) (int, error) {
+	var duration time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ignore_duration %q", value)
+		}
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ignore_duration %q (use e.g. 30m, 6h or 7d)", value)
+		}
+		duration = parsed
+	}
+	if duration < time.Minute {
+		return 0, fmt.Errorf("ignore_duration must be at least 1m")
+	}
+	return int(duration / time.Minute), nil
+}
+
 // Helper functions
 func (p *SentryProvider) createErrorResult(err error) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
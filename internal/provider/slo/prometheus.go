@@ -0,0 +1,147 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/loki"
+)
+
+const defaultPrometheusTimeout = 30 * time.Second
+
+// Querier runs an instant query and returns its value
+type Querier interface {
+	Instant(ctx context.Context, query string, at time.Time) (float64, error)
+}
+
+// LogSource runs LogQL queries
+type LogSource interface {
+	Query(params loki.QueryParams) (*loki.QueryResponse, error)
+}
+
+// PrometheusClient queries the Prometheus HTTP API
+type PrometheusClient struct {
+	client *resty.Client
+}
+
+// NewPrometheusClient creates a Prometheus client; it returns nil when no URL is configured
+func NewPrometheusClient(cfg *config.PrometheusConfig) *PrometheusClient {
+	if cfg == nil || cfg.URL == "" {
+		return nil
+	}
+
+	timeout := defaultPrometheusTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	client := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.URL, "/")).
+		SetHeader("User-Agent", "dev-mcp/1.0").
+		SetTimeout(timeout)
+	if cfg.Token != "" {
+		client.SetAuthToken(cfg.Token)
+	} else if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+
+	return &PrometheusClient{client: client}
+}
+
+// Instant runs a PromQL instant query at a time (/api/v1/query)
+func (c *PrometheusClient) Instant(ctx context.Context, query string, at time.Time) (float64, error) {
+	var result struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"query": query,
+			"time":  strconv.FormatFloat(float64(at.UnixMilli())/1000, 'f', 3, 64),
+		}).
+		SetResult(&result).
+		SetError(&result).
+		Get("/api/v1/query")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	if resp.IsError() || result.Status != "success" {
+		if result.Error != "" {
+			return 0, fmt.Errorf("prometheus API error: %s: %s", resp.Status(), result.Error)
+		}
+		return 0, fmt.Errorf("prometheus API error: %s", resp.Status())
+	}
+	return sampleValue(result.Data.ResultType, result.Data.Result)
+}
+
+// Ping checks that the Prometheus API answers
+func (c *PrometheusClient) Ping(ctx context.Context) error {
+	_, err := c.Instant(ctx, "vector(1)", time.Now())
+	return err
+}
+
+// lokiQuerier runs LogQL metric queries through the Loki provider
+type lokiQuerier struct {
+	logs LogSource
+}
+
+// Instant runs a LogQL instant metric query at a time
+func (q lokiQuerier) Instant(ctx context.Context, query string, at time.Time) (float64, error) {
+	resp, err := q.logs.Query(loki.QueryParams{Query: query, End: at})
+	if err != nil {
+		return 0, err
+	}
+	return sampleValue(resp.Data.ResultType, resp.Data.Result)
+}
+
+// sampleValue sums an instant vector or reads a scalar; an empty vector (no matching series) is zero
+// and NaN is returned as is
+func sampleValue(resultType string, raw json.RawMessage) (float64, error) {
+	switch resultType {
+	case "scalar":
+		var sample [2]interface{}
+		if err := json.Unmarshal(raw, &sample); err != nil {
+			return 0, fmt.Errorf("invalid scalar result: %w", err)
+		}
+		return parseSample(sample[1])
+	case "vector":
+		var samples []struct {
+			Value [2]interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(raw, &samples); err != nil {
+			return 0, fmt.Errorf("invalid vector result: %w", err)
+		}
+		total := 0.0
+		for _, sample := range samples {
+			value, err := parseSample(sample.Value[1])
+			if err != nil {
+				return 0, err
+			}
+			total += value
+		}
+		return total, nil
+	default:
+		return 0, fmt.Errorf("expected a vector or scalar result, got %q; the query must return a single number", resultType)
+	}
+}
+
+func parseSample(value interface{}) (float64, error) {
+	text, _ := value.(string)
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return math.NaN(), fmt.Errorf("invalid sample value %v", value)
+	}
+	return f, nil
+}
@@ -0,0 +1,296 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"dev-mcp/internal/config"
+)
+
+const defaultPeriodDays = 30
+
+// Window is a burn rate window with the multiwindow alerting threshold from the SRE workbook: a 1h burn
+// of 14.4 spends 2% of a 30-day budget, a 6h burn of 6 spends 5%, a 3d burn of 1 spends 10%
+type Window struct {
+	Name      string
+	Duration  time.Duration
+	Threshold float64
+	Severity  string // page or ticket
+}
+
+// Windows are the standard burn rate windows
+var Windows = []Window{
+	{Name: "1h", Duration: time.Hour, Threshold: 14.4, Severity: "page"},
+	{Name: "6h", Duration: 6 * time.Hour, Threshold: 6, Severity: "page"},
+	{Name: "3d", Duration: 72 * time.Hour, Threshold: 1, Severity: "ticket"},
+}
+
+// Objective is a validated SLO definition
+type Objective struct {
+	config.SLODefinition
+	Budget float64       // Allowed bad ratio, e.g. 0.001 for 99.9%
+	Period time.Duration // Error budget period
+}
+
+// WindowStatus is the burn rate over one window
+type WindowStatus struct {
+	Window          string   `json:"window"`
+	ErrorRatio      *float64 `json:"error_ratio,omitempty"` // Bad events over all events; absent without traffic
+	SLI             *float64 `json:"sli_percent,omitempty"`
+	BurnRate        float64  `json:"burn_rate"`        // Error ratio over the budget; 1 spends the budget exactly over the period
+	BudgetSpent     float64  `json:"budget_spent_pct"` // Share of the period's budget spent within this window
+	AlertThreshold  float64  `json:"alert_threshold"`  // Burn rate above which this window alerts
+	Alerting        bool     `json:"alerting"`
+	Severity        string   `json:"severity"`
+	TotalEventsRate float64  `json:"total_events_per_second"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// BudgetStatus is the error budget over the whole period
+type BudgetStatus struct {
+	Period     string   `json:"period"`
+	ErrorRatio *float64 `json:"error_ratio,omitempty"`
+	SLI        *float64 `json:"sli_percent,omitempty"`
+	Consumed   float64  `json:"consumed_pct"`
+	Remaining  float64  `json:"remaining_pct"`
+	// RemainingOutage is how long a full outage would take to spend what is left
+	RemainingOutage string `json:"remaining_full_outage,omitempty"`
+	// Exhaustion is when the budget runs out at the current 1h burn rate
+	Exhaustion string `json:"exhausted_in,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Status is the burn rate and remaining error budget of an objective
+type Status struct {
+	Name        string         `json:"name"`
+	Service     string         `json:"service,omitempty"`
+	Type        string         `json:"type,omitempty"`
+	Target      float64        `json:"target_percent"`
+	ErrorBudget float64        `json:"error_budget_ratio"`
+	Source      string         `json:"source"`
+	State       string         `json:"state"` // ok, ticket, page, exhausted or unknown
+	Summary     string         `json:"summary"`
+	Windows     []WindowStatus `json:"windows,omitempty"`
+	Budget      *BudgetStatus  `json:"budget,omitempty"`
+	EvaluatedAt time.Time      `json:"evaluated_at"`
+}
+
+// SLOClient computes burn rates and error budgets from Prometheus or Loki
+type SLOClient struct {
+	objectives []Objective
+	sources    map[string]Querier
+}
+
+// NewSLOClient validates the objectives; prometheus and logs may be nil when not configured
+func NewSLOClient(cfg *config.SLOConfig, prometheus *PrometheusClient, logs LogSource) (*SLOClient, error) {
+	c := &SLOClient{sources: map[string]Querier{}}
+	if prometheus != nil {
+		c.sources["prometheus"] = prometheus
+	}
+	if logs != nil {
+		c.sources["loki"] = lokiQuerier{logs: logs}
+	}
+
+	seen := map[string]bool{}
+	for _, def := range cfg.Objectives {
+		if def.Name == "" {
+			return nil, fmt.Errorf("slo.objectives: every objective needs a name")
+		}
+		if seen[def.Name] {
+			return nil, fmt.Errorf("slo.objectives: duplicate objective %q", def.Name)
+		}
+		seen[def.Name] = true
+		if def.Target <= 0 || def.Target >= 100 {
+			return nil, fmt.Errorf("slo objective %s: target must be a percentage between 0 and 100, e.g. 99.9", def.Name)
+		}
+		if def.TotalQuery == "" || (def.BadQuery == "") == (def.GoodQuery == "") {
+			return nil, fmt.Errorf("slo objective %s: give total_query and one of bad_query or good_query", def.Name)
+		}
+		if def.Source == "" {
+			def.Source = "prometheus"
+		}
+		if def.Source != "prometheus" && def.Source != "loki" {
+			return nil, fmt.Errorf("slo objective %s: unknown source %q (use prometheus or loki)", def.Name, def.Source)
+		}
+		if def.PeriodDays <= 0 {
+			def.PeriodDays = defaultPeriodDays
+		}
+		c.objectives = append(c.objectives, Objective{
+			SLODefinition: def,
+			Budget:        1 - def.Target/100,
+			Period:        time.Duration(def.PeriodDays) * 24 * time.Hour,
+		})
+	}
+	return c, nil
+}
+
+// Objectives returns the configured objectives
+func (c *SLOClient) Objectives() []Objective {
+	return c.objectives
+}
+
+// HasSource reports whether the source of an objective is running
+func (c *SLOClient) HasSource(source string) bool {
+	return c.sources[source] != nil
+}
+
+// Get returns the objective with a name
+func (c *SLOClient) Get(name string) (Objective, bool) {
+	for _, objective := range c.objectives {
+		if objective.Name == name {
+			return objective, true
+		}
+	}
+	return Objective{}, false
+}
+
+// Evaluate computes the burn rate over each window and the error budget left in the period ending at a time
+func (c *SLOClient) Evaluate(ctx context.Context, objective Objective, at time.Time) Status {
+	status := Status{
+		Name:        objective.Name,
+		Service:     objective.Service,
+		Type:        objective.Type,
+		Target:      objective.Target,
+		ErrorBudget: round(objective.Budget, 6),
+		Source:      objective.Source,
+		EvaluatedAt: at.UTC(),
+	}
+	querier := c.sources[objective.Source]
+	if querier == nil {
+		status.State = "unknown"
+		status.Summary = fmt.Sprintf("the %s source is not configured", objective.Source)
+		return status
+	}
+
+	var paging, ticketing, failed []string
+	var burn1h *float64
+	for _, window := range Windows {
+		ws := WindowStatus{Window: window.Name, AlertThreshold: window.Threshold, Severity: window.Severity}
+		ratio, total, err := c.errorRatio(ctx, querier, objective, window.Duration, at)
+		switch {
+		case err != nil:
+			ws.Error = err.Error()
+			failed = append(failed, window.Name)
+		case ratio != nil:
+			ws.ErrorRatio = ptr(round(*ratio, 6))
+			ws.SLI = ptr(round((1-*ratio)*100, 4))
+			ws.BurnRate = round(*ratio/objective.Budget, 3)
+			ws.BudgetSpent = round(ws.BurnRate*window.Duration.Hours()/objective.Period.Hours()*100, 2)
+			ws.Alerting = ws.BurnRate > window.Threshold
+			ws.TotalEventsRate = round(total, 4)
+			if window.Name == "1h" {
+				burn1h = &ws.BurnRate
+			}
+		}
+		if ws.Alerting && window.Severity == "page" {
+			paging = append(paging, window.Name)
+		} else if ws.Alerting {
+			ticketing = append(ticketing, window.Name)
+		}
+		status.Windows = append(status.Windows, ws)
+	}
+
+	status.Budget = c.budget(ctx, querier, objective, at, burn1h)
+
+	switch {
+	case status.Budget.Error == "" && status.Budget.ErrorRatio != nil && status.Budget.Remaining <= 0:
+		status.State = "exhausted"
+		status.Summary = fmt.Sprintf("the %s error budget is spent (%.1f%% consumed)", status.Budget.Period, status.Budget.Consumed)
+	case len(paging) > 0:
+		status.State = "page"
+		status.Summary = fmt.Sprintf("fast burn over %s; %.1f%% of the budget left", strings.Join(paging, ", "), status.Budget.Remaining)
+	case len(ticketing) > 0:
+		status.State = "ticket"
+		status.Summary = fmt.Sprintf("slow burn over %s; %.1f%% of the budget left", strings.Join(ticketing, ", "), status.Budget.Remaining)
+	case len(failed) > 0 || status.Budget.Error != "":
+		status.State = "unknown"
+		status.Summary = "some queries failed; see the window errors"
+	default:
+		status.State = "ok"
+		status.Summary = fmt.Sprintf("no window burns too fast; %.1f%% of the budget left", status.Budget.Remaining)
+	}
+	return status
+}
+
+// budget computes the error budget spent over the period ending at a time
+func (c *SLOClient) budget(ctx context.Context, querier Querier, objective Objective, at time.Time, burn1h *float64) *BudgetStatus {
+	budget := &BudgetStatus{Period: fmt.Sprintf("%dd", objective.PeriodDays), Remaining: 100}
+	ratio, _, err := c.errorRatio(ctx, querier, objective, objective.Period, at)
+	if err != nil {
+		budget.Error = err.Error()
+		return budget
+	}
+	if ratio == nil {
+		return budget
+	}
+
+	consumed := *ratio / objective.Budget
+	remaining := 1 - consumed
+	budget.ErrorRatio = ptr(round(*ratio, 6))
+	budget.SLI = ptr(round((1-*ratio)*100, 4))
+	budget.Consumed = round(consumed*100, 2)
+	budget.Remaining = round(remaining*100, 2)
+	if remaining > 0 {
+		left := time.Duration(remaining * objective.Budget * float64(objective.Period))
+		budget.RemainingOutage = left.Round(time.Second).String()
+		if burn1h != nil && *burn1h > 0 {
+			budget.Exhaustion = time.Duration(remaining / *burn1h * float64(objective.Period)).Round(time.Minute).String()
+		}
+	}
+	return budget
+}
+
+// errorRatio returns the bad event ratio and the total event rate over a window; the ratio is nil without traffic
+func (c *SLOClient) errorRatio(ctx context.Context, querier Querier, objective Objective, window time.Duration, at time.Time) (*float64, float64, error) {
+	total, err := querier.Instant(ctx, expand(objective.TotalQuery, window), at)
+	if err != nil {
+		return nil, 0, fmt.Errorf("total_query: %w", err)
+	}
+	if total <= 0 || math.IsNaN(total) {
+		return nil, 0, nil
+	}
+
+	var bad float64
+	if objective.BadQuery != "" {
+		if bad, err = querier.Instant(ctx, expand(objective.BadQuery, window), at); err != nil {
+			return nil, 0, fmt.Errorf("bad_query: %w", err)
+		}
+	} else {
+		good, err := querier.Instant(ctx, expand(objective.GoodQuery, window), at)
+		if err != nil {
+			return nil, 0, fmt.Errorf("good_query: %w", err)
+		}
+		bad = total - good
+	}
+	if math.IsNaN(bad) {
+		bad = 0
+	}
+	ratio := math.Min(math.Max(bad/total, 0), 1)
+	return &ratio, total, nil
+}
+
+// expand replaces ${window} with a Prometheus/LogQL duration
+func expand(query string, window time.Duration) string {
+	var duration string
+	switch {
+	case window%(24*time.Hour) == 0:
+		duration = fmt.Sprintf("%dd", int(window.Hours()/24))
+	case window%time.Hour == 0:
+		duration = fmt.Sprintf("%dh", int(window.Hours()))
+	default:
+		duration = fmt.Sprintf("%dm", int(window.Minutes()))
+	}
+	return strings.ReplaceAll(query, "${window}", duration)
+}
+
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
+
+func ptr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,263 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/loki"
+)
+
+// SLOProvider reports burn rates and error budgets of the configured service level objectives
+type SLOProvider struct {
+	*provider.BaseProvider
+	client     *SLOClient
+	prometheus *PrometheusClient
+}
+
+// NewSLOProvider creates a new SLO provider with config; logs may be nil when the Loki provider is not running
+func NewSLOProvider(cfg *config.SLOConfig, logs LogSource) *SLOProvider {
+	p := &SLOProvider{
+		BaseProvider: provider.NewBaseProvider("slo"),
+		prometheus:   NewPrometheusClient(&cfg.Prometheus),
+	}
+
+	if len(cfg.Objectives) == 0 {
+		p.SetStatus(false, "No SLOs configured", nil)
+		return p
+	}
+
+	client, err := NewSLOClient(cfg, p.prometheus, logs)
+	if err != nil {
+		log.Printf("⚠ SLO configuration invalid: %v", err)
+		p.SetStatus(false, "SLO configuration invalid", err)
+		return p
+	}
+	p.client = client
+
+	for _, objective := range client.Objectives() {
+		if !client.HasSource(objective.Source) {
+			log.Printf("⚠ SLO %s: the %s source is not configured", objective.Name, objective.Source)
+		}
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ SLO provider initialized successfully (%d objectives)", len(client.Objectives()))
+
+	return p
+}
+
+// Test tests the SLO configuration (for ProviderClient interface compatibility)
+func (p *SLOProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("slo provider not available")
+	}
+	return nil
+}
+
+// AddTools adds SLO tools to the MCP server (for ProviderClient interface compatibility)
+func (p *SLOProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the SLO provider
+func (p *SLOProvider) Close() error {
+	return nil
+}
+
+// HealthCheck checks that the Prometheus API answers
+func (p *SLOProvider) HealthCheck() error {
+	if p.prometheus == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.prometheus.Ping(ctx)
+}
+
+// addToolsToServer adds SLO tools to the MCP server
+func (p *SLOProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ SLO provider not available, tools not added")
+		return
+	}
+
+	tools := []entity.ToolDefinition{
+		p.createListTool(),
+		p.createStatusTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered SLO tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All SLO tools registered successfully")
+}
+
+// createListTool creates the tool listing the configured objectives
+func (p *SLOProvider) createListTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "slo_list",
+		Description: "List the configured service level objectives with their target, error budget period, source and queries",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"service": {
+					"type": "string",
+					"description": "Only list objectives of this service"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Service string `json:"service,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		type objective struct {
+			Name        string  `json:"name"`
+			Service     string  `json:"service,omitempty"`
+			Type        string  `json:"type,omitempty"`
+			Description string  `json:"description,omitempty"`
+			Target      float64 `json:"target_percent"`
+			Period      string  `json:"period"`
+			Source      string  `json:"source"`
+			Available   bool    `json:"source_available"`
+			BadQuery    string  `json:"bad_query,omitempty"`
+			GoodQuery   string  `json:"good_query,omitempty"`
+			TotalQuery  string  `json:"total_query"`
+		}
+		objectives := []objective{}
+		for _, o := range p.client.Objectives() {
+			if args.Service != "" && o.Service != args.Service {
+				continue
+			}
+			objectives = append(objectives, objective{
+				Name:        o.Name,
+				Service:     o.Service,
+				Type:        o.Type,
+				Description: o.Description,
+				Target:      o.Target,
+				Period:      fmt.Sprintf("%dd", o.PeriodDays),
+				Source:      o.Source,
+				Available:   p.client.HasSource(o.Source),
+				BadQuery:    o.BadQuery,
+				GoodQuery:   o.GoodQuery,
+				TotalQuery:  o.TotalQuery,
+			})
+		}
+		return p.formatJSONResult(map[string]interface{}{"objectives": objectives, "count": len(objectives)}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createStatusTool creates the burn rate and error budget tool
+func (p *SLOProvider) createStatusTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name: "slo_status",
+		Description: "Compute the burn rate over the last 1h, 6h and 3d and the error budget left in the SLO period for one or all objectives. " +
+			"A burn rate of 1 spends the budget exactly over the period; the 1h and 6h windows page above 14.4 and 6, the 3d window tickets above 1. " +
+			"Also reports how long a full outage would take to spend the rest and when the budget runs out at the current 1h burn",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"name": {
+					"type": "string",
+					"description": "Objective name (default: all objectives)"
+				},
+				"service": {
+					"type": "string",
+					"description": "Only evaluate objectives of this service"
+				},
+				"at": {
+					"type": "string",
+					"description": "Evaluate as of this time: RFC3339, Unix seconds or relative like 2h (ago). Default: now"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Name    string `json:"name,omitempty"`
+			Service string `json:"service,omitempty"`
+			At      string `json:"at,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+
+		at, err := loki.ParseTime(args.At, time.Now())
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid at: %w", err)), nil
+		}
+
+		var objectives []Objective
+		if args.Name != "" {
+			objective, ok := p.client.Get(args.Name)
+			if !ok {
+				return p.createErrorResult(fmt.Errorf("unknown objective %q (see slo_list)", args.Name)), nil
+			}
+			objectives = append(objectives, objective)
+		} else {
+			for _, objective := range p.client.Objectives() {
+				if args.Service == "" || objective.Service == args.Service {
+					objectives = append(objectives, objective)
+				}
+			}
+		}
+		if len(objectives) == 0 {
+			return p.createErrorResult(fmt.Errorf("no objectives for service %q (see slo_list)", args.Service)), nil
+		}
+
+		statuses := make([]Status, 0, len(objectives))
+		states := map[string]int{}
+		for _, objective := range objectives {
+			status := p.client.Evaluate(ctx, objective, at)
+			states[status.State]++
+			statuses = append(statuses, status)
+		}
+		return p.formatJSONResult(map[string]interface{}{"objectives": statuses, "states": states}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func (p *SLOProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("SLO Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *SLOProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that SLOProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*SLOProvider)(nil)
@@ -0,0 +1,423 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider/s3"
+)
+
+const redacted = "(sensitive)"
+
+// sensitiveKeyPattern matches attribute names that are redacted even when not marked sensitive in state
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|secret|token|private_key|access_key|credentials)`)
+
+// State is the subset of the Terraform state (format version 4) we inspect
+type State struct {
+	Version          int                    `json:"version"`
+	TerraformVersion string                 `json:"terraform_version"`
+	Serial           int64                  `json:"serial"`
+	Lineage          string                 `json:"lineage"`
+	Outputs          map[string]StateOutput `json:"outputs"`
+	Resources        []StateResource        `json:"resources"`
+}
+
+// StateOutput is a root module output
+type StateOutput struct {
+	Value     interface{} `json:"value"`
+	Type      interface{} `json:"type"`
+	Sensitive bool        `json:"sensitive"`
+}
+
+// StateResource is a resource block with its instances
+type StateResource struct {
+	Module    string          `json:"module,omitempty"`
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Provider  string          `json:"provider"`
+	Instances []StateInstance `json:"instances"`
+}
+
+// StateInstance is a single instance of a resource
+type StateInstance struct {
+	IndexKey            interface{}            `json:"index_key,omitempty"`
+	SchemaVersion       int                    `json:"schema_version"`
+	Attributes          map[string]interface{} `json:"attributes"`
+	SensitiveAttributes []interface{}          `json:"sensitive_attributes,omitempty"`
+	Dependencies        []string               `json:"dependencies,omitempty"`
+}
+
+// ResourceSummary is a resource instance listed by address
+type ResourceSummary struct {
+	Address  string `json:"address"`
+	Module   string `json:"module,omitempty"`
+	Mode     string `json:"mode"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	ID       string `json:"id,omitempty"`
+}
+
+// ResourceDetail is a resource instance with its (redacted) attributes
+type ResourceDetail struct {
+	ResourceSummary
+	Attributes   map[string]interface{} `json:"attributes"`
+	Dependencies []string               `json:"dependencies,omitempty"`
+
+	raw map[string]interface{} // unredacted attributes, used for diffing
+}
+
+// AttributeChange is a changed top-level attribute
+type AttributeChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ResourceChange describes a changed resource between two states
+type ResourceChange struct {
+	Address    string                     `json:"address"`
+	Attributes map[string]AttributeChange `json:"attributes"`
+}
+
+// StateDiff is the difference between two state versions
+type StateDiff struct {
+	FromSerial int64                      `json:"from_serial"`
+	ToSerial   int64                      `json:"to_serial"`
+	SameLine   bool                       `json:"same_lineage"`
+	Added      []string                   `json:"added"`
+	Removed    []string                   `json:"removed"`
+	Changed    []ResourceChange           `json:"changed"`
+	Outputs    map[string]AttributeChange `json:"outputs_changed,omitempty"`
+}
+
+// TerraformClient loads Terraform state files from disk or an S3 backend
+type TerraformClient struct {
+	states   map[string]config.TerraformStateConfig
+	s3Client *s3.S3Client
+}
+
+// NewTerraformClient creates a new Terraform state client
+func NewTerraformClient(cfg *config.TerraformConfig, s3Cfg *config.S3Config) *TerraformClient {
+	c := &TerraformClient{states: map[string]config.TerraformStateConfig{}}
+	if cfg == nil {
+		return c
+	}
+
+	needsS3 := false
+	for _, st := range cfg.States {
+		if st.Name == "" || (st.Path == "" && (st.Bucket == "" || st.Key == "")) {
+			continue
+		}
+		c.states[st.Name] = st
+		if st.Path == "" {
+			needsS3 = true
+		}
+	}
+	if needsS3 {
+		c.s3Client = s3.NewS3Client(s3Cfg)
+	}
+	return c
+}
+
+// IsAvailable checks if at least one state is configured
+func (c *TerraformClient) IsAvailable() bool {
+	return len(c.states) > 0
+}
+
+// States returns the configured states sorted by name
+func (c *TerraformClient) States() []config.TerraformStateConfig {
+	out := make([]config.TerraformStateConfig, 0, len(c.states))
+	for _, st := range c.states {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// stateConfig resolves a state name; an empty name is allowed when exactly one state is configured
+func (c *TerraformClient) stateConfig(name string) (config.TerraformStateConfig, error) {
+	if name == "" && len(c.states) == 1 {
+		for _, st := range c.states {
+			return st, nil
+		}
+	}
+	st, ok := c.states[name]
+	if !ok {
+		names := make([]string, 0, len(c.states))
+		for n := range c.states {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return st, fmt.Errorf("terraform state %q is not configured (available: %v)", name, names)
+	}
+	return st, nil
+}
+
+// Versions lists the S3 object versions of a state (newest first)
+func (c *TerraformClient) Versions(name string, limit int) ([]s3.ObjectVersion, error) {
+	st, err := c.stateConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	if st.Path != "" {
+		return nil, fmt.Errorf("state %q is a local file; versions are only available for S3 backends", st.Name)
+	}
+	return c.s3Client.ListObjectVersions(st.Bucket, st.Key, limit)
+}
+
+// Load reads and parses a state. For S3 states version is an object version ID;
+// for local states it may name another state file (e.g. terraform.tfstate.backup).
+// An empty version reads the current state.
+func (c *TerraformClient) Load(name, version string) (*State, error) {
+	st, err := c.stateConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if st.Path != "" {
+		path := st.Path
+		if version != "" {
+			path = version
+		}
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state file: %w", err)
+		}
+	} else {
+		if c.s3Client == nil || !c.s3Client.IsAvailable() {
+			return nil, fmt.Errorf("s3 client not available for state %q", st.Name)
+		}
+		data, err = c.s3Client.GetObjectVersionBytes(st.Bucket, st.Key, version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state from s3://%s/%s: %w", st.Bucket, st.Key, err)
+		}
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported state format version %d (only version 4 is supported)", state.Version)
+	}
+	return &state, nil
+}
+
+// address builds the Terraform address of a resource instance
+func address(r StateResource, inst StateInstance) string {
+	var b strings.Builder
+	if r.Module != "" {
+		b.WriteString(r.Module + ".")
+	}
+	if r.Mode == "data" {
+		b.WriteString("data.")
+	}
+	b.WriteString(r.Type + "." + r.Name)
+	switch key := inst.IndexKey.(type) {
+	case float64:
+		b.WriteString(fmt.Sprintf("[%d]", int64(key)))
+	case string:
+		b.WriteString(fmt.Sprintf("[%q]", key))
+	}
+	return b.String()
+}
+
+// providerName shortens `provider["registry.terraform.io/hashicorp/aws"]` to "hashicorp/aws"
+func providerName(p string) string {
+	p = strings.TrimPrefix(p, "provider[\"")
+	p = strings.TrimSuffix(p, "\"]")
+	return strings.TrimPrefix(p, "registry.terraform.io/")
+}
+
+// ListResources lists resource instances, optionally filtered by type and module prefix
+func (s *State) ListResources(typeFilter, moduleFilter string) []ResourceSummary {
+	var out []ResourceSummary
+	for _, r := range s.Resources {
+		if typeFilter != "" && r.Type != typeFilter {
+			continue
+		}
+		if moduleFilter != "" && !strings.HasPrefix(r.Module, moduleFilter) {
+			continue
+		}
+		for _, inst := range r.Instances {
+			summary := ResourceSummary{
+				Address:  address(r, inst),
+				Module:   r.Module,
+				Mode:     r.Mode,
+				Type:     r.Type,
+				Name:     r.Name,
+				Provider: providerName(r.Provider),
+			}
+			if id, ok := inst.Attributes["id"].(string); ok {
+				summary.ID = id
+			}
+			out = append(out, summary)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address < out[j].Address })
+	return out
+}
+
+// instances indexes every resource instance by address
+func (s *State) instances() map[string]ResourceDetail {
+	out := map[string]ResourceDetail{}
+	for _, r := range s.Resources {
+		for _, inst := range r.Instances {
+			addr := address(r, inst)
+			detail := ResourceDetail{
+				ResourceSummary: ResourceSummary{
+					Address:  addr,
+					Module:   r.Module,
+					Mode:     r.Mode,
+					Type:     r.Type,
+					Name:     r.Name,
+					Provider: providerName(r.Provider),
+				},
+				Attributes:   redact(inst.Attributes, inst.SensitiveAttributes),
+				Dependencies: inst.Dependencies,
+				raw:          inst.Attributes,
+			}
+			if id, ok := inst.Attributes["id"].(string); ok {
+				detail.ID = id
+			}
+			out[addr] = detail
+		}
+	}
+	return out
+}
+
+// Resource returns a single resource instance by address with sensitive values redacted
+func (s *State) Resource(addr string) (*ResourceDetail, error) {
+	detail, ok := s.instances()[addr]
+	if !ok {
+		return nil, fmt.Errorf("resource not found in state: %s", addr)
+	}
+	return &detail, nil
+}
+
+// Diff compares two states resource by resource
+func Diff(from, to *State) *StateDiff {
+	diff := &StateDiff{
+		FromSerial: from.Serial,
+		ToSerial:   to.Serial,
+		SameLine:   from.Lineage == to.Lineage,
+		Added:      []string{},
+		Removed:    []string{},
+		Changed:    []ResourceChange{},
+	}
+
+	before, after := from.instances(), to.instances()
+	for addr, b := range before {
+		a, ok := after[addr]
+		if !ok {
+			diff.Removed = append(diff.Removed, addr)
+			continue
+		}
+		if changes := diffAttributes(b, a); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, ResourceChange{Address: addr, Attributes: changes})
+		}
+	}
+	for addr := range after {
+		if _, ok := before[addr]; !ok {
+			diff.Added = append(diff.Added, addr)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Address < diff.Changed[j].Address })
+
+	outputs := map[string]AttributeChange{}
+	for name, o := range to.Outputs {
+		old, ok := from.Outputs[name]
+		if !ok || !reflect.DeepEqual(old.Value, o.Value) {
+			change := AttributeChange{After: outputValue(o)}
+			if ok {
+				change.Before = outputValue(old)
+			}
+			outputs[name] = change
+		}
+	}
+	for name, o := range from.Outputs {
+		if _, ok := to.Outputs[name]; !ok {
+			outputs[name] = AttributeChange{Before: outputValue(o)}
+		}
+	}
+	if len(outputs) > 0 {
+		diff.Outputs = outputs
+	}
+	return diff
+}
+
+// diffAttributes compares top-level attributes on their raw values and reports the redacted ones,
+// so a rotated secret shows up as changed without being revealed
+func diffAttributes(before, after ResourceDetail) map[string]AttributeChange {
+	changes := map[string]AttributeChange{}
+	for k, b := range before.raw {
+		a, ok := after.raw[k]
+		if !ok || !reflect.DeepEqual(a, b) {
+			changes[k] = AttributeChange{Before: before.Attributes[k], After: after.Attributes[k]}
+		}
+	}
+	for k := range after.raw {
+		if _, ok := before.raw[k]; !ok {
+			changes[k] = AttributeChange{After: after.Attributes[k]}
+		}
+	}
+	return changes
+}
+
+// OutputValues returns root module outputs with sensitive values redacted
+func (s *State) OutputValues() map[string]interface{} {
+	out := map[string]interface{}{}
+	for name, o := range s.Outputs {
+		out[name] = outputValue(o)
+	}
+	return out
+}
+
+func outputValue(o StateOutput) interface{} {
+	if o.Sensitive {
+		return redacted
+	}
+	return o.Value
+}
+
+// redact replaces sensitive top-level attributes: those listed in sensitive_attributes
+// and any whose name looks like a credential
+func redact(attrs map[string]interface{}, sensitive []interface{}) map[string]interface{} {
+	marked := map[string]bool{}
+	for _, path := range sensitive {
+		steps, ok := path.([]interface{})
+		if !ok || len(steps) == 0 {
+			continue
+		}
+		if step, ok := steps[0].(map[string]interface{}); ok {
+			if name, ok := step["value"].(string); ok {
+				marked[name] = true
+			}
+		}
+	}
+
+	out := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		if marked[k] || (sensitiveKeyPattern.MatchString(k) && v != nil && v != "") {
+			out[k] = redacted
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Close closes the Terraform client
+func (c *TerraformClient) Close() error {
+	return nil
+}
@@ -0,0 +1,344 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	"dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+)
+
+// TerraformProvider provides read-only inspection of Terraform state
+type TerraformProvider struct {
+	*provider.BaseProvider
+	client *TerraformClient
+}
+
+// NewTerraformProvider creates a new Terraform provider with config.
+// S3 settings are used for states stored in an S3 backend.
+func NewTerraformProvider(cfg *config.TerraformConfig, s3Cfg *config.S3Config) *TerraformProvider {
+	p := &TerraformProvider{
+		BaseProvider: provider.NewBaseProvider("terraform"),
+		client:       NewTerraformClient(cfg, s3Cfg),
+	}
+
+	if !p.client.IsAvailable() {
+		p.SetStatus(false, "Terraform states not configured", nil)
+		return p
+	}
+
+	p.SetAvailable(true)
+	log.Printf("✓ Terraform provider initialized successfully (%d states)", len(p.client.States()))
+
+	return p
+}
+
+// Test tests the Terraform configuration (for ProviderClient interface compatibility)
+func (p *TerraformProvider) Test(config interface{}) error {
+	if !p.IsAvailable() {
+		return fmt.Errorf("terraform provider not available")
+	}
+	return nil
+}
+
+// AddTools adds Terraform tools to the MCP server (for ProviderClient interface compatibility)
+func (p *TerraformProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the Terraform provider
+func (p *TerraformProvider) Close() error {
+	return p.client.Close()
+}
+
+// addToolsToServer adds Terraform tools to the MCP server
+func (p *TerraformProvider) addToolsToServer(server *mcp.Server) {
+	if !p.IsAvailable() {
+		log.Printf("⚠ Terraform provider not available, tools not added")
+		return
+	}
+
+	tools := []struct {
+		tool    *mcp.Tool
+		handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{p.createListStatesTool().Tool, p.createListStatesTool().Handler},
+		{p.createListResourcesTool().Tool, p.createListResourcesTool().Handler},
+		{p.createShowResourceTool().Tool, p.createShowResourceTool().Handler},
+		{p.createDiffStatesTool().Tool, p.createDiffStatesTool().Handler},
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.tool, tool.handler)
+		log.Printf("✓ Registered Terraform tool: %s", tool.tool.Name)
+	}
+
+	log.Printf("✓ All Terraform tools registered successfully")
+}
+
+// createListStatesTool creates the state listing tool
+func (p *TerraformProvider) createListStatesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "terraform_list_states",
+		Description: "List the configured Terraform states and, for S3 backends, the stored versions of a state",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"state": {
+					"type": "string",
+					"description": "State name whose S3 object versions should be listed; omit to list all configured states"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of versions to return",
+					"default": 20
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			State string `json:"state,omitempty"`
+			Limit int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.State == "" {
+			states := []map[string]string{}
+			for _, st := range p.client.States() {
+				entry := map[string]string{"name": st.Name}
+				if st.Path != "" {
+					entry["backend"] = "local"
+					entry["path"] = st.Path
+				} else {
+					entry["backend"] = "s3"
+					entry["location"] = fmt.Sprintf("s3://%s/%s", st.Bucket, st.Key)
+				}
+				states = append(states, entry)
+			}
+			return p.formatJSONResult(map[string]interface{}{
+				"states": states,
+				"count":  len(states),
+			}), nil
+		}
+
+		if args.Limit <= 0 {
+			args.Limit = 20
+		}
+		versions, err := p.client.Versions(args.State, args.Limit)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"state":    args.State,
+			"versions": versions,
+			"count":    len(versions),
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createListResourcesTool creates the resource listing tool
+func (p *TerraformProvider) createListResourcesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "terraform_list_resources",
+		Description: "List the resources recorded in a Terraform state, with root module outputs (sensitive values redacted)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"state": {
+					"type": "string",
+					"description": "Configured state name; optional when only one is configured"
+				},
+				"version": {
+					"type": "string",
+					"description": "S3 object version ID, or another state file path for local states; defaults to the current state"
+				},
+				"type": {
+					"type": "string",
+					"description": "Only list resources of this type (e.g. aws_instance)"
+				},
+				"module": {
+					"type": "string",
+					"description": "Only list resources whose module address starts with this prefix (e.g. module.network)"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			State   string `json:"state,omitempty"`
+			Version string `json:"version,omitempty"`
+			Type    string `json:"type,omitempty"`
+			Module  string `json:"module,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		state, err := p.client.Load(args.State, args.Version)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		resources := state.ListResources(args.Type, args.Module)
+		result := map[string]interface{}{
+			"terraform_version": state.TerraformVersion,
+			"serial":            state.Serial,
+			"lineage":           state.Lineage,
+			"resources":         resources,
+			"count":             len(resources),
+			"outputs":           state.OutputValues(),
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createShowResourceTool creates the resource detail tool
+func (p *TerraformProvider) createShowResourceTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "terraform_show_resource",
+		Description: "Show the attributes and dependencies of a resource in a Terraform state (sensitive values redacted)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"state": {
+					"type": "string",
+					"description": "Configured state name; optional when only one is configured"
+				},
+				"address": {
+					"type": "string",
+					"description": "Resource address (e.g. module.network.aws_vpc.main or aws_instance.web[0])"
+				},
+				"version": {
+					"type": "string",
+					"description": "S3 object version ID, or another state file path for local states; defaults to the current state"
+				}
+			},
+			"required": ["address"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			State   string `json:"state,omitempty"`
+			Address string `json:"address"`
+			Version string `json:"version,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Address == "" {
+			return p.createErrorResult(fmt.Errorf("address parameter is required")), nil
+		}
+
+		state, err := p.client.Load(args.State, args.Version)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		resource, err := state.Resource(args.Address)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		return p.formatJSONResult(resource), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createDiffStatesTool creates the state diff tool
+func (p *TerraformProvider) createDiffStatesTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "terraform_diff_states",
+		Description: "Diff two versions of a Terraform state: added, removed and changed resources and changed outputs",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"state": {
+					"type": "string",
+					"description": "Configured state name; optional when only one is configured"
+				},
+				"from_version": {
+					"type": "string",
+					"description": "Baseline S3 object version ID, or state file path for local states (e.g. terraform.tfstate.backup)"
+				},
+				"to_version": {
+					"type": "string",
+					"description": "Version to compare against; defaults to the current state"
+				}
+			},
+			"required": ["from_version"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			State       string `json:"state,omitempty"`
+			FromVersion string `json:"from_version"`
+			ToVersion   string `json:"to_version,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.FromVersion == "" {
+			return p.createErrorResult(fmt.Errorf("from_version parameter is required")), nil
+		}
+
+		from, err := p.client.Load(args.State, args.FromVersion)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("failed to load from_version: %w", err)), nil
+		}
+		to, err := p.client.Load(args.State, args.ToVersion)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("failed to load to_version: %w", err)), nil
+		}
+
+		return p.formatJSONResult(Diff(from, to)), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// Helper functions
+func (p *TerraformProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Terraform Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *TerraformProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that TerraformProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*TerraformProvider)(nil)
@@ -0,0 +1,348 @@
+package utility
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxCodecInput bounds the input of the encode, hash and JWT tools
+const maxCodecInput = 1024 * 1024
+
+// Encode converts raw bytes into the given encoding
+func Encode(data []byte, encoding string) (string, error) {
+	switch encoding {
+	case "base64":
+		return base64.StdEncoding.EncodeToString(data), nil
+	case "base64url":
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	case "hex":
+		return hex.EncodeToString(data), nil
+	case "url":
+		return url.QueryEscape(string(data)), nil
+	case "url_path":
+		return url.PathEscape(string(data)), nil
+	default:
+		return "", fmt.Errorf("unsupported encoding: %s (use base64, base64url, hex, url or url_path)", encoding)
+	}
+}
+
+// Decode reverses Encode; base64 variants accept input with or without padding
+func Decode(text, encoding string) ([]byte, error) {
+	switch encoding {
+	case "base64", "base64url":
+		return decodeBase64(text)
+	case "hex":
+		cleaned := strings.NewReplacer(" ", "", ":", "", "\n", "").Replace(strings.TrimPrefix(strings.TrimSpace(text), "0x"))
+		data, err := hex.DecodeString(cleaned)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex: %w", err)
+		}
+		return data, nil
+	case "url":
+		decoded, err := url.QueryUnescape(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL encoding: %w", err)
+		}
+		return []byte(decoded), nil
+	case "url_path":
+		decoded, err := url.PathUnescape(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL encoding: %w", err)
+		}
+		return []byte(decoded), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s (use base64, base64url, hex, url or url_path)", encoding)
+	}
+}
+
+// decodeBase64 accepts both alphabets, with or without padding, ignoring whitespace
+func decodeBase64(text string) ([]byte, error) {
+	cleaned := strings.Join(strings.Fields(text), "")
+	cleaned = strings.TrimRight(cleaned, "=")
+	if strings.ContainsAny(cleaned, "-_") {
+		cleaned = strings.NewReplacer("-", "+", "_", "/").Replace(cleaned)
+	}
+	data, err := base64.RawStdEncoding.DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	return data, nil
+}
+
+// describeBytes renders decoded bytes as text when they are valid UTF-8, otherwise as hex
+func describeBytes(data []byte) map[string]interface{} {
+	if utf8.Valid(data) {
+		return map[string]interface{}{"output": string(data), "bytes": len(data)}
+	}
+	return map[string]interface{}{
+		"output": hex.EncodeToString(data),
+		"bytes":  len(data),
+		"binary": true,
+		"note":   "decoded data is not UTF-8 text; shown as hex",
+	}
+}
+
+// newHash returns a constructor for a supported hash algorithm
+func newHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToLower(strings.ReplaceAll(algorithm, "-", "")) {
+	case "md5":
+		return md5.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "sha256":
+		return sha256.New, nil
+	case "sha384":
+		return sha512.New384, nil
+	case "sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %s (use md5, sha1, sha256, sha384 or sha512)", algorithm)
+	}
+}
+
+// Digest hashes data, or computes an HMAC when key is non-empty
+func Digest(data, key []byte, algorithm string) ([]byte, error) {
+	constructor, err := newHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(constructor, key)
+	} else {
+		h = constructor()
+	}
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// DecodedJWT is a JWT split into its parts
+type DecodedJWT struct {
+	Header    map[string]interface{} `json:"header"`
+	Payload   interface{}            `json:"payload"`
+	Claims    map[string]interface{} `json:"time_claims,omitempty"`
+	Signature string                 `json:"signature"`
+	signed    []byte
+	sig       []byte
+}
+
+// DecodeJWT parses a compact JWS without verifying it
+func DecodeJWT(token string) (*DecodedJWT, error) {
+	token = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(token), "Bearer "))
+	parts := strings.Split(token, ".")
+	if len(parts) == 5 {
+		return nil, fmt.Errorf("token is an encrypted JWE; only signed JWTs can be decoded")
+	}
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token must have 3 dot-separated parts, got %d", len(parts))
+	}
+
+	headerJSON, err := decodeBase64(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	decoded := &DecodedJWT{Signature: parts[2], signed: []byte(parts[0] + "." + parts[1])}
+	if err := json.Unmarshal(headerJSON, &decoded.Header); err != nil {
+		return nil, fmt.Errorf("header is not JSON: %w", err)
+	}
+
+	payloadJSON, err := decodeBase64(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	var claims map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payloadJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err == nil {
+		decoded.Payload = claims
+		decoded.Claims = timeClaims(claims, time.Now())
+	} else {
+		// Payloads need not be JSON claims sets
+		decoded.Payload = string(payloadJSON)
+	}
+
+	if decoded.sig, err = decodeBase64(parts[2]); err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	return decoded, nil
+}
+
+// timeClaims renders exp/nbf/iat/auth_time as timestamps and reports whether the token is currently valid
+func timeClaims(claims map[string]interface{}, now time.Time) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, name := range []string{"iat", "nbf", "exp", "auth_time"} {
+		number, ok := claims[name].(json.Number)
+		if !ok {
+			continue
+		}
+		seconds, err := number.Float64()
+		if err != nil {
+			continue
+		}
+		at := time.Unix(int64(seconds), 0).UTC()
+		result[name] = map[string]interface{}{
+			"time":     at.Format(time.RFC3339),
+			"relative": RelativeTo(at, now),
+		}
+		switch name {
+		case "exp":
+			result["expired"] = !now.Before(at)
+		case "nbf":
+			result["not_yet_valid"] = now.Before(at)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// VerifyJWT checks the signature with an HMAC secret or a PEM public key/certificate
+func VerifyJWT(decoded *DecodedJWT, key []byte) error {
+	alg, _ := decoded.Header["alg"].(string)
+	if alg == "" || strings.EqualFold(alg, "none") {
+		return fmt.Errorf("token is unsigned (alg %q)", alg)
+	}
+	if len(alg) < 5 {
+		return fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+
+	switch alg[:2] {
+	case "HS":
+		hashAlg, err := jwtHash(alg)
+		if err != nil {
+			return err
+		}
+		mac := hmac.New(hashAlg.New, key)
+		mac.Write(decoded.signed)
+		if !hmac.Equal(mac.Sum(nil), decoded.sig) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	case "RS", "PS", "ES", "Ed":
+		publicKey, err := parsePublicKey(key)
+		if err != nil {
+			return err
+		}
+		return verifyAsymmetric(alg, publicKey, decoded.signed, decoded.sig)
+	default:
+		return fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+}
+
+func jwtHash(alg string) (crypto.Hash, error) {
+	switch alg[len(alg)-3:] {
+	case "256":
+		return crypto.SHA256, nil
+	case "384":
+		return crypto.SHA384, nil
+	case "512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+}
+
+func verifyAsymmetric(alg string, publicKey interface{}, signed, sig []byte) error {
+	if alg == "EdDSA" {
+		edKey, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("EdDSA needs an Ed25519 public key, got %T", publicKey)
+		}
+		if !ed25519.Verify(edKey, signed, sig) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	}
+
+	hashAlg, err := jwtHash(alg)
+	if err != nil {
+		return err
+	}
+	h := hashAlg.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%s needs an RSA public key, got %T", alg, publicKey)
+		}
+		if alg[:2] == "RS" {
+			err = rsa.VerifyPKCS1v15(rsaKey, hashAlg, digest, sig)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, hashAlg, digest, sig, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	case "ES":
+		ecKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%s needs an ECDSA public key, got %T", alg, publicKey)
+		}
+		// JWS ECDSA signatures are the raw r||s concatenation, not ASN.1
+		if len(sig)%2 != 0 {
+			return fmt.Errorf("malformed ECDSA signature")
+		}
+		r := new(big.Int).SetBytes(sig[:len(sig)/2])
+		s := new(big.Int).SetBytes(sig[len(sig)/2:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm: %s", alg)
+}
+
+// parsePublicKey reads a PEM public key (PKIX or PKCS#1) or certificate
+func parsePublicKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key must be a PEM public key or certificate for asymmetric algorithms")
+	}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA public key: %w", err)
+		}
+		return key, nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q; provide a public key, not a private key", block.Type)
+	}
+}
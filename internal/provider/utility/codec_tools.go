@@ -0,0 +1,296 @@
+package utility
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// createEncodeTool creates the encode/decode tool
+func (p *UtilityProvider) createEncodeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "encode_decode",
+		Description: "Encode or decode text as base64, base64url, hex or URL (query or path) encoding. Decoded binary data is shown as hex",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"input": {
+					"type": "string",
+					"description": "Text to encode, or encoded text to decode (max 1MB)"
+				},
+				"operation": {
+					"type": "string",
+					"description": "Whether to encode or decode",
+					"enum": ["encode", "decode"]
+				},
+				"encoding": {
+					"type": "string",
+					"description": "Encoding to use; url escapes for query strings (space as +), url_path for path segments (space as %20)",
+					"enum": ["base64", "base64url", "hex", "url", "url_path"]
+				},
+				"input_encoding": {
+					"type": "string",
+					"description": "For encode: how the input is given; use hex or base64 to encode binary data",
+					"enum": ["text", "hex", "base64"],
+					"default": "text"
+				}
+			},
+			"required": ["input", "operation", "encoding"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Input         string `json:"input"`
+			Operation     string `json:"operation"`
+			Encoding      string `json:"encoding"`
+			InputEncoding string `json:"input_encoding,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if len(args.Input) > maxCodecInput {
+			return p.createErrorResult(fmt.Errorf("input is too large (%d bytes, max %d)", len(args.Input), maxCodecInput)), nil
+		}
+
+		switch args.Operation {
+		case "encode":
+			data, err := codecInput(args.Input, args.InputEncoding)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			output, err := Encode(data, args.Encoding)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			return p.formatJSONResult(map[string]interface{}{
+				"operation": "encode",
+				"encoding":  args.Encoding,
+				"output":    output,
+			}), nil
+		case "decode":
+			data, err := Decode(args.Input, args.Encoding)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			result := describeBytes(data)
+			result["operation"] = "decode"
+			result["encoding"] = args.Encoding
+			return p.formatJSONResult(result), nil
+		case "":
+			return p.createErrorResult(fmt.Errorf("operation parameter is required")), nil
+		default:
+			return p.createErrorResult(fmt.Errorf("unsupported operation: %s (use encode or decode)", args.Operation)), nil
+		}
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createHashTool creates the hashing tool
+func (p *UtilityProvider) createHashTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "hash",
+		Description: "Compute MD5/SHA-1/SHA-256/SHA-384/SHA-512 digests of text, or HMACs when a key is given (e.g. to check webhook signatures). Returns hex and base64",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"input": {
+					"type": "string",
+					"description": "Data to hash (max 1MB)"
+				},
+				"input_encoding": {
+					"type": "string",
+					"description": "How the input is given; use hex or base64 to hash binary data",
+					"enum": ["text", "hex", "base64"],
+					"default": "text"
+				},
+				"algorithms": {
+					"type": "array",
+					"items": {"type": "string", "enum": ["md5", "sha1", "sha256", "sha384", "sha512"]},
+					"description": "Algorithms to compute",
+					"default": ["md5", "sha1", "sha256"]
+				},
+				"hmac_key": {
+					"type": "string",
+					"description": "Compute an HMAC with this key instead of a plain digest"
+				},
+				"expected": {
+					"type": "string",
+					"description": "Digest to compare against (hex or base64, e.g. from a signature header); reports which algorithm matches"
+				}
+			},
+			"required": ["input"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Input         string   `json:"input"`
+			InputEncoding string   `json:"input_encoding,omitempty"`
+			Algorithms    []string `json:"algorithms,omitempty"`
+			HMACKey       string   `json:"hmac_key,omitempty"`
+			Expected      string   `json:"expected,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if len(args.Input) > maxCodecInput {
+			return p.createErrorResult(fmt.Errorf("input is too large (%d bytes, max %d)", len(args.Input), maxCodecInput)), nil
+		}
+		if len(args.Algorithms) == 0 {
+			args.Algorithms = []string{"md5", "sha1", "sha256"}
+		}
+
+		data, err := codecInput(args.Input, args.InputEncoding)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		digests := map[string]interface{}{}
+		var matched []string
+		for _, algorithm := range args.Algorithms {
+			sum, err := Digest(data, []byte(args.HMACKey), algorithm)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			hexSum := hex.EncodeToString(sum)
+			base64Sum := base64.StdEncoding.EncodeToString(sum)
+			digests[algorithm] = map[string]string{"hex": hexSum, "base64": base64Sum}
+			if args.Expected != "" && digestMatches(args.Expected, sum) {
+				matched = append(matched, algorithm)
+			}
+		}
+
+		result := map[string]interface{}{
+			"bytes":   len(data),
+			"hmac":    args.HMACKey != "",
+			"digests": digests,
+		}
+		if args.Expected != "" {
+			result["matches"] = len(matched) > 0
+			result["matched_algorithms"] = matched
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createJWTDecodeTool creates the JWT inspection tool
+func (p *UtilityProvider) createJWTDecodeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "jwt_decode",
+		Description: "Decode a JWT's header and payload (exp/nbf/iat shown as timestamps with expiry status) without verification, and optionally verify its signature with an HMAC secret or a PEM public key/certificate",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"token": {
+					"type": "string",
+					"description": "The JWT (a leading \"Bearer \" is ignored)"
+				},
+				"key": {
+					"type": "string",
+					"description": "Verify the signature: the shared secret for HS256/384/512, or a PEM public key or certificate for RS*, PS*, ES* and EdDSA"
+				},
+				"key_encoding": {
+					"type": "string",
+					"description": "Encoding of an HMAC secret",
+					"enum": ["text", "base64", "hex"],
+					"default": "text"
+				}
+			},
+			"required": ["token"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Token       string `json:"token"`
+			Key         string `json:"key,omitempty"`
+			KeyEncoding string `json:"key_encoding,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Token == "" {
+			return p.createErrorResult(fmt.Errorf("token parameter is required")), nil
+		}
+		if len(args.Token) > maxCodecInput {
+			return p.createErrorResult(fmt.Errorf("token is too large (%d bytes, max %d)", len(args.Token), maxCodecInput)), nil
+		}
+
+		decoded, err := DecodeJWT(args.Token)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"header":  decoded.Header,
+			"payload": decoded.Payload,
+		}
+		if decoded.Claims != nil {
+			result["time_claims"] = decoded.Claims
+		}
+
+		verification := map[string]interface{}{"verified": false}
+		if args.Key == "" {
+			verification["note"] = "signature not checked; pass key to verify"
+		} else {
+			key, err := codecInput(args.Key, args.KeyEncoding)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid key: %w", err)), nil
+			}
+			if err := VerifyJWT(decoded, key); err != nil {
+				verification["error"] = err.Error()
+			} else {
+				verification["verified"] = true
+			}
+		}
+		result["signature"] = verification
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// codecInput converts tool input given as text, hex or base64 into bytes
+func codecInput(input, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", "text":
+		return []byte(input), nil
+	case "hex", "base64":
+		return Decode(input, encoding)
+	default:
+		return nil, fmt.Errorf("unsupported input_encoding: %s (use text, hex or base64)", encoding)
+	}
+}
+
+// digestMatches compares a digest against an expected value given in hex or base64,
+// tolerating prefixes such as "sha256=" used by webhook signature headers
+func digestMatches(expected string, sum []byte) bool {
+	if prefix, rest, ok := strings.Cut(expected, "="); ok && rest != "" {
+		if _, err := newHash(prefix); err == nil || prefix == "v0" || prefix == "v1" {
+			expected = rest
+		}
+	}
+	if decoded, err := hex.DecodeString(expected); err == nil && hmac.Equal(decoded, sum) {
+		return true
+	}
+	decoded, err := decodeBase64(expected)
+	return err == nil && hmac.Equal(decoded, sum)
+}
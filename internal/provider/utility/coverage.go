@@ -0,0 +1,498 @@
+package utility
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoverageBlock is a span of code with its execution count. Go profiles have statement blocks;
+// lcov and Cobertura reports have one block per line with Statements set to 1.
+type CoverageBlock struct {
+	StartLine  int
+	EndLine    int
+	Statements int
+	Count      int
+}
+
+// FunctionCoverage is the coverage of one function
+type FunctionCoverage struct {
+	Name      string  `json:"name"`
+	File      string  `json:"file,omitempty"`
+	StartLine int     `json:"start_line"`
+	EndLine   int     `json:"end_line,omitempty"`
+	Covered   int     `json:"covered"`
+	Total     int     `json:"total"`
+	Percent   float64 `json:"percent"`
+	Calls     *int    `json:"calls,omitempty"` // lcov FNDA hit count
+}
+
+// FileCoverage is the coverage of one source file
+type FileCoverage struct {
+	Path      string             `json:"path"`
+	Source    string             `json:"source,omitempty"` // Local file the report path was mapped to
+	Covered   int                `json:"covered"`
+	Total     int                `json:"total"`
+	Percent   float64            `json:"percent"`
+	Uncovered []string           `json:"uncovered_lines,omitempty"`
+	Functions []FunctionCoverage `json:"functions,omitempty"`
+	Blocks    []CoverageBlock    `json:"-"`
+}
+
+// CoverageReport is a parsed coverage file
+type CoverageReport struct {
+	Format string          `json:"format"`
+	Unit   string          `json:"unit"` // statements (Go) or lines
+	Files  []*FileCoverage `json:"files"`
+}
+
+// DetectCoverageFormat guesses the format of a coverage file from its content
+func DetectCoverageFormat(content string) string {
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "mode:"):
+		return "go"
+	case strings.HasPrefix(trimmed, "<?xml") || strings.HasPrefix(trimmed, "<coverage") || strings.HasPrefix(trimmed, "<!DOCTYPE coverage"):
+		return "cobertura"
+	case strings.Contains(content, "\nSF:") || strings.HasPrefix(trimmed, "SF:") || strings.HasPrefix(trimmed, "TN:"):
+		return "lcov"
+	}
+	return ""
+}
+
+// ParseCoverage parses a Go coverprofile, lcov tracefile or Cobertura XML report
+func ParseCoverage(content, format string) (*CoverageReport, error) {
+	if format == "" || format == "auto" {
+		format = DetectCoverageFormat(content)
+	}
+	var report *CoverageReport
+	var err error
+	switch format {
+	case "go":
+		report, err = parseGoCoverProfile(content)
+	case "lcov":
+		report, err = parseLcov(content)
+	case "cobertura":
+		report, err = parseCobertura(content)
+	case "":
+		return nil, fmt.Errorf("unrecognized coverage format (expected a Go coverprofile, lcov or Cobertura XML)")
+	default:
+		return nil, fmt.Errorf("unsupported coverage format %q (use go, lcov or cobertura)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Files) == 0 {
+		return nil, fmt.Errorf("no files found in %s coverage report", format)
+	}
+	for _, file := range report.Files {
+		file.summarize()
+	}
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Path < report.Files[j].Path })
+	return report, nil
+}
+
+// parseGoCoverProfile reads "file:startLine.startCol,endLine.endCol numStmts count" lines. Blocks repeated
+// across test binaries (e.g. with -coverpkg) are merged.
+func parseGoCoverProfile(content string) (*CoverageReport, error) {
+	report := &CoverageReport{Format: "go", Unit: "statements"}
+	files := map[string]*FileCoverage{}
+	blocks := map[string]map[[2]int]int{} // file -> (start, end) -> index in Blocks
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: invalid coverprofile line %q", lineNo, line)
+		}
+		name := line[:colon]
+		var startLine, startCol, endLine, endCol, stmts, count int
+		if _, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d %d %d", &startLine, &startCol, &endLine, &endCol, &stmts, &count); err != nil {
+			return nil, fmt.Errorf("line %d: invalid coverprofile line %q", lineNo, line)
+		}
+
+		file := files[name]
+		if file == nil {
+			file = &FileCoverage{Path: name}
+			files[name] = file
+			blocks[name] = map[[2]int]int{}
+			report.Files = append(report.Files, file)
+		}
+		key := [2]int{startLine*100000 + startCol, endLine*100000 + endCol}
+		if i, ok := blocks[name][key]; ok {
+			file.Blocks[i].Count += count
+			continue
+		}
+		blocks[name][key] = len(file.Blocks)
+		file.Blocks = append(file.Blocks, CoverageBlock{StartLine: startLine, EndLine: endLine, Statements: stmts, Count: count})
+	}
+	return report, scanner.Err()
+}
+
+// parseLcov reads SF/FN/FNDA/DA records of an lcov tracefile
+func parseLcov(content string) (*CoverageReport, error) {
+	report := &CoverageReport{Format: "lcov", Unit: "lines"}
+	files := map[string]*FileCoverage{}
+	var file *FileCoverage
+	var lines map[int]int
+	var functions map[string]*FunctionCoverage
+
+	finish := func() {
+		if file == nil {
+			return
+		}
+		for line, hits := range lines {
+			file.Blocks = append(file.Blocks, CoverageBlock{StartLine: line, EndLine: line, Statements: 1, Count: hits})
+		}
+		for _, fn := range functions {
+			file.Functions = append(file.Functions, *fn)
+		}
+		file, lines, functions = nil, nil, nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, _ := strings.Cut(line, ":")
+		switch key {
+		case "SF":
+			finish()
+			// The same file may appear in several records (one per test); they are merged
+			file = files[value]
+			if file == nil {
+				file = &FileCoverage{Path: value}
+				files[value] = file
+				report.Files = append(report.Files, file)
+			}
+			lines = map[int]int{}
+			for _, b := range file.Blocks {
+				lines[b.StartLine] = b.Count
+			}
+			file.Blocks = nil
+			functions = map[string]*FunctionCoverage{}
+			for i := range file.Functions {
+				functions[file.Functions[i].Name] = &file.Functions[i]
+			}
+			file.Functions = nil
+		case "FN":
+			// FN:<start>,<name> or, since lcov 2.0, FN:<start>,<end>,<name>
+			if functions == nil {
+				continue
+			}
+			parts := strings.Split(value, ",")
+			if len(parts) < 2 {
+				continue
+			}
+			fn := &FunctionCoverage{Name: parts[len(parts)-1], StartLine: atoiOrZero(parts[0])}
+			if len(parts) == 3 {
+				fn.EndLine = atoiOrZero(parts[1])
+			}
+			if existing := functions[fn.Name]; existing != nil {
+				fn.Calls = existing.Calls
+			}
+			functions[fn.Name] = fn
+		case "FNDA":
+			if functions == nil {
+				continue
+			}
+			hits, name, ok := strings.Cut(value, ",")
+			if !ok {
+				continue
+			}
+			fn := functions[name]
+			if fn == nil {
+				fn = &FunctionCoverage{Name: name}
+				functions[name] = fn
+			}
+			calls := atoiOrZero(hits)
+			if fn.Calls != nil {
+				calls += *fn.Calls
+			}
+			fn.Calls = &calls
+		case "DA":
+			if lines == nil {
+				continue
+			}
+			parts := strings.Split(value, ",")
+			if len(parts) < 2 {
+				continue
+			}
+			lines[atoiOrZero(parts[0])] += atoiOrZero(parts[1])
+		case "end_of_record":
+			finish()
+		}
+	}
+	finish()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// lcov 1.x gives only start lines; a function ends before the next one starts
+	for _, file := range report.Files {
+		sort.Slice(file.Functions, func(i, j int) bool { return file.Functions[i].StartLine < file.Functions[j].StartLine })
+		last := 0
+		for _, b := range file.Blocks {
+			last = max(last, b.EndLine)
+		}
+		for i := range file.Functions {
+			fn := &file.Functions[i]
+			if fn.EndLine > 0 {
+				continue
+			}
+			fn.EndLine = last
+			if i+1 < len(file.Functions) && file.Functions[i+1].StartLine > fn.StartLine {
+				fn.EndLine = file.Functions[i+1].StartLine - 1
+			}
+		}
+	}
+	return report, nil
+}
+
+type coberturaLine struct {
+	Number int    `xml:"number,attr"`
+	Hits   string `xml:"hits,attr"`
+}
+
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Methods  []struct {
+				Name  string          `xml:"name,attr"`
+				Lines []coberturaLine `xml:"lines>line"`
+			} `xml:"methods>method"`
+			Lines []coberturaLine `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCobertura reads class line hits and method line ranges of a Cobertura XML report
+func parseCobertura(content string) (*CoverageReport, error) {
+	var doc coberturaReport
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	// Reports usually declare a DTD; entities are not expanded
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Cobertura XML: %w", err)
+	}
+
+	report := &CoverageReport{Format: "cobertura", Unit: "lines"}
+	files := map[string]*FileCoverage{}
+	lines := map[string]map[int]int{}
+	for _, pkg := range doc.Packages {
+		for _, class := range pkg.Classes {
+			name := class.Filename
+			// Filenames are relative to one of the sources; a single source can be applied unambiguously
+			if len(doc.Sources) == 1 && !path.IsAbs(name) && strings.TrimSpace(doc.Sources[0]) != "" {
+				name = path.Join(strings.TrimSpace(doc.Sources[0]), name)
+			}
+			file := files[name]
+			if file == nil {
+				file = &FileCoverage{Path: name}
+				files[name] = file
+				lines[name] = map[int]int{}
+				report.Files = append(report.Files, file)
+			}
+			for _, line := range class.Lines {
+				lines[name][line.Number] += coberturaHits(line.Hits)
+			}
+			for _, method := range class.Methods {
+				fn := FunctionCoverage{Name: method.Name, StartLine: math.MaxInt}
+				for _, line := range method.Lines {
+					fn.StartLine = min(fn.StartLine, line.Number)
+					fn.EndLine = max(fn.EndLine, line.Number)
+					if _, ok := lines[name][line.Number]; !ok {
+						lines[name][line.Number] = coberturaHits(line.Hits)
+					}
+				}
+				if fn.StartLine == math.MaxInt {
+					continue
+				}
+				file.Functions = append(file.Functions, fn)
+			}
+		}
+	}
+	for name, file := range files {
+		for line, hits := range lines[name] {
+			file.Blocks = append(file.Blocks, CoverageBlock{StartLine: line, EndLine: line, Statements: 1, Count: hits})
+		}
+	}
+	return report, nil
+}
+
+// coberturaHits parses hit counts, which some tools write as floats or with a thousands separator
+func coberturaHits(hits string) int {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(hits, ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return int(f)
+}
+
+// summarize computes file and function totals and the uncovered line ranges
+func (f *FileCoverage) summarize() {
+	sort.Slice(f.Blocks, func(i, j int) bool { return f.Blocks[i].StartLine < f.Blocks[j].StartLine })
+	f.Covered, f.Total = countBlocks(f.Blocks, 0, math.MaxInt)
+	f.Percent = percent(f.Covered, f.Total)
+	f.Uncovered = formatLineRanges(uncoveredLines(f.Blocks, 0, math.MaxInt))
+	for i := range f.Functions {
+		fn := &f.Functions[i]
+		fn.Covered, fn.Total = countBlocks(f.Blocks, fn.StartLine, fn.EndLine)
+		fn.Percent = percent(fn.Covered, fn.Total)
+	}
+}
+
+// AddGoFunctions finds the functions of a Go source file and computes their coverage,
+// like go tool cover -func
+func (f *FileCoverage) AddGoFunctions(source []byte) error {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, f.Path, source, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+	f.Functions = nil
+	for _, decl := range parsed.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		fn := FunctionCoverage{
+			Name:      goFuncName(fd),
+			StartLine: fset.Position(fd.Pos()).Line,
+			EndLine:   fset.Position(fd.End()).Line,
+		}
+		fn.Covered, fn.Total = countBlocks(f.Blocks, fn.StartLine, fn.EndLine)
+		fn.Percent = percent(fn.Covered, fn.Total)
+		f.Functions = append(f.Functions, fn)
+	}
+	return nil
+}
+
+// goFuncName names methods as (*T).Name or T.Name
+func goFuncName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	switch t := withoutTypeParams(fd.Recv.List[0].Type).(type) {
+	case *ast.StarExpr:
+		if ident, ok := withoutTypeParams(t.X).(*ast.Ident); ok {
+			return "(*" + ident.Name + ")." + fd.Name.Name
+		}
+	case *ast.Ident:
+		return t.Name + "." + fd.Name.Name
+	}
+	return fd.Name.Name
+}
+
+// withoutTypeParams strips the type arguments of a generic receiver such as List[T]
+func withoutTypeParams(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		return t.X
+	case *ast.IndexListExpr:
+		return t.X
+	}
+	return expr
+}
+
+// MatchesFunction reports whether a function name matches a query such as Start, Server.Start or (*Server).Start
+func MatchesFunction(name, query string) bool {
+	if name == query {
+		return true
+	}
+	plain := strings.NewReplacer("(", "", ")", "", "*", "").Replace(name)
+	query = strings.NewReplacer("(", "", ")", "", "*", "").Replace(query)
+	return plain == query || strings.HasSuffix(plain, "."+query) || strings.HasSuffix(plain, "::"+query)
+}
+
+// LineStatus returns, per line from start to end, whether code on it ran: 1 covered, 0 not covered, -1 no code
+func LineStatus(blocks []CoverageBlock, start, end int) map[int]int {
+	status := map[int]int{}
+	for _, b := range blocks {
+		if b.EndLine < start || b.StartLine > end {
+			continue
+		}
+		for line := max(b.StartLine, start); line <= min(b.EndLine, end); line++ {
+			if b.Count > 0 {
+				status[line] = 1
+			} else if _, ok := status[line]; !ok {
+				status[line] = 0
+			}
+		}
+	}
+	return status
+}
+
+// countBlocks sums covered and total statements of the blocks inside [start, end]
+func countBlocks(blocks []CoverageBlock, start, end int) (covered, total int) {
+	for _, b := range blocks {
+		if b.StartLine < start || b.EndLine > end {
+			continue
+		}
+		total += b.Statements
+		if b.Count > 0 {
+			covered += b.Statements
+		}
+	}
+	return covered, total
+}
+
+// uncoveredLines lists the lines in [start, end] with code that never ran and no code that did
+func uncoveredLines(blocks []CoverageBlock, start, end int) []int {
+	status := LineStatus(blocks, start, end)
+	var lines []int
+	for line, s := range status {
+		if s == 0 {
+			lines = append(lines, line)
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// formatLineRanges collapses sorted line numbers into ranges such as 12-15
+func formatLineRanges(lines []int) []string {
+	var ranges []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return ranges
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(covered)/float64(total)*1000) / 10
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(s))
+	return n
+}
@@ -0,0 +1,304 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+const (
+	maxCoverageInput = 32 << 20
+	// maxCoverageSources bounds the Go source files parsed for per-function coverage
+	maxCoverageSources  = 2000
+	maxFunctionMatches  = 5
+	maxAnnotatedLines   = 300
+	defaultCoverageRows = 30
+)
+
+// createCoverageReportTool creates the coverage report parsing tool
+func (p *UtilityProvider) createCoverageReportTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "coverage_report",
+		Description: "Parse a test coverage report (Go coverprofile, lcov tracefile or Cobertura XML) into per-file and per-function coverage percentages with uncovered line ranges, least-covered files first, and the functions with the most uncovered code. With function, return that function's coverage and its source annotated line by line as covered (+), not covered (-) or no code, to show which paths still need tests",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"path": {
+					"type": "string",
+					"description": "Coverage file to read, e.g. coverage.out, lcov.info or coverage.xml"
+				},
+				"content": {
+					"type": "string",
+					"description": "Coverage report text instead of path"
+				},
+				"format": {
+					"type": "string",
+					"enum": ["auto", "go", "lcov", "cobertura"],
+					"description": "Report format; detected from the content when omitted",
+					"default": "auto"
+				},
+				"source_root": {
+					"type": "string",
+					"description": "Directory holding the sources, for mapping report paths (e.g. Go import paths) to local files, Go per-function coverage and annotated source",
+					"default": "."
+				},
+				"file": {
+					"type": "string",
+					"description": "Only report files whose path contains this text"
+				},
+				"function": {
+					"type": "string",
+					"description": "Function to focus on, e.g. Parse, Server.Start or (*Server).Start"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum files and functions to list",
+					"default": 30
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Path       string `json:"path,omitempty"`
+			Content    string `json:"content,omitempty"`
+			Format     string `json:"format,omitempty"`
+			SourceRoot string `json:"source_root,omitempty"`
+			File       string `json:"file,omitempty"`
+			Function   string `json:"function,omitempty"`
+			Limit      int    `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		text := args.Content
+		if text == "" && args.Path != "" {
+			if p.files == nil {
+				return p.createErrorResult(fmt.Errorf("reading files is not available")), nil
+			}
+			content, err := p.files.ReadFile(args.Path)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			text = string(content)
+		}
+		if strings.TrimSpace(text) == "" {
+			return p.createErrorResult(fmt.Errorf("path or content parameter is required")), nil
+		}
+		if len(text) > maxCoverageInput {
+			return p.createErrorResult(fmt.Errorf("coverage report is too large (%d bytes, max %d)", len(text), maxCoverageInput)), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = defaultCoverageRows
+		}
+
+		report, err := ParseCoverage(text, args.Format)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		var notes []string
+		if note := p.mapCoverageSources(report, args.SourceRoot); note != "" {
+			notes = append(notes, note)
+		}
+
+		var files []*FileCoverage
+		for _, file := range report.Files {
+			if args.File == "" || strings.Contains(file.Path, args.File) || strings.Contains(file.Source, args.File) {
+				files = append(files, file)
+			}
+		}
+		if len(files) == 0 {
+			return p.createErrorResult(fmt.Errorf("no files in the report match %q", args.File)), nil
+		}
+
+		covered, total := 0, 0
+		for _, file := range files {
+			covered += file.Covered
+			total += file.Total
+		}
+		result := map[string]interface{}{
+			"format": report.Format,
+			"total": map[string]interface{}{
+				"files":   len(files),
+				"covered": covered,
+				"total":   total,
+				"percent": percent(covered, total),
+				"unit":    report.Unit,
+			},
+		}
+
+		if args.Function != "" {
+			matches := p.coverageFunctionDetails(files, args.Function)
+			if len(matches) == 0 {
+				return p.createErrorResult(fmt.Errorf("function %q not found in the covered files%s", args.Function, functionHint(report))), nil
+			}
+			result["functions"] = matches
+		} else {
+			result["files"] = leastCoveredFiles(files, args.Limit)
+			if gaps := largestFunctionGaps(files, args.Limit); len(gaps) > 0 {
+				result["least_covered_functions"] = gaps
+			}
+		}
+
+		if len(notes) > 0 {
+			result["notes"] = notes
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// mapCoverageSources links report paths to local files and, for Go profiles, computes per-function coverage
+// from the sources; it returns a note when that was not possible
+func (p *UtilityProvider) mapCoverageSources(report *CoverageReport, root string) string {
+	if p.files == nil {
+		return "source mapping skipped: reading files is not available"
+	}
+	if root == "" {
+		root = "."
+	}
+	local, truncated, err := p.files.ListFiles(root, maxSourceIndexFiles)
+	if err != nil {
+		return fmt.Sprintf("source mapping skipped: %v", err)
+	}
+	index := NewSourceIndex(local)
+
+	parsed, unmapped := 0, 0
+	for _, file := range report.Files {
+		candidates, _ := index.Match(file.Path)
+		if len(candidates) == 0 {
+			unmapped++
+			continue
+		}
+		file.Source = candidates[0]
+		if report.Format != "go" || parsed >= maxCoverageSources {
+			continue
+		}
+		content, err := p.files.ReadFile(file.Source)
+		if err != nil {
+			continue
+		}
+		if err := file.AddGoFunctions(content); err == nil {
+			parsed++
+		}
+	}
+
+	switch {
+	case truncated:
+		return fmt.Sprintf("source index stopped at %d files; narrow source_root for complete mapping", maxSourceIndexFiles)
+	case unmapped == len(report.Files):
+		return fmt.Sprintf("no report paths were found under %s; set source_root to the project directory", root)
+	case report.Format == "go" && unmapped > 0:
+		return fmt.Sprintf("%d files were not found under %s and have no per-function coverage", unmapped, root)
+	}
+	return ""
+}
+
+// coverageFunctionDetails returns the functions matching query with their uncovered lines and annotated source
+func (p *UtilityProvider) coverageFunctionDetails(files []*FileCoverage, query string) []map[string]interface{} {
+	var matches []map[string]interface{}
+	for _, file := range files {
+		for _, fn := range file.Functions {
+			if !MatchesFunction(fn.Name, query) {
+				continue
+			}
+			fn.File = file.Path
+			detail := map[string]interface{}{
+				"function":        fn,
+				"uncovered_lines": formatLineRanges(uncoveredLines(file.Blocks, fn.StartLine, fn.EndLine)),
+			}
+			if file.Source != "" {
+				detail["source"] = fmt.Sprintf("%s:%d", file.Source, fn.StartLine)
+				if lines := p.annotateCoverage(file, fn); len(lines) > 0 {
+					detail["lines"] = lines
+				}
+			}
+			matches = append(matches, detail)
+			if len(matches) == maxFunctionMatches {
+				return matches
+			}
+		}
+	}
+	return matches
+}
+
+// annotateCoverage returns the function's source lines prefixed with + (ran), - (never ran) or blank (no code)
+func (p *UtilityProvider) annotateCoverage(file *FileCoverage, fn FunctionCoverage) []string {
+	content, err := p.files.ReadFile(file.Source)
+	if err != nil || fn.EndLine < fn.StartLine {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	end := min(fn.EndLine, len(lines), fn.StartLine+maxAnnotatedLines-1)
+	status := LineStatus(file.Blocks, fn.StartLine, end)
+
+	var annotated []string
+	for n := fn.StartLine; n <= end; n++ {
+		marker := " "
+		if s, ok := status[n]; ok {
+			marker = "-"
+			if s == 1 {
+				marker = "+"
+			}
+		}
+		annotated = append(annotated, fmt.Sprintf("%s %d: %s", marker, n, lines[n-1]))
+	}
+	if end < fn.EndLine {
+		annotated = append(annotated, fmt.Sprintf("... %d more lines", fn.EndLine-end))
+	}
+	return annotated
+}
+
+// leastCoveredFiles lists files from lowest to highest coverage without their functions
+func leastCoveredFiles(files []*FileCoverage, limit int) []FileCoverage {
+	sorted := append([]*FileCoverage(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Percent != sorted[j].Percent {
+			return sorted[i].Percent < sorted[j].Percent
+		}
+		return sorted[i].Total-sorted[i].Covered > sorted[j].Total-sorted[j].Covered
+	})
+	var rows []FileCoverage
+	for _, file := range sorted[:min(limit, len(sorted))] {
+		row := *file
+		row.Functions = nil
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// largestFunctionGaps lists partly or wholly uncovered functions, most uncovered code first
+func largestFunctionGaps(files []*FileCoverage, limit int) []FunctionCoverage {
+	var gaps []FunctionCoverage
+	for _, file := range files {
+		for _, fn := range file.Functions {
+			if fn.Total > 0 && fn.Covered < fn.Total {
+				fn.File = file.Path
+				gaps = append(gaps, fn)
+			}
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return gaps[i].Total-gaps[i].Covered > gaps[j].Total-gaps[j].Covered
+	})
+	return gaps[:min(limit, len(gaps))]
+}
+
+// functionHint explains why Go functions may be missing
+func functionHint(report *CoverageReport) string {
+	if report.Format == "go" {
+		return "; Go functions come from the sources, so check source_root"
+	}
+	return ""
+}
@@ -0,0 +1,433 @@
+package utility
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCronRuns = 5
+	maxCronRuns     = 100
+	// cronSearchYears bounds the search for the next run of schedules that never fire (e.g. Feb 30)
+	cronSearchYears = 5
+)
+
+// cronDescriptors are the @ shortcuts accepted by Kubernetes CronJobs (robfig/cron)
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	dayNames   = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+)
+
+// cronBounds describes one field of a cron expression
+type cronBounds struct {
+	name     string
+	min, max int
+	// names maps three-letter aliases (JAN, MON) to values
+	names map[string]int
+}
+
+var (
+	secondBounds = cronBounds{name: "second", min: 0, max: 59}
+	minuteBounds = cronBounds{name: "minute", min: 0, max: 59}
+	hourBounds   = cronBounds{name: "hour", min: 0, max: 23}
+	domBounds    = cronBounds{name: "day-of-month", min: 1, max: 31}
+	monthBounds  = cronBounds{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	// Day-of-week accepts 7 as Sunday like most cron implementations
+	dowBounds = cronBounds{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+// CronField is one parsed field; Values lists every matching value in order
+type CronField struct {
+	Name   string `json:"name"`
+	Raw    string `json:"raw"`
+	Values []int  `json:"values"`
+	// Any is true for * and ?, which matter for the day-of-month/day-of-week rule
+	Any  bool `json:"-"`
+	bits uint64
+}
+
+func (f *CronField) has(v int) bool {
+	return f.bits&(1<<uint(v)) != 0
+}
+
+// CronSchedule is a parsed cron expression
+type CronSchedule struct {
+	Expression string
+	HasSeconds bool
+	Second     *CronField
+	Minute     *CronField
+	Hour       *CronField
+	DayOfMonth *CronField
+	Month      *CronField
+	DayOfWeek  *CronField
+	Location   *time.Location
+	Descriptor string
+	Warnings   []string
+}
+
+// ParseCron parses a standard 5-field cron expression, a 6-field expression with leading seconds,
+// an @ descriptor, and an optional CRON_TZ= or TZ= prefix
+func ParseCron(expression string, loc *time.Location) (*CronSchedule, error) {
+	schedule := &CronSchedule{Expression: strings.TrimSpace(expression), Location: loc}
+	spec := schedule.Expression
+	if spec == "" {
+		return nil, fmt.Errorf("cron expression is empty")
+	}
+
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		prefix, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(prefix, "=")
+		tz, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone in %s: %w", prefix, err)
+		}
+		schedule.Location = tz
+		schedule.Warnings = append(schedule.Warnings, "Kubernetes rejects CRON_TZ/TZ in spec.schedule since v1.27; set spec.timeZone instead")
+		spec = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		if strings.HasPrefix(spec, "@every") {
+			return nil, fmt.Errorf("@every is a robfig/cron extension that Kubernetes CronJobs do not accept")
+		}
+		if strings.EqualFold(spec, "@reboot") {
+			return nil, fmt.Errorf("@reboot runs once at startup and has no schedule")
+		}
+		expanded, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown descriptor %s", spec)
+		}
+		schedule.Descriptor = strings.ToLower(spec)
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+		schedule.HasSeconds = true
+		schedule.Warnings = append(schedule.Warnings, "6-field expressions with seconds are not standard cron; Kubernetes CronJobs accept only 5 fields")
+	default:
+		return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	bounds := []cronBounds{secondBounds, minuteBounds, hourBounds, domBounds, monthBounds, dowBounds}
+	parsed := make([]*CronField, len(fields))
+	for i, raw := range fields {
+		field, err := parseCronField(raw, bounds[i])
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = field
+	}
+	schedule.Second, schedule.Minute, schedule.Hour = parsed[0], parsed[1], parsed[2]
+	schedule.DayOfMonth, schedule.Month, schedule.DayOfWeek = parsed[3], parsed[4], parsed[5]
+
+	// Fold 7 into Sunday
+	if schedule.DayOfWeek.has(7) {
+		schedule.DayOfWeek.bits = schedule.DayOfWeek.bits&^(1<<7) | 1
+		schedule.DayOfWeek.Values = bitValues(schedule.DayOfWeek.bits, 0, 6)
+	}
+
+	if !schedule.DayOfMonth.Any && !schedule.DayOfWeek.Any {
+		schedule.Warnings = append(schedule.Warnings, "both day-of-month and day-of-week are restricted, so the job runs when EITHER matches")
+	}
+	if last := schedule.DayOfMonth.Values[len(schedule.DayOfMonth.Values)-1]; !schedule.DayOfMonth.Any && schedule.DayOfWeek.Any && last > 28 {
+		schedule.Warnings = append(schedule.Warnings, fmt.Sprintf("day-of-month %d does not exist in every month; those months are skipped", last))
+	}
+	return schedule, nil
+}
+
+// parseCronField parses a comma-separated list of *, ?, values, ranges and steps
+func parseCronField(raw string, bounds cronBounds) (*CronField, error) {
+	field := &CronField{Name: bounds.name, Raw: raw}
+	for _, part := range strings.Split(raw, ",") {
+		if part == "" {
+			return nil, fmt.Errorf("%s: empty list item in %q", bounds.name, raw)
+		}
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("%s: invalid step %q", bounds.name, stepPart)
+			}
+			step = n
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			if rangePart == "?" && bounds.name != domBounds.name && bounds.name != dowBounds.name {
+				return nil, fmt.Errorf("%s: ? is only allowed for day-of-month and day-of-week", bounds.name)
+			}
+			low, high = bounds.min, bounds.max
+			if bounds.name == dowBounds.name {
+				high = 6
+			}
+			if !hasStep {
+				field.Any = true
+			}
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = cronValue(from, bounds); err != nil {
+				return nil, err
+			}
+			if high, err = cronValue(to, bounds); err != nil {
+				return nil, err
+			}
+			if low > high {
+				return nil, fmt.Errorf("%s: range %q runs backwards", bounds.name, rangePart)
+			}
+		default:
+			value, err := cronValue(rangePart, bounds)
+			if err != nil {
+				return nil, err
+			}
+			low, high = value, value
+			if hasStep {
+				// a/n means from a to the end of the range
+				high = bounds.max
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			field.bits |= 1 << uint(v)
+		}
+	}
+	field.Values = bitValues(field.bits, bounds.min, bounds.max)
+	return field, nil
+}
+
+func cronValue(text string, bounds cronBounds) (int, error) {
+	if value, ok := bounds.names[strings.ToUpper(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", bounds.name, text)
+	}
+	if value < bounds.min || value > bounds.max {
+		return 0, fmt.Errorf("%s: %d is out of range %d-%d", bounds.name, value, bounds.min, bounds.max)
+	}
+	return value, nil
+}
+
+func bitValues(bits uint64, min, max int) []int {
+	var values []int
+	for v := min; v <= max; v++ {
+		if bits&(1<<uint(v)) != 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Next returns the first run strictly after t, or the zero time if there is none within cronSearchYears
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.Location)
+	if s.HasSeconds {
+		t = t.Truncate(time.Second).Add(time.Second)
+	} else {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+	}
+	yearLimit := t.Year() + cronSearchYears
+
+	// Advance the most significant mismatching field, resetting the ones below it;
+	// each wrap-around restarts the check from the month
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for !s.Month.has(int(t.Month())) {
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, s.Location).AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.Location).AddDate(0, 0, 1)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for !s.Hour.has(t.Hour()) {
+		next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, s.Location).Add(time.Hour)
+		if next.Day() != t.Day() {
+			t = next
+			goto wrap
+		}
+		t = next
+	}
+
+	for !s.Minute.has(t.Minute()) {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.HasSeconds && !s.Second.has(t.Second()) {
+		t = t.Truncate(time.Second).Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+// dayMatches applies the cron rule: when both day fields are restricted either may match
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.DayOfMonth.has(t.Day())
+	dowMatch := s.DayOfWeek.has(int(t.Weekday()))
+	if s.DayOfMonth.Any || s.DayOfWeek.Any {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Describe renders the schedule in English
+func (s *CronSchedule) Describe() string {
+	var parts []string
+	parts = append(parts, s.describeTime())
+
+	switch {
+	case s.DayOfMonth.Any && s.DayOfWeek.Any:
+		// every day; said implicitly unless the month is restricted
+	case s.DayOfWeek.Any:
+		parts = append(parts, "on "+describeField(s.DayOfMonth, "day-of-month", nil))
+	case s.DayOfMonth.Any:
+		parts = append(parts, "on "+describeField(s.DayOfWeek, "", dayNames))
+	default:
+		parts = append(parts, "on "+describeField(s.DayOfMonth, "day-of-month", nil)+" or on "+describeField(s.DayOfWeek, "", dayNames))
+	}
+
+	if !s.Month.Any {
+		parts = append(parts, "in "+describeField(s.Month, "", monthNames))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *CronSchedule) describeTime() string {
+	exactSecond := !s.HasSeconds || len(s.Second.Values) == 1
+	if exactSecond && len(s.Minute.Values) == 1 && !s.Hour.Any && isList(s.Hour.Raw) && len(s.Hour.Values) <= 6 {
+		times := make([]string, len(s.Hour.Values))
+		for i, hour := range s.Hour.Values {
+			if s.HasSeconds {
+				times[i] = fmt.Sprintf("%02d:%02d:%02d", hour, s.Minute.Values[0], s.Second.Values[0])
+			} else {
+				times[i] = fmt.Sprintf("%02d:%02d", hour, s.Minute.Values[0])
+			}
+		}
+		return "At " + joinEnglish(times)
+	}
+
+	var parts []string
+	if s.HasSeconds {
+		parts = append(parts, describeUnit(s.Second, "second"))
+	}
+	minute := describeUnit(s.Minute, "minute")
+	if len(s.Minute.Values) == 1 && (!s.HasSeconds || len(s.Second.Values) == 1) {
+		minute = fmt.Sprintf("at minute %d", s.Minute.Values[0])
+	}
+	parts = append(parts, minute)
+	if s.Hour.Any {
+		if len(s.Minute.Values) == 1 {
+			parts = append(parts, "of every hour")
+		}
+	} else {
+		parts = append(parts, "during "+describeField(s.Hour, "hour", nil))
+	}
+	text := strings.Join(parts, " ")
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// describeUnit renders a time field as "every minute", "every 15 minutes" or "minutes 0, 30"
+func describeUnit(field *CronField, unit string) string {
+	if field.Any {
+		return "every " + unit
+	}
+	if base, step, ok := strings.Cut(field.Raw, "/"); ok && !strings.Contains(field.Raw, ",") {
+		text := fmt.Sprintf("every %s %ss", step, unit)
+		if base != "*" {
+			text += fmt.Sprintf(" from %d through %d", field.Values[0], field.Values[len(field.Values)-1])
+		}
+		return text
+	}
+	return describeField(field, unit, nil)
+}
+
+// describeField renders the values of a field, collapsing consecutive runs into ranges
+func describeField(field *CronField, unit string, names []string) string {
+	label := func(v int) string {
+		if names != nil {
+			return names[v]
+		}
+		if unit == "hour" {
+			return fmt.Sprintf("%02d:00", v)
+		}
+		return strconv.Itoa(v)
+	}
+
+	var items []string
+	values := field.Values
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		switch {
+		case j-i >= 2:
+			items = append(items, label(values[i])+" through "+label(values[j]))
+		case j > i:
+			items = append(items, label(values[i]), label(values[j]))
+		default:
+			items = append(items, label(values[i]))
+		}
+		i = j + 1
+	}
+
+	text := joinEnglish(items)
+	if unit != "" && unit != "hour" {
+		text = unit + " " + text
+	}
+	return text
+}
+
+func isList(raw string) bool {
+	return !strings.ContainsAny(raw, "/-*")
+}
+
+func joinEnglish(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+	}
+}
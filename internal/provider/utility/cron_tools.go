@@ -0,0 +1,122 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// createCronExplainTool creates the cron expression explain tool
+func (p *UtilityProvider) createCronExplainTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "cron_explain",
+		Description: "Parse a cron expression, describe it in English and list its next run times in a timezone. Accepts standard 5-field cron as used by Kubernetes CronJobs (names like MON/JAN, ranges, steps, ?, @hourly/@daily/@weekly/@monthly/@yearly, CRON_TZ= prefix) and 6-field expressions with seconds",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"expression": {
+					"type": "string",
+					"description": "Cron expression, e.g. \"*/15 9-17 * * MON-FRI\" or \"@daily\""
+				},
+				"timezone": {
+					"type": "string",
+					"description": "Timezone the schedule runs in (IANA name, local or offset); a CRON_TZ= prefix takes precedence. For Kubernetes this is spec.timeZone, or the controller's timezone (usually UTC) when unset",
+					"default": "UTC"
+				},
+				"count": {
+					"type": "integer",
+					"description": "Number of upcoming runs to list (max 100)",
+					"default": 5
+				},
+				"from": {
+					"type": "string",
+					"description": "List runs after this timestamp (default: now)"
+				}
+			},
+			"required": ["expression"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Expression string `json:"expression"`
+			Timezone   string `json:"timezone,omitempty"`
+			Count      int    `json:"count,omitempty"`
+			From       string `json:"from,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Expression == "" {
+			return p.createErrorResult(fmt.Errorf("expression parameter is required")), nil
+		}
+		if args.Count <= 0 {
+			args.Count = defaultCronRuns
+		}
+		if args.Count > maxCronRuns {
+			args.Count = maxCronRuns
+		}
+
+		loc, err := loadLocation(args.Timezone)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		schedule, err := ParseCron(args.Expression, loc)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid cron expression: %w", err)), nil
+		}
+
+		now := time.Now()
+		from := now
+		if args.From != "" {
+			parsed, err := ParseTimestamp(args.From, schedule.Location, now)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("invalid from: %w", err)), nil
+			}
+			from = parsed.Time
+		}
+
+		var runs []map[string]string
+		for t := schedule.Next(from); !t.IsZero() && len(runs) < args.Count; t = schedule.Next(t) {
+			runs = append(runs, map[string]string{
+				"time":     t.Format(time.RFC3339),
+				"utc":      t.UTC().Format(time.RFC3339),
+				"weekday":  t.Weekday().String(),
+				"relative": RelativeTo(t, now),
+			})
+		}
+
+		fields := []*CronField{schedule.Minute, schedule.Hour, schedule.DayOfMonth, schedule.Month, schedule.DayOfWeek}
+		if schedule.HasSeconds {
+			fields = append([]*CronField{schedule.Second}, fields...)
+		}
+
+		result := map[string]interface{}{
+			"expression":  schedule.Expression,
+			"description": schedule.Describe(),
+			"timezone":    schedule.Location.String(),
+			"fields":      fields,
+			"next_runs":   runs,
+		}
+		if schedule.Descriptor != "" {
+			result["descriptor"] = schedule.Descriptor
+		}
+		if len(runs) == 0 {
+			schedule.Warnings = append(schedule.Warnings, fmt.Sprintf("the schedule never fires within %d years", cronSearchYears))
+		}
+		if len(schedule.Warnings) > 0 {
+			result["warnings"] = schedule.Warnings
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
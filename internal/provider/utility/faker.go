@@ -0,0 +1,662 @@
+package utility
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"regexp/syntax"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultFakeCount = 10
+	maxFakeCount     = 1000
+	// maxPatternRepeat bounds open-ended regex repetition (*, +, {n,})
+	maxPatternRepeat = 8
+	// maxUniqueAttempts is how often a unique field is regenerated before giving up
+	maxUniqueAttempts = 100
+)
+
+// FieldSpec is a JSON-schema-like description of one generated value
+type FieldSpec struct {
+	Type       string        `json:"type,omitempty"`
+	Format     string        `json:"format,omitempty"`
+	Faker      string        `json:"faker,omitempty"`
+	Enum       []interface{} `json:"enum,omitempty"`
+	Const      interface{}   `json:"const,omitempty"`
+	Pattern    string        `json:"pattern,omitempty"`
+	Minimum    *float64      `json:"minimum,omitempty"`
+	Maximum    *float64      `json:"maximum,omitempty"`
+	MinLength  int           `json:"minLength,omitempty"`
+	MaxLength  int           `json:"maxLength,omitempty"`
+	MinItems   int           `json:"minItems,omitempty"`
+	MaxItems   int           `json:"maxItems,omitempty"`
+	Items      *FieldSpec    `json:"items,omitempty"`
+	Properties Properties    `json:"properties,omitempty"`
+	// From and To bound generated timestamps; any format accepted by time_parse
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Nullable is the probability (0-1) of generating null instead of a value
+	Nullable float64 `json:"nullable,omitempty"`
+	Unique   bool    `json:"unique,omitempty"`
+}
+
+// Property is a named field of an object spec
+type Property struct {
+	Name string
+	Spec *FieldSpec
+}
+
+// Properties keeps object fields in the order they were declared, which becomes the CSV column order
+type Properties []Property
+
+// UnmarshalJSON decodes an object of field specs while preserving key order
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("properties must be an object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		spec := &FieldSpec{}
+		if err := dec.Decode(spec); err != nil {
+			return fmt.Errorf("property %v: %w", tok, err)
+		}
+		*p = append(*p, Property{Name: tok.(string), Spec: spec})
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// Faker generates values from field specs with a seedable source
+type Faker struct {
+	rng    *rand.Rand
+	now    time.Time
+	seen   map[*FieldSpec]map[string]bool
+	fakers map[string]func() interface{}
+}
+
+// NewFaker creates a faker; the same seed produces the same data
+func NewFaker(seed int64, now time.Time) *Faker {
+	f := &Faker{
+		rng:  rand.New(rand.NewSource(seed)),
+		now:  now,
+		seen: map[*FieldSpec]map[string]bool{},
+	}
+	f.fakers = f.builtinFakers()
+	return f
+}
+
+// FakerNames lists the supported faker/format generators
+func (f *Faker) FakerNames() []string {
+	names := make([]string, 0, len(f.fakers))
+	for name := range f.fakers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks a spec for unknown generators and bad patterns before generating
+func (f *Faker) Validate(spec *FieldSpec, path string) error {
+	if name := spec.generatorName(); name != "" {
+		if _, ok := f.fakers[name]; !ok {
+			return fmt.Errorf("%s: unknown faker %q", path, name)
+		}
+	}
+	if spec.Pattern != "" {
+		if _, err := syntax.Parse(spec.Pattern, syntax.Perl); err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+	}
+	for _, value := range []string{spec.From, spec.To} {
+		if value == "" {
+			continue
+		}
+		if _, err := ParseTimestamp(value, time.UTC, f.now); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if spec.Items != nil {
+		if err := f.Validate(spec.Items, path+"[]"); err != nil {
+			return err
+		}
+	}
+	for _, prop := range spec.Properties {
+		if err := f.Validate(prop.Spec, path+"."+prop.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generatorName is the named generator a spec asks for, if any; string formats that are
+// only validation hints in JSON schema (e.g. "date-time") map onto generators too
+func (s *FieldSpec) generatorName() string {
+	if s.Faker != "" {
+		return s.Faker
+	}
+	if s.Format != "" && (s.Type == "" || s.Type == "string") {
+		return s.Format
+	}
+	return ""
+}
+
+// Generate produces one value for spec
+func (f *Faker) Generate(spec *FieldSpec) (interface{}, error) {
+	if spec.Nullable > 0 && f.rng.Float64() < spec.Nullable {
+		return nil, nil
+	}
+	if !spec.Unique {
+		return f.generate(spec)
+	}
+
+	seen := f.seen[spec]
+	if seen == nil {
+		seen = map[string]bool{}
+		f.seen[spec] = seen
+	}
+	for i := 0; i < maxUniqueAttempts; i++ {
+		value, err := f.generate(spec)
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprint(value)
+		if !seen[key] {
+			seen[key] = true
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("could not generate a unique value after %d attempts (%d generated so far); widen the field's range", maxUniqueAttempts, len(seen))
+}
+
+func (f *Faker) generate(spec *FieldSpec) (interface{}, error) {
+	switch {
+	case spec.Const != nil:
+		return spec.Const, nil
+	case len(spec.Enum) > 0:
+		return spec.Enum[f.rng.Intn(len(spec.Enum))], nil
+	case spec.Pattern != "":
+		return f.fromPattern(spec.Pattern)
+	}
+
+	if name := spec.generatorName(); name != "" {
+		if name == "date-time" || name == "timestamp" || name == "date" || name == "epoch" || name == "epoch_ms" {
+			return f.timestamp(spec, name)
+		}
+		return f.fakers[name](), nil
+	}
+
+	kind := spec.Type
+	if kind == "" && len(spec.Properties) > 0 {
+		kind = "object"
+	} else if kind == "" && spec.Items != nil {
+		kind = "array"
+	}
+
+	switch kind {
+	case "", "string":
+		minLen, maxLen := spec.MinLength, spec.MaxLength
+		if maxLen == 0 {
+			maxLen = max(minLen, 12)
+		}
+		return f.letters(f.between(minLen, maxLen)), nil
+	case "integer":
+		lo, hi := 0.0, 1000.0
+		if spec.Minimum != nil {
+			lo = *spec.Minimum
+		}
+		if spec.Maximum != nil {
+			hi = *spec.Maximum
+		}
+		if hi < lo {
+			return nil, fmt.Errorf("maximum %v is below minimum %v", hi, lo)
+		}
+		return int64(lo) + f.rng.Int63n(int64(hi)-int64(lo)+1), nil
+	case "number":
+		lo, hi := 0.0, 1000.0
+		if spec.Minimum != nil {
+			lo = *spec.Minimum
+		}
+		if spec.Maximum != nil {
+			hi = *spec.Maximum
+		}
+		if hi < lo {
+			return nil, fmt.Errorf("maximum %v is below minimum %v", hi, lo)
+		}
+		v, _ := strconv.ParseFloat(strconv.FormatFloat(lo+f.rng.Float64()*(hi-lo), 'f', 2, 64), 64)
+		return v, nil
+	case "boolean":
+		return f.rng.Intn(2) == 1, nil
+	case "null":
+		return nil, nil
+	case "array":
+		if spec.Items == nil {
+			return nil, fmt.Errorf("array spec requires items")
+		}
+		minItems, maxItems := spec.MinItems, spec.MaxItems
+		if maxItems == 0 {
+			maxItems = max(minItems, 3)
+		}
+		n := f.between(minItems, maxItems)
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := f.Generate(spec.Items)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case "object":
+		return f.object(spec)
+	default:
+		return nil, fmt.Errorf("unsupported type %q", spec.Type)
+	}
+}
+
+// object generates an ordered record so JSON output keeps the declared field order
+func (f *Faker) object(spec *FieldSpec) (*Record, error) {
+	record := &Record{}
+	for _, prop := range spec.Properties {
+		value, err := f.Generate(prop.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", prop.Name, err)
+		}
+		record.Keys = append(record.Keys, prop.Name)
+		record.Values = append(record.Values, value)
+	}
+	return record, nil
+}
+
+// timestamp generates a time between spec.From and spec.To (default: the last 30 days)
+func (f *Faker) timestamp(spec *FieldSpec, format string) (interface{}, error) {
+	from, to := f.now.Add(-30*24*time.Hour), f.now
+	if spec.From != "" {
+		parsed, err := ParseTimestamp(spec.From, time.UTC, f.now)
+		if err != nil {
+			return nil, err
+		}
+		from = parsed.Time
+	}
+	if spec.To != "" {
+		parsed, err := ParseTimestamp(spec.To, time.UTC, f.now)
+		if err != nil {
+			return nil, err
+		}
+		to = parsed.Time
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("to must be after from")
+	}
+
+	t := from.Add(time.Duration(f.rng.Int63n(int64(to.Sub(from))))).UTC().Truncate(time.Millisecond)
+	switch format {
+	case "date":
+		return t.Format("2006-01-02"), nil
+	case "epoch":
+		return t.Unix(), nil
+	case "epoch_ms":
+		return t.UnixMilli(), nil
+	default:
+		return t.Format(time.RFC3339Nano), nil
+	}
+}
+
+// fromPattern generates a string matching a regular expression
+func (f *Faker) fromPattern(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %w", err)
+	}
+	var sb strings.Builder
+	f.writePattern(&sb, re.Simplify())
+	return sb.String(), nil
+}
+
+func (f *Faker) writePattern(sb *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && f.rng.Intn(2) == 1 {
+				r = []rune(strings.ToUpper(string(r)))[0]
+			}
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		// Rune holds inclusive ranges as pairs
+		var total int
+		for i := 0; i < len(re.Rune); i += 2 {
+			total += int(re.Rune[i+1]-re.Rune[i]) + 1
+		}
+		if total == 0 {
+			return
+		}
+		n := f.rng.Intn(total)
+		for i := 0; i < len(re.Rune); i += 2 {
+			size := int(re.Rune[i+1]-re.Rune[i]) + 1
+			if n < size {
+				sb.WriteRune(re.Rune[i] + rune(n))
+				return
+			}
+			n -= size
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(alphanumeric[f.rng.Intn(len(alphanumeric))])
+	case syntax.OpCapture:
+		f.writePattern(sb, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			f.writePattern(sb, sub)
+		}
+	case syntax.OpAlternate:
+		f.writePattern(sb, re.Sub[f.rng.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			lo, hi = 0, -1
+		case syntax.OpPlus:
+			lo, hi = 1, -1
+		case syntax.OpQuest:
+			lo, hi = 0, 1
+		}
+		if hi < 0 {
+			hi = lo + maxPatternRepeat
+		}
+		for i, n := 0, f.between(lo, hi); i < n; i++ {
+			f.writePattern(sb, re.Sub[0])
+		}
+	}
+	// Anchors, word boundaries and empty matches produce no output
+}
+
+// between returns a random int in [lo, hi]
+func (f *Faker) between(lo, hi int) int {
+	if hi <= lo {
+		return lo
+	}
+	return lo + f.rng.Intn(hi-lo+1)
+}
+
+const (
+	lowercase    = "abcdefghijklmnopqrstuvwxyz"
+	alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+func (f *Faker) letters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = lowercase[f.rng.Intn(len(lowercase))]
+	}
+	return string(b)
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.rng.Intn(len(values))]
+}
+
+func (f *Faker) hexString(n int) string {
+	b := make([]byte, n)
+	f.rng.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var (
+	firstNames  = []string{"Alice", "Bob", "Carol", "David", "Emma", "Farid", "Grace", "Hiro", "Ines", "Jonas", "Kavya", "Liam", "Mei", "Noah", "Olga", "Priya", "Quinn", "Rosa", "Sven", "Tariq", "Uma", "Victor", "Wen", "Yusuf", "Zoe"}
+	lastNames   = []string{"Smith", "Garcia", "Chen", "Müller", "Okafor", "Tanaka", "Rossi", "Novak", "Silva", "Kowalski", "Haddad", "Johansson", "Patel", "Kim", "Dubois", "Nguyen", "Ivanova", "O'Brien"}
+	words       = []string{"alpha", "amber", "anchor", "beacon", "cedar", "delta", "ember", "falcon", "garnet", "harbor", "indigo", "juniper", "kepler", "lumen", "meadow", "nimbus", "orbit", "pioneer", "quartz", "river", "summit", "tundra", "vertex", "willow", "zephyr"}
+	companies   = []string{"Acme", "Globex", "Initech", "Umbrella", "Stark", "Wayne", "Hooli", "Vandelay", "Soylent", "Tyrell"}
+	suffixes    = []string{"Inc", "LLC", "GmbH", "Ltd", "Corp"}
+	cities      = []string{"Amsterdam", "Berlin", "Chicago", "Dublin", "Lagos", "Lisbon", "Melbourne", "Mumbai", "Osaka", "São Paulo", "Seoul", "Toronto", "Warsaw", "Zürich"}
+	countries   = []string{"AU", "BR", "CA", "DE", "GB", "IE", "IN", "JP", "KR", "NG", "NL", "PL", "PT", "US"}
+	streets     = []string{"Main St", "Oak Ave", "Station Rd", "Harbour Way", "Market St", "Park Lane", "Church St"}
+	tlds        = []string{"com", "io", "dev", "net", "org", "example"}
+	regions     = []string{"us-east-1", "us-west-2", "eu-west-1", "eu-central-1", "ap-southeast-1", "ap-northeast-1"}
+	envs        = []string{"dev", "staging", "prod"}
+	logLevels   = []string{"DEBUG", "INFO", "INFO", "INFO", "WARN", "ERROR"}
+	httpMethods = []string{"GET", "GET", "GET", "POST", "PUT", "PATCH", "DELETE"}
+	httpStatus  = []int{200, 200, 200, 200, 201, 204, 301, 304, 400, 401, 403, 404, 409, 422, 429, 500, 502, 503}
+	services    = []string{"api-gateway", "auth-service", "billing", "checkout", "inventory", "notifications", "orders", "payments", "search", "user-service"}
+	userAgents  = []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+		"curl/8.5.0",
+		"okhttp/4.12.0",
+	}
+)
+
+// builtinFakers maps faker/format names to generators
+func (f *Faker) builtinFakers() map[string]func() interface{} {
+	firstName := func() string { return f.pick(firstNames) }
+	lastName := func() string { return f.pick(lastNames) }
+	domain := func() string { return f.pick(words) + "." + f.pick(tlds) }
+	url := func() interface{} {
+		return "https://" + domain() + "/" + f.pick(words) + "/" + strconv.Itoa(f.rng.Intn(10000))
+	}
+	username := func() string {
+		return strings.ToLower(firstName()) + "." + strings.ToLower(strings.ReplaceAll(lastName(), "'", "")) + strconv.Itoa(f.rng.Intn(100))
+	}
+
+	return map[string]func() interface{}{
+		"uuid": func() interface{} {
+			b := make([]byte, 16)
+			f.rng.Read(b)
+			b[6] = b[6]&0x0f | 0x40
+			b[8] = b[8]&0x3f | 0x80
+			return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+		},
+		"first_name": func() interface{} { return firstName() },
+		"last_name":  func() interface{} { return lastName() },
+		"name":       func() interface{} { return firstName() + " " + lastName() },
+		"username":   func() interface{} { return username() },
+		"email":      func() interface{} { return username() + "@" + domain() },
+		"phone": func() interface{} {
+			return fmt.Sprintf("+1-%03d-555-%04d", 200+f.rng.Intn(800), f.rng.Intn(10000))
+		},
+		"company": func() interface{} { return f.pick(companies) + " " + f.pick(suffixes) },
+		"city":    func() interface{} { return f.pick(cities) },
+		"country": func() interface{} { return f.pick(countries) },
+		"address": func() interface{} {
+			return fmt.Sprintf("%d %s, %s", 1+f.rng.Intn(999), f.pick(streets), f.pick(cities))
+		},
+		"domain": func() interface{} { return domain() },
+		"hostname": func() interface{} {
+			return f.pick(services) + "-" + f.hexString(2) + "." + f.pick(regions) + ".internal"
+		},
+		"uri": url,
+		"url": url,
+		"ipv4": func() interface{} {
+			return fmt.Sprintf("%d.%d.%d.%d", 1+f.rng.Intn(223), f.rng.Intn(256), f.rng.Intn(256), 1+f.rng.Intn(254))
+		},
+		"ipv6": func() interface{} {
+			return fmt.Sprintf("2001:db8:%x:%x::%x", f.rng.Intn(0x10000), f.rng.Intn(0x10000), 1+f.rng.Intn(0xffff))
+		},
+		"word":     func() interface{} { return f.pick(words) },
+		"sentence": func() interface{} { return f.sentence() },
+		"paragraph": func() interface{} {
+			sentences := make([]string, f.between(3, 6))
+			for i := range sentences {
+				sentences[i] = f.sentence()
+			}
+			return strings.Join(sentences, " ")
+		},
+		"hex":         func() interface{} { return f.hexString(8) },
+		"sha256":      func() interface{} { return f.hexString(32) },
+		"git_sha":     func() interface{} { return f.hexString(20) },
+		"trace_id":    func() interface{} { return f.hexString(16) },
+		"span_id":     func() interface{} { return f.hexString(8) },
+		"semver":      func() interface{} { return fmt.Sprintf("%d.%d.%d", f.rng.Intn(5), f.rng.Intn(20), f.rng.Intn(30)) },
+		"user_agent":  func() interface{} { return f.pick(userAgents) },
+		"http_method": func() interface{} { return f.pick(httpMethods) },
+		"http_status": func() interface{} { return httpStatus[f.rng.Intn(len(httpStatus))] },
+		"log_level":   func() interface{} { return f.pick(logLevels) },
+		"service":     func() interface{} { return f.pick(services) },
+		"region":      func() interface{} { return f.pick(regions) },
+		"environment": func() interface{} { return f.pick(envs) },
+		"pod_name": func() interface{} {
+			return f.pick(services) + "-" + f.hexString(5) + "-" + f.letters(5)
+		},
+		"latency_ms": func() interface{} {
+			// Long-tailed, like real request latencies
+			return int(f.rng.ExpFloat64()*80) + 2
+		},
+		// Timestamps are handled by timestamp() so they can honour from/to
+		"date-time": nil,
+		"timestamp": nil,
+		"date":      nil,
+		"epoch":     nil,
+		"epoch_ms":  nil,
+	}
+}
+
+func (f *Faker) sentence() string {
+	n := f.between(4, 10)
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = f.pick(words)
+	}
+	parts[0] = strings.ToUpper(parts[0][:1]) + parts[0][1:]
+	return strings.Join(parts, " ") + "."
+}
+
+// Record is a generated object that marshals with its fields in declaration order
+type Record struct {
+	Keys   []string
+	Values []interface{}
+}
+
+// MarshalJSON writes the record as a JSON object in key order
+func (r *Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.Values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// flatten writes nested records as dotted columns; arrays are JSON-encoded into one cell
+func (r *Record) flatten(prefix string, columns *[]string, cells map[string]string) {
+	for i, key := range r.Keys {
+		name := prefix + key
+		if nested, ok := r.Values[i].(*Record); ok {
+			nested.flatten(name+".", columns, cells)
+			continue
+		}
+		*columns = append(*columns, name)
+		cells[name] = csvCell(r.Values[i])
+	}
+}
+
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}, map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// EncodeFixtures renders generated values as json, jsonl or csv
+func EncodeFixtures(values []interface{}, format string) ([]byte, error) {
+	switch format {
+	case "", "json":
+		return json.MarshalIndent(values, "", "  ")
+	case "jsonl", "ndjson":
+		var buf bytes.Buffer
+		for _, value := range values {
+			line, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+	case "csv":
+		// Columns are the union over all rows, since nullable nested objects drop theirs
+		var header []string
+		known := map[string]bool{}
+		rows := make([]map[string]string, len(values))
+		for i, value := range values {
+			var columns []string
+			cells := map[string]string{}
+			if record, ok := value.(*Record); ok {
+				record.flatten("", &columns, cells)
+			} else {
+				columns = []string{"value"}
+				cells["value"] = csvCell(value)
+			}
+			for _, column := range columns {
+				if !known[column] {
+					known[column] = true
+					header = append(header, column)
+				}
+			}
+			rows[i] = cells
+		}
+		// A null nested object shows up as a bare column next to its dotted fields; keep only the fields
+		var columns []string
+		for _, column := range header {
+			if !slices.ContainsFunc(header, func(other string) bool { return strings.HasPrefix(other, column+".") }) {
+				columns = append(columns, column)
+			}
+		}
+		header = columns
+
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(header); err != nil {
+			return nil, err
+		}
+		for _, cells := range rows {
+			row := make([]string, len(header))
+			for j, column := range header {
+				row[j] = cells[column]
+			}
+			if err := w.Write(row); err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+		return buf.Bytes(), w.Error()
+	default:
+		return nil, fmt.Errorf("unsupported format %q (use json, jsonl or csv)", format)
+	}
+}
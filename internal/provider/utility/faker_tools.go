@@ -0,0 +1,143 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// fixturePreviewRows is how many records are echoed back when fixtures are written to a file
+const fixturePreviewRows = 3
+
+// createFakeDataTool creates the test data generation tool
+func (p *UtilityProvider) createFakeDataTool() entity.ToolDefinition {
+	names := strings.Join(NewFaker(0, time.Now()).FakerNames(), ", ")
+
+	tool := &mcp.Tool{
+		Name:        "fake_data",
+		Description: "Generate synthetic test data from a JSON-schema-like spec (names, emails, UUIDs, timestamps, regex patterns, enums, nested objects/arrays), optionally writing it as a JSON, JSONL or CSV fixture file. Use a seed for reproducible datasets",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"schema": {
+					"type": "object",
+					"description": "Spec of one record, e.g. {\"type\":\"object\",\"properties\":{\"id\":{\"format\":\"uuid\"},\"email\":{\"faker\":\"email\",\"unique\":true},\"order\":{\"pattern\":\"ORD-[0-9]{6}\"},\"status\":{\"enum\":[\"paid\",\"refunded\"]},\"amount\":{\"type\":\"number\",\"minimum\":1,\"maximum\":500},\"created_at\":{\"format\":\"date-time\",\"from\":\"-7d\"}}}. Fields support type, format/faker, enum, const, pattern, minimum/maximum, minLength/maxLength, items/minItems/maxItems, properties, from/to (timestamps), nullable (probability) and unique. Fakers: ` + names + `"
+				},
+				"count": {
+					"type": "integer",
+					"description": "Number of records to generate (max 1000)",
+					"default": 10
+				},
+				"seed": {
+					"type": "integer",
+					"description": "Random seed; the same seed and schema produce the same data (default: random)"
+				},
+				"format": {
+					"type": "string",
+					"description": "Output format",
+					"enum": ["json", "jsonl", "csv"],
+					"default": "json"
+				},
+				"output_path": {
+					"type": "string",
+					"description": "Write the fixture to this file via the file provider (subject to its whitelist and read-only mode) instead of returning all records"
+				},
+				"create_dirs": {
+					"type": "boolean",
+					"description": "Create parent directories of output_path",
+					"default": false
+				}
+			},
+			"required": ["schema"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Schema     *FieldSpec `json:"schema"`
+			Count      int        `json:"count,omitempty"`
+			Seed       *int64     `json:"seed,omitempty"`
+			Format     string     `json:"format,omitempty"`
+			OutputPath string     `json:"output_path,omitempty"`
+			CreateDirs bool       `json:"create_dirs,omitempty"`
+		}{Count: defaultFakeCount, Format: "json"}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Schema == nil {
+			return p.createErrorResult(fmt.Errorf("schema parameter is required")), nil
+		}
+		if args.Count <= 0 {
+			args.Count = defaultFakeCount
+		}
+		if args.Count > maxFakeCount {
+			args.Count = maxFakeCount
+		}
+		if args.OutputPath != "" && p.files == nil {
+			return p.createErrorResult(fmt.Errorf("writing fixtures is not available")), nil
+		}
+
+		seed := time.Now().UnixNano()
+		if args.Seed != nil {
+			seed = *args.Seed
+		}
+		faker := NewFaker(seed, time.Now())
+		if err := faker.Validate(args.Schema, "schema"); err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		records := make([]interface{}, 0, args.Count)
+		for i := 0; i < args.Count; i++ {
+			record, err := faker.Generate(args.Schema)
+			if err != nil {
+				return p.createErrorResult(fmt.Errorf("record %d: %w", i+1, err)), nil
+			}
+			records = append(records, record)
+		}
+
+		if args.OutputPath == "" {
+			if args.Format == "json" {
+				return p.formatJSONResult(map[string]interface{}{
+					"count":   len(records),
+					"seed":    seed,
+					"records": records,
+				}), nil
+			}
+			content, err := EncodeFixtures(records, args.Format)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: string(content)}},
+			}, nil
+		}
+
+		content, err := EncodeFixtures(records, args.Format)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		if err := p.files.WriteFile(args.OutputPath, content, args.CreateDirs); err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"path":          args.OutputPath,
+			"format":        args.Format,
+			"count":         len(records),
+			"seed":          seed,
+			"written_bytes": len(content),
+			"preview":       records[:min(fixturePreviewRows, len(records))],
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
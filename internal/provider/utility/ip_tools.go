@@ -0,0 +1,292 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// createIPInfoTool creates the IP/CIDR analysis tool
+func (p *UtilityProvider) createIPInfoTool() entity.ToolDefinition {
+	geoNote := "No GeoIP database is configured (utility.geoip_databases)"
+	if p.geoip.Available() {
+		geoNote = "GeoIP data comes from " + strings.Join(p.geoip.Databases(), ", ")
+	}
+
+	tool := &mcp.Tool{
+		Name:        "ip_info",
+		Description: "Classify IP addresses (private, public, loopback, carrier-grade NAT, link-local, documentation, ...), check CIDR membership, describe CIDR ranges and look up country/city/ASN. Accepts addresses with ports, X-Forwarded-For lists, or log text to extract and count addresses from. " + geoNote,
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"ips": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Addresses such as 10.0.0.1, [2001:db8::1]:443 or \"203.0.113.7, 10.0.0.2\" (X-Forwarded-For); a CIDR such as 10.0.0.0/22 is described as a range"
+				},
+				"text": {
+					"type": "string",
+					"description": "Free text such as access log lines; every address found is analyzed with its number of occurrences (max 1MB)"
+				},
+				"cidrs": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "CIDR blocks to check each address against, e.g. office or VPC ranges"
+				},
+				"geoip": {
+					"type": "boolean",
+					"description": "Look up public addresses in the configured GeoIP databases",
+					"default": true
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of addresses to return, most frequent first when extracted from text",
+					"default": 100
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			IPs   []string `json:"ips,omitempty"`
+			Text  string   `json:"text,omitempty"`
+			CIDRs []string `json:"cidrs,omitempty"`
+			GeoIP *bool    `json:"geoip,omitempty"`
+			Limit int      `json:"limit,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if len(args.IPs) == 0 && args.Text == "" {
+			return p.createErrorResult(fmt.Errorf("ips or text parameter is required")), nil
+		}
+		if len(args.IPs) > maxIPInputs {
+			return p.createErrorResult(fmt.Errorf("too many ips (%d, max %d)", len(args.IPs), maxIPInputs)), nil
+		}
+		if len(args.Text) > maxIPText {
+			return p.createErrorResult(fmt.Errorf("text is too large (%d bytes, max %d)", len(args.Text), maxIPText)), nil
+		}
+		if args.Limit <= 0 {
+			args.Limit = 100
+		}
+		if args.Limit > maxIPInputs {
+			args.Limit = maxIPInputs
+		}
+		lookupGeo := p.geoip.Available() && (args.GeoIP == nil || *args.GeoIP)
+
+		cidrs, err := ParseCIDRs(args.CIDRs)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		var addresses []IPInfo
+		var ranges []*PrefixInfo
+		var invalid []string
+		counts := map[string]int{}
+
+		// Header values may hold several comma-separated addresses, client first
+		for _, value := range args.IPs {
+			for _, item := range strings.Split(value, ",") {
+				item = strings.TrimSpace(item)
+				if item == "" {
+					continue
+				}
+				if strings.Contains(item, "/") {
+					prefix, err := DescribePrefix(item)
+					if err != nil {
+						invalid = append(invalid, item)
+						continue
+					}
+					ranges = append(ranges, prefix)
+					continue
+				}
+				addr, err := ParseIP(item)
+				if err != nil {
+					invalid = append(invalid, item)
+					continue
+				}
+				addresses = append(addresses, DescribeIP(item, addr, cidrs))
+			}
+		}
+
+		if args.Text != "" {
+			order, textCounts := ExtractIPs(args.Text)
+			for _, ip := range order {
+				addr, _ := netip.ParseAddr(ip)
+				info := DescribeIP(ip, addr, cidrs)
+				info.Count = textCounts[ip]
+				addresses = append(addresses, info)
+			}
+			sort.SliceStable(addresses, func(i, j int) bool { return addresses[i].Count > addresses[j].Count })
+		}
+
+		total := len(addresses)
+		summary := map[string]interface{}{}
+		categories := map[string]int{}
+		inCIDR := map[string]int{}
+		countries := map[string]int{}
+		networks := map[string]int{}
+		outside := 0
+		for i := range addresses {
+			info := &addresses[i]
+			weight := info.Count
+			if weight == 0 {
+				weight = 1
+			}
+			counts[info.IP] += weight
+			categories[info.Category] += weight
+			for _, cidr := range info.InCIDRs {
+				inCIDR[cidr] += weight
+			}
+			if len(info.InCIDRs) == 0 {
+				outside += weight
+			}
+			if lookupGeo && info.Public && i < args.Limit {
+				addr, _ := netip.ParseAddr(info.IP)
+				geo, err := p.geoip.Lookup(addr)
+				if err != nil {
+					return p.createErrorResult(err), nil
+				}
+				info.Geo = geo
+				if geo != nil && geo.CountryCode != "" {
+					countries[geo.CountryCode] += weight
+				}
+				if geo != nil && geo.ASN != 0 {
+					networks[fmt.Sprintf("AS%d %s", geo.ASN, geo.Organization)] += weight
+				}
+			}
+		}
+		if len(addresses) > args.Limit {
+			addresses = addresses[:args.Limit]
+		}
+
+		result := map[string]interface{}{
+			"addresses": addresses,
+			"total":     total,
+		}
+		if len(ranges) > 0 {
+			result["ranges"] = ranges
+		}
+		if len(invalid) > 0 {
+			result["invalid"] = invalid
+		}
+		if total > 1 {
+			summary["by_category"] = categories
+			summary["unique"] = len(counts)
+			if len(cidrs) > 0 {
+				summary["in_cidrs"] = inCIDR
+				summary["outside_cidrs"] = outside
+			}
+			if len(countries) > 0 {
+				summary["top_countries"] = topCounts(countries, 10)
+			}
+			if len(networks) > 0 {
+				summary["top_networks"] = topCounts(networks, 10)
+			}
+			result["summary"] = summary
+		}
+		if total > len(addresses) {
+			result["truncated"] = true
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createUserAgentParseTool creates the user-agent parsing tool
+func (p *UtilityProvider) createUserAgentParseTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "user_agent_parse",
+		Description: "Parse user-agent strings into browser, version, engine, OS, device type (desktop, mobile, tablet, tv, bot, client) and bot identity (search engines, AI crawlers, uptime monitors, Kubernetes and load balancer probes, curl and HTTP libraries). Several user agents are deduplicated and summarized",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"user_agents": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "User-agent strings, e.g. the user_agent field of access log lines (max 1000)"
+				}
+			},
+			"required": ["user_agents"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			UserAgents []string `json:"user_agents"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if len(args.UserAgents) == 0 {
+			return p.createErrorResult(fmt.Errorf("user_agents parameter is required")), nil
+		}
+		if len(args.UserAgents) > maxUserAgents {
+			return p.createErrorResult(fmt.Errorf("too many user agents (%d, max %d)", len(args.UserAgents), maxUserAgents)), nil
+		}
+
+		if len(args.UserAgents) == 1 {
+			return p.formatJSONResult(ParseUserAgent(args.UserAgents[0])), nil
+		}
+
+		var parsed []UserAgentInfo
+		index := map[string]int{}
+		for _, ua := range args.UserAgents {
+			key := strings.TrimSpace(ua)
+			if i, ok := index[key]; ok {
+				parsed[i].Count++
+				continue
+			}
+			info := ParseUserAgent(ua)
+			info.Count = 1
+			index[key] = len(parsed)
+			parsed = append(parsed, info)
+		}
+		sort.SliceStable(parsed, func(i, j int) bool { return parsed[i].Count > parsed[j].Count })
+
+		browsers := map[string]int{}
+		systems := map[string]int{}
+		devices := map[string]int{}
+		bots := map[string]int{}
+		for _, info := range parsed {
+			if info.Browser != "" {
+				browsers[info.Browser] += info.Count
+			}
+			if info.OS != "" {
+				systems[info.OS] += info.Count
+			}
+			devices[info.Device] += info.Count
+			if info.Bot {
+				bots[info.BotName] += info.Count
+			}
+		}
+
+		summary := map[string]interface{}{
+			"total":      len(args.UserAgents),
+			"unique":     len(parsed),
+			"browsers":   topCounts(browsers, 0),
+			"os":         topCounts(systems, 0),
+			"devices":    topCounts(devices, 0),
+			"bot_agents": topCounts(bots, 0),
+		}
+		return p.formatJSONResult(map[string]interface{}{
+			"summary":     summary,
+			"user_agents": parsed,
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,446 @@
+package utility
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+const (
+	// maxIPInputs bounds the number of addresses analyzed per call
+	maxIPInputs = 1000
+	// maxIPText bounds text scanned for addresses
+	maxIPText = 1 << 20
+)
+
+// specialRange is an IANA special-purpose block with the classification it implies
+type specialRange struct {
+	prefix   netip.Prefix
+	category string
+	note     string
+}
+
+// specialRanges lists the special-purpose blocks most often seen in access logs; more specific blocks come first
+var specialRanges = func() []specialRange {
+	ranges := []struct{ cidr, category, note string }{
+		{"0.0.0.0/8", "unspecified", "\"this network\""},
+		{"10.0.0.0/8", "private", "RFC 1918"},
+		{"100.64.0.0/10", "shared", "carrier-grade NAT (RFC 6598)"},
+		{"127.0.0.0/8", "loopback", ""},
+		{"169.254.0.0/16", "link_local", "also cloud metadata endpoints such as 169.254.169.254"},
+		{"172.16.0.0/12", "private", "RFC 1918"},
+		{"192.0.0.0/24", "reserved", "IETF protocol assignments"},
+		{"192.0.2.0/24", "documentation", "TEST-NET-1"},
+		{"192.88.99.0/24", "reserved", "deprecated 6to4 relay anycast"},
+		{"192.168.0.0/16", "private", "RFC 1918"},
+		{"198.18.0.0/15", "benchmarking", "RFC 2544"},
+		{"198.51.100.0/24", "documentation", "TEST-NET-2"},
+		{"203.0.113.0/24", "documentation", "TEST-NET-3"},
+		{"224.0.0.0/4", "multicast", ""},
+		{"255.255.255.255/32", "broadcast", ""},
+		{"240.0.0.0/4", "reserved", "future use"},
+		{"::/128", "unspecified", ""},
+		{"::1/128", "loopback", ""},
+		{"64:ff9b::/96", "nat64", "IPv4/IPv6 translation (RFC 6052)"},
+		{"64:ff9b:1::/48", "nat64", "local-use IPv4/IPv6 translation"},
+		{"100::/64", "reserved", "discard-only"},
+		{"2001::/32", "teredo", "Teredo tunnel"},
+		{"2001:db8::/32", "documentation", ""},
+		{"2002::/16", "6to4", "6to4 tunnel"},
+		{"3fff::/20", "documentation", ""},
+		{"fc00::/7", "private", "unique local address"},
+		{"fe80::/10", "link_local", ""},
+		{"ff00::/8", "multicast", ""},
+	}
+	special := make([]specialRange, 0, len(ranges))
+	for _, r := range ranges {
+		special = append(special, specialRange{prefix: netip.MustParsePrefix(r.cidr), category: r.category, note: r.note})
+	}
+	return special
+}()
+
+// IPInfo describes a single address
+type IPInfo struct {
+	Input    string      `json:"input"`
+	IP       string      `json:"ip"`
+	Version  int         `json:"version"`
+	Category string      `json:"category"`
+	Public   bool        `json:"public"`
+	Note     string      `json:"note,omitempty"`
+	Mapped   string      `json:"ipv4_mapped,omitempty"`
+	Count    int         `json:"count,omitempty"`
+	InCIDRs  []string    `json:"in_cidrs,omitempty"`
+	Geo      *GeoIPInfo  `json:"geo,omitempty"`
+	Network  *PrefixInfo `json:"network,omitempty"`
+}
+
+// PrefixInfo describes a CIDR block
+type PrefixInfo struct {
+	CIDR      string `json:"cidr"`
+	Network   string `json:"network"`
+	Netmask   string `json:"netmask,omitempty"`
+	First     string `json:"first"`
+	Last      string `json:"last"`
+	Broadcast string `json:"broadcast,omitempty"`
+	Size      string `json:"size"`
+	Category  string `json:"category"`
+	Public    bool   `json:"public"`
+	HostBits  bool   `json:"host_bits_set,omitempty"`
+}
+
+// ParseIP parses an address as it appears in logs and headers: plain, with a port, bracketed IPv6 or with a zone
+func ParseIP(input string) (netip.Addr, error) {
+	s := strings.TrimSpace(input)
+	s = strings.Trim(s, "\"'")
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return addr, nil
+	}
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr(), nil
+	}
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		if addr, err := netip.ParseAddr(s[1 : len(s)-1]); err == nil {
+			return addr, nil
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("%q is not an IP address", input)
+}
+
+// ClassifyIP returns the category of an address, whether it is publicly routable and a note on the range
+func ClassifyIP(addr netip.Addr) (category string, public bool, note string) {
+	addr = addr.WithZone("")
+	if addr.Is4In6() {
+		category, public, note = ClassifyIP(addr.Unmap())
+		if note != "" {
+			return category, public, "IPv4-mapped IPv6 address; " + note
+		}
+		return category, public, "IPv4-mapped IPv6 address"
+	}
+	for _, r := range specialRanges {
+		if r.prefix.Contains(addr) {
+			return r.category, false, r.note
+		}
+	}
+	if addr.Is6() && !netip.MustParsePrefix("2000::/3").Contains(addr) {
+		return "reserved", false, "outside the global unicast range 2000::/3"
+	}
+	return "public", true, ""
+}
+
+// DescribeIP classifies an address and checks it against the given prefixes
+func DescribeIP(input string, addr netip.Addr, cidrs []netip.Prefix) IPInfo {
+	info := IPInfo{Input: input, IP: addr.String(), Version: 4}
+	if addr.Is6() && !addr.Is4In6() {
+		info.Version = 6
+	}
+	info.Category, info.Public, info.Note = ClassifyIP(addr)
+	if addr.Is4In6() {
+		info.Mapped = addr.Unmap().String()
+	}
+	for _, prefix := range cidrs {
+		if prefix.Contains(addr.WithZone("")) || (addr.Is4In6() && prefix.Contains(addr.Unmap())) {
+			info.InCIDRs = append(info.InCIDRs, prefix.String())
+		}
+	}
+	return info
+}
+
+// ParseCIDRs parses prefixes such as 10.0.0.0/8; a bare address is treated as a single-host prefix
+func ParseCIDRs(values []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			addr, err := ParseIP(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q", value)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.WithZone(""), addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// DescribePrefix returns the range, size and mask of a CIDR block
+func DescribePrefix(value string) (*PrefixInfo, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+	}
+	masked := prefix.Masked()
+	first := masked.Addr()
+	last := lastAddr(masked)
+	hostBits := first.BitLen() - masked.Bits()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(hostBits))
+
+	info := &PrefixInfo{
+		CIDR:     masked.String(),
+		Network:  first.String(),
+		First:    first.String(),
+		Last:     last.String(),
+		Size:     size.String(),
+		HostBits: masked.Addr() != prefix.Addr(),
+	}
+	info.Category, info.Public, _ = ClassifyIP(first)
+	if first.Is4() {
+		info.Netmask = net.IP(net.CIDRMask(masked.Bits(), 32)).String()
+		// Network and broadcast addresses are not usable hosts except in /31 and /32
+		if hostBits >= 2 {
+			info.First = first.Next().String()
+			info.Last = last.Prev().String()
+			info.Broadcast = last.String()
+		}
+	}
+	return info, nil
+}
+
+// lastAddr returns the highest address in a masked prefix
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Addr().AsSlice()
+	bits := prefix.Bits()
+	for i := range bytes {
+		for b := 0; b < 8; b++ {
+			if i*8+b >= bits {
+				bytes[i] |= 0x80 >> b
+			}
+		}
+	}
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
+
+var (
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	// The first alternative matches IPv6 with an embedded dotted quad such as ::ffff:10.0.0.1
+	ipv6Pattern = regexp.MustCompile(`[0-9A-Fa-f:]*:(?:\d{1,3}\.){3}\d{1,3}|[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+)
+
+// ExtractIPs finds the addresses in free text such as log lines, with the number of times each occurs
+// in order of first appearance
+func ExtractIPs(text string) ([]string, map[string]int) {
+	type found struct {
+		start int
+		addr  netip.Addr
+	}
+	var matches []found
+	// IPv6 first, so dotted quads embedded in them (::ffff:10.0.0.1) are not counted twice
+	var v6Spans [][]int
+	for _, span := range ipv6Pattern.FindAllStringIndex(text, -1) {
+		candidate := text[span[0]:span[1]]
+		// Times such as 12:30:45 and MAC addresses do not parse as IPv6; bare "::" separators would
+		if strings.Trim(candidate, ":") == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(candidate); err == nil {
+			matches = append(matches, found{span[0], addr})
+			v6Spans = append(v6Spans, span)
+		}
+	}
+	for _, span := range ipv4Pattern.FindAllStringIndex(text, -1) {
+		inside := false
+		for _, v6 := range v6Spans {
+			if span[0] >= v6[0] && span[1] <= v6[1] {
+				inside = true
+				break
+			}
+		}
+		// Skip longer dotted numbers such as versions or OIDs
+		before := span[0] > 0 && text[span[0]-1] == '.'
+		after := span[1]+1 < len(text) && text[span[1]] == '.' && text[span[1]+1] >= '0' && text[span[1]+1] <= '9'
+		if inside || before || after {
+			continue
+		}
+		if addr, err := netip.ParseAddr(text[span[0]:span[1]]); err == nil {
+			matches = append(matches, found{span[0], addr})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+
+	counts := map[string]int{}
+	var order []string
+	for _, m := range matches {
+		key := m.addr.String()
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+	return order, counts
+}
+
+// GeoIPInfo is the location and network owner of an address from MaxMind databases
+type GeoIPInfo struct {
+	Country      string  `json:"country,omitempty"`
+	CountryCode  string  `json:"country_code,omitempty"`
+	Region       string  `json:"region,omitempty"`
+	City         string  `json:"city,omitempty"`
+	Continent    string  `json:"continent,omitempty"`
+	Latitude     float64 `json:"latitude,omitempty"`
+	Longitude    float64 `json:"longitude,omitempty"`
+	AccuracyKM   int     `json:"accuracy_radius_km,omitempty"`
+	TimeZone     string  `json:"time_zone,omitempty"`
+	ASN          uint    `json:"asn,omitempty"`
+	Organization string  `json:"organization,omitempty"`
+	Network      string  `json:"network,omitempty"`
+}
+
+// mmdbRecord covers the fields of the GeoIP2/GeoLite2 City, Country and ASN databases
+type mmdbRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Continent struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"continent"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude       float64 `maxminddb:"latitude"`
+		Longitude      float64 `maxminddb:"longitude"`
+		AccuracyRadius int     `maxminddb:"accuracy_radius"`
+		TimeZone       string  `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	ASN          uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
+
+// GeoIP looks addresses up in one or more local MaxMind databases, merging their fields
+type GeoIP struct {
+	readers []*maxminddb.Reader
+	names   []string
+}
+
+// OpenGeoIP opens the configured .mmdb files; databases that fail to open are reported and skipped
+func OpenGeoIP(paths []string) (*GeoIP, []error) {
+	geo := &GeoIP{}
+	var errs []error
+	for _, path := range paths {
+		reader, err := maxminddb.Open(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to open GeoIP database %s: %w", path, err))
+			continue
+		}
+		geo.readers = append(geo.readers, reader)
+		geo.names = append(geo.names, reader.Metadata.DatabaseType)
+	}
+	return geo, errs
+}
+
+// Available reports whether any database is loaded
+func (g *GeoIP) Available() bool {
+	return g != nil && len(g.readers) > 0
+}
+
+// Databases returns the database types loaded, e.g. GeoLite2-City
+func (g *GeoIP) Databases() []string {
+	if g == nil {
+		return nil
+	}
+	return g.names
+}
+
+// Lookup returns what the databases know about an address, or nil when none has a record
+func (g *GeoIP) Lookup(addr netip.Addr) (*GeoIPInfo, error) {
+	if !g.Available() {
+		return nil, nil
+	}
+	ip := net.IP(addr.Unmap().AsSlice())
+	var info GeoIPInfo
+	found := false
+	for _, reader := range g.readers {
+		var record mmdbRecord
+		network, ok, err := reader.LookupNetwork(ip, &record)
+		if err != nil {
+			return nil, fmt.Errorf("GeoIP lookup failed: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		found = true
+		if info.Network == "" || record.ASN != 0 {
+			info.Network = network.String()
+		}
+		if record.Country.ISOCode != "" {
+			info.CountryCode = record.Country.ISOCode
+			info.Country = record.Country.Names["en"]
+			info.Continent = record.Continent.Code
+		}
+		if name := record.City.Names["en"]; name != "" {
+			info.City = name
+		}
+		if len(record.Subdivisions) > 0 {
+			info.Region = record.Subdivisions[0].Names["en"]
+		}
+		if record.Location.Latitude != 0 || record.Location.Longitude != 0 {
+			info.Latitude, info.Longitude = record.Location.Latitude, record.Location.Longitude
+			info.AccuracyKM = record.Location.AccuracyRadius
+			info.TimeZone = record.Location.TimeZone
+		}
+		if record.ASN != 0 {
+			info.ASN, info.Organization = record.ASN, record.Organization
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return &info, nil
+}
+
+// Close closes the databases
+func (g *GeoIP) Close() error {
+	if g == nil {
+		return nil
+	}
+	var firstErr error
+	for _, reader := range g.readers {
+		if err := reader.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	g.readers = nil
+	return firstErr
+}
+
+// countEntry is one row of a frequency table
+type countEntry struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// topCounts returns the most frequent values, largest first
+func topCounts(counts map[string]int, limit int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for value, count := range counts {
+		entries = append(entries, countEntry{Value: value, Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
@@ -0,0 +1,263 @@
+package utility
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultMaxValidationErrors = 50
+	// swaggerResourceURL is the id the Swagger document is registered under so its $refs resolve
+	swaggerResourceURL = "mem://swagger.json"
+	schemaResourceURL  = "mem://schema.json"
+)
+
+// ValidationIssue is one schema violation located in the document
+type ValidationIssue struct {
+	// Path is a JSON pointer into the document, "" for the root
+	Path string `json:"path"`
+	// SchemaPath is the failing keyword's location in the schema
+	SchemaPath string `json:"schema_path"`
+	Message    string `json:"message"`
+}
+
+// parseDocument decodes JSON, falling back to YAML so config files can be validated too
+func parseDocument(data []byte) (interface{}, string, error) {
+	doc, jsonErr := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if jsonErr == nil {
+		return doc, "json", nil
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, "", fmt.Errorf("document is neither valid JSON (%v) nor YAML (%v)", jsonErr, err)
+	}
+	// Round-trip through JSON so numbers are json.Number like the JSON path produces
+	normalized, err := json.Marshal(normalizeYAML(raw))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to convert YAML document: %w", err)
+	}
+	doc, err = jsonschema.UnmarshalJSON(bytes.NewReader(normalized))
+	if err != nil {
+		return nil, "", err
+	}
+	return doc, "yaml", nil
+}
+
+// normalizeYAML converts yaml.v2 maps (map[interface{}]interface{}) into JSON-compatible maps
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// validateDocument validates doc against the schema at location (a resource URL with optional fragment)
+// after all resources have been added, returning at most maxErrors issues
+func validateDocument(resources map[string]interface{}, location string, draft *jsonschema.Draft, doc interface{}, maxErrors int) ([]ValidationIssue, int, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(draft)
+	compiler.AssertFormat()
+	// Only in-memory resources; never fetch remote $refs
+	compiler.UseLoader(noRemoteLoader{})
+	for url, resource := range resources {
+		if err := compiler.AddResource(url, resource); err != nil {
+			return nil, 0, fmt.Errorf("invalid schema: %w", err)
+		}
+	}
+	schema, err := compiler.Compile(location)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil, 0, nil
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return nil, 0, err
+	}
+
+	// The basic output is a flat list; wrapper units ("allOf failed" and the like) carry no
+	// message of their own beyond their causes, so only leaf errors are reported
+	var issues []ValidationIssue
+	seen := map[string]bool{}
+	for _, unit := range verr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		issue := ValidationIssue{
+			Path:       unit.InstanceLocation,
+			SchemaPath: unit.KeywordLocation,
+			Message:    unit.Error.String(),
+		}
+		key := issue.Path + "\x00" + issue.Message
+		if seen[key] || isWrapperMessage(issue.Message) {
+			continue
+		}
+		seen[key] = true
+		issues = append(issues, issue)
+	}
+	if len(issues) == 0 {
+		issues = append(issues, ValidationIssue{Message: verr.Error()})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	total := len(issues)
+	if len(issues) > maxErrors {
+		issues = issues[:maxErrors]
+	}
+	return issues, total, nil
+}
+
+// isWrapperMessage reports messages that only summarise nested failures
+func isWrapperMessage(message string) bool {
+	return strings.HasPrefix(message, "validation failed") || strings.HasPrefix(message, "doesn't validate with")
+}
+
+// noRemoteLoader refuses to load schemas that were not added as resources
+type noRemoteLoader struct{}
+
+func (noRemoteLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("remote $ref %q is not supported; inline the referenced schema", url)
+}
+
+// loadSwaggerDocument reads the configured Swagger/OpenAPI document from its file, or from its URL when absolute
+func (p *UtilityProvider) loadSwaggerDocument() (map[string]interface{}, error) {
+	if p.swagger == nil || (p.swagger.Filepath == "" && p.swagger.URL == "") {
+		return nil, fmt.Errorf("swagger is not configured (set swagger.filepath or swagger.url)")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case p.swagger.Filepath != "" && p.files != nil:
+		data, err = p.files.ReadFile(p.swagger.Filepath)
+	case strings.HasPrefix(p.swagger.URL, "http://") || strings.HasPrefix(p.swagger.URL, "https://"):
+		data, err = fetchURL(p.swagger.URL)
+	default:
+		return nil, fmt.Errorf("swagger document is not readable (swagger.filepath unset and swagger.url %q is not absolute)", p.swagger.URL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load swagger document: %w", err)
+	}
+	return parseSwaggerDocument(data)
+}
+
+// parseSwaggerDocument parses a Swagger/OpenAPI document in JSON or YAML
+func parseSwaggerDocument(data []byte) (map[string]interface{}, error) {
+	doc, _, err := parseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse swagger document: %w", err)
+	}
+	spec, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("swagger document is not an object")
+	}
+	convertNullable(spec)
+	return spec, nil
+}
+
+// swaggerDefinitionPointer finds a named schema under definitions (Swagger 2) or components/schemas (OpenAPI 3)
+func swaggerDefinitionPointer(spec map[string]interface{}, name string) (string, error) {
+	var available []string
+	for _, pointer := range []string{"definitions", "components/schemas"} {
+		section := lookupPointer(spec, pointer)
+		defs, ok := section.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := defs[name]; ok {
+			return "#/" + pointer + "/" + escapePointerToken(name), nil
+		}
+		for defName := range defs {
+			available = append(available, defName)
+		}
+	}
+	if len(available) == 0 {
+		return "", fmt.Errorf("swagger document has no definitions or components/schemas")
+	}
+	sort.Strings(available)
+	if len(available) > 50 {
+		available = append(available[:50], "...")
+	}
+	return "", fmt.Errorf("definition %q not found; available: %s", name, strings.Join(available, ", "))
+}
+
+func lookupPointer(doc map[string]interface{}, pointer string) interface{} {
+	var current interface{} = doc
+	for _, token := range strings.Split(pointer, "/") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[token]
+	}
+	return current
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// convertNullable rewrites OpenAPI's nullable / x-nullable flags into JSON Schema type unions
+func convertNullable(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		nullable, _ := v["nullable"].(bool)
+		xNullable, _ := v["x-nullable"].(bool)
+		if nullable || xNullable {
+			if t, ok := v["type"].(string); ok {
+				v["type"] = []interface{}{t, "null"}
+			}
+			if enum, ok := v["enum"].([]interface{}); ok {
+				v["enum"] = append(enum, nil)
+			}
+		}
+		for _, item := range v {
+			convertNullable(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			convertNullable(item)
+		}
+	}
+}
+
+// fetchURL downloads a small document over HTTP
+func fetchURL(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+}
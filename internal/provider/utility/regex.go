@@ -0,0 +1,281 @@
+package utility
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultRegexMatches = 100
+	maxRegexMatches     = 1000
+	// maxRegexInput bounds the text a pattern is run against
+	maxRegexInput = 1024 * 1024
+	// maxUnmatchedSamples is how many non-matching lines are echoed back in line mode
+	maxUnmatchedSamples = 10
+	maxGrokDepth        = 20
+)
+
+// grokPatterns is a subset of the standard Logstash grok library
+var grokPatterns = map[string]string{
+	"USERNAME":          `[a-zA-Z0-9._-]+`,
+	"USER":              `%{USERNAME}`,
+	"INT":               `(?:[+-]?(?:[0-9]+))`,
+	"BASE10NUM":         `(?:[+-]?(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+))`,
+	"NUMBER":            `(?:%{BASE10NUM})`,
+	"BASE16NUM":         `(?:0[xX]?[0-9a-fA-F]+)`,
+	"POSINT":            `\b(?:[1-9][0-9]*)\b`,
+	"NONNEGINT":         `\b(?:[0-9]+)\b`,
+	"WORD":              `\b\w+\b`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+	"UUID":              `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+	"IPV4":              `(?:(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])`,
+	"IPV6":              `(?:[0-9A-Fa-f]{0,4}:){2,7}[0-9A-Fa-f]{0,4}`,
+	"IP":                `(?:%{IPV6}|%{IPV4})`,
+	"HOSTNAME":          `\b(?:[0-9A-Za-z][0-9A-Za-z-]{0,62})(?:\.(?:[0-9A-Za-z][0-9A-Za-z-]{0,62}))*\.?\b`,
+	"IPORHOST":          `(?:%{IP}|%{HOSTNAME})`,
+	"HOSTPORT":          `%{IPORHOST}:%{POSINT}`,
+	"PATH":              `(?:/[^/\s?#]*)+`,
+	"URIPROTO":          `[A-Za-z][A-Za-z0-9+.-]+`,
+	"URIPATH":           `(?:/[A-Za-z0-9$.+!*'(){},~:;=@#%&_\-]*)+`,
+	"URIPARAM":          `\?[A-Za-z0-9$.+!*'|(){},~@#%&/=:;_?\-\[\]<>]*`,
+	"URIPATHPARAM":      `%{URIPATH}(?:%{URIPARAM})?`,
+	"URI":               `%{URIPROTO}://(?:%{USER}(?::[^@]*)?@)?(?:%{IPORHOST})?(?::%{POSINT})?(?:%{URIPATHPARAM})?`,
+	"MONTH":             `\b(?:[Jj]an(?:uary)?|[Ff]eb(?:ruary)?|[Mm]ar(?:ch)?|[Aa]pr(?:il)?|[Mm]ay|[Jj]une?|[Jj]uly?|[Aa]ug(?:ust)?|[Ss]ep(?:tember)?|[Oo]ct(?:ober)?|[Nn]ov(?:ember)?|[Dd]ec(?:ember)?)\b`,
+	"MONTHNUM":          `(?:0?[1-9]|1[0-2])`,
+	"MONTHDAY":          `(?:(?:0[1-9])|(?:[12][0-9])|(?:3[01])|[1-9])`,
+	"DAY":               `(?:Mon(?:day)?|Tue(?:sday)?|Wed(?:nesday)?|Thu(?:rsday)?|Fri(?:day)?|Sat(?:urday)?|Sun(?:day)?)`,
+	"YEAR":              `(?:\d\d){1,2}`,
+	"HOUR":              `(?:2[0123]|[01]?[0-9])`,
+	"MINUTE":            `(?:[0-5][0-9])`,
+	"SECOND":            `(?:(?:[0-5]?[0-9]|60)(?:[:.,][0-9]+)?)`,
+	"TIME":              `%{HOUR}:%{MINUTE}(?::%{SECOND})`,
+	"ISO8601_TIMEZONE":  `(?:Z|[+-]%{HOUR}(?::?%{MINUTE}))`,
+	"TIMESTAMP_ISO8601": `%{YEAR}-%{MONTHNUM}-%{MONTHDAY}[T ]%{HOUR}:?%{MINUTE}(?::?%{SECOND})?%{ISO8601_TIMEZONE}?`,
+	"HTTPDATE":          `%{MONTHDAY}/%{MONTH}/%{YEAR}:%{TIME} %{INT}`,
+	"SYSLOGTIMESTAMP":   `%{MONTH} +%{MONTHDAY} %{TIME}`,
+	"LOGLEVEL":          `(?:[Aa]lert|ALERT|[Tt]race|TRACE|[Dd]ebug|DEBUG|[Nn]otice|NOTICE|[Ii]nfo(?:rmation)?|INFO(?:RMATION)?|[Ww]arn(?:ing)?|WARN(?:ING)?|[Ee]rr(?:or)?|ERR(?:OR)?|[Cc]rit(?:ical)?|CRIT(?:ICAL)?|[Ff]atal|FATAL|[Ss]evere|SEVERE|EMERG(?:ENCY)?|[Ee]merg(?:ency)?)`,
+	"HTTPMETHOD":        `(?:GET|HEAD|POST|PUT|DELETE|CONNECT|OPTIONS|TRACE|PATCH)`,
+	"COMMONAPACHELOG":   `%{IPORHOST:clientip} %{USER:ident} %{USER:auth} \[%{HTTPDATE:timestamp}\] "(?:%{WORD:verb} %{NOTSPACE:request}(?: HTTP/%{NUMBER:httpversion})?|%{DATA:rawrequest})" %{NUMBER:response} (?:%{NUMBER:bytes}|-)`,
+	"COMBINEDAPACHELOG": `%{COMMONAPACHELOG} %{QUOTEDSTRING:referrer} %{QUOTEDSTRING:agent}`,
+}
+
+var (
+	grokReference = regexp.MustCompile(`%\{(\w+)(?::([\w.@\[\]-]+))?(?::\w+)?\}`)
+	invalidGroup  = regexp.MustCompile(`\W`)
+)
+
+// ExpandGrok rewrites %{PATTERN} and %{PATTERN:field} references into a Go regular expression,
+// returning the expression and a map from Go group names back to the grok field names
+func ExpandGrok(pattern string, custom map[string]string) (string, map[string]string, error) {
+	fields := map[string]string{}
+	expanded, err := expandGrok(pattern, custom, fields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return expanded, fields, nil
+}
+
+func expandGrok(pattern string, custom map[string]string, fields map[string]string, depth int) (string, error) {
+	if depth > maxGrokDepth {
+		return "", fmt.Errorf("grok patterns nest too deeply (recursive definition?)")
+	}
+
+	var expandErr error
+	expanded := grokReference.ReplaceAllStringFunc(pattern, func(ref string) string {
+		if expandErr != nil {
+			return ""
+		}
+		parts := grokReference.FindStringSubmatch(ref)
+		name, field := parts[1], parts[2]
+		definition, ok := custom[name]
+		if !ok {
+			definition, ok = grokPatterns[name]
+		}
+		if !ok {
+			expandErr = fmt.Errorf("unknown grok pattern %%{%s}", name)
+			return ""
+		}
+		inner, err := expandGrok(definition, custom, fields, depth+1)
+		if err != nil {
+			expandErr = err
+			return ""
+		}
+		if field == "" {
+			return "(?:" + inner + ")"
+		}
+		// Go group names only allow word characters, so fields like "http.status" are renamed
+		group := invalidGroup.ReplaceAllString(field, "_")
+		if _, dup := fields[group]; dup {
+			for i := 2; ; i++ {
+				if _, taken := fields[fmt.Sprintf("%s_%d", group, i)]; !taken {
+					group = fmt.Sprintf("%s_%d", group, i)
+					break
+				}
+			}
+		}
+		fields[group] = field
+		return "(?P<" + group + ">" + inner + ")"
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return expanded, nil
+}
+
+// RegexGroup is one capture group of a match
+type RegexGroup struct {
+	Index int    `json:"index"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	// Matched is false for optional groups that did not participate
+	Matched bool `json:"matched"`
+}
+
+// RegexMatch is one match with its position; Line and Column are 1-based, Start/End are byte offsets
+type RegexMatch struct {
+	Line   int          `json:"line,omitempty"`
+	Column int          `json:"column"`
+	Start  int          `json:"start"`
+	End    int          `json:"end"`
+	Text   string       `json:"text"`
+	Groups []RegexGroup `json:"groups,omitempty"`
+}
+
+// RegexResult summarises running a pattern against text
+type RegexResult struct {
+	Pattern        string       `json:"pattern"`
+	Expanded       string       `json:"expanded_pattern,omitempty"`
+	GroupNames     []string     `json:"group_names,omitempty"`
+	Matches        []RegexMatch `json:"matches"`
+	MatchCount     int          `json:"match_count"`
+	LinesTotal     int          `json:"lines_total,omitempty"`
+	LinesMatched   int          `json:"lines_matched,omitempty"`
+	UnmatchedLines []string     `json:"unmatched_samples,omitempty"`
+	Truncated      bool         `json:"truncated"`
+	TimedOut       bool         `json:"timed_out,omitempty"`
+	Replaced       *string      `json:"replaced,omitempty"`
+}
+
+// RegexOptions controls how a pattern is evaluated
+type RegexOptions struct {
+	// PerLine matches each line separately (log parsing); otherwise the whole text is one input
+	PerLine    bool
+	MaxMatches int
+	// Fields maps group names to display names (grok fields that were renamed)
+	Fields  map[string]string
+	Replace *string
+	// FirstLine is the line number of the first input line (for file excerpts), default 1
+	FirstLine int
+}
+
+// runRegex evaluates re against text, stopping at MaxMatches or when ctx expires
+func runRegex(ctx context.Context, re *regexp.Regexp, text string, opts RegexOptions) *RegexResult {
+	result := &RegexResult{Matches: []RegexMatch{}}
+	if opts.FirstLine <= 0 {
+		opts.FirstLine = 1
+	}
+	names := re.SubexpNames()
+	for _, name := range names[1:] {
+		if name != "" {
+			result.GroupNames = append(result.GroupNames, displayGroupName(name, opts.Fields))
+		}
+	}
+
+	collect := func(input string, base, line int) bool {
+		for _, loc := range re.FindAllStringSubmatchIndex(input, -1) {
+			if len(result.Matches) >= opts.MaxMatches {
+				result.Truncated = true
+				return false
+			}
+			result.Matches = append(result.Matches, buildMatch(input, loc, names, base, line, opts.Fields))
+		}
+		return true
+	}
+
+	if !opts.PerLine {
+		collect(text, 0, 0)
+		for i := range result.Matches {
+			result.Matches[i].Line += opts.FirstLine - 1
+		}
+	} else {
+		offset := 0
+		for i, line := range strings.SplitAfter(text, "\n") {
+			if line == "" {
+				continue
+			}
+			if ctx.Err() != nil {
+				result.TimedOut = true
+				break
+			}
+			trimmed := strings.TrimRight(line, "\r\n")
+			result.LinesTotal++
+			before := len(result.Matches)
+			more := collect(trimmed, offset, i+opts.FirstLine)
+			if len(result.Matches) > before {
+				result.LinesMatched++
+			} else if !re.MatchString(trimmed) && len(result.UnmatchedLines) < maxUnmatchedSamples {
+				result.UnmatchedLines = append(result.UnmatchedLines, fmt.Sprintf("%d: %s", i+opts.FirstLine, snippetLine(trimmed)))
+			}
+			offset += len(line)
+			if !more {
+				break
+			}
+		}
+	}
+	result.MatchCount = len(result.Matches)
+
+	if opts.Replace != nil {
+		replaced := re.ReplaceAllString(text, *opts.Replace)
+		result.Replaced = &replaced
+	}
+	return result
+}
+
+func buildMatch(input string, loc []int, names []string, base, line int, fields map[string]string) RegexMatch {
+	match := RegexMatch{
+		Line:   line,
+		Column: utf8.RuneCountInString(input[:loc[0]]) + 1,
+		Start:  base + loc[0],
+		End:    base + loc[1],
+		Text:   input[loc[0]:loc[1]],
+	}
+	if line == 0 {
+		// Whole-text mode: report the line the match starts on
+		match.Line = strings.Count(input[:loc[0]], "\n") + 1
+		match.Column = utf8.RuneCountInString(input[strings.LastIndex(input[:loc[0]], "\n")+1:loc[0]]) + 1
+	}
+	for i := 1; i < len(names); i++ {
+		group := RegexGroup{Index: i, Name: displayGroupName(names[i], fields), Start: -1, End: -1}
+		if start, end := loc[2*i], loc[2*i+1]; start >= 0 {
+			group.Value = input[start:end]
+			group.Start = base + start
+			group.End = base + end
+			group.Matched = true
+		}
+		match.Groups = append(match.Groups, group)
+	}
+	return match
+}
+
+func displayGroupName(name string, fields map[string]string) string {
+	if field, ok := fields[name]; ok {
+		return field
+	}
+	return name
+}
+
+func snippetLine(line string) string {
+	if len(line) > 200 {
+		return line[:200] + "..."
+	}
+	return line
+}
@@ -0,0 +1,250 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+const (
+	defaultRegexTimeout = 2 * time.Second
+	maxRegexTimeout     = 10 * time.Second
+	maxRegexPattern     = 8192
+)
+
+// createRegexTestTool creates the regex/grok pattern testing tool
+func (p *UtilityProvider) createRegexTestTool() entity.ToolDefinition {
+	grokNames := make([]string, 0, len(grokPatterns))
+	for name := range grokPatterns {
+		grokNames = append(grokNames, name)
+	}
+	sort.Strings(grokNames)
+
+	tool := &mcp.Tool{
+		Name:        "regex_test",
+		Description: "Test a regular expression (Go RE2 syntax) or grok pattern against sample text or a file excerpt and return every match with its line, column, byte offsets and capture groups. Line mode also reports which lines did not match, which helps iterate on log parsing patterns",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"pattern": {
+					"type": "string",
+					"description": "Regular expression, or a grok pattern such as %{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} %{GREEDYDATA:msg}. Grok patterns: ` + strings.Join(grokNames, ", ") + `"
+				},
+				"text": {
+					"type": "string",
+					"description": "Sample text to test against"
+				},
+				"path": {
+					"type": "string",
+					"description": "Test against this file instead (via the file provider)"
+				},
+				"start_line": {
+					"type": "integer",
+					"description": "First line of the file excerpt (1-based)",
+					"default": 1
+				},
+				"end_line": {
+					"type": "integer",
+					"description": "Last line of the file excerpt (inclusive, default: end of file)"
+				},
+				"syntax": {
+					"type": "string",
+					"description": "Pattern syntax; auto treats patterns containing %{...} as grok",
+					"enum": ["auto", "regex", "grok"],
+					"default": "auto"
+				},
+				"custom_patterns": {
+					"type": "object",
+					"description": "Extra grok definitions, e.g. {\"REQID\": \"req-[0-9a-f]{8}\"}",
+					"additionalProperties": {"type": "string"}
+				},
+				"flags": {
+					"type": "string",
+					"description": "Flags: i (case-insensitive), m (^/$ match at line breaks), s (. matches newline), U (ungreedy)"
+				},
+				"mode": {
+					"type": "string",
+					"description": "lines matches each line on its own (like a log parser); text matches across the whole input",
+					"enum": ["lines", "text"],
+					"default": "lines"
+				},
+				"replace": {
+					"type": "string",
+					"description": "Optional replacement template (e.g. \"$1\" or \"${level}\") to preview ReplaceAll on the input"
+				},
+				"max_matches": {
+					"type": "integer",
+					"description": "Maximum number of matches to return (max 1000)",
+					"default": 100
+				},
+				"timeout_ms": {
+					"type": "integer",
+					"description": "Execution limit in milliseconds (max 10000)",
+					"default": 2000
+				}
+			},
+			"required": ["pattern"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Pattern        string            `json:"pattern"`
+			Text           *string           `json:"text,omitempty"`
+			Path           string            `json:"path,omitempty"`
+			StartLine      int               `json:"start_line,omitempty"`
+			EndLine        int               `json:"end_line,omitempty"`
+			Syntax         string            `json:"syntax,omitempty"`
+			CustomPatterns map[string]string `json:"custom_patterns,omitempty"`
+			Flags          string            `json:"flags,omitempty"`
+			Mode           string            `json:"mode,omitempty"`
+			Replace        *string           `json:"replace,omitempty"`
+			MaxMatches     int               `json:"max_matches,omitempty"`
+			TimeoutMs      int               `json:"timeout_ms,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Pattern == "" {
+			return p.createErrorResult(fmt.Errorf("pattern parameter is required")), nil
+		}
+		if len(args.Pattern) > maxRegexPattern {
+			return p.createErrorResult(fmt.Errorf("pattern is too long (%d bytes, max %d)", len(args.Pattern), maxRegexPattern)), nil
+		}
+		if args.MaxMatches <= 0 {
+			args.MaxMatches = defaultRegexMatches
+		}
+		if args.MaxMatches > maxRegexMatches {
+			args.MaxMatches = maxRegexMatches
+		}
+		timeout := defaultRegexTimeout
+		if args.TimeoutMs > 0 {
+			timeout = min(time.Duration(args.TimeoutMs)*time.Millisecond, maxRegexTimeout)
+		}
+
+		var perLine bool
+		switch args.Mode {
+		case "", "lines":
+			perLine = true
+		case "text":
+		default:
+			return p.createErrorResult(fmt.Errorf("unsupported mode: %s (use lines or text)", args.Mode)), nil
+		}
+
+		text, source, firstLine, err := p.loadRegexInput(args.Text, args.Path, args.StartLine, args.EndLine)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		expression := args.Pattern
+		var fields map[string]string
+		grok := args.Syntax == "grok" || ((args.Syntax == "" || args.Syntax == "auto") && strings.Contains(args.Pattern, "%{"))
+		switch args.Syntax {
+		case "", "auto", "regex", "grok":
+		default:
+			return p.createErrorResult(fmt.Errorf("unsupported syntax: %s (use auto, regex or grok)", args.Syntax)), nil
+		}
+		if grok {
+			expression, fields, err = ExpandGrok(args.Pattern, args.CustomPatterns)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+		}
+
+		flags, err := regexFlags(args.Flags)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		re, err := regexp.Compile(flags + expression)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid pattern: %w", err)), nil
+		}
+
+		// RE2 runs in linear time, so the limits only bound input size, match count and wall time
+		runCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		result := runRegex(runCtx, re, text, RegexOptions{
+			PerLine:    perLine,
+			MaxMatches: args.MaxMatches,
+			Fields:     fields,
+			Replace:    args.Replace,
+			FirstLine:  firstLine,
+		})
+		result.Pattern = args.Pattern
+		if grok {
+			result.Expanded = flags + expression
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"source": source,
+			"result": result,
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// loadRegexInput returns the text to test, either given inline or read from a file excerpt
+// along with the line number the text starts at
+func (p *UtilityProvider) loadRegexInput(text *string, path string, startLine, endLine int) (string, string, int, error) {
+	if (text != nil) == (path != "") {
+		return "", "", 0, fmt.Errorf("provide exactly one of text or path")
+	}
+
+	if text != nil {
+		if len(*text) > maxRegexInput {
+			return "", "", 0, fmt.Errorf("text is too large (%d bytes, max %d)", len(*text), maxRegexInput)
+		}
+		return *text, "inline", 1, nil
+	}
+
+	if p.files == nil {
+		return "", "", 0, fmt.Errorf("reading files is not available")
+	}
+	content, err := p.files.ReadFile(path)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	if startLine <= 0 {
+		startLine = 1
+	}
+	if endLine > 0 && endLine < startLine {
+		return "", "", 0, fmt.Errorf("end_line (%d) is before start_line (%d)", endLine, startLine)
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if startLine > len(lines) {
+		return "", "", 0, fmt.Errorf("start_line %d is past the end of %s (%d lines)", startLine, path, len(lines))
+	}
+	if endLine <= 0 || endLine > len(lines) {
+		endLine = len(lines)
+	}
+	excerpt := strings.Join(lines[startLine-1:endLine], "")
+	if len(excerpt) > maxRegexInput {
+		return "", "", 0, fmt.Errorf("excerpt is too large (%d bytes, max %d); narrow start_line/end_line", len(excerpt), maxRegexInput)
+	}
+	return excerpt, fmt.Sprintf("%s (lines %d-%d)", path, startLine, endLine), startLine, nil
+}
+
+// regexFlags converts a flag string such as "im" into a Go inline flag group
+func regexFlags(flags string) (string, error) {
+	if flags == "" {
+		return "", nil
+	}
+	for _, flag := range flags {
+		if !strings.ContainsRune("imsU", flag) {
+			return "", fmt.Errorf("unsupported flag %q (use i, m, s or U)", flag)
+		}
+	}
+	return "(?" + flags + ")", nil
+}
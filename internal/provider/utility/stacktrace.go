@@ -0,0 +1,639 @@
+package utility
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxStacktraceInput bounds the trace text or event JSON accepted
+	maxStacktraceInput = 1 << 20
+	// maxStackTraces bounds the traces (goroutines, chained exceptions) returned
+	maxStackTraces   = 20
+	defaultMaxFrames = 50
+)
+
+// StackFrame is one normalized frame
+type StackFrame struct {
+	Function string          `json:"function,omitempty"`
+	Module   string          `json:"module,omitempty"` // Go package, Java class or JS/Python module
+	File     string          `json:"file,omitempty"`
+	Line     int             `json:"line,omitempty"`
+	Column   int             `json:"column,omitempty"`
+	InApp    bool            `json:"in_app"`
+	Code     string          `json:"code,omitempty"` // source line as printed in the trace
+	Source   *SourceLocation `json:"source,omitempty"`
+}
+
+// StackTrace is one exception, panic or goroutine with its frames innermost (where it was raised) first
+type StackTrace struct {
+	Type          string       `json:"type,omitempty"`
+	Message       string       `json:"message,omitempty"`
+	Thread        string       `json:"thread,omitempty"`
+	Relation      string       `json:"relation,omitempty"` // caused_by, during_handling or suppressed, relative to the previous trace
+	Frames        []StackFrame `json:"frames"`
+	OmittedFrames int          `json:"omitted_frames,omitempty"`
+}
+
+// ParsedStacktrace is the result of parsing a trace or Sentry event
+type ParsedStacktrace struct {
+	Language string       `json:"language"`
+	Traces   []StackTrace `json:"traces"`
+	Omitted  int          `json:"omitted_traces,omitempty"`
+}
+
+var (
+	goGoroutinePattern = regexp.MustCompile(`^goroutine (\d+) \[([^\]]*)\]:?$`)
+	goLocationPattern  = regexp.MustCompile(`^\s+(.+\.(?:go|s)):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	goCreatedByPattern = regexp.MustCompile(`^created by (\S+?)(?: in goroutine (\d+))?$`)
+
+	javaFramePattern  = regexp.MustCompile(`^\s*at\s+(?:[\w.-]+(?:@[^/]*)?/)?([\w$.<>]+)\.([\w$<>-]+)\(([^)]*)\)`)
+	javaHeaderPattern = regexp.MustCompile(`^(?:Exception in thread "(.*?)" |Caused by: |\s*Suppressed: )?((?:[A-Za-z_$][\w$]*\.)+[A-Za-z_$][\w$]*)(?::\s?(.*))?$`)
+	javaMorePattern   = regexp.MustCompile(`^\s*\.\.\. (\d+) (?:more|common frames omitted)`)
+
+	pythonFramePattern     = regexp.MustCompile(`^\s*File "(.+)", line (\d+)(?:, in (.+))?$`)
+	pythonExceptionPattern = regexp.MustCompile(`^([A-Za-z_][\w.]*)(?::\s?(.*))?$`)
+
+	jsFramePattern    = regexp.MustCompile(`^\s*at (?:async )?(?:(.+?) \()?((?:[a-z][\w+.-]*:/*)?[^()]+?):(\d+):(\d+)\)?$`)
+	jsNativePattern   = regexp.MustCompile(`^\s*at (?:async )?(.+?) \((native|<anonymous>|index \d+)\)$`)
+	jsGeckoPattern    = regexp.MustCompile(`^([^@\s]*)@(.+?):(\d+):(\d+)$`)
+	jsExceptionHeader = regexp.MustCompile(`^(?:Uncaught )?([A-Za-z_$][\w$.]*(?:Error|Exception)|Error)(?: \[[\w-]+\])?:?\s?(.*)$`)
+
+	javaLocationPattern = regexp.MustCompile(`\((?:[\w$]+\.(?:java|kt|scala|groovy|clj):\d+|Native Method|Unknown Source)\)`)
+	pythonStdlibPattern = regexp.MustCompile(`/lib/python\d`)
+)
+
+// DetectStacktraceLanguage guesses the language of a trace from its frame syntax
+func DetectStacktraceLanguage(text string) string {
+	trimmed := strings.TrimSpace(text)
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		return "sentry"
+	case strings.Contains(text, "Traceback (most recent call last)") || pythonFramePattern.MatchString(firstMatchingLine(text, `File "`)):
+		return "python"
+	case strings.Contains(text, "goroutine ") && goLocationPattern.MatchString(firstMatchingLine(text, ".go:")):
+		return "go"
+	case javaFramePattern.MatchString(firstMatchingLine(text, "at ")) && javaLocationPattern.MatchString(text):
+		return "java"
+	case jsFramePattern.MatchString(firstMatchingLine(text, "at ")) || jsGeckoPattern.MatchString(firstMatchingLine(text, "@")):
+		return "javascript"
+	case goLocationPattern.MatchString(firstMatchingLine(text, ".go:")):
+		return "go"
+	}
+	return ""
+}
+
+func firstMatchingLine(text, substr string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, substr) {
+			return strings.TrimRight(line, "\r")
+		}
+	}
+	return ""
+}
+
+// ParseStacktrace parses a Go, Java, Python or JavaScript trace, or a Sentry event JSON; language may be empty to detect it
+func ParseStacktrace(text, language string) (*ParsedStacktrace, error) {
+	if language == "" || language == "auto" {
+		language = DetectStacktraceLanguage(text)
+		if language == "" {
+			return nil, fmt.Errorf("no Go, Java, Python or JavaScript stack frames recognized; set language explicitly")
+		}
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var traces []StackTrace
+	var err error
+	switch language {
+	case "go":
+		traces = parseGoTrace(text)
+	case "java":
+		traces = parseJavaTrace(text)
+	case "python":
+		traces = parsePythonTrace(text)
+	case "javascript", "js", "node":
+		language = "javascript"
+		traces = parseJSTrace(text)
+	case "sentry":
+		traces, language, err = parseSentryEvent(text)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported language %q (use go, java, python, javascript or sentry)", language)
+	}
+
+	frames := 0
+	for _, trace := range traces {
+		frames += len(trace.Frames)
+	}
+	if frames == 0 {
+		return nil, fmt.Errorf("no %s stack frames found", language)
+	}
+
+	result := &ParsedStacktrace{Language: language, Traces: traces}
+	if len(result.Traces) > maxStackTraces {
+		result.Omitted = len(result.Traces) - maxStackTraces
+		result.Traces = result.Traces[:maxStackTraces]
+	}
+	return result, nil
+}
+
+// parseGoTrace parses panics and goroutine dumps; the panic message belongs to the first goroutine
+func parseGoTrace(text string) []StackTrace {
+	var traces []StackTrace
+	var panicMessage []string
+	var current *StackTrace
+	var pending *StackFrame
+
+	flush := func() {
+		if current != nil {
+			traces = append(traces, *current)
+		}
+		current = nil
+	}
+	lines := strings.Split(text, "\n")
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "panic: ") || strings.HasPrefix(line, "fatal error: "):
+			panicMessage = append(panicMessage, line)
+		case strings.HasPrefix(line, "[signal "):
+			panicMessage = append(panicMessage, strings.Trim(line, "[]"))
+		case goGoroutinePattern.MatchString(line):
+			flush()
+			m := goGoroutinePattern.FindStringSubmatch(line)
+			current = &StackTrace{Type: "goroutine", Thread: "goroutine " + m[1] + " [" + m[2] + "]"}
+		case goLocationPattern.MatchString(line) && pending != nil:
+			m := goLocationPattern.FindStringSubmatch(line)
+			pending.File = m[1]
+			pending.Line, _ = strconv.Atoi(m[2])
+			pending.InApp = goInApp(pending.Module, pending.File)
+			if current == nil {
+				current = &StackTrace{Type: "stack"}
+			}
+			current.Frames = append(current.Frames, *pending)
+			pending = nil
+		case strings.HasPrefix(line, "created by "):
+			m := goCreatedByPattern.FindStringSubmatch(strings.TrimSpace(line))
+			if m != nil {
+				module, function := splitGoFunction(m[1])
+				pending = &StackFrame{Function: "created by " + function, Module: module}
+			}
+		case line != "" && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ")") && strings.Contains(line, "("):
+			name := line[:strings.LastIndex(line, "(")]
+			if strings.HasSuffix(name, "...") {
+				name = strings.TrimSuffix(name, "...")
+			}
+			module, function := splitGoFunction(name)
+			pending = &StackFrame{Function: function, Module: module}
+		}
+	}
+	flush()
+
+	if len(traces) > 0 && len(panicMessage) > 0 {
+		first := &traces[0]
+		first.Type = "panic"
+		first.Message = strings.Join(panicMessage, "; ")
+		if strings.HasPrefix(panicMessage[0], "fatal error: ") {
+			first.Type = "fatal error"
+		}
+	}
+	return traces
+}
+
+// splitGoFunction splits github.com/org/repo/pkg.(*Type).Method into its package path and function
+func splitGoFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+1+dot+1:]
+}
+
+func goInApp(module, file string) bool {
+	if strings.Contains(file, "/pkg/mod/") || strings.Contains(file, "/vendor/") || strings.Contains(file, "/go/src/") || strings.Contains(file, "/libexec/src/") {
+		return false
+	}
+	// Standard library packages have no dot in their first path element
+	first, _, _ := strings.Cut(module, "/")
+	return module == "main" || strings.Contains(first, ".")
+}
+
+// parseJavaTrace parses JVM traces with Caused by and Suppressed sections
+func parseJavaTrace(text string) []StackTrace {
+	var traces []StackTrace
+	var current *StackTrace
+	flush := func() {
+		if current != nil {
+			traces = append(traces, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if m := javaFramePattern.FindStringSubmatch(line); m != nil {
+			if current == nil {
+				current = &StackTrace{}
+			}
+			frame := StackFrame{Module: m[1], Function: m[2]}
+			location := m[3]
+			if file, lineNo, ok := strings.Cut(location, ":"); ok {
+				frame.Line, _ = strconv.Atoi(lineNo)
+				location = file
+			}
+			if location != "Native Method" && location != "Unknown Source" && location != "" {
+				frame.File = javaSourcePath(frame.Module, location)
+			}
+			frame.InApp = javaInApp(frame.Module)
+			current.Frames = append(current.Frames, frame)
+			continue
+		}
+		if m := javaMorePattern.FindStringSubmatch(line); m != nil && current != nil {
+			n, _ := strconv.Atoi(m[1])
+			current.OmittedFrames += n
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if m := javaHeaderPattern.FindStringSubmatch(trimmed); m != nil && trimmed != "" {
+			flush()
+			current = &StackTrace{Type: m[2], Message: m[3], Thread: m[1]}
+			switch {
+			case strings.HasPrefix(trimmed, "Caused by:"):
+				current.Relation = "caused_by"
+			case strings.HasPrefix(trimmed, "Suppressed:"):
+				current.Relation = "suppressed"
+			}
+			continue
+		}
+		// Multi-line messages continue the header
+		if current != nil && len(current.Frames) == 0 && trimmed != "" {
+			current.Message += "\n" + trimmed
+		}
+	}
+	flush()
+	return traces
+}
+
+// javaSourcePath derives the source path from the class's package: com.acme.Foo$Inner + Foo.java -> com/acme/Foo.java
+func javaSourcePath(class, file string) string {
+	dot := strings.LastIndex(class, ".")
+	if dot < 0 {
+		return file
+	}
+	return strings.ReplaceAll(class[:dot], ".", "/") + "/" + file
+}
+
+var javaLibraryPrefixes = []string{
+	"java.", "javax.", "jakarta.", "jdk.", "sun.", "com.sun.", "kotlin.", "kotlinx.", "scala.", "groovy.",
+	"org.springframework.", "org.apache.", "org.hibernate.", "org.eclipse.", "org.junit.", "io.netty.",
+	"io.grpc.", "io.micrometer.", "reactor.", "com.fasterxml.", "com.google.", "com.zaxxer.", "okhttp3.", "retrofit2.",
+}
+
+func javaInApp(class string) bool {
+	for _, prefix := range javaLibraryPrefixes {
+		if strings.HasPrefix(class, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePythonTrace parses tracebacks including chained exceptions; Python prints frames oldest first
+func parsePythonTrace(text string) []StackTrace {
+	var traces []StackTrace
+	var current *StackTrace
+	relation := ""
+	inTraceback := false
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		reverseFrames(current.Frames)
+		traces = append(traces, *current)
+		current = nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Traceback (most recent call last)"):
+			finish()
+			current = &StackTrace{Relation: relation}
+			relation = ""
+			inTraceback = true
+		case strings.HasPrefix(trimmed, "During handling of the above exception"):
+			relation = "during_handling"
+		case strings.HasPrefix(trimmed, "The above exception was the direct cause"):
+			relation = "caused_by"
+		case pythonFramePattern.MatchString(line) && current != nil:
+			m := pythonFramePattern.FindStringSubmatch(line)
+			frame := StackFrame{File: m[1], Function: m[3]}
+			frame.Line, _ = strconv.Atoi(m[2])
+			frame.InApp = pythonInApp(frame.File)
+			// The next, more indented line is the source line unless it is another frame
+			if i+1 < len(lines) && !pythonFramePattern.MatchString(lines[i+1]) && strings.HasPrefix(lines[i+1], "    ") {
+				frame.Code = strings.TrimSpace(lines[i+1])
+				i++
+				// Skip the ^^^^ markers of Python 3.11+
+				for i+1 < len(lines) && strings.Trim(strings.TrimSpace(lines[i+1]), "^~") == "" && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+			}
+			current.Frames = append(current.Frames, frame)
+		case inTraceback && current != nil && trimmed != "" && !strings.HasPrefix(line, " ") && current.Type == "":
+			if m := pythonExceptionPattern.FindStringSubmatch(trimmed); m != nil {
+				current.Type, current.Message = m[1], m[2]
+			} else {
+				current.Message = trimmed
+			}
+			inTraceback = false
+		case current != nil && current.Type != "" && !inTraceback && trimmed != "" && !strings.HasPrefix(trimmed, "Traceback"):
+			// Exception messages may span several lines
+			current.Message += "\n" + trimmed
+		}
+	}
+	finish()
+
+	// Python prints the original exception first; put the one that surfaced first like other languages
+	for i, j := 0, len(traces)-1; i < j; i, j = i+1, j-1 {
+		traces[i], traces[j] = traces[j], traces[i]
+	}
+	// Relations were recorded on the later exception; after reversing they describe the next trace
+	for i := 0; i < len(traces)-1; i++ {
+		traces[i+1].Relation, traces[i].Relation = traces[i].Relation, ""
+	}
+	if len(traces) > 0 {
+		traces[0].Relation = ""
+	}
+	return traces
+}
+
+func pythonInApp(file string) bool {
+	return !strings.Contains(file, "site-packages") && !strings.Contains(file, "dist-packages") &&
+		!strings.HasPrefix(file, "<") && !pythonStdlibPattern.MatchString(file)
+}
+
+// parseJSTrace parses V8 (Node, Chrome) and Gecko/WebKit (Firefox, Safari) traces
+func parseJSTrace(text string) []StackTrace {
+	var traces []StackTrace
+	var current *StackTrace
+	var header []string
+
+	flush := func() {
+		if current != nil && len(current.Frames) > 0 {
+			traces = append(traces, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		var frame *StackFrame
+		if m := jsNativePattern.FindStringSubmatch(line); m != nil {
+			// Native and Promise.all frames have no source file
+			frame = &StackFrame{Function: m[1]}
+		} else if m := jsFramePattern.FindStringSubmatch(line); m != nil {
+			frame = &StackFrame{Function: m[1], File: m[2]}
+			frame.Line, _ = strconv.Atoi(m[3])
+			frame.Column, _ = strconv.Atoi(m[4])
+		} else if m := jsGeckoPattern.FindStringSubmatch(trimmed); m != nil {
+			frame = &StackFrame{Function: m[1], File: m[2]}
+			frame.Line, _ = strconv.Atoi(m[3])
+			frame.Column, _ = strconv.Atoi(m[4])
+		}
+
+		if frame != nil {
+			if current == nil {
+				current = &StackTrace{}
+				headerText := strings.Join(header, "\n")
+				if m := jsExceptionHeader.FindStringSubmatch(headerText); m != nil && len(header) > 0 {
+					current.Type, current.Message = m[1], m[2]
+				} else {
+					current.Message = headerText
+				}
+				header = nil
+			}
+			frame.InApp = jsInApp(frame.File)
+			current.Frames = append(current.Frames, *frame)
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		// A non-frame line after frames starts the next error (e.g. a cause printed below)
+		flush()
+		if strings.HasPrefix(trimmed, "[cause]:") || strings.HasPrefix(trimmed, "Caused by:") {
+			trimmed = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(trimmed, "[cause]:"), "Caused by:"))
+			header = nil
+		}
+		header = append(header, trimmed)
+	}
+	flush()
+	return traces
+}
+
+func jsInApp(file string) bool {
+	return file != "" && !strings.Contains(file, "node_modules") && !strings.HasPrefix(file, "node:") &&
+		!strings.HasPrefix(file, "internal/")
+}
+
+// sentryFrame is a frame in a Sentry event's stacktrace interface
+type sentryFrame struct {
+	Filename    string `json:"filename"`
+	AbsPath     string `json:"abs_path"`
+	Function    string `json:"function"`
+	Module      string `json:"module"`
+	Package     string `json:"package"`
+	LineNo      int    `json:"lineno"`
+	ColNo       int    `json:"colno"`
+	InApp       *bool  `json:"in_app"`
+	ContextLine string `json:"context_line"`
+}
+
+type sentryException struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Module     string `json:"module"`
+	ThreadID   any    `json:"thread_id"`
+	Stacktrace *struct {
+		Frames []sentryFrame `json:"frames"`
+	} `json:"stacktrace"`
+}
+
+// parseSentryEvent reads the exception interface of a Sentry event, as stored or as returned by the API (entries)
+func parseSentryEvent(text string) ([]StackTrace, string, error) {
+	var event struct {
+		Platform  string `json:"platform"`
+		Exception *struct {
+			Values []sentryException `json:"values"`
+		} `json:"exception"`
+		Entries []struct {
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(text), &event); err != nil {
+		return nil, "", fmt.Errorf("invalid Sentry event JSON: %w", err)
+	}
+
+	var values []sentryException
+	if event.Exception != nil {
+		values = event.Exception.Values
+	}
+	for _, entry := range event.Entries {
+		if entry.Type != "exception" {
+			continue
+		}
+		var data struct {
+			Values []sentryException `json:"values"`
+		}
+		if err := json.Unmarshal(entry.Data, &data); err == nil {
+			values = append(values, data.Values...)
+		}
+	}
+	if len(values) == 0 {
+		return nil, "", fmt.Errorf("the Sentry event has no exception")
+	}
+
+	language := event.Platform
+	switch language {
+	case "node", "javascript":
+		language = "javascript"
+	case "":
+		language = "sentry"
+	}
+
+	// Sentry lists chained exceptions oldest first with frames oldest call first; reverse both
+	var traces []StackTrace
+	for i := len(values) - 1; i >= 0; i-- {
+		value := values[i]
+		trace := StackTrace{Type: value.Type, Message: value.Value}
+		if i < len(values)-1 {
+			trace.Relation = "caused_by"
+		}
+		if value.Stacktrace != nil {
+			for j := len(value.Stacktrace.Frames) - 1; j >= 0; j-- {
+				f := value.Stacktrace.Frames[j]
+				frame := StackFrame{Function: f.Function, Module: f.Module, Line: f.LineNo, Column: f.ColNo, Code: strings.TrimSpace(f.ContextLine)}
+				frame.File = f.AbsPath
+				if frame.File == "" {
+					frame.File = f.Filename
+				}
+				if f.InApp != nil {
+					frame.InApp = *f.InApp
+				} else {
+					frame.InApp = heuristicInApp(language, frame)
+				}
+				trace.Frames = append(trace.Frames, frame)
+			}
+		}
+		traces = append(traces, trace)
+	}
+	return traces, language, nil
+}
+
+// heuristicInApp applies the language's library heuristics to a frame that does not say whether it is in-app
+func heuristicInApp(language string, frame StackFrame) bool {
+	switch language {
+	case "go":
+		return goInApp(frame.Module, frame.File)
+	case "java":
+		return javaInApp(frame.Module)
+	case "python":
+		return pythonInApp(frame.File)
+	case "javascript":
+		return jsInApp(frame.File)
+	}
+	return true
+}
+
+func reverseFrames(frames []StackFrame) {
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+}
+
+// SourceLocation is a local file matched to a frame
+type SourceLocation struct {
+	Path      string   `json:"path"`
+	Anchor    string   `json:"anchor"` // path:line, for opening in an editor or file_read
+	Matched   int      `json:"matched_path_segments"`
+	Ambiguous []string `json:"other_candidates,omitempty"`
+	Context   []string `json:"context,omitempty"`
+	Stale     bool     `json:"line_out_of_range,omitempty"`
+}
+
+// SourceIndex finds local files by the trailing segments of a frame's path
+type SourceIndex struct {
+	byName map[string][]string
+}
+
+// NewSourceIndex indexes files by base name
+func NewSourceIndex(files []string) *SourceIndex {
+	index := &SourceIndex{byName: map[string][]string{}}
+	for _, file := range files {
+		name := path.Base(toSlash(file))
+		index.byName[name] = append(index.byName[name], file)
+	}
+	return index
+}
+
+// Match returns the local files whose paths share the most trailing segments with framePath
+func (s *SourceIndex) Match(framePath string) (best []string, segments int) {
+	framePath = normalizeFramePath(framePath)
+	if framePath == "" {
+		return nil, 0
+	}
+	frameParts := strings.Split(framePath, "/")
+	for _, candidate := range s.byName[frameParts[len(frameParts)-1]] {
+		localParts := strings.Split(toSlash(candidate), "/")
+		n := 0
+		for n < len(frameParts) && n < len(localParts) && frameParts[len(frameParts)-1-n] == localParts[len(localParts)-1-n] {
+			n++
+		}
+		switch {
+		case n > segments:
+			best, segments = []string{candidate}, n
+		case n == segments && n > 0:
+			best = append(best, candidate)
+		}
+	}
+	return best, segments
+}
+
+// normalizeFramePath strips URL schemes, hosts, bundler prefixes and query strings from a frame's file
+func normalizeFramePath(file string) string {
+	file = toSlash(file)
+	for _, prefix := range []string{"webpack-internal:///", "webpack:///", "file://"} {
+		file = strings.TrimPrefix(file, prefix)
+	}
+	if i := strings.Index(file, "://"); i >= 0 {
+		// http(s)://host/path: drop the host
+		rest := file[i+3:]
+		if slash := strings.Index(rest, "/"); slash >= 0 {
+			file = rest[slash:]
+		} else {
+			file = ""
+		}
+	}
+	if i := strings.IndexAny(file, "?#"); i >= 0 {
+		file = file[:i]
+	}
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+	if file == "." || strings.HasPrefix(file, "<") || file == "native" {
+		return ""
+	}
+	return file
+}
+
+func toSlash(p string) string {
+	return strings.ReplaceAll(p, "\\", "/")
+}
@@ -0,0 +1,285 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+const (
+	// maxSourceIndexFiles bounds the files indexed under source_root for source mapping
+	maxSourceIndexFiles = 50000
+	maxContextLines     = 10
+)
+
+// createParseStacktraceTool creates the stack trace parsing and source mapping tool
+func (p *UtilityProvider) createParseStacktraceTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "parse_stacktrace",
+		Description: "Parse Go panics and goroutine dumps, Java/Kotlin exceptions, Python tracebacks, Node/browser JavaScript errors and Sentry event JSON into normalized frames (innermost first, chained causes as separate traces), mark application frames versus library/runtime frames, and map frames to local source files (path:line anchors with surrounding lines) by matching trailing path segments under source_root",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"trace": {
+					"type": "string",
+					"description": "Stack trace text or Sentry event JSON (max 1MB)"
+				},
+				"path": {
+					"type": "string",
+					"description": "File to read the trace from instead of trace, e.g. a saved log or event export"
+				},
+				"language": {
+					"type": "string",
+					"enum": ["auto", "go", "java", "python", "javascript", "sentry"],
+					"description": "Trace format; detected from the frame syntax when omitted",
+					"default": "auto"
+				},
+				"map_sources": {
+					"type": "boolean",
+					"description": "Match frames to files under source_root through the file provider",
+					"default": true
+				},
+				"source_root": {
+					"type": "string",
+					"description": "Directory to search for source files, relative to the working directory",
+					"default": "."
+				},
+				"context_lines": {
+					"type": "integer",
+					"description": "Source lines to include before and after each mapped application frame (max 10)",
+					"default": 2
+				},
+				"app_packages": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Module, package or path prefixes of application code (e.g. com.acme., github.com/acme/, src/); overrides the built-in library heuristics"
+				},
+				"max_frames": {
+					"type": "integer",
+					"description": "Maximum frames to return per trace; application frames are kept first when trimming",
+					"default": 50
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Trace        string   `json:"trace,omitempty"`
+			Path         string   `json:"path,omitempty"`
+			Language     string   `json:"language,omitempty"`
+			MapSources   *bool    `json:"map_sources,omitempty"`
+			SourceRoot   string   `json:"source_root,omitempty"`
+			ContextLines *int     `json:"context_lines,omitempty"`
+			AppPackages  []string `json:"app_packages,omitempty"`
+			MaxFrames    int      `json:"max_frames,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		text := args.Trace
+		if text == "" && args.Path != "" {
+			if p.files == nil {
+				return p.createErrorResult(fmt.Errorf("reading files is not available")), nil
+			}
+			content, err := p.files.ReadFile(args.Path)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			text = string(content)
+		}
+		if strings.TrimSpace(text) == "" {
+			return p.createErrorResult(fmt.Errorf("trace or path parameter is required")), nil
+		}
+		if len(text) > maxStacktraceInput {
+			return p.createErrorResult(fmt.Errorf("trace is too large (%d bytes, max %d)", len(text), maxStacktraceInput)), nil
+		}
+		if args.MaxFrames <= 0 {
+			args.MaxFrames = defaultMaxFrames
+		}
+		contextLines := 2
+		if args.ContextLines != nil {
+			contextLines = min(max(*args.ContextLines, 0), maxContextLines)
+		}
+
+		parsed, err := ParseStacktrace(text, args.Language)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		if len(args.AppPackages) > 0 {
+			for i := range parsed.Traces {
+				for j := range parsed.Traces[i].Frames {
+					frame := &parsed.Traces[i].Frames[j]
+					frame.InApp = matchesAppPackage(frame, args.AppPackages)
+				}
+			}
+		}
+		for i := range parsed.Traces {
+			trimFrames(&parsed.Traces[i], args.MaxFrames)
+		}
+
+		result := map[string]interface{}{
+			"language": parsed.Language,
+			"traces":   parsed.Traces,
+		}
+		if parsed.Omitted > 0 {
+			result["omitted_traces"] = parsed.Omitted
+		}
+
+		var notes []string
+		if args.MapSources == nil || *args.MapSources {
+			if note := p.mapStacktraceSources(parsed, args.SourceRoot, contextLines); note != "" {
+				notes = append(notes, note)
+			}
+		}
+
+		frames, inApp, mapped := 0, 0, 0
+		var top *StackFrame
+		for i := range parsed.Traces {
+			for j := range parsed.Traces[i].Frames {
+				frame := &parsed.Traces[i].Frames[j]
+				frames++
+				if frame.InApp {
+					inApp++
+					if top == nil {
+						top = frame
+					}
+				}
+				if frame.Source != nil {
+					mapped++
+				}
+			}
+		}
+		summary := map[string]interface{}{
+			"traces":        len(parsed.Traces),
+			"frames":        frames,
+			"in_app_frames": inApp,
+			"mapped_frames": mapped,
+		}
+		if len(parsed.Traces) > 0 {
+			first := parsed.Traces[0]
+			summary["error"] = strings.TrimSpace(strings.Trim(first.Type+": "+first.Message, ": "))
+		}
+		if top != nil {
+			location := fmt.Sprintf("%s:%d", top.File, top.Line)
+			if top.Source != nil {
+				location = top.Source.Anchor
+			}
+			summary["top_app_frame"] = map[string]interface{}{
+				"function": strings.TrimPrefix(top.Module+"."+top.Function, "."),
+				"location": location,
+			}
+		}
+		result["summary"] = summary
+		if len(notes) > 0 {
+			result["notes"] = notes
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// mapStacktraceSources links frames to files under root and returns a note when mapping was not possible
+func (p *UtilityProvider) mapStacktraceSources(parsed *ParsedStacktrace, root string, contextLines int) string {
+	if p.files == nil {
+		return "source mapping skipped: reading files is not available"
+	}
+	if root == "" {
+		root = "."
+	}
+	files, truncated, err := p.files.ListFiles(root, maxSourceIndexFiles)
+	if err != nil {
+		return fmt.Sprintf("source mapping skipped: %v", err)
+	}
+	index := NewSourceIndex(files)
+	contents := map[string][]string{}
+
+	for i := range parsed.Traces {
+		for j := range parsed.Traces[i].Frames {
+			frame := &parsed.Traces[i].Frames[j]
+			if frame.File == "" {
+				continue
+			}
+			candidates, segments := index.Match(frame.File)
+			if len(candidates) == 0 {
+				continue
+			}
+			location := &SourceLocation{Path: candidates[0], Matched: segments}
+			if len(candidates) > 1 {
+				location.Ambiguous = candidates[1:min(len(candidates), 5)]
+			}
+			location.Anchor = location.Path
+			if frame.Line > 0 {
+				location.Anchor = fmt.Sprintf("%s:%d", location.Path, frame.Line)
+			}
+
+			lines, ok := contents[location.Path]
+			if !ok {
+				if content, err := p.files.ReadFile(location.Path); err == nil {
+					lines = strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+				}
+				contents[location.Path] = lines
+			}
+			if frame.Line > len(lines) && lines != nil {
+				location.Stale = true
+			} else if frame.InApp && frame.Line > 0 && contextLines > 0 && lines != nil {
+				start := max(frame.Line-contextLines, 1)
+				end := min(frame.Line+contextLines, len(lines))
+				for n := start; n <= end; n++ {
+					marker := "  "
+					if n == frame.Line {
+						marker = "> "
+					}
+					location.Context = append(location.Context, fmt.Sprintf("%s%d: %s", marker, n, lines[n-1]))
+				}
+			}
+			frame.Source = location
+		}
+	}
+
+	if truncated {
+		return fmt.Sprintf("source index stopped at %d files; narrow source_root for complete mapping", maxSourceIndexFiles)
+	}
+	return ""
+}
+
+// matchesAppPackage reports whether a frame's module or file starts with one of the application prefixes
+func matchesAppPackage(frame *StackFrame, prefixes []string) bool {
+	file := normalizeFramePath(frame.File)
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			continue
+		}
+		if strings.HasPrefix(frame.Module, prefix) || strings.HasPrefix(file, strings.TrimPrefix(prefix, "/")) || strings.Contains(frame.File, "/"+strings.Trim(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// trimFrames keeps the innermost frames plus application frames beyond the limit
+func trimFrames(trace *StackTrace, limit int) {
+	if len(trace.Frames) <= limit {
+		return
+	}
+	kept := append([]StackFrame{}, trace.Frames[:limit]...)
+	omitted := 0
+	for _, frame := range trace.Frames[limit:] {
+		if frame.InApp && len(kept) < limit*2 {
+			kept = append(kept, frame)
+			continue
+		}
+		omitted++
+	}
+	trace.Frames = kept
+	trace.OmittedFrames += omitted
+}
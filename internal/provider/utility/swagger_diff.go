@@ -0,0 +1,557 @@
+package utility
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSchemaDiffDepth bounds how deep nested and recursive schemas are compared
+const maxSchemaDiffDepth = 8
+
+// swaggerMethods are the operations of a path item
+var swaggerMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// pathParamPattern matches path template parameters, whose names do not change the endpoint
+var pathParamPattern = regexp.MustCompile(`\{[^}/]*\}`)
+
+// SwaggerChange is one difference of an endpoint present in both documents
+type SwaggerChange struct {
+	Endpoint string `json:"endpoint"`           // METHOD /path as in the target document
+	Kind     string `json:"kind"`               // e.g. parameter_removed, type_changed, response_removed
+	Location string `json:"location,omitempty"` // Parameter, body or response field, e.g. query.limit or response.200.items[].id
+	Detail   string `json:"detail"`
+	Breaking bool   `json:"breaking"` // Existing clients may fail against the target
+}
+
+// SwaggerDiffSummary counts the differences
+type SwaggerDiffSummary struct {
+	EndpointsAdded   int `json:"endpoints_added"`
+	EndpointsRemoved int `json:"endpoints_removed"`
+	EndpointsChanged int `json:"endpoints_changed"`
+	BreakingChanges  int `json:"breaking_changes"` // Removed endpoints included
+}
+
+// SwaggerDiff lists the API changes from a base to a target Swagger/OpenAPI document
+type SwaggerDiff struct {
+	BaseVersion   string             `json:"base_version,omitempty"`
+	TargetVersion string             `json:"target_version,omitempty"`
+	Breaking      bool               `json:"breaking"`
+	Summary       SwaggerDiffSummary `json:"summary"`
+	Added         []string           `json:"added,omitempty"`
+	Removed       []string           `json:"removed,omitempty"`
+	Changes       []SwaggerChange    `json:"changes,omitempty"`
+}
+
+// swaggerOperation is an operation with the path item it belongs to
+type swaggerOperation struct {
+	endpoint string
+	path     string
+	op       map[string]interface{}
+	item     map[string]interface{}
+}
+
+// specDiff compares the operations of two documents, resolving local $refs in each
+type specDiff struct {
+	base, target map[string]interface{}
+	endpoint     string
+	changes      []SwaggerChange
+}
+
+// diffSwagger compares two Swagger 2 or OpenAPI 3 documents endpoint by endpoint. Path parameters are
+// matched by position, so renaming {id} to {userId} is not a change.
+func diffSwagger(base, target map[string]interface{}) *SwaggerDiff {
+	result := &SwaggerDiff{
+		BaseVersion:   stringField(asMap(base["info"]), "version"),
+		TargetVersion: stringField(asMap(target["info"]), "version"),
+	}
+	baseOps := swaggerOperations(base)
+	targetOps := swaggerOperations(target)
+
+	d := &specDiff{base: base, target: target}
+	changed := map[string]bool{}
+	for _, key := range sortedKeys(baseOps) {
+		baseOp := baseOps[key]
+		targetOp, ok := targetOps[key]
+		if !ok {
+			result.Removed = append(result.Removed, baseOp.endpoint)
+			continue
+		}
+		before := len(d.changes)
+		d.endpoint = targetOp.endpoint
+		d.compareOperation(baseOp, targetOp)
+		if len(d.changes) > before {
+			changed[key] = true
+		}
+	}
+	for _, key := range sortedKeys(targetOps) {
+		if _, ok := baseOps[key]; !ok {
+			result.Added = append(result.Added, targetOps[key].endpoint)
+		}
+	}
+
+	result.Changes = d.changes
+	result.Summary = SwaggerDiffSummary{
+		EndpointsAdded:   len(result.Added),
+		EndpointsRemoved: len(result.Removed),
+		EndpointsChanged: len(changed),
+		BreakingChanges:  len(result.Removed),
+	}
+	for _, change := range d.changes {
+		if change.Breaking {
+			result.Summary.BreakingChanges++
+		}
+	}
+	result.Breaking = result.Summary.BreakingChanges > 0
+	return result
+}
+
+// swaggerOperations indexes the operations of a document by method and normalized path
+func swaggerOperations(spec map[string]interface{}) map[string]swaggerOperation {
+	ops := map[string]swaggerOperation{}
+	for path, rawItem := range asMap(spec["paths"]) {
+		item := asMap(rawItem)
+		for _, method := range swaggerMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			upper := strings.ToUpper(method)
+			key := upper + " " + pathParamPattern.ReplaceAllString(path, "{}")
+			ops[key] = swaggerOperation{endpoint: upper + " " + path, path: path, op: op, item: item}
+		}
+	}
+	return ops
+}
+
+func (d *specDiff) add(kind, location string, breaking bool, format string, args ...interface{}) {
+	d.changes = append(d.changes, SwaggerChange{
+		Endpoint: d.endpoint,
+		Kind:     kind,
+		Location: location,
+		Detail:   fmt.Sprintf(format, args...),
+		Breaking: breaking,
+	})
+}
+
+// compareOperation compares the parameters, request body, responses and deprecation of an endpoint
+func (d *specDiff) compareOperation(base, target swaggerOperation) {
+	if !isTrue(base.op["deprecated"]) && isTrue(target.op["deprecated"]) {
+		d.add("deprecated", "", false, "the endpoint is deprecated")
+	}
+
+	baseParams, baseBody := d.parameters(d.base, base)
+	targetParams, targetBody := d.parameters(d.target, target)
+
+	// Path parameters match by position, like the paths: compare the base's under the target's names
+	baseNames, targetNames := pathParamPattern.FindAllString(base.path, -1), pathParamPattern.FindAllString(target.path, -1)
+	renamed := map[string]map[string]interface{}{}
+	for i, name := range baseNames {
+		oldKey, newKey := "path."+strings.Trim(name, "{}"), "path."+strings.Trim(targetNames[i], "{}")
+		if param, ok := baseParams[oldKey]; ok && oldKey != newKey {
+			delete(baseParams, oldKey)
+			renamed[newKey] = param
+		}
+	}
+	for key, param := range renamed {
+		baseParams[key] = param
+	}
+	d.compareParameters(baseParams, targetParams)
+	d.compareRequestBody(baseBody, targetBody)
+	d.compareResponses(asMap(base.op["responses"]), asMap(target.op["responses"]))
+}
+
+// requestBody is the request body of an operation: an OpenAPI 3 requestBody or a Swagger 2 body parameter
+type requestBody struct {
+	required bool
+	content  map[string]interface{} // Media type -> schema; Swagger 2 bodies have the single type ""
+}
+
+// parameters collects the path item and operation parameters by location and name, the operation's
+// overriding the path item's, and the request body
+func (d *specDiff) parameters(spec map[string]interface{}, operation swaggerOperation) (map[string]map[string]interface{}, *requestBody) {
+	params := map[string]map[string]interface{}{}
+	var body *requestBody
+	for _, list := range []interface{}{operation.item["parameters"], operation.op["parameters"]} {
+		items, _ := list.([]interface{})
+		for _, raw := range items {
+			param := resolveRef(spec, raw)
+			in, name := stringField(param, "in"), stringField(param, "name")
+			if in == "body" {
+				body = &requestBody{required: isTrue(param["required"]), content: map[string]interface{}{"": param["schema"]}}
+				continue
+			}
+			params[in+"."+name] = param
+		}
+	}
+
+	if raw, ok := operation.op["requestBody"]; ok {
+		rb := resolveRef(spec, raw)
+		body = &requestBody{required: isTrue(rb["required"]), content: map[string]interface{}{}}
+		for mediaType, media := range asMap(rb["content"]) {
+			body.content[mediaType] = asMap(media)["schema"]
+		}
+	}
+	return params, body
+}
+
+// compareParameters reports removed, added and changed parameters
+func (d *specDiff) compareParameters(base, target map[string]map[string]interface{}) {
+	for _, key := range sortedKeys(base) {
+		baseParam := base[key]
+		targetParam, ok := target[key]
+		if !ok {
+			d.add("parameter_removed", key, true, "parameter %s was removed", key)
+			continue
+		}
+		baseRequired, targetRequired := isTrue(baseParam["required"]), isTrue(targetParam["required"])
+		switch {
+		case !baseRequired && targetRequired:
+			d.add("parameter_required", key, true, "parameter %s became required", key)
+		case baseRequired && !targetRequired:
+			d.add("parameter_optional", key, false, "parameter %s became optional", key)
+		}
+		d.compareSchema(key, parameterSchema(baseParam), parameterSchema(targetParam), true, 0)
+	}
+	for _, key := range sortedKeys(target) {
+		if _, ok := base[key]; ok {
+			continue
+		}
+		required := isTrue(target[key]["required"])
+		d.add("parameter_added", key, required, "%s parameter %s was added", requiredWord(required), key)
+	}
+}
+
+// parameterSchema returns the schema of an OpenAPI 3 parameter, or the Swagger 2 parameter itself,
+// which carries type, enum and items directly
+func parameterSchema(param map[string]interface{}) interface{} {
+	if schema, ok := param["schema"]; ok {
+		return schema
+	}
+	return param
+}
+
+// compareRequestBody reports an added, removed or changed request body
+func (d *specDiff) compareRequestBody(base, target *requestBody) {
+	switch {
+	case base == nil && target == nil:
+		return
+	case base == nil:
+		d.add("request_body_added", "body", target.required, "%s request body was added", requiredWord(target.required))
+		return
+	case target == nil:
+		d.add("request_body_removed", "body", true, "the request body was removed")
+		return
+	}
+
+	switch {
+	case !base.required && target.required:
+		d.add("request_body_required", "body", true, "the request body became required")
+	case base.required && !target.required:
+		d.add("request_body_optional", "body", false, "the request body became optional")
+	}
+	d.compareContent("body", base.content, target.content, true)
+}
+
+// compareContent compares the schemas of the media types of a body, and reports media types that were
+// removed or added
+func (d *specDiff) compareContent(location string, base, target map[string]interface{}, request bool) {
+	_, baseSwagger2 := base[""]
+	_, targetSwagger2 := target[""]
+	if baseSwagger2 != targetSwagger2 {
+		// A Swagger 2 body against an OpenAPI 3 one compares with its JSON schema
+		d.compareSchema(location, base[jsonMediaType(base)], target[jsonMediaType(target)], request, 0)
+		return
+	}
+
+	for _, mediaType := range sortedKeys(base) {
+		targetSchema, ok := target[mediaType]
+		if !ok {
+			d.add("media_type_removed", location, true, "media type %s was removed", mediaType)
+			continue
+		}
+		d.compareSchema(location, base[mediaType], targetSchema, request, 0)
+	}
+	for _, mediaType := range sortedKeys(target) {
+		if _, ok := base[mediaType]; !ok {
+			d.add("media_type_added", location, false, "media type %s was added", mediaType)
+		}
+	}
+}
+
+// jsonMediaType picks the JSON media type of a body, else its first one
+func jsonMediaType(content map[string]interface{}) string {
+	types := sortedKeys(content)
+	for _, mediaType := range types {
+		if mediaType == "" || strings.Contains(mediaType, "json") {
+			return mediaType
+		}
+	}
+	if len(types) == 0 {
+		return ""
+	}
+	return types[0]
+}
+
+// compareResponses reports removed and added status codes and compares the schemas of the rest
+func (d *specDiff) compareResponses(base, target map[string]interface{}) {
+	for _, code := range sortedKeys(base) {
+		location := "response." + code
+		targetResponse, ok := target[code]
+		if !ok {
+			// Clients handle unexpected errors already; a missing success response changes what they get
+			d.add("response_removed", location, isSuccessCode(code), "response %s was removed", code)
+			continue
+		}
+		d.compareContent(location, responseContent(d.base, base[code]), responseContent(d.target, targetResponse), false)
+	}
+	for _, code := range sortedKeys(target) {
+		if _, ok := base[code]; !ok {
+			d.add("response_added", "response."+code, false, "response %s was added", code)
+		}
+	}
+}
+
+// responseContent returns the schemas of a response by media type; Swagger 2 responses have the single type ""
+func responseContent(spec map[string]interface{}, raw interface{}) map[string]interface{} {
+	response := resolveRef(spec, raw)
+	content := map[string]interface{}{}
+	if schema, ok := response["schema"]; ok {
+		content[""] = schema
+	}
+	for mediaType, media := range asMap(response["content"]) {
+		content[mediaType] = asMap(media)["schema"]
+	}
+	return content
+}
+
+// compareSchema compares types, enums, properties and items. A request schema breaks clients when it
+// accepts less than before; a response schema breaks them when it returns more than before.
+func (d *specDiff) compareSchema(location string, baseRaw, targetRaw interface{}, request bool, depth int) {
+	if depth > maxSchemaDiffDepth || baseRaw == nil || targetRaw == nil {
+		return
+	}
+	base, target := flattenSchema(d.base, baseRaw, 0), flattenSchema(d.target, targetRaw, 0)
+
+	baseTypes, targetTypes := schemaTypes(base), schemaTypes(target)
+	if len(baseTypes) > 0 && len(targetTypes) > 0 && strings.Join(baseTypes, "|") != strings.Join(targetTypes, "|") {
+		breaking := !subset(baseTypes, targetTypes)
+		if !request {
+			breaking = !subset(targetTypes, baseTypes)
+		}
+		d.add("type_changed", location, breaking, "type changed from %s to %s",
+			strings.Join(baseTypes, "|"), strings.Join(targetTypes, "|"))
+	}
+
+	baseEnum, targetEnum := enumValues(base), enumValues(target)
+	if removed, added := difference(baseEnum, targetEnum), difference(targetEnum, baseEnum); len(removed) > 0 || len(added) > 0 {
+		var parts []string
+		if len(baseEnum) == 0 {
+			parts = append(parts, "values were restricted to "+strings.Join(targetEnum, ", "))
+		} else if len(targetEnum) == 0 {
+			parts = append(parts, "the value restriction was lifted")
+		} else {
+			if len(removed) > 0 {
+				parts = append(parts, "removed "+strings.Join(removed, ", "))
+			}
+			if len(added) > 0 {
+				parts = append(parts, "added "+strings.Join(added, ", "))
+			}
+		}
+		// An empty enum allows any value
+		narrowed := len(targetEnum) > 0 && (len(baseEnum) == 0 || len(removed) > 0)
+		widened := len(baseEnum) > 0 && (len(targetEnum) == 0 || len(added) > 0)
+		breaking := narrowed
+		if !request {
+			breaking = widened
+		}
+		d.add("enum_changed", location, breaking, "enum %s", strings.Join(parts, "; "))
+	}
+
+	baseProps, targetProps := asMap(base["properties"]), asMap(target["properties"])
+	baseRequired, targetRequired := requiredSet(base), requiredSet(target)
+	for _, name := range sortedKeys(baseProps) {
+		propLocation := location + "." + name
+		targetProp, ok := targetProps[name]
+		if !ok {
+			d.add("property_removed", propLocation, true, "property %s was removed", name)
+			continue
+		}
+		switch {
+		case !baseRequired[name] && targetRequired[name]:
+			d.add("property_required", propLocation, request, "property %s became required", name)
+		case baseRequired[name] && !targetRequired[name]:
+			d.add("property_optional", propLocation, !request, "property %s became optional", name)
+		}
+		d.compareSchema(propLocation, baseProps[name], targetProp, request, depth+1)
+	}
+	for _, name := range sortedKeys(targetProps) {
+		if _, ok := baseProps[name]; ok {
+			continue
+		}
+		required := targetRequired[name]
+		d.add("property_added", location+"."+name, request && required, "%s property %s was added", requiredWord(required), name)
+	}
+
+	if baseItems, ok := base["items"]; ok {
+		d.compareSchema(location+"[]", baseItems, target["items"], request, depth+1)
+	}
+}
+
+// flattenSchema resolves a schema's $ref and merges the properties and required lists of its allOf parts
+func flattenSchema(spec map[string]interface{}, raw interface{}, depth int) map[string]interface{} {
+	schema := resolveRef(spec, raw)
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok || depth > maxSchemaDiffDepth {
+		return schema
+	}
+
+	merged := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	var required []interface{}
+	parts := []map[string]interface{}{schema}
+	for _, part := range allOf {
+		parts = append(parts, flattenSchema(spec, part, depth+1))
+	}
+	for _, resolved := range parts {
+		for key, value := range resolved {
+			if key != "allOf" && key != "properties" && key != "required" {
+				merged[key] = value
+			}
+		}
+		for name, prop := range asMap(resolved["properties"]) {
+			properties[name] = prop
+		}
+		list, _ := resolved["required"].([]interface{})
+		required = append(required, list...)
+	}
+	merged["properties"] = properties
+	merged["required"] = required
+	return merged
+}
+
+// resolveRef follows local $refs (#/...) to the object they point at; other values resolve to an empty object
+func resolveRef(spec map[string]interface{}, raw interface{}) map[string]interface{} {
+	current := asMap(raw)
+	for range maxSchemaDiffDepth {
+		ref, ok := current["$ref"].(string)
+		if !ok {
+			return current
+		}
+		pointer, ok := strings.CutPrefix(ref, "#/")
+		if !ok {
+			return current
+		}
+		tokens := strings.Split(pointer, "/")
+		for i, token := range tokens {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		}
+		var next interface{} = spec
+		for _, token := range tokens {
+			next = asMap(next)[token]
+		}
+		current = asMap(next)
+	}
+	return current
+}
+
+// schemaTypes returns the sorted types of a schema; nullable was already turned into a "null" type
+func schemaTypes(schema map[string]interface{}) []string {
+	var types []string
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			types = append(types, fmt.Sprint(item))
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// enumValues returns the sorted values of a schema's enum
+func enumValues(schema map[string]interface{}) []string {
+	list, _ := schema["enum"].([]interface{})
+	values := make([]string, 0, len(list))
+	for _, value := range list {
+		values = append(values, fmt.Sprint(value))
+	}
+	sort.Strings(values)
+	return values
+}
+
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	set := map[string]bool{}
+	list, _ := schema["required"].([]interface{})
+	for _, name := range list {
+		set[fmt.Sprint(name)] = true
+	}
+	return set
+}
+
+// difference returns the values of a missing from b
+func difference(a, b []string) []string {
+	var missing []string
+	for _, value := range a {
+		if !contains(b, value) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// subset reports whether every value of a is in b
+func subset(a, b []string) bool {
+	return len(difference(a, b)) == 0
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isSuccessCode(code string) bool {
+	status, err := strconv.Atoi(code)
+	return code == "2XX" || err == nil && status >= 200 && status < 300
+}
+
+func requiredWord(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func isTrue(value interface{}) bool {
+	b, _ := value.(bool)
+	return b
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,138 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// createSwaggerDiffTool creates the Swagger/OpenAPI comparison tool
+func (p *UtilityProvider) createSwaggerDiffTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "swagger_diff",
+		Description: "Compare two Swagger 2 / OpenAPI 3 documents and report added and removed endpoints, parameter, request body and response schema changes, each classified as breaking or not for existing clients. The base is the configured Swagger document unless base_path or base_url is given; the target is a file or URL, e.g. the spec of a feature branch",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"target_path": {
+					"type": "string",
+					"description": "Read the new document from this file (via the file provider)"
+				},
+				"target_url": {
+					"type": "string",
+					"description": "Fetch the new document from this http(s) URL"
+				},
+				"base_path": {
+					"type": "string",
+					"description": "Read the old document from this file instead of the configured one"
+				},
+				"base_url": {
+					"type": "string",
+					"description": "Fetch the old document from this http(s) URL instead of the configured one"
+				},
+				"breaking_only": {
+					"type": "boolean",
+					"description": "Only list breaking changes",
+					"default": false
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			TargetPath   string `json:"target_path,omitempty"`
+			TargetURL    string `json:"target_url,omitempty"`
+			BasePath     string `json:"base_path,omitempty"`
+			BaseURL      string `json:"base_url,omitempty"`
+			BreakingOnly bool   `json:"breaking_only,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		if (args.TargetPath == "") == (args.TargetURL == "") {
+			return p.createErrorResult(fmt.Errorf("provide exactly one of target_path or target_url")), nil
+		}
+		if args.BasePath != "" && args.BaseURL != "" {
+			return p.createErrorResult(fmt.Errorf("provide at most one of base_path or base_url")), nil
+		}
+
+		base, baseSource, err := p.loadSwaggerSource(args.BasePath, args.BaseURL)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("base: %w", err)), nil
+		}
+		target, targetSource, err := p.loadSwaggerSource(args.TargetPath, args.TargetURL)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("target: %w", err)), nil
+		}
+
+		diff := diffSwagger(base, target)
+		if args.BreakingOnly {
+			var breaking []SwaggerChange
+			for _, change := range diff.Changes {
+				if change.Breaking {
+					breaking = append(breaking, change)
+				}
+			}
+			diff.Changes = breaking
+			diff.Added = nil
+		}
+
+		return p.formatJSONResult(map[string]interface{}{
+			"base":   baseSource,
+			"target": targetSource,
+			"diff":   diff,
+		}), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// loadSwaggerSource reads a Swagger/OpenAPI document from a file or an http(s) URL, or the configured
+// document when neither is given
+func (p *UtilityProvider) loadSwaggerSource(path, url string) (map[string]interface{}, string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case path != "":
+		if p.files == nil {
+			return nil, "", fmt.Errorf("reading files is not available")
+		}
+		data, err = p.files.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+	case url != "":
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return nil, "", fmt.Errorf("url %q is not an http(s) URL", url)
+		}
+		data, err = fetchURL(url)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch swagger document: %w", err)
+		}
+	default:
+		spec, err := p.loadSwaggerDocument()
+		if err != nil {
+			return nil, "", err
+		}
+		source := p.swagger.Filepath
+		if source == "" || p.files == nil {
+			source = p.swagger.URL
+		}
+		return spec, "configured " + source, nil
+	}
+
+	spec, err := parseSwaggerDocument(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return spec, path + url, nil
+}
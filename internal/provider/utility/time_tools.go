@@ -0,0 +1,386 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// timeView is a timestamp rendered in one zone
+type timeView struct {
+	Zone    string `json:"zone"`
+	Time    string `json:"time"`
+	Offset  string `json:"offset"`
+	Weekday string `json:"weekday"`
+}
+
+func viewIn(t time.Time, loc *time.Location) timeView {
+	local := t.In(loc)
+	return timeView{
+		Zone:    loc.String(),
+		Time:    local.Format(time.RFC3339Nano),
+		Offset:  local.Format("-07:00 MST"),
+		Weekday: local.Weekday().String(),
+	}
+}
+
+// epochs renders a timestamp in the epoch units used by log and metric backends
+func epochs(t time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"seconds":      t.Unix(),
+		"milliseconds": t.UnixMilli(),
+		"nanoseconds":  fmt.Sprintf("%d", t.UnixNano()),
+	}
+}
+
+// createTimeConvertTool creates the timezone conversion tool
+func (p *UtilityProvider) createTimeConvertTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "time_convert",
+		Description: "Convert a timestamp between timezones. Accepts epoch numbers, RFC 3339 and common log formats; times without a zone are read in from_timezone",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"time": {
+					"type": "string",
+					"description": "Timestamp to convert (e.g. 2024-03-10 14:30:00, 1710081000, now)"
+				},
+				"from_timezone": {
+					"type": "string",
+					"description": "Zone of timestamps without an explicit zone (IANA name or offset like +05:30)",
+					"default": "UTC"
+				},
+				"to_timezones": {
+					"type": "array",
+					"items": {"type": "string"},
+					"description": "Zones to convert to (e.g. [\"America/New_York\", \"Asia/Tokyo\"]); UTC is always included"
+				}
+			},
+			"required": ["time"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Time         string   `json:"time"`
+			FromTimezone string   `json:"from_timezone,omitempty"`
+			ToTimezones  []string `json:"to_timezones,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Time == "" {
+			return p.createErrorResult(fmt.Errorf("time parameter is required")), nil
+		}
+
+		from, err := loadLocation(args.FromTimezone)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		parsed, err := ParseTimestamp(args.Time, from, time.Now())
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		views := []timeView{viewIn(parsed.Time, time.UTC)}
+		for _, name := range args.ToTimezones {
+			loc, err := loadLocation(name)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			if loc != time.UTC {
+				views = append(views, viewIn(parsed.Time, loc))
+			}
+		}
+
+		result := map[string]interface{}{
+			"input":     args.Time,
+			"format":    parsed.Format,
+			"converted": views,
+			"epoch":     epochs(parsed.Time),
+		}
+		if parsed.Assumed != "" {
+			result["assumed"] = parsed.Assumed
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createTimeParseTool creates the timestamp parsing tool
+func (p *UtilityProvider) createTimeParseTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "time_parse",
+		Description: "Parse an arbitrary timestamp (epoch s/ms/µs/ns, RFC 3339, RFC 1123, Apache/nginx, syslog, SQL datetime, relative like -2h) and show it as UTC, epoch values and relative to now",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"value": {
+					"type": "string",
+					"description": "Timestamp to parse"
+				},
+				"timezone": {
+					"type": "string",
+					"description": "Zone of timestamps without an explicit zone",
+					"default": "UTC"
+				}
+			},
+			"required": ["value"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Value    string `json:"value"`
+			Timezone string `json:"timezone,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Value == "" {
+			return p.createErrorResult(fmt.Errorf("value parameter is required")), nil
+		}
+
+		loc, err := loadLocation(args.Timezone)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		now := time.Now()
+		parsed, err := ParseTimestamp(args.Value, loc, now)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"input":    args.Value,
+			"format":   parsed.Format,
+			"utc":      viewIn(parsed.Time, time.UTC),
+			"epoch":    epochs(parsed.Time),
+			"relative": RelativeTo(parsed.Time, now),
+		}
+		if loc != time.UTC {
+			result["local"] = viewIn(parsed.Time, loc)
+		}
+		if parsed.Assumed != "" {
+			result["assumed"] = parsed.Assumed
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createTimeDiffTool creates the duration calculation tool
+func (p *UtilityProvider) createTimeDiffTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "time_diff",
+		Description: "Compute the duration between two timestamps (e.g. two log lines in different formats or zones)",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"start": {
+					"type": "string",
+					"description": "Earlier timestamp"
+				},
+				"end": {
+					"type": "string",
+					"description": "Later timestamp; defaults to now",
+					"default": "now"
+				},
+				"timezone": {
+					"type": "string",
+					"description": "Zone of timestamps without an explicit zone",
+					"default": "UTC"
+				}
+			},
+			"required": ["start"]
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Start    string `json:"start"`
+			End      string `json:"end,omitempty"`
+			Timezone string `json:"timezone,omitempty"`
+		}{End: "now"}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.Start == "" {
+			return p.createErrorResult(fmt.Errorf("start parameter is required")), nil
+		}
+
+		loc, err := loadLocation(args.Timezone)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		now := time.Now()
+		start, err := ParseTimestamp(args.Start, loc, now)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("start: %w", err)), nil
+		}
+		end, err := ParseTimestamp(args.End, loc, now)
+		if err != nil {
+			return p.createErrorResult(fmt.Errorf("end: %w", err)), nil
+		}
+
+		d := end.Time.Sub(start.Time)
+		result := map[string]interface{}{
+			"start":        viewIn(start.Time, time.UTC),
+			"end":          viewIn(end.Time, time.UTC),
+			"duration":     HumanDuration(d),
+			"go_duration":  d.String(),
+			"seconds":      d.Seconds(),
+			"milliseconds": d.Milliseconds(),
+			"negative":     d < 0,
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// createTimeRangeTool creates the query range tool
+func (p *UtilityProvider) createTimeRangeTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "time_range",
+		Description: "Build a query time range and render it as epoch seconds/milliseconds/nanoseconds, RFC 3339 and ready-made Loki and Prometheus parameters. Give last (e.g. 1h), start/end, or around plus window",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"last": {
+					"type": "string",
+					"description": "Range ending at end (or now), e.g. 15m, 6h, 7d"
+				},
+				"start": {
+					"type": "string",
+					"description": "Range start timestamp"
+				},
+				"end": {
+					"type": "string",
+					"description": "Range end timestamp; defaults to now"
+				},
+				"around": {
+					"type": "string",
+					"description": "Center the range on this timestamp (e.g. an incident start)"
+				},
+				"window": {
+					"type": "string",
+					"description": "Half-width of the range around the timestamp",
+					"default": "15m"
+				},
+				"timezone": {
+					"type": "string",
+					"description": "Zone of timestamps without an explicit zone",
+					"default": "UTC"
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := struct {
+			Last     string `json:"last,omitempty"`
+			Start    string `json:"start,omitempty"`
+			End      string `json:"end,omitempty"`
+			Around   string `json:"around,omitempty"`
+			Window   string `json:"window,omitempty"`
+			Timezone string `json:"timezone,omitempty"`
+		}{Window: "15m"}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		loc, err := loadLocation(args.Timezone)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+		now := time.Now()
+		parse := func(name, value string) (time.Time, error) {
+			parsed, err := ParseTimestamp(value, loc, now)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%s: %w", name, err)
+			}
+			return parsed.Time, nil
+		}
+
+		var start, end time.Time
+		switch {
+		case args.Around != "":
+			center, err := parse("around", args.Around)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			window, err := ParseDuration(args.Window)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			start, end = center.Add(-window), center.Add(window)
+		case args.Last != "":
+			last, err := ParseDuration(args.Last)
+			if err != nil {
+				return p.createErrorResult(err), nil
+			}
+			end = now
+			if args.End != "" {
+				if end, err = parse("end", args.End); err != nil {
+					return p.createErrorResult(err), nil
+				}
+			}
+			start = end.Add(-last)
+		case args.Start != "":
+			if start, err = parse("start", args.Start); err != nil {
+				return p.createErrorResult(err), nil
+			}
+			end = now
+			if args.End != "" {
+				if end, err = parse("end", args.End); err != nil {
+					return p.createErrorResult(err), nil
+				}
+			}
+		default:
+			return p.createErrorResult(fmt.Errorf("one of last, start or around is required")), nil
+		}
+
+		if !end.After(start) {
+			return p.createErrorResult(fmt.Errorf("range end must be after its start")), nil
+		}
+
+		step := suggestStep(end.Sub(start))
+		result := map[string]interface{}{
+			"start":    viewIn(start, loc),
+			"end":      viewIn(end, loc),
+			"duration": HumanDuration(end.Sub(start)),
+			"epoch": map[string]interface{}{
+				"start": epochs(start),
+				"end":   epochs(end),
+			},
+			"loki": map[string]interface{}{
+				"start": fmt.Sprintf("%d", start.UnixNano()),
+				"end":   fmt.Sprintf("%d", end.UnixNano()),
+			},
+			"prometheus": map[string]interface{}{
+				"start": start.Unix(),
+				"end":   end.Unix(),
+				"step":  step.String(),
+				"query": fmt.Sprintf("start=%d&end=%d&step=%d", start.Unix(), end.Unix(), int(step.Seconds())),
+			},
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
@@ -0,0 +1,262 @@
+package utility
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsedTime is a timestamp with the layout it was recognised by
+type ParsedTime struct {
+	Time   time.Time
+	Format string
+	// Assumed is set when the input had no zone (or no year) and one was assumed
+	Assumed string
+}
+
+// timeLayouts are tried in order for non-numeric timestamps
+var timeLayouts = []struct {
+	name   string
+	layout string
+	zoned  bool
+}{
+	{"RFC3339", time.RFC3339Nano, true},
+	{"ISO 8601 (no colon in offset)", "2006-01-02T15:04:05.999999999-0700", true},
+	{"RFC1123", time.RFC1123, true},
+	{"RFC1123Z", time.RFC1123Z, true},
+	{"RFC850", time.RFC850, true},
+	{"RFC822Z", time.RFC822Z, true},
+	{"RFC822", time.RFC822, true},
+	{"Unix date", time.UnixDate, true},
+	{"Ruby date", time.RubyDate, true},
+	{"Common Log Format", "02/Jan/2006:15:04:05 -0700", true},
+	{"SQL datetime with offset", "2006-01-02 15:04:05.999999999-07:00", true},
+	{"SQL datetime with zone", "2006-01-02 15:04:05.999999999 -0700 MST", true},
+	{"SQL datetime with offset", "2006-01-02 15:04:05.999999999 -0700", true},
+	{"ISO 8601 local", "2006-01-02T15:04:05.999999999", false},
+	{"SQL datetime", "2006-01-02 15:04:05.999999999", false},
+	{"ANSI C", time.ANSIC, false},
+	{"Slash date time", "2006/01/02 15:04:05", false},
+	{"Date", "2006-01-02", false},
+}
+
+// syslogLayouts have no year; the current year (or last year, if that would be in the future) is assumed
+var syslogLayouts = []string{time.Stamp, time.StampMilli, time.StampMicro}
+
+var numericPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// ParseTimestamp recognises epoch numbers (s, ms, µs or ns by magnitude) and common log/date formats.
+// Timestamps without a zone are read in loc.
+func ParseTimestamp(value string, loc *time.Location, now time.Time) (*ParsedTime, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("empty timestamp")
+	}
+
+	switch strings.ToLower(value) {
+	case "now":
+		return &ParsedTime{Time: now, Format: "now"}, nil
+	case "today":
+		y, m, d := now.In(loc).Date()
+		return &ParsedTime{Time: time.Date(y, m, d, 0, 0, 0, 0, loc), Format: "today"}, nil
+	}
+
+	if numericPattern.MatchString(value) {
+		return parseEpoch(value)
+	}
+
+	// Relative to now: "-1h", "-7d", "+30m"
+	if value[0] == '-' || value[0] == '+' {
+		if d, err := ParseDuration(value[1:]); err == nil {
+			if value[0] == '-' {
+				d = -d
+			}
+			return &ParsedTime{Time: now.Add(d), Format: "relative"}, nil
+		}
+	}
+
+	for _, l := range timeLayouts {
+		var (
+			t   time.Time
+			err error
+		)
+		if l.zoned {
+			t, err = time.Parse(l.layout, value)
+		} else {
+			t, err = time.ParseInLocation(l.layout, value, loc)
+		}
+		if err != nil {
+			continue
+		}
+		parsed := &ParsedTime{Time: t, Format: l.name}
+		if !l.zoned {
+			parsed.Assumed = "timezone " + loc.String()
+		}
+		return parsed, nil
+	}
+
+	for _, layout := range syslogLayouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			continue
+		}
+		year := now.In(loc).Year()
+		t = t.AddDate(year, 0, 0)
+		if t.After(now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return &ParsedTime{Time: t, Format: "Syslog", Assumed: fmt.Sprintf("year %d, timezone %s", t.Year(), loc)}, nil
+	}
+
+	return nil, fmt.Errorf("unrecognised timestamp %q", value)
+}
+
+// parseEpoch reads an epoch number, choosing the unit from its magnitude
+func parseEpoch(value string) (*ParsedTime, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid epoch %q: %w", value, err)
+	}
+
+	abs := math.Abs(f)
+	switch {
+	case abs >= 1e17:
+		n, err := strconv.ParseInt(strings.Split(value, ".")[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid epoch %q: %w", value, err)
+		}
+		return &ParsedTime{Time: time.Unix(0, n).UTC(), Format: "Unix epoch (nanoseconds)"}, nil
+	case abs >= 1e14:
+		return &ParsedTime{Time: time.UnixMicro(int64(f)).UTC(), Format: "Unix epoch (microseconds)"}, nil
+	case abs >= 1e11:
+		return &ParsedTime{Time: time.UnixMilli(int64(f)).UTC(), Format: "Unix epoch (milliseconds)"}, nil
+	default:
+		sec, frac := math.Modf(f)
+		return &ParsedTime{Time: time.Unix(int64(sec), int64(frac*1e9)).UTC(), Format: "Unix epoch (seconds)"}, nil
+	}
+}
+
+var durationUnitPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)(w|d)`)
+
+// ParseDuration extends time.ParseDuration with days ("d") and weeks ("w"), e.g. "1d12h" or "2w"
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var total time.Duration
+	rest := durationUnitPattern.ReplaceAllStringFunc(value, func(m string) string {
+		parts := durationUnitPattern.FindStringSubmatch(m)
+		n, _ := strconv.ParseFloat(parts[1], 64)
+		unit := 24 * time.Hour
+		if parts[2] == "w" {
+			unit *= 7
+		}
+		total += time.Duration(n * float64(unit))
+		return ""
+	})
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q (use e.g. 90s, 15m, 1h30m, 7d, 2w)", value)
+		}
+		total += d
+	}
+	if total == 0 && !strings.ContainsAny(value, "0123456789") {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 90s, 15m, 1h30m, 7d, 2w)", value)
+	}
+	return total, nil
+}
+
+// HumanDuration formats a duration as days, hours, minutes and seconds, e.g. "2d 3h 4m 5.25s"
+func HumanDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	var parts []string
+	if days := d / (24 * time.Hour); days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+		d -= days * 24 * time.Hour
+	}
+	if hours := d / time.Hour; hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+		d -= minutes * time.Minute
+	}
+	if d > 0 {
+		if d%time.Second == 0 {
+			parts = append(parts, fmt.Sprintf("%ds", d/time.Second))
+		} else if d < time.Second {
+			parts = append(parts, d.String())
+		} else {
+			parts = append(parts, strconv.FormatFloat(d.Seconds(), 'f', -1, 64)+"s")
+		}
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// RelativeTo describes t relative to now, e.g. "3h 5m ago" or "in 2d"
+func RelativeTo(t, now time.Time) string {
+	d := now.Sub(t).Truncate(time.Second)
+	switch {
+	case d == 0:
+		return "now"
+	case d > 0:
+		return HumanDuration(d) + " ago"
+	default:
+		return "in " + HumanDuration(-d)
+	}
+}
+
+// loadLocation resolves a zone name, accepting "local", "UTC", IANA names and fixed offsets like "+05:30"
+func loadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	switch strings.ToLower(name) {
+	case "", "utc", "z", "gmt":
+		return time.UTC, nil
+	case "local":
+		return time.Local, nil
+	}
+	if name[0] == '+' || name[0] == '-' {
+		layout := "-07:00"
+		if !strings.Contains(name, ":") {
+			layout = "-0700"
+			if len(name) == 3 {
+				layout = "-07"
+			}
+		}
+		if t, err := time.Parse(layout, name); err == nil {
+			_, offset := t.Zone()
+			return time.FixedZone("UTC"+name, offset), nil
+		}
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use an IANA name such as Europe/Berlin, or an offset such as +05:30)", name)
+	}
+	return loc, nil
+}
+
+// suggestStep picks a Prometheus query step that keeps a range near 250 points
+func suggestStep(d time.Duration) time.Duration {
+	steps := []time.Duration{
+		15 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute, 5 * time.Minute,
+		10 * time.Minute, 15 * time.Minute, 30 * time.Minute, time.Hour, 2 * time.Hour,
+		6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+	}
+	for _, step := range steps {
+		if d/step <= 250 {
+			return step
+		}
+	}
+	return steps[len(steps)-1]
+}
@@ -0,0 +1,296 @@
+package utility
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxUserAgents bounds the number of user agents parsed per call
+const maxUserAgents = 1000
+
+// UserAgentInfo is what a user-agent string says about the client
+type UserAgentInfo struct {
+	Input       string   `json:"input"`
+	Browser     string   `json:"browser,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	Engine      string   `json:"engine,omitempty"`
+	OS          string   `json:"os,omitempty"`
+	OSVersion   string   `json:"os_version,omitempty"`
+	Device      string   `json:"device"` // desktop, mobile, tablet, tv, bot or client
+	Model       string   `json:"model,omitempty"`
+	Bot         bool     `json:"bot"`
+	BotName     string   `json:"bot_name,omitempty"`
+	BotCategory string   `json:"bot_category,omitempty"` // search, crawler, social, ai, monitoring, probe, http_client or testing
+	Count       int      `json:"count,omitempty"`
+	Notes       []string `json:"notes,omitempty"`
+}
+
+// uaBot matches a token in an automated client's user agent
+type uaBot struct {
+	token    string // lower-case substring
+	name     string
+	category string
+}
+
+// uaBots are checked in order, so more specific tokens come before generic ones
+var uaBots = []uaBot{
+	{"googlebot", "Googlebot", "search"},
+	{"google-inspectiontool", "Google Inspection Tool", "search"},
+	{"adsbot-google", "AdsBot-Google", "search"},
+	{"mediapartners-google", "Google AdSense", "search"},
+	{"bingbot", "Bingbot", "search"},
+	{"duckduckbot", "DuckDuckBot", "search"},
+	{"baiduspider", "Baiduspider", "search"},
+	{"yandexbot", "YandexBot", "search"},
+	{"applebot", "Applebot", "search"},
+	{"petalbot", "PetalBot", "search"},
+	{"gptbot", "GPTBot", "ai"},
+	{"chatgpt-user", "ChatGPT-User", "ai"},
+	{"claudebot", "ClaudeBot", "ai"},
+	{"perplexitybot", "PerplexityBot", "ai"},
+	{"bytespider", "Bytespider", "ai"},
+	{"ccbot", "CCBot", "ai"},
+	{"facebookexternalhit", "Facebook link preview", "social"},
+	{"twitterbot", "Twitterbot", "social"},
+	{"linkedinbot", "LinkedInBot", "social"},
+	{"slackbot", "Slackbot", "social"},
+	{"discordbot", "Discordbot", "social"},
+	{"telegrambot", "TelegramBot", "social"},
+	{"whatsapp", "WhatsApp link preview", "social"},
+	{"ahrefsbot", "AhrefsBot", "crawler"},
+	{"semrushbot", "SemrushBot", "crawler"},
+	{"mj12bot", "MJ12bot", "crawler"},
+	{"dotbot", "DotBot", "crawler"},
+	{"scrapy", "Scrapy", "crawler"},
+	{"uptimerobot", "UptimeRobot", "monitoring"},
+	{"pingdom", "Pingdom", "monitoring"},
+	{"statuscake", "StatusCake", "monitoring"},
+	{"datadog", "Datadog Synthetics", "monitoring"},
+	{"newrelicpinger", "New Relic Synthetics", "monitoring"},
+	{"site24x7", "Site24x7", "monitoring"},
+	{"blackbox-exporter", "Prometheus Blackbox Exporter", "monitoring"},
+	{"prometheus", "Prometheus", "monitoring"},
+	{"kube-probe", "Kubernetes probe", "probe"},
+	{"elb-healthchecker", "AWS ELB health check", "probe"},
+	{"googlehc", "Google Cloud health check", "probe"},
+	{"consul health check", "Consul health check", "probe"},
+	{"envoy", "Envoy", "probe"},
+	{"lighthouse", "Lighthouse", "testing"},
+	{"headlesschrome", "Headless Chrome", "testing"},
+	{"phantomjs", "PhantomJS", "testing"},
+	{"k6/", "k6", "testing"},
+	{"apache-jmeter", "JMeter", "testing"},
+	{"postmanruntime", "Postman", "http_client"},
+	{"insomnia", "Insomnia", "http_client"},
+	{"curl/", "curl", "http_client"},
+	{"wget/", "Wget", "http_client"},
+	{"python-requests", "python-requests", "http_client"},
+	{"python-urllib", "Python urllib", "http_client"},
+	{"python-httpx", "httpx", "http_client"},
+	{"aiohttp", "aiohttp", "http_client"},
+	{"go-http-client", "Go net/http", "http_client"},
+	{"okhttp", "OkHttp", "http_client"},
+	{"apache-httpclient", "Apache HttpClient", "http_client"},
+	{"java/", "Java", "http_client"},
+	{"axios", "axios", "http_client"},
+	{"node-fetch", "node-fetch", "http_client"},
+	{"undici", "undici", "http_client"},
+	{"faraday", "Faraday", "http_client"},
+	{"libwww-perl", "libwww-perl", "http_client"},
+	{"dart:io", "Dart", "http_client"},
+	{"grpc-", "gRPC", "http_client"},
+	{"aws-sdk", "AWS SDK", "http_client"},
+}
+
+// uaBrowser matches a browser token; earlier entries win because many browsers also claim Chrome or Safari
+type uaBrowser struct {
+	token  string
+	name   string
+	engine string
+}
+
+var uaBrowsers = []uaBrowser{
+	{"EdgA/", "Edge", "Blink"},
+	{"EdgiOS/", "Edge", "WebKit"},
+	{"Edg/", "Edge", "Blink"},
+	{"Edge/", "Edge Legacy", "EdgeHTML"},
+	{"OPR/", "Opera", "Blink"},
+	{"OPiOS/", "Opera", "WebKit"},
+	{"SamsungBrowser/", "Samsung Internet", "Blink"},
+	{"YaBrowser/", "Yandex Browser", "Blink"},
+	{"Vivaldi/", "Vivaldi", "Blink"},
+	{"UCBrowser/", "UC Browser", "Blink"},
+	{"DuckDuckGo/", "DuckDuckGo", "WebKit"},
+	{"FxiOS/", "Firefox", "WebKit"},
+	{"Firefox/", "Firefox", "Gecko"},
+	{"CriOS/", "Chrome", "WebKit"},
+	{"Chrome/", "Chrome", "Blink"},
+	{"Chromium/", "Chromium", "Blink"},
+}
+
+var (
+	uaVersionPattern  = regexp.MustCompile(`^[0-9][0-9._]*`)
+	uaAndroidModel    = regexp.MustCompile(`Android [0-9.]+; (?:[a-zA-Z]{2}[-_][a-zA-Z]{2}; )?([^;)]+?)(?: Build/[^;)]*)?[;)]`)
+	uaWindowsVersions = map[string]string{
+		"10.0": "10/11", "6.3": "8.1", "6.2": "8", "6.1": "7", "6.0": "Vista", "5.2": "XP x64", "5.1": "XP",
+	}
+)
+
+// versionAfter returns the version number that follows token in ua
+func versionAfter(ua, token string) string {
+	i := strings.Index(ua, token)
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimRight(uaVersionPattern.FindString(ua[i+len(token):]), "._")
+}
+
+// ParseUserAgent classifies a user-agent string by browser, engine, OS, device type and bot
+func ParseUserAgent(ua string) UserAgentInfo {
+	ua = strings.TrimSpace(ua)
+	info := UserAgentInfo{Input: ua, Device: "desktop"}
+	if ua == "" || ua == "-" {
+		info.Device = "client"
+		info.Notes = append(info.Notes, "empty user agent, typical of scripts and scanners")
+		return info
+	}
+	lower := strings.ToLower(ua)
+
+	parseUserAgentOS(ua, &info)
+	parseUserAgentBrowser(ua, lower, &info)
+
+	for _, bot := range uaBots {
+		if strings.Contains(lower, bot.token) {
+			info.Bot, info.BotName, info.BotCategory = true, bot.name, bot.category
+			break
+		}
+	}
+	if !info.Bot {
+		for _, word := range []string{"bot", "crawler", "spider", "scraper"} {
+			if strings.Contains(lower, word) && !strings.Contains(lower, "cubot") {
+				info.Bot, info.BotName, info.BotCategory = true, "unknown "+word, "crawler"
+				break
+			}
+		}
+	}
+
+	switch {
+	case info.Bot && info.BotCategory == "http_client":
+		info.Device = "client"
+	case info.Bot:
+		info.Device = "bot"
+	case strings.Contains(ua, "iPad") || (info.OS == "Android" && !strings.Contains(ua, "Mobile")):
+		info.Device = "tablet"
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod") || strings.Contains(ua, "Mobile"):
+		info.Device = "mobile"
+	case strings.Contains(lower, "smart-tv") || strings.Contains(lower, "smarttv") || info.OS == "Tizen" || info.OS == "webOS" || strings.Contains(ua, "CrKey"):
+		info.Device = "tv"
+	case info.Browser == "" && info.OS == "":
+		info.Device = "client"
+	}
+
+	if info.BotCategory == "search" || info.BotCategory == "ai" {
+		info.Notes = append(info.Notes, "crawler user agents are easily spoofed; verify the source IP with reverse DNS or the operator's published ranges")
+	}
+	if info.OS == "macOS" && info.OSVersion == "10.15.7" {
+		info.Notes = append(info.Notes, "browsers freeze macOS at 10.15.7, so the real version is unknown")
+	}
+	if info.OS == "Windows" && info.OSVersion == "10/11" {
+		info.Notes = append(info.Notes, "Windows 10 and 11 report the same NT 10.0 version")
+	}
+	if info.Model == "K" {
+		info.Model = ""
+		info.Notes = append(info.Notes, "reduced Chrome user agent; the device model is hidden")
+	}
+	return info
+}
+
+// parseUserAgentOS fills in the operating system and device model
+func parseUserAgentOS(ua string, info *UserAgentInfo) {
+	switch {
+	case strings.Contains(ua, "Windows Phone"):
+		info.OS, info.OSVersion = "Windows Phone", versionAfter(ua, "Windows Phone ")
+	case strings.Contains(ua, "Windows NT"):
+		info.OS = "Windows"
+		nt := versionAfter(ua, "Windows NT ")
+		if version, ok := uaWindowsVersions[nt]; ok {
+			info.OSVersion = version
+		} else {
+			info.OSVersion = "NT " + nt
+		}
+	case strings.Contains(ua, "iPad") && strings.Contains(ua, " OS "):
+		info.OS, info.OSVersion, info.Model = "iPadOS", strings.ReplaceAll(versionAfter(ua, " OS "), "_", "."), "iPad"
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod"):
+		info.OS, info.OSVersion = "iOS", strings.ReplaceAll(versionAfter(ua, " OS "), "_", ".")
+		info.Model = "iPhone"
+		if strings.Contains(ua, "iPod") {
+			info.Model = "iPod"
+		}
+	case strings.Contains(ua, "HarmonyOS"):
+		info.OS, info.OSVersion = "HarmonyOS", versionAfter(ua, "HarmonyOS ")
+	case strings.Contains(ua, "Android"):
+		info.OS, info.OSVersion = "Android", versionAfter(ua, "Android ")
+		if m := uaAndroidModel.FindStringSubmatch(ua); m != nil {
+			info.Model = strings.TrimSpace(m[1])
+		}
+	case strings.Contains(ua, "CrOS"):
+		info.OS = "ChromeOS"
+	case strings.Contains(ua, "Mac OS X"):
+		info.OS, info.OSVersion = "macOS", strings.ReplaceAll(versionAfter(ua, "Mac OS X "), "_", ".")
+	case strings.Contains(ua, "Tizen"):
+		info.OS, info.OSVersion = "Tizen", versionAfter(ua, "Tizen ")
+	case strings.Contains(ua, "Web0S") || strings.Contains(ua, "WebOS"):
+		info.OS = "webOS"
+	case strings.Contains(ua, "Ubuntu"):
+		info.OS = "Ubuntu"
+	case strings.Contains(ua, "FreeBSD"):
+		info.OS = "FreeBSD"
+	case strings.Contains(ua, "Linux") || strings.Contains(ua, "X11"):
+		info.OS = "Linux"
+	}
+}
+
+// parseUserAgentBrowser fills in the browser, its version and rendering engine
+func parseUserAgentBrowser(ua, lower string, info *UserAgentInfo) {
+	switch {
+	case strings.Contains(ua, "FBAN/") || strings.Contains(ua, "FBAV/"):
+		info.Browser, info.Version = "Facebook in-app", versionAfter(ua, "FBAV/")
+	case strings.Contains(ua, "Instagram "):
+		info.Browser, info.Version = "Instagram in-app", versionAfter(ua, "Instagram ")
+	case strings.Contains(ua, "MSIE "):
+		info.Browser, info.Version, info.Engine = "Internet Explorer", versionAfter(ua, "MSIE "), "Trident"
+	case strings.Contains(ua, "Trident/"):
+		info.Browser, info.Version, info.Engine = "Internet Explorer", versionAfter(ua, "rv:"), "Trident"
+	}
+	if info.Browser == "" {
+		for _, browser := range uaBrowsers {
+			if strings.Contains(ua, browser.token) {
+				info.Browser, info.Version, info.Engine = browser.name, versionAfter(ua, browser.token), browser.engine
+				break
+			}
+		}
+	}
+	if info.Browser == "Chrome" && strings.Contains(ua, "; wv)") {
+		info.Browser = "Android WebView"
+	}
+	if info.Browser == "" && strings.Contains(ua, "Safari/") && strings.Contains(ua, "Version/") {
+		info.Browser, info.Version, info.Engine = "Safari", versionAfter(ua, "Version/"), "WebKit"
+	}
+	if info.Browser == "" && strings.Contains(ua, "AppleWebKit/") && (info.OS == "iOS" || info.OS == "iPadOS") {
+		info.Browser, info.Engine = "iOS WebView", "WebKit"
+	}
+	// Every browser on iOS must use WebKit
+	if info.OS == "iOS" || info.OS == "iPadOS" {
+		if info.Engine != "" {
+			info.Engine = "WebKit"
+		}
+	}
+	if info.Engine == "" {
+		switch {
+		case strings.Contains(ua, "AppleWebKit/"):
+			info.Engine = "WebKit"
+		case strings.Contains(lower, "gecko/"):
+			info.Engine = "Gecko"
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package utility
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+	appcfg "dev-mcp/internal/config"
+	"dev-mcp/internal/provider"
+	"dev-mcp/internal/provider/s3"
+)
+
+// FileAccess reads and writes local files; the file provider implements it so its
+// directory whitelist, size limit and read-only mode apply
+type FileAccess interface {
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, content []byte, createDirs bool) error
+	ListFiles(root string, limit int) ([]string, bool, error)
+}
+
+// UtilityProvider provides self-contained helper tools (time math, test data, validation and similar) that need no backend
+type UtilityProvider struct {
+	*provider.BaseProvider
+	files    FileAccess
+	swagger  *appcfg.SwaggerConfig
+	s3Client *s3.S3Client
+	geoip    *GeoIP
+}
+
+// NewUtilityProvider creates a new utility provider with config; files may be nil, which disables file input and output
+func NewUtilityProvider(cfg *appcfg.UtilityConfig, swaggerCfg *appcfg.SwaggerConfig, s3Cfg *appcfg.S3Config, files FileAccess) *UtilityProvider {
+	p := &UtilityProvider{
+		BaseProvider: provider.NewBaseProvider("utility"),
+		files:        files,
+		swagger:      swaggerCfg,
+		s3Client:     s3.NewS3Client(s3Cfg),
+	}
+
+	// A missing GeoIP database only disables the geo fields of ip_info
+	geoip, errs := OpenGeoIP(cfg.GeoIPDatabases)
+	for _, err := range errs {
+		log.Printf("⚠ %v", err)
+	}
+	p.geoip = geoip
+
+	// Utility tools have no dependencies and are always available
+	p.SetAvailable(true)
+	log.Printf("✓ Utility provider initialized successfully")
+
+	return p
+}
+
+// Test tests the utility provider (for ProviderClient interface compatibility)
+func (p *UtilityProvider) Test(config interface{}) error {
+	return nil
+}
+
+// AddTools adds utility tools to the MCP server (for ProviderClient interface compatibility)
+func (p *UtilityProvider) AddTools(server *mcp.Server, config interface{}) error {
+	p.addToolsToServer(server)
+	return nil
+}
+
+// Close closes the utility provider
+func (p *UtilityProvider) Close() error {
+	if err := p.geoip.Close(); err != nil {
+		log.Printf("⚠ Failed to close GeoIP databases: %v", err)
+	}
+	return p.s3Client.Close()
+}
+
+// addToolsToServer adds utility tools to the MCP server
+func (p *UtilityProvider) addToolsToServer(server *mcp.Server) {
+	tools := []entity.ToolDefinition{
+		p.createTimeConvertTool(),
+		p.createTimeParseTool(),
+		p.createTimeDiffTool(),
+		p.createTimeRangeTool(),
+		p.createCronExplainTool(),
+		p.createFakeDataTool(),
+		p.createValidateJSONTool(),
+		p.createSwaggerDiffTool(),
+		p.createRegexTestTool(),
+		p.createEncodeTool(),
+		p.createHashTool(),
+		p.createJWTDecodeTool(),
+		p.createIPInfoTool(),
+		p.createUserAgentParseTool(),
+		p.createParseStacktraceTool(),
+		p.createCoverageReportTool(),
+	}
+
+	for _, tool := range tools {
+		server.AddTool(tool.Tool, tool.Handler)
+		log.Printf("✓ Registered utility tool: %s", tool.Tool.Name)
+	}
+
+	log.Printf("✓ All utility tools registered successfully")
+}
+
+// Helper functions
+func (p *UtilityProvider) createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Utility Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func (p *UtilityProvider) formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return p.createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
+
+// Verify that UtilityProvider implements ProviderClient interface
+var _ provider.ProviderClient = (*UtilityProvider)(nil)
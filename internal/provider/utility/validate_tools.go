@@ -0,0 +1,218 @@
+package utility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"dev-mcp/entity"
+)
+
+// createValidateJSONTool creates the JSON Schema validation tool
+func (p *UtilityProvider) createValidateJSONTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "validate_json",
+		Description: "Validate a JSON (or YAML) document against a JSON Schema and list every violation with its JSON pointer path. The document can be inline, a file or an S3 object; the schema can be inline, a file or a named Swagger/OpenAPI definition",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"document": {
+					"description": "Inline document: any JSON value, or a string holding JSON/YAML text"
+				},
+				"document_path": {
+					"type": "string",
+					"description": "Read the document from this file (via the file provider)"
+				},
+				"s3_bucket": {
+					"type": "string",
+					"description": "Read the document from this S3 bucket (with s3_key)"
+				},
+				"s3_key": {
+					"type": "string",
+					"description": "Key of the S3 object holding the document"
+				},
+				"schema": {
+					"type": "object",
+					"description": "Inline JSON Schema"
+				},
+				"schema_path": {
+					"type": "string",
+					"description": "Read the JSON Schema from this file (via the file provider)"
+				},
+				"swagger_definition": {
+					"type": "string",
+					"description": "Name of a schema in the configured Swagger document (definitions or components/schemas), e.g. CreateOrderRequest"
+				},
+				"max_errors": {
+					"type": "integer",
+					"description": "Maximum number of violations to return",
+					"default": 50
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Document          json.RawMessage `json:"document,omitempty"`
+			DocumentPath      string          `json:"document_path,omitempty"`
+			S3Bucket          string          `json:"s3_bucket,omitempty"`
+			S3Key             string          `json:"s3_key,omitempty"`
+			Schema            json.RawMessage `json:"schema,omitempty"`
+			SchemaPath        string          `json:"schema_path,omitempty"`
+			SwaggerDefinition string          `json:"swagger_definition,omitempty"`
+			MaxErrors         int             `json:"max_errors,omitempty"`
+		}
+
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return p.createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+
+		if args.MaxErrors <= 0 {
+			args.MaxErrors = defaultMaxValidationErrors
+		}
+
+		doc, docSource, err := p.loadValidationDocument(args.Document, args.DocumentPath, args.S3Bucket, args.S3Key)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		resources, location, draft, schemaSource, err := p.loadValidationSchema(args.Schema, args.SchemaPath, args.SwaggerDefinition)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		issues, total, err := validateDocument(resources, location, draft, doc, args.MaxErrors)
+		if err != nil {
+			return p.createErrorResult(err), nil
+		}
+
+		result := map[string]interface{}{
+			"valid":       total == 0,
+			"document":    docSource,
+			"schema":      schemaSource,
+			"error_count": total,
+		}
+		if total > 0 {
+			result["errors"] = issues
+			result["truncated"] = total > len(issues)
+		}
+		return p.formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+// loadValidationDocument resolves exactly one document source
+func (p *UtilityProvider) loadValidationDocument(inline json.RawMessage, path, bucket, key string) (interface{}, string, error) {
+	sources := 0
+	for _, set := range []bool{len(inline) > 0, path != "", bucket != "" || key != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, "", fmt.Errorf("provide exactly one of document, document_path or s3_bucket/s3_key")
+	}
+
+	var (
+		data   []byte
+		source string
+	)
+	switch {
+	case len(inline) > 0:
+		// A JSON string is treated as document text so YAML or escaped JSON can be pasted in
+		var text string
+		if err := json.Unmarshal(inline, &text); err == nil {
+			data, source = []byte(text), "inline text"
+		} else {
+			data, source = inline, "inline"
+		}
+	case path != "":
+		if p.files == nil {
+			return nil, "", fmt.Errorf("reading files is not available")
+		}
+		content, err := p.files.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		data, source = content, path
+	default:
+		if bucket == "" || key == "" {
+			return nil, "", fmt.Errorf("s3_bucket and s3_key must be given together")
+		}
+		if !p.s3Client.IsAvailable() {
+			return nil, "", fmt.Errorf("s3 is not configured")
+		}
+		content, err := p.s3Client.GetObjectBytes(bucket, key)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read s3://%s/%s: %w", bucket, key, err)
+		}
+		data, source = content, fmt.Sprintf("s3://%s/%s", bucket, key)
+	}
+
+	doc, format, err := parseDocument(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return doc, fmt.Sprintf("%s (%s)", source, format), nil
+}
+
+// loadValidationSchema resolves exactly one schema source into compiler resources and the location to compile
+func (p *UtilityProvider) loadValidationSchema(inline json.RawMessage, path, definition string) (map[string]interface{}, string, *jsonschema.Draft, string, error) {
+	sources := 0
+	for _, set := range []bool{len(inline) > 0, path != "", definition != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, "", nil, "", fmt.Errorf("provide exactly one of schema, schema_path or swagger_definition")
+	}
+
+	if definition != "" {
+		spec, err := p.loadSwaggerDocument()
+		if err != nil {
+			return nil, "", nil, "", err
+		}
+		pointer, err := swaggerDefinitionPointer(spec, definition)
+		if err != nil {
+			return nil, "", nil, "", err
+		}
+		// Swagger 2 and OpenAPI 3.0 schemas follow draft 4 semantics; OpenAPI 3.1 uses 2020-12
+		draft := jsonschema.Draft4
+		if version, _ := spec["openapi"].(string); strings.HasPrefix(version, "3.1") {
+			draft = jsonschema.Draft2020
+		}
+		resources := map[string]interface{}{swaggerResourceURL: spec}
+		return resources, swaggerResourceURL + pointer, draft, "swagger " + pointer, nil
+	}
+
+	var (
+		data   []byte
+		source string
+	)
+	if path != "" {
+		if p.files == nil {
+			return nil, "", nil, "", fmt.Errorf("reading files is not available")
+		}
+		content, err := p.files.ReadFile(path)
+		if err != nil {
+			return nil, "", nil, "", err
+		}
+		data, source = content, path
+	} else {
+		data, source = inline, "inline"
+	}
+
+	schema, _, err := parseDocument(data)
+	if err != nil {
+		return nil, "", nil, "", fmt.Errorf("invalid schema: %w", err)
+	}
+	// Schemas without $schema are read as 2020-12
+	return map[string]interface{}{schemaResourceURL: schema}, schemaResourceURL, jsonschema.Draft2020, source, nil
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dev-mcp/entity"
+)
+
+// defaultInspectLimit bounds the keys listed by kv_inspect
+const defaultInspectLimit = 100
+
+// InspectTool creates the kv_inspect tool, which shows the buckets, keys and values of the store
+func (s *Store) InspectTool() entity.ToolDefinition {
+	tool := &mcp.Tool{
+		Name:        "kv_inspect",
+		Description: "Inspect the server's embedded state store: without a bucket, list the buckets with their key counts and sizes and the migration versions; with a bucket, list its keys (optionally by prefix) with their values; with a key, show that value. Read-only",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"bucket": {
+					"type": "string",
+					"description": "Bucket to list"
+				},
+				"prefix": {
+					"type": "string",
+					"description": "Only list keys starting with this prefix"
+				},
+				"key": {
+					"type": "string",
+					"description": "Show the value of this key of the bucket"
+				},
+				"limit": {
+					"type": "integer",
+					"description": "Maximum number of keys to list",
+					"default": 100
+				}
+			}
+		}`),
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Bucket string `json:"bucket,omitempty"`
+			Prefix string `json:"prefix,omitempty"`
+			Key    string `json:"key,omitempty"`
+			Limit  int    `json:"limit,omitempty"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return createErrorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if args.Limit <= 0 {
+			args.Limit = defaultInspectLimit
+		}
+
+		if args.Bucket == "" {
+			if args.Key != "" || args.Prefix != "" {
+				return createErrorResult(fmt.Errorf("key and prefix need a bucket")), nil
+			}
+			migrations := map[string]int{}
+			err := s.View(func(tx *Tx) error {
+				versions := tx.Bucket(migrationsBucket)
+				for _, owner := range versions.Keys("") {
+					var version int
+					if _, err := versions.Get(owner, &version); err != nil {
+						return err
+					}
+					migrations[owner] = version
+				}
+				return nil
+			})
+			if err != nil {
+				return createErrorResult(err), nil
+			}
+			return formatJSONResult(map[string]interface{}{
+				"store":      s.Stats(),
+				"migrations": migrations,
+			}), nil
+		}
+
+		type item struct {
+			Key     string          `json:"key"`
+			Value   json.RawMessage `json:"value"`
+			Expires string          `json:"expires,omitempty"`
+		}
+		var result interface{}
+		err := s.View(func(tx *Tx) error {
+			bucket := tx.Bucket(args.Bucket)
+			describe := func(key string) item {
+				value, _ := bucket.GetRaw(key)
+				it := item{Key: key, Value: value}
+				if expires := bucket.Expires(key); !expires.IsZero() {
+					it.Expires = expires.UTC().Format(time.RFC3339)
+				}
+				return it
+			}
+
+			if args.Key != "" {
+				if _, ok := bucket.GetRaw(args.Key); !ok {
+					return fmt.Errorf("key %q not found in bucket %s", args.Key, args.Bucket)
+				}
+				result = describe(args.Key)
+				return nil
+			}
+
+			keys := bucket.Keys(args.Prefix)
+			items := make([]item, 0, min(len(keys), args.Limit))
+			for _, key := range keys[:min(len(keys), args.Limit)] {
+				items = append(items, describe(key))
+			}
+			result = map[string]interface{}{
+				"bucket":    args.Bucket,
+				"total":     len(keys),
+				"truncated": len(keys) > args.Limit,
+				"keys":      items,
+			}
+			return nil
+		})
+		if err != nil {
+			return createErrorResult(err), nil
+		}
+		return formatJSONResult(result), nil
+	}
+
+	return entity.ToolDefinition{Tool: tool, Handler: handler}
+}
+
+func createErrorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Store Error: %v", err)}},
+		IsError: true,
+	}
+}
+
+func formatJSONResult(data interface{}) *mcp.CallToolResult {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return createErrorResult(fmt.Errorf("failed to marshal data: %w", err))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(jsonData)}},
+	}
+}
@@ -0,0 +1,530 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dev-mcp/internal/config"
+)
+
+const (
+	// migrationsBucket records the schema version of each owner that ran migrations
+	migrationsBucket = "_migrations"
+	// compactMinBytes is the log size below which the store is never compacted
+	compactMinBytes = 1 << 20
+)
+
+// Store is a small embedded key-value store for server state, such as sessions, bookmarks and quotas.
+// Keys live in named buckets and values are JSON. Everything is held in memory and every commit is
+// appended to a log file as one line, so a commit is applied in full or not at all after a crash;
+// the log is compacted when it grows to twice the live data.
+type Store struct {
+	path string
+	sync bool
+
+	mu      sync.RWMutex
+	buckets map[string]map[string]entry
+	file    *os.File // Opened on the first commit
+	size    int64    // Bytes in the log
+	live    int64    // Bytes the live entries would take in a compacted log
+}
+
+// entry is a stored value
+type entry struct {
+	Value   json.RawMessage
+	Expires time.Time // Zero for no expiry
+}
+
+// record is a change of one key in the log; a record without a value deletes the key
+type record struct {
+	Bucket  string          `json:"b"`
+	Key     string          `json:"k"`
+	Value   json.RawMessage `json:"v,omitempty"`
+	Expires int64           `json:"x,omitempty"` // Unix milliseconds
+}
+
+// commit is one line of the log
+type commit struct {
+	Records []record `json:"ops"`
+}
+
+// Open loads the store at store.path (./data/dev-mcp.db by default). The file and its directory are
+// only created when something is first written.
+func Open(cfg *config.StoreConfig) (*Store, error) {
+	s := &Store{path: cfg.Path, sync: cfg.Sync, buckets: map[string]map[string]entry{}}
+	if s.path == "" {
+		s.path = "./data/dev-mcp.db"
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load replays the log. A torn last line, left by a crash in the middle of a commit, is dropped.
+func (s *Store) load() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	var valid int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read store: %w", err)
+		}
+		var c commit
+		if err := json.Unmarshal(line, &c); err != nil {
+			return fmt.Errorf("store %s is corrupt at byte %d: %w", s.path, valid, err)
+		}
+		s.apply(c.Records)
+		valid += int64(len(line))
+	}
+	s.size = valid
+
+	if info, err := f.Stat(); err == nil && info.Size() > valid {
+		if err := os.Truncate(s.path, valid); err != nil {
+			return fmt.Errorf("failed to drop incomplete commit: %w", err)
+		}
+	}
+	return nil
+}
+
+// apply changes the in-memory state; the caller holds the write lock or is loading
+func (s *Store) apply(records []record) {
+	now := time.Now()
+	for _, r := range records {
+		bucket := s.buckets[r.Bucket]
+		if old, ok := bucket[r.Key]; ok {
+			s.live -= recordSize(r.Bucket, r.Key, old)
+			delete(bucket, r.Key)
+			if len(bucket) == 0 {
+				delete(s.buckets, r.Bucket)
+				bucket = nil
+			}
+		}
+		if r.Value == nil {
+			continue
+		}
+		e := entry{Value: r.Value}
+		if r.Expires > 0 {
+			e.Expires = time.UnixMilli(r.Expires)
+			if !e.Expires.After(now) {
+				continue
+			}
+		}
+		if bucket == nil {
+			bucket = map[string]entry{}
+			s.buckets[r.Bucket] = bucket
+		}
+		bucket[r.Key] = e
+		s.live += recordSize(r.Bucket, r.Key, e)
+	}
+}
+
+// recordSize estimates the bytes an entry takes in a compacted log
+func recordSize(bucket, key string, e entry) int64 {
+	return int64(len(bucket) + len(key) + len(e.Value) + 32)
+}
+
+// View runs fn with a read-only transaction
+func (s *Store) View(fn func(tx *Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&Tx{store: s})
+}
+
+// Update runs fn with a read-write transaction and commits its writes when fn returns nil. Updates
+// run one at a time, so a read followed by a write within fn is atomic.
+func (s *Store) Update(fn func(tx *Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := &Tx{store: s, writable: true, pending: map[string]map[string]*record{}}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return s.commit(tx.records())
+}
+
+// commit appends the records to the log and applies them; the caller holds the write lock
+func (s *Store) commit(records []record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(commit{Records: records})
+	if err != nil {
+		return fmt.Errorf("failed to encode commit: %w", err)
+	}
+	line = append(line, '\n')
+
+	if s.file == nil {
+		if err := s.openLog(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.file.Write(line); err != nil {
+		// Drop the partial line, so later commits do not follow a corrupt one
+		_ = s.file.Truncate(s.size)
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	s.size += int64(len(line))
+	if s.sync {
+		if err := s.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+	s.apply(records)
+
+	if s.size > compactMinBytes && s.size > 2*s.live {
+		return s.compact()
+	}
+	return nil
+}
+
+func (s *Store) openLog() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	s.file = f
+	return nil
+}
+
+// compact rewrites the log with one record per live key and swaps it in; the caller holds the write lock
+func (s *Store) compact() error {
+	tmp := s.path + ".compact"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to compact store: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	var size int64
+	now := time.Now()
+	for _, name := range s.bucketNames() {
+		var records []record
+		for key, e := range s.buckets[name] {
+			if e.expired(now) {
+				continue
+			}
+			records = append(records, record{Bucket: name, Key: key, Value: e.Value, Expires: e.expiresMs()})
+		}
+		if len(records) == 0 {
+			continue
+		}
+		line, err := json.Marshal(commit{Records: records})
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("failed to compact store: %w", err)
+		}
+		n, _ := w.Write(append(line, '\n'))
+		size += int64(n)
+	}
+	err = w.Flush()
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to compact store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to compact store: %w", err)
+	}
+
+	s.file.Close()
+	s.file = nil
+	s.size = size
+	return s.openLog()
+}
+
+// bucketNames returns the sorted bucket names; the caller holds the lock
+func (s *Store) bucketNames() []string {
+	names := make([]string, 0, len(s.buckets))
+	for name := range s.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get reads a value into v and reports whether the key exists
+func (s *Store) Get(bucket, key string, v interface{}) (bool, error) {
+	var found bool
+	err := s.View(func(tx *Tx) error {
+		var err error
+		found, err = tx.Bucket(bucket).Get(key, v)
+		return err
+	})
+	return found, err
+}
+
+// Put writes a value
+func (s *Store) Put(bucket, key string, v interface{}) error {
+	return s.Update(func(tx *Tx) error { return tx.Bucket(bucket).Put(key, v) })
+}
+
+// Delete removes a key
+func (s *Store) Delete(bucket, key string) error {
+	return s.Update(func(tx *Tx) error { return tx.Bucket(bucket).Delete(key) })
+}
+
+// BucketStats describes a bucket
+type BucketStats struct {
+	Name  string `json:"name"`
+	Keys  int    `json:"keys"`
+	Bytes int    `json:"bytes"` // Size of the values
+}
+
+// Stats describes the store
+type Stats struct {
+	Path     string        `json:"path"`
+	LogBytes int64         `json:"log_bytes"`
+	Buckets  []BucketStats `json:"buckets"`
+}
+
+// Stats returns the size of the log and of every bucket
+func (s *Store) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := Stats{Path: s.path, LogBytes: s.size, Buckets: []BucketStats{}}
+	now := time.Now()
+	for _, name := range s.bucketNames() {
+		b := BucketStats{Name: name}
+		for _, e := range s.buckets[name] {
+			if !e.expired(now) {
+				b.Keys++
+				b.Bytes += len(e.Value)
+			}
+		}
+		stats.Buckets = append(stats.Buckets, b)
+	}
+	return stats
+}
+
+// Close compacts the log when it holds stale records and closes it
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	var err error
+	if s.size > 2*s.live {
+		err = s.compact()
+	}
+	if s.file != nil {
+		if closeErr := s.file.Close(); err == nil {
+			err = closeErr
+		}
+		s.file = nil
+	}
+	return err
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !e.Expires.After(now)
+}
+
+func (e entry) expiresMs() int64 {
+	if e.Expires.IsZero() {
+		return 0
+	}
+	return e.Expires.UnixMilli()
+}
+
+// Tx is a transaction; writes are only visible to it until it commits
+type Tx struct {
+	store    *Store
+	writable bool
+	pending  map[string]map[string]*record // Bucket -> key -> write
+	order    []*record
+}
+
+// Bucket returns a bucket of the transaction; buckets exist as long as they hold keys
+func (tx *Tx) Bucket(name string) *Bucket {
+	return &Bucket{tx: tx, name: name}
+}
+
+func (tx *Tx) records() []record {
+	records := make([]record, len(tx.order))
+	for i, r := range tx.order {
+		records[i] = *r
+	}
+	return records
+}
+
+// Bucket is a namespace of keys within a transaction
+type Bucket struct {
+	tx   *Tx
+	name string
+}
+
+// raw returns the value of a key as the transaction sees it
+func (b *Bucket) raw(key string) (json.RawMessage, bool) {
+	if r, ok := b.tx.pending[b.name][key]; ok {
+		return r.Value, r.Value != nil
+	}
+	e, ok := b.tx.store.buckets[b.name][key]
+	if !ok || e.expired(time.Now()) {
+		return nil, false
+	}
+	return e.Value, true
+}
+
+// Get reads a value into v and reports whether the key exists
+func (b *Bucket) Get(key string, v interface{}) (bool, error) {
+	value, ok := b.raw(key)
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return true, fmt.Errorf("failed to decode %s/%s: %w", b.name, key, err)
+	}
+	return true, nil
+}
+
+// GetRaw returns the JSON of a value
+func (b *Bucket) GetRaw(key string) (json.RawMessage, bool) {
+	return b.raw(key)
+}
+
+// Put writes a value as JSON
+func (b *Bucket) Put(key string, v interface{}) error {
+	return b.PutTTL(key, v, 0)
+}
+
+// PutTTL writes a value that expires after ttl; 0 keeps it until it is deleted
+func (b *Bucket) PutTTL(key string, v interface{}, ttl time.Duration) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", b.name, key, err)
+	}
+	r := &record{Bucket: b.name, Key: key, Value: value}
+	if ttl > 0 {
+		r.Expires = time.Now().Add(ttl).UnixMilli()
+	}
+	return b.write(r)
+}
+
+// Delete removes a key
+func (b *Bucket) Delete(key string) error {
+	if _, ok := b.raw(key); !ok {
+		return nil
+	}
+	return b.write(&record{Bucket: b.name, Key: key})
+}
+
+func (b *Bucket) write(r *record) error {
+	if !b.tx.writable {
+		return fmt.Errorf("write to %s in a read-only transaction", b.name)
+	}
+	if b.name == "" || r.Key == "" {
+		return fmt.Errorf("bucket and key must not be empty")
+	}
+	if b.tx.pending[b.name] == nil {
+		b.tx.pending[b.name] = map[string]*record{}
+	}
+	if previous, ok := b.tx.pending[b.name][r.Key]; ok {
+		*previous = *r
+		return nil
+	}
+	b.tx.pending[b.name][r.Key] = r
+	b.tx.order = append(b.tx.order, r)
+	return nil
+}
+
+// Keys returns the sorted keys starting with prefix
+func (b *Bucket) Keys(prefix string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	now := time.Now()
+	for key, e := range b.tx.store.buckets[b.name] {
+		if _, pending := b.tx.pending[b.name][key]; !pending && !e.expired(now) && strings.HasPrefix(key, prefix) {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for key, r := range b.tx.pending[b.name] {
+		if r.Value != nil && !seen[key] && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Expires returns when a key expires; zero when it does not
+func (b *Bucket) Expires(key string) time.Time {
+	if r, ok := b.tx.pending[b.name][key]; ok {
+		if r.Expires == 0 {
+			return time.Time{}
+		}
+		return time.UnixMilli(r.Expires)
+	}
+	return b.tx.store.buckets[b.name][key].Expires
+}
+
+// Migration is a numbered change to the data of a subsystem
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx *Tx) error
+}
+
+// Migrate runs the migrations of an owner (a subsystem name) newer than the version it last reached,
+// in order and each in its own transaction together with the new version
+func (s *Store) Migrate(owner string, migrations ...Migration) error {
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for _, m := range migrations {
+		err := s.Update(func(tx *Tx) error {
+			versions := tx.Bucket(migrationsBucket)
+			var current int
+			if _, err := versions.Get(owner, &current); err != nil {
+				return err
+			}
+			if m.Version <= current {
+				return nil
+			}
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return versions.Put(owner, m.Version)
+		})
+		if err != nil {
+			return fmt.Errorf("%s migration %d (%s): %w", owner, m.Version, m.Description, err)
+		}
+	}
+	return nil
+}
+
+// Version returns the migration version an owner reached
+func (s *Store) Version(owner string) int {
+	var version int
+	_, _ = s.Get(migrationsBucket, owner, &version)
+	return version
+}
@@ -4,34 +4,30 @@ echo "Testing Dev MCP Server Transport Modes"
 echo "====================================="
 
 echo ""
-echo "1. Testing default mode (should force to SSE):"
-echo "Command: ./dev-mcp mcp"
+echo "1. Testing default mode (server.transport, SSE unless configured):"
+echo "Command: ./dev-mcp"
 echo ""
 
-echo "2. Testing explicit SSE mode:"
-echo "Command: ./dev-mcp mcp --sse"
+echo "2. Testing SSE mode:"
+echo "Command: ./dev-mcp --transport sse"
 echo ""
 
-echo "3. Testing HTTP mode (should use SSE):"
-echo "Command: ./dev-mcp mcp --http"
+echo "3. Testing Streamable HTTP mode (endpoint /mcp):"
+echo "Command: ./dev-mcp --transport http"
 echo ""
 
-echo "4. Testing stdio mode (should force to SSE):"
-echo "Command: ./dev-mcp mcp --stdio"
-echo ""
-
-echo "5. Testing transport parameter:"
-echo "Command: ./dev-mcp mcp --transport sse"
+echo "4. Testing stdio mode:"
+echo "Command: ./dev-mcp --transport stdio"
 echo ""
 
 echo "Available transport modes:"
-echo "- sse (Server-Sent Events) - DEFAULT/FORCED"
-echo "- http (HTTP-based, uses SSE implementation)"
-echo "- stdio (Forced to SSE mode)"
+echo "- sse (Server-Sent Events) - DEFAULT"
+echo "- http (Streamable HTTP with sessions and Last-Event-ID resumption)"
+echo "- stdio (stdin/stdout, logs on stderr)"
 echo ""
 
 echo "To run in debug mode, add --debug or -d flag"
-echo "Example: ./dev-mcp mcp --sse --debug"
+echo "Example: ./dev-mcp --transport http --debug"
 echo ""
 
-echo "Note: All modes are currently forced to use SSE transport as requested"
\ No newline at end of file
+echo "The transport can also be set with server.transport in configs/config.yaml or MCP_SERVER_TRANSPORT"
\ No newline at end of file